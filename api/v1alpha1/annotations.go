@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
 
 const (
 	// AnnotationKeyCreateActor is an annotation key that can be injected to a
@@ -37,6 +41,114 @@ const (
 	// of the annotation should be in the format of "<project>:<stage>".
 	AnnotationKeyAuthorizedStage = "kargo.akuity.io/authorized-stage"
 
+	// AnnotationKeyManagedByStage is an annotation key that is set by the
+	// controller on Argo CD Applications whose lifecycle is managed by a Stage.
+	// The value of the annotation is in the format of "<project>:<stage>".
+	AnnotationKeyManagedByStage = "kargo.akuity.io/managed-by-stage"
+
+	// AnnotationKeyPausePromotions is an annotation key that can be set on a
+	// Stage resource to pause the execution of Promotions to that Stage. When
+	// the value of the annotation is "true", the controller will not make any
+	// further progress on Promotions to the Stage until the annotation is
+	// removed or set to any other value.
+	AnnotationKeyPausePromotions = "kargo.akuity.io/pause-promotions"
+
+	// AnnotationKeyAcknowledgeDrift is an annotation key that can be set on a
+	// Stage resource to acknowledge the drift described by its status, thereby
+	// allowing Promotions to the Stage to continue. The value of the annotation
+	// must be the ID of the drifted commit, so that an acknowledgement does not
+	// extend to drift that is detected later.
+	AnnotationKeyAcknowledgeDrift = "kargo.akuity.io/acknowledge-drift"
+
+	// AnnotationKeyUpstreamStages is an annotation key that is set by the
+	// controller on automatically created Promotion resources. Its value is a
+	// comma-separated list of the upstream Stages in which the Freight being
+	// promoted had been verified, thereby making it available to the Stage. The
+	// annotation is absent if the Freight was available directly from its
+	// origin.
+	AnnotationKeyUpstreamStages = "kargo.akuity.io/upstream-stages"
+
+	// AnnotationKeyPromotionRequest is an annotation key that is set on
+	// Promotion resources created in response to a request, e.g. from the API or
+	// from the controller's auto-promotion. Its value is the idempotency key of
+	// the request, which prevents the same request from producing more than one
+	// Promotion when it is replayed.
+	AnnotationKeyPromotionRequest = "kargo.akuity.io/promotion-request"
+
+	// AnnotationKeyReplayPromotionRequests is an annotation key that can be set
+	// on a Stage resource to request that the journaled requests to promote to
+	// the Stage are replayed. The value of the annotation must be a timestamp in
+	// RFC 3339 format. Requests accepted at or after that time are replayed,
+	// unless a Promotion was already created for them. Every distinct value is
+	// handled once.
+	AnnotationKeyReplayPromotionRequests = "kargo.akuity.io/replay-promotion-requests"
+
+	// AnnotationKeyRefreshes is an annotation key that is set by the controller
+	// on Freight resources whose creation was deferred by a Warehouse's
+	// FreightBatchWindow. Its value is a comma-separated list of the refresh
+	// requests (values of the AnnotationKeyRefresh annotation) that were handled
+	// while the batch window was open and therefore contributed to the Freight.
+	AnnotationKeyRefreshes = "kargo.akuity.io/refreshes"
+
+	// AnnotationKeyAllowDowngrade is an annotation key that can be set on a
+	// Promotion resource to have it executed even if it would move the Stage
+	// back to an older version of one or more of its images while the Stage
+	// prevents downgrades. This permits deliberate rollbacks. The value of the
+	// annotation must be "true" for it to take effect.
+	AnnotationKeyAllowDowngrade = "kargo.akuity.io/allow-downgrade"
+
+	// AnnotationKeyForceSync is an annotation key that can be set on a
+	// Promotion resource to have the Argo CD Applications it updates synced even
+	// if the manifests they are synced to did not change, e.g. so that sync
+	// hooks are run again. The value of the annotation must be "true" for it to
+	// take effect.
+	AnnotationKeyForceSync = "kargo.akuity.io/force-sync"
+
+	// AnnotationKeyFailAt is an annotation key that can be set on a Promotion
+	// resource to have a synthetic failure injected into its execution at the
+	// phase named by its value, e.g. "rendered-push:terminal". It exists only
+	// to test how failures are handled and is ignored unless failure injection
+	// has been explicitly enabled for the controller.
+	AnnotationKeyFailAt = "kargo.akuity.io/fail-at"
+
+	// AnnotationKeyApprove is an annotation key that can be set on a Promotion
+	// resource to approve it when the Stage requires Promotions to be approved
+	// before they are executed. The webhook only admits the annotation if the
+	// user setting it is one of the Stage's allowed approvers, and replaces its
+	// value with a record of the approval. Once set, the annotation cannot be
+	// changed or removed.
+	AnnotationKeyApprove = "kargo.akuity.io/approve"
+
+	// AnnotationKeyExtendExpiry is an annotation key that can be set on a
+	// Promotion resource to extend the deadline by which it must be approved
+	// before it expires, as determined by the MaxAge of the approval policy it
+	// is subject to. The value of the annotation must be a positive duration,
+	// e.g. "48h", which is added to the MaxAge. It has no effect on Promotions
+	// that have already expired.
+	AnnotationKeyExtendExpiry = "kargo.akuity.io/extend-expiry"
+
+	// AnnotationKeyArgoCDImageUpdaterApplication is an annotation key that is
+	// set by the controller on Warehouses whose image subscriptions it
+	// maintains on the basis of the Argo CD Image Updater annotations of an
+	// Argo CD Application. The value of the annotation is in the format of
+	// "<namespace>/<name>" and identifies that Application. Removing the
+	// annotation stops the controller from updating the Warehouse.
+	AnnotationKeyArgoCDImageUpdaterApplication = "kargo.akuity.io/argocd-image-updater-application"
+
+	// AnnotationKeyPromote is an annotation key that can be set on an Argo CD
+	// Application to request that the Stage that is authorized to manage the
+	// Application is promoted to the Freight containing the specified images.
+	// The value of the annotation is a comma-separated list of images in the
+	// format of "<repo>:<tag>", "<repo>@<digest>" or "<repo>:<tag>@<digest>".
+	// The controller removes the annotation once it has handled the request.
+	AnnotationKeyPromote = "kargo.akuity.io/promote"
+
+	// AnnotationKeyLastPromotion is an annotation key that is set by the
+	// controller on Argo CD Applications after it created a Promotion in
+	// response to the AnnotationKeyPromote annotation. Its value identifies
+	// that Promotion in the format of "<project>:<promotion>".
+	AnnotationKeyLastPromotion = "kargo.akuity.io/last-promotion"
+
 	// AnnotationValueTrue is a value that can be set on an annotation to
 	// indicate that it applies.
 	AnnotationValueTrue = "true"
@@ -131,3 +243,81 @@ func AbortPromotionAnnotationValue(annotations map[string]string) (*AbortPromoti
 	}
 	return &apr, ok
 }
+
+// ApprovePromotionAnnotationValue returns the value of the AnnotationKeyApprove
+// annotation, and a boolean indicating whether the annotation was present.
+//
+// If the value of the annotation is a JSON object, it is unmarshalled into an
+// ApprovePromotionRequest struct. Otherwise, e.g. if the annotation was just
+// set to "true" by a user and has not been enriched by the webhook yet, an
+// empty ApprovePromotionRequest is returned.
+func ApprovePromotionAnnotationValue(annotations map[string]string) (*ApprovePromotionRequest, bool) {
+	requested, ok := annotations[AnnotationKeyApprove]
+	if !ok {
+		return nil, ok
+	}
+	var apr ApprovePromotionRequest
+	if strings.HasPrefix(strings.TrimSpace(requested), "{") {
+		if err := json.Unmarshal([]byte(requested), &apr); err != nil {
+			return &ApprovePromotionRequest{}, ok
+		}
+	}
+	return &apr, ok
+}
+
+// PromotionsPausedAnnotationValue returns true if the AnnotationKeyPausePromotions
+// annotation is present and set to AnnotationValueTrue.
+func PromotionsPausedAnnotationValue(annotations map[string]string) bool {
+	return annotations[AnnotationKeyPausePromotions] == AnnotationValueTrue
+}
+
+// AcknowledgeDriftAnnotationValue returns the value of the
+// AnnotationKeyAcknowledgeDrift annotation, which is the ID of the drifted
+// commit being acknowledged, and a boolean indicating whether the annotation
+// was present.
+func AcknowledgeDriftAnnotationValue(annotations map[string]string) (string, bool) {
+	commit, ok := annotations[AnnotationKeyAcknowledgeDrift]
+	return commit, ok
+}
+
+// ReplayPromotionRequestsAnnotationValue returns the value of the
+// AnnotationKeyReplayPromotionRequests annotation, and a boolean indicating
+// whether the annotation was present.
+func ReplayPromotionRequestsAnnotationValue(annotations map[string]string) (string, bool) {
+	since, ok := annotations[AnnotationKeyReplayPromotionRequests]
+	return since, ok
+}
+
+// AllowDowngradeAnnotationValue returns true if the AnnotationKeyAllowDowngrade
+// annotation is present and set to AnnotationValueTrue.
+func AllowDowngradeAnnotationValue(annotations map[string]string) bool {
+	return annotations[AnnotationKeyAllowDowngrade] == AnnotationValueTrue
+}
+
+// ForceSyncAnnotationValue returns true if the AnnotationKeyForceSync
+// annotation is present and set to AnnotationValueTrue.
+func ForceSyncAnnotationValue(annotations map[string]string) bool {
+	return annotations[AnnotationKeyForceSync] == AnnotationValueTrue
+}
+
+// FailAtAnnotationValue returns the value of the AnnotationKeyFailAt
+// annotation and a boolean indicating whether the annotation was present.
+func FailAtAnnotationValue(annotations map[string]string) (string, bool) {
+	failAt, ok := annotations[AnnotationKeyFailAt]
+	return failAt, ok
+}
+
+// ExtendExpiryAnnotationValue returns the duration specified by the
+// AnnotationKeyExtendExpiry annotation and a boolean indicating whether the
+// annotation was present with a valid, positive duration.
+func ExtendExpiryAnnotationValue(annotations map[string]string) (time.Duration, bool) {
+	val, ok := annotations[AnnotationKeyExtendExpiry]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
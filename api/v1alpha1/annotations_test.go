@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -126,3 +127,68 @@ func TestAbortPromotionAnnotationValue(t *testing.T) {
 		require.Nil(t, result)
 	})
 }
+
+func TestPromotionsPausedAnnotationValue(t *testing.T) {
+	t.Run("has pause annotation set to true", func(t *testing.T) {
+		require.True(t, PromotionsPausedAnnotationValue(map[string]string{
+			AnnotationKeyPausePromotions: AnnotationValueTrue,
+		}))
+	})
+
+	t.Run("has pause annotation set to other value", func(t *testing.T) {
+		require.False(t, PromotionsPausedAnnotationValue(map[string]string{
+			AnnotationKeyPausePromotions: "false",
+		}))
+	})
+
+	t.Run("does not have pause annotation", func(t *testing.T) {
+		require.False(t, PromotionsPausedAnnotationValue(nil))
+	})
+}
+
+func TestAllowDowngradeAnnotationValue(t *testing.T) {
+	t.Run("has allow downgrade annotation set to true", func(t *testing.T) {
+		require.True(t, AllowDowngradeAnnotationValue(map[string]string{
+			AnnotationKeyAllowDowngrade: AnnotationValueTrue,
+		}))
+	})
+
+	t.Run("has allow downgrade annotation set to other value", func(t *testing.T) {
+		require.False(t, AllowDowngradeAnnotationValue(map[string]string{
+			AnnotationKeyAllowDowngrade: "yes",
+		}))
+	})
+
+	t.Run("does not have allow downgrade annotation", func(t *testing.T) {
+		require.False(t, AllowDowngradeAnnotationValue(nil))
+	})
+}
+
+func TestExtendExpiryAnnotationValue(t *testing.T) {
+	t.Run("has extend expiry annotation set to a duration", func(t *testing.T) {
+		d, ok := ExtendExpiryAnnotationValue(map[string]string{
+			AnnotationKeyExtendExpiry: "48h",
+		})
+		require.True(t, ok)
+		require.Equal(t, 48*time.Hour, d)
+	})
+
+	t.Run("has extend expiry annotation set to an invalid duration", func(t *testing.T) {
+		_, ok := ExtendExpiryAnnotationValue(map[string]string{
+			AnnotationKeyExtendExpiry: "two days",
+		})
+		require.False(t, ok)
+	})
+
+	t.Run("has extend expiry annotation set to a negative duration", func(t *testing.T) {
+		_, ok := ExtendExpiryAnnotationValue(map[string]string{
+			AnnotationKeyExtendExpiry: "-1h",
+		})
+		require.False(t, ok)
+	})
+
+	t.Run("does not have extend expiry annotation", func(t *testing.T) {
+		_, ok := ExtendExpiryAnnotationValue(nil)
+		require.False(t, ok)
+	})
+}
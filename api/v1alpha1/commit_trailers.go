@@ -0,0 +1,133 @@
+package v1alpha1
+
+import (
+	"strings"
+)
+
+const (
+	// CommitTrailerPromotion is the key of the Git commit trailer that records
+	// the Promotion that produced a commit, in the form <namespace>/<name>.
+	CommitTrailerPromotion = "Kargo-Promotion"
+	// CommitTrailerImages is the key of the Git commit trailer that records the
+	// comma-separated list of container images that were promoted, each in the
+	// form <repoURL>:<tag> or <repoURL>@<digest>.
+	CommitTrailerImages = "Kargo-Images"
+	// CommitTrailerCharts is the key of the Git commit trailer that records the
+	// comma-separated list of Helm charts that were promoted, each in the form
+	// <repoURL>/<name>:<version> or, for charts in OCI registries,
+	// <repoURL>:<version>.
+	CommitTrailerCharts = "Kargo-Charts"
+	// CommitTrailerSourceCommits is the key of the Git commit trailer that
+	// records the comma-separated list of IDs of the Git commits that were
+	// promoted.
+	CommitTrailerSourceCommits = "Kargo-Source-SHA"
+)
+
+// CommitTrailers summarizes a Promotion in a form that can be recorded as Git
+// trailers in the message of a commit produced by that Promotion, and parsed
+// back out of it by tools that wish to link to what changed.
+//
+// +protobuf=false
+// +k8s:deepcopy-gen=false
+// +k8s:openapi-gen=false
+type CommitTrailers struct {
+	// Promotion identifies the Promotion that produced the commit, in the form
+	// <namespace>/<name>.
+	Promotion string
+	// Images lists the container images that were promoted, each in the form
+	// <repoURL>:<tag> or <repoURL>@<digest>.
+	Images []string
+	// Charts lists the Helm charts that were promoted, each in the form
+	// <repoURL>/<name>:<version> or, for charts in OCI registries,
+	// <repoURL>:<version>.
+	Charts []string
+	// SourceCommits lists the IDs of the Git commits that were promoted.
+	SourceCommits []string
+}
+
+// IsEmpty returns true if the CommitTrailers record nothing at all.
+func (t CommitTrailers) IsEmpty() bool {
+	return t.Promotion == "" && len(t.Images) == 0 && len(t.Charts) == 0 &&
+		len(t.SourceCommits) == 0
+}
+
+// String returns the CommitTrailers formatted as a block of Git trailers, one
+// per line, or an empty string if the CommitTrailers are empty. Empty
+// trailers are omitted.
+func (t CommitTrailers) String() string {
+	var lines []string
+	if t.Promotion != "" {
+		lines = append(lines, CommitTrailerPromotion+": "+t.Promotion)
+	}
+	if len(t.Images) > 0 {
+		lines = append(lines, CommitTrailerImages+": "+strings.Join(t.Images, ","))
+	}
+	if len(t.Charts) > 0 {
+		lines = append(lines, CommitTrailerCharts+": "+strings.Join(t.Charts, ","))
+	}
+	if len(t.SourceCommits) > 0 {
+		lines = append(
+			lines,
+			CommitTrailerSourceCommits+": "+strings.Join(t.SourceCommits, ","),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AppendTo returns the provided commit message with the CommitTrailers
+// appended to it as a separate, final paragraph. If the CommitTrailers are
+// empty, the message is returned unchanged.
+func (t CommitTrailers) AppendTo(message string) string {
+	trailers := t.String()
+	if trailers == "" {
+		return message
+	}
+	message = strings.TrimRight(message, "\n")
+	if message == "" {
+		return trailers
+	}
+	return message + "\n\n" + trailers
+}
+
+// ParseCommitTrailers parses the CommitTrailers out of the final paragraph of
+// the provided commit message. Trailers with unrecognized keys are ignored, as
+// are any trailers that do not appear in the final paragraph. If the message
+// does not contain any recognized trailers, empty CommitTrailers are returned.
+func ParseCommitTrailers(message string) CommitTrailers {
+	var t CommitTrailers
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	paragraph := message
+	if i := strings.LastIndex(message, "\n\n"); i >= 0 {
+		paragraph = message[i+2:]
+	}
+	for _, line := range strings.Split(paragraph, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case CommitTrailerPromotion:
+			t.Promotion = value
+		case CommitTrailerImages:
+			t.Images = splitTrailerList(value)
+		case CommitTrailerCharts:
+			t.Charts = splitTrailerList(value)
+		case CommitTrailerSourceCommits:
+			t.SourceCommits = splitTrailerList(value)
+		}
+	}
+	return t
+}
+
+// splitTrailerList splits the comma-separated value of a trailer into its
+// non-empty elements.
+func splitTrailerList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitTrailers_String(t *testing.T) {
+	testCases := []struct {
+		name     string
+		trailers CommitTrailers
+		expected string
+	}{
+		{
+			name:     "empty",
+			expected: "",
+		},
+		{
+			name:     "promotion only",
+			trailers: CommitTrailers{Promotion: "kargo-demo/test.01j.abc"},
+			expected: "Kargo-Promotion: kargo-demo/test.01j.abc",
+		},
+		{
+			name: "everything",
+			trailers: CommitTrailers{
+				Promotion: "kargo-demo/test.01j.abc",
+				Images: []string{
+					"ghcr.io/example/app:v1.2.3",
+					"localhost:5000/example/sidecar@sha256:abc",
+				},
+				Charts:        []string{"ghcr.io/example/charts/app:1.4.2"},
+				SourceCommits: []string{"1234567", "89abcde"},
+			},
+			expected: "Kargo-Promotion: kargo-demo/test.01j.abc\n" +
+				"Kargo-Images: ghcr.io/example/app:v1.2.3,localhost:5000/example/sidecar@sha256:abc\n" +
+				"Kargo-Charts: ghcr.io/example/charts/app:1.4.2\n" +
+				"Kargo-Source-SHA: 1234567,89abcde",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, testCase.trailers.String())
+		})
+	}
+}
+
+func TestCommitTrailers_AppendTo(t *testing.T) {
+	trailers := CommitTrailers{Promotion: "kargo-demo/test.01j.abc"}
+	require.Equal(
+		t,
+		"Update image\n\nKargo-Promotion: kargo-demo/test.01j.abc",
+		trailers.AppendTo("Update image\n"),
+	)
+	require.Equal(
+		t,
+		"Kargo-Promotion: kargo-demo/test.01j.abc",
+		trailers.AppendTo(""),
+	)
+	require.Equal(t, "Update image", CommitTrailers{}.AppendTo("Update image"))
+}
+
+func TestParseCommitTrailers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected CommitTrailers
+	}{
+		{
+			name:    "no trailers",
+			message: "Update image\n\nSome details",
+		},
+		{
+			name:     "message consisting of trailers only",
+			message:  "Kargo-Promotion: kargo-demo/test.01j.abc",
+			expected: CommitTrailers{Promotion: "kargo-demo/test.01j.abc"},
+		},
+		{
+			name: "trailers not in final paragraph are ignored",
+			message: "Update image\n\nKargo-Promotion: kargo-demo/old\n\n" +
+				"Signed-off-by: Someone <someone@example.com>",
+		},
+		{
+			name: "unrecognized trailers are ignored",
+			message: "Update image\n\n" +
+				"Signed-off-by: Someone <someone@example.com>\n" +
+				"Kargo-Promotion: kargo-demo/test.01j.abc\r\n" +
+				"Kargo-Images: ghcr.io/example/app:v1.2.3, ,ghcr.io/example/sidecar:v2\n",
+			expected: CommitTrailers{
+				Promotion: "kargo-demo/test.01j.abc",
+				Images:    []string{"ghcr.io/example/app:v1.2.3", "ghcr.io/example/sidecar:v2"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, ParseCommitTrailers(testCase.message))
+		})
+	}
+}
+
+func TestCommitTrailers_roundTrip(t *testing.T) {
+	trailers := CommitTrailers{
+		Promotion: "kargo-demo/test.01j.abc",
+		Images: []string{
+			"ghcr.io/example/app:v1.2.3",
+			"localhost:5000/example/sidecar@sha256:abc",
+			"docker.io/library/nginx:1.27",
+		},
+		SourceCommits: []string{"1234567", "89abcde"},
+	}
+	message := trailers.AppendTo(
+		"Kargo applied multiple changes\n\nIncluding:\n\n" +
+			"  * updated image ghcr.io/example/app to v1.2.3\n" +
+			"  * updated image docker.io/library/nginx to 1.27",
+	)
+	require.Equal(t, trailers, ParseCommitTrailers(message))
+	require.True(t, ParseCommitTrailers("Update image").IsEmpty())
+}
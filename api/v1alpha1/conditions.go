@@ -82,4 +82,70 @@ const (
 	// the Freight has been verified, and the absence of the condition or a
 	// status of "False" indicates that the Freight has not been verified.
 	ConditionTypeVerified = "Verified"
+
+	// ConditionTypeExternallyModified denotes that the last Promotion to a
+	// Stage found the Stage's rendered branch to have been modified by
+	// something other than Kargo, and overwrote the modification because the
+	// Stage's policy is to only warn about it.
+	//
+	// This is a "normal-false" or "negative polarity" condition, meaning
+	// that the presence of the condition with a status of "True" indicates
+	// that a modification was overwritten, and the absence of the condition
+	// indicates that none was.
+	ConditionTypeExternallyModified = "ExternallyModified"
+
+	// ConditionTypeSynced denotes that the Argo CD Applications updated by a
+	// Promotion were synced. Its reason classifies why a sync failed, and its
+	// message relays the message of the sync operation along with the
+	// resources that failed to sync.
+	//
+	// This is a "normal-true" or "positive polarity" condition, meaning that
+	// the presence of the condition with a status of "True" indicates that
+	// the Applications were synced, and a status of "False" indicates that a
+	// sync failed. The condition is absent until a sync operation completes.
+	ConditionTypeSynced = "Synced"
+
+	// ConditionTypeOutputIgnored denotes that manifests rendered by a
+	// Promotion were written to files that the Argo CD Application they were
+	// rendered for does not read, as configured by the path and directory
+	// settings of its source. Its message lists the files.
+	//
+	// This is a "normal-false" or "negative polarity" condition, meaning
+	// that the presence of the condition with a status of "True" indicates
+	// that rendered manifests would be ignored, and the absence of the
+	// condition indicates that none would be.
+	ConditionTypeOutputIgnored = "OutputIgnored"
+
+	// ConditionTypeChildLimitExceeded denotes that an object, such as the
+	// ConfigMap holding the transcript of a Promotion, was not created on
+	// behalf of the Promotion because the limit of objects that may be
+	// created on behalf of a single Promotion was reached. Its message names
+	// the object.
+	//
+	// This is a "normal-false" or "negative polarity" condition, meaning
+	// that the presence of the condition with a status of "True" indicates
+	// that an object was not created, and the absence of the condition
+	// indicates that all objects were.
+	ConditionTypeChildLimitExceeded = "ChildLimitExceeded"
+
+	// ConditionTypeRenderWarnings denotes that the tools rendering the
+	// manifests of a Promotion, such as Kustomize, emitted warnings, e.g.
+	// about the use of deprecated fields. Its message lists the warnings.
+	//
+	// This is a "normal-false" or "negative polarity" condition, meaning
+	// that the presence of the condition with a status of "True" indicates
+	// that warnings were emitted, and the absence of the condition indicates
+	// that none were.
+	ConditionTypeRenderWarnings = "RenderWarnings"
+
+	// ConditionTypeStepsFailed denotes that steps of a Promotion failed, but
+	// that the Promotion succeeded regardless because the steps were
+	// configured to continue on error, e.g. a post-promotion hook run by the
+	// run-job step. Its message names the steps and why they failed.
+	//
+	// This is a "normal-false" or "negative polarity" condition, meaning
+	// that the presence of the condition with a status of "True" indicates
+	// that steps failed, and the absence of the condition indicates that
+	// none did.
+	ConditionTypeStepsFailed = "StepsFailed"
 )
@@ -25,6 +25,9 @@ const (
 	AnnotationKeyEventVerificationStartTime  = "event.kargo.akuity.io/verification-start-time"
 	AnnotationKeyEventVerificationFinishTime = "event.kargo.akuity.io/verification-finish-time"
 	AnnotationKeyEventApplications           = "event.kargo.akuity.io/applications"
+	AnnotationKeyEventStageMetadata          = "event.kargo.akuity.io/stage-metadata"
+	AnnotationKeyEventGitIdentity            = "event.kargo.akuity.io/git-identity"
+	AnnotationKeyEventOverlayGitIdentities   = "event.kargo.akuity.io/overlay-git-identities"
 )
 
 const (
@@ -33,6 +36,10 @@ const (
 	EventReasonPromotionFailed                 = "PromotionFailed"
 	EventReasonPromotionErrored                = "PromotionErrored"
 	EventReasonPromotionAborted                = "PromotionAborted"
+	EventReasonPromotionSkipped                = "PromotionSkipped"
+	EventReasonPromotionExpired                = "PromotionExpired"
+	EventReasonPromotionRenderWarnings         = "PromotionRenderWarnings"
+	EventReasonPromotionReconcileOutcome       = "PromotionReconcileOutcome"
 	EventReasonFreightApproved                 = "FreightApproved"
 	EventReasonFreightVerificationSucceeded    = "FreightVerificationSucceeded"
 	EventReasonFreightVerificationFailed       = "FreightVerificationFailed"
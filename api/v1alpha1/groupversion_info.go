@@ -31,6 +31,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterPromotionTaskList{},
 		&Freight{},
 		&FreightList{},
+		&KargoConfig{},
+		&KargoConfigList{},
 		&Stage{},
 		&StageList{},
 		&Project{},
@@ -0,0 +1,196 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultCommitMessageMaxImages is the default maximum number of images
+	// itemized in commit messages generated by promotion steps.
+	DefaultCommitMessageMaxImages = 20
+	// DefaultStatusMaxImages is the default maximum number of images listed in
+	// the image digest of a Promotion's status.
+	DefaultStatusMaxImages = 10
+	// DefaultImageSoftLimit is the default number of images above which the
+	// creation of Freight is accompanied by a warning.
+	DefaultImageSoftLimit = 50
+	// DefaultImageHardLimit is the default number of images above which the
+	// creation of Freight is rejected.
+	DefaultImageHardLimit = 500
+	// DefaultExecTimeout is the default maximum amount of time an ExecCommand
+	// may run for.
+	DefaultExecTimeout = time.Minute
+	// DefaultExecMaxOutputBytes is the default maximum number of bytes an
+	// ExecCommand may write to its standard output.
+	DefaultExecMaxOutputBytes = 10 * 1024 * 1024
+	// DefaultMaxRepoSize is the default maximum size, in bytes, of a Git
+	// repository that the git-clone promotion step clones.
+	DefaultMaxRepoSize = 1024 * 1024 * 1024
+	// DefaultMaxRenderedOutputSize is the default maximum size, in bytes, of
+	// the manifests that the kustomize-build promotion step renders.
+	DefaultMaxRenderedOutputSize = 50 * 1024 * 1024
+)
+
+// GetKargoConfig returns a pointer to the KargoConfig resource that is read by
+// the controller. If no such resource is found, nil is returned instead.
+func GetKargoConfig(ctx context.Context, c client.Client) (*KargoConfig, error) {
+	cfg := KargoConfig{}
+	if err := c.Get(ctx, types.NamespacedName{Name: KargoConfigName}, &cfg); err != nil {
+		if err = client.IgnoreNotFound(err); err == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting KargoConfig %q: %w", KargoConfigName, err)
+	}
+	return &cfg, nil
+}
+
+// GetCommitMessageMaxImages returns the maximum number of images itemized in
+// commit messages. It is safe to call on a nil ImageLimits.
+func (l *ImageLimits) GetCommitMessageMaxImages() int {
+	if l == nil || l.CommitMessageMaxImages == nil {
+		return DefaultCommitMessageMaxImages
+	}
+	return int(*l.CommitMessageMaxImages)
+}
+
+// GetStatusMaxImages returns the maximum number of images listed in the image
+// digest of a Promotion's status. It is safe to call on a nil ImageLimits.
+func (l *ImageLimits) GetStatusMaxImages() int {
+	if l == nil || l.StatusMaxImages == nil {
+		return DefaultStatusMaxImages
+	}
+	return int(*l.StatusMaxImages)
+}
+
+// GetSoftLimit returns the number of images above which the creation of
+// Freight is accompanied by a warning, or 0 if there is no such limit. It is
+// safe to call on a nil ImageLimits.
+func (l *ImageLimits) GetSoftLimit() int {
+	if l == nil || l.SoftLimit == nil {
+		return DefaultImageSoftLimit
+	}
+	return int(*l.SoftLimit)
+}
+
+// GetHardLimit returns the number of images above which the creation of
+// Freight is rejected, or 0 if there is no such limit. It is safe to call on a
+// nil ImageLimits.
+func (l *ImageLimits) GetHardLimit() int {
+	if l == nil || l.HardLimit == nil {
+		return DefaultImageHardLimit
+	}
+	return int(*l.HardLimit)
+}
+
+// GetCommand returns the ExecCommand with the provided name, or nil if the
+// command may not be executed. It is safe to call on a nil ExecPolicy, which
+// allows no commands.
+func (p *ExecPolicy) GetCommand(name string) *ExecCommand {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Commands {
+		if p.Commands[i].Name == name {
+			return &p.Commands[i]
+		}
+	}
+	return nil
+}
+
+// GetTimeout returns the maximum amount of time the command may run for.
+func (c *ExecCommand) GetTimeout() time.Duration {
+	if c.Timeout == nil || c.Timeout.Duration <= 0 {
+		return DefaultExecTimeout
+	}
+	return c.Timeout.Duration
+}
+
+// GetMaxOutputBytes returns the maximum number of bytes the command may write
+// to its standard output.
+func (c *ExecCommand) GetMaxOutputBytes() int {
+	if c.MaxOutputBytes == nil {
+		return DefaultExecMaxOutputBytes
+	}
+	return int(*c.MaxOutputBytes)
+}
+
+// EffectiveResourceLimits returns the ResourceLimits that apply to Promotions
+// to a Stage, given the ones specified by the KargoConfig resource and the
+// ones specified by the Stage, either of which may be nil. A Stage may only
+// lower the global limits. Limits the Stage does not specify, or specifies as
+// 0, fall back to the global limits.
+func EffectiveResourceLimits(global, stage *ResourceLimits) *ResourceLimits {
+	if stage == nil {
+		return global
+	}
+	limits := global.DeepCopy()
+	if limits == nil {
+		limits = &ResourceLimits{}
+	}
+	if lowersLimit(stage.MaxRepoSize, global.GetMaxRepoSize()) {
+		limits.MaxRepoSize = stage.MaxRepoSize
+	}
+	if lowersLimit(stage.MaxRenderedOutputSize, global.GetMaxRenderedOutputSize()) {
+		limits.MaxRenderedOutputSize = stage.MaxRenderedOutputSize
+	}
+	return limits
+}
+
+// lowersLimit returns true if the provided limit specified by a Stage is lower
+// than the provided global limit, of which 0 means there is no limit.
+func lowersLimit(stage *resource.Quantity, global int64) bool {
+	if stage == nil || stage.Sign() <= 0 {
+		return false
+	}
+	return global == 0 || stage.Value() < global
+}
+
+// GetMaxRepoSize returns the maximum size, in bytes, of a Git repository that
+// the git-clone promotion step clones, or 0 if there is no such limit. It is
+// safe to call on a nil ResourceLimits.
+func (l *ResourceLimits) GetMaxRepoSize() int64 {
+	if l == nil || l.MaxRepoSize == nil {
+		return DefaultMaxRepoSize
+	}
+	return l.MaxRepoSize.Value()
+}
+
+// GetMaxRenderedOutputSize returns the maximum size, in bytes, of the
+// manifests that the kustomize-build promotion step renders, or 0 if there is
+// no such limit. It is safe to call on a nil ResourceLimits.
+func (l *ResourceLimits) GetMaxRenderedOutputSize() int64 {
+	if l == nil || l.MaxRenderedOutputSize == nil {
+		return DefaultMaxRenderedOutputSize
+	}
+	return l.MaxRenderedOutputSize.Value()
+}
+
+// AllowsHost returns true if remote Kustomize bases may be fetched from the
+// provided host. It is safe to call on a nil RemoteBasePolicy, which allows
+// all hosts.
+func (p *RemoteBasePolicy) AllowsHost(host string) bool {
+	if p == nil {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range p.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasPrefix(suffix, ".") && strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
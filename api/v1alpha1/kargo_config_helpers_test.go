@@ -0,0 +1,193 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetKargoConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, SchemeBuilder.AddToScheme(scheme))
+
+	t.Run("not found", func(t *testing.T) {
+		cfg, err := GetKargoConfig(
+			context.Background(),
+			fake.NewClientBuilder().WithScheme(scheme).Build(),
+		)
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		cfg, err := GetKargoConfig(
+			context.Background(),
+			fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&KargoConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: KargoConfigName},
+					Spec:       KargoConfigSpec{PausePromotions: true},
+				},
+			).Build(),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+		require.True(t, cfg.Spec.PausePromotions)
+	})
+}
+
+func TestImageLimits(t *testing.T) {
+	testCases := []struct {
+		name                           string
+		limits                         *ImageLimits
+		expectedCommitMessageMaxImages int
+		expectedStatusMaxImages        int
+		expectedSoftLimit              int
+		expectedHardLimit              int
+	}{
+		{
+			name:                           "nil",
+			expectedCommitMessageMaxImages: DefaultCommitMessageMaxImages,
+			expectedStatusMaxImages:        DefaultStatusMaxImages,
+			expectedSoftLimit:              DefaultImageSoftLimit,
+			expectedHardLimit:              DefaultImageHardLimit,
+		},
+		{
+			name:                           "empty",
+			limits:                         &ImageLimits{},
+			expectedCommitMessageMaxImages: DefaultCommitMessageMaxImages,
+			expectedStatusMaxImages:        DefaultStatusMaxImages,
+			expectedSoftLimit:              DefaultImageSoftLimit,
+			expectedHardLimit:              DefaultImageHardLimit,
+		},
+		{
+			name: "specified",
+			limits: &ImageLimits{
+				CommitMessageMaxImages: ptr.To[int32](1),
+				StatusMaxImages:        ptr.To[int32](2),
+				SoftLimit:              ptr.To[int32](0),
+				HardLimit:              ptr.To[int32](3),
+			},
+			expectedCommitMessageMaxImages: 1,
+			expectedStatusMaxImages:        2,
+			expectedSoftLimit:              0,
+			expectedHardLimit:              3,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expectedCommitMessageMaxImages, testCase.limits.GetCommitMessageMaxImages())
+			require.Equal(t, testCase.expectedStatusMaxImages, testCase.limits.GetStatusMaxImages())
+			require.Equal(t, testCase.expectedSoftLimit, testCase.limits.GetSoftLimit())
+			require.Equal(t, testCase.expectedHardLimit, testCase.limits.GetHardLimit())
+		})
+	}
+}
+
+func TestExecPolicy_GetCommand(t *testing.T) {
+	var policy *ExecPolicy
+	require.Nil(t, policy.GetCommand("render"))
+
+	policy = &ExecPolicy{
+		Commands: []ExecCommand{{Name: "render", Path: "/usr/local/bin/render"}},
+	}
+	require.Nil(t, policy.GetCommand("other"))
+	cmd := policy.GetCommand("render")
+	require.NotNil(t, cmd)
+	require.Equal(t, "/usr/local/bin/render", cmd.Path)
+	require.Equal(t, DefaultExecTimeout, cmd.GetTimeout())
+	require.Equal(t, DefaultExecMaxOutputBytes, cmd.GetMaxOutputBytes())
+
+	cmd.Timeout = &metav1.Duration{Duration: 10 * time.Second}
+	cmd.MaxOutputBytes = ptr.To[int32](1024)
+	require.Equal(t, 10*time.Second, cmd.GetTimeout())
+	require.Equal(t, 1024, cmd.GetMaxOutputBytes())
+}
+
+func TestRemoteBasePolicy_AllowsHost(t *testing.T) {
+	var policy *RemoteBasePolicy
+	require.True(t, policy.AllowsHost("github.com"))
+
+	policy = &RemoteBasePolicy{AllowedHosts: []string{"GitHub.com", "*.example.com"}}
+	require.True(t, policy.AllowsHost("github.com"))
+	require.True(t, policy.AllowsHost("git.example.com"))
+	require.False(t, policy.AllowsHost("example.com"))
+	require.False(t, policy.AllowsHost("gitlab.com"))
+	require.False(t, policy.AllowsHost(""))
+
+	require.False(t, (&RemoteBasePolicy{}).AllowsHost("github.com"))
+}
+
+func TestResourceLimits(t *testing.T) {
+	var limits *ResourceLimits
+	require.Equal(t, int64(DefaultMaxRepoSize), limits.GetMaxRepoSize())
+	require.Equal(t, int64(DefaultMaxRenderedOutputSize), limits.GetMaxRenderedOutputSize())
+
+	limits = &ResourceLimits{
+		MaxRepoSize:           ptr.To(resource.MustParse("2Gi")),
+		MaxRenderedOutputSize: ptr.To(resource.MustParse("0")),
+	}
+	require.Equal(t, int64(2*1024*1024*1024), limits.GetMaxRepoSize())
+	require.Equal(t, int64(0), limits.GetMaxRenderedOutputSize())
+}
+
+func TestEffectiveResourceLimits(t *testing.T) {
+	global := &ResourceLimits{
+		MaxRepoSize:           ptr.To(resource.MustParse("2Gi")),
+		MaxRenderedOutputSize: ptr.To(resource.MustParse("10Mi")),
+	}
+
+	require.Nil(t, EffectiveResourceLimits(nil, nil))
+	require.Same(t, global, EffectiveResourceLimits(global, nil))
+
+	t.Run("stage lowers global limits", func(t *testing.T) {
+		limits := EffectiveResourceLimits(global, &ResourceLimits{
+			MaxRenderedOutputSize: ptr.To(resource.MustParse("5Mi")),
+		})
+		require.Equal(t, int64(2*1024*1024*1024), limits.GetMaxRepoSize())
+		require.Equal(t, int64(5*1024*1024), limits.GetMaxRenderedOutputSize())
+		// The global limits are left unchanged
+		require.Equal(t, int64(10*1024*1024), global.GetMaxRenderedOutputSize())
+	})
+
+	t.Run("stage cannot raise global limits", func(t *testing.T) {
+		limits := EffectiveResourceLimits(global, &ResourceLimits{
+			MaxRepoSize:           ptr.To(resource.MustParse("4Gi")),
+			MaxRenderedOutputSize: ptr.To(resource.MustParse("100Mi")),
+		})
+		require.Equal(t, int64(2*1024*1024*1024), limits.GetMaxRepoSize())
+		require.Equal(t, int64(10*1024*1024), limits.GetMaxRenderedOutputSize())
+	})
+
+	t.Run("stage cannot raise default limits", func(t *testing.T) {
+		limits := EffectiveResourceLimits(nil, &ResourceLimits{
+			MaxRepoSize:           ptr.To(resource.MustParse("4Gi")),
+			MaxRenderedOutputSize: ptr.To(resource.MustParse("5Mi")),
+		})
+		require.Equal(t, int64(DefaultMaxRepoSize), limits.GetMaxRepoSize())
+		require.Equal(t, int64(5*1024*1024), limits.GetMaxRenderedOutputSize())
+	})
+
+	t.Run("stage limit of 0 inherits global limit", func(t *testing.T) {
+		limits := EffectiveResourceLimits(global, &ResourceLimits{
+			MaxRepoSize:           ptr.To(resource.MustParse("0")),
+			MaxRenderedOutputSize: ptr.To(resource.MustParse("0")),
+		})
+		require.Equal(t, int64(2*1024*1024*1024), limits.GetMaxRepoSize())
+		require.Equal(t, int64(10*1024*1024), limits.GetMaxRenderedOutputSize())
+	})
+
+	t.Run("stage limits apply if global limits are disabled", func(t *testing.T) {
+		limits := EffectiveResourceLimits(
+			&ResourceLimits{MaxRepoSize: ptr.To(resource.MustParse("0"))},
+			&ResourceLimits{MaxRepoSize: ptr.To(resource.MustParse("4Gi"))},
+		)
+		require.Equal(t, int64(4*1024*1024*1024), limits.GetMaxRepoSize())
+	})
+}
@@ -0,0 +1,233 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KargoConfigName is the name of the one KargoConfig resource that is read by
+// the controller. KargoConfig resources with any other name are rejected.
+const KargoConfigName = "kargo"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name=Promotions Paused,type=boolean,JSONPath=`.spec.pausePromotions`
+// +kubebuilder:printcolumn:name=Age,type=date,JSONPath=`.metadata.creationTimestamp`
+
+// KargoConfig is a cluster-scoped singleton resource holding configuration
+// that applies to the Kargo controller as a whole. Settings specified here take
+// precedence over those specified when the controller was installed, while
+// settings specified on individual Stages (e.g. through annotations) take
+// precedence over those specified here.
+type KargoConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec describes the configuration of the Kargo controller.
+	Spec KargoConfigSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// KargoConfigSpec describes the configuration of the Kargo controller.
+type KargoConfigSpec struct {
+	// PausePromotions indicates whether the execution of all Promotions should
+	// be paused. Paused Promotions resume where they left off once this is
+	// disabled again. Changes to this setting take effect without restarting
+	// the controller.
+	PausePromotions bool `json:"pausePromotions,omitempty" protobuf:"varint,1,opt,name=pausePromotions"`
+	// GitClient describes how the controller performs network operations
+	// against Git hosts. Changes to these settings take effect only after the
+	// controller has been restarted.
+	GitClient *GitClientConfig `json:"gitClient,omitempty" protobuf:"bytes,2,opt,name=gitClient"`
+	// RepoPolicy restricts the Git repositories that Promotions may access.
+	// Changes to this setting take effect without restarting the controller.
+	RepoPolicy *RepoPolicy `json:"repoPolicy,omitempty" protobuf:"bytes,3,opt,name=repoPolicy"`
+	// ImageLimits limits the number of container images that Freight may
+	// reference and how many of them are itemized in commit messages and in
+	// the status of Promotions. Changes to these settings take effect without
+	// restarting the controller.
+	ImageLimits *ImageLimits `json:"imageLimits,omitempty" protobuf:"bytes,4,opt,name=imageLimits"`
+	// ExecPolicy specifies the commands that the exec-render promotion step may
+	// execute. If it is not specified, no commands may be executed. Changes to
+	// this setting take effect without restarting the controller.
+	ExecPolicy *ExecPolicy `json:"execPolicy,omitempty" protobuf:"bytes,5,opt,name=execPolicy"`
+	// ResourceLimits limits the size of the Git repositories that Promotions
+	// clone and of the manifests they render. Stages may override these limits.
+	// Changes to these settings take effect without restarting the controller.
+	ResourceLimits *ResourceLimits `json:"resourceLimits,omitempty" protobuf:"bytes,6,opt,name=resourceLimits"`
+	// RemoteBasePolicy restricts the hosts that the kustomize-build and
+	// kustomize-set-image promotion steps may fetch remote Kustomize bases
+	// from. If it is not specified, remote bases may be fetched from any host,
+	// subject to the RepoPolicy. Changes to this setting take effect without
+	// restarting the controller.
+	RemoteBasePolicy *RemoteBasePolicy `json:"remoteBasePolicy,omitempty" protobuf:"bytes,7,opt,name=remoteBasePolicy"`
+}
+
+// RemoteBasePolicy restricts the hosts that remote Kustomize bases, i.e.
+// entries of Kustomization files referring to directories of Git
+// repositories, may be fetched from.
+type RemoteBasePolicy struct {
+	// AllowedHosts are the hosts that remote bases may be fetched from. A host
+	// prefixed with "*." also matches all of its subdomains. If empty, remote
+	// bases may not be fetched from any host.
+	AllowedHosts []string `json:"allowedHosts,omitempty" protobuf:"bytes,1,rep,name=allowedHosts"`
+}
+
+// ResourceLimits limits the size of the Git repositories that Promotions clone
+// and of the manifests they render, so that a misconfigured Stage cannot
+// exhaust the disk or memory of the controller. Any limit that is not
+// specified falls back to its default.
+type ResourceLimits struct {
+	// MaxRepoSize is the maximum size of a Git repository that the git-clone
+	// promotion step clones. Where the API of the repository's Git provider
+	// reports its size, larger repositories are not cloned at all. Otherwise,
+	// the clone is aborted as soon as it exceeds this size. A value of 0
+	// disables the limit. Defaults to 1Gi.
+	MaxRepoSize *resource.Quantity `json:"maxRepoSize,omitempty" protobuf:"bytes,1,opt,name=maxRepoSize"`
+	// MaxRenderedOutputSize is the maximum size of the manifests that the
+	// kustomize-build promotion step renders. Promotions rendering larger
+	// manifests fail with a RenderTooLarge message. A value of 0 disables the
+	// limit. Defaults to 50Mi.
+	MaxRenderedOutputSize *resource.Quantity `json:"maxRenderedOutputSize,omitempty" protobuf:"bytes,2,opt,name=maxRenderedOutputSize"`
+}
+
+// ExecPolicy specifies the commands that the exec-render promotion step may
+// execute. Commands are executed by the controller, in the working directory
+// of the Promotion, and must therefore be present in the controller's image.
+type ExecPolicy struct {
+	// Commands are the commands that may be executed. Commands that are not
+	// listed may not be executed.
+	//
+	// +listType=map
+	// +listMapKey=name
+	Commands []ExecCommand `json:"commands,omitempty" protobuf:"bytes,1,rep,name=commands"`
+}
+
+// ExecCommand describes a command that the exec-render promotion step may
+// execute.
+type ExecCommand struct {
+	// Name is the name by which promotion steps refer to the command.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Path is the absolute path of the command's executable.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path" protobuf:"bytes,2,opt,name=path"`
+	// Args are arguments that are always passed to the command, ahead of any
+	// arguments specified by promotion steps.
+	Args []string `json:"args,omitempty" protobuf:"bytes,3,rep,name=args"`
+	// AllowArgs indicates whether promotion steps may pass further arguments to
+	// the command.
+	AllowArgs bool `json:"allowArgs,omitempty" protobuf:"varint,4,opt,name=allowArgs"`
+	// Env are the environment variables of the command's process. The process
+	// does not inherit any environment variables from the controller.
+	Env []ExecEnvVar `json:"env,omitempty" protobuf:"bytes,5,rep,name=env"`
+	// Timeout is the maximum amount of time the command may run for before it
+	// is killed. Defaults to 1m.
+	//
+	// +kubebuilder:validation:Format=duration
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,6,opt,name=timeout"`
+	// MaxOutputBytes is the maximum number of bytes the command may write to
+	// its standard output before it is killed. Defaults to 10MiB.
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxOutputBytes *int32 `json:"maxOutputBytes,omitempty" protobuf:"varint,7,opt,name=maxOutputBytes"`
+}
+
+// ExecEnvVar is an environment variable of the process of an ExecCommand.
+type ExecEnvVar struct {
+	// Name is the name of the environment variable.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Value is the value of the environment variable.
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+}
+
+// ImageLimits limits the number of container images that Freight may
+// reference and how many of them are itemized in commit messages and in the
+// status of Promotions. Any limit that is not specified falls back to its
+// default.
+type ImageLimits struct {
+	// CommitMessageMaxImages is the maximum number of images itemized in commit
+	// messages generated by promotion steps. Any further images are only
+	// counted. Defaults to 20.
+	//
+	// +kubebuilder:validation:Minimum=1
+	CommitMessageMaxImages *int32 `json:"commitMessageMaxImages,omitempty" protobuf:"varint,1,opt,name=commitMessageMaxImages"`
+	// StatusMaxImages is the maximum number of images listed in the image
+	// digest of a Promotion's status. If the promoted Freight references more
+	// images than this, they are also omitted from the Freight recorded in the
+	// Promotion's status and must be retrieved from the Freight itself.
+	// Defaults to 10.
+	//
+	// +kubebuilder:validation:Minimum=1
+	StatusMaxImages *int32 `json:"statusMaxImages,omitempty" protobuf:"varint,2,opt,name=statusMaxImages"`
+	// SoftLimit is the number of images above which the creation of Freight
+	// is accompanied by a warning. A value of 0 disables the warning. Defaults
+	// to 50.
+	//
+	// +kubebuilder:validation:Minimum=0
+	SoftLimit *int32 `json:"softLimit,omitempty" protobuf:"varint,3,opt,name=softLimit"`
+	// HardLimit is the number of images above which the creation of Freight is
+	// rejected. A value of 0 disables the limit. Defaults to 500.
+	//
+	// +kubebuilder:validation:Minimum=0
+	HardLimit *int32 `json:"hardLimit,omitempty" protobuf:"varint,4,opt,name=hardLimit"`
+}
+
+// RepoPolicy restricts the Git repositories that Promotions may access. It is
+// consulted before credentials for a repository are looked up, and Promotions
+// that attempt to access a repository that is not allowed are Errored with a
+// RepoNotAllowed message.
+//
+// Patterns are matched against normalized repository URLs, e.g.
+// "https://github.com/example/repo" or "ssh://git@github.com/example/repo".
+// They are glob patterns (optionally prefixed with "glob:"), in which "*" does
+// not match "/", or regular expressions (prefixed with "regex:" or
+// "regexp:").
+type RepoPolicy struct {
+	// Allow is a list of patterns of repository URLs that may be accessed. If
+	// empty, all repositories that are not denied may be accessed.
+	Allow []string `json:"allow,omitempty" protobuf:"bytes,1,rep,name=allow"`
+	// Deny is a list of patterns of repository URLs that may not be accessed.
+	// Deny patterns take precedence over Allow patterns.
+	Deny []string `json:"deny,omitempty" protobuf:"bytes,2,rep,name=deny"`
+}
+
+// GitClientConfig describes how the controller performs network operations
+// (e.g. clone, fetch, and push) against Git hosts. Any setting that is not
+// specified falls back to the value specified when the controller was
+// installed.
+type GitClientConfig struct {
+	// MaxConcurrentOpsPerHost is the maximum number of network operations the
+	// controller may perform concurrently against any single Git host. A value
+	// of 0 means no limit.
+	//
+	// +kubebuilder:validation:Minimum=0
+	MaxConcurrentOpsPerHost *int32 `json:"maxConcurrentOpsPerHost,omitempty" protobuf:"varint,1,opt,name=maxConcurrentOpsPerHost"`
+	// MaxOpsPerMinutePerHost is the maximum number of network operations the
+	// controller may start against any single Git host per minute. A value of
+	// 0 means no limit.
+	//
+	// +kubebuilder:validation:Minimum=0
+	MaxOpsPerMinutePerHost *int32 `json:"maxOpsPerMinutePerHost,omitempty" protobuf:"varint,2,opt,name=maxOpsPerMinutePerHost"`
+	// NetworkMaxAttempts is the maximum number of attempts, including the
+	// first, the controller makes for any network operation that fails for
+	// reasons that appear to be transient.
+	//
+	// +kubebuilder:validation:Minimum=1
+	NetworkMaxAttempts *int32 `json:"networkMaxAttempts,omitempty" protobuf:"varint,3,opt,name=networkMaxAttempts"`
+}
+
+// +kubebuilder:object:root=true
+
+// KargoConfigList contains a list of KargoConfigs.
+type KargoConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	Items           []KargoConfig `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
@@ -13,12 +13,17 @@ const (
 	ProjectSecretLabelKey = "kargo.akuity.io/project-secret" // nolint: gosec
 
 	// Kargo core API
+	ComponentLabelKey         = "kargo.akuity.io/component"
 	FreightCollectionLabelKey = "kargo.akuity.io/freight-collection"
 	ProjectLabelKey           = "kargo.akuity.io/project"
 	PromotionLabelKey         = "kargo.akuity.io/promotion"
 	ShardLabelKey             = "kargo.akuity.io/shard"
 	StageLabelKey             = "kargo.akuity.io/stage"
 
+	// Promotion requests
+	PromotionRequestLabelKey = "kargo.akuity.io/promotion-request"
+	PromotionSourceLabelKey  = "kargo.akuity.io/promotion-source"
+
 	// AnalysisRunTemplate labels
 	AnalysisRunTemplateLabelKey         = "kargo.akuity.io/analysis-run-template"
 	AnalysisRunTemplateLabelValueConfig = "config"
@@ -2,8 +2,10 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -66,6 +68,43 @@ func (r *AbortPromotionRequest) String() string {
 	return string(b)
 }
 
+// ApprovePromotionRequest is the record of the approval of a Promotion that the
+// webhook stores in the AnnotationKeyApprove annotation.
+//
+// +protobuf=false
+// +k8s:deepcopy-gen=false
+// +k8s:openapi-gen=false
+type ApprovePromotionRequest struct {
+	// Actor is the user who approved the Promotion.
+	Actor string `json:"actor,omitempty"`
+	// ApprovedAt is the time at which the Promotion was approved.
+	ApprovedAt *metav1.Time `json:"approvedAt,omitempty"`
+	// SpecHash is the hash of the Promotion's spec at the time it was
+	// approved.
+	SpecHash string `json:"specHash,omitempty"`
+}
+
+// String returns the JSON string representation of the
+// ApprovePromotionRequest, or an empty string if the ApprovePromotionRequest
+// is nil.
+func (r *ApprovePromotionRequest) String() string {
+	if r == nil {
+		return ""
+	}
+	b, _ := json.Marshal(r)
+	if b == nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Hash returns a hash of the PromotionSpec, which changes whenever any part of
+// the spec does.
+func (s *PromotionSpec) Hash() string {
+	b, _ := json.Marshal(s)
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
+
 // GetPromotion returns a pointer to the Promotion resource specified by the
 // namespacedName argument. If no such resource is found, nil is returned
 // instead.
@@ -208,3 +247,23 @@ func ComparePromotionPhase(a, b PromotionPhase) int {
 		return 0
 	}
 }
+
+// NewImageSetDigest returns an ImageSetDigest of the provided images, listing
+// at most maxImages of them.
+func NewImageSetDigest(images []Image, maxImages int) *ImageSetDigest {
+	refs := make([]string, len(images))
+	for i, image := range images {
+		refs[i] = fmt.Sprintf("%s:%s@%s", image.RepoURL, image.Tag, image.Digest)
+	}
+	// Sort the references, so that the hash does not depend on the order of the
+	// images.
+	slices.Sort(refs)
+	digest := &ImageSetDigest{
+		Count: int32(len(images)), // nolint: gosec
+		Hash:  fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(strings.Join(refs, "\n")))),
+	}
+	if len(images) > 0 {
+		digest.Images = slices.Clone(images[:min(len(images), maxImages)])
+	}
+	return digest
+}
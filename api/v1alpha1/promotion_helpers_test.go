@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -440,3 +441,51 @@ func TestComparePromotionPhase(t *testing.T) {
 		})
 	}
 }
+
+func TestNewImageSetDigest(t *testing.T) {
+	images := []Image{
+		{RepoURL: "fake-repo-a", Tag: "v1.0.0", Digest: "fake-digest-a"},
+		{RepoURL: "fake-repo-b", Tag: "v2.0.0", Digest: "fake-digest-b"},
+		{RepoURL: "fake-repo-c", Tag: "v3.0.0", Digest: "fake-digest-c"},
+	}
+
+	t.Run("no images", func(t *testing.T) {
+		digest := NewImageSetDigest(nil, 10)
+		require.Equal(t, int32(0), digest.Count)
+		require.NotEmpty(t, digest.Hash)
+		require.Nil(t, digest.Images)
+	})
+
+	t.Run("fewer images than limit", func(t *testing.T) {
+		digest := NewImageSetDigest(images, 4)
+		require.Equal(t, int32(3), digest.Count)
+		require.Equal(t, images, digest.Images)
+	})
+
+	t.Run("as many images as limit", func(t *testing.T) {
+		digest := NewImageSetDigest(images, 3)
+		require.Equal(t, int32(3), digest.Count)
+		require.Equal(t, images, digest.Images)
+	})
+
+	t.Run("more images than limit", func(t *testing.T) {
+		digest := NewImageSetDigest(images, 2)
+		require.Equal(t, int32(3), digest.Count)
+		require.Equal(t, images[:2], digest.Images)
+		// The hash covers all images, not just the listed ones
+		require.Equal(t, NewImageSetDigest(images, 3).Hash, digest.Hash)
+	})
+
+	t.Run("hash does not depend on order", func(t *testing.T) {
+		reversed := slices.Clone(images)
+		slices.Reverse(reversed)
+		require.Equal(t, NewImageSetDigest(images, 1).Hash, NewImageSetDigest(reversed, 1).Hash)
+	})
+
+	t.Run("hash changes with images", func(t *testing.T) {
+		changed := slices.Clone(images)
+		changed[1].Digest = "fake-digest-d"
+		require.NotEqual(t, NewImageSetDigest(images, 1).Hash, NewImageSetDigest(changed, 1).Hash)
+		require.NotEqual(t, NewImageSetDigest(images, 1).Hash, NewImageSetDigest(images[:2], 1).Hash)
+	})
+}
@@ -34,18 +34,78 @@ const (
 	// PromotionPhaseAborted denotes a Promotion that has been aborted by a
 	// user.
 	PromotionPhaseAborted PromotionPhase = "Aborted"
+	// PromotionPhaseSkipped denotes a Promotion that was not executed because
+	// it would have moved the Stage back to an older version of one or more of
+	// its images. Further information can be found in the Promotion's status.
+	PromotionPhaseSkipped PromotionPhase = "Skipped"
+	// PromotionPhaseExpired denotes a Promotion that was not executed because
+	// it was not approved within the maximum age permitted by the approval
+	// policy of its Stage. Further information can be found in the
+	// Promotion's status.
+	PromotionPhaseExpired PromotionPhase = "Expired"
 )
 
 // IsTerminal returns true if the PromotionPhase is a terminal one.
 func (p *PromotionPhase) IsTerminal() bool {
 	switch *p {
-	case PromotionPhaseSucceeded, PromotionPhaseFailed, PromotionPhaseErrored, PromotionPhaseAborted:
+	case PromotionPhaseSucceeded, PromotionPhaseFailed, PromotionPhaseErrored, PromotionPhaseAborted,
+		PromotionPhaseSkipped, PromotionPhaseExpired:
 		return true
 	default:
 		return false
 	}
 }
 
+// ReconcileOutcomeReason is a machine-readable reason for the outcome of a
+// reconciliation of a Promotion. Promotions outside the controller's shard,
+// and duplicate requests to reconcile a Promotion, never reach a
+// reconciliation, so there are no reasons for them.
+type ReconcileOutcomeReason string
+
+const (
+	// ReconcileOutcomeStageNotFound denotes a reconciliation that did nothing
+	// because the Promotion's Stage does not exist (yet).
+	ReconcileOutcomeStageNotFound ReconcileOutcomeReason = "StageNotFound"
+	// ReconcileOutcomeQueued denotes a reconciliation that did nothing because
+	// the Promotion's Stage is not awaiting the Promotion, e.g. because another
+	// Promotion to the Stage is running.
+	ReconcileOutcomeQueued ReconcileOutcomeReason = "Queued"
+	// ReconcileOutcomePaused denotes a reconciliation that did nothing because
+	// Promotions are paused, either globally or for the Promotion's Stage.
+	ReconcileOutcomePaused ReconcileOutcomeReason = "Paused"
+	// ReconcileOutcomeDriftBlocked denotes a reconciliation that did nothing
+	// because the Promotion's Stage has unresolved drift.
+	ReconcileOutcomeDriftBlocked ReconcileOutcomeReason = "DriftBlocked"
+	// ReconcileOutcomeAwaitingApproval denotes a reconciliation that did
+	// nothing because the Promotion has not been approved yet.
+	ReconcileOutcomeAwaitingApproval ReconcileOutcomeReason = "AwaitingApproval"
+	// ReconcileOutcomeProgressed denotes a reconciliation that executed steps
+	// of the Promotion and changed its state.
+	ReconcileOutcomeProgressed ReconcileOutcomeReason = "Progressed"
+	// ReconcileOutcomeNoProgress denotes a reconciliation that checked on the
+	// steps of the Promotion without observing any change in its state, e.g.
+	// because a step is waiting for an Argo CD Application to sync.
+	ReconcileOutcomeNoProgress ReconcileOutcomeReason = "NoProgress"
+	// ReconcileOutcomeDowngradeProtected denotes a reconciliation that skipped
+	// the Promotion because it would have downgraded its Stage.
+	ReconcileOutcomeDowngradeProtected ReconcileOutcomeReason = "DowngradeProtected"
+	// ReconcileOutcomeCompleted denotes a reconciliation that left the
+	// Promotion in any other terminal phase.
+	ReconcileOutcomeCompleted ReconcileOutcomeReason = "Completed"
+)
+
+// ReconcileOutcome describes what a reconciliation of a Promotion did, or why
+// it did nothing.
+type ReconcileOutcome struct {
+	// Reason is a machine-readable reason for the outcome.
+	Reason ReconcileOutcomeReason `json:"reason" protobuf:"bytes,1,opt,name=reason"`
+	// Message is a human-readable description of the outcome.
+	Message string `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+	// Since is the time at which the first of the consecutive
+	// reconciliations that had this outcome completed.
+	Since metav1.Time `json:"since" protobuf:"bytes,3,opt,name=since"`
+}
+
 // +kubebuilder:resource:shortName={promo,promos}
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
@@ -105,6 +165,73 @@ type PromotionSpec struct {
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:items:XValidation:message="Promotion step must have uses set and must not reference a task",rule="has(self.uses) && !has(self.task)"
 	Steps []PromotionStep `json:"steps" protobuf:"bytes,3,rep,name=steps"`
+	// Lanes configures the concurrent execution of steps that are assigned to
+	// lanes.
+	Lanes *PromotionLanes `json:"lanes,omitempty" protobuf:"bytes,5,opt,name=lanes"`
+	// Priority determines the order in which Promotions waiting for their turn
+	// to be executed against the same Stage are executed. Promotions of higher
+	// priority are executed first, and Promotions of equal priority in the
+	// order in which they were created. A Promotion that is already being
+	// executed is never preempted. To prevent Promotions of low priority from
+	// waiting indefinitely, the priority of a waiting Promotion is raised
+	// periodically. If not specified when the Promotion is created, the
+	// PromotionPriority of the Stage is used.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Priority *int32 `json:"priority,omitempty" protobuf:"varint,6,opt,name=priority"`
+	// Approval is the PromotionApproval policy of the Stage at the time the
+	// Promotion was created. It is always set by the webhook. When set, the
+	// Promotion is not executed until one of the allowed approvers has
+	// approved it using the kargo.akuity.io/approve annotation.
+	Approval *PromotionApprovalPolicy `json:"approval,omitempty" protobuf:"bytes,7,opt,name=approval"`
+	// OriginCommit identifies the commit to an application's source repository
+	// that produced the Freight being promoted, e.g. the commit a container
+	// image was built from. When set, the outcome of the Promotion is reported
+	// to that commit as a commit status, using the Git provider's API.
+	OriginCommit *OriginCommit `json:"originCommit,omitempty" protobuf:"bytes,8,opt,name=originCommit"`
+}
+
+// OriginCommit identifies a commit to an application's source repository.
+type OriginCommit struct {
+	// RepoURL is the URL of the source repository.
+	//
+	// +kubebuilder:validation:MinLength=1
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// ID is the ID (SHA) of the commit.
+	//
+	// +kubebuilder:validation:MinLength=1
+	ID string `json:"id" protobuf:"bytes,2,opt,name=id"`
+}
+
+// PromotionLanes configures the concurrent execution of the steps of a
+// Promotion that are assigned to lanes.
+type PromotionLanes struct {
+	// MaxConcurrent is the maximum number of lanes whose steps may be executed
+	// concurrently. Defaults to 4.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty" protobuf:"varint,1,opt,name=maxConcurrent"`
+	// FailFast indicates whether a step in one lane failing should interrupt
+	// the steps of all other lanes. By default, the other lanes run to
+	// completion and the failures of all lanes are reported together.
+	FailFast bool `json:"failFast,omitempty" protobuf:"varint,2,opt,name=failFast"`
+}
+
+// GetMaxConcurrent returns the maximum number of lanes whose steps may be
+// executed concurrently. It is safe to call on a nil PromotionLanes.
+func (l *PromotionLanes) GetMaxConcurrent() int {
+	if l == nil || l.MaxConcurrent <= 0 {
+		return 4
+	}
+	return int(l.MaxConcurrent)
+}
+
+// GetFailFast returns whether a step in one lane failing should interrupt the
+// steps of all other lanes. It is safe to call on a nil PromotionLanes.
+func (l *PromotionLanes) GetFailFast() bool {
+	return l != nil && l.FailFast
 }
 
 // PromotionVariable describes a single variable that may be referenced by
@@ -209,6 +336,27 @@ type PromotionStep struct {
 	As string `json:"as,omitempty" protobuf:"bytes,2,opt,name=as"`
 	// Retry is the retry policy for this step.
 	Retry *PromotionStepRetry `json:"retry,omitempty" protobuf:"bytes,4,opt,name=retry"`
+	// ContinueOnError indicates whether a failure of this step, once retries
+	// (if any) have been exhausted, should be tolerated. When true, the step's
+	// failure is recorded in the Promotion's StepExecutionMetadata and execution
+	// continues with the next step instead of failing the entire Promotion.
+	// This is useful for optional steps, such as notifying an external system
+	// after all other steps have completed.
+	ContinueOnError bool `json:"continueOnError,omitempty" protobuf:"varint,7,opt,name=continueOnError"`
+	// Lane optionally assigns the step to a named lane. Consecutive steps that
+	// are assigned to lanes form a block in which the steps of different lanes
+	// are executed concurrently, while the steps of each lane are executed in
+	// the order in which they are listed. This is useful for e.g. rendering
+	// manifests for several independent environments and pushing them to
+	// distinct branches after a single shared clone. Lanes whose steps refer to
+	// overlapping paths or to the same branch are executed one after the
+	// other. Steps may only rely on the output of steps in the same lane or of
+	// steps preceding the block. Steps of a task that is assigned to a lane are
+	// assigned to the same lane unless they specify a lane of their own.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9]([a-zA-Z0-9_-]*[a-zA-Z0-9])?$
+	Lane string `json:"lane,omitempty" protobuf:"bytes,8,opt,name=lane"`
 	// Vars is a list of variables that can be referenced by expressions in
 	// the step's Config. The values override the values specified in the
 	// PromotionSpec.
@@ -271,6 +419,232 @@ type PromotionStatus struct {
 	// State stores the state of the promotion process between reconciliation
 	// attempts.
 	State *apiextensionsv1.JSON `json:"state,omitempty" protobuf:"bytes,10,opt,name=state"`
+	// RenderedBranch is the name of the branch that manifests rendered for the
+	// Stage are written to, as resolved from the Stage's RenderedBranch
+	// template when the Promotion began. It is empty if the Stage does not
+	// specify a template.
+	RenderedBranch string `json:"renderedBranch,omitempty" protobuf:"bytes,12,opt,name=renderedBranch"`
+	// RepoPolicyDecisions records, for each Git repository the Promotion
+	// attempted to access, whether access was allowed by the RepoPolicy of the
+	// KargoConfig resource. It is only populated when such a policy is in
+	// effect.
+	RepoPolicyDecisions []RepoPolicyDecision `json:"repoPolicyDecisions,omitempty" protobuf:"bytes,13,rep,name=repoPolicyDecisions"`
+	// Images is a compact digest of the container images referenced by the
+	// Freight referenced by this Promotion. If there are many images, only the
+	// first few are listed, and the Freight field omits them altogether. The
+	// full set can be retrieved from the Freight referenced by the Promotion's
+	// spec.
+	Images *ImageSetDigest `json:"images,omitempty" protobuf:"bytes,14,opt,name=images"`
+	// TranscriptConfigMap is the name of a ConfigMap, in the namespace of the
+	// Promotion, holding a transcript of the commands that were executed over the
+	// course of the Promotion, with any credentials redacted. It is only set if
+	// the Promotion Errored or Failed.
+	TranscriptConfigMap string `json:"transcriptConfigMap,omitempty" protobuf:"bytes,15,opt,name=transcriptConfigMap"`
+	// Overlays records, for each of the overlays of the Stage, the branch that
+	// manifests rendered from it are written to, as resolved when the
+	// Promotion began, and the commit that was pushed to it, once it has been.
+	// It is empty if the Stage does not specify any overlays.
+	Overlays []PromotedOverlay `json:"overlays,omitempty" protobuf:"bytes,16,rep,name=overlays"`
+	// Approval records who approved the Promotion and when, if the Stage
+	// required the Promotion to be approved. Once recorded, it is never
+	// changed.
+	Approval *PromotionApproval `json:"approval,omitempty" protobuf:"bytes,17,opt,name=approval"`
+	// RenderedBranchPush records the commits that the Promotion pushed to the
+	// Stage's rendered branch, if it has pushed to it.
+	RenderedBranchPush *RenderedBranchPush `json:"renderedBranchPush,omitempty" protobuf:"bytes,18,opt,name=renderedBranchPush"`
+	// RenderedBranchCleanup records what was done to the Stage's rendered
+	// branch after the Promotion failed, as described by the OnFailure policy
+	// of the Stage's RenderedBranch, and whether it was done successfully. It
+	// is only set if the Promotion failed after pushing to the branch.
+	RenderedBranchCleanup *RenderedBranchCleanup `json:"renderedBranchCleanup,omitempty" protobuf:"bytes,19,opt,name=renderedBranchCleanup"`
+	// ExternalModification records that the Stage's rendered branch did not
+	// point to the commit that Kargo last pushed to it when the Promotion was
+	// about to push to it, i.e. that the branch was modified by something
+	// other than Kargo, along with how the Stage's OnExternalModification
+	// policy dealt with it.
+	ExternalModification *ExternalModification `json:"externalModification,omitempty" protobuf:"bytes,20,opt,name=externalModification"`
+	// Conditions contains the last observations of the Promotion's current
+	// state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchMergeKey:"type" patchStrategy:"merge" protobuf:"bytes,21,rep,name=conditions"`
+	// AwaitingApprovalSince is the time at which the Promotion began waiting
+	// to be approved, i.e. when its Stage was first ready to execute it while
+	// it had not been approved yet. It is the time from which the MaxAge of
+	// the Promotion's approval policy is counted.
+	AwaitingApprovalSince *metav1.Time `json:"awaitingApprovalSince,omitempty" protobuf:"bytes,22,opt,name=awaitingApprovalSince"`
+	// RetainedImages records the images that the Warehouse of the promoted
+	// Freight subscribes to, but that the Freight does not carry. These are
+	// left at the versions the Stage's manifests already specify, which are
+	// recorded as well once a step has read them from a Kustomization file.
+	RetainedImages []RetainedImage `json:"retainedImages,omitempty" protobuf:"bytes,23,rep,name=retainedImages"`
+	// SourceUpdateBatch records the batch of updates to a branch of a Git
+	// repository that the Promotion added its update to, in accordance with
+	// the SourceUpdateBatching of the Stage.
+	SourceUpdateBatch *SourceUpdateBatch `json:"sourceUpdateBatch,omitempty" protobuf:"bytes,24,opt,name=sourceUpdateBatch"`
+	// RenderWarnings records the distinct warnings that the tools rendering
+	// the Stage's manifests, such as Kustomize, emitted over the course of the
+	// Promotion, e.g. about the use of deprecated fields.
+	RenderWarnings []string `json:"renderWarnings,omitempty" protobuf:"bytes,25,rep,name=renderWarnings"`
+	// GitIdentity records the GitIdentity of the Stage that the Promotion
+	// commits to Git repositories as, as resolved when the Promotion began.
+	// Overlays of the Stage that specify an identity of their own record it
+	// among the Promotion's Overlays.
+	GitIdentity *GitIdentity `json:"gitIdentity,omitempty" protobuf:"bytes,26,opt,name=gitIdentity"`
+	// LastReconcileOutcome describes the outcome of the last reconciliation
+	// of the Promotion, i.e. what it did or why it did nothing. It is only
+	// updated when the outcome changes.
+	LastReconcileOutcome *ReconcileOutcome `json:"lastReconcileOutcome,omitempty" protobuf:"bytes,27,opt,name=lastReconcileOutcome"`
+}
+
+func (p *PromotionStatus) GetConditions() []metav1.Condition {
+	return p.Conditions
+}
+
+func (p *PromotionStatus) SetConditions(conditions []metav1.Condition) {
+	p.Conditions = conditions
+}
+
+// RenderedBranchPush records the commits that a Promotion pushed to the
+// rendered branch of a Stage.
+type RenderedBranchPush struct {
+	// RepoURL is the URL of the repository the branch belongs to.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Branch is the name of the branch.
+	Branch string `json:"branch" protobuf:"bytes,2,opt,name=branch"`
+	// PreviousCommit is the ID of the commit that the branch pointed to before
+	// the Promotion first pushed to it. It is empty if the branch did not
+	// exist.
+	PreviousCommit string `json:"previousCommit,omitempty" protobuf:"bytes,3,opt,name=previousCommit"`
+	// Commit is the ID of the commit that the Promotion most recently pushed to
+	// the branch.
+	Commit string `json:"commit" protobuf:"bytes,4,opt,name=commit"`
+}
+
+// RenderedBranchCleanup records what was done to the rendered branch of a
+// Stage after a Promotion to the Stage failed.
+type RenderedBranchCleanup struct {
+	// Action is what was done to the branch.
+	Action RenderedBranchFailurePolicy `json:"action" protobuf:"bytes,1,opt,name=action"`
+	// Tag is the name of the tag that was created, if Action is Tag.
+	Tag string `json:"tag,omitempty" protobuf:"bytes,2,opt,name=tag"`
+	// Succeeded indicates whether the action was carried out successfully.
+	Succeeded bool `json:"succeeded" protobuf:"varint,3,opt,name=succeeded"`
+	// Message describes the outcome of the action.
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+}
+
+// ExternalModification records that a Stage's rendered branch was found to
+// have been modified by something other than Kargo.
+type ExternalModification struct {
+	// RepoURL is the URL of the repository the branch belongs to.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Branch is the name of the branch.
+	Branch string `json:"branch" protobuf:"bytes,2,opt,name=branch"`
+	// ExpectedCommit is the ID of the commit that Kargo last pushed to the
+	// branch.
+	ExpectedCommit string `json:"expectedCommit" protobuf:"bytes,3,opt,name=expectedCommit"`
+	// ActualCommit is the ID of the commit that the branch pointed to instead.
+	ActualCommit string `json:"actualCommit" protobuf:"bytes,4,opt,name=actualCommit"`
+	// Policy is the OnExternalModification policy that was applied.
+	Policy RenderedBranchExternalModificationPolicy `json:"policy" protobuf:"bytes,5,opt,name=policy"`
+}
+
+// PromotionApproval records the approval of a Promotion.
+type PromotionApproval struct {
+	// Approver is the user who approved the Promotion.
+	Approver string `json:"approver" protobuf:"bytes,1,opt,name=approver"`
+	// ApprovedAt is the time at which the Promotion was approved.
+	ApprovedAt *metav1.Time `json:"approvedAt,omitempty" protobuf:"bytes,2,opt,name=approvedAt"`
+	// SpecHash is the hash of the Promotion's spec at the time it was
+	// approved. The approval is only valid for a spec with the same hash.
+	SpecHash string `json:"specHash,omitempty" protobuf:"bytes,3,opt,name=specHash"`
+}
+
+// SourceUpdateBatch records a batch of updates to a branch of a Git repository
+// that are accumulated on a rolling branch.
+type SourceUpdateBatch struct {
+	// RepoURL is the URL of the Git repository.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Branch is the name of the branch that the updates are accumulated for.
+	Branch string `json:"branch" protobuf:"bytes,2,opt,name=branch"`
+	// RollingBranch is the name of the branch that the updates are
+	// accumulated on.
+	RollingBranch string `json:"rollingBranch" protobuf:"bytes,3,opt,name=rollingBranch"`
+	// RollingCommit is the ID of the commit that the Promotion pushed to
+	// RollingBranch, which contains all updates of the batch squashed into
+	// one. Manifests rendered by the Promotion were rendered from it.
+	RollingCommit string `json:"rollingCommit" protobuf:"bytes,4,opt,name=rollingCommit"`
+	// Updates is the number of updates in the batch, including the
+	// Promotion's own.
+	Updates int32 `json:"updates" protobuf:"varint,5,opt,name=updates"`
+	// MergedCommit is the ID of the commit that the batch was merged into
+	// Branch as, if the Promotion merged it. As the squashed commit is rebased
+	// onto Branch when it is merged, it usually differs from RollingCommit.
+	// It is empty if the batch has not been merged yet or was merged by a pull
+	// request or by a later Promotion.
+	MergedCommit string `json:"mergedCommit,omitempty" protobuf:"bytes,6,opt,name=mergedCommit"`
+	// PullRequestURL is the URL of the pull request that was opened to merge
+	// the batch into Branch, if any.
+	PullRequestURL string `json:"pullRequestURL,omitempty" protobuf:"bytes,7,opt,name=pullRequestURL"`
+}
+
+// RetainedImage records an image that a Promotion left at the version the
+// Stage's manifests already specified, because the promoted Freight did not
+// carry it.
+type RetainedImage struct {
+	// RepoURL is the URL of the image's repository.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Tag is the tag the image was left at. It is empty if the version of the
+	// image was not read, or if it is only specified by digest.
+	Tag string `json:"tag,omitempty" protobuf:"bytes,2,opt,name=tag"`
+	// Digest is the digest the image was left at. It is empty if the version
+	// of the image was not read, or if it is only specified by tag.
+	Digest string `json:"digest,omitempty" protobuf:"bytes,3,opt,name=digest"`
+}
+
+// PromotedOverlay records the promotion of one of the overlays of a Stage.
+type PromotedOverlay struct {
+	// Name is the name of the overlay.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Path is the path of the overlay's directory, relative to the root of the
+	// repository.
+	Path string `json:"path" protobuf:"bytes,2,opt,name=path"`
+	// RenderedBranch is the name of the branch that manifests rendered from the
+	// overlay are written to.
+	RenderedBranch string `json:"renderedBranch" protobuf:"bytes,3,opt,name=renderedBranch"`
+	// Commit is the ID of the commit that was pushed to RenderedBranch. It is
+	// empty until manifests rendered from the overlay have been pushed.
+	Commit string `json:"commit,omitempty" protobuf:"bytes,4,opt,name=commit"`
+	// GitIdentity is the identity that manifests rendered from the overlay are
+	// committed as, if the overlay specifies one in place of the Stage's.
+	GitIdentity *GitIdentity `json:"gitIdentity,omitempty" protobuf:"bytes,5,opt,name=gitIdentity"`
+}
+
+// ImageSetDigest is a compact digest of a set of container images.
+type ImageSetDigest struct {
+	// Count is the number of images in the set.
+	Count int32 `json:"count" protobuf:"varint,1,opt,name=count"`
+	// Hash is a hash of all images in the set, which changes whenever any
+	// image is added, removed, or changes its tag or digest.
+	Hash string `json:"hash" protobuf:"bytes,2,opt,name=hash"`
+	// Images lists the first images in the set. It lists all images in the set
+	// if Count does not exceed the number of images listed.
+	Images []Image `json:"images,omitempty" protobuf:"bytes,3,rep,name=images"`
+}
+
+// RepoPolicyDecision records whether a Promotion was allowed to access a Git
+// repository.
+type RepoPolicyDecision struct {
+	// RepoURL is the URL of the repository.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Allowed indicates whether access to the repository was allowed.
+	Allowed bool `json:"allowed" protobuf:"varint,2,opt,name=allowed"`
+	// Rule is the pattern the decision was based on. It is empty if access was
+	// denied because the URL matched none of the allowed patterns.
+	Rule string `json:"rule,omitempty" protobuf:"bytes,3,opt,name=rule"`
 }
 
 // GetState returns the State field as unmarshalled YAML.
@@ -336,7 +710,8 @@ type StepExecutionMetadata struct {
 	// Alias is the alias of the step.
 	Alias string `json:"alias,omitempty" protobuf:"bytes,1,opt,name=alias"`
 	// StartedAt is the time at which the first attempt to execute the step
-	// began.
+	// began or, if the step waited for a point in time, the time at which it
+	// last did so.
 	StartedAt *metav1.Time `json:"startedAt,omitempty" protobuf:"bytes,2,opt,name=startedAt"`
 	// FinishedAt is the time at which the final attempt to execute the step
 	// completed.
@@ -347,4 +722,8 @@ type StepExecutionMetadata struct {
 	Status PromotionPhase `json:"status,omitempty" protobuf:"bytes,5,opt,name=status"`
 	// Message is a display message about the step, including any errors.
 	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
+	// WaitUntil is the point in time the step is waiting for, if it is waiting
+	// for one rather than for a change in some external state. The Promotion
+	// is reconciled again no later than this time.
+	WaitUntil *metav1.Time `json:"waitUntil,omitempty" protobuf:"bytes,7,opt,name=waitUntil"`
 }
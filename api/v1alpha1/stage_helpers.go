@@ -294,3 +294,65 @@ func AbortStageFreightVerification(
 	}
 	return patchAnnotation(ctx, c, stage, AnnotationKeyAbort, ar.String())
 }
+
+// IsAllowedApprover returns whether the user with the provided name, who is a
+// member of the provided groups, is one of the policy's allowed approvers.
+// ServiceAccounts that do not specify a namespace are assumed to be in the
+// provided namespace.
+func (p *PromotionApprovalPolicy) IsAllowedApprover(
+	username string,
+	groups []string,
+	namespace string,
+) bool {
+	if p == nil {
+		return false
+	}
+	for _, approver := range p.AllowedApprovers {
+		switch approver.Kind {
+		case PromotionApproverKindUser:
+			if approver.Name == username {
+				return true
+			}
+		case PromotionApproverKindGroup:
+			if slices.Contains(groups, approver.Name) {
+				return true
+			}
+		case PromotionApproverKindServiceAccount:
+			ns := approver.Namespace
+			if ns == "" {
+				ns = namespace
+			}
+			if username == fmt.Sprintf("system:serviceaccount:%s:%s", ns, approver.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const (
+	// DefaultDryRunMaxDocuments is the default maximum number of resources that
+	// the argocd-dry-run promotion step applies.
+	DefaultDryRunMaxDocuments = 500
+	// DefaultDryRunTimeout is the default maximum amount of time the
+	// argocd-dry-run promotion step spends applying resources.
+	DefaultDryRunTimeout = 2 * time.Minute
+)
+
+// GetMaxDocuments returns the maximum number of resources that are applied in
+// dry-run mode. It is safe to call on a nil DryRunApply.
+func (d *DryRunApply) GetMaxDocuments() int {
+	if d == nil || d.MaxDocuments <= 0 {
+		return DefaultDryRunMaxDocuments
+	}
+	return int(d.MaxDocuments)
+}
+
+// GetTimeout returns the maximum amount of time spent applying resources in
+// dry-run mode. It is safe to call on a nil DryRunApply.
+func (d *DryRunApply) GetTimeout() time.Duration {
+	if d == nil || d.Timeout == nil || d.Timeout.Duration <= 0 {
+		return DefaultDryRunTimeout
+	}
+	return d.Timeout.Duration
+}
@@ -134,6 +134,8 @@ const FreightOriginKindWarehouse FreightOriginKind = "Warehouse"
 // +kubebuilder:printcolumn:name=Current Freight,type=string,JSONPath=`.status.freightSummary`
 // +kubebuilder:printcolumn:name=Health,type=string,JSONPath=`.status.health.status`
 // +kubebuilder:printcolumn:name=Phase,type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name=Last Promoted Image,type=string,JSONPath=`.status.promotionHistory[0].freight.images[0].tag`,priority=1
+// +kubebuilder:printcolumn:name=Last Promotion,type=date,JSONPath=`.status.promotionHistory[0].finishedAt`,priority=1
 // +kubebuilder:printcolumn:name=Age,type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Stage is the Kargo API's main type.
@@ -192,6 +194,456 @@ type StageSpec struct {
 	// Verification describes how to verify a Stage's current Freight is fit for
 	// promotion downstream.
 	Verification *Verification `json:"verification,omitempty" protobuf:"bytes,3,opt,name=verification"`
+	// ArgoCDApps describes Argo CD Applications whose lifecycle is managed by
+	// the Stage. The controller creates each Application that does not exist,
+	// adopts existing ones only if explicitly permitted, and keeps their
+	// source, destination, and sync policy in sync with what is specified here.
+	ArgoCDApps []ManagedArgoCDApp `json:"argoCDApps,omitempty" protobuf:"bytes,7,rep,name=argoCDApps"`
+	// ServiceAccountRef optionally references a ServiceAccount in the Stage's
+	// namespace whose identity the controller assumes when getting and
+	// updating Argo CD Applications on behalf of Promotions to this Stage. This
+	// permits Kubernetes RBAC to limit which Applications the Stage may
+	// promote to. When not specified, the controller's own identity is used.
+	// The run-job promotion step creates its Jobs with this identity and can
+	// not be used by Stages that do not specify one.
+	ServiceAccountRef *ServiceAccountReference `json:"serviceAccountRef,omitempty" protobuf:"bytes,8,opt,name=serviceAccountRef"`
+	// ArgoCDContext optionally names the Argo CD context, registered in the
+	// controller's configuration, in which the Argo CD Applications related to
+	// this Stage reside. All interactions with those Applications, including
+	// updating them on behalf of Promotions, managing their lifecycle, and
+	// assessing their health, take place through this context. When not
+	// specified, the controller's default Argo CD context is used.
+	ArgoCDContext string `json:"argoCDContext,omitempty" protobuf:"bytes,9,opt,name=argoCDContext"`
+	// PreventDowngrades indicates whether Promotions that would move any of
+	// the Stage's images back to an older version should be skipped instead of
+	// executed. Versions are compared as semantic versions where possible and
+	// otherwise by the order in which they were promoted to the Stage. This
+	// guards against stale Promotions, e.g. ones that are retried after a more
+	// recent Promotion already succeeded. Promotions that are annotated with
+	// kargo.akuity.io/allow-downgrade: "true" are executed regardless, which
+	// permits deliberate rollbacks.
+	PreventDowngrades bool `json:"preventDowngrades,omitempty" protobuf:"varint,10,opt,name=preventDowngrades"`
+	// RenderedBranch optionally describes how the name of the branch that
+	// manifests rendered for the Stage are written to is derived. The name is
+	// resolved whenever a Promotion to the Stage is executed, is recorded in
+	// the Promotion's status, and is available to its steps as
+	// ctx.renderedBranch, so that the same branch can consistently be checked
+	// out, pushed to, and synced by Argo CD.
+	RenderedBranch *RenderedBranch `json:"renderedBranch,omitempty" protobuf:"bytes,11,opt,name=renderedBranch"`
+	// ImageMappings optionally describes how references to container images
+	// found in Freight are rewritten before promotion steps such as
+	// kustomize-set-image write them to the Stage's manifests. This is useful
+	// when images are copied to a different registry or repository for each
+	// environment. The first mapping that applies to an image is used, and
+	// images that no mapping applies to are left unchanged.
+	ImageMappings []ImageMapping `json:"imageMappings,omitempty" protobuf:"bytes,12,rep,name=imageMappings"`
+	// ToolVersions optionally pins the versions of the tools that promotion
+	// steps such as kustomize-build and helm-template use to render the
+	// Stage's manifests, so that the rendered manifests do not change when
+	// the versions of the tools embedded in Kargo do.
+	ToolVersions *ToolVersions `json:"toolVersions,omitempty" protobuf:"bytes,13,opt,name=toolVersions"`
+	// PromotionPriority is the priority assigned to Promotions to this Stage
+	// that do not specify one themselves when they are created. See the
+	// Priority field of PromotionSpec.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	PromotionPriority int32 `json:"promotionPriority,omitempty" protobuf:"varint,14,opt,name=promotionPriority"`
+	// Overlays optionally describes several Kustomize overlays that together
+	// make up the Stage, e.g. one per region, each of which manifests are
+	// rendered from into a branch of its own. The kustomize-promote-overlays
+	// promotion step updates and renders all of them as a unit. The branch of
+	// each overlay is resolved whenever a Promotion to the Stage is executed
+	// and is recorded in the Promotion's status, along with the commit pushed
+	// to it. When overlays are specified, the Stage's RenderedBranch template
+	// is resolved once per overlay instead of once for the whole Stage.
+	Overlays []StageOverlay `json:"overlays,omitempty" protobuf:"bytes,15,rep,name=overlays"`
+	// PromotionApproval optionally requires Promotions to the Stage to be
+	// approved by one of a list of allowed approvers before they are executed.
+	// It is copied to each Promotion to the Stage when the Promotion is
+	// created, so changes to it only apply to Promotions created afterwards.
+	PromotionApproval *PromotionApprovalPolicy `json:"promotionApproval,omitempty" protobuf:"bytes,16,opt,name=promotionApproval"`
+	// Metadata is optional, free-form information about the Stage, such as the
+	// team that owns it or the ID of the Stage in a change management system.
+	// The expressions in the Stage's promotion steps can refer to it using the
+	// stageMetadata() function, and it is included in the annotations of the
+	// events recorded for Promotions to the Stage. Keys must begin with a
+	// letter and may only contain letters, digits, '_', '-', and '.'.
+	//
+	// +kubebuilder:validation:MaxProperties=32
+	Metadata map[string]string `json:"metadata,omitempty" protobuf:"bytes,17,rep,name=metadata" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ResourceLimits optionally lowers, for Promotions to the Stage, the
+	// limits on the size of the Git repositories they clone and of the
+	// manifests they render that are specified by the KargoConfig resource.
+	// Limits above those of the KargoConfig resource are lowered to them.
+	// Limits that are not specified here, or that are 0, fall back to those
+	// of the KargoConfig resource.
+	ResourceLimits *ResourceLimits `json:"resourceLimits,omitempty" protobuf:"bytes,18,opt,name=resourceLimits"`
+	// DryRunApply optionally permits Promotions to the Stage to verify the
+	// manifests they render using the argocd-dry-run promotion step, which
+	// applies them to the destination cluster of an Argo CD Application in
+	// server-side dry-run mode before they are committed. Doing so requires
+	// the controller to read the credentials of the destination cluster from
+	// Argo CD, so the step fails for Stages that do not specify this.
+	DryRunApply *DryRunApply `json:"dryRunApply,omitempty" protobuf:"bytes,19,opt,name=dryRunApply"`
+	// ImageCompleteness specifies whether the Freight promoted to the Stage
+	// must carry every image that its Warehouse subscribes to. Any, the
+	// default, permits Freight that carries only some of them, in which case
+	// the others are left at the versions the Stage's manifests already
+	// specify, which the Promotion records in its status. Declared requires
+	// the Freight to carry exactly the images its Warehouse subscribes to, and
+	// Promotions of any other Freight are rejected.
+	//
+	// +kubebuilder:default=Any
+	ImageCompleteness ImageCompletenessPolicy `json:"imageCompleteness,omitempty" protobuf:"bytes,20,opt,name=imageCompleteness"`
+	// SourceUpdateBatching optionally accumulates the updates that Promotions
+	// to the Stage push to a branch of a Git repository, such as image updates
+	// to the source of the Stage's manifests, on a rolling branch instead of
+	// committing each of them to the branch. The updates on the rolling
+	// branch are squashed into a single commit, which is merged into the
+	// branch once enough updates have accumulated or enough time has passed.
+	// Manifests that the Promotions render are rendered from the content of
+	// the rolling branch, so rendered branches continue to be updated by every
+	// Promotion.
+	SourceUpdateBatching *SourceUpdateBatching `json:"sourceUpdateBatching,omitempty" protobuf:"bytes,21,opt,name=sourceUpdateBatching"`
+	// StrictRender specifies whether Promotions to the Stage fail when the
+	// tools rendering the Stage's manifests, such as Kustomize, emit warnings,
+	// e.g. about the use of deprecated fields. Regardless of this setting,
+	// such warnings are recorded in the status of the Promotion.
+	StrictRender bool `json:"strictRender,omitempty" protobuf:"varint,22,opt,name=strictRender"`
+	// GitIdentity optionally specifies the identity that Promotions to the
+	// Stage commit to Git repositories as, and the credentials they access the
+	// repositories with, in place of the controller's defaults. It is resolved
+	// when a Promotion to the Stage begins and is recorded in the Promotion's
+	// status.
+	GitIdentity *GitIdentity `json:"gitIdentity,omitempty" protobuf:"bytes,23,opt,name=gitIdentity"`
+}
+
+// GitIdentity describes the identity that commits are made as and the
+// credentials that Git repositories are accessed with, e.g. so that branch
+// protection rules or CODEOWNERS route the review of the changes Promotions
+// make according to the environment they are made for.
+type GitIdentity struct {
+	// Name is the name of the author and committer of commits. If not
+	// specified, the controller's default is used.
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Email is the email address of the author and committer of commits. If
+	// not specified, the controller's default is used.
+	Email string `json:"email,omitempty" protobuf:"bytes,2,opt,name=email"`
+	// CredentialsSecret is the name of a Secret in the Project namespace that
+	// holds the Git credentials, e.g. a token or a GitHub App, that
+	// repositories are cloned, pushed to, and opened pull requests against
+	// with, in place of the credentials otherwise looked up for them. The
+	// Secret must be labeled as holding Git credentials. If not specified,
+	// credentials are looked up as usual.
+	CredentialsSecret string `json:"credentialsSecret,omitempty" protobuf:"bytes,3,opt,name=credentialsSecret"`
+}
+
+// ImageCompletenessPolicy describes whether the Freight promoted to a Stage
+// must carry every image that its Warehouse subscribes to.
+//
+// +kubebuilder:validation:Enum=Any;Declared
+type ImageCompletenessPolicy string
+
+const (
+	ImageCompletenessPolicyAny      ImageCompletenessPolicy = "Any"
+	ImageCompletenessPolicyDeclared ImageCompletenessPolicy = "Declared"
+)
+
+// SourceUpdateBatching describes how the updates that Promotions to a Stage
+// push to a branch of a Git repository are accumulated on a rolling branch
+// before they are merged into it.
+//
+// +kubebuilder:validation:XValidation:message="rollingBranch must differ from branch",rule="!has(self.rollingBranch) || self.rollingBranch != self.branch"
+type SourceUpdateBatching struct {
+	// RepoURL is the URL of the Git repository whose branch updates are
+	// accumulated for.
+	//
+	// +kubebuilder:validation:MinLength=1
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Branch is the name of the branch that updates are accumulated for. If
+	// the rolling branch exists, the git-clone step checks it out when asked
+	// to check out this branch, and the git-push step pushes to the rolling
+	// branch when asked to push to this branch.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Branch string `json:"branch" protobuf:"bytes,2,opt,name=branch"`
+	// RollingBranch is the name of the branch that updates are accumulated on.
+	// It holds a single commit on top of Branch, which every update amends.
+	//
+	// +kubebuilder:default=kargo/image-updates
+	RollingBranch string `json:"rollingBranch,omitempty" protobuf:"bytes,3,opt,name=rollingBranch"`
+	// MergeAfter is the number of updates after which the accumulated updates
+	// are merged into Branch. If neither MergeAfter nor MergeInterval is
+	// specified, updates accumulate until the rolling branch is merged by
+	// other means.
+	//
+	// +kubebuilder:validation:Minimum=1
+	MergeAfter int32 `json:"mergeAfter,omitempty" protobuf:"varint,4,opt,name=mergeAfter"`
+	// MergeInterval is the time after the first update on the rolling branch
+	// after which the accumulated updates are merged into Branch. It is
+	// evaluated whenever a Promotion pushes an update, so the updates are
+	// merged along with the first update pushed after the interval elapsed.
+	//
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+	MergeInterval *metav1.Duration `json:"mergeInterval,omitempty" protobuf:"bytes,5,opt,name=mergeInterval"`
+	// MergeMethod describes how the accumulated updates are merged into
+	// Branch. Push, the default, rebases the squashed commit onto Branch,
+	// pushes it to Branch, and deletes the rolling branch. PullRequest opens
+	// a pull request from the rolling branch to Branch, unless one is open
+	// already, and leaves merging it to the Git hosting provider's merge
+	// queue or to a person.
+	//
+	// +kubebuilder:default=Push
+	MergeMethod SourceUpdateMergeMethod `json:"mergeMethod,omitempty" protobuf:"bytes,6,opt,name=mergeMethod"`
+}
+
+// SourceUpdateMergeMethod describes how the updates accumulated on a rolling
+// branch are merged into the branch they were accumulated for.
+//
+// +kubebuilder:validation:Enum=Push;PullRequest
+type SourceUpdateMergeMethod string
+
+const (
+	SourceUpdateMergeMethodPush        SourceUpdateMergeMethod = "Push"
+	SourceUpdateMergeMethodPullRequest SourceUpdateMergeMethod = "PullRequest"
+)
+
+// DryRunApply describes how manifests rendered for a Stage are applied to the
+// destination cluster of an Argo CD Application in server-side dry-run mode.
+type DryRunApply struct {
+	// MaxDocuments is the maximum number of resources that are applied. The
+	// resources of very large renders beyond this number are not verified.
+	//
+	// +kubebuilder:default=500
+	// +kubebuilder:validation:Minimum=1
+	MaxDocuments int32 `json:"maxDocuments,omitempty" protobuf:"varint,1,opt,name=maxDocuments"`
+	// Timeout is the maximum amount of time spent applying resources. The
+	// resources that were not applied yet when it elapses are not verified.
+	//
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+	// +kubebuilder:default="2m0s"
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,2,opt,name=timeout"`
+	// MissingNamespaces describes what is done with resources in namespaces
+	// that do not exist in the destination cluster yet, which cannot be
+	// applied even in dry-run mode. Skip, the default, does not verify them.
+	// Create verifies that each missing namespace could be created, in
+	// dry-run mode, but still does not verify the resources in it.
+	//
+	// +kubebuilder:default=Skip
+	MissingNamespaces DryRunMissingNamespacePolicy `json:"missingNamespaces,omitempty" protobuf:"bytes,3,opt,name=missingNamespaces"`
+}
+
+// DryRunMissingNamespacePolicy describes what is done with resources in
+// namespaces that do not exist in a destination cluster when they are applied
+// in dry-run mode.
+//
+// +kubebuilder:validation:Enum=Skip;Create
+type DryRunMissingNamespacePolicy string
+
+const (
+	DryRunMissingNamespacePolicySkip   DryRunMissingNamespacePolicy = "Skip"
+	DryRunMissingNamespacePolicyCreate DryRunMissingNamespacePolicy = "Create"
+)
+
+// PromotionApprovalPolicy describes who may approve Promotions to a Stage.
+type PromotionApprovalPolicy struct {
+	// AllowedApprovers lists the users, groups, and ServiceAccounts that may
+	// approve Promotions to the Stage.
+	//
+	// +kubebuilder:validation:MinItems=1
+	AllowedApprovers []PromotionApprover `json:"allowedApprovers" protobuf:"bytes,1,rep,name=allowedApprovers"`
+	// PreventSelfApproval indicates whether the user who created a Promotion
+	// is prevented from approving it, even if they are an allowed approver.
+	PreventSelfApproval bool `json:"preventSelfApproval,omitempty" protobuf:"varint,2,opt,name=preventSelfApproval"`
+	// MaxAge optionally limits how long a Promotion may wait to be approved.
+	// A Promotion that has been waiting for longer than this, counted from the
+	// time it began waiting, is Expired instead of being executed. This
+	// prevents a Promotion from being approved long after newer Freight has
+	// become available. The deadline of an individual Promotion can be
+	// extended using the kargo.akuity.io/extend-expiry annotation. Promotions
+	// never expire if this is not specified.
+	//
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+	MaxAge *metav1.Duration `json:"maxAge,omitempty" protobuf:"bytes,3,opt,name=maxAge"`
+}
+
+// PromotionApproverKind is the kind of subject a PromotionApprover refers to.
+//
+// +kubebuilder:validation:Enum=User;Group;ServiceAccount
+type PromotionApproverKind string
+
+const (
+	PromotionApproverKindUser           PromotionApproverKind = "User"
+	PromotionApproverKindGroup          PromotionApproverKind = "Group"
+	PromotionApproverKindServiceAccount PromotionApproverKind = "ServiceAccount"
+)
+
+// PromotionApprover describes a user, group, or ServiceAccount that may approve
+// Promotions.
+//
+// +kubebuilder:validation:XValidation:message="namespace may only be specified for a ServiceAccount",rule="!has(self.__namespace__) || self.kind == 'ServiceAccount'"
+type PromotionApprover struct {
+	// Kind is the kind of subject: User, Group, or ServiceAccount.
+	//
+	// +kubebuilder:validation:Required
+	Kind PromotionApproverKind `json:"kind" protobuf:"bytes,1,opt,name=kind"`
+	// Name is the name of the user, group, or ServiceAccount, as it is known
+	// to the Kubernetes API server.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,2,opt,name=name"`
+	// Namespace is the namespace of a ServiceAccount. If not specified, the
+	// namespace of the Stage is assumed.
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+}
+
+// StageOverlay describes one of several Kustomize overlays that make up a
+// Stage, along with the branch that manifests rendered from it are written
+// to.
+type StageOverlay struct {
+	// Name uniquely identifies the overlay among the Stage's overlays, e.g. by
+	// the region it targets. It is available to the Stage's RenderedBranch
+	// template as .Region.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Path is the path of the overlay's directory, relative to the root of the
+	// repository.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path" protobuf:"bytes,2,opt,name=path"`
+	// RenderedBranch is the name of the branch that manifests rendered from the
+	// overlay are written to. If not specified, it is resolved from the Stage's
+	// RenderedBranch template.
+	RenderedBranch string `json:"renderedBranch,omitempty" protobuf:"bytes,3,opt,name=renderedBranch"`
+	// GitIdentity optionally specifies the identity that manifests rendered
+	// from the overlay are committed as, and the credentials they are pushed
+	// with, in place of the Stage's GitIdentity.
+	GitIdentity *GitIdentity `json:"gitIdentity,omitempty" protobuf:"bytes,4,opt,name=gitIdentity"`
+}
+
+// ToolVersions describes the versions of the tools used to render manifests.
+type ToolVersions struct {
+	// Kustomize is the version of Kustomize, e.g. "v5.5.0". If not specified,
+	// the version embedded in Kargo is used.
+	//
+	// +kubebuilder:validation:Pattern=^v[0-9]+\.[0-9]+\.[0-9]+$
+	Kustomize string `json:"kustomize,omitempty" protobuf:"bytes,1,opt,name=kustomize"`
+	// Helm is the version of Helm, e.g. "v3.16.4". If not specified, the
+	// version embedded in Kargo is used.
+	//
+	// +kubebuilder:validation:Pattern=^v[0-9]+\.[0-9]+\.[0-9]+$
+	Helm string `json:"helm,omitempty" protobuf:"bytes,2,opt,name=helm"`
+}
+
+// ImageMapping describes how references to the container images of one or
+// more image repositories are rewritten.
+type ImageMapping struct {
+	// RepoURL is the URL of the image repository the mapping applies to. If it
+	// ends with "/*", the mapping applies to all repositories beneath that
+	// path. e.g. "registry.dev.example.com/*"
+	//
+	// +kubebuilder:validation:MinLength=1
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// NewRepoURL is the URL of the image repository that references are
+	// rewritten to. It must end with "/*" if and only if RepoURL does, in which
+	// case the part of the URL matched by "*" is appended to it. e.g.
+	// "registry.prod.example.com/*". If not specified, the repository is left
+	// unchanged.
+	NewRepoURL string `json:"newRepoURL,omitempty" protobuf:"bytes,2,opt,name=newRepoURL"`
+	// TagPrefix is prepended to the tags of images the mapping applies to.
+	TagPrefix string `json:"tagPrefix,omitempty" protobuf:"bytes,3,opt,name=tagPrefix"`
+	// TagSuffix is appended to the tags of images the mapping applies to.
+	TagSuffix string `json:"tagSuffix,omitempty" protobuf:"bytes,4,opt,name=tagSuffix"`
+}
+
+// RenderedBranch describes how the name of the branch that manifests rendered
+// for a Stage are written to is derived.
+type RenderedBranch struct {
+	// Template is a Go template that renders the name of the branch. The
+	// template may refer to .Project, .Stage, .App, .Cluster, and .Region. The
+	// rendered name must be a valid Git branch name. e.g.
+	// "rendered/{{ .Stage }}/{{ .Region }}"
+	//
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template" protobuf:"bytes,1,opt,name=template"`
+	// App is the name of the application that is available to the template as
+	// .App. If not specified, the name of the first Argo CD Application
+	// managed by the Stage is used.
+	App string `json:"app,omitempty" protobuf:"bytes,2,opt,name=app"`
+	// Cluster is the name of the cluster that is available to the template as
+	// .Cluster. If not specified, the destination name of the first Argo CD
+	// Application managed by the Stage is used.
+	Cluster string `json:"cluster,omitempty" protobuf:"bytes,3,opt,name=cluster"`
+	// Region is the name of the region that is available to the template as
+	// .Region.
+	Region string `json:"region,omitempty" protobuf:"bytes,4,opt,name=region"`
+	// OnFailure describes what is done to the branch when a Promotion to the
+	// Stage fails or errors after pushing to it, e.g. so that Argo CD does not
+	// sync manifests that the Promotion did not finish promoting. Leave, the
+	// default, leaves the branch as is. Tag tags the commit that was pushed as
+	// kargo/failed/<promotion>, so that it can be found. Reset resets the
+	// branch to the commit it pointed to before the Promotion pushed to it,
+	// unless it has been pushed to since. Nothing is done if the Promotion did
+	// not push to the branch.
+	//
+	// +kubebuilder:default=Leave
+	OnFailure RenderedBranchFailurePolicy `json:"onFailure,omitempty" protobuf:"bytes,5,opt,name=onFailure"`
+	// OnExternalModification describes what is done when a Promotion to the
+	// Stage is about to push to the branch and finds that the branch no longer
+	// points to the commit that Kargo last pushed to it, e.g. because a person
+	// or another tool pushed to it, whose changes the Promotion would
+	// otherwise overwrite. Fail, the default, fails the Promotion. Warn
+	// proceeds with the Promotion and sets the Stage's ExternallyModified
+	// condition. Drift fails the Promotion and records the modification as
+	// drift of the Stage, which blocks further Promotions until it is
+	// acknowledged using the kargo.akuity.io/acknowledge-drift annotation,
+	// after which subsequent Promotions accept the modification and push on
+	// top of it.
+	//
+	// +kubebuilder:default=Fail
+	OnExternalModification RenderedBranchExternalModificationPolicy `json:"onExternalModification,omitempty" protobuf:"bytes,6,opt,name=onExternalModification"`
+}
+
+// RenderedBranchFailurePolicy describes what is done to a Stage's rendered
+// branch when a Promotion to the Stage fails after pushing to it.
+//
+// +kubebuilder:validation:Enum=Leave;Tag;Reset
+type RenderedBranchFailurePolicy string
+
+const (
+	RenderedBranchFailurePolicyLeave RenderedBranchFailurePolicy = "Leave"
+	RenderedBranchFailurePolicyTag   RenderedBranchFailurePolicy = "Tag"
+	RenderedBranchFailurePolicyReset RenderedBranchFailurePolicy = "Reset"
+)
+
+// RenderedBranchExternalModificationPolicy describes what is done when a
+// Stage's rendered branch is found to have been modified by something other
+// than Kargo.
+//
+// +kubebuilder:validation:Enum=Fail;Warn;Drift
+type RenderedBranchExternalModificationPolicy string
+
+const (
+	RenderedBranchExternalModificationPolicyFail  RenderedBranchExternalModificationPolicy = "Fail"
+	RenderedBranchExternalModificationPolicyWarn  RenderedBranchExternalModificationPolicy = "Warn"
+	RenderedBranchExternalModificationPolicyDrift RenderedBranchExternalModificationPolicy = "Drift"
+)
+
+// ServiceAccountReference is a reference to a ServiceAccount.
+type ServiceAccountReference struct {
+	// Name is the name of the ServiceAccount in the same project/namespace as
+	// the Stage.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
 }
 
 // FreightRequest expresses a Stage's need for Freight having originated from a
@@ -286,6 +738,108 @@ type PromotionTemplateSpec struct {
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:items:XValidation:message="PromotionTemplate step must have exactly one of uses or task set",rule="(has(self.uses) ? !has(self.task) : has(self.task))"
 	Steps []PromotionStep `json:"steps,omitempty" protobuf:"bytes,1,rep,name=steps"`
+	// Lanes configures the concurrent execution of steps that are assigned to
+	// lanes.
+	Lanes *PromotionLanes `json:"lanes,omitempty" protobuf:"bytes,3,opt,name=lanes"`
+}
+
+// ArgoCDAppDeletionPolicy describes what happens to an Argo CD Application
+// managed by a Stage when that Stage is deleted.
+//
+// +kubebuilder:validation:Enum={Orphan,Cascade}
+type ArgoCDAppDeletionPolicy string
+
+const (
+	// ArgoCDAppDeletionPolicyOrphan leaves the Application and the resources
+	// it manages in place. Only the annotations linking the Application to the
+	// Stage are removed.
+	ArgoCDAppDeletionPolicyOrphan ArgoCDAppDeletionPolicy = "Orphan"
+	// ArgoCDAppDeletionPolicyCascade deletes the Application along with all
+	// resources it manages.
+	ArgoCDAppDeletionPolicyCascade ArgoCDAppDeletionPolicy = "Cascade"
+)
+
+// ManagedArgoCDApp is a template for an Argo CD Application whose lifecycle is
+// managed by a Stage.
+type ManagedArgoCDApp struct {
+	// Name is the name of the Application.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Namespace is the namespace of the Application. If not specified, the
+	// namespace Argo CD is installed in is used.
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,2,opt,name=namespace"`
+	// Project is the Argo CD project the Application belongs to. If not
+	// specified, the "default" project is used.
+	Project string `json:"project,omitempty" protobuf:"bytes,3,opt,name=project"`
+	// Source describes where the Application's manifests are obtained from.
+	//
+	// +kubebuilder:validation:Required
+	Source ManagedArgoCDAppSource `json:"source" protobuf:"bytes,4,opt,name=source"`
+	// Destination describes where the Application's manifests are deployed.
+	//
+	// +kubebuilder:validation:Required
+	Destination ManagedArgoCDAppDestination `json:"destination" protobuf:"bytes,5,opt,name=destination"`
+	// SyncPolicy describes when and how the Application is synced. If not
+	// specified, the Application's sync policy is left unmanaged.
+	SyncPolicy *ManagedArgoCDAppSyncPolicy `json:"syncPolicy,omitempty" protobuf:"bytes,6,opt,name=syncPolicy"`
+	// Adopt indicates whether an existing Application with the same name that
+	// was not created by Kargo may be taken over by the Stage. If false, the
+	// controller refuses to modify such an Application.
+	Adopt bool `json:"adopt,omitempty" protobuf:"varint,7,opt,name=adopt"`
+	// DeletionPolicy describes what happens to the Application when the Stage
+	// is deleted. Defaults to Orphan.
+	//
+	// +kubebuilder:default=Orphan
+	DeletionPolicy ArgoCDAppDeletionPolicy `json:"deletionPolicy,omitempty" protobuf:"bytes,8,opt,name=deletionPolicy"`
+}
+
+// ManagedArgoCDAppSource describes where the manifests of an Argo CD
+// Application managed by a Stage are obtained from.
+type ManagedArgoCDAppSource struct {
+	// RepoURL is the URL of the repository containing the manifests.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// TargetRevision is the revision (e.g. a branch) of the repository to
+	// deploy. Note that this field is kept in sync by the controller and should
+	// therefore not also be updated by promotion steps.
+	TargetRevision string `json:"targetRevision,omitempty" protobuf:"bytes,2,opt,name=targetRevision"`
+	// Path is the path to the manifests within the repository.
+	Path string `json:"path,omitempty" protobuf:"bytes,3,opt,name=path"`
+}
+
+// ManagedArgoCDAppDestination describes where the manifests of an Argo CD
+// Application managed by a Stage are deployed.
+//
+// +kubebuilder:validation:XValidation:message="exactly one of server or name must be specified",rule="has(self.server) ? !has(self.name) : has(self.name)"
+type ManagedArgoCDAppDestination struct {
+	// Server is the URL of the Kubernetes API server of the target cluster.
+	// Mutually exclusive with Name.
+	Server string `json:"server,omitempty" protobuf:"bytes,1,opt,name=server"`
+	// Name is the name of the target cluster, as known to Argo CD. Mutually
+	// exclusive with Server.
+	Name string `json:"name,omitempty" protobuf:"bytes,2,opt,name=name"`
+	// Namespace is the namespace manifests are deployed to.
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+}
+
+// ManagedArgoCDAppSyncPolicy describes when and how an Argo CD Application
+// managed by a Stage is synced.
+type ManagedArgoCDAppSyncPolicy struct {
+	// Automated indicates whether Argo CD should automatically sync the
+	// Application when it is out of sync.
+	Automated bool `json:"automated,omitempty" protobuf:"varint,1,opt,name=automated"`
+	// Prune indicates whether automated syncs may delete resources that are no
+	// longer defined in the source.
+	Prune bool `json:"prune,omitempty" protobuf:"varint,2,opt,name=prune"`
+	// SelfHeal indicates whether automated syncs are triggered when the live
+	// state deviates from the desired state.
+	SelfHeal bool `json:"selfHeal,omitempty" protobuf:"varint,3,opt,name=selfHeal"`
+	// SyncOptions are options applied to every sync of the Application.
+	SyncOptions []string `json:"syncOptions,omitempty" protobuf:"bytes,4,rep,name=syncOptions"`
 }
 
 // StageStatus describes a Stages's current and recent Freight, health, and
@@ -303,6 +857,11 @@ type StageStatus struct {
 	// determine whether the request to refresh the resource has been handled.
 	// +optional
 	LastHandledRefresh string `json:"lastHandledRefresh,omitempty" protobuf:"bytes,11,opt,name=lastHandledRefresh"`
+	// LastHandledReplay holds the value of the most recent
+	// AnnotationKeyReplayPromotionRequests annotation that was handled by the
+	// controller.
+	// +optional
+	LastHandledReplay string `json:"lastHandledReplay,omitempty" protobuf:"bytes,18,opt,name=lastHandledReplay"`
 	// Phase describes where the Stage currently is in its lifecycle.
 	Phase StagePhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase"`
 	// FreightHistory is a list of recent Freight selections that were deployed
@@ -333,6 +892,35 @@ type StageStatus struct {
 	CurrentPromotion *PromotionReference `json:"currentPromotion,omitempty" protobuf:"bytes,7,opt,name=currentPromotion"`
 	// LastPromotion is a reference to the last completed promotion.
 	LastPromotion *PromotionReference `json:"lastPromotion,omitempty" protobuf:"bytes,10,opt,name=lastPromotion"`
+	// PromotionHistory is a list of recently completed Promotions to the Stage,
+	// regardless of their outcome. By default, the last ten Promotions are
+	// stored. The first item in the list is the most recently completed
+	// Promotion, subsequent items are older Promotions. Unlike the Promotion
+	// resources themselves, these records are retained when the Promotions they
+	// describe are deleted.
+	PromotionHistory PromotionHistory `json:"promotionHistory,omitempty" protobuf:"bytes,14,rep,name=promotionHistory"`
+	// PromotionQueue describes the Promotions that are waiting for their turn
+	// to be executed against the Stage. It is absent when no Promotions are
+	// waiting.
+	PromotionQueue *PromotionQueue `json:"promotionQueue,omitempty" protobuf:"bytes,15,opt,name=promotionQueue"`
+	// Images compares the container images that have been promoted to the
+	// Stage with those that are about to be promoted to it and with those that
+	// have been promoted to its upstream Stages. It is recomputed on every
+	// reconciliation.
+	Images *StageImages `json:"images,omitempty" protobuf:"bytes,16,opt,name=images"`
+	// Drift describes changes that were made to a branch pushed to by the last
+	// Promotion after that Promotion completed, e.g. a hotfix applied by hand to
+	// rendered manifests. Unless the drift has been resolved, no further
+	// Promotions to the Stage are started. It is absent when no drift has been
+	// detected.
+	Drift *Drift `json:"drift,omitempty" protobuf:"bytes,17,opt,name=drift"`
+	// RenderedBranchCommit records the commit that Kargo last pushed to the
+	// Stage's rendered branch. It is updated from the status of each
+	// Promotion to the Stage that pushed to the branch once the Promotion
+	// completes, and is compared to the commit the branch points to by the
+	// next Promotion before it pushes to the branch, to detect modifications
+	// of the branch made by something other than Kargo.
+	RenderedBranchCommit *RenderedBranchCommit `json:"renderedBranchCommit,omitempty" protobuf:"bytes,19,opt,name=renderedBranchCommit"`
 }
 
 func (w *StageStatus) GetConditions() []metav1.Condition {
@@ -592,6 +1180,230 @@ type PromotionReference struct {
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty" protobuf:"bytes,4,opt,name=finishedAt"`
 }
 
+// PromotionRecord is a lightweight record of a completed Promotion, retained
+// by a Stage for reporting purposes.
+type PromotionRecord struct {
+	// Name is the name of the Promotion.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Freight is the Freight that was promoted.
+	Freight *FreightReference `json:"freight,omitempty" protobuf:"bytes,2,opt,name=freight"`
+	// Phase is the terminal phase of the Promotion.
+	Phase PromotionPhase `json:"phase,omitempty" protobuf:"bytes,3,opt,name=phase"`
+	// Message is the message of the Promotion at the time it completed. It
+	// typically explains why a Promotion did not succeed.
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+	// StartedAt is the time at which the first step of the Promotion started
+	// executing.
+	StartedAt *metav1.Time `json:"startedAt,omitempty" protobuf:"bytes,5,opt,name=startedAt"`
+	// FinishedAt is the time at which the Promotion was completed.
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty" protobuf:"bytes,6,opt,name=finishedAt"`
+}
+
+// PromotionHistory is a linear list of PromotionRecords. The list is ordered by
+// the time at which the Promotions completed, with the most recently completed
+// Promotion at the top of the list.
+type PromotionHistory []PromotionRecord
+
+// Record adds the provided PromotionRecords as the most recent PromotionRecords
+// in the history. I.e. The first provided PromotionRecord becomes the first item
+// in the list. Existing records of the same Promotions are replaced. If the list
+// grows beyond maxSize items, the bottom items are removed.
+func (p *PromotionHistory) Record(maxSize int, records ...PromotionRecord) {
+	history := make(PromotionHistory, 0, len(records)+len(*p))
+	history = append(history, records...)
+	for _, existing := range *p {
+		if !slices.ContainsFunc(records, func(r PromotionRecord) bool {
+			return r.Name == existing.Name
+		}) {
+			history = append(history, existing)
+		}
+	}
+	if len(history) > maxSize {
+		history = history[:max(maxSize, 0)]
+	}
+	*p = history
+}
+
+// PromotionQueue describes the Promotions that are waiting for their turn to be
+// executed against a Stage.
+type PromotionQueue struct {
+	// Pending lists the names of the Promotions that are waiting for their turn,
+	// in the order in which they will be executed. The Promotion that is
+	// currently being executed is not included, as it is referenced by the
+	// Stage's CurrentPromotion field. Promotions are ordered by their effective
+	// priority, which is their priority raised for the time they have spent
+	// waiting, and Promotions of equal effective priority in the order in which
+	// they were created. This order therefore changes whenever a Promotion of
+	// higher priority is created or a Promotion has waited long enough to
+	// overtake others.
+	Pending []string `json:"pending,omitempty" protobuf:"bytes,1,rep,name=pending"`
+	// EstimatedWait is an estimate of how long a Promotion created now would
+	// have to wait before it is executed. It is derived from the durations of
+	// the Promotions in the Stage's PromotionHistory, and is absent when the
+	// history does not hold enough information to make an estimate.
+	EstimatedWait *metav1.Duration `json:"estimatedWait,omitempty" protobuf:"bytes,2,opt,name=estimatedWait"`
+}
+
+// GetPending returns the names of the queued Promotions. It is safe to call on
+// a nil PromotionQueue.
+func (q *PromotionQueue) GetPending() []string {
+	if q == nil {
+		return nil
+	}
+	return q.Pending
+}
+
+// RenderedBranchCommit records the commit that Kargo last pushed to the
+// rendered branch of a Stage.
+type RenderedBranchCommit struct {
+	// RepoURL is the URL of the repository the branch belongs to.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Branch is the name of the branch.
+	Branch string `json:"branch" protobuf:"bytes,2,opt,name=branch"`
+	// Commit is the ID of the commit.
+	Commit string `json:"commit" protobuf:"bytes,3,opt,name=commit"`
+	// Promotion is the name of the Promotion that pushed the commit. It is
+	// empty if the commit was not pushed by a Promotion, e.g. because drift of
+	// the branch to the commit was acknowledged.
+	Promotion string `json:"promotion,omitempty" protobuf:"bytes,4,opt,name=promotion"`
+}
+
+// DriftResolution describes how the drift of a branch has been resolved.
+type DriftResolution string
+
+const (
+	// DriftResolutionAcknowledged indicates that the drift was acknowledged
+	// using the AnnotationKeyAcknowledgeDrift annotation.
+	DriftResolutionAcknowledged DriftResolution = "Acknowledged"
+	// DriftResolutionPullRequestMerged indicates that the pull request that
+	// was opened to port the drift to the source branch was merged.
+	DriftResolutionPullRequestMerged DriftResolution = "PullRequestMerged"
+)
+
+// Drift describes changes that were made to a branch after a Promotion pushed
+// to it. The changes themselves are the difference between PromotedCommit and
+// DriftedCommit.
+type Drift struct {
+	// RepoURL is the URL of the repository containing the branch that drifted.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Branch is the name of the branch that drifted.
+	Branch string `json:"branch" protobuf:"bytes,2,opt,name=branch"`
+	// PromotedCommit is the ID of the commit that was pushed to the branch by
+	// the last Promotion.
+	PromotedCommit string `json:"promotedCommit" protobuf:"bytes,3,opt,name=promotedCommit"`
+	// DriftedCommit is the ID of the commit the branch pointed to when the
+	// drift was detected.
+	DriftedCommit string `json:"driftedCommit" protobuf:"bytes,4,opt,name=driftedCommit"`
+	// DetectedAt is the time at which the drift was first detected.
+	DetectedAt *metav1.Time `json:"detectedAt,omitempty" protobuf:"bytes,5,opt,name=detectedAt"`
+	// PullRequest describes the pull request that was opened to port the drift
+	// to the branch the promoted content is derived from. It is absent if no
+	// pull request was requested, or if it has not been opened yet.
+	PullRequest *DriftPullRequest `json:"pullRequest,omitempty" protobuf:"bytes,6,opt,name=pullRequest"`
+	// Resolution describes how the drift has been resolved. As long as it is
+	// empty, no further Promotions to the Stage are started.
+	Resolution DriftResolution `json:"resolution,omitempty" protobuf:"bytes,7,opt,name=resolution"`
+}
+
+// IsBlocking returns true if the Drift has not been resolved and therefore
+// prevents further Promotions to the Stage from being started. It is safe to
+// call on a nil Drift.
+func (d *Drift) IsBlocking() bool {
+	return d != nil && d.Resolution == ""
+}
+
+// DriftPullRequest describes a pull request that contains the changes of a
+// Drift as a patch, for a human to port to the source branch.
+type DriftPullRequest struct {
+	// RepoURL is the URL of the repository the pull request was opened in.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Number is the number of the pull request.
+	Number int64 `json:"number" protobuf:"varint,2,opt,name=number"`
+	// URL is the URL of the pull request.
+	URL string `json:"url,omitempty" protobuf:"bytes,3,opt,name=url"`
+	// Branch is the name of the branch containing the patch.
+	Branch string `json:"branch" protobuf:"bytes,4,opt,name=branch"`
+	// PatchPath is the path of the patch, relative to the root of the
+	// repository.
+	PatchPath string `json:"patchPath" protobuf:"bytes,5,opt,name=patchPath"`
+	// Merged is true if the pull request has been merged.
+	Merged bool `json:"merged,omitempty" protobuf:"varint,6,opt,name=merged"`
+}
+
+// StageImagesSource indicates where the images that have been promoted to a
+// Stage were learned from.
+type StageImagesSource string
+
+const (
+	// StageImagesSourceFreightHistory indicates that the images were derived
+	// from the Stage's FreightHistory.
+	StageImagesSourceFreightHistory StageImagesSource = "FreightHistory"
+	// StageImagesSourceArgoCDApps indicates that the images were reported by
+	// the Argo CD Applications managed by the Stage. This is the case when the
+	// Stage has no FreightHistory, e.g. because it has only recently been
+	// adopted by Kargo.
+	StageImagesSourceArgoCDApps StageImagesSource = "ArgoCDApps"
+)
+
+// StageImages compares the container images that have been promoted to a Stage
+// with those that are about to be promoted to it and with those that have been
+// promoted to its upstream Stages.
+type StageImages struct {
+	// Current lists the images that have most recently been promoted to the
+	// Stage, ordered by repository URL.
+	Current []Image `json:"current,omitempty" protobuf:"bytes,1,rep,name=current"`
+	// CurrentSource indicates where the images listed by Current were learned
+	// from.
+	CurrentSource StageImagesSource `json:"currentSource,omitempty" protobuf:"bytes,2,opt,name=currentSource"`
+	// Next lists the images of the Freight that is being promoted to the Stage
+	// or, if no Promotion is running, the Freight of the next queued
+	// Promotion. It is absent when there is neither.
+	Next []Image `json:"next,omitempty" protobuf:"bytes,3,rep,name=next"`
+	// Upstream describes, for each of the Stage's upstream Stages, how the
+	// images that have been promoted to that Stage differ from those that have
+	// been promoted to this one.
+	Upstream []UpstreamStageImages `json:"upstream,omitempty" protobuf:"bytes,4,rep,name=upstream"`
+}
+
+// GetCurrent returns the images that have most recently been promoted to the
+// Stage. It is safe to call on a nil StageImages.
+func (s *StageImages) GetCurrent() []Image {
+	if s == nil {
+		return nil
+	}
+	return s.Current
+}
+
+// UpstreamStageImages describes how the images that have been promoted to an
+// upstream Stage differ from those that have been promoted to a Stage.
+type UpstreamStageImages struct {
+	// Stage is the name of the upstream Stage.
+	Stage string `json:"stage" protobuf:"bytes,1,opt,name=stage"`
+	// Differences lists the images whose versions differ between the upstream
+	// Stage and this one, ordered by repository URL. It is empty when the
+	// Stages are in step.
+	Differences []ImageDifference `json:"differences,omitempty" protobuf:"bytes,2,rep,name=differences"`
+}
+
+// ImageDifference describes an image whose version differs between an upstream
+// Stage and a Stage.
+type ImageDifference struct {
+	// RepoURL is the URL of the image repository.
+	RepoURL string `json:"repoURL" protobuf:"bytes,1,opt,name=repoURL"`
+	// Version is the tag or, in its absence, the digest of the image in the
+	// Stage. It is empty if the image has not been promoted to the Stage.
+	Version string `json:"version,omitempty" protobuf:"bytes,2,opt,name=version"`
+	// UpstreamVersion is the tag or, in its absence, the digest of the image in
+	// the upstream Stage.
+	UpstreamVersion string `json:"upstreamVersion" protobuf:"bytes,3,opt,name=upstreamVersion"`
+	// VersionsBehind is the number of versions by which the Stage trails the
+	// upstream Stage. It is negative if the Stage is ahead. It is counted
+	// among the tags most recently discovered by the Warehouse that the image
+	// originated from, and is only set when both versions are semantic
+	// versions.
+	VersionsBehind *int32 `json:"versionsBehind,omitempty" protobuf:"varint,4,opt,name=versionsBehind"`
+}
+
 // GetHealthChecks returns the list of health checks for the PromotionReference.
 func (r *PromotionReference) GetHealthChecks() []HealthCheckStep {
 	if r == nil || r.Status == nil {
@@ -536,6 +536,65 @@ func TestVerificationInfoStack_UpdateOrPush(t *testing.T) {
 	}
 }
 
+func TestPromotionHistory_Record(t *testing.T) {
+	testCases := []struct {
+		name            string
+		history         PromotionHistory
+		maxSize         int
+		newRecords      []PromotionRecord
+		expectedHistory PromotionHistory
+	}{
+		{
+			name:            "initial history is nil",
+			history:         nil,
+			maxSize:         10,
+			newRecords:      []PromotionRecord{{Name: "foo"}, {Name: "bar"}},
+			expectedHistory: PromotionHistory{{Name: "foo"}, {Name: "bar"}},
+		},
+		{
+			name:            "initial history is not nil",
+			history:         PromotionHistory{{Name: "foo"}},
+			maxSize:         10,
+			newRecords:      []PromotionRecord{{Name: "bar"}, {Name: "baz"}},
+			expectedHistory: PromotionHistory{{Name: "bar"}, {Name: "baz"}, {Name: "foo"}},
+		},
+		{
+			name:    "initial history has matching names",
+			history: PromotionHistory{{Name: "foo"}, {Name: "bar"}},
+			maxSize: 10,
+			newRecords: []PromotionRecord{
+				{Name: "bar", Phase: PromotionPhaseFailed},
+				{Name: "baz"},
+			},
+			expectedHistory: PromotionHistory{
+				{Name: "bar", Phase: PromotionPhaseFailed},
+				{Name: "baz"},
+				{Name: "foo"},
+			},
+		},
+		{
+			name:            "history grows beyond max size",
+			history:         PromotionHistory{{Name: "foo"}, {Name: "bar"}},
+			maxSize:         2,
+			newRecords:      []PromotionRecord{{Name: "baz"}},
+			expectedHistory: PromotionHistory{{Name: "baz"}, {Name: "foo"}},
+		},
+		{
+			name:            "max size is zero",
+			history:         PromotionHistory{{Name: "foo"}},
+			maxSize:         0,
+			newRecords:      []PromotionRecord{{Name: "bar"}},
+			expectedHistory: PromotionHistory{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.history.Record(testCase.maxSize, testCase.newRecords...)
+			require.Equal(t, testCase.expectedHistory, testCase.history)
+		})
+	}
+}
+
 func TestImageDeepEquals(t *testing.T) {
 	testCases := []struct {
 		name           string
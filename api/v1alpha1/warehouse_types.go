@@ -72,6 +72,19 @@ type WarehouseSpec struct {
 	// +kubebuilder:default=Automatic
 	// +kubebuilder:validation:Optional
 	FreightCreationPolicy FreightCreationPolicy `json:"freightCreationPolicy" protobuf:"bytes,3,opt,name=freightCreationPolicy"`
+	// FreightBatchWindow, when specified, causes Freight creation to be deferred
+	// for this long after changes to the latest artifacts are first discovered.
+	// Further changes discovered within the window (for instance, due to refresh
+	// requests issued for each of several images built by a single CI run) are
+	// combined into the same Freight instead of each producing Freight of its
+	// own. This field is optional and only has any effect when the
+	// FreightCreationPolicy is Automatic. When left unspecified, Freight is
+	// created as soon as changes are discovered.
+	//
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+	// +kubebuilder:validation:Optional
+	FreightBatchWindow *metav1.Duration `json:"freightBatchWindow,omitempty" protobuf:"bytes,5,opt,name=freightBatchWindow"`
 	// Subscriptions describes sources of artifacts to be included in Freight
 	// produced by this Warehouse.
 	//
@@ -208,6 +221,24 @@ type GitSubscription struct {
 
 // ImageSubscription defines a subscription to an image repository.
 type ImageSubscription struct {
+	// Name uniquely identifies the subscription among the Warehouse's image
+	// subscriptions. Its status is reported under this name in the Warehouse's
+	// status. This field is optional. When left unspecified, the subscription
+	// is identified by its RepoURL instead.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name,omitempty" protobuf:"bytes,11,opt,name=name"`
+	// Paused specifies whether discovery of new images for this subscription
+	// is paused. While it is, the image last discovered for the subscription
+	// continues to be included in new Freight, but newer images are neither
+	// discovered nor cause Freight to be created, regardless of whether
+	// discovery was requested by a refresh or is due on the Warehouse's
+	// interval. Other subscriptions of the Warehouse are not affected.
+	//
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty" protobuf:"varint,12,opt,name=paused"`
 	// RepoURL specifies the URL of the image repository to subscribe to. The
 	// value in this field MUST NOT include an image tag. This field is required.
 	//
@@ -355,6 +386,71 @@ type WarehouseStatus struct {
 	LastFreightID string `json:"lastFreightID,omitempty" protobuf:"bytes,8,opt,name=lastFreightID"`
 	// DiscoveredArtifacts holds the artifacts discovered by the Warehouse.
 	DiscoveredArtifacts *DiscoveredArtifacts `json:"discoveredArtifacts,omitempty" protobuf:"bytes,7,opt,name=discoveredArtifacts"`
+	// PendingFreight describes Freight that the Warehouse will create from the
+	// latest discovered artifacts once its FreightBatchWindow has elapsed.
+	//
+	// +optional
+	PendingFreight *PendingFreight `json:"pendingFreight,omitempty" protobuf:"bytes,10,opt,name=pendingFreight"`
+	// ImageSubscriptions describes the most recently observed state of each of
+	// the Warehouse's image subscriptions.
+	//
+	// +optional
+	ImageSubscriptions []ImageSubscriptionStatus `json:"imageSubscriptions,omitempty" protobuf:"bytes,11,rep,name=imageSubscriptions"`
+}
+
+// ImageSubscriptionStatus describes the most recently observed state of an
+// ImageSubscription.
+type ImageSubscriptionStatus struct {
+	// Name is the name of the ImageSubscription, or its RepoURL if it has no
+	// name.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// RepoURL is the URL of the image repository of the ImageSubscription.
+	RepoURL string `json:"repoURL" protobuf:"bytes,2,opt,name=repoURL"`
+	// Paused indicates that discovery of new images for the ImageSubscription
+	// is paused.
+	//
+	// +optional
+	Paused bool `json:"paused,omitempty" protobuf:"varint,3,opt,name=paused"`
+	// LastDiscoveredAt is the time at which the image repository was last
+	// polled for images, whether because discovery was due on the Warehouse's
+	// interval or because it was requested by a refresh.
+	//
+	// +optional
+	LastDiscoveredAt *metav1.Time `json:"lastDiscoveredAt,omitempty" protobuf:"bytes,4,opt,name=lastDiscoveredAt"`
+	// LastTag is the tag of the newest image that was last discovered for the
+	// ImageSubscription.
+	//
+	// +optional
+	LastTag string `json:"lastTag,omitempty" protobuf:"bytes,5,opt,name=lastTag"`
+	// LastFreightID is the identifier (name) of the most recent Freight that
+	// was created while discovery for the ImageSubscription was not paused.
+	//
+	// +optional
+	LastFreightID string `json:"lastFreightID,omitempty" protobuf:"bytes,6,opt,name=lastFreightID"`
+	// Error describes why the most recent discovery of images for the
+	// ImageSubscription failed, e.g. because its constraint was invalid or the
+	// registry rejected the credentials. It is empty if discovery succeeded.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,7,opt,name=error"`
+}
+
+// PendingFreight describes Freight whose creation has been deferred by a
+// Warehouse's FreightBatchWindow.
+type PendingFreight struct {
+	// ID is the system-assigned identifier (name) of the Freight that would be
+	// created from the latest discovered artifacts. It is updated in place as
+	// further changes are discovered within the batch window.
+	ID string `json:"id" protobuf:"bytes,1,opt,name=id"`
+	// Since is the time at which changes to the latest artifacts were first
+	// discovered, and therefore the time at which the batch window opened.
+	Since metav1.Time `json:"since" protobuf:"bytes,2,opt,name=since"`
+	// Refreshes holds the values of the AnnotationKeyRefresh annotations that
+	// were handled while the batch window was open. These identify the refresh
+	// requests that contributed to the Freight.
+	//
+	// +optional
+	Refreshes []string `json:"refreshes,omitempty" protobuf:"bytes,3,rep,name=refreshes"`
 }
 
 func (w *WarehouseStatus) GetConditions() []metav1.Condition {
@@ -357,6 +357,152 @@ func (in *DiscoveredImageReference) DeepCopy() *DiscoveredImageReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Drift) DeepCopyInto(out *Drift) {
+	*out = *in
+	if in.DetectedAt != nil {
+		in, out := &in.DetectedAt, &out.DetectedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.PullRequest != nil {
+		in, out := &in.PullRequest, &out.PullRequest
+		*out = new(DriftPullRequest)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Drift.
+func (in *Drift) DeepCopy() *Drift {
+	if in == nil {
+		return nil
+	}
+	out := new(Drift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftPullRequest) DeepCopyInto(out *DriftPullRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftPullRequest.
+func (in *DriftPullRequest) DeepCopy() *DriftPullRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftPullRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunApply) DeepCopyInto(out *DryRunApply) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunApply.
+func (in *DryRunApply) DeepCopy() *DryRunApply {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunApply)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecCommand) DeepCopyInto(out *ExecCommand) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ExecEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxOutputBytes != nil {
+		in, out := &in.MaxOutputBytes, &out.MaxOutputBytes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecCommand.
+func (in *ExecCommand) DeepCopy() *ExecCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecEnvVar) DeepCopyInto(out *ExecEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecEnvVar.
+func (in *ExecEnvVar) DeepCopy() *ExecEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecPolicy) DeepCopyInto(out *ExecPolicy) {
+	*out = *in
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]ExecCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecPolicy.
+func (in *ExecPolicy) DeepCopy() *ExecPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalModification) DeepCopyInto(out *ExternalModification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalModification.
+func (in *ExternalModification) DeepCopy() *ExternalModification {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalModification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Freight) DeepCopyInto(out *Freight) {
 	*out = *in
@@ -609,6 +755,36 @@ func (in *FreightStatus) DeepCopy() *FreightStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitClientConfig) DeepCopyInto(out *GitClientConfig) {
+	*out = *in
+	if in.MaxConcurrentOpsPerHost != nil {
+		in, out := &in.MaxConcurrentOpsPerHost, &out.MaxConcurrentOpsPerHost
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxOpsPerMinutePerHost != nil {
+		in, out := &in.MaxOpsPerMinutePerHost, &out.MaxOpsPerMinutePerHost
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetworkMaxAttempts != nil {
+		in, out := &in.NetworkMaxAttempts, &out.NetworkMaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitClientConfig.
+func (in *GitClientConfig) DeepCopy() *GitClientConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitClientConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitCommit) DeepCopyInto(out *GitCommit) {
 	*out = *in
@@ -646,6 +822,21 @@ func (in *GitDiscoveryResult) DeepCopy() *GitDiscoveryResult {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitIdentity) DeepCopyInto(out *GitIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitIdentity.
+func (in *GitIdentity) DeepCopy() *GitIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(GitIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitSubscription) DeepCopyInto(out *GitSubscription) {
 	*out = *in
@@ -741,6 +932,26 @@ func (in *Image) DeepCopy() *Image {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageDifference) DeepCopyInto(out *ImageDifference) {
+	*out = *in
+	if in.VersionsBehind != nil {
+		in, out := &in.VersionsBehind, &out.VersionsBehind
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDifference.
+func (in *ImageDifference) DeepCopy() *ImageDifference {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageDifference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageDiscoveryResult) DeepCopyInto(out *ImageDiscoveryResult) {
 	*out = *in
@@ -764,151 +975,134 @@ func (in *ImageDiscoveryResult) DeepCopy() *ImageDiscoveryResult {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSubscription) DeepCopyInto(out *ImageSubscription) {
+func (in *ImageLimits) DeepCopyInto(out *ImageLimits) {
 	*out = *in
-	if in.IgnoreTags != nil {
-		in, out := &in.IgnoreTags, &out.IgnoreTags
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.CommitMessageMaxImages != nil {
+		in, out := &in.CommitMessageMaxImages, &out.CommitMessageMaxImages
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StatusMaxImages != nil {
+		in, out := &in.StatusMaxImages, &out.StatusMaxImages
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SoftLimit != nil {
+		in, out := &in.SoftLimit, &out.SoftLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HardLimit != nil {
+		in, out := &in.HardLimit, &out.HardLimit
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSubscription.
-func (in *ImageSubscription) DeepCopy() *ImageSubscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLimits.
+func (in *ImageLimits) DeepCopy() *ImageLimits {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSubscription)
+	out := new(ImageLimits)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Project) DeepCopyInto(out *Project) {
+func (in *ImageMapping) DeepCopyInto(out *ImageMapping) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.Spec != nil {
-		in, out := &in.Spec, &out.Spec
-		*out = new(ProjectSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
-func (in *Project) DeepCopy() *Project {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMapping.
+func (in *ImageMapping) DeepCopy() *ImageMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(Project)
+	out := new(ImageMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Project) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+func (in *ImageSetDigest) DeepCopyInto(out *ImageSetDigest) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Project, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]Image, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
-func (in *ProjectList) DeepCopy() *ProjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSetDigest.
+func (in *ImageSetDigest) DeepCopy() *ImageSetDigest {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectList)
+	out := new(ImageSetDigest)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
+func (in *ImageSubscription) DeepCopyInto(out *ImageSubscription) {
 	*out = *in
-	if in.PromotionPolicies != nil {
-		in, out := &in.PromotionPolicies, &out.PromotionPolicies
-		*out = make([]PromotionPolicy, len(*in))
+	if in.IgnoreTags != nil {
+		in, out := &in.IgnoreTags, &out.IgnoreTags
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
-func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSubscription.
+func (in *ImageSubscription) DeepCopy() *ImageSubscription {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectSpec)
+	out := new(ImageSubscription)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+func (in *ImageSubscriptionStatus) DeepCopyInto(out *ImageSubscriptionStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastDiscoveredAt != nil {
+		in, out := &in.LastDiscoveredAt, &out.LastDiscoveredAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
-func (in *ProjectStatus) DeepCopy() *ProjectStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSubscriptionStatus.
+func (in *ImageSubscriptionStatus) DeepCopy() *ImageSubscriptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectStatus)
+	out := new(ImageSubscriptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Promotion) DeepCopyInto(out *Promotion) {
+func (in *KargoConfig) DeepCopyInto(out *KargoConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Promotion.
-func (in *Promotion) DeepCopy() *Promotion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KargoConfig.
+func (in *KargoConfig) DeepCopy() *KargoConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Promotion)
+	out := new(KargoConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Promotion) DeepCopyObject() runtime.Object {
+func (in *KargoConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -916,31 +1110,31 @@ func (in *Promotion) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionList) DeepCopyInto(out *PromotionList) {
+func (in *KargoConfigList) DeepCopyInto(out *KargoConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Promotion, len(*in))
+		*out = make([]KargoConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionList.
-func (in *PromotionList) DeepCopy() *PromotionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KargoConfigList.
+func (in *KargoConfigList) DeepCopy() *KargoConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionList)
+	out := new(KargoConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PromotionList) DeepCopyObject() runtime.Object {
+func (in *KargoConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -948,12 +1142,444 @@ func (in *PromotionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionPolicy) DeepCopyInto(out *PromotionPolicy) {
+func (in *KargoConfigSpec) DeepCopyInto(out *KargoConfigSpec) {
 	*out = *in
+	if in.GitClient != nil {
+		in, out := &in.GitClient, &out.GitClient
+		*out = new(GitClientConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepoPolicy != nil {
+		in, out := &in.RepoPolicy, &out.RepoPolicy
+		*out = new(RepoPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageLimits != nil {
+		in, out := &in.ImageLimits, &out.ImageLimits
+		*out = new(ImageLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecPolicy != nil {
+		in, out := &in.ExecPolicy, &out.ExecPolicy
+		*out = new(ExecPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceLimits != nil {
+		in, out := &in.ResourceLimits, &out.ResourceLimits
+		*out = new(ResourceLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteBasePolicy != nil {
+		in, out := &in.RemoteBasePolicy, &out.RemoteBasePolicy
+		*out = new(RemoteBasePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionPolicy.
-func (in *PromotionPolicy) DeepCopy() *PromotionPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KargoConfigSpec.
+func (in *KargoConfigSpec) DeepCopy() *KargoConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KargoConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedArgoCDApp) DeepCopyInto(out *ManagedArgoCDApp) {
+	*out = *in
+	out.Source = in.Source
+	out.Destination = in.Destination
+	if in.SyncPolicy != nil {
+		in, out := &in.SyncPolicy, &out.SyncPolicy
+		*out = new(ManagedArgoCDAppSyncPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedArgoCDApp.
+func (in *ManagedArgoCDApp) DeepCopy() *ManagedArgoCDApp {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedArgoCDApp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedArgoCDAppDestination) DeepCopyInto(out *ManagedArgoCDAppDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedArgoCDAppDestination.
+func (in *ManagedArgoCDAppDestination) DeepCopy() *ManagedArgoCDAppDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedArgoCDAppDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedArgoCDAppSource) DeepCopyInto(out *ManagedArgoCDAppSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedArgoCDAppSource.
+func (in *ManagedArgoCDAppSource) DeepCopy() *ManagedArgoCDAppSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedArgoCDAppSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedArgoCDAppSyncPolicy) DeepCopyInto(out *ManagedArgoCDAppSyncPolicy) {
+	*out = *in
+	if in.SyncOptions != nil {
+		in, out := &in.SyncOptions, &out.SyncOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedArgoCDAppSyncPolicy.
+func (in *ManagedArgoCDAppSyncPolicy) DeepCopy() *ManagedArgoCDAppSyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedArgoCDAppSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OriginCommit) DeepCopyInto(out *OriginCommit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OriginCommit.
+func (in *OriginCommit) DeepCopy() *OriginCommit {
+	if in == nil {
+		return nil
+	}
+	out := new(OriginCommit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingFreight) DeepCopyInto(out *PendingFreight) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	if in.Refreshes != nil {
+		in, out := &in.Refreshes, &out.Refreshes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingFreight.
+func (in *PendingFreight) DeepCopy() *PendingFreight {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingFreight)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Project) DeepCopyInto(out *Project) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(ProjectSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
+func (in *Project) DeepCopy() *Project {
+	if in == nil {
+		return nil
+	}
+	out := new(Project)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Project) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Project, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
+func (in *ProjectList) DeepCopy() *ProjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
+	*out = *in
+	if in.PromotionPolicies != nil {
+		in, out := &in.PromotionPolicies, &out.PromotionPolicies
+		*out = make([]PromotionPolicy, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
+func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
+func (in *ProjectStatus) DeepCopy() *ProjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotedOverlay) DeepCopyInto(out *PromotedOverlay) {
+	*out = *in
+	if in.GitIdentity != nil {
+		in, out := &in.GitIdentity, &out.GitIdentity
+		*out = new(GitIdentity)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotedOverlay.
+func (in *PromotedOverlay) DeepCopy() *PromotedOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotedOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Promotion) DeepCopyInto(out *Promotion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Promotion.
+func (in *Promotion) DeepCopy() *Promotion {
+	if in == nil {
+		return nil
+	}
+	out := new(Promotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Promotion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApproval) DeepCopyInto(out *PromotionApproval) {
+	*out = *in
+	if in.ApprovedAt != nil {
+		in, out := &in.ApprovedAt, &out.ApprovedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApproval.
+func (in *PromotionApproval) DeepCopy() *PromotionApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApprovalPolicy) DeepCopyInto(out *PromotionApprovalPolicy) {
+	*out = *in
+	if in.AllowedApprovers != nil {
+		in, out := &in.AllowedApprovers, &out.AllowedApprovers
+		*out = make([]PromotionApprover, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApprovalPolicy.
+func (in *PromotionApprovalPolicy) DeepCopy() *PromotionApprovalPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApprovalPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApprover) DeepCopyInto(out *PromotionApprover) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApprover.
+func (in *PromotionApprover) DeepCopy() *PromotionApprover {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApprover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PromotionHistory) DeepCopyInto(out *PromotionHistory) {
+	{
+		in := &in
+		*out = make(PromotionHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionHistory.
+func (in PromotionHistory) DeepCopy() PromotionHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionHistory)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionLanes) DeepCopyInto(out *PromotionLanes) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionLanes.
+func (in *PromotionLanes) DeepCopy() *PromotionLanes {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionLanes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionList) DeepCopyInto(out *PromotionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Promotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionList.
+func (in *PromotionList) DeepCopy() *PromotionList {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PromotionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionPolicy) DeepCopyInto(out *PromotionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionPolicy.
+func (in *PromotionPolicy) DeepCopy() *PromotionPolicy {
 	if in == nil {
 		return nil
 	}
@@ -962,6 +1588,59 @@ func (in *PromotionPolicy) DeepCopy() *PromotionPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionQueue) DeepCopyInto(out *PromotionQueue) {
+	*out = *in
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EstimatedWait != nil {
+		in, out := &in.EstimatedWait, &out.EstimatedWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionQueue.
+func (in *PromotionQueue) DeepCopy() *PromotionQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionRecord) DeepCopyInto(out *PromotionRecord) {
+	*out = *in
+	if in.Freight != nil {
+		in, out := &in.Freight, &out.Freight
+		*out = new(FreightReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionRecord.
+func (in *PromotionRecord) DeepCopy() *PromotionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromotionReference) DeepCopyInto(out *PromotionReference) {
 	*out = *in
@@ -1006,6 +1685,26 @@ func (in *PromotionSpec) DeepCopyInto(out *PromotionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Lanes != nil {
+		in, out := &in.Lanes, &out.Lanes
+		*out = new(PromotionLanes)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(PromotionApprovalPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OriginCommit != nil {
+		in, out := &in.OriginCommit, &out.OriginCommit
+		*out = new(OriginCommit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionSpec.
@@ -1054,6 +1753,79 @@ func (in *PromotionStatus) DeepCopyInto(out *PromotionStatus) {
 		*out = new(apiextensionsv1.JSON)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RepoPolicyDecisions != nil {
+		in, out := &in.RepoPolicyDecisions, &out.RepoPolicyDecisions
+		*out = make([]RepoPolicyDecision, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ImageSetDigest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = make([]PromotedOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(PromotionApproval)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RenderedBranchPush != nil {
+		in, out := &in.RenderedBranchPush, &out.RenderedBranchPush
+		*out = new(RenderedBranchPush)
+		**out = **in
+	}
+	if in.RenderedBranchCleanup != nil {
+		in, out := &in.RenderedBranchCleanup, &out.RenderedBranchCleanup
+		*out = new(RenderedBranchCleanup)
+		**out = **in
+	}
+	if in.ExternalModification != nil {
+		in, out := &in.ExternalModification, &out.ExternalModification
+		*out = new(ExternalModification)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AwaitingApprovalSince != nil {
+		in, out := &in.AwaitingApprovalSince, &out.AwaitingApprovalSince
+		*out = (*in).DeepCopy()
+	}
+	if in.RetainedImages != nil {
+		in, out := &in.RetainedImages, &out.RetainedImages
+		*out = make([]RetainedImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceUpdateBatch != nil {
+		in, out := &in.SourceUpdateBatch, &out.SourceUpdateBatch
+		*out = new(SourceUpdateBatch)
+		**out = **in
+	}
+	if in.RenderWarnings != nil {
+		in, out := &in.RenderWarnings, &out.RenderWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GitIdentity != nil {
+		in, out := &in.GitIdentity, &out.GitIdentity
+		*out = new(GitIdentity)
+		**out = **in
+	}
+	if in.LastReconcileOutcome != nil {
+		in, out := &in.LastReconcileOutcome, &out.LastReconcileOutcome
+		*out = new(ReconcileOutcome)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionStatus.
@@ -1096,215 +1868,446 @@ func (in *PromotionStep) DeepCopy() *PromotionStep {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionStep)
+	out := new(PromotionStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionStepRetry) DeepCopyInto(out *PromotionStepRetry) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionStepRetry.
+func (in *PromotionStepRetry) DeepCopy() *PromotionStepRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionStepRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionTask) DeepCopyInto(out *PromotionTask) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTask.
+func (in *PromotionTask) DeepCopy() *PromotionTask {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PromotionTask) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionTaskList) DeepCopyInto(out *PromotionTaskList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PromotionTask, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTaskList.
+func (in *PromotionTaskList) DeepCopy() *PromotionTaskList {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionTaskList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PromotionTaskList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionTaskReference) DeepCopyInto(out *PromotionTaskReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTaskReference.
+func (in *PromotionTaskReference) DeepCopy() *PromotionTaskReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionTaskReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionTaskSpec) DeepCopyInto(out *PromotionTaskSpec) {
+	*out = *in
+	if in.Vars != nil {
+		in, out := &in.Vars, &out.Vars
+		*out = make([]PromotionVariable, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PromotionStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTaskSpec.
+func (in *PromotionTaskSpec) DeepCopy() *PromotionTaskSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionTaskSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionTemplate) DeepCopyInto(out *PromotionTemplate) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTemplate.
+func (in *PromotionTemplate) DeepCopy() *PromotionTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionTemplateSpec) DeepCopyInto(out *PromotionTemplateSpec) {
+	*out = *in
+	if in.Vars != nil {
+		in, out := &in.Vars, &out.Vars
+		*out = make([]PromotionVariable, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PromotionStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Lanes != nil {
+		in, out := &in.Lanes, &out.Lanes
+		*out = new(PromotionLanes)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTemplateSpec.
+func (in *PromotionTemplateSpec) DeepCopy() *PromotionTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionVariable) DeepCopyInto(out *PromotionVariable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionVariable.
+func (in *PromotionVariable) DeepCopy() *PromotionVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileOutcome) DeepCopyInto(out *ReconcileOutcome) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileOutcome.
+func (in *ReconcileOutcome) DeepCopy() *ReconcileOutcome {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileOutcome)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteBasePolicy) DeepCopyInto(out *RemoteBasePolicy) {
+	*out = *in
+	if in.AllowedHosts != nil {
+		in, out := &in.AllowedHosts, &out.AllowedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteBasePolicy.
+func (in *RemoteBasePolicy) DeepCopy() *RemoteBasePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteBasePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenderedBranch) DeepCopyInto(out *RenderedBranch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderedBranch.
+func (in *RenderedBranch) DeepCopy() *RenderedBranch {
+	if in == nil {
+		return nil
+	}
+	out := new(RenderedBranch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionStepRetry) DeepCopyInto(out *PromotionStepRetry) {
+func (in *RenderedBranchCleanup) DeepCopyInto(out *RenderedBranchCleanup) {
 	*out = *in
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
-		*out = new(v1.Duration)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionStepRetry.
-func (in *PromotionStepRetry) DeepCopy() *PromotionStepRetry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderedBranchCleanup.
+func (in *RenderedBranchCleanup) DeepCopy() *RenderedBranchCleanup {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionStepRetry)
+	out := new(RenderedBranchCleanup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionTask) DeepCopyInto(out *PromotionTask) {
+func (in *RenderedBranchCommit) DeepCopyInto(out *RenderedBranchCommit) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTask.
-func (in *PromotionTask) DeepCopy() *PromotionTask {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderedBranchCommit.
+func (in *RenderedBranchCommit) DeepCopy() *RenderedBranchCommit {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionTask)
+	out := new(RenderedBranchCommit)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PromotionTask) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenderedBranchPush) DeepCopyInto(out *RenderedBranchPush) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderedBranchPush.
+func (in *RenderedBranchPush) DeepCopy() *RenderedBranchPush {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(RenderedBranchPush)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionTaskList) DeepCopyInto(out *PromotionTaskList) {
+func (in *RepoPolicy) DeepCopyInto(out *RepoPolicy) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]PromotionTask, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTaskList.
-func (in *PromotionTaskList) DeepCopy() *PromotionTaskList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoPolicy.
+func (in *RepoPolicy) DeepCopy() *RepoPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionTaskList)
+	out := new(RepoPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PromotionTaskList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoPolicyDecision) DeepCopyInto(out *RepoPolicyDecision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoPolicyDecision.
+func (in *RepoPolicyDecision) DeepCopy() *RepoPolicyDecision {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(RepoPolicyDecision)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionTaskReference) DeepCopyInto(out *PromotionTaskReference) {
+func (in *RepoSubscription) DeepCopyInto(out *RepoSubscription) {
 	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSubscription)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ImageSubscription)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ChartSubscription)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTaskReference.
-func (in *PromotionTaskReference) DeepCopy() *PromotionTaskReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoSubscription.
+func (in *RepoSubscription) DeepCopy() *RepoSubscription {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionTaskReference)
+	out := new(RepoSubscription)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionTaskSpec) DeepCopyInto(out *PromotionTaskSpec) {
+func (in *ResourceLimits) DeepCopyInto(out *ResourceLimits) {
 	*out = *in
-	if in.Vars != nil {
-		in, out := &in.Vars, &out.Vars
-		*out = make([]PromotionVariable, len(*in))
-		copy(*out, *in)
+	if in.MaxRepoSize != nil {
+		in, out := &in.MaxRepoSize, &out.MaxRepoSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.Steps != nil {
-		in, out := &in.Steps, &out.Steps
-		*out = make([]PromotionStep, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaxRenderedOutputSize != nil {
+		in, out := &in.MaxRenderedOutputSize, &out.MaxRenderedOutputSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTaskSpec.
-func (in *PromotionTaskSpec) DeepCopy() *PromotionTaskSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceLimits.
+func (in *ResourceLimits) DeepCopy() *ResourceLimits {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionTaskSpec)
+	out := new(ResourceLimits)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionTemplate) DeepCopyInto(out *PromotionTemplate) {
+func (in *RetainedImage) DeepCopyInto(out *RetainedImage) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTemplate.
-func (in *PromotionTemplate) DeepCopy() *PromotionTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetainedImage.
+func (in *RetainedImage) DeepCopy() *RetainedImage {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionTemplate)
+	out := new(RetainedImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionTemplateSpec) DeepCopyInto(out *PromotionTemplateSpec) {
+func (in *ServiceAccountReference) DeepCopyInto(out *ServiceAccountReference) {
 	*out = *in
-	if in.Vars != nil {
-		in, out := &in.Vars, &out.Vars
-		*out = make([]PromotionVariable, len(*in))
-		copy(*out, *in)
-	}
-	if in.Steps != nil {
-		in, out := &in.Steps, &out.Steps
-		*out = make([]PromotionStep, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionTemplateSpec.
-func (in *PromotionTemplateSpec) DeepCopy() *PromotionTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountReference.
+func (in *ServiceAccountReference) DeepCopy() *ServiceAccountReference {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionTemplateSpec)
+	out := new(ServiceAccountReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromotionVariable) DeepCopyInto(out *PromotionVariable) {
+func (in *SourceUpdateBatch) DeepCopyInto(out *SourceUpdateBatch) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionVariable.
-func (in *PromotionVariable) DeepCopy() *PromotionVariable {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceUpdateBatch.
+func (in *SourceUpdateBatch) DeepCopy() *SourceUpdateBatch {
 	if in == nil {
 		return nil
 	}
-	out := new(PromotionVariable)
+	out := new(SourceUpdateBatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepoSubscription) DeepCopyInto(out *RepoSubscription) {
+func (in *SourceUpdateBatching) DeepCopyInto(out *SourceUpdateBatching) {
 	*out = *in
-	if in.Git != nil {
-		in, out := &in.Git, &out.Git
-		*out = new(GitSubscription)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(ImageSubscription)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Chart != nil {
-		in, out := &in.Chart, &out.Chart
-		*out = new(ChartSubscription)
+	if in.MergeInterval != nil {
+		in, out := &in.MergeInterval, &out.MergeInterval
+		*out = new(v1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoSubscription.
-func (in *RepoSubscription) DeepCopy() *RepoSubscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceUpdateBatching.
+func (in *SourceUpdateBatching) DeepCopy() *SourceUpdateBatching {
 	if in == nil {
 		return nil
 	}
-	out := new(RepoSubscription)
+	out := new(SourceUpdateBatching)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1336,6 +2339,38 @@ func (in *Stage) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageImages) DeepCopyInto(out *StageImages) {
+	*out = *in
+	if in.Current != nil {
+		in, out := &in.Current, &out.Current
+		*out = make([]Image, len(*in))
+		copy(*out, *in)
+	}
+	if in.Next != nil {
+		in, out := &in.Next, &out.Next
+		*out = make([]Image, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upstream != nil {
+		in, out := &in.Upstream, &out.Upstream
+		*out = make([]UpstreamStageImages, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageImages.
+func (in *StageImages) DeepCopy() *StageImages {
+	if in == nil {
+		return nil
+	}
+	out := new(StageImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageList) DeepCopyInto(out *StageList) {
 	*out = *in
@@ -1368,6 +2403,26 @@ func (in *StageList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageOverlay) DeepCopyInto(out *StageOverlay) {
+	*out = *in
+	if in.GitIdentity != nil {
+		in, out := &in.GitIdentity, &out.GitIdentity
+		*out = new(GitIdentity)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageOverlay.
+func (in *StageOverlay) DeepCopy() *StageOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(StageOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageSpec) DeepCopyInto(out *StageSpec) {
 	*out = *in
@@ -1388,6 +2443,72 @@ func (in *StageSpec) DeepCopyInto(out *StageSpec) {
 		*out = new(Verification)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ArgoCDApps != nil {
+		in, out := &in.ArgoCDApps, &out.ArgoCDApps
+		*out = make([]ManagedArgoCDApp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAccountRef != nil {
+		in, out := &in.ServiceAccountRef, &out.ServiceAccountRef
+		*out = new(ServiceAccountReference)
+		**out = **in
+	}
+	if in.RenderedBranch != nil {
+		in, out := &in.RenderedBranch, &out.RenderedBranch
+		*out = new(RenderedBranch)
+		**out = **in
+	}
+	if in.ImageMappings != nil {
+		in, out := &in.ImageMappings, &out.ImageMappings
+		*out = make([]ImageMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.ToolVersions != nil {
+		in, out := &in.ToolVersions, &out.ToolVersions
+		*out = new(ToolVersions)
+		**out = **in
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = make([]StageOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PromotionApproval != nil {
+		in, out := &in.PromotionApproval, &out.PromotionApproval
+		*out = new(PromotionApprovalPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceLimits != nil {
+		in, out := &in.ResourceLimits, &out.ResourceLimits
+		*out = new(ResourceLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunApply != nil {
+		in, out := &in.DryRunApply, &out.DryRunApply
+		*out = new(DryRunApply)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceUpdateBatching != nil {
+		in, out := &in.SourceUpdateBatching, &out.SourceUpdateBatching
+		*out = new(SourceUpdateBatching)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitIdentity != nil {
+		in, out := &in.GitIdentity, &out.GitIdentity
+		*out = new(GitIdentity)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageSpec.
@@ -1436,6 +2557,33 @@ func (in *StageStatus) DeepCopyInto(out *StageStatus) {
 		*out = new(PromotionReference)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PromotionHistory != nil {
+		in, out := &in.PromotionHistory, &out.PromotionHistory
+		*out = make(PromotionHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PromotionQueue != nil {
+		in, out := &in.PromotionQueue, &out.PromotionQueue
+		*out = new(PromotionQueue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(StageImages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RenderedBranchCommit != nil {
+		in, out := &in.RenderedBranchCommit, &out.RenderedBranchCommit
+		*out = new(RenderedBranchCommit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageStatus.
@@ -1459,6 +2607,10 @@ func (in *StepExecutionMetadata) DeepCopyInto(out *StepExecutionMetadata) {
 		in, out := &in.FinishedAt, &out.FinishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.WaitUntil != nil {
+		in, out := &in.WaitUntil, &out.WaitUntil
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepExecutionMetadata.
@@ -1492,6 +2644,43 @@ func (in StepExecutionMetadataList) DeepCopy() StepExecutionMetadataList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolVersions) DeepCopyInto(out *ToolVersions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolVersions.
+func (in *ToolVersions) DeepCopy() *ToolVersions {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolVersions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamStageImages) DeepCopyInto(out *UpstreamStageImages) {
+	*out = *in
+	if in.Differences != nil {
+		in, out := &in.Differences, &out.Differences
+		*out = make([]ImageDifference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamStageImages.
+func (in *UpstreamStageImages) DeepCopy() *UpstreamStageImages {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamStageImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Verification) DeepCopyInto(out *Verification) {
 	*out = *in
@@ -1658,6 +2847,11 @@ func (in *WarehouseList) DeepCopyObject() runtime.Object {
 func (in *WarehouseSpec) DeepCopyInto(out *WarehouseSpec) {
 	*out = *in
 	out.Interval = in.Interval
+	if in.FreightBatchWindow != nil {
+		in, out := &in.FreightBatchWindow, &out.FreightBatchWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.Subscriptions != nil {
 		in, out := &in.Subscriptions, &out.Subscriptions
 		*out = make([]RepoSubscription, len(*in))
@@ -1692,6 +2886,18 @@ func (in *WarehouseStatus) DeepCopyInto(out *WarehouseStatus) {
 		*out = new(DiscoveredArtifacts)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PendingFreight != nil {
+		in, out := &in.PendingFreight, &out.PendingFreight
+		*out = new(PendingFreight)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageSubscriptions != nil {
+		in, out := &in.ImageSubscriptions, &out.ImageSubscriptions
+		*out = make([]ImageSubscriptionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarehouseStatus.
@@ -91,6 +91,19 @@ app.kubernetes.io/component: management-controller
 app.kubernetes.io/component: webhooks-server
 {{- end -}}
 
+{{/*
+The directory under which the controller creates a working directory for each
+Promotion. If a volume is configured for it, it defaults to a directory other
+than the one for temporary files.
+*/}}
+{{- define "kargo.controller.promotionWorkDir" -}}
+{{- if .Values.controller.reconcilers.promotions.workDirVolume -}}
+{{- .Values.controller.reconcilers.promotions.workDir | default "/var/lib/kargo/promotions" -}}
+{{- else -}}
+{{- .Values.controller.reconcilers.promotions.workDir -}}
+{{- end -}}
+{{- end -}}
+
 {{- define "kargo.api.baseURL" -}}
 {{- if or .Values.api.tls.enabled (and .Values.api.ingress.enabled .Values.api.ingress.tls.enabled) .Values.api.tls.terminatedUpstream -}}
 {{- printf "https://%s" .Values.api.host -}}
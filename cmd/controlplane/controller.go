@@ -7,12 +7,16 @@ import (
 	"sync"
 
 	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -21,6 +25,12 @@ import (
 	libargocd "github.com/akuity/kargo/internal/argocd"
 	"github.com/akuity/kargo/internal/controller"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/branches"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/controller/health"
+	"github.com/akuity/kargo/internal/controller/imageupdater"
+	"github.com/akuity/kargo/internal/controller/kargoconfig"
+	"github.com/akuity/kargo/internal/controller/promotetrigger"
 	"github.com/akuity/kargo/internal/controller/promotions"
 	rollouts "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/stages"
@@ -28,7 +38,9 @@ import (
 	"github.com/akuity/kargo/internal/credentials"
 	credsdb "github.com/akuity/kargo/internal/credentials/kubernetes"
 	"github.com/akuity/kargo/internal/directives"
+	libExec "github.com/akuity/kargo/internal/exec"
 	"github.com/akuity/kargo/internal/indexer"
+	"github.com/akuity/kargo/internal/kubeclient"
 	"github.com/akuity/kargo/internal/logging"
 	"github.com/akuity/kargo/internal/os"
 	"github.com/akuity/kargo/internal/types"
@@ -43,7 +55,9 @@ type controllerOptions struct {
 	ArgoCDKubeConfig    string
 	ArgoCDNamespaceOnly bool
 
-	PprofBindAddress string
+	PprofBindAddress       string
+	HealthProbeBindAddress string
+	MetricsBindAddress     string
 
 	Logger *logging.Logger
 }
@@ -77,6 +91,8 @@ func (o *controllerOptions) complete() {
 	o.ArgoCDKubeConfig = os.GetEnv("ARGOCD_KUBECONFIG", "")
 	o.ArgoCDNamespaceOnly = types.MustParseBool(os.GetEnv("ARGOCD_WATCH_ARGOCD_NAMESPACE_ONLY", "false"))
 	o.PprofBindAddress = os.GetEnv("PPROF_BIND_ADDRESS", "")
+	o.HealthProbeBindAddress = os.GetEnv("HEALTH_PROBE_BIND_ADDRESS", ":8081")
+	o.MetricsBindAddress = os.GetEnv("METRICS_BIND_ADDRESS", "0")
 }
 
 func (o *controllerOptions) run(ctx context.Context) error {
@@ -93,6 +109,15 @@ func (o *controllerOptions) run(ctx context.Context) error {
 	}
 	startupLogger.Info("Starting Kargo Controller")
 
+	binaryVersionsErr := health.CheckBinaryVersions(ctx, startupLogger)
+	if binaryVersionsErr != nil {
+		startupLogger.Error(
+			binaryVersionsErr,
+			"unsupported external binaries; the controller will not become ready "+
+				"unless READINESS_CHECK_BINARY_VERSIONS_ENABLED is false",
+		)
+	}
+
 	kargoMgr, stagesReconcilerCfg, err := o.setupKargoManager(
 		ctx,
 		stages.ReconcilerConfigFromEnv(),
@@ -101,11 +126,37 @@ func (o *controllerOptions) run(ctx context.Context) error {
 		return fmt.Errorf("error initializing Kargo controller manager: %w", err)
 	}
 
+	libExec.SetOperationsConfig(libExec.OperationsConfigFromEnv())
+
+	// Settings from the KargoConfig resource take precedence over those from
+	// the environment.
+	kargoConfig := kargoconfig.NewWatcher()
+	kargoConfigSpec, err := kargoConfig.Load(ctx, kargoMgr.GetAPIReader())
+	if err != nil {
+		return fmt.Errorf("error loading KargoConfig: %w", err)
+	}
+
+	git.SetHostLimits(
+		kargoconfig.HostLimiterConfig(kargoConfigSpec, git.HostLimiterConfigFromEnv()),
+	)
+	if err = git.SetNetworkRetries(
+		kargoconfig.NetworkRetryConfig(kargoConfigSpec, git.NetworkRetryConfigFromEnv()),
+	); err != nil {
+		return fmt.Errorf("error configuring Git network retries: %w", err)
+	}
+
 	argocdMgr, err := o.setupArgoCDManager(ctx)
 	if err != nil {
 		return fmt.Errorf("error initializing Argo CD Application controller manager: %w", err)
 	}
 
+	saClientFactory := newServiceAccountClientFactory(kargoMgr)
+
+	argoCDContexts, argoCDContextMgrs, err := o.setupArgoCDContexts(ctx, argocdMgr, saClientFactory)
+	if err != nil {
+		return fmt.Errorf("error initializing Argo CD contexts: %w", err)
+	}
+
 	credentialsDB := credsdb.NewDatabase(
 		ctx,
 		kargoMgr.GetClient(),
@@ -115,14 +166,20 @@ func (o *controllerOptions) run(ctx context.Context) error {
 	if err := o.setupReconcilers(
 		ctx,
 		kargoMgr,
-		argocdMgr,
+		saClientFactory,
+		argoCDContexts,
 		credentialsDB,
+		kargoConfig,
 		stagesReconcilerCfg,
 	); err != nil {
 		return fmt.Errorf("error setting up reconcilers: %w", err)
 	}
 
-	return o.startManagers(ctx, kargoMgr, argocdMgr)
+	if err := o.setupHealthChecks(kargoMgr, argocdMgr, binaryVersionsErr); err != nil {
+		return fmt.Errorf("error setting up health checks: %w", err)
+	}
+
+	return o.startManagers(ctx, kargoMgr, argocdMgr, argoCDContexts, argoCDContextMgrs)
 }
 
 func (o *controllerOptions) setupKargoManager(
@@ -151,12 +208,28 @@ func (o *controllerOptions) setupKargoManager(
 			err,
 		)
 	}
+	// Jobs are created by the run-job promotion step.
+	if err = batchv1.AddToScheme(scheme); err != nil {
+		return nil, stagesReconcilerCfg, fmt.Errorf(
+			"error adding Kubernetes batch API to Kargo controller manager scheme: %w",
+			err,
+		)
+	}
 	if err = kargoapi.AddToScheme(scheme); err != nil {
 		return nil, stagesReconcilerCfg, fmt.Errorf(
 			"error adding Kargo API to Kargo controller manager scheme: %w",
 			err,
 		)
 	}
+	// Clients impersonating ServiceAccounts are built using this scheme and
+	// are shared with the default Argo CD context when Argo CD runs in the same
+	// cluster as Kargo. No Argo CD resources are watched by this manager.
+	if err = argocd.AddToScheme(scheme); err != nil {
+		return nil, stagesReconcilerCfg, fmt.Errorf(
+			"error adding Argo CD API to Kargo controller manager scheme: %w",
+			err,
+		)
+	}
 	if stagesReconcilerCfg.RolloutsIntegrationEnabled {
 		if argoRolloutsExists(ctx, restCfg) {
 			o.Logger.Info("Argo Rollouts integration is enabled")
@@ -187,9 +260,15 @@ func (o *controllerOptions) setupKargoManager(
 		ctrl.Options{
 			Scheme: scheme,
 			Metrics: server.Options{
-				BindAddress: "0",
+				BindAddress: o.MetricsBindAddress,
 			},
-			PprofBindAddress: o.PprofBindAddress,
+			PprofBindAddress:       o.PprofBindAddress,
+			HealthProbeBindAddress: o.HealthProbeBindAddress,
+			// Allow Promotions that are in progress to reach a point from which
+			// they can be resumed before the manager exits.
+			GracefulShutdownTimeout: ptr.To(
+				promotions.ReconcilerConfigFromEnv().ShutdownTimeout(),
+			),
 			Client: client.Options{
 				Cache: &client.CacheOptions{
 					// The controller does not have cluster-wide permissions, to
@@ -197,7 +276,16 @@ func (o *controllerOptions) setupKargoManager(
 					// dynamically as Projects are created and deleted. We disable caching
 					// here since the underlying informer will not be able to watch
 					// Secrets in all namespaces.
-					DisableFor: []client.Object{&corev1.Secret{}},
+					// ConfigMaps are only read by name, to journal Promotion
+					// requests, and listed by label, to count the ConfigMaps
+					// owned by a Promotion, for which the controller is not
+					// permitted to watch them. The same goes for Jobs, which
+					// are only listed to count those owned by a Promotion.
+					DisableFor: []client.Object{
+						&corev1.Secret{},
+						&corev1.ConfigMap{},
+						&batchv1.Job{},
+					},
 				},
 			},
 			Cache: cache.Options{
@@ -251,19 +339,103 @@ func (o *controllerOptions) setupArgoCDManager(ctx context.Context) (manager.Man
 
 	o.Logger.Info("Argo CD integration is enabled")
 
+	return o.newArgoCDManager(restCfg, argocdNamespace)
+}
+
+// setupArgoCDContexts returns the Argo CD contexts through which Stages interact
+// with Argo CD, along with a manager for each of the named contexts, keyed by
+// name. The default context is backed by the provided manager. If Argo CD runs
+// in the same cluster as Kargo, the default context impersonates
+// ServiceAccounts using the provided factory. A named context that cannot be
+// connected to is recorded as unavailable, which affects only the Stages that
+// use it.
+func (o *controllerOptions) setupArgoCDContexts(
+	ctx context.Context,
+	argocdMgr manager.Manager,
+	saClientFactory *kubeclient.ImpersonatingClientFactory,
+) (*libargocd.Contexts, map[string]manager.Manager, error) {
+	argoCDContexts := libargocd.NewContexts()
+	if argocdMgr != nil {
+		if o.ArgoCDKubeConfig != o.KubeConfig {
+			saClientFactory = newServiceAccountClientFactory(argocdMgr)
+		}
+		argoCDContexts.Register(
+			newArgoCDContext("", libargocd.Namespace(), argocdMgr, saClientFactory),
+		)
+	}
+	if !o.ArgoCDEnabled {
+		return argoCDContexts, nil, nil
+	}
+
+	cfgs, err := libargocd.ContextConfigsFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	mgrs := make(map[string]manager.Manager, len(cfgs))
+	for _, cfg := range cfgs {
+		logger := o.Logger.WithValues("argoCDContext", cfg.Name)
+		mgr, err := o.setupArgoCDContextManager(ctx, cfg)
+		if err != nil {
+			logger.Error(err, "Argo CD context is unavailable")
+			argoCDContexts.SetUnavailable(cfg.Name, err)
+			continue
+		}
+		argoCDContexts.Register(
+			newArgoCDContext(cfg.Name, cfg.Namespace, mgr, newServiceAccountClientFactory(mgr)),
+		)
+		mgrs[cfg.Name] = mgr
+		logger.Info("Argo CD context is enabled", "namespace", cfg.Namespace)
+	}
+	return argoCDContexts, mgrs, nil
+}
+
+// setupArgoCDContextManager returns a manager for Argo CD resources in the Argo
+// CD control plane described by the provided ContextConfig.
+func (o *controllerOptions) setupArgoCDContextManager(
+	ctx context.Context,
+	cfg libargocd.ContextConfig,
+) (manager.Manager, error) {
+	restCfg, err := kubernetes.GetRestConfig(ctx, cfg.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading REST config: %w", err)
+	}
+	restCfg.ContentType = runtime.ContentTypeJSON
+	// Unlike for the default context, not finding the Argo CD CRDs is an error,
+	// since the context was explicitly configured.
+	if !argoCDExists(ctx, restCfg, cfg.Namespace) {
+		return nil, fmt.Errorf(
+			"unable to list Argo CD Applications in namespace %q", cfg.Namespace,
+		)
+	}
+	return o.newArgoCDManager(restCfg, cfg.Namespace)
+}
+
+// newArgoCDManager returns a manager for Argo CD resources using the provided
+// REST config. If the controller is configured to watch Argo CD's own
+// namespace only, the provided namespace is the one that is watched.
+func (o *controllerOptions) newArgoCDManager(
+	restCfg *rest.Config,
+	argocdNamespace string,
+) (manager.Manager, error) {
 	scheme := runtime.NewScheme()
-	if err = corev1.AddToScheme(scheme); err != nil {
+	if err := corev1.AddToScheme(scheme); err != nil {
 		return nil, fmt.Errorf(
 			"error adding Kubernetes core API to Argo CD controller manager scheme: %w",
 			err,
 		)
 	}
-	if err = argocd.AddToScheme(scheme); err != nil {
+	if err := argocd.AddToScheme(scheme); err != nil {
 		return nil, fmt.Errorf(
 			"error adding Argo CD API to Argo CD controller manager scheme: %w",
 			err,
 		)
 	}
+	if err := rollouts.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf(
+			"error adding Argo Rollouts API to Argo CD controller manager scheme: %w",
+			err,
+		)
+	}
 	cacheOpts := cache.Options{} // Watches all namespaces by default
 	if o.ArgoCDNamespaceOnly {
 		cacheOpts.DefaultNamespaces = map[string]cache.Config{
@@ -278,30 +450,86 @@ func (o *controllerOptions) setupArgoCDManager(ctx context.Context) (manager.Man
 			Metrics: server.Options{
 				BindAddress: "0",
 			},
+			Client: client.Options{
+				Cache: &client.CacheOptions{
+					// The Secrets holding the credentials of Argo CD's clusters
+					// are only read by the argocd-dry-run promotion step, and
+					// only if permitted, so they are not watched. The same
+					// goes for the AnalysisRuns of Rollouts managed by an
+					// Application, which are only looked up when assessing
+					// the health of a Stage.
+					DisableFor: []client.Object{
+						&corev1.Secret{},
+						&rollouts.AnalysisRun{},
+					},
+				},
+			},
 			Cache: cacheOpts,
 		},
 	)
 }
 
+// newArgoCDContext returns an Argo CD context with the provided name and
+// namespace that is backed by the provided manager and that impersonates
+// ServiceAccounts using the provided factory.
+func newArgoCDContext(
+	name string,
+	namespace string,
+	mgr manager.Manager,
+	saClientFactory *kubeclient.ImpersonatingClientFactory,
+) *libargocd.Context {
+	return &libargocd.Context{
+		Name:                    name,
+		Namespace:               namespace,
+		Client:                  mgr.GetClient(),
+		ClientForServiceAccount: saClientFactory.ForServiceAccount,
+		Cache:                   mgr.GetCache(),
+	}
+}
+
+// newServiceAccountClientFactory returns a factory for clients that
+// impersonate ServiceAccounts in the cluster the provided manager connects to.
+// Since the factory caches a client per ServiceAccount, a single factory should
+// be used per cluster.
+func newServiceAccountClientFactory(mgr manager.Manager) *kubeclient.ImpersonatingClientFactory {
+	return kubeclient.NewImpersonatingClientFactory(
+		mgr.GetConfig(),
+		client.Options{
+			Scheme: mgr.GetScheme(),
+			Mapper: mgr.GetRESTMapper(),
+		},
+	)
+}
+
 func (o *controllerOptions) setupReconcilers(
 	ctx context.Context,
-	kargoMgr, argocdMgr manager.Manager,
+	kargoMgr manager.Manager,
+	saClientFactory *kubeclient.ImpersonatingClientFactory,
+	argoCDContexts *libargocd.Contexts,
 	credentialsDB credentials.Database,
+	kargoConfig *kargoconfig.Watcher,
 	stagesReconcilerCfg stages.ReconcilerConfig,
 ) error {
-	var argoCDClient client.Client
-	if argocdMgr != nil {
-		argoCDClient = argocdMgr.GetClient()
-	}
 	sharedIndexer := indexer.NewSharedFieldIndexer(kargoMgr.GetFieldIndexer())
 
-	directivesEngine := directives.NewSimpleEngine(credentialsDB, kargoMgr.GetClient(), argoCDClient)
+	directivesEngine := directives.NewSimpleEngine(
+		credentialsDB,
+		kargoMgr.GetClient(),
+		saClientFactory.ForServiceAccount,
+		argoCDContexts,
+	)
+
+	if err := kargoConfig.SetupWithManager(ctx, kargoMgr); err != nil {
+		return fmt.Errorf("error setting up KargoConfig watcher: %w", err)
+	}
 
 	if err := promotions.SetupReconcilerWithManager(
 		ctx,
 		kargoMgr,
-		argocdMgr,
+		argoCDContexts,
 		directivesEngine,
+		credentialsDB,
+		kargoConfig,
 		promotions.ReconcilerConfigFromEnv(),
 	); err != nil {
 		return fmt.Errorf("error setting up Promotions reconciler: %w", err)
@@ -310,7 +538,7 @@ func (o *controllerOptions) setupReconcilers(
 	if err := stages.NewRegularStageReconciler(stagesReconcilerCfg, directivesEngine).SetupWithManager(
 		ctx,
 		kargoMgr,
-		argocdMgr,
+		argoCDContexts,
 		sharedIndexer,
 	); err != nil {
 		return fmt.Errorf("error setting up regular Stages reconciler: %w", err)
@@ -333,15 +561,83 @@ func (o *controllerOptions) setupReconcilers(
 		return fmt.Errorf("error setting up Warehouses reconciler: %w", err)
 	}
 
+	if err := imageupdater.SetupReconcilerWithManager(
+		ctx,
+		kargoMgr,
+		argoCDContexts,
+		imageupdater.ReconcilerConfigFromEnv(),
+	); err != nil {
+		return fmt.Errorf("error setting up Argo CD Image Updater compatibility reconciler: %w", err)
+	}
+
+	if err := promotetrigger.SetupReconcilerWithManager(
+		ctx,
+		kargoMgr,
+		argoCDContexts,
+		promotetrigger.ReconcilerConfigFromEnv(),
+	); err != nil {
+		return fmt.Errorf("error setting up Argo CD promote annotation reconciler: %w", err)
+	}
+
+	if err := branches.SetupSweeperWithManager(
+		ctx,
+		kargoMgr,
+		credentialsDB,
+		branches.SweeperConfigFromEnv(),
+	); err != nil {
+		return fmt.Errorf("error setting up promotion branch sweeper: %w", err)
+	}
+
 	return nil
 }
 
-func (o *controllerOptions) startManagers(ctx context.Context, kargoMgr, argocdMgr manager.Manager) error {
+func (o *controllerOptions) setupHealthChecks(
+	kargoMgr, argocdMgr manager.Manager,
+	binaryVersionsErr error,
+) error {
+	if err := kargoMgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return fmt.Errorf("error adding liveness check: %w", err)
+	}
+	var argocdReader client.Reader
+	if argocdMgr != nil {
+		argocdReader = argocdMgr.GetAPIReader()
+	}
+	return health.AddReadinessChecks(
+		kargoMgr,
+		argocdReader,
+		binaryVersionsErr,
+		health.ReadinessConfigFromEnv(),
+	)
+}
+
+func (o *controllerOptions) startManagers(
+	ctx context.Context,
+	kargoMgr, argocdMgr manager.Manager,
+	argoCDContexts *libargocd.Contexts,
+	argoCDContextMgrs map[string]manager.Manager,
+) error {
 	var (
 		errChan = make(chan error)
 		wg      = sync.WaitGroup{}
 	)
 
+	// A failure of the manager for a named Argo CD context must not bring down
+	// the whole controller. The context is instead recorded as unavailable, so
+	// only the Stages that use it are affected.
+	for name, mgr := range argoCDContextMgrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := mgr.Start(ctx); err != nil {
+				o.Logger.Error(
+					err, "Argo CD context manager stopped unexpectedly",
+					"argoCDContext", name,
+				)
+				argoCDContexts.SetUnavailable(name, err)
+			}
+		}()
+	}
+
 	if argocdMgr != nil {
 		wg.Add(1)
 		go func() {
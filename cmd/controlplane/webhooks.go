@@ -22,6 +22,7 @@ import (
 	versionpkg "github.com/akuity/kargo/internal/version"
 	libWebhook "github.com/akuity/kargo/internal/webhook"
 	"github.com/akuity/kargo/internal/webhook/freight"
+	"github.com/akuity/kargo/internal/webhook/kargoconfig"
 	"github.com/akuity/kargo/internal/webhook/project"
 	"github.com/akuity/kargo/internal/webhook/promotion"
 	"github.com/akuity/kargo/internal/webhook/promotiontask"
@@ -127,6 +128,9 @@ func (o *webhooksServerOptions) run(ctx context.Context) error {
 	if err = freight.SetupWebhookWithManager(ctx, webhookCfg, mgr); err != nil {
 		return fmt.Errorf("setup Freight webhook: %w", err)
 	}
+	if err = kargoconfig.SetupWebhookWithManager(mgr); err != nil {
+		return fmt.Errorf("setup KargoConfig webhook: %w", err)
+	}
 	if err = project.SetupWebhookWithManager(
 		mgr,
 		project.WebhookConfigFromEnv(),
@@ -10,7 +10,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
-	"github.com/akuity/kargo/internal/kargo"
 	svcv1alpha1 "github.com/akuity/kargo/pkg/api/service/v1alpha1"
 )
 
@@ -138,15 +137,15 @@ func (s *server) PromoteDownstream(
 			// steps and is therefore a "control flow" Stage.
 			continue
 		}
-		newPromo, err := kargo.NewPromotionBuilder(s.client).Build(ctx, downstream, freight.Name)
+		newPromo, err := s.createPromotionFn(
+			ctx,
+			&downstream,
+			newAPIPromotionRequest(ctx, &downstream, freight),
+		)
 		if err != nil {
 			promoteErrs = append(promoteErrs, err)
 			continue
 		}
-		if err = s.createPromotionFn(ctx, newPromo); err != nil {
-			promoteErrs = append(promoteErrs, err)
-			continue
-		}
 		s.recordPromotionCreatedEvent(ctx, newPromo, freight)
 		createdPromos = append(createdPromos, newPromo)
 	}
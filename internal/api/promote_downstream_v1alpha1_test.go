@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/events"
 	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
 	svcv1alpha1 "github.com/akuity/kargo/pkg/api/service/v1alpha1"
 )
@@ -534,10 +535,10 @@ func TestPromoteDownstream(t *testing.T) {
 				},
 				createPromotionFn: func(
 					context.Context,
-					client.Object,
-					...client.CreateOption,
-				) error {
-					return errors.New("something went wrong")
+					*kargoapi.Stage,
+					*events.PromotionRequested,
+				) (*kargoapi.Promotion, error) {
+					return nil, errors.New("something went wrong")
 				},
 			},
 			assertions: func(
@@ -630,11 +631,23 @@ func TestPromoteDownstream(t *testing.T) {
 					return nil
 				},
 				createPromotionFn: func(
-					context.Context,
-					client.Object,
-					...client.CreateOption,
-				) error {
-					return nil
+					_ context.Context,
+					stage *kargoapi.Stage,
+					req *events.PromotionRequested,
+				) (*kargoapi.Promotion, error) {
+					require.Equal(t, events.SourceAPI, req.Source)
+					require.Equal(t, stage.Name, req.Stage)
+					require.Equal(t, "fake-freight", req.Freight)
+					return &kargoapi.Promotion{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: stage.Namespace,
+							Name:      "fake-promotion",
+						},
+						Spec: kargoapi.PromotionSpec{
+							Stage:   stage.Name,
+							Freight: req.Freight,
+						},
+					}, nil
 				},
 			},
 			assertions: func(
@@ -13,7 +13,7 @@ import (
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/api/user"
 	"github.com/akuity/kargo/internal/event"
-	"github.com/akuity/kargo/internal/kargo"
+	"github.com/akuity/kargo/internal/events"
 	svcv1alpha1 "github.com/akuity/kargo/pkg/api/service/v1alpha1"
 )
 
@@ -42,6 +42,22 @@ func (s *server) PromoteToStage(
 		)
 	}
 
+	var originCommit *kargoapi.OriginCommit
+	originRepoURL := req.Msg.GetOriginRepoUrl()
+	originCommitID := req.Msg.GetOriginCommitId()
+	if (originRepoURL == "") != (originCommitID == "") {
+		return nil, connect.NewError(
+			connect.CodeInvalidArgument,
+			errors.New("originRepoURL and originCommitID must be specified together"),
+		)
+	}
+	if originRepoURL != "" {
+		originCommit = &kargoapi.OriginCommit{
+			RepoURL: originRepoURL,
+			ID:      originCommitID,
+		}
+	}
+
 	if err := s.validateProjectExistsFn(ctx, project); err != nil {
 		return nil, err
 	}
@@ -115,11 +131,10 @@ func (s *server) PromoteToStage(
 		return nil, err
 	}
 
-	promotion, err := kargo.NewPromotionBuilder(s.client).Build(ctx, *stage, freight.Name)
+	promoReq := newAPIPromotionRequest(ctx, stage, freight)
+	promoReq.OriginCommit = originCommit
+	promotion, err := s.createPromotionFn(ctx, stage, promoReq)
 	if err != nil {
-		return nil, fmt.Errorf("build promotion: %w", err)
-	}
-	if err := s.createPromotionFn(ctx, promotion); err != nil {
 		return nil, fmt.Errorf("create promotion: %w", err)
 	}
 	s.recordPromotionCreatedEvent(ctx, promotion, freight)
@@ -135,6 +150,25 @@ func (s *server) isFreightAvailable(
 	return stage.IsFreightAvailable(freight)
 }
 
+// newAPIPromotionRequest returns a request, on behalf of the user bound to the
+// provided context, to promote the provided Freight to the provided Stage.
+func newAPIPromotionRequest(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	freight *kargoapi.Freight,
+) *events.PromotionRequested {
+	req := &events.PromotionRequested{
+		Source:  events.SourceAPI,
+		Project: stage.Namespace,
+		Stage:   stage.Name,
+		Freight: freight.Name,
+	}
+	if u, ok := user.InfoFromContext(ctx); ok {
+		req.Actor = kargoapi.FormatEventUserActor(u)
+	}
+	return req
+}
+
 func (s *server) recordPromotionCreatedEvent(
 	ctx context.Context,
 	p *kargoapi.Promotion,
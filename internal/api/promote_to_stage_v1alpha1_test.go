@@ -9,11 +9,14 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/events"
 	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
 	svcv1alpha1 "github.com/akuity/kargo/pkg/api/service/v1alpha1"
 )
@@ -35,6 +38,16 @@ func TestPromoteToStage(t *testing.T) {
 			},
 		},
 	}
+
+	// As in the API server, Promotions are created with the client that
+	// enforces the user's permissions, while requests are journaled with the
+	// internal client.
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	internalClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	userClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
 	testCases := []struct {
 		name       string
 		req        *svcv1alpha1.PromoteToStageRequest
@@ -62,6 +75,28 @@ func TestPromoteToStage(t *testing.T) {
 				require.Equal(t, connect.CodeInvalidArgument, connErr.Code())
 			},
 		},
+		{
+			name: "origin commit without repo URL",
+			req: &svcv1alpha1.PromoteToStageRequest{
+				Project:        "fake-project",
+				Stage:          "fake-stage",
+				Freight:        "fake-freight",
+				OriginCommitId: "abc123",
+			},
+			server: &server{},
+			assertions: func(
+				t *testing.T,
+				_ *fakeevent.EventRecorder,
+				_ *connect.Response[svcv1alpha1.PromoteToStageResponse],
+				err error,
+			) {
+				require.Error(t, err)
+				var connErr *connect.Error
+				require.True(t, errors.As(err, &connErr))
+				require.Equal(t, connect.CodeInvalidArgument, connErr.Code())
+				require.ErrorContains(t, err, "must be specified together")
+			},
+		},
 		{
 			name: "error validating project",
 			req: &svcv1alpha1.PromoteToStageRequest{
@@ -320,7 +355,7 @@ func TestPromoteToStage(t *testing.T) {
 			},
 		},
 		{
-			name: "error building Promotion",
+			name: "invalid Promotion request",
 			req: &svcv1alpha1.PromoteToStageRequest{
 				Project: "fake-project",
 				Stage:   "fake-stage",
@@ -362,6 +397,10 @@ func TestPromoteToStage(t *testing.T) {
 				) error {
 					return nil
 				},
+				createPromotionFn: events.NewPromotionCreator(
+					fake.NewClientBuilder().Build(),
+					events.PromotionCreatorOptions{},
+				).Create,
 			},
 			assertions: func(
 				t *testing.T,
@@ -369,7 +408,8 @@ func TestPromoteToStage(t *testing.T) {
 				_ *connect.Response[svcv1alpha1.PromoteToStageResponse],
 				err error,
 			) {
-				require.ErrorContains(t, err, "build promotion")
+				require.ErrorIs(t, err, events.ErrInvalidRequest)
+				require.ErrorContains(t, err, "freight is required")
 			},
 		},
 		{
@@ -422,10 +462,10 @@ func TestPromoteToStage(t *testing.T) {
 				},
 				createPromotionFn: func(
 					context.Context,
-					client.Object,
-					...client.CreateOption,
-				) error {
-					return errors.New("something went wrong")
+					*kargoapi.Stage,
+					*events.PromotionRequested,
+				) (*kargoapi.Promotion, error) {
+					return nil, errors.New("something went wrong")
 				},
 			},
 			assertions: func(
@@ -438,7 +478,7 @@ func TestPromoteToStage(t *testing.T) {
 			},
 		},
 		{
-			name: "success",
+			name: "Promotion is created and request is journaled",
 			req: &svcv1alpha1.PromoteToStageRequest{
 				Project: "fake-project",
 				Stage:   "fake-stage",
@@ -485,13 +525,115 @@ func TestPromoteToStage(t *testing.T) {
 				) error {
 					return nil
 				},
-				createPromotionFn: func(
+				createPromotionFn: events.NewPromotionCreator(
+					internalClient,
+					events.PromotionCreatorOptions{
+						CreatePromotionFn: userClient.Create,
+						Journal:           events.NewJournal(internalClient, events.DefaultJournalSize),
+					},
+				).Create,
+			},
+			assertions: func(
+				t *testing.T,
+				_ *fakeevent.EventRecorder,
+				res *connect.Response[svcv1alpha1.PromoteToStageResponse],
+				err error,
+			) {
+				require.NoError(t, err)
+				promo := res.Msg.GetPromotion()
+				require.NotNil(t, promo)
+				require.NoError(t, userClient.Get(
+					context.Background(),
+					client.ObjectKeyFromObject(promo),
+					&kargoapi.Promotion{},
+				))
+				reqs, err := events.NewJournal(internalClient, 0).List(
+					context.Background(),
+					"fake-project",
+				)
+				require.NoError(t, err)
+				require.Len(t, reqs, 1)
+				require.Equal(t, events.SourceAPI, reqs[0].Source)
+				require.Equal(t, promo.Annotations[kargoapi.AnnotationKeyPromotionRequest], reqs[0].ID)
+			},
+		},
+		{
+			name: "success",
+			req: &svcv1alpha1.PromoteToStageRequest{
+				Project:        "fake-project",
+				Stage:          "fake-stage",
+				Freight:        "fake-freight",
+				OriginRepoUrl:  "https://github.com/example/app",
+				OriginCommitId: "abc123",
+			},
+			server: &server{
+				validateProjectExistsFn: func(context.Context, string) error {
+					return nil
+				},
+				getStageFn: func(
 					context.Context,
-					client.Object,
-					...client.CreateOption,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "fake-project",
+							Name:      "fake-stage",
+						},
+						Spec: testStageSpec,
+					}, nil
+				},
+				getFreightByNameOrAliasFn: func(
+					context.Context,
+					client.Client,
+					string, string, string,
+				) (*kargoapi.Freight, error) {
+					return &kargoapi.Freight{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "fake-project",
+							Name:      "fake-freight",
+						},
+					}, nil
+				},
+				isFreightAvailableFn: func(*kargoapi.Stage, *kargoapi.Freight) bool {
+					return true
+				},
+				authorizeFn: func(
+					context.Context,
+					string,
+					schema.GroupVersionResource,
+					string,
+					client.ObjectKey,
 				) error {
 					return nil
 				},
+				createPromotionFn: func(
+					_ context.Context,
+					stage *kargoapi.Stage,
+					req *events.PromotionRequested,
+				) (*kargoapi.Promotion, error) {
+					require.Equal(t, events.SourceAPI, req.Source)
+					require.Equal(t, stage.Name, req.Stage)
+					require.Equal(t, "fake-freight", req.Freight)
+					require.Equal(
+						t,
+						&kargoapi.OriginCommit{
+							RepoURL: "https://github.com/example/app",
+							ID:      "abc123",
+						},
+						req.OriginCommit,
+					)
+					return &kargoapi.Promotion{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: stage.Namespace,
+							Name:      "fake-promotion",
+						},
+						Spec: kargoapi.PromotionSpec{
+							Stage:   stage.Name,
+							Freight: req.Freight,
+						},
+					}, nil
+				},
 			},
 			assertions: func(
 				t *testing.T,
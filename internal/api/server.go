@@ -31,6 +31,7 @@ import (
 	"github.com/akuity/kargo/internal/api/rbac"
 	"github.com/akuity/kargo/internal/api/validation"
 	rollouts "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
+	"github.com/akuity/kargo/internal/events"
 	httputil "github.com/akuity/kargo/internal/http"
 	"github.com/akuity/kargo/internal/logging"
 	"github.com/akuity/kargo/pkg/api/service/v1alpha1/svcv1alpha1connect"
@@ -81,9 +82,9 @@ type server struct {
 	// Common Promotions:
 	createPromotionFn func(
 		context.Context,
-		client.Object,
-		...client.CreateOption,
-	) error
+		*kargoapi.Stage,
+		*events.PromotionRequested,
+	) (*kargoapi.Promotion, error)
 
 	// Promote downstream:
 	findDownstreamStagesFn func(
@@ -172,7 +173,16 @@ func NewServer(
 	s.getStageFn = kargoapi.GetStage
 	s.getFreightByNameOrAliasFn = kargoapi.GetFreightByNameOrAlias
 	s.isFreightAvailableFn = s.isFreightAvailable
-	s.createPromotionFn = kubeClient.Create
+	// Promotions are created with the client that enforces the user's
+	// permissions. Existing Promotions are looked up and requests are journaled
+	// with the internal client.
+	s.createPromotionFn = events.NewPromotionCreator(
+		kubeClient.InternalClient(),
+		events.PromotionCreatorOptions{
+			CreatePromotionFn: kubeClient.Create,
+			Journal:           events.NewJournal(kubeClient.InternalClient(), events.DefaultJournalSize),
+		},
+	).Create
 	s.findDownstreamStagesFn = s.findDownstreamStages
 	s.listFreightFn = kubeClient.List
 	s.getAvailableFreightForStageFn = s.getAvailableFreightForStage
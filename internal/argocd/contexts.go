@@ -0,0 +1,161 @@
+package argocd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/akuity/kargo/internal/os"
+)
+
+// ContextConfig describes how to connect to an Argo CD control plane other
+// than the default one.
+type ContextConfig struct {
+	// Name is the name by which Stages refer to the context.
+	Name string `json:"name"`
+	// Namespace is the namespace into which Argo CD is installed. It defaults
+	// to the namespace of the default context.
+	Namespace string `json:"namespace,omitempty"`
+	// KubeConfig is the path to a kubeconfig file for the cluster in which the
+	// Argo CD control plane resides. When empty, the cluster the controller is
+	// running in is used.
+	KubeConfig string `json:"kubeconfig,omitempty"`
+}
+
+// ContextConfigsFromEnv returns the configuration of all Argo CD contexts
+// other than the default one. These are read from the ARGOCD_CONTEXTS
+// environment variable, which is expected to contain a JSON array.
+func ContextConfigsFromEnv() ([]ContextConfig, error) {
+	return parseContextConfigs(os.GetEnv("ARGOCD_CONTEXTS", ""))
+}
+
+func parseContextConfigs(value string) ([]ContextConfig, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var cfgs []ContextConfig
+	if err := json.Unmarshal([]byte(value), &cfgs); err != nil {
+		return nil, fmt.Errorf("error parsing Argo CD contexts: %w", err)
+	}
+	names := make(map[string]struct{}, len(cfgs))
+	for i := range cfgs {
+		cfg := &cfgs[i]
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("Argo CD context at index %d has no name", i)
+		}
+		if _, ok := names[cfg.Name]; ok {
+			return nil, fmt.Errorf("Argo CD context %q is defined more than once", cfg.Name)
+		}
+		names[cfg.Name] = struct{}{}
+		if cfg.Namespace == "" {
+			cfg.Namespace = Namespace()
+		}
+	}
+	return cfgs, nil
+}
+
+// Context is a connection to an Argo CD control plane.
+type Context struct {
+	// Name is the name of the context. It is empty for the default context.
+	Name string
+	// Namespace is the namespace into which Argo CD is installed.
+	Namespace string
+	// Client is a client for Argo CD resources.
+	Client client.Client
+	// ClientForServiceAccount, if non-nil, returns a client for Argo CD
+	// resources that impersonates the ServiceAccount with the provided
+	// namespace and name.
+	ClientForServiceAccount func(namespace, name string) (client.Client, error)
+	// Cache, if non-nil, is an informer cache through which Argo CD resources
+	// may be watched.
+	Cache cache.Cache
+}
+
+// Contexts is a registry of Argo CD contexts, keyed by name. A context that
+// could not be connected to is recorded as unavailable, which causes lookups
+// of that context (and only that context) to fail.
+type Contexts struct {
+	mu          sync.RWMutex
+	contexts    map[string]*Context
+	unavailable map[string]error
+}
+
+// NewContexts returns a new registry containing the provided Argo CD contexts.
+func NewContexts(contexts ...*Context) *Contexts {
+	c := &Contexts{
+		contexts:    make(map[string]*Context, len(contexts)),
+		unavailable: map[string]error{},
+	}
+	for _, argoCDCtx := range contexts {
+		c.Register(argoCDCtx)
+	}
+	return c
+}
+
+// Register adds the provided Argo CD context to the registry, replacing any
+// existing context with the same name.
+func (c *Contexts) Register(argoCDCtx *Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.contexts[argoCDCtx.Name] = argoCDCtx
+	delete(c.unavailable, argoCDCtx.Name)
+}
+
+// SetUnavailable records that the Argo CD context with the provided name could
+// not be connected to for the provided reason.
+func (c *Contexts) SetUnavailable(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.contexts, name)
+	c.unavailable[name] = err
+}
+
+// List returns all available Argo CD contexts, ordered by name.
+func (c *Contexts) List() []*Context {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	contexts := make([]*Context, 0, len(c.contexts))
+	for _, argoCDCtx := range c.contexts {
+		contexts = append(contexts, argoCDCtx)
+	}
+	slices.SortFunc(contexts, func(lhs, rhs *Context) int {
+		return strings.Compare(lhs.Name, rhs.Name)
+	})
+	return contexts
+}
+
+// Get returns the Argo CD context with the provided name. An empty name refers
+// to the default context. If there is no default context, because Argo CD
+// integration is disabled, nil is returned without an error. An error is
+// returned if a named context does not exist or is unavailable.
+func (c *Contexts) Get(name string) (*Context, error) {
+	if c == nil {
+		if name == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Argo CD context %q is not configured on this controller", name)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if argoCDCtx, ok := c.contexts[name]; ok {
+		return argoCDCtx, nil
+	}
+	if err, ok := c.unavailable[name]; ok {
+		if name == "" {
+			return nil, fmt.Errorf("default Argo CD context is unavailable: %w", err)
+		}
+		return nil, fmt.Errorf("Argo CD context %q is unavailable: %w", name, err)
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("Argo CD context %q is not configured on this controller", name)
+}
@@ -0,0 +1,131 @@
+package argocd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseContextConfigs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		value      string
+		assertions func(*testing.T, []ContextConfig, error)
+	}{
+		{
+			name: "empty",
+			assertions: func(t *testing.T, cfgs []ContextConfig, err error) {
+				require.NoError(t, err)
+				require.Empty(t, cfgs)
+			},
+		},
+		{
+			name:  "invalid JSON",
+			value: "prod",
+			assertions: func(t *testing.T, _ []ContextConfig, err error) {
+				require.ErrorContains(t, err, "error parsing Argo CD contexts")
+			},
+		},
+		{
+			name:  "missing name",
+			value: `[{"namespace":"argocd"}]`,
+			assertions: func(t *testing.T, _ []ContextConfig, err error) {
+				require.ErrorContains(t, err, "Argo CD context at index 0 has no name")
+			},
+		},
+		{
+			name:  "duplicate name",
+			value: `[{"name":"prod"},{"name":"prod"}]`,
+			assertions: func(t *testing.T, _ []ContextConfig, err error) {
+				require.ErrorContains(t, err, `Argo CD context "prod" is defined more than once`)
+			},
+		},
+		{
+			name: "success",
+			value: `[
+				{"name":"prod","namespace":"argocd-prod","kubeconfig":"/etc/kargo/prod.yaml"},
+				{"name":"staging"}
+			]`,
+			assertions: func(t *testing.T, cfgs []ContextConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					[]ContextConfig{
+						{
+							Name:       "prod",
+							Namespace:  "argocd-prod",
+							KubeConfig: "/etc/kargo/prod.yaml",
+						},
+						{
+							Name:      "staging",
+							Namespace: Namespace(),
+						},
+					},
+					cfgs,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cfgs, err := parseContextConfigs(testCase.value)
+			testCase.assertions(t, cfgs, err)
+		})
+	}
+}
+
+func TestContexts_Get(t *testing.T) {
+	defaultCtx := &Context{Namespace: "argocd"}
+	prodCtx := &Context{Name: "prod", Namespace: "argocd-prod"}
+	contexts := NewContexts(defaultCtx, prodCtx)
+	contexts.SetUnavailable("staging", errors.New("connection refused"))
+
+	argoCDCtx, err := contexts.Get("")
+	require.NoError(t, err)
+	require.Same(t, defaultCtx, argoCDCtx)
+
+	argoCDCtx, err = contexts.Get("prod")
+	require.NoError(t, err)
+	require.Same(t, prodCtx, argoCDCtx)
+
+	_, err = contexts.Get("staging")
+	require.EqualError(t, err, `Argo CD context "staging" is unavailable: connection refused`)
+
+	_, err = contexts.Get("dev")
+	require.EqualError(t, err, `Argo CD context "dev" is not configured on this controller`)
+
+	// A context that becomes unavailable affects only itself
+	contexts.SetUnavailable("prod", errors.New("manager stopped"))
+	_, err = contexts.Get("prod")
+	require.EqualError(t, err, `Argo CD context "prod" is unavailable: manager stopped`)
+	argoCDCtx, err = contexts.Get("")
+	require.NoError(t, err)
+	require.Same(t, defaultCtx, argoCDCtx)
+	require.Equal(t, []*Context{defaultCtx}, contexts.List())
+}
+
+func TestContexts_Get_integrationDisabled(t *testing.T) {
+	for _, contexts := range []*Contexts{nil, NewContexts()} {
+		argoCDCtx, err := contexts.Get("")
+		require.NoError(t, err)
+		require.Nil(t, argoCDCtx)
+
+		_, err = contexts.Get("prod")
+		require.ErrorContains(t, err, "is not configured")
+		require.Empty(t, contexts.List())
+	}
+}
+
+func TestContexts_List(t *testing.T) {
+	contexts := NewContexts(
+		&Context{Name: "staging"},
+		&Context{},
+		&Context{Name: "prod"},
+	)
+	var names []string
+	for _, argoCDCtx := range contexts.List() {
+		names = append(names, argoCDCtx.Name)
+	}
+	require.Equal(t, []string{"", "prod", "staging"}, names)
+}
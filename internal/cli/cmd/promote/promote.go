@@ -29,14 +29,17 @@ type promotionOptions struct {
 	Config        config.CLIConfig
 	ClientOptions client.Options
 
-	Project        string
-	FreightName    string
-	FreightAlias   string
-	Promotion      string
-	Stage          string
-	DownstreamFrom string
-	Abort          bool
-	Wait           bool
+	Project          string
+	FreightName      string
+	FreightAlias     string
+	FreightFromStage string
+	Promotion        string
+	Stage            string
+	DownstreamFrom   string
+	Abort            bool
+	Wait             bool
+	OriginRepoURL    string
+	OriginCommit     string
 }
 
 func NewCommand(cfg config.CLIConfig, streams genericiooptions.IOStreams) *cobra.Command {
@@ -47,7 +50,8 @@ func NewCommand(cfg config.CLIConfig, streams genericiooptions.IOStreams) *cobra
 	}
 
 	cmd := &cobra.Command{
-		Use: "promote [--project=project] (--freight=freight | --freight-alias=alias | --name=name) " +
+		Use: "promote [--project=project] " +
+			"(--freight=freight | --freight-alias=alias | --freight-from-stage=stage | --name=name) " +
 			"[(--stage=stage | --downstream-from=stage) | --abort]",
 		Short: "Promote a piece of freight",
 		Args:  option.NoArgs,
@@ -65,6 +69,14 @@ kargo promote --project=my-project --freight=abc123 --downstream-from=qa
 # Promote a piece of freight specified by alias to stages immediately downstream from the QA stage
 kargo promote --project=my-project --freight-alias=wonky-wombat --downstream-from=qa
 
+# Promote the freight currently in use by the staging stage to the prod stage
+kargo promote --project=my-project --freight-from-stage=staging --stage=prod
+
+# Promote a piece of freight to the QA stage and report the outcome to the
+# application commit that produced it
+kargo promote --project=my-project --freight=abc123 --stage=qa \
+  --origin-repo-url=https://github.com/example/app --origin-commit=1a2b3c4
+
 # Abort a Promotion by name
 kargo promote --project=my-project --name=my-promotion --abort
 
@@ -113,6 +125,10 @@ func (o *promotionOptions) addFlags(cmd *cobra.Command) {
 	)
 	option.Freight(cmd.Flags(), &o.FreightName, "The name of piece of freight to promote.")
 	option.FreightAlias(cmd.Flags(), &o.FreightAlias, "The alias of piece of freight to promote.")
+	option.FreightFromStage(
+		cmd.Flags(), &o.FreightFromStage,
+		"The stage whose current piece of freight should be promoted.",
+	)
 	option.Name(cmd.Flags(), &o.Promotion, "The name of a promotion. Only used when aborting a promotion.")
 	option.Stage(
 		cmd.Flags(), &o.Stage,
@@ -132,14 +148,27 @@ func (o *promotionOptions) addFlags(cmd *cobra.Command) {
 		"Abort a non-terminal promotion. If set, --%s must be set.", option.NameFlag,
 	))
 	option.Wait(cmd.Flags(), &o.Wait, false, "Wait for the promotion(s) to complete.")
+	option.OriginRepoURL(
+		cmd.Flags(), &o.OriginRepoURL,
+		"The URL of the application source repository to report the outcome of the promotion to.",
+	)
+	option.OriginCommit(
+		cmd.Flags(), &o.OriginCommit,
+		"The ID of the commit to the application source repository to report the outcome of the promotion to.",
+	)
 
-	cmd.MarkFlagsOneRequired(option.FreightFlag, option.FreightAliasFlag, option.NameFlag)
-	cmd.MarkFlagsMutuallyExclusive(option.FreightFlag, option.FreightAliasFlag, option.NameFlag)
+	cmd.MarkFlagsOneRequired(
+		option.FreightFlag, option.FreightAliasFlag, option.FreightFromStageFlag, option.NameFlag,
+	)
+	cmd.MarkFlagsMutuallyExclusive(
+		option.FreightFlag, option.FreightAliasFlag, option.FreightFromStageFlag, option.NameFlag,
+	)
 
 	cmd.MarkFlagsOneRequired(option.StageFlag, option.DownstreamFromFlag, option.AbortFlag)
 	cmd.MarkFlagsMutuallyExclusive(option.StageFlag, option.DownstreamFromFlag, option.AbortFlag)
 
 	cmd.MarkFlagsRequiredTogether(option.NameFlag, option.AbortFlag)
+	cmd.MarkFlagsRequiredTogether(option.OriginRepoURLFlag, option.OriginCommitFlag)
 }
 
 // validate performs validation of the options. If the options are invalid, an
@@ -156,10 +185,13 @@ func (o *promotionOptions) validate() error {
 			errs = append(errs, fmt.Errorf("%s is required when aborting a promotion", option.NameFlag))
 		}
 	} else {
-		if o.FreightName == "" && o.FreightAlias == "" {
+		if o.FreightName == "" && o.FreightAlias == "" && o.FreightFromStage == "" {
 			errs = append(
 				errs,
-				fmt.Errorf("either %s or %s is required", option.FreightFlag, option.FreightAliasFlag),
+				fmt.Errorf(
+					"one of %s, %s, or %s is required",
+					option.FreightFlag, option.FreightAliasFlag, option.FreightFromStageFlag,
+				),
 			)
 		}
 		if o.Stage == "" && o.DownstreamFrom == "" {
@@ -184,6 +216,24 @@ func (o *promotionOptions) run(ctx context.Context) error {
 		return fmt.Errorf("new printer: %w", err)
 	}
 
+	if !o.Abort && o.FreightFromStage != "" {
+		res, err := kargoSvcCli.GetStage(
+			ctx,
+			connect.NewRequest(
+				&v1alpha1.GetStageRequest{
+					Project: o.Project,
+					Name:    o.FreightFromStage,
+				},
+			),
+		)
+		if err != nil {
+			return fmt.Errorf("get stage: %w", err)
+		}
+		if o.FreightName, err = currentFreight(res.Msg.GetStage()); err != nil {
+			return err
+		}
+	}
+
 	switch {
 	case o.Abort:
 		if _, err = kargoSvcCli.AbortPromotion(
@@ -203,10 +253,12 @@ func (o *promotionOptions) run(ctx context.Context) error {
 			ctx,
 			connect.NewRequest(
 				&v1alpha1.PromoteToStageRequest{
-					Project:      o.Project,
-					Freight:      o.FreightName,
-					FreightAlias: o.FreightAlias,
-					Stage:        o.Stage,
+					Project:        o.Project,
+					Freight:        o.FreightName,
+					FreightAlias:   o.FreightAlias,
+					Stage:          o.Stage,
+					OriginRepoUrl:  o.OriginRepoURL,
+					OriginCommitId: o.OriginCommit,
 				},
 			),
 		)
@@ -248,6 +300,29 @@ func (o *promotionOptions) run(ctx context.Context) error {
 	return nil
 }
 
+// currentFreight returns the name of the piece of freight currently in use by
+// the provided stage. An error is returned if the stage is not currently using
+// exactly one piece of freight, as a promotion can only promote one.
+func currentFreight(stage *kargoapi.Stage) (string, error) {
+	if stage == nil {
+		return "", errors.New("stage not found")
+	}
+	current := stage.Status.FreightHistory.Current()
+	if current == nil || len(current.Freight) == 0 {
+		return "", fmt.Errorf("stage %q has no current freight", stage.Name)
+	}
+	if len(current.Freight) > 1 {
+		return "", fmt.Errorf(
+			"stage %q currently uses freight from %d origins; use --%s to specify which to promote",
+			stage.Name, len(current.Freight), option.FreightFlag,
+		)
+	}
+	for _, ref := range current.Freight {
+		return ref.Name, nil
+	}
+	return "", nil
+}
+
 func waitForPromotions(
 	ctx context.Context,
 	kargoSvcCli svcv1alpha1connect.KargoServiceClient,
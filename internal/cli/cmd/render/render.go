@@ -0,0 +1,175 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/akuity/kargo/internal/cli/io"
+	"github.com/akuity/kargo/internal/cli/option"
+	"github.com/akuity/kargo/internal/cli/templates"
+	"github.com/akuity/kargo/internal/directives"
+)
+
+const (
+	overlayFlag   = "overlay"
+	imageFlag     = "image"
+	outputDirFlag = "output-dir"
+)
+
+type renderOptions struct {
+	genericiooptions.IOStreams
+
+	RepoDir       string
+	Overlay       string
+	Images        []string
+	OutputDir     string
+	SortDocuments bool
+	SortKeys      bool
+	StripFields   bool
+}
+
+func NewCommand(streams genericiooptions.IOStreams) *cobra.Command {
+	cmdOpts := &renderOptions{IOStreams: streams}
+
+	cmd := &cobra.Command{
+		Use: "render (PATH) --overlay=overlay --image=repo:tag... " +
+			"[--output-dir=dir] [--sort-documents] [--sort-keys] [--strip-fields]",
+		Short: "Render the manifests promoting images to a Kustomize overlay would produce, offline",
+		Args:  option.ExactArgs(1),
+		Example: templates.Example(`
+# Render the manifests of an overlay with a new image tag, without modifying
+# the local checkout, pushing anything, or accessing a cluster
+kargo render ./my-repo --overlay=overlays/prod --image=example.com/app:v1.2.3
+
+# Render the manifests of an overlay with an image pinned by digest
+kargo render ./my-repo --overlay=overlays/prod --image=example.com/app@sha256:4f0b...
+
+# Render the normalized manifests to a file per manifest in a directory
+kargo render ./my-repo --overlay=overlays/prod --image=example.com/app:v1.2.3 --sort-documents --sort-keys --strip-fields --output-dir=./rendered
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdOpts.complete(args)
+
+			if err := cmdOpts.validate(); err != nil {
+				return err
+			}
+
+			return cmdOpts.run(cmd.Context())
+		},
+	}
+
+	// Register the option flags on the command.
+	cmdOpts.addFlags(cmd)
+
+	// Set the input/output streams for the command.
+	io.SetIOStreams(cmd, cmdOpts.IOStreams)
+
+	return cmd
+}
+
+// addFlags adds the flags for the render options to the provided command.
+func (o *renderOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Overlay, overlayFlag, "",
+		"The path to the Kustomize overlay, relative to PATH.")
+	cmd.Flags().StringArrayVar(&o.Images, imageFlag, nil,
+		"An image to promote, in the form <repoURL>:<tag> or <repoURL>@<digest>. "+
+			"May be specified multiple times.")
+	cmd.Flags().StringVar(&o.OutputDir, outputDirFlag, "",
+		"A directory to write each rendered manifest to a file of its own in. "+
+			"If not set, all manifests are written to stdout.")
+	cmd.Flags().BoolVar(&o.SortDocuments, "sort-documents", false,
+		"If set, the manifests are ordered by API group, kind, namespace, and name.")
+	cmd.Flags().BoolVar(&o.SortKeys, "sort-keys", false,
+		"If set, the keys of every map within the manifests are ordered alphabetically.")
+	cmd.Flags().BoolVar(&o.StripFields, "strip-fields", false,
+		"If set, fields managed by the cluster or carrying no information are removed.")
+
+	if err := cmd.MarkFlagRequired(overlayFlag); err != nil {
+		panic(fmt.Errorf("could not mark %s flag as required: %w", overlayFlag, err))
+	}
+	if err := cmd.MarkFlagRequired(imageFlag); err != nil {
+		panic(fmt.Errorf("could not mark %s flag as required: %w", imageFlag, err))
+	}
+}
+
+// complete sets the options from the command arguments.
+func (o *renderOptions) complete(args []string) {
+	o.RepoDir = strings.TrimSpace(args[0])
+	o.Overlay = strings.TrimSpace(o.Overlay)
+}
+
+// validate performs validation of the options. If the options are invalid, an
+// error is returned.
+func (o *renderOptions) validate() error {
+	var errs []error
+	// While the flags are marked as required, a user could still provide an empty
+	// string. This is a check to ensure that the flags are not empty.
+	if o.RepoDir == "" {
+		errs = append(errs, errors.New("path is required"))
+	}
+	if o.Overlay == "" {
+		errs = append(errs, fmt.Errorf("%s is required", overlayFlag))
+	}
+	if len(o.Images) == 0 {
+		errs = append(errs, fmt.Errorf("at least one %s is required", imageFlag))
+	}
+	return errors.Join(errs...)
+}
+
+// run renders the manifests and writes them to stdout or the output
+// directory.
+func (o *renderOptions) run(ctx context.Context) error {
+	sim := directives.RenderSimulation{
+		RepoDir: o.RepoDir,
+		Overlay: o.Overlay,
+		Images:  o.Images,
+		Split:   o.OutputDir != "",
+	}
+	if o.SortDocuments || o.SortKeys || o.StripFields {
+		sim.Normalize = &directives.Normalize{
+			SortDocuments: o.SortDocuments,
+			SortKeys:      o.SortKeys,
+			StripFields:   o.StripFields,
+		}
+	}
+	res, err := directives.SimulateRender(ctx, sim)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+	for _, msg := range res.Messages {
+		_, _ = fmt.Fprintln(o.IOStreams.ErrOut, msg)
+	}
+
+	if o.OutputDir == "" {
+		for _, b := range res.Files {
+			if _, err = o.IOStreams.Out.Write(b); err != nil {
+				return fmt.Errorf("write manifests: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err = os.MkdirAll(o.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+	names := make([]string, 0, len(res.Files))
+	for name := range res.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		path := filepath.Join(o.OutputDir, name)
+		if err = os.WriteFile(path, res.Files[name], 0o600); err != nil {
+			return fmt.Errorf("write %q: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/akuity/kargo/internal/directives/rendertest"
+)
+
+func TestRender(t *testing.T) {
+	rendertest.Run(t, func(t *testing.T, c rendertest.Case) ([]byte, error) {
+		streams, _, out, _ := genericiooptions.NewTestIOStreams()
+		cmd := NewCommand(streams)
+		args := []string{c.RepoDir, "--overlay", c.Overlay}
+		for _, image := range c.Images {
+			args = append(args, "--image", image)
+		}
+		if c.Normalize {
+			args = append(args, "--sort-documents", "--sort-keys", "--strip-fields")
+		}
+		cmd.SetArgs(args)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		if err := cmd.Execute(); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	})
+}
+
+func TestRender_outputDir(t *testing.T) {
+	repoDir := filepath.Join("..", "..", "..", "directives", "rendertest", "testdata", "basic", "repo")
+	outputDir := filepath.Join(t.TempDir(), "rendered")
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+	cmd := NewCommand(streams)
+	cmd.SetArgs([]string{
+		repoDir,
+		"--overlay", "overlays/prod",
+		"--image", "example.com/guestbook:v0.2.0",
+		"--output-dir", outputDir,
+	})
+	require.NoError(t, cmd.Execute())
+	require.Empty(t, out.String())
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	var rendered bytes.Buffer
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(outputDir, entry.Name()))
+		require.NoError(t, err)
+		rendered.Write(b)
+	}
+	require.Contains(t, rendered.String(), "image: example.com/guestbook:v0.2.0")
+}
@@ -33,6 +33,9 @@ const (
 	// FreightAliasFlag is the flag name for the freight-alias flag.
 	FreightAliasFlag = "freight-alias"
 
+	// FreightFromStageFlag is the flag name for the freight-from-stage flag.
+	FreightFromStageFlag = "freight-from-stage"
+
 	// GitFlag is the flag name for the git flag.
 	GitFlag = string(credentials.TypeGit)
 
@@ -114,6 +117,12 @@ const (
 
 	// AbortFlag is the flag name for the abort flag.
 	AbortFlag = "abort"
+
+	// OriginRepoURLFlag is the flag name for the origin-repo-url flag.
+	OriginRepoURLFlag = "origin-repo-url"
+
+	// OriginCommitFlag is the flag name for the origin-commit flag.
+	OriginCommitFlag = "origin-commit"
 )
 
 // Alias adds the AliasFlag to the provided flag set.
@@ -163,6 +172,11 @@ func FreightAlias(fs *pflag.FlagSet, stage *string, usage string) {
 	fs.StringVar(stage, FreightAliasFlag, "", usage)
 }
 
+// FreightFromStage adds the FreightFromStageFlag to the provided flag set.
+func FreightFromStage(fs *pflag.FlagSet, stage *string, usage string) {
+	fs.StringVar(stage, FreightFromStageFlag, "", usage)
+}
+
 // Git adds the GitFlag to the provided flag set.
 func Git(fs *pflag.FlagSet, git *bool, usage string) {
 	fs.BoolVar(git, GitFlag, false, usage)
@@ -301,3 +315,13 @@ func Wait(fs *pflag.FlagSet, wait *bool, defaultWait bool, usage string) {
 func Abort(fs *pflag.FlagSet, abort *bool, defaultAbort bool, usage string) {
 	fs.BoolVar(abort, AbortFlag, defaultAbort, usage)
 }
+
+// OriginRepoURL adds the OriginRepoURLFlag to the provided flag set.
+func OriginRepoURL(fs *pflag.FlagSet, repoURL *string, usage string) {
+	fs.StringVar(repoURL, OriginRepoURLFlag, "", usage)
+}
+
+// OriginCommit adds the OriginCommitFlag to the provided flag set.
+func OriginCommit(fs *pflag.FlagSet, commit *string, usage string) {
+	fs.StringVar(commit, OriginCommitFlag, "", usage)
+}
@@ -18,19 +18,39 @@ type Application struct {
 }
 
 type ApplicationSpec struct {
-	Source     *ApplicationSource `json:"source,omitempty"`
-	SyncPolicy *SyncPolicy        `json:"syncPolicy,omitempty"`
-	Sources    ApplicationSources `json:"sources,omitempty"`
+	Project     string                  `json:"project,omitempty"`
+	Source      *ApplicationSource      `json:"source,omitempty"`
+	Destination *ApplicationDestination `json:"destination,omitempty"`
+	SyncPolicy  *SyncPolicy             `json:"syncPolicy,omitempty"`
+	Sources     ApplicationSources      `json:"sources,omitempty"`
+}
+
+// ApplicationDestination holds information about the Application's
+// destination.
+type ApplicationDestination struct {
+	Server    string `json:"server,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
 }
 
 type ApplicationSource struct {
 	RepoURL        string                      `json:"repoURL"`
+	Path           string                      `json:"path,omitempty"`
 	TargetRevision string                      `json:"targetRevision,omitempty"`
 	Helm           *ApplicationSourceHelm      `json:"helm,omitempty"`
 	Kustomize      *ApplicationSourceKustomize `json:"kustomize,omitempty"`
+	Directory      *ApplicationSourceDirectory `json:"directory,omitempty"`
 	Chart          string                      `json:"chart,omitempty"`
 }
 
+// ApplicationSourceDirectory holds options for an Application source that
+// reads plain manifests from a directory.
+type ApplicationSourceDirectory struct {
+	Recurse bool   `json:"recurse,omitempty"`
+	Exclude string `json:"exclude,omitempty"`
+	Include string `json:"include,omitempty"`
+}
+
 // Equals compares two instances of ApplicationSource and returns true if
 // they are equal.
 func (source *ApplicationSource) Equals(other *ApplicationSource) bool {
@@ -62,7 +82,8 @@ func (s ApplicationSources) Equals(other ApplicationSources) bool {
 type RefreshType string
 
 const (
-	RefreshTypeHard RefreshType = "hard"
+	RefreshTypeHard   RefreshType = "hard"
+	RefreshTypeNormal RefreshType = "normal"
 )
 
 type ApplicationSourceHelm struct {
@@ -87,6 +108,19 @@ type ApplicationStatus struct {
 	Sync           SyncStatus             `json:"sync,omitempty"`
 	Conditions     []ApplicationCondition `json:"conditions,omitempty"`
 	OperationState *OperationState        `json:"operationState,omitempty"`
+	Resources      []ResourceStatus       `json:"resources,omitempty"`
+}
+
+// ResourceStatus holds the current sync and health status of a resource
+// managed by an Application.
+type ResourceStatus struct {
+	Group     string         `json:"group,omitempty"`
+	Version   string         `json:"version,omitempty"`
+	Kind      string         `json:"kind,omitempty"`
+	Namespace string         `json:"namespace,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Status    SyncStatusCode `json:"status,omitempty"`
+	Health    *HealthStatus  `json:"health,omitempty"`
 }
 
 type OperationInitiator struct {
@@ -178,8 +212,24 @@ type OperationState struct {
 }
 
 type SyncOperationResult struct {
+	Resources ResourceResults    `json:"resources,omitempty"`
 	Revision  string             `json:"revision,omitempty"`
 	Revisions []string           `json:"revisions,omitempty"`
 	Source    ApplicationSource  `json:"source,omitempty"`
 	Sources   ApplicationSources `json:"sources,omitempty"`
 }
+
+type ResourceResults []*ResourceResult
+
+type ResourceResult struct {
+	Group     string         `json:"group"`
+	Version   string         `json:"version"`
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Status    ResultCode     `json:"status,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	HookType  HookType       `json:"hookType,omitempty"`
+	HookPhase OperationPhase `json:"hookPhase,omitempty"`
+	SyncPhase SyncPhase      `json:"syncPhase,omitempty"`
+}
@@ -0,0 +1,232 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetAppProject returns a pointer to the Argo CD AppProject resource specified
+// by the namespace and name arguments. If no such resource is found, nil is
+// returned instead.
+func GetAppProject(
+	ctx context.Context,
+	ctrlRuntimeClient client.Client,
+	namespace string,
+	name string,
+) (*AppProject, error) {
+	project := AppProject{}
+	if err := ctrlRuntimeClient.Get(
+		ctx,
+		client.ObjectKey{
+			Namespace: namespace,
+			Name:      name,
+		},
+		&project,
+	); err != nil {
+		if err = client.IgnoreNotFound(err); err == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(
+			"error getting Argo CD AppProject %q in namespace %q: %w",
+			name,
+			namespace,
+			err,
+		)
+	}
+	return &project, nil
+}
+
+// PermitsSourceNamespace returns true if Applications belonging to the
+// AppProject may reside in the specified namespace according to the
+// AppProject's source namespaces. Note that Applications residing in the
+// namespace Argo CD is installed in are always permitted, which this method
+// does not account for.
+func (p *AppProject) PermitsSourceNamespace(namespace string) bool {
+	for _, pattern := range p.Spec.SourceNamespaces {
+		if matched, err := path.Match(pattern, namespace); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// SyncWindowKindAllow is the kind of a SyncWindow that allows syncs while
+	// it is active.
+	SyncWindowKindAllow = "allow"
+	// SyncWindowKindDeny is the kind of a SyncWindow that denies syncs while
+	// it is active.
+	SyncWindowKindDeny = "deny"
+)
+
+// maxSyncWindowTransitions bounds the number of times SyncWindows are opened
+// or closed that NextSyncTime considers before giving up.
+const maxSyncWindowTransitions = 1000
+
+// syncWindowScheduleParser parses the schedules of SyncWindows the same way
+// Argo CD does.
+var syncWindowScheduleParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow,
+)
+
+// Matches returns the SyncWindows that apply to the specified Application,
+// i.e. those that match its name, its destination namespace, or its
+// destination cluster.
+func (w SyncWindows) Matches(app *Application) SyncWindows {
+	var matches SyncWindows
+	for _, window := range w {
+		if window.matches(app) {
+			matches = append(matches, window)
+		}
+	}
+	return matches
+}
+
+// CanSync returns true if the SyncWindows permit a sync at the specified
+// time. Like Argo CD, a sync is denied while any deny window is active, or
+// while no allow window is active if there are any. A manual sync is
+// nevertheless permitted if all active deny windows, or, absent those, any
+// inactive allow window, permit manual syncs.
+func (w SyncWindows) CanSync(manual bool, t time.Time) (bool, error) {
+	var denyActive, allowActive, allowInactive, allowInactiveManual bool
+	denyManual := true
+	for _, window := range w {
+		active, _, err := window.active(t)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case window.Kind == SyncWindowKindDeny && active:
+			denyActive = true
+			denyManual = denyManual && window.ManualSync
+		case window.Kind == SyncWindowKindAllow && active:
+			allowActive = true
+		case window.Kind == SyncWindowKindAllow:
+			allowInactive = true
+			allowInactiveManual = allowInactiveManual || window.ManualSync
+		}
+	}
+	if denyActive {
+		return manual && denyManual, nil
+	}
+	if allowActive || !allowInactive {
+		return true, nil
+	}
+	return manual && allowInactiveManual, nil
+}
+
+// NextSyncTime returns the earliest time after the specified one at which the
+// SyncWindows permit a sync. If no such time can be found, because the
+// SyncWindows never permit a sync or only do so in the distant future, the
+// zero time is returned.
+func (w SyncWindows) NextSyncTime(manual bool, t time.Time) (time.Time, error) {
+	for range maxSyncWindowTransitions {
+		var next time.Time
+		for _, window := range w {
+			transition, err := window.nextTransition(t)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if !transition.IsZero() && (next.IsZero() || transition.Before(next)) {
+				next = transition
+			}
+		}
+		if next.IsZero() {
+			return time.Time{}, nil
+		}
+		t = next
+		ok, err := w.CanSync(manual, t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			return t, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// matches returns true if the SyncWindow applies to the specified
+// Application.
+func (w *SyncWindow) matches(app *Application) bool {
+	var dest ApplicationDestination
+	if app.Spec.Destination != nil {
+		dest = *app.Spec.Destination
+	}
+	return matchesAny(w.Applications, app.Name) ||
+		matchesAny(w.Namespaces, dest.Namespace) ||
+		matchesAny(w.Clusters, dest.Server) ||
+		matchesAny(w.Clusters, dest.Name)
+}
+
+// active returns true if the SyncWindow is active at the specified time. If
+// it is, the time at which the occurrence of the SyncWindow that is active
+// closes is returned as well.
+func (w *SyncWindow) active(t time.Time) (bool, time.Time, error) {
+	schedule, duration, location, err := w.parse()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	start := schedule.Next(t.In(location).Add(-duration))
+	if start.IsZero() || start.After(t) {
+		return false, time.Time{}, nil
+	}
+	return true, start.Add(duration), nil
+}
+
+// nextTransition returns the earliest time after the specified one at which
+// the SyncWindow opens or closes. If the SyncWindow never opens again, the
+// zero time is returned.
+func (w *SyncWindow) nextTransition(t time.Time) (time.Time, error) {
+	active, end, err := w.active(t)
+	if err != nil || active {
+		return end, err
+	}
+	schedule, _, location, err := w.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(t.In(location)), nil
+}
+
+// parse returns the schedule, duration, and time zone of the SyncWindow.
+func (w *SyncWindow) parse() (cron.Schedule, time.Duration, *time.Location, error) {
+	schedule, err := syncWindowScheduleParser.Parse(w.Schedule)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf(
+			"error parsing schedule %q of sync window: %w", w.Schedule, err,
+		)
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf(
+			"error parsing duration %q of sync window: %w", w.Duration, err,
+		)
+	}
+	location, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf(
+			"error loading time zone %q of sync window: %w", w.TimeZone, err,
+		)
+	}
+	return schedule, duration, location, nil
+}
+
+// matchesAny returns true if the specified value is not empty and matches any
+// of the specified glob patterns.
+func matchesAny(patterns []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
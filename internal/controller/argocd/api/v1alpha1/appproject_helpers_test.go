@@ -0,0 +1,174 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncWindows_Matches(t *testing.T) {
+	app := &Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook-prod"},
+		Spec: ApplicationSpec{
+			Destination: &ApplicationDestination{
+				Server:    "https://kubernetes.default.svc",
+				Namespace: "guestbook",
+			},
+		},
+	}
+	windows := SyncWindows{
+		{Kind: SyncWindowKindDeny, Applications: []string{"guestbook-*"}},
+		{Kind: SyncWindowKindDeny, Namespaces: []string{"guestbook"}},
+		{Kind: SyncWindowKindDeny, Clusters: []string{"https://kubernetes.default.svc"}},
+		{Kind: SyncWindowKindDeny, Applications: []string{"other"}},
+		{Kind: SyncWindowKindDeny, Namespaces: []string{"other"}},
+		{Kind: SyncWindowKindDeny},
+	}
+	require.Equal(t, windows[:3], windows.Matches(app))
+}
+
+func TestSyncWindows_CanSync(t *testing.T) {
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		windows   SyncWindows
+		manual    bool
+		canSync   bool
+		nextSync  time.Time
+		errSubstr string
+	}{
+		{
+			name:    "no windows",
+			canSync: true,
+		},
+		{
+			name: "active deny window",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindDeny,
+				Schedule: "0 9 * * *",
+				Duration: "2h",
+			}},
+			nextSync: time.Date(2026, 10, 15, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "active deny window permitting manual syncs",
+			windows: SyncWindows{{
+				Kind:       SyncWindowKindDeny,
+				Schedule:   "0 9 * * *",
+				Duration:   "2h",
+				ManualSync: true,
+			}},
+			manual:  true,
+			canSync: true,
+		},
+		{
+			name: "active deny windows not all permitting manual syncs",
+			windows: SyncWindows{
+				{
+					Kind:       SyncWindowKindDeny,
+					Schedule:   "0 9 * * *",
+					Duration:   "2h",
+					ManualSync: true,
+				},
+				{
+					Kind:     SyncWindowKindDeny,
+					Schedule: "30 9 * * *",
+					Duration: "2h",
+				},
+			},
+			manual:   true,
+			nextSync: time.Date(2026, 10, 15, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "inactive deny window",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindDeny,
+				Schedule: "0 22 * * *",
+				Duration: "1h",
+			}},
+			canSync: true,
+		},
+		{
+			name: "active allow window",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindAllow,
+				Schedule: "0 9 * * *",
+				Duration: "2h",
+			}},
+			canSync: true,
+		},
+		{
+			name: "inactive allow window",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindAllow,
+				Schedule: "0 22 * * *",
+				Duration: "1h",
+			}},
+			nextSync: time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "active deny window overlapping allow window",
+			windows: SyncWindows{
+				{
+					Kind:     SyncWindowKindAllow,
+					Schedule: "0 9 * * *",
+					Duration: "4h",
+				},
+				{
+					Kind:     SyncWindowKindDeny,
+					Schedule: "0 9 * * *",
+					Duration: "2h",
+				},
+			},
+			nextSync: time.Date(2026, 10, 15, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "deny window that never closes",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindDeny,
+				Schedule: "* * * * *",
+				Duration: "1h",
+			}},
+		},
+		{
+			name: "invalid schedule",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindDeny,
+				Schedule: "invalid",
+				Duration: "1h",
+			}},
+			errSubstr: "error parsing schedule",
+		},
+		{
+			name: "invalid duration",
+			windows: SyncWindows{{
+				Kind:     SyncWindowKindDeny,
+				Schedule: "0 9 * * *",
+				Duration: "invalid",
+			}},
+			errSubstr: "error parsing duration",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			canSync, err := testCase.windows.CanSync(testCase.manual, now)
+			if testCase.errSubstr != "" {
+				require.ErrorContains(t, err, testCase.errSubstr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.canSync, canSync)
+			if canSync {
+				return
+			}
+			nextSync, err := testCase.windows.NextSyncTime(testCase.manual, now)
+			require.NoError(t, err)
+			require.True(
+				t, testCase.nextSync.Equal(nextSync),
+				"expected %s, got %s", testCase.nextSync, nextSync,
+			)
+		})
+	}
+}
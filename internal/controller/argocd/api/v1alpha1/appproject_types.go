@@ -0,0 +1,58 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+
+type AppProject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              AppProjectSpec `json:"spec"`
+}
+
+type AppProjectSpec struct {
+	// SourceNamespaces lists the namespaces, other than the one Argo CD is
+	// installed in, that Applications belonging to the project may reside in.
+	// Entries may be glob patterns.
+	SourceNamespaces []string `json:"sourceNamespaces,omitempty"`
+	// SyncWindows controls when syncs of the project's Applications may run.
+	SyncWindows SyncWindows `json:"syncWindows,omitempty"`
+}
+
+// SyncWindows is a collection of SyncWindow.
+type SyncWindows []SyncWindow
+
+// SyncWindow describes a recurring period of time during which syncs of the
+// Applications it matches are allowed or denied.
+type SyncWindow struct {
+	// Kind is either "allow" or "deny".
+	Kind string `json:"kind,omitempty"`
+	// Schedule is the time the window opens, in cron format.
+	Schedule string `json:"schedule,omitempty"`
+	// Duration is the amount of time the window remains open, e.g. "1h".
+	Duration string `json:"duration,omitempty"`
+	// Applications are the names of the Applications the window matches.
+	// Entries may be glob patterns.
+	Applications []string `json:"applications,omitempty"`
+	// Namespaces are the destination namespaces of the Applications the
+	// window matches. Entries may be glob patterns.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Clusters are the destination clusters, by server URL or name, of the
+	// Applications the window matches. Entries may be glob patterns.
+	Clusters []string `json:"clusters,omitempty"`
+	// ManualSync permits manual syncs while the window denies syncs.
+	ManualSync bool `json:"manualSync,omitempty"`
+	// TimeZone is the time zone the schedule is evaluated in. If empty, UTC
+	// is used.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+type AppProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []AppProject `json:"items"`
+}
@@ -0,0 +1,290 @@
+package branches
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/oklog/ulid/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/directives"
+	"github.com/akuity/kargo/internal/gitprovider"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// gitOpenPRStep is the name of the promotion step that opens pull requests.
+const gitOpenPRStep = "git-open-pr"
+
+// SweeperConfig is configuration for the promotion branch sweeper.
+type SweeperConfig struct {
+	// Interval specifies how often the sweeper runs. A value of 0 disables the
+	// sweeper.
+	Interval time.Duration `envconfig:"PROMOTION_BRANCH_SWEEP_INTERVAL" default:"0s"`
+	// MinAge specifies the minimum age a promotion branch must be before it is
+	// considered eligible for deletion. The age of a branch is derived from the
+	// name of the Promotion that created it.
+	MinAge time.Duration `envconfig:"PROMOTION_BRANCH_MIN_AGE" default:"168h"` // 1 week
+	// DryRun specifies whether the sweeper should only log the branches it
+	// would delete instead of actually deleting them.
+	DryRun bool `envconfig:"PROMOTION_BRANCH_SWEEP_DRY_RUN" default:"false"`
+}
+
+// SweeperConfigFromEnv returns a SweeperConfig populated from environment
+// variables.
+func SweeperConfigFromEnv() SweeperConfig {
+	cfg := SweeperConfig{}
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+// repo identifies a remote Git repository to which pull requests are opened
+// by some Stage's promotion steps.
+type repo struct {
+	project               string
+	url                   string
+	provider              string
+	insecureSkipTLSVerify bool
+}
+
+// sweeper periodically deletes stale branches generated by the git-push step
+// (see directives.PromotionBranchPrefix) from the remote repositories that
+// Stages open pull requests to.
+type sweeper struct {
+	cfg           SweeperConfig
+	client        client.Client
+	credentialsDB credentials.Database
+
+	// The following behaviors are overridable for testing purposes:
+
+	nowFn func() time.Time
+
+	listRemoteBranchesFn func(string, string, *git.ClientOptions) ([]string, error)
+
+	deleteRemoteBranchFn func(string, string, *git.ClientOptions) error
+
+	newGitProviderFn func(string, *gitprovider.Options) (gitprovider.Interface, error)
+}
+
+// SetupSweeperWithManager registers a promotion branch sweeper with the
+// provided Manager. Nothing is registered if the sweeper is disabled by the
+// provided SweeperConfig.
+func SetupSweeperWithManager(
+	ctx context.Context,
+	mgr manager.Manager,
+	credentialsDB credentials.Database,
+	cfg SweeperConfig,
+) error {
+	if cfg.Interval <= 0 {
+		return nil
+	}
+	if err := mgr.Add(newSweeper(mgr.GetClient(), credentialsDB, cfg)); err != nil {
+		return fmt.Errorf("error adding promotion branch sweeper to manager: %w", err)
+	}
+	logging.LoggerFromContext(ctx).Info(
+		"Initialized promotion branch sweeper",
+		"interval", cfg.Interval,
+		"minAge", cfg.MinAge,
+		"dryRun", cfg.DryRun,
+	)
+	return nil
+}
+
+func newSweeper(
+	kubeClient client.Client,
+	credentialsDB credentials.Database,
+	cfg SweeperConfig,
+) *sweeper {
+	return &sweeper{
+		cfg:                  cfg,
+		client:               kubeClient,
+		credentialsDB:        credentialsDB,
+		nowFn:                time.Now,
+		listRemoteBranchesFn: git.ListRemoteBranches,
+		deleteRemoteBranchFn: git.DeleteRemoteBranch,
+		newGitProviderFn:     gitprovider.New,
+	}
+}
+
+// Start implements manager.Runnable. It sweeps once per configured interval
+// until the provided context is canceled.
+func (s *sweeper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.sweep(ctx); err != nil {
+				logging.LoggerFromContext(ctx).Error(err, "error sweeping promotion branches")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *sweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// sweep deletes stale promotion branches from all repositories that Stages
+// open pull requests to. Failures to sweep an individual repository are
+// logged, but do not prevent other repositories from being swept.
+func (s *sweeper) sweep(ctx context.Context) error {
+	stages := kargoapi.StageList{}
+	if err := s.client.List(ctx, &stages); err != nil {
+		return fmt.Errorf("error listing Stages: %w", err)
+	}
+	for _, r := range reposFromStages(stages.Items) {
+		if err := s.sweepRepo(ctx, r); err != nil {
+			logging.LoggerFromContext(ctx).Error(
+				err, "error sweeping promotion branches",
+				"project", r.project,
+				"repo", r.url,
+			)
+		}
+	}
+	return nil
+}
+
+// sweepRepo deletes promotion branches from the provided repository that are
+// older than the configured minimum age and have no open pull request.
+func (s *sweeper) sweepRepo(ctx context.Context, r repo) error {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"project", r.project,
+		"repo", r.url,
+	)
+
+	var repoCreds *git.RepoCredentials
+	creds, found, err := s.credentialsDB.Get(ctx, r.project, credentials.TypeGit, r.url)
+	if err != nil {
+		return fmt.Errorf("error getting credentials for %s: %w", r.url, err)
+	}
+	if found {
+		repoCreds = &git.RepoCredentials{
+			Username:      creds.Username,
+			Password:      creds.Password,
+			SSHPrivateKey: creds.SSHPrivateKey,
+			HTTPAuthMode:  git.HTTPAuthMode(creds.HTTPAuthMode),
+		}
+	}
+	clientOpts := &git.ClientOptions{
+		Credentials:           repoCreds,
+		InsecureSkipTLSVerify: r.insecureSkipTLSVerify,
+	}
+
+	branches, err := s.listRemoteBranchesFn(r.url, directives.PromotionBranchPrefix, clientOpts)
+	if err != nil {
+		return err
+	}
+
+	var gitProv gitprovider.Interface
+	for _, branch := range branches {
+		createdAt, ok := promotionCreationTime(
+			strings.TrimPrefix(branch, directives.PromotionBranchPrefix),
+		)
+		if !ok || s.nowFn().Sub(createdAt) < s.cfg.MinAge {
+			continue
+		}
+		if gitProv == nil {
+			gpOpts := &gitprovider.Options{
+				Name:                  r.provider,
+				InsecureSkipTLSVerify: r.insecureSkipTLSVerify,
+			}
+			if repoCreds != nil {
+				gpOpts.Token = repoCreds.Password
+			}
+			if gitProv, err = s.newGitProviderFn(r.url, gpOpts); err != nil {
+				return fmt.Errorf("error creating git provider service: %w", err)
+			}
+		}
+		prs, err := gitProv.ListPullRequests(ctx, &gitprovider.ListPullRequestOptions{
+			State:      gitprovider.PullRequestStateOpen,
+			HeadBranch: branch,
+		})
+		if err != nil {
+			logger.Error(err, "error listing open pull requests", "branch", branch)
+			continue
+		}
+		if len(prs) > 0 {
+			continue
+		}
+		if s.cfg.DryRun {
+			logger.Info("would delete stale promotion branch (dry run)", "branch", branch)
+			continue
+		}
+		if err = s.deleteRemoteBranchFn(r.url, branch, clientOpts); err != nil &&
+			!errors.Is(err, git.ErrRemoteBranchNotFound) {
+			logger.Error(err, "error deleting stale promotion branch", "branch", branch)
+			continue
+		}
+		logger.Info("deleted stale promotion branch", "branch", branch)
+	}
+	return nil
+}
+
+// reposFromStages returns the distinct repositories that the provided Stages
+// open pull requests to. Steps whose configuration cannot be determined
+// without evaluating expressions are ignored.
+func reposFromStages(stages []kargoapi.Stage) []repo {
+	var repos []repo
+	seen := map[repo]struct{}{}
+	for _, stage := range stages {
+		if stage.Spec.PromotionTemplate == nil {
+			continue
+		}
+		for _, step := range stage.Spec.PromotionTemplate.Spec.Steps {
+			if step.Uses != gitOpenPRStep || step.Config == nil {
+				continue
+			}
+			cfg := directives.GitOpenPRConfig{}
+			if err := json.Unmarshal(step.Config.Raw, &cfg); err != nil {
+				continue
+			}
+			if cfg.RepoURL == "" || strings.Contains(cfg.RepoURL, "${{") {
+				continue
+			}
+			r := repo{
+				project:               stage.Namespace,
+				url:                   cfg.RepoURL,
+				insecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
+			}
+			if cfg.Provider != nil {
+				r.provider = string(*cfg.Provider)
+			}
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+			repos = append(repos, r)
+		}
+	}
+	return repos
+}
+
+// promotionCreationTime returns the time at which the Promotion with the
+// provided name was created, as encoded in the ULID that is part of every
+// Promotion name. If the name does not contain a valid ULID, false is
+// returned.
+func promotionCreationTime(promoName string) (time.Time, bool) {
+	// Promotion names are of the form <stage-name>.<ulid>.<short-hash>, where
+	// the Stage name may itself contain dots.
+	parts := strings.Split(promoName, ".")
+	if len(parts) < 3 {
+		return time.Time{}, false
+	}
+	id, err := ulid.ParseStrict(parts[len(parts)-2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ulid.Time(id.Time()), true
+}
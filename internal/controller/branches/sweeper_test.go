@@ -0,0 +1,192 @@
+package branches
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+func TestSweeper_sweepRepo(t *testing.T) {
+	now := time.Now()
+	oldBranch := "kargo/promotion/" + testPromotionName(now.Add(-48*time.Hour))
+	newBranch := "kargo/promotion/" + testPromotionName(now.Add(-time.Hour))
+	openPRBranch := "kargo/promotion/" + testPromotionName(now.Add(-72*time.Hour))
+	const unparseableBranch = "kargo/promotion/not-a-promotion"
+
+	testCases := []struct {
+		name       string
+		dryRun     bool
+		listErr    error
+		deleteErr  error
+		assertions func(t *testing.T, deleted []string, err error)
+	}{
+		{
+			name:    "error listing branches",
+			listErr: errors.New("something went wrong"),
+			assertions: func(t *testing.T, deleted []string, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.Empty(t, deleted)
+			},
+		},
+		{
+			name: "only stale branches without open pull requests are deleted",
+			assertions: func(t *testing.T, deleted []string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{oldBranch}, deleted)
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			assertions: func(t *testing.T, deleted []string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, deleted)
+			},
+		},
+		{
+			name:      "error deleting branch",
+			deleteErr: errors.New("something went wrong"),
+			assertions: func(t *testing.T, deleted []string, err error) {
+				// Deletion failures are logged, not returned
+				require.NoError(t, err)
+				require.Equal(t, []string{oldBranch}, deleted)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var deleted []string
+			s := &sweeper{
+				cfg: SweeperConfig{
+					MinAge: 24 * time.Hour,
+					DryRun: testCase.dryRun,
+				},
+				credentialsDB: &credentials.FakeDB{},
+				nowFn:         func() time.Time { return now },
+				listRemoteBranchesFn: func(
+					_ string,
+					prefix string,
+					_ *git.ClientOptions,
+				) ([]string, error) {
+					require.Equal(t, "kargo/promotion/", prefix)
+					return []string{
+						oldBranch,
+						newBranch,
+						openPRBranch,
+						unparseableBranch,
+					}, testCase.listErr
+				},
+				deleteRemoteBranchFn: func(_ string, branch string, _ *git.ClientOptions) error {
+					deleted = append(deleted, branch)
+					return testCase.deleteErr
+				},
+				newGitProviderFn: func(string, *gitprovider.Options) (gitprovider.Interface, error) {
+					return &gitprovider.Fake{
+						ListPullRequestsFn: func(
+							_ context.Context,
+							opts *gitprovider.ListPullRequestOptions,
+						) ([]gitprovider.PullRequest, error) {
+							require.Equal(t, gitprovider.PullRequestStateOpen, opts.State)
+							if opts.HeadBranch == openPRBranch {
+								return []gitprovider.PullRequest{{Open: true}}, nil
+							}
+							return nil, nil
+						},
+					}, nil
+				},
+			}
+			err := s.sweepRepo(
+				context.Background(),
+				repo{project: "fake-project", url: "https://github.com/example/repo.git"},
+			)
+			testCase.assertions(t, deleted, err)
+		})
+	}
+}
+
+func Test_reposFromStages(t *testing.T) {
+	newStage := func(namespace string, steps ...kargoapi.PromotionStep) kargoapi.Stage {
+		return kargoapi.Stage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+			Spec: kargoapi.StageSpec{
+				PromotionTemplate: &kargoapi.PromotionTemplate{
+					Spec: kargoapi.PromotionTemplateSpec{Steps: steps},
+				},
+			},
+		}
+	}
+	openPRStep := func(cfg string) kargoapi.PromotionStep {
+		return kargoapi.PromotionStep{
+			Uses:   "git-open-pr",
+			Config: &apiextensionsv1.JSON{Raw: []byte(cfg)},
+		}
+	}
+
+	repos := reposFromStages([]kargoapi.Stage{
+		{}, // No promotion template
+		newStage(
+			"project-a",
+			kargoapi.PromotionStep{
+				Uses:   "git-push",
+				Config: &apiextensionsv1.JSON{Raw: []byte(`{"path":"./out"}`)},
+			},
+			openPRStep(`{"repoURL":"https://github.com/example/repo.git","provider":"github"}`),
+			openPRStep(`{"repoURL":"${{ vars.repoURL }}"}`),
+		),
+		newStage(
+			"project-a",
+			openPRStep(`{"repoURL":"https://github.com/example/repo.git","provider":"github"}`),
+		),
+		newStage(
+			"project-b",
+			openPRStep(`{"repoURL":"https://github.com/example/repo.git","insecureSkipTLSVerify":true}`),
+		),
+	})
+	require.Equal(
+		t,
+		[]repo{
+			{
+				project:  "project-a",
+				url:      "https://github.com/example/repo.git",
+				provider: "github",
+			},
+			{
+				project:               "project-b",
+				url:                   "https://github.com/example/repo.git",
+				insecureSkipTLSVerify: true,
+			},
+		},
+		repos,
+	)
+}
+
+func Test_promotionCreationTime(t *testing.T) {
+	createdAt := time.Now().Truncate(time.Millisecond)
+
+	got, ok := promotionCreationTime(testPromotionName(createdAt))
+	require.True(t, ok)
+	require.True(t, createdAt.Equal(got))
+
+	_, ok = promotionCreationTime("not-a-promotion")
+	require.False(t, ok)
+
+	_, ok = promotionCreationTime("stage.not-a-ulid.abcdef0")
+	require.False(t, ok)
+}
+
+func testPromotionName(createdAt time.Time) string {
+	id := ulid.MustNew(ulid.Timestamp(createdAt), ulid.DefaultEntropy())
+	return strings.ToLower("my.stage." + id.String() + ".abcdef0")
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// DefaultMaxChildrenPerPromotion is the default number of objects that may be
+// created on behalf of a single Promotion.
+const DefaultMaxChildrenPerPromotion = 10
+
+// ErrChildLimitExceeded is returned by ChildCreator.Create when creating an
+// object would exceed the number of objects that may be created on behalf of
+// a Promotion.
+var ErrChildLimitExceeded = errors.New("ChildLimitExceeded")
+
+// childListKinds are the kinds of lists of the objects that are created on
+// behalf of Promotions. Objects of all of these kinds count towards the limit
+// enforced by ChildCreator, so any kind of object it is used to create must be
+// added here.
+var childListKinds = []schema.GroupVersionKind{
+	corev1.SchemeGroupVersion.WithKind("ConfigMapList"),
+	batchv1.SchemeGroupVersion.WithKind("JobList"),
+}
+
+// ChildCreator creates objects on behalf of Promotions, such as the
+// ConfigMaps holding their transcripts and the Jobs run by their steps. Every
+// such object is owned by its Promotion, so that it is garbage collected along
+// with it, and labeled with the Promotion and the component that created it,
+// so that all of them can be found and accounted for.
+//
+// The owner reference blocks the deletion of the Promotion, so deleting the
+// Promotion with foreground propagation waits for all of its children to be
+// deleted, while background propagation (the default) deletes them after it.
+type ChildCreator struct {
+	client      client.Client
+	maxChildren int
+}
+
+// NewChildCreator returns a ChildCreator that creates objects using the
+// provided client and refuses to create more than the provided number of
+// objects on behalf of any one Promotion. If maxChildren is not positive,
+// DefaultMaxChildrenPerPromotion is used.
+func NewChildCreator(c client.Client, maxChildren int) *ChildCreator {
+	if maxChildren <= 0 {
+		maxChildren = DefaultMaxChildrenPerPromotion
+	}
+	return &ChildCreator{
+		client:      c,
+		maxChildren: maxChildren,
+	}
+}
+
+// Create creates the provided object in the namespace of the provided
+// Promotion on its behalf, as the provided component. If the limit of objects
+// created on behalf of the Promotion has been reached, the object is not
+// created and an error wrapping ErrChildLimitExceeded is returned. An object
+// with the same name as an existing one does not count towards the limit
+// twice, so the error from creating it again is returned as is.
+func (c *ChildCreator) Create(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	component string,
+	obj client.Object,
+) error {
+	return c.create(ctx, c.client, promo, component, obj, true)
+}
+
+// CreateAs is like Create, but creates the object using the provided client,
+// e.g. one impersonating a ServiceAccount, so that creating it is subject to
+// that client's permissions. The objects created on behalf of the Promotion
+// are still counted using the ChildCreator's own client. Since blocking the
+// deletion of the Promotion would require the provided client to be permitted
+// to update the Promotion's finalizers, the owner reference of the object does
+// not block it.
+func (c *ChildCreator) CreateAs(
+	ctx context.Context,
+	creator client.Client,
+	promo *kargoapi.Promotion,
+	component string,
+	obj client.Object,
+) error {
+	return c.create(ctx, creator, promo, component, obj, false)
+}
+
+func (c *ChildCreator) create(
+	ctx context.Context,
+	creator client.Client,
+	promo *kargoapi.Promotion,
+	component string,
+	obj client.Object,
+	blockOwnerDeletion bool,
+) error {
+	count, err := c.countChildren(ctx, promo, obj.GetName())
+	if err != nil {
+		return err
+	}
+	if count >= c.maxChildren {
+		return fmt.Errorf(
+			"%w: refusing to create %s %q for Promotion %q in namespace %q, "+
+				"which already has %d of at most %d objects created on its behalf",
+			ErrChildLimitExceeded, component, obj.GetName(), promo.Name,
+			promo.Namespace, count, c.maxChildren,
+		)
+	}
+
+	obj.SetNamespace(promo.Namespace)
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 2)
+	}
+	labels[kargoapi.PromotionLabelKey] = promo.Name
+	labels[kargoapi.ComponentLabelKey] = component
+	obj.SetLabels(labels)
+	ownerRef := metav1.NewControllerRef(promo, kargoapi.GroupVersion.WithKind("Promotion"))
+	ownerRef.BlockOwnerDeletion = ptr.To(blockOwnerDeletion)
+	obj.SetOwnerReferences([]metav1.OwnerReference{*ownerRef})
+	return creator.Create(ctx, obj)
+}
+
+// countChildren returns the number of objects created on behalf of the
+// provided Promotion, not counting any with the provided name.
+func (c *ChildCreator) countChildren(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	excludeName string,
+) (int, error) {
+	var count int
+	for _, gvk := range childListKinds {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk)
+		if err := c.client.List(
+			ctx,
+			list,
+			client.InNamespace(promo.Namespace),
+			client.MatchingLabels{kargoapi.PromotionLabelKey: promo.Name},
+		); err != nil {
+			return 0, fmt.Errorf(
+				"error listing %s of Promotion %q in namespace %q: %w",
+				gvk.Kind, promo.Name, promo.Namespace, err,
+			)
+		}
+		for _, item := range list.Items {
+			// A label is no proof of ownership: a previous Promotion with the
+			// same name may have left objects behind that are yet to be
+			// garbage collected.
+			if item.Name != excludeName && metav1.IsControlledBy(&item, promo) {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestChildCreator_Create(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+
+	promo := &kargoapi.Promotion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-namespace",
+			Name:      "fake-promo",
+			UID:       "fake-uid",
+		},
+	}
+	// A ConfigMap left behind by a previous Promotion with the same name does
+	// not count towards the limit.
+	stale := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: promo.Namespace,
+			Name:      "stale",
+			Labels:    map[string]string{kargoapi.PromotionLabelKey: promo.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: kargoapi.GroupVersion.String(),
+				Kind:       "Promotion",
+				Name:       promo.Name,
+				UID:        "previous-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stale).Build()
+	creator := NewChildCreator(c, 2)
+
+	for _, name := range []string{"first", "second"} {
+		require.NoError(t, creator.Create(
+			context.Background(),
+			promo,
+			"fake-component",
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		))
+	}
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(
+		context.Background(),
+		types.NamespacedName{Namespace: promo.Namespace, Name: "first"},
+		cm,
+	))
+	require.Equal(
+		t,
+		map[string]string{
+			kargoapi.PromotionLabelKey: promo.Name,
+			kargoapi.ComponentLabelKey: "fake-component",
+		},
+		cm.Labels,
+	)
+	require.Len(t, cm.OwnerReferences, 1)
+	ownerRef := cm.OwnerReferences[0]
+	require.Equal(t, promo.UID, ownerRef.UID)
+	require.Equal(t, "Promotion", ownerRef.Kind)
+	// Deleting the Promotion with foreground propagation waits for its
+	// children to be deleted.
+	require.True(t, ptr.Deref(ownerRef.Controller, false))
+	require.True(t, ptr.Deref(ownerRef.BlockOwnerDeletion, false))
+
+	// Creating an object that already exists does not count towards the
+	// limit, so the error from creating it again is returned.
+	err := creator.Create(
+		context.Background(),
+		promo,
+		"fake-component",
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "second"}},
+	)
+	require.True(t, apierrors.IsAlreadyExists(err))
+
+	// Creating a new object exceeds the limit.
+	err = creator.Create(
+		context.Background(),
+		promo,
+		"fake-component",
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "third"}},
+	)
+	require.ErrorIs(t, err, ErrChildLimitExceeded)
+	require.ErrorContains(t, err, "already has 2 of at most 2 objects")
+	err = c.Get(
+		context.Background(),
+		types.NamespacedName{Namespace: promo.Namespace, Name: "third"},
+		&corev1.ConfigMap{},
+	)
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestChildCreator_CreateAs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+
+	promo := &kargoapi.Promotion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-namespace",
+			Name:      "fake-promo",
+			UID:       "fake-uid",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	creator := NewChildCreator(c, 2)
+
+	// A ConfigMap created by the ChildCreator itself counts towards the limit
+	// of objects created using another client.
+	require.NoError(t, creator.Create(
+		context.Background(),
+		promo,
+		"fake-component",
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "fake-configmap"}},
+	))
+	require.NoError(t, creator.CreateAs(
+		context.Background(),
+		c,
+		promo,
+		"fake-component",
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "first"}},
+	))
+
+	job := &batchv1.Job{}
+	require.NoError(t, c.Get(
+		context.Background(),
+		types.NamespacedName{Namespace: promo.Namespace, Name: "first"},
+		job,
+	))
+	require.Equal(t, promo.Name, job.Labels[kargoapi.PromotionLabelKey])
+	require.Len(t, job.OwnerReferences, 1)
+	ownerRef := job.OwnerReferences[0]
+	require.Equal(t, promo.UID, ownerRef.UID)
+	require.True(t, ptr.Deref(ownerRef.Controller, false))
+	require.False(t, ptr.Deref(ownerRef.BlockOwnerDeletion, false))
+
+	err := creator.CreateAs(
+		context.Background(),
+		c,
+		promo,
+		"fake-component",
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "second"}},
+	)
+	require.ErrorIs(t, err, ErrChildLimitExceeded)
+}
+
+func TestNewChildCreator(t *testing.T) {
+	require.Equal(t, DefaultMaxChildrenPerPromotion, NewChildCreator(nil, 0).maxChildren)
+	require.Equal(t, 3, NewChildCreator(nil, 3).maxChildren)
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// AuthMethod identifies the means by which a repository authenticates to its
+// remote.
+type AuthMethod string
+
+const (
+	// AuthMethodNone indicates that no credentials are used.
+	AuthMethodNone AuthMethod = "none"
+	// AuthMethodSSH indicates that an SSH private key is used.
+	AuthMethodSSH AuthMethod = "ssh"
+	// AuthMethodHTTPS indicates that a username and password (or token) are
+	// used over HTTPS.
+	AuthMethodHTTPS AuthMethod = "https"
+)
+
+// connectionErrorPatterns match output of git commands that failed because the
+// remote could not be reached at all, as opposed to e.g. because it rejected
+// the credentials that were presented.
+var connectionErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`ssh: connect to host .+ port \d+`),
+	regexp.MustCompile(`ssh: Could not resolve hostname`),
+	regexp.MustCompile(`[Cc]onnection (refused|timed out|reset by peer|closed by remote host)`),
+	regexp.MustCompile(`[Nn]etwork is unreachable`),
+	regexp.MustCompile(`[Nn]o route to host`),
+	regexp.MustCompile(`kex_exchange_identification`),
+}
+
+// isConnectionError returns true if the provided error was produced by a
+// command whose output indicates that the remote could not be reached.
+func isConnectionError(err error) bool {
+	var exitErr *libExec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	for _, regex := range connectionErrorPatterns {
+		if regex.Match(exitErr.Output) {
+			return true
+		}
+	}
+	return false
+}
+
+// execCloneCommand executes the clone command returned by buildCmd. If cloning
+// over SSH fails because the remote could not be reached, and the credentials
+// also include a password, cloning is retried once over HTTPS. In that case,
+// the repository is configured to continue using HTTPS for all subsequent
+// interactions with the remote, while its URL is left unchanged. The commands
+// returned by buildCmd are expected to be bound to the provided context.
+func (b *baseRepo) execCloneCommand(
+	ctx context.Context,
+	buildCmd func() *exec.Cmd,
+) error {
+	logger := logging.LoggerFromContext(context.Background()).WithValues("repo", b.url)
+	_, err := b.execNetworkCommandContext(ctx, buildCmd())
+	if err == nil {
+		logger.Debug("cloned repo", "authMethod", b.authMethod)
+		return nil
+	}
+	if b.authMethod != AuthMethodSSH || b.creds.Password == "" || !isConnectionError(err) {
+		return err
+	}
+	httpsURL, ok := libgit.SSHToHTTPSURL(b.url)
+	if !ok {
+		return err
+	}
+	logger.Info(
+		"could not reach repo over SSH; retrying over HTTPS",
+		"httpsURL", httpsURL,
+		"error", err.Error(),
+	)
+	if fallbackErr := b.useHTTPS(httpsURL); fallbackErr != nil {
+		return errors.Join(err, fallbackErr)
+	}
+	// Anything the failed attempt may have left behind would cause the next
+	// attempt to fail.
+	if rmErr := os.RemoveAll(b.dir); rmErr != nil {
+		return errors.Join(
+			err,
+			fmt.Errorf("error cleaning up after failed clone: %w", rmErr),
+		)
+	}
+	if _, httpsErr := b.execNetworkCommandContext(ctx, buildCmd()); httpsErr != nil {
+		return errors.Join(err, fmt.Errorf("error cloning over HTTPS: %w", httpsErr))
+	}
+	logger.Info("cloned repo", "authMethod", b.authMethod)
+	return nil
+}
+
+// useHTTPS configures the git CLI to interact with the remote at the provided
+// HTTPS URL, using the username and password from the repository's
+// credentials, whenever it is asked to interact with the repository's URL.
+func (b *baseRepo) useHTTPS(httpsURL string) error {
+	u, err := url.Parse(httpsURL)
+	if err != nil {
+		return fmt.Errorf("error parsing URL %q: %w", httpsURL, err)
+	}
+	if b.creds.Username != "" {
+		u.User = url.User(b.creds.Username)
+	}
+	cmd := b.buildGitCommand(
+		"config",
+		"--global",
+		fmt.Sprintf("url.%s.insteadOf", u.String()),
+		b.url,
+	)
+	cmd.Dir = b.homeDir // Override the cmd.Dir that's set by b.buildGitCommand()
+	if _, err = libExec.Exec(cmd); err != nil {
+		return fmt.Errorf("error configuring HTTPS URL for repo %q: %w", b.url, err)
+	}
+	b.authMethod = AuthMethodHTTPS
+	return nil
+}
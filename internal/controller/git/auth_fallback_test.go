@@ -0,0 +1,138 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+func Test_isConnectionError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name: "not an exit error",
+			err:  errors.New("Connection refused"),
+		},
+		{
+			name: "authentication failure",
+			err: &libExec.ExitError{
+				Output: []byte("git@github.com: Permission denied (publickey)."),
+			},
+		},
+		{
+			name: "port blocked",
+			err: &libExec.ExitError{
+				Output: []byte("ssh: connect to host github.com port 22: Connection timed out"),
+			},
+			expected: true,
+		},
+		{
+			name: "connection closed",
+			err: &libExec.ExitError{
+				Output: []byte("kex_exchange_identification: Connection closed by remote host"),
+			},
+			expected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, isConnectionError(testCase.err))
+		})
+	}
+}
+
+func Test_baseRepo_execCloneCommand(t *testing.T) {
+	const repoURL = "git@github.com:example/repo.git"
+
+	// failOverSSH returns a function that builds a command that fails the way a
+	// clone over SSH does when port 22 is blocked, for as long as the git CLI
+	// is not configured to use HTTPS instead.
+	failOverSSH := func(b *baseRepo, calls *int) func() *exec.Cmd {
+		return func() *exec.Cmd {
+			*calls++
+			cmd := b.buildCommand(
+				"sh", "-c",
+				`git config --global --get-regexp '^url\..*\.insteadof$' "^$0$" >/dev/null || `+
+					`{ echo "ssh: connect to host github.com port 22: Connection refused" >&2; exit 128; }; `+
+					`mkdir -p repo`,
+				repoURL,
+			)
+			cmd.Dir = b.homeDir
+			return cmd
+		}
+	}
+
+	newBaseRepo := func(t *testing.T, creds *RepoCredentials) *baseRepo {
+		homeDir := t.TempDir()
+		b := &baseRepo{
+			creds:   creds,
+			dir:     filepath.Join(homeDir, "repo"),
+			homeDir: homeDir,
+			url:     repoURL,
+		}
+		require.NoError(t, b.setupAuth())
+		return b
+	}
+
+	t.Run("falls back to HTTPS", func(t *testing.T) {
+		b := newBaseRepo(t, &RepoCredentials{
+			SSHPrivateKey: "fake-key",
+			Username:      "fake-user",
+			Password:      "fake-token",
+		})
+		require.Equal(t, AuthMethodSSH, b.AuthMethod())
+		// Something left behind by the failed attempt must not get in the way
+		require.NoError(t, os.MkdirAll(b.dir, 0700))
+
+		var calls int
+		require.NoError(t, b.execCloneCommand(context.Background(), failOverSSH(b, &calls)))
+		require.Equal(t, 2, calls)
+		require.Equal(t, AuthMethodHTTPS, b.AuthMethod())
+		// The URL is unchanged, but the git CLI will use HTTPS in its place
+		require.Equal(t, repoURL, b.URL())
+		cmd := b.buildGitCommand(
+			"config", "--global", "--get",
+			"url.https://fake-user@github.com/example/repo.git.insteadOf",
+		)
+		cmd.Dir = b.homeDir
+		res, err := libExec.Exec(cmd)
+		require.NoError(t, err)
+		require.Equal(t, repoURL, strings.TrimSpace(string(res)))
+	})
+
+	t.Run("no alternate credentials", func(t *testing.T) {
+		b := newBaseRepo(t, &RepoCredentials{SSHPrivateKey: "fake-key"})
+		var calls int
+		err := b.execCloneCommand(context.Background(), failOverSSH(b, &calls))
+		require.ErrorContains(t, err, "Connection refused")
+		require.Equal(t, 1, calls)
+		require.Equal(t, AuthMethodSSH, b.AuthMethod())
+	})
+
+	t.Run("not a connection error", func(t *testing.T) {
+		b := newBaseRepo(t, &RepoCredentials{
+			SSHPrivateKey: "fake-key",
+			Username:      "fake-user",
+			Password:      "fake-token",
+		})
+		var calls int
+		err := b.execCloneCommand(context.Background(), func() *exec.Cmd {
+			calls++
+			return b.buildCommand("sh", "-c", `echo "Permission denied (publickey)" >&2; exit 128`)
+		})
+		require.ErrorContains(t, err, "Permission denied")
+		require.Equal(t, 1, calls)
+		require.Equal(t, AuthMethodSSH, b.AuthMethod())
+	})
+}
@@ -3,9 +3,13 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -16,11 +20,29 @@ import (
 type BareRepo interface {
 	// AddWorkTree adds a working tree to the repository.
 	AddWorkTree(path string, opts *AddWorkTreeOptions) (WorkTree, error)
+	// AuthMethod returns the means by which the repository authenticated to the
+	// remote repository when it was cloned.
+	AuthMethod() AuthMethod
 	// Close cleans up file system resources used by this repository. This should
 	// always be called before a repository goes out of scope.
 	Close() error
+	// DefaultBranch returns the name of the default branch of the remote
+	// repository, as it was at the time of cloning. ErrRemoteBranchNotFound is
+	// returned if the branch was not cloned. Note that if the remote's HEAD did
+	// not point to a branch that exists, git may have fallen back to its own
+	// default branch name. Use ListRemoteHeads to ask the remote instead.
+	DefaultBranch() (string, error)
 	// Dir returns an absolute path to the repository.
 	Dir() string
+	// FetchRemoteBranch fetches the specified branch from the remote repository
+	// into a remote-tracking ref and returns the name of that ref, from which
+	// a working tree may be added. If the repository is shallow, only the
+	// branch's latest commit is fetched. An error wrapping
+	// ErrRemoteBranchNotFound is returned if the branch does not exist.
+	FetchRemoteBranch(branch string) (string, error)
+	// FetchRemoteBranchContext is like FetchRemoteBranch, but the git command
+	// it runs is killed if the provided context is done before it completes.
+	FetchRemoteBranchContext(ctx context.Context, branch string) (string, error)
 	// HomeDir returns an absolute path to the home directory of the system user
 	// who has cloned this repo.
 	HomeDir() string
@@ -69,6 +91,26 @@ type BareCloneOptions struct {
 	// should be ignored when cloning the repository. The setting will be
 	// remembered for subsequent interactions with the remote repository.
 	InsecureSkipTLSVerify bool
+	// Filter allows for partially cloning the repository by specifying a
+	// filter, e.g. "blob:none". When a filter is specified, the server will
+	// only send a subset of reachable objects according to the filter, and
+	// any other objects are fetched on demand, e.g. when files are checked out
+	// into a working tree. This is most useful in combination with sparse
+	// working trees.
+	//
+	// For more information, see:
+	// - https://git-scm.com/docs/git-clone#Documentation/git-clone.txt-code--filtercodeemltfilter-specgtem
+	// - https://git-scm.com/docs/partial-clone
+	Filter string
+	// Depth limits the history that is cloned to the specified number of
+	// commits. When it is specified, only the remote's default branch is
+	// cloned. Further branches fetched using FetchRemoteBranch are fetched to a
+	// depth of one commit. A value of 0 means no limit.
+	Depth uint
+	// MaxSize is the maximum number of bytes the clone may occupy on the file
+	// system. Cloning is aborted, and an error wrapping ErrRepoTooLarge is
+	// returned, as soon as the clone exceeds it. A value of 0 means no limit.
+	MaxSize int64
 }
 
 // CloneBare produces a local, bare clone of the remote Git repository at the
@@ -80,6 +122,17 @@ func CloneBare(
 	repoURL string,
 	clientOpts *ClientOptions,
 	cloneOpts *BareCloneOptions,
+) (BareRepo, error) {
+	return CloneBareContext(context.Background(), repoURL, clientOpts, cloneOpts)
+}
+
+// CloneBareContext is like CloneBare, but cloning is aborted if the provided
+// context is done before it completes.
+func CloneBareContext(
+	ctx context.Context,
+	repoURL string,
+	clientOpts *ClientOptions,
+	cloneOpts *BareCloneOptions,
 ) (BareRepo, error) {
 	if clientOpts == nil {
 		clientOpts = &ClientOptions{}
@@ -107,7 +160,17 @@ func CloneBare(
 	if err = b.setupClient(clientOpts); err != nil {
 		return nil, err
 	}
-	if err = b.clone(); err != nil {
+	if err = b.clone(ctx, cloneOpts); err != nil {
+		if !IsRepoTooLarge(err) {
+			return nil, err
+		}
+		// Do not leave a partial clone behind that is known to be large.
+		if rmErr := os.RemoveAll(homeDir); rmErr != nil {
+			return nil, errors.Join(
+				err,
+				fmt.Errorf("error cleaning up after failed clone: %w", rmErr),
+			)
+		}
 		return nil, err
 	}
 	if err = b.saveDirs(); err != nil {
@@ -116,10 +179,32 @@ func CloneBare(
 	return b, nil
 }
 
-func (b *bareRepo) clone() error {
-	cmd := b.buildGitCommand("clone", "--bare", b.url, b.dir)
-	cmd.Dir = b.homeDir // Override the cmd.Dir that's set by r.buildGitCommand()
-	if _, err := libExec.Exec(cmd); err != nil {
+func (b *bareRepo) clone(ctx context.Context, opts *BareCloneOptions) error {
+	args := []string{"clone", "--bare"}
+	if opts.Filter != "" {
+		args = append(args, "--filter", opts.Filter)
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", fmt.Sprint(opts.Depth))
+	}
+	args = append(args, b.url, b.dir)
+	var watcher *sizeWatcher
+	if opts.MaxSize > 0 {
+		ctx, watcher = watchSize(ctx, b.dir, opts.MaxSize)
+	}
+	err := b.execCloneCommand(ctx, func() *exec.Cmd {
+		cmd := commandWithContext(ctx, b.buildGitCommand(args...))
+		cmd.Dir = b.homeDir // Override the cmd.Dir that's set by r.buildGitCommand()
+		cmd.WaitDelay = killWaitDelay
+		return cmd
+	})
+	if watcher != nil && watcher.stop() {
+		return fmt.Errorf(
+			"%w: cloning repo %q was aborted because it exceeded %d bytes",
+			ErrRepoTooLarge, b.url, opts.MaxSize,
+		)
+	}
+	if err != nil {
 		return fmt.Errorf("error cloning repo %q into %q: %w", b.url, b.dir, err)
 	}
 	return nil
@@ -146,9 +231,15 @@ func LoadBareRepo(path string, opts *LoadBareRepoOptions) (BareRepo, error) {
 		return nil,
 			fmt.Errorf(`error reading URL of remote "origin" from config: %w`, err)
 	}
+	if err := b.loadInsecureNoAuth(); err != nil {
+		return nil, err
+	}
 	if err := b.setupAuth(); err != nil {
 		return nil, fmt.Errorf("error configuring the credentials: %w", err)
 	}
+	if err := b.loadAuthMethod(); err != nil {
+		return nil, err
+	}
 	return b, nil
 }
 
@@ -161,6 +252,17 @@ type AddWorkTreeOptions struct {
 	// Ref specifies the branch or commit to check out in the working tree. Will
 	// be ignored if Orphan is true.
 	Ref string
+	// Branch, if specified, is the name of a local branch to create at Ref, or
+	// to reset to Ref if it already exists, and check out in the working tree.
+	// This is useful when Ref is a remote-tracking ref. Will be ignored if
+	// Orphan is true.
+	Branch string
+	// SparsePaths, if specified, are the paths, relative to the root of the
+	// repository, of the directories to check out in the working tree. Files
+	// in all other directories are left out, except for files at the root of
+	// the repository and at the root of any parent directory of these paths.
+	// Will be ignored if Orphan is true.
+	SparsePaths []string
 }
 
 func (b *bareRepo) AddWorkTree(path string, opts *AddWorkTreeOptions) (WorkTree, error) {
@@ -178,11 +280,19 @@ func (b *bareRepo) AddWorkTree(path string, opts *AddWorkTreeOptions) (WorkTree,
 	if slices.Contains(workTreePaths, path) {
 		return nil, fmt.Errorf("working tree already exists at %q", path)
 	}
-	args := []string{"worktree", "add", path}
-	if opts.Orphan {
-		args = append(args, "--orphan")
-	} else {
-		args = append(args, opts.Ref)
+	sparse := len(opts.SparsePaths) > 0 && !opts.Orphan
+	args := []string{"worktree", "add"}
+	if sparse {
+		// Files are checked out once the sparse checkout has been configured.
+		args = append(args, "--no-checkout")
+	}
+	switch {
+	case opts.Orphan:
+		args = append(args, path, "--orphan")
+	case opts.Branch != "":
+		args = append(args, "-B", opts.Branch, path, opts.Ref)
+	default:
+		args = append(args, path, opts.Ref)
 	}
 	if _, err = libExec.Exec(b.buildGitCommand(args...)); err != nil {
 		return nil, fmt.Errorf("error adding working tree at %q: %w", path, err)
@@ -190,15 +300,76 @@ func (b *bareRepo) AddWorkTree(path string, opts *AddWorkTreeOptions) (WorkTree,
 	if path, err = filepath.EvalSymlinks(path); err != nil {
 		return nil, fmt.Errorf("error resolving symlinks in path %s: %w", path, err)
 	}
-	return &workTree{
+	w := &workTree{
 		baseRepo: &baseRepo{
-			creds:   b.creds,
-			dir:     path,
-			homeDir: b.homeDir,
-			url:     b.url,
+			creds:      b.creds,
+			authMethod: b.authMethod,
+			dir:        path,
+			homeDir:    b.homeDir,
+			url:        b.url,
 		},
 		bareRepo: b,
-	}, nil
+	}
+	if sparse {
+		if _, err = libExec.Exec(w.buildGitCommand(
+			append([]string{"sparse-checkout", "set", "--cone", "--"}, opts.SparsePaths...)...,
+		)); err != nil {
+			return nil, fmt.Errorf("error configuring sparse checkout of working tree at %q: %w", path, err)
+		}
+		// If the repository is a partial clone, this fetches the contents of the
+		// checked out files.
+		if _, err = w.execNetworkCommand(w.buildGitCommand("read-tree", "-mu", "HEAD")); err != nil {
+			return nil, fmt.Errorf("error checking out files into working tree at %q: %w", path, err)
+		}
+	}
+	return w, nil
+}
+
+// remoteRefNotFoundRegex matches the output of a git fetch command that failed
+// because the ref that was asked for does not exist in the remote repository.
+var remoteRefNotFoundRegex = regexp.MustCompile(`couldn't find remote ref`)
+
+func (b *bareRepo) FetchRemoteBranch(branch string) (string, error) {
+	return b.FetchRemoteBranchContext(context.Background(), branch)
+}
+
+func (b *bareRepo) FetchRemoteBranchContext(ctx context.Context, branch string) (string, error) {
+	trackingRef := "refs/remotes/origin/" + branch
+	args := []string{
+		"fetch",
+		"origin",
+		fmt.Sprintf("+refs/heads/%s:%s", branch, trackingRef),
+	}
+	shallow, err := b.isShallow()
+	if err != nil {
+		return "", err
+	}
+	if shallow {
+		args = append(args, "--depth", "1")
+	}
+	if _, err = b.execNetworkCommandContext(ctx, b.buildGitCommandContext(ctx, args...)); err != nil {
+		var exitErr *libExec.ExitError
+		if errors.As(err, &exitErr) && remoteRefNotFoundRegex.Match(exitErr.Output) {
+			return "", fmt.Errorf(
+				"branch %q of remote repo %q: %w", branch, b.url, ErrRemoteBranchNotFound,
+			)
+		}
+		return "", fmt.Errorf(
+			"error fetching branch %q from remote repo %q: %w", branch, b.url, err,
+		)
+	}
+	return trackingRef, nil
+}
+
+// isShallow returns true if the repository is a shallow clone.
+func (b *bareRepo) isShallow() (bool, error) {
+	res, err := libExec.Exec(b.buildGitCommand("rev-parse", "--is-shallow-repository"))
+	if err != nil {
+		return false, fmt.Errorf(
+			"error determining if repo %q is shallow: %w", b.url, err,
+		)
+	}
+	return strings.TrimSpace(string(res)) == "true", nil
 }
 
 func (b *bareRepo) Close() error {
@@ -214,6 +385,28 @@ func (b *bareRepo) Close() error {
 	return os.RemoveAll(b.homeDir)
 }
 
+func (b *bareRepo) DefaultBranch() (string, error) {
+	// In a bare clone, HEAD mirrors the HEAD of the remote repository.
+	res, err := libExec.Exec(b.buildGitCommand("symbolic-ref", "--short", "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf(
+			"error determining default branch of repo %q: %w", b.url, err,
+		)
+	}
+	branch := strings.TrimSpace(string(res))
+	// If the remote's HEAD did not point to a branch that exists, HEAD of the
+	// clone points to a branch that was not cloned.
+	if _, err = libExec.Exec(b.buildGitCommand(
+		"rev-parse", "--verify", "--quiet", "refs/heads/"+branch+"^{commit}",
+	)); err != nil {
+		return "", fmt.Errorf(
+			"default branch %q of remote repo %q was not cloned: %w",
+			branch, b.url, ErrRemoteBranchNotFound,
+		)
+	}
+	return branch, nil
+}
+
 func (b *bareRepo) RemoveWorkTree(path string) error {
 	workTreePaths, err := b.workTrees()
 	if err != nil {
@@ -242,10 +435,11 @@ func (b *bareRepo) WorkTrees() ([]WorkTree, error) {
 	for i, workTreePath := range workTreePaths {
 		workTrees[i] = &workTree{
 			baseRepo: &baseRepo{
-				creds:   b.creds,
-				dir:     workTreePath,
-				homeDir: b.homeDir,
-				url:     b.url,
+				creds:      b.creds,
+				authMethod: b.authMethod,
+				dir:        workTreePath,
+				homeDir:    b.homeDir,
+				url:        b.url,
 			},
 			bareRepo: b,
 		}
@@ -1,11 +1,14 @@
 package git
 
 import (
+	"crypto/rand"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/uuid"
@@ -13,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	libExec "github.com/akuity/kargo/internal/exec"
 	"github.com/akuity/kargo/internal/types"
 )
 
@@ -107,6 +111,14 @@ func TestBareRepo(t *testing.T) {
 		require.Equal(t, r.dir, r.Dir())
 	})
 
+	t.Run("can get the default branch", func(t *testing.T) {
+		branch, err := r.DefaultBranch()
+		require.NoError(t, err)
+		// "master" is still the default branch name for a new repository unless
+		// you configure it otherwise.
+		require.Equal(t, "master", branch)
+	})
+
 	workingTreePath := filepath.Join(rep.HomeDir(), "working-tree")
 	workTree, err := rep.AddWorkTree(
 		workingTreePath,
@@ -162,6 +174,272 @@ func TestBareRepo(t *testing.T) {
 	})
 }
 
+func Test_bareRepo_FetchRemoteBranch(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	var unavailable atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if unavailable.Load() {
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			service.ServeHTTP(w, r)
+		},
+	))
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, setupRep.AddAllAndCommit("initial commit"))
+	require.NoError(t, setupRep.Push(nil))
+
+	rep, err := CloneBare(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer rep.Close()
+
+	// This branch did not exist when the repo was cloned
+	require.NoError(t, setupRep.Push(&PushOptions{TargetBranch: "stage/dev"}))
+	expectedCommitID, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+
+	t.Run("branch exists", func(t *testing.T) {
+		exists, err := rep.RemoteBranchExists("stage/dev")
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		ref, err := rep.FetchRemoteBranch("stage/dev")
+		require.NoError(t, err)
+		require.Equal(t, "refs/remotes/origin/stage/dev", ref)
+
+		workTree, err := rep.AddWorkTree(
+			filepath.Join(t.TempDir(), "out"),
+			&AddWorkTreeOptions{Ref: ref, Branch: "stage/dev"},
+		)
+		require.NoError(t, err)
+		defer workTree.Close()
+		branch, err := workTree.CurrentBranch()
+		require.NoError(t, err)
+		require.Equal(t, "stage/dev", branch)
+		commitID, err := workTree.LastCommitID()
+		require.NoError(t, err)
+		require.Equal(t, expectedCommitID, commitID)
+	})
+
+	t.Run("branch missing", func(t *testing.T) {
+		// Only an exact match counts, not a branch whose name merely ends with
+		// the one that was asked for.
+		exists, err := rep.RemoteBranchExists("dev")
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		_, err = rep.FetchRemoteBranch("dev")
+		require.ErrorIs(t, err, ErrRemoteBranchNotFound)
+	})
+
+	t.Run("transient error", func(t *testing.T) {
+		require.NoError(t, SetNetworkRetries(NetworkRetryConfig{MaxAttempts: 1}))
+		unavailable.Store(true)
+		defer func() {
+			unavailable.Store(false)
+			require.NoError(t, SetNetworkRetries(NetworkRetryConfig{MaxAttempts: 3}))
+		}()
+
+		_, err := rep.RemoteBranchExists("stage/dev")
+		require.ErrorContains(t, err, "503")
+
+		_, err = rep.FetchRemoteBranch("stage/dev")
+		require.ErrorContains(t, err, "503")
+		require.NotErrorIs(t, err, ErrRemoteBranchNotFound)
+	})
+}
+
+func TestCloneBare_maxSize(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	// Random data does not compress, so the clone is at least this large.
+	data := make([]byte, 512*1024)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "blob.bin"), data, 0600)
+	require.NoError(t, err)
+	require.NoError(t, setupRep.AddAllAndCommit("initial commit"))
+	require.NoError(t, setupRep.Push(nil))
+
+	t.Run("within limit", func(t *testing.T) {
+		rep, err := CloneBare(
+			testRepoURL,
+			nil,
+			&BareCloneOptions{BaseDir: t.TempDir(), MaxSize: 10 * 1024 * 1024},
+		)
+		require.NoError(t, err)
+		require.NoError(t, rep.Close())
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		baseDir := t.TempDir()
+		_, err := CloneBare(
+			testRepoURL,
+			nil,
+			&BareCloneOptions{BaseDir: baseDir, MaxSize: 64 * 1024},
+		)
+		require.ErrorIs(t, err, ErrRepoTooLarge)
+		require.True(t, IsRepoTooLarge(err))
+		// The partial clone was cleaned up
+		entries, err := os.ReadDir(baseDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
+func Test_bareRepo_DefaultBranch(t *testing.T) {
+	serverDir := t.TempDir()
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        serverDir,
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "README.md"), []byte{}, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, setupRep.AddAllAndCommit("initial commit"))
+	require.NoError(t, setupRep.Push(nil))
+	require.NoError(t, setupRep.CreateChildBranch("env-config"))
+	require.NoError(t, setupRep.Push(&PushOptions{TargetBranch: "env-config"}))
+
+	setRemoteHead := func(t *testing.T, branch string) {
+		require.NoError(t, os.WriteFile(
+			filepath.Join(serverDir, "test.git", "HEAD"),
+			[]byte("ref: refs/heads/"+branch+"\n"),
+			0o600,
+		))
+	}
+
+	t.Run("follows the remote's HEAD", func(t *testing.T) {
+		setRemoteHead(t, "env-config")
+		rep, err := CloneBare(testRepoURL, nil, &BareCloneOptions{BaseDir: t.TempDir()})
+		require.NoError(t, err)
+		defer rep.Close()
+		branch, err := rep.DefaultBranch()
+		require.NoError(t, err)
+		require.Equal(t, "env-config", branch)
+	})
+}
+
+func Test_dirSize(t *testing.T) {
+	dir := t.TempDir()
+	require.Zero(t, dirSize(filepath.Join(dir, "nonexistent")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), make([]byte, 10), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 5), 0600))
+	require.Equal(t, int64(15), dirSize(dir))
+}
+
+func Test_bareRepo_AddWorkTree_sparse(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	for _, file := range []string{
+		"README.md",
+		"apps/guestbook/kustomization.yaml",
+		"base/deployment.yaml",
+		"other/large.bin",
+	} {
+		p := filepath.Join(setupRep.Dir(), file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o700))
+		require.NoError(t, os.WriteFile(p, []byte(file), 0o600))
+	}
+	require.NoError(t, setupRep.AddAllAndCommit("initial commit"))
+	require.NoError(t, setupRep.Push(nil))
+
+	rep, err := CloneBare(testRepoURL, nil, &BareCloneOptions{Filter: "blob:none"})
+	require.NoError(t, err)
+	defer rep.Close()
+	defaultBranch, err := rep.DefaultBranch()
+	require.NoError(t, err)
+
+	sparseTree, err := rep.AddWorkTree(
+		filepath.Join(t.TempDir(), "src"),
+		&AddWorkTreeOptions{Ref: defaultBranch, SparsePaths: []string{"apps/guestbook"}},
+	)
+	require.NoError(t, err)
+	defer sparseTree.Close()
+
+	// Only the sparse paths and files at the root are checked out
+	assert.FileExists(t, filepath.Join(sparseTree.Dir(), "README.md"))
+	assert.FileExists(t, filepath.Join(sparseTree.Dir(), "apps", "guestbook", "kustomization.yaml"))
+	assert.NoDirExists(t, filepath.Join(sparseTree.Dir(), "base"))
+	assert.NoDirExists(t, filepath.Join(sparseTree.Dir(), "other"))
+	hasDiffs, err := sparseTree.HasDiffs()
+	require.NoError(t, err)
+	assert.False(t, hasDiffs)
+
+	require.NoError(t, sparseTree.AddSparseCheckoutPaths("base"))
+	assert.FileExists(t, filepath.Join(sparseTree.Dir(), "base", "deployment.yaml"))
+	assert.NoDirExists(t, filepath.Join(sparseTree.Dir(), "other"))
+
+	// Clearing a sparse working tree removes files outside of the sparse
+	// checkout as well
+	require.NoError(t, sparseTree.Clear(&ClearOptions{Keep: []string{"README.md"}}))
+	res, err := libExec.Exec(sparseTree.(*workTree).buildGitCommand("ls-files")) // nolint: forcetypeassert
+	require.NoError(t, err)
+	assert.Equal(t, "README.md\n", string(res))
+
+	// Working trees that are not sparse cannot be extended
+	fullWorkTree, err := rep.AddWorkTree(
+		filepath.Join(t.TempDir(), "full"),
+		&AddWorkTreeOptions{Ref: defaultBranch, Branch: "full"},
+	)
+	require.NoError(t, err)
+	defer fullWorkTree.Close()
+	assert.FileExists(t, filepath.Join(fullWorkTree.Dir(), "other", "large.bin"))
+	require.ErrorContains(t, fullWorkTree.AddSparseCheckoutPaths("base"), "not a sparse checkout")
+}
+
 func Test_bareRepo_parseWorkTreeOutput(t *testing.T) {
 	tests := []struct {
 		name       string
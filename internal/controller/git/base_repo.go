@@ -1,15 +1,19 @@
 package git
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode"
 
 	libExec "github.com/akuity/kargo/internal/exec"
+	libgit "github.com/akuity/kargo/internal/git"
 )
 
 const (
@@ -21,10 +25,12 @@ const (
 // single working tree, a bare repository, or working tree associated with a
 // bare repository.
 type baseRepo struct {
-	creds   *RepoCredentials
-	dir     string
-	homeDir string
-	url     string
+	creds          *RepoCredentials
+	insecureNoAuth bool
+	authMethod     AuthMethod
+	dir            string
+	homeDir        string
+	url            string
 }
 
 // ClientOptions represents options for a repository-specific Git client.
@@ -38,6 +44,12 @@ type ClientOptions struct {
 	// InsecureSkipTLSVerify indicates whether to ignore certificate verification
 	// errors when interacting with the remote repository.
 	InsecureSkipTLSVerify bool
+	// InsecureNoAuth indicates that the remote repository does not require
+	// authentication, in which case any Credentials are ignored. This is
+	// recorded in the repository's configuration, so that it also applies when
+	// the repository is later loaded from the file system. Repositories on the
+	// local file system never use credentials, regardless of this option.
+	InsecureNoAuth bool
 }
 
 // setupClient configures the git CLI for authentication using either SSH or
@@ -51,6 +63,8 @@ func (b *baseRepo) setupClient(opts *ClientOptions) error {
 		return fmt.Errorf("error configuring the author: %w", err)
 	}
 
+	b.insecureNoAuth = opts.InsecureNoAuth
+
 	if err := b.setupAuth(); err != nil {
 		return fmt.Errorf("error configuring the credentials: %w", err)
 	}
@@ -79,6 +93,19 @@ type User struct {
 	SigningKeyPath string
 }
 
+// nameAndEmail returns the name and email of the User, or the defaults if
+// they are not specified.
+func (u *User) nameAndEmail() (string, string) {
+	name, email := u.Name, u.Email
+	if name == "" {
+		name = defaultUsername
+	}
+	if email == "" {
+		email = defaultEmail
+	}
+	return name, email
+}
+
 // setupAuthor configures the git CLI with a default commit author.
 // Optionally, the author can have an associated signing key. When using GPG
 // signing, the name and email must match the GPG key identity.
@@ -125,11 +152,19 @@ func (b *baseRepo) setupAuthor(author *User) error {
 }
 
 func (b *baseRepo) setupAuth() error {
+	// Credentials are never used for repositories that do not require them.
+	// Discarding them here also ensures that no credential helper is ever
+	// configured for such repositories.
+	if b.insecureNoAuth || libgit.IsLocalURL(b.url) {
+		b.creds = nil
+	}
 	if b.creds == nil {
+		b.authMethod = AuthMethodNone
 		return nil
 	}
 	// If an SSH key was provided, use that.
 	if b.creds.SSHPrivateKey != "" {
+		b.authMethod = AuthMethodSSH
 		sshPath := filepath.Join(b.homeDir, ".ssh")
 		if err := os.MkdirAll(sshPath, 0700); err != nil {
 			return fmt.Errorf("error creating SSH directory %q: %w", sshPath, err)
@@ -155,7 +190,21 @@ func (b *baseRepo) setupAuth() error {
 
 	// If no password is specified, we're done'.
 	if b.creds.Password == "" {
+		b.authMethod = AuthMethodNone
+		return nil
+	}
+	b.authMethod = AuthMethodHTTPS
+
+	switch b.creds.HTTPAuthMode {
+	case HTTPAuthModeBearer:
+		// The token is presented by buildGitCommand. As a username is of no use
+		// here, the URL is left as is.
 		return nil
+	case HTTPAuthModeNetrc:
+		return b.writeNetrc()
+	case "", HTTPAuthModeBasic:
+	default:
+		return fmt.Errorf("unknown HTTP auth mode %q", b.creds.HTTPAuthMode)
 	}
 
 	lowerURL := strings.ToLower(b.url)
@@ -171,6 +220,35 @@ func (b *baseRepo) setupAuth() error {
 	return nil
 }
 
+// writeNetrc writes the username and password from the repository's
+// credentials to a .netrc file in the repository's home directory, from which
+// git reads them whenever the remote asks for credentials. As the tokens of a
+// .netrc file are separated by whitespace and cannot be quoted, a username or
+// password that contains whitespace is rejected rather than written.
+func (b *baseRepo) writeNetrc() error {
+	u, err := url.Parse(b.url)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("error determining host of URL %q for .netrc file", b.url)
+	}
+	if strings.IndexFunc(b.creds.Username, unicode.IsSpace) >= 0 ||
+		strings.IndexFunc(b.creds.Password, unicode.IsSpace) >= 0 {
+		return errors.New(
+			"username and password must not contain whitespace to be written to a .netrc file",
+		)
+	}
+	var sb strings.Builder
+	sb.WriteString("machine " + u.Hostname())
+	if b.creds.Username != "" {
+		sb.WriteString(" login " + b.creds.Username)
+	}
+	sb.WriteString(" password " + b.creds.Password + "\n")
+	netrcPath := filepath.Join(b.homeDir, ".netrc")
+	if err = os.WriteFile(netrcPath, []byte(sb.String()), 0600); err != nil {
+		return fmt.Errorf("error writing .netrc file to %q: %w", netrcPath, err)
+	}
+	return nil
+}
+
 // saveDirs saves information about the repository's directories to the
 // repository's configuration. This is useful for reliably determining this
 // information later if an existing repository or working tree is loaded from
@@ -190,6 +268,22 @@ func (b *baseRepo) saveDirs() error {
 	)); err != nil {
 		return fmt.Errorf("error saving repo home dir as config: %w", err)
 	}
+	if b.insecureNoAuth {
+		if _, err := libExec.Exec(b.buildGitCommand(
+			"config",
+			"kargo.insecureNoAuth",
+			"true",
+		)); err != nil {
+			return fmt.Errorf("error saving insecure no auth setting as config: %w", err)
+		}
+	}
+	if _, err := libExec.Exec(b.buildGitCommand(
+		"config",
+		"kargo.authMethod",
+		string(b.authMethod),
+	)); err != nil {
+		return fmt.Errorf("error saving auth method as config: %w", err)
+	}
 	return nil
 }
 
@@ -217,6 +311,45 @@ func (b *baseRepo) loadURL() error {
 	return nil
 }
 
+// loadInsecureNoAuth restores the setting indicating whether the remote
+// repository requires authentication from the repository's configuration.
+func (b *baseRepo) loadInsecureNoAuth() error {
+	res, err := libExec.Exec(b.buildGitCommand("config", "--bool", "kargo.insecureNoAuth"))
+	if err != nil {
+		// Exit code 1 indicates the setting is not present
+		var exitErr *libExec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode == 1 {
+			b.insecureNoAuth = false
+			return nil
+		}
+		return fmt.Errorf("error reading insecure no auth setting from config: %w", err)
+	}
+	b.insecureNoAuth = strings.TrimSpace(string(res)) == "true"
+	return nil
+}
+
+// loadAuthMethod restores the means by which the repository authenticated to
+// its remote when it was cloned from the repository's configuration. This
+// matters because a repository that was cloned over HTTPS after cloning over
+// SSH failed continues to use HTTPS, whatever its credentials would suggest.
+// If the setting is not present, the means suggested by the credentials is
+// retained.
+func (b *baseRepo) loadAuthMethod() error {
+	res, err := libExec.Exec(b.buildGitCommand("config", "kargo.authMethod"))
+	if err != nil {
+		// Exit code 1 indicates the setting is not present
+		var exitErr *libExec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode == 1 {
+			return nil
+		}
+		return fmt.Errorf("error reading auth method from config: %w", err)
+	}
+	if method := strings.TrimSpace(string(res)); method != "" {
+		b.authMethod = AuthMethod(method)
+	}
+	return nil
+}
+
 func (b *baseRepo) buildCommand(command string, arg ...string) *exec.Cmd {
 	cmd := exec.Command(command, arg...)
 	homeEnvVar := fmt.Sprintf("HOME=%s", b.homeDir)
@@ -229,19 +362,113 @@ func (b *baseRepo) buildCommand(command string, arg ...string) *exec.Cmd {
 	return cmd
 }
 
+// buildGitCommandContext is like buildGitCommand, but the command is killed if
+// the provided context is done before it completes.
+func (b *baseRepo) buildGitCommandContext(ctx context.Context, arg ...string) *exec.Cmd {
+	cmd := commandWithContext(ctx, b.buildGitCommand(arg...))
+	cmd.WaitDelay = killWaitDelay
+	return cmd
+}
+
 func (b *baseRepo) buildGitCommand(arg ...string) *exec.Cmd {
 	cmd := b.buildCommand("git", arg...)
-	cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_SSH_COMMAND=ssh -F %s/.ssh/config", b.homeDir))
+	cmd.Env = append(
+		cmd.Env,
+		fmt.Sprintf("GIT_SSH_COMMAND=ssh -F %s/.ssh/config", b.homeDir),
+		// Never prompt for credentials, e.g. if none were found or those that
+		// were found were rejected, since nobody would ever answer. This makes
+		// git fail instead, with output that identifies the cause.
+		"GIT_TERMINAL_PROMPT=0",
+		"GCM_INTERACTIVE=never",
+	)
 	if b.creds != nil && b.creds.Password != "" {
-		cmd.Env = append(
-			cmd.Env,
-			"GIT_ASKPASS=/usr/local/bin/credential-helper",
-			fmt.Sprintf("GIT_PASSWORD=%s", b.creds.Password),
-		)
+		switch b.creds.HTTPAuthMode {
+		case HTTPAuthModeBearer:
+			// The header is scoped to the repository's own URL, so it is never
+			// sent to any other remote, e.g. that of a submodule.
+			cmd.Env = withGitConfig(
+				cmd.Env,
+				fmt.Sprintf("http.%s.extraHeader", extraHeaderScope(b.url)),
+				"Authorization: Bearer "+b.creds.Password,
+			)
+		case HTTPAuthModeNetrc:
+			// git reads the credentials from the .netrc file in the home
+			// directory.
+		default:
+			cmd.Env = append(
+				cmd.Env,
+				"GIT_ASKPASS=/usr/local/bin/credential-helper",
+				fmt.Sprintf("GIT_PASSWORD=%s", b.creds.Password),
+			)
+		}
 	}
 	return cmd
 }
 
+// withGitConfig returns the provided environment of a git command with
+// variables added that set the provided configuration key to the provided
+// value for that command only. Configuration passed this way never appears in
+// the repository's configuration or in command line arguments.
+func withGitConfig(env []string, key, value string) []string {
+	count := 0
+	countIdx := -1
+	for i, e := range env {
+		if v, ok := strings.CutPrefix(e, "GIT_CONFIG_COUNT="); ok {
+			count, _ = strconv.Atoi(v)
+			countIdx = i
+		}
+	}
+	env = append(
+		env,
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", count, key),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, value),
+	)
+	countVar := fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1)
+	if countIdx >= 0 {
+		env[countIdx] = countVar
+	} else {
+		env = append(env, countVar)
+	}
+	return env
+}
+
+// extraHeaderScope returns the URL that an http.<url>.extraHeader setting for
+// the remote with the provided URL is scoped to, which is the URL without any
+// user information, query, or fragment. git only sends the header with
+// requests to that URL and to URLs beneath it.
+func extraHeaderScope(remoteURL string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return remoteURL
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return strings.TrimSuffix(u.String(), "/")
+}
+
+// execNetworkCommand executes a git command that communicates with the remote
+// repository once any limits configured for the remote's host permit it.
+// Failures that appear to be transient are retried. Every attempt is recorded
+// in metrics.
+func (b *baseRepo) execNetworkCommand(cmd *exec.Cmd) ([]byte, error) {
+	return networkRetries.Load().exec(cmd, b.url)
+}
+
+// execNetworkCommandContext is like execNetworkCommand, but the command,
+// which is expected to have been bound to the provided context, is neither
+// retried nor allowed to keep running once the context is done.
+func (b *baseRepo) execNetworkCommandContext(
+	ctx context.Context,
+	cmd *exec.Cmd,
+) ([]byte, error) {
+	return networkRetries.Load().execContext(ctx, cmd, b.url)
+}
+
+func (b *baseRepo) AuthMethod() AuthMethod {
+	return b.authMethod
+}
+
 func (b *baseRepo) Dir() string {
 	return b.dir
 }
@@ -251,18 +478,23 @@ func (b *baseRepo) HomeDir() string {
 }
 
 func (b *baseRepo) RemoteBranchExists(branch string) (bool, error) {
-	_, err := libExec.Exec(b.buildGitCommand(
+	return b.remoteBranchExists(context.Background(), branch)
+}
+
+// remoteBranchExists is like RemoteBranchExists, but the git command it runs
+// is killed if the provided context is done before it completes.
+func (b *baseRepo) remoteBranchExists(ctx context.Context, branch string) (bool, error) {
+	// Rather than relying on --exit-code, whose exit code for a missing branch
+	// cannot be told apart from that of other failures by all versions of git,
+	// any failure is treated as such and the output is inspected instead.
+	ref := "refs/heads/" + branch
+	res, err := b.execNetworkCommandContext(ctx, b.buildGitCommandContext(
+		ctx,
 		"ls-remote",
 		"--heads",
-		"--exit-code", // Return 2 if not found
 		b.url,
-		branch,
+		ref,
 	))
-	var exitErr *libExec.ExitError
-	if errors.As(err, &exitErr) && exitErr.ExitCode == 2 {
-		// Branch does not exist
-		return false, nil
-	}
 	if err != nil {
 		return false, fmt.Errorf(
 			"error checking for existence of branch %q in remote repo %q: %w",
@@ -271,7 +503,14 @@ func (b *baseRepo) RemoteBranchExists(branch string) (bool, error) {
 			err,
 		)
 	}
-	return true, nil
+	// Output is of the form "<sha>\trefs/heads/<branch>" for every branch whose
+	// name ends with the pattern, so an exact match must be looked for.
+	for _, line := range strings.Split(strings.TrimSpace(string(res)), "\n") {
+		if _, lineRef, found := strings.Cut(line, "\t"); found && lineRef == ref {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (b *baseRepo) URL() string {
@@ -0,0 +1,321 @@
+package git
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+func Test_withGitConfig(t *testing.T) {
+	env := withGitConfig([]string{"HOME=/tmp/home"}, "http.sslVerify", "false")
+	require.Equal(
+		t,
+		[]string{
+			"HOME=/tmp/home",
+			"GIT_CONFIG_KEY_0=http.sslVerify",
+			"GIT_CONFIG_VALUE_0=false",
+			"GIT_CONFIG_COUNT=1",
+		},
+		env,
+	)
+	env = withGitConfig(env, "core.askPass", "")
+	require.Equal(
+		t,
+		[]string{
+			"HOME=/tmp/home",
+			"GIT_CONFIG_KEY_0=http.sslVerify",
+			"GIT_CONFIG_VALUE_0=false",
+			"GIT_CONFIG_COUNT=2",
+			"GIT_CONFIG_KEY_1=core.askPass",
+			"GIT_CONFIG_VALUE_1=",
+		},
+		env,
+	)
+}
+
+func Test_extraHeaderScope(t *testing.T) {
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "https://git.example.com/org/repo.git",
+			expected: "https://git.example.com/org/repo.git",
+		},
+		{
+			url:      "https://kargo@git.example.com:8443/org/repo/?x=y#z",
+			expected: "https://git.example.com:8443/org/repo",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.url, func(t *testing.T) {
+			require.Equal(t, testCase.expected, extraHeaderScope(testCase.url))
+		})
+	}
+}
+
+// authRecorder is an HTTP handler that rejects every request after recording
+// the Authorization header it was made with, if any.
+type authRecorder struct {
+	mu      sync.Mutex
+	headers []string
+}
+
+func (a *authRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.headers = append(a.headers, r.Header.Get("Authorization"))
+	a.mu.Unlock()
+	w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func (a *authRecorder) received(header string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, h := range a.headers {
+		if h == header {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_baseRepo_setupAuth(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		creds      *RepoCredentials
+		assertions func(t *testing.T, b *baseRepo, homeDir string)
+	}{
+		{
+			name: "no credentials",
+			url:  "https://github.com/example/repo.git",
+			assertions: func(t *testing.T, b *baseRepo, homeDir string) {
+				require.Equal(t, AuthMethodNone, b.authMethod)
+				entries, err := os.ReadDir(homeDir)
+				require.NoError(t, err)
+				require.Empty(t, entries)
+			},
+		},
+		{
+			name: "credentials are discarded for local repository",
+			url:  "file:///tmp/repo.git",
+			creds: &RepoCredentials{
+				SSHPrivateKey: "fake-key",
+			},
+			assertions: func(t *testing.T, b *baseRepo, homeDir string) {
+				require.Equal(t, AuthMethodNone, b.authMethod)
+				require.Nil(t, b.creds)
+				require.NoDirExists(t, filepath.Join(homeDir, ".ssh"))
+			},
+		},
+		{
+			name: "SSH key",
+			url:  "ssh://git@github.com/example/repo.git",
+			creds: &RepoCredentials{
+				SSHPrivateKey: "fake-key",
+			},
+			assertions: func(t *testing.T, b *baseRepo, homeDir string) {
+				require.Equal(t, AuthMethodSSH, b.authMethod)
+				keyPath := filepath.Join(homeDir, ".ssh", "id_rsa")
+				key, err := os.ReadFile(keyPath)
+				require.NoError(t, err)
+				require.Equal(t, "fake-key", string(key))
+				info, err := os.Stat(keyPath)
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+				sshConfig, err := os.ReadFile(filepath.Join(homeDir, ".ssh", "config"))
+				require.NoError(t, err)
+				require.Contains(t, string(sshConfig), fmt.Sprintf("IdentityFile %q", keyPath))
+			},
+		},
+		{
+			name: "basic auth",
+			url:  "https://github.com/example/repo.git",
+			creds: &RepoCredentials{
+				Username: "kargo",
+				Password: "fake-password",
+			},
+			assertions: func(t *testing.T, b *baseRepo, homeDir string) {
+				require.Equal(t, AuthMethodHTTPS, b.authMethod)
+				require.Equal(t, "https://kargo@github.com/example/repo.git", b.url)
+				// The password is passed to git through the environment of its
+				// commands and never written to the home directory.
+				require.NoFileExists(t, filepath.Join(homeDir, ".netrc"))
+			},
+		},
+		{
+			name: "netrc",
+			url:  "https://github.com/example/repo.git",
+			creds: &RepoCredentials{
+				Username:     "kargo",
+				Password:     "fake-password",
+				HTTPAuthMode: HTTPAuthModeNetrc,
+			},
+			assertions: func(t *testing.T, b *baseRepo, homeDir string) {
+				require.Equal(t, AuthMethodHTTPS, b.authMethod)
+				netrc, err := os.ReadFile(filepath.Join(homeDir, ".netrc"))
+				require.NoError(t, err)
+				require.Equal(t, "machine github.com login kargo password fake-password\n", string(netrc))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			b := &baseRepo{
+				creds:   testCase.creds,
+				homeDir: homeDir,
+				url:     testCase.url,
+			}
+			require.NoError(t, b.setupAuth())
+			testCase.assertions(t, b, homeDir)
+		})
+	}
+}
+
+func Test_baseRepo_setupAuth_invalidCredentials(t *testing.T) {
+	testCases := []struct {
+		name  string
+		creds *RepoCredentials
+		err   string
+	}{
+		{
+			name: "unknown HTTP auth mode",
+			creds: &RepoCredentials{
+				Username:     "kargo",
+				Password:     "fake-password",
+				HTTPAuthMode: "Bearer",
+			},
+			err: `unknown HTTP auth mode "Bearer"`,
+		},
+		{
+			name: "netrc password with whitespace",
+			creds: &RepoCredentials{
+				Username:     "kargo",
+				Password:     "fake-password\nmachine evil.example.com",
+				HTTPAuthMode: HTTPAuthModeNetrc,
+			},
+			err: "must not contain whitespace",
+		},
+		{
+			name: "netrc username with whitespace",
+			creds: &RepoCredentials{
+				Username:     "kargo admin",
+				Password:     "fake-password",
+				HTTPAuthMode: HTTPAuthModeNetrc,
+			},
+			err: "must not contain whitespace",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			b := &baseRepo{
+				creds:   testCase.creds,
+				homeDir: homeDir,
+				url:     "https://github.com/example/repo.git",
+			}
+			require.ErrorContains(t, b.setupAuth(), testCase.err)
+			require.NoFileExists(t, filepath.Join(homeDir, ".netrc"))
+		})
+	}
+}
+
+func TestHTTPAuthModes(t *testing.T) {
+	const testSecret = "s3cr3t-t0k3n"
+	testCases := []struct {
+		name       string
+		creds      *RepoCredentials
+		assertions func(t *testing.T, recorder *authRecorder, homeDir string)
+	}{
+		{
+			name: "bearer",
+			creds: &RepoCredentials{
+				Password:     testSecret,
+				HTTPAuthMode: HTTPAuthModeBearer,
+			},
+			assertions: func(t *testing.T, recorder *authRecorder, homeDir string) {
+				require.True(t, recorder.received("Bearer "+testSecret))
+				// Nothing that contains the token is written to disk.
+				require.NoFileExists(t, filepath.Join(homeDir, ".netrc"))
+				gitConfig, err := os.ReadFile(filepath.Join(homeDir, ".gitconfig"))
+				require.NoError(t, err)
+				require.NotContains(t, string(gitConfig), testSecret)
+			},
+		},
+		{
+			name: "netrc",
+			creds: &RepoCredentials{
+				Username:     "kargo",
+				Password:     testSecret,
+				HTTPAuthMode: HTTPAuthModeNetrc,
+			},
+			assertions: func(t *testing.T, recorder *authRecorder, homeDir string) {
+				require.True(t, recorder.received(
+					"Basic "+base64.StdEncoding.EncodeToString([]byte("kargo:"+testSecret)),
+				))
+				info, err := os.Stat(filepath.Join(homeDir, ".netrc"))
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := &authRecorder{}
+			server := httptest.NewServer(recorder)
+			defer server.Close()
+			baseDir := t.TempDir()
+			transcript := libExec.NewTranscript(baseDir)
+			defer transcript.Track()()
+
+			_, err := CloneBare(
+				server.URL+"/org/repo.git",
+				&ClientOptions{Credentials: testCase.creds},
+				&BareCloneOptions{BaseDir: baseDir},
+			)
+			require.Error(t, err)
+			require.NotContains(t, err.Error(), testSecret)
+			for _, entry := range transcript.Entries() {
+				require.NotContains(t, entry.Command, testSecret)
+				require.NotContains(t, entry.Output, testSecret)
+			}
+			homeDirs, err := filepath.Glob(filepath.Join(baseDir, "repo-*"))
+			require.NoError(t, err)
+			require.Len(t, homeDirs, 1)
+			testCase.assertions(t, recorder, homeDirs[0])
+		})
+	}
+}
+
+func TestHTTPAuthModeBearer_scopedToRemote(t *testing.T) {
+	recorder := &authRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+	b := &baseRepo{
+		creds:   &RepoCredentials{Password: "fake-token", HTTPAuthMode: HTTPAuthModeBearer},
+		homeDir: t.TempDir(),
+		url:     server.URL + "/org/repo.git",
+	}
+	require.NoError(t, b.setupAuth())
+	// No username is added to the URL.
+	require.Equal(t, server.URL+"/org/repo.git", b.url)
+	// The header is not sent to another repository on the same host.
+	_, err := libExec.Exec(b.buildGitCommand("ls-remote", server.URL+"/org/other.git"))
+	require.Error(t, err)
+	require.False(t, recorder.received("Bearer fake-token"))
+	// But it is sent to the repository itself.
+	_, err = libExec.Exec(b.buildGitCommand("ls-remote", b.url))
+	require.Error(t, err)
+	require.True(t, recorder.received("Bearer fake-token"))
+}
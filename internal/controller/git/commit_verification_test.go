@@ -0,0 +1,219 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkTree_VerifyCommitSignature(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	rep, err := Clone(remoteDir, nil, nil)
+	require.NoError(t, err)
+	defer rep.Close()
+
+	run := func(t *testing.T, env []string, name string, args ...string) string {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = rep.Dir()
+		cmd.Env = append([]string{"HOME=" + rep.HomeDir()}, env...)
+		res, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(res))
+		return strings.TrimSpace(string(res))
+	}
+	commit := func(t *testing.T, signArgs ...string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(rep.Dir(), uuid.NewString()), []byte("foo"), 0o600))
+		run(t, nil, "git", "add", ".")
+		run(t, gpgEnv(t), "git", append(signArgs, "commit", "-m", "commit")...)
+		return run(t, nil, "git", "rev-parse", "HEAD")
+	}
+
+	// An SSH signing key and the allowed signers it belongs to.
+	sshKeyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	run(t, nil, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", sshKeyPath)
+	sshPublicKey, err := os.ReadFile(sshKeyPath + ".pub")
+	require.NoError(t, err)
+	allowedSigners := "alice@example.com " + string(sshPublicKey)
+	sshSign := []string{"-c", "gpg.format=ssh", "-c", "user.signingKey=" + sshKeyPath, "-c", "commit.gpgSign=true"}
+
+	// A GPG signing key.
+	run(t, gpgEnv(t), "gpg", "--batch", "--passphrase", "",
+		"--quick-gen-key", "Bob <bob@example.com>", "ed25519", "sign", "never")
+	gpgPublicKeys := run(t, gpgEnv(t), "gpg", "--armor", "--export", "bob@example.com")
+	gpgSign := []string{"-c", "user.signingKey=bob@example.com", "-c", "commit.gpgSign=true"}
+
+	unsigned := commit(t)
+	sshSigned := commit(t, sshSign...)
+	gpgSigned := commit(t, gpgSign...)
+
+	keys := &VerifyCommitSignatureOptions{
+		GPGPublicKeys:     gpgPublicKeys,
+		SSHAllowedSigners: allowedSigners,
+	}
+
+	t.Run("unsigned", func(t *testing.T) {
+		sig, err := rep.VerifyCommitSignature(unsigned, keys)
+		require.NoError(t, err)
+		require.Equal(t, unsigned, sig.Commit)
+		require.Equal(t, SignatureStatusUnsigned, sig.Status)
+	})
+
+	t.Run("signed with allowed SSH key", func(t *testing.T) {
+		sig, err := rep.VerifyCommitSignature(sshSigned, keys)
+		require.NoError(t, err)
+		require.Equal(t, SignatureStatusGood, sig.Status)
+		require.Equal(t, "alice@example.com", sig.Signer)
+		require.NotEmpty(t, sig.Fingerprint)
+	})
+
+	t.Run("signed with SSH key that is not allowed", func(t *testing.T) {
+		sig, err := rep.VerifyCommitSignature(sshSigned, &VerifyCommitSignatureOptions{
+			GPGPublicKeys: gpgPublicKeys,
+		})
+		require.NoError(t, err)
+		require.Equal(t, SignatureStatusUnknownKey, sig.Status)
+	})
+
+	t.Run("signed with provided GPG key", func(t *testing.T) {
+		sig, err := rep.VerifyCommitSignature(gpgSigned, keys)
+		require.NoError(t, err)
+		require.Equal(t, SignatureStatusGood, sig.Status)
+		require.Equal(t, "Bob <bob@example.com>", sig.Signer)
+	})
+
+	t.Run("signed with GPG key that is not provided", func(t *testing.T) {
+		sig, err := rep.VerifyCommitSignature(gpgSigned, &VerifyCommitSignatureOptions{
+			SSHAllowedSigners: allowedSigners,
+		})
+		require.NoError(t, err)
+		require.Equal(t, SignatureStatusUnknownKey, sig.Status)
+	})
+
+	t.Run("defaults to HEAD", func(t *testing.T) {
+		sig, err := rep.VerifyCommitSignature("", keys)
+		require.NoError(t, err)
+		require.Equal(t, gpgSigned, sig.Commit)
+	})
+
+	t.Run("signed merge of unsigned commits", func(t *testing.T) {
+		run(t, nil, "git", "checkout", "-q", "-b", "feature", unsigned)
+		featureCommit := commit(t)
+		run(t, nil, "git", "checkout", "-q", "-")
+		run(t, gpgEnv(t), "git", append(sshSign, "merge", "--no-ff", "-m", "merge", featureCommit)...)
+		merge := run(t, nil, "git", "rev-parse", "HEAD")
+
+		sig, err := rep.VerifyCommitSignature(merge, keys)
+		require.NoError(t, err)
+		require.Equal(t, SignatureStatusGood, sig.Status)
+	})
+
+	t.Run("unsigned merge of signed commits", func(t *testing.T) {
+		run(t, nil, "git", "checkout", "-q", "-b", "signed-feature", unsigned)
+		featureCommit := commit(t, sshSign...)
+		run(t, nil, "git", "checkout", "-q", "-")
+		run(t, nil, "git", "merge", "--no-ff", "-m", "merge", featureCommit)
+		merge := run(t, nil, "git", "rev-parse", "HEAD")
+
+		sig, err := rep.VerifyCommitSignature(merge, keys)
+		require.NoError(t, err)
+		require.Equal(t, SignatureStatusUnsigned, sig.Status)
+	})
+
+	t.Run("commit is fetched", func(t *testing.T) {
+		require.NoError(t, rep.Push(nil))
+		other, err := Clone(remoteDir, nil, &CloneOptions{Depth: 1})
+		require.NoError(t, err)
+		defer other.Close()
+
+		sig, err := other.VerifyCommitSignature(sshSigned, keys)
+		require.NoError(t, err)
+		require.Equal(t, sshSigned, sig.Commit)
+		require.Equal(t, SignatureStatusGood, sig.Status)
+	})
+
+	t.Run("commit does not exist", func(t *testing.T) {
+		_, err := rep.VerifyCommitSignature(strings.Repeat("0", 40), keys)
+		require.ErrorContains(t, err, "error fetching commit")
+	})
+}
+
+// gpgEnv returns the environment for running GPG with a home directory that
+// is shared by all callers within the provided test.
+func gpgEnv(t *testing.T) []string {
+	t.Helper()
+	const key = "KARGO_TEST_GNUPGHOME"
+	home := os.Getenv(key)
+	if home == "" {
+		// The path of the agent's socket is limited in length, so the home
+		// directory must not be nested too deeply.
+		var err error
+		home, err = os.MkdirTemp("", "gnupg-")
+		require.NoError(t, err)
+		require.NoError(t, os.Chmod(home, 0o700))
+		t.Setenv(key, home)
+		t.Cleanup(func() {
+			cmd := exec.Command("gpgconf", "--kill", "all")
+			cmd.Env = []string{"GNUPGHOME=" + home}
+			_ = cmd.Run()
+			_ = os.RemoveAll(home)
+		})
+	}
+	return []string{"GNUPGHOME=" + home}
+}
+
+func Test_parseCommitSignature(t *testing.T) {
+	testCases := []struct {
+		name       string
+		output     string
+		assertions func(*testing.T, *CommitSignature, error)
+	}{
+		{
+			name:   "unexpected output",
+			output: "foo",
+			assertions: func(t *testing.T, _ *CommitSignature, err error) {
+				require.ErrorContains(t, err, "unexpected signature information")
+			},
+		},
+		{
+			name:   "good signature",
+			output: "abc\x00G\x00Jane <jane@example.com>\x00ABCDEF\n",
+			assertions: func(t *testing.T, sig *CommitSignature, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &CommitSignature{
+					Commit:      "abc",
+					Status:      SignatureStatusGood,
+					Signer:      "Jane <jane@example.com>",
+					Fingerprint: "ABCDEF",
+				}, sig)
+			},
+		},
+		{
+			name:   "revoked key",
+			output: "abc\x00R\x00\x00ABCDEF\n",
+			assertions: func(t *testing.T, sig *CommitSignature, err error) {
+				require.NoError(t, err)
+				require.Equal(t, SignatureStatusRevokedKey, sig.Status)
+			},
+		},
+		{
+			name:   "unknown validity",
+			output: "abc\x00U\x00\x00ABCDEF\n",
+			assertions: func(t *testing.T, sig *CommitSignature, err error) {
+				require.NoError(t, err)
+				require.Equal(t, SignatureStatusUnknownKey, sig.Status)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sig, err := parseCommitSignature([]byte(testCase.output))
+			testCase.assertions(t, sig, err)
+		})
+	}
+}
@@ -1,5 +1,25 @@
 package git
 
+// HTTPAuthMode identifies how a password or token is presented to a remote
+// repository over HTTP(S).
+type HTTPAuthMode string
+
+const (
+	// HTTPAuthModeBasic indicates that the username and password are presented
+	// using HTTP basic authentication, as obtained by git from a credential
+	// helper. This is the default.
+	HTTPAuthModeBasic HTTPAuthMode = "basic"
+	// HTTPAuthModeBearer indicates that the password is a token that is
+	// presented in an "Authorization: Bearer" header, e.g. to a remote behind
+	// an OAuth2 proxy. The header is only ever sent to the repository's own
+	// URL.
+	HTTPAuthModeBearer HTTPAuthMode = "bearer"
+	// HTTPAuthModeNetrc indicates that the username and password are presented
+	// using HTTP basic authentication, as read by git from a .netrc file in the
+	// repository's home directory.
+	HTTPAuthModeNetrc HTTPAuthMode = "netrc"
+)
+
 // RepoCredentials represents the credentials for connecting to a private git
 // repository.
 type RepoCredentials struct {
@@ -14,4 +34,7 @@ type RepoCredentials struct {
 	// field, can be used for both reading from and writing to some remote
 	// repository.
 	Password string `json:"password,omitempty"`
+	// HTTPAuthMode specifies how the Password is presented to the remote
+	// repository over HTTP(S). If empty, HTTPAuthModeBasic is assumed.
+	HTTPAuthMode HTTPAuthMode `json:"httpAuthMode,omitempty"`
 }
@@ -0,0 +1,151 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+// DiagnosedError wraps an error produced by a git command whose output matches
+// the signature of a well-known failure, such as a Git server redirecting to
+// a single sign-on login page. Its message explains the failure in terms that
+// do not require familiarity with the often misleading output of git, which
+// remains available through the wrapped error.
+type DiagnosedError struct {
+	// Reason is a short, machine-readable identifier of the failure, e.g.
+	// "SSORedirect".
+	Reason string
+	// Message is a human-readable explanation of the failure.
+	Message string
+	// Err is the error that was diagnosed.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DiagnosedError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Message)
+}
+
+// Unwrap returns the wrapped error.
+func (e *DiagnosedError) Unwrap() error {
+	return e.Err
+}
+
+// failureSignature maps output of git commands that is characteristic of a
+// well-known failure to a reason and a human-readable explanation.
+type failureSignature struct {
+	reason   string
+	message  string
+	patterns []*regexp.Regexp
+}
+
+// failureSignatures are the signatures of well-known failures of git commands
+// that communicate with a remote. They are matched in order, so signatures of
+// specific failures must precede those of more general ones that would also
+// match, e.g. a 403 caused by SAML enforcement must be diagnosed before any
+// other 403.
+var failureSignatures = []failureSignature{
+	{
+		reason: "SAMLNotAuthorized",
+		message: "the organization enforces SAML single sign-on and the token or SSH key " +
+			"has not been authorized for it",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`enabled or enforced SAML SSO`),
+			regexp.MustCompile(`(?i)SAML (SSO|single sign-on) (is )?(required|enforced)`),
+		},
+	},
+	{
+		reason:  "SSORedirect",
+		message: "server redirected to SSO login — token likely expired or missing",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`unable to update url base from redirection`),
+			regexp.MustCompile(`(?i)redirect(ing)?( to)?:? https?://\S*(sso|saml|login|signin|oauth|okta|adfs)`),
+		},
+	},
+	{
+		reason: "HTMLResponse",
+		message: "server responded with an HTML page instead of a Git repository — " +
+			"likely a login page of a proxy or SSO gateway",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`not valid: is this a git repository\?`),
+			regexp.MustCompile(`(?i)<!doctype html|<html`),
+		},
+	},
+	{
+		reason:  "PasswordAuthNotSupported",
+		message: "server does not accept passwords — a personal access token is required",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`Support for password authentication was removed`),
+		},
+	},
+	{
+		reason: "CredentialsRejected",
+		message: "server rejected the credentials — token likely expired, revoked, or " +
+			"lacking required scopes, or a password was used where a personal access token " +
+			"is required, e.g. because two-factor authentication is enabled",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`HTTP Basic: Access denied`),
+			regexp.MustCompile(`Invalid username or (password|token)`),
+			regexp.MustCompile(`Authentication failed for`),
+			regexp.MustCompile(`The requested URL returned error: 401`),
+		},
+	},
+	{
+		reason:  "CredentialsMissing",
+		message: "server requires authentication, but no credentials were found for the repository",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`could not read (Username|Password) for`),
+			regexp.MustCompile(`terminal prompts disabled`),
+		},
+	},
+	{
+		reason:  "SSHKeyRejected",
+		message: "server did not accept the SSH key",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`Permission denied \(publickey`),
+		},
+	},
+	{
+		reason:  "PushForbidden",
+		message: "credentials are valid, but do not permit pushing to the repository",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`Permission to \S+ denied`),
+			regexp.MustCompile(`[Ww]rite access to repository not granted`),
+			regexp.MustCompile(`You are not allowed to push code`),
+			regexp.MustCompile(`prohibited by Gerrit: not permitted`),
+		},
+	},
+	{
+		reason:  "RepoNotFound",
+		message: "repository not found — it may not exist, or the credentials may not grant access to it",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`[Rr]epository not found`),
+			regexp.MustCompile(`[Rr]epository '.+' not found`),
+			regexp.MustCompile(`could not be found or you don't have permission to view it`),
+		},
+	},
+}
+
+// diagnoseError returns the provided error wrapped in a DiagnosedError if it
+// was produced by a git command whose output matches a failure signature, and
+// the provided error as-is otherwise.
+func diagnoseError(err error) error {
+	var exitErr *libExec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	for _, sig := range failureSignatures {
+		for _, regex := range sig.patterns {
+			if regex.Match(exitErr.Output) {
+				return &DiagnosedError{
+					Reason:  sig.reason,
+					Message: sig.message,
+					Err:     err,
+				}
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+func Test_diagnoseError(t *testing.T) {
+	// Each file in testdata/failures holds output captured from a git command
+	// that failed while communicating with the Git server it is named after.
+	testCases := map[string]string{
+		"github/saml-sso.txt":                        "SAMLNotAuthorized",
+		"github/password-auth.txt":                   "PasswordAuthNotSupported",
+		"github/invalid-token.txt":                   "CredentialsRejected",
+		"github/no-credentials.txt":                  "CredentialsMissing",
+		"github/repo-not-found.txt":                  "RepoNotFound",
+		"github/push-denied.txt":                     "PushForbidden",
+		"github/ssh-key.txt":                         "SSHKeyRejected",
+		"gitlab/access-denied.txt":                   "CredentialsRejected",
+		"gitlab/project-not-found.txt":               "RepoNotFound",
+		"gitlab/push-denied.txt":                     "PushForbidden",
+		"bitbucket-server/sso-redirect.txt":          "SSORedirect",
+		"bitbucket-server/html-response.txt":         "HTMLResponse",
+		"bitbucket-server/authentication-failed.txt": "CredentialsRejected",
+		"bitbucket-server/repo-not-found.txt":        "RepoNotFound",
+		"gerrit/unauthorized.txt":                    "CredentialsRejected",
+		"gerrit/push-prohibited.txt":                 "PushForbidden",
+		"gerrit/repo-not-found.txt":                  "RepoNotFound",
+		"gerrit/sso-redirect.txt":                    "SSORedirect",
+		// Failures that are not well-known are not diagnosed.
+		"gerrit/non-fast-forward.txt": "",
+	}
+	for file, reason := range testCases {
+		t.Run(file, func(t *testing.T) {
+			output, err := os.ReadFile(filepath.Join("testdata", "failures", file))
+			require.NoError(t, err)
+			exitErr := &libExec.ExitError{
+				Command:  "git clone",
+				Output:   output,
+				ExitCode: 128,
+			}
+			err = diagnoseError(fmt.Errorf("wrapped: %w", exitErr))
+
+			// The output of the command must remain accessible either way.
+			var e *libExec.ExitError
+			require.True(t, errors.As(err, &e))
+			require.Same(t, exitErr, e)
+
+			var diagnosed *DiagnosedError
+			if reason == "" {
+				require.False(t, errors.As(err, &diagnosed))
+				return
+			}
+			require.True(t, errors.As(err, &diagnosed))
+			require.Equal(t, reason, diagnosed.Reason)
+			require.Equal(t, reason+": "+diagnosed.Message, err.Error())
+			require.NotContains(t, err.Error(), string(output))
+		})
+	}
+
+	t.Run("not an exit error", func(t *testing.T) {
+		err := errors.New("something went wrong")
+		require.Same(t, err, diagnoseError(err))
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		require.NoError(t, diagnoseError(nil))
+	})
+}
@@ -22,3 +22,22 @@ var ErrNonFastForward = errors.New("non-fast-forward")
 func IsNonFastForward(err error) bool {
 	return errors.Is(err, ErrNonFastForward)
 }
+
+// ErrAtomicPushNotSupported is returned when an atomic push of multiple
+// branches is rejected because the remote repository does not support atomic
+// pushes.
+var ErrAtomicPushNotSupported = errors.New("atomic push not supported")
+
+// IsAtomicPushNotSupported returns true if the error is an
+// ErrAtomicPushNotSupported or wraps one and false otherwise.
+func IsAtomicPushNotSupported(err error) bool {
+	return errors.Is(err, ErrAtomicPushNotSupported)
+}
+
+// IsAuthError returns true if the provided error was produced by a git command
+// that failed because the remote did not accept the credentials that were
+// presented, if any, and false otherwise. Presenting different credentials may
+// succeed where these failed, e.g. if they have since been rotated.
+func IsAuthError(err error) bool {
+	return err != nil && classifyError(err) == failureReasonAuth
+}
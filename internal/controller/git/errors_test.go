@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	libExec "github.com/akuity/kargo/internal/exec"
 )
 
 func TestIsMergeConflict(t *testing.T) {
@@ -77,3 +79,85 @@ func TestIsNonFastForward(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAtomicPushNotSupported(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "not an atomic push not supported error",
+			err:      errors.New("something went wrong"),
+			expected: false,
+		},
+		{
+			name:     "an atomic push not supported error",
+			err:      ErrAtomicPushNotSupported,
+			expected: true,
+		},
+		{
+			name:     "a wrapped atomic push not supported error",
+			err:      fmt.Errorf("an error occurred: %w", ErrAtomicPushNotSupported),
+			expected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual := IsAtomicPushNotSupported(testCase.err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "not an exit error",
+			err:      errors.New("something went wrong"),
+			expected: false,
+		},
+		{
+			name: "credentials rejected over HTTPS",
+			err: fmt.Errorf("error cloning repo: %w", &libExec.ExitError{
+				Output: []byte("remote: Invalid username or password.\n" +
+					"fatal: Authentication failed for 'https://github.com/akuity/kargo/'"),
+			}),
+			expected: true,
+		},
+		{
+			name: "credentials rejected over SSH",
+			err: &libExec.ExitError{
+				Output: []byte("git@github.com: Permission denied (publickey)."),
+			},
+			expected: true,
+		},
+		{
+			name: "valid credentials without write access",
+			err: &libExec.ExitError{
+				Output: []byte("remote: Permission to akuity/kargo.git denied to someone."),
+			},
+			expected: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual := IsAuthError(testCase.err)
+			require.Equal(t, testCase.expected, actual)
+		})
+	}
+}
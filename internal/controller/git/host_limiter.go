@@ -0,0 +1,154 @@
+package git
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"golang.org/x/time/rate"
+
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// HostLimiterConfig represents configuration for limiting the concurrency and
+// rate of network operations (e.g. clone, fetch, ls-remote, and push) that are
+// performed against any single Git host. The limits apply to all repositories
+// in the process, regardless of which goroutine is operating on them.
+type HostLimiterConfig struct {
+	// MaxConcurrentOps is the maximum number of network operations that may be
+	// in progress against a single host at any given time. A value of zero
+	// means no limit.
+	MaxConcurrentOps int `envconfig:"GIT_MAX_CONCURRENT_OPS_PER_HOST" default:"0"`
+	// MaxOpsPerMinute is the maximum number of network operations that may be
+	// started against a single host per minute. A value of zero means no limit.
+	MaxOpsPerMinute int `envconfig:"GIT_MAX_OPS_PER_MINUTE_PER_HOST" default:"0"`
+}
+
+func HostLimiterConfigFromEnv() HostLimiterConfig {
+	cfg := HostLimiterConfig{}
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+// hostLimits is the hostLimiter shared by all repositories in the process. By
+// default, it does not impose any limits.
+var hostLimits atomic.Pointer[hostLimiter]
+
+func init() {
+	hostLimits.Store(newHostLimiter(HostLimiterConfig{}))
+}
+
+// SetHostLimits configures limits on the network operations performed against
+// any single Git host. It is intended to be called once, at startup, before any
+// repositories are cloned or loaded.
+func SetHostLimits(cfg HostLimiterConfig) {
+	hostLimits.Store(newHostLimiter(cfg))
+}
+
+// hostLimiter limits the concurrency and rate of network operations performed
+// against each Git host. Hosts are keyed by the hostname of the normalized
+// repository URL. Limits are acquired separately for each operation and never
+// held across operations, so a caller performing several operations against
+// the same host in sequence can not deadlock itself.
+type hostLimiter struct {
+	cfg   HostLimiterConfig
+	mu    sync.Mutex
+	hosts map[string]*hostLimit
+}
+
+// hostLimit holds the limits for a single host. Either field may be nil if the
+// corresponding limit is not configured.
+type hostLimit struct {
+	sem     chan struct{}
+	limiter *rate.Limiter
+}
+
+func newHostLimiter(cfg HostLimiterConfig) *hostLimiter {
+	return &hostLimiter{
+		cfg:   cfg,
+		hosts: map[string]*hostLimit{},
+	}
+}
+
+// acquire blocks until a network operation against the host of the specified
+// repository URL is permitted to start, and records how long that took in
+// metrics. It returns a function that must be called once the operation has
+// completed. If the provided context is done before the operation is permitted
+// to start, the context's error is returned instead.
+func (h *hostLimiter) acquire(ctx context.Context, repoURL string) (func(), error) {
+	if h.cfg.MaxConcurrentOps <= 0 && h.cfg.MaxOpsPerMinute <= 0 {
+		return func() {}, nil
+	}
+	host := hostForURL(repoURL)
+	l := h.forHost(host)
+	start := time.Now()
+	var waited bool
+	if l.limiter != nil {
+		waited = l.limiter.Tokens() < 1
+		if err := l.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		default:
+			waited = true
+			select {
+			case l.sem <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	hostLimitWaitDuration.WithLabelValues(metricsHost(repoURL)).Observe(time.Since(start).Seconds())
+	if waited {
+		logging.LoggerFromContext(ctx).Debug(
+			"waited for Git host limits",
+			"host", host,
+			"duration", time.Since(start),
+		)
+	}
+	return func() {
+		if l.sem != nil {
+			<-l.sem
+		}
+	}, nil
+}
+
+// forHost returns the hostLimit for the specified host, creating it if it does
+// not already exist.
+func (h *hostLimiter) forHost(host string) *hostLimit {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	l, ok := h.hosts[host]
+	if !ok {
+		l = &hostLimit{}
+		if h.cfg.MaxConcurrentOps > 0 {
+			l.sem = make(chan struct{}, h.cfg.MaxConcurrentOps)
+		}
+		if h.cfg.MaxOpsPerMinute > 0 {
+			l.limiter = rate.NewLimiter(
+				rate.Every(time.Minute/time.Duration(h.cfg.MaxOpsPerMinute)),
+				h.cfg.MaxOpsPerMinute,
+			)
+		}
+		h.hosts[host] = l
+	}
+	return l
+}
+
+// hostForURL returns the hostname of the specified repository URL after
+// normalization. If no hostname can be determined, the URL itself is returned
+// so that limits are, at worst, applied per repository.
+func hostForURL(repoURL string) string {
+	u, err := url.Parse(libgit.NormalizeURL(repoURL))
+	if err != nil || u.Hostname() == "" {
+		return repoURL
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_hostLimiter_acquire(t *testing.T) {
+	// mustAcquire acquires the limits for the specified repository URL using a
+	// context that is never done, so acquisition can not fail.
+	mustAcquire := func(t *testing.T, h *hostLimiter, repoURL string) func() {
+		release, err := h.acquire(context.Background(), repoURL)
+		require.NoError(t, err)
+		return release
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		h := newHostLimiter(HostLimiterConfig{})
+		for range 10 {
+			mustAcquire(t, h, "https://github.com/akuity/kargo")()
+		}
+		require.Empty(t, h.hosts)
+	})
+
+	t.Run("concurrency is limited per host", func(t *testing.T) {
+		h := newHostLimiter(HostLimiterConfig{MaxConcurrentOps: 1})
+		release := mustAcquire(t, h, "https://github.com/akuity/kargo")
+
+		// An operation against a different host is not blocked
+		mustAcquire(t, h, "https://gitlab.com/akuity/kargo")()
+
+		acquired := make(chan struct{})
+		go func() {
+			// Same host, different repository and URL format
+			if release, err := h.acquire(context.Background(), "git@github.com:example/repo.git"); err == nil {
+				release()
+			}
+			close(acquired)
+		}()
+		select {
+		case <-acquired:
+			require.Fail(t, "second operation against the same host was not blocked")
+		case <-time.After(100 * time.Millisecond):
+		}
+		release()
+		select {
+		case <-acquired:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "second operation was not unblocked")
+		}
+	})
+
+	t.Run("sequential operations do not deadlock", func(t *testing.T) {
+		h := newHostLimiter(HostLimiterConfig{MaxConcurrentOps: 1})
+		for range 5 {
+			mustAcquire(t, h, "https://github.com/akuity/kargo")()
+		}
+	})
+
+	t.Run("rate is limited per host", func(t *testing.T) {
+		// Permits a burst of 600 operations, then one every 100ms
+		h := newHostLimiter(HostLimiterConfig{MaxOpsPerMinute: 600})
+		for range 600 {
+			mustAcquire(t, h, "https://github.com/akuity/kargo")()
+		}
+		start := time.Now()
+		mustAcquire(t, h, "https://github.com/akuity/kargo")()
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("waiting for concurrency is canceled with the context", func(t *testing.T) {
+		h := newHostLimiter(HostLimiterConfig{MaxConcurrentOps: 1})
+		release := mustAcquire(t, h, "https://github.com/akuity/kargo")
+		defer release()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := h.acquire(ctx, "https://github.com/akuity/kargo")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("waiting for rate is canceled with the context", func(t *testing.T) {
+		// Permits a burst of 1 operation, then one every minute
+		h := newHostLimiter(HostLimiterConfig{MaxOpsPerMinute: 1})
+		mustAcquire(t, h, "https://github.com/akuity/kargo")()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := h.acquire(ctx, "https://github.com/akuity/kargo")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("wait durations are recorded per host", func(t *testing.T) {
+		waits := func(host string) *dto.Histogram {
+			m := &dto.Metric{}
+			observer := hostLimitWaitDuration.WithLabelValues(host)
+			require.NoError(t, observer.(prometheus.Metric).Write(m)) // nolint: forcetypeassert
+			return m.GetHistogram()
+		}
+
+		mustAcquire(t, newHostLimiter(HostLimiterConfig{}), "https://unlimited.example.com/repo")()
+		require.Zero(t, waits("unlimited.example.com").GetSampleCount())
+
+		h := newHostLimiter(HostLimiterConfig{MaxOpsPerMinute: 600})
+		for range 601 {
+			mustAcquire(t, h, "https://limited.example.com/repo")()
+		}
+		limited := waits("limited.example.com")
+		require.Equal(t, uint64(601), limited.GetSampleCount())
+		require.GreaterOrEqual(t, limited.GetSampleSum(), 0.05)
+	})
+}
+
+func Test_hostForURL(t *testing.T) {
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "https://github.com/akuity/kargo.git",
+			expected: "github.com",
+		},
+		{
+			url:      "https://user@GitHub.com:443/akuity/kargo",
+			expected: "github.com",
+		},
+		{
+			url:      "ssh://git@github.com/akuity/kargo.git",
+			expected: "github.com",
+		},
+		{
+			url:      "git@github.com:akuity/kargo.git",
+			expected: "github.com",
+		},
+		{
+			url:      "/some/local/path",
+			expected: "/some/local/path",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.url, func(t *testing.T) {
+			require.Equal(t, testCase.expected, hostForURL(testCase.url))
+		})
+	}
+}
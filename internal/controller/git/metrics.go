@@ -0,0 +1,204 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+var (
+	operationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kargo_git_operation_duration_seconds",
+			Help:    "Duration of git operations, by operation and remote host",
+			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		},
+		[]string{"operation", "host"},
+	)
+	operationFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kargo_git_operation_failures_total",
+			Help: "Number of failed git operations, by operation, remote host, " +
+				"and type of error",
+		},
+		[]string{"operation", "host", "reason"},
+	)
+	operationRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kargo_git_operation_retries_total",
+			Help: "Number of retries of git network operations that failed with " +
+				"transient errors, by operation and remote host",
+		},
+		[]string{"operation", "host"},
+	)
+	hostLimitWaitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "kargo_git_host_limit_wait_duration_seconds",
+			Help: "Time git network operations waited to be permitted by the " +
+				"per-host concurrency and rate limits, by remote host",
+			Buckets: []float64{0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"host"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		operationDuration,
+		operationFailures,
+		operationRetries,
+		hostLimitWaitDuration,
+	)
+}
+
+// observedOperations are the git subcommands that are reported individually.
+// Any other subcommand is reported as "other" to keep the number of label
+// values bounded.
+var observedOperations = []string{
+	"clone",
+	"commit",
+	"fetch",
+	"ls-remote",
+	"pull",
+	"push",
+	"read-tree",
+	"lfs",
+}
+
+// Reasons that failed git operations are classified by.
+const (
+	failureReasonAuth       = "auth"
+	failureReasonConnection = "connection"
+	failureReasonExec       = "exec"
+	failureReasonForbidden  = "forbidden"
+	failureReasonNotFound   = "not_found"
+	failureReasonOther      = "other"
+	failureReasonRejected   = "rejected"
+	failureReasonTransient  = "transient"
+)
+
+// authErrorPatterns match output of git commands that failed because the
+// credentials that were presented, if any, were not accepted.
+var authErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Authentication failed`),
+	regexp.MustCompile(`could not read Username`),
+	regexp.MustCompile(`Permission denied`),
+	regexp.MustCompile(`The requested URL returned error: 401`),
+}
+
+// notFoundErrorPatterns match output of git commands that failed because the
+// remote repository does not exist or is not visible with the credentials that
+// were presented.
+var notFoundErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[Rr]epository not found`),
+	regexp.MustCompile(`The requested URL returned error: 404`),
+	regexp.MustCompile(`does not appear to be a git repository`),
+}
+
+// execGitCommand executes the provided git command, which operates on the
+// repository having the specified remote URL, and records its duration and
+// outcome in metrics, as well as in any libExec.Timings tracked for the
+// directory it is executed in. A message naming the operation and repository
+// is logged if the command is slow.
+func execGitCommand(cmd *exec.Cmd, repoURL string) ([]byte, error) {
+	op := gitOperation(cmd.Args)
+	host := metricsHost(repoURL)
+	start := time.Now()
+	res, err := libExec.Exec(cmd)
+	duration := time.Since(start)
+
+	operationDuration.WithLabelValues(op, host).Observe(duration.Seconds())
+	if err != nil {
+		operationFailures.WithLabelValues(op, host, classifyError(err)).Inc()
+	}
+	libExec.RecordTiming(cmd.Dir, "git "+op, duration)
+	if libExec.IsSlow(duration) {
+		logging.LoggerFromContext(context.Background()).Info(
+			"slow git operation",
+			"operation", op,
+			"repo", repoURL,
+			"duration", duration.Round(time.Millisecond).String(),
+		)
+	}
+	return res, err
+}
+
+// gitOperation returns the name under which the git command with the provided
+// arguments is reported. The first argument is expected to be the git binary.
+func gitOperation(args []string) string {
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-c" || arg == "-C":
+			// These take a value as the next argument.
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			if slices.Contains(observedOperations, arg) {
+				return arg
+			}
+			return "other"
+		}
+	}
+	return "other"
+}
+
+// metricsHost returns the hostname of the specified repository URL after
+// normalization. Unlike the URL itself, the number of distinct hosts is small,
+// which makes it suitable as a label value. Repositories without a hostname,
+// e.g. on the local file system, are reported as "local".
+func metricsHost(repoURL string) string {
+	if repoURL == "" {
+		return "unknown"
+	}
+	u, err := url.Parse(libgit.NormalizeURL(repoURL))
+	if err != nil {
+		return "unknown"
+	}
+	if u.Hostname() == "" {
+		return "local"
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// classifyError returns the reason a git command failed with the provided
+// error, as reported in metrics.
+func classifyError(err error) string {
+	var exitErr *libExec.ExitError
+	if !errors.As(err, &exitErr) {
+		return failureReasonExec
+	}
+	matchesAny := func(patterns []*regexp.Regexp) bool {
+		return slices.ContainsFunc(patterns, func(regex *regexp.Regexp) bool {
+			return regex.Match(exitErr.Output)
+		})
+	}
+	switch {
+	case nonFastForwardRegex.Match(exitErr.Output):
+		return failureReasonRejected
+	case matchesAny(pushForbiddenPatterns):
+		return failureReasonForbidden
+	case matchesAny(notFoundErrorPatterns):
+		return failureReasonNotFound
+	case matchesAny(authErrorPatterns):
+		return failureReasonAuth
+	case isConnectionError(err):
+		return failureReasonConnection
+	case networkRetries.Load().isTransient(err):
+		return failureReasonTransient
+	default:
+		return failureReasonOther
+	}
+}
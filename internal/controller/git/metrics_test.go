@@ -0,0 +1,123 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+func Test_gitOperation(t *testing.T) {
+	testCases := map[string][]string{
+		"clone":     {"git", "clone", "--bare", "https://github.com/akuity/kargo", "repo"},
+		"ls-remote": {"git", "-c", "protocol.version=2", "ls-remote", "origin"},
+		"push":      {"git", "--no-pager", "push", "origin", "main"},
+		"other":     {"git", "sparse-checkout", "add", "--", "foo"},
+	}
+	for expected, args := range testCases {
+		require.Equal(t, expected, gitOperation(args))
+	}
+	require.Equal(t, "other", gitOperation([]string{"git"}))
+}
+
+func Test_metricsHost(t *testing.T) {
+	require.Equal(t, "github.com", metricsHost("https://GitHub.com/akuity/kargo.git"))
+	require.Equal(t, "github.com", metricsHost("git@github.com:akuity/kargo.git"))
+	require.Equal(t, "local", metricsHost("file:///tmp/repo"))
+	require.Equal(t, "unknown", metricsHost(""))
+}
+
+func Test_classifyError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "not an exit error",
+			err:      errors.New("executable file not found"),
+			expected: failureReasonExec,
+		},
+		{
+			name: "non-fast-forward",
+			err: &libExec.ExitError{
+				Output: []byte(" ! [rejected]        main -> main (fetch first)\n"),
+			},
+			expected: failureReasonRejected,
+		},
+		{
+			name: "push forbidden",
+			err: &libExec.ExitError{
+				Output: []byte("remote: Permission to akuity/kargo.git denied to someone."),
+			},
+			expected: failureReasonForbidden,
+		},
+		{
+			name: "repository not found",
+			err: &libExec.ExitError{
+				Output: []byte("remote: Repository not found.\nfatal: the remote end hung up unexpectedly"),
+			},
+			expected: failureReasonNotFound,
+		},
+		{
+			name: "authentication failure",
+			err: &libExec.ExitError{
+				Output: []byte("fatal: Authentication failed for 'https://github.com/akuity/kargo/'"),
+			},
+			expected: failureReasonAuth,
+		},
+		{
+			name: "connection failure",
+			err: &libExec.ExitError{
+				Output: []byte("ssh: connect to host github.com port 22: Connection refused"),
+			},
+			expected: failureReasonConnection,
+		},
+		{
+			name: "transient failure",
+			err: &libExec.ExitError{
+				Output: []byte("fetch-pack: unexpected disconnect\nfatal: early EOF"),
+			},
+			expected: failureReasonTransient,
+		},
+		{
+			name: "unrecognized output",
+			err: &libExec.ExitError{
+				Output: []byte("fatal: something went wrong"),
+			},
+			expected: failureReasonOther,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, classifyError(testCase.err))
+		})
+	}
+}
+
+func Test_execGitCommand(t *testing.T) {
+	dir := t.TempDir()
+	timings, untrack := libExec.TrackTimings(dir)
+	defer untrack()
+
+	const repoURL = "https://git.example.com/org/repo.git"
+	failures := testutil.ToFloat64(
+		operationFailures.WithLabelValues("ls-remote", "git.example.com", failureReasonOther),
+	)
+
+	cmd := exec.Command("git", "ls-remote", "--not-a-real-flag")
+	cmd.Dir = dir
+	_, err := execGitCommand(cmd, repoURL)
+	require.Error(t, err)
+
+	require.Equal(t, failures+1, testutil.ToFloat64(
+		operationFailures.WithLabelValues("ls-remote", "git.example.com", failureReasonOther),
+	))
+	ops := timings.Get()
+	require.Len(t, ops, 1)
+	require.Equal(t, "git ls-remote", ops[0].Operation)
+}
@@ -0,0 +1,222 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// defaultTransientErrorPatterns match output of git commands that failed for
+// reasons that are likely to resolve themselves, e.g. dropped connections,
+// server-side errors, and DNS hiccups.
+var defaultTransientErrorPatterns = []string{
+	`early EOF`,
+	`RPC failed`,
+	`[Cc]onnection reset by peer`,
+	`[Cc]onnection timed out`,
+	`[Oo]peration timed out`,
+	`Could not resolve host`,
+	`[Tt]emporary failure in name resolution`,
+	`Failed to connect to .+ port \d+`,
+	`[Tt]he remote end hung up unexpectedly`,
+	`unexpected disconnect while reading sideband packet`,
+	`The requested URL returned error: 5\d\d`,
+	`HTTP/2 stream \d+ was not closed cleanly`,
+	`gnutls_handshake\(\) failed`,
+	`SSL_ERROR_SYSCALL`,
+}
+
+// permanentErrorPatterns match output of git commands that failed for reasons
+// that no amount of retries will fix. These take precedence over any transient
+// error patterns that might also match.
+var permanentErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Authentication failed`),
+	regexp.MustCompile(`could not read Username`),
+	regexp.MustCompile(`Permission denied`),
+	regexp.MustCompile(`[Rr]epository not found`),
+	regexp.MustCompile(`The requested URL returned error: 40[134]`),
+	regexp.MustCompile(`does not appear to be a git repository`),
+}
+
+// NetworkRetryConfig represents configuration for retrying network operations
+// (e.g. clone, fetch, ls-remote, and push) that fail for reasons that appear
+// to be transient.
+type NetworkRetryConfig struct {
+	// MaxAttempts is the maximum number of attempts, including the first, to
+	// make for any single network operation. A value of 1 disables retries.
+	MaxAttempts int `envconfig:"GIT_NETWORK_MAX_ATTEMPTS" default:"3"`
+	// TransientErrorPatterns are regular expressions that, in addition to a
+	// built-in list, are matched against the output of a failed operation to
+	// determine whether the failure was transient.
+	TransientErrorPatterns []string `envconfig:"GIT_TRANSIENT_ERROR_PATTERNS"`
+}
+
+func NetworkRetryConfigFromEnv() NetworkRetryConfig {
+	cfg := NetworkRetryConfig{}
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+// networkRetries is the networkRetrier shared by all repositories in the
+// process.
+var networkRetries atomic.Pointer[networkRetrier]
+
+func init() {
+	r, err := newNetworkRetrier(NetworkRetryConfig{MaxAttempts: 3})
+	if err != nil {
+		panic(err)
+	}
+	networkRetries.Store(r)
+}
+
+// SetNetworkRetries configures the retrying of network operations that fail
+// for reasons that appear to be transient. It is intended to be called once,
+// at startup, before any repositories are cloned or loaded. An error is
+// returned if any of the configured patterns is not a valid regular
+// expression.
+func SetNetworkRetries(cfg NetworkRetryConfig) error {
+	r, err := newNetworkRetrier(cfg)
+	if err != nil {
+		return err
+	}
+	networkRetries.Store(r)
+	return nil
+}
+
+// networkRetrier retries network operations that fail for reasons that appear
+// to be transient, with a jittered exponential backoff between attempts.
+type networkRetrier struct {
+	backoff           wait.Backoff
+	transientPatterns []*regexp.Regexp
+}
+
+func newNetworkRetrier(cfg NetworkRetryConfig) (*networkRetrier, error) {
+	r := &networkRetrier{
+		backoff: wait.Backoff{
+			Steps:    max(cfg.MaxAttempts, 1),
+			Duration: time.Second,
+			Factor:   2,
+			Jitter:   0.5,
+			Cap:      10 * time.Second,
+		},
+	}
+	patterns := append(
+		append([]string{}, defaultTransientErrorPatterns...),
+		cfg.TransientErrorPatterns...,
+	)
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error compiling transient error pattern %q: %w", pattern, err,
+			)
+		}
+		r.transientPatterns = append(r.transientPatterns, regex)
+	}
+	return r, nil
+}
+
+// isTransient returns true if the provided error was produced by a command
+// whose output matches any transient error pattern and no permanent error
+// pattern.
+func (n *networkRetrier) isTransient(err error) bool {
+	var exitErr *libExec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	for _, regex := range permanentErrorPatterns {
+		if regex.Match(exitErr.Output) {
+			return false
+		}
+	}
+	for _, regex := range n.transientPatterns {
+		if regex.Match(exitErr.Output) {
+			return true
+		}
+	}
+	return false
+}
+
+// exec executes the provided command, which communicates with the remote
+// repository having the specified URL, retrying it if it fails for reasons that
+// appear to be transient. Retries are counted in metrics. Each attempt waits
+// until any limits configured for the remote's host permit it. If the command
+// ultimately fails with output matching a well-known failure signature, the
+// error is a DiagnosedError.
+func (n *networkRetrier) exec(cmd *exec.Cmd, repoURL string) ([]byte, error) {
+	return n.execContext(context.Background(), cmd, repoURL)
+}
+
+// execContext is like exec, but stops retrying once the provided context is
+// done, and any retries are killed if it is done before they complete. The
+// provided command is expected to have been bound to the same context.
+func (n *networkRetrier) execContext(
+	ctx context.Context,
+	cmd *exec.Cmd,
+	repoURL string,
+) ([]byte, error) {
+	var res []byte
+	var attempt int
+	// Running a command sets its output streams, so retries are copied from a
+	// copy that was made before the first attempt.
+	template := commandWithContext(ctx, cmd)
+	isTransient := func(err error) bool {
+		return ctx.Err() == nil && n.isTransient(err)
+	}
+	err := retry.OnError(n.backoff, isTransient, func() error {
+		attempt++
+		attemptCmd := cmd
+		if attempt > 1 {
+			// A command can only be run once, so each retry requires a copy.
+			attemptCmd = commandWithContext(ctx, template)
+			if seeker, ok := attemptCmd.Stdin.(io.Seeker); ok {
+				// Replay the input that the previous attempt consumed.
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+			}
+			operationRetries.WithLabelValues(gitOperation(cmd.Args), metricsHost(repoURL)).Inc()
+			logging.LoggerFromContext(ctx).Debug(
+				"retrying git command after transient error",
+				"repo", repoURL,
+				"attempt", attempt,
+				"maxAttempts", n.backoff.Steps,
+			)
+		}
+		release, err := hostLimits.Load().acquire(ctx, repoURL)
+		if err != nil {
+			return err
+		}
+		defer release()
+		res, err = execGitCommand(attemptCmd, repoURL)
+		return err
+	})
+	return res, diagnoseError(err)
+}
+
+// commandWithContext returns a copy of the provided command that has not been
+// started yet. The copy is killed if the provided context is done before it
+// completes.
+func commandWithContext(ctx context.Context, cmd *exec.Cmd) *exec.Cmd {
+	c := exec.CommandContext(ctx, cmd.Path, cmd.Args[1:]...) // nolint: gosec
+	c.Args = cmd.Args
+	c.Env = cmd.Env
+	c.Dir = cmd.Dir
+	c.Stdin = cmd.Stdin
+	c.Stdout = cmd.Stdout
+	c.Stderr = cmd.Stderr
+	c.WaitDelay = cmd.WaitDelay
+	return c
+}
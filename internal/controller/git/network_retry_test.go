@@ -0,0 +1,209 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+func Test_newNetworkRetrier(t *testing.T) {
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := newNetworkRetrier(NetworkRetryConfig{
+			TransientErrorPatterns: []string{"("},
+		})
+		require.ErrorContains(t, err, "error compiling transient error pattern")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		r, err := newNetworkRetrier(NetworkRetryConfig{
+			MaxAttempts:            5,
+			TransientErrorPatterns: []string{"try again later"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, r.backoff.Steps)
+		require.Len(t, r.transientPatterns, len(defaultTransientErrorPatterns)+1)
+	})
+
+	t.Run("attempts are at least one", func(t *testing.T) {
+		r, err := newNetworkRetrier(NetworkRetryConfig{})
+		require.NoError(t, err)
+		require.Equal(t, 1, r.backoff.Steps)
+	})
+}
+
+func Test_networkRetrier_isTransient(t *testing.T) {
+	r, err := newNetworkRetrier(NetworkRetryConfig{
+		TransientErrorPatterns: []string{"try again later"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{
+			name:      "not an exit error",
+			err:       errors.New("early EOF"),
+			transient: false,
+		},
+		{
+			name: "unrecognized output",
+			err: &libExec.ExitError{
+				Output: []byte("fatal: something went wrong"),
+			},
+			transient: false,
+		},
+		{
+			name: "early EOF",
+			err: &libExec.ExitError{
+				Output: []byte("fetch-pack: unexpected disconnect\nfatal: early EOF"),
+			},
+			transient: true,
+		},
+		{
+			name: "server error",
+			err: &libExec.ExitError{
+				Output: []byte("error: RPC failed; HTTP 500 curl 22 The requested URL returned error: 500"),
+			},
+			transient: true,
+		},
+		{
+			name: "DNS failure",
+			err: &libExec.ExitError{
+				Output: []byte("fatal: unable to access 'https://github.com/akuity/kargo/': Could not resolve host: github.com"),
+			},
+			transient: true,
+		},
+		{
+			name: "configured pattern",
+			err: &libExec.ExitError{
+				Output: []byte("remote: please try again later"),
+			},
+			transient: true,
+		},
+		{
+			name: "authentication failure",
+			err: &libExec.ExitError{
+				Output: []byte("fatal: Authentication failed for 'https://github.com/akuity/kargo/'"),
+			},
+			transient: false,
+		},
+		{
+			name: "repository not found",
+			err: &libExec.ExitError{
+				Output: []byte("remote: Repository not found.\nfatal: the remote end hung up unexpectedly"),
+			},
+			transient: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.transient, r.isTransient(testCase.err))
+		})
+	}
+}
+
+func Test_networkRetrier_exec(t *testing.T) {
+	r, err := newNetworkRetrier(NetworkRetryConfig{})
+	require.NoError(t, err)
+	r.backoff = wait.Backoff{
+		Steps:    3,
+		Duration: time.Millisecond,
+	}
+
+	// This script fails with a transient error until it has been run the
+	// specified number of times.
+	newCmd := func(t *testing.T, failures int) (*exec.Cmd, string) {
+		countFile := filepath.Join(t.TempDir(), "count")
+		cmd := exec.Command(
+			"sh", "-c",
+			`echo x >> "$0"; `+
+				`test "$(wc -l < "$0")" -gt "$1" || { echo "fatal: early EOF"; exit 128; }; `+
+				`echo done`,
+			countFile, strconv.Itoa(failures),
+		)
+		return cmd, countFile
+	}
+	attempts := func(t *testing.T, countFile string) int {
+		data, err := os.ReadFile(countFile)
+		require.NoError(t, err)
+		return strings.Count(string(data), "\n")
+	}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		retries := operationRetries.WithLabelValues("other", "github.com")
+		before := testutil.ToFloat64(retries)
+		cmd, countFile := newCmd(t, 2)
+		res, err := r.exec(cmd, "https://github.com/akuity/kargo")
+		require.NoError(t, err)
+		require.Equal(t, "done\n", string(res))
+		require.Equal(t, 3, attempts(t, countFile))
+		require.Equal(t, before+2, testutil.ToFloat64(retries))
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		cmd, countFile := newCmd(t, 3)
+		_, err := r.exec(cmd, "https://github.com/akuity/kargo")
+		require.ErrorContains(t, err, "early EOF")
+		require.Equal(t, 3, attempts(t, countFile))
+	})
+
+	t.Run("does not retry permanent failures", func(t *testing.T) {
+		cmd := exec.Command("sh", "-c", `echo "fatal: Authentication failed"; exit 128`)
+		_, err := r.exec(cmd, "https://github.com/akuity/kargo")
+		require.ErrorContains(t, err, "Authentication failed")
+	})
+
+	t.Run("retries receive the same input", func(t *testing.T) {
+		cmd, countFile := newCmd(t, 1)
+		cmd.Args[2] = `cat; ` + cmd.Args[2]
+		cmd.Stdin = strings.NewReader("input\n")
+		res, err := r.exec(cmd, "https://github.com/akuity/kargo")
+		require.NoError(t, err)
+		require.Equal(t, "input\ndone\n", string(res))
+		require.Equal(t, 2, attempts(t, countFile))
+	})
+
+	t.Run("does not retry once context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cmd, countFile := newCmd(t, 2)
+		_, err := r.execContext(ctx, cmd, "https://github.com/akuity/kargo")
+		require.ErrorContains(t, err, "early EOF")
+		require.Equal(t, 1, attempts(t, countFile))
+	})
+}
+
+func Test_commandWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.Command("sleep", "10")
+	cmd.Dir = t.TempDir()
+	cmd.Env = []string{"FOO=bar"}
+	cmd.Stdin = strings.NewReader("input")
+	cmd.Stdout = &bytes.Buffer{}
+	cmd.Stderr = &bytes.Buffer{}
+	c := commandWithContext(ctx, cmd)
+	require.Equal(t, cmd.Args, c.Args)
+	require.Equal(t, cmd.Dir, c.Dir)
+	require.Equal(t, cmd.Env, c.Env)
+	require.Same(t, cmd.Stdin, c.Stdin)
+	require.Same(t, cmd.Stdout, c.Stdout)
+	require.Same(t, cmd.Stderr, c.Stderr)
+	require.NoError(t, c.Start())
+	cancel()
+	require.Error(t, c.Wait())
+}
@@ -0,0 +1,485 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+// ErrRemoteBranchNotFound is returned by RemoteBranchCommit when the specified
+// branch does not exist in the remote repository.
+var ErrRemoteBranchNotFound = errors.New("remote branch not found")
+
+// ErrPushForbidden is returned by CheckRemotePushAccess when the remote
+// repository refuses to accept pushes using the credentials that were
+// presented.
+var ErrPushForbidden = errors.New("push forbidden")
+
+// ErrRemoteBranchMoved is returned by ResetRemoteBranch when the specified
+// branch of the remote repository no longer points to the expected commit.
+var ErrRemoteBranchMoved = errors.New("remote branch moved")
+
+// pushForbiddenPatterns match output of git push commands that failed because
+// the credentials that were presented, although valid, do not permit pushing.
+var pushForbiddenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`The requested URL returned error: 403`),
+	regexp.MustCompile(`Permission to \S+ denied`),
+	regexp.MustCompile(`[Ww]rite access to repository not granted`),
+	regexp.MustCompile(`You are not allowed to push code`),
+	regexp.MustCompile(`GenericContribute`),
+	regexp.MustCompile(`pre-receive hook declined`),
+}
+
+// RemoteBranchCommit returns the ID (SHA) of the commit that the specified
+// branch of the remote Git repository at the specified URL currently points
+// to. Unlike the methods of a Repo, BareRepo, or WorkTree, this does not
+// require a clone of the repository. ErrRemoteBranchNotFound is returned if
+// the branch does not exist.
+func RemoteBranchCommit(
+	repoURL string,
+	branch string,
+	clientOpts *ClientOptions,
+) (string, error) {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(b.homeDir)
+	res, err := b.execNetworkCommand(b.buildGitCommand(
+		"ls-remote",
+		"--heads",
+		"--exit-code", // Return 2 if not found
+		b.url,
+		"refs/heads/"+branch,
+	))
+	var exitErr *libExec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode == 2 {
+		return "", fmt.Errorf(
+			"branch %q of remote repo %q: %w", branch, repoURL, ErrRemoteBranchNotFound,
+		)
+	}
+	if err != nil {
+		return "", fmt.Errorf(
+			"error getting commit of branch %q in remote repo %q: %w",
+			branch, repoURL, err,
+		)
+	}
+	// Output is of the form "<sha>\trefs/heads/<branch>"
+	commitID, _, _ := strings.Cut(strings.TrimSpace(string(res)), "\t")
+	return commitID, nil
+}
+
+// RemoteHeads describes the branches of a remote Git repository, as reported
+// by a single git ls-remote.
+type RemoteHeads struct {
+	// DefaultBranch is the name of the branch that HEAD of the remote
+	// repository points to. It is empty if HEAD does not point to a branch that
+	// exists.
+	DefaultBranch string
+	// Branches maps the name of each branch of the remote repository to the ID
+	// (SHA) of the commit it points to.
+	Branches map[string]string
+}
+
+// ListRemoteHeads returns the default branch and all branches of the remote Git
+// repository at the specified URL using a single git ls-remote. Unlike the
+// methods of a Repo, BareRepo, or WorkTree, this does not require a clone of
+// the repository.
+func ListRemoteHeads(
+	repoURL string,
+	clientOpts *ClientOptions,
+) (*RemoteHeads, error) {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(b.homeDir)
+	res, err := b.execNetworkCommand(b.buildGitCommand(
+		"ls-remote",
+		"--symref",
+		b.url,
+		"HEAD",
+		"refs/heads/*",
+	))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error listing branches of remote repo %q: %w", repoURL, err,
+		)
+	}
+	heads := &RemoteHeads{Branches: map[string]string{}}
+	for _, line := range strings.Split(strings.TrimSpace(string(res)), "\n") {
+		// HEAD is reported as "ref: refs/heads/<branch>\tHEAD" if it points to a
+		// branch that exists. Each branch is reported as
+		// "<sha>\trefs/heads/<branch>".
+		if ref, found := strings.CutPrefix(line, "ref: refs/heads/"); found {
+			heads.DefaultBranch, _, _ = strings.Cut(ref, "\t")
+			continue
+		}
+		commitID, ref, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+		if branch, found := strings.CutPrefix(ref, "refs/heads/"); found {
+			heads.Branches[branch] = commitID
+		}
+	}
+	// HEAD may point to a branch that was not listed, if it was created in
+	// between.
+	if _, ok := heads.Branches[heads.DefaultBranch]; !ok {
+		heads.DefaultBranch = ""
+	}
+	return heads, nil
+}
+
+// ListRemoteBranches returns the names of all branches of the remote Git
+// repository at the specified URL whose names begin with the specified prefix.
+// Unlike the methods of a Repo, BareRepo, or WorkTree, this does not require a
+// clone of the repository.
+func ListRemoteBranches(
+	repoURL string,
+	prefix string,
+	clientOpts *ClientOptions,
+) ([]string, error) {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(b.homeDir)
+	res, err := b.execNetworkCommand(b.buildGitCommand(
+		"ls-remote",
+		"--heads",
+		b.url,
+		"refs/heads/"+prefix+"*",
+	))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error listing branches of remote repo %q: %w", repoURL, err,
+		)
+	}
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(res)), "\n") {
+		// Each line is of the form "<sha>\trefs/heads/<branch>"
+		_, ref, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+		if branch := strings.TrimPrefix(ref, "refs/heads/"); strings.HasPrefix(branch, prefix) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches, nil
+}
+
+// DeleteRemoteBranch deletes the specified branch from the remote Git
+// repository at the specified URL. Unlike the methods of a Repo, BareRepo, or
+// WorkTree, this does not require a clone of the repository.
+// ErrRemoteBranchNotFound is returned if the branch does not exist.
+func DeleteRemoteBranch(
+	repoURL string,
+	branch string,
+	clientOpts *ClientOptions,
+) error {
+	// The output of a failed push is not meant to be parsed, so whether the
+	// branch exists is determined beforehand using the exit code of ls-remote.
+	if _, err := RemoteBranchCommit(repoURL, branch, clientOpts); err != nil {
+		return err
+	}
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(b.homeDir)
+	if _, err = libExec.Exec(b.buildGitCommand("init", "--bare", b.dir)); err != nil {
+		return fmt.Errorf("error initializing repo for remote %q: %w", repoURL, err)
+	}
+	if _, err = b.execNetworkCommand(b.buildGitCommand(
+		"push",
+		b.url,
+		"--delete",
+		branch,
+	)); err != nil {
+		return fmt.Errorf(
+			"error deleting branch %q from remote repo %q: %w", branch, repoURL, err,
+		)
+	}
+	return nil
+}
+
+// TagRemoteCommit creates a lightweight tag with the specified name for the
+// specified commit of the remote Git repository at the specified URL. The
+// commit must be reachable from the specified branch. Unlike the methods of a
+// Repo, BareRepo, or WorkTree, this does not require a clone of the
+// repository, although the branch is fetched. Tagging a commit with a tag that
+// already refers to it is a no-op.
+func TagRemoteCommit(
+	repoURL string,
+	branch string,
+	commit string,
+	tag string,
+	clientOpts *ClientOptions,
+) error {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(b.homeDir)
+	if err = fetchRemoteBranchInto(b, branch); err != nil {
+		return err
+	}
+	if _, err = b.execNetworkCommand(b.buildGitCommand(
+		"push",
+		b.url,
+		commit+":refs/tags/"+tag,
+	)); err != nil {
+		return fmt.Errorf(
+			"error pushing tag %q for commit %q to remote repo %q: %w",
+			tag, commit, repoURL, err,
+		)
+	}
+	return nil
+}
+
+// ResetRemoteBranch resets the specified branch of the remote Git repository
+// at the specified URL to the specified commit, which must be reachable from
+// the branch, but only if the branch still points to the expected commit. If
+// the commit to reset to is empty, the branch is deleted instead. Unlike the
+// methods of a Repo, BareRepo, or WorkTree, this does not require a clone of
+// the repository, although the branch is fetched. An error wrapping
+// ErrRemoteBranchMoved is returned if the branch no longer points to the
+// expected commit, in which case it is left as is.
+func ResetRemoteBranch(
+	repoURL string,
+	branch string,
+	expected string,
+	commit string,
+	clientOpts *ClientOptions,
+) error {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(b.homeDir)
+	if err = fetchRemoteBranchInto(b, branch); err != nil {
+		return err
+	}
+	if _, err = b.execNetworkCommand(b.buildGitCommand(
+		"push",
+		fmt.Sprintf("--force-with-lease=refs/heads/%s:%s", branch, expected),
+		b.url,
+		commit+":refs/heads/"+branch,
+	)); err != nil {
+		if strings.Contains(err.Error(), "stale info") {
+			return fmt.Errorf(
+				"branch %q of remote repo %q: %w", branch, repoURL, ErrRemoteBranchMoved,
+			)
+		}
+		return fmt.Errorf(
+			"error resetting branch %q of remote repo %q: %w", branch, repoURL, err,
+		)
+	}
+	return nil
+}
+
+// RemoteCommitTrees returns the IDs of the trees of the specified commits of
+// the remote Git repository at the specified URL, keyed by commit ID. Commits
+// with identical trees have identical content. Unlike the methods of a Repo,
+// BareRepo, or WorkTree, this does not require a clone of the repository. Only
+// the commits themselves are fetched, without their history or, if the remote
+// supports partial clones, their content.
+func RemoteCommitTrees(
+	repoURL string,
+	commits []string,
+	clientOpts *ClientOptions,
+) (map[string]string, error) {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(b.homeDir)
+	if _, err = libExec.Exec(b.buildGitCommand("init", "--bare", b.dir)); err != nil {
+		return nil, fmt.Errorf("error initializing repo for remote %q: %w", repoURL, err)
+	}
+	args := append([]string{"fetch", "--depth=1", "--filter=tree:0", b.url}, commits...)
+	if _, err = b.execNetworkCommand(b.buildGitCommand(args...)); err != nil {
+		return nil, fmt.Errorf(
+			"error fetching commits %v of remote repo %q: %w", commits, repoURL, err,
+		)
+	}
+	trees := make(map[string]string, len(commits))
+	for _, commit := range commits {
+		res, err := libExec.Exec(b.buildGitCommand("rev-parse", commit+"^{tree}"))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error getting tree of commit %q of remote repo %q: %w", commit, repoURL, err,
+			)
+		}
+		trees[commit] = strings.TrimSpace(string(res))
+	}
+	return trees, nil
+}
+
+// RemoteIsAncestor returns true if the specified ancestor commit of the remote
+// Git repository at the specified URL is an ancestor of, or identical to, the
+// specified descendant commit. Unlike the methods of a Repo, BareRepo, or
+// WorkTree, this does not require a clone of the repository, and it does not
+// depend on the history available in any existing, possibly shallow, clone.
+// Only the two commits and their ancestors are fetched, without, if the remote
+// supports partial clones, their content.
+func RemoteIsAncestor(
+	repoURL string,
+	ancestor string,
+	descendant string,
+	clientOpts *ClientOptions,
+) (bool, error) {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(b.homeDir)
+	if _, err = libExec.Exec(b.buildGitCommand("init", "--bare", b.dir)); err != nil {
+		return false, fmt.Errorf("error initializing repo for remote %q: %w", repoURL, err)
+	}
+	if _, err = b.execNetworkCommand(b.buildGitCommand(
+		"fetch",
+		"--filter=tree:0",
+		b.url,
+		ancestor,
+		descendant,
+	)); err != nil {
+		return false, fmt.Errorf(
+			"error fetching commits %q and %q of remote repo %q: %w",
+			ancestor, descendant, repoURL, err,
+		)
+	}
+	_, err = libExec.Exec(b.buildGitCommand(
+		"merge-base",
+		"--is-ancestor",
+		ancestor,
+		descendant,
+	))
+	var exitErr *libExec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf(
+			"error determining whether commit %q is an ancestor of commit %q of "+
+				"remote repo %q: %w",
+			ancestor, descendant, repoURL, err,
+		)
+	}
+	return true, nil
+}
+
+// fetchRemoteBranchInto initializes a bare repository in the directory of the
+// provided baseRepo, which must have been returned by newRemoteRepo, and
+// fetches the specified branch of the remote repository into it. An error
+// wrapping ErrRemoteBranchNotFound is returned if the branch does not exist.
+func fetchRemoteBranchInto(b *baseRepo, branch string) error {
+	if _, err := libExec.Exec(b.buildGitCommand("init", "--bare", b.dir)); err != nil {
+		return fmt.Errorf("error initializing repo for remote %q: %w", b.url, err)
+	}
+	if _, err := b.execNetworkCommand(b.buildGitCommand(
+		"fetch",
+		b.url,
+		"refs/heads/"+branch,
+	)); err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			return fmt.Errorf(
+				"branch %q of remote repo %q: %w", branch, b.url, ErrRemoteBranchNotFound,
+			)
+		}
+		return fmt.Errorf(
+			"error fetching branch %q of remote repo %q: %w", branch, b.url, err,
+		)
+	}
+	return nil
+}
+
+// CheckRemotePushAccess determines, without changing anything, whether the
+// remote Git repository at the specified URL accepts pushes to the specified
+// branch, which need not exist yet, using the specified client options. Unlike
+// the methods of a Repo, BareRepo, or WorkTree, this does not require a clone
+// of the repository. An error wrapping ErrPushForbidden is returned if the
+// remote refuses the push. Any other error indicates that access could not be
+// determined.
+//
+// Because nothing is actually pushed, rules that the remote evaluates only
+// upon receiving commits, such as most forms of branch protection, are not
+// taken into account.
+func CheckRemotePushAccess(
+	repoURL string,
+	branch string,
+	clientOpts *ClientOptions,
+) error {
+	b, err := newRemoteRepo(repoURL, clientOpts)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(b.homeDir)
+	if _, err = libExec.Exec(b.buildGitCommand("init", "--bare", b.dir)); err != nil {
+		return fmt.Errorf("error initializing repo for remote %q: %w", repoURL, err)
+	}
+	// A dry run of deleting the branch requires the remote to authorize a push
+	// without there being anything to push.
+	_, err = b.execNetworkCommand(b.buildGitCommand(
+		"push",
+		"--dry-run",
+		b.url,
+		":refs/heads/"+branch,
+	))
+	if err == nil || strings.Contains(err.Error(), "remote ref does not exist") {
+		return nil
+	}
+	var exitErr *libExec.ExitError
+	if errors.As(err, &exitErr) {
+		for _, regex := range pushForbiddenPatterns {
+			if regex.Match(exitErr.Output) {
+				return fmt.Errorf(
+					"%w: remote repo %q refused push to branch %q: %s",
+					ErrPushForbidden, repoURL, branch,
+					strings.TrimSpace(string(exitErr.Output)),
+				)
+			}
+		}
+	}
+	return fmt.Errorf(
+		"error checking push access to branch %q of remote repo %q: %w",
+		branch, repoURL, err,
+	)
+}
+
+// newRemoteRepo returns a baseRepo that can be used for executing commands
+// against the remote Git repository at the specified URL without cloning it.
+// The caller is responsible for removing the baseRepo's home directory.
+func newRemoteRepo(repoURL string, clientOpts *ClientOptions) (*baseRepo, error) {
+	if clientOpts == nil {
+		clientOpts = &ClientOptions{}
+	}
+	homeDir, err := os.MkdirTemp("", "repo-")
+	if err != nil {
+		return nil,
+			fmt.Errorf("error creating home directory for repo %q: %w", repoURL, err)
+	}
+	if homeDir, err = filepath.EvalSymlinks(homeDir); err != nil {
+		_ = os.RemoveAll(homeDir)
+		return nil,
+			fmt.Errorf("error resolving symlinks in path %s: %w", homeDir, err)
+	}
+	b := &baseRepo{
+		creds:   clientOpts.Credentials,
+		dir:     homeDir,
+		homeDir: homeDir,
+		url:     repoURL,
+	}
+	if err = b.setupClient(clientOpts); err != nil {
+		_ = os.RemoveAll(homeDir)
+		return nil, err
+	}
+	return b, nil
+}
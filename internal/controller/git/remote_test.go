@@ -0,0 +1,448 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sosedoff/gitkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteBranchCommit(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	err = setupRep.AddAllAndCommit("initial commit")
+	require.NoError(t, err)
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	expectedCommitID, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+
+	t.Run("branch exists", func(t *testing.T) {
+		commitID, err := RemoteBranchCommit(testRepoURL, "master", nil)
+		require.NoError(t, err)
+		require.Equal(t, expectedCommitID, commitID)
+	})
+
+	t.Run("branch does not exist", func(t *testing.T) {
+		_, err := RemoteBranchCommit(testRepoURL, "nonexistent", nil)
+		require.ErrorIs(t, err, ErrRemoteBranchNotFound)
+	})
+}
+
+func TestListRemoteHeads(t *testing.T) {
+	serverDir := t.TempDir()
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        serverDir,
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	err = setupRep.AddAllAndCommit("initial commit")
+	require.NoError(t, err)
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	err = setupRep.Push(&PushOptions{TargetBranch: "env-config"})
+	require.NoError(t, err)
+	expectedCommitID, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+
+	setRemoteHead := func(t *testing.T, branch string) {
+		require.NoError(t, os.WriteFile(
+			filepath.Join(serverDir, "test.git", "HEAD"),
+			[]byte("ref: refs/heads/"+branch+"\n"),
+			0o600,
+		))
+	}
+
+	t.Run("HEAD points to an existing branch", func(t *testing.T) {
+		setRemoteHead(t, "env-config")
+		heads, err := ListRemoteHeads(testRepoURL, nil)
+		require.NoError(t, err)
+		require.Equal(t, "env-config", heads.DefaultBranch)
+		require.Equal(
+			t,
+			map[string]string{
+				"master":     expectedCommitID,
+				"env-config": expectedCommitID,
+			},
+			heads.Branches,
+		)
+	})
+
+	t.Run("HEAD points to a missing branch", func(t *testing.T) {
+		setRemoteHead(t, "missing")
+		heads, err := ListRemoteHeads(testRepoURL, nil)
+		require.NoError(t, err)
+		require.Empty(t, heads.DefaultBranch)
+		require.Len(t, heads.Branches, 2)
+	})
+}
+
+func TestListAndDeleteRemoteBranches(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	err = setupRep.AddAllAndCommit("initial commit")
+	require.NoError(t, err)
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	for _, branch := range []string{"kargo/promotion/a", "kargo/promotion/b", "other"} {
+		err = setupRep.Push(&PushOptions{TargetBranch: branch})
+		require.NoError(t, err)
+	}
+
+	branches, err := ListRemoteBranches(testRepoURL, "kargo/promotion/", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kargo/promotion/a", "kargo/promotion/b"}, branches)
+
+	require.NoError(t, DeleteRemoteBranch(testRepoURL, "kargo/promotion/a", nil))
+
+	branches, err = ListRemoteBranches(testRepoURL, "kargo/promotion/", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kargo/promotion/b"}, branches)
+
+	err = DeleteRemoteBranch(testRepoURL, "kargo/promotion/a", nil)
+	require.ErrorIs(t, err, ErrRemoteBranchNotFound)
+
+	branches, err = ListRemoteBranches(testRepoURL, "nonexistent/", nil)
+	require.NoError(t, err)
+	require.Empty(t, branches)
+}
+
+func TestCheckRemotePushAccess(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	var readOnly atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if readOnly.Load() && (r.URL.Query().Get("service") == "git-receive-pack" ||
+				strings.HasSuffix(r.URL.Path, "/git-receive-pack")) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			service.ServeHTTP(w, r)
+		},
+	))
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	err = setupRep.AddAllAndCommit("initial commit")
+	require.NoError(t, err)
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	expectedCommitID, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+
+	t.Run("branch exists", func(t *testing.T) {
+		require.NoError(t, CheckRemotePushAccess(testRepoURL, "master", nil))
+		// Nothing was changed
+		commitID, err := RemoteBranchCommit(testRepoURL, "master", nil)
+		require.NoError(t, err)
+		require.Equal(t, expectedCommitID, commitID)
+	})
+
+	t.Run("branch does not exist", func(t *testing.T) {
+		require.NoError(t, CheckRemotePushAccess(testRepoURL, "nonexistent", nil))
+	})
+
+	t.Run("push forbidden", func(t *testing.T) {
+		readOnly.Store(true)
+		defer readOnly.Store(false)
+		err := CheckRemotePushAccess(testRepoURL, "master", nil)
+		require.ErrorIs(t, err, ErrPushForbidden)
+		// Reads are still allowed
+		_, err = RemoteBranchCommit(testRepoURL, "master", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("remote unreachable", func(t *testing.T) {
+		err := CheckRemotePushAccess("http://127.0.0.1:1/test.git", "master", nil)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrPushForbidden)
+	})
+}
+
+func TestTagAndResetRemoteBranch(t *testing.T) {
+	serviceDir := t.TempDir()
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        serviceDir,
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	commitIDs := make([]string, 3)
+	for i := range commitIDs {
+		err = os.WriteFile(
+			filepath.Join(setupRep.Dir(), "test.txt"),
+			[]byte(fmt.Sprintf("foo-%d", i)),
+			0600,
+		)
+		require.NoError(t, err)
+		err = setupRep.AddAllAndCommit(fmt.Sprintf("commit %d", i))
+		require.NoError(t, err)
+		commitIDs[i], err = setupRep.LastCommitID()
+		require.NoError(t, err)
+	}
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+
+	t.Run("tag", func(t *testing.T) {
+		err := TagRemoteCommit(testRepoURL, "master", commitIDs[1], "kargo/failed/fake-promo", nil)
+		require.NoError(t, err)
+		res, err := exec.Command(
+			"git", "-C", filepath.Join(serviceDir, "test.git"),
+			"rev-parse", "refs/tags/kargo/failed/fake-promo",
+		).Output()
+		require.NoError(t, err)
+		require.Equal(t, commitIDs[1], strings.TrimSpace(string(res)))
+		// Tagging again is a no-op
+		err = TagRemoteCommit(testRepoURL, "master", commitIDs[1], "kargo/failed/fake-promo", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("tag branch does not exist", func(t *testing.T) {
+		err := TagRemoteCommit(testRepoURL, "nonexistent", commitIDs[1], "fake-tag", nil)
+		require.ErrorIs(t, err, ErrRemoteBranchNotFound)
+	})
+
+	t.Run("reset when branch has moved", func(t *testing.T) {
+		err := ResetRemoteBranch(testRepoURL, "master", commitIDs[1], commitIDs[0], nil)
+		require.ErrorIs(t, err, ErrRemoteBranchMoved)
+		// Nothing was changed
+		commitID, err := RemoteBranchCommit(testRepoURL, "master", nil)
+		require.NoError(t, err)
+		require.Equal(t, commitIDs[2], commitID)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		err := ResetRemoteBranch(testRepoURL, "master", commitIDs[2], commitIDs[1], nil)
+		require.NoError(t, err)
+		commitID, err := RemoteBranchCommit(testRepoURL, "master", nil)
+		require.NoError(t, err)
+		require.Equal(t, commitIDs[1], commitID)
+	})
+
+	t.Run("reset deletes branch without previous commit", func(t *testing.T) {
+		err := setupRep.Push(&PushOptions{TargetBranch: "rendered"})
+		require.NoError(t, err)
+		err = ResetRemoteBranch(testRepoURL, "rendered", commitIDs[2], "", nil)
+		require.NoError(t, err)
+		_, err = RemoteBranchCommit(testRepoURL, "rendered", nil)
+		require.ErrorIs(t, err, ErrRemoteBranchNotFound)
+	})
+}
+
+func TestRemoteCommitTrees(t *testing.T) {
+	serviceDir := t.TempDir()
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        serviceDir,
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	// The first and last commits have identical contents.
+	commitIDs := make([]string, 3)
+	for i, content := range []string{"foo", "bar", "foo"} {
+		err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte(content), 0600)
+		require.NoError(t, err)
+		err = setupRep.AddAllAndCommit(fmt.Sprintf("commit %d", i))
+		require.NoError(t, err)
+		commitIDs[i], err = setupRep.LastCommitID()
+		require.NoError(t, err)
+	}
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	// Like most Git hosting services, permit fetching commits that are not the
+	// tip of a branch, and partial clones.
+	for _, cfg := range [][]string{
+		{"uploadpack.allowReachableSHA1InWant", "true"},
+		{"uploadpack.allowFilter", "true"},
+	} {
+		err = exec.Command(
+			"git", "-C", filepath.Join(serviceDir, "test.git"), "config", cfg[0], cfg[1],
+		).Run()
+		require.NoError(t, err)
+	}
+
+	t.Run("commits exist", func(t *testing.T) {
+		trees, err := RemoteCommitTrees(testRepoURL, commitIDs, nil)
+		require.NoError(t, err)
+		require.Len(t, trees, 3)
+		require.Equal(t, trees[commitIDs[0]], trees[commitIDs[2]])
+		require.NotEqual(t, trees[commitIDs[0]], trees[commitIDs[1]])
+	})
+
+	t.Run("commit does not exist", func(t *testing.T) {
+		_, err := RemoteCommitTrees(
+			testRepoURL,
+			[]string{commitIDs[0], "0123456789abcdef0123456789abcdef01234567"},
+			nil,
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestRemoteIsAncestor(t *testing.T) {
+	serviceDir := t.TempDir()
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        serviceDir,
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	commitIDs := make([]string, 2)
+	for i := range commitIDs {
+		err = os.WriteFile(
+			filepath.Join(setupRep.Dir(), "test.txt"), []byte(fmt.Sprintf("%d", i)), 0600,
+		)
+		require.NoError(t, err)
+		err = setupRep.AddAllAndCommit(fmt.Sprintf("commit %d", i))
+		require.NoError(t, err)
+		commitIDs[i], err = setupRep.LastCommitID()
+		require.NoError(t, err)
+	}
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	// A commit on another branch, diverging from the first commit.
+	err = setupRep.CreateChildBranch("diverged")
+	require.NoError(t, err)
+	err = exec.Command("git", "-C", setupRep.Dir(), "reset", "--hard", commitIDs[0]).Run()
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("diverged"), 0600)
+	require.NoError(t, err)
+	err = setupRep.AddAllAndCommit("diverged commit")
+	require.NoError(t, err)
+	divergedCommitID, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+	err = setupRep.Push(nil)
+	require.NoError(t, err)
+	for _, cfg := range [][]string{
+		{"uploadpack.allowReachableSHA1InWant", "true"},
+		{"uploadpack.allowFilter", "true"},
+	} {
+		err = exec.Command(
+			"git", "-C", filepath.Join(serviceDir, "test.git"), "config", cfg[0], cfg[1],
+		).Run()
+		require.NoError(t, err)
+	}
+
+	t.Run("ancestor", func(t *testing.T) {
+		isAncestor, err := RemoteIsAncestor(testRepoURL, commitIDs[0], commitIDs[1], nil)
+		require.NoError(t, err)
+		require.True(t, isAncestor)
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		isAncestor, err := RemoteIsAncestor(testRepoURL, commitIDs[1], commitIDs[1], nil)
+		require.NoError(t, err)
+		require.True(t, isAncestor)
+	})
+
+	t.Run("descendant", func(t *testing.T) {
+		isAncestor, err := RemoteIsAncestor(testRepoURL, commitIDs[1], commitIDs[0], nil)
+		require.NoError(t, err)
+		require.False(t, isAncestor)
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		isAncestor, err := RemoteIsAncestor(testRepoURL, commitIDs[1], divergedCommitID, nil)
+		require.NoError(t, err)
+		require.False(t, isAncestor)
+	})
+
+	t.Run("commit does not exist", func(t *testing.T) {
+		_, err := RemoteIsAncestor(
+			testRepoURL,
+			commitIDs[0],
+			"0123456789abcdef0123456789abcdef01234567",
+			nil,
+		)
+		require.Error(t, err)
+	})
+}
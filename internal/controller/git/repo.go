@@ -1,16 +1,19 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
-
-	libExec "github.com/akuity/kargo/internal/exec"
 )
 
 // Repo is an interface for interacting with a Git repository with a single
 // working tree.
 type Repo interface {
+	// AuthMethod returns the means by which the repository authenticated to the
+	// remote repository when it was cloned.
+	AuthMethod() AuthMethod
 	// Close cleans up file system resources used by this repository. This should
 	// always be called before a repository goes out of scope.
 	Close() error
@@ -124,9 +127,11 @@ func (r *repo) clone(opts *CloneOptions) error {
 		args = append(args, "--depth", fmt.Sprint(opts.Depth))
 	}
 	args = append(args, r.url, r.dir)
-	cmd := r.buildGitCommand(args...)
-	cmd.Dir = r.homeDir // Override the cmd.Dir that's set by r.buildGitCommand()
-	if _, err := libExec.Exec(cmd); err != nil {
+	if err := r.execCloneCommand(context.Background(), func() *exec.Cmd {
+		cmd := r.buildGitCommand(args...)
+		cmd.Dir = r.homeDir // Override the cmd.Dir that's set by r.buildGitCommand()
+		return cmd
+	}); err != nil {
 		return fmt.Errorf("error cloning repo %q into %q: %w", r.url, r.dir, err)
 	}
 	return nil
@@ -157,9 +162,15 @@ func LoadRepo(path string, opts *LoadRepoOptions) (Repo, error) {
 		return nil,
 			fmt.Errorf(`error reading URL of remote "origin" from config: %w`, err)
 	}
+	if err := r.loadInsecureNoAuth(); err != nil {
+		return nil, err
+	}
 	if err := r.setupAuth(); err != nil {
 		return nil, fmt.Errorf("error configuring the credentials: %w", err)
 	}
+	if err := r.loadAuthMethod(); err != nil {
+		return nil, err
+	}
 	return r, nil
 }
 
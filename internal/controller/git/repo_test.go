@@ -5,6 +5,9 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -63,6 +66,7 @@ func TestRepo(t *testing.T) {
 		require.NoError(t, err)
 		repoURL.User = nil
 		require.Equal(t, testRepoURL, repoURL.String())
+		require.Equal(t, AuthMethodHTTPS, rep.AuthMethod())
 		require.NotEmpty(t, r.homeDir)
 		var fi os.FileInfo
 		fi, err = os.Stat(r.homeDir)
@@ -104,7 +108,9 @@ func TestRepo(t *testing.T) {
 	})
 
 	testCommitMessage := fmt.Sprintf("test commit %s", uuid.NewString())
-	err = rep.AddAllAndCommit(testCommitMessage)
+	err = rep.AddAllAndCommit(
+		testCommitMessage + "\n\nSome details\n\nKargo-Promotion: fake-project/fake-promotion",
+	)
 	require.NoError(t, err)
 
 	t.Run("can commit", func(t *testing.T) {
@@ -126,6 +132,13 @@ func TestRepo(t *testing.T) {
 		require.Equal(t, testCommitMessage, msg)
 	})
 
+	t.Run("can get commit trailers by id", func(t *testing.T) {
+		var trailers string
+		trailers, err = rep.CommitTrailers(lastCommitID)
+		require.NoError(t, err)
+		require.Equal(t, "Kargo-Promotion: fake-project/fake-promotion", trailers)
+	})
+
 	t.Run("can get diff paths", func(t *testing.T) {
 		var paths []string
 		paths, err = rep.GetDiffPathsForCommitID(lastCommitID)
@@ -204,3 +217,74 @@ func TestRepo(t *testing.T) {
 	})
 
 }
+
+func TestRepo_localRemote(t *testing.T) {
+	// A bare repository on the local file system serves as the remote
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	// Credentials that must never be used for a local remote
+	testRepoCreds := &RepoCredentials{
+		Username:      "fake-username",
+		Password:      "fake-password",
+		SSHPrivateKey: "fake-key",
+	}
+
+	for _, repoURL := range []string{"file://" + remoteDir, remoteDir} {
+		t.Run(repoURL, func(t *testing.T) {
+			rep, err := Clone(
+				repoURL,
+				&ClientOptions{Credentials: testRepoCreds},
+				nil,
+			)
+			require.NoError(t, err)
+			defer rep.Close()
+
+			// No SSH configuration was written
+			_, err = os.Stat(filepath.Join(rep.HomeDir(), ".ssh", "id_rsa"))
+			require.True(t, os.IsNotExist(err))
+
+			// The Git identity is still configured
+			cmd := exec.Command("git", "config", "--global", "user.name")
+			cmd.Env = []string{"HOME=" + rep.HomeDir()}
+			res, err := cmd.Output()
+			require.NoError(t, err)
+			require.Equal(t, defaultUsername, strings.TrimSpace(string(res)))
+
+			require.NoError(t, os.WriteFile(
+				filepath.Join(rep.Dir(), uuid.NewString()),
+				[]byte("foo"),
+				0600,
+			))
+			require.NoError(t, rep.AddAllAndCommit("commit"))
+			require.NoError(t, rep.Push(nil))
+		})
+	}
+}
+
+func TestBareRepo_insecureNoAuth(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	rep, err := CloneBare(
+		"file://"+remoteDir,
+		&ClientOptions{InsecureNoAuth: true},
+		nil,
+	)
+	require.NoError(t, err)
+	defer rep.Close()
+
+	// The setting survives reloading the repository from the file system, and
+	// any credentials provided at that time are ignored
+	loaded, err := LoadBareRepo(
+		rep.Dir(),
+		&LoadBareRepoOptions{
+			Credentials: &RepoCredentials{Password: "fake-password"},
+		},
+	)
+	require.NoError(t, err)
+	b, ok := loaded.(*bareRepo)
+	require.True(t, ok)
+	require.True(t, b.insecureNoAuth)
+	require.Nil(t, b.creds)
+}
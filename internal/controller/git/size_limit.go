@@ -0,0 +1,115 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrRepoTooLarge is returned when cloning a repository was aborted because
+// the repository exceeded the maximum size.
+var ErrRepoTooLarge = errors.New("repository too large")
+
+// IsRepoTooLarge returns true if the error is an ErrRepoTooLarge or wraps one
+// and false otherwise.
+func IsRepoTooLarge(err error) bool {
+	return errors.Is(err, ErrRepoTooLarge)
+}
+
+const (
+	// sizeWatchInterval is how often the size of a repository that is being
+	// cloned is checked.
+	sizeWatchInterval = 250 * time.Millisecond
+	// killWaitDelay is how long to wait for the output of a git command to be
+	// closed after it was killed, e.g. by helper processes it started.
+	killWaitDelay = 5 * time.Second
+)
+
+// sizeWatcher monitors the number of bytes written to a directory and cancels
+// a context once they exceed a maximum size.
+type sizeWatcher struct {
+	dir      string
+	maxSize  int64
+	cancel   context.CancelFunc
+	done     chan struct{}
+	wg       sync.WaitGroup
+	exceeded atomic.Bool
+}
+
+// watchSize starts monitoring the size of the provided directory, which need
+// not exist yet, and returns a context, derived from the provided one, that is
+// cancelled once the directory exceeds the provided maximum size. The returned
+// sizeWatcher must be stopped.
+func watchSize(
+	ctx context.Context,
+	dir string,
+	maxSize int64,
+) (context.Context, *sizeWatcher) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &sizeWatcher{
+		dir:     dir,
+		maxSize: maxSize,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return ctx, w
+}
+
+func (w *sizeWatcher) run() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(sizeWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if w.check() {
+				return
+			}
+		}
+	}
+}
+
+// check returns true, after cancelling the context, if the directory exceeds
+// the maximum size.
+func (w *sizeWatcher) check() bool {
+	if dirSize(w.dir) <= w.maxSize {
+		return false
+	}
+	w.exceeded.Store(true)
+	w.cancel()
+	return true
+}
+
+// stop stops monitoring the directory and returns true if it exceeded the
+// maximum size at any point, including now.
+func (w *sizeWatcher) stop() bool {
+	close(w.done)
+	w.wg.Wait()
+	defer w.cancel()
+	return w.exceeded.Load() || w.check()
+}
+
+// dirSize returns the total size of the regular files in the provided
+// directory and its subdirectories. Files that disappear while they are being
+// counted, e.g. temporary files of a running git command, are ignored.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil // nolint: nilerr
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -3,11 +3,16 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,10 +28,21 @@ type WorkTree interface {
 	// commit to the current branch and then commits them using the provided
 	// commit message.
 	AddAllAndCommit(message string) error
+	// AddSparseCheckoutPaths adds the specified directories, relative to the
+	// root of the repository, to the sparse checkout of the working tree and
+	// checks out the files within them. It returns an error if the working tree
+	// is not a sparse checkout.
+	AddSparseCheckoutPaths(paths ...string) error
+	// BareRepo returns the bare repository the working tree belongs to, e.g. so
+	// that working trees of other branches can be added to it.
+	BareRepo() BareRepo
 	// Clean cleans the working tree.
 	Clean() error
-	// Clear executes `git rm -rf .` to remove all files from the working tree.
-	Clear() error
+	// Clear executes `git rm -rf .` to remove all files from the working tree,
+	// except for any paths excluded using the provided ClearOptions. In a sparse
+	// checkout, files outside of the sparse checkout are removed as well, so
+	// that the next commit does not retain them.
+	Clear(opts *ClearOptions) error
 	// Close cleans up file system resources used by this working tree. This
 	// should always be called before a WorkTree goes out of scope.
 	Close() error
@@ -44,6 +60,10 @@ type WorkTree interface {
 	CurrentBranch() (string, error)
 	// DeleteBranch deletes the specified branch
 	DeleteBranch(branch string) error
+	// Diff returns a patch of the changes between the two specified commits,
+	// suitable for applying with git apply. Commits that are not present in the
+	// local repository are fetched from the remote repository first.
+	Diff(commit1 string, commit2 string) ([]byte, error)
 	// Dir returns an absolute path to the working tree.
 	Dir() string
 	// HasDiffs returns a bool indicating whether the working tree currently
@@ -71,11 +91,38 @@ type WorkTree interface {
 	// ListCommits returns a slice of commits in the current branch with
 	// metadata such as commit ID, commit date, and subject.
 	ListCommits(limit, skip uint) ([]CommitMetadata, error)
+	// MergeBase returns the ID of the best common ancestor of the two specified
+	// commits.
+	MergeBase(commit1 string, commit2 string) (string, error)
 	// CommitMessage returns the text of the most recent commit message associated
 	// with the specified commit ID.
 	CommitMessage(id string) (string, error)
+	// CommitTrailers returns the trailers of the commit message associated with
+	// the specified commit ID, one per line, as parsed by git.
+	CommitTrailers(id string) (string, error)
+	// CommitMessagesSince returns the full messages of the commits of the
+	// current branch that are not reachable from the specified commit, oldest
+	// first.
+	CommitMessagesSince(base string) ([]string, error)
+	// PullRebase pulls the specified branch from the remote repository and
+	// rebases the current branch on top of it. If the remote branch does not
+	// exist, this is a no-op.
+	PullRebase(branch string) error
+	// PullRebaseContext is like PullRebase, but the git commands it runs are
+	// killed if the provided context is done before they complete.
+	PullRebaseContext(ctx context.Context, branch string) error
+	// PullLFS installs Git LFS hooks and filters into the repository and
+	// fetches and checks out any Git LFS objects referenced by the current
+	// branch. It requires the git-lfs binary to be installed.
+	PullLFS() error
+	// PullLFSContext is like PullLFS, but the git commands it runs are killed
+	// if the provided context is done before they complete.
+	PullLFSContext(context.Context) error
 	// Push pushes from the local repository to the remote repository.
 	Push(*PushOptions) error
+	// PushContext is like Push, but the git commands it runs are killed if the
+	// provided context is done before they complete.
+	PushContext(context.Context, *PushOptions) error
 	// RefsHaveDiffs returns whether there is a diff between two commits/branches
 	RefsHaveDiffs(commit1 string, commit2 string) (bool, error)
 	// RemoteBranchExists returns a bool indicating if the specified branch exists
@@ -83,8 +130,34 @@ type WorkTree interface {
 	RemoteBranchExists(branch string) (bool, error)
 	// ResetHard performs a hard reset on the working tree.
 	ResetHard() error
+	// SquashSince replaces the commits of the current branch that are not
+	// reachable from the specified commit with a single commit on top of it,
+	// which has the provided message and the same content as the current
+	// HEAD.
+	SquashSince(base string, message string) error
+	// SubmoduleURLs returns the URLs of all submodules configured in the
+	// .gitmodules file at the root of the working tree.
+	SubmoduleURLs() ([]string, error)
+	// UpdateSubmodules initializes and checks out all submodules of the working
+	// tree, recursively.
+	UpdateSubmodules(*UpdateSubmodulesOptions) error
+	// UpdateSubmodulesContext is like UpdateSubmodules, but the git command it
+	// runs is killed if the provided context is done before it completes.
+	UpdateSubmodulesContext(context.Context, *UpdateSubmodulesOptions) error
 	// URL returns the remote URL of the repository.
 	URL() string
+	// UsesLFS returns a bool indicating whether the .gitattributes file at the
+	// root of the working tree configures Git LFS for any paths.
+	UsesLFS() (bool, error)
+	// VerifyCommitSignature verifies the signature of the specified commit,
+	// which defaults to HEAD, against the keys provided in the options only. If
+	// the commit is not present in the local repository, it is fetched from the
+	// remote repository first. Only the signature of the commit itself is
+	// verified. In particular, the signature of a merge commit is verified
+	// regardless of whether the commits it merges are signed. An error is only
+	// returned if verification could not be performed. The outcome is reported
+	// by the returned CommitSignature.
+	VerifyCommitSignature(commit string, opts *VerifyCommitSignatureOptions) (*CommitSignature, error)
 }
 
 // workTree is an implementation of the WorkTree interface for interacting with
@@ -123,9 +196,15 @@ func LoadWorkTree(path string, opts *LoadWorkTreeOptions) (WorkTree, error) {
 		return nil,
 			fmt.Errorf(`error reading URL of remote "origin" from config: %w`, err)
 	}
+	if err = w.loadInsecureNoAuth(); err != nil {
+		return nil, err
+	}
 	if err = w.setupAuth(); err != nil {
 		return nil, fmt.Errorf("error configuring the credentials: %w", err)
 	}
+	if err = w.loadAuthMethod(); err != nil {
+		return nil, err
+	}
 	br, err := LoadBareRepo(repoPath, &LoadBareRepoOptions{
 		Credentials: opts.Credentials,
 	})
@@ -150,6 +229,28 @@ func (w *workTree) AddAllAndCommit(message string) error {
 	return w.Commit(message, nil)
 }
 
+func (w *workTree) AddSparseCheckoutPaths(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	res, err := libExec.Exec(w.buildGitCommand("config", "--type", "bool", "core.sparseCheckout"))
+	if err != nil || strings.TrimSpace(string(res)) != "true" {
+		return fmt.Errorf("working tree at %q is not a sparse checkout", w.dir)
+	}
+	// If the repository is a partial clone, this fetches the contents of the
+	// newly checked out files.
+	if _, err = w.execNetworkCommand(w.buildGitCommand(
+		append([]string{"sparse-checkout", "add", "--"}, paths...)...,
+	)); err != nil {
+		return fmt.Errorf("error adding paths to sparse checkout: %w", err)
+	}
+	return nil
+}
+
+func (w *workTree) BareRepo() BareRepo {
+	return w.bareRepo
+}
+
 func (w *workTree) Clean() error {
 	if _, err := libExec.Exec(w.buildGitCommand("clean", "-fd")); err != nil {
 		return fmt.Errorf("error cleaning worktree: %w", err)
@@ -157,10 +258,25 @@ func (w *workTree) Clean() error {
 	return nil
 }
 
-func (w *workTree) Clear() error {
-	if _, err := libExec.Exec(
-		w.buildGitCommand("rm", "-rf", "--ignore-unmatch", "."),
-	); err != nil {
+// ClearOptions represents options for clearing a working tree.
+type ClearOptions struct {
+	// Keep is a list of paths or glob patterns, relative to the root of the
+	// working tree, that should not be removed. Files matching these are left
+	// untouched, so their content and mode are preserved.
+	Keep []string
+}
+
+func (w *workTree) Clear(opts *ClearOptions) error {
+	if opts == nil {
+		opts = &ClearOptions{}
+	}
+	// --sparse extends the removal to files outside of a sparse checkout, which
+	// would otherwise remain in the index.
+	cmdTokens := []string{"rm", "-rf", "--sparse", "--ignore-unmatch", "--", "."}
+	for _, path := range opts.Keep {
+		cmdTokens = append(cmdTokens, ":(exclude)"+path)
+	}
+	if _, err := libExec.Exec(w.buildGitCommand(cmdTokens...)); err != nil {
 		return fmt.Errorf("error clearing worktree: %w", err)
 	}
 	return nil
@@ -197,6 +313,9 @@ type CommitOptions struct {
 	// Author is the author of the commit. If nil, the default author already
 	// configured in the git repository will be used.
 	Author *User
+	// Committer is the committer of the commit. If nil, the default author
+	// already configured in the git repository will be used.
+	Committer *User
 }
 
 func (w *workTree) Commit(message string, opts *CommitOptions) error {
@@ -205,21 +324,19 @@ func (w *workTree) Commit(message string, opts *CommitOptions) error {
 	}
 	cmdTokens := []string{"commit", "-m", message}
 	if opts.Author != nil {
-		name := opts.Author.Name
-		if name == "" {
-			name = defaultUsername
-		}
-		email := opts.Author.Email
-		if email == "" {
-			email = defaultEmail
-		}
+		name, email := opts.Author.nameAndEmail()
 		cmdTokens = append(cmdTokens, "--author", fmt.Sprintf("%s <%s>", name, email))
 	}
 	if opts.AllowEmpty {
 		cmdTokens = append(cmdTokens, "--allow-empty")
 	}
 
-	if _, err := libExec.Exec(w.buildGitCommand(cmdTokens...)); err != nil {
+	cmd := w.buildGitCommand(cmdTokens...)
+	if opts.Committer != nil {
+		name, email := opts.Committer.nameAndEmail()
+		cmd.Env = append(cmd.Env, "GIT_COMMITTER_NAME="+name, "GIT_COMMITTER_EMAIL="+email)
+	}
+	if _, err := execGitCommand(cmd, w.url); err != nil {
 		return fmt.Errorf("error committing changes: %w", err)
 	}
 	return nil
@@ -235,6 +352,37 @@ func (w *workTree) CommitMessage(id string) (string, error) {
 	return string(msgBytes), nil
 }
 
+func (w *workTree) CommitTrailers(id string) (string, error) {
+	trailerBytes, err := libExec.Exec(
+		w.buildGitCommand("log", "-n", "1", "--format=%(trailers:unfold)", id),
+	)
+	if err != nil {
+		return "", fmt.Errorf("error obtaining trailers for commit %q: %w", id, err)
+	}
+	return strings.TrimSpace(string(trailerBytes)), nil
+}
+
+// commitMessageSeparator separates the messages of consecutive commits in the
+// output of CommitMessagesSince's git log command. It is a NUL byte, which
+// cannot occur in a commit message.
+const commitMessageSeparator = "\x00"
+
+func (w *workTree) CommitMessagesSince(base string) ([]string, error) {
+	res, err := libExec.Exec(w.buildGitCommand(
+		"log", "--reverse", "--format=%B%x00", base+"..HEAD",
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error listing commit messages since %q: %w", base, err)
+	}
+	var messages []string
+	for _, msg := range strings.Split(string(res), commitMessageSeparator) {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
 func (w *workTree) CreateChildBranch(branch string) error {
 	if _, err := libExec.Exec(w.buildGitCommand(
 		"checkout",
@@ -439,8 +587,18 @@ type TagMetadata struct {
 	Subject string
 }
 
+func (w *workTree) MergeBase(commit1 string, commit2 string) (string, error) {
+	res, err := libExec.Exec(w.buildGitCommand("merge-base", commit1, commit2))
+	if err != nil {
+		return "", fmt.Errorf(
+			"error finding merge base of commits %q and %q: %w", commit1, commit2, err,
+		)
+	}
+	return strings.TrimSpace(string(res)), nil
+}
+
 func (w *workTree) ListTags() ([]TagMetadata, error) {
-	if _, err := libExec.Exec(w.buildGitCommand("fetch", "origin", "--tags")); err != nil {
+	if _, err := w.execNetworkCommand(w.buildGitCommand("fetch", "origin", "--tags")); err != nil {
 		return nil, fmt.Errorf("error fetching tags from repo %q: %w", w.url, err)
 	}
 
@@ -515,6 +673,30 @@ type PushOptions struct {
 	// be useful when pushing changes to a remote branch that has been updated
 	// in the time since the local branch was last pulled.
 	PullRebase bool
+	// AdditionalRefs specifies commits, e.g. ones checked out in other working
+	// trees of the same repository, to push to other remote branches along
+	// with the current branch. If any are specified, all branches are updated
+	// in a single atomic push, i.e. either all of them are updated or none
+	// are. If the remote repository does not support atomic pushes, an error
+	// wrapping ErrAtomicPushNotSupported is returned and nothing is pushed.
+	// PullRebase only applies to the current branch.
+	AdditionalRefs []PushRef
+	// ChangeID, if non-empty, indicates that the commit at HEAD should be pushed
+	// for review to a Gerrit server instead of being pushed to the target branch
+	// directly. Before pushing, the commit is amended to carry the value as its
+	// Change-Id trailer, replacing any existing one, and it is then pushed to
+	// the magic refs/for/<TargetBranch> ref. Pushing a commit with the same
+	// Change-Id again uploads a new patch set of the same change. Force and
+	// AdditionalRefs are not supported in combination with ChangeID.
+	ChangeID string
+}
+
+// PushRef describes a commit to push to a remote branch.
+type PushRef struct {
+	// Commit is the ID of the commit to push.
+	Commit string
+	// TargetBranch is the remote branch to push the commit to.
+	TargetBranch string
 }
 
 // https://regex101.com/r/aNYjHP/1
@@ -522,7 +704,17 @@ type PushOptions struct {
 // nolint: lll
 var nonFastForwardRegex = regexp.MustCompile(`(?m)^\s*!\s+\[(?:remote )?rejected].+\((?:non-fast-forward|fetch first|cannot lock ref.*)\)\s*$`)
 
+var atomicPushNotSupportedRegex = regexp.MustCompile(`the receiving end does not support --atomic push`)
+
+// noNewChangesRegex matches the rejection by a Gerrit server of a push for
+// review of a commit that is already a patch set of a change.
+var noNewChangesRegex = regexp.MustCompile(`(?m)^\s*!\s+\[remote rejected].+\(no new changes\)\s*$`)
+
 func (w *workTree) Push(opts *PushOptions) error {
+	return w.PushContext(context.Background(), opts)
+}
+
+func (w *workTree) PushContext(ctx context.Context, opts *PushOptions) error {
 	if opts == nil {
 		opts = &PushOptions{}
 	}
@@ -534,38 +726,100 @@ func (w *workTree) Push(opts *PushOptions) error {
 		}
 	}
 	if opts.PullRebase {
-		exists, err := w.RemoteBranchExists(targetBranch)
-		if err != nil {
+		if err := w.PullRebaseContext(ctx, targetBranch); err != nil {
 			return err
 		}
-		// We only want to pull and rebase if the remote branch exists.
-		if exists {
-			if _, err = libExec.Exec(w.buildGitCommand("pull", "--rebase", "origin", targetBranch)); err != nil {
-				// The error we're most concerned with is a merge conflict requiring
-				// manual resolution, because it's an error that no amount of retries
-				// will fix. If we find that a rebase is in progress, this is what
-				// has happened.
-				if isRebasing, isRebasingErr := w.IsRebasing(); isRebasingErr == nil && isRebasing {
-					return ErrMergeConflict
-				}
-				// If we get to here, the error isn't a merge conflict.
-				return fmt.Errorf("error pulling and rebasing branch: %w", err)
-			}
-		}
+	}
+	if opts.ChangeID != "" {
+		return w.pushForReview(ctx, targetBranch, opts)
 	}
 	args := []string{"push", "origin", fmt.Sprintf("HEAD:%s", targetBranch)}
+	if len(opts.AdditionalRefs) > 0 {
+		args = append(args, "--atomic")
+		for _, ref := range opts.AdditionalRefs {
+			args = append(args, fmt.Sprintf("%s:refs/heads/%s", ref.Commit, ref.TargetBranch))
+		}
+	}
 	if opts.Force {
 		args = append(args, "--force")
 	}
-	if res, err := libExec.Exec(w.buildGitCommand(args...)); err != nil {
+	if res, err := w.execNetworkCommandContext(ctx, w.buildGitCommandContext(ctx, args...)); err != nil {
 		if nonFastForwardRegex.MatchString(string(res)) {
 			return fmt.Errorf("error pushing branch: %w", ErrNonFastForward)
 		}
+		if atomicPushNotSupportedRegex.Match(res) {
+			return fmt.Errorf("error pushing branches: %w", ErrAtomicPushNotSupported)
+		}
 		return fmt.Errorf("error pushing branch: %w", err)
 	}
 	return nil
 }
 
+// pushForReview amends the commit at HEAD to carry the Change-Id specified by
+// the provided options and pushes it to the magic ref that creates or updates
+// a change for review of the provided target branch on a Gerrit server.
+func (w *workTree) pushForReview(
+	ctx context.Context,
+	targetBranch string,
+	opts *PushOptions,
+) error {
+	if opts.Force || len(opts.AdditionalRefs) > 0 {
+		return errors.New("force pushes and additional refs cannot be pushed for review")
+	}
+	if _, err := execGitCommand(w.buildGitCommand(
+		"-c", "trailer.ifexists=replace",
+		"commit", "--amend", "--no-edit",
+		"--trailer", "Change-Id: "+opts.ChangeID,
+	), w.url); err != nil {
+		return fmt.Errorf("error adding Change-Id to commit: %w", err)
+	}
+	res, err := w.execNetworkCommandContext(ctx, w.buildGitCommandContext(
+		ctx, "push", "origin", "HEAD:refs/for/"+targetBranch,
+	))
+	if err != nil {
+		if noNewChangesRegex.Match(res) {
+			// The commit was already pushed for review, e.g. by a previous attempt
+			// whose result was lost.
+			return nil
+		}
+		if nonFastForwardRegex.Match(res) {
+			return fmt.Errorf("error pushing for review: %w", ErrNonFastForward)
+		}
+		return fmt.Errorf("error pushing for review: %w", err)
+	}
+	return nil
+}
+
+func (w *workTree) PullRebase(branch string) error {
+	return w.PullRebaseContext(context.Background(), branch)
+}
+
+func (w *workTree) PullRebaseContext(ctx context.Context, branch string) error {
+	exists, err := w.remoteBranchExists(ctx, branch)
+	if err != nil {
+		return err
+	}
+	// We only want to pull and rebase if the remote branch exists.
+	if !exists {
+		return nil
+	}
+	if _, err = w.execNetworkCommandContext(
+		ctx,
+		w.buildGitCommandContext(ctx, "pull", "--rebase", "origin", branch),
+	); err != nil {
+		// The error we're most concerned with is a merge conflict requiring
+		// manual resolution, because it's an error that no amount of retries
+		// will fix. If we find that a rebase is in progress, this is what
+		// has happened.
+		if isRebasing, isRebasingErr := w.IsRebasing(); isRebasingErr == nil && isRebasing {
+			return ErrMergeConflict
+		}
+		// If we get to here, the error isn't a merge conflict.
+		return fmt.Errorf("error pulling and rebasing branch: %w", err)
+	}
+	return nil
+}
+
 func (w *workTree) RefsHaveDiffs(commit1 string, commit2 string) (bool, error) {
 	// `git diff --quiet` returns 0 if no diff, 1 if diff, and non-zero/one for any other error
 	_, err := libExec.Exec(w.buildGitCommand(
@@ -582,9 +836,181 @@ func (w *workTree) RefsHaveDiffs(commit1 string, commit2 string) (bool, error) {
 	return false, fmt.Errorf("error diffing commits %s..%s: %w", commit1, commit2, err)
 }
 
+func (w *workTree) Diff(commit1 string, commit2 string) ([]byte, error) {
+	for _, commit := range []string{commit1, commit2} {
+		if err := w.ensureCommit(commit); err != nil {
+			return nil, err
+		}
+	}
+	res, err := libExec.Exec(w.buildGitCommand("diff", "--binary", commit1, commit2, "--"))
+	if err != nil {
+		return nil, fmt.Errorf("error diffing commits %s..%s: %w", commit1, commit2, err)
+	}
+	return res, nil
+}
+
 func (w *workTree) ResetHard() error {
 	if _, err := libExec.Exec(w.buildGitCommand("reset", "--hard")); err != nil {
 		return fmt.Errorf("error resetting branch working tree: %w", err)
 	}
 	return nil
 }
+
+func (w *workTree) SquashSince(base string, message string) error {
+	if _, err := libExec.Exec(w.buildGitCommand("reset", "--soft", base)); err != nil {
+		return fmt.Errorf("error resetting branch to commit %q: %w", base, err)
+	}
+	if _, err := execGitCommand(
+		w.buildGitCommand("commit", "--allow-empty", "-m", message), w.url,
+	); err != nil {
+		return fmt.Errorf("error committing squashed changes: %w", err)
+	}
+	return nil
+}
+
+func (w *workTree) PullLFS() error {
+	return w.PullLFSContext(context.Background())
+}
+
+func (w *workTree) PullLFSContext(ctx context.Context) error {
+	if _, err := libExec.Exec(w.buildGitCommand("lfs", "install", "--local")); err != nil {
+		return fmt.Errorf("error installing Git LFS in repo %q: %w", w.url, err)
+	}
+	if _, err := w.execNetworkCommandContext(ctx, w.buildGitCommandContext(ctx, "lfs", "pull")); err != nil {
+		return fmt.Errorf("error pulling Git LFS objects from repo %q: %w", w.url, err)
+	}
+	return nil
+}
+
+func (w *workTree) UsesLFS() (bool, error) {
+	f, err := os.Open(filepath.Join(w.dir, ".gitattributes"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error opening .gitattributes: %w", err)
+	}
+	defer f.Close()
+	return gitAttributesUseLFS(f)
+}
+
+// gitAttributesUseLFS returns a bool indicating whether any of the patterns in
+// the provided .gitattributes content are assigned the Git LFS filter.
+func gitAttributesUseLFS(r io.Reader) (bool, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// The first field is the pattern. All others are attributes.
+		if slices.Contains(strings.Fields(line)[1:], "filter=lfs") {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("error reading .gitattributes: %w", err)
+	}
+	return false, nil
+}
+
+func (w *workTree) SubmoduleURLs() ([]string, error) {
+	if _, err := os.Stat(filepath.Join(w.dir, ".gitmodules")); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error checking for .gitmodules: %w", err)
+	}
+	res, err := libExec.Exec(w.buildGitCommand(
+		"config",
+		"--file", ".gitmodules",
+		"--get-regexp", `^submodule\..*\.url$`,
+	))
+	var exitErr *libExec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode == 1 {
+		// No submodule URLs are configured
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading submodule URLs from .gitmodules: %w", err)
+	}
+	var urls []string
+	scanner := bufio.NewScanner(bytes.NewReader(res))
+	for scanner.Scan() {
+		// Each line is of the form "submodule.<name>.url <url>"
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 {
+			urls = append(urls, fields[1])
+		}
+	}
+	return urls, scanner.Err()
+}
+
+// UpdateSubmodulesOptions represents options for updating the submodules of a
+// working tree.
+type UpdateSubmodulesOptions struct {
+	// Credentials optionally maps the URLs of submodules hosted elsewhere than
+	// the repository itself to the credentials that should be used for
+	// accessing them. Only username/password credentials are supported and are
+	// applied to all submodules on the same host as the URL. Submodules on the
+	// same host as the repository itself are accessed using the repository's
+	// own credentials.
+	Credentials map[string]RepoCredentials
+}
+
+func (w *workTree) UpdateSubmodules(opts *UpdateSubmodulesOptions) error {
+	return w.UpdateSubmodulesContext(context.Background(), opts)
+}
+
+func (w *workTree) UpdateSubmodulesContext(
+	ctx context.Context,
+	opts *UpdateSubmodulesOptions,
+) error {
+	if opts == nil {
+		opts = &UpdateSubmodulesOptions{}
+	}
+	creds := make(map[string]RepoCredentials, len(opts.Credentials)+1)
+	for u, c := range opts.Credentials {
+		creds[u] = c
+	}
+	if w.creds != nil {
+		creds[w.url] = *w.creds
+	}
+	cmd := w.buildGitCommandContext(ctx, "submodule", "update", "--init", "--recursive")
+	// Configure a credential helper for each host for which we have a password.
+	// These take precedence over GIT_ASKPASS, which would otherwise provide the
+	// repository's own password to any host that asks for one. Credentials are
+	// passed via environment variables so they never appear in the repository's
+	// configuration or in command line arguments.
+	var count int
+	for _, u := range slices.Sorted(maps.Keys(creds)) {
+		c := creds[u]
+		// A bearer token is only ever presented to the repository's own URL.
+		if c.Password == "" || c.HTTPAuthMode == HTTPAuthModeBearer {
+			continue
+		}
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		cmd.Env = withGitConfig(
+			cmd.Env,
+			fmt.Sprintf("credential.%s://%s.helper", parsed.Scheme, parsed.Host),
+			fmt.Sprintf(
+				`!f() { test "$1" = get && `+
+					`echo "username=${KARGO_GIT_USERNAME_%d}" && `+
+					`echo "password=${KARGO_GIT_PASSWORD_%d}"; }; f`,
+				count, count,
+			),
+		)
+		cmd.Env = append(
+			cmd.Env,
+			fmt.Sprintf("KARGO_GIT_USERNAME_%d=%s", count, c.Username),
+			fmt.Sprintf("KARGO_GIT_PASSWORD_%d=%s", count, c.Password),
+		)
+		count++
+	}
+	if _, err := w.execNetworkCommandContext(ctx, cmd); err != nil {
+		return fmt.Errorf("error updating submodules of repo %q: %w", w.url, err)
+	}
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -56,6 +58,10 @@ func TestWorkTree(t *testing.T) {
 	defer setupRep.Close()
 	err = os.WriteFile(fmt.Sprintf("%s/%s", setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
 	require.NoError(t, err)
+	err = os.MkdirAll(filepath.Join(setupRep.Dir(), ".github"), 0700)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), ".github", "hook.sh"), []byte("#!/bin/sh"), 0700)
+	require.NoError(t, err)
 	err = setupRep.AddAllAndCommit(fmt.Sprintf("initial commit %s", uuid.NewString()))
 	require.NoError(t, err)
 	err = setupRep.Push(nil)
@@ -95,6 +101,34 @@ func TestWorkTree(t *testing.T) {
 		require.Equal(t, workTree, existingWorkTree)
 	})
 
+	t.Run("can get the bare repository", func(t *testing.T) {
+		require.Equal(t, rep.Dir(), workTree.BareRepo().Dir())
+	})
+
+	t.Run("can detect working tree does not use LFS", func(t *testing.T) {
+		usesLFS, err := workTree.UsesLFS()
+		require.NoError(t, err)
+		require.False(t, usesLFS)
+	})
+
+	t.Run("can detect working tree has no submodules", func(t *testing.T) {
+		urls, err := workTree.SubmoduleURLs()
+		require.NoError(t, err)
+		require.Empty(t, urls)
+	})
+
+	t.Run("can clear working tree while keeping some paths", func(t *testing.T) {
+		require.NoError(t, workTree.Clear(&ClearOptions{Keep: []string{".github"}}))
+		_, err := os.Stat(filepath.Join(workTree.Dir(), "test.txt"))
+		require.True(t, os.IsNotExist(err))
+		// Kept files retain their executable bit
+		fi, err := os.Stat(filepath.Join(workTree.Dir(), ".github", "hook.sh"))
+		require.NoError(t, err)
+		require.NotZero(t, fi.Mode().Perm()&0100)
+		// Restore the working tree so that it can be closed cleanly
+		require.NoError(t, exec.Command("git", "-C", workTree.Dir(), "reset", "--hard").Run())
+	})
+
 	t.Run("can close working tree", func(t *testing.T) {
 		require.NoError(t, workTree.Close())
 		_, err := os.Stat(workTree.Dir())
@@ -103,3 +137,352 @@ func TestWorkTree(t *testing.T) {
 	})
 
 }
+
+func TestWorkTree_UpdateSubmodules(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	subRepoURL := fmt.Sprintf("%s/sub.git", server.URL)
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	// Set up the repository that will be used as a submodule
+	subRep, err := Clone(subRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer subRep.Close()
+	err = os.WriteFile(filepath.Join(subRep.Dir(), "sub.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, subRep.AddAllAndCommit("initial commit"))
+	require.NoError(t, subRep.Push(nil))
+
+	// Set up the repository that uses the submodule
+	setupRep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	cmd := exec.Command("git", "submodule", "add", subRepoURL, "sub")
+	cmd.Dir = setupRep.Dir()
+	cmd.Env = append(os.Environ(), "HOME="+setupRep.HomeDir())
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, setupRep.AddAllAndCommit("add submodule"))
+	require.NoError(t, setupRep.Push(nil))
+
+	rep, err := CloneBare(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer rep.Close()
+	workTree, err := rep.AddWorkTree(
+		filepath.Join(rep.HomeDir(), "working-tree"),
+		&AddWorkTreeOptions{Ref: "master"},
+	)
+	require.NoError(t, err)
+	defer workTree.Close()
+
+	t.Run("can list submodule URLs", func(t *testing.T) {
+		urls, err := workTree.SubmoduleURLs()
+		require.NoError(t, err)
+		require.Equal(t, []string{subRepoURL}, urls)
+	})
+
+	t.Run("can update submodules", func(t *testing.T) {
+		_, err := os.Stat(filepath.Join(workTree.Dir(), "sub", "sub.txt"))
+		require.True(t, os.IsNotExist(err))
+		require.NoError(t, workTree.UpdateSubmodules(nil))
+		_, err = os.Stat(filepath.Join(workTree.Dir(), "sub", "sub.txt"))
+		require.NoError(t, err)
+	})
+}
+
+func TestWorkTree_Push_additionalRefs(t *testing.T) {
+	testCases := []struct {
+		name             string
+		supportsAtomic   bool
+		expectedErr      error
+		expectedRendered bool
+	}{
+		{
+			name:             "remote supports atomic pushes",
+			supportsAtomic:   true,
+			expectedRendered: true,
+		},
+		{
+			name:        "remote does not support atomic pushes",
+			expectedErr: ErrAtomicPushNotSupported,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			serverDir := t.TempDir()
+			service := gitkit.New(
+				gitkit.Config{
+					Dir:        serverDir,
+					AutoCreate: true,
+				},
+			)
+			require.NoError(t, service.Setup())
+			server := httptest.NewServer(service)
+			defer server.Close()
+
+			testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+			setupRep, err := Clone(testRepoURL, nil, nil)
+			require.NoError(t, err)
+			defer setupRep.Close()
+			err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo"), 0600)
+			require.NoError(t, err)
+			require.NoError(t, setupRep.AddAllAndCommit("initial commit"))
+			require.NoError(t, setupRep.Push(nil))
+			initialCommit, err := setupRep.LastCommitID()
+			require.NoError(t, err)
+
+			if !testCase.supportsAtomic {
+				out, err := exec.Command(
+					"git", "-C", filepath.Join(serverDir, "test.git"),
+					"config", "receive.advertiseAtomic", "false",
+				).CombinedOutput()
+				require.NoError(t, err, string(out))
+			}
+
+			rep, err := CloneBare(testRepoURL, nil, nil)
+			require.NoError(t, err)
+			defer rep.Close()
+			srcTree, err := rep.AddWorkTree(
+				filepath.Join(rep.HomeDir(), "src"),
+				&AddWorkTreeOptions{Ref: "master"},
+			)
+			require.NoError(t, err)
+			defer srcTree.Close()
+			outTree, err := rep.AddWorkTree(
+				filepath.Join(rep.HomeDir(), "out"),
+				&AddWorkTreeOptions{Ref: "master", Branch: "rendered"},
+			)
+			require.NoError(t, err)
+			defer outTree.Close()
+
+			for _, w := range []WorkTree{srcTree, outTree} {
+				err = os.WriteFile(filepath.Join(w.Dir(), "test.txt"), []byte(w.Dir()), 0600)
+				require.NoError(t, err)
+				require.NoError(t, w.AddAllAndCommit("update"))
+			}
+			outCommit, err := outTree.LastCommitID()
+			require.NoError(t, err)
+
+			err = srcTree.Push(&PushOptions{
+				PullRebase: true,
+				AdditionalRefs: []PushRef{{
+					Commit:       outCommit,
+					TargetBranch: "rendered",
+				}},
+			})
+			if testCase.expectedErr != nil {
+				require.ErrorIs(t, err, testCase.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// Either both branches were updated or neither was
+			srcCommit, err := srcTree.LastCommitID()
+			require.NoError(t, err)
+			remoteSrcCommit, err := RemoteBranchCommit(testRepoURL, "master", nil)
+			require.NoError(t, err)
+			renderedExists, err := srcTree.RemoteBranchExists("rendered")
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedRendered, renderedExists)
+			if testCase.expectedRendered {
+				require.Equal(t, srcCommit, remoteSrcCommit)
+				remoteOutCommit, err := RemoteBranchCommit(testRepoURL, "rendered", nil)
+				require.NoError(t, err)
+				require.Equal(t, outCommit, remoteOutCommit)
+			} else {
+				require.Equal(t, initialCommit, remoteSrcCommit)
+			}
+		})
+	}
+}
+
+func TestWorkTree_Push_changeID(t *testing.T) {
+	// A plain Git server stores refs/for/<branch> as an ordinary ref, which
+	// suffices to verify what would be pushed for review to a Gerrit server.
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	rep, err := Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer rep.Close()
+	err = os.WriteFile(filepath.Join(rep.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, rep.AddAllAndCommit("initial commit"))
+	require.NoError(t, rep.Push(nil))
+
+	err = os.WriteFile(filepath.Join(rep.Dir(), "test.txt"), []byte("bar"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, rep.AddAllAndCommit("update\n\nChange-Id: Iold"))
+
+	const changeID = "I0123456789abcdef0123456789abcdef01234567"
+	require.NoError(t, rep.Push(&PushOptions{ChangeID: changeID, PullRebase: true}))
+
+	commitID, err := rep.LastCommitID()
+	require.NoError(t, err)
+	trailers, err := rep.CommitTrailers(commitID)
+	require.NoError(t, err)
+	require.Equal(t, "Change-Id: "+changeID, strings.TrimSpace(trailers))
+
+	// The commit was pushed for review rather than to the branch itself
+	out, err := exec.Command("git", "ls-remote", testRepoURL, "refs/for/master").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), commitID)
+	remoteCommit, err := RemoteBranchCommit(testRepoURL, "master", nil)
+	require.NoError(t, err)
+	require.NotEqual(t, commitID, remoteCommit)
+
+	require.Error(t, rep.Push(&PushOptions{ChangeID: changeID, Force: true}))
+}
+
+func TestWorkTree_Diff(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	setupRep, err := Clone(remoteDir, nil, nil)
+	require.NoError(t, err)
+	defer setupRep.Close()
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("foo\n"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, setupRep.AddAllAndCommit("initial commit"))
+	commit1, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(setupRep.Dir(), "test.txt"), []byte("bar\n"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, setupRep.AddAllAndCommit("update"))
+	commit2, err := setupRep.LastCommitID()
+	require.NoError(t, err)
+	require.NoError(t, setupRep.Push(nil))
+
+	// A shallow clone only contains the second commit, so the first one must be
+	// fetched.
+	rep, err := Clone(remoteDir, nil, &CloneOptions{Depth: 1})
+	require.NoError(t, err)
+	defer rep.Close()
+
+	diff, err := rep.Diff(commit1, commit2)
+	require.NoError(t, err)
+	require.Contains(t, string(diff), "diff --git a/test.txt b/test.txt")
+	require.Contains(t, string(diff), "-foo\n+bar\n")
+
+	diff, err = rep.Diff(commit2, commit2)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	_, err = rep.Diff(commit1, strings.Repeat("0", 40))
+	require.ErrorContains(t, err, "error fetching commit")
+}
+
+func TestWorkTree_Commit_committer(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	rep, err := Clone(remoteDir, &ClientOptions{User: &User{Name: "Kargo Dev", Email: "dev@example.com"}}, nil)
+	require.NoError(t, err)
+	defer rep.Close()
+	err = os.WriteFile(filepath.Join(rep.Dir(), "test.txt"), []byte("foo\n"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, rep.AddAll())
+	prod := &User{Name: "Kargo Prod", Email: "prod@example.com"}
+	require.NoError(t, rep.Commit("initial commit", &CommitOptions{Author: prod, Committer: prod}))
+
+	commits, err := rep.ListCommits(1, 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	require.Equal(t, "Kargo Prod <prod@example.com>", commits[0].Author)
+	require.Equal(t, "Kargo Prod <prod@example.com>", commits[0].Committer)
+}
+
+func TestWorkTree_SquashSince(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	rep, err := Clone(remoteDir, nil, nil)
+	require.NoError(t, err)
+	defer rep.Close()
+	err = os.WriteFile(filepath.Join(rep.Dir(), "a.txt"), []byte("foo\n"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, rep.AddAllAndCommit("initial commit"))
+	base, err := rep.LastCommitID()
+	require.NoError(t, err)
+
+	for _, file := range []string{"b.txt", "c.txt"} {
+		err = os.WriteFile(filepath.Join(rep.Dir(), file), []byte(file), 0600)
+		require.NoError(t, err)
+		require.NoError(t, rep.AddAllAndCommit("add "+file+"\n\nwith a body"))
+	}
+	head, err := rep.LastCommitID()
+	require.NoError(t, err)
+
+	mergeBase, err := rep.MergeBase(head, base)
+	require.NoError(t, err)
+	require.Equal(t, base, mergeBase)
+
+	messages, err := rep.CommitMessagesSince(base)
+	require.NoError(t, err)
+	require.Equal(t, []string{"add b.txt\n\nwith a body", "add c.txt\n\nwith a body"}, messages)
+
+	require.NoError(t, rep.SquashSince(base, "squashed"))
+	squashed, err := rep.LastCommitID()
+	require.NoError(t, err)
+	require.NotEqual(t, head, squashed)
+	hasDiffs, err := rep.RefsHaveDiffs(head, squashed)
+	require.NoError(t, err)
+	require.False(t, hasDiffs)
+	messages, err = rep.CommitMessagesSince(base)
+	require.NoError(t, err)
+	require.Equal(t, []string{"squashed"}, messages)
+}
+
+func Test_gitAttributesUseLFS(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes string
+		expected   bool
+	}{
+		{
+			name:       "empty",
+			attributes: "",
+			expected:   false,
+		},
+		{
+			name:       "no LFS filter",
+			attributes: "*.sh text eol=lf\n*.png binary\n",
+			expected:   false,
+		},
+		{
+			name:       "LFS filter in comment",
+			attributes: "# *.bin filter=lfs diff=lfs merge=lfs -text\n",
+			expected:   false,
+		},
+		{
+			name:       "LFS filter",
+			attributes: "*.sh text eol=lf\n*.bin filter=lfs diff=lfs merge=lfs -text\n",
+			expected:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			usesLFS, err := gitAttributesUseLFS(strings.NewReader(testCase.attributes))
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, usesLFS)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// binaryVersionTimeout is the maximum amount of time a binary may take to
+// report its version.
+const binaryVersionTimeout = 10 * time.Second
+
+// binary is an external binary that the controller executes.
+type binary struct {
+	// name is the name of the binary, which is looked up in the PATH.
+	name string
+	// args are the arguments that make the binary print its version.
+	args []string
+	// minVersion is the oldest version of the binary that the controller
+	// works with.
+	minVersion *semver.Version
+	// optional indicates that the controller works without the binary, e.g.
+	// because it is only executed by some promotion steps. The version of an
+	// optional binary is only checked if the binary is found.
+	optional bool
+}
+
+// binaries are the external binaries whose versions are checked at startup.
+var binaries = []binary{
+	{
+		name: "git",
+		args: []string{"version"},
+		// `git worktree add --orphan` was introduced in 2.42.0.
+		minVersion: semver.MustParse("2.42.0"),
+	},
+	{
+		// Used to pull Git LFS objects into work trees that use Git LFS.
+		name:       "git-lfs",
+		args:       []string{"version"},
+		minVersion: semver.MustParse("3.0.0"),
+		optional:   true,
+	},
+	{
+		// Used to inflate Helm charts while building kustomizations, unless a
+		// Stage pins a version of Helm.
+		name:       "helm",
+		args:       []string{"version", "--short"},
+		minVersion: semver.MustParse("3.0.0"),
+		optional:   true,
+	},
+	{
+		name:       "kustomize",
+		args:       []string{"version"},
+		minVersion: semver.MustParse("5.0.0"),
+		optional:   true,
+	},
+}
+
+var binaryInfo = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kargo_controller_binary_info",
+		Help: "Version of each external binary found by the controller at " +
+			"startup (always 1)",
+	},
+	[]string{"binary", "version"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(binaryInfo)
+}
+
+// CheckBinaryVersions determines the versions of the external binaries that
+// the controller executes, logs them, and exports them as a metric. It returns
+// an error if a binary that is not optional is missing, if the version of a
+// binary cannot be determined, or if it is older than the oldest version the
+// controller works with.
+func CheckBinaryVersions(ctx context.Context, logger *logging.Logger) error {
+	return checkBinaryVersions(ctx, logger, binaries, binaryVersion)
+}
+
+func checkBinaryVersions(
+	ctx context.Context,
+	logger *logging.Logger,
+	bins []binary,
+	versionFn func(context.Context, binary) (*semver.Version, error),
+) error {
+	var errs []error
+	for _, bin := range bins {
+		version, err := versionFn(ctx, bin)
+		switch {
+		case errors.Is(err, exec.ErrNotFound) && bin.optional:
+			logger.Info("optional binary not found", "binary", bin.name)
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("error determining version of %s: %w", bin.name, err))
+			continue
+		}
+		binaryInfo.WithLabelValues(bin.name, version.String()).Set(1)
+		logger.Info(
+			"found binary",
+			"binary", bin.name,
+			"version", version.String(),
+			"minVersion", bin.minVersion.String(),
+		)
+		if version.LessThan(bin.minVersion) {
+			errs = append(errs, fmt.Errorf(
+				"version %s of %s is older than the oldest supported version %s",
+				version, bin.name, bin.minVersion,
+			))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// binaryVersion executes the provided binary to determine its version.
+func binaryVersion(ctx context.Context, bin binary) (*semver.Version, error) {
+	path, err := exec.LookPath(bin.name)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, binaryVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, bin.args...).Output() // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("error executing %s %s: %w", path, strings.Join(bin.args, " "), err)
+	}
+	return parseBinaryVersion(string(out))
+}
+
+// binaryVersionRegex matches the first version number in the output of a
+// binary, ignoring anything that precedes it, e.g. "git version ", and
+// anything that follows its patch number, e.g. distribution-specific suffixes.
+var binaryVersionRegex = regexp.MustCompile(`(?:^|[^0-9.])v?([0-9]+)\.([0-9]+)(?:\.([0-9]+))?`)
+
+// parseBinaryVersion parses the version of a binary from what the binary
+// printed when it was asked for its version. Only the major, minor, and patch
+// numbers are retained, since pre-release and build information is often
+// appended by distributions in formats that are not valid semantic versions.
+func parseBinaryVersion(output string) (*semver.Version, error) {
+	match := binaryVersionRegex.FindStringSubmatch(output)
+	if match == nil {
+		return nil, fmt.Errorf("no version found in %q", strings.TrimSpace(output))
+	}
+	var nums [3]uint64
+	for i, s := range match[1:] {
+		if s == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing version in %q: %w", strings.TrimSpace(output), err)
+		}
+		nums[i] = n
+	}
+	return semver.New(nums[0], nums[1], nums[2], "", ""), nil
+}
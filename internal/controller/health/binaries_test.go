@@ -0,0 +1,160 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akuity/kargo/internal/logging"
+)
+
+func TestParseBinaryVersion(t *testing.T) {
+	testCases := []struct {
+		output   string
+		expected string
+		invalid  bool
+	}{
+		// git
+		{output: "git version 2.39.5\n", expected: "2.39.5"},
+		{output: "git version 2.43.0\n", expected: "2.43.0"},
+		{output: "git version 2.39.3 (Apple Git-145)\n", expected: "2.39.3"},
+		{output: "git version 2.45.2.windows.1\n", expected: "2.45.2"},
+		{output: "git version 2.47.0.vfs.0.0\n", expected: "2.47.0"},
+		{output: "git version 2.44.0-rc2\n", expected: "2.44.0"},
+		{output: "git version 2.34.1.dirty\n", expected: "2.34.1"},
+		// helm
+		{output: "v3.16.4+g7877b45\n", expected: "3.16.4"},
+		{
+			output: `version.BuildInfo{Version:"v3.16.4", GitCommit:"7877b45b63f95635153b29a42c0c2f4273ec45ca", ` +
+				`GitTreeState:"clean", GoVersion:"go1.23.4"}` + "\n",
+			expected: "3.16.4",
+		},
+		{output: "v3.11.1+5.fc38\n", expected: "3.11.1"},
+		// kustomize
+		{output: "v5.5.0\n", expected: "5.5.0"},
+		{
+			output: "{Version:kustomize/v4.5.7 GitCommit:56d82a8378dfc8dc3b3b1085e5a6e67b82966bd7 " +
+				"BuildDate:2022-08-02T16:35:54Z GoOs:linux GoArch:amd64}\n",
+			expected: "4.5.7",
+		},
+		{output: "v5.0\n", expected: "5.0.0"},
+		// unparseable
+		{output: "(devel)\n", invalid: true},
+		{output: "", invalid: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.output, func(t *testing.T) {
+			version, err := parseBinaryVersion(testCase.output)
+			if testCase.invalid {
+				require.ErrorContains(t, err, "no version found")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, version.String())
+		})
+	}
+}
+
+func TestCheckBinaryVersions(t *testing.T) {
+	testBinaries := []binary{
+		{name: "fake-required", minVersion: semver.MustParse("2.0.0")},
+		{name: "fake-optional", minVersion: semver.MustParse("1.0.0"), optional: true},
+	}
+	testCases := []struct {
+		name       string
+		versions   map[string]string
+		errs       map[string]error
+		assertions func(*testing.T, error)
+	}{
+		{
+			name:     "all binaries are supported",
+			versions: map[string]string{"fake-required": "2.1.0", "fake-optional": "1.0.0"},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					float64(1),
+					testutil.ToFloat64(binaryInfo.WithLabelValues("fake-required", "2.1.0")),
+				)
+			},
+		},
+		{
+			name:     "optional binary is missing",
+			versions: map[string]string{"fake-required": "2.0.0"},
+			errs:     map[string]error{"fake-optional": exec.ErrNotFound},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "required binary is missing",
+			versions: map[string]string{"fake-optional": "1.0.0"},
+			errs:     map[string]error{"fake-required": exec.ErrNotFound},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error determining version of fake-required")
+				require.ErrorIs(t, err, exec.ErrNotFound)
+			},
+		},
+		{
+			name:     "version of optional binary cannot be determined",
+			versions: map[string]string{"fake-required": "2.0.0"},
+			errs:     map[string]error{"fake-optional": errors.New("something went wrong")},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error determining version of fake-optional")
+			},
+		},
+		{
+			name:     "binaries are too old",
+			versions: map[string]string{"fake-required": "1.9.9", "fake-optional": "0.1.0"},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(
+					t, err, "version 1.9.9 of fake-required is older than the oldest supported version 2.0.0",
+				)
+				require.ErrorContains(
+					t, err, "version 0.1.0 of fake-optional is older than the oldest supported version 1.0.0",
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := checkBinaryVersions(
+				context.Background(),
+				logging.NewLogger(logging.InfoLevel),
+				testBinaries,
+				func(_ context.Context, bin binary) (*semver.Version, error) {
+					if err, ok := testCase.errs[bin.name]; ok {
+						return nil, fmt.Errorf("wrapped: %w", err)
+					}
+					return semver.MustParse(testCase.versions[bin.name]), nil
+				},
+			)
+			testCase.assertions(t, err)
+		})
+	}
+}
+
+func TestBinaryVersion(t *testing.T) {
+	// git is required to run the tests, so its version must be determinable.
+	version, err := binaryVersion(context.Background(), binaries[0])
+	require.NoError(t, err)
+	require.NotNil(t, version)
+
+	_, err = binaryVersion(context.Background(), binary{name: "fake-binary-that-does-not-exist"})
+	require.ErrorIs(t, err, exec.ErrNotFound)
+}
+
+func TestBinaryVersionsCheck(t *testing.T) {
+	require.NoError(t, binaryVersionsCheck(nil)(nil))
+	require.Equal(t, float64(1), testutil.ToFloat64(checkStatus.WithLabelValues("binary-versions")))
+
+	checkErr := errors.New("something went wrong")
+	require.ErrorIs(t, binaryVersionsCheck(checkErr)(nil), checkErr)
+	require.Equal(t, float64(0), testutil.ToFloat64(checkStatus.WithLabelValues("binary-versions")))
+}
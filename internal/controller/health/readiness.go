@@ -0,0 +1,240 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+const (
+	// checkTimeout is the maximum amount of time a single run of any readiness
+	// check may take.
+	checkTimeout = 30 * time.Second
+
+	// canaryBranch is the branch of the canary repository that is looked up to
+	// verify connectivity. Whether the branch exists is irrelevant.
+	canaryBranch = "kargo/readiness-check"
+)
+
+// ReadinessConfig is configuration for the controller's readiness checks.
+type ReadinessConfig struct {
+	// APIServerCheckEnabled specifies whether readiness depends upon the
+	// controller being able to list Kargo resources using the Kubernetes API
+	// server.
+	APIServerCheckEnabled bool `envconfig:"READINESS_CHECK_API_SERVER_ENABLED" default:"true"`
+	// ArgoCDCheckEnabled specifies whether readiness depends upon the controller
+	// being able to list Argo CD Applications. This check only has any effect
+	// when Argo CD integration is enabled.
+	ArgoCDCheckEnabled bool `envconfig:"READINESS_CHECK_ARGOCD_ENABLED" default:"true"`
+	// CacheTTL specifies how long the result of the API server and Argo CD
+	// checks is reused before the check is run again.
+	CacheTTL time.Duration `envconfig:"READINESS_CHECK_CACHE_TTL" default:"30s"`
+	// CanaryRepoURL optionally specifies the URL of a Git repository that the
+	// controller must be able to reach in order to be ready. The repository
+	// must be readable without credentials.
+	CanaryRepoURL string `envconfig:"READINESS_CHECK_CANARY_REPO_URL"`
+	// CanaryRepoCacheTTL specifies how long the result of the canary repository
+	// check is reused before the check is run again. This is intentionally long
+	// so that readiness probes do not place undue load on the Git server.
+	CanaryRepoCacheTTL time.Duration `envconfig:"READINESS_CHECK_CANARY_REPO_CACHE_TTL" default:"10m"`
+	// BinaryVersionsCheckEnabled specifies whether readiness depends upon the
+	// external binaries the controller executes, such as git, being present
+	// and recent enough. Disabling this allows the controller to run with
+	// binaries that are known to be unsupported, at one's own risk.
+	BinaryVersionsCheckEnabled bool `envconfig:"READINESS_CHECK_BINARY_VERSIONS_ENABLED" default:"true"`
+}
+
+// ReadinessConfigFromEnv returns a ReadinessConfig populated from environment
+// variables.
+func ReadinessConfigFromEnv() ReadinessConfig {
+	cfg := ReadinessConfig{}
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+var checkStatus = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kargo_controller_readiness_check_status",
+		Help: "Result of the most recent run of each controller readiness " +
+			"check (1 for passing, 0 for failing)",
+	},
+	[]string{"check"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(checkStatus)
+}
+
+// AddReadinessChecks registers the readiness checks enabled by the provided
+// ReadinessConfig with the provided Manager. The Argo CD check is only
+// registered if a non-nil Reader for Argo CD resources is provided. The binary
+// versions check fails with the provided error, as returned by
+// CheckBinaryVersions at startup, if it is not nil.
+func AddReadinessChecks(
+	mgr manager.Manager,
+	argocdReader client.Reader,
+	binaryVersionsErr error,
+	cfg ReadinessConfig,
+) error {
+	checks := map[string]*cachedCheck{}
+	if cfg.APIServerCheckEnabled {
+		checks["kargo-api"] = newCachedCheck(
+			"kargo-api",
+			cfg.CacheTTL,
+			func(ctx context.Context) error {
+				return checkKargoAPI(ctx, mgr.GetAPIReader())
+			},
+		)
+	}
+	if cfg.ArgoCDCheckEnabled && argocdReader != nil {
+		checks["argocd"] = newCachedCheck(
+			"argocd",
+			cfg.CacheTTL,
+			func(ctx context.Context) error {
+				return checkArgoCD(ctx, argocdReader)
+			},
+		)
+	}
+	if cfg.CanaryRepoURL != "" {
+		checks["canary-repo"] = newCachedCheck(
+			"canary-repo",
+			cfg.CanaryRepoCacheTTL,
+			func(context.Context) error {
+				return checkGitRepo(cfg.CanaryRepoURL, git.RemoteBranchCommit)
+			},
+		)
+	}
+	for name, check := range checks {
+		if err := mgr.AddReadyzCheck(name, check.Check); err != nil {
+			return fmt.Errorf("error adding %q readiness check: %w", name, err)
+		}
+	}
+	if cfg.BinaryVersionsCheckEnabled {
+		if err := mgr.AddReadyzCheck("binary-versions", binaryVersionsCheck(binaryVersionsErr)); err != nil {
+			return fmt.Errorf("error adding %q readiness check: %w", "binary-versions", err)
+		}
+	}
+	return nil
+}
+
+// binaryVersionsCheck returns a readiness check that fails with the provided
+// error if it is not nil. The versions of binaries do not change while the
+// controller is running, so they are only checked once, at startup.
+func binaryVersionsCheck(err error) func(*http.Request) error {
+	if err != nil {
+		checkStatus.WithLabelValues("binary-versions").Set(0)
+	} else {
+		checkStatus.WithLabelValues("binary-versions").Set(1)
+	}
+	return func(*http.Request) error {
+		return err
+	}
+}
+
+// cachedCheck wraps a readiness check so that it is run at most once per TTL,
+// with probes arriving in the meantime receiving the cached result. Runs of
+// the check are serialized, so that concurrent probes never result in
+// concurrent runs.
+type cachedCheck struct {
+	name  string
+	ttl   time.Duration
+	check func(context.Context) error
+	nowFn func() time.Time
+
+	mu      sync.Mutex
+	lastRun time.Time
+	lastErr error
+}
+
+func newCachedCheck(
+	name string,
+	ttl time.Duration,
+	check func(context.Context) error,
+) *cachedCheck {
+	return &cachedCheck{
+		name:  name,
+		ttl:   ttl,
+		check: check,
+		nowFn: time.Now,
+	}
+}
+
+// Check implements healthz.Checker.
+func (c *cachedCheck) Check(*http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.nowFn()
+	if !c.lastRun.IsZero() && now.Sub(c.lastRun) < c.ttl {
+		return c.lastErr
+	}
+	// The check is deliberately not bound to the context of the probe, which
+	// may time out sooner. Doing so would result in a timeout being cached.
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	c.lastErr = c.check(ctx)
+	c.lastRun = now
+	if c.lastErr != nil {
+		checkStatus.WithLabelValues(c.name).Set(0)
+	} else {
+		checkStatus.WithLabelValues(c.name).Set(1)
+	}
+	return c.lastErr
+}
+
+// checkKargoAPI verifies that Kargo resources can be listed using the provided
+// Reader.
+func checkKargoAPI(ctx context.Context, reader client.Reader) error {
+	for _, list := range []client.ObjectList{
+		&kargoapi.StageList{},
+		&kargoapi.WarehouseList{},
+		&kargoapi.PromotionList{},
+		&kargoapi.FreightList{},
+	} {
+		if err := reader.List(ctx, list, client.Limit(1)); err != nil {
+			return fmt.Errorf("error listing %T: %w", list, err)
+		}
+	}
+	return nil
+}
+
+// checkArgoCD verifies that Argo CD Applications can be listed using the
+// provided Reader. The check is limited to the Argo CD namespace, as access to
+// Applications may be limited to that namespace.
+func checkArgoCD(ctx context.Context, reader client.Reader) error {
+	if err := reader.List(
+		ctx,
+		&argocd.ApplicationList{},
+		client.InNamespace(libargocd.Namespace()),
+		client.Limit(1),
+	); err != nil {
+		return fmt.Errorf("error listing Argo CD Applications: %w", err)
+	}
+	return nil
+}
+
+// checkGitRepo verifies that the remote Git repository at the provided URL can
+// be reached using the provided function for looking up a remote branch.
+func checkGitRepo(
+	repoURL string,
+	remoteBranchCommitFn func(string, string, *git.ClientOptions) (string, error),
+) error {
+	if _, err := remoteBranchCommitFn(repoURL, canaryBranch, nil); err != nil &&
+		!errors.Is(err, git.ErrRemoteBranchNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+func TestCachedCheck_Check(t *testing.T) {
+	now := time.Now()
+	var runs int
+	checkErr := errors.New("something went wrong")
+	c := newCachedCheck(
+		"fake-check",
+		time.Minute,
+		func(context.Context) error {
+			runs++
+			return checkErr
+		},
+	)
+	c.nowFn = func() time.Time { return now }
+
+	require.ErrorIs(t, c.Check(nil), checkErr)
+	require.Equal(t, 1, runs)
+	require.Equal(t, float64(0), testutil.ToFloat64(checkStatus.WithLabelValues("fake-check")))
+
+	// The cached result is returned until the TTL elapses
+	checkErr = nil
+	now = now.Add(30 * time.Second)
+	require.Error(t, c.Check(nil))
+	require.Equal(t, 1, runs)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, c.Check(nil))
+	require.Equal(t, 2, runs)
+	require.Equal(t, float64(1), testutil.ToFloat64(checkStatus.WithLabelValues("fake-check")))
+}
+
+func TestCheckKargoAPI(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	require.NoError(t, checkKargoAPI(context.Background(), c))
+
+	c = fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return errors.New("something went wrong")
+		},
+	}).Build()
+	require.ErrorContains(t, checkKargoAPI(context.Background(), c), "something went wrong")
+}
+
+func TestCheckArgoCD(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, argocd.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	require.NoError(t, checkArgoCD(context.Background(), c))
+
+	// Argo CD types are not registered
+	c = fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	require.ErrorContains(t, checkArgoCD(context.Background(), c), "error listing Argo CD Applications")
+}
+
+func TestCheckGitRepo(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		assertions func(*testing.T, error)
+	}{
+		{
+			name: "branch exists",
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "branch does not exist",
+			err:  git.ErrRemoteBranchNotFound,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "repository cannot be reached",
+			err:  errors.New("something went wrong"),
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.assertions(
+				t,
+				checkGitRepo(
+					"https://github.com/example/repo.git",
+					func(repoURL, branch string, _ *git.ClientOptions) (string, error) {
+						require.Equal(t, "https://github.com/example/repo.git", repoURL)
+						require.Equal(t, canaryBranch, branch)
+						return "", testCase.err
+					},
+				),
+			)
+		})
+	}
+}
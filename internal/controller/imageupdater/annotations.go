@@ -0,0 +1,319 @@
+package imageupdater
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+const (
+	// annotationPrefix is the prefix of all annotations that Argo CD Image
+	// Updater recognizes on Argo CD Applications.
+	annotationPrefix = "argocd-image-updater.argoproj.io/"
+
+	// annotationImageList is the annotation listing the images of an Argo CD
+	// Application that Argo CD Image Updater updates. Its value is a
+	// comma-separated list of entries in the format of
+	// "[<alias>=]<image>[:<constraint>]".
+	annotationImageList = annotationPrefix + "image-list"
+
+	settingUpdateStrategy = "update-strategy"
+	settingAllowTags      = "allow-tags"
+	settingIgnoreTags     = "ignore-tags"
+	settingPlatforms      = "platforms"
+	settingPullSecret     = "pull-secret"
+	settingForceUpdate    = "force-update"
+
+	// defaultDiscoveryLimit is the number of images that image subscriptions
+	// created from Argo CD Image Updater annotations discover. It is set
+	// explicitly, rather than left to be defaulted, so that subscriptions can
+	// be compared to those of an existing Warehouse.
+	defaultDiscoveryLimit int32 = 20
+)
+
+// imageSettings are the settings that may be specified for an individual image
+// using an "<alias>.<setting>" annotation and that, if they are not, are taken
+// from the "<setting>" annotation that applies to all images of the Argo CD
+// Application.
+var imageSettings = []string{
+	settingUpdateStrategy,
+	settingAllowTags,
+	settingIgnoreTags,
+	settingPlatforms,
+	settingPullSecret,
+	settingForceUpdate,
+}
+
+// writeBackAnnotations are the annotations, other than the image-specific
+// ones, that configure how Argo CD Image Updater writes updated images back to
+// an Argo CD Application or a Git repository. Kargo has no equivalent of these,
+// as this is up to the promotion steps of a Stage.
+var writeBackAnnotations = []string{
+	annotationPrefix + "write-back-method",
+	annotationPrefix + "write-back-target",
+	annotationPrefix + "git-branch",
+	annotationPrefix + "git-repository",
+}
+
+// image is an entry of the image list annotation.
+type image struct {
+	// alias is the alias of the image, which is used to refer to the image in
+	// the names of image-specific annotations. It may be empty.
+	alias string
+	// repoURL is the URL of the image repository.
+	repoURL string
+	// constraint is the version constraint of the image, or, if the update
+	// strategy is "digest", the tag whose digest is tracked. It may be empty.
+	constraint string
+}
+
+// String returns the alias of the image if it has one, and its repository URL
+// otherwise.
+func (i image) String() string {
+	if i.alias != "" {
+		return fmt.Sprintf("%s (%s)", i.alias, i.repoURL)
+	}
+	return i.repoURL
+}
+
+// translation is the result of translating the Argo CD Image Updater
+// annotations of an Argo CD Application into Kargo image subscriptions.
+type translation struct {
+	// subscriptions are the image subscriptions that are equivalent to the
+	// annotations.
+	subscriptions []kargoapi.RepoSubscription
+	// problems describe the features used by the annotations that could not be
+	// translated. An image whose selection depends upon such a feature has no
+	// subscription at all, since subscribing to it regardless could select
+	// images that Argo CD Image Updater would not have selected. Features that
+	// have no bearing on the selection of images are ignored.
+	problems []string
+}
+
+// translate translates the Argo CD Image Updater annotations among the provided
+// annotations into Kargo image subscriptions. The provided Stage is the one
+// that is authorized to manage the Argo CD Application the annotations are
+// from and is only used in the descriptions of problems.
+func translate(annotations map[string]string, project, stage string) translation {
+	var t translation
+	consumed := map[string]struct{}{annotationImageList: {}}
+
+	images, problems := parseImageList(annotations[annotationImageList])
+	t.problems = append(t.problems, problems...)
+	if len(images) == 0 && len(problems) == 0 {
+		t.problems = append(t.problems, fmt.Sprintf("annotation %q lists no images", annotationImageList))
+	}
+
+	for _, img := range images {
+		settings := make(map[string]string, len(imageSettings))
+		for _, name := range imageSettings {
+			key := annotationPrefix + name
+			if img.alias != "" {
+				if value, ok := annotations[annotationPrefix+img.alias+"."+name]; ok {
+					key = annotationPrefix + img.alias + "." + name
+					settings[name] = value
+					consumed[key] = struct{}{}
+					continue
+				}
+			}
+			if value, ok := annotations[key]; ok {
+				settings[name] = value
+				consumed[key] = struct{}{}
+			}
+		}
+		sub, subProblems := imageSubscription(img, settings, project)
+		t.problems = append(t.problems, subProblems...)
+		if sub != nil {
+			t.subscriptions = append(t.subscriptions, kargoapi.RepoSubscription{Image: sub})
+		}
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		if _, ok := consumed[key]; !ok && strings.HasPrefix(key, annotationPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if isWriteBackAnnotation(key, images) {
+			t.problems = append(t.problems, fmt.Sprintf(
+				"annotation %q is ignored; how updated images are written is "+
+					"determined by the promotion steps of Stage %q",
+				key, stage,
+			))
+			continue
+		}
+		t.problems = append(t.problems, fmt.Sprintf("annotation %q is not supported and is ignored", key))
+	}
+
+	return t
+}
+
+// parseImageList parses the value of the image list annotation. Entries that
+// cannot be parsed are described by the returned problems.
+func parseImageList(value string) ([]image, []string) {
+	var images []image
+	var problems []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		var img image
+		ref := entry
+		if alias, rest, ok := strings.Cut(entry, "="); ok {
+			img.alias, ref = strings.TrimSpace(alias), strings.TrimSpace(rest)
+		}
+		if strings.Contains(ref, "@") {
+			problems = append(problems, fmt.Sprintf(
+				"image %q of annotation %q is not subscribed to: images must not be "+
+					"referenced by digest", entry, annotationImageList,
+			))
+			continue
+		}
+		// A colon after the last slash separates the version constraint from
+		// the repository. Any other colon precedes the port of a registry.
+		img.repoURL = ref
+		if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+			img.repoURL, img.constraint = ref[:i], ref[i+1:]
+		}
+		if img.repoURL == "" {
+			problems = append(problems, fmt.Sprintf(
+				"image %q of annotation %q is not subscribed to: no image repository "+
+					"is specified", entry, annotationImageList,
+			))
+			continue
+		}
+		images = append(images, img)
+	}
+	return images, problems
+}
+
+// imageSubscription returns the Kargo image subscription that selects the same
+// images as Argo CD Image Updater does for the provided image with the provided
+// settings. If that is not possible, nil is returned, along with problems
+// describing why. Problems are also returned for settings that are ignored.
+func imageSubscription(
+	img image,
+	settings map[string]string,
+	project string,
+) (*kargoapi.ImageSubscription, []string) {
+	var problems []string
+	notSubscribed := func(format string, args ...any) (*kargoapi.ImageSubscription, []string) {
+		return nil, append(problems, fmt.Sprintf(
+			"image %s is not subscribed to: %s", img, fmt.Sprintf(format, args...),
+		))
+	}
+
+	sub := &kargoapi.ImageSubscription{
+		RepoURL:        img.repoURL,
+		DiscoveryLimit: defaultDiscoveryLimit,
+	}
+
+	switch strategy := settings[settingUpdateStrategy]; strategy {
+	case "", "semver":
+		sub.ImageSelectionStrategy = kargoapi.ImageSelectionStrategySemVer
+		sub.SemverConstraint = img.constraint
+		// Argo CD Image Updater considers any tag that parses as a semantic
+		// version, including ones that lack minor or patch version components.
+		sub.StrictSemvers = false
+	case "digest":
+		if img.constraint == "" {
+			return notSubscribed("update strategy %q requires the tag to track to be specified", strategy)
+		}
+		sub.ImageSelectionStrategy = kargoapi.ImageSelectionStrategyDigest
+		sub.SemverConstraint = img.constraint
+	case "latest", "newest-build":
+		sub.ImageSelectionStrategy = kargoapi.ImageSelectionStrategyNewestBuild
+	case "name", "alphabetical":
+		sub.ImageSelectionStrategy = kargoapi.ImageSelectionStrategyLexical
+	default:
+		return notSubscribed("update strategy %q is not supported", strategy)
+	}
+	if img.constraint != "" &&
+		sub.ImageSelectionStrategy != kargoapi.ImageSelectionStrategySemVer &&
+		sub.ImageSelectionStrategy != kargoapi.ImageSelectionStrategyDigest {
+		problems = append(problems, fmt.Sprintf(
+			"version constraint %q of image %s is ignored by update strategy %q",
+			img.constraint, img, settings[settingUpdateStrategy],
+		))
+	}
+
+	if allowTags, ok := settings[settingAllowTags]; ok && allowTags != "any" {
+		expr, ok := strings.CutPrefix(allowTags, "regexp:")
+		if !ok {
+			return notSubscribed("allowed tags %q must be \"any\" or start with \"regexp:\"", allowTags)
+		}
+		if _, err := regexp.Compile(expr); err != nil {
+			return notSubscribed("allowed tags %q are not a valid regular expression: %v", allowTags, err)
+		}
+		sub.AllowTags = expr
+	}
+
+	if ignoreTags, ok := settings[settingIgnoreTags]; ok {
+		for _, tag := range strings.Split(ignoreTags, ",") {
+			if tag = strings.TrimSpace(tag); tag == "" {
+				continue
+			}
+			if strings.ContainsAny(tag, "*?[") {
+				return notSubscribed(
+					"ignored tag %q is a glob pattern, but only exact tags can be ignored", tag,
+				)
+			}
+			sub.IgnoreTags = append(sub.IgnoreTags, tag)
+		}
+	}
+
+	if platforms, ok := settings[settingPlatforms]; ok {
+		var list []string
+		for _, platform := range strings.Split(platforms, ",") {
+			if platform = strings.TrimSpace(platform); platform != "" {
+				list = append(list, platform)
+			}
+		}
+		if len(list) > 1 {
+			return notSubscribed("platforms %q specify more than one platform", platforms)
+		}
+		if len(list) == 1 {
+			sub.Platform = list[0]
+		}
+	}
+
+	if _, ok := settings[settingPullSecret]; ok {
+		problems = append(problems, fmt.Sprintf(
+			"pull secret of image %s is ignored; the image repository is accessed "+
+				"using the credentials of Project %q", img, project,
+		))
+	}
+	if _, ok := settings[settingForceUpdate]; ok {
+		problems = append(problems, fmt.Sprintf(
+			"force update of image %s is ignored; Freight is created whenever "+
+				"a new image is selected", img,
+		))
+	}
+
+	return sub, problems
+}
+
+// isWriteBackAnnotation returns true if the annotation with the provided key
+// configures how Argo CD Image Updater writes updated images, either for the
+// Argo CD Application as a whole or for one of the provided images.
+func isWriteBackAnnotation(key string, images []image) bool {
+	if slices.Contains(writeBackAnnotations, key) {
+		return true
+	}
+	for _, img := range images {
+		if img.alias == "" {
+			continue
+		}
+		prefix := annotationPrefix + img.alias + "."
+		if strings.HasPrefix(key, prefix+"helm.") || strings.HasPrefix(key, prefix+"kustomize.") {
+			return true
+		}
+	}
+	return false
+}
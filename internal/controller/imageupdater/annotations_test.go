@@ -0,0 +1,166 @@
+package imageupdater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_parseImageList(t *testing.T) {
+	images, problems := parseImageList(
+		"app=ghcr.io/example/app:~1.2, localhost:5000/tool, " +
+			"nginx:1.27 ,, bad=example/app@sha256:abc, empty=:1.x",
+	)
+	require.Equal(
+		t,
+		[]image{
+			{alias: "app", repoURL: "ghcr.io/example/app", constraint: "~1.2"},
+			{repoURL: "localhost:5000/tool"},
+			{repoURL: "nginx", constraint: "1.27"},
+		},
+		images,
+	)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "must not be referenced by digest")
+	require.Contains(t, problems[1], "no image repository is specified")
+}
+
+func Test_translate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		subscriptions []kargoapi.RepoSubscription
+		problems      []string
+	}{
+		{
+			name: "semver with constraint",
+			annotations: map[string]string{
+				annotationImageList: "app=ghcr.io/example/app:1.x",
+			},
+			subscriptions: []kargoapi.RepoSubscription{{
+				Image: &kargoapi.ImageSubscription{
+					RepoURL:                "ghcr.io/example/app",
+					ImageSelectionStrategy: kargoapi.ImageSelectionStrategySemVer,
+					SemverConstraint:       "1.x",
+					DiscoveryLimit:         defaultDiscoveryLimit,
+				},
+			}},
+		},
+		{
+			name: "image-specific settings take precedence",
+			annotations: map[string]string{
+				annotationImageList:                       "app=ghcr.io/example/app,tool=ghcr.io/example/tool",
+				annotationPrefix + "update-strategy":      "newest-build",
+				annotationPrefix + "allow-tags":           "regexp:^main-",
+				annotationPrefix + "app.update-strategy":  "alphabetical",
+				annotationPrefix + "app.ignore-tags":      "main-broken, main-old",
+				annotationPrefix + "tool.platforms":       "linux/arm64",
+				annotationPrefix + "tool.allow-tags":      "any",
+				annotationPrefix + "unrelated.allow-tags": "regexp:.*",
+			},
+			subscriptions: []kargoapi.RepoSubscription{
+				{
+					Image: &kargoapi.ImageSubscription{
+						RepoURL:                "ghcr.io/example/app",
+						ImageSelectionStrategy: kargoapi.ImageSelectionStrategyLexical,
+						AllowTags:              "^main-",
+						IgnoreTags:             []string{"main-broken", "main-old"},
+						DiscoveryLimit:         defaultDiscoveryLimit,
+					},
+				},
+				{
+					Image: &kargoapi.ImageSubscription{
+						RepoURL:                "ghcr.io/example/tool",
+						ImageSelectionStrategy: kargoapi.ImageSelectionStrategyNewestBuild,
+						Platform:               "linux/arm64",
+						DiscoveryLimit:         defaultDiscoveryLimit,
+					},
+				},
+			},
+			problems: []string{
+				`annotation "argocd-image-updater.argoproj.io/unrelated.allow-tags" is not supported and is ignored`,
+			},
+		},
+		{
+			name: "digest",
+			annotations: map[string]string{
+				annotationImageList:                  "app=ghcr.io/example/app:latest,tool=ghcr.io/example/tool",
+				annotationPrefix + "update-strategy": "digest",
+			},
+			subscriptions: []kargoapi.RepoSubscription{{
+				Image: &kargoapi.ImageSubscription{
+					RepoURL:                "ghcr.io/example/app",
+					ImageSelectionStrategy: kargoapi.ImageSelectionStrategyDigest,
+					SemverConstraint:       "latest",
+					DiscoveryLimit:         defaultDiscoveryLimit,
+				},
+			}},
+			problems: []string{
+				`image tool (ghcr.io/example/tool) is not subscribed to: update strategy "digest" ` +
+					`requires the tag to track to be specified`,
+			},
+		},
+		{
+			name: "unsupported selection features",
+			annotations: map[string]string{
+				annotationImageList:                    "a=example/a,b=example/b,c=example/c,d=example/d",
+				annotationPrefix + "a.update-strategy": "random",
+				annotationPrefix + "b.allow-tags":      "^v1",
+				annotationPrefix + "c.ignore-tags":     "v1.*",
+				annotationPrefix + "d.platforms":       "linux/amd64,linux/arm64",
+			},
+			problems: []string{
+				`image a (example/a) is not subscribed to: update strategy "random" is not supported`,
+				`image b (example/b) is not subscribed to: allowed tags "^v1" must be "any" or start with "regexp:"`,
+				`image c (example/c) is not subscribed to: ignored tag "v1.*" is a glob pattern, ` +
+					`but only exact tags can be ignored`,
+				`image d (example/d) is not subscribed to: platforms "linux/amd64,linux/arm64" ` +
+					`specify more than one platform`,
+			},
+		},
+		{
+			name: "write-back and credentials are ignored",
+			annotations: map[string]string{
+				annotationImageList:                     "app=ghcr.io/example/app:1.x",
+				annotationPrefix + "write-back-method":  "git",
+				annotationPrefix + "app.helm.image-tag": "image.tag",
+				annotationPrefix + "app.pull-secret":    "pullsecret:argocd/ghcr",
+				"kargo.akuity.io/authorized-stage":      "demo:prod",
+			},
+			subscriptions: []kargoapi.RepoSubscription{{
+				Image: &kargoapi.ImageSubscription{
+					RepoURL:                "ghcr.io/example/app",
+					ImageSelectionStrategy: kargoapi.ImageSelectionStrategySemVer,
+					SemverConstraint:       "1.x",
+					DiscoveryLimit:         defaultDiscoveryLimit,
+				},
+			}},
+			problems: []string{
+				`pull secret of image app (ghcr.io/example/app) is ignored; the image repository ` +
+					`is accessed using the credentials of Project "demo"`,
+				`annotation "argocd-image-updater.argoproj.io/app.helm.image-tag" is ignored; how ` +
+					`updated images are written is determined by the promotion steps of Stage "prod"`,
+				`annotation "argocd-image-updater.argoproj.io/write-back-method" is ignored; how ` +
+					`updated images are written is determined by the promotion steps of Stage "prod"`,
+			},
+		},
+		{
+			name: "no images",
+			annotations: map[string]string{
+				annotationImageList: " ",
+			},
+			problems: []string{
+				`annotation "argocd-image-updater.argoproj.io/image-list" lists no images`,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := translate(testCase.annotations, "demo", "prod")
+			require.Equal(t, testCase.subscriptions, result.subscriptions)
+			require.Equal(t, testCase.problems, result.problems)
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package imageupdater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	"github.com/akuity/kargo/internal/controller"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	libEvent "github.com/akuity/kargo/internal/kubernetes/event"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+const (
+	// reasonWarehouseSynced is the reason of the Event that is recorded on an
+	// Argo CD Application when the Warehouse that corresponds to it has been
+	// created or updated.
+	reasonWarehouseSynced = "KargoWarehouseSynced"
+	// reasonTranslationProblems is the reason of the Event that is recorded on
+	// an Argo CD Application when some of its Argo CD Image Updater annotations
+	// could not be translated.
+	reasonTranslationProblems = "KargoUnsupportedImageUpdaterAnnotations"
+	// reasonTranslationFailed is the reason of the Event that is recorded on an
+	// Argo CD Application when its Argo CD Image Updater annotations could not
+	// be translated at all.
+	reasonTranslationFailed = "KargoImageUpdaterTranslationFailed"
+
+	// defaultWarehouseInterval is the reconciliation interval of Warehouses
+	// created from Argo CD Image Updater annotations.
+	defaultWarehouseInterval = 5 * time.Minute
+)
+
+// errWarehouseNotMaintained is returned when the Warehouse that corresponds to
+// an Argo CD Application exists, but was not created for that Application.
+var errWarehouseNotMaintained = errors.New(
+	"Warehouse exists, but is not maintained for this Application",
+)
+
+// ReconcilerConfig is configuration for the reconciler that translates the
+// Argo CD Image Updater annotations of Argo CD Applications into Warehouses.
+type ReconcilerConfig struct {
+	// Enabled specifies whether the reconciler is enabled at all.
+	Enabled                 bool `envconfig:"ARGOCD_IMAGE_UPDATER_COMPATIBILITY_ENABLED" default:"false"`
+	MaxConcurrentReconciles int  `envconfig:"MAX_CONCURRENT_ARGOCD_IMAGE_UPDATER_RECONCILES" default:"1"`
+}
+
+func (c ReconcilerConfig) Name() string {
+	return "argocd-image-updater-compatibility-controller"
+}
+
+// ReconcilerConfigFromEnv returns a ReconcilerConfig populated from environment
+// variables.
+func ReconcilerConfigFromEnv() ReconcilerConfig {
+	var cfg ReconcilerConfig
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+// reconciler translates the Argo CD Image Updater annotations of Argo CD
+// Applications into the image subscriptions of Warehouses, so that the images
+// Argo CD Image Updater would have updated the Applications to become
+// available as Freight instead. Each Application is translated into a
+// Warehouse of the same name in the Project of the Stage that is authorized to
+// manage the Application. Problems that prevent annotations from being
+// translated faithfully are recorded as Events on the Application.
+type reconciler struct {
+	argocdClient client.Client
+	kargoClient  client.Client
+	recorder     record.EventRecorder
+}
+
+// SetupReconcilerWithManager initializes a reconciler for Argo CD Applications
+// carrying Argo CD Image Updater annotations and registers it with the
+// provided Manager. Nothing is registered if the reconciler is disabled by the
+// provided ReconcilerConfig or if Argo CD integration is disabled. Only
+// Applications of the default Argo CD context are watched.
+func SetupReconcilerWithManager(
+	ctx context.Context,
+	kargoMgr manager.Manager,
+	argoCDContexts *libargocd.Contexts,
+	cfg ReconcilerConfig,
+) error {
+	logger := logging.LoggerFromContext(ctx)
+	if !cfg.Enabled {
+		return nil
+	}
+	argoCDCtx, err := argoCDContexts.Get("")
+	if err != nil {
+		return err
+	}
+	if argoCDCtx == nil || argoCDCtx.Cache == nil {
+		logger.Info(
+			"Argo CD Image Updater compatibility was enabled, but Argo CD " +
+				"integration is disabled. Proceeding without Argo CD Image Updater " +
+				"compatibility.",
+		)
+		return nil
+	}
+
+	r := &reconciler{
+		argocdClient: argoCDCtx.Client,
+		kargoClient:  kargoMgr.GetClient(),
+		recorder: libEvent.NewRecorder(
+			ctx, argoCDCtx.Client.Scheme(), argoCDCtx.Client, cfg.Name(),
+		),
+	}
+
+	if err = ctrl.NewControllerManagedBy(kargoMgr).
+		Named("argocd_image_updater_compatibility").
+		WatchesRawSource(
+			source.Kind(
+				argoCDCtx.Cache,
+				&argocd.Application{},
+				&handler.TypedEnqueueRequestForObject[*argocd.Application]{},
+				// Events are only recorded when annotations change, so that the
+				// frequent updates to the status of Applications do not flood
+				// them with Events.
+				predicate.TypedAnnotationChangedPredicate[*argocd.Application]{},
+				predicate.NewTypedPredicateFuncs(func(app *argocd.Application) bool {
+					_, ok := app.Annotations[annotationImageList]
+					return ok
+				}),
+			),
+		).
+		WithOptions(controller.CommonOptions(cfg.MaxConcurrentReconciles)).
+		Complete(r); err != nil {
+		return fmt.Errorf("error building Argo CD Image Updater compatibility controller: %w", err)
+	}
+
+	logger.Info(
+		"Initialized Argo CD Image Updater compatibility reconciler",
+		"maxConcurrentReconciles", cfg.MaxConcurrentReconciles,
+	)
+
+	return nil
+}
+
+// Reconcile translates the Argo CD Image Updater annotations of an Argo CD
+// Application into the image subscriptions of a Warehouse.
+func (r *reconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"namespace", req.NamespacedName.Namespace,
+		"app", req.NamespacedName.Name,
+	)
+	ctx = logging.ContextWithLogger(ctx, logger)
+
+	app := &argocd.Application{}
+	if err := r.argocdClient.Get(ctx, req.NamespacedName, app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if _, ok := app.Annotations[annotationImageList]; !ok {
+		// The Warehouse, if any, is deliberately left alone, so that removing
+		// the annotations once a Stage has been migrated to the Warehouse does
+		// not disrupt it.
+		return ctrl.Result{}, nil
+	}
+
+	project, stage, ok := authorizedStage(app)
+	if !ok {
+		r.recorder.Eventf(
+			app, corev1.EventTypeWarning, reasonTranslationFailed,
+			"Argo CD Image Updater annotations cannot be translated into a Kargo "+
+				"Warehouse: annotation %q must identify the Kargo Stage that is "+
+				"authorized to manage the Application in the format of "+
+				"\"<project>:<stage>\"",
+			kargoapi.AnnotationKeyAuthorizedStage,
+		)
+		return ctrl.Result{}, nil
+	}
+	logger = logger.WithValues("project", project, "stage", stage)
+	ctx = logging.ContextWithLogger(ctx, logger)
+
+	t := translate(app.Annotations, project, stage)
+	if len(t.problems) > 0 {
+		r.recorder.Eventf(
+			app, corev1.EventTypeWarning, reasonTranslationProblems,
+			"Some Argo CD Image Updater annotations cannot be translated into "+
+				"Kargo Warehouse %q in Project %q: %s",
+			app.Name, project, strings.Join(t.problems, "; "),
+		)
+	}
+	if len(t.subscriptions) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	changed, err := r.syncWarehouse(ctx, app, project, t.subscriptions)
+	if err != nil {
+		r.recorder.Eventf(
+			app, corev1.EventTypeWarning, reasonTranslationFailed,
+			"Kargo Warehouse %q in Project %q cannot be updated: %s",
+			app.Name, project, err,
+		)
+		if errors.Is(err, errWarehouseNotMaintained) {
+			// Retrying is pointless until either resource changes.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if changed {
+		logger.Debug("synced Warehouse")
+		r.recorder.Eventf(
+			app, corev1.EventTypeNormal, reasonWarehouseSynced,
+			"Kargo Warehouse %q in Project %q subscribes to %d image(s); Stage %q "+
+				"receives them once it requests Freight from that Warehouse",
+			app.Name, project, len(t.subscriptions), stage,
+		)
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncWarehouse creates the Warehouse that corresponds to the provided Argo CD
+// Application in the provided Project, or updates its subscriptions to the
+// provided ones. Only the subscriptions of an existing Warehouse are updated,
+// so that other fields may be tuned by users. A Warehouse that exists, but was
+// not created for the Application, is never updated. It returns true if the
+// Warehouse was created or updated.
+func (r *reconciler) syncWarehouse(
+	ctx context.Context,
+	app *argocd.Application,
+	project string,
+	subs []kargoapi.RepoSubscription,
+) (bool, error) {
+	appRef := app.Namespace + "/" + app.Name
+
+	warehouse := &kargoapi.Warehouse{}
+	err := r.kargoClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: project, Name: app.Name},
+		warehouse,
+	)
+	if client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("error getting Warehouse: %w", err)
+	}
+	if err != nil {
+		warehouse = &kargoapi.Warehouse{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: project,
+				Name:      app.Name,
+				Annotations: map[string]string{
+					kargoapi.AnnotationKeyArgoCDImageUpdaterApplication: appRef,
+				},
+			},
+			Spec: kargoapi.WarehouseSpec{
+				Interval:              metav1.Duration{Duration: defaultWarehouseInterval},
+				FreightCreationPolicy: kargoapi.FreightCreationPolicyAutomatic,
+				Subscriptions:         subs,
+			},
+		}
+		if err = r.kargoClient.Create(ctx, warehouse); err != nil {
+			return false, fmt.Errorf("error creating Warehouse: %w", err)
+		}
+		return true, nil
+	}
+
+	if managedBy := warehouse.Annotations[kargoapi.AnnotationKeyArgoCDImageUpdaterApplication]; managedBy != appRef {
+		return false, fmt.Errorf(
+			"%w; its %q annotation is %q",
+			errWarehouseNotMaintained, kargoapi.AnnotationKeyArgoCDImageUpdaterApplication, managedBy,
+		)
+	}
+	if equality.Semantic.DeepEqual(warehouse.Spec.Subscriptions, subs) {
+		return false, nil
+	}
+	patch := client.MergeFrom(warehouse.DeepCopy())
+	warehouse.Spec.Subscriptions = subs
+	if err = r.kargoClient.Patch(ctx, warehouse, patch); err != nil {
+		return false, fmt.Errorf("error patching Warehouse: %w", err)
+	}
+	return true, nil
+}
+
+// authorizedStage returns the Project and name of the Stage that is authorized
+// to manage the provided Argo CD Application, along with true, or false if no
+// single Stage is.
+func authorizedStage(app *argocd.Application) (string, string, bool) {
+	project, stage, ok := strings.Cut(app.Annotations[kargoapi.AnnotationKeyAuthorizedStage], ":")
+	if !ok || project == "" || stage == "" ||
+		strings.Contains(project, "*") || strings.Contains(stage, "*") {
+		return "", "", false
+	}
+	return project, stage, true
+}
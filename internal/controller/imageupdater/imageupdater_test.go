@@ -0,0 +1,213 @@
+package imageupdater
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
+)
+
+func TestReconciler_Reconcile(t *testing.T) {
+	argocdScheme := runtime.NewScheme()
+	require.NoError(t, argocd.AddToScheme(argocdScheme))
+	kargoScheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(kargoScheme))
+
+	newApp := func(annotations map[string]string) *argocd.Application {
+		return &argocd.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "argocd",
+				Name:        "guestbook",
+				Annotations: annotations,
+			},
+		}
+	}
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: "argocd", Name: "guestbook"},
+	}
+	warehouseKey := types.NamespacedName{Namespace: "demo", Name: "guestbook"}
+
+	testCases := []struct {
+		name       string
+		app        *argocd.Application
+		objects    []client.Object
+		assertions func(*testing.T, client.Client, []fakeevent.Event, error)
+	}{
+		{
+			name: "Application not found",
+			assertions: func(t *testing.T, _ client.Client, events []fakeevent.Event, err error) {
+				require.NoError(t, err)
+				require.Empty(t, events)
+			},
+		},
+		{
+			name: "no authorized Stage",
+			app: newApp(map[string]string{
+				annotationImageList: "ghcr.io/example/app",
+			}),
+			assertions: func(t *testing.T, c client.Client, events []fakeevent.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, corev1.EventTypeWarning, events[0].EventType)
+				require.Equal(t, reasonTranslationFailed, events[0].Reason)
+				require.Contains(t, events[0].Message, kargoapi.AnnotationKeyAuthorizedStage)
+				err = c.Get(context.Background(), warehouseKey, &kargoapi.Warehouse{})
+				require.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+			},
+		},
+		{
+			name: "Warehouse is created",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage:  "demo:prod",
+				annotationImageList:                    "app=ghcr.io/example/app:^1.0.0",
+				annotationPrefix + "write-back-method": "git",
+			}),
+			assertions: func(t *testing.T, c client.Client, events []fakeevent.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 2)
+				require.Equal(t, reasonTranslationProblems, events[0].Reason)
+				require.Contains(t, events[0].Message, "write-back-method")
+				require.Equal(t, corev1.EventTypeNormal, events[1].EventType)
+				require.Equal(t, reasonWarehouseSynced, events[1].Reason)
+
+				warehouse := &kargoapi.Warehouse{}
+				require.NoError(t, c.Get(context.Background(), warehouseKey, warehouse))
+				require.Equal(
+					t,
+					"argocd/guestbook",
+					warehouse.Annotations[kargoapi.AnnotationKeyArgoCDImageUpdaterApplication],
+				)
+				require.Equal(t, defaultWarehouseInterval, warehouse.Spec.Interval.Duration)
+				require.Len(t, warehouse.Spec.Subscriptions, 1)
+				require.Equal(t, "ghcr.io/example/app", warehouse.Spec.Subscriptions[0].Image.RepoURL)
+				require.Equal(t, "^1.0.0", warehouse.Spec.Subscriptions[0].Image.SemverConstraint)
+			},
+		},
+		{
+			name: "maintained Warehouse is updated",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				annotationImageList:                   "ghcr.io/example/app:^2.0.0",
+			}),
+			objects: []client.Object{
+				&kargoapi.Warehouse{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "demo",
+						Name:      "guestbook",
+						Annotations: map[string]string{
+							kargoapi.AnnotationKeyArgoCDImageUpdaterApplication: "argocd/guestbook",
+						},
+					},
+					Spec: kargoapi.WarehouseSpec{
+						FreightCreationPolicy: kargoapi.FreightCreationPolicyManual,
+						Subscriptions: []kargoapi.RepoSubscription{{
+							Image: &kargoapi.ImageSubscription{
+								RepoURL:          "ghcr.io/example/app",
+								SemverConstraint: "^1.0.0",
+							},
+						}},
+					},
+				},
+			},
+			assertions: func(t *testing.T, c client.Client, events []fakeevent.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, reasonWarehouseSynced, events[0].Reason)
+
+				warehouse := &kargoapi.Warehouse{}
+				require.NoError(t, c.Get(context.Background(), warehouseKey, warehouse))
+				require.Equal(t, "^2.0.0", warehouse.Spec.Subscriptions[0].Image.SemverConstraint)
+				// Fields other than the subscriptions are left alone
+				require.Equal(
+					t,
+					kargoapi.FreightCreationPolicyManual,
+					warehouse.Spec.FreightCreationPolicy,
+				)
+			},
+		},
+		{
+			name: "Warehouse not maintained for the Application is left alone",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				annotationImageList:                   "ghcr.io/example/app:^2.0.0",
+			}),
+			objects: []client.Object{
+				&kargoapi.Warehouse{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "demo",
+						Name:      "guestbook",
+					},
+					Spec: kargoapi.WarehouseSpec{
+						Subscriptions: []kargoapi.RepoSubscription{{
+							Image: &kargoapi.ImageSubscription{
+								RepoURL: "ghcr.io/example/other",
+							},
+						}},
+					},
+				},
+			},
+			assertions: func(t *testing.T, c client.Client, events []fakeevent.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, corev1.EventTypeWarning, events[0].EventType)
+				require.Contains(t, events[0].Message, "not maintained for this Application")
+
+				warehouse := &kargoapi.Warehouse{}
+				require.NoError(t, c.Get(context.Background(), warehouseKey, warehouse))
+				require.Equal(t, "ghcr.io/example/other", warehouse.Spec.Subscriptions[0].Image.RepoURL)
+			},
+		},
+		{
+			name: "nothing translatable",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				annotationImageList:                   "app=ghcr.io/example/app",
+				annotationPrefix + "app.ignore-tags":  "*-rc",
+			}),
+			assertions: func(t *testing.T, c client.Client, events []fakeevent.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, reasonTranslationProblems, events[0].Reason)
+				require.Contains(t, events[0].Message, "is not subscribed to")
+				err = c.Get(context.Background(), warehouseKey, &kargoapi.Warehouse{})
+				require.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			argocdClient := fake.NewClientBuilder().WithScheme(argocdScheme)
+			if testCase.app != nil {
+				argocdClient = argocdClient.WithObjects(testCase.app)
+			}
+			kargoClient := fake.NewClientBuilder().
+				WithScheme(kargoScheme).
+				WithObjects(testCase.objects...).
+				Build()
+			recorder := fakeevent.NewEventRecorder(10)
+			r := &reconciler{
+				argocdClient: argocdClient.Build(),
+				kargoClient:  kargoClient,
+				recorder:     recorder,
+			}
+			_, err := r.Reconcile(context.Background(), req)
+			close(recorder.Events)
+			var events []fakeevent.Event
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			testCase.assertions(t, kargoClient, events, err)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package kargoconfig
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// Watcher keeps track of the KargoConfig resource. Settings that are safe to
+// change at runtime are made available to the rest of the controller as soon
+// as the resource changes. Changes to any other setting are logged, but only
+// take effect after the controller has been restarted.
+//
+// A nil *Watcher is valid and behaves as if no KargoConfig exists.
+type Watcher struct {
+	client client.Client
+
+	// startupSpec is the KargoConfigSpec that was in effect when the controller
+	// started.
+	startupSpec kargoapi.KargoConfigSpec
+	// spec is the KargoConfigSpec that is currently in effect.
+	spec atomic.Pointer[kargoapi.KargoConfigSpec]
+	// repoPolicy is the compiled form of the RepoPolicy of spec.
+	repoPolicy atomic.Pointer[libgit.RepoURLPolicy]
+}
+
+// NewWatcher returns a Watcher for the KargoConfig resource.
+func NewWatcher() *Watcher {
+	w := &Watcher{}
+	w.spec.Store(&kargoapi.KargoConfigSpec{})
+	return w
+}
+
+// store makes the provided spec the one that is currently in effect and
+// returns the one that was previously in effect. If the spec's RepoPolicy is
+// invalid, an error is returned and the previous spec remains in effect.
+func (w *Watcher) store(spec kargoapi.KargoConfigSpec) (*kargoapi.KargoConfigSpec, error) {
+	policy, err := compileRepoPolicy(spec.RepoPolicy)
+	if err != nil {
+		return nil, err
+	}
+	w.repoPolicy.Store(policy)
+	return w.spec.Swap(&spec), nil
+}
+
+// Load retrieves the KargoConfig resource using the provided client.Reader
+// and records its spec as the one in effect at startup. It is intended to be
+// called once, before the controller's managers are started, using a reader
+// that does not depend on a started cache. If no KargoConfig exists, an empty
+// spec is returned.
+func (w *Watcher) Load(
+	ctx context.Context,
+	reader client.Reader,
+) (kargoapi.KargoConfigSpec, error) {
+	spec, err := getSpec(ctx, reader)
+	if err != nil {
+		return spec, err
+	}
+	if _, err = w.store(spec); err != nil {
+		return spec, err
+	}
+	w.startupSpec = spec
+	return spec, nil
+}
+
+// SetupWithManager registers the Watcher with the provided Manager, so that
+// subsequent changes to the KargoConfig resource are observed.
+func (w *Watcher) SetupWithManager(ctx context.Context, mgr manager.Manager) error {
+	w.client = mgr.GetClient()
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&kargoapi.KargoConfig{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == kargoapi.KargoConfigName
+		})).
+		Complete(w); err != nil {
+		return fmt.Errorf("error building KargoConfig watcher: %w", err)
+	}
+	logging.LoggerFromContext(ctx).Info(
+		"Initialized KargoConfig watcher",
+		"pausePromotions", w.PromotionsPaused(),
+		"repoPolicy", w.RepoURLPolicy() != nil,
+	)
+	return nil
+}
+
+// Reconcile records the current spec of the KargoConfig resource.
+func (w *Watcher) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"kargoConfig", kargoapi.KargoConfigName,
+	)
+
+	spec, err := getSpec(ctx, w.client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	old, err := w.store(spec)
+	if err != nil {
+		// The webhook prevents this from happening, so it is not worth retrying.
+		logger.Error(err, "ignoring invalid KargoConfig")
+		return ctrl.Result{}, nil
+	}
+	if old.PausePromotions != spec.PausePromotions {
+		logger.Info(
+			"KargoConfig setting changed",
+			"setting", "spec.pausePromotions",
+			"value", spec.PausePromotions,
+		)
+	}
+	if !equality.Semantic.DeepEqual(old.RepoPolicy, spec.RepoPolicy) {
+		logger.Info(
+			"KargoConfig setting changed",
+			"setting", "spec.repoPolicy",
+		)
+	}
+	if !equality.Semantic.DeepEqual(old.ImageLimits, spec.ImageLimits) {
+		logger.Info(
+			"KargoConfig setting changed",
+			"setting", "spec.imageLimits",
+		)
+	}
+	if !equality.Semantic.DeepEqual(old.ExecPolicy, spec.ExecPolicy) {
+		logger.Info(
+			"KargoConfig setting changed",
+			"setting", "spec.execPolicy",
+		)
+	}
+	if !equality.Semantic.DeepEqual(old.ResourceLimits, spec.ResourceLimits) {
+		logger.Info(
+			"KargoConfig setting changed",
+			"setting", "spec.resourceLimits",
+		)
+	}
+	if !equality.Semantic.DeepEqual(old.RemoteBasePolicy, spec.RemoteBasePolicy) {
+		logger.Info(
+			"KargoConfig setting changed",
+			"setting", "spec.remoteBasePolicy",
+		)
+	}
+	if !equality.Semantic.DeepEqual(w.startupSpec.GitClient, spec.GitClient) {
+		logger.Info(
+			"KargoConfig setting differs from the one in effect; "+
+				"restart the controller for it to take effect",
+			"setting", "spec.gitClient",
+		)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// PromotionsPaused returns true if the execution of all Promotions is
+// currently paused by the KargoConfig resource.
+func (w *Watcher) PromotionsPaused() bool {
+	if w == nil {
+		return false
+	}
+	return w.spec.Load().PausePromotions
+}
+
+// RepoURLPolicy returns the policy that restricts the Git repositories that
+// Promotions may access, as specified by the KargoConfig resource. A nil
+// policy, which allows access to all repositories, is returned if none is
+// specified.
+func (w *Watcher) RepoURLPolicy() *libgit.RepoURLPolicy {
+	if w == nil {
+		return nil
+	}
+	return w.repoPolicy.Load()
+}
+
+// ImageLimits returns the limits on the number of container images that
+// Freight may reference and how many of them are itemized, as specified by the
+// KargoConfig resource. Nil, which is valid and implies the default limits, is
+// returned if none are specified.
+func (w *Watcher) ImageLimits() *kargoapi.ImageLimits {
+	if w == nil {
+		return nil
+	}
+	return w.spec.Load().ImageLimits
+}
+
+// ExecPolicy returns the policy that specifies the commands the exec-render
+// promotion step may execute, as specified by the KargoConfig resource. Nil,
+// which is valid and allows no commands, is returned if none is specified.
+func (w *Watcher) ExecPolicy() *kargoapi.ExecPolicy {
+	if w == nil {
+		return nil
+	}
+	return w.spec.Load().ExecPolicy
+}
+
+// ResourceLimits returns the limits on the size of the Git repositories that
+// Promotions clone and of the manifests they render, as specified by the
+// KargoConfig resource. Nil, which is valid and implies the default limits, is
+// returned if none are specified.
+func (w *Watcher) ResourceLimits() *kargoapi.ResourceLimits {
+	if w == nil {
+		return nil
+	}
+	return w.spec.Load().ResourceLimits
+}
+
+// RemoteBasePolicy returns the policy that restricts the hosts remote
+// Kustomize bases may be fetched from, as specified by the KargoConfig
+// resource. Nil, which is valid and allows all hosts, is returned if none is
+// specified.
+func (w *Watcher) RemoteBasePolicy() *kargoapi.RemoteBasePolicy {
+	if w == nil {
+		return nil
+	}
+	return w.spec.Load().RemoteBasePolicy
+}
+
+// compileRepoPolicy returns the libgit.RepoURLPolicy for the provided
+// RepoPolicy, or nil if no RepoPolicy is provided.
+func compileRepoPolicy(policy *kargoapi.RepoPolicy) (*libgit.RepoURLPolicy, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	p, err := libgit.NewRepoURLPolicy(policy.Allow, policy.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repo policy: %w", err)
+	}
+	return p, nil
+}
+
+// getSpec retrieves the spec of the KargoConfig resource. If no KargoConfig
+// exists, an empty spec is returned.
+func getSpec(ctx context.Context, reader client.Reader) (kargoapi.KargoConfigSpec, error) {
+	cfg := &kargoapi.KargoConfig{}
+	if err := reader.Get(
+		ctx,
+		types.NamespacedName{Name: kargoapi.KargoConfigName},
+		cfg,
+	); err != nil {
+		if err = client.IgnoreNotFound(err); err != nil {
+			return kargoapi.KargoConfigSpec{}, fmt.Errorf(
+				"error getting KargoConfig %q: %w", kargoapi.KargoConfigName, err,
+			)
+		}
+		return kargoapi.KargoConfigSpec{}, nil
+	}
+	return cfg.Spec, nil
+}
+
+// HostLimiterConfig returns a copy of the provided git.HostLimiterConfig with
+// any limits specified by the provided KargoConfigSpec applied.
+func HostLimiterConfig(
+	spec kargoapi.KargoConfigSpec,
+	cfg git.HostLimiterConfig,
+) git.HostLimiterConfig {
+	if spec.GitClient == nil {
+		return cfg
+	}
+	if spec.GitClient.MaxConcurrentOpsPerHost != nil {
+		cfg.MaxConcurrentOps = int(*spec.GitClient.MaxConcurrentOpsPerHost)
+	}
+	if spec.GitClient.MaxOpsPerMinutePerHost != nil {
+		cfg.MaxOpsPerMinute = int(*spec.GitClient.MaxOpsPerMinutePerHost)
+	}
+	return cfg
+}
+
+// NetworkRetryConfig returns a copy of the provided git.NetworkRetryConfig
+// with any settings specified by the provided KargoConfigSpec applied.
+func NetworkRetryConfig(
+	spec kargoapi.KargoConfigSpec,
+	cfg git.NetworkRetryConfig,
+) git.NetworkRetryConfig {
+	if spec.GitClient != nil && spec.GitClient.NetworkMaxAttempts != nil {
+		cfg.MaxAttempts = int(*spec.GitClient.NetworkMaxAttempts)
+	}
+	return cfg
+}
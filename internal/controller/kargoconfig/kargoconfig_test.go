@@ -0,0 +1,260 @@
+package kargoconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+func TestWatcher_Load(t *testing.T) {
+	testCases := []struct {
+		name       string
+		objects    []client.Object
+		assertions func(*testing.T, *Watcher, kargoapi.KargoConfigSpec, error)
+	}{
+		{
+			name: "KargoConfig does not exist",
+			assertions: func(t *testing.T, w *Watcher, spec kargoapi.KargoConfigSpec, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.KargoConfigSpec{}, spec)
+				require.False(t, w.PromotionsPaused())
+			},
+		},
+		{
+			name: "KargoConfig exists",
+			objects: []client.Object{
+				&kargoapi.KargoConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: kargoapi.KargoConfigName},
+					Spec: kargoapi.KargoConfigSpec{
+						PausePromotions: true,
+						GitClient: &kargoapi.GitClientConfig{
+							NetworkMaxAttempts: ptr.To[int32](5),
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, w *Watcher, spec kargoapi.KargoConfigSpec, err error) {
+				require.NoError(t, err)
+				require.True(t, spec.PausePromotions)
+				require.Equal(t, spec, w.startupSpec)
+				require.True(t, w.PromotionsPaused())
+			},
+		},
+		{
+			name: "KargoConfig with invalid repo policy",
+			objects: []client.Object{
+				&kargoapi.KargoConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: kargoapi.KargoConfigName},
+					Spec: kargoapi.KargoConfigSpec{
+						RepoPolicy: &kargoapi.RepoPolicy{
+							Allow: []string{"regex:("},
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, w *Watcher, _ kargoapi.KargoConfigSpec, err error) {
+				require.ErrorContains(t, err, "invalid repo policy")
+				require.Nil(t, w.RepoURLPolicy())
+			},
+		},
+		{
+			name: "KargoConfig with another name is ignored",
+			objects: []client.Object{
+				&kargoapi.KargoConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "other"},
+					Spec: kargoapi.KargoConfigSpec{
+						PausePromotions: true,
+					},
+				},
+			},
+			assertions: func(t *testing.T, w *Watcher, spec kargoapi.KargoConfigSpec, err error) {
+				require.NoError(t, err)
+				require.False(t, spec.PausePromotions)
+				require.False(t, w.PromotionsPaused())
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := NewWatcher()
+			spec, err := w.Load(
+				context.Background(),
+				newFakeClient(t, testCase.objects...),
+			)
+			testCase.assertions(t, w, spec, err)
+		})
+	}
+}
+
+func TestWatcher_Reconcile(t *testing.T) {
+	cfg := &kargoapi.KargoConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: kargoapi.KargoConfigName},
+	}
+	c := newFakeClient(t, cfg)
+
+	w := NewWatcher()
+	_, err := w.Load(context.Background(), c)
+	require.NoError(t, err)
+	w.client = c
+	require.False(t, w.PromotionsPaused())
+
+	// Pausing takes effect without a restart
+	cfg.Spec.PausePromotions = true
+	require.NoError(t, c.Update(context.Background(), cfg))
+	result, err := w.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+	require.True(t, w.PromotionsPaused())
+
+	// The repo policy takes effect without a restart
+	require.Nil(t, w.RepoURLPolicy())
+	cfg.Spec.RepoPolicy = &kargoapi.RepoPolicy{
+		Allow: []string{"https://github.com/example/*"},
+	}
+	require.NoError(t, c.Update(context.Background(), cfg))
+	_, err = w.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	require.True(t, w.RepoURLPolicy().Evaluate("https://github.com/example/repo").Allowed)
+	require.False(t, w.RepoURLPolicy().Evaluate("https://github.com/other/repo").Allowed)
+
+	// An invalid repo policy is ignored
+	cfg.Spec.RepoPolicy.Allow = []string{"regex:("}
+	require.NoError(t, c.Update(context.Background(), cfg))
+	_, err = w.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	require.True(t, w.RepoURLPolicy().Evaluate("https://github.com/example/repo").Allowed)
+
+	// Image limits take effect without a restart
+	require.Equal(t, kargoapi.DefaultStatusMaxImages, w.ImageLimits().GetStatusMaxImages())
+	cfg.Spec.RepoPolicy = nil
+	cfg.Spec.ImageLimits = &kargoapi.ImageLimits{StatusMaxImages: ptr.To[int32](3)}
+	require.NoError(t, c.Update(context.Background(), cfg))
+	_, err = w.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	require.Equal(t, 3, w.ImageLimits().GetStatusMaxImages())
+
+	// The exec policy takes effect without a restart
+	require.Nil(t, w.ExecPolicy().GetCommand("render"))
+	cfg.Spec.ExecPolicy = &kargoapi.ExecPolicy{
+		Commands: []kargoapi.ExecCommand{{Name: "render", Path: "/usr/local/bin/render"}},
+	}
+	require.NoError(t, c.Update(context.Background(), cfg))
+	_, err = w.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	require.NotNil(t, w.ExecPolicy().GetCommand("render"))
+
+	// Deleting the KargoConfig reverts to the defaults
+	require.NoError(t, c.Delete(context.Background(), cfg))
+	_, err = w.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	require.False(t, w.PromotionsPaused())
+	require.Nil(t, w.RepoURLPolicy())
+	require.Nil(t, w.ImageLimits())
+	require.Nil(t, w.ExecPolicy())
+}
+
+func TestWatcher_PromotionsPaused(t *testing.T) {
+	var w *Watcher
+	require.False(t, w.PromotionsPaused())
+	require.False(t, NewWatcher().PromotionsPaused())
+}
+
+func TestWatcher_RepoURLPolicy(t *testing.T) {
+	var w *Watcher
+	require.Nil(t, w.RepoURLPolicy())
+	require.Nil(t, NewWatcher().RepoURLPolicy())
+}
+
+func TestWatcher_ImageLimits(t *testing.T) {
+	var w *Watcher
+	require.Nil(t, w.ImageLimits())
+	require.Nil(t, NewWatcher().ImageLimits())
+}
+
+func TestWatcher_ExecPolicy(t *testing.T) {
+	var w *Watcher
+	require.Nil(t, w.ExecPolicy())
+	require.Nil(t, NewWatcher().ExecPolicy())
+}
+
+func TestWatcher_ResourceLimits(t *testing.T) {
+	var w *Watcher
+	require.Nil(t, w.ResourceLimits())
+	require.Nil(t, NewWatcher().ResourceLimits())
+}
+
+func TestWatcher_RemoteBasePolicy(t *testing.T) {
+	var w *Watcher
+	require.Nil(t, w.RemoteBasePolicy())
+	require.Nil(t, NewWatcher().RemoteBasePolicy())
+}
+
+func TestHostLimiterConfig(t *testing.T) {
+	envCfg := git.HostLimiterConfig{
+		MaxConcurrentOps: 1,
+		MaxOpsPerMinute:  2,
+	}
+	require.Equal(
+		t,
+		envCfg,
+		HostLimiterConfig(kargoapi.KargoConfigSpec{}, envCfg),
+	)
+	require.Equal(
+		t,
+		git.HostLimiterConfig{
+			MaxConcurrentOps: 0,
+			MaxOpsPerMinute:  2,
+		},
+		HostLimiterConfig(
+			kargoapi.KargoConfigSpec{
+				GitClient: &kargoapi.GitClientConfig{
+					MaxConcurrentOpsPerHost: ptr.To[int32](0),
+				},
+			},
+			envCfg,
+		),
+	)
+}
+
+func TestNetworkRetryConfig(t *testing.T) {
+	envCfg := git.NetworkRetryConfig{
+		MaxAttempts:            3,
+		TransientErrorPatterns: []string{"foo"},
+	}
+	require.Equal(
+		t,
+		envCfg,
+		NetworkRetryConfig(kargoapi.KargoConfigSpec{}, envCfg),
+	)
+	require.Equal(
+		t,
+		git.NetworkRetryConfig{
+			MaxAttempts:            1,
+			TransientErrorPatterns: []string{"foo"},
+		},
+		NetworkRetryConfig(
+			kargoapi.KargoConfigSpec{
+				GitClient: &kargoapi.GitClientConfig{
+					NetworkMaxAttempts: ptr.To[int32](1),
+				},
+			},
+			envCfg,
+		),
+	)
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
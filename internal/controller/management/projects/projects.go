@@ -29,11 +29,35 @@ import (
 )
 
 const (
-	controllerServiceAccountLabelKey     = "app.kubernetes.io/component"
-	controllerServiceAccountLabelValue   = "controller"
-	controllerReadSecretsClusterRoleName = "kargo-controller-read-secrets"
+	controllerServiceAccountLabelKey   = "app.kubernetes.io/component"
+	controllerServiceAccountLabelValue = "controller"
 )
 
+// controllerClusterRole describes a ClusterRole that is bound to each
+// controller ServiceAccount in every Project namespace.
+type controllerClusterRole struct {
+	// name is the name of the ClusterRole.
+	name string
+	// roleBindingSuffix is appended to the name of the ServiceAccount to form
+	// the name of the RoleBinding that binds the ClusterRole to it.
+	roleBindingSuffix string
+}
+
+// controllerClusterRoles are the ClusterRoles that are bound to each
+// controller ServiceAccount in every Project namespace.
+var controllerClusterRoles = []controllerClusterRole{
+	{
+		name:              "kargo-controller-read-secrets",
+		roleBindingSuffix: "read-secrets",
+	},
+	{
+		// Permits the controller to impersonate ServiceAccounts in the
+		// Project namespace only, e.g. for the run-job promotion step.
+		name:              "kargo-controller-impersonate-service-accounts",
+		roleBindingSuffix: "impersonate-service-accounts",
+	},
+}
+
 type ReconcilerConfig struct {
 	ManageControllerRoleBindings bool   `envconfig:"MANAGE_CONTROLLER_ROLE_BINDINGS" default:"true"`
 	KargoNamespace               string `envconfig:"KARGO_NAMESPACE" default:"kargo"`
@@ -472,11 +496,6 @@ func (r *reconciler) ensureControllerPermissions(
 	ctx context.Context,
 	project *kargoapi.Project,
 ) error {
-	logger := logging.LoggerFromContext(ctx).WithValues(
-		"project", project.Name,
-		"namespace", project.Name,
-	)
-
 	// Get all ServiceAccounts labeled as controller ServiceAccounts
 	controllerSAs := &corev1.ServiceAccountList{}
 	if err := r.client.List(
@@ -489,7 +508,7 @@ func (r *reconciler) ensureControllerPermissions(
 		return fmt.Errorf("error listing controller ServiceAccounts: %w", err)
 	}
 
-	// Create/update a RoleBinding for each ServiceAccount
+	// Create/update the RoleBindings for each ServiceAccount
 	for _, controllerSA := range controllerSAs.Items {
 		sa := &controllerSA
 		if controllerutil.AddFinalizer(sa, kargoapi.FinalizerName) {
@@ -501,50 +520,70 @@ func (r *reconciler) ensureControllerPermissions(
 			}
 		}
 
-		roleBindingName := getRoleBindingName(sa.Name)
-		saLogger := logger.WithValues(
-			"serviceAccount", sa.Name,
-			"serviceAccount.namespace", sa.Namespace,
-			"roleBinding", roleBindingName,
-		)
+		for _, clusterRole := range controllerClusterRoles {
+			if err := r.ensureControllerRoleBinding(ctx, project, sa, clusterRole); err != nil {
+				return err
+			}
+		}
+	}
 
-		roleBinding := &rbacv1.RoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      roleBindingName,
-				Namespace: project.Name,
-			},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: rbacv1.GroupName,
-				Kind:     "ClusterRole",
-				Name:     controllerReadSecretsClusterRoleName,
-			},
-			Subjects: []rbacv1.Subject{
-				{
-					Kind:      "ServiceAccount",
-					Name:      sa.Name,
-					Namespace: sa.Namespace,
-				},
+	return nil
+}
+
+// ensureControllerRoleBinding creates or updates a RoleBinding in the
+// namespace of the provided Project that binds the provided ClusterRole to the
+// provided controller ServiceAccount.
+func (r *reconciler) ensureControllerRoleBinding(
+	ctx context.Context,
+	project *kargoapi.Project,
+	sa *corev1.ServiceAccount,
+	clusterRole controllerClusterRole,
+) error {
+	roleBindingName := getRoleBindingName(sa.Name, clusterRole.roleBindingSuffix)
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"project", project.Name,
+		"namespace", project.Name,
+		"serviceAccount", sa.Name,
+		"serviceAccount.namespace", sa.Namespace,
+		"roleBinding", roleBindingName,
+	)
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      roleBindingName,
+			Namespace: project.Name,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
 			},
-		}
+		},
+	}
 
-		if err := r.client.Create(ctx, roleBinding); err != nil {
-			if !kubeerr.IsAlreadyExists(err) {
-				return fmt.Errorf(
-					"error creating RoleBinding %q for ServiceAccount %q in Project namespace %q: %w",
-					roleBinding.Name, sa.Name, project.Name, err,
-				)
-			}
-			if err = r.client.Update(ctx, roleBinding); err != nil {
-				return fmt.Errorf(
-					"error updating existing RoleBinding %q in Project namespace %q: %w",
-					roleBinding.Name, project.Name, err,
-				)
-			}
-			saLogger.Debug("updated RoleBinding")
-			continue
+	if err := r.client.Create(ctx, roleBinding); err != nil {
+		if !kubeerr.IsAlreadyExists(err) {
+			return fmt.Errorf(
+				"error creating RoleBinding %q for ServiceAccount %q in Project namespace %q: %w",
+				roleBinding.Name, sa.Name, project.Name, err,
+			)
+		}
+		if err = r.client.Update(ctx, roleBinding); err != nil {
+			return fmt.Errorf(
+				"error updating existing RoleBinding %q in Project namespace %q: %w",
+				roleBinding.Name, project.Name, err,
+			)
 		}
-		saLogger.Debug("created RoleBinding")
+		logger.Debug("updated RoleBinding")
+		return nil
 	}
+	logger.Debug("created RoleBinding")
 
 	return nil
 }
@@ -838,6 +877,6 @@ func mustReconcileProject(project *kargoapi.Project) (string, bool) {
 	return "", true
 }
 
-func getRoleBindingName(serviceAccountName string) string {
-	return fmt.Sprintf("%s-read-secrets", serviceAccountName)
+func getRoleBindingName(serviceAccountName, suffix string) string {
+	return fmt.Sprintf("%s-%s", serviceAccountName, suffix)
 }
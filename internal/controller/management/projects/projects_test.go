@@ -838,7 +838,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSA.Name),
+						Name:      getRoleBindingName(testControllerSA.Name, "read-secrets"),
 						Namespace: testProject.Name,
 					},
 					rb,
@@ -850,7 +850,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					rbacv1.RoleRef{
 						APIGroup: rbacv1.GroupName,
 						Kind:     "ClusterRole",
-						Name:     controllerReadSecretsClusterRoleName,
+						Name:     "kargo-controller-read-secrets",
 					},
 					rb.RoleRef,
 				)
@@ -863,6 +863,24 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					},
 					rb.Subjects[0],
 				)
+				err = cl.Get(
+					context.Background(),
+					types.NamespacedName{
+						Name:      getRoleBindingName(testControllerSA.Name, "impersonate-service-accounts"),
+						Namespace: testProject.Name,
+					},
+					rb,
+				)
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					rbacv1.RoleRef{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "ClusterRole",
+						Name:     "kargo-controller-impersonate-service-accounts",
+					},
+					rb.RoleRef,
+				)
 			},
 		},
 		{
@@ -874,7 +892,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					&rbacv1.RoleBinding{
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace: testProject.Name,
-							Name:      getRoleBindingName(testControllerSA.Name),
+							Name:      getRoleBindingName(testControllerSA.Name, "read-secrets"),
 						},
 					},
 				).Build(),
@@ -884,7 +902,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSA.Name),
+						Name:      getRoleBindingName(testControllerSA.Name, "read-secrets"),
 						Namespace: testProject.Name,
 					},
 					rb,
@@ -896,7 +914,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					rbacv1.RoleRef{
 						APIGroup: rbacv1.GroupName,
 						Kind:     "ClusterRole",
-						Name:     controllerReadSecretsClusterRoleName,
+						Name:     "kargo-controller-read-secrets",
 					},
 					rb.RoleRef,
 				)
@@ -919,7 +937,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					testControllerSA,
 					&rbacv1.RoleBinding{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      getRoleBindingName(testControllerSA.Name),
+							Name:      getRoleBindingName(testControllerSA.Name, "read-secrets"),
 							Namespace: testProject.Name,
 						},
 					},
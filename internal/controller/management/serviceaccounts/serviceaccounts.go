@@ -27,7 +27,32 @@ const (
 	controllerServiceAccountLabelValue = "controller"
 )
 
-const controllerReadSecretsClusterRoleName = "kargo-controller-read-secrets"
+// controllerClusterRole describes a ClusterRole that is bound to each
+// controller ServiceAccount in every Project namespace.
+type controllerClusterRole struct {
+	// name is the name of the ClusterRole.
+	name string
+	// roleBindingSuffix is appended to the name of the ServiceAccount to form
+	// the name of the RoleBinding that binds the ClusterRole to it.
+	roleBindingSuffix string
+}
+
+// controllerClusterRoles are the ClusterRoles that are bound to each
+// controller ServiceAccount in every Project namespace. Besides reading
+// Secrets, the controller may impersonate ServiceAccounts in a Project's
+// namespace, e.g. for the run-job promotion step, which thereby does not
+// require the controller to be permitted to impersonate ServiceAccounts
+// cluster-wide.
+var controllerClusterRoles = []controllerClusterRole{
+	{
+		name:              "kargo-controller-read-secrets",
+		roleBindingSuffix: "read-secrets",
+	},
+	{
+		name:              "kargo-controller-impersonate-service-accounts",
+		roleBindingSuffix: "impersonate-service-accounts",
+	},
+}
 
 type ReconcilerConfig struct {
 	KargoNamespace          string `envconfig:"KARGO_NAMESPACE" default:"kargo"`
@@ -109,7 +134,8 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if (sa.DeletionTimestamp != nil || !hasControllerLabel(sa)) &&
 		controllerutil.ContainsFinalizer(sa, kargoapi.FinalizerName) {
 		// Ensure non-existence of RoleBindings that grant this controller
-		// ServiceAccount access to read Secrets in all Project namespaces.
+		// ServiceAccount access to read Secrets and to impersonate
+		// ServiceAccounts in all Project namespaces.
 		if err := r.removeControllerPermissions(ctx, req.NamespacedName); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -128,7 +154,8 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	if sa.DeletionTimestamp == nil && hasControllerLabel(sa) {
 		// Ensure the existence of RoleBindings that grant this controller
-		// ServiceAccount access to read Secrets in all Project namespaces.
+		// ServiceAccount access to read Secrets and to impersonate
+		// ServiceAccounts in all Project namespaces.
 		if controllerutil.AddFinalizer(sa, kargoapi.FinalizerName) {
 			if err := r.client.Update(ctx, sa); err != nil {
 				return ctrl.Result{}, fmt.Errorf(
@@ -148,15 +175,11 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 }
 
 // ensureControllerPermissions ensure the existence of RoleBindings that grant
-// this referenced controller ServiceAccount access to read Secrets in all
-// Project namespaces.
+// this referenced controller ServiceAccount access to read Secrets and to
+// impersonate ServiceAccounts in all Project namespaces.
 func (r *reconciler) ensureControllerPermissions(ctx context.Context, sa types.NamespacedName) error {
-	roleBindingName := getRoleBindingName(sa.Name)
-
-	logger := logging.LoggerFromContext(ctx).WithValues(
-		"roleBinding", roleBindingName,
-	)
-	logger.Debug("ensuring necessary RoleBinding in all Project namespaces")
+	logger := logging.LoggerFromContext(ctx)
+	logger.Debug("ensuring necessary RoleBindings in all Project namespaces")
 
 	projectList := &kargoapi.ProjectList{}
 	if err := r.client.List(ctx, projectList); err != nil {
@@ -164,43 +187,49 @@ func (r *reconciler) ensureControllerPermissions(ctx context.Context, sa types.N
 	}
 
 	// Loop through each Project to create or update the corresponding
-	// RoleBinding.
+	// RoleBindings.
 	for _, project := range projectList.Items {
-		projectLogger := logger.WithValues("project.namespace", project.Name)
-		roleBinding := &rbacv1.RoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      roleBindingName,
-				Namespace: project.Name,
-			},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: rbacv1.GroupName,
-				Kind:     "ClusterRole",
-				Name:     controllerReadSecretsClusterRoleName,
-			},
-			Subjects: []rbacv1.Subject{
-				{
-					Kind:      "ServiceAccount",
-					Name:      sa.Name,
-					Namespace: r.cfg.KargoNamespace,
+		for _, clusterRole := range controllerClusterRoles {
+			roleBindingName := getRoleBindingName(sa.Name, clusterRole.roleBindingSuffix)
+			projectLogger := logger.WithValues(
+				"roleBinding", roleBindingName,
+				"project.namespace", project.Name,
+			)
+			roleBinding := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      roleBindingName,
+					Namespace: project.Name,
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     clusterRole.name,
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						Kind:      "ServiceAccount",
+						Name:      sa.Name,
+						Namespace: r.cfg.KargoNamespace,
+					},
 				},
-			},
-		}
-		if err := r.client.Create(ctx, roleBinding); err != nil {
-			if !kubeerr.IsAlreadyExists(err) {
-				return fmt.Errorf(
-					"error creating RoleBinding %q for ServiceAccount %q in Project namespace %q: %w",
-					roleBinding.Name, sa.Name, project.Name, err,
-				)
 			}
-			if err = r.client.Update(ctx, roleBinding); err != nil {
-				return fmt.Errorf(
-					"error updating existing RoleBinding %q in Project namespace %q: %w",
-					roleBinding.Name, project.Name, err,
-				)
+			if err := r.client.Create(ctx, roleBinding); err != nil {
+				if !kubeerr.IsAlreadyExists(err) {
+					return fmt.Errorf(
+						"error creating RoleBinding %q for ServiceAccount %q in Project namespace %q: %w",
+						roleBinding.Name, sa.Name, project.Name, err,
+					)
+				}
+				if err = r.client.Update(ctx, roleBinding); err != nil {
+					return fmt.Errorf(
+						"error updating existing RoleBinding %q in Project namespace %q: %w",
+						roleBinding.Name, project.Name, err,
+					)
+				}
+				projectLogger.Debug("updated existing RoleBinding")
+			} else {
+				projectLogger.Debug("created RoleBinding")
 			}
-			projectLogger.Debug("updated existing RoleBinding")
-		} else {
-			projectLogger.Debug("created RoleBinding")
 		}
 	}
 	logger.Debug("necessary RoleBindings exist in all Project namespaces")
@@ -209,14 +238,10 @@ func (r *reconciler) ensureControllerPermissions(ctx context.Context, sa types.N
 
 // removeControllerPermissions ensure the non-existence of RoleBindings that
 // would grant the referenced controller ServiceAccount access to read Secrets
-// in all Project namespaces.
+// or to impersonate ServiceAccounts in all Project namespaces.
 func (r *reconciler) removeControllerPermissions(ctx context.Context, sa types.NamespacedName) error {
-	roleBindingName := getRoleBindingName(sa.Name)
-
-	logger := logging.LoggerFromContext(ctx).WithValues(
-		"roleBinding", roleBindingName,
-	)
-	logger.Debug("ensuring non-existence of necessary RoleBinding in all Project namespaces")
+	logger := logging.LoggerFromContext(ctx)
+	logger.Debug("ensuring non-existence of necessary RoleBindings in all Project namespaces")
 
 	projectList := &kargoapi.ProjectList{}
 	if err := r.client.List(ctx, projectList); err != nil {
@@ -224,26 +249,32 @@ func (r *reconciler) removeControllerPermissions(ctx context.Context, sa types.N
 	}
 
 	for _, project := range projectList.Items {
-		projectLogger := logger.WithValues("project.namespace", project.Name)
-		if err := r.client.Delete(
-			ctx,
-			&rbacv1.RoleBinding{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      roleBindingName,
-					Namespace: project.Name,
+		for _, clusterRole := range controllerClusterRoles {
+			roleBindingName := getRoleBindingName(sa.Name, clusterRole.roleBindingSuffix)
+			projectLogger := logger.WithValues(
+				"roleBinding", roleBindingName,
+				"project.namespace", project.Name,
+			)
+			if err := r.client.Delete(
+				ctx,
+				&rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      roleBindingName,
+						Namespace: project.Name,
+					},
 				},
-			},
-		); err != nil {
-			if kubeerr.IsNotFound(err) {
-				projectLogger.Debug("RoleBinding not found")
-				continue
+			); err != nil {
+				if kubeerr.IsNotFound(err) {
+					projectLogger.Debug("RoleBinding not found")
+					continue
+				}
+				return fmt.Errorf(
+					"error deleting RoleBinding %q in Project namespace %q: %w",
+					roleBindingName, project.Name, err,
+				)
 			}
-			return fmt.Errorf(
-				"error deleting RoleBinding %q in Project namespace %q: %w",
-				roleBindingName, project.Name, err,
-			)
+			projectLogger.Debug("deleted RoleBinding")
 		}
-		projectLogger.Debug("deleted RoleBinding")
 	}
 	logger.Debug("Completed deletion of RoleBindings for all Projects")
 	return nil
@@ -253,6 +284,6 @@ func hasControllerLabel(sa *corev1.ServiceAccount) bool {
 	return sa.GetLabels()[controllerServiceAccountLabelKey] == controllerServiceAccountLabelValue
 }
 
-func getRoleBindingName(serviceAccountName string) string {
-	return fmt.Sprintf("%s-read-secrets", serviceAccountName)
+func getRoleBindingName(serviceAccountName, suffix string) string {
+	return fmt.Sprintf("%s-%s", serviceAccountName, suffix)
 }
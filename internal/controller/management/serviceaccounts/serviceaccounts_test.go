@@ -110,7 +110,7 @@ func TestReconcile(t *testing.T) {
 				&rbacv1.RoleBinding{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: testProjectName,
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 					},
 				},
 			).Build(),
@@ -122,7 +122,7 @@ func TestReconcile(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProjectName,
 					},
 					rb,
@@ -162,7 +162,7 @@ func TestReconcile(t *testing.T) {
 				&rbacv1.RoleBinding{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: testProjectName,
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 					},
 				},
 			).Build(),
@@ -174,7 +174,7 @@ func TestReconcile(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProjectName,
 					},
 					rb,
@@ -294,7 +294,7 @@ func TestReconcile(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProjectName,
 					},
 					rb,
@@ -393,7 +393,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProject.Name,
 					},
 					rb,
@@ -405,7 +405,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					rbacv1.RoleRef{
 						APIGroup: rbacv1.GroupName,
 						Kind:     "ClusterRole",
-						Name:     controllerReadSecretsClusterRoleName,
+						Name:     "kargo-controller-read-secrets",
 					},
 					rb.RoleRef,
 				)
@@ -418,6 +418,24 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					},
 					rb.Subjects[0],
 				)
+				err = cl.Get(
+					context.Background(),
+					types.NamespacedName{
+						Name:      getRoleBindingName(testControllerSARef.Name, "impersonate-service-accounts"),
+						Namespace: testProject.Name,
+					},
+					rb,
+				)
+				require.NoError(t, err)
+				require.Equal(
+					t,
+					rbacv1.RoleRef{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "ClusterRole",
+						Name:     "kargo-controller-impersonate-service-accounts",
+					},
+					rb.RoleRef,
+				)
 			},
 		},
 		{
@@ -429,7 +447,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					&rbacv1.RoleBinding{
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace: testProject.Name,
-							Name:      getRoleBindingName(testControllerSARef.Name),
+							Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						},
 					},
 				).Build(),
@@ -439,7 +457,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProject.Name,
 					},
 					rb,
@@ -451,7 +469,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					rbacv1.RoleRef{
 						APIGroup: rbacv1.GroupName,
 						Kind:     "ClusterRole",
-						Name:     controllerReadSecretsClusterRoleName,
+						Name:     "kargo-controller-read-secrets",
 					},
 					rb.RoleRef,
 				)
@@ -475,7 +493,7 @@ func TestEnsureControllerPermissions(t *testing.T) {
 					&rbacv1.RoleBinding{
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace: testProject.Name,
-							Name:      getRoleBindingName(testControllerSARef.Name),
+							Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						},
 					},
 				).
@@ -577,7 +595,7 @@ func TestRemoveControllerPermissions(t *testing.T) {
 					&rbacv1.RoleBinding{
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace: testProject.Name,
-							Name:      getRoleBindingName(testControllerSARef.Name),
+							Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						},
 					},
 				).
@@ -588,7 +606,7 @@ func TestRemoveControllerPermissions(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProject.Name,
 					},
 					rb,
@@ -609,7 +627,7 @@ func TestRemoveControllerPermissions(t *testing.T) {
 				err = cl.Get(
 					context.Background(),
 					types.NamespacedName{
-						Name:      getRoleBindingName(testControllerSARef.Name),
+						Name:      getRoleBindingName(testControllerSARef.Name, "read-secrets"),
 						Namespace: testProject.Name,
 					},
 					rb,
@@ -0,0 +1,107 @@
+package promotetrigger
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+var (
+	// repoRegex matches image repositories, optionally prefixed by a registry
+	// host and port, in the format of the OCI distribution specification.
+	repoRegex = regexp.MustCompile(
+		`^[a-zA-Z0-9]+(?:[.-][a-zA-Z0-9]+)*(?::[0-9]+)?` +
+			`(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`,
+	)
+	// tagRegex matches image tags.
+	tagRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+	// digestRegex matches image digests.
+	digestRegex = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+)
+
+// imageRef is an entry of the value of the promote annotation. At least one of
+// its tag and digest is not empty.
+type imageRef struct {
+	repoURL string
+	tag     string
+	digest  string
+}
+
+// String returns the image reference in the format it was specified in.
+func (i imageRef) String() string {
+	s := i.repoURL
+	if i.tag != "" {
+		s += ":" + i.tag
+	}
+	if i.digest != "" {
+		s += "@" + i.digest
+	}
+	return s
+}
+
+// matches returns true if the provided image of a piece of Freight is the one
+// referenced.
+func (i imageRef) matches(img kargoapi.Image) bool {
+	return img.RepoURL == i.repoURL &&
+		(i.tag == "" || img.Tag == i.tag) &&
+		(i.digest == "" || img.Digest == i.digest)
+}
+
+// parsePromoteAnnotation parses the value of the promote annotation, which is
+// a comma-separated list of images in the format of "<repo>:<tag>",
+// "<repo>@<digest>" or "<repo>:<tag>@<digest>". Every entry must be valid and
+// no repository may be listed more than once.
+func parsePromoteAnnotation(value string) ([]imageRef, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, errors.New("no images are listed")
+	}
+	entries := strings.Split(value, ",")
+	refs := make([]imageRef, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		ref, err := parseImageRef(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[ref.repoURL]; ok {
+			return nil, fmt.Errorf("image %q is listed more than once", ref.repoURL)
+		}
+		seen[ref.repoURL] = struct{}{}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// parseImageRef parses an entry of the value of the promote annotation.
+func parseImageRef(entry string) (imageRef, error) {
+	if entry == "" {
+		return imageRef{}, errors.New("empty entries are not permitted")
+	}
+	var ref imageRef
+	repoAndTag, digest, hasDigest := strings.Cut(entry, "@")
+	if hasDigest {
+		if !digestRegex.MatchString(digest) {
+			return imageRef{}, fmt.Errorf("%q has an invalid digest", entry)
+		}
+		ref.digest = digest
+	}
+	ref.repoURL = repoAndTag
+	// A colon before the last slash separates the registry host from its port,
+	// rather than the repository from the tag.
+	if i := strings.LastIndex(repoAndTag, ":"); i > strings.LastIndex(repoAndTag, "/") {
+		ref.repoURL, ref.tag = repoAndTag[:i], repoAndTag[i+1:]
+		if !tagRegex.MatchString(ref.tag) {
+			return imageRef{}, fmt.Errorf("%q has an invalid tag", entry)
+		}
+	}
+	if !repoRegex.MatchString(ref.repoURL) {
+		return imageRef{}, fmt.Errorf("%q has an invalid repository", entry)
+	}
+	if ref.tag == "" && ref.digest == "" {
+		return imageRef{}, fmt.Errorf("%q specifies neither a tag nor a digest", entry)
+	}
+	return ref, nil
+}
@@ -0,0 +1,73 @@
+package promotetrigger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePromoteAnnotation(t *testing.T) {
+	const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testCases := []struct {
+		name   string
+		value  string
+		refs   []imageRef
+		errMsg string
+	}{
+		{
+			name:  "tags and digests",
+			value: "ghcr.io/example/app:1.4.2, localhost:5000/tool@" + testDigest + ",nginx:1.27@" + testDigest,
+			refs: []imageRef{
+				{repoURL: "ghcr.io/example/app", tag: "1.4.2"},
+				{repoURL: "localhost:5000/tool", digest: testDigest},
+				{repoURL: "nginx", tag: "1.27", digest: testDigest},
+			},
+		},
+		{
+			name:   "empty",
+			value:  " ",
+			errMsg: "no images are listed",
+		},
+		{
+			name:   "empty entry",
+			value:  "nginx:1.27,,",
+			errMsg: "empty entries are not permitted",
+		},
+		{
+			name:   "neither tag nor digest",
+			value:  "localhost:5000/tool",
+			errMsg: "specifies neither a tag nor a digest",
+		},
+		{
+			name:   "invalid tag",
+			value:  "nginx:^1.27",
+			errMsg: "has an invalid tag",
+		},
+		{
+			name:   "invalid digest",
+			value:  "nginx@sha256:abc",
+			errMsg: "has an invalid digest",
+		},
+		{
+			name:   "invalid repository",
+			value:  "example/App:1.0.0",
+			errMsg: "has an invalid repository",
+		},
+		{
+			name:   "repository listed twice",
+			value:  "nginx:1.27,nginx:1.28",
+			errMsg: `image "nginx" is listed more than once`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			refs, err := parsePromoteAnnotation(testCase.value)
+			if testCase.errMsg != "" {
+				require.ErrorContains(t, err, testCase.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.refs, refs)
+		})
+	}
+}
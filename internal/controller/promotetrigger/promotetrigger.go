@@ -0,0 +1,391 @@
+package promotetrigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	"github.com/akuity/kargo/internal/controller"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	kargoEvent "github.com/akuity/kargo/internal/event"
+	"github.com/akuity/kargo/internal/events"
+	libEvent "github.com/akuity/kargo/internal/kubernetes/event"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+const (
+	// reasonPromotionCreated is the reason of the Event that is recorded on an
+	// Argo CD Application when a Promotion was created in response to its
+	// promote annotation.
+	reasonPromotionCreated = "KargoPromotionCreated"
+	// reasonPromotionRejected is the reason of the Event that is recorded on an
+	// Argo CD Application when no Promotion was created in response to its
+	// promote annotation, because the annotation is not valid or could not be
+	// satisfied.
+	reasonPromotionRejected = "KargoPromotionRejected"
+)
+
+// rejectionError is returned when the promote annotation of an Argo CD
+// Application can not be acted upon until it is changed.
+type rejectionError struct {
+	err error
+}
+
+func (e *rejectionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rejectionError) Unwrap() error {
+	return e.err
+}
+
+// ReconcilerConfig is configuration for the reconciler that creates
+// Promotions in response to the promote annotation of Argo CD Applications.
+type ReconcilerConfig struct {
+	// Enabled specifies whether the reconciler is enabled at all. It should
+	// not be enabled where the right to edit Argo CD Applications is granted
+	// more broadly than the right to promote to the Stages that manage them.
+	Enabled                 bool `envconfig:"ARGOCD_PROMOTE_ANNOTATION_ENABLED" default:"false"`
+	MaxConcurrentReconciles int  `envconfig:"MAX_CONCURRENT_ARGOCD_PROMOTE_ANNOTATION_RECONCILES" default:"1"`
+}
+
+func (c ReconcilerConfig) Name() string {
+	return "argocd-promote-annotation-controller"
+}
+
+// ReconcilerConfigFromEnv returns a ReconcilerConfig populated from environment
+// variables.
+func ReconcilerConfigFromEnv() ReconcilerConfig {
+	var cfg ReconcilerConfig
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+// reconciler creates Promotions in response to the promote annotation of Argo
+// CD Applications. The Stage that is promoted is the one that is authorized to
+// manage the Application, and the Freight that is promoted to it is the most
+// recent Freight available to it that contains all of the images listed by the
+// annotation. Once a Promotion was created, the annotation is removed and the
+// Promotion is recorded in another annotation. Requests that can not be acted
+// upon are recorded as Events on the Application and removed as well.
+type reconciler struct {
+	cfg              ReconcilerConfig
+	argocdClient     client.Client
+	kargoClient      client.Client
+	recorder         record.EventRecorder
+	kargoRecorder    record.EventRecorder
+	promotionCreator *events.PromotionCreator
+}
+
+// SetupReconcilerWithManager initializes a reconciler for Argo CD Applications
+// carrying the promote annotation and registers it with the provided Manager.
+// Nothing is registered if the reconciler is disabled by the provided
+// ReconcilerConfig or if Argo CD integration is disabled. Only Applications of
+// the default Argo CD context are watched.
+func SetupReconcilerWithManager(
+	ctx context.Context,
+	kargoMgr manager.Manager,
+	argoCDContexts *libargocd.Contexts,
+	cfg ReconcilerConfig,
+) error {
+	logger := logging.LoggerFromContext(ctx)
+	if !cfg.Enabled {
+		return nil
+	}
+	argoCDCtx, err := argoCDContexts.Get("")
+	if err != nil {
+		return err
+	}
+	if argoCDCtx == nil || argoCDCtx.Cache == nil {
+		logger.Info(
+			"Argo CD promote annotation was enabled, but Argo CD integration is " +
+				"disabled. Proceeding without Argo CD promote annotation.",
+		)
+		return nil
+	}
+
+	r := &reconciler{
+		cfg:          cfg,
+		argocdClient: argoCDCtx.Client,
+		kargoClient:  kargoMgr.GetClient(),
+		recorder: libEvent.NewRecorder(
+			ctx, argoCDCtx.Client.Scheme(), argoCDCtx.Client, cfg.Name(),
+		),
+		kargoRecorder: libEvent.NewRecorder(
+			ctx, kargoMgr.GetScheme(), kargoMgr.GetClient(), cfg.Name(),
+		),
+		promotionCreator: events.NewPromotionCreator(
+			kargoMgr.GetClient(),
+			events.PromotionCreatorOptions{
+				Journal: events.NewJournal(kargoMgr.GetClient(), events.DefaultJournalSize),
+			},
+		),
+	}
+
+	if err = ctrl.NewControllerManagedBy(kargoMgr).
+		Named("argocd_promote_annotation").
+		WatchesRawSource(
+			source.Kind(
+				argoCDCtx.Cache,
+				&argocd.Application{},
+				&handler.TypedEnqueueRequestForObject[*argocd.Application]{},
+				predicate.TypedAnnotationChangedPredicate[*argocd.Application]{},
+				predicate.NewTypedPredicateFuncs(func(app *argocd.Application) bool {
+					_, ok := app.Annotations[kargoapi.AnnotationKeyPromote]
+					return ok
+				}),
+			),
+		).
+		WithOptions(controller.CommonOptions(cfg.MaxConcurrentReconciles)).
+		Complete(r); err != nil {
+		return fmt.Errorf("error building Argo CD promote annotation controller: %w", err)
+	}
+
+	logger.Info(
+		"Initialized Argo CD promote annotation reconciler",
+		"maxConcurrentReconciles", cfg.MaxConcurrentReconciles,
+	)
+
+	return nil
+}
+
+// Reconcile creates a Promotion in response to the promote annotation of an
+// Argo CD Application.
+func (r *reconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"namespace", req.NamespacedName.Namespace,
+		"app", req.NamespacedName.Name,
+	)
+	ctx = logging.ContextWithLogger(ctx, logger)
+
+	app := &argocd.Application{}
+	if err := r.argocdClient.Get(ctx, req.NamespacedName, app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	value, ok := app.Annotations[kargoapi.AnnotationKeyPromote]
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	promo, freight, err := r.promote(ctx, app, value)
+	if err != nil {
+		var rejectionErr *rejectionError
+		if !errors.As(err, &rejectionErr) {
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(
+			app, corev1.EventTypeWarning, reasonPromotionRejected,
+			"No Kargo Promotion was created for annotation %q: %s",
+			kargoapi.AnnotationKeyPromote, err,
+		)
+		// The annotation is removed, so that it can be set again once the
+		// problem was addressed.
+		return ctrl.Result{}, r.completeRequest(ctx, app, "")
+	}
+	if promo == nil {
+		logger.Debug("promotion request was dropped")
+		return ctrl.Result{}, r.completeRequest(ctx, app, "")
+	}
+
+	logger.Info(
+		"created Promotion for promote annotation",
+		"project", promo.Namespace,
+		"promotion", promo.Name,
+	)
+	r.kargoRecorder.AnnotatedEventf(
+		promo,
+		kargoEvent.NewPromotionAnnotations(
+			ctx,
+			kargoapi.FormatEventControllerActor(r.cfg.Name()),
+			promo,
+			freight,
+		),
+		corev1.EventTypeNormal,
+		kargoapi.EventReasonPromotionCreated,
+		"Promoted Freight %q to Stage %q as requested by Argo CD Application %q",
+		promo.Spec.Freight, promo.Spec.Stage, app.Namespace+"/"+app.Name,
+	)
+	r.recorder.Eventf(
+		app, corev1.EventTypeNormal, reasonPromotionCreated,
+		"Created Kargo Promotion %q in Project %q to promote Freight %q to Stage %q",
+		promo.Name, promo.Namespace, promo.Spec.Freight, promo.Spec.Stage,
+	)
+	return ctrl.Result{}, r.completeRequest(ctx, app, promo.Namespace+":"+promo.Name)
+}
+
+// promote creates a Promotion for the provided value of the promote annotation
+// of the provided Argo CD Application and returns it, along with the Freight
+// it promotes. If a hook of the PromotionCreator dropped the request, nil is
+// returned for both. A *rejectionError is returned if the request can not be
+// acted upon.
+func (r *reconciler) promote(
+	ctx context.Context,
+	app *argocd.Application,
+	value string,
+) (*kargoapi.Promotion, *kargoapi.Freight, error) {
+	refs, err := parsePromoteAnnotation(value)
+	if err != nil {
+		return nil, nil, &rejectionError{err: err}
+	}
+	project, stageName, ok := authorizedStage(app)
+	if !ok {
+		return nil, nil, &rejectionError{err: fmt.Errorf(
+			"annotation %q must identify the Kargo Stage that is authorized to "+
+				"manage the Application in the format of \"<project>:<stage>\"",
+			kargoapi.AnnotationKeyAuthorizedStage,
+		)}
+	}
+
+	stage, err := kargoapi.GetStage(
+		ctx,
+		r.kargoClient,
+		types.NamespacedName{Namespace: project, Name: stageName},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stage == nil {
+		return nil, nil, &rejectionError{err: fmt.Errorf(
+			"Stage %q does not exist in Project %q", stageName, project,
+		)}
+	}
+
+	freight, err := r.findFreight(ctx, stage, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if freight == nil {
+		imgs := make([]string, len(refs))
+		for i, ref := range refs {
+			imgs[i] = ref.String()
+		}
+		return nil, nil, &rejectionError{err: fmt.Errorf(
+			"no Freight available to Stage %q in Project %q contains %s",
+			stageName, project, strings.Join(imgs, ", "),
+		)}
+	}
+
+	// The request is identified by the Application, the Promotion last created
+	// for it, and the requested images, so that a Promotion is not created
+	// twice if the annotation could not be removed, while the same images may
+	// be requested again later.
+	promo, err := r.promotionCreator.Create(ctx, stage, &events.PromotionRequested{
+		ID: fmt.Sprintf(
+			"%s/%s/%s/%s",
+			events.SourceApplication,
+			app.UID,
+			app.Annotations[kargoapi.AnnotationKeyLastPromotion],
+			value,
+		),
+		Source:  events.SourceApplication,
+		Project: project,
+		Stage:   stageName,
+		Freight: freight.Name,
+	})
+	if err != nil {
+		if errors.Is(err, events.ErrInvalidRequest) {
+			return nil, nil, &rejectionError{err: err}
+		}
+		return nil, nil, fmt.Errorf(
+			"error creating Promotion for Freight %q in namespace %q: %w",
+			freight.Name, project, err,
+		)
+	}
+	if promo == nil {
+		return nil, nil, nil
+	}
+	return promo, freight, nil
+}
+
+// findFreight returns the most recently created Freight that is available to
+// the provided Stage and contains all of the referenced images, or nil if
+// there is none.
+func (r *reconciler) findFreight(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	refs []imageRef,
+) (*kargoapi.Freight, error) {
+	freight := &kargoapi.FreightList{}
+	if err := r.kargoClient.List(ctx, freight, client.InNamespace(stage.Namespace)); err != nil {
+		return nil, fmt.Errorf(
+			"error listing Freight in namespace %q: %w", stage.Namespace, err,
+		)
+	}
+	var found *kargoapi.Freight
+	for i := range freight.Items {
+		f := &freight.Items[i]
+		if !stage.IsFreightAvailable(f) || !containsImages(f, refs) {
+			continue
+		}
+		if found == nil || f.CreationTimestamp.After(found.CreationTimestamp.Time) {
+			found = f
+		}
+	}
+	return found, nil
+}
+
+// containsImages returns true if the provided Freight contains all of the
+// referenced images.
+func containsImages(freight *kargoapi.Freight, refs []imageRef) bool {
+	for _, ref := range refs {
+		var found bool
+		for _, img := range freight.Images {
+			if ref.matches(img) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// completeRequest removes the promote annotation from the provided Argo CD
+// Application and, if the provided reference to a Promotion is not empty,
+// records that Promotion in the last-promotion annotation.
+func (r *reconciler) completeRequest(
+	ctx context.Context,
+	app *argocd.Application,
+	promoRef string,
+) error {
+	patch := client.MergeFrom(app.DeepCopy())
+	delete(app.Annotations, kargoapi.AnnotationKeyPromote)
+	if promoRef != "" {
+		app.Annotations[kargoapi.AnnotationKeyLastPromotion] = promoRef
+	}
+	if err := r.argocdClient.Patch(ctx, app, patch); err != nil {
+		return fmt.Errorf("error patching annotations of Argo CD Application: %w", err)
+	}
+	return nil
+}
+
+// authorizedStage returns the Project and name of the Stage that is authorized
+// to manage the provided Argo CD Application, along with true, or false if no
+// single Stage is.
+func authorizedStage(app *argocd.Application) (string, string, bool) {
+	project, stage, ok := strings.Cut(app.Annotations[kargoapi.AnnotationKeyAuthorizedStage], ":")
+	if !ok || project == "" || stage == "" ||
+		strings.Contains(project, "*") || strings.Contains(stage, "*") {
+		return "", "", false
+	}
+	return project, stage, true
+}
@@ -0,0 +1,249 @@
+package promotetrigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/events"
+	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
+)
+
+func TestReconciler_Reconcile(t *testing.T) {
+	argocdScheme := runtime.NewScheme()
+	require.NoError(t, argocd.AddToScheme(argocdScheme))
+	kargoScheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(kargoScheme))
+	require.NoError(t, corev1.AddToScheme(kargoScheme))
+
+	newApp := func(annotations map[string]string) *argocd.Application {
+		return &argocd.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "argocd",
+				Name:        "guestbook",
+				UID:         "fake-uid",
+				Annotations: annotations,
+			},
+		}
+	}
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: "argocd", Name: "guestbook"},
+	}
+	origin := kargoapi.FreightOrigin{Kind: kargoapi.FreightOriginKindWarehouse, Name: "fake-warehouse"}
+	stage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "prod"},
+		Spec: kargoapi.StageSpec{
+			RequestedFreight: []kargoapi.FreightRequest{{
+				Origin:  origin,
+				Sources: kargoapi.FreightSources{Direct: true},
+			}},
+			PromotionTemplate: &kargoapi.PromotionTemplate{
+				Spec: kargoapi.PromotionTemplateSpec{
+					Steps: []kargoapi.PromotionStep{{Uses: "fake-step"}},
+				},
+			},
+		},
+	}
+	newFreight := func(name string, created time.Time, images ...kargoapi.Image) *kargoapi.Freight {
+		return &kargoapi.Freight{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "demo",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Origin: origin,
+			Images: images,
+		}
+	}
+	now := time.Now()
+	objects := []client.Object{
+		stage,
+		newFreight(
+			"older", now.Add(-2*time.Hour),
+			kargoapi.Image{RepoURL: "ghcr.io/example/app", Tag: "1.4.2"},
+			kargoapi.Image{RepoURL: "ghcr.io/example/tool", Tag: "0.1.0"},
+		),
+		newFreight(
+			"newer", now.Add(-time.Hour),
+			kargoapi.Image{RepoURL: "ghcr.io/example/app", Tag: "1.4.2"},
+			kargoapi.Image{RepoURL: "ghcr.io/example/tool", Tag: "0.2.0"},
+		),
+	}
+
+	testCases := []struct {
+		name       string
+		app        *argocd.Application
+		assertions func(*testing.T, *argocd.Application, []kargoapi.Promotion, []fakeevent.Event, error)
+	}{
+		{
+			name: "no promote annotation",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+			}),
+			assertions: func(
+				t *testing.T,
+				_ *argocd.Application,
+				promos []kargoapi.Promotion,
+				recorded []fakeevent.Event,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Empty(t, promos)
+				require.Empty(t, recorded)
+			},
+		},
+		{
+			name: "invalid annotation",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				kargoapi.AnnotationKeyPromote:         "ghcr.io/example/app",
+			}),
+			assertions: func(
+				t *testing.T,
+				app *argocd.Application,
+				promos []kargoapi.Promotion,
+				recorded []fakeevent.Event,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Empty(t, promos)
+				require.Len(t, recorded, 1)
+				require.Equal(t, corev1.EventTypeWarning, recorded[0].EventType)
+				require.Equal(t, reasonPromotionRejected, recorded[0].Reason)
+				require.Contains(t, recorded[0].Message, "neither a tag nor a digest")
+				require.NotContains(t, app.Annotations, kargoapi.AnnotationKeyPromote)
+				require.NotContains(t, app.Annotations, kargoapi.AnnotationKeyLastPromotion)
+			},
+		},
+		{
+			name: "no authorized Stage",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyPromote: "ghcr.io/example/app:1.4.2",
+			}),
+			assertions: func(
+				t *testing.T,
+				app *argocd.Application,
+				promos []kargoapi.Promotion,
+				recorded []fakeevent.Event,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Empty(t, promos)
+				require.Len(t, recorded, 1)
+				require.Contains(t, recorded[0].Message, kargoapi.AnnotationKeyAuthorizedStage)
+				require.NotContains(t, app.Annotations, kargoapi.AnnotationKeyPromote)
+			},
+		},
+		{
+			name: "no matching Freight",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				kargoapi.AnnotationKeyPromote:         "ghcr.io/example/app:1.5.0",
+			}),
+			assertions: func(
+				t *testing.T,
+				_ *argocd.Application,
+				promos []kargoapi.Promotion,
+				recorded []fakeevent.Event,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Empty(t, promos)
+				require.Len(t, recorded, 1)
+				require.Contains(t, recorded[0].Message, "contains ghcr.io/example/app:1.5.0")
+			},
+		},
+		{
+			name: "most recent matching Freight is promoted",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				kargoapi.AnnotationKeyPromote:         "ghcr.io/example/app:1.4.2",
+			}),
+			assertions: func(
+				t *testing.T,
+				app *argocd.Application,
+				promos []kargoapi.Promotion,
+				recorded []fakeevent.Event,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Len(t, promos, 1)
+				require.Equal(t, "prod", promos[0].Spec.Stage)
+				require.Equal(t, "newer", promos[0].Spec.Freight)
+				require.Equal(
+					t,
+					string(events.SourceApplication),
+					promos[0].Labels[kargoapi.PromotionSourceLabelKey],
+				)
+				require.Len(t, recorded, 1)
+				require.Equal(t, reasonPromotionCreated, recorded[0].Reason)
+				require.NotContains(t, app.Annotations, kargoapi.AnnotationKeyPromote)
+				require.Equal(
+					t,
+					"demo:"+promos[0].Name,
+					app.Annotations[kargoapi.AnnotationKeyLastPromotion],
+				)
+			},
+		},
+		{
+			name: "all images must match",
+			app: newApp(map[string]string{
+				kargoapi.AnnotationKeyAuthorizedStage: "demo:prod",
+				kargoapi.AnnotationKeyPromote:         "ghcr.io/example/app:1.4.2,ghcr.io/example/tool:0.1.0",
+			}),
+			assertions: func(
+				t *testing.T,
+				_ *argocd.Application,
+				promos []kargoapi.Promotion,
+				_ []fakeevent.Event,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Len(t, promos, 1)
+				require.Equal(t, "older", promos[0].Spec.Freight)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			argocdClient := fake.NewClientBuilder().
+				WithScheme(argocdScheme).
+				WithObjects(testCase.app).
+				Build()
+			kargoClient := fake.NewClientBuilder().
+				WithScheme(kargoScheme).
+				WithObjects(objects...).
+				Build()
+			recorder := fakeevent.NewEventRecorder(10)
+			r := &reconciler{
+				argocdClient:     argocdClient,
+				kargoClient:      kargoClient,
+				recorder:         recorder,
+				kargoRecorder:    fakeevent.NewEventRecorder(10),
+				promotionCreator: events.NewPromotionCreator(kargoClient, events.PromotionCreatorOptions{}),
+			}
+			_, err := r.Reconcile(context.Background(), req)
+			close(recorder.Events)
+			var recorded []fakeevent.Event
+			for event := range recorder.Events {
+				recorded = append(recorded, event)
+			}
+			app := &argocd.Application{}
+			require.NoError(t, argocdClient.Get(context.Background(), req.NamespacedName, app))
+			promos := &kargoapi.PromotionList{}
+			require.NoError(t, kargoClient.List(context.Background(), promos))
+			testCase.assertions(t, app, promos.Items, recorded, err)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package promotions
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// imageDowngrade describes an image that a Promotion would move back to an
+// older version.
+type imageDowngrade struct {
+	repoURL string
+	current string
+	target  string
+}
+
+func (d imageDowngrade) String() string {
+	return fmt.Sprintf("image %q would be downgraded from %s to %s", d.repoURL, d.current, d.target)
+}
+
+// findImageDowngrade returns the first image of the provided Freight that is
+// older than the version of the same image that is currently running in the
+// provided Stage. If no image would be downgraded, nil is returned.
+//
+// The images currently running in the Stage are taken from its status or, if
+// it does not list any, from the current entry of its FreightHistory. Images
+// the Stage does not know about yet, e.g. on its first Promotion, are never
+// considered to be downgraded.
+//
+// Two versions of an image are compared as semantic versions if both of them
+// can be parsed as such. Otherwise, the target version is considered to be
+// older if it has been promoted to the Stage before the current version, as
+// recorded by the Stage's FreightHistory. A version that has never been
+// promoted to the Stage is assumed to be newer.
+func findImageDowngrade(stage *kargoapi.Stage, freight kargoapi.FreightReference) *imageDowngrade {
+	current := stage.Status.Images.GetCurrent()
+	if len(current) == 0 {
+		if col := stage.Status.FreightHistory.Current(); col != nil {
+			for _, f := range col.Freight {
+				current = append(current, f.Images...)
+			}
+		}
+	}
+	for _, target := range freight.Images {
+		for _, cur := range current {
+			if cur.RepoURL != target.RepoURL {
+				continue
+			}
+			if isImageDowngrade(stage.Status.FreightHistory, cur, target) {
+				return &imageDowngrade{
+					repoURL: target.RepoURL,
+					current: imageVersion(cur),
+					target:  imageVersion(target),
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// isImageDowngrade returns true if the target version of an image is older
+// than its current version.
+func isImageDowngrade(history kargoapi.FreightHistory, current, target kargoapi.Image) bool {
+	currentVersion, targetVersion := imageVersion(current), imageVersion(target)
+	if currentVersion == targetVersion ||
+		(current.Digest != "" && current.Digest == target.Digest) {
+		return false
+	}
+	if currentSemver, err := semver.NewVersion(currentVersion); err == nil {
+		if targetSemver, err := semver.NewVersion(targetVersion); err == nil {
+			return targetSemver.LessThan(currentSemver)
+		}
+	}
+	targetIdx := historyIndex(history, target.RepoURL, targetVersion)
+	if targetIdx < 0 {
+		return false
+	}
+	// If the current version is not found in the history, e.g. because it was
+	// learned from Argo CD, it is assumed to be more recent than any version
+	// that is.
+	currentIdx := historyIndex(history, current.RepoURL, currentVersion)
+	return currentIdx < 0 || currentIdx < targetIdx
+}
+
+// historyIndex returns the index of the most recent entry of the provided
+// FreightHistory that includes the provided version of an image, or -1 if none
+// does.
+func historyIndex(history kargoapi.FreightHistory, repoURL, version string) int {
+	for i, col := range history {
+		if col == nil {
+			continue
+		}
+		for _, f := range col.Freight {
+			for _, img := range f.Images {
+				if img.RepoURL == repoURL && imageVersion(img) == version {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// imageVersion returns the tag of the provided image or, if it has none, its
+// digest.
+func imageVersion(img kargoapi.Image) string {
+	if img.Tag != "" {
+		return img.Tag
+	}
+	return img.Digest
+}
@@ -0,0 +1,187 @@
+package promotions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_findImageDowngrade(t *testing.T) {
+	const repoURL = "example.com/app"
+	historyOf := func(tags ...string) kargoapi.FreightHistory {
+		history := make(kargoapi.FreightHistory, len(tags))
+		for i, tag := range tags {
+			history[i] = &kargoapi.FreightCollection{
+				Freight: map[string]kargoapi.FreightReference{
+					"Warehouse/test": {
+						Images: []kargoapi.Image{{RepoURL: repoURL, Tag: tag}},
+					},
+				},
+			}
+		}
+		return history
+	}
+	freightOf := func(images ...kargoapi.Image) kargoapi.FreightReference {
+		return kargoapi.FreightReference{Images: images}
+	}
+
+	tests := []struct {
+		name     string
+		status   kargoapi.StageStatus
+		freight  kargoapi.FreightReference
+		expected *imageDowngrade
+	}{
+		{
+			name:    "first promotion",
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.0.0"}),
+		},
+		{
+			name:    "image not running in Stage",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("v2.0.0")},
+			freight: freightOf(kargoapi.Image{RepoURL: "example.com/other", Tag: "v1.0.0"}),
+		},
+		{
+			name:    "same version",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("v1.0.0")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.0.0"}),
+		},
+		{
+			name: "same digest",
+			status: kargoapi.StageStatus{
+				Images: &kargoapi.StageImages{
+					Current: []kargoapi.Image{{RepoURL: repoURL, Tag: "latest", Digest: "sha256:abc"}},
+				},
+			},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "stable", Digest: "sha256:abc"}),
+		},
+		{
+			name:    "newer semantic version",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("v1.0.0")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.1.0"}),
+		},
+		{
+			name:    "older semantic version",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("v1.10.0")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.9.0"}),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "v1.10.0",
+				target:  "v1.9.0",
+			},
+		},
+		{
+			name:    "older semantic version with different notation",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("2.0")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.9.9"}),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "2.0",
+				target:  "v1.9.9",
+			},
+		},
+		{
+			name: "older semantic version than reported by Argo CD",
+			status: kargoapi.StageStatus{
+				Images: &kargoapi.StageImages{
+					Current:       []kargoapi.Image{{RepoURL: repoURL, Tag: "v3.0.0"}},
+					CurrentSource: kargoapi.StageImagesSourceArgoCDApps,
+				},
+			},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v2.0.0"}),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "v3.0.0",
+				target:  "v2.0.0",
+			},
+		},
+		{
+			name:    "non-semantic version promoted before current version",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("build-b", "build-a")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "build-a"}),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "build-b",
+				target:  "build-a",
+			},
+		},
+		{
+			name: "non-semantic version promoted again after current version",
+			status: kargoapi.StageStatus{
+				Images: &kargoapi.StageImages{
+					Current: []kargoapi.Image{{RepoURL: repoURL, Tag: "build-b"}},
+				},
+				FreightHistory: historyOf("build-a", "build-b", "build-a"),
+			},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "build-a"}),
+		},
+		{
+			name:    "non-semantic version never promoted",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("build-b", "build-a")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "build-c"}),
+		},
+		{
+			name:    "semantic version replacing non-semantic version",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("latest")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.0.0"}),
+		},
+		{
+			name:    "semantic version promoted before non-semantic version",
+			status:  kargoapi.StageStatus{FreightHistory: historyOf("latest", "v1.0.0")},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Tag: "v1.0.0"}),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "latest",
+				target:  "v1.0.0",
+			},
+		},
+		{
+			name: "digest promoted before version reported by Argo CD",
+			status: kargoapi.StageStatus{
+				Images: &kargoapi.StageImages{
+					Current: []kargoapi.Image{{RepoURL: repoURL, Digest: "sha256:def"}},
+				},
+				FreightHistory: kargoapi.FreightHistory{{
+					Freight: map[string]kargoapi.FreightReference{
+						"Warehouse/test": {
+							Images: []kargoapi.Image{{RepoURL: repoURL, Digest: "sha256:abc"}},
+						},
+					},
+				}},
+			},
+			freight: freightOf(kargoapi.Image{RepoURL: repoURL, Digest: "sha256:abc"}),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "sha256:def",
+				target:  "sha256:abc",
+			},
+		},
+		{
+			name: "only one of several images is downgraded",
+			status: kargoapi.StageStatus{
+				Images: &kargoapi.StageImages{
+					Current: []kargoapi.Image{
+						{RepoURL: "example.com/api", Tag: "v1.0.0"},
+						{RepoURL: repoURL, Tag: "v2.0.0"},
+					},
+				},
+			},
+			freight: freightOf(
+				kargoapi.Image{RepoURL: "example.com/api", Tag: "v1.1.0"},
+				kargoapi.Image{RepoURL: repoURL, Tag: "v1.5.0"},
+			),
+			expected: &imageDowngrade{
+				repoURL: repoURL,
+				current: "v2.0.0",
+				target:  "v1.5.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := &kargoapi.Stage{Status: tt.status}
+			assert.Equal(t, tt.expected, findImageDowngrade(stage, tt.freight))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/kargo"
+)
+
+// checkImageCompleteness compares the images carried by the provided Freight
+// with the images subscribed to by the Warehouse it originates from. If the
+// Stage requires the Freight to carry exactly the subscribed images and it does
+// not, a message explaining why the Promotion has to fail is returned.
+// Otherwise, the subscribed images that the Freight does not carry are
+// returned, so that the versions the Stage retains of them can be recorded.
+//
+// Freight that does not originate from a Warehouse, or whose Warehouse no
+// longer exists, is never considered to be incomplete.
+func (r *reconciler) checkImageCompleteness(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	freight *kargoapi.Freight,
+) ([]kargoapi.RetainedImage, string, error) {
+	if freight.Origin.Kind != kargoapi.FreightOriginKindWarehouse {
+		return nil, "", nil
+	}
+	warehouse, err := kargoapi.GetWarehouse(ctx, r.kargoClient, types.NamespacedName{
+		Namespace: freight.Namespace,
+		Name:      freight.Origin.Name,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if warehouse == nil {
+		return nil, "", nil
+	}
+	coverage := kargo.NewImageCoverage(warehouse, freight.Images)
+	if stage.Spec.ImageCompleteness == kargoapi.ImageCompletenessPolicyDeclared &&
+		!coverage.Complete() {
+		return nil, fmt.Sprintf("IncompleteImages: Freight %q %s", freight.Name, coverage), nil
+	}
+	var retained []kargoapi.RetainedImage
+	for _, repoURL := range coverage.Missing {
+		retained = append(retained, kargoapi.RetainedImage{RepoURL: repoURL})
+	}
+	return retained, "", nil
+}
@@ -0,0 +1,98 @@
+package promotions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_reconciler_checkImageCompleteness(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+	r := &reconciler{
+		kargoClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&kargoapi.Warehouse{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fake-project", Name: "fake-warehouse"},
+				Spec: kargoapi.WarehouseSpec{
+					Subscriptions: []kargoapi.RepoSubscription{
+						{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/frontend"}},
+						{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/backend"}},
+					},
+				},
+			},
+		).Build(),
+	}
+	freightOf := func(warehouse string, images ...kargoapi.Image) *kargoapi.Freight {
+		return &kargoapi.Freight{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "fake-project", Name: "fake-freight"},
+			Origin: kargoapi.FreightOrigin{
+				Kind: kargoapi.FreightOriginKindWarehouse,
+				Name: warehouse,
+			},
+			Images: images,
+		}
+	}
+	frontend := kargoapi.Image{RepoURL: "example.com/frontend", Tag: "v1.0.0"}
+	backend := kargoapi.Image{RepoURL: "example.com/backend", Tag: "v1.0.0"}
+
+	tests := []struct {
+		name             string
+		policy           kargoapi.ImageCompletenessPolicy
+		freight          *kargoapi.Freight
+		expectedRetained []kargoapi.RetainedImage
+		expectedMessage  string
+	}{
+		{
+			name:    "Freight carries all images",
+			policy:  kargoapi.ImageCompletenessPolicyDeclared,
+			freight: freightOf("fake-warehouse", frontend, backend),
+		},
+		{
+			name:             "any: missing images are retained",
+			policy:           kargoapi.ImageCompletenessPolicyAny,
+			freight:          freightOf("fake-warehouse", frontend),
+			expectedRetained: []kargoapi.RetainedImage{{RepoURL: "example.com/backend"}},
+		},
+		{
+			name:    "declared: missing images",
+			policy:  kargoapi.ImageCompletenessPolicyDeclared,
+			freight: freightOf("fake-warehouse", frontend),
+			expectedMessage: `IncompleteImages: Freight "fake-freight" does not carry ` +
+				`subscribed image(s) example.com/backend`,
+		},
+		{
+			name:   "declared: undeclared images",
+			policy: kargoapi.ImageCompletenessPolicyDeclared,
+			freight: freightOf(
+				"fake-warehouse",
+				frontend,
+				backend,
+				kargoapi.Image{RepoURL: "example.com/sidecar", Tag: "v1.0.0"},
+			),
+			expectedMessage: `IncompleteImages: Freight "fake-freight" carries ` +
+				`image(s) example.com/sidecar that are not subscribed to`,
+		},
+		{
+			name:    "Warehouse does not exist",
+			policy:  kargoapi.ImageCompletenessPolicyDeclared,
+			freight: freightOf("missing-warehouse", frontend),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := &kargoapi.Stage{
+				Spec: kargoapi.StageSpec{ImageCompleteness: tt.policy},
+			}
+			retained, msg, err := r.checkImageCompleteness(context.Background(), stage, tt.freight)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRetained, retained)
+			require.Equal(t, tt.expectedMessage, msg)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package promotions
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	runningReconciles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kargo_promotion_running_reconciles_total",
+			Help: "Number of reconciliations of Promotions that were already " +
+				"Running, i.e. that checked on their progress",
+		},
+		[]string{"project", "stage"},
+	)
+	wastedReconciles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kargo_promotion_wasted_reconciles_total",
+			Help: "Number of reconciliations of Promotions that were already " +
+				"Running and observed no change in their state",
+		},
+		[]string{"project", "stage"},
+	)
+	reconcileOutcomes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kargo_promotion_reconcile_outcomes_total",
+			Help: "Number of reconciliations of Promotions by the reason for " +
+				"their outcome, i.e. what they did or why they did nothing",
+		},
+		[]string{"project", "stage", "reason"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(runningReconciles, wastedReconciles, reconcileOutcomes)
+}
+
+// registerGloballyPausedGauge registers a gauge that reports whether the
+// execution of all Promotions is paused. Its value is determined by the
+// provided function whenever metrics are collected, so that it follows changes
+// to the KargoConfig resource.
+func registerGloballyPausedGauge(pausedFn func() bool) error {
+	return metrics.Registry.Register(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "kargo_promotions_paused_globally",
+			Help: "Whether the execution of all Promotions is paused, either " +
+				"by the controller's configuration or by the KargoConfig " +
+				"resource (1) or not (0)",
+		},
+		func() float64 {
+			if pausedFn() {
+				return 1
+			}
+			return 0
+		},
+	))
+}
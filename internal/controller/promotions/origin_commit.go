@@ -0,0 +1,112 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// reportToOriginCommit reports the outcome of the provided Promotion, whose
+// new, terminal status is also provided, as a status of the commit to an
+// application's source repository that the Promotion's Freight originated
+// from, if any. Failing to do so is logged, but does not otherwise affect the
+// Promotion. In particular, if there are no credentials for the source
+// repository, no status is reported.
+func (r *reconciler) reportToOriginCommit(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	newStatus *kargoapi.PromotionStatus,
+) {
+	origin := promo.Spec.OriginCommit
+	if origin == nil {
+		return
+	}
+	status, ok := r.originCommitStatus(promo, newStatus)
+	if !ok {
+		return
+	}
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"originRepoURL", origin.RepoURL,
+		"originCommit", origin.ID,
+	)
+
+	creds, found, err := r.credentialsDB.Get(
+		ctx,
+		promo.Namespace,
+		credentials.TypeGit,
+		origin.RepoURL,
+	)
+	if err != nil {
+		logger.Error(err, "error getting credentials for origin commit repository")
+		return
+	}
+	if !found {
+		logger.Info(
+			"no credentials found for origin commit repository; " +
+				"outcome of Promotion will not be reported to it",
+		)
+		return
+	}
+
+	gitProvider, err := r.newGitProviderFn(
+		origin.RepoURL,
+		&gitprovider.Options{Token: creds.Password},
+	)
+	if err != nil {
+		logger.Error(err, "error creating git provider service for origin commit repository")
+		return
+	}
+	setter, ok := gitProvider.(gitprovider.CommitStatusSetter)
+	if !ok {
+		logger.Info(
+			"git provider of origin commit repository does not support commit " +
+				"statuses; outcome of Promotion will not be reported to it",
+		)
+		return
+	}
+	if err = setter.SetCommitStatus(ctx, origin.ID, status); err != nil {
+		logger.Error(err, "error reporting outcome of Promotion to origin commit")
+		return
+	}
+	logger.Debug("reported outcome of Promotion to origin commit", "state", status.State)
+}
+
+// originCommitStatus returns the commit status that reports the provided
+// terminal status of the provided Promotion. False is returned if the outcome
+// of the Promotion is not to be reported.
+func (r *reconciler) originCommitStatus(
+	promo *kargoapi.Promotion,
+	newStatus *kargoapi.PromotionStatus,
+) (*gitprovider.CommitStatus, bool) {
+	status := &gitprovider.CommitStatus{
+		// Each Stage reports its own status, so the progress of the commit
+		// through all of them is visible.
+		Context: fmt.Sprintf("kargo/%s/%s", promo.Namespace, promo.Spec.Stage),
+	}
+	switch newStatus.Phase {
+	case kargoapi.PromotionPhaseSucceeded:
+		status.State = gitprovider.CommitStatusStateSuccess
+		status.Description = fmt.Sprintf("Promoted to %s", promo.Spec.Stage)
+	case kargoapi.PromotionPhaseFailed:
+		status.State = gitprovider.CommitStatusStateFailure
+		status.Description = fmt.Sprintf("Promotion to %s failed", promo.Spec.Stage)
+	case kargoapi.PromotionPhaseErrored:
+		status.State = gitprovider.CommitStatusStateError
+		status.Description = fmt.Sprintf("Promotion to %s errored", promo.Spec.Stage)
+	default:
+		// A Skipped or Expired Promotion did not change what is deployed to
+		// the Stage.
+		return nil, false
+	}
+	if r.cfg.APIServerBaseURL != "" {
+		status.TargetURL = fmt.Sprintf(
+			"%s/project/%s/stage/%s",
+			r.cfg.APIServerBaseURL, promo.Namespace, promo.Spec.Stage,
+		)
+	}
+	return status, true
+}
@@ -0,0 +1,164 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+func TestReportToOriginCommit(t *testing.T) {
+	const testRepoURL = "https://github.com/example/app"
+	testPromo := &kargoapi.Promotion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "fake-promo",
+		},
+		Spec: kargoapi.PromotionSpec{
+			Stage: "fake-stage",
+			OriginCommit: &kargoapi.OriginCommit{
+				RepoURL: testRepoURL,
+				ID:      "abc123",
+			},
+		},
+	}
+	foundCreds := &credentials.FakeDB{
+		GetFn: func(
+			context.Context,
+			string,
+			credentials.Type,
+			string,
+		) (credentials.Credentials, bool, error) {
+			return credentials.Credentials{Password: "fake-token"}, true, nil
+		},
+	}
+	testCases := []struct {
+		name       string
+		promo      *kargoapi.Promotion
+		phase      kargoapi.PromotionPhase
+		db         credentials.Database
+		assertions func(*testing.T, *gitprovider.Options, []*gitprovider.CommitStatus)
+	}{
+		{
+			name: "no origin commit",
+			promo: &kargoapi.Promotion{
+				Spec: kargoapi.PromotionSpec{Stage: "fake-stage"},
+			},
+			phase: kargoapi.PromotionPhaseSucceeded,
+			db:    foundCreds,
+			assertions: func(t *testing.T, opts *gitprovider.Options, statuses []*gitprovider.CommitStatus) {
+				require.Nil(t, opts)
+				require.Empty(t, statuses)
+			},
+		},
+		{
+			name:  "skipped",
+			promo: testPromo,
+			phase: kargoapi.PromotionPhaseSkipped,
+			db:    foundCreds,
+			assertions: func(t *testing.T, opts *gitprovider.Options, statuses []*gitprovider.CommitStatus) {
+				require.Nil(t, opts)
+				require.Empty(t, statuses)
+			},
+		},
+		{
+			name:  "error getting credentials",
+			promo: testPromo,
+			phase: kargoapi.PromotionPhaseSucceeded,
+			db: &credentials.FakeDB{
+				GetFn: func(
+					context.Context,
+					string,
+					credentials.Type,
+					string,
+				) (credentials.Credentials, bool, error) {
+					return credentials.Credentials{}, false, errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, opts *gitprovider.Options, statuses []*gitprovider.CommitStatus) {
+				require.Nil(t, opts)
+				require.Empty(t, statuses)
+			},
+		},
+		{
+			name:  "no credentials",
+			promo: testPromo,
+			phase: kargoapi.PromotionPhaseSucceeded,
+			db:    &credentials.FakeDB{},
+			assertions: func(t *testing.T, opts *gitprovider.Options, statuses []*gitprovider.CommitStatus) {
+				require.Nil(t, opts)
+				require.Empty(t, statuses)
+			},
+		},
+		{
+			name:  "succeeded",
+			promo: testPromo,
+			phase: kargoapi.PromotionPhaseSucceeded,
+			db:    foundCreds,
+			assertions: func(t *testing.T, opts *gitprovider.Options, statuses []*gitprovider.CommitStatus) {
+				require.Equal(t, "fake-token", opts.Token)
+				require.Equal(
+					t,
+					[]*gitprovider.CommitStatus{{
+						Context:     "kargo/fake-project/fake-stage",
+						State:       gitprovider.CommitStatusStateSuccess,
+						Description: "Promoted to fake-stage",
+						TargetURL:   "https://kargo.example.com/project/fake-project/stage/fake-stage",
+					}},
+					statuses,
+				)
+			},
+		},
+		{
+			name:  "errored",
+			promo: testPromo,
+			phase: kargoapi.PromotionPhaseErrored,
+			db:    foundCreds,
+			assertions: func(t *testing.T, _ *gitprovider.Options, statuses []*gitprovider.CommitStatus) {
+				require.Len(t, statuses, 1)
+				require.Equal(t, gitprovider.CommitStatusStateError, statuses[0].State)
+				require.Equal(t, "Promotion to fake-stage errored", statuses[0].Description)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var providerOpts *gitprovider.Options
+			var statuses []*gitprovider.CommitStatus
+			r := &reconciler{
+				credentialsDB: testCase.db,
+				cfg:           ReconcilerConfig{APIServerBaseURL: "https://kargo.example.com"},
+				newGitProviderFn: func(
+					repoURL string,
+					opts *gitprovider.Options,
+				) (gitprovider.Interface, error) {
+					require.Equal(t, testRepoURL, repoURL)
+					providerOpts = opts
+					return &gitprovider.Fake{
+						SetCommitStatusFn: func(
+							_ context.Context,
+							commitID string,
+							status *gitprovider.CommitStatus,
+						) error {
+							require.Equal(t, "abc123", commitID)
+							statuses = append(statuses, status)
+							return nil
+						},
+					}, nil
+				},
+			}
+			r.reportToOriginCommit(
+				context.Background(),
+				testCase.promo,
+				&kargoapi.PromotionStatus{Phase: testCase.phase},
+			)
+			testCase.assertions(t, providerOpts, statuses)
+		})
+	}
+}
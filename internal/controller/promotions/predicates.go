@@ -1,6 +1,8 @@
 package promotions
 
 import (
+	"slices"
+
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
@@ -15,8 +17,10 @@ type ArgoCDAppOperationCompleted[T any] struct {
 	logger *logging.Logger
 }
 
+// Create admits all Application creation events, as running Promotions may be
+// waiting for the Application to be created.
 func (p ArgoCDAppOperationCompleted[T]) Create(event.TypedCreateEvent[T]) bool {
-	return false
+	return true
 }
 
 func (p ArgoCDAppOperationCompleted[T]) Update(e event.TypedUpdateEvent[T]) bool {
@@ -55,3 +59,60 @@ func (p ArgoCDAppOperationCompleted[T]) Delete(event.TypedDeleteEvent[T]) bool {
 func (p ArgoCDAppOperationCompleted[T]) Generic(event.TypedGenericEvent[T]) bool {
 	return false
 }
+
+// ArgoCDAppStatusChanged is a predicate that admits Argo CD Application Update
+// events where the health or sync status of the Application changed. This
+// permits Promotions waiting for an Application to become healthy or synced to
+// notice as soon as it does, instead of polling for it.
+type ArgoCDAppStatusChanged[T any] struct{}
+
+func (p ArgoCDAppStatusChanged[T]) Create(event.TypedCreateEvent[T]) bool {
+	return false
+}
+
+func (p ArgoCDAppStatusChanged[T]) Update(e event.TypedUpdateEvent[T]) bool {
+	oldApp, _ := any(e.ObjectOld).(*argocd.Application)
+	newApp, _ := any(e.ObjectNew).(*argocd.Application)
+	if oldApp == nil || newApp == nil {
+		return false
+	}
+	return oldApp.Status.Health.Status != newApp.Status.Health.Status ||
+		oldApp.Status.Sync.Status != newApp.Status.Sync.Status ||
+		oldApp.Status.Sync.Revision != newApp.Status.Sync.Revision ||
+		!slices.Equal(oldApp.Status.Sync.Revisions, newApp.Status.Sync.Revisions)
+}
+
+func (p ArgoCDAppStatusChanged[T]) Delete(event.TypedDeleteEvent[T]) bool {
+	return false
+}
+
+func (p ArgoCDAppStatusChanged[T]) Generic(event.TypedGenericEvent[T]) bool {
+	return false
+}
+
+// ArgoCDAppDeleted is a predicate that admits Argo CD Application Delete
+// events, as well as Update events where the Application begins to be
+// deleted. This permits Promotions waiting for an Application to be synced to
+// fail as soon as it is deleted, instead of when they are next requeued.
+type ArgoCDAppDeleted[T any] struct{}
+
+func (p ArgoCDAppDeleted[T]) Create(event.TypedCreateEvent[T]) bool {
+	return false
+}
+
+func (p ArgoCDAppDeleted[T]) Update(e event.TypedUpdateEvent[T]) bool {
+	oldApp, _ := any(e.ObjectOld).(*argocd.Application)
+	newApp, _ := any(e.ObjectNew).(*argocd.Application)
+	if oldApp == nil || newApp == nil {
+		return false
+	}
+	return oldApp.DeletionTimestamp == nil && newApp.DeletionTimestamp != nil
+}
+
+func (p ArgoCDAppDeleted[T]) Delete(event.TypedDeleteEvent[T]) bool {
+	return true
+}
+
+func (p ArgoCDAppDeleted[T]) Generic(event.TypedGenericEvent[T]) bool {
+	return false
+}
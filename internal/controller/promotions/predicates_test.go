@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
@@ -98,3 +99,129 @@ func TestArgoCDAppOperationCompleted_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestArgoCDAppStatusChanged_Update(t *testing.T) {
+	testCases := []struct {
+		name string
+		e    event.TypedUpdateEvent[*argocd.Application]
+		want bool
+	}{
+		{
+			name: "ObjectOld is nil",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectNew: &argocd.Application{},
+			},
+			want: false,
+		},
+		{
+			name: "Status unchanged",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectOld: &argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Health: argocd.HealthStatus{Status: argocd.HealthStatusProgressing},
+					},
+				},
+				ObjectNew: &argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Health: argocd.HealthStatus{
+							Status:  argocd.HealthStatusProgressing,
+							Message: "Waiting for rollout",
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Health changed",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectOld: &argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Health: argocd.HealthStatus{Status: argocd.HealthStatusProgressing},
+					},
+				},
+				ObjectNew: &argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Health: argocd.HealthStatus{Status: argocd.HealthStatusHealthy},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Synced revision changed",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectOld: &argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Sync: argocd.SyncStatus{Status: argocd.SyncStatusCodeSynced, Revision: "abc"},
+					},
+				},
+				ObjectNew: &argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Sync: argocd.SyncStatus{Status: argocd.SyncStatusCodeSynced, Revision: "def"},
+					},
+				},
+			},
+			want: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p := ArgoCDAppStatusChanged[*argocd.Application]{}
+			require.Equal(t, testCase.want, p.Update(testCase.e))
+		})
+	}
+}
+
+func TestArgoCDAppDeleted_Update(t *testing.T) {
+	now := metav1.Now()
+	testCases := []struct {
+		name string
+		e    event.TypedUpdateEvent[*argocd.Application]
+		want bool
+	}{
+		{
+			name: "ObjectOld is nil",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectNew: &argocd.Application{},
+			},
+			want: false,
+		},
+		{
+			name: "Not being deleted",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectOld: &argocd.Application{},
+				ObjectNew: &argocd.Application{},
+			},
+			want: false,
+		},
+		{
+			name: "Deletion began",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectOld: &argocd.Application{},
+				ObjectNew: &argocd.Application{
+					ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Already being deleted",
+			e: event.TypedUpdateEvent[*argocd.Application]{
+				ObjectOld: &argocd.Application{
+					ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				},
+				ObjectNew: &argocd.Application{
+					ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p := ArgoCDAppDeleted[*argocd.Application]{}
+			require.Equal(t, testCase.want, p.Update(testCase.e))
+		})
+	}
+}
@@ -1,19 +1,25 @@
 package promotions
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,16 +28,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	"github.com/akuity/kargo/internal/conditions"
 	"github.com/akuity/kargo/internal/controller"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/controller/kargoconfig"
+	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/directives"
 	"github.com/akuity/kargo/internal/event"
+	"github.com/akuity/kargo/internal/gitprovider"
 	"github.com/akuity/kargo/internal/indexer"
 	"github.com/akuity/kargo/internal/kargo"
 	"github.com/akuity/kargo/internal/kubeclient"
 	libEvent "github.com/akuity/kargo/internal/kubernetes/event"
 	"github.com/akuity/kargo/internal/logging"
+	libos "github.com/akuity/kargo/internal/os"
 	intpredicate "github.com/akuity/kargo/internal/predicate"
+	"github.com/akuity/kargo/internal/tools"
+)
+
+const (
+	// transcriptConfigMapSuffix is appended to the name of a Promotion to form
+	// the name of the ConfigMap holding the transcript of its commands.
+	transcriptConfigMapSuffix = "-transcript"
+	// transcriptComponent is the value of the component label of ConfigMaps
+	// holding transcripts.
+	transcriptComponent = "transcript"
+	// transcriptDataKey is the key of the data of a transcript ConfigMap that
+	// holds the transcript.
+	transcriptDataKey = "transcript"
 )
 
 // ReconcilerConfig represents configuration for the promotion reconciler.
@@ -39,8 +65,67 @@ type ReconcilerConfig struct {
 	ShardName               string `envconfig:"SHARD_NAME"`
 	APIServerBaseURL        string `envconfig:"API_SERVER_BASE_URL"`
 	MaxConcurrentReconciles int    `envconfig:"MAX_CONCURRENT_PROMOTION_RECONCILES" default:"4"`
+	// Paused indicates whether the execution of all Promotions handled by this
+	// reconciler is paused.
+	Paused bool `envconfig:"PROMOTIONS_PAUSED" default:"false"`
+	// WorkDir is the directory under which a working directory is created for
+	// each Promotion. If not specified, the default directory for temporary
+	// files is used.
+	WorkDir string `envconfig:"PROMOTION_WORK_DIR"`
+	// WorkDirMinFreeMiB is the minimum amount of free space, in MiB, that must be
+	// available in WorkDir for a Promotion to be executed. A value of zero
+	// disables this check.
+	WorkDirMinFreeMiB uint64 `envconfig:"PROMOTION_WORK_DIR_MIN_FREE_MIB" default:"0"`
+	// StageGracePeriod is how long a Promotion waits for the Stage it
+	// references to be created before it is marked as Errored. This allows a
+	// Promotion to be created before (or alongside) its Stage.
+	StageGracePeriod time.Duration `envconfig:"PROMOTION_STAGE_GRACE_PERIOD" default:"5m"`
+	// FailureInjectionEnabled indicates whether synthetic failures are injected
+	// into the execution of Promotions that request them using the
+	// kargo.akuity.io/fail-at annotation. This is INSECURE, since it allows
+	// anyone who can annotate a Promotion to make it fail, and must only ever be
+	// enabled in test environments.
+	FailureInjectionEnabled bool `envconfig:"INSECURE_PROMOTION_FAILURE_INJECTION_ENABLED" default:"false"`
+	// ShutdownGracePeriod is how long Promotion steps that are in progress when
+	// the controller begins shutting down are given to finish. No further steps
+	// are started once shutdown begins, and the progress of each Promotion is
+	// recorded so that it resumes from where it left off once the controller
+	// is running again.
+	ShutdownGracePeriod time.Duration `envconfig:"PROMOTION_SHUTDOWN_GRACE_PERIOD" default:"20s"`
+	// ToolCacheDir is the directory in which the binaries of the versions of
+	// tools pinned by Stages are cached. Binaries may be placed there in
+	// advance. If not specified, only the versions of tools embedded in Kargo
+	// are available.
+	ToolCacheDir string `envconfig:"TOOL_CACHE_DIR"`
+	// ToolDownloadsEnabled indicates whether binaries of pinned tool versions
+	// missing from ToolCacheDir are downloaded.
+	ToolDownloadsEnabled bool `envconfig:"TOOL_DOWNLOADS_ENABLED" default:"true"`
+	// KustomizeDownloadURL and KustomizeChecksumURL are templates of the URLs
+	// that Kustomize binaries and their checksums are downloaded from. If not
+	// specified, Kustomize is downloaded from its GitHub releases.
+	KustomizeDownloadURL string `envconfig:"KUSTOMIZE_DOWNLOAD_URL"`
+	KustomizeChecksumURL string `envconfig:"KUSTOMIZE_CHECKSUM_URL"`
+	// HelmDownloadURL and HelmChecksumURL are templates of the URLs that Helm
+	// binaries and their checksums are downloaded from. If not specified, Helm
+	// is downloaded from get.helm.sh.
+	HelmDownloadURL string `envconfig:"HELM_DOWNLOAD_URL"`
+	HelmChecksumURL string `envconfig:"HELM_CHECKSUM_URL"`
+	// MaxChildrenPerPromotion is the number of objects, such as the ConfigMaps
+	// holding transcripts, that may be created on behalf of a single
+	// Promotion. Objects exceeding it are not created, which is recorded in the
+	// Promotion's status.
+	MaxChildrenPerPromotion int `envconfig:"MAX_CHILDREN_PER_PROMOTION" default:"10"`
 }
 
+// shutdownStatusTimeout is how long, beyond ShutdownGracePeriod, the
+// controller waits during shutdown for the progress of Promotions to be
+// recorded.
+const shutdownStatusTimeout = 10 * time.Second
+
+// pausedRequeueInterval is the interval after which a Promotion is requeued
+// when promotions are paused.
+const pausedRequeueInterval = time.Minute
+
 func (c ReconcilerConfig) Name() string {
 	name := "promotion-controller"
 	if c.ShardName != "" {
@@ -49,6 +134,43 @@ func (c ReconcilerConfig) Name() string {
 	return name
 }
 
+// workDirRoot returns the directory under which a working directory is created
+// for each Promotion.
+func (c ReconcilerConfig) workDirRoot() string {
+	if c.WorkDir != "" {
+		return c.WorkDir
+	}
+	return os.TempDir()
+}
+
+// toolCache returns the Cache providing the binaries of tool versions pinned
+// by Stages, or nil if no ToolCacheDir is specified.
+func (c ReconcilerConfig) toolCache() *tools.Cache {
+	if c.ToolCacheDir == "" {
+		return nil
+	}
+	var sources map[tools.Tool]tools.Source
+	if c.ToolDownloadsEnabled {
+		sources = map[tools.Tool]tools.Source{
+			tools.Kustomize: {
+				URL:         cmp.Or(c.KustomizeDownloadURL, tools.DefaultKustomizeURL),
+				ChecksumURL: cmp.Or(c.KustomizeChecksumURL, tools.DefaultKustomizeChecksumURL),
+			},
+			tools.Helm: {
+				URL:         cmp.Or(c.HelmDownloadURL, tools.DefaultHelmURL),
+				ChecksumURL: cmp.Or(c.HelmChecksumURL, tools.DefaultHelmChecksumURL),
+			},
+		}
+	}
+	return tools.NewCache(c.ToolCacheDir, sources)
+}
+
+// ShutdownTimeout returns how long the manager running the reconciler should
+// wait for it to stop during shutdown.
+func (c ReconcilerConfig) ShutdownTimeout() time.Duration {
+	return c.ShutdownGracePeriod + shutdownStatusTimeout
+}
+
 func ReconcilerConfigFromEnv() ReconcilerConfig {
 	var cfg ReconcilerConfig
 	envconfig.MustProcess("", &cfg)
@@ -60,10 +182,26 @@ type reconciler struct {
 	kargoClient      client.Client
 	directivesEngine directives.Engine
 
+	// credentialsDB provides the credentials used to report the outcome of
+	// Promotions to the commits their Freight originated from and to clean up
+	// the rendered branches of Stages after Promotions failed.
+	credentialsDB credentials.Database
+
+	// kargoConfig provides settings from the KargoConfig resource that may
+	// change while the controller is running.
+	kargoConfig *kargoconfig.Watcher
+
 	cfg ReconcilerConfig
 
+	// toolCache provides the binaries of tool versions pinned by Stages. It is
+	// nil if the controller is not configured with a tool cache.
+	toolCache *tools.Cache
+
 	recorder record.EventRecorder
 
+	// childCreator creates the objects owned by Promotions.
+	childCreator *controller.ChildCreator
+
 	// The following behaviors are overridable for testing purposes:
 
 	getStageFn func(
@@ -85,6 +223,12 @@ type reconciler struct {
 		*kargoapi.Promotion,
 		*kargoapi.Freight,
 	) error
+
+	newGitProviderFn func(string, *gitprovider.Options) (gitprovider.Interface, error)
+
+	tagRemoteCommitFn func(string, string, string, string, *git.ClientOptions) error
+
+	resetRemoteBranchFn func(string, string, string, string, *git.ClientOptions) error
 }
 
 // SetupReconcilerWithManager initializes a reconciler for Promotion resources
@@ -92,8 +236,10 @@ type reconciler struct {
 func SetupReconcilerWithManager(
 	ctx context.Context,
 	kargoMgr manager.Manager,
-	argocdMgr manager.Manager,
+	argoCDContexts *libargocd.Contexts,
 	directivesEngine directives.Engine,
+	credentialsDB credentials.Database,
+	kargoConfig *kargoconfig.Watcher,
 	cfg ReconcilerConfig,
 ) error {
 	// Index running Promotions by Argo CD Applications
@@ -110,9 +256,15 @@ func SetupReconcilerWithManager(
 		kargoMgr.GetClient(),
 		libEvent.NewRecorder(ctx, kargoMgr.GetScheme(), kargoMgr.GetClient(), cfg.Name()),
 		directivesEngine,
+		credentialsDB,
+		kargoConfig,
 		cfg,
 	)
 
+	if err := registerGloballyPausedGauge(reconciler.promotionsPausedGlobally); err != nil {
+		return fmt.Errorf("error registering metrics: %w", err)
+	}
+
 	c, err := ctrl.NewControllerManagedBy(kargoMgr).
 		For(&kargoapi.Promotion{}).
 		WithEventFilter(intpredicate.IgnoreDelete[client.Object]{}).
@@ -120,6 +272,7 @@ func SetupReconcilerWithManager(
 			predicate.GenerationChangedPredicate{},
 			kargo.RefreshRequested{},
 			kargo.PromotionAbortRequested{},
+			kargo.PromotionApproved{},
 		)).
 		WithOptions(controller.CommonOptions(cfg.MaxConcurrentReconciles)).
 		Build(reconciler)
@@ -140,28 +293,40 @@ func SetupReconcilerWithManager(
 		return fmt.Errorf("unable to watch Stages: %w", err)
 	}
 
-	// If Argo CD integration is disabled, this manager will be nil, and we won't
-	// care about this watch anyway.
-	if argocdMgr != nil {
+	// If Argo CD integration is disabled, there will be no Argo CD contexts, and
+	// we won't care about this watch anyway.
+	for _, argoCDCtx := range argoCDContexts.List() {
+		if argoCDCtx.Cache == nil {
+			continue
+		}
 		if err = c.Watch(
 			source.Kind(
-				argocdMgr.GetCache(),
+				argoCDCtx.Cache,
 				&argocd.Application{},
 				&UpdatedArgoCDAppHandler[*argocd.Application]{
 					kargoClient: kargoMgr.GetClient(),
 				},
-				ArgoCDAppOperationCompleted[*argocd.Application]{
-					logger: logger,
-				},
+				predicate.Or[*argocd.Application](
+					ArgoCDAppOperationCompleted[*argocd.Application]{
+						logger: logger,
+					},
+					ArgoCDAppStatusChanged[*argocd.Application]{},
+					ArgoCDAppDeleted[*argocd.Application]{},
+				),
 			),
 		); err != nil {
-			return fmt.Errorf("unable to watch Applications: %w", err)
+			return fmt.Errorf(
+				"unable to watch Applications in Argo CD context %q: %w",
+				argoCDCtx.Name, err,
+			)
 		}
 	}
 
 	logging.LoggerFromContext(ctx).Info(
 		"Initialized Promotion reconciler",
 		"maxConcurrentReconciles", cfg.MaxConcurrentReconciles,
+		"paused", cfg.Paused,
+		"workDir", cfg.workDirRoot(),
 	)
 
 	return nil
@@ -171,20 +336,36 @@ func newReconciler(
 	kargoClient client.Client,
 	recorder record.EventRecorder,
 	directivesEngine directives.Engine,
+	credentialsDB credentials.Database,
+	kargoConfig *kargoconfig.Watcher,
 	cfg ReconcilerConfig,
 ) *reconciler {
 	r := &reconciler{
 		kargoClient:      kargoClient,
 		directivesEngine: directivesEngine,
+		credentialsDB:    credentialsDB,
+		kargoConfig:      kargoConfig,
 		recorder:         recorder,
 		cfg:              cfg,
+		toolCache:        cfg.toolCache(),
+		childCreator:     controller.NewChildCreator(kargoClient, cfg.MaxChildrenPerPromotion),
 	}
 	r.getStageFn = kargoapi.GetStage
 	r.promoteFn = r.promote
 	r.terminatePromotionFn = r.terminatePromotion
+	r.newGitProviderFn = gitprovider.New
+	r.tagRemoteCommitFn = git.TagRemoteCommit
+	r.resetRemoteBranchFn = git.ResetRemoteBranch
 	return r
 }
 
+// promotionsPausedGlobally returns true if the execution of all Promotions is
+// paused, either by the reconciler's configuration or by the KargoConfig
+// resource.
+func (r *reconciler) promotionsPausedGlobally() bool {
+	return r.cfg.Paused || r.kargoConfig.PromotionsPaused()
+}
+
 // Reconcile is part of the main Kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *reconciler) Reconcile(
@@ -236,7 +417,7 @@ func (r *reconciler) Reconcile(
 		if err = r.terminatePromotionFn(ctx, req, promo, freight); err != nil {
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.recordOutcome(ctx, promo, freight, kargoapi.ReconcileOutcomeCompleted, "")
 	}
 
 	// If the Promotion does not have a Phase, it must be new and (initially)
@@ -265,12 +446,37 @@ func (r *reconciler) Reconcile(
 		)
 	}
 	if stage == nil {
-		return ctrl.Result{}, fmt.Errorf(
-			"could not find Stage %q in namespace %q",
-			promo.Spec.Stage, promo.Namespace,
+		result, err := r.waitForStage(ctx, promo)
+		if err != nil {
+			return result, err
+		}
+		return result, r.recordOutcome(
+			ctx, promo, freight, kargoapi.ReconcileOutcomeStageNotFound, promo.Status.Message,
 		)
 	}
 
+	// Do not begin before the Promotion has been approved, if the Stage required
+	// Promotions to be approved when it was created. This is checked before the
+	// Stage is consulted, as the Stage only hands a Promotion its turn once its
+	// approval has been recorded.
+	if promo.Spec.Approval != nil && promo.Status.Phase != kargoapi.PromotionPhaseRunning {
+		approved, err := r.checkApproval(ctx, promo)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !approved {
+			// A Promotion that has been waiting to be approved for too long
+			// expires, so that it is not approved after it has become stale.
+			result, err := r.expireUnapproved(ctx, promo, freight)
+			if err != nil {
+				return result, err
+			}
+			return result, r.recordOutcome(
+				ctx, promo, freight, kargoapi.ReconcileOutcomeAwaitingApproval, promo.Status.Message,
+			)
+		}
+	}
+
 	// Confirm that the Stage is awaiting this Promotion.
 	// This effectively prevents the Promotion from running until the Stage
 	// decides it is the next Promotion to run.
@@ -278,9 +484,53 @@ func (r *reconciler) Reconcile(
 		// The watch on the Stage will requeue the Promotion if the Stage
 		// acknowledges it.
 		logger.Debug("Stage is not awaiting Promotion", "stage", stage.Name, "promotion", promo.Name)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.recordOutcome(
+			ctx, promo, freight, kargoapi.ReconcileOutcomeQueued,
+			fmt.Sprintf("Stage %q is not awaiting the Promotion", stage.Name),
+		)
 	}
 
+	// Do not make any progress while promotions are paused, either globally or
+	// for the Stage. Once unpaused, the Promotion resumes where it left off.
+	globallyPaused := r.promotionsPausedGlobally()
+	if globallyPaused || kargoapi.PromotionsPausedAnnotationValue(stage.GetAnnotations()) {
+		logger.Debug("promotions are paused; skipping", "globallyPaused", globallyPaused)
+		const pausedMsg = "Promotions are paused"
+		if promo.Status.Message != pausedMsg {
+			if err = kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+				status.Message = pausedMsg
+			}); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: pausedRequeueInterval},
+			r.recordOutcome(ctx, promo, freight, kargoapi.ReconcileOutcomePaused, pausedMsg)
+	}
+
+	// Do not begin while the Stage's branch has drifted from what was last
+	// promoted to it, as the Promotion would otherwise overwrite the changes
+	// that caused the drift. A Promotion that is already Running is allowed to
+	// finish.
+	if drift := stage.Status.Drift; drift.IsBlocking() && promo.Status.Phase != kargoapi.PromotionPhaseRunning {
+		logger.Debug("Stage has unresolved drift; skipping", "drift", drift.DriftedCommit)
+		driftMsg := driftBlockedMessage(drift)
+		if promo.Status.Message != driftMsg {
+			if err = kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+				status.Message = driftMsg
+			}); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: pausedRequeueInterval},
+			r.recordOutcome(ctx, promo, freight, kargoapi.ReconcileOutcomeDriftBlocked, driftMsg)
+	}
+
+	// Promotions that were already Running before this reconciliation are
+	// polled for progress. Those reconciliations are accounted for, so that it
+	// is apparent how many of them are wasted on Promotions that did not
+	// progress.
+	wasRunning := promo.Status.Phase == kargoapi.PromotionPhaseRunning
+
 	// Update promo status as Running to give visibility in UI. Also, a promo which
 	// has already entered Running status will be allowed to continue to reconcile.
 	if promo.Status.Phase != kargoapi.PromotionPhaseRunning {
@@ -294,7 +544,13 @@ func (r *reconciler) Reconcile(
 		logger.Debug("continuing Promotion")
 	}
 
-	promoCtx := logging.ContextWithLogger(ctx, logger)
+	// Steps that are in progress when the controller begins shutting down are
+	// given a grace period to finish, instead of being interrupted at once.
+	promoCtx, cancel := directives.ContextWithShutdownGracePeriod(
+		logging.ContextWithLogger(ctx, logger),
+		r.cfg.ShutdownGracePeriod,
+	)
+	defer cancel()
 
 	newStatus := promo.Status.DeepCopy()
 
@@ -324,16 +580,43 @@ func (r *reconciler) Reconcile(
 		}
 		if promoteErr != nil {
 			newStatus.Phase = kargoapi.PromotionPhaseErrored
-			newStatus.Message = promoteErr.Error()
-			logger.Error(promoteErr, "error executing Promotion")
+			// Errors may quote commands or responses that contain credentials.
+			newStatus.Message = credentials.Redact(promoteErr.Error())
+			logger.Error(errors.New(newStatus.Message), "error executing Promotion")
 		}
 	}()
 
+	// If the Promotion failed after pushing to the Stage's rendered branch, do
+	// whatever the Stage's policy says to do about the commits it pushed.
+	r.cleanUpRenderedBranch(ctx, stage, promo, newStatus)
+
+	changed := !equality.Semantic.DeepEqual(promo.Status, *newStatus)
+	if wasRunning {
+		runningReconciles.WithLabelValues(promo.Namespace, promo.Spec.Stage).Inc()
+		if !changed {
+			wastedReconciles.WithLabelValues(promo.Namespace, promo.Spec.Stage).Inc()
+		}
+	}
+
 	if newStatus.Phase.IsTerminal() {
 		newStatus.FinishedAt = &metav1.Time{Time: time.Now()}
 		logger.Info("promotion", "phase", newStatus.Phase)
 	}
 
+	outcomeReason := kargoapi.ReconcileOutcomeNoProgress
+	if changed {
+		outcomeReason = kargoapi.ReconcileOutcomeProgressed
+	}
+	if outcome, ok := r.observeOutcome(
+		ctx, promo, freight, newStatus, outcomeReason, newStatus.Message,
+	); ok {
+		newStatus.LastReconcileOutcome = outcome
+	}
+
+	// The progress of the Promotion must be recorded even if the controller
+	// began shutting down in the meantime, so that it can be resumed.
+	ctx = context.WithoutCancel(ctx)
+
 	// Record the current refresh token as having been handled.
 	if token, ok := kargoapi.RefreshAnnotationValue(promo.GetAnnotations()); ok {
 		newStatus.LastHandledRefresh = token
@@ -387,6 +670,8 @@ func (r *reconciler) Reconcile(
 			reason = kargoapi.EventReasonPromotionFailed
 		case kargoapi.PromotionPhaseErrored:
 			reason = kargoapi.EventReasonPromotionErrored
+		case kargoapi.PromotionPhaseSkipped:
+			reason = kargoapi.EventReasonPromotionSkipped
 		}
 
 		msg := fmt.Sprintf("Promotion %s", newStatus.Phase)
@@ -397,12 +682,15 @@ func (r *reconciler) Reconcile(
 		eventAnnotations := event.NewPromotionAnnotations(ctx,
 			kargoapi.FormatEventControllerActor(r.cfg.Name()),
 			promo, freight)
+		event.AddStageMetadata(ctx, eventAnnotations, stage)
 
 		if newStatus.Phase == kargoapi.PromotionPhaseSucceeded {
 			eventAnnotations[kargoapi.AnnotationKeyEventVerificationPending] =
 				strconv.FormatBool(stage.Spec.Verification != nil)
 		}
 		r.recorder.AnnotatedEventf(promo, eventAnnotations, corev1.EventTypeNormal, reason, msg)
+
+		r.reportToOriginCommit(ctx, promo, newStatus)
 	}
 
 	if err != nil {
@@ -416,11 +704,134 @@ func (r *reconciler) Reconcile(
 	//
 	// TODO: Make this configurable
 	if newStatus.Phase == kargoapi.PromotionPhaseRunning {
-		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+		return ctrl.Result{
+			RequeueAfter: runningRequeueInterval(
+				newStatus,
+				expectedPromotionDuration(stage.Status.PromotionHistory),
+				changed,
+			),
+		}, nil
 	}
 	return ctrl.Result{}, nil
 }
 
+const (
+	// minRunningRequeueInterval and maxRunningRequeueInterval clamp the
+	// interval after which a Running Promotion is requeued.
+	minRunningRequeueInterval = 5 * time.Second
+	maxRunningRequeueInterval = 5 * time.Minute
+)
+
+// runningRequeueInterval returns the interval after which a Running Promotion
+// with the provided status is to be requeued. A Promotion whose state changed
+// during the last reconciliation is actively progressing, e.g. because an Argo
+// CD Application is being synced, and is checked on again soon. Otherwise, it
+// is checked on around the time Promotions to the same Stage typically take to
+// complete, as indicated by the provided expected duration, and increasingly
+// rarely the longer it takes beyond that. Either way, the interval is clamped
+// between minRunningRequeueInterval and maxRunningRequeueInterval, unless a
+// step that is still running is waiting for an earlier point in time. As
+// changes to Argo CD Applications trigger reconciliations of their own, this
+// is only a fallback.
+func runningRequeueInterval(
+	status *kargoapi.PromotionStatus,
+	expected time.Duration,
+	changed bool,
+) time.Duration {
+	interval := maxRunningRequeueInterval
+	if startedAt := promoStartedAt(status); startedAt != nil {
+		elapsed := time.Since(startedAt.Time)
+		switch {
+		case changed:
+			interval = minRunningRequeueInterval
+		case expected > elapsed:
+			interval = expected - elapsed
+		default:
+			interval = elapsed / 4
+		}
+		interval = min(max(interval, minRunningRequeueInterval), maxRunningRequeueInterval)
+	}
+	for _, meta := range status.StepExecutionMetadata {
+		if meta.Status != kargoapi.PromotionPhaseRunning || meta.WaitUntil == nil {
+			continue
+		}
+		// Requeue slightly after the point in time, so that the step observes
+		// it has been reached.
+		interval = min(interval, max(time.Until(meta.WaitUntil.Time)+time.Second, time.Second))
+	}
+	return interval
+}
+
+// promoStartedAt returns the time at which the first step of a Promotion with
+// the provided status started executing, or nil if none has.
+func promoStartedAt(status *kargoapi.PromotionStatus) *metav1.Time {
+	if len(status.StepExecutionMetadata) == 0 {
+		return nil
+	}
+	return status.StepExecutionMetadata[0].StartedAt
+}
+
+// expectedPromotionDuration returns the median duration of the Promotions
+// recorded in the provided history that succeeded, or zero if there are none.
+func expectedPromotionDuration(history kargoapi.PromotionHistory) time.Duration {
+	var durations []time.Duration
+	for _, record := range history {
+		if record.Phase != kargoapi.PromotionPhaseSucceeded || record.StartedAt == nil ||
+			record.FinishedAt == nil {
+			continue
+		}
+		durations = append(durations, record.FinishedAt.Sub(record.StartedAt.Time))
+	}
+	if len(durations) == 0 {
+		return 0
+	}
+	slices.Sort(durations)
+	return durations[len(durations)/2]
+}
+
+// waitForStage handles a Promotion whose Stage does not exist (yet). Within
+// the configured grace period, the Promotion is requeued with backoff and its
+// status records what it is waiting for. Once the grace period has elapsed,
+// the Promotion is marked as Errored.
+func (r *reconciler) waitForStage(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+) (ctrl.Result, error) {
+	age := time.Since(promo.CreationTimestamp.Time)
+	if age >= r.cfg.StageGracePeriod {
+		if err := kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+			status.Phase = kargoapi.PromotionPhaseErrored
+			status.Message = fmt.Sprintf(
+				"Stage %q in namespace %q was not found within %s",
+				promo.Spec.Stage, promo.Namespace, r.cfg.StageGracePeriod,
+			)
+			status.FinishedAt = &metav1.Time{Time: time.Now()}
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	msg := fmt.Sprintf(
+		"Waiting: ReferentNotFound: Stage %q in namespace %q",
+		promo.Spec.Stage, promo.Namespace,
+	)
+	if promo.Status.Message != msg {
+		if err := kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+			status.Message = msg
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// Back off as the Promotion ages, but never wait longer than a minute or
+	// beyond the end of the grace period.
+	requeueAfter := min(max(age, time.Second), time.Minute, r.cfg.StageGracePeriod-age)
+	logging.LoggerFromContext(ctx).Debug(
+		"Stage not found; waiting",
+		"requeueAfter", requeueAfter,
+	)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
 func (r *reconciler) promote(
 	ctx context.Context,
 	promo kargoapi.Promotion,
@@ -458,6 +869,75 @@ func (r *reconciler) promote(
 	// engine, which may modify its status.
 	workingPromo := promo.DeepCopy()
 	workingPromo.Status.Freight = &targetFreightRef
+
+	// Record a compact digest of the images instead of all of them if there are
+	// too many, so the Promotion and the Stage, which copies its status, stay
+	// well below Kubernetes' object size limit.
+	imageLimits := r.kargoConfig.ImageLimits()
+	workingPromo.Status.Images = kargoapi.NewImageSetDigest(
+		targetFreight.Images,
+		imageLimits.GetStatusMaxImages(),
+	)
+	if len(targetFreight.Images) > imageLimits.GetStatusMaxImages() {
+		statusFreightRef := targetFreightRef
+		statusFreightRef.Images = nil
+		workingPromo.Status.Freight = &statusFreightRef
+	}
+
+	// Before any step has been executed, skip the Promotion if it would move
+	// the Stage back to an older version of any of its images, unless it was
+	// explicitly permitted to.
+	if stage.Spec.PreventDowngrades && !promoStarted(promo) &&
+		!kargoapi.AllowDowngradeAnnotationValue(promo.GetAnnotations()) {
+		if downgrade := findImageDowngrade(stage, targetFreightRef); downgrade != nil {
+			logger.Info("skipping Promotion that would downgrade Stage", "image", downgrade.repoURL)
+			workingPromo.Status.Phase = kargoapi.PromotionPhaseSkipped
+			workingPromo.Status.Message = fmt.Sprintf("OlderThanCurrent: %s", downgrade)
+			return &workingPromo.Status, nil
+		}
+	}
+	// Before any step has been executed, determine which of the subscribed
+	// images the Freight does not carry, and fail the Promotion if the Stage
+	// requires the Freight to carry exactly the subscribed images.
+	if !promoStarted(promo) {
+		retained, msg, err := r.checkImageCompleteness(ctx, stage, targetFreight)
+		if err != nil {
+			return nil, err
+		}
+		if msg != "" {
+			logger.Info("failing Promotion of Freight with incomplete images")
+			workingPromo.Status.Phase = kargoapi.PromotionPhaseFailed
+			workingPromo.Status.Message = msg
+			return &workingPromo.Status, nil
+		}
+		workingPromo.Status.RetainedImages = retained
+	}
+	// Resolve the name of the Stage's rendered branch once, so that all steps
+	// consistently refer to the same branch, even if the Stage is updated while
+	// the Promotion is running.
+	if workingPromo.Status.RenderedBranch == "" || !promoStarted(promo) {
+		renderedBranch, err := kargo.ResolveRenderedBranch(stage)
+		if err != nil {
+			return nil, err
+		}
+		workingPromo.Status.RenderedBranch = renderedBranch
+	}
+	// Likewise, resolve the branches of the Stage's overlays once. Once the
+	// Promotion has started, the commits already pushed to them are retained,
+	// so that the overlays that were promoted are not promoted again.
+	if len(workingPromo.Status.Overlays) == 0 || !promoStarted(promo) {
+		overlays, err := kargo.ResolveOverlays(stage)
+		if err != nil {
+			return nil, err
+		}
+		workingPromo.Status.Overlays = overlays
+	}
+	// Likewise, resolve the identity that the Promotion commits to Git
+	// repositories as once, so that rotating the Stage's identity does not
+	// affect a Promotion that is already running.
+	if workingPromo.Status.GitIdentity == nil || !promoStarted(promo) {
+		workingPromo.Status.GitIdentity = stage.Spec.GitIdentity.DeepCopy()
+	}
 	workingPromo.Status.FreightCollection = r.buildTargetFreightCollection(
 		ctx,
 		targetFreightRef,
@@ -468,17 +948,23 @@ func (r *reconciler) promote(
 	steps := make([]directives.PromotionStep, len(workingPromo.Spec.Steps))
 	for i, step := range workingPromo.Spec.Steps {
 		steps[i] = directives.PromotionStep{
-			Kind:   step.Uses,
-			Alias:  step.As,
-			Retry:  step.Retry,
-			Vars:   step.Vars,
-			Config: step.Config.Raw,
+			Kind:            step.Uses,
+			Alias:           step.As,
+			Retry:           step.Retry,
+			ContinueOnError: step.ContinueOnError,
+			Lane:            step.Lane,
+			Vars:            step.Vars,
+			Config:          step.Config.Raw,
 		}
 	}
 
+	resourceLimits := kargoapi.EffectiveResourceLimits(
+		r.kargoConfig.ResourceLimits(),
+		stage.Spec.ResourceLimits,
+	)
 	promoCtx := directives.PromotionContext{
 		UIBaseURL:             r.cfg.APIServerBaseURL,
-		WorkDir:               filepath.Join(os.TempDir(), "promotion-"+string(workingPromo.UID)),
+		WorkDir:               filepath.Join(r.cfg.workDirRoot(), "promotion-"+string(workingPromo.UID)),
 		Project:               stageNamespace,
 		Stage:                 stageName,
 		Promotion:             workingPromo.Name,
@@ -488,6 +974,51 @@ func (r *reconciler) promote(
 		StepExecutionMetadata: promo.Status.StepExecutionMetadata,
 		State:                 directives.State(workingPromo.Status.GetState()),
 		Vars:                  workingPromo.Spec.Vars,
+		ArgoCDContext:         stage.Spec.ArgoCDContext,
+		ChildCreator:          r.childCreator,
+		Lanes:                 workingPromo.Spec.Lanes,
+		RenderedBranch:        workingPromo.Status.RenderedBranch,
+		Overlays:              workingPromo.Status.Overlays,
+		RenderedBranchPush:    workingPromo.Status.RenderedBranchPush,
+		StageMetadata:         stage.Spec.Metadata,
+		ForceSync:             kargoapi.ForceSyncAnnotationValue(workingPromo.GetAnnotations()),
+		RepoPolicy:            r.kargoConfig.RepoURLPolicy(),
+
+		CommitMessageMaxImages: imageLimits.GetCommitMessageMaxImages(),
+		ImageMappings:          stage.Spec.ImageMappings,
+		ToolVersions:           stage.Spec.ToolVersions,
+		ToolCache:              r.toolCache,
+		ExecPolicy:             r.kargoConfig.ExecPolicy(),
+		MaxRepoSize:            resourceLimits.GetMaxRepoSize(),
+		MaxRenderedOutputSize:  resourceLimits.GetMaxRenderedOutputSize(),
+		RemoteBasePolicy:       r.kargoConfig.RemoteBasePolicy(),
+		DryRunApply:            stage.Spec.DryRunApply,
+		RetainedImages:         workingPromo.Status.RetainedImages,
+		SourceUpdateBatching:   stage.Spec.SourceUpdateBatching,
+		StrictRender:           stage.Spec.StrictRender,
+		RenderWarnings:         workingPromo.Status.RenderWarnings,
+		GitIdentity:            workingPromo.Status.GitIdentity,
+	}
+	if saRef := stage.Spec.ServiceAccountRef; saRef != nil {
+		promoCtx.ServiceAccount = saRef.Name
+	}
+	if rb := stage.Spec.RenderedBranch; rb != nil {
+		promoCtx.RenderedBranchCommit = stage.Status.RenderedBranchCommit
+		promoCtx.OnExternalModification = rb.OnExternalModification
+	}
+	if failAt, ok := kargoapi.FailAtAnnotationValue(workingPromo.GetAnnotations()); ok &&
+		r.cfg.FailureInjectionEnabled {
+		failureInjection, err := directives.ParseFailureInjection(failAt)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error parsing %s annotation: %w", kargoapi.AnnotationKeyFailAt, err,
+			)
+		}
+		logger.Info("injecting failure into Promotion", "failAt", failAt)
+		promoCtx.FailureInjection = failureInjection
+	}
+	if err := r.ensureWorkDirFreeSpace(); err != nil {
+		return nil, err
 	}
 	if err := os.Mkdir(promoCtx.WorkDir, 0o700); err == nil {
 		// If we're working with a fresh directory, we should start the promotion
@@ -514,6 +1045,65 @@ func (r *reconciler) promote(
 	workingPromo.Status.CurrentStep = res.CurrentStep
 	workingPromo.Status.StepExecutionMetadata = res.StepExecutionMetadata
 	workingPromo.Status.State = &apiextensionsv1.JSON{Raw: res.State.ToJSON()}
+	workingPromo.Status.RepoPolicyDecisions = mergeRepoPolicyDecisions(
+		workingPromo.Status.RepoPolicyDecisions,
+		res.RepoPolicyDecisions,
+	)
+	if res.Overlays != nil {
+		workingPromo.Status.Overlays = res.Overlays
+	}
+	if res.RenderedBranchPush != nil {
+		workingPromo.Status.RenderedBranchPush = res.RenderedBranchPush
+	}
+	if res.RetainedImages != nil {
+		workingPromo.Status.RetainedImages = res.RetainedImages
+	}
+	if res.SourceUpdateBatch != nil {
+		workingPromo.Status.SourceUpdateBatch = res.SourceUpdateBatch
+	}
+	r.recordRenderWarnings(ctx, workingPromo, targetFreight, res.RenderWarnings)
+	if res.ExternalModification != nil {
+		workingPromo.Status.ExternalModification = res.ExternalModification
+	}
+	if res.SyncCondition != nil {
+		syncCondition := *res.SyncCondition
+		syncCondition.ObservedGeneration = workingPromo.Generation
+		conditions.Set(&workingPromo.Status, &syncCondition)
+	}
+	if res.OutputIgnoredCondition != nil {
+		outputIgnored := *res.OutputIgnoredCondition
+		outputIgnored.ObservedGeneration = workingPromo.Generation
+		conditions.Set(&workingPromo.Status, &outputIgnored)
+	}
+	if workingPromo.Status.Phase == kargoapi.PromotionPhaseSucceeded {
+		if msg := toleratedStepFailuresMessage(workingPromo.Status.StepExecutionMetadata); msg != "" {
+			conditions.Set(&workingPromo.Status, &metav1.Condition{
+				Type:               kargoapi.ConditionTypeStepsFailed,
+				Status:             metav1.ConditionTrue,
+				Reason:             "ContinuedOnError",
+				Message:            msg,
+				ObservedGeneration: workingPromo.Generation,
+			})
+		}
+	}
+	if res.Transcript != "" {
+		// Failing to record the transcript must not prevent the Promotion's
+		// outcome from being recorded.
+		if name, err := r.saveTranscript(ctx, workingPromo, res.Transcript); err != nil {
+			logger.Error(err, "error saving transcript of Promotion")
+			if errors.Is(err, controller.ErrChildLimitExceeded) {
+				conditions.Set(&workingPromo.Status, &metav1.Condition{
+					Type:               kargoapi.ConditionTypeChildLimitExceeded,
+					Status:             metav1.ConditionTrue,
+					Reason:             "TranscriptNotSaved",
+					Message:            err.Error(),
+					ObservedGeneration: workingPromo.Generation,
+				})
+			}
+		} else {
+			workingPromo.Status.TranscriptConfigMap = name
+		}
+	}
 	for _, step := range res.HealthCheckSteps {
 		workingPromo.Status.HealthChecks = append(
 			workingPromo.Status.HealthChecks,
@@ -525,6 +1115,16 @@ func (r *reconciler) promote(
 	}
 	if err != nil {
 		workingPromo.Status.Phase = kargoapi.PromotionPhaseErrored
+		var notAllowed *directives.RepoNotAllowedError
+		if errors.As(err, &notAllowed) {
+			logger.Info(
+				"Promotion attempted to access a repository that is not allowed",
+				"repo", notAllowed.RepoURL,
+				"rule", notAllowed.Decision.Rule,
+			)
+			workingPromo.Status.Message = fmt.Sprintf("RepoNotAllowed: %s", notAllowed)
+			return &workingPromo.Status, nil
+		}
 		return &workingPromo.Status, err
 	}
 
@@ -558,6 +1158,280 @@ func (r *reconciler) promote(
 	return &workingPromo.Status, nil
 }
 
+// saveTranscript creates a ConfigMap holding the provided transcript of the
+// commands executed by the provided Promotion and returns its name. The
+// ConfigMap is created by the childCreator, so that it is deleted along with
+// the Promotion and counts towards the limit of objects created on its behalf.
+// If the ConfigMap already exists, for instance because the status of the
+// Promotion could not be updated after it was created, it is left as is.
+func (r *reconciler) saveTranscript(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	transcript string,
+) (string, error) {
+	name := promo.Name
+	if maxLen := validation.DNS1123SubdomainMaxLength - len(transcriptConfigMapSuffix); len(name) > maxLen {
+		name = strings.TrimRight(name[:maxLen], ".-")
+	}
+	name += transcriptConfigMapSuffix
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Data:       map[string]string{transcriptDataKey: transcript},
+	}
+	if err := r.childCreator.Create(
+		ctx, promo, transcriptComponent, cm,
+	); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf(
+			"error creating ConfigMap %q in namespace %q: %w",
+			name, promo.Namespace, err,
+		)
+	}
+	return name, nil
+}
+
+// checkApproval records the approval of the provided Promotion, which requires
+// approval, in its status and returns true if the Promotion has been validly
+// approved. If it has not been approved yet, its status is updated to explain
+// who may approve it. If the recorded approval was not given for the
+// Promotion's current spec, the approval is invalid and the Promotion fails.
+func (r *reconciler) checkApproval(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+) (bool, error) {
+	logger := logging.LoggerFromContext(ctx)
+	approval := promo.Status.Approval
+	if approval == nil {
+		req, ok := kargoapi.ApprovePromotionAnnotationValue(promo.GetAnnotations())
+		if !ok || req.Actor == "" {
+			logger.Debug("Promotion has not been approved; skipping")
+			msg := awaitingApprovalMessage(promo.Spec.Approval)
+			if promo.Status.Message != msg || promo.Status.AwaitingApprovalSince == nil {
+				if err := kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+					status.Message = msg
+					// The time the Promotion began waiting is recorded once,
+					// so that its deadline does not move.
+					if status.AwaitingApprovalSince == nil {
+						status.AwaitingApprovalSince = &metav1.Time{Time: time.Now()}
+					}
+				}); err != nil {
+					return false, err
+				}
+			}
+			// Setting the approve annotation requeues the Promotion.
+			return false, nil
+		}
+		approval = &kargoapi.PromotionApproval{
+			Approver:   req.Actor,
+			ApprovedAt: req.ApprovedAt,
+			SpecHash:   req.SpecHash,
+		}
+		if err := kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+			status.Approval = approval
+			status.Message = ""
+		}); err != nil {
+			return false, err
+		}
+		logger.Info("Promotion approved", "approver", approval.Approver)
+	}
+	// The spec of a Promotion can not be changed once it has been created, so
+	// this only guards against an approval that was recorded or tampered with
+	// by other means than the webhook, e.g. by writing to the status directly.
+	if approval.SpecHash != promo.Spec.Hash() {
+		if err := kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+			status.Phase = kargoapi.PromotionPhaseFailed
+			status.Message = fmt.Sprintf(
+				"ApprovalInvalidated: the approval by %s was not given for the current spec of the Promotion",
+				approval.Approver,
+			)
+			status.FinishedAt = &metav1.Time{Time: time.Now()}
+		}); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// expireUnapproved expires the provided Promotion, which is waiting to be
+// approved, if it has been waiting for longer than the MaxAge of the approval
+// policy it is subject to, extended by its AnnotationKeyExtendExpiry
+// annotation. If it has not been waiting for that long yet, the returned
+// result requeues it for when it does. Promotions that have already begun
+// executing steps never expire.
+func (r *reconciler) expireUnapproved(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	freight *kargoapi.Freight,
+) (ctrl.Result, error) {
+	logger := logging.LoggerFromContext(ctx)
+	if promo.Status.Phase.IsTerminal() || promoStarted(*promo) {
+		return ctrl.Result{}, nil
+	}
+	deadline, ok := approvalDeadline(promo)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	if remaining := time.Until(deadline); remaining > 0 {
+		// The approve annotation requeues the Promotion if it is approved in
+		// the meantime.
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	logger.Info("expiring Promotion that was not approved in time", "deadline", deadline)
+
+	newStatus := promo.Status.DeepCopy()
+	newStatus.Phase = kargoapi.PromotionPhaseExpired
+	newStatus.Message = fmt.Sprintf(
+		"NotApprovedInTime: the Promotion was not approved by its deadline of %s",
+		deadline.UTC().Format(time.RFC3339),
+	)
+	newStatus.FinishedAt = &metav1.Time{Time: time.Now()}
+
+	if err := kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+		*status = *newStatus
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.recorder.AnnotatedEventf(
+		promo,
+		event.NewPromotionAnnotations(ctx, kargoapi.FormatEventControllerActor(r.cfg.Name()), promo, freight),
+		corev1.EventTypeNormal,
+		kargoapi.EventReasonPromotionExpired,
+		fmt.Sprintf("Promotion %s: %s", newStatus.Phase, newStatus.Message),
+	)
+
+	return ctrl.Result{}, nil
+}
+
+// approvalDeadline returns the time by which the provided Promotion must be
+// approved before it expires, and a boolean indicating whether there is such a
+// deadline. There is none if the Promotion's approval policy does not specify a
+// MaxAge, or if the Promotion has not begun waiting for approval yet.
+func approvalDeadline(promo *kargoapi.Promotion) (time.Time, bool) {
+	policy := promo.Spec.Approval
+	since := promo.Status.AwaitingApprovalSince
+	if policy == nil || policy.MaxAge == nil || since == nil {
+		return time.Time{}, false
+	}
+	deadline := since.Add(policy.MaxAge.Duration)
+	if extension, ok := kargoapi.ExtendExpiryAnnotationValue(promo.GetAnnotations()); ok {
+		deadline = deadline.Add(extension)
+	}
+	return deadline, true
+}
+
+// awaitingApprovalMessage returns a message explaining that a Promotion is
+// waiting to be approved by one of the allowed approvers of the provided
+// policy, and how it can be approved.
+func awaitingApprovalMessage(policy *kargoapi.PromotionApprovalPolicy) string {
+	approvers := make([]string, len(policy.AllowedApprovers))
+	for i, approver := range policy.AllowedApprovers {
+		if approver.Namespace != "" {
+			approvers[i] = fmt.Sprintf("%s %s/%s", approver.Kind, approver.Namespace, approver.Name)
+		} else {
+			approvers[i] = fmt.Sprintf("%s %s", approver.Kind, approver.Name)
+		}
+	}
+	return fmt.Sprintf(
+		"Waiting for approval by one of: %s; set the annotation %s=%s on the Promotion to approve it",
+		strings.Join(approvers, ", "), kargoapi.AnnotationKeyApprove, kargoapi.AnnotationValueTrue,
+	)
+}
+
+// toleratedStepFailuresMessage returns a message naming the steps described by
+// the provided metadata that failed or errored, along with why, or an empty
+// string if none did. It is meant for Promotions that succeeded, in which case
+// the failures of any such steps were tolerated because the steps were
+// configured to continue on error.
+func toleratedStepFailuresMessage(metas kargoapi.StepExecutionMetadataList) string {
+	var failures []string
+	for i, meta := range metas {
+		switch meta.Status {
+		case kargoapi.PromotionPhaseErrored, kargoapi.PromotionPhaseFailed:
+		default:
+			continue
+		}
+		step := fmt.Sprintf("step %d", i)
+		if meta.Alias != "" {
+			step = fmt.Sprintf("step %q", meta.Alias)
+		}
+		if meta.Message != "" {
+			step += ": " + meta.Message
+		}
+		failures = append(failures, step)
+	}
+	if len(failures) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(
+		"%d step(s) failed but were configured to continue on error: %s",
+		len(failures), strings.Join(failures, "; "),
+	)
+}
+
+// promoStarted returns true if the execution of any of the steps of the
+// provided Promotion has started.
+func promoStarted(promo kargoapi.Promotion) bool {
+	return promo.Status.CurrentStep > 0 || len(promo.Status.StepExecutionMetadata) > 0
+}
+
+// driftBlockedMessage returns a message explaining that a Promotion is blocked
+// by the provided Drift, and how the drift can be resolved.
+func driftBlockedMessage(drift *kargoapi.Drift) string {
+	msg := fmt.Sprintf(
+		"Branch %q of repository %s has drifted from what was last promoted "+
+			"(commit %s); set the annotation %s=%s on the Stage to acknowledge the drift",
+		drift.Branch, drift.RepoURL, drift.DriftedCommit,
+		kargoapi.AnnotationKeyAcknowledgeDrift, drift.DriftedCommit,
+	)
+	if drift.PullRequest != nil && drift.PullRequest.URL != "" {
+		msg += fmt.Sprintf(", or merge pull request %s", drift.PullRequest.URL)
+	}
+	return msg
+}
+
+// mergeRepoPolicyDecisions returns the provided RepoPolicyDecisions, updated
+// with the provided newer ones. Newer decisions replace older ones for the same
+// repository.
+func mergeRepoPolicyDecisions(
+	decisions []kargoapi.RepoPolicyDecision,
+	newer []kargoapi.RepoPolicyDecision,
+) []kargoapi.RepoPolicyDecision {
+	for _, n := range newer {
+		i := slices.IndexFunc(decisions, func(d kargoapi.RepoPolicyDecision) bool {
+			return d.RepoURL == n.RepoURL
+		})
+		if i < 0 {
+			decisions = append(decisions, n)
+			continue
+		}
+		decisions[i] = n
+	}
+	return decisions
+}
+
+// ensureWorkDirFreeSpace returns an error if less than the configured minimum
+// amount of free space is available in the directory under which Promotion
+// working directories are created.
+func (r *reconciler) ensureWorkDirFreeSpace() error {
+	if r.cfg.WorkDirMinFreeMiB == 0 {
+		return nil
+	}
+	root := r.cfg.workDirRoot()
+	free, err := libos.FreeSpace(root)
+	if err != nil {
+		return fmt.Errorf("error determining free space in %s: %w", root, err)
+	}
+	if freeMiB := free / (1024 * 1024); freeMiB < r.cfg.WorkDirMinFreeMiB {
+		return fmt.Errorf(
+			"insufficient free space in %s: %d MiB available, %d MiB required",
+			root, freeMiB, r.cfg.WorkDirMinFreeMiB,
+		)
+	}
+	return nil
+}
+
 // buildTargetFreightCollection constructs a FreightCollection that contains all
 // FreightReferences from the previous Promotion (excepting those that are no
 // longer requested), plus a FreightReference for the provided targetFreight.
@@ -3,10 +3,15 @@ package promotions
 import (
 	"context"
 	"errors"
+	"math"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -17,6 +22,8 @@ import (
 
 	"github.com/akuity/kargo/api/v1alpha1"
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller"
+	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/directives"
 	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
 )
@@ -32,13 +39,17 @@ func TestNewPromotionReconciler(t *testing.T) {
 		kubeClient,
 		&fakeevent.EventRecorder{},
 		&directives.FakeEngine{},
+		&credentials.FakeDB{},
+		nil,
 		ReconcilerConfig{},
 	)
 	require.NotNil(t, r.kargoClient)
 	require.NotNil(t, r.recorder)
 	require.NotNil(t, r.directivesEngine)
+	require.NotNil(t, r.credentialsDB)
 	require.NotNil(t, r.getStageFn)
 	require.NotNil(t, r.promoteFn)
+	require.NotNil(t, r.newGitProviderFn)
 }
 
 func newFakeReconciler(
@@ -54,6 +65,8 @@ func newFakeReconciler(
 		kargoClient,
 		recorder,
 		&directives.FakeEngine{},
+		&credentials.FakeDB{},
+		nil,
 		ReconcilerConfig{},
 	)
 }
@@ -66,14 +79,18 @@ func TestReconcile(t *testing.T) {
 			*v1alpha1.Freight) (*kargoapi.PromotionStatus, error)
 		terminateFn             func(context.Context, *kargoapi.Promotion) error
 		promoToReconcile        *types.NamespacedName // if nil, uses the first of the promos
+		paused                  bool
 		expectPromoteFnCalled   bool
 		expectTerminateFnCalled bool
 		expectedPhase           kargoapi.PromotionPhase
+		expectedMessage         string
 		expectedEventRecorded   bool
 		expectedEventReason     string
+		expectedOutcome         kargoapi.ReconcileOutcomeReason
 	}{
 		{
 			name:                  "normal reconcile",
+			expectedOutcome:       kargoapi.ReconcileOutcomeCompleted,
 			expectPromoteFnCalled: true,
 			expectedPhase:         kargoapi.PromotionPhaseSucceeded,
 			expectedEventRecorded: true,
@@ -111,6 +128,7 @@ func TestReconcile(t *testing.T) {
 		},
 		{
 			name:                  "promo already running",
+			expectedOutcome:       kargoapi.ReconcileOutcomeCompleted,
 			expectPromoteFnCalled: true,
 			expectedPhase:         kargoapi.PromotionPhaseSucceeded,
 			expectedEventRecorded: true,
@@ -133,6 +151,7 @@ func TestReconcile(t *testing.T) {
 		},
 		{
 			name:                  "promo does not have highest priority",
+			expectedOutcome:       kargoapi.ReconcileOutcomeQueued,
 			expectPromoteFnCalled: false,
 			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo2"},
 			expectedPhase:         kargoapi.PromotionPhasePending,
@@ -154,6 +173,7 @@ func TestReconcile(t *testing.T) {
 		},
 		{
 			name:                  "promo has highest priority",
+			expectedOutcome:       kargoapi.ReconcileOutcomeCompleted,
 			expectPromoteFnCalled: true,
 			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo1"},
 			expectedPhase:         kargoapi.PromotionPhaseSucceeded,
@@ -177,6 +197,7 @@ func TestReconcile(t *testing.T) {
 		},
 		{
 			name:                  "stage not awaiting promo",
+			expectedOutcome:       kargoapi.ReconcileOutcomeQueued,
 			expectPromoteFnCalled: false,
 			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
 			expectedPhase:         kargoapi.PromotionPhasePending,
@@ -196,8 +217,113 @@ func TestReconcile(t *testing.T) {
 				newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now),
 			},
 		},
+		{
+			name:                  "promotions paused globally",
+			expectedOutcome:       kargoapi.ReconcileOutcomePaused,
+			paused:                true,
+			expectPromoteFnCalled: false,
+			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
+			expectedPhase:         kargoapi.PromotionPhaseRunning,
+			expectedEventRecorded: false,
+			promos: []client.Object{
+				&kargoapi.Stage{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fake-stage",
+						Namespace: "fake-namespace",
+					},
+					Status: kargoapi.StageStatus{
+						CurrentPromotion: &kargoapi.PromotionReference{
+							Name: "fake-promo",
+						},
+					},
+				},
+				newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhaseRunning, now),
+			},
+		},
+		{
+			name:                  "promotions paused for stage",
+			expectedOutcome:       kargoapi.ReconcileOutcomePaused,
+			expectPromoteFnCalled: false,
+			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
+			expectedPhase:         kargoapi.PromotionPhasePending,
+			expectedEventRecorded: false,
+			promos: []client.Object{
+				&kargoapi.Stage{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fake-stage",
+						Namespace: "fake-namespace",
+						Annotations: map[string]string{
+							kargoapi.AnnotationKeyPausePromotions: kargoapi.AnnotationValueTrue,
+						},
+					},
+					Status: kargoapi.StageStatus{
+						CurrentPromotion: &kargoapi.PromotionReference{
+							Name: "fake-promo",
+						},
+					},
+				},
+				newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now),
+			},
+		},
+		{
+			name:                  "stage has unresolved drift",
+			expectedOutcome:       kargoapi.ReconcileOutcomeDriftBlocked,
+			expectPromoteFnCalled: false,
+			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
+			expectedPhase:         kargoapi.PromotionPhasePending,
+			expectedEventRecorded: false,
+			promos: []client.Object{
+				&kargoapi.Stage{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fake-stage",
+						Namespace: "fake-namespace",
+					},
+					Status: kargoapi.StageStatus{
+						CurrentPromotion: &kargoapi.PromotionReference{
+							Name: "fake-promo",
+						},
+						Drift: &kargoapi.Drift{
+							RepoURL:       "https://github.com/example/repo.git",
+							Branch:        "stage/fake-stage",
+							DriftedCommit: "fake-commit",
+						},
+					},
+				},
+				newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now),
+			},
+		},
+		{
+			name:                  "stage has acknowledged drift",
+			expectedOutcome:       kargoapi.ReconcileOutcomeCompleted,
+			expectPromoteFnCalled: true,
+			promoToReconcile:      &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
+			expectedPhase:         kargoapi.PromotionPhaseSucceeded,
+			expectedEventRecorded: true,
+			expectedEventReason:   kargoapi.EventReasonPromotionSucceeded,
+			promos: []client.Object{
+				&kargoapi.Stage{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fake-stage",
+						Namespace: "fake-namespace",
+					},
+					Status: kargoapi.StageStatus{
+						CurrentPromotion: &kargoapi.PromotionReference{
+							Name: "fake-promo",
+						},
+						Drift: &kargoapi.Drift{
+							RepoURL:       "https://github.com/example/repo.git",
+							Branch:        "stage/fake-stage",
+							DriftedCommit: "fake-commit",
+							Resolution:    kargoapi.DriftResolutionAcknowledged,
+						},
+					},
+				},
+				newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now),
+			},
+		},
 		{
 			name:                  "promoteFn panics",
+			expectedOutcome:       kargoapi.ReconcileOutcomeCompleted,
 			expectPromoteFnCalled: true,
 			expectedPhase:         kargoapi.PromotionPhaseErrored,
 			expectedEventRecorded: true,
@@ -223,8 +349,10 @@ func TestReconcile(t *testing.T) {
 		},
 		{
 			name:                  "promoteFn errors",
+			expectedOutcome:       kargoapi.ReconcileOutcomeCompleted,
 			expectPromoteFnCalled: true,
 			expectedPhase:         kargoapi.PromotionPhaseErrored,
+			expectedMessage:       "expected error: Authorization: Bearer ***",
 			expectedEventRecorded: true,
 			expectedEventReason:   kargoapi.EventReasonPromotionErrored,
 			promos: []client.Object{
@@ -243,11 +371,12 @@ func TestReconcile(t *testing.T) {
 			},
 			promoToReconcile: &types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
 			promoteFn: func(_ context.Context, _ v1alpha1.Promotion, _ *v1alpha1.Freight) (*kargoapi.PromotionStatus, error) {
-				return nil, errors.New("expected error")
+				return nil, errors.New("expected error: Authorization: Bearer fake-token")
 			},
 		},
 		{
-			name: "terminates promotion on request",
+			name:            "terminates promotion on request",
+			expectedOutcome: kargoapi.ReconcileOutcomeCompleted,
 			promos: []client.Object{
 				func() *kargoapi.Promotion {
 					p := newPromo(
@@ -270,8 +399,9 @@ func TestReconcile(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.TODO()
-			recorder := fakeevent.NewEventRecorder(1)
+			recorder := fakeevent.NewEventRecorder(10)
 			r := newFakeReconciler(t, recorder, tc.promos...)
+			r.cfg.Paused = tc.paused
 
 			promoteWasCalled := false
 			r.promoteFn = func(
@@ -318,6 +448,7 @@ func TestReconcile(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, tc.expectPromoteFnCalled, promoteWasCalled,
 				"promoteFn called: %t, expected %t", promoteWasCalled, tc.expectPromoteFnCalled)
+
 			require.Equal(t, tc.expectTerminateFnCalled, terminateWasCalled,
 				"terminateFn called: %t, expected %t", terminateWasCalled, tc.expectTerminateFnCalled)
 
@@ -326,16 +457,309 @@ func TestReconcile(t *testing.T) {
 				err = r.kargoClient.Get(ctx, req.NamespacedName, &updatedPromo)
 				require.NoError(t, err)
 				require.Equal(t, tc.expectedPhase, updatedPromo.Status.Phase)
+				if tc.expectedMessage != "" {
+					require.Equal(t, tc.expectedMessage, updatedPromo.Status.Message)
+				}
+				if tc.expectedOutcome != "" {
+					require.NotNil(t, updatedPromo.Status.LastReconcileOutcome)
+					require.Equal(t, tc.expectedOutcome, updatedPromo.Status.LastReconcileOutcome.Reason)
+				}
+				var reasons []string
+				for len(recorder.Events) > 0 {
+					reasons = append(reasons, (<-recorder.Events).Reason)
+				}
 				if tc.expectedEventRecorded {
-					require.Len(t, recorder.Events, 1)
-					event := <-recorder.Events
-					require.Equal(t, tc.expectedEventReason, event.Reason)
+					require.Contains(t, reasons, tc.expectedEventReason)
+				}
+				if tc.expectedOutcome != "" {
+					require.Contains(t, reasons, kargoapi.EventReasonPromotionReconcileOutcome)
 				}
 			}
 		})
 	}
 }
 
+func TestReconcile_stageNotFound(t *testing.T) {
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
+	}
+
+	t.Run("waits within grace period", func(t *testing.T) {
+		r := newFakeReconciler(
+			t,
+			fakeevent.NewEventRecorder(1),
+			newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now),
+		)
+		r.cfg.StageGracePeriod = time.Minute
+		res, err := r.Reconcile(context.TODO(), req)
+		require.NoError(t, err)
+		require.Greater(t, res.RequeueAfter, time.Duration(0))
+		require.LessOrEqual(t, res.RequeueAfter, time.Minute)
+
+		promo := &kargoapi.Promotion{}
+		require.NoError(t, r.kargoClient.Get(context.TODO(), req.NamespacedName, promo))
+		require.Equal(t, kargoapi.PromotionPhasePending, promo.Status.Phase)
+		require.Contains(t, promo.Status.Message, "Waiting: ReferentNotFound")
+	})
+
+	t.Run("errors after grace period", func(t *testing.T) {
+		r := newFakeReconciler(
+			t,
+			fakeevent.NewEventRecorder(1),
+			newPromo(
+				"fake-namespace",
+				"fake-promo",
+				"fake-stage",
+				kargoapi.PromotionPhasePending,
+				metav1.Time{Time: now.Add(-time.Hour)},
+			),
+		)
+		r.cfg.StageGracePeriod = time.Minute
+		res, err := r.Reconcile(context.TODO(), req)
+		require.NoError(t, err)
+		require.Zero(t, res.RequeueAfter)
+
+		promo := &kargoapi.Promotion{}
+		require.NoError(t, r.kargoClient.Get(context.TODO(), req.NamespacedName, promo))
+		require.Equal(t, kargoapi.PromotionPhaseErrored, promo.Status.Phase)
+		require.Contains(t, promo.Status.Message, "was not found within")
+		require.NotNil(t, promo.Status.FinishedAt)
+	})
+
+	t.Run("proceeds once Stage appears", func(t *testing.T) {
+		recorder := fakeevent.NewEventRecorder(10)
+		r := newFakeReconciler(
+			t,
+			recorder,
+			newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now),
+		)
+		r.cfg.StageGracePeriod = time.Minute
+		var promoteWasCalled bool
+		r.promoteFn = func(
+			context.Context,
+			v1alpha1.Promotion,
+			*v1alpha1.Stage,
+			*v1alpha1.Freight,
+		) (*kargoapi.PromotionStatus, error) {
+			promoteWasCalled = true
+			return &kargoapi.PromotionStatus{Phase: kargoapi.PromotionPhaseSucceeded}, nil
+		}
+
+		_, err := r.Reconcile(context.TODO(), req)
+		require.NoError(t, err)
+		require.False(t, promoteWasCalled)
+
+		stage := &kargoapi.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-stage",
+				Namespace: "fake-namespace",
+			},
+			Status: kargoapi.StageStatus{
+				CurrentPromotion: &kargoapi.PromotionReference{Name: "fake-promo"},
+			},
+		}
+		require.NoError(t, r.kargoClient.Create(context.TODO(), stage))
+
+		_, err = r.Reconcile(context.TODO(), req)
+		require.NoError(t, err)
+		require.True(t, promoteWasCalled)
+
+		promo := &kargoapi.Promotion{}
+		require.NoError(t, r.kargoClient.Get(context.TODO(), req.NamespacedName, promo))
+		require.Equal(t, kargoapi.PromotionPhaseSucceeded, promo.Status.Phase)
+		require.Equal(t, kargoapi.ReconcileOutcomeCompleted, promo.Status.LastReconcileOutcome.Reason)
+
+		// Each change of the outcome is reported, in order.
+		var outcomes []string
+		for len(recorder.Events) > 0 {
+			if e := <-recorder.Events; e.Reason == kargoapi.EventReasonPromotionReconcileOutcome {
+				outcomes = append(outcomes, e.Message)
+			}
+		}
+		require.Len(t, outcomes, 2)
+		require.True(t, strings.HasPrefix(outcomes[0], string(kargoapi.ReconcileOutcomeStageNotFound)+": "))
+		require.Equal(t, "Completed: Promotion Succeeded", outcomes[1])
+	})
+}
+
+func TestReconcilerConfig_workDirRoot(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		require.Equal(t, os.TempDir(), ReconcilerConfig{}.workDirRoot())
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		require.Equal(
+			t,
+			"/var/lib/kargo",
+			ReconcilerConfig{WorkDir: "/var/lib/kargo"}.workDirRoot(),
+		)
+	})
+}
+
+func Test_mergeRepoPolicyDecisions(t *testing.T) {
+	require.Nil(t, mergeRepoPolicyDecisions(nil, nil))
+	require.Equal(
+		t,
+		[]kargoapi.RepoPolicyDecision{
+			{RepoURL: "https://github.com/example/a", Allowed: true, Rule: "new"},
+			{RepoURL: "https://github.com/example/b", Allowed: true},
+			{RepoURL: "https://github.com/example/c"},
+		},
+		mergeRepoPolicyDecisions(
+			[]kargoapi.RepoPolicyDecision{
+				{RepoURL: "https://github.com/example/a", Allowed: true, Rule: "old"},
+				{RepoURL: "https://github.com/example/b", Allowed: true},
+			},
+			[]kargoapi.RepoPolicyDecision{
+				{RepoURL: "https://github.com/example/c"},
+				{RepoURL: "https://github.com/example/a", Allowed: true, Rule: "new"},
+			},
+		),
+	)
+}
+
+func Test_toleratedStepFailuresMessage(t *testing.T) {
+	require.Empty(t, toleratedStepFailuresMessage(nil))
+	require.Empty(t, toleratedStepFailuresMessage(kargoapi.StepExecutionMetadataList{
+		{Alias: "step-1", Status: kargoapi.PromotionPhaseSucceeded},
+	}))
+	require.Equal(
+		t,
+		"2 step(s) failed but were configured to continue on error: "+
+			`step "post-hook": Job "fake-job" failed; step 2`,
+		toleratedStepFailuresMessage(kargoapi.StepExecutionMetadataList{
+			{Alias: "step-1", Status: kargoapi.PromotionPhaseSucceeded},
+			{
+				Alias:   "post-hook",
+				Status:  kargoapi.PromotionPhaseFailed,
+				Message: `Job "fake-job" failed`,
+			},
+			{Status: kargoapi.PromotionPhaseErrored},
+		}),
+	)
+}
+
+func Test_runningRequeueInterval(t *testing.T) {
+	require.Equal(t, 5*time.Minute, runningRequeueInterval(&kargoapi.PromotionStatus{}, 0, false))
+	interval := runningRequeueInterval(&kargoapi.PromotionStatus{
+		StepExecutionMetadata: kargoapi.StepExecutionMetadataList{
+			{
+				// Finished steps are disregarded.
+				Status:    kargoapi.PromotionPhaseSucceeded,
+				WaitUntil: &metav1.Time{Time: time.Now().Add(time.Second)},
+			},
+			{
+				Status:    kargoapi.PromotionPhaseRunning,
+				WaitUntil: &metav1.Time{Time: time.Now().Add(time.Minute)},
+			},
+		},
+	}, 0, false)
+	require.Greater(t, interval, 55*time.Second)
+	require.LessOrEqual(t, interval, time.Minute+time.Second)
+	require.Equal(t, time.Second, runningRequeueInterval(&kargoapi.PromotionStatus{
+		StepExecutionMetadata: kargoapi.StepExecutionMetadataList{{
+			Status:    kargoapi.PromotionPhaseRunning,
+			WaitUntil: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+		}},
+	}, 0, false))
+
+	started := func(ago time.Duration) *kargoapi.PromotionStatus {
+		return &kargoapi.PromotionStatus{
+			StepExecutionMetadata: kargoapi.StepExecutionMetadataList{{
+				Status:    kargoapi.PromotionPhaseRunning,
+				StartedAt: &metav1.Time{Time: time.Now().Add(-ago)},
+			}},
+		}
+	}
+	// A Promotion that is progressing is checked on again soon.
+	require.Equal(t, minRunningRequeueInterval, runningRequeueInterval(started(time.Minute), 3*time.Minute, true))
+	// A Promotion that is not progressing is checked on around the time
+	// Promotions typically complete.
+	interval = runningRequeueInterval(started(time.Minute), 3*time.Minute, false)
+	require.Greater(t, interval, 115*time.Second)
+	require.LessOrEqual(t, interval, 2*time.Minute)
+	// Beyond that, it is checked on increasingly rarely.
+	interval = runningRequeueInterval(started(4*time.Minute), 3*time.Minute, false)
+	require.Greater(t, interval, 55*time.Second)
+	require.LessOrEqual(t, interval, time.Minute+time.Second)
+	require.Equal(t, maxRunningRequeueInterval, runningRequeueInterval(started(time.Hour), 3*time.Minute, false))
+	// Intervals are never shorter than the minimum.
+	require.Equal(t, minRunningRequeueInterval, runningRequeueInterval(started(time.Second), 0, false))
+}
+
+func Test_expectedPromotionDuration(t *testing.T) {
+	require.Zero(t, expectedPromotionDuration(nil))
+	record := func(phase kargoapi.PromotionPhase, d time.Duration) kargoapi.PromotionRecord {
+		return kargoapi.PromotionRecord{
+			Phase:      phase,
+			StartedAt:  &metav1.Time{Time: now.Add(-d)},
+			FinishedAt: &now,
+		}
+	}
+	require.Equal(t, 2*time.Minute, expectedPromotionDuration(kargoapi.PromotionHistory{
+		record(kargoapi.PromotionPhaseSucceeded, 3*time.Minute),
+		// Promotions that did not succeed are disregarded.
+		record(kargoapi.PromotionPhaseFailed, 10*time.Second),
+		record(kargoapi.PromotionPhaseSucceeded, time.Minute),
+		record(kargoapi.PromotionPhaseSucceeded, 2*time.Minute),
+		{Phase: kargoapi.PromotionPhaseSucceeded},
+	}))
+}
+
+func Test_reconciler_ensureWorkDirFreeSpace(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        ReconcilerConfig
+		assertions func(*testing.T, error)
+	}{
+		{
+			name: "check disabled",
+			cfg: ReconcilerConfig{
+				WorkDir: "/does/not/exist",
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "work dir does not exist",
+			cfg: ReconcilerConfig{
+				WorkDir:           "/does/not/exist",
+				WorkDirMinFreeMiB: 1,
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error determining free space")
+			},
+		},
+		{
+			name: "insufficient free space",
+			cfg: ReconcilerConfig{
+				WorkDir:           t.TempDir(),
+				WorkDirMinFreeMiB: math.MaxUint64 / (1024 * 1024),
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "insufficient free space")
+			},
+		},
+		{
+			name: "sufficient free space",
+			cfg: ReconcilerConfig{
+				WorkDir:           t.TempDir(),
+				WorkDirMinFreeMiB: 1,
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reconciler{cfg: tt.cfg}
+			tt.assertions(t, r.ensureWorkDirFreeSpace())
+		})
+	}
+}
+
 func Test_reconciler_terminatePromotion(t *testing.T) {
 	scheme := k8sruntime.NewScheme()
 	require.NoError(t, kargoapi.SchemeBuilder.AddToScheme(scheme))
@@ -463,6 +887,282 @@ func Test_reconciler_terminatePromotion(t *testing.T) {
 	}
 }
 
+func Test_reconciler_checkApproval(t *testing.T) {
+	scheme := k8sruntime.NewScheme()
+	require.NoError(t, kargoapi.SchemeBuilder.AddToScheme(scheme))
+
+	approvedAt := metav1.NewTime(now.Add(-time.Minute).Truncate(time.Second))
+	newApprovalPromo := func() *kargoapi.Promotion {
+		promo := newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now)
+		promo.Spec.Approval = &kargoapi.PromotionApprovalPolicy{
+			AllowedApprovers: []kargoapi.PromotionApprover{{
+				Kind: kargoapi.PromotionApproverKindGroup,
+				Name: "prod-approvers",
+			}},
+		}
+		return promo
+	}
+
+	tests := []struct {
+		name       string
+		promo      func() *kargoapi.Promotion
+		assertions func(*testing.T, *kargoapi.Promotion, bool, error)
+	}{
+		{
+			name:  "not approved",
+			promo: newApprovalPromo,
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, approved bool, err error) {
+				require.NoError(t, err)
+				require.False(t, approved)
+				require.Nil(t, promo.Status.Approval)
+				require.Equal(t, kargoapi.PromotionPhasePending, promo.Status.Phase)
+				require.Contains(t, promo.Status.Message, "Waiting for approval by one of: Group prod-approvers")
+				require.NotNil(t, promo.Status.AwaitingApprovalSince)
+			},
+		},
+		{
+			name: "time at which the Promotion began waiting is not changed",
+			promo: func() *kargoapi.Promotion {
+				promo := newApprovalPromo()
+				promo.Status.AwaitingApprovalSince = &approvedAt
+				return promo
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, approved bool, err error) {
+				require.NoError(t, err)
+				require.False(t, approved)
+				require.Equal(t, &approvedAt, promo.Status.AwaitingApprovalSince)
+			},
+		},
+		{
+			name: "approved",
+			promo: func() *kargoapi.Promotion {
+				promo := newApprovalPromo()
+				promo.Annotations = map[string]string{
+					kargoapi.AnnotationKeyApprove: (&kargoapi.ApprovePromotionRequest{
+						Actor:      "kubernetes:alice",
+						ApprovedAt: &approvedAt,
+						SpecHash:   promo.Spec.Hash(),
+					}).String(),
+				}
+				return promo
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, approved bool, err error) {
+				require.NoError(t, err)
+				require.True(t, approved)
+				require.Equal(t, &kargoapi.PromotionApproval{
+					Approver:   "kubernetes:alice",
+					ApprovedAt: &approvedAt,
+					SpecHash:   promo.Spec.Hash(),
+				}, promo.Status.Approval)
+			},
+		},
+		{
+			name: "recorded approval is not changed",
+			promo: func() *kargoapi.Promotion {
+				promo := newApprovalPromo()
+				promo.Annotations = map[string]string{
+					kargoapi.AnnotationKeyApprove: (&kargoapi.ApprovePromotionRequest{
+						Actor:    "kubernetes:mallory",
+						SpecHash: promo.Spec.Hash(),
+					}).String(),
+				}
+				promo.Status.Approval = &kargoapi.PromotionApproval{
+					Approver:   "kubernetes:alice",
+					ApprovedAt: &approvedAt,
+					SpecHash:   promo.Spec.Hash(),
+				}
+				return promo
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, approved bool, err error) {
+				require.NoError(t, err)
+				require.True(t, approved)
+				require.Equal(t, "kubernetes:alice", promo.Status.Approval.Approver)
+			},
+		},
+		{
+			name: "approval was not given for current spec",
+			promo: func() *kargoapi.Promotion {
+				promo := newApprovalPromo()
+				promo.Annotations = map[string]string{
+					kargoapi.AnnotationKeyApprove: (&kargoapi.ApprovePromotionRequest{
+						Actor:      "kubernetes:alice",
+						ApprovedAt: &approvedAt,
+						SpecHash:   promo.Spec.Hash(),
+					}).String(),
+				}
+				promo.Spec.Freight = "other-freight"
+				return promo
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, approved bool, err error) {
+				require.NoError(t, err)
+				require.False(t, approved)
+				require.Equal(t, kargoapi.PromotionPhaseFailed, promo.Status.Phase)
+				require.Equal(
+					t,
+					"ApprovalInvalidated: the approval by kubernetes:alice was not given for the current spec of the Promotion",
+					promo.Status.Message,
+				)
+				require.NotNil(t, promo.Status.FinishedAt)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promo := tt.promo()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(promo).
+				WithStatusSubresource(&kargoapi.Promotion{}).
+				Build()
+			r := &reconciler{kargoClient: c}
+			approved, err := r.checkApproval(context.Background(), promo)
+			tt.assertions(t, promo, approved, err)
+		})
+	}
+}
+
+func Test_reconciler_expireUnapproved(t *testing.T) {
+	scheme := k8sruntime.NewScheme()
+	require.NoError(t, kargoapi.SchemeBuilder.AddToScheme(scheme))
+
+	newWaitingPromo := func(waitingFor time.Duration) *kargoapi.Promotion {
+		promo := newPromo("fake-namespace", "fake-promo", "fake-stage", kargoapi.PromotionPhasePending, now)
+		promo.Spec.Approval = &kargoapi.PromotionApprovalPolicy{
+			AllowedApprovers: []kargoapi.PromotionApprover{{
+				Kind: kargoapi.PromotionApproverKindGroup,
+				Name: "prod-approvers",
+			}},
+			MaxAge: &metav1.Duration{Duration: 24 * time.Hour},
+		}
+		promo.Status.AwaitingApprovalSince = &metav1.Time{Time: time.Now().Add(-waitingFor)}
+		return promo
+	}
+
+	tests := []struct {
+		name       string
+		promo      func() *kargoapi.Promotion
+		assertions func(*testing.T, *fakeevent.EventRecorder, *kargoapi.Promotion, ctrl.Result, error)
+	}{
+		{
+			name: "no maximum age",
+			promo: func() *kargoapi.Promotion {
+				promo := newWaitingPromo(48 * time.Hour)
+				promo.Spec.Approval.MaxAge = nil
+				return promo
+			},
+			assertions: func(
+				t *testing.T,
+				recorder *fakeevent.EventRecorder,
+				promo *kargoapi.Promotion,
+				res ctrl.Result,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Zero(t, res.RequeueAfter)
+				require.Equal(t, kargoapi.PromotionPhasePending, promo.Status.Phase)
+				require.Empty(t, recorder.Events)
+			},
+		},
+		{
+			name: "deadline not reached",
+			promo: func() *kargoapi.Promotion {
+				return newWaitingPromo(time.Hour)
+			},
+			assertions: func(
+				t *testing.T,
+				recorder *fakeevent.EventRecorder,
+				promo *kargoapi.Promotion,
+				res ctrl.Result,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhasePending, promo.Status.Phase)
+				require.Greater(t, res.RequeueAfter, 22*time.Hour)
+				require.LessOrEqual(t, res.RequeueAfter, 23*time.Hour)
+				require.Empty(t, recorder.Events)
+			},
+		},
+		{
+			name: "deadline extended",
+			promo: func() *kargoapi.Promotion {
+				promo := newWaitingPromo(25 * time.Hour)
+				promo.Annotations = map[string]string{kargoapi.AnnotationKeyExtendExpiry: "24h"}
+				return promo
+			},
+			assertions: func(
+				t *testing.T,
+				_ *fakeevent.EventRecorder,
+				promo *kargoapi.Promotion,
+				res ctrl.Result,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhasePending, promo.Status.Phase)
+				require.Greater(t, res.RequeueAfter, 22*time.Hour)
+			},
+		},
+		{
+			name: "deadline passed",
+			promo: func() *kargoapi.Promotion {
+				return newWaitingPromo(25 * time.Hour)
+			},
+			assertions: func(
+				t *testing.T,
+				recorder *fakeevent.EventRecorder,
+				promo *kargoapi.Promotion,
+				res ctrl.Result,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Zero(t, res.RequeueAfter)
+				require.Equal(t, kargoapi.PromotionPhaseExpired, promo.Status.Phase)
+				require.Contains(t, promo.Status.Message, "NotApprovedInTime")
+				require.NotNil(t, promo.Status.FinishedAt)
+
+				require.Len(t, recorder.Events, 1)
+				event := <-recorder.Events
+				require.Equal(t, kargoapi.EventReasonPromotionExpired, event.Reason)
+			},
+		},
+		{
+			name: "Promotion that has started is not expired",
+			promo: func() *kargoapi.Promotion {
+				promo := newWaitingPromo(25 * time.Hour)
+				promo.Status.CurrentStep = 1
+				return promo
+			},
+			assertions: func(
+				t *testing.T,
+				recorder *fakeevent.EventRecorder,
+				promo *kargoapi.Promotion,
+				_ ctrl.Result,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhasePending, promo.Status.Phase)
+				require.Empty(t, recorder.Events)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promo := tt.promo()
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(promo).
+				WithStatusSubresource(&kargoapi.Promotion{}).
+				Build()
+			recorder := fakeevent.NewEventRecorder(1)
+			r := &reconciler{
+				kargoClient: c,
+				recorder:    recorder,
+			}
+			res, err := r.expireUnapproved(context.Background(), promo, nil)
+			tt.assertions(t, recorder, promo, res, err)
+		})
+	}
+}
+
 // nolint: unparam
 func newPromo(namespace, name, stage string,
 	phase kargoapi.PromotionPhase,
@@ -482,3 +1182,45 @@ func newPromo(namespace, name, stage string,
 		},
 	}
 }
+
+func Test_reconciler_saveTranscript(t *testing.T) {
+	scheme := k8sruntime.NewScheme()
+	require.NoError(t, kargoapi.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+
+	promo := &kargoapi.Promotion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-namespace",
+			Name:      "fake-promo",
+			UID:       "fake-uid",
+		},
+	}
+	kargoClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &reconciler{
+		kargoClient:  kargoClient,
+		childCreator: controller.NewChildCreator(kargoClient, 1),
+	}
+
+	name, err := r.saveTranscript(context.Background(), promo, "$ git fetch\n")
+	require.NoError(t, err)
+	require.Equal(t, "fake-promo-transcript", name)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, r.kargoClient.Get(
+		context.Background(),
+		types.NamespacedName{Namespace: promo.Namespace, Name: name},
+		cm,
+	))
+	require.Equal(t, map[string]string{"transcript": "$ git fetch\n"}, cm.Data)
+	require.Len(t, cm.OwnerReferences, 1)
+	require.Equal(t, promo.UID, cm.OwnerReferences[0].UID)
+	require.Equal(t, "fake-promo", cm.Labels[kargoapi.PromotionLabelKey])
+	require.Equal(t, "transcript", cm.Labels[kargoapi.ComponentLabelKey])
+
+	// A transcript that was already saved is left as is, even though the
+	// limit of objects created on behalf of the Promotion was reached.
+	name, err = r.saveTranscript(context.Background(), promo, "$ git push\n")
+	require.NoError(t, err)
+	require.Equal(t, "fake-promo-transcript", name)
+}
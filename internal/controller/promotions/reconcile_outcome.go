@@ -0,0 +1,96 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/event"
+	"github.com/akuity/kargo/internal/kubeclient"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// recordOutcome records the outcome of a reconciliation of the provided
+// Promotion that did nothing to it beyond what is already reflected in its
+// status, for the provided reason, as described by observeOutcome. If the
+// outcome changed, it is recorded in the status of the Promotion.
+func (r *reconciler) recordOutcome(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	freight *kargoapi.Freight,
+	reason kargoapi.ReconcileOutcomeReason,
+	message string,
+) error {
+	outcome, changed := r.observeOutcome(ctx, promo, freight, &promo.Status, reason, message)
+	if !changed {
+		return nil
+	}
+	return kubeclient.PatchStatus(ctx, r.kargoClient, promo, func(status *kargoapi.PromotionStatus) {
+		status.LastReconcileOutcome = outcome
+	})
+}
+
+// observeOutcome counts the outcome of a reconciliation of the provided
+// Promotion that left it with the provided status, for the provided reason
+// and with the provided message, unless the Promotion is in a terminal phase,
+// in which case the reconciliation completed it. If the outcome differs from
+// the one last recorded in the provided status, it is logged, reported using
+// an Event, and returned along with true, so that the caller records it in the
+// status of the Promotion. Otherwise, the last recorded outcome is returned
+// along with false, so that reconciliations that repeatedly have the same
+// outcome, e.g. while Promotions are paused, do not update the Promotion.
+func (r *reconciler) observeOutcome(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	freight *kargoapi.Freight,
+	status *kargoapi.PromotionStatus,
+	reason kargoapi.ReconcileOutcomeReason,
+	message string,
+) (*kargoapi.ReconcileOutcome, bool) {
+	if status.Phase.IsTerminal() {
+		reason = kargoapi.ReconcileOutcomeCompleted
+		// Only Promotions that would downgrade their Stage are Skipped.
+		if status.Phase == kargoapi.PromotionPhaseSkipped {
+			reason = kargoapi.ReconcileOutcomeDowngradeProtected
+		}
+		message = fmt.Sprintf("Promotion %s", status.Phase)
+		if status.Message != "" {
+			message += ": " + status.Message
+		}
+	}
+	reconcileOutcomes.WithLabelValues(promo.Namespace, promo.Spec.Stage, string(reason)).Inc()
+
+	last := status.LastReconcileOutcome
+	if last != nil && last.Reason == reason && last.Message == message {
+		return last, false
+	}
+	outcome := &kargoapi.ReconcileOutcome{
+		Reason:  reason,
+		Message: message,
+		Since:   metav1.Time{Time: time.Now()},
+	}
+
+	logger := logging.LoggerFromContext(ctx).WithValues("reason", reason, "message", message)
+	if last != nil {
+		logger = logger.WithValues("previousReason", last.Reason)
+	}
+	logger.Info("reconcile outcome changed")
+
+	eventMsg := string(reason)
+	if message != "" {
+		eventMsg += ": " + message
+	}
+	r.recorder.AnnotatedEventf(
+		promo,
+		event.NewPromotionAnnotations(ctx, kargoapi.FormatEventControllerActor(r.cfg.Name()), promo, freight),
+		corev1.EventTypeNormal,
+		kargoapi.EventReasonPromotionReconcileOutcome,
+		"%s",
+		eventMsg,
+	)
+	return outcome, true
+}
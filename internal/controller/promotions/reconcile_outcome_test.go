@@ -0,0 +1,100 @@
+package promotions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
+)
+
+func Test_reconciler_recordOutcome(t *testing.T) {
+	const stage = "outcome-stage"
+	newPromo := func(phase kargoapi.PromotionPhase) *kargoapi.Promotion {
+		return &kargoapi.Promotion{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "fake-namespace", Name: "fake-promo"},
+			Spec:       kargoapi.PromotionSpec{Stage: stage},
+			Status:     kargoapi.PromotionStatus{Phase: phase},
+		}
+	}
+	count := func(reason kargoapi.ReconcileOutcomeReason) float64 {
+		return testutil.ToFloat64(reconcileOutcomes.WithLabelValues("fake-namespace", stage, string(reason)))
+	}
+	getPromo := func(t *testing.T, c client.Client) *kargoapi.Promotion {
+		promo := &kargoapi.Promotion{}
+		require.NoError(t, c.Get(
+			context.Background(),
+			types.NamespacedName{Namespace: "fake-namespace", Name: "fake-promo"},
+			promo,
+		))
+		return promo
+	}
+
+	t.Run("records changes of the outcome", func(t *testing.T) {
+		recorder := fakeevent.NewEventRecorder(10)
+		promo := newPromo(kargoapi.PromotionPhasePending)
+		r := newFakeReconciler(t, recorder, promo)
+		paused := count(kargoapi.ReconcileOutcomePaused)
+
+		for range 2 {
+			require.NoError(t, r.recordOutcome(
+				context.Background(), promo, nil, kargoapi.ReconcileOutcomePaused, "Promotions are paused",
+			))
+		}
+		assert.Equal(t, paused+2, count(kargoapi.ReconcileOutcomePaused))
+		outcome := getPromo(t, r.kargoClient).Status.LastReconcileOutcome
+		require.NotNil(t, outcome)
+		assert.Equal(t, kargoapi.ReconcileOutcomePaused, outcome.Reason)
+		assert.Equal(t, "Promotions are paused", outcome.Message)
+		// Only the first of the reconciliations with the same outcome is
+		// reported.
+		require.Len(t, recorder.Events, 1)
+		event := <-recorder.Events
+		assert.Equal(t, corev1.EventTypeNormal, event.EventType)
+		assert.Equal(t, kargoapi.EventReasonPromotionReconcileOutcome, event.Reason)
+		assert.Equal(t, "Paused: Promotions are paused", event.Message)
+
+		require.NoError(t, r.recordOutcome(
+			context.Background(), promo, nil, kargoapi.ReconcileOutcomeQueued, "",
+		))
+		outcome = getPromo(t, r.kargoClient).Status.LastReconcileOutcome
+		require.NotNil(t, outcome)
+		assert.Equal(t, kargoapi.ReconcileOutcomeQueued, outcome.Reason)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Queued", (<-recorder.Events).Message)
+	})
+
+	t.Run("terminal phases", func(t *testing.T) {
+		testCases := []struct {
+			phase    kargoapi.PromotionPhase
+			expected kargoapi.ReconcileOutcomeReason
+		}{
+			{kargoapi.PromotionPhaseSucceeded, kargoapi.ReconcileOutcomeCompleted},
+			{kargoapi.PromotionPhaseExpired, kargoapi.ReconcileOutcomeCompleted},
+			{kargoapi.PromotionPhaseSkipped, kargoapi.ReconcileOutcomeDowngradeProtected},
+		}
+		for _, tc := range testCases {
+			t.Run(string(tc.phase), func(t *testing.T) {
+				recorder := fakeevent.NewEventRecorder(1)
+				promo := newPromo(tc.phase)
+				promo.Status.Message = "fake message"
+				r := newFakeReconciler(t, recorder, promo)
+				require.NoError(t, r.recordOutcome(
+					context.Background(), promo, nil, kargoapi.ReconcileOutcomeAwaitingApproval, "",
+				))
+				outcome := getPromo(t, r.kargoClient).Status.LastReconcileOutcome
+				require.NotNil(t, outcome)
+				assert.Equal(t, tc.expected, outcome.Reason)
+				assert.Equal(t, "Promotion "+string(tc.phase)+": fake message", outcome.Message)
+			})
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/conditions"
+	"github.com/akuity/kargo/internal/event"
+)
+
+// renderWarningsMaxListed is the maximum number of warnings that are listed
+// in the message of a RenderWarnings condition or Event. All of them are
+// recorded in the status of the Promotion regardless.
+const renderWarningsMaxListed = 10
+
+// recordRenderWarnings records the provided warnings, which tools rendering
+// the manifests of the provided Promotion emitted over the course of it, in
+// its status and in a RenderWarnings condition. A Warning Event is emitted
+// for those of the warnings that were not recorded before, so that warnings
+// emitted every time a step is retried are only reported once.
+func (r *reconciler) recordRenderWarnings(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	freight *kargoapi.Freight,
+	warnings []string,
+) {
+	var added []string
+	for _, warning := range warnings {
+		if !slices.Contains(promo.Status.RenderWarnings, warning) {
+			added = append(added, warning)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	promo.Status.RenderWarnings = warnings
+	conditions.Set(&promo.Status, &metav1.Condition{
+		Type:               kargoapi.ConditionTypeRenderWarnings,
+		Status:             metav1.ConditionTrue,
+		Reason:             "WarningsEmitted",
+		Message:            renderWarningsMessage(warnings),
+		ObservedGeneration: promo.Generation,
+	})
+	r.recorder.AnnotatedEventf(
+		promo,
+		event.NewPromotionAnnotations(ctx, kargoapi.FormatEventControllerActor(r.cfg.Name()), promo, freight),
+		corev1.EventTypeWarning,
+		kargoapi.EventReasonPromotionRenderWarnings,
+		"%s",
+		renderWarningsMessage(added),
+	)
+}
+
+// renderWarningsMessage returns a message listing the provided warnings, of
+// which at most renderWarningsMaxListed are named.
+func renderWarningsMessage(warnings []string) string {
+	listed := warnings
+	if len(listed) > renderWarningsMaxListed {
+		listed = listed[:renderWarningsMaxListed]
+	}
+	msg := fmt.Sprintf(
+		"rendering manifests emitted %d warning(s): %s",
+		len(warnings), strings.Join(listed, "; "),
+	)
+	if omitted := len(warnings) - len(listed); omitted > 0 {
+		msg += fmt.Sprintf("; and %d more", omitted)
+	}
+	return msg
+}
@@ -0,0 +1,84 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/conditions"
+	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
+)
+
+func Test_reconciler_recordRenderWarnings(t *testing.T) {
+	freight := &kargoapi.Freight{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fake-namespace", Name: "fake-freight"},
+	}
+	newPromo := func(recorded ...string) *kargoapi.Promotion {
+		return &kargoapi.Promotion{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "fake-namespace",
+				Name:       "fake-promo",
+				Generation: 2,
+			},
+			Spec:   kargoapi.PromotionSpec{Stage: "fake-stage", Freight: freight.Name},
+			Status: kargoapi.PromotionStatus{RenderWarnings: recorded},
+		}
+	}
+
+	t.Run("no warnings", func(t *testing.T) {
+		recorder := fakeevent.NewEventRecorder(1)
+		r := newFakeReconciler(t, recorder)
+		promo := newPromo()
+		r.recordRenderWarnings(context.Background(), promo, freight, nil)
+		assert.Empty(t, promo.Status.RenderWarnings)
+		assert.Nil(t, conditions.Get(&promo.Status, kargoapi.ConditionTypeRenderWarnings))
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("new warnings", func(t *testing.T) {
+		recorder := fakeevent.NewEventRecorder(1)
+		r := newFakeReconciler(t, recorder)
+		promo := newPromo("foo")
+		r.recordRenderWarnings(context.Background(), promo, freight, []string{"foo", "bar"})
+		assert.Equal(t, []string{"foo", "bar"}, promo.Status.RenderWarnings)
+
+		cond := conditions.Get(&promo.Status, kargoapi.ConditionTypeRenderWarnings)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, int64(2), cond.ObservedGeneration)
+		assert.Equal(t, "rendering manifests emitted 2 warning(s): foo; bar", cond.Message)
+
+		require.Len(t, recorder.Events, 1)
+		event := <-recorder.Events
+		assert.Equal(t, corev1.EventTypeWarning, event.EventType)
+		assert.Equal(t, kargoapi.EventReasonPromotionRenderWarnings, event.Reason)
+		// Only the warning that was not recorded before is reported.
+		assert.Equal(t, "rendering manifests emitted 1 warning(s): bar", event.Message)
+	})
+
+	t.Run("warnings recorded before", func(t *testing.T) {
+		recorder := fakeevent.NewEventRecorder(1)
+		r := newFakeReconciler(t, recorder)
+		promo := newPromo("foo", "bar")
+		r.recordRenderWarnings(context.Background(), promo, freight, []string{"foo", "bar"})
+		assert.Equal(t, []string{"foo", "bar"}, promo.Status.RenderWarnings)
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func Test_renderWarningsMessage(t *testing.T) {
+	var warnings []string
+	for i := range renderWarningsMaxListed + 2 {
+		warnings = append(warnings, fmt.Sprintf("warning %d", i))
+	}
+	msg := renderWarningsMessage(warnings)
+	assert.Contains(t, msg, "emitted 12 warning(s): warning 0;")
+	assert.Contains(t, msg, "warning 9; and 2 more")
+	assert.NotContains(t, msg, "warning 10")
+}
@@ -0,0 +1,115 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// failedPromotionTagPrefix is the prefix of the names of the tags created for
+// commits pushed to a Stage's rendered branch by a Promotion that failed, when
+// the Stage's OnFailure policy is Tag. The remainder of such a tag's name is
+// the name of the Promotion.
+const failedPromotionTagPrefix = "kargo/failed/"
+
+// cleanUpRenderedBranch applies the OnFailure policy of the provided Stage's
+// rendered branch after the provided Promotion, whose new status is also
+// provided, failed or errored, and records the outcome in that status.
+// Nothing is done if the Promotion did not fail, did not push to the rendered
+// branch before it failed, or the policy was already applied.
+func (r *reconciler) cleanUpRenderedBranch(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	promo *kargoapi.Promotion,
+	newStatus *kargoapi.PromotionStatus,
+) {
+	switch newStatus.Phase {
+	case kargoapi.PromotionPhaseFailed, kargoapi.PromotionPhaseErrored:
+	default:
+		return
+	}
+	push := newStatus.RenderedBranchPush
+	if push == nil || newStatus.RenderedBranchCleanup != nil {
+		return
+	}
+	policy := kargoapi.RenderedBranchFailurePolicyLeave
+	if rb := stage.Spec.RenderedBranch; rb != nil && rb.OnFailure != "" {
+		policy = rb.OnFailure
+	}
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"renderedBranch", push.Branch,
+		"policy", policy,
+	)
+
+	cleanup := &kargoapi.RenderedBranchCleanup{Action: policy}
+	newStatus.RenderedBranchCleanup = cleanup
+	if policy == kargoapi.RenderedBranchFailurePolicyLeave {
+		cleanup.Succeeded = true
+		cleanup.Message = fmt.Sprintf("left branch %q at commit %s", push.Branch, push.Commit)
+		return
+	}
+
+	clientOpts := &git.ClientOptions{}
+	creds, found, err := r.credentialsDB.Get(ctx, promo.Namespace, credentials.TypeGit, push.RepoURL)
+	if err != nil {
+		cleanup.Message = credentials.Redact(
+			fmt.Sprintf("error getting credentials for %s: %s", push.RepoURL, err),
+		)
+		logger.Error(errors.New(cleanup.Message), "error cleaning up rendered branch")
+		return
+	}
+	if found {
+		clientOpts.Credentials = &git.RepoCredentials{
+			Username:      creds.Username,
+			Password:      creds.Password,
+			SSHPrivateKey: creds.SSHPrivateKey,
+			HTTPAuthMode:  git.HTTPAuthMode(creds.HTTPAuthMode),
+		}
+	}
+
+	switch policy {
+	case kargoapi.RenderedBranchFailurePolicyTag:
+		cleanup.Tag = failedPromotionTagPrefix + promo.Name
+		if err = r.tagRemoteCommitFn(
+			push.RepoURL, push.Branch, push.Commit, cleanup.Tag, clientOpts,
+		); err == nil {
+			cleanup.Message = fmt.Sprintf("tagged commit %s as %q", push.Commit, cleanup.Tag)
+		}
+	case kargoapi.RenderedBranchFailurePolicyReset:
+		if err = r.resetRemoteBranchFn(
+			push.RepoURL, push.Branch, push.Commit, push.PreviousCommit, clientOpts,
+		); err == nil {
+			if push.PreviousCommit == "" {
+				cleanup.Message = fmt.Sprintf(
+					"deleted branch %q, which did not exist before the Promotion", push.Branch,
+				)
+			} else {
+				cleanup.Message = fmt.Sprintf(
+					"reset branch %q from commit %s to commit %s",
+					push.Branch, push.Commit, push.PreviousCommit,
+				)
+			}
+		} else if errors.Is(err, git.ErrRemoteBranchMoved) {
+			cleanup.Message = fmt.Sprintf(
+				"left branch %q as is, because it no longer points to commit %s",
+				push.Branch, push.Commit,
+			)
+			logger.Info(cleanup.Message)
+			return
+		}
+	default:
+		err = fmt.Errorf("unknown policy %q", policy)
+	}
+	if err != nil {
+		cleanup.Message = credentials.Redact(err.Error())
+		logger.Error(errors.New(cleanup.Message), "error cleaning up rendered branch")
+		return
+	}
+	cleanup.Succeeded = true
+	logger.Info("cleaned up rendered branch after Promotion failed", "outcome", cleanup.Message)
+}
@@ -0,0 +1,185 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sosedoff/gitkit"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func TestCleanUpRenderedBranch(t *testing.T) {
+	const testBranch = "rendered/dev"
+	testPromo := &kargoapi.Promotion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "fake-promo",
+		},
+	}
+	testCases := []struct {
+		name   string
+		policy kargoapi.RenderedBranchFailurePolicy
+		phase  kargoapi.PromotionPhase
+		// pushed indicates whether the Promotion pushed to the rendered branch.
+		pushed bool
+		// moved indicates whether the rendered branch was pushed to after the
+		// Promotion pushed to it.
+		moved      bool
+		assertions func(
+			t *testing.T,
+			cleanup *kargoapi.RenderedBranchCleanup,
+			repoDir string,
+			commits []string,
+		)
+	}{
+		{
+			name:   "succeeded",
+			policy: kargoapi.RenderedBranchFailurePolicyReset,
+			phase:  kargoapi.PromotionPhaseSucceeded,
+			pushed: true,
+			assertions: func(t *testing.T, cleanup *kargoapi.RenderedBranchCleanup, repoDir string, commits []string) {
+				require.Nil(t, cleanup)
+				require.Equal(t, commits[1], revParse(t, repoDir, "refs/heads/"+testBranch))
+			},
+		},
+		{
+			name:   "failed before rendered branch was pushed to",
+			policy: kargoapi.RenderedBranchFailurePolicyReset,
+			phase:  kargoapi.PromotionPhaseFailed,
+			assertions: func(t *testing.T, cleanup *kargoapi.RenderedBranchCleanup, repoDir string, commits []string) {
+				require.Nil(t, cleanup)
+				require.Equal(t, commits[1], revParse(t, repoDir, "refs/heads/"+testBranch))
+			},
+		},
+		{
+			name:   "leave by default",
+			phase:  kargoapi.PromotionPhaseFailed,
+			pushed: true,
+			assertions: func(t *testing.T, cleanup *kargoapi.RenderedBranchCleanup, repoDir string, commits []string) {
+				require.Equal(t, kargoapi.RenderedBranchFailurePolicyLeave, cleanup.Action)
+				require.True(t, cleanup.Succeeded)
+				require.Equal(t, commits[1], revParse(t, repoDir, "refs/heads/"+testBranch))
+			},
+		},
+		{
+			name:   "tag",
+			policy: kargoapi.RenderedBranchFailurePolicyTag,
+			phase:  kargoapi.PromotionPhaseErrored,
+			pushed: true,
+			assertions: func(t *testing.T, cleanup *kargoapi.RenderedBranchCleanup, repoDir string, commits []string) {
+				require.Equal(t, kargoapi.RenderedBranchFailurePolicyTag, cleanup.Action)
+				require.True(t, cleanup.Succeeded, cleanup.Message)
+				require.Equal(t, "kargo/failed/fake-promo", cleanup.Tag)
+				require.Equal(t, commits[1], revParse(t, repoDir, "refs/tags/kargo/failed/fake-promo"))
+				// The branch itself is left as is
+				require.Equal(t, commits[1], revParse(t, repoDir, "refs/heads/"+testBranch))
+			},
+		},
+		{
+			name:   "reset",
+			policy: kargoapi.RenderedBranchFailurePolicyReset,
+			phase:  kargoapi.PromotionPhaseFailed,
+			pushed: true,
+			assertions: func(t *testing.T, cleanup *kargoapi.RenderedBranchCleanup, repoDir string, commits []string) {
+				require.Equal(t, kargoapi.RenderedBranchFailurePolicyReset, cleanup.Action)
+				require.True(t, cleanup.Succeeded, cleanup.Message)
+				require.Equal(t, commits[0], revParse(t, repoDir, "refs/heads/"+testBranch))
+			},
+		},
+		{
+			name:   "reset after rendered branch moved",
+			policy: kargoapi.RenderedBranchFailurePolicyReset,
+			phase:  kargoapi.PromotionPhaseFailed,
+			pushed: true,
+			moved:  true,
+			assertions: func(t *testing.T, cleanup *kargoapi.RenderedBranchCleanup, repoDir string, commits []string) {
+				require.Equal(t, kargoapi.RenderedBranchFailurePolicyReset, cleanup.Action)
+				require.False(t, cleanup.Succeeded)
+				require.Contains(t, cleanup.Message, "no longer points to commit")
+				require.Equal(t, commits[2], revParse(t, repoDir, "refs/heads/"+testBranch))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Set up a test Git server in-process
+			serverDir := t.TempDir()
+			service := gitkit.New(gitkit.Config{Dir: serverDir, AutoCreate: true})
+			require.NoError(t, service.Setup())
+			server := httptest.NewServer(service)
+			defer server.Close()
+			testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+			// Push a commit that precedes the Promotion and one that was pushed
+			// by the Promotion.
+			repo, err := git.Clone(testRepoURL, nil, nil)
+			require.NoError(t, err)
+			defer repo.Close()
+			commits := make([]string, 0, 3)
+			pushCommit := func() {
+				err = os.WriteFile(
+					filepath.Join(repo.Dir(), "test.txt"),
+					[]byte(fmt.Sprintf("%d", len(commits))),
+					0600,
+				)
+				require.NoError(t, err)
+				require.NoError(t, repo.AddAllAndCommit("Update"))
+				require.NoError(t, repo.Push(&git.PushOptions{TargetBranch: testBranch}))
+				commitID, err := repo.LastCommitID()
+				require.NoError(t, err)
+				commits = append(commits, commitID)
+			}
+			pushCommit()
+			pushCommit()
+			if testCase.moved {
+				pushCommit()
+			}
+
+			status := &kargoapi.PromotionStatus{Phase: testCase.phase}
+			if testCase.pushed {
+				status.RenderedBranchPush = &kargoapi.RenderedBranchPush{
+					RepoURL:        testRepoURL,
+					Branch:         testBranch,
+					PreviousCommit: commits[0],
+					Commit:         commits[1],
+				}
+			}
+			stage := &kargoapi.Stage{
+				Spec: kargoapi.StageSpec{
+					RenderedBranch: &kargoapi.RenderedBranch{
+						Template:  testBranch,
+						OnFailure: testCase.policy,
+					},
+				},
+			}
+
+			r := newReconciler(nil, nil, nil, &credentials.FakeDB{}, nil, ReconcilerConfig{})
+			r.cleanUpRenderedBranch(context.Background(), stage, testPromo, status)
+			testCase.assertions(
+				t,
+				status.RenderedBranchCleanup,
+				filepath.Join(serverDir, "test.git"),
+				commits,
+			)
+		})
+	}
+}
+
+// revParse returns the ID of the commit that the provided ref of the Git
+// repository in the provided directory points to.
+func revParse(t *testing.T, repoDir, ref string) string {
+	res, err := exec.Command("git", "-C", repoDir, "rev-parse", ref).Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(res))
+}
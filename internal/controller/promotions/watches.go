@@ -18,27 +18,47 @@ import (
 )
 
 // UpdatedArgoCDAppHandler is an event handler that enqueues Promotions for
-// reconciliation when an associated ArgoCD Application is updated.
+// reconciliation when an associated ArgoCD Application is created, updated, or
+// deleted.
 type UpdatedArgoCDAppHandler[T any] struct {
 	kargoClient client.Client
 }
 
-// Create implements TypedEventHandler.
+// Create implements TypedEventHandler. Running Promotions may be waiting for
+// the Application to be created, so they are enqueued immediately.
 func (u *UpdatedArgoCDAppHandler[T]) Create(
-	context.Context,
-	event.TypedCreateEvent[T],
-	workqueue.TypedRateLimitingInterface[reconcile.Request],
+	ctx context.Context,
+	e event.TypedCreateEvent[T],
+	wq workqueue.TypedRateLimitingInterface[reconcile.Request],
 ) {
-	// No-op
+	app := any(e.Object).(*argocd.Application) // nolint: forcetypeassert
+	if app == nil {
+		logging.LoggerFromContext(ctx).Error(
+			nil, "Create event has no object",
+			"event", e,
+		)
+		return
+	}
+	u.enqueuePromotionsForApp(ctx, app, wq)
 }
 
-// Delete implements TypedEventHandler.
+// Delete implements TypedEventHandler. Running Promotions may be waiting for
+// the Application to be synced, which will never happen now, so they are
+// enqueued immediately.
 func (u *UpdatedArgoCDAppHandler[T]) Delete(
-	context.Context,
-	event.TypedDeleteEvent[T],
-	workqueue.TypedRateLimitingInterface[reconcile.Request],
+	ctx context.Context,
+	e event.TypedDeleteEvent[T],
+	wq workqueue.TypedRateLimitingInterface[reconcile.Request],
 ) {
-	// No-op
+	app := any(e.Object).(*argocd.Application) // nolint: forcetypeassert
+	if app == nil {
+		logging.LoggerFromContext(ctx).Error(
+			nil, "Delete event has no object",
+			"event", e,
+		)
+		return
+	}
+	u.enqueuePromotionsForApp(ctx, app, wq)
 }
 
 // Generic implements TypedEventHandler.
@@ -68,6 +88,18 @@ func (u *UpdatedArgoCDAppHandler[T]) Update(
 		return
 	}
 
+	u.enqueuePromotionsForApp(ctx, newApp, wq)
+}
+
+// enqueuePromotionsForApp enqueues all running Promotions that reference the
+// provided Argo CD Application for reconciliation.
+func (u *UpdatedArgoCDAppHandler[T]) enqueuePromotionsForApp(
+	ctx context.Context,
+	app *argocd.Application,
+	wq workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	logger := logging.LoggerFromContext(ctx)
+
 	promotions := &kargoapi.PromotionList{}
 	if err := u.kargoClient.List(
 		ctx,
@@ -75,14 +107,14 @@ func (u *UpdatedArgoCDAppHandler[T]) Update(
 		&client.ListOptions{
 			FieldSelector: fields.OneTermEqualSelector(
 				indexer.RunningPromotionsByArgoCDApplicationsField,
-				fmt.Sprintf("%s:%s", newApp.Namespace, newApp.Name),
+				fmt.Sprintf("%s:%s", app.Namespace, app.Name),
 			),
 		},
 	); err != nil {
 		logger.Error(
 			err, "error listing Promotions for Application",
-			"app", newApp.Name,
-			"namespace", newApp.Namespace,
+			"app", app.Name,
+			"namespace", app.Namespace,
 		)
 		return
 	}
@@ -100,7 +132,7 @@ func (u *UpdatedArgoCDAppHandler[T]) Update(
 			"enqueued Promotion for reconciliation",
 			"namespace", promotion.Namespace,
 			"promotion", promotion.Name,
-			"app", newApp.Name,
+			"app", app.Name,
 		)
 	}
 }
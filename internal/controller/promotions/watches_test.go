@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -243,3 +244,137 @@ func TestUpdatedArgoCDAppHandler_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatedArgoCDAppHandler_Create(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+
+	// A running Promotion whose argocd-update step is waiting for an
+	// Application that does not exist yet.
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "waiting-promotion",
+					Namespace: "fake-namespace",
+				},
+				Spec: kargoapi.PromotionSpec{
+					Steps: []kargoapi.PromotionStep{{
+						Uses: "argocd-update",
+						Config: &apiextensionsv1.JSON{
+							Raw: []byte(`{"apps":[{"name":"fake-app","namespace":"argocd"}]}`),
+						},
+					}},
+				},
+				Status: kargoapi.PromotionStatus{
+					Phase: kargoapi.PromotionPhaseRunning,
+				},
+			},
+		).
+		WithIndex(
+			&kargoapi.Promotion{},
+			indexer.RunningPromotionsByArgoCDApplicationsField,
+			indexer.RunningPromotionsByArgoCDApplications(context.TODO(), ""),
+		).
+		Build()
+
+	u := &UpdatedArgoCDAppHandler[*argocd.Application]{kargoClient: c}
+	wq := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+
+	// An unrelated Application appears
+	u.Create(
+		context.TODO(),
+		event.TypedCreateEvent[*argocd.Application]{
+			Object: &argocd.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-app", Namespace: "argocd"},
+			},
+		},
+		wq,
+	)
+	require.Equal(t, 0, wq.Len())
+
+	// The Application the Promotion is waiting for appears
+	u.Create(
+		context.TODO(),
+		event.TypedCreateEvent[*argocd.Application]{
+			Object: &argocd.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake-app", Namespace: "argocd"},
+			},
+		},
+		wq,
+	)
+	require.Equal(t, 1, wq.Len())
+	item, _ := wq.Get()
+	require.Equal(
+		t,
+		reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: "fake-namespace",
+				Name:      "waiting-promotion",
+			},
+		},
+		item,
+	)
+}
+
+func TestUpdatedArgoCDAppHandler_Delete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+
+	// A running Promotion whose argocd-update step is waiting for an
+	// Application to be synced.
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "syncing-promotion",
+					Namespace: "fake-namespace",
+				},
+				Spec: kargoapi.PromotionSpec{
+					Steps: []kargoapi.PromotionStep{{
+						Uses: "argocd-update",
+						Config: &apiextensionsv1.JSON{
+							Raw: []byte(`{"apps":[{"name":"fake-app","namespace":"argocd"}]}`),
+						},
+					}},
+				},
+				Status: kargoapi.PromotionStatus{
+					Phase: kargoapi.PromotionPhaseRunning,
+				},
+			},
+		).
+		WithIndex(
+			&kargoapi.Promotion{},
+			indexer.RunningPromotionsByArgoCDApplicationsField,
+			indexer.RunningPromotionsByArgoCDApplications(context.TODO(), ""),
+		).
+		Build()
+
+	u := &UpdatedArgoCDAppHandler[*argocd.Application]{kargoClient: c}
+	wq := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+
+	// The Application the Promotion is waiting for is deleted
+	u.Delete(
+		context.TODO(),
+		event.TypedDeleteEvent[*argocd.Application]{
+			Object: &argocd.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake-app", Namespace: "argocd"},
+			},
+		},
+		wq,
+	)
+	require.Equal(t, 1, wq.Len())
+	item, _ := wq.Get()
+	require.Equal(
+		t,
+		reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: "fake-namespace",
+				Name:      "syncing-promotion",
+			},
+		},
+		item,
+	)
+}
@@ -0,0 +1,295 @@
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	argocdapi "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// argoCDResourcesFinalizer is the finalizer that instructs Argo CD to delete
+// the resources managed by an Application before deleting the Application
+// itself.
+const argoCDResourcesFinalizer = "resources-finalizer.argocd.argoproj.io"
+
+// syncArgoCDApps creates, adopts, or updates the Argo CD Applications that are
+// managed by the provided Stage. An error is returned if any Application could
+// not be synced, but a failure to sync one Application does not prevent the
+// others from being synced.
+func (r *RegularStageReconciler) syncArgoCDApps(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) error {
+	if len(stage.Spec.ArgoCDApps) == 0 {
+		return nil
+	}
+	argoCDCtx, err := r.argoCDContexts.Get(stage.Spec.ArgoCDContext)
+	if err != nil {
+		return fmt.Errorf("cannot manage Argo CD Applications: %w", err)
+	}
+	if argoCDCtx == nil {
+		return errors.New(
+			"cannot manage Argo CD Applications: Argo CD integration is disabled",
+		)
+	}
+	var errs []error
+	for _, tmpl := range stage.Spec.ArgoCDApps {
+		if err := r.syncArgoCDApp(ctx, argoCDCtx, stage, tmpl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.Flatten(kerrors.NewAggregate(errs))
+}
+
+// syncArgoCDApp creates the Argo CD Application described by the provided
+// template if it does not exist. If it does exist, it is updated to match the
+// template, provided it is managed by the Stage or may be adopted by it.
+func (r *RegularStageReconciler) syncArgoCDApp(
+	ctx context.Context,
+	argoCDCtx *libargocd.Context,
+	stage *kargoapi.Stage,
+	tmpl kargoapi.ManagedArgoCDApp,
+) error {
+	logger := logging.LoggerFromContext(ctx)
+	stageID := fmt.Sprintf("%s:%s", stage.Namespace, stage.Name)
+
+	app := newUnstructuredArgoCDApp(tmpl, argoCDCtx.Namespace)
+	logger = logger.WithValues("app", app.GetName(), "appNamespace", app.GetNamespace())
+	if err := argoCDCtx.Client.Get(ctx, client.ObjectKeyFromObject(app), app); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf(
+				"error getting Argo CD Application %q in namespace %q: %w",
+				app.GetName(), app.GetNamespace(), err,
+			)
+		}
+		if err = applyArgoCDAppTemplate(app, tmpl, stageID); err != nil {
+			return err
+		}
+		if err = argoCDCtx.Client.Create(ctx, app); err != nil {
+			return fmt.Errorf(
+				"error creating Argo CD Application %q in namespace %q: %w",
+				app.GetName(), app.GetNamespace(), err,
+			)
+		}
+		logger.Info("created Argo CD Application")
+		return nil
+	}
+
+	switch managedBy := app.GetAnnotations()[kargoapi.AnnotationKeyManagedByStage]; {
+	case managedBy == stageID:
+	case managedBy != "":
+		return fmt.Errorf(
+			"existing Argo CD Application %q in namespace %q is managed by Stage %q",
+			app.GetName(), app.GetNamespace(), managedBy,
+		)
+	case !tmpl.Adopt:
+		return fmt.Errorf(
+			"existing Argo CD Application %q in namespace %q was not created by "+
+				"Kargo; set adopt to true to allow the Stage to manage it",
+			app.GetName(), app.GetNamespace(),
+		)
+	default:
+		logger.Info("adopting Argo CD Application")
+	}
+
+	original := app.DeepCopy()
+	if err := applyArgoCDAppTemplate(app, tmpl, stageID); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(original.Object, app.Object) {
+		return nil
+	}
+	if err := argoCDCtx.Client.Patch(
+		ctx,
+		app,
+		client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}),
+	); err != nil {
+		return fmt.Errorf(
+			"error updating Argo CD Application %q in namespace %q: %w",
+			app.GetName(), app.GetNamespace(), err,
+		)
+	}
+	logger.Debug("updated Argo CD Application")
+	return nil
+}
+
+// releaseArgoCDApps applies the deletion policy of each Argo CD Application
+// managed by the provided Stage. Applications with the Orphan policy are
+// unlinked from the Stage, while those with the Cascade policy are deleted
+// along with the resources they manage. Applications that are not (or no
+// longer) managed by the Stage are left untouched.
+func (r *RegularStageReconciler) releaseArgoCDApps(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) error {
+	if len(stage.Spec.ArgoCDApps) == 0 {
+		return nil
+	}
+	argoCDCtx, err := r.argoCDContexts.Get(stage.Spec.ArgoCDContext)
+	if err != nil {
+		return fmt.Errorf("cannot release Argo CD Applications: %w", err)
+	}
+	if argoCDCtx == nil {
+		return nil
+	}
+	logger := logging.LoggerFromContext(ctx)
+	stageID := fmt.Sprintf("%s:%s", stage.Namespace, stage.Name)
+
+	var errs []error
+	for _, tmpl := range stage.Spec.ArgoCDApps {
+		app := newUnstructuredArgoCDApp(tmpl, argoCDCtx.Namespace)
+		if err := argoCDCtx.Client.Get(ctx, client.ObjectKeyFromObject(app), app); err != nil {
+			if err = client.IgnoreNotFound(err); err != nil {
+				errs = append(errs, fmt.Errorf(
+					"error getting Argo CD Application %q in namespace %q: %w",
+					app.GetName(), app.GetNamespace(), err,
+				))
+			}
+			continue
+		}
+		if app.GetAnnotations()[kargoapi.AnnotationKeyManagedByStage] != stageID {
+			continue
+		}
+
+		original := app.DeepCopy()
+		if tmpl.DeletionPolicy == kargoapi.ArgoCDAppDeletionPolicyCascade {
+			controllerutil.AddFinalizer(app, argoCDResourcesFinalizer)
+		} else {
+			annotations := app.GetAnnotations()
+			delete(annotations, kargoapi.AnnotationKeyManagedByStage)
+			if annotations[kargoapi.AnnotationKeyAuthorizedStage] == stageID {
+				delete(annotations, kargoapi.AnnotationKeyAuthorizedStage)
+			}
+			app.SetAnnotations(annotations)
+		}
+		if err := argoCDCtx.Client.Patch(ctx, app, client.MergeFrom(original)); err != nil {
+			errs = append(errs, fmt.Errorf(
+				"error updating Argo CD Application %q in namespace %q: %w",
+				app.GetName(), app.GetNamespace(), err,
+			))
+			continue
+		}
+		if tmpl.DeletionPolicy != kargoapi.ArgoCDAppDeletionPolicyCascade {
+			logger.Info("orphaned Argo CD Application", "app", app.GetName())
+			continue
+		}
+		if err := argoCDCtx.Client.Delete(ctx, app); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf(
+				"error deleting Argo CD Application %q in namespace %q: %w",
+				app.GetName(), app.GetNamespace(), err,
+			))
+			continue
+		}
+		logger.Info("deleted Argo CD Application", "app", app.GetName())
+	}
+	return kerrors.Flatten(kerrors.NewAggregate(errs))
+}
+
+// newUnstructuredArgoCDApp returns an empty Argo CD Application with the name
+// and namespace specified by the provided template. If the template does not
+// specify a namespace, the provided namespace that Argo CD is installed into is
+// used. Applications are handled in unstructured form so that fields unknown
+// to Kargo are never lost when they are updated.
+func newUnstructuredArgoCDApp(
+	tmpl kargoapi.ManagedArgoCDApp,
+	argocdNamespace string,
+) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(argocdapi.GroupVersion.WithKind("Application"))
+	app.SetName(tmpl.Name)
+	app.SetNamespace(tmpl.Namespace)
+	if app.GetNamespace() == "" {
+		app.SetNamespace(argocdNamespace)
+	}
+	return app
+}
+
+// applyArgoCDAppTemplate updates the provided Argo CD Application to match the
+// provided template and links it to the Stage with the provided identifier.
+// Fields not described by the template are left untouched.
+func applyArgoCDAppTemplate(
+	app *unstructured.Unstructured,
+	tmpl kargoapi.ManagedArgoCDApp,
+	stageID string,
+) error {
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 2)
+	}
+	annotations[kargoapi.AnnotationKeyManagedByStage] = stageID
+	annotations[kargoapi.AnnotationKeyAuthorizedStage] = stageID
+	app.SetAnnotations(annotations)
+
+	project := tmpl.Project
+	if project == "" {
+		project = "default"
+	}
+	type field struct {
+		path  []string
+		value any
+	}
+	fields := []field{
+		{path: []string{"spec", "project"}, value: project},
+		{path: []string{"spec", "source", "repoURL"}, value: tmpl.Source.RepoURL},
+		{path: []string{"spec", "source", "targetRevision"}, value: tmpl.Source.TargetRevision},
+		{path: []string{"spec", "source", "path"}, value: tmpl.Source.Path},
+		{path: []string{"spec", "destination", "server"}, value: tmpl.Destination.Server},
+		{path: []string{"spec", "destination", "name"}, value: tmpl.Destination.Name},
+		{path: []string{"spec", "destination", "namespace"}, value: tmpl.Destination.Namespace},
+	}
+	if policy := tmpl.SyncPolicy; policy != nil {
+		var automated any
+		if policy.Automated {
+			automated = map[string]any{
+				"prune":    policy.Prune,
+				"selfHeal": policy.SelfHeal,
+			}
+		}
+		syncOptions := make([]any, len(policy.SyncOptions))
+		for i, opt := range policy.SyncOptions {
+			syncOptions[i] = opt
+		}
+		fields = append(
+			fields,
+			field{path: []string{"spec", "syncPolicy", "automated"}, value: automated},
+			field{path: []string{"spec", "syncPolicy", "syncOptions"}, value: syncOptions},
+		)
+	}
+	for _, f := range fields {
+		// Empty values are removed rather than set, so that they are not
+		// recorded as explicit empty values in the Application.
+		switch v := f.value.(type) {
+		case string:
+			if v == "" {
+				unstructured.RemoveNestedField(app.Object, f.path...)
+				continue
+			}
+		case []any:
+			if len(v) == 0 {
+				unstructured.RemoveNestedField(app.Object, f.path...)
+				continue
+			}
+		case nil:
+			unstructured.RemoveNestedField(app.Object, f.path...)
+			continue
+		}
+		if err := unstructured.SetNestedField(app.Object, f.value, f.path...); err != nil {
+			return fmt.Errorf(
+				"error setting field %v of Argo CD Application %q: %w",
+				f.path, app.GetName(), err,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package stages
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	argocdapi "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+func TestRegularStageReconciler_syncArgoCDApps(t *testing.T) {
+	const stageID = "fake-project:fake-stage"
+
+	tmpl := kargoapi.ManagedArgoCDApp{
+		Name:      "fake-app",
+		Namespace: "argocd",
+		Source: kargoapi.ManagedArgoCDAppSource{
+			RepoURL:        "https://github.com/example/repo.git",
+			TargetRevision: "env/test",
+			Path:           "manifests",
+		},
+		Destination: kargoapi.ManagedArgoCDAppDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "test",
+		},
+		SyncPolicy: &kargoapi.ManagedArgoCDAppSyncPolicy{
+			Automated: true,
+			SelfHeal:  true,
+		},
+	}
+
+	newExistingApp := func(annotations map[string]string) *argocdapi.Application {
+		return &argocdapi.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "fake-app",
+				Namespace:   "argocd",
+				Annotations: annotations,
+			},
+			Spec: argocdapi.ApplicationSpec{
+				Source: &argocdapi.ApplicationSource{
+					RepoURL:        "https://github.com/example/old-repo.git",
+					TargetRevision: "main",
+					Chart:          "fake-chart",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		tmpl          kargoapi.ManagedArgoCDApp
+		objects       []*argocdapi.Application
+		noArgoCD      bool
+		argoCDContext string
+		assertions    func(*testing.T, *unstructured.Unstructured, error)
+	}{
+		{
+			name:     "Argo CD integration disabled",
+			tmpl:     tmpl,
+			noArgoCD: true,
+			assertions: func(t *testing.T, _ *unstructured.Unstructured, err error) {
+				require.ErrorContains(t, err, "Argo CD integration is disabled")
+			},
+		},
+		{
+			name:          "Argo CD context unavailable",
+			tmpl:          tmpl,
+			argoCDContext: "broken",
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.ErrorContains(t, err, `Argo CD context "broken" is unavailable`)
+				require.Nil(t, app)
+			},
+		},
+		{
+			name: "Application is created",
+			tmpl: tmpl,
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, app)
+				assert.Equal(t, stageID, app.GetAnnotations()[kargoapi.AnnotationKeyManagedByStage])
+				assert.Equal(t, stageID, app.GetAnnotations()[kargoapi.AnnotationKeyAuthorizedStage])
+				assertNestedString(t, app, "default", "spec", "project")
+				assertNestedString(t, app, "https://github.com/example/repo.git", "spec", "source", "repoURL")
+				assertNestedString(t, app, "env/test", "spec", "source", "targetRevision")
+				assertNestedString(t, app, "manifests", "spec", "source", "path")
+				assertNestedString(t, app, "https://kubernetes.default.svc", "spec", "destination", "server")
+				assertNestedString(t, app, "test", "spec", "destination", "namespace")
+				selfHeal, _, _ := unstructured.NestedBool(app.Object, "spec", "syncPolicy", "automated", "selfHeal")
+				assert.True(t, selfHeal)
+			},
+		},
+		{
+			name:    "existing Application not created by Kargo is not adopted",
+			tmpl:    tmpl,
+			objects: []*argocdapi.Application{newExistingApp(nil)},
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.ErrorContains(t, err, "was not created by Kargo")
+				assert.Empty(t, app.GetAnnotations()[kargoapi.AnnotationKeyManagedByStage])
+				assertNestedString(t, app, "https://github.com/example/old-repo.git", "spec", "source", "repoURL")
+			},
+		},
+		{
+			name: "existing Application managed by another Stage",
+			tmpl: func() kargoapi.ManagedArgoCDApp { t := tmpl; t.Adopt = true; return t }(),
+			objects: []*argocdapi.Application{newExistingApp(map[string]string{
+				kargoapi.AnnotationKeyManagedByStage: "fake-project:other-stage",
+			})},
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.ErrorContains(t, err, "is managed by Stage")
+				assertNestedString(t, app, "https://github.com/example/old-repo.git", "spec", "source", "repoURL")
+			},
+		},
+		{
+			name:    "existing Application is adopted",
+			tmpl:    func() kargoapi.ManagedArgoCDApp { t := tmpl; t.Adopt = true; return t }(),
+			objects: []*argocdapi.Application{newExistingApp(nil)},
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, stageID, app.GetAnnotations()[kargoapi.AnnotationKeyManagedByStage])
+				assertNestedString(t, app, "https://github.com/example/repo.git", "spec", "source", "repoURL")
+				// Fields not described by the template are left untouched
+				assertNestedString(t, app, "fake-chart", "spec", "source", "chart")
+			},
+		},
+		{
+			name: "managed Application is kept in sync",
+			tmpl: tmpl,
+			objects: []*argocdapi.Application{newExistingApp(map[string]string{
+				kargoapi.AnnotationKeyManagedByStage: stageID,
+			})},
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.NoError(t, err)
+				assertNestedString(t, app, "https://github.com/example/repo.git", "spec", "source", "repoURL")
+				assertNestedString(t, app, "env/test", "spec", "source", "targetRevision")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeArgoCDClient(t, tt.objects...)
+			r := &RegularStageReconciler{}
+			if !tt.noArgoCD {
+				r.argoCDContexts = libargocd.NewContexts(&libargocd.Context{
+					Namespace: "argocd",
+					Client:    c,
+				})
+				r.argoCDContexts.SetUnavailable("broken", errors.New("connection refused"))
+			}
+			err := r.syncArgoCDApps(context.Background(), &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "fake-stage",
+				},
+				Spec: kargoapi.StageSpec{
+					ArgoCDApps:    []kargoapi.ManagedArgoCDApp{tt.tmpl},
+					ArgoCDContext: tt.argoCDContext,
+				},
+			})
+			tt.assertions(t, getUnstructuredArgoCDApp(t, c), err)
+		})
+	}
+}
+
+func TestRegularStageReconciler_releaseArgoCDApps(t *testing.T) {
+	const stageID = "fake-project:fake-stage"
+
+	tests := []struct {
+		name       string
+		policy     kargoapi.ArgoCDAppDeletionPolicy
+		managedBy  string
+		assertions func(*testing.T, *unstructured.Unstructured, error)
+	}{
+		{
+			name:      "Application is orphaned",
+			policy:    kargoapi.ArgoCDAppDeletionPolicyOrphan,
+			managedBy: stageID,
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, app)
+				assert.NotContains(t, app.GetAnnotations(), kargoapi.AnnotationKeyManagedByStage)
+				assert.NotContains(t, app.GetAnnotations(), kargoapi.AnnotationKeyAuthorizedStage)
+			},
+		},
+		{
+			name:      "Application is deleted",
+			policy:    kargoapi.ArgoCDAppDeletionPolicyCascade,
+			managedBy: stageID,
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.NoError(t, err)
+				// The fake client honors the finalizer, so the Application is
+				// only marked for deletion
+				require.NotNil(t, app)
+				assert.NotNil(t, app.GetDeletionTimestamp())
+				assert.Contains(t, app.GetFinalizers(), argoCDResourcesFinalizer)
+			},
+		},
+		{
+			name:      "Application managed by another Stage is left untouched",
+			policy:    kargoapi.ArgoCDAppDeletionPolicyCascade,
+			managedBy: "fake-project:other-stage",
+			assertions: func(t *testing.T, app *unstructured.Unstructured, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, app)
+				assert.Nil(t, app.GetDeletionTimestamp())
+				assert.Equal(
+					t,
+					"fake-project:other-stage",
+					app.GetAnnotations()[kargoapi.AnnotationKeyManagedByStage],
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeArgoCDClient(t, &argocdapi.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-app",
+					Namespace: "argocd",
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyManagedByStage:  tt.managedBy,
+						kargoapi.AnnotationKeyAuthorizedStage: tt.managedBy,
+					},
+				},
+			})
+			r := &RegularStageReconciler{
+				argoCDContexts: libargocd.NewContexts(&libargocd.Context{
+					Namespace: "argocd",
+					Client:    c,
+				}),
+			}
+			err := r.releaseArgoCDApps(context.Background(), &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "fake-stage",
+				},
+				Spec: kargoapi.StageSpec{
+					ArgoCDApps: []kargoapi.ManagedArgoCDApp{{
+						Name:           "fake-app",
+						Namespace:      "argocd",
+						DeletionPolicy: tt.policy,
+					}},
+				},
+			})
+			tt.assertions(t, getUnstructuredArgoCDApp(t, c), err)
+		})
+	}
+}
+
+// newFakeArgoCDClient returns a fake client that handles Argo CD Applications
+// only in unstructured form, so that fields unknown to the partial Application
+// type are retained, as they would be by a real API server.
+func newFakeArgoCDClient(t *testing.T, apps ...*argocdapi.Application) client.Client {
+	gvk := argocdapi.GroupVersion.WithKind("Application")
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(gvk, meta.RESTScopeNamespace)
+	objects := make([]client.Object, len(apps))
+	for i, app := range apps {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+		require.NoError(t, err)
+		u := &unstructured.Unstructured{Object: obj}
+		u.SetGroupVersionKind(gvk)
+		objects[i] = u
+	}
+	return fake.NewClientBuilder().
+		WithScheme(runtime.NewScheme()).
+		WithRESTMapper(restMapper).
+		WithObjects(objects...).
+		Build()
+}
+
+func getUnstructuredArgoCDApp(t *testing.T, c client.Client) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(argocdapi.GroupVersion.WithKind("Application"))
+	err := c.Get(
+		context.Background(),
+		client.ObjectKey{Namespace: "argocd", Name: "fake-app"},
+		app,
+	)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return app
+}
+
+func assertNestedString(t *testing.T, obj *unstructured.Unstructured, expected string, path ...string) {
+	actual, _, err := unstructured.NestedString(obj.Object, path...)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual, path)
+}
@@ -0,0 +1,29 @@
+package stages
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	promotionQueueLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kargo_promotion_queue_length",
+			Help: "Number of Promotions waiting for their turn to be executed " +
+				"against a Stage, excluding the Promotion currently being executed",
+		},
+		[]string{"project", "stage"},
+	)
+	pausedStages = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kargo_promotions_paused",
+			Help: "Whether Promotions to a Stage are paused for the Stage (1) " +
+				"or not (0)",
+		},
+		[]string{"project", "stage"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(promotionQueueLength, pausedStages)
+}
@@ -1,7 +1,10 @@
 package stages
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -10,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -28,12 +32,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
 	"github.com/akuity/kargo/internal/conditions"
 	"github.com/akuity/kargo/internal/controller"
 	argocdapi "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
 	rolloutsapi "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
 	"github.com/akuity/kargo/internal/directives"
 	kargoEvent "github.com/akuity/kargo/internal/event"
+	"github.com/akuity/kargo/internal/events"
 	"github.com/akuity/kargo/internal/indexer"
 	"github.com/akuity/kargo/internal/kargo"
 	"github.com/akuity/kargo/internal/kubeclient"
@@ -43,6 +49,10 @@ import (
 	"github.com/akuity/kargo/internal/rollouts"
 )
 
+// minSoakRequeueInterval is the shortest delay after which a Stage is requeued
+// to check whether Freight soaking in an upstream Stage can be auto-promoted.
+const minSoakRequeueInterval = 5 * time.Second
+
 // ReconcilerConfig represents configuration for the stage reconciler.
 type ReconcilerConfig struct {
 	ShardName                          string `envconfig:"SHARD_NAME"`
@@ -50,6 +60,12 @@ type ReconcilerConfig struct {
 	RolloutsControllerInstanceID       string `envconfig:"ROLLOUTS_CONTROLLER_INSTANCE_ID"`
 	MaxConcurrentControlFlowReconciles int    `envconfig:"MAX_CONCURRENT_CONTROL_FLOW_RECONCILES" default:"4"`
 	MaxConcurrentReconciles            int    `envconfig:"MAX_CONCURRENT_STAGE_RECONCILES" default:"4"`
+	MaxPromotionHistory                int    `envconfig:"MAX_STAGE_PROMOTION_HISTORY" default:"10"`
+	// PromotionPriorityAgingInterval is the interval at which the effective
+	// priority of a Promotion that is waiting for its turn is raised by one, so
+	// that Promotions of low priority are eventually executed even if
+	// Promotions of higher priority keep being created. Zero disables aging.
+	PromotionPriorityAgingInterval time.Duration `envconfig:"PROMOTION_PRIORITY_AGING_INTERVAL" default:"10m"`
 }
 
 // Name returns the name of the Stage controller.
@@ -74,6 +90,9 @@ type RegularStageReconciler struct {
 	client           client.Client
 	eventRecorder    record.EventRecorder
 	directivesEngine directives.Engine
+	argoCDContexts   *libargocd.Contexts
+	promotionCreator *events.PromotionCreator
+	promotionJournal *events.Journal
 
 	backoffCfg wait.Backoff
 }
@@ -98,12 +117,19 @@ func NewRegularStageReconciler(cfg ReconcilerConfig, engine directives.Engine) *
 // on the required objects.
 func (r *RegularStageReconciler) SetupWithManager(
 	ctx context.Context,
-	kargoMgr, argocdMgr ctrl.Manager,
+	kargoMgr ctrl.Manager,
+	argoCDContexts *libargocd.Contexts,
 	sharedIndexer client.FieldIndexer,
 ) error {
 	// Configure client and event recorder using manager.
 	r.client = kargoMgr.GetClient()
 	r.eventRecorder = libEvent.NewRecorder(ctx, kargoMgr.GetScheme(), kargoMgr.GetClient(), r.cfg.Name())
+	r.argoCDContexts = argoCDContexts
+	r.promotionJournal = events.NewJournal(kargoMgr.GetClient(), events.DefaultJournalSize)
+	r.promotionCreator = events.NewPromotionCreator(
+		kargoMgr.GetClient(),
+		events.PromotionCreatorOptions{Journal: r.promotionJournal},
+	)
 
 	// This index is used to find all Promotions that are associated with a
 	// specific Stage.
@@ -173,6 +199,9 @@ func (r *RegularStageReconciler) SetupWithManager(
 					kargo.RefreshRequested{},
 					kargo.ReverifyRequested{},
 					kargo.VerificationAbortRequested{},
+					kargo.DriftAcknowledged{},
+					kargo.PromotionRequestsReplayRequested{},
+					kargo.PromotionsPauseChanged{},
 				),
 			),
 		).
@@ -246,19 +275,25 @@ func (r *RegularStageReconciler) SetupWithManager(
 		return fmt.Errorf("unable to watch Freight produced by Warehouse: %w", err)
 	}
 
-	// If we have an ArgoCD manager, then we should watch for changes to
-	// ArgCD Applications and enqueue the related Stages for reconciliation.
-	if argocdMgr != nil {
+	// For every Argo CD context we can watch, we should watch for changes to
+	// Argo CD Applications and enqueue the related Stages for reconciliation.
+	for _, argoCDCtx := range argoCDContexts.List() {
+		if argoCDCtx.Cache == nil {
+			continue
+		}
 		if err = c.Watch(
 			source.Kind(
-				argocdMgr.GetCache(),
+				argoCDCtx.Cache,
 				&argocdapi.Application{},
 				&stageEnqueuerForArgoCDChanges[*argocdapi.Application]{
 					kargoClient: kargoMgr.GetClient(),
 				},
 			),
 		); err != nil {
-			return fmt.Errorf("unable to watch Applications: %w", err)
+			return fmt.Errorf(
+				"unable to watch Applications in Argo CD context %q: %w",
+				argoCDCtx.Name, err,
+			)
 		}
 	}
 
@@ -319,6 +354,14 @@ func (r *RegularStageReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, r.handleDelete(ctx, stage)
 	}
 
+	// Record whether Promotions to the Stage are paused. This is done for every
+	// Stage, regardless of whether any Promotions to it are pending.
+	var paused float64
+	if kargoapi.PromotionsPausedAnnotationValue(stage.GetAnnotations()) {
+		paused = 1
+	}
+	pausedStages.WithLabelValues(stage.Namespace, stage.Name).Set(paused)
+
 	// Ensure the Stage has a finalizer and requeue if it was added.
 	// The reason to requeue is to ensure that a possible deletion of the Stage
 	// directly after the finalizer was added is handled without delay.
@@ -356,9 +399,18 @@ func (r *RegularStageReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if needsRequeue {
 		return ctrl.Result{Requeue: true}, nil
 	}
-	// Otherwise, requeue after a delay.
+	// Otherwise, requeue after a delay. If Freight is soaking in an upstream
+	// Stage, requeue around the time it becomes available for auto-promotion
+	// instead of waiting for the full delay.
 	// TODO: Make the requeue delay configurable.
-	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	requeueAfter := 5 * time.Minute
+	soakRemaining, err := r.minRemainingSoakTime(ctx, stage)
+	if err != nil {
+		logger.Error(err, "error determining remaining soak time of upstream Freight")
+	} else if soakRemaining > 0 {
+		requeueAfter = min(requeueAfter, max(soakRemaining, minSoakRequeueInterval))
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *RegularStageReconciler) reconcile(
@@ -440,6 +492,15 @@ func (r *RegularStageReconciler) reconcile(
 				return status, err
 			},
 		},
+		{
+			name: "syncing Argo CD Applications",
+			reconcile: func() (kargoapi.StageStatus, error) {
+				if err := r.syncArgoCDApps(ctx, stage); err != nil {
+					return stage.Status, fmt.Errorf("failed to sync Argo CD Applications: %w", err)
+				}
+				return stage.Status, nil
+			},
+		},
 		{
 			name: "auto-promoting Freight",
 			reconcile: func() (kargoapi.StageStatus, error) {
@@ -450,6 +511,26 @@ func (r *RegularStageReconciler) reconcile(
 				return status, err
 			},
 		},
+		{
+			name: "replaying Promotion requests",
+			reconcile: func() (kargoapi.StageStatus, error) {
+				status, err := r.replayPromotionRequests(ctx, stage)
+				if err != nil {
+					err = fmt.Errorf("failed to replay Promotion requests: %w", err)
+				}
+				return status, err
+			},
+		},
+		{
+			name: "summarizing images",
+			reconcile: func() (kargoapi.StageStatus, error) {
+				status, err := r.summarizeImages(ctx, stage)
+				if err != nil {
+					err = fmt.Errorf("failed to summarize images: %w", err)
+				}
+				return status, err
+			},
+		},
 	}
 	for _, subR := range subReconcilers {
 		logger.Debug(subR.name)
@@ -531,13 +612,29 @@ func (r *RegularStageReconciler) syncPromotions(
 		// from the Stage status.
 		conditions.Delete(&newStatus, kargoapi.ConditionTypePromoting)
 		newStatus.CurrentPromotion = nil
+		newStatus.PromotionQueue = nil
+		promotionQueueLength.WithLabelValues(stage.Namespace, stage.Name).Set(0)
 
 		return newStatus, false, nil
 	}
 
-	// Sort the Promotions by phase and creation time to determine the current
-	// state of the Stage.
-	slices.SortFunc(promotions.Items, kargoapi.ComparePromotionByPhaseAndCreationTime)
+	// Sort the Promotions by phase, priority and creation time to determine the
+	// current state of the Stage.
+	sortPromotions(
+		promotions.Items,
+		stage.Status.CurrentPromotion,
+		time.Now(),
+		r.cfg.PromotionPriorityAgingInterval,
+	)
+
+	// Reflect the Promotions that are waiting for their turn in the Stage
+	// status. As this is derived from the Promotions themselves on every
+	// reconciliation, Promotions that have been aborted drop out of the queue
+	// as soon as the Stage is reconciled after their phase changed.
+	newStatus.PromotionQueue = newPromotionQueue(promotions.Items, stage.Status.PromotionHistory)
+	promotionQueueLength.WithLabelValues(stage.Namespace, stage.Name).Set(
+		float64(len(newStatus.PromotionQueue.GetPending())),
+	)
 
 	// The Promotion with the highest priority (i.e. a Running or Pending phase)
 	// is the one that we will consider for the current state of the Stage.
@@ -572,35 +669,35 @@ func (r *RegularStageReconciler) syncPromotions(
 			conditions.Delete(&newStatus, kargoapi.ConditionTypePromoting)
 			newStatus.CurrentPromotion = nil
 
-			if lastPromo != nil {
-				// We can break here since we know that all subsequent Promotions
-				// will be older than the last Promotion we saw.
-				// NB: This makes use of the fact that Promotion names are
-				// generated, and contain a timestamp component which will ensure
-				// that they can be sorted in a consistent order.
-				if strings.Compare(promo.Name, lastPromo.Name) <= 0 {
-					break
-				}
+			if !promo.Status.Phase.IsTerminal() {
+				continue
+			}
+			if lastPromo != nil && !terminatedAfter(&promo, lastPromo) {
+				continue
 			}
 
-			if promo.Status.Phase.IsTerminal() {
-				info := kargoapi.PromotionReference{
-					Name:       promo.Name,
-					Status:     promo.Status.DeepCopy(),
-					FinishedAt: promo.Status.FinishedAt,
-				}
-				if promo.Status.Freight != nil {
-					info.Freight = promo.Status.Freight.DeepCopy()
-				}
-				newPromotions = append(newPromotions, info)
+			info := kargoapi.PromotionReference{
+				Name:       promo.Name,
+				Status:     promo.Status.DeepCopy(),
+				FinishedAt: promo.Status.FinishedAt,
+			}
+			if promo.Status.Freight != nil {
+				info.Freight = promo.Status.Freight.DeepCopy()
 			}
+			newPromotions = append(newPromotions, info)
 		}
 
 		// As we will be appending to the Freight history, we need to ensure that
-		// we order the Promotions from oldest to newest. This is because the
-		// Freight history is garbage collected based on the number of entries,
-		// and we want to ensure that the oldest entries are removed first.
+		// we order the Promotions from first to last finished. This is because
+		// the Freight history is garbage collected based on the number of
+		// entries, and we want to ensure that the oldest entries are removed
+		// first.
 		slices.SortFunc(newPromotions, func(a, b kargoapi.PromotionReference) int {
+			if a.FinishedAt != nil && b.FinishedAt != nil {
+				if c := a.FinishedAt.Compare(b.FinishedAt.Time); c != 0 {
+					return c
+				}
+			}
 			return strings.Compare(a.Name, b.Name)
 		})
 
@@ -608,7 +705,15 @@ func (r *RegularStageReconciler) syncPromotions(
 		// Promotions, and any new Freight that was successfully promoted.
 		for _, p := range newPromotions {
 			promo := p
+			newStatus.PromotionHistory.Record(r.cfg.MaxPromotionHistory, newPromotionRecord(promo))
+			if p.Status.Phase == kargoapi.PromotionPhaseSkipped ||
+				p.Status.Phase == kargoapi.PromotionPhaseExpired {
+				// A skipped or expired Promotion did not touch the Stage, so it
+				// does not supersede the last Promotion that did.
+				continue
+			}
 			newStatus.LastPromotion = &promo
+			syncRenderedBranchCommit(&newStatus, promo, stage.Generation, time.Now())
 			if p.Status.Phase == kargoapi.PromotionPhaseSucceeded {
 				// If the Promotion was successful, then we should add the Freight
 				// to the history of successfully promoted Freight.
@@ -669,6 +774,16 @@ func (r *RegularStageReconciler) syncPromotions(
 		}
 	}
 
+	// If the highest priority Promotion is waiting to be approved, then so are
+	// all other non-terminal Promotions, as they are sorted after those that
+	// are not. None of them is handed its turn until it has been approved.
+	if awaitingApproval(&highestPrioPromo) {
+		logger.Debug("all pending Promotions are waiting to be approved")
+		conditions.Delete(&newStatus, kargoapi.ConditionTypePromoting)
+		newStatus.CurrentPromotion = nil
+		return newStatus, hasNonTerminalPromotions, nil
+	}
+
 	// If the highest priority Promotion is not in a terminal phase, then we
 	// are promoting the Freight.
 	if !highestPrioPromo.Status.Phase.IsTerminal() {
@@ -698,6 +813,171 @@ func (r *RegularStageReconciler) syncPromotions(
 	return newStatus, hasNonTerminalPromotions, nil
 }
 
+// terminatedAfter returns true if the provided terminal Promotion terminated
+// after the provided last Promotion of a Stage. Promotions are executed in the
+// order in which they were created unless priorities say otherwise, so a
+// Promotion with a newer ULID in its name is assumed to have terminated later,
+// and a Promotion with an older one only if it finished later.
+func terminatedAfter(promo *kargoapi.Promotion, last *kargoapi.PromotionReference) bool {
+	if promo.Name == last.Name {
+		return false
+	}
+	if strings.Compare(promo.Name, last.Name) > 0 {
+		return true
+	}
+	return promo.Status.FinishedAt != nil && last.FinishedAt != nil &&
+		promo.Status.FinishedAt.After(last.FinishedAt.Time)
+}
+
+// awaitingApproval returns true if the provided Promotion requires approval
+// that has not been recorded yet, and has therefore not begun.
+func awaitingApproval(promo *kargoapi.Promotion) bool {
+	return promo.Spec.Approval != nil && promo.Status.Approval == nil &&
+		!promo.Status.Phase.IsTerminal() && promo.Status.Phase != kargoapi.PromotionPhaseRunning
+}
+
+// sortPromotions sorts the provided Promotions in the order in which they are
+// to be executed. Running Promotions come first. Promotions waiting to be
+// approved come after all other non-terminal Promotions, so that they do not
+// block those queued behind them. Otherwise, the provided current Promotion of
+// the Stage comes next, as Promotions that have already been handed their
+// turn are never preempted. The remaining non-terminal Promotions are
+// ordered by their effective priority, from highest to lowest, and Promotions
+// of equal effective priority by the ULID in their name, from oldest to
+// newest. The effective priority of a Promotion is its priority plus the
+// number of full aging intervals that have passed since it was created, as of
+// the provided time. If the aging interval is not positive, priorities do not
+// age. Terminal Promotions come last, from newest to oldest.
+func sortPromotions(
+	promos []kargoapi.Promotion,
+	current *kargoapi.PromotionReference,
+	now time.Time,
+	agingInterval time.Duration,
+) {
+	effectivePriority := func(promo *kargoapi.Promotion) int64 {
+		var prio int64
+		if promo.Spec.Priority != nil {
+			prio = int64(*promo.Spec.Priority)
+		}
+		if agingInterval > 0 && !promo.CreationTimestamp.IsZero() {
+			if age := now.Sub(promo.CreationTimestamp.Time); age > 0 {
+				prio += int64(age / agingInterval)
+			}
+		}
+		return prio
+	}
+	isCurrent := func(promo *kargoapi.Promotion) bool {
+		return current != nil && promo.Name == current.Name
+	}
+	slices.SortFunc(promos, func(a, b kargoapi.Promotion) int {
+		if phaseCompare := kargoapi.ComparePromotionPhase(a.Status.Phase, b.Status.Phase); phaseCompare != 0 {
+			return phaseCompare
+		}
+		if a.Status.Phase.IsTerminal() {
+			return strings.Compare(b.Name, a.Name)
+		}
+		if a.Status.Phase != kargoapi.PromotionPhaseRunning {
+			aWaiting, bWaiting := awaitingApproval(&a), awaitingApproval(&b)
+			switch {
+			case !aWaiting && bWaiting:
+				return -1
+			case aWaiting && !bWaiting:
+				return 1
+			}
+			aCurrent, bCurrent := isCurrent(&a), isCurrent(&b)
+			switch {
+			case aCurrent && !bCurrent:
+				return -1
+			case !aCurrent && bCurrent:
+				return 1
+			}
+			if prioCompare := cmp.Compare(effectivePriority(&b), effectivePriority(&a)); prioCompare != 0 {
+				return prioCompare
+			}
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// newPromotionQueue returns a PromotionQueue for the provided Promotions, which
+// must have been sorted using sortPromotions.
+// The first non-terminal Promotion is the one that is currently being executed
+// (or is next in line to be) and is therefore not considered to be queued,
+// unless it is waiting to be approved. If no Promotions are queued, nil is
+// returned.
+func newPromotionQueue(
+	promos []kargoapi.Promotion,
+	history kargoapi.PromotionHistory,
+) *kargoapi.PromotionQueue {
+	var pending []string
+	for i, promo := range promos {
+		if promo.Status.Phase.IsTerminal() {
+			// Terminal Promotions are sorted after all non-terminal ones.
+			break
+		}
+		if i == 0 && !awaitingApproval(&promo) {
+			continue
+		}
+		pending = append(pending, promo.Name)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	queue := &kargoapi.PromotionQueue{Pending: pending}
+	if avg, ok := averagePromotionDuration(history); ok {
+		// A Promotion created now has to wait for the current Promotion and all
+		// the queued ones.
+		queue.EstimatedWait = &metav1.Duration{
+			Duration: avg * time.Duration(len(pending)+1),
+		}
+	}
+	return queue
+}
+
+// averagePromotionDuration returns the average duration of the Promotions in
+// the provided history, rounded to the second. Records that do not indicate
+// when the Promotion started or finished are ignored. If no record indicates
+// both, false is returned.
+func averagePromotionDuration(history kargoapi.PromotionHistory) (time.Duration, bool) {
+	var total time.Duration
+	var count int
+	for _, record := range history {
+		if record.StartedAt == nil || record.FinishedAt == nil {
+			continue
+		}
+		d := record.FinishedAt.Sub(record.StartedAt.Time)
+		if d < 0 {
+			continue
+		}
+		total += d
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return (total / time.Duration(count)).Round(time.Second), true
+}
+
+// newPromotionRecord returns a PromotionRecord for the provided reference to
+// a terminated Promotion.
+func newPromotionRecord(promo kargoapi.PromotionReference) kargoapi.PromotionRecord {
+	record := kargoapi.PromotionRecord{
+		Name:       promo.Name,
+		FinishedAt: promo.FinishedAt,
+	}
+	if promo.Freight != nil {
+		record.Freight = promo.Freight.DeepCopy()
+	}
+	if promo.Status != nil {
+		record.Phase = promo.Status.Phase
+		record.Message = promo.Status.Message
+		if len(promo.Status.StepExecutionMetadata) > 0 {
+			record.StartedAt = promo.Status.StepExecutionMetadata[0].StartedAt.DeepCopy()
+		}
+	}
+	return record
+}
+
 // assessHealth assesses the health of a Stage based on the health checks from
 // the last Promotion.
 func (r *RegularStageReconciler) assessHealth(ctx context.Context, stage *kargoapi.Stage) kargoapi.StageStatus {
@@ -715,6 +995,7 @@ func (r *RegularStageReconciler) assessHealth(ctx context.Context, stage *kargoa
 			ObservedGeneration: stage.Generation,
 		})
 		newStatus.Health = nil
+		newStatus.Drift = nil
 		return newStatus
 	}
 
@@ -738,6 +1019,9 @@ func (r *RegularStageReconciler) assessHealth(ctx context.Context, stage *kargoa
 			Status: kargoapi.HealthStateUnhealthy,
 			Issues: []string{"Last Promotion did not succeed"},
 		}
+		// Drift that a failed Promotion recorded can still be resolved.
+		resolveDrift(stage, newStatus.Drift)
+		adoptAcknowledgedDrift(&newStatus)
 		return newStatus
 	}
 
@@ -753,10 +1037,13 @@ func (r *RegularStageReconciler) assessHealth(ctx context.Context, stage *kargoa
 
 	// Run the health checks.
 	health := r.directivesEngine.CheckHealth(ctx, directives.HealthCheckContext{
-		Project: stage.Namespace,
-		Stage:   stage.Name,
+		Project:       stage.Namespace,
+		Stage:         stage.Name,
+		ArgoCDContext: stage.Spec.ArgoCDContext,
 	}, steps)
 	newStatus.Health = &health
+	newStatus.Drift = syncDrift(stage, &health, time.Now())
+	adoptAcknowledgedDrift(&newStatus)
 
 	// Set the Healthy condition based on the health status.
 	switch health.Status {
@@ -791,6 +1078,158 @@ func (r *RegularStageReconciler) assessHealth(ctx context.Context, stage *kargoa
 	return newStatus
 }
 
+// syncDrift returns the drift of the Stage based on the provided result of its
+// health checks. Drift of the same commit that was detected before retains
+// when it was first detected and how it was resolved. If the health checks
+// could not determine whether there is any drift, the drift that was detected
+// before is retained as is.
+func syncDrift(stage *kargoapi.Stage, health *kargoapi.Health, now time.Time) *kargoapi.Drift {
+	prev := stage.Status.Drift
+	drift := driftFromHealthOutput(health.Output)
+	if drift == nil {
+		if health.Status == kargoapi.HealthStateUnknown {
+			return prev
+		}
+		return nil
+	}
+	if prev != nil && prev.RepoURL == drift.RepoURL && prev.Branch == drift.Branch &&
+		prev.DriftedCommit == drift.DriftedCommit {
+		drift.DetectedAt = prev.DetectedAt
+		drift.Resolution = prev.Resolution
+	} else {
+		drift.DetectedAt = &metav1.Time{Time: now}
+	}
+	resolveDrift(stage, drift)
+	return drift
+}
+
+// resolveDrift records how the provided drift of the Stage has been resolved,
+// if it has not been resolved before and has been resolved since.
+func resolveDrift(stage *kargoapi.Stage, drift *kargoapi.Drift) {
+	if drift == nil || drift.Resolution != "" {
+		return
+	}
+	if commit, ok := kargoapi.AcknowledgeDriftAnnotationValue(
+		stage.GetAnnotations(),
+	); ok && commit == drift.DriftedCommit {
+		drift.Resolution = kargoapi.DriftResolutionAcknowledged
+	} else if drift.PullRequest != nil && drift.PullRequest.Merged {
+		drift.Resolution = kargoapi.DriftResolutionPullRequestMerged
+	}
+}
+
+// syncRenderedBranchCommit updates the provided status of a Stage with the
+// commit that the provided Promotion pushed to the Stage's rendered branch,
+// unless the Promotion reset the branch after failing, in which case the
+// branch points to the commit it pointed to before. Any modification of the
+// branch by something other than Kargo that the Promotion found is reported
+// as the Stage's OnExternalModification policy prescribes: the Promotion
+// overwrote it if the policy is Warn, and it is recorded as drift if the
+// policy is Drift.
+func syncRenderedBranchCommit(
+	newStatus *kargoapi.StageStatus,
+	promo kargoapi.PromotionReference,
+	generation int64,
+	now time.Time,
+) {
+	if promo.Status == nil {
+		return
+	}
+	mod := promo.Status.ExternalModification
+	if mod != nil {
+		switch mod.Policy {
+		case kargoapi.RenderedBranchExternalModificationPolicyWarn:
+			conditions.Set(newStatus, &metav1.Condition{
+				Type:   kargoapi.ConditionTypeExternallyModified,
+				Status: metav1.ConditionTrue,
+				Reason: "ExternalModificationOverwritten",
+				Message: fmt.Sprintf(
+					"Promotion %q overwrote commit %s, which was pushed to rendered branch %q "+
+						"by something other than Kargo",
+					promo.Name, mod.ActualCommit, mod.Branch,
+				),
+				ObservedGeneration: generation,
+			})
+		case kargoapi.RenderedBranchExternalModificationPolicyDrift:
+			newStatus.Drift = &kargoapi.Drift{
+				RepoURL:        mod.RepoURL,
+				Branch:         mod.Branch,
+				PromotedCommit: mod.ExpectedCommit,
+				DriftedCommit:  mod.ActualCommit,
+				DetectedAt:     &metav1.Time{Time: now},
+			}
+		}
+	}
+	push := promo.Status.RenderedBranchPush
+	if push == nil || push.Commit == "" {
+		return
+	}
+	if mod == nil {
+		conditions.Delete(newStatus, kargoapi.ConditionTypeExternallyModified)
+	}
+	commit := &kargoapi.RenderedBranchCommit{
+		RepoURL:   push.RepoURL,
+		Branch:    push.Branch,
+		Commit:    push.Commit,
+		Promotion: promo.Name,
+	}
+	if cleanup := promo.Status.RenderedBranchCleanup; cleanup != nil && cleanup.Succeeded &&
+		cleanup.Action == kargoapi.RenderedBranchFailurePolicyReset {
+		if push.PreviousCommit == "" {
+			// The branch was deleted.
+			newStatus.RenderedBranchCommit = nil
+			return
+		}
+		if prev := newStatus.RenderedBranchCommit; prev != nil && prev.RepoURL == push.RepoURL &&
+			prev.Branch == push.Branch && prev.Commit == push.PreviousCommit {
+			return
+		}
+		commit.Commit = push.PreviousCommit
+		commit.Promotion = ""
+	}
+	newStatus.RenderedBranchCommit = commit
+}
+
+// adoptAcknowledgedDrift records the drifted commit of the provided status of
+// a Stage as the commit that Kargo last pushed to the Stage's rendered branch
+// if the drift is of that branch and has been acknowledged, so that the next
+// Promotion to the Stage does not treat the drifted commit as a modification
+// of the branch by something other than Kargo.
+func adoptAcknowledgedDrift(newStatus *kargoapi.StageStatus) {
+	drift, commit := newStatus.Drift, newStatus.RenderedBranchCommit
+	if drift == nil || commit == nil ||
+		drift.Resolution != kargoapi.DriftResolutionAcknowledged ||
+		drift.RepoURL != commit.RepoURL || drift.Branch != commit.Branch ||
+		drift.PromotedCommit != commit.Commit {
+		return
+	}
+	newStatus.RenderedBranchCommit = &kargoapi.RenderedBranchCommit{
+		RepoURL: commit.RepoURL,
+		Branch:  commit.Branch,
+		Commit:  drift.DriftedCommit,
+	}
+}
+
+// driftFromHealthOutput returns the first drift reported in the provided
+// output of a Stage's health checks, or nil if none was reported.
+func driftFromHealthOutput(output *apiextensionsv1.JSON) *kargoapi.Drift {
+	if output == nil {
+		return nil
+	}
+	var outputs []struct {
+		Drift *kargoapi.Drift `json:"drift,omitempty"`
+	}
+	if err := json.Unmarshal(output.Raw, &outputs); err != nil {
+		return nil
+	}
+	for _, o := range outputs {
+		if o.Drift != nil {
+			return o.Drift
+		}
+	}
+	return nil
+}
+
 // syncFreight ensures that all Freight statuses accurately reflect whether they
 // are currently in use by the Stage.
 func (r *RegularStageReconciler) syncFreight(ctx context.Context, stage *kargoapi.Stage) error {
@@ -1650,20 +2089,34 @@ func (r *RegularStageReconciler) autoPromoteFreight(
 			continue
 		}
 
-		// Auto promote the latest available Freight and record an event.
-		promotion, err := kargo.NewPromotionBuilder(r.client).Build(ctx, *stage, latestFreight.Name)
-		if err != nil {
-			return newStatus, fmt.Errorf(
-				"error building Promotion for Freight %q in namespace %q: %w",
-				latestFreight.Name, stage.Namespace, err,
-			)
+		// Auto promote the latest available Freight and record an event. The
+		// request is identified by the Stage and Freight, so that replaying it
+		// does not promote the same Freight again.
+		req := &events.PromotionRequested{
+			ID:      fmt.Sprintf("%s/%s/%s", events.SourceAutoPromotion, stage.Name, latestFreight.Name),
+			Source:  events.SourceAutoPromotion,
+			Project: stage.Namespace,
+			Stage:   stage.Name,
+			Freight: latestFreight.Name,
+		}
+		// Record which upstream Stages made the Freight available, so that the
+		// provenance of the Promotion is apparent.
+		if upstream := upstreamStagesForFreight(stage, &latestFreight); len(upstream) > 0 {
+			req.Annotations = map[string]string{
+				kargoapi.AnnotationKeyUpstreamStages: strings.Join(upstream, ","),
+			}
 		}
-		if err = r.client.Create(ctx, promotion); err != nil {
+		promotion, err := r.promotionCreator.Create(ctx, stage, req)
+		if err != nil {
 			return newStatus, fmt.Errorf(
 				"error creating Promotion for Freight %q in namespace %q: %w",
 				latestFreight.Name, stage.Namespace, err,
 			)
 		}
+		if promotion == nil {
+			freightLogger.Debug("promotion request was dropped")
+			continue
+		}
 		r.eventRecorder.AnnotatedEventf(
 			promotion,
 			kargoEvent.NewPromotionAnnotations(
@@ -1687,6 +2140,157 @@ func (r *RegularStageReconciler) autoPromoteFreight(
 	return newStatus, nil
 }
 
+// replayPromotionRequests replays the journaled requests to promote to the
+// Stage that were accepted at or after the time specified by the Stage's
+// AnnotationKeyReplayPromotionRequests annotation, unless that value has been
+// handled already. Requests for which a Promotion was created before are not
+// replayed, and neither are requests for Freight that is older than the
+// Freight from the same origin that the Stage currently uses, so that a replay
+// never rolls the Stage back. Replayed requests are attributed to the
+// controller, as the journal is not protected from tampering by users of the
+// Project.
+func (r *RegularStageReconciler) replayPromotionRequests(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) (kargoapi.StageStatus, error) {
+	newStatus := *stage.Status.DeepCopy()
+	value, ok := kargoapi.ReplayPromotionRequestsAnnotationValue(stage.GetAnnotations())
+	if !ok || value == stage.Status.LastHandledReplay {
+		return newStatus, nil
+	}
+	logger := logging.LoggerFromContext(ctx).WithValues("replay", value)
+
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// Retrying would not help, so the value is considered handled.
+		logger.Error(
+			err, "ignoring invalid value of annotation",
+			"annotation", kargoapi.AnnotationKeyReplayPromotionRequests,
+		)
+		newStatus.LastHandledReplay = value
+		return newStatus, nil
+	}
+
+	reqs, err := r.promotionJournal.List(ctx, stage.Namespace)
+	if err != nil {
+		return newStatus, err
+	}
+	var errs []error
+	for i := range reqs {
+		req := &reqs[i]
+		if req.Stage != stage.Name || req.Time.Before(since) {
+			continue
+		}
+		existing, err := r.promotionCreator.GetPromotion(ctx, req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error replaying request %q: %w", req.ID, err))
+			continue
+		}
+		if existing != nil {
+			if existing.Status.Phase.IsTerminal() {
+				logger.Debug(
+					"skipping promotion request whose Promotion has already run",
+					"request", req.ID,
+					"promotion", existing.Name,
+					"phase", existing.Status.Phase,
+				)
+			}
+			continue
+		}
+		superseded, err := r.isFreightSuperseded(ctx, stage, req.Freight)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error replaying request %q: %w", req.ID, err))
+			continue
+		}
+		if superseded {
+			logger.Debug(
+				"skipping promotion request for Freight older than the Stage's current Freight",
+				"request", req.ID,
+				"freight", req.Freight,
+			)
+			continue
+		}
+		// Anyone who may write ConfigMaps in the Project's namespace may also
+		// write to the journal, so nothing in its entries that vouches for the
+		// origin of a request is trusted. The Promotion is attributed to the
+		// controller instead of to the recorded actor.
+		req.Actor = kargoapi.FormatEventControllerActor(r.cfg.Name())
+		req.Annotations = nil
+		req.OriginCommit = nil
+		promo, err := r.promotionCreator.Replay(ctx, stage, req)
+		switch {
+		case errors.Is(err, events.ErrInvalidRequest) || apierrors.IsInvalid(err):
+			// The request can not succeed, e.g. because the Freight is no
+			// longer available to the Stage, so it is not retried.
+			logger.Error(err, "skipping promotion request", "request", req.ID)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("error replaying request %q: %w", req.ID, err))
+		default:
+			logger.Debug(
+				"replayed promotion request",
+				"request", req.ID,
+				"promotion", promo.Name,
+			)
+		}
+	}
+	if len(errs) > 0 {
+		return newStatus, errors.Join(errs...)
+	}
+	newStatus.LastHandledReplay = value
+	return newStatus, nil
+}
+
+// isFreightSuperseded returns true if the Freight with the given name is older
+// than the Freight from the same origin that the Stage currently uses. If
+// either piece of Freight can not be found, false is returned.
+func (r *RegularStageReconciler) isFreightSuperseded(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	freightName string,
+) (bool, error) {
+	curFreightCol := stage.Status.FreightHistory.Current()
+	if curFreightCol == nil || curFreightCol.Includes(freightName) {
+		return false, nil
+	}
+	freight, err := kargoapi.GetFreight(ctx, r.client, types.NamespacedName{
+		Namespace: stage.Namespace,
+		Name:      freightName,
+	})
+	if err != nil || freight == nil {
+		return false, err
+	}
+	curFreightRef, ok := curFreightCol.Freight[freight.Origin.String()]
+	if !ok {
+		return false, nil
+	}
+	curFreight, err := kargoapi.GetFreight(ctx, r.client, types.NamespacedName{
+		Namespace: stage.Namespace,
+		Name:      curFreightRef.Name,
+	})
+	if err != nil || curFreight == nil {
+		return false, err
+	}
+	return freight.CreationTimestamp.Before(&curFreight.CreationTimestamp), nil
+}
+
+// upstreamStagesForFreight returns the names of the Stages, among those the
+// given Stage requests the given Freight's origin from, in which the Freight
+// has been verified.
+func upstreamStagesForFreight(stage *kargoapi.Stage, freight *kargoapi.Freight) []string {
+	var upstream []string
+	for _, req := range stage.Spec.RequestedFreight {
+		if !req.Origin.Equals(&freight.Origin) {
+			continue
+		}
+		for _, upstreamStage := range req.Sources.Stages {
+			if freight.IsVerifiedIn(upstreamStage) {
+				upstream = append(upstream, upstreamStage)
+			}
+		}
+	}
+	return upstream
+}
+
 // autoPromotionAllowed checks if auto-promotion is allowed for the given Stage.
 func (r *RegularStageReconciler) autoPromotionAllowed(
 	ctx context.Context,
@@ -1718,6 +2322,57 @@ func (r *RegularStageReconciler) autoPromotionAllowed(
 	return false, nil
 }
 
+// minRemainingSoakTime returns the shortest time remaining until Freight that
+// is currently soaking in an upstream Stage has soaked for long enough to be
+// auto-promoted to the given Stage. If auto-promotion is not allowed for the
+// Stage, or no such Freight exists, zero is returned.
+func (r *RegularStageReconciler) minRemainingSoakTime(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) (time.Duration, error) {
+	stageRef := types.NamespacedName{Namespace: stage.Namespace, Name: stage.Name}
+	if autoPromotionAllowed, err := r.autoPromotionAllowed(ctx, stageRef); err != nil || !autoPromotionAllowed {
+		return 0, err
+	}
+
+	var minRemaining time.Duration
+	for _, req := range stage.Spec.RequestedFreight {
+		if req.Sources.Direct || req.Sources.RequiredSoakTime == nil {
+			continue
+		}
+		warehouse, err := kargoapi.GetWarehouse(
+			ctx,
+			r.client,
+			types.NamespacedName{Namespace: stage.Namespace, Name: req.Origin.Name},
+		)
+		if err != nil || warehouse == nil {
+			return 0, err
+		}
+		freight, err := warehouse.ListFreight(
+			ctx,
+			r.client,
+			&kargoapi.ListWarehouseFreightOptions{VerifiedIn: req.Sources.Stages},
+		)
+		if err != nil {
+			return 0, err
+		}
+		for _, f := range freight {
+			for _, source := range req.Sources.Stages {
+				// Freight only continues to soak while it is in the upstream
+				// Stage.
+				if !f.IsCurrentlyIn(source) {
+					continue
+				}
+				remaining := req.Sources.RequiredSoakTime.Duration - f.GetLongestSoak(source)
+				if remaining > 0 && (minRemaining == 0 || remaining < minRemaining) {
+					minRemaining = remaining
+				}
+			}
+		}
+	}
+	return minRemaining, nil
+}
+
 // getPromotableFreight retrieves a map of []Freight promotable to the specified
 // Stage, indexed by origin.
 func (r *RegularStageReconciler) getPromotableFreight(
@@ -1764,6 +2419,7 @@ func (r *RegularStageReconciler) handleDelete(ctx context.Context, stage *kargoa
 		r.clearVerifications,
 		r.clearApprovals,
 		r.clearAnalysisRuns,
+		r.releaseArgoCDApps,
 	}
 	var errs []error
 	for _, c := range toClear {
@@ -1782,6 +2438,9 @@ func (r *RegularStageReconciler) handleDelete(ctx context.Context, stage *kargoa
 		return fmt.Errorf("error removing finalizer from Stage: %w", err)
 	}
 
+	promotionQueueLength.DeleteLabelValues(stage.Namespace, stage.Name)
+	pausedStages.DeleteLabelValues(stage.Namespace, stage.Name)
+
 	return nil
 }
 
@@ -2,14 +2,17 @@ package stages
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,6 +28,7 @@ import (
 	"github.com/akuity/kargo/internal/conditions"
 	rolloutsapi "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
 	"github.com/akuity/kargo/internal/directives"
+	"github.com/akuity/kargo/internal/events"
 	"github.com/akuity/kargo/internal/indexer"
 	fakeevent "github.com/akuity/kargo/internal/kubernetes/event/fake"
 )
@@ -341,6 +345,42 @@ func TestRegularStageReconciler_Reconcile(t *testing.T) {
 				assert.Equal(t, metav1.ConditionFalse, readyCond.Status)
 			},
 		},
+		{
+			name: "paused stage without promotions",
+			req: ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: "default",
+					Name:      "paused-stage",
+				},
+			},
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "default",
+					Name:       "paused-stage",
+					Finalizers: []string{kargoapi.FinalizerName},
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyPausePromotions: kargoapi.AnnotationValueTrue,
+					},
+				},
+				Spec: kargoapi.StageSpec{
+					PromotionTemplate: &kargoapi.PromotionTemplate{
+						Spec: kargoapi.PromotionTemplateSpec{
+							Steps: []kargoapi.PromotionStep{
+								{}, {},
+							},
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, _ client.Client, _ ctrl.Result, err error) {
+				require.NoError(t, err)
+				assert.Equal(
+					t,
+					float64(1),
+					testutil.ToFloat64(pausedStages.WithLabelValues("default", "paused-stage")),
+				)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -599,6 +639,7 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 				require.NoError(t, err)
 				assert.False(t, hasPendingPromotions)
 				assert.Nil(t, status.CurrentPromotion)
+				assert.Nil(t, status.PromotionQueue)
 				assert.Empty(t, status.Conditions)
 			},
 		},
@@ -656,6 +697,11 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 				require.NotNil(t, status.LastPromotion)
 				assert.Equal(t, "successful-promotion", status.LastPromotion.Name)
 
+				// Verify promotion history
+				require.Len(t, status.PromotionHistory, 1)
+				assert.Equal(t, "successful-promotion", status.PromotionHistory[0].Name)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, status.PromotionHistory[0].Phase)
+
 				// Verify freight history
 				require.Len(t, status.FreightHistory, 1)
 				assert.Equal(t, &kargoapi.FreightCollection{
@@ -685,6 +731,107 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 				assert.Equal(t, "WaitingForVerification", verifiedCond.Reason)
 			},
 		},
+		{
+			name: "skipped promotion does not replace last promotion",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "test-stage",
+				},
+				Status: kargoapi.StageStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type: kargoapi.ConditionTypePromoting,
+						},
+					},
+					CurrentPromotion: &kargoapi.PromotionReference{
+						Name: "skipped-promotion",
+					},
+					LastPromotion: &kargoapi.PromotionReference{
+						Name: "earlier-promotion",
+					},
+				},
+			},
+			objects: []client.Object{
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "skipped-promotion",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage: "test-stage",
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase:      kargoapi.PromotionPhaseSkipped,
+						Message:    "OlderThanCurrent: image would be downgraded",
+						FinishedAt: &metav1.Time{Time: now},
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, hasPendingPromotions bool, err error) {
+				require.NoError(t, err)
+				assert.False(t, hasPendingPromotions)
+				assert.Nil(t, status.CurrentPromotion)
+
+				require.NotNil(t, status.LastPromotion)
+				assert.Equal(t, "earlier-promotion", status.LastPromotion.Name)
+
+				require.Len(t, status.PromotionHistory, 1)
+				assert.Equal(t, "skipped-promotion", status.PromotionHistory[0].Name)
+				assert.Equal(t, kargoapi.PromotionPhaseSkipped, status.PromotionHistory[0].Phase)
+
+				assert.Empty(t, status.FreightHistory)
+			},
+		},
+		{
+			name: "expired promotion does not replace last promotion",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "test-stage",
+				},
+				Status: kargoapi.StageStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type: kargoapi.ConditionTypePromoting,
+						},
+					},
+					CurrentPromotion: &kargoapi.PromotionReference{
+						Name: "expired-promotion",
+					},
+					LastPromotion: &kargoapi.PromotionReference{
+						Name: "earlier-promotion",
+					},
+				},
+			},
+			objects: []client.Object{
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "expired-promotion",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage: "test-stage",
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase:      kargoapi.PromotionPhaseExpired,
+						Message:    "NotApprovedInTime: the Promotion was not approved by its deadline",
+						FinishedAt: &metav1.Time{Time: now},
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, hasPendingPromotions bool, err error) {
+				require.NoError(t, err)
+				assert.False(t, hasPendingPromotions)
+				assert.Nil(t, status.CurrentPromotion)
+
+				require.NotNil(t, status.LastPromotion)
+				assert.Equal(t, "earlier-promotion", status.LastPromotion.Name)
+
+				require.Len(t, status.PromotionHistory, 1)
+				assert.Equal(t, kargoapi.PromotionPhaseExpired, status.PromotionHistory[0].Phase)
+			},
+		},
 		{
 			name: "active promotion updates status",
 			stage: &kargoapi.Stage{
@@ -1100,6 +1247,10 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 					CurrentPromotion: &kargoapi.PromotionReference{
 						Name: "failed-promotion",
 					},
+					PromotionHistory: kargoapi.PromotionHistory{
+						{Name: "older-promotion-2"},
+						{Name: "older-promotion-1"},
+					},
 				},
 			},
 			objects: []client.Object{
@@ -1114,7 +1265,12 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 					},
 					Status: kargoapi.PromotionStatus{
 						Phase:      kargoapi.PromotionPhaseFailed,
+						Message:    "something went wrong",
 						FinishedAt: &metav1.Time{Time: now},
+						Freight:    &kargoapi.FreightReference{Name: "failed-freight"},
+						StepExecutionMetadata: kargoapi.StepExecutionMetadataList{{
+							StartedAt: &metav1.Time{Time: hourAgo},
+						}},
 						FreightCollection: &kargoapi.FreightCollection{
 							ID: "failed-collection",
 							Freight: map[string]kargoapi.FreightReference{
@@ -1133,6 +1289,19 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 				assert.Equal(t, "failed-promotion", status.LastPromotion.Name)
 				assert.Empty(t, status.FreightHistory)
 
+				// The history is bounded, so the oldest record is dropped
+				assert.Equal(t, kargoapi.PromotionHistory{
+					{
+						Name:       "failed-promotion",
+						Freight:    &kargoapi.FreightReference{Name: "failed-freight"},
+						Phase:      kargoapi.PromotionPhaseFailed,
+						Message:    "something went wrong",
+						StartedAt:  &metav1.Time{Time: hourAgo.Truncate(time.Second)},
+						FinishedAt: &metav1.Time{Time: now.Truncate(time.Second)},
+					},
+					{Name: "older-promotion-2"},
+				}, status.PromotionHistory)
+
 				promotingCond := conditions.Get(&status, kargoapi.ConditionTypePromoting)
 				assert.Nil(t, promotingCond)
 			},
@@ -1217,130 +1386,551 @@ func TestRegularStageReconciler_syncPromotions(t *testing.T) {
 				assert.Nil(t, promotingCond)
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c := fake.NewClientBuilder().
-				WithScheme(scheme).
-				WithObjects(tt.objects...).
-				WithIndex(
-					&kargoapi.Promotion{},
-					indexer.PromotionsByStageField,
-					indexer.PromotionsByStage,
-				).
-				WithStatusSubresource(&kargoapi.Stage{}).
-				WithInterceptorFuncs(tt.interceptor).
-				Build()
-
-			r := &RegularStageReconciler{
-				client: c,
-			}
-
-			status, requeue, err := r.syncPromotions(context.Background(), tt.stage)
-			tt.assertions(t, status, requeue, err)
-		})
-	}
-}
-
-func TestRegularStageReconciler_syncFreight(t *testing.T) {
-	testProject := "fake-project"
-
-	testStage := &kargoapi.Stage{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: testProject,
-			Name:      "fake-stage",
-		},
-		Status: kargoapi.StageStatus{
-			FreightHistory: kargoapi.FreightHistory{{
-				Freight: map[string]kargoapi.FreightReference{
-					"fake-warehouse-1": {Name: "fake-freight-1"},
-					"fake-warehouse-2": {Name: "fake-freight-2"},
-				},
-			}},
-		},
-	}
-
-	testCases := []struct {
-		name        string
-		objects     []client.Object
-		interceptor interceptor.Funcs
-		assertions  func(*testing.T, client.Client, error)
-	}{
 		{
-			name: "error listing Freight",
-			interceptor: interceptor.Funcs{
-				List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
-					return fmt.Errorf("something went wrong")
+			name: "queued promotions",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "queued-stage",
 				},
-			},
-			assertions: func(t *testing.T, _ client.Client, err error) {
-				require.Error(t, err)
-				require.Contains(t, err.Error(), "error listing Freight in namespace")
-				require.Contains(t, err.Error(), "something went wrong")
-			},
-		},
-		{
-			name: "error getting Freight",
-			interceptor: interceptor.Funcs{
-				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
-					return fmt.Errorf("something went wrong")
+				Status: kargoapi.StageStatus{
+					CurrentPromotion: &kargoapi.PromotionReference{
+						Name: "promotion-01",
+					},
+					PromotionQueue: &kargoapi.PromotionQueue{
+						Pending: []string{"promotion-02", "promotion-03", "promotion-04"},
+					},
+					PromotionHistory: kargoapi.PromotionHistory{
+						{
+							Name:       "promotion-00",
+							StartedAt:  &metav1.Time{Time: twoHoursAgo.Add(-3 * time.Minute)},
+							FinishedAt: &metav1.Time{Time: twoHoursAgo},
+						},
+						{
+							Name:       "promotion-old",
+							StartedAt:  &metav1.Time{Time: twoHoursAgo.Add(-time.Minute)},
+							FinishedAt: &metav1.Time{Time: twoHoursAgo},
+						},
+						{
+							// No start time, so it does not count towards the estimate
+							Name:       "promotion-older",
+							FinishedAt: &metav1.Time{Time: twoHoursAgo},
+						},
+					},
 				},
 			},
-			assertions: func(t *testing.T, _ client.Client, err error) {
-				require.Error(t, err)
-				require.Contains(t, err.Error(), "error getting Freight")
-				require.Contains(t, err.Error(), "something went wrong")
-			},
-		},
-		{
-			name: "successful sync",
 			objects: []client.Object{
-				&kargoapi.Freight{ // The Stage is using this, but the Freight doesn't know it.
+				&kargoapi.Promotion{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: testProject,
-						Name:      "fake-freight-1",
+						Name:      "promotion-04",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{Stage: "queued-stage"},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhasePending,
 					},
 				},
-				&kargoapi.Freight{ // The Stage is using this, but the Freight doesn't know it.
+				&kargoapi.Promotion{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: testProject,
-						Name:      "fake-freight-2",
+						Name:      "promotion-01",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{Stage: "queued-stage"},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhaseRunning,
 					},
 				},
-				&kargoapi.Freight{ // The Freight thinks the Stage is using this, but it's not.
+				&kargoapi.Promotion{
+					// Aborted, so it drops out of the queue
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: testProject,
-						Name:      "fake-freight-3",
+						Name:      "promotion-03",
+						Namespace: "fake-project",
 					},
-					Status: kargoapi.FreightStatus{
-						CurrentlyIn: map[string]kargoapi.CurrentStage{testStage.Name: {}},
+					Spec: kargoapi.PromotionSpec{Stage: "queued-stage"},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhaseAborted,
 					},
 				},
-				&kargoapi.Freight{ // The Freight thinks the Stage is using this, but it's not.
+				&kargoapi.Promotion{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: testProject,
-						Name:      "fake-freight-4",
+						Name:      "promotion-02",
+						Namespace: "fake-project",
 					},
-					Status: kargoapi.FreightStatus{
-						CurrentlyIn: map[string]kargoapi.CurrentStage{testStage.Name: {}},
+					Spec: kargoapi.PromotionSpec{Stage: "queued-stage"},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhasePending,
 					},
 				},
 			},
-			assertions: func(t *testing.T, c client.Client, err error) {
-				require.NoError(t, err)
-				freight := &kargoapi.Freight{}
-				err = c.Get(
-					context.Background(),
-					types.NamespacedName{Namespace: testProject, Name: "fake-freight-1"},
-					freight,
-				)
+			assertions: func(t *testing.T, status kargoapi.StageStatus, hasPendingPromotions bool, err error) {
 				require.NoError(t, err)
-				require.Contains(t, freight.Status.CurrentlyIn, testStage.Name)
-				err = c.Get(
-					context.Background(),
-					types.NamespacedName{Namespace: testProject, Name: "fake-freight-2"},
-					freight,
+				assert.True(t, hasPendingPromotions)
+
+				assert.Equal(t, "promotion-01", status.CurrentPromotion.Name)
+				assert.Equal(t, &kargoapi.PromotionQueue{
+					Pending: []string{"promotion-02", "promotion-04"},
+					// Two minutes on average, for the current and two queued
+					// Promotions
+					EstimatedWait: &metav1.Duration{Duration: 6 * time.Minute},
+				}, status.PromotionQueue)
+				assert.Equal(
+					t,
+					float64(2),
+					testutil.ToFloat64(promotionQueueLength.WithLabelValues("fake-project", "queued-stage")),
+				)
+			},
+		},
+		{
+			name: "promotion awaiting approval is not handed its turn",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "test-stage",
+				},
+			},
+			objects: []client.Object{
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "promotion-01",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage:    "test-stage",
+						Approval: &kargoapi.PromotionApprovalPolicy{},
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhasePending,
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, hasPendingPromotions bool, err error) {
+				require.NoError(t, err)
+				assert.True(t, hasPendingPromotions)
+				assert.Nil(t, status.CurrentPromotion)
+				assert.Nil(t, conditions.Get(&status, kargoapi.ConditionTypePromoting))
+				assert.Equal(t, &kargoapi.PromotionQueue{
+					Pending: []string{"promotion-01"},
+				}, status.PromotionQueue)
+			},
+		},
+		{
+			name: "promotion awaiting approval does not block the queue",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "test-stage",
+				},
+				Status: kargoapi.StageStatus{
+					// Handed its turn before it was required to be approved
+					CurrentPromotion: &kargoapi.PromotionReference{
+						Name: "promotion-01",
+					},
+				},
+			},
+			objects: []client.Object{
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "promotion-01",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage:    "test-stage",
+						Approval: &kargoapi.PromotionApprovalPolicy{},
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhasePending,
+					},
+				},
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "promotion-02",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage:    "test-stage",
+						Approval: &kargoapi.PromotionApprovalPolicy{},
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase:    kargoapi.PromotionPhasePending,
+						Approval: &kargoapi.PromotionApproval{Approver: "kubernetes:alice"},
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, hasPendingPromotions bool, err error) {
+				require.NoError(t, err)
+				assert.True(t, hasPendingPromotions)
+				// The approved Promotion is handed its turn on the next
+				// reconciliation, once the current one has been released.
+				assert.Nil(t, status.CurrentPromotion)
+				assert.Equal(t, &kargoapi.PromotionQueue{
+					Pending: []string{"promotion-01"},
+				}, status.PromotionQueue)
+			},
+		},
+		{
+			name: "approved promotion is handed its turn ahead of one awaiting approval",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "test-stage",
+				},
+			},
+			objects: []client.Object{
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "promotion-01",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage:    "test-stage",
+						Approval: &kargoapi.PromotionApprovalPolicy{},
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhasePending,
+					},
+				},
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "promotion-02",
+						Namespace: "fake-project",
+					},
+					Spec: kargoapi.PromotionSpec{Stage: "test-stage"},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhasePending,
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, hasPendingPromotions bool, err error) {
+				require.NoError(t, err)
+				assert.True(t, hasPendingPromotions)
+				require.NotNil(t, status.CurrentPromotion)
+				assert.Equal(t, "promotion-02", status.CurrentPromotion.Name)
+				assert.Equal(t, &kargoapi.PromotionQueue{
+					Pending: []string{"promotion-01"},
+				}, status.PromotionQueue)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.objects...).
+				WithIndex(
+					&kargoapi.Promotion{},
+					indexer.PromotionsByStageField,
+					indexer.PromotionsByStage,
+				).
+				WithStatusSubresource(&kargoapi.Stage{}).
+				WithInterceptorFuncs(tt.interceptor).
+				Build()
+
+			r := &RegularStageReconciler{
+				cfg: ReconcilerConfig{
+					MaxPromotionHistory: 2,
+				},
+				client: c,
+			}
+
+			status, requeue, err := r.syncPromotions(context.Background(), tt.stage)
+			tt.assertions(t, status, requeue, err)
+		})
+	}
+}
+
+func Test_sortPromotions(t *testing.T) {
+	now := time.Now()
+	newPromo := func(
+		name string,
+		phase kargoapi.PromotionPhase,
+		priority *int32,
+		age time.Duration,
+	) kargoapi.Promotion {
+		return kargoapi.Promotion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec:   kargoapi.PromotionSpec{Priority: priority},
+			Status: kargoapi.PromotionStatus{Phase: phase},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		promos        []kargoapi.Promotion
+		current       *kargoapi.PromotionReference
+		agingInterval time.Duration
+		expected      []string
+	}{
+		{
+			name: "equal priorities are executed in order of creation",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhaseSucceeded, nil, 0),
+				newPromo("promotion-04", kargoapi.PromotionPhasePending, nil, 0),
+				newPromo("promotion-03", "", ptr.To[int32](0), 0),
+				newPromo("promotion-02", kargoapi.PromotionPhaseErrored, nil, 0),
+			},
+			expected: []string{"promotion-03", "promotion-04", "promotion-02", "promotion-01"},
+		},
+		{
+			name: "higher priority jumps the queue",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhasePending, nil, 0),
+				newPromo("promotion-02", kargoapi.PromotionPhasePending, ptr.To[int32](10), 0),
+				newPromo("promotion-03", kargoapi.PromotionPhasePending, ptr.To[int32](50), 0),
+			},
+			expected: []string{"promotion-03", "promotion-02", "promotion-01"},
+		},
+		{
+			name: "running Promotion is never preempted",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-02", kargoapi.PromotionPhasePending, ptr.To[int32](50), 0),
+				newPromo("promotion-01", kargoapi.PromotionPhaseRunning, nil, 0),
+			},
+			expected: []string{"promotion-01", "promotion-02"},
+		},
+		{
+			name: "current Promotion is never preempted",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhasePending, nil, 0),
+				newPromo("promotion-02", kargoapi.PromotionPhasePending, ptr.To[int32](50), 0),
+				newPromo("promotion-03", kargoapi.PromotionPhasePending, ptr.To[int32](10), 0),
+			},
+			current:  &kargoapi.PromotionReference{Name: "promotion-01"},
+			expected: []string{"promotion-01", "promotion-02", "promotion-03"},
+		},
+		{
+			name: "waiting Promotions age",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhasePending, nil, 25*time.Minute),
+				newPromo("promotion-02", kargoapi.PromotionPhasePending, ptr.To[int32](2), 5*time.Minute),
+				newPromo("promotion-03", kargoapi.PromotionPhasePending, ptr.To[int32](3), 0),
+			},
+			agingInterval: 10 * time.Minute,
+			expected:      []string{"promotion-03", "promotion-01", "promotion-02"},
+		},
+		{
+			name: "Promotions awaiting approval come last",
+			promos: func() []kargoapi.Promotion {
+				awaiting := newPromo("promotion-01", kargoapi.PromotionPhasePending, ptr.To[int32](50), 0)
+				awaiting.Spec.Approval = &kargoapi.PromotionApprovalPolicy{}
+				approved := newPromo("promotion-02", kargoapi.PromotionPhasePending, nil, 0)
+				approved.Spec.Approval = &kargoapi.PromotionApprovalPolicy{}
+				approved.Status.Approval = &kargoapi.PromotionApproval{Approver: "kubernetes:alice"}
+				return []kargoapi.Promotion{
+					awaiting,
+					approved,
+					newPromo("promotion-03", kargoapi.PromotionPhasePending, nil, 0),
+				}
+			}(),
+			current:  &kargoapi.PromotionReference{Name: "promotion-01"},
+			expected: []string{"promotion-02", "promotion-03", "promotion-01"},
+		},
+		{
+			name: "aging disabled",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhasePending, nil, 24*time.Hour),
+				newPromo("promotion-02", kargoapi.PromotionPhasePending, ptr.To[int32](1), 0),
+			},
+			expected: []string{"promotion-02", "promotion-01"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sortPromotions(testCase.promos, testCase.current, now, testCase.agingInterval)
+			names := make([]string, len(testCase.promos))
+			for i, promo := range testCase.promos {
+				names[i] = promo.Name
+			}
+			require.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func Test_terminatedAfter(t *testing.T) {
+	now := time.Now()
+	last := &kargoapi.PromotionReference{
+		Name:       "promotion-02",
+		FinishedAt: &metav1.Time{Time: now},
+	}
+	newPromo := func(name string, finishedAt time.Time) *kargoapi.Promotion {
+		return &kargoapi.Promotion{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     kargoapi.PromotionStatus{FinishedAt: &metav1.Time{Time: finishedAt}},
+		}
+	}
+	require.False(t, terminatedAfter(newPromo("promotion-02", now), last))
+	require.True(t, terminatedAfter(newPromo("promotion-03", now.Add(-time.Minute)), last))
+	require.False(t, terminatedAfter(newPromo("promotion-01", now.Add(-time.Minute)), last))
+	// Overtaken by the last Promotion because of its lower priority.
+	require.True(t, terminatedAfter(newPromo("promotion-01", now.Add(time.Minute)), last))
+	require.False(t, terminatedAfter(newPromo("promotion-01", now), &kargoapi.PromotionReference{
+		Name: "promotion-02",
+	}))
+}
+
+func Test_newPromotionQueue(t *testing.T) {
+	now := time.Now()
+	newPromo := func(name string, phase kargoapi.PromotionPhase) kargoapi.Promotion {
+		return kargoapi.Promotion{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     kargoapi.PromotionStatus{Phase: phase},
+		}
+	}
+	history := kargoapi.PromotionHistory{
+		{
+			Name:       "promotion-00",
+			StartedAt:  &metav1.Time{Time: now.Add(-90 * time.Second)},
+			FinishedAt: &metav1.Time{Time: now},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		promos   []kargoapi.Promotion
+		history  kargoapi.PromotionHistory
+		expected *kargoapi.PromotionQueue
+	}{
+		{
+			name: "nothing queued",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-02", kargoapi.PromotionPhaseRunning),
+				newPromo("promotion-01", kargoapi.PromotionPhaseSucceeded),
+			},
+			history: history,
+		},
+		{
+			name: "next Promotion has not started yet",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhasePending),
+				newPromo("promotion-02", kargoapi.PromotionPhasePending),
+			},
+			history: history,
+			expected: &kargoapi.PromotionQueue{
+				Pending:       []string{"promotion-02"},
+				EstimatedWait: &metav1.Duration{Duration: 3 * time.Minute},
+			},
+		},
+		{
+			name: "no history to estimate from",
+			promos: []kargoapi.Promotion{
+				newPromo("promotion-01", kargoapi.PromotionPhaseRunning),
+				newPromo("promotion-02", kargoapi.PromotionPhasePending),
+				newPromo("promotion-00", kargoapi.PromotionPhaseAborted),
+			},
+			history: kargoapi.PromotionHistory{{Name: "promotion-00"}},
+			expected: &kargoapi.PromotionQueue{
+				Pending: []string{"promotion-02"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, newPromotionQueue(testCase.promos, testCase.history))
+		})
+	}
+}
+
+func TestRegularStageReconciler_syncFreight(t *testing.T) {
+	testProject := "fake-project"
+
+	testStage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testProject,
+			Name:      "fake-stage",
+		},
+		Status: kargoapi.StageStatus{
+			FreightHistory: kargoapi.FreightHistory{{
+				Freight: map[string]kargoapi.FreightReference{
+					"fake-warehouse-1": {Name: "fake-freight-1"},
+					"fake-warehouse-2": {Name: "fake-freight-2"},
+				},
+			}},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		objects     []client.Object
+		interceptor interceptor.Funcs
+		assertions  func(*testing.T, client.Client, error)
+	}{
+		{
+			name: "error listing Freight",
+			interceptor: interceptor.Funcs{
+				List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+					return fmt.Errorf("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, _ client.Client, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error listing Freight in namespace")
+				require.Contains(t, err.Error(), "something went wrong")
+			},
+		},
+		{
+			name: "error getting Freight",
+			interceptor: interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return fmt.Errorf("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, _ client.Client, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "error getting Freight")
+				require.Contains(t, err.Error(), "something went wrong")
+			},
+		},
+		{
+			name: "successful sync",
+			objects: []client.Object{
+				&kargoapi.Freight{ // The Stage is using this, but the Freight doesn't know it.
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testProject,
+						Name:      "fake-freight-1",
+					},
+				},
+				&kargoapi.Freight{ // The Stage is using this, but the Freight doesn't know it.
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testProject,
+						Name:      "fake-freight-2",
+					},
+				},
+				&kargoapi.Freight{ // The Freight thinks the Stage is using this, but it's not.
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testProject,
+						Name:      "fake-freight-3",
+					},
+					Status: kargoapi.FreightStatus{
+						CurrentlyIn: map[string]kargoapi.CurrentStage{testStage.Name: {}},
+					},
+				},
+				&kargoapi.Freight{ // The Freight thinks the Stage is using this, but it's not.
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testProject,
+						Name:      "fake-freight-4",
+					},
+					Status: kargoapi.FreightStatus{
+						CurrentlyIn: map[string]kargoapi.CurrentStage{testStage.Name: {}},
+					},
+				},
+			},
+			assertions: func(t *testing.T, c client.Client, err error) {
+				require.NoError(t, err)
+				freight := &kargoapi.Freight{}
+				err = c.Get(
+					context.Background(),
+					types.NamespacedName{Namespace: testProject, Name: "fake-freight-1"},
+					freight,
+				)
+				require.NoError(t, err)
+				require.Contains(t, freight.Status.CurrentlyIn, testStage.Name)
+				err = c.Get(
+					context.Background(),
+					types.NamespacedName{Namespace: testProject, Name: "fake-freight-2"},
+					freight,
 				)
 				require.NoError(t, err)
 				require.Contains(t, freight.Status.CurrentlyIn, testStage.Name)
@@ -1576,76 +2166,510 @@ func TestRegularStageReconciler_assessHealth(t *testing.T) {
 					},
 				},
 			},
-			checkHealthFn: func(
-				context.Context,
-				directives.HealthCheckContext,
-				[]directives.HealthCheckStep,
-			) kargoapi.Health {
-				return kargoapi.Health{Status: kargoapi.HealthStateNotApplicable}
+			checkHealthFn: func(
+				context.Context,
+				directives.HealthCheckContext,
+				[]directives.HealthCheckStep,
+			) kargoapi.Health {
+				return kargoapi.Health{Status: kargoapi.HealthStateNotApplicable}
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus) {
+				require.NotNil(t, status.Health)
+				assert.Equal(t, kargoapi.HealthStateNotApplicable, status.Health.Status)
+
+				healthyCond := conditions.Get(&status, kargoapi.ConditionTypeHealthy)
+				assert.NotNil(t, healthyCond)
+				assert.Equal(t, metav1.ConditionUnknown, healthyCond.Status)
+			},
+		},
+		{
+			name: "unknown state",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "test-stage",
+				},
+				Status: kargoapi.StageStatus{
+					LastPromotion: &kargoapi.PromotionReference{
+						Status: &kargoapi.PromotionStatus{
+							Phase: kargoapi.PromotionPhaseSucceeded,
+							HealthChecks: []kargoapi.HealthCheckStep{
+								{
+									Uses: "test-check",
+								},
+							},
+						},
+					},
+				},
+			},
+			checkHealthFn: func(
+				context.Context,
+				directives.HealthCheckContext,
+				[]directives.HealthCheckStep,
+			) kargoapi.Health {
+				return kargoapi.Health{Status: kargoapi.HealthStateUnknown}
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus) {
+				require.NotNil(t, status.Health)
+				assert.Equal(t, kargoapi.HealthStateUnknown, status.Health.Status)
+
+				healthyCond := conditions.Get(&status, kargoapi.ConditionTypeHealthy)
+				require.NotNil(t, healthyCond)
+				assert.Equal(t, metav1.ConditionUnknown, healthyCond.Status)
+				assert.Equal(t, string(kargoapi.HealthStateUnknown), healthyCond.Reason)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				Build()
+
+			r := &RegularStageReconciler{
+				client: c,
+				directivesEngine: &directives.FakeEngine{
+					CheckHealthFn: tt.checkHealthFn,
+				},
+			}
+
+			status := r.assessHealth(context.Background(), tt.stage)
+			tt.assertions(t, status)
+		})
+	}
+}
+
+func Test_syncDrift(t *testing.T) {
+	now := time.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	driftOutput := func(drift *kargoapi.Drift) *apiextensionsv1.JSON {
+		raw, err := json.Marshal([]map[string]any{{"drift": drift}})
+		require.NoError(t, err)
+		return &apiextensionsv1.JSON{Raw: raw}
+	}
+	drift := &kargoapi.Drift{
+		RepoURL:        "https://github.com/example/repo.git",
+		Branch:         "stage/test",
+		PromotedCommit: "promoted",
+		DriftedCommit:  "drifted",
+	}
+
+	tests := []struct {
+		name       string
+		stage      *kargoapi.Stage
+		health     *kargoapi.Health
+		assertions func(*testing.T, *kargoapi.Drift)
+	}{
+		{
+			name:  "no drift",
+			stage: &kargoapi.Stage{},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateHealthy,
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.Nil(t, d)
+			},
+		},
+		{
+			name: "previous drift is cleared once healthy",
+			stage: &kargoapi.Stage{
+				Status: kargoapi.StageStatus{Drift: drift.DeepCopy()},
+			},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateHealthy,
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.Nil(t, d)
+			},
+		},
+		{
+			name: "previous drift is retained while health is unknown",
+			stage: &kargoapi.Stage{
+				Status: kargoapi.StageStatus{Drift: drift.DeepCopy()},
+			},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnknown,
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.Equal(t, drift, d)
+			},
+		},
+		{
+			name:  "new drift",
+			stage: &kargoapi.Stage{},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnhealthy,
+				Output: driftOutput(drift),
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.NotNil(t, d)
+				require.Equal(t, "drifted", d.DriftedCommit)
+				require.Equal(t, now, d.DetectedAt.Time)
+				require.True(t, d.IsBlocking())
+			},
+		},
+		{
+			name: "existing drift retains detection time and resolution",
+			stage: &kargoapi.Stage{
+				Status: kargoapi.StageStatus{
+					Drift: func() *kargoapi.Drift {
+						d := drift.DeepCopy()
+						d.DetectedAt = &earlier
+						d.Resolution = kargoapi.DriftResolutionAcknowledged
+						return d
+					}(),
+				},
+			},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnhealthy,
+				Output: driftOutput(drift),
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.NotNil(t, d)
+				require.Equal(t, earlier.Time, d.DetectedAt.Time)
+				require.Equal(t, kargoapi.DriftResolutionAcknowledged, d.Resolution)
+				require.False(t, d.IsBlocking())
+			},
+		},
+		{
+			name: "further drift is not covered by previous resolution",
+			stage: &kargoapi.Stage{
+				Status: kargoapi.StageStatus{
+					Drift: func() *kargoapi.Drift {
+						d := drift.DeepCopy()
+						d.DriftedCommit = "older"
+						d.DetectedAt = &earlier
+						d.Resolution = kargoapi.DriftResolutionAcknowledged
+						return d
+					}(),
+				},
+			},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnhealthy,
+				Output: driftOutput(drift),
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.NotNil(t, d)
+				require.Equal(t, now, d.DetectedAt.Time)
+				require.True(t, d.IsBlocking())
+			},
+		},
+		{
+			name: "drift acknowledged by annotation",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "drifted",
+					},
+				},
+			},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnhealthy,
+				Output: driftOutput(drift),
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.NotNil(t, d)
+				require.Equal(t, kargoapi.DriftResolutionAcknowledged, d.Resolution)
+			},
+		},
+		{
+			name: "annotation acknowledges a different commit",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "older",
+					},
+				},
+			},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnhealthy,
+				Output: driftOutput(drift),
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.NotNil(t, d)
+				require.True(t, d.IsBlocking())
+			},
+		},
+		{
+			name:  "drift resolved by merged pull request",
+			stage: &kargoapi.Stage{},
+			health: &kargoapi.Health{
+				Status: kargoapi.HealthStateUnhealthy,
+				Output: driftOutput(func() *kargoapi.Drift {
+					d := drift.DeepCopy()
+					d.PullRequest = &kargoapi.DriftPullRequest{
+						Number: 42,
+						Merged: true,
+					}
+					return d
+				}()),
+			},
+			assertions: func(t *testing.T, d *kargoapi.Drift) {
+				require.NotNil(t, d)
+				require.Equal(t, kargoapi.DriftResolutionPullRequestMerged, d.Resolution)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(t, syncDrift(tt.stage, tt.health, now))
+		})
+	}
+}
+
+func Test_syncRenderedBranchCommit(t *testing.T) {
+	now := time.Now()
+	push := &kargoapi.RenderedBranchPush{
+		RepoURL:        "https://github.com/example/repo.git",
+		Branch:         "rendered/test",
+		PreviousCommit: "previous",
+		Commit:         "pushed",
+	}
+	mod := &kargoapi.ExternalModification{
+		RepoURL:        "https://github.com/example/repo.git",
+		Branch:         "rendered/test",
+		ExpectedCommit: "expected",
+		ActualCommit:   "external",
+	}
+
+	tests := []struct {
+		name       string
+		status     kargoapi.StageStatus
+		promo      kargoapi.PromotionStatus
+		assertions func(*testing.T, kargoapi.StageStatus)
+	}{
+		{
+			name: "Promotion did not push to the rendered branch",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: &kargoapi.RenderedBranchCommit{Commit: "recorded"},
+			},
+			promo: kargoapi.PromotionStatus{Phase: kargoapi.PromotionPhaseSucceeded},
+			assertions: func(t *testing.T, status kargoapi.StageStatus) {
+				require.Equal(t, "recorded", status.RenderedBranchCommit.Commit)
+			},
+		},
+		{
+			name: "Promotion pushed to the rendered branch",
+			status: kargoapi.StageStatus{
+				Conditions: []metav1.Condition{{
+					Type:   kargoapi.ConditionTypeExternallyModified,
+					Status: metav1.ConditionTrue,
+				}},
+			},
+			promo: kargoapi.PromotionStatus{
+				Phase:              kargoapi.PromotionPhaseSucceeded,
+				RenderedBranchPush: push.DeepCopy(),
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus) {
+				require.Equal(
+					t,
+					&kargoapi.RenderedBranchCommit{
+						RepoURL:   "https://github.com/example/repo.git",
+						Branch:    "rendered/test",
+						Commit:    "pushed",
+						Promotion: "fake-promotion",
+					},
+					status.RenderedBranchCommit,
+				)
+				require.Nil(t, conditions.Get(&status, kargoapi.ConditionTypeExternallyModified))
+			},
+		},
+		{
+			name: "Promotion failed and reset the rendered branch",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: &kargoapi.RenderedBranchCommit{
+					RepoURL:   "https://github.com/example/repo.git",
+					Branch:    "rendered/test",
+					Commit:    "previous",
+					Promotion: "earlier-promotion",
+				},
+			},
+			promo: kargoapi.PromotionStatus{
+				Phase:              kargoapi.PromotionPhaseFailed,
+				RenderedBranchPush: push.DeepCopy(),
+				RenderedBranchCleanup: &kargoapi.RenderedBranchCleanup{
+					Action:    kargoapi.RenderedBranchFailurePolicyReset,
+					Succeeded: true,
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus) {
+				require.Equal(t, "previous", status.RenderedBranchCommit.Commit)
+				require.Equal(t, "earlier-promotion", status.RenderedBranchCommit.Promotion)
+			},
+		},
+		{
+			name: "Promotion failed and deleted the rendered branch",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: &kargoapi.RenderedBranchCommit{Commit: "unrelated"},
+			},
+			promo: kargoapi.PromotionStatus{
+				Phase: kargoapi.PromotionPhaseFailed,
+				RenderedBranchPush: func() *kargoapi.RenderedBranchPush {
+					p := push.DeepCopy()
+					p.PreviousCommit = ""
+					return p
+				}(),
+				RenderedBranchCleanup: &kargoapi.RenderedBranchCleanup{
+					Action:    kargoapi.RenderedBranchFailurePolicyReset,
+					Succeeded: true,
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus) {
+				require.Nil(t, status.RenderedBranchCommit)
+			},
+		},
+		{
+			name: "Promotion overwrote an external modification",
+			promo: kargoapi.PromotionStatus{
+				Phase:              kargoapi.PromotionPhaseSucceeded,
+				RenderedBranchPush: push.DeepCopy(),
+				ExternalModification: func() *kargoapi.ExternalModification {
+					m := mod.DeepCopy()
+					m.Policy = kargoapi.RenderedBranchExternalModificationPolicyWarn
+					return m
+				}(),
 			},
 			assertions: func(t *testing.T, status kargoapi.StageStatus) {
-				require.NotNil(t, status.Health)
-				assert.Equal(t, kargoapi.HealthStateNotApplicable, status.Health.Status)
-
-				healthyCond := conditions.Get(&status, kargoapi.ConditionTypeHealthy)
-				assert.NotNil(t, healthyCond)
-				assert.Equal(t, metav1.ConditionUnknown, healthyCond.Status)
+				require.Equal(t, "pushed", status.RenderedBranchCommit.Commit)
+				cond := conditions.Get(&status, kargoapi.ConditionTypeExternallyModified)
+				require.NotNil(t, cond)
+				require.Equal(t, metav1.ConditionTrue, cond.Status)
+				require.Contains(t, cond.Message, "external")
+				require.Nil(t, status.Drift)
 			},
 		},
 		{
-			name: "unknown state",
-			stage: &kargoapi.Stage{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "fake-project",
-					Name:      "test-stage",
-				},
-				Status: kargoapi.StageStatus{
-					LastPromotion: &kargoapi.PromotionReference{
-						Status: &kargoapi.PromotionStatus{
-							Phase: kargoapi.PromotionPhaseSucceeded,
-							HealthChecks: []kargoapi.HealthCheckStep{
-								{
-									Uses: "test-check",
-								},
-							},
-						},
-					},
-				},
+			name: "Promotion recorded an external modification as drift",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: &kargoapi.RenderedBranchCommit{Commit: "expected"},
 			},
-			checkHealthFn: func(
-				context.Context,
-				directives.HealthCheckContext,
-				[]directives.HealthCheckStep,
-			) kargoapi.Health {
-				return kargoapi.Health{Status: kargoapi.HealthStateUnknown}
+			promo: kargoapi.PromotionStatus{
+				Phase: kargoapi.PromotionPhaseFailed,
+				ExternalModification: func() *kargoapi.ExternalModification {
+					m := mod.DeepCopy()
+					m.Policy = kargoapi.RenderedBranchExternalModificationPolicyDrift
+					return m
+				}(),
 			},
 			assertions: func(t *testing.T, status kargoapi.StageStatus) {
-				require.NotNil(t, status.Health)
-				assert.Equal(t, kargoapi.HealthStateUnknown, status.Health.Status)
-
-				healthyCond := conditions.Get(&status, kargoapi.ConditionTypeHealthy)
-				require.NotNil(t, healthyCond)
-				assert.Equal(t, metav1.ConditionUnknown, healthyCond.Status)
-				assert.Equal(t, string(kargoapi.HealthStateUnknown), healthyCond.Reason)
+				require.Equal(t, "expected", status.RenderedBranchCommit.Commit)
+				require.NotNil(t, status.Drift)
+				require.Equal(t, "expected", status.Drift.PromotedCommit)
+				require.Equal(t, "external", status.Drift.DriftedCommit)
+				require.Equal(t, now, status.Drift.DetectedAt.Time)
+				require.True(t, status.Drift.IsBlocking())
 			},
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := fake.NewClientBuilder().
-				WithScheme(scheme).
-				Build()
+			status := *tt.status.DeepCopy()
+			syncRenderedBranchCommit(
+				&status,
+				kargoapi.PromotionReference{Name: "fake-promotion", Status: &tt.promo},
+				1,
+				now,
+			)
+			tt.assertions(t, status)
+		})
+	}
+}
 
-			r := &RegularStageReconciler{
-				client: c,
-				directivesEngine: &directives.FakeEngine{
-					CheckHealthFn: tt.checkHealthFn,
-				},
-			}
+func Test_adoptAcknowledgedDrift(t *testing.T) {
+	commit := &kargoapi.RenderedBranchCommit{
+		RepoURL:   "https://github.com/example/repo.git",
+		Branch:    "rendered/test",
+		Commit:    "promoted",
+		Promotion: "fake-promotion",
+	}
+	drift := &kargoapi.Drift{
+		RepoURL:        "https://github.com/example/repo.git",
+		Branch:         "rendered/test",
+		PromotedCommit: "promoted",
+		DriftedCommit:  "drifted",
+	}
 
-			status := r.assessHealth(context.Background(), tt.stage)
-			tt.assertions(t, status)
+	tests := []struct {
+		name     string
+		status   kargoapi.StageStatus
+		expected string
+	}{
+		{
+			name:     "no drift",
+			status:   kargoapi.StageStatus{RenderedBranchCommit: commit.DeepCopy()},
+			expected: "promoted",
+		},
+		{
+			name: "drift is not acknowledged",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: commit.DeepCopy(),
+				Drift:                drift.DeepCopy(),
+			},
+			expected: "promoted",
+		},
+		{
+			name: "drift of another branch is acknowledged",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: commit.DeepCopy(),
+				Drift: func() *kargoapi.Drift {
+					d := drift.DeepCopy()
+					d.Branch = "stage/test"
+					d.Resolution = kargoapi.DriftResolutionAcknowledged
+					return d
+				}(),
+			},
+			expected: "promoted",
+		},
+		{
+			name: "drift of the rendered branch is acknowledged",
+			status: kargoapi.StageStatus{
+				RenderedBranchCommit: commit.DeepCopy(),
+				Drift: func() *kargoapi.Drift {
+					d := drift.DeepCopy()
+					d.Resolution = kargoapi.DriftResolutionAcknowledged
+					return d
+				}(),
+			},
+			expected: "drifted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adoptAcknowledgedDrift(&tt.status)
+			require.Equal(t, tt.expected, tt.status.RenderedBranchCommit.Commit)
+		})
+	}
+}
+
+func Test_driftFromHealthOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   *apiextensionsv1.JSON
+		expected *kargoapi.Drift
+	}{
+		{
+			name: "nil output",
+		},
+		{
+			name:   "invalid output",
+			output: &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+		},
+		{
+			name:   "no drift",
+			output: &apiextensionsv1.JSON{Raw: []byte(`[{"foo":"bar"},null]`)},
+		},
+		{
+			name: "first drift is returned",
+			output: &apiextensionsv1.JSON{
+				Raw: []byte(`[{"foo":"bar"},{"drift":{"driftedCommit":"abc"}},{"drift":{"driftedCommit":"def"}}]`),
+			},
+			expected: &kargoapi.Drift{DriftedCommit: "abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, driftFromHealthOutput(tt.output))
 		})
 	}
 }
@@ -4807,6 +5831,16 @@ func TestRegularStageReconciler_autoPromoteFreight(t *testing.T) {
 				require.NoError(t, c.List(context.Background(), promoList, client.InNamespace("fake-project")))
 				require.Len(t, promoList.Items, 1)
 				assert.Equal(t, "test-freight-1", promoList.Items[0].Spec.Freight)
+				assert.Equal(
+					t,
+					"upstream-stage",
+					promoList.Items[0].Annotations[kargoapi.AnnotationKeyUpstreamStages],
+				)
+				assert.Equal(
+					t,
+					string(events.SourceAutoPromotion),
+					promoList.Items[0].Labels[kargoapi.PromotionSourceLabelKey],
+				)
 			},
 		},
 		{
@@ -5466,8 +6500,9 @@ func TestRegularStageReconciler_autoPromoteFreight(t *testing.T) {
 			recorder := fakeevent.NewEventRecorder(5)
 
 			r := &RegularStageReconciler{
-				client:        c,
-				eventRecorder: recorder,
+				client:           c,
+				eventRecorder:    recorder,
+				promotionCreator: events.NewPromotionCreator(c, events.PromotionCreatorOptions{}),
 			}
 
 			status, err := r.autoPromoteFreight(context.Background(), tt.stage)
@@ -5748,6 +6783,147 @@ func TestRegularStageReconciler_autoPromotionAllowed(t *testing.T) {
 	}
 }
 
+func TestRegularStageReconciler_minRemainingSoakTime(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+
+	now := time.Now()
+	stage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "test-stage",
+		},
+		Spec: kargoapi.StageSpec{
+			RequestedFreight: []kargoapi.FreightRequest{{
+				Origin: kargoapi.FreightOrigin{
+					Kind: kargoapi.FreightOriginKindWarehouse,
+					Name: "test-warehouse",
+				},
+				Sources: kargoapi.FreightSources{
+					Stages:           []string{"upstream-stage"},
+					RequiredSoakTime: &metav1.Duration{Duration: time.Hour},
+				},
+			}},
+		},
+	}
+	project := func(autoPromotionEnabled bool) *kargoapi.Project {
+		return &kargoapi.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: "fake-project"},
+			Spec: &kargoapi.ProjectSpec{
+				PromotionPolicies: []kargoapi.PromotionPolicy{{
+					Stage:                "test-stage",
+					AutoPromotionEnabled: autoPromotionEnabled,
+				}},
+			},
+		}
+	}
+	freight := func(name string, in time.Duration, current bool) *kargoapi.Freight {
+		f := &kargoapi.Freight{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "fake-project",
+				Name:      name,
+			},
+			Origin: kargoapi.FreightOrigin{
+				Kind: kargoapi.FreightOriginKindWarehouse,
+				Name: "test-warehouse",
+			},
+			Status: kargoapi.FreightStatus{
+				VerifiedIn: map[string]kargoapi.VerifiedStage{"upstream-stage": {}},
+			},
+		}
+		if current {
+			f.Status.CurrentlyIn = map[string]kargoapi.CurrentStage{
+				"upstream-stage": {Since: &metav1.Time{Time: now.Add(-in)}},
+			}
+		} else {
+			f.Status.VerifiedIn["upstream-stage"] = kargoapi.VerifiedStage{
+				LongestCompletedSoak: &metav1.Duration{Duration: in},
+			}
+		}
+		return f
+	}
+	warehouse := &kargoapi.Warehouse{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "test-warehouse",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		assertions func(*testing.T, time.Duration, error)
+	}{
+		{
+			name: "auto-promotion not allowed",
+			objects: []client.Object{
+				project(false),
+				warehouse,
+				freight("soaking", 50*time.Minute, true),
+			},
+			assertions: func(t *testing.T, remaining time.Duration, err error) {
+				require.NoError(t, err)
+				require.Zero(t, remaining)
+			},
+		},
+		{
+			name: "no Freight soaking",
+			objects: []client.Object{
+				project(true),
+				warehouse,
+				freight("soaked", 2*time.Hour, true),
+				// Freight that is no longer in the upstream Stage does not soak
+				// any further.
+				freight("left", 10*time.Minute, false),
+			},
+			assertions: func(t *testing.T, remaining time.Duration, err error) {
+				require.NoError(t, err)
+				require.Zero(t, remaining)
+			},
+		},
+		{
+			name: "Freight soaking",
+			objects: []client.Object{
+				project(true),
+				warehouse,
+				freight("soaking", 50*time.Minute, true),
+				freight("soaked", 2*time.Hour, true),
+			},
+			assertions: func(t *testing.T, remaining time.Duration, err error) {
+				require.NoError(t, err)
+				require.Greater(t, remaining, 9*time.Minute)
+				require.LessOrEqual(t, remaining, 10*time.Minute)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.objects...).
+				WithIndex(
+					&kargoapi.Freight{},
+					indexer.FreightByWarehouseField,
+					indexer.FreightByWarehouse,
+				).
+				WithIndex(
+					&kargoapi.Freight{},
+					indexer.FreightByVerifiedStagesField,
+					indexer.FreightByVerifiedStages,
+				).
+				Build()
+
+			r := &RegularStageReconciler{
+				client: c,
+			}
+
+			remaining, err := r.minRemainingSoakTime(context.Background(), stage)
+			tt.assertions(t, remaining, err)
+		})
+	}
+}
+
 func Test_summarizeConditions(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -6176,3 +7352,200 @@ func Test_buildFreightSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestRegularStageReconciler_replayPromotionRequests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	since := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := ReconcilerConfigFromEnv()
+	testOrigin := kargoapi.FreightOrigin{
+		Kind: kargoapi.FreightOriginKindWarehouse,
+		Name: "fake-warehouse",
+	}
+	newStage := func(annotation, lastHandled string) *kargoapi.Stage {
+		stage := &kargoapi.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "fake-project",
+				Name:      "fake-stage",
+			},
+			Spec: kargoapi.StageSpec{
+				PromotionTemplate: &kargoapi.PromotionTemplate{
+					Spec: kargoapi.PromotionTemplateSpec{
+						Steps: []kargoapi.PromotionStep{{Uses: "fake-step"}},
+					},
+				},
+			},
+			Status: kargoapi.StageStatus{
+				LastHandledReplay: lastHandled,
+				FreightHistory: kargoapi.FreightHistory{{
+					Freight: map[string]kargoapi.FreightReference{
+						testOrigin.String(): {Name: "fake-freight-current", Origin: testOrigin},
+					},
+				}},
+			},
+		}
+		if annotation != "" {
+			stage.Annotations = map[string]string{
+				kargoapi.AnnotationKeyReplayPromotionRequests: annotation,
+			}
+		}
+		return stage
+	}
+	newFreight := func(name string, created time.Time) *kargoapi.Freight {
+		return &kargoapi.Freight{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "fake-project",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Origin: testOrigin,
+		}
+	}
+	freight := []client.Object{
+		newFreight("fake-freight-current", since.Add(-time.Hour)),
+		newFreight("fake-freight-4", since),
+		newFreight("fake-freight-5", since.Add(-2*time.Hour)),
+	}
+	journaled := []events.PromotionRequested{
+		{
+			ID:      "too-old",
+			Source:  events.SourceWebhook,
+			Project: "fake-project",
+			Stage:   "fake-stage",
+			Freight: "fake-freight-1",
+			Time:    since.Add(-time.Minute),
+		},
+		{
+			ID:      "other-stage",
+			Source:  events.SourceWebhook,
+			Project: "fake-project",
+			Stage:   "other-stage",
+			Freight: "fake-freight-2",
+			Time:    since.Add(time.Minute),
+		},
+		{
+			ID:      "already-promoted",
+			Source:  events.SourceWebhook,
+			Project: "fake-project",
+			Stage:   "fake-stage",
+			Freight: "fake-freight-3",
+			Time:    since.Add(time.Minute),
+		},
+		{
+			ID:      "missed",
+			Source:  events.SourceWebhook,
+			Project: "fake-project",
+			Stage:   "fake-stage",
+			Freight: "fake-freight-4",
+			Actor:   "fake-actor",
+			Annotations: map[string]string{
+				"forged": "true",
+			},
+			Time: since.Add(2 * time.Minute),
+		},
+		{
+			// The Freight is older than the Stage's current Freight.
+			ID:      "superseded",
+			Source:  events.SourceWebhook,
+			Project: "fake-project",
+			Stage:   "fake-stage",
+			Freight: "fake-freight-5",
+			Time:    since.Add(3 * time.Minute),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		stage      *kargoapi.Stage
+		assertions func(*testing.T, client.Client, kargoapi.StageStatus, error)
+	}{
+		{
+			name:  "no replay requested",
+			stage: newStage("", ""),
+			assertions: func(t *testing.T, c client.Client, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				require.Empty(t, status.LastHandledReplay)
+				promos := &kargoapi.PromotionList{}
+				require.NoError(t, c.List(context.Background(), promos))
+				require.Len(t, promos.Items, 1)
+			},
+		},
+		{
+			name:  "replay already handled",
+			stage: newStage(since.Format(time.RFC3339), since.Format(time.RFC3339)),
+			assertions: func(t *testing.T, c client.Client, _ kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				promos := &kargoapi.PromotionList{}
+				require.NoError(t, c.List(context.Background(), promos))
+				require.Len(t, promos.Items, 1)
+			},
+		},
+		{
+			name:  "invalid replay value",
+			stage: newStage("yesterday", ""),
+			assertions: func(t *testing.T, c client.Client, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "yesterday", status.LastHandledReplay)
+				promos := &kargoapi.PromotionList{}
+				require.NoError(t, c.List(context.Background(), promos))
+				require.Len(t, promos.Items, 1)
+			},
+		},
+		{
+			name:  "missed requests are replayed",
+			stage: newStage(since.Format(time.RFC3339), ""),
+			assertions: func(t *testing.T, c client.Client, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				require.Equal(t, since.Format(time.RFC3339), status.LastHandledReplay)
+				promos := &kargoapi.PromotionList{}
+				require.NoError(t, c.List(context.Background(), promos))
+				require.Len(t, promos.Items, 2)
+				var replayed *kargoapi.Promotion
+				for i := range promos.Items {
+					if promos.Items[i].Spec.Freight == "fake-freight-4" {
+						replayed = &promos.Items[i]
+					}
+				}
+				require.NotNil(t, replayed)
+				require.Equal(t, "missed", replayed.Annotations[kargoapi.AnnotationKeyPromotionRequest])
+				// The recorded actor is not trusted.
+				require.Equal(
+					t,
+					kargoapi.FormatEventControllerActor(cfg.Name()),
+					replayed.Annotations[kargoapi.AnnotationKeyCreateActor],
+				)
+				require.NotContains(t, replayed.Annotations, "forged")
+				require.Equal(
+					t,
+					string(events.SourceWebhook),
+					replayed.Labels[kargoapi.PromotionSourceLabelKey],
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(freight...).Build()
+			ctx := context.Background()
+			journal := events.NewJournal(c, events.DefaultJournalSize)
+			creator := events.NewPromotionCreator(c, events.PromotionCreatorOptions{})
+			for i := range journaled {
+				require.NoError(t, journal.Append(ctx, &journaled[i]))
+			}
+			// The request that was already promoted must not be promoted again.
+			_, err := creator.Replay(ctx, tt.stage, &journaled[2])
+			require.NoError(t, err)
+
+			r := &RegularStageReconciler{
+				cfg:              cfg,
+				client:           c,
+				promotionCreator: creator,
+				promotionJournal: journal,
+			}
+			status, err := r.replayPromotionRequests(ctx, tt.stage)
+			tt.assertions(t, c, status, err)
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package stages
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// summarizeImages computes the StageImages of the provided Stage. Everything
+// it needs is read from the status of the Stage and related resources, so no
+// calls to container image registries or Git repositories are made.
+func (r *RegularStageReconciler) summarizeImages(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) (kargoapi.StageStatus, error) {
+	newStatus := *stage.Status.DeepCopy()
+
+	images := &kargoapi.StageImages{
+		Current:       imagesFromFreight(stage.Status.FreightHistory.Current().References()...),
+		CurrentSource: kargoapi.StageImagesSourceFreightHistory,
+	}
+	if len(images.Current) == 0 {
+		if images.Current = r.getArgoCDAppImages(ctx, stage); len(images.Current) > 0 {
+			images.CurrentSource = kargoapi.StageImagesSourceArgoCDApps
+		} else {
+			images.CurrentSource = ""
+		}
+	}
+
+	next, err := r.getNextImages(ctx, stage)
+	if err != nil {
+		return newStatus, err
+	}
+	images.Next = next
+
+	if images.Upstream, err = r.compareUpstreamImages(ctx, stage, images.Current); err != nil {
+		return newStatus, err
+	}
+
+	if len(images.Current) == 0 && len(images.Next) == 0 && len(images.Upstream) == 0 {
+		images = nil
+	}
+	newStatus.Images = images
+	return newStatus, nil
+}
+
+// getNextImages returns the images of the Freight that is being promoted to
+// the provided Stage or, if no Promotion is running, of the Freight of the
+// first queued Promotion.
+func (r *RegularStageReconciler) getNextImages(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) ([]kargoapi.Image, error) {
+	if cur := stage.Status.CurrentPromotion; cur != nil && cur.Freight != nil {
+		return imagesFromFreight(*cur.Freight), nil
+	}
+	pending := stage.Status.PromotionQueue.GetPending()
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	promo, err := kargoapi.GetPromotion(ctx, r.client, types.NamespacedName{
+		Namespace: stage.Namespace,
+		Name:      pending[0],
+	})
+	if err != nil || promo == nil {
+		return nil, err
+	}
+	freight, err := kargoapi.GetFreight(ctx, r.client, types.NamespacedName{
+		Namespace: stage.Namespace,
+		Name:      promo.Spec.Freight,
+	})
+	if err != nil || freight == nil {
+		return nil, err
+	}
+	return imagesFromFreight(kargoapi.FreightReference{Images: freight.Images}), nil
+}
+
+// getArgoCDAppImages returns the images that the Argo CD Applications managed
+// by the provided Stage report to be running. Failures are logged rather than
+// returned, as these images are only a fallback for Stages without history.
+func (r *RegularStageReconciler) getArgoCDAppImages(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) []kargoapi.Image {
+	if len(stage.Spec.ArgoCDApps) == 0 {
+		return nil
+	}
+	logger := logging.LoggerFromContext(ctx)
+	argoCDCtx, err := r.argoCDContexts.Get(stage.Spec.ArgoCDContext)
+	if err != nil || argoCDCtx == nil {
+		logger.Debug("cannot read images from Argo CD Applications", "error", err)
+		return nil
+	}
+	var images []kargoapi.Image
+	for _, tmpl := range stage.Spec.ArgoCDApps {
+		app := newUnstructuredArgoCDApp(tmpl, argoCDCtx.Namespace)
+		if err = argoCDCtx.Client.Get(ctx, client.ObjectKeyFromObject(app), app); err != nil {
+			logger.Debug(
+				"cannot read images from Argo CD Application",
+				"app", app.GetName(),
+				"error", err,
+			)
+			continue
+		}
+		refs, _, _ := unstructured.NestedStringSlice(app.Object, "status", "summary", "images")
+		for _, ref := range refs {
+			img := parseImageReference(ref)
+			if !slices.ContainsFunc(images, func(existing kargoapi.Image) bool {
+				return existing.DeepEquals(&img)
+			}) {
+				images = append(images, img)
+			}
+		}
+	}
+	sortImages(images)
+	return images
+}
+
+// compareUpstreamImages compares the provided images, which have been promoted
+// to the provided Stage, with those that have been promoted to each of the
+// Stage's upstream Stages. Upstream Stages that do not exist are ignored.
+func (r *RegularStageReconciler) compareUpstreamImages(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	current []kargoapi.Image,
+) ([]kargoapi.UpstreamStageImages, error) {
+	// Map each upstream Stage to the Warehouses Freight is requested from it
+	// for, as these discovered the versions we count.
+	warehousesByUpstream := map[string][]string{}
+	for _, req := range stage.Spec.RequestedFreight {
+		for _, upstream := range req.Sources.Stages {
+			warehouses := warehousesByUpstream[upstream]
+			if req.Origin.Kind == kargoapi.FreightOriginKindWarehouse &&
+				!slices.Contains(warehouses, req.Origin.Name) {
+				warehouses = append(warehouses, req.Origin.Name)
+			}
+			warehousesByUpstream[upstream] = warehouses
+		}
+	}
+	if len(warehousesByUpstream) == 0 {
+		return nil, nil
+	}
+
+	// Tags discovered by each Warehouse, indexed by image repository URL.
+	discoveredTags := map[string]map[string][]string{}
+	getDiscoveredTags := func(warehouseName string) (map[string][]string, error) {
+		if tags, ok := discoveredTags[warehouseName]; ok {
+			return tags, nil
+		}
+		warehouse, err := kargoapi.GetWarehouse(ctx, r.client, types.NamespacedName{
+			Namespace: stage.Namespace,
+			Name:      warehouseName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error getting Warehouse %q in namespace %q: %w",
+				warehouseName, stage.Namespace, err,
+			)
+		}
+		tags := map[string][]string{}
+		if warehouse != nil && warehouse.Status.DiscoveredArtifacts != nil {
+			for _, result := range warehouse.Status.DiscoveredArtifacts.Images {
+				for _, ref := range result.References {
+					tags[result.RepoURL] = append(tags[result.RepoURL], ref.Tag)
+				}
+			}
+		}
+		discoveredTags[warehouseName] = tags
+		return tags, nil
+	}
+
+	upstreamNames := make([]string, 0, len(warehousesByUpstream))
+	for name := range warehousesByUpstream {
+		upstreamNames = append(upstreamNames, name)
+	}
+	slices.Sort(upstreamNames)
+
+	var comparisons []kargoapi.UpstreamStageImages
+	for _, upstreamName := range upstreamNames {
+		upstream, err := kargoapi.GetStage(ctx, r.client, types.NamespacedName{
+			Namespace: stage.Namespace,
+			Name:      upstreamName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error getting upstream Stage %q in namespace %q: %w",
+				upstreamName, stage.Namespace, err,
+			)
+		}
+		if upstream == nil {
+			continue
+		}
+		upstreamImages := upstream.Status.Images.GetCurrent()
+		if len(upstreamImages) == 0 {
+			upstreamImages = imagesFromFreight(upstream.Status.FreightHistory.Current().References()...)
+		}
+
+		comparison := kargoapi.UpstreamStageImages{Stage: upstreamName}
+		for _, upstreamImg := range upstreamImages {
+			diff := kargoapi.ImageDifference{
+				RepoURL:         upstreamImg.RepoURL,
+				UpstreamVersion: imageVersion(upstreamImg),
+			}
+			if i := slices.IndexFunc(current, func(img kargoapi.Image) bool {
+				return img.RepoURL == upstreamImg.RepoURL
+			}); i >= 0 {
+				diff.Version = imageVersion(current[i])
+			}
+			if diff.Version == diff.UpstreamVersion {
+				continue
+			}
+			for _, warehouseName := range warehousesByUpstream[upstreamName] {
+				tags, err := getDiscoveredTags(warehouseName)
+				if err != nil {
+					return nil, err
+				}
+				if behind, ok := countVersionsBehind(
+					tags[upstreamImg.RepoURL],
+					diff.Version,
+					diff.UpstreamVersion,
+				); ok {
+					diff.VersionsBehind = &behind
+					break
+				}
+			}
+			comparison.Differences = append(comparison.Differences, diff)
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons, nil
+}
+
+// countVersionsBehind counts the provided tags that are semantic versions
+// greater than from and less than or equal to to. If from is greater than to,
+// the count is negated. False is returned if from or to is not a semantic
+// version, or if none of the tags are.
+func countVersionsBehind(tags []string, from, to string) (int32, bool) {
+	fromVer, err := semver.NewVersion(from)
+	if err != nil {
+		return 0, false
+	}
+	toVer, err := semver.NewVersion(to)
+	if err != nil {
+		return 0, false
+	}
+	sign := int32(1)
+	if fromVer.GreaterThan(toVer) {
+		fromVer, toVer = toVer, fromVer
+		sign = -1
+	}
+	var count int32
+	var anySemver bool
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		ver, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		anySemver = true
+		if _, ok := seen[ver.String()]; ok {
+			continue
+		}
+		seen[ver.String()] = struct{}{}
+		if ver.GreaterThan(fromVer) && !ver.GreaterThan(toVer) {
+			count++
+		}
+	}
+	if !anySemver {
+		return 0, false
+	}
+	return sign * count, true
+}
+
+// imagesFromFreight returns the images referenced by the provided Freight,
+// ordered by repository URL. Where several pieces of Freight reference the
+// same image, the first reference wins.
+func imagesFromFreight(freight ...kargoapi.FreightReference) []kargoapi.Image {
+	var images []kargoapi.Image
+	for _, f := range freight {
+		for _, img := range f.Images {
+			if slices.ContainsFunc(images, func(existing kargoapi.Image) bool {
+				return existing.RepoURL == img.RepoURL
+			}) {
+				continue
+			}
+			images = append(images, kargoapi.Image{
+				RepoURL: img.RepoURL,
+				Tag:     img.Tag,
+				Digest:  img.Digest,
+			})
+		}
+	}
+	sortImages(images)
+	return images
+}
+
+func sortImages(images []kargoapi.Image) {
+	slices.SortFunc(images, func(lhs, rhs kargoapi.Image) int {
+		return strings.Compare(lhs.RepoURL, rhs.RepoURL)
+	})
+}
+
+// imageVersion returns the tag of the provided image or, if it has none, its
+// digest.
+func imageVersion(img kargoapi.Image) string {
+	if img.Tag != "" {
+		return img.Tag
+	}
+	return img.Digest
+}
+
+// parseImageReference parses an image reference of the form reported by Argo
+// CD, i.e. repo:tag or repo@digest.
+func parseImageReference(ref string) kargoapi.Image {
+	if repo, digest, ok := strings.Cut(ref, "@"); ok {
+		return kargoapi.Image{RepoURL: repo, Digest: digest}
+	}
+	// A colon before the last slash separates a registry host from its port
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return kargoapi.Image{RepoURL: ref[:i], Tag: ref[i+1:]}
+	}
+	return kargoapi.Image{RepoURL: ref}
+}
@@ -0,0 +1,314 @@
+package stages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
+	argocdapi "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+func TestRegularStageReconciler_summarizeImages(t *testing.T) {
+	const testProject = "fake-project"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+
+	newFreightCollection := func(images ...kargoapi.Image) kargoapi.FreightHistory {
+		return kargoapi.FreightHistory{{
+			Freight: map[string]kargoapi.FreightReference{
+				"Warehouse/fake-warehouse": {
+					Name: "fake-freight",
+					Origin: kargoapi.FreightOrigin{
+						Kind: kargoapi.FreightOriginKindWarehouse,
+						Name: "fake-warehouse",
+					},
+					Images: images,
+				},
+			},
+		}}
+	}
+	requestedFreight := []kargoapi.FreightRequest{{
+		Origin: kargoapi.FreightOrigin{
+			Kind: kargoapi.FreightOriginKindWarehouse,
+			Name: "fake-warehouse",
+		},
+		Sources: kargoapi.FreightSources{Stages: []string{"upstream", "missing"}},
+	}}
+	warehouse := &kargoapi.Warehouse{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "fake-warehouse"},
+		Status: kargoapi.WarehouseStatus{
+			DiscoveredArtifacts: &kargoapi.DiscoveredArtifacts{
+				Images: []kargoapi.ImageDiscoveryResult{{
+					RepoURL: "example/app",
+					References: []kargoapi.DiscoveredImageReference{
+						{Tag: "1.4.0"},
+						{Tag: "1.3.0"},
+						{Tag: "1.2.1"},
+						{Tag: "1.2.0"},
+						{Tag: "latest"},
+					},
+				}},
+			},
+		},
+	}
+	upstream := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "upstream"},
+		Status: kargoapi.StageStatus{
+			FreightHistory: newFreightCollection(
+				kargoapi.Image{RepoURL: "example/app", Tag: "1.4.0"},
+				kargoapi.Image{RepoURL: "example/sidecar", Tag: "2.0.0"},
+				kargoapi.Image{RepoURL: "example/worker", Digest: "sha256:abc"},
+			),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		stage      *kargoapi.Stage
+		objects    []client.Object
+		assertions func(*testing.T, kargoapi.StageStatus, error)
+	}{
+		{
+			name: "nothing to summarize",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "fake-stage"},
+				Status: kargoapi.StageStatus{
+					Images: &kargoapi.StageImages{
+						Current: []kargoapi.Image{{RepoURL: "example/app", Tag: "stale"}},
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				assert.Nil(t, status.Images)
+			},
+		},
+		{
+			name: "current, next, and upstream images",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "fake-stage"},
+				Spec:       kargoapi.StageSpec{RequestedFreight: requestedFreight},
+				Status: kargoapi.StageStatus{
+					FreightHistory: newFreightCollection(
+						kargoapi.Image{RepoURL: "example/worker", Digest: "sha256:abc"},
+						kargoapi.Image{RepoURL: "example/app", Tag: "1.2.0"},
+					),
+					PromotionQueue: &kargoapi.PromotionQueue{
+						Pending: []string{"fake-promotion"},
+					},
+				},
+			},
+			objects: []client.Object{
+				warehouse,
+				upstream,
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "fake-promotion"},
+					Spec:       kargoapi.PromotionSpec{Freight: "next-freight"},
+				},
+				&kargoapi.Freight{
+					ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "next-freight"},
+					Images:     []kargoapi.Image{{RepoURL: "example/app", Tag: "1.3.0"}},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &kargoapi.StageImages{
+					Current: []kargoapi.Image{
+						{RepoURL: "example/app", Tag: "1.2.0"},
+						{RepoURL: "example/worker", Digest: "sha256:abc"},
+					},
+					CurrentSource: kargoapi.StageImagesSourceFreightHistory,
+					Next:          []kargoapi.Image{{RepoURL: "example/app", Tag: "1.3.0"}},
+					Upstream: []kargoapi.UpstreamStageImages{{
+						Stage: "upstream",
+						Differences: []kargoapi.ImageDifference{
+							{
+								RepoURL:         "example/app",
+								Version:         "1.2.0",
+								UpstreamVersion: "1.4.0",
+								VersionsBehind:  ptr.To[int32](3),
+							},
+							{
+								RepoURL:         "example/sidecar",
+								UpstreamVersion: "2.0.0",
+							},
+						},
+					}},
+				}, status.Images)
+			},
+		},
+		{
+			name: "running Promotion",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "fake-stage"},
+				Status: kargoapi.StageStatus{
+					CurrentPromotion: &kargoapi.PromotionReference{
+						Name: "fake-promotion",
+						Freight: &kargoapi.FreightReference{
+							Images: []kargoapi.Image{{RepoURL: "example/app", Tag: "1.3.0"}},
+						},
+					},
+					PromotionQueue: &kargoapi.PromotionQueue{
+						Pending: []string{"missing-promotion"},
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &kargoapi.StageImages{
+					Next: []kargoapi.Image{{RepoURL: "example/app", Tag: "1.3.0"}},
+				}, status.Images)
+			},
+		},
+		{
+			name: "no history falls back to Argo CD Applications",
+			stage: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testProject, Name: "fake-stage"},
+				Spec: kargoapi.StageSpec{
+					ArgoCDApps: []kargoapi.ManagedArgoCDApp{
+						{Name: "fake-app"},
+						{Name: "missing-app"},
+					},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.StageStatus, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &kargoapi.StageImages{
+					Current: []kargoapi.Image{
+						{RepoURL: "example/app", Tag: "1.2.0"},
+						{RepoURL: "registry.example.com:5000/worker", Digest: "sha256:abc"},
+					},
+					CurrentSource: kargoapi.StageImagesSourceArgoCDApps,
+				}, status.Images)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RegularStageReconciler{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(append(tt.objects, tt.stage)...).
+					Build(),
+				argoCDContexts: libargocd.NewContexts(&libargocd.Context{
+					Namespace: "argocd",
+					Client: newFakeUnstructuredArgoCDClient(t, map[string]any{
+						"metadata": map[string]any{
+							"name":      "fake-app",
+							"namespace": "argocd",
+						},
+						"status": map[string]any{
+							"summary": map[string]any{
+								"images": []any{
+									"example/app:1.2.0",
+									"registry.example.com:5000/worker@sha256:abc",
+									"example/app:1.2.0",
+								},
+							},
+						},
+					}),
+				}),
+			}
+			status, err := r.summarizeImages(context.Background(), tt.stage)
+			tt.assertions(t, status, err)
+		})
+	}
+}
+
+func Test_countVersionsBehind(t *testing.T) {
+	tags := []string{"v1.3.0", "1.2.0", "1.1.0", "1.1.0", "1.0.0", "latest"}
+	testCases := []struct {
+		name     string
+		tags     []string
+		from     string
+		to       string
+		expected *int32
+	}{
+		{
+			name:     "behind",
+			tags:     tags,
+			from:     "1.0.0",
+			to:       "1.3.0",
+			expected: ptr.To[int32](3),
+		},
+		{
+			name:     "ahead",
+			tags:     tags,
+			from:     "1.2.0",
+			to:       "1.1.0",
+			expected: ptr.To[int32](-1),
+		},
+		{
+			name: "not a semantic version",
+			tags: tags,
+			from: "1.0.0",
+			to:   "latest",
+		},
+		{
+			name: "no semantic versions discovered",
+			tags: []string{"latest"},
+			from: "1.0.0",
+			to:   "1.1.0",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			count, ok := countVersionsBehind(testCase.tags, testCase.from, testCase.to)
+			if testCase.expected == nil {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, *testCase.expected, count)
+		})
+	}
+}
+
+func Test_parseImageReference(t *testing.T) {
+	testCases := map[string]kargoapi.Image{
+		"nginx":                           {RepoURL: "nginx"},
+		"nginx:1.21.0":                    {RepoURL: "nginx", Tag: "1.21.0"},
+		"nginx@sha256:abc":                {RepoURL: "nginx", Digest: "sha256:abc"},
+		"registry.example.com:5000/nginx": {RepoURL: "registry.example.com:5000/nginx"},
+		"registry.example.com:5000/nginx:1.21.0": {
+			RepoURL: "registry.example.com:5000/nginx",
+			Tag:     "1.21.0",
+		},
+	}
+	for ref, expected := range testCases {
+		t.Run(ref, func(t *testing.T) {
+			require.Equal(t, expected, parseImageReference(ref))
+		})
+	}
+}
+
+func newFakeUnstructuredArgoCDClient(t *testing.T, apps ...map[string]any) client.Client {
+	t.Helper()
+	gvk := argocdapi.GroupVersion.WithKind("Application")
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(gvk, meta.RESTScopeNamespace)
+	objects := make([]client.Object, len(apps))
+	for i, app := range apps {
+		u := &unstructured.Unstructured{Object: app}
+		u.SetGroupVersionKind(gvk)
+		objects[i] = u
+	}
+	return fake.NewClientBuilder().
+		WithScheme(runtime.NewScheme()).
+		WithRESTMapper(restMapper).
+		WithObjects(objects...).
+		Build()
+}
@@ -78,6 +78,7 @@ func (r *reconciler) discoverCommits(
 				Username:      creds.Username,
 				Password:      creds.Password,
 				SSHPrivateKey: creds.SSHPrivateKey,
+				HTTPAuthMode:  git.HTTPAuthMode(creds.HTTPAuthMode),
 			}
 			repoLogger.Debug("obtained credentials for git repo")
 		} else {
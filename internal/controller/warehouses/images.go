@@ -2,7 +2,9 @@ package warehouses
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,11 +37,14 @@ func (r *reconciler) discoverImages(
 		// Obtain credentials for the image repository.
 		creds, ok, err := r.credentialsDB.Get(ctx, namespace, credentials.TypeImage, sub.RepoURL)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"error obtaining credentials for image repo %q: %w",
-				sub.RepoURL,
-				err,
-			)
+			return nil, &imageSubscriptionError{
+				name: imageSubscriptionName(sub),
+				err: fmt.Errorf(
+					"error obtaining credentials for image repo %q: %w",
+					sub.RepoURL,
+					err,
+				),
+			}
 		}
 		var regCreds *image.Credentials
 		if ok {
@@ -58,11 +63,14 @@ func (r *reconciler) discoverImages(
 		// Discover the latest suitable images.
 		images, err := r.discoverImageRefsFn(ctx, sub, regCreds)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"error discovering latest images %q: %w",
-				sub.RepoURL,
-				err,
-			)
+			return nil, &imageSubscriptionError{
+				name: imageSubscriptionName(sub),
+				err: fmt.Errorf(
+					"error discovering latest images %q: %w",
+					sub.RepoURL,
+					err,
+				),
+			}
 		}
 		if len(images) == 0 {
 			results = append(results, kargoapi.ImageDiscoveryResult{
@@ -100,6 +108,147 @@ func (r *reconciler) discoverImages(
 	return results, nil
 }
 
+// imageSubscriptionError is an error indicating that the discovery of images
+// for a particular ImageSubscription failed.
+type imageSubscriptionError struct {
+	name string
+	err  error
+}
+
+// Error implements the error interface.
+func (e *imageSubscriptionError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *imageSubscriptionError) Unwrap() error {
+	return e.err
+}
+
+// imageSubscriptionName returns the name under which the status of the
+// provided ImageSubscription is reported, which is its RepoURL if it has no
+// name.
+func imageSubscriptionName(sub kargoapi.ImageSubscription) string {
+	if sub.Name != "" {
+		return sub.Name
+	}
+	return sub.RepoURL
+}
+
+// withoutPausedImageSubscriptions returns the provided subscriptions, less any
+// image subscriptions for which discovery is paused.
+func withoutPausedImageSubscriptions(
+	subs []kargoapi.RepoSubscription,
+) []kargoapi.RepoSubscription {
+	unpaused := make([]kargoapi.RepoSubscription, 0, len(subs))
+	for _, s := range subs {
+		if s.Image == nil || !s.Image.Paused {
+			unpaused = append(unpaused, s)
+		}
+	}
+	return unpaused
+}
+
+// withPausedImageResults returns the provided results of discovering images
+// for the provided Warehouse's unpaused image subscriptions, merged in the
+// order of the subscriptions with the results last discovered for its paused
+// ones. This way, the images last discovered for paused subscriptions remain
+// part of new Freight, while newer images are not picked up.
+func withPausedImageResults(
+	warehouse *kargoapi.Warehouse,
+	discovered []kargoapi.ImageDiscoveryResult,
+) []kargoapi.ImageDiscoveryResult {
+	if !slices.ContainsFunc(warehouse.Spec.Subscriptions, func(s kargoapi.RepoSubscription) bool {
+		return s.Image != nil && s.Image.Paused
+	}) {
+		return discovered
+	}
+	var previous []kargoapi.ImageDiscoveryResult
+	if warehouse.Status.DiscoveredArtifacts != nil {
+		previous = warehouse.Status.DiscoveredArtifacts.Images
+	}
+	results := make([]kargoapi.ImageDiscoveryResult, 0, len(discovered))
+	for _, s := range warehouse.Spec.Subscriptions {
+		if s.Image == nil {
+			continue
+		}
+		if !s.Image.Paused {
+			if len(discovered) > 0 {
+				results = append(results, discovered[0])
+				discovered = discovered[1:]
+			}
+			continue
+		}
+		result := kargoapi.ImageDiscoveryResult{
+			RepoURL:  s.Image.RepoURL,
+			Platform: s.Image.Platform,
+		}
+		for _, p := range previous {
+			if p.RepoURL == s.Image.RepoURL && p.Platform == s.Image.Platform {
+				result = p
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// updateImageSubscriptionStatuses updates the statuses of the provided
+// Warehouse's image subscriptions in the provided WarehouseStatus with the
+// outcome of discovering artifacts, which either resulted in the provided
+// artifacts or failed with the provided error. Statuses of subscriptions that
+// no longer exist are removed.
+func updateImageSubscriptionStatuses(
+	warehouse *kargoapi.Warehouse,
+	status *kargoapi.WarehouseStatus,
+	artifacts *kargoapi.DiscoveredArtifacts,
+	discoveryErr error,
+) {
+	var subErr *imageSubscriptionError
+	if !errors.As(discoveryErr, &subErr) {
+		subErr = nil
+	}
+	statuses := make([]kargoapi.ImageSubscriptionStatus, 0, len(status.ImageSubscriptions))
+	for _, s := range warehouse.Spec.Subscriptions {
+		if s.Image == nil {
+			continue
+		}
+		sub := *s.Image
+		subStatus := kargoapi.ImageSubscriptionStatus{
+			Name:    imageSubscriptionName(sub),
+			RepoURL: sub.RepoURL,
+		}
+		for _, existing := range status.ImageSubscriptions {
+			if existing.Name == subStatus.Name {
+				subStatus = *existing.DeepCopy()
+				subStatus.RepoURL = sub.RepoURL
+				break
+			}
+		}
+		subStatus.Paused = sub.Paused
+		switch {
+		case sub.Paused:
+		case subErr != nil && subErr.name == subStatus.Name:
+			subStatus.Error = subErr.Error()
+		case discoveryErr == nil && artifacts != nil:
+			subStatus.LastDiscoveredAt = artifacts.DiscoveredAt.DeepCopy()
+			subStatus.Error = ""
+			for _, result := range artifacts.Images {
+				if result.RepoURL == sub.RepoURL && result.Platform == sub.Platform {
+					subStatus.LastTag = ""
+					if len(result.References) > 0 {
+						subStatus.LastTag = result.References[0].Tag
+					}
+					break
+				}
+			}
+		}
+		statuses = append(statuses, subStatus)
+	}
+	status.ImageSubscriptions = statuses
+}
+
 func (r *reconciler) discoverImageRefs(
 	ctx context.Context,
 	sub kargoapi.ImageSubscription,
@@ -2,10 +2,13 @@ package warehouses
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/credentials"
@@ -209,3 +212,128 @@ func TestGetGithubImageSourceURL(t *testing.T) {
 		})
 	}
 }
+
+func TestWithPausedImageResults(t *testing.T) {
+	warehouse := &kargoapi.Warehouse{
+		Spec: kargoapi.WarehouseSpec{
+			Subscriptions: []kargoapi.RepoSubscription{
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example/api", Paused: true}},
+				{Git: &kargoapi.GitSubscription{RepoURL: "https://github.com/example/repo"}},
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example/web"}},
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example/worker", Paused: true}},
+			},
+		},
+		Status: kargoapi.WarehouseStatus{
+			DiscoveredArtifacts: &kargoapi.DiscoveredArtifacts{
+				Images: []kargoapi.ImageDiscoveryResult{
+					{
+						RepoURL:    "example/api",
+						References: []kargoapi.DiscoveredImageReference{{Tag: "v1.0.0"}},
+					},
+					{
+						RepoURL:    "example/web",
+						References: []kargoapi.DiscoveredImageReference{{Tag: "v2.0.0"}},
+					},
+				},
+			},
+		},
+	}
+
+	// Only unpaused subscriptions are discovered
+	unpaused := withoutPausedImageSubscriptions(warehouse.Spec.Subscriptions)
+	require.Len(t, unpaused, 2)
+	require.Equal(t, "example/web", unpaused[1].Image.RepoURL)
+
+	require.Equal(
+		t,
+		[]kargoapi.ImageDiscoveryResult{
+			{
+				RepoURL:    "example/api",
+				References: []kargoapi.DiscoveredImageReference{{Tag: "v1.0.0"}},
+			},
+			{
+				RepoURL:    "example/web",
+				References: []kargoapi.DiscoveredImageReference{{Tag: "v2.1.0"}},
+			},
+			{RepoURL: "example/worker"},
+		},
+		withPausedImageResults(
+			warehouse,
+			[]kargoapi.ImageDiscoveryResult{{
+				RepoURL:    "example/web",
+				References: []kargoapi.DiscoveredImageReference{{Tag: "v2.1.0"}},
+			}},
+		),
+	)
+}
+
+func TestUpdateImageSubscriptionStatuses(t *testing.T) {
+	lastDiscoveredAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	warehouse := &kargoapi.Warehouse{
+		Spec: kargoapi.WarehouseSpec{
+			Subscriptions: []kargoapi.RepoSubscription{
+				{Image: &kargoapi.ImageSubscription{Name: "api", RepoURL: "example/api"}},
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example/web", Paused: true}},
+			},
+		},
+	}
+	status := &kargoapi.WarehouseStatus{
+		ImageSubscriptions: []kargoapi.ImageSubscriptionStatus{
+			{
+				Name:             "example/web",
+				RepoURL:          "example/web",
+				LastDiscoveredAt: &lastDiscoveredAt,
+				LastTag:          "v1.0.0",
+				LastFreightID:    "fake-freight",
+			},
+			{Name: "removed", RepoURL: "example/removed"},
+		},
+	}
+
+	t.Run("discovery succeeded", func(t *testing.T) {
+		status := status.DeepCopy()
+		artifacts := &kargoapi.DiscoveredArtifacts{
+			DiscoveredAt: metav1.Now(),
+			Images: []kargoapi.ImageDiscoveryResult{
+				{
+					RepoURL:    "example/api",
+					References: []kargoapi.DiscoveredImageReference{{Tag: "v2.0.0"}},
+				},
+				{
+					RepoURL:    "example/web",
+					References: []kargoapi.DiscoveredImageReference{{Tag: "v1.0.0"}},
+				},
+			},
+		}
+		updateImageSubscriptionStatuses(warehouse, status, artifacts, nil)
+		require.Len(t, status.ImageSubscriptions, 2)
+
+		api := status.ImageSubscriptions[0]
+		require.Equal(t, "api", api.Name)
+		require.Equal(t, "v2.0.0", api.LastTag)
+		require.Equal(t, &artifacts.DiscoveredAt, api.LastDiscoveredAt)
+		require.Empty(t, api.Error)
+
+		// The status of a paused subscription is left as it was
+		web := status.ImageSubscriptions[1]
+		require.True(t, web.Paused)
+		require.Equal(t, &lastDiscoveredAt, web.LastDiscoveredAt)
+		require.Equal(t, "fake-freight", web.LastFreightID)
+	})
+
+	t.Run("discovery failed for a subscription", func(t *testing.T) {
+		status := status.DeepCopy()
+		updateImageSubscriptionStatuses(
+			warehouse,
+			status,
+			nil,
+			fmt.Errorf("error discovering images: %w", &imageSubscriptionError{
+				name: "api",
+				err:  errors.New("unauthorized"),
+			}),
+		)
+		require.Len(t, status.ImageSubscriptions, 2)
+		require.Equal(t, "unauthorized", status.ImageSubscriptions[0].Error)
+		require.Nil(t, status.ImageSubscriptions[0].LastDiscoveredAt)
+	})
+}
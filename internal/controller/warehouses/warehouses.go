@@ -3,6 +3,7 @@ package warehouses
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -198,8 +199,16 @@ func (r *reconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
-	// Everything succeeded, look for new changes on the defined interval.
-	return ctrl.Result{RequeueAfter: getRequeueInterval(warehouse)}, nil
+	// Everything succeeded, look for new changes on the defined interval, or
+	// sooner if Freight creation was deferred.
+	requeueAfter := getRequeueInterval(warehouse)
+	if pending := newStatus.PendingFreight; pending != nil {
+		requeueAfter = min(
+			requeueAfter,
+			max(time.Until(pending.Since.Add(warehouse.Spec.FreightBatchWindow.Duration)), 0),
+		)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *reconciler) syncWarehouse(
@@ -254,6 +263,7 @@ func (r *reconciler) syncWarehouse(
 
 		// Discover the latest artifacts.
 		discoveredArtifacts, err := r.discoverArtifactsFn(ctx, warehouse)
+		updateImageSubscriptionStatuses(warehouse, &status, discoveredArtifacts, err)
 		if err != nil {
 			// Mark the Warehouse as unhealthy and not ready if we failed to
 			// discover artifacts.
@@ -346,6 +356,26 @@ func (r *reconciler) syncWarehouse(
 			Name: warehouse.Name,
 		}
 
+		// Defer creation of the Freight if the Warehouse batches changes and
+		// the batch window has not yet elapsed.
+		if !batchFreight(warehouse, &status, freight, metav1.Now()) {
+			conditions.Delete(&status, kargoapi.ConditionTypeReconciling)
+			conditions.Set(
+				&status,
+				&metav1.Condition{
+					Type:   kargoapi.ConditionTypeReady,
+					Status: metav1.ConditionTrue,
+					Reason: "FreightBatchPending",
+					Message: fmt.Sprintf(
+						"Freight creation from latest artifacts is deferred until %s",
+						status.PendingFreight.Since.Add(warehouse.Spec.FreightBatchWindow.Duration).
+							Format(time.RFC3339),
+					),
+				},
+			)
+			return status, nil
+		}
+
 		// Attempt to create the Freight.
 		if err = r.createFreightFn(ctx, freight); client.IgnoreAlreadyExists(err) != nil {
 			// Make the error visible in the status and mark the Warehouse as
@@ -380,6 +410,11 @@ func (r *reconciler) syncWarehouse(
 		}
 
 		status.LastFreightID = freight.Name
+		for i := range status.ImageSubscriptions {
+			if !status.ImageSubscriptions[i].Paused {
+				status.ImageSubscriptions[i].LastFreightID = freight.Name
+			}
+		}
 	}
 
 	// Remove the reconciling condition and mark the Warehouse as ready.
@@ -406,10 +441,15 @@ func (r *reconciler) discoverArtifacts(
 		return nil, fmt.Errorf("error discovering commits: %w", err)
 	}
 
-	images, err := r.discoverImagesFn(ctx, warehouse.Namespace, warehouse.Spec.Subscriptions)
+	images, err := r.discoverImagesFn(
+		ctx,
+		warehouse.Namespace,
+		withoutPausedImageSubscriptions(warehouse.Spec.Subscriptions),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error discovering images: %w", err)
 	}
+	images = withPausedImageResults(warehouse, images)
 
 	charts, err := r.discoverChartsFn(ctx, warehouse.Namespace, warehouse.Spec.Subscriptions)
 	if err != nil {
@@ -661,6 +701,54 @@ func validateDiscoveredArtifacts(
 	return true
 }
 
+// batchFreight determines whether the provided Freight, built from the latest
+// discovered artifacts, should be created now, or whether its creation should
+// be deferred by the Warehouse's FreightBatchWindow. The PendingFreight and
+// the refresh requests contributing to it are tracked in the provided status.
+// When true is returned, the PendingFreight has been cleared and the refresh
+// requests that contributed to the Freight, if any, have been recorded in an
+// annotation on the Freight.
+func batchFreight(
+	warehouse *kargoapi.Warehouse,
+	status *kargoapi.WarehouseStatus,
+	freight *kargoapi.Freight,
+	now metav1.Time,
+) bool {
+	window := warehouse.Spec.FreightBatchWindow
+	if window == nil || window.Duration <= 0 || freight.Name == status.LastFreightID {
+		status.PendingFreight = nil
+		return true
+	}
+
+	pending := status.PendingFreight
+	if pending == nil {
+		pending = &kargoapi.PendingFreight{Since: now}
+	}
+	pending.ID = freight.Name
+	// Only a refresh request handled by the current reconciliation contributes
+	// to the Freight. The Warehouse's own status still holds the previously
+	// handled refresh request.
+	if token := status.LastHandledRefresh; token != "" &&
+		token != warehouse.Status.LastHandledRefresh &&
+		!slices.Contains(pending.Refreshes, token) {
+		pending.Refreshes = append(pending.Refreshes, token)
+	}
+
+	if now.Before(&metav1.Time{Time: pending.Since.Add(window.Duration)}) {
+		status.PendingFreight = pending
+		return false
+	}
+
+	status.PendingFreight = nil
+	if len(pending.Refreshes) > 0 {
+		if freight.Annotations == nil {
+			freight.Annotations = make(map[string]string, 1)
+		}
+		freight.Annotations[kargoapi.AnnotationKeyRefreshes] = strings.Join(pending.Refreshes, ",")
+	}
+	return true
+}
+
 // shouldDiscoverArtifacts returns true if the Warehouse should attempt to
 // discover new artifacts. This is determined by the following conditions:
 //
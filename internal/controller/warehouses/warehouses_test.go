@@ -1005,6 +1005,144 @@ func TestShouldDiscoverArtifacts(t *testing.T) {
 	}
 }
 
+func TestBatchFreight(t *testing.T) {
+	now := metav1.Now()
+	window := &metav1.Duration{Duration: time.Minute}
+
+	testCases := []struct {
+		name       string
+		warehouse  *kargoapi.Warehouse
+		status     kargoapi.WarehouseStatus
+		assertions func(*testing.T, kargoapi.WarehouseStatus, *kargoapi.Freight, bool)
+	}{
+		{
+			name:      "batching disabled",
+			warehouse: &kargoapi.Warehouse{},
+			status: kargoapi.WarehouseStatus{
+				LastHandledRefresh: "refresh-1",
+			},
+			assertions: func(t *testing.T, status kargoapi.WarehouseStatus, freight *kargoapi.Freight, ready bool) {
+				require.True(t, ready)
+				require.Nil(t, status.PendingFreight)
+				require.Empty(t, freight.Annotations)
+			},
+		},
+		{
+			name: "no changes since last Freight",
+			warehouse: &kargoapi.Warehouse{
+				Spec: kargoapi.WarehouseSpec{FreightBatchWindow: window},
+			},
+			status: kargoapi.WarehouseStatus{
+				LastFreightID: "fake-freight",
+				PendingFreight: &kargoapi.PendingFreight{
+					ID:    "other-freight",
+					Since: now,
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.WarehouseStatus, _ *kargoapi.Freight, ready bool) {
+				require.True(t, ready)
+				require.Nil(t, status.PendingFreight)
+			},
+		},
+		{
+			name: "batch window opens",
+			warehouse: &kargoapi.Warehouse{
+				Spec: kargoapi.WarehouseSpec{FreightBatchWindow: window},
+			},
+			status: kargoapi.WarehouseStatus{
+				LastHandledRefresh: "refresh-1",
+			},
+			assertions: func(t *testing.T, status kargoapi.WarehouseStatus, _ *kargoapi.Freight, ready bool) {
+				require.False(t, ready)
+				require.Equal(
+					t,
+					&kargoapi.PendingFreight{
+						ID:        "fake-freight",
+						Since:     now,
+						Refreshes: []string{"refresh-1"},
+					},
+					status.PendingFreight,
+				)
+			},
+		},
+		{
+			name: "further changes within batch window",
+			warehouse: &kargoapi.Warehouse{
+				Spec: kargoapi.WarehouseSpec{FreightBatchWindow: window},
+				Status: kargoapi.WarehouseStatus{
+					LastHandledRefresh: "refresh-1",
+				},
+			},
+			status: kargoapi.WarehouseStatus{
+				LastHandledRefresh: "refresh-2",
+				PendingFreight: &kargoapi.PendingFreight{
+					ID:        "other-freight",
+					Since:     metav1.NewTime(now.Add(-30 * time.Second)),
+					Refreshes: []string{"refresh-1"},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.WarehouseStatus, _ *kargoapi.Freight, ready bool) {
+				require.False(t, ready)
+				require.NotNil(t, status.PendingFreight)
+				require.Equal(t, "fake-freight", status.PendingFreight.ID)
+				require.Equal(t, []string{"refresh-1", "refresh-2"}, status.PendingFreight.Refreshes)
+			},
+		},
+		{
+			name: "refresh already handled does not contribute again",
+			warehouse: &kargoapi.Warehouse{
+				Spec: kargoapi.WarehouseSpec{FreightBatchWindow: window},
+				Status: kargoapi.WarehouseStatus{
+					LastHandledRefresh: "refresh-1",
+				},
+			},
+			status: kargoapi.WarehouseStatus{
+				LastHandledRefresh: "refresh-1",
+				PendingFreight: &kargoapi.PendingFreight{
+					ID:        "fake-freight",
+					Since:     now,
+					Refreshes: []string{"refresh-1"},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.WarehouseStatus, _ *kargoapi.Freight, ready bool) {
+				require.False(t, ready)
+				require.Equal(t, []string{"refresh-1"}, status.PendingFreight.Refreshes)
+			},
+		},
+		{
+			name: "batch window elapsed",
+			warehouse: &kargoapi.Warehouse{
+				Spec: kargoapi.WarehouseSpec{FreightBatchWindow: window},
+			},
+			status: kargoapi.WarehouseStatus{
+				PendingFreight: &kargoapi.PendingFreight{
+					ID:        "other-freight",
+					Since:     metav1.NewTime(now.Add(-2 * time.Minute)),
+					Refreshes: []string{"refresh-1", "refresh-2"},
+				},
+			},
+			assertions: func(t *testing.T, status kargoapi.WarehouseStatus, freight *kargoapi.Freight, ready bool) {
+				require.True(t, ready)
+				require.Nil(t, status.PendingFreight)
+				require.Equal(
+					t,
+					"refresh-1,refresh-2",
+					freight.Annotations[kargoapi.AnnotationKeyRefreshes],
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			freight := &kargoapi.Freight{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake-freight"},
+			}
+			ready := batchFreight(testCase.warehouse, &testCase.status, freight, now)
+			testCase.assertions(t, testCase.status, freight, ready)
+		})
+	}
+}
+
 func TestGetRequeueInterval(t *testing.T) {
 	testCases := []struct {
 		name       string
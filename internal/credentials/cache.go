@@ -0,0 +1,196 @@
+package credentials
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/helm"
+)
+
+var cacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kargo_credentials_cache_requests_total",
+		Help: "Number of lookups of repository credentials, by type of " +
+			"credentials and whether they were served from the cache",
+	},
+	[]string{"type", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheRequests)
+}
+
+// Results that lookups of credentials are counted by.
+const (
+	cacheResultHit  = "hit"
+	cacheResultMiss = "miss"
+)
+
+// Invalidator is implemented by Databases that cache Credentials and can be
+// told to forget them, e.g. because a remote repository rejected them.
+type Invalidator interface {
+	// Invalidate evicts any cached Credentials of the specified type for the
+	// specified repository in the specified namespace.
+	Invalidate(namespace string, credType Type, repoURL string)
+}
+
+// Invalidate evicts any Credentials of the specified type for the specified
+// repository in the specified namespace that the provided Database has cached,
+// provided it implements the Invalidator interface. It is a no-op otherwise.
+func Invalidate(db Database, namespace string, credType Type, repoURL string) {
+	if i, ok := db.(Invalidator); ok {
+		i.Invalidate(namespace, credType, repoURL)
+	}
+}
+
+// cacheKey identifies cached Credentials.
+type cacheKey struct {
+	namespace string
+	credType  Type
+	repoURL   string
+}
+
+// newCacheKey returns the cacheKey for Credentials of the specified type for
+// the specified repository in the specified namespace. The URL of the
+// repository is normalized, so that equivalent URLs share an entry.
+func newCacheKey(namespace string, credType Type, repoURL string) cacheKey {
+	switch credType {
+	case TypeGit:
+		repoURL = git.NormalizeURL(repoURL)
+	case TypeHelm:
+		repoURL = helm.NormalizeChartRepositoryURL(repoURL)
+	}
+	return cacheKey{namespace: namespace, credType: credType, repoURL: repoURL}
+}
+
+// String returns the string representation of a cacheKey.
+func (k cacheKey) String() string {
+	return k.namespace + "\x00" + k.credType.String() + "\x00" + k.repoURL
+}
+
+// cacheEntry is the outcome of looking up Credentials that is retained until
+// it expires.
+type cacheEntry struct {
+	creds     Credentials
+	found     bool
+	expiresAt time.Time
+}
+
+// cachingDatabase is an implementation of the Database interface that retains
+// the outcomes of looking up Credentials in another Database for a limited
+// time. Errors are never cached. Concurrent lookups of the same Credentials
+// that miss the cache are coalesced into a single lookup.
+type cachingDatabase struct {
+	db  Database
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	// generations counts how often the Credentials for each key were
+	// invalidated, so that lookups that were already underway when they were
+	// invalidated do not repopulate the cache with them.
+	generations map[cacheKey]uint64
+	group       singleflight.Group
+
+	nowFn func() time.Time
+}
+
+// NewCachingDatabase returns an implementation of the Database interface that
+// retains the outcomes of looking up Credentials in the provided Database for
+// the provided amount of time. Cached Credentials can be evicted early using
+// Invalidate. If the provided amount of time is not positive, the provided
+// Database is returned as is.
+func NewCachingDatabase(db Database, ttl time.Duration) Database {
+	if ttl <= 0 {
+		return db
+	}
+	return &cachingDatabase{
+		db:          db,
+		ttl:         ttl,
+		entries:     map[cacheKey]cacheEntry{},
+		generations: map[cacheKey]uint64{},
+		nowFn:       time.Now,
+	}
+}
+
+// Get implements the Database interface.
+func (c *cachingDatabase) Get(
+	ctx context.Context,
+	namespace string,
+	credType Type,
+	repoURL string,
+) (Credentials, bool, error) {
+	key := newCacheKey(namespace, credType, repoURL)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !c.nowFn().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	generation := c.generations[key]
+	c.mu.Unlock()
+	if ok {
+		cacheRequests.WithLabelValues(credType.String(), cacheResultHit).Inc()
+		return entry.creds, entry.found, nil
+	}
+	cacheRequests.WithLabelValues(credType.String(), cacheResultMiss).Inc()
+
+	res, err, _ := c.group.Do(key.String(), func() (any, error) {
+		creds, found, err := c.db.Get(ctx, namespace, credType, repoURL)
+		if err != nil {
+			return nil, err
+		}
+		entry := cacheEntry{
+			creds:     creds,
+			found:     found,
+			expiresAt: c.nowFn().Add(c.ttl),
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.generations[key] == generation {
+			c.entries[key] = entry
+		}
+		return entry, nil
+	})
+	if err != nil {
+		return Credentials{}, false, err
+	}
+	entry = res.(cacheEntry) // nolint: forcetypeassert
+	return entry.creds, entry.found, nil
+}
+
+// GetFromSecret implements the SecretGetter interface. Credentials looked up
+// in a Secret specified by name are not cached, as they are only looked up
+// for Stages that specify a GitIdentity, and as the Secret may be changed to
+// hold different Credentials at any time.
+func (c *cachingDatabase) GetFromSecret(
+	ctx context.Context,
+	namespace string,
+	name string,
+	credType Type,
+	repoURL string,
+) (Credentials, bool, error) {
+	return GetFromSecret(ctx, c.db, namespace, name, credType, repoURL)
+}
+
+// Invalidate implements the Invalidator interface.
+func (c *cachingDatabase) Invalidate(
+	namespace string,
+	credType Type,
+	repoURL string,
+) {
+	key := newCacheKey(namespace, credType, repoURL)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	c.generations[key]++
+	// Lookups that are already underway must not be joined by subsequent ones,
+	// as they may return the Credentials that were just invalidated.
+	c.group.Forget(key.String())
+}
@@ -0,0 +1,195 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCachingDatabase(t *testing.T) {
+	db := &FakeDB{}
+	require.Same(t, db, NewCachingDatabase(db, 0))
+	require.IsType(t, &cachingDatabase{}, NewCachingDatabase(db, time.Minute))
+}
+
+func TestCachingDatabase_Get(t *testing.T) {
+	var lookups atomic.Int32
+	var fail atomic.Bool
+	cache, ok := NewCachingDatabase(
+		&FakeDB{
+			GetFn: func(context.Context, string, Type, string) (Credentials, bool, error) {
+				lookups.Add(1)
+				if fail.Load() {
+					return Credentials{}, false, errors.New("something went wrong")
+				}
+				return Credentials{Password: "fake-password"}, true, nil
+			},
+		},
+		time.Minute,
+	).(*cachingDatabase)
+	require.True(t, ok)
+	now := time.Now()
+	cache.nowFn = func() time.Time { return now }
+
+	// The first lookup misses the cache.
+	creds, found, err := cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "fake-password", creds.Password)
+	require.Equal(t, int32(1), lookups.Load())
+
+	// Lookups for equivalent URLs hit the cache.
+	for _, repoURL := range []string{
+		"https://github.com/example/repo",
+		"https://github.com/example/repo.git",
+		"https://GitHub.com/example/repo/",
+	} {
+		creds, found, err = cache.Get(context.Background(), "fake-namespace", TypeGit, repoURL)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "fake-password", creds.Password)
+	}
+	require.Equal(t, int32(1), lookups.Load())
+
+	// Lookups in other namespaces or of other types miss the cache.
+	_, _, err = cache.Get(context.Background(), "other-namespace", TypeGit, "https://github.com/example/repo")
+	require.NoError(t, err)
+	_, _, err = cache.Get(context.Background(), "fake-namespace", TypeHelm, "https://github.com/example/repo")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), lookups.Load())
+
+	// Once expired, the Credentials are looked up again. Errors are not
+	// cached.
+	now = now.Add(time.Minute)
+	fail.Store(true)
+	_, _, err = cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+	require.ErrorContains(t, err, "something went wrong")
+	fail.Store(false)
+	_, found, err = cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, int32(5), lookups.Load())
+
+	// Invalidated Credentials are looked up again.
+	Invalidate(cache, "fake-namespace", TypeGit, "https://github.com/example/repo.git")
+	_, _, err = cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+	require.NoError(t, err)
+	require.Equal(t, int32(6), lookups.Load())
+}
+
+func TestCachingDatabase_concurrentGet(t *testing.T) {
+	release := make(chan struct{})
+	var lookups atomic.Int32
+	cache := NewCachingDatabase(
+		&FakeDB{
+			GetFn: func(context.Context, string, Type, string) (Credentials, bool, error) {
+				lookups.Add(1)
+				<-release
+				return Credentials{Password: "fake-password"}, true, nil
+			},
+		},
+		time.Minute,
+	)
+	const concurrency = 10
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for range concurrency {
+		go func() {
+			defer wg.Done()
+			creds, found, err := cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, "fake-password", creds.Password)
+		}()
+	}
+	// Give all goroutines a chance to join the lookup that is underway.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), lookups.Load())
+}
+
+func TestCachingDatabase_invalidateDuringGet(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var lookups atomic.Int32
+	cache := NewCachingDatabase(
+		&FakeDB{
+			GetFn: func(context.Context, string, Type, string) (Credentials, bool, error) {
+				if lookups.Add(1) == 1 {
+					close(started)
+					<-release
+					return Credentials{Password: "stale-password"}, true, nil
+				}
+				return Credentials{Password: "fresh-password"}, true, nil
+			},
+		},
+		time.Minute,
+	)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+	}()
+	<-started
+	Invalidate(cache, "fake-namespace", TypeGit, "https://github.com/example/repo")
+	close(release)
+	<-done
+
+	// The lookup that was underway when the Credentials were invalidated did
+	// not repopulate the cache.
+	creds, _, err := cache.Get(context.Background(), "fake-namespace", TypeGit, "https://github.com/example/repo")
+	require.NoError(t, err)
+	require.Equal(t, "fresh-password", creds.Password)
+}
+
+func TestInvalidate(t *testing.T) {
+	// Databases that do not cache are left alone.
+	require.NotPanics(t, func() {
+		Invalidate(&FakeDB{}, "fake-namespace", TypeGit, "https://github.com/example/repo")
+	})
+}
+
+func TestGetFromSecret(t *testing.T) {
+	db := &FakeDB{
+		GetFromSecretFn: func(
+			_ context.Context,
+			_ string,
+			name string,
+			_ Type,
+			_ string,
+		) (Credentials, bool, error) {
+			return Credentials{Username: name}, true, nil
+		},
+	}
+
+	// Credentials looked up in a Secret specified by name are not cached.
+	creds, found, err := GetFromSecret(
+		context.Background(),
+		NewCachingDatabase(db, time.Minute),
+		"fake-namespace",
+		"fake-secret",
+		TypeGit,
+		"https://github.com/example/repo",
+	)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "fake-secret", creds.Username)
+
+	// Databases that do not implement SecretGetter cannot look up Credentials
+	// this way.
+	_, _, err = GetFromSecret(
+		context.Background(),
+		struct{ Database }{db},
+		"fake-namespace",
+		"fake-secret",
+		TypeGit,
+		"https://github.com/example/repo",
+	)
+	require.ErrorContains(t, err, "not supported")
+}
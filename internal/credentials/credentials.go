@@ -11,6 +11,36 @@ const (
 	FieldRepoURLIsRegex = "repoURLIsRegex"
 	FieldUsername       = "username"
 	FieldPassword       = "password"
+	FieldAuthMode       = "authMode"
+	// FieldTokenSecretName is the field of a credentials Secret that names
+	// another Secret in the same namespace, from which the password (or token)
+	// is taken instead.
+	FieldTokenSecretName = "tokenSecretName"
+	// FieldTokenSecretKey is the field of a credentials Secret that specifies
+	// the key of the Secret named by FieldTokenSecretName that holds the
+	// password (or token). If not specified, DefaultTokenSecretKey is used.
+	FieldTokenSecretKey = "tokenSecretKey"
+)
+
+// DefaultTokenSecretKey is the key of a Secret referenced by a credentials
+// Secret that holds the password (or token) if the credentials Secret does not
+// specify one.
+const DefaultTokenSecretKey = "token"
+
+// HTTPAuthMode is a string type used to represent how a password (or token)
+// is presented to a remote Git repository over HTTP(S).
+type HTTPAuthMode string
+
+const (
+	// HTTPAuthModeBasic represents presenting the username and password using
+	// HTTP basic authentication. This is the default.
+	HTTPAuthModeBasic HTTPAuthMode = "basic"
+	// HTTPAuthModeBearer represents presenting the password as a token in an
+	// "Authorization: Bearer" header. No username is required.
+	HTTPAuthModeBearer HTTPAuthMode = "bearer"
+	// HTTPAuthModeNetrc represents presenting the username and password using
+	// HTTP basic authentication, as read from a .netrc file.
+	HTTPAuthModeNetrc HTTPAuthMode = "netrc"
 )
 
 // Type is a string type used to represent a type of Credentials.
@@ -41,6 +71,9 @@ type Credentials struct {
 	// SSHPrivateKey is a private key that can be used for access to some remote
 	// repository. This is primarily applicable for Git repositories.
 	SSHPrivateKey string
+	// HTTPAuthMode specifies how the Password is presented to a remote Git
+	// repository over HTTP(S). If empty, HTTPAuthModeBasic is assumed.
+	HTTPAuthMode HTTPAuthMode
 }
 
 type Helper func(
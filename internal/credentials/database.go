@@ -1,6 +1,9 @@
 package credentials
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // Database is an interface for a Credentials store.
 type Database interface {
@@ -12,6 +15,46 @@ type Database interface {
 	) (Credentials, bool, error)
 }
 
+// SecretGetter is implemented by Databases that can look up Credentials in a
+// Secret specified by name, rather than in the Secret that is found to hold
+// Credentials for a repository.
+type SecretGetter interface {
+	// GetFromSecret returns the Credentials of the specified type for the
+	// specified repository that the Secret with the specified name in the
+	// specified namespace holds. It returns an error if there is no such
+	// Secret or if it is not labeled as holding Credentials of the specified
+	// type.
+	GetFromSecret(
+		ctx context.Context,
+		namespace string,
+		name string,
+		credType Type,
+		repo string,
+	) (Credentials, bool, error)
+}
+
+// GetFromSecret returns the Credentials of the specified type for the
+// specified repository that the Secret with the specified name in the
+// specified namespace holds, provided the Database implements the
+// SecretGetter interface. It returns an error otherwise.
+func GetFromSecret(
+	ctx context.Context,
+	db Database,
+	namespace string,
+	name string,
+	credType Type,
+	repo string,
+) (Credentials, bool, error) {
+	g, ok := db.(SecretGetter)
+	if !ok {
+		return Credentials{}, false, fmt.Errorf(
+			"cannot look up credentials in Secret %q: not supported by the credentials database",
+			name,
+		)
+	}
+	return g.GetFromSecret(ctx, namespace, name, credType, repo)
+}
+
 // FakeDB is a mock implementation of the Database interface that is used to
 // facilitate unit testing.
 type FakeDB struct {
@@ -21,6 +64,13 @@ type FakeDB struct {
 		credType Type,
 		repo string,
 	) (Credentials, bool, error)
+	GetFromSecretFn func(
+		ctx context.Context,
+		namespace string,
+		name string,
+		credType Type,
+		repo string,
+	) (Credentials, bool, error)
 }
 
 func (f *FakeDB) Get(
@@ -34,3 +84,16 @@ func (f *FakeDB) Get(
 	}
 	return f.GetFn(ctx, namespace, credType, repo)
 }
+
+func (f *FakeDB) GetFromSecret(
+	ctx context.Context,
+	namespace string,
+	name string,
+	credType Type,
+	repo string,
+) (Credentials, bool, error) {
+	if f.GetFromSecretFn == nil {
+		return Credentials{}, false, nil
+	}
+	return f.GetFromSecretFn(ctx, namespace, name, credType, repo)
+}
@@ -2,6 +2,9 @@ package basic
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"unicode"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -9,7 +12,12 @@ import (
 )
 
 // SecretToCreds is an implementation of credentials.Helper that simply extracts
-// a username, password, and SSH private key from a secret.
+// a username, password, SSH private key, and HTTP auth mode from a secret. A
+// password (or token) that is presented as a bearer token does not require a
+// username. An error is returned if the secret specifies an unknown HTTP auth
+// mode, or if it specifies that the username and password are to be written to
+// a .netrc file although either contains whitespace, which the format of such a
+// file cannot represent.
 func SecretToCreds(
 	_ context.Context,
 	_ string,
@@ -23,13 +31,40 @@ func SecretToCreds(
 	}
 
 	creds := &credentials.Credentials{
-		Username:      string(secret.Data["username"]),
-		Password:      string(secret.Data["password"]),
+		Username:      string(secret.Data[credentials.FieldUsername]),
+		Password:      string(secret.Data[credentials.FieldPassword]),
 		SSHPrivateKey: string(secret.Data["sshPrivateKey"]),
+		HTTPAuthMode:  credentials.HTTPAuthMode(secret.Data[credentials.FieldAuthMode]),
 	}
-	if (creds.Username != "" && creds.Password != "") ||
-		creds.SSHPrivateKey != "" {
+	switch creds.HTTPAuthMode {
+	case "", credentials.HTTPAuthModeBasic, credentials.HTTPAuthModeBearer:
+	case credentials.HTTPAuthModeNetrc:
+		if containsSpace(creds.Username) || containsSpace(creds.Password) {
+			return nil, fmt.Errorf(
+				"username and password in Secret %q in namespace %q must not contain "+
+					"whitespace when %s is %q",
+				secret.Name, secret.Namespace, credentials.FieldAuthMode, creds.HTTPAuthMode,
+			)
+		}
+	default:
+		return nil, fmt.Errorf(
+			"invalid %s %q in Secret %q in namespace %q; must be one of %q, %q, or %q",
+			credentials.FieldAuthMode, creds.HTTPAuthMode, secret.Name, secret.Namespace,
+			credentials.HTTPAuthModeBasic, credentials.HTTPAuthModeBearer,
+			credentials.HTTPAuthModeNetrc,
+		)
+	}
+	switch {
+	case creds.SSHPrivateKey != "",
+		creds.Username != "" && creds.Password != "",
+		creds.HTTPAuthMode == credentials.HTTPAuthModeBearer && creds.Password != "":
 		return creds, nil
 	}
 	return nil, nil
 }
+
+// containsSpace returns true if the provided string contains any whitespace,
+// including newlines.
+func containsSpace(s string) bool {
+	return strings.IndexFunc(s, unicode.IsSpace) >= 0
+}
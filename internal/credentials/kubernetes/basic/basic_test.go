@@ -0,0 +1,107 @@
+package basic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func TestSecretToCreds(t *testing.T) {
+	testCases := []struct {
+		name       string
+		data       map[string][]byte
+		assertions func(t *testing.T, creds *credentials.Credentials, err error)
+	}{
+		{
+			name: "username and password",
+			data: map[string][]byte{
+				credentials.FieldUsername: []byte("kargo"),
+				credentials.FieldPassword: []byte("fake-password"),
+			},
+			assertions: func(t *testing.T, creds *credentials.Credentials, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "kargo", creds.Username)
+				require.Equal(t, "fake-password", creds.Password)
+			},
+		},
+		{
+			name: "bearer token without username",
+			data: map[string][]byte{
+				credentials.FieldPassword: []byte("fake-token"),
+				credentials.FieldAuthMode: []byte(credentials.HTTPAuthModeBearer),
+			},
+			assertions: func(t *testing.T, creds *credentials.Credentials, err error) {
+				require.NoError(t, err)
+				require.Equal(t, credentials.HTTPAuthModeBearer, creds.HTTPAuthMode)
+			},
+		},
+		{
+			name: "password without username",
+			data: map[string][]byte{
+				credentials.FieldPassword: []byte("fake-password"),
+			},
+			assertions: func(t *testing.T, creds *credentials.Credentials, err error) {
+				require.NoError(t, err)
+				require.Nil(t, creds)
+			},
+		},
+		{
+			name: "unknown auth mode",
+			data: map[string][]byte{
+				credentials.FieldUsername: []byte("kargo"),
+				credentials.FieldPassword: []byte("fake-password"),
+				credentials.FieldAuthMode: []byte("token"),
+			},
+			assertions: func(t *testing.T, creds *credentials.Credentials, err error) {
+				require.ErrorContains(t, err, `invalid authMode "token"`)
+				require.Nil(t, creds)
+			},
+		},
+		{
+			name: "netrc password with newline",
+			data: map[string][]byte{
+				credentials.FieldUsername: []byte("kargo"),
+				credentials.FieldPassword: []byte("fake-password\nmachine evil.example.com"),
+				credentials.FieldAuthMode: []byte(credentials.HTTPAuthModeNetrc),
+			},
+			assertions: func(t *testing.T, creds *credentials.Credentials, err error) {
+				require.ErrorContains(t, err, "must not contain whitespace")
+				require.Nil(t, creds)
+			},
+		},
+		{
+			name: "basic auth password with whitespace",
+			data: map[string][]byte{
+				credentials.FieldUsername: []byte("kargo"),
+				credentials.FieldPassword: []byte("fake password"),
+			},
+			assertions: func(t *testing.T, creds *credentials.Credentials, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "fake password", creds.Password)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			creds, err := SecretToCreds(
+				context.Background(),
+				"fake-namespace",
+				credentials.TypeGit,
+				"https://github.com/example/repo.git",
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "fake-namespace",
+						Name:      "fake-secret",
+					},
+					Data: testCase.data,
+				},
+			)
+			testCase.assertions(t, creds, err)
+		})
+	}
+}
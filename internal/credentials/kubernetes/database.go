@@ -2,13 +2,16 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
@@ -35,6 +38,9 @@ type database struct {
 // of the credentials.Database interface.
 type DatabaseConfig struct {
 	GlobalCredentialsNamespaces []string `envconfig:"GLOBAL_CREDENTIALS_NAMESPACES" default:""`
+	// CacheTTL is how long credentials that were looked up are retained before
+	// they are looked up again. A value of zero disables caching.
+	CacheTTL time.Duration `envconfig:"CREDENTIALS_CACHE_TTL" default:"30s"`
 }
 
 func DatabaseConfigFromEnv() DatabaseConfig {
@@ -46,7 +52,8 @@ func DatabaseConfigFromEnv() DatabaseConfig {
 
 // NewDatabase initializes and returns an implementation of the
 // credentials.Database interface that utilizes a Kubernetes controller runtime
-// client to retrieve Credentials stored in Kubernetes Secrets.
+// client to retrieve Credentials stored in Kubernetes Secrets. Credentials are
+// cached for the amount of time specified by the provided DatabaseConfig.
 func NewDatabase(
 	ctx context.Context,
 	kargoClient client.Client,
@@ -66,11 +73,14 @@ func NewDatabase(
 			finalCredentialHelpers = append(finalCredentialHelpers, helper)
 		}
 	}
-	return &database{
-		kargoClient:       kargoClient,
-		credentialHelpers: finalCredentialHelpers,
-		cfg:               cfg,
-	}
+	return credentials.NewCachingDatabase(
+		&database{
+			kargoClient:       kargoClient,
+			credentialHelpers: finalCredentialHelpers,
+			cfg:               cfg,
+		},
+		cfg.CacheTTL,
+	)
 }
 
 func (k *database) Get(
@@ -89,6 +99,12 @@ func (k *database) Get(
 		return credentials.Credentials{}, false, nil
 	}
 
+	// Repositories on the local file system never require credentials, so
+	// don't bother looking for any.
+	if credType == credentials.TypeGit && git.IsLocalURL(repoURL) {
+		return credentials.Credentials{}, false, nil
+	}
+
 	var secret *corev1.Secret
 	var err error
 
@@ -119,6 +135,59 @@ func (k *database) Get(
 		}
 	}
 
+	if secret != nil {
+		if secret, err = k.resolveTokenSecret(ctx, secret); err != nil {
+			return credentials.Credentials{}, false, err
+		}
+	}
+
+	return k.applyHelpers(ctx, namespace, credType, repoURL, secret)
+}
+
+// GetFromSecret implements the credentials.SecretGetter interface. Only
+// Secrets in the specified namespace that are labeled as holding credentials
+// of the specified type can be used, so that arbitrary Secrets cannot be read
+// this way.
+func (k *database) GetFromSecret(
+	ctx context.Context,
+	namespace string,
+	name string,
+	credType credentials.Type,
+	repoURL string,
+) (credentials.Credentials, bool, error) {
+	secret := &corev1.Secret{}
+	if err := k.kargoClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: namespace, Name: name},
+		secret,
+	); err != nil {
+		return credentials.Credentials{}, false, fmt.Errorf(
+			"error getting credentials Secret %q in namespace %q: %w", name, namespace, err,
+		)
+	}
+	if secret.Labels[kargoapi.CredentialTypeLabelKey] != credType.String() {
+		return credentials.Credentials{}, false, fmt.Errorf(
+			"credentials Secret %q in namespace %q is not labeled as holding %s credentials",
+			name, namespace, credType,
+		)
+	}
+	secret, err := k.resolveTokenSecret(ctx, secret)
+	if err != nil {
+		return credentials.Credentials{}, false, err
+	}
+	return k.applyHelpers(ctx, namespace, credType, repoURL, secret)
+}
+
+// applyHelpers returns the Credentials that the first of the credential
+// helpers able to derive any from the provided Secret, which may be nil,
+// derives.
+func (k *database) applyHelpers(
+	ctx context.Context,
+	namespace string,
+	credType credentials.Type,
+	repoURL string,
+	secret *corev1.Secret,
+) (credentials.Credentials, bool, error) {
 	for _, helper := range k.credentialHelpers {
 		creds, err := helper(ctx, namespace, credType, repoURL, secret)
 		if err != nil {
@@ -128,10 +197,49 @@ func (k *database) Get(
 			return *creds, true, nil
 		}
 	}
-
 	return credentials.Credentials{}, false, nil
 }
 
+// resolveTokenSecret returns the provided credentials Secret with its password
+// (or token) taken from the Secret it references, if any. The referenced
+// Secret must be in the same namespace as the credentials Secret. This permits
+// a token that is maintained by another process, e.g. one that periodically
+// obtains a new token from an OAuth2 provider, to be used as is.
+func (k *database) resolveTokenSecret(
+	ctx context.Context,
+	secret *corev1.Secret,
+) (*corev1.Secret, error) {
+	name := string(secret.Data[credentials.FieldTokenSecretName])
+	if name == "" {
+		return secret, nil
+	}
+	key := string(secret.Data[credentials.FieldTokenSecretKey])
+	if key == "" {
+		key = credentials.DefaultTokenSecretKey
+	}
+	tokenSecret := &corev1.Secret{}
+	if err := k.kargoClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: secret.Namespace, Name: name},
+		tokenSecret,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"error getting Secret %q referenced by credentials Secret %q in namespace %q: %w",
+			name, secret.Name, secret.Namespace, err,
+		)
+	}
+	token, ok := tokenSecret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf(
+			"credentials Secret %q in namespace %q references Secret %q, which has no key %q",
+			secret.Name, secret.Namespace, name, key,
+		)
+	}
+	secret = secret.DeepCopy()
+	secret.Data[credentials.FieldPassword] = token
+	return secret, nil
+}
+
 func (k *database) getCredentialsSecret(
 	ctx context.Context,
 	namespace string,
@@ -3,6 +3,7 @@ package kubernetes
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +26,11 @@ func TestNewKubernetesDatabase(t *testing.T) {
 	require.True(t, ok)
 	require.Same(t, testClient, k.kargoClient)
 	require.Equal(t, testCfg, k.cfg)
+
+	// With a cache TTL, the database is wrapped in a cache.
+	testCfg.CacheTTL = time.Minute
+	_, ok = NewDatabase(context.Background(), testClient, testCfg).(*database)
+	require.False(t, ok)
 }
 
 // TestGet simply validates that, given a set of valid/matching secrets in
@@ -296,6 +302,28 @@ func TestGet(t *testing.T) {
 			repoURL:  testInsecureGitURL,
 			expected: nil,
 		},
+		{
+			name: "local repository",
+			// Would match if not for the local repository check
+			secrets: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "project-credential-git-local-repo-url",
+						Namespace: testProjectNamespace,
+						Labels:    testGitLabels,
+					},
+					Data: map[string][]byte{
+						credentials.FieldRepoURL:        []byte(".*"),
+						credentials.FieldRepoURLIsRegex: []byte("true"),
+						credentials.FieldUsername:       []byte("project-local"),
+						credentials.FieldPassword:       []byte("fake-password"),
+					},
+				},
+			},
+			credType: credentials.TypeGit,
+			repoURL:  "file:///srv/git/repo.git",
+			expected: nil,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -329,3 +357,161 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+func TestGet_tokenSecret(t *testing.T) {
+	const (
+		testNamespace  = "fake-namespace"
+		testGitRepoURL = "https://git.example.com/org/repo.git"
+	)
+	credsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "credentials",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				kargoapi.CredentialTypeLabelKey: credentials.TypeGit.String(),
+			},
+		},
+		Data: map[string][]byte{
+			credentials.FieldRepoURL:         []byte(testGitRepoURL),
+			credentials.FieldAuthMode:        []byte(credentials.HTTPAuthModeBearer),
+			credentials.FieldTokenSecretName: []byte("oauth-token"),
+		},
+	}
+	testCases := []struct {
+		name       string
+		objects    []client.Object
+		assertions func(*testing.T, credentials.Credentials, bool, error)
+	}{
+		{
+			name:    "referenced Secret does not exist",
+			objects: []client.Object{credsSecret},
+			assertions: func(t *testing.T, _ credentials.Credentials, _ bool, err error) {
+				require.ErrorContains(t, err, `error getting Secret "oauth-token"`)
+			},
+		},
+		{
+			name: "referenced Secret lacks key",
+			objects: []client.Object{
+				credsSecret,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "oauth-token", Namespace: testNamespace},
+					Data:       map[string][]byte{"access_token": []byte("fake-token")},
+				},
+			},
+			assertions: func(t *testing.T, _ credentials.Credentials, _ bool, err error) {
+				require.ErrorContains(t, err, `has no key "token"`)
+			},
+		},
+		{
+			name: "token taken from referenced Secret",
+			objects: []client.Object{
+				credsSecret,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "oauth-token", Namespace: testNamespace},
+					Data:       map[string][]byte{"token": []byte("fake-token")},
+				},
+			},
+			assertions: func(t *testing.T, creds credentials.Credentials, found bool, err error) {
+				require.NoError(t, err)
+				require.True(t, found)
+				require.Equal(
+					t,
+					credentials.Credentials{
+						Password:     "fake-token",
+						HTTPAuthMode: credentials.HTTPAuthModeBearer,
+					},
+					creds,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			creds, found, err := NewDatabase(
+				context.Background(),
+				fake.NewClientBuilder().WithObjects(testCase.objects...).Build(),
+				DatabaseConfig{},
+			).Get(context.Background(), testNamespace, credentials.TypeGit, testGitRepoURL)
+			testCase.assertions(t, creds, found, err)
+		})
+	}
+}
+
+func TestGetFromSecret(t *testing.T) {
+	const (
+		testNamespace  = "fake-namespace"
+		testGitRepoURL = "https://git.example.com/org/repo.git"
+	)
+	newSecret := func(name, credType string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels: map[string]string{
+					kargoapi.CredentialTypeLabelKey: credType,
+				},
+			},
+			Data: map[string][]byte{
+				// The URL of the repository is not matched.
+				credentials.FieldRepoURL:  []byte("https://git.example.com/org/other.git"),
+				credentials.FieldUsername: []byte(name),
+				credentials.FieldPassword: []byte("fake-password"),
+			},
+		}
+	}
+	testCases := []struct {
+		name       string
+		secretName string
+		assertions func(*testing.T, credentials.Credentials, bool, error)
+	}{
+		{
+			name:       "Secret does not exist",
+			secretName: "missing",
+			assertions: func(t *testing.T, _ credentials.Credentials, _ bool, err error) {
+				require.ErrorContains(t, err, `error getting credentials Secret "missing"`)
+			},
+		},
+		{
+			name:       "Secret holds credentials of another type",
+			secretName: "helm-bot",
+			assertions: func(t *testing.T, _ credentials.Credentials, _ bool, err error) {
+				require.ErrorContains(t, err, "is not labeled as holding git credentials")
+			},
+		},
+		{
+			name:       "credentials taken from Secret",
+			secretName: "prod-bot",
+			assertions: func(t *testing.T, creds credentials.Credentials, found bool, err error) {
+				require.NoError(t, err)
+				require.True(t, found)
+				require.Equal(
+					t,
+					credentials.Credentials{Username: "prod-bot", Password: "fake-password"},
+					creds,
+				)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			db := NewDatabase(
+				context.Background(),
+				fake.NewClientBuilder().WithObjects(
+					newSecret("dev-bot", credentials.TypeGit.String()),
+					newSecret("prod-bot", credentials.TypeGit.String()),
+					newSecret("helm-bot", credentials.TypeHelm.String()),
+				).Build(),
+				DatabaseConfig{},
+			)
+			creds, found, err := credentials.GetFromSecret(
+				context.Background(),
+				db,
+				testNamespace,
+				testCase.secretName,
+				credentials.TypeGit,
+				testGitRepoURL,
+			)
+			testCase.assertions(t, creds, found, err)
+		})
+	}
+}
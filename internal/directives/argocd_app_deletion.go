@@ -0,0 +1,80 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stateKeyPatchedApps is the key used to store the Argo CD Applications, each
+// in the form <namespace>/<name>, that the argocd-update step has patched and
+// waits for to be synced in the shared State.
+const stateKeyPatchedApps = "patchedApps"
+
+// patchedArgoCDApps returns the Argo CD Applications that previous executions
+// of the step have patched, as recorded in the step's output in the shared
+// State.
+func patchedArgoCDApps(stepCtx *PromotionStepContext) []string {
+	stepOutput, ok := stepCtx.SharedState.Get(stepCtx.Alias)
+	if !ok {
+		return nil
+	}
+	stepOutputMap, ok := stepOutput.(map[string]any)
+	if !ok {
+		return nil
+	}
+	// If the state was rehydrated from the PromotionStatus, the list is a
+	// []any rather than a []string.
+	switch apps := stepOutputMap[stateKeyPatchedApps].(type) {
+	case []string:
+		return slices.Clone(apps)
+	case []any:
+		keys := make([]string, 0, len(apps))
+		for _, app := range apps {
+			if key, ok := app.(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+// newArgoCDAppDeletedError returns a terminal error indicating that the Argo
+// CD Application with the provided key was deleted after it was patched, while
+// the step was waiting for it to be synced. Such a sync will never complete.
+func newArgoCDAppDeletedError(appKey client.ObjectKey) error {
+	return &terminalError{err: fmt.Errorf(
+		"ApplicationDeleted: Argo CD Application %q in namespace %q was deleted "+
+			"while the Promotion was waiting for it to be synced",
+		appKey.Name, appKey.Namespace,
+	)}
+}
+
+// wasAppDeleted returns true if the provided error, returned by an attempt to
+// patch the Argo CD Application with the provided key, is due to the
+// Application having been deleted. A conflict may also be caused by a
+// concurrent update, so the Application is looked up again to tell the two
+// apart.
+func (a *argocdUpdater) wasAppDeleted(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	appKey client.ObjectKey,
+	err error,
+) bool {
+	if apierrors.IsNotFound(err) {
+		return true
+	}
+	if !apierrors.IsConflict(err) {
+		return false
+	}
+	app, getErr := a.getAuthorizedApplicationFn(ctx, stepCtx, appKey)
+	if errors.Is(getErr, errArgoCDAppNotFound) {
+		return true
+	}
+	return getErr == nil && app.DeletionTimestamp != nil
+}
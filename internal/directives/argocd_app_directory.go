@@ -0,0 +1,217 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+// outputIgnoredReason is the reason of an OutputIgnored condition indicating
+// that an Argo CD Application does not read some of the files rendered
+// manifests were written to.
+const outputIgnoredReason = "IgnoredByApplication"
+
+// maxIgnoredFiles is the maximum number of files ignored by an Argo CD
+// Application that are listed in the message of an OutputIgnored condition.
+const maxIgnoredFiles = 5
+
+// appDirectory describes the files of a working tree that an Argo CD
+// Application reads manifests from, as configured by the path and directory
+// settings of its source.
+//
+// Argo CD reads the files in the directory at the source's path and, if it
+// recurses, its subdirectories. The include and exclude globs are matched
+// against the path of each file relative to that directory, with wildcards
+// matching path separators.
+type appDirectory struct {
+	// appName is the name of the Application, for use in messages.
+	appName string
+	// path is the absolute path of the directory the Application reads
+	// manifests from.
+	path    string
+	recurse bool
+	include glob.Glob
+	exclude glob.Glob
+	// includePattern and excludePattern are the patterns include and exclude
+	// were compiled from, for use in messages.
+	includePattern string
+	excludePattern string
+}
+
+// getAppDirectory returns the appDirectory of the Argo CD Application
+// identified by the provided reference, whose source's path is resolved
+// relative to the provided root of a working tree of the branch it reads
+// manifests from.
+func getAppDirectory(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	ref *KustomizeBuildApp,
+	workTreeRoot string,
+) (*appDirectory, error) {
+	if stepCtx.ArgoCDClient == nil {
+		return nil, errors.New("Argo CD integration is disabled on this controller; " +
+			"cannot read the Argo CD Application the manifests are built for")
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = argoCDNamespace(stepCtx.ArgoCDNamespace)
+	}
+	app, err := argocd.GetApplication(ctx, stepCtx.ArgoCDClient, namespace, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error finding Argo CD Application %q in namespace %q: %w",
+			ref.Name, namespace, err,
+		)
+	}
+	if app == nil {
+		return nil, fmt.Errorf(
+			"unable to find Argo CD Application %q in namespace %q: %w",
+			ref.Name, namespace, errArgoCDAppNotFound,
+		)
+	}
+	return newAppDirectory(app, stepCtx.RenderedBranch, workTreeRoot)
+}
+
+// newAppDirectory returns the appDirectory of the provided Argo CD
+// Application. Of an Application with multiple sources, the source targeting
+// the provided rendered branch is used, or else the first one that is not a
+// Helm chart.
+func newAppDirectory(
+	app *argocd.Application,
+	renderedBranch string,
+	workTreeRoot string,
+) (*appDirectory, error) {
+	source := app.Spec.Source
+	if source == nil {
+		for i := range app.Spec.Sources {
+			s := &app.Spec.Sources[i]
+			if s.Chart != "" {
+				continue
+			}
+			if source == nil || (renderedBranch != "" && s.TargetRevision == renderedBranch) {
+				source = s
+			}
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf(
+			"Argo CD Application %q in namespace %q has no source that reads "+
+				"manifests from a Git repository",
+			app.Name, app.Namespace,
+		)
+	}
+	path := filepath.Join(workTreeRoot, filepath.FromSlash(source.Path))
+	if !isSubPath(workTreeRoot, path) {
+		return nil, fmt.Errorf(
+			"path %q of Argo CD Application %q in namespace %q is outside of the repository",
+			source.Path, app.Name, app.Namespace,
+		)
+	}
+	d := &appDirectory{
+		appName: app.Name,
+		path:    path,
+	}
+	if dir := source.Directory; dir != nil {
+		d.recurse = dir.Recurse
+		var err error
+		if dir.Include != "" {
+			if d.include, err = glob.Compile(dir.Include); err != nil {
+				return nil, fmt.Errorf(
+					"invalid include pattern %q of Argo CD Application %q: %w",
+					dir.Include, app.Name, err,
+				)
+			}
+			d.includePattern = dir.Include
+		}
+		if dir.Exclude != "" {
+			if d.exclude, err = glob.Compile(dir.Exclude); err != nil {
+				return nil, fmt.Errorf(
+					"invalid exclude pattern %q of Argo CD Application %q: %w",
+					dir.Exclude, app.Name, err,
+				)
+			}
+			d.excludePattern = dir.Exclude
+		}
+	}
+	return d, nil
+}
+
+// ignoreReason returns why the Application does not read the file at the
+// provided absolute path, or an empty string if it does.
+func (d *appDirectory) ignoreReason(path string) string {
+	rel, err := filepath.Rel(d.path, path)
+	if err != nil || !isSubPath(d.path, path) {
+		return "it is outside of the Application's path"
+	}
+	rel = filepath.ToSlash(rel)
+	if !d.recurse && strings.Contains(rel, "/") {
+		return "the Application does not recurse into subdirectories of its path"
+	}
+	if d.exclude != nil && d.exclude.Match(rel) {
+		return fmt.Sprintf("it is excluded by the pattern %q", d.excludePattern)
+	}
+	if d.include != nil && !d.include.Match(rel) {
+		return fmt.Sprintf("it is not included by the pattern %q", d.includePattern)
+	}
+	return ""
+}
+
+// outputIgnoredCondition returns an OutputIgnored condition listing those of
+// the provided files that the Application does not read, with paths relative
+// to the provided working directory, or nil if it reads all of them.
+func (d *appDirectory) outputIgnoredCondition(workDir string, files []string) *metav1.Condition {
+	var ignored []string
+	var count int
+	for _, file := range files {
+		reason := d.ignoreReason(file)
+		if reason == "" {
+			continue
+		}
+		if count++; count > maxIgnoredFiles {
+			continue
+		}
+		if rel, err := filepath.Rel(workDir, file); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		ignored = append(ignored, fmt.Sprintf("%q, because %s", file, reason))
+	}
+	if count == 0 {
+		return nil
+	}
+	if count > maxIgnoredFiles {
+		ignored = append(ignored, fmt.Sprintf("and %d more", count-maxIgnoredFiles))
+	}
+	return &metav1.Condition{
+		Type:   kargoapi.ConditionTypeOutputIgnored,
+		Status: metav1.ConditionTrue,
+		Reason: outputIgnoredReason,
+		Message: fmt.Sprintf(
+			"Argo CD Application %q does not read rendered manifests written to %s",
+			d.appName, strings.Join(ignored, "; "),
+		),
+	}
+}
+
+// findWorkTreeRoot returns the root of the working tree of a Git repository
+// that the provided path is within, searching no further up than the provided
+// working directory. An empty string is returned if there is none.
+func findWorkTreeRoot(workDir, path string) string {
+	for dir := path; isSubPath(workDir, dir); dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		if dir == workDir {
+			break
+		}
+	}
+	return ""
+}
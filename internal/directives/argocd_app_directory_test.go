@@ -0,0 +1,185 @@
+package directives
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+func Test_appDirectory_ignoreReason(t *testing.T) {
+	const root = "/work/out"
+	testCases := []struct {
+		name            string
+		source          argocd.ApplicationSource
+		file            string
+		expectedIgnored bool
+		expectedReason  string
+	}{
+		{
+			name:   "file in path",
+			source: argocd.ApplicationSource{Path: "apps/dev"},
+			file:   "apps/dev/deployment.yaml",
+		},
+		{
+			name:            "file outside of path",
+			source:          argocd.ApplicationSource{Path: "apps/dev"},
+			file:            "deployment.yaml",
+			expectedIgnored: true,
+			expectedReason:  "outside of the Application's path",
+		},
+		{
+			name:            "file in subdirectory without recursion",
+			source:          argocd.ApplicationSource{Path: "apps/dev"},
+			file:            "apps/dev/rendered/deployment.yaml",
+			expectedIgnored: true,
+			expectedReason:  "does not recurse",
+		},
+		{
+			name: "file in subdirectory with recursion",
+			source: argocd.ApplicationSource{
+				Path:      "apps/dev",
+				Directory: &argocd.ApplicationSourceDirectory{Recurse: true},
+			},
+			file: "apps/dev/rendered/deployment.yaml",
+		},
+		{
+			name: "file hidden by exclude glob with recursion",
+			source: argocd.ApplicationSource{
+				Path: ".",
+				Directory: &argocd.ApplicationSourceDirectory{
+					Recurse: true,
+					Exclude: "rendered/*",
+				},
+			},
+			file:            "rendered/deployment.yaml",
+			expectedIgnored: true,
+			expectedReason:  `excluded by the pattern "rendered/*"`,
+		},
+		{
+			name: "wildcard of exclude glob matches path separators",
+			source: argocd.ApplicationSource{
+				Directory: &argocd.ApplicationSourceDirectory{
+					Recurse: true,
+					Exclude: "*.yaml",
+				},
+			},
+			file:            "rendered/nested/deployment.yaml",
+			expectedIgnored: true,
+			expectedReason:  "excluded",
+		},
+		{
+			name: "file not included",
+			source: argocd.ApplicationSource{
+				Directory: &argocd.ApplicationSourceDirectory{
+					Recurse: true,
+					Include: "{config/*,*.json}",
+				},
+			},
+			file:            "rendered/deployment.yaml",
+			expectedIgnored: true,
+			expectedReason:  `not included by the pattern "{config/*,*.json}"`,
+		},
+		{
+			name: "file included",
+			source: argocd.ApplicationSource{
+				Directory: &argocd.ApplicationSourceDirectory{
+					Recurse: true,
+					Include: "{config/*,rendered/*}",
+				},
+			},
+			file: "rendered/deployment.yaml",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			d, err := newAppDirectory(
+				&argocd.Application{
+					ObjectMeta: metav1.ObjectMeta{Name: "fake-app"},
+					Spec:       argocd.ApplicationSpec{Source: &testCase.source},
+				},
+				"",
+				root,
+			)
+			require.NoError(t, err)
+			reason := d.ignoreReason(filepath.Join(root, filepath.FromSlash(testCase.file)))
+			if !testCase.expectedIgnored {
+				require.Empty(t, reason)
+				return
+			}
+			require.Contains(t, reason, testCase.expectedReason)
+		})
+	}
+}
+
+func Test_newAppDirectory(t *testing.T) {
+	const root = "/work/out"
+	app := &argocd.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "fake-app"},
+		Spec: argocd.ApplicationSpec{
+			Sources: argocd.ApplicationSources{
+				{RepoURL: "https://charts.example.com", Chart: "fake-chart"},
+				{RepoURL: "https://github.com/example/repo", Path: "base", TargetRevision: "main"},
+				{RepoURL: "https://github.com/example/repo", Path: "apps/dev", TargetRevision: "stage/dev"},
+			},
+		},
+	}
+
+	// The source targeting the rendered branch is used
+	d, err := newAppDirectory(app, "stage/dev", root)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "apps", "dev"), d.path)
+
+	// Or else the first source that is not a chart
+	d, err = newAppDirectory(app, "", root)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "base"), d.path)
+
+	// A path outside of the repository is rejected
+	_, err = newAppDirectory(
+		&argocd.Application{
+			Spec: argocd.ApplicationSpec{
+				Source: &argocd.ApplicationSource{Path: "../elsewhere"},
+			},
+		},
+		"",
+		root,
+	)
+	require.ErrorContains(t, err, "outside of the repository")
+
+	// An invalid glob is rejected
+	_, err = newAppDirectory(
+		&argocd.Application{
+			Spec: argocd.ApplicationSpec{
+				Source: &argocd.ApplicationSource{
+					Directory: &argocd.ApplicationSourceDirectory{Exclude: "[a-"},
+				},
+			},
+		},
+		"",
+		root,
+	)
+	require.ErrorContains(t, err, "invalid exclude pattern")
+}
+
+func Test_appDirectory_outputIgnoredCondition(t *testing.T) {
+	d := &appDirectory{appName: "fake-app", path: "/work/out/apps/dev"}
+	require.Nil(t, d.outputIgnoredCondition("/work", []string{"/work/out/apps/dev/a.yaml"}))
+
+	var files []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		files = append(files, "/work/out/"+name+".yaml")
+	}
+	cond := d.outputIgnoredCondition("/work", files)
+	require.NotNil(t, cond)
+	require.Equal(t, kargoapi.ConditionTypeOutputIgnored, cond.Type)
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, outputIgnoredReason, cond.Reason)
+	require.Contains(t, cond.Message, `"out/a.yaml", because it is outside of the Application's path`)
+	require.NotContains(t, cond.Message, "out/f.yaml")
+	require.Contains(t, cond.Message, "; and 2 more")
+}
@@ -0,0 +1,153 @@
+package directives
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+const (
+	// argoCDSecretTypeLabelKey is the key of the label that identifies the
+	// purpose of a Secret in Argo CD's namespace.
+	argoCDSecretTypeLabelKey = "argocd.argoproj.io/secret-type"
+	// argoCDSecretTypeCluster is the value of the argoCDSecretTypeLabelKey
+	// label of Secrets holding the credentials of a cluster.
+	argoCDSecretTypeCluster = "cluster"
+	// argoCDInClusterServer is the URL of the API server of the cluster Argo
+	// CD is running in, as referred to by Application destinations.
+	argoCDInClusterServer = "https://kubernetes.default.svc"
+)
+
+// argoCDClusterConfig is the configuration of a cluster that Argo CD stores,
+// as JSON, under the config key of the cluster's Secret.
+type argoCDClusterConfig struct {
+	Username        string                 `json:"username,omitempty"`
+	Password        string                 `json:"password,omitempty"`
+	BearerToken     string                 `json:"bearerToken,omitempty"`
+	TLSClientConfig argoCDClusterTLSConfig `json:"tlsClientConfig"`
+	// AWSAuthConfig and ExecProviderConfig are not supported, since they
+	// require executing binaries that are only available to Argo CD. They are
+	// only decoded to detect clusters that rely on them.
+	AWSAuthConfig      json.RawMessage `json:"awsAuthConfig,omitempty"`
+	ExecProviderConfig json.RawMessage `json:"execProviderConfig,omitempty"`
+}
+
+// argoCDClusterTLSConfig is the TLS configuration of a cluster that Argo CD
+// stores as part of an argoCDClusterConfig.
+type argoCDClusterTLSConfig struct {
+	Insecure   bool   `json:"insecure,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+	CAData     []byte `json:"caData,omitempty"`
+	CertData   []byte `json:"certData,omitempty"`
+	KeyData    []byte `json:"keyData,omitempty"`
+}
+
+// getDestinationRESTConfig returns a REST config for the API server of the
+// destination cluster of an Argo CD Application, using the credentials found
+// in the Secret in which Argo CD stores them. The Secret is looked up in the
+// provided namespace, which Argo CD is installed into, using the provided
+// client.
+//
+// The cluster Argo CD is running in is only supported if it is registered with
+// a Secret of its own, since Argo CD otherwise uses its own identity for it,
+// which is not available to Kargo.
+func getDestinationRESTConfig(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	dest *argocd.ApplicationDestination,
+) (*rest.Config, error) {
+	ref := dest.Server
+	if ref == "" {
+		ref = dest.Name
+	}
+	if ref == "" {
+		return nil, errors.New("destination specifies neither a server nor a name")
+	}
+	secrets := &corev1.SecretList{}
+	if err := c.List(
+		ctx,
+		secrets,
+		client.InNamespace(namespace),
+		client.MatchingLabels{argoCDSecretTypeLabelKey: argoCDSecretTypeCluster},
+	); err != nil {
+		return nil, fmt.Errorf(
+			"error listing Argo CD cluster Secrets in namespace %q: %w", namespace, err,
+		)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		server := strings.TrimSuffix(string(secret.Data["server"]), "/")
+		if dest.Server != "" && server != strings.TrimSuffix(dest.Server, "/") ||
+			dest.Server == "" && string(secret.Data["name"]) != dest.Name {
+			continue
+		}
+		restCfg, err := newClusterRESTConfig(server, secret.Data["config"])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error reading Argo CD cluster Secret %q in namespace %q: %w",
+				secret.Name, namespace, err,
+			)
+		}
+		return restCfg, nil
+	}
+	if strings.TrimSuffix(dest.Server, "/") == argoCDInClusterServer || dest.Name == "in-cluster" {
+		return nil, fmt.Errorf(
+			"destination cluster %q is the one Argo CD is running in, which is "+
+				"only supported if it is registered with a cluster Secret",
+			ref,
+		)
+	}
+	return nil, fmt.Errorf(
+		"no Argo CD cluster Secret found for destination cluster %q in namespace %q",
+		ref, namespace,
+	)
+}
+
+// newClusterRESTConfig returns a REST config for the API server at the
+// provided URL, using the credentials found in the provided configuration of
+// a cluster, as Argo CD stores it.
+func newClusterRESTConfig(server string, config []byte) (*rest.Config, error) {
+	if server == "" {
+		return nil, errors.New("Secret does not specify a server")
+	}
+	cfg := argoCDClusterConfig{}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return nil, fmt.Errorf("error parsing cluster configuration: %w", err)
+		}
+	}
+	if isSetJSON(cfg.AWSAuthConfig) || isSetJSON(cfg.ExecProviderConfig) {
+		return nil, errors.New(
+			"cluster configuration relies on an AWS IAM or exec provider, " +
+				"which is not supported",
+		)
+	}
+	return &rest.Config{
+		Host:        server,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+		BearerToken: cfg.BearerToken,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure:   cfg.TLSClientConfig.Insecure,
+			ServerName: cfg.TLSClientConfig.ServerName,
+			CAData:     cfg.TLSClientConfig.CAData,
+			CertData:   cfg.TLSClientConfig.CertData,
+			KeyData:    cfg.TLSClientConfig.KeyData,
+		},
+	}, nil
+}
+
+// isSetJSON returns true if the provided raw JSON holds a value other than
+// null.
+func isSetJSON(raw json.RawMessage) bool {
+	return len(raw) > 0 && string(raw) != "null"
+}
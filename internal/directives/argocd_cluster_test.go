@@ -0,0 +1,87 @@
+package directives
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+func Test_getDestinationRESTConfig(t *testing.T) {
+	newClusterSecret := func(name, server, config string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "argocd",
+				Name:      name,
+				Labels:    map[string]string{argoCDSecretTypeLabelKey: argoCDSecretTypeCluster},
+			},
+			Data: map[string][]byte{
+				"name":   []byte(name),
+				"server": []byte(server),
+				"config": []byte(config),
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		newClusterSecret(
+			"prod",
+			"https://prod.example.com/",
+			`{"bearerToken":"token","tlsClientConfig":{"caData":"Y2E=","serverName":"prod"}}`,
+		),
+		newClusterSecret("eks", "https://eks.example.com", `{"awsAuthConfig":{"clusterName":"eks"}}`),
+		// Not a cluster Secret
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "argocd", Name: "repo"},
+			Data:       map[string][]byte{"server": []byte("https://staging.example.com")},
+		},
+	).Build()
+
+	testCases := []struct {
+		name        string
+		dest        argocd.ApplicationDestination
+		expectedErr string
+	}{
+		{
+			name: "found by server",
+			dest: argocd.ApplicationDestination{Server: "https://prod.example.com"},
+		},
+		{
+			name: "found by name",
+			dest: argocd.ApplicationDestination{Name: "prod"},
+		},
+		{
+			name:        "unsupported credentials",
+			dest:        argocd.ApplicationDestination{Name: "eks"},
+			expectedErr: "AWS IAM or exec provider",
+		},
+		{
+			name:        "not found",
+			dest:        argocd.ApplicationDestination{Server: "https://staging.example.com"},
+			expectedErr: "no Argo CD cluster Secret found",
+		},
+		{
+			name:        "in-cluster without Secret",
+			dest:        argocd.ApplicationDestination{Server: "https://kubernetes.default.svc"},
+			expectedErr: "only supported if it is registered with a cluster Secret",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			restCfg, err := getDestinationRESTConfig(context.Background(), c, "argocd", &testCase.dest)
+			if testCase.expectedErr != "" {
+				require.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "https://prod.example.com", restCfg.Host)
+			require.Equal(t, "token", restCfg.BearerToken)
+			require.Equal(t, []byte("ca"), restCfg.TLSClientConfig.CAData)
+			require.Equal(t, "prod", restCfg.TLSClientConfig.ServerName)
+		})
+	}
+}
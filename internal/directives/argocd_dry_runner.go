@@ -0,0 +1,439 @@
+package directives
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/xeipuuv/gojsonschema"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// dryRunFieldManager is the field manager that resources are applied as in
+// dry-run mode.
+const dryRunFieldManager = "kargo-dry-run"
+
+// maxDryRunFailures is the maximum number of resources that failed to apply
+// in dry-run mode that are listed in the error returned by argocd-dry-run.
+const maxDryRunFailures = 5
+
+// maxDryRunMessageLength is the maximum length of the message of each
+// resource that failed to apply in dry-run mode that is included in the error
+// returned by argocd-dry-run.
+const maxDryRunMessageLength = 200
+
+func init() {
+	builtins.RegisterPromotionStepRunner(
+		newArgocdDryRunner(),
+		&StepRunnerPermissions{AllowArgoCDClient: true},
+	)
+}
+
+// argocdDryRunner is an implementation of the PromotionStepRunner interface
+// that applies rendered manifests to the destination cluster of an Argo CD
+// Application in server-side dry-run mode.
+type argocdDryRunner struct {
+	schemaLoader gojsonschema.JSONLoader
+	// newClusterClient returns a client for the cluster described by the
+	// provided REST config. It is overridable for testing purposes.
+	newClusterClient func(*rest.Config) (client.Client, error)
+}
+
+// newArgocdDryRunner returns an implementation of the PromotionStepRunner
+// interface that applies rendered manifests to the destination cluster of an
+// Argo CD Application in server-side dry-run mode.
+func newArgocdDryRunner() PromotionStepRunner {
+	r := &argocdDryRunner{
+		newClusterClient: func(cfg *rest.Config) (client.Client, error) {
+			return client.New(cfg, client.Options{})
+		},
+	}
+	r.schemaLoader = getConfigSchemaLoader(r.Name())
+	return r
+}
+
+// Name implements the PromotionStepRunner interface.
+func (a *argocdDryRunner) Name() string {
+	return "argocd-dry-run"
+}
+
+// RunPromotionStep implements the PromotionStepRunner interface.
+func (a *argocdDryRunner) RunPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+) (PromotionStepResult, error) {
+	// Validate the configuration against the JSON Schema.
+	if err := validate(a.schemaLoader, gojsonschema.NewGoLoader(stepCtx.Config), a.Name()); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Convert the configuration into a typed object.
+	cfg, err := ConfigToStruct[ArgoCDDryRunConfig](stepCtx.Config)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not convert config into %s config: %w", a.Name(), err)
+	}
+
+	return a.runPromotionStep(ctx, stepCtx, cfg)
+}
+
+// dryRunFailure describes a resource that failed to apply in dry-run mode.
+type dryRunFailure struct {
+	kind      string
+	namespace string
+	name      string
+	message   string
+}
+
+// dryRunResult describes the outcome of applying a set of resources in
+// dry-run mode.
+type dryRunResult struct {
+	// applied is the number of resources that were applied, whether
+	// successfully or not.
+	applied int
+	// skipped is the number of resources that were not applied because they
+	// are in namespaces that do not exist in the destination cluster, or
+	// because their kind is defined by a CustomResourceDefinition among the
+	// resources.
+	skipped int
+	// unverified is the number of resources that were not applied because the
+	// maximum number of resources or the timeout was reached.
+	unverified int
+	failures   []dryRunFailure
+}
+
+func (a *argocdDryRunner) runPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg ArgoCDDryRunConfig,
+) (PromotionStepResult, error) {
+	if stepCtx.DryRunApply == nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"Stage %q does not permit applying manifests in dry-run mode; "+
+					"it must specify spec.dryRunApply",
+				stepCtx.Stage,
+			)}
+	}
+	if stepCtx.ArgoCDClient == nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, errors.New(
+			"Argo CD integration is disabled on this controller; cannot apply " +
+				"manifests to the destination cluster of an Argo CD Application",
+		)
+	}
+
+	path, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.Path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not secure join path %q: %w", cfg.Path, err)
+	}
+	objs, err := readManifests(path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"error reading manifests in %q: %w", cfg.Path, sanitizePathError(err, stepCtx.WorkDir),
+			)}
+	}
+
+	namespace := cfg.App.Namespace
+	if namespace == "" {
+		namespace = argoCDNamespace(stepCtx.ArgoCDNamespace)
+	}
+	app, err := argocd.GetApplication(ctx, stepCtx.ArgoCDClient, namespace, cfg.App.Name)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error finding Argo CD Application %q in namespace %q: %w",
+			cfg.App.Name, namespace, err,
+		)
+	}
+	if app == nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"unable to find Argo CD Application %q in namespace %q: %w",
+			cfg.App.Name, namespace, errArgoCDAppNotFound,
+		)
+	}
+	dest := app.Spec.Destination
+	if dest == nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"Argo CD Application %q in namespace %q has no destination",
+				app.Name, app.Namespace,
+			)}
+	}
+
+	restCfg, err := getDestinationRESTConfig(
+		ctx, stepCtx.ArgoCDClient, argoCDNamespace(stepCtx.ArgoCDNamespace), dest,
+	)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error getting credentials of the destination cluster of Argo CD "+
+				"Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)
+	}
+	c, err := a.newClusterClient(restCfg)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error creating client for destination cluster %q: %w", restCfg.Host, err,
+		)
+	}
+
+	res := dryRunApply(ctx, c, objs, dest.Namespace, stepCtx.DryRunApply)
+	logging.LoggerFromContext(ctx).Debug(
+		"applied manifests in dry-run mode",
+		"app", app.Name,
+		"applied", res.applied,
+		"skipped", res.skipped,
+		"unverified", res.unverified,
+		"failed", len(res.failures),
+	)
+	if len(res.failures) > 0 {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"%d of %d resources failed to apply to the destination cluster of "+
+					"Argo CD Application %q in dry-run mode: %s",
+				len(res.failures), res.applied, app.Name, formatDryRunFailures(res.failures),
+			)}
+	}
+	return PromotionStepResult{
+		Status:  kargoapi.PromotionPhaseSucceeded,
+		Message: res.message(),
+		Output: map[string]any{
+			"applied":    res.applied,
+			"skipped":    res.skipped,
+			"unverified": res.unverified,
+		},
+	}, nil
+}
+
+// dryRunApply applies the provided resources to the cluster of the provided
+// client in server-side dry-run mode, as described by the provided policy.
+// Namespaced resources that do not specify a namespace are applied to the
+// provided default namespace.
+func dryRunApply(
+	ctx context.Context,
+	c client.Client,
+	objs []*unstructured.Unstructured,
+	defaultNamespace string,
+	policy *kargoapi.DryRunApply,
+) dryRunResult {
+	if defaultNamespace == "" {
+		defaultNamespace = metav1.NamespaceDefault
+	}
+	ctx, cancel := context.WithTimeout(ctx, policy.GetTimeout())
+	defer cancel()
+
+	crdKinds := customResourceKinds(objs)
+	namespaces := map[string]bool{}
+	res := dryRunResult{}
+	for i, obj := range objs {
+		if i == policy.GetMaxDocuments() || ctx.Err() != nil {
+			res.unverified = len(objs) - i
+			break
+		}
+		namespaced, err := c.IsObjectNamespaced(obj)
+		if err != nil {
+			if crdKinds[obj.GroupVersionKind().GroupKind()] {
+				// The kind is not known to the cluster yet, since it is
+				// defined by a CustomResourceDefinition that is to be
+				// applied along with the resource.
+				res.skipped++
+				continue
+			}
+			res.applied++
+			res.failures = append(res.failures, newDryRunFailure(obj, err))
+			continue
+		}
+		if namespaced {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(defaultNamespace)
+			}
+			exists, err := namespaceExists(ctx, c, obj.GetNamespace(), namespaces, policy)
+			if err != nil {
+				if ctx.Err() != nil {
+					res.unverified = len(objs) - i
+					break
+				}
+				res.applied++
+				res.failures = append(res.failures, newDryRunFailure(obj, err))
+				continue
+			}
+			if !exists {
+				res.skipped++
+				continue
+			}
+		}
+		// Fields that are set by the cluster cannot be applied.
+		obj.SetManagedFields(nil)
+		obj.SetResourceVersion("")
+		err = c.Patch(
+			ctx,
+			obj,
+			client.Apply,
+			client.DryRunAll,
+			client.ForceOwnership,
+			client.FieldOwner(dryRunFieldManager),
+		)
+		if err != nil && ctx.Err() != nil {
+			res.unverified = len(objs) - i
+			break
+		}
+		res.applied++
+		if err != nil {
+			res.failures = append(res.failures, newDryRunFailure(obj, err))
+		}
+	}
+	return res
+}
+
+// namespaceExists returns true if the namespace with the provided name exists
+// in the cluster of the provided client. Results are memoized in the provided
+// map. If the namespace does not exist and the provided policy specifies that
+// missing namespaces are created, it is created in dry-run mode, which
+// returns an error if it could not actually be created.
+func namespaceExists(
+	ctx context.Context,
+	c client.Client,
+	name string,
+	namespaces map[string]bool,
+	policy *kargoapi.DryRunApply,
+) (bool, error) {
+	if exists, ok := namespaces[name]; ok {
+		return exists, nil
+	}
+	ns := &corev1.Namespace{}
+	err := c.Get(ctx, client.ObjectKey{Name: name}, ns)
+	if client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("error getting namespace %q: %w", name, err)
+	}
+	exists := err == nil
+	if !exists && policy.MissingNamespaces == kargoapi.DryRunMissingNamespacePolicyCreate {
+		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if err = c.Create(ctx, ns, client.DryRunAll); err != nil {
+			// The failure is only reported for the first resource in the
+			// namespace.
+			namespaces[name] = false
+			return false, fmt.Errorf("error creating namespace %q in dry-run mode: %w", name, err)
+		}
+	}
+	namespaces[name] = exists
+	return exists, nil
+}
+
+// customResourceKinds returns the kinds that are defined by the
+// CustomResourceDefinitions among the provided resources.
+func customResourceKinds(objs []*unstructured.Unstructured) map[schema.GroupKind]bool {
+	kinds := map[schema.GroupKind]bool{}
+	for _, obj := range objs {
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		kinds[schema.GroupKind{Group: group, Kind: kind}] = true
+	}
+	return kinds
+}
+
+// readManifests returns the resources in the YAML file at the provided path,
+// or in the YAML files in the directory at the provided path and its
+// subdirectories.
+func readManifests(path string) ([]*unstructured.Unstructured, error) {
+	files, err := manifestFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var objs []*unstructured.Unstructured
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := (&kio.ByteReader{
+			Reader:                bytes.NewReader(b),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", filepath.Base(file), err)
+		}
+		for _, node := range nodes {
+			m, err := node.Map()
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %w", filepath.Base(file), err)
+			}
+			objs = append(objs, &unstructured.Unstructured{Object: m})
+		}
+	}
+	return objs, nil
+}
+
+// newDryRunFailure returns a dryRunFailure describing the provided resource,
+// which failed to apply with the provided error.
+func newDryRunFailure(obj *unstructured.Unstructured, err error) dryRunFailure {
+	return dryRunFailure{
+		kind:      obj.GetKind(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+		message:   err.Error(),
+	}
+}
+
+// formatDryRunFailures returns a summary of the provided resources that failed
+// to apply in dry-run mode, which lists at most maxDryRunFailures of them.
+func formatDryRunFailures(failures []dryRunFailure) string {
+	summaries := make([]string, 0, min(len(failures), maxDryRunFailures)+1)
+	for i, f := range failures {
+		if i == maxDryRunFailures {
+			summaries = append(summaries, fmt.Sprintf("and %d more", len(failures)-i))
+			break
+		}
+		name := f.name
+		if f.namespace != "" {
+			name = f.namespace + "/" + name
+		}
+		message := f.message
+		if len(message) > maxDryRunMessageLength {
+			message = message[:maxDryRunMessageLength] + "..."
+		}
+		summaries = append(summaries, fmt.Sprintf("%s %q: %s", f.kind, name, message))
+	}
+	return strings.Join(summaries, "; ")
+}
+
+// message returns a message describing the resources that were not verified,
+// or an empty string if all of them were.
+func (r dryRunResult) message() string {
+	var parts []string
+	if r.skipped > 0 {
+		parts = append(parts, fmt.Sprintf(
+			"%d resources in missing namespaces or of kinds defined by the manifests were skipped",
+			r.skipped,
+		))
+	}
+	if r.unverified > 0 {
+		parts = append(parts, fmt.Sprintf(
+			"%d resources were not verified because the maximum number of "+
+				"resources or the timeout was reached",
+			r.unverified,
+		))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("applied %d resources in dry-run mode; %s", r.applied, strings.Join(parts, "; "))
+}
@@ -0,0 +1,296 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+func Test_argocdDryRunner_runPromotionStep(t *testing.T) {
+	argoCDScheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(argoCDScheme))
+	require.NoError(t, argocd.AddToScheme(argoCDScheme))
+	argoCDClient := fake.NewClientBuilder().WithScheme(argoCDScheme).WithObjects(
+		&argocd.Application{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "argocd", Name: "fake-app"},
+			Spec: argocd.ApplicationSpec{
+				Destination: &argocd.ApplicationDestination{
+					Server:    "https://dest.example.com",
+					Namespace: "app",
+				},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "argocd",
+				Name:      "dest",
+				Labels:    map[string]string{argoCDSecretTypeLabelKey: argoCDSecretTypeCluster},
+			},
+			Data: map[string][]byte{
+				"server": []byte("https://dest.example.com"),
+				"config": []byte(`{"bearerToken":"token"}`),
+			},
+		},
+	).Build()
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	restMapper.Add(
+		schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+		meta.RESTScopeRoot,
+	)
+
+	const configMaps = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: defaulted
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: explicit
+  namespace: app
+`
+
+	testCases := []struct {
+		name        string
+		manifests   string
+		policy      *kargoapi.DryRunApply
+		assertions  func(*testing.T, PromotionStepResult, error, []string, []string)
+		patchErrFor string
+	}{
+		{
+			name:      "Stage does not permit dry-run",
+			manifests: configMaps,
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, _ []string) {
+				require.ErrorContains(t, err, "does not permit applying manifests in dry-run mode")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.Empty(t, applied)
+			},
+		},
+		{
+			name:      "all resources applied",
+			manifests: configMaps,
+			policy:    &kargoapi.DryRunApply{},
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, _ []string) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Empty(t, res.Message)
+				require.Equal(t, []string{"app/defaulted", "app/explicit"}, applied)
+				require.Equal(t, 2, res.Output["applied"])
+			},
+		},
+		{
+			name:        "resource fails to apply",
+			manifests:   configMaps,
+			policy:      &kargoapi.DryRunApply{},
+			patchErrFor: "explicit",
+			assertions: func(t *testing.T, res PromotionStepResult, err error, _, _ []string) {
+				require.True(t, isTerminal(err))
+				require.ErrorContains(t, err, "1 of 2 resources failed to apply")
+				require.ErrorContains(t, err, `ConfigMap "app/explicit": `)
+				require.ErrorContains(t, err, "field is immutable")
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name: "unknown kind",
+			manifests: `---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`,
+			policy: &kargoapi.DryRunApply{},
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, _ []string) {
+				require.True(t, isTerminal(err))
+				require.ErrorContains(t, err, `Widget "widget": `)
+				require.ErrorContains(t, err, `no matches for kind "Widget"`)
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.Empty(t, applied)
+			},
+		},
+		{
+			name: "kind defined by rendered CustomResourceDefinition",
+			manifests: `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`,
+			policy: &kargoapi.DryRunApply{},
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, _ []string) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Equal(t, []string{"widgets.example.com"}, applied)
+				require.Equal(t, 1, res.Output["skipped"])
+				require.Contains(t, res.Message, "1 resources in missing namespaces or of kinds")
+			},
+		},
+		{
+			name: "missing namespace is skipped",
+			manifests: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: elsewhere
+  namespace: new
+`,
+			policy: &kargoapi.DryRunApply{},
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, created []string) {
+				require.NoError(t, err)
+				require.Empty(t, applied)
+				require.Empty(t, created)
+				require.Equal(t, 1, res.Output["skipped"])
+			},
+		},
+		{
+			name: "missing namespace is created",
+			manifests: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: elsewhere
+  namespace: new
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: elsewhere-too
+  namespace: new
+`,
+			policy: &kargoapi.DryRunApply{
+				MissingNamespaces: kargoapi.DryRunMissingNamespacePolicyCreate,
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, created []string) {
+				require.NoError(t, err)
+				require.Empty(t, applied)
+				require.Equal(t, []string{"new"}, created)
+				require.Equal(t, 2, res.Output["skipped"])
+			},
+		},
+		{
+			name:      "maximum number of documents",
+			manifests: configMaps,
+			policy:    &kargoapi.DryRunApply{MaxDocuments: 1},
+			assertions: func(t *testing.T, res PromotionStepResult, err error, applied, _ []string) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Equal(t, []string{"app/defaulted"}, applied)
+				require.Equal(t, 1, res.Output["unverified"])
+				require.Contains(t, res.Message, "1 resources were not verified")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			require.NoError(t, os.WriteFile(
+				filepath.Join(workDir, "manifests.yaml"), []byte(testCase.manifests), 0o600,
+			))
+
+			var applied, created []string
+			clusterClient := fake.NewClientBuilder().
+				WithRESTMapper(restMapper).
+				WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(
+						_ context.Context,
+						_ client.WithWatch,
+						obj client.Object,
+						patch client.Patch,
+						opts ...client.PatchOption,
+					) error {
+						require.Equal(t, client.Apply, patch)
+						patchOpts := &client.PatchOptions{}
+						patchOpts.ApplyOptions(opts)
+						require.Equal(t, []string{metav1.DryRunAll}, patchOpts.DryRun)
+						require.Equal(t, dryRunFieldManager, patchOpts.FieldManager)
+						if obj.GetName() == testCase.patchErrFor {
+							return apierrors.NewBadRequest("field is immutable")
+						}
+						name := obj.GetName()
+						if obj.GetNamespace() != "" {
+							name = obj.GetNamespace() + "/" + name
+						}
+						applied = append(applied, name)
+						return nil
+					},
+					Create: func(
+						_ context.Context,
+						_ client.WithWatch,
+						obj client.Object,
+						opts ...client.CreateOption,
+					) error {
+						createOpts := &client.CreateOptions{}
+						createOpts.ApplyOptions(opts)
+						require.Equal(t, []string{metav1.DryRunAll}, createOpts.DryRun)
+						created = append(created, obj.GetName())
+						return nil
+					},
+				}).
+				Build()
+
+			runner := &argocdDryRunner{
+				newClusterClient: func(cfg *rest.Config) (client.Client, error) {
+					require.Equal(t, "https://dest.example.com", cfg.Host)
+					require.Equal(t, "token", cfg.BearerToken)
+					return clusterClient, nil
+				},
+			}
+			res, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{
+					WorkDir:         workDir,
+					Stage:           "fake-stage",
+					ArgoCDClient:    argoCDClient,
+					ArgoCDNamespace: "argocd",
+					DryRunApply:     testCase.policy,
+				},
+				ArgoCDDryRunConfig{
+					App:  ArgoCDDryRunApp{Name: "fake-app"},
+					Path: "manifests.yaml",
+				},
+			)
+			testCase.assertions(t, res, err, applied, created)
+		})
+	}
+}
+
+func Test_formatDryRunFailures(t *testing.T) {
+	var failures []dryRunFailure
+	for range maxDryRunFailures + 2 {
+		failures = append(failures, dryRunFailure{kind: "ConfigMap", namespace: "app", name: "cm", message: "invalid"})
+	}
+	summary := formatDryRunFailures(failures)
+	require.Contains(t, summary, `ConfigMap "app/cm": invalid; `)
+	require.Contains(t, summary, "; and 2 more")
+}
@@ -5,15 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
-	libargocd "github.com/akuity/kargo/internal/argocd"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	rollouts "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
 )
 
 const applicationStatusesKey = "applicationStatuses"
@@ -36,6 +39,13 @@ type ArgoCDAppHealthCheck struct {
 	// DesiredRevisions is a list of desired revisions for the Argo CD Application
 	// to be synced to.
 	DesiredRevisions []string `json:"desiredRevisions,omitempty"`
+	// Health optionally customizes how the health of the Argo CD Application is
+	// assessed. If nil, the health reported by Argo CD is used.
+	Health *ArgoCDAppHealth `json:"health,omitempty"`
+	// SyncWindowPolicy is how the sync windows of the Argo CD Application's
+	// AppProject are respected when it is not synced to the desired revisions.
+	// If empty, Wait is assumed.
+	SyncWindowPolicy SyncWindowPolicy `json:"syncWindowPolicy,omitempty"`
 }
 
 // ArgoCDAppStatus describes the current state of a single ArgoCD Application.
@@ -45,6 +55,10 @@ type ArgoCDAppStatus struct {
 	// Name is the name of the ArgoCD Application.
 	Name                     string
 	argocd.ApplicationStatus `json:",inline"`
+	// ResourceHealth summarizes the health of the resources managed by the
+	// ArgoCD Application. It is only reported when the health check customizes
+	// how the health of the Application is assessed.
+	ResourceHealth *ArgoCDResourceHealthSummary `json:"resourceHealth,omitempty"`
 }
 
 // compositeError is an interface for wrapped standard errors produced by
@@ -96,7 +110,7 @@ func (a *argocdUpdater) runHealthCheckStep(
 	for i, appHealthCheck := range healthCfg.Apps {
 		namespace := appHealthCheck.Namespace
 		if namespace == "" {
-			namespace = libargocd.Namespace()
+			namespace = argoCDNamespace(healthCtx.ArgoCDNamespace)
 		}
 		appStatuses[i] = ArgoCDAppStatus{
 			Namespace: namespace,
@@ -112,6 +126,8 @@ func (a *argocdUpdater) runHealthCheckStep(
 				Name:      appHealthCheck.Name,
 			},
 			appHealthCheck.DesiredRevisions,
+			appHealthCheck.Health,
+			appHealthCheck.SyncWindowPolicy,
 		)
 		health.Status = health.Status.Merge(state)
 		if err != nil {
@@ -141,12 +157,18 @@ var healthErrorConditions = []argocd.ApplicationConditionType{
 // at its conditions, health status, and sync status. Based on these, it returns
 // an overall health state, the Argo CD Application's health status, and its sync
 // status. If it can not (fully) assess the health of the Argo CD Application, it
-// returns an error with a message explaining why.
+// returns an error with a message explaining why. If healthCfg is not nil, it
+// customizes how the health of the Argo CD Application itself is assessed. An
+// Argo CD Application that is not synced to the desired revisions while the
+// sync windows of its AppProject deny syncs under the provided
+// SyncWindowPolicy is considered progressing rather than unhealthy.
 func (a *argocdUpdater) getApplicationHealth(
 	ctx context.Context,
 	healthCtx *HealthCheckStepContext,
 	appKey client.ObjectKey,
 	desiredRevisions []string,
+	healthCfg *ArgoCDAppHealth,
+	syncWindowPolicy SyncWindowPolicy,
 ) (kargoapi.HealthState, ArgoCDAppStatus, error) {
 	appStatus := ArgoCDAppStatus{
 		Namespace: appKey.Namespace,
@@ -176,8 +198,10 @@ func (a *argocdUpdater) getApplicationHealth(
 		return kargoapi.HealthStateUnknown, appStatus, err
 	}
 
-	// Reflect the health and sync status of the Argo CD Application.
+	// Reflect the health and sync status of the Argo CD Application. The status
+	// of individual resources is omitted as it can be very large.
 	appStatus.ApplicationStatus = app.Status
+	appStatus.Resources = nil
 
 	// Check for any error conditions. If these are found, the application is
 	// considered unhealthy as they may indicate a problem which can result in
@@ -198,6 +222,27 @@ func (a *argocdUpdater) getApplicationHealth(
 
 	if len(desiredRevisions) > 0 {
 		if stageHealth, err := a.stageHealthForAppSync(app, desiredRevisions); err != nil {
+			if stageHealth != kargoapi.HealthStateUnhealthy {
+				return stageHealth, appStatus, err
+			}
+			if syncWindowPolicy == "" {
+				syncWindowPolicy = Wait
+			}
+			wait, waitErr := a.awaitSyncWindow(
+				ctx,
+				healthCtx.ArgoCDClient,
+				argoCDNamespace(healthCtx.ArgoCDNamespace),
+				syncWindowPolicy,
+				app,
+			)
+			if waitErr != nil {
+				return stageHealth, appStatus, errors.Join(err, waitErr)
+			}
+			if wait != nil {
+				return kargoapi.HealthStateProgressing, appStatus, errors.Join(
+					errors.New(wait.message), err,
+				)
+			}
 			return stageHealth, appStatus, err
 		}
 		// If we care about revisions, and recently finished an operation, we
@@ -238,7 +283,15 @@ func (a *argocdUpdater) getApplicationHealth(
 
 	// With all the above checks passed, we can now assume the Argo CD
 	// Application's health state is reliable.
-	stageHealth, err := a.stageHealthForAppHealth(app)
+	if healthCfg != nil {
+		var stageHealth kargoapi.HealthState
+		var err error
+		stageHealth, appStatus.ResourceHealth, err = a.stageHealthForAppHealthConfig(
+			ctx, healthCtx.KargoClient, app, healthCfg,
+		)
+		return stageHealth, appStatus, err
+	}
+	stageHealth, err := a.stageHealthForAppAndRollouts(ctx, healthCtx.KargoClient, app)
 	return stageHealth, appStatus, err
 }
 
@@ -284,6 +337,20 @@ func (a *argocdUpdater) stageHealthForAppSync(
 			app.Name, app.Namespace, len(observedRevisions), len(desiredRevisions),
 		)
 	}
+	// When multiple Stages are represented by different directories on a single
+	// branch, commits made to that branch on behalf of other Stages will advance
+	// the revision Argo CD compares the Application against, even though the
+	// Application's own manifests are unchanged. To accommodate this, a source
+	// is also considered synced to the desired revision if that was the revision
+	// last synced to and the Application is still reported as Synced.
+	var lastSyncedRevisions []string
+	if app.Status.Sync.Status == argocd.SyncStatusCodeSynced &&
+		app.Status.OperationState.SyncResult != nil {
+		lastSyncedRevisions = app.Status.OperationState.SyncResult.Revisions
+		if len(lastSyncedRevisions) == 0 {
+			lastSyncedRevisions = []string{app.Status.OperationState.SyncResult.Revision}
+		}
+	}
 	// Aggregate issues for all sources
 	issues := make([]string, 0)
 	for i, observedRevision := range observedRevisions {
@@ -293,7 +360,8 @@ func (a *argocdUpdater) stageHealthForAppSync(
 			// negatively impact Stage health.
 			continue
 		}
-		if observedRevision != desiredRevision {
+		if observedRevision != desiredRevision &&
+			(i >= len(lastSyncedRevisions) || lastSyncedRevisions[i] != desiredRevision) {
 			issues = append(
 				issues,
 				fmt.Sprintf(
@@ -349,6 +417,162 @@ func (a *argocdUpdater) stageHealthForAppHealth(
 	}
 }
 
+// stageHealthForAppAndRollouts returns the v1alpha1.HealthState for an Argo CD
+// Application based on its health status. A common reason for an Application
+// not being healthy is an Argo Rollouts Rollout that is still progressing
+// through its steps, or that was aborted because of a failed analysis. The
+// details of these are surfaced as additional issues, and the outcome of the
+// Rollouts' current analysis, if any, determines the health of the Stage. The
+// AnalysisRuns are looked up using the provided Kargo client.
+func (a *argocdUpdater) stageHealthForAppAndRollouts(
+	ctx context.Context,
+	c client.Client,
+	app *argocd.Application,
+) (kargoapi.HealthState, error) {
+	stageHealth, err := a.stageHealthForAppHealth(app)
+	if err == nil {
+		return stageHealth, nil
+	}
+	rolloutHealth, rolloutIssues := a.rolloutIssues(ctx, c, app)
+	if len(rolloutIssues) > 0 {
+		err = errors.Join(append([]error{err}, rolloutIssues...)...)
+	}
+	if rolloutHealth != "" {
+		stageHealth = rolloutHealth
+	}
+	return stageHealth, err
+}
+
+// rolloutIssues returns an error for each Argo Rollouts Rollout managed by the
+// Argo CD Application which is not (yet) healthy. For example, because its
+// canary has not been fully promoted yet, or because it was aborted after an
+// AnalysisRun failed. The current AnalysisRuns of each such Rollout are looked
+// up using the provided Kargo client to determine the health of the Stage: it
+// is Unhealthy if any of them was unsuccessful, and Progressing if any of them
+// is still running. If there are no such AnalysisRuns, an empty HealthState is
+// returned, and the health of the Application determines that of the Stage.
+// The same goes if they cannot be looked up, which is reported as an issue.
+func (a *argocdUpdater) rolloutIssues(
+	ctx context.Context,
+	c client.Client,
+	app *argocd.Application,
+) (kargoapi.HealthState, []error) {
+	var health kargoapi.HealthState
+	var issues []error
+	for _, res := range app.Status.Resources {
+		if res.Group != rollouts.GroupVersion.Group || res.Kind != "Rollout" ||
+			res.Health == nil || res.Health.Status == argocd.HealthStatusHealthy {
+			continue
+		}
+		msg := fmt.Sprintf(
+			"Argo Rollouts Rollout %q in namespace %q of Argo CD Application %q "+
+				"in namespace %q has health state %q",
+			res.Name, res.Namespace, app.GetName(), app.GetNamespace(), res.Health.Status,
+		)
+		if res.Health.Message != "" {
+			msg += ": " + res.Health.Message
+		}
+		issues = append(issues, errors.New(msg))
+
+		if c == nil {
+			continue
+		}
+		analysisRuns, err := currentAnalysisRuns(ctx, c, res.Namespace, res.Name)
+		if runtime.IsNotRegisteredError(err) {
+			// The Argo Rollouts integration is disabled, so there is nothing to
+			// look up.
+			continue
+		}
+		if err != nil {
+			// The AnalysisRuns are merely used to refine the health of the
+			// Stage, so failing to look them up does not change it.
+			issues = append(issues, fmt.Errorf(
+				"unable to look up AnalysisRuns of Argo Rollouts Rollout %q in "+
+					"namespace %q: %w",
+				res.Name, res.Namespace, err,
+			))
+			continue
+		}
+		for _, run := range analysisRuns {
+			switch phase := run.Status.Phase; {
+			case phase == rollouts.AnalysisPhaseSuccessful:
+			case phase.Completed():
+				health = kargoapi.HealthStateUnhealthy
+				issues = append(issues, fmt.Errorf(
+					"AnalysisRun %q of Argo Rollouts Rollout %q in namespace %q is %s: %s",
+					run.Name, res.Name, res.Namespace, phase, analysisRunMessage(run),
+				))
+			default:
+				if health == "" {
+					health = kargoapi.HealthStateProgressing
+				}
+				issues = append(issues, fmt.Errorf(
+					"AnalysisRun %q of Argo Rollouts Rollout %q in namespace %q is still running",
+					run.Name, res.Name, res.Namespace,
+				))
+			}
+		}
+	}
+	return health, issues
+}
+
+// rolloutRevisionAnnotationKey is the annotation Argo Rollouts records the
+// revision of a Rollout with on the AnalysisRuns it creates for it.
+const rolloutRevisionAnnotationKey = "rollout.argoproj.io/revision"
+
+// currentAnalysisRuns returns the AnalysisRuns owned by the Argo Rollouts
+// Rollout with the provided namespace and name that belong to its most recent
+// revision. AnalysisRuns of earlier revisions are ignored, as their outcome
+// no longer reflects the health of the Rollout.
+func currentAnalysisRuns(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	rollout string,
+) ([]rollouts.AnalysisRun, error) {
+	list := &rollouts.AnalysisRunList{}
+	if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var current []rollouts.AnalysisRun
+	var currentRevision int
+	for _, run := range list.Items {
+		owner := metav1.GetControllerOf(&run)
+		if owner == nil || owner.Kind != "Rollout" || owner.Name != rollout {
+			continue
+		}
+		revision, _ := strconv.Atoi(run.Annotations[rolloutRevisionAnnotationKey])
+		switch {
+		case revision > currentRevision:
+			currentRevision = revision
+			current = []rollouts.AnalysisRun{run}
+		case revision == currentRevision:
+			current = append(current, run)
+		}
+	}
+	return current, nil
+}
+
+// analysisRunMessage returns the message of the provided AnalysisRun or, if it
+// has none, the messages of those of its metrics that were not successful.
+func analysisRunMessage(run rollouts.AnalysisRun) string {
+	if run.Status.Message != "" {
+		return run.Status.Message
+	}
+	var msgs []string
+	for _, metric := range run.Status.MetricResults {
+		if metric.Phase == rollouts.AnalysisPhaseSuccessful {
+			continue
+		}
+		msg := fmt.Sprintf("metric %q assessed %s", metric.Name, metric.Phase)
+		if metric.Message != "" {
+			msg += ": " + metric.Message
+		}
+		msgs = append(msgs, msg)
+	}
+	return strings.Join(msgs, "; ")
+}
+
 // filterAppConditions returns a slice of v1alpha1.ApplicationCondition that
 // match the provided types.
 func (a *argocdUpdater) filterAppConditions(
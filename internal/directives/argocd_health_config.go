@@ -0,0 +1,311 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+// maxReportedUnhealthyResources is the maximum number of resources that are
+// not healthy to report for a single Argo CD Application, either in its
+// ArgoCDResourceHealthSummary or as issues. Applications may manage thousands
+// of resources, and reporting all of them would bloat the status of the Stage.
+const maxReportedUnhealthyResources = 10
+
+// ArgoCDResourceHealthSummary summarizes the health of the resources managed
+// by an Argo CD Application.
+type ArgoCDResourceHealthSummary struct {
+	// Total is the number of resources of which the health was assessed.
+	Total int `json:"total"`
+	// Statuses is the number of resources by health status.
+	Statuses map[argocd.HealthStatusCode]int `json:"statuses,omitempty"`
+	// Unhealthy lists resources that are not healthy. At most
+	// maxReportedUnhealthyResources are listed.
+	Unhealthy []argocd.ResourceStatus `json:"unhealthy,omitempty"`
+}
+
+// validateAppHealthConfig returns an error if the provided ArgoCDAppHealth
+// cannot be used to assess the health of an Argo CD Application.
+func validateAppHealthConfig(healthCfg *ArgoCDAppHealth) error {
+	if healthCfg == nil {
+		return nil
+	}
+	if healthCfg.ProgressDeadline != "" {
+		if _, err := time.ParseDuration(healthCfg.ProgressDeadline); err != nil {
+			return fmt.Errorf("error parsing progress deadline: %w", err)
+		}
+	}
+	if healthCfg.Expression != "" {
+		if _, err := expr.Compile(healthCfg.Expression); err != nil {
+			return fmt.Errorf("error compiling health expression: %w", err)
+		}
+	}
+	return nil
+}
+
+// stageHealthForAppHealthConfig returns the v1alpha1.HealthState for an Argo
+// CD Application assessed as described by the provided ArgoCDAppHealth, along
+// with a summary of the health of the resources taken into account.
+func (a *argocdUpdater) stageHealthForAppHealthConfig(
+	ctx context.Context,
+	c client.Client,
+	app *argocd.Application,
+	healthCfg *ArgoCDAppHealth,
+) (kargoapi.HealthState, *ArgoCDResourceHealthSummary, error) {
+	var state kargoapi.HealthState
+	var summary *ArgoCDResourceHealthSummary
+	var err error
+	switch {
+	case len(healthCfg.Resources) > 0:
+		var resources []argocd.ResourceStatus
+		if resources, err = selectAppResources(app, healthCfg.Resources); err != nil {
+			return kargoapi.HealthStateUnknown, nil, err
+		}
+		summary = summarizeResourceHealth(resources)
+		state, err = a.stageHealthForResources(app, resources)
+	case healthCfg.Expression != "":
+		summary = summarizeResourceHealth(app.Status.Resources)
+		state, err = a.stageHealthForExpression(app, healthCfg.Expression)
+	default:
+		summary = summarizeResourceHealth(app.Status.Resources)
+		state, err = a.stageHealthForAppAndRollouts(ctx, c, app)
+	}
+	if state == kargoapi.HealthStateProgressing && healthCfg.ProgressDeadline != "" {
+		state, err = a.applyProgressDeadline(app, healthCfg.ProgressDeadline, err)
+	}
+	return state, summary, err
+}
+
+// selectAppResources returns the resources managed by the Argo CD Application
+// that match any of the provided selectors. An error is returned if any
+// selector does not match a single resource, as this most likely means it is
+// misconfigured.
+func selectAppResources(
+	app *argocd.Application,
+	selectors []ArgoCDResourceSelector,
+) ([]argocd.ResourceStatus, error) {
+	selected := make([]bool, len(app.Status.Resources))
+	for _, sel := range selectors {
+		var found bool
+		for i, res := range app.Status.Resources {
+			if res.Group == sel.Group && res.Kind == sel.Kind &&
+				(sel.Name == "" || res.Name == sel.Name) &&
+				(sel.Namespace == "" || res.Namespace == sel.Namespace) {
+				selected[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf(
+				"Argo CD Application %q in namespace %q does not manage any resources "+
+					"matching %s",
+				app.Name, app.Namespace, sel,
+			)
+		}
+	}
+	resources := make([]argocd.ResourceStatus, 0, len(selected))
+	for i, res := range app.Status.Resources {
+		if selected[i] {
+			resources = append(resources, res)
+		}
+	}
+	return resources, nil
+}
+
+// String returns a human-readable description of the selector.
+func (s ArgoCDResourceSelector) String() string {
+	str := "kind " + s.Kind
+	if s.Group != "" {
+		str = fmt.Sprintf("%s in group %s", str, s.Group)
+	}
+	if s.Name != "" {
+		str = fmt.Sprintf("%s with name %q", str, s.Name)
+	}
+	if s.Namespace != "" {
+		str = fmt.Sprintf("%s in namespace %q", str, s.Namespace)
+	}
+	return str
+}
+
+// stageHealthForResources returns the v1alpha1.HealthState for an Argo CD
+// Application based on the health of the provided resources managed by it.
+// Resources of which Argo CD does not assess the health are considered
+// healthy.
+func (a *argocdUpdater) stageHealthForResources(
+	app *argocd.Application,
+	resources []argocd.ResourceStatus,
+) (kargoapi.HealthState, error) {
+	state := kargoapi.HealthStateHealthy
+	var issues []error
+	var unreported int
+	for _, res := range resources {
+		if res.Health == nil || res.Health.Status == argocd.HealthStatusHealthy {
+			continue
+		}
+		state = state.Merge(stageHealthForHealthStatus(res.Health.Status))
+		if len(issues) == maxReportedUnhealthyResources {
+			unreported++
+			continue
+		}
+		msg := fmt.Sprintf(
+			"%s %q in namespace %q of Argo CD Application %q in namespace %q "+
+				"has health state %q",
+			res.Kind, res.Name, res.Namespace, app.Name, app.Namespace, res.Health.Status,
+		)
+		if res.Health.Message != "" {
+			msg += ": " + res.Health.Message
+		}
+		issues = append(issues, errors.New(msg))
+	}
+	if unreported > 0 {
+		issues = append(issues, fmt.Errorf(
+			"%d more resources of Argo CD Application %q in namespace %q are not healthy",
+			unreported, app.Name, app.Namespace,
+		))
+	}
+	return state, errors.Join(issues...)
+}
+
+// stageHealthForExpression returns the v1alpha1.HealthState for an Argo CD
+// Application based on the result of the provided expression. The expression
+// is evaluated against the Application ("app") and the resources it manages
+// ("resources"), and must evaluate to either a boolean or an Argo CD health
+// status.
+func (a *argocdUpdater) stageHealthForExpression(
+	app *argocd.Application,
+	expression string,
+) (kargoapi.HealthState, error) {
+	appMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+	if err != nil {
+		return kargoapi.HealthStateUnknown, fmt.Errorf(
+			"error converting Argo CD Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)
+	}
+	resources := []any{}
+	if status, ok := appMap["status"].(map[string]any); ok {
+		if res, ok := status["resources"].([]any); ok {
+			resources = res
+		}
+	}
+	program, err := expr.Compile(expression)
+	if err != nil {
+		return kargoapi.HealthStateUnknown, fmt.Errorf("error compiling health expression: %w", err)
+	}
+	result, err := expr.Run(program, map[string]any{
+		"app":       appMap,
+		"resources": resources,
+	})
+	if err != nil {
+		return kargoapi.HealthStateUnknown, fmt.Errorf(
+			"error evaluating health expression for Argo CD Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)
+	}
+	switch result := result.(type) {
+	case bool:
+		if result {
+			return kargoapi.HealthStateHealthy, nil
+		}
+		return kargoapi.HealthStateProgressing, fmt.Errorf(
+			"health expression for Argo CD Application %q in namespace %q is not satisfied",
+			app.Name, app.Namespace,
+		)
+	case string:
+		status := argocd.HealthStatusCode(result)
+		switch status {
+		case argocd.HealthStatusHealthy:
+			return kargoapi.HealthStateHealthy, nil
+		case argocd.HealthStatusProgressing, argocd.HealthStatusSuspended,
+			argocd.HealthStatusDegraded, argocd.HealthStatusMissing,
+			argocd.HealthStatusUnknown:
+			return stageHealthForHealthStatus(status), fmt.Errorf(
+				"health expression for Argo CD Application %q in namespace %q "+
+					"evaluated to health state %q",
+				app.Name, app.Namespace, status,
+			)
+		}
+	}
+	return kargoapi.HealthStateUnknown, fmt.Errorf(
+		"health expression for Argo CD Application %q in namespace %q evaluated "+
+			"to %v instead of a boolean or health status",
+		app.Name, app.Namespace, result,
+	)
+}
+
+// applyProgressDeadline returns the v1alpha1.HealthState for a progressing Argo
+// CD Application, which is unhealthy if the provided deadline has passed since
+// its last sync finished. The provided error describing why the Application is
+// progressing is returned along with any error describing the missed deadline.
+func (a *argocdUpdater) applyProgressDeadline(
+	app *argocd.Application,
+	progressDeadline string,
+	progressErr error,
+) (kargoapi.HealthState, error) {
+	deadline, err := time.ParseDuration(progressDeadline)
+	if err != nil {
+		return kargoapi.HealthStateUnknown, errors.Join(
+			progressErr,
+			fmt.Errorf("error parsing progress deadline: %w", err),
+		)
+	}
+	if app.Status.OperationState == nil || app.Status.OperationState.FinishedAt.IsZero() {
+		return kargoapi.HealthStateProgressing, progressErr
+	}
+	if time.Since(app.Status.OperationState.FinishedAt.Time) <= deadline {
+		return kargoapi.HealthStateProgressing, progressErr
+	}
+	return kargoapi.HealthStateUnhealthy, errors.Join(
+		progressErr,
+		fmt.Errorf(
+			"Argo CD Application %q in namespace %q has been progressing for more "+
+				"than %s since its last sync finished",
+			app.Name, app.Namespace, deadline,
+		),
+	)
+}
+
+// stageHealthForHealthStatus returns the v1alpha1.HealthState corresponding to
+// an Argo CD health status.
+func stageHealthForHealthStatus(status argocd.HealthStatusCode) kargoapi.HealthState {
+	switch status {
+	case argocd.HealthStatusHealthy:
+		return kargoapi.HealthStateHealthy
+	case argocd.HealthStatusProgressing, argocd.HealthStatusSuspended, "":
+		return kargoapi.HealthStateProgressing
+	case argocd.HealthStatusUnknown:
+		return kargoapi.HealthStateUnknown
+	default:
+		return kargoapi.HealthStateUnhealthy
+	}
+}
+
+// summarizeResourceHealth returns a summary of the health of the provided
+// resources. Resources of which Argo CD does not assess the health are
+// omitted.
+func summarizeResourceHealth(resources []argocd.ResourceStatus) *ArgoCDResourceHealthSummary {
+	summary := &ArgoCDResourceHealthSummary{}
+	for _, res := range resources {
+		if res.Health == nil {
+			continue
+		}
+		summary.Total++
+		if summary.Statuses == nil {
+			summary.Statuses = map[argocd.HealthStatusCode]int{}
+		}
+		summary.Statuses[res.Health.Status]++
+		if res.Health.Status != argocd.HealthStatusHealthy &&
+			len(summary.Unhealthy) < maxReportedUnhealthyResources {
+			summary.Unhealthy = append(summary.Unhealthy, res)
+		}
+	}
+	return summary
+}
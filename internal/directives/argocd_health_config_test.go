@@ -0,0 +1,353 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	rollouts "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
+)
+
+func Test_validateAppHealthConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		healthCfg *ArgoCDAppHealth
+		errMsg    string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "valid",
+			healthCfg: &ArgoCDAppHealth{
+				Expression:       `app.status.health.status == "Healthy"`,
+				ProgressDeadline: "10m",
+			},
+		},
+		{
+			name:      "invalid progress deadline",
+			healthCfg: &ArgoCDAppHealth{ProgressDeadline: "10"},
+			errMsg:    "error parsing progress deadline",
+		},
+		{
+			name:      "invalid expression",
+			healthCfg: &ArgoCDAppHealth{Expression: "app.status ==="},
+			errMsg:    "error compiling health expression",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAppHealthConfig(testCase.healthCfg)
+			if testCase.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, testCase.errMsg)
+		})
+	}
+}
+
+func Test_argocdUpdater_stageHealthForAppHealthConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, rollouts.AddToScheme(scheme))
+
+	testResources := []argocd.ResourceStatus{
+		{
+			Kind:      "ConfigMap",
+			Namespace: "fake-namespace",
+			Name:      "fake-config",
+		},
+		{
+			Group:     "apps",
+			Kind:      "Deployment",
+			Namespace: "fake-namespace",
+			Name:      "fake-deployment",
+			Health:    &argocd.HealthStatus{Status: argocd.HealthStatusHealthy},
+		},
+		{
+			Group:     "batch",
+			Kind:      "Job",
+			Namespace: "fake-namespace",
+			Name:      "fake-job",
+			Health: &argocd.HealthStatus{
+				Status:  argocd.HealthStatusDegraded,
+				Message: "Job has reached the specified backoff limit",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		appStatus  argocd.ApplicationStatus
+		healthCfg  *ArgoCDAppHealth
+		assertions func(*testing.T, kargoapi.HealthState, *ArgoCDResourceHealthSummary, error)
+	}{
+		{
+			name: "health reported by Argo CD",
+			appStatus: argocd.ApplicationStatus{
+				Health:    argocd.HealthStatus{Status: argocd.HealthStatusHealthy},
+				Resources: testResources,
+			},
+			healthCfg: &ArgoCDAppHealth{},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				summary *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.HealthStateHealthy, state)
+				require.Equal(t, 2, summary.Total)
+				require.Equal(
+					t,
+					map[argocd.HealthStatusCode]int{
+						argocd.HealthStatusHealthy:  1,
+						argocd.HealthStatusDegraded: 1,
+					},
+					summary.Statuses,
+				)
+				require.Equal(t, []argocd.ResourceStatus{testResources[2]}, summary.Unhealthy)
+			},
+		},
+		{
+			name: "selected resources are healthy",
+			appStatus: argocd.ApplicationStatus{
+				Health:    argocd.HealthStatus{Status: argocd.HealthStatusDegraded},
+				Resources: testResources,
+			},
+			healthCfg: &ArgoCDAppHealth{
+				Resources: []ArgoCDResourceSelector{
+					{Kind: "ConfigMap"},
+					{Group: "apps", Kind: "Deployment", Name: "fake-deployment"},
+				},
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				summary *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.HealthStateHealthy, state)
+				require.Equal(t, 1, summary.Total)
+				require.Empty(t, summary.Unhealthy)
+			},
+		},
+		{
+			name: "selected resource is not healthy",
+			appStatus: argocd.ApplicationStatus{
+				Health:    argocd.HealthStatus{Status: argocd.HealthStatusDegraded},
+				Resources: testResources,
+			},
+			healthCfg: &ArgoCDAppHealth{
+				Resources: []ArgoCDResourceSelector{
+					{Group: "batch", Kind: "Job", Namespace: "fake-namespace"},
+				},
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				summary *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, `Job "fake-job" in namespace "fake-namespace"`)
+				require.ErrorContains(t, err, "Job has reached the specified backoff limit")
+				require.Equal(t, kargoapi.HealthStateUnhealthy, state)
+				require.Equal(t, []argocd.ResourceStatus{testResources[2]}, summary.Unhealthy)
+			},
+		},
+		{
+			name: "selector matches no resources",
+			appStatus: argocd.ApplicationStatus{
+				Resources: testResources,
+			},
+			healthCfg: &ArgoCDAppHealth{
+				Resources: []ArgoCDResourceSelector{
+					{Group: "apps", Kind: "StatefulSet"},
+				},
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				summary *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, "does not manage any resources matching kind StatefulSet in group apps")
+				require.Equal(t, kargoapi.HealthStateUnknown, state)
+				require.Nil(t, summary)
+			},
+		},
+		{
+			name: "expression evaluates to true",
+			appStatus: argocd.ApplicationStatus{
+				Health:    argocd.HealthStatus{Status: argocd.HealthStatusDegraded},
+				Resources: testResources,
+			},
+			healthCfg: &ArgoCDAppHealth{
+				Expression: `all(filter(resources, .kind == "Deployment"), .health.status == "Healthy")`,
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				summary *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.HealthStateHealthy, state)
+				require.Equal(t, 2, summary.Total)
+			},
+		},
+		{
+			name: "expression evaluates to false",
+			appStatus: argocd.ApplicationStatus{
+				Health:    argocd.HealthStatus{Status: argocd.HealthStatusHealthy},
+				Resources: testResources,
+			},
+			healthCfg: &ArgoCDAppHealth{
+				Expression: `all(resources, .health?.status in [nil, "Healthy"])`,
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				_ *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, "is not satisfied")
+				require.Equal(t, kargoapi.HealthStateProgressing, state)
+			},
+		},
+		{
+			name: "expression evaluates to health status",
+			appStatus: argocd.ApplicationStatus{
+				Health: argocd.HealthStatus{Status: argocd.HealthStatusDegraded},
+			},
+			healthCfg: &ArgoCDAppHealth{
+				Expression: `app.status.health.status`,
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				_ *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, `evaluated to health state "Degraded"`)
+				require.Equal(t, kargoapi.HealthStateUnhealthy, state)
+			},
+		},
+		{
+			name: "expression evaluates to something else",
+			healthCfg: &ArgoCDAppHealth{
+				Expression: `42`,
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				_ *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, "evaluated to 42 instead of a boolean or health status")
+				require.Equal(t, kargoapi.HealthStateUnknown, state)
+			},
+		},
+		{
+			name: "progressing within progress deadline",
+			appStatus: argocd.ApplicationStatus{
+				Health: argocd.HealthStatus{Status: argocd.HealthStatusProgressing},
+				OperationState: &argocd.OperationState{
+					FinishedAt: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				},
+			},
+			healthCfg: &ArgoCDAppHealth{
+				ProgressDeadline: "10m",
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				_ *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, "is progressing")
+				require.NotContains(t, err.Error(), "progressing for more than")
+				require.Equal(t, kargoapi.HealthStateProgressing, state)
+			},
+		},
+		{
+			name: "progressing past progress deadline",
+			appStatus: argocd.ApplicationStatus{
+				Health: argocd.HealthStatus{Status: argocd.HealthStatusProgressing},
+				OperationState: &argocd.OperationState{
+					FinishedAt: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+				},
+			},
+			healthCfg: &ArgoCDAppHealth{
+				ProgressDeadline: "10m",
+			},
+			assertions: func(
+				t *testing.T,
+				state kargoapi.HealthState,
+				_ *ArgoCDResourceHealthSummary,
+				err error,
+			) {
+				require.ErrorContains(t, err, "has been progressing for more than 10m0s")
+				require.Equal(t, kargoapi.HealthStateUnhealthy, state)
+			},
+		},
+	}
+
+	runner := &argocdUpdater{}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			app := &argocd.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-namespace",
+					Name:      "fake-name",
+				},
+				Status: testCase.appStatus,
+			}
+			state, summary, err := runner.stageHealthForAppHealthConfig(
+				context.Background(),
+				fake.NewClientBuilder().WithScheme(scheme).Build(),
+				app,
+				testCase.healthCfg,
+			)
+			testCase.assertions(t, state, summary, err)
+		})
+	}
+}
+
+func Test_argocdUpdater_stageHealthForResources(t *testing.T) {
+	resources := make([]argocd.ResourceStatus, maxReportedUnhealthyResources+5)
+	for i := range resources {
+		resources[i] = argocd.ResourceStatus{
+			Kind:   "Pod",
+			Name:   fmt.Sprintf("fake-pod-%d", i),
+			Health: &argocd.HealthStatus{Status: argocd.HealthStatusMissing},
+		}
+	}
+	app := &argocd.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-namespace",
+			Name:      "fake-name",
+		},
+	}
+
+	state, err := (&argocdUpdater{}).stageHealthForResources(app, resources)
+	require.Equal(t, kargoapi.HealthStateUnhealthy, state)
+	cErr, ok := err.(compositeError)
+	require.True(t, ok)
+	require.Len(t, cErr.Unwrap(), maxReportedUnhealthyResources+1)
+	require.ErrorContains(t, err, "5 more resources")
+
+	summary := summarizeResourceHealth(resources)
+	require.Equal(t, len(resources), summary.Total)
+	require.Len(t, summary.Unhealthy, maxReportedUnhealthyResources)
+}
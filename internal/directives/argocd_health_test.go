@@ -18,6 +18,7 @@ import (
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	rollouts "github.com/akuity/kargo/internal/controller/rollouts/api/v1alpha1"
 )
 
 func Test_argocdUpdater_runHealthCheckStep(t *testing.T) {
@@ -158,6 +159,7 @@ func Test_argocdUpdater_runHealthCheckStep(t *testing.T) {
 	}
 
 	runner := &argocdUpdater{}
+	runner.getSyncWindowsFn = runner.getSyncWindows
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -216,6 +218,8 @@ func Test_argocdUpdater_getApplicationHealth(t *testing.T) {
 		appStatus        argocd.ApplicationStatus
 		interceptor      interceptor.Funcs
 		desiredRevisions []string
+		project          *argocd.AppProject
+		syncWindowPolicy SyncWindowPolicy
 		assertions       func(*testing.T, kargoapi.HealthState, ArgoCDAppStatus, error)
 	}{
 		{
@@ -366,6 +370,87 @@ func Test_argocdUpdater_getApplicationHealth(t *testing.T) {
 				require.Equal(t, argocd.SyncStatusCodeSynced, appStatus.ApplicationStatus.Sync.Status)
 			},
 		},
+		{
+			name: "revisions out of sync while sync windows deny syncs",
+			appStatus: argocd.ApplicationStatus{
+				Health: argocd.HealthStatus{
+					Status: argocd.HealthStatusHealthy,
+				},
+				Sync: argocd.SyncStatus{
+					Status:    argocd.SyncStatusCodeOutOfSync,
+					Revisions: []string{"fake-version", "wrong-fake-commit", "another-fake-commit"},
+				},
+				OperationState: &argocd.OperationState{
+					FinishedAt: ptr.To(metav1.Now()),
+				},
+			},
+			desiredRevisions: []string{"fake-version", "fake-commit", "another-fake-commit"},
+			project: &argocd.AppProject{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-argocd-namespace",
+					Name:      "default",
+				},
+				Spec: argocd.AppProjectSpec{
+					SyncWindows: argocd.SyncWindows{{
+						Kind:         argocd.SyncWindowKindDeny,
+						Schedule:     "* * * * *",
+						Duration:     "1h",
+						Applications: []string{"fake-*"},
+					}},
+				},
+			},
+			assertions: func(
+				t *testing.T,
+				stageHealth kargoapi.HealthState,
+				_ ArgoCDAppStatus,
+				err error,
+			) {
+				require.ErrorContains(t, err, "are denied by the sync windows of its AppProject")
+				require.ErrorContains(t, err, "Not all sources of Application")
+				require.Equal(t, kargoapi.HealthStateProgressing, stageHealth)
+			},
+		},
+		{
+			name: "revisions out of sync while sync windows are ignored",
+			appStatus: argocd.ApplicationStatus{
+				Health: argocd.HealthStatus{
+					Status: argocd.HealthStatusHealthy,
+				},
+				Sync: argocd.SyncStatus{
+					Status:    argocd.SyncStatusCodeOutOfSync,
+					Revisions: []string{"fake-version", "wrong-fake-commit", "another-fake-commit"},
+				},
+				OperationState: &argocd.OperationState{
+					FinishedAt: ptr.To(metav1.Now()),
+				},
+			},
+			desiredRevisions: []string{"fake-version", "fake-commit", "another-fake-commit"},
+			project: &argocd.AppProject{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-argocd-namespace",
+					Name:      "default",
+				},
+				Spec: argocd.AppProjectSpec{
+					SyncWindows: argocd.SyncWindows{{
+						Kind:         argocd.SyncWindowKindDeny,
+						Schedule:     "* * * * *",
+						Duration:     "1h",
+						Applications: []string{"fake-*"},
+					}},
+				},
+			},
+			syncWindowPolicy: Ignore,
+			assertions: func(
+				t *testing.T,
+				stageHealth kargoapi.HealthState,
+				_ ArgoCDAppStatus,
+				err error,
+			) {
+				require.ErrorContains(t, err, "Not all sources of Application")
+				require.NotContains(t, err.Error(), "sync windows")
+				require.Equal(t, kargoapi.HealthStateUnhealthy, stageHealth)
+			},
+		},
 		{
 			name: "no error conditions and revisions in sync",
 			appStatus: argocd.ApplicationStatus{
@@ -398,25 +483,33 @@ func Test_argocdUpdater_getApplicationHealth(t *testing.T) {
 	}
 
 	runner := &argocdUpdater{}
+	runner.getSyncWindowsFn = runner.getSyncWindows
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			app := testApp.DeepCopy()
 			app.Status = testCase.appStatus
+			objects := []client.Object{app}
+			if testCase.project != nil {
+				objects = append(objects, testCase.project)
+			}
 			stageHealth, appStatus, err := runner.getApplicationHealth(
 				context.Background(),
 				&HealthCheckStepContext{
 					ArgoCDClient: fake.NewClientBuilder().
 						WithScheme(scheme).
-						WithObjects(app).
+						WithObjects(objects...).
 						WithInterceptorFuncs(testCase.interceptor).
 						Build(),
+					ArgoCDNamespace: "fake-argocd-namespace",
 				},
 				client.ObjectKey{
 					Namespace: app.Namespace,
 					Name:      app.Name,
 				},
 				testCase.desiredRevisions,
+				nil,
+				testCase.syncWindowPolicy,
 			)
 			testCase.assertions(t, stageHealth, appStatus, err)
 		})
@@ -465,6 +558,8 @@ func Test_argocdUpdater_getApplicationHealth(t *testing.T) {
 				Name:      testApp.Name,
 			},
 			[]string{"fake-version", "fake-commit", "another-fake-commit"},
+			nil,
+			Ignore,
 		)
 		elapsed := time.Since(app.Status.OperationState.FinishedAt.Time)
 		require.NoError(t, err)
@@ -565,6 +660,56 @@ func Test_argocdUpdater_stageHealthForAppSync(t *testing.T) {
 				require.Equal(t, kargoapi.HealthStateUnhealthy, health)
 			},
 		},
+		{
+			name:      "sync revision mismatch after sync to desired revision",
+			revisions: []string{"fake-revision"},
+			app: &argocd.Application{
+				Spec: argocd.ApplicationSpec{
+					Source: &argocd.ApplicationSource{},
+				},
+				Status: argocd.ApplicationStatus{
+					Sync: argocd.SyncStatus{
+						Status:   argocd.SyncStatusCodeOutOfSync,
+						Revision: "newer-fake-revision",
+					},
+					OperationState: &argocd.OperationState{
+						FinishedAt: ptr.To(metav1.Now()),
+						SyncResult: &argocd.SyncOperationResult{
+							Revision: "fake-revision",
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, health kargoapi.HealthState, err error) {
+				require.ErrorContains(t, err, "are synced to the desired revisions")
+				require.Equal(t, kargoapi.HealthStateUnhealthy, health)
+			},
+		},
+		{
+			name:      "synced to desired revision on a since advanced branch",
+			revisions: []string{"fake-revision", "another-fake-revision"},
+			app: &argocd.Application{
+				Spec: argocd.ApplicationSpec{
+					Sources: []argocd.ApplicationSource{{}, {}},
+				},
+				Status: argocd.ApplicationStatus{
+					Sync: argocd.SyncStatus{
+						Status:    argocd.SyncStatusCodeSynced,
+						Revisions: []string{"newer-fake-revision", "another-fake-revision"},
+					},
+					OperationState: &argocd.OperationState{
+						FinishedAt: ptr.To(metav1.Now()),
+						SyncResult: &argocd.SyncOperationResult{
+							Revisions: []string{"fake-revision", "another-fake-revision"},
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, state kargoapi.HealthState, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.HealthStateHealthy, state)
+			},
+		},
 		{
 			name:      "synced",
 			revisions: []string{"fake-revision", "another-fake-revision"},
@@ -687,6 +832,191 @@ func Test_argocdUpdater_stageHealthForAppHealth(t *testing.T) {
 	}
 }
 
+func Test_argocdUpdater_rolloutIssues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, rollouts.AddToScheme(scheme))
+
+	degradedRollout := argocd.ResourceStatus{
+		Group:     "argoproj.io",
+		Kind:      "Rollout",
+		Namespace: "fake-namespace",
+		Name:      "fake-rollout",
+		Health: &argocd.HealthStatus{
+			Status:  argocd.HealthStatusDegraded,
+			Message: "RolloutAborted: metric \"success-rate\" assessed Failed",
+		},
+	}
+	analysisRun := func(
+		name string,
+		revision string,
+		phase rollouts.AnalysisPhase,
+	) *rollouts.AnalysisRun {
+		return &rollouts.AnalysisRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "fake-namespace",
+				Name:        name,
+				Annotations: map[string]string{rolloutRevisionAnnotationKey: revision},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "argoproj.io/v1alpha1",
+					Kind:       "Rollout",
+					Name:       "fake-rollout",
+					UID:        "fake-uid",
+					Controller: ptr.To(true),
+				}},
+			},
+			Status: rollouts.AnalysisRunStatus{
+				Phase: phase,
+				MetricResults: []rollouts.MetricResult{{
+					Name:  "success-rate",
+					Phase: phase,
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		resources    []argocd.ResourceStatus
+		analysisRuns []client.Object
+		client       client.Client
+		assertions   func(*testing.T, kargoapi.HealthState, []error)
+	}{
+		{
+			name: "no resources",
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Empty(t, health)
+				require.Empty(t, issues)
+			},
+		},
+		{
+			name: "healthy Rollout",
+			resources: []argocd.ResourceStatus{{
+				Group:  "argoproj.io",
+				Kind:   "Rollout",
+				Name:   "fake-rollout",
+				Health: &argocd.HealthStatus{Status: argocd.HealthStatusHealthy},
+			}},
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Empty(t, health)
+				require.Empty(t, issues)
+			},
+		},
+		{
+			name: "unhealthy resource that is not a Rollout",
+			resources: []argocd.ResourceStatus{{
+				Group:  "apps",
+				Kind:   "Deployment",
+				Name:   "fake-deployment",
+				Health: &argocd.HealthStatus{Status: argocd.HealthStatusDegraded},
+			}},
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Empty(t, health)
+				require.Empty(t, issues)
+			},
+		},
+		{
+			name: "unhealthy Rollouts without AnalysisRuns",
+			resources: []argocd.ResourceStatus{
+				degradedRollout,
+				{
+					Group:     "argoproj.io",
+					Kind:      "Rollout",
+					Namespace: "fake-namespace",
+					Name:      "other-rollout",
+					Health:    &argocd.HealthStatus{Status: argocd.HealthStatusSuspended},
+				},
+			},
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Empty(t, health)
+				require.Len(t, issues, 2)
+				require.ErrorContains(t, issues[0], `Rollout "fake-rollout"`)
+				require.ErrorContains(t, issues[0], "assessed Failed")
+				require.ErrorContains(t, issues[1], `Rollout "other-rollout"`)
+				require.ErrorContains(t, issues[1], `health state "Suspended"`)
+			},
+		},
+		{
+			name:      "AnalysisRun of current revision is running",
+			resources: []argocd.ResourceStatus{degradedRollout},
+			analysisRuns: []client.Object{
+				analysisRun("previous", "1", rollouts.AnalysisPhaseFailed),
+				analysisRun("current", "2", rollouts.AnalysisPhaseRunning),
+			},
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Equal(t, kargoapi.HealthStateProgressing, health)
+				require.Len(t, issues, 2)
+				require.ErrorContains(t, issues[1], `AnalysisRun "current"`)
+				require.ErrorContains(t, issues[1], "is still running")
+			},
+		},
+		{
+			name:      "AnalysisRun of current revision failed",
+			resources: []argocd.ResourceStatus{degradedRollout},
+			analysisRuns: []client.Object{
+				analysisRun("previous", "1", rollouts.AnalysisPhaseSuccessful),
+				analysisRun("current", "2", rollouts.AnalysisPhaseFailed),
+				analysisRun("background", "2", rollouts.AnalysisPhaseRunning),
+			},
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Equal(t, kargoapi.HealthStateUnhealthy, health)
+				require.Len(t, issues, 3)
+				require.ErrorContains(t, issues[1], `AnalysisRun "background"`)
+				require.ErrorContains(t, issues[2], `AnalysisRun "current"`)
+				require.ErrorContains(t, issues[2], `metric "success-rate" assessed Failed`)
+			},
+		},
+		{
+			name:      "Argo Rollouts integration disabled",
+			resources: []argocd.ResourceStatus{degradedRollout},
+			client:    fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Empty(t, health)
+				require.Len(t, issues, 1)
+			},
+		},
+		{
+			name:      "AnalysisRuns cannot be looked up",
+			resources: []argocd.ResourceStatus{degradedRollout},
+			client: fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(
+				interceptor.Funcs{
+					List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+						return errors.New("something went wrong")
+					},
+				},
+			).Build(),
+			assertions: func(t *testing.T, health kargoapi.HealthState, issues []error) {
+				require.Empty(t, health)
+				require.Len(t, issues, 2)
+				require.ErrorContains(t, issues[1], "unable to look up AnalysisRuns")
+				require.ErrorContains(t, issues[1], `Rollout "fake-rollout"`)
+				require.ErrorContains(t, issues[1], "something went wrong")
+			},
+		},
+	}
+
+	runner := &argocdUpdater{}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := testCase.client
+			if c == nil {
+				c = fake.NewClientBuilder().WithScheme(scheme).
+					WithObjects(testCase.analysisRuns...).Build()
+			}
+			health, issues := runner.rolloutIssues(
+				context.Background(),
+				c,
+				&argocd.Application{
+					Status: argocd.ApplicationStatus{
+						Resources: testCase.resources,
+					},
+				},
+			)
+			testCase.assertions(t, health, issues)
+		})
+	}
+}
+
 func Test_argocdUpdater_filterAppConditions(t *testing.T) {
 	testCases := []struct {
 		name       string
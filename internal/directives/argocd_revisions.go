@@ -2,8 +2,11 @@ package directives
 
 import (
 	"fmt"
+	"path"
 
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/helm"
 )
 
 // getDesiredRevisions returns the desired revisions for all sources of the given
@@ -49,6 +52,83 @@ func (a *argocdUpdater) getDesiredRevisions(
 	return revisions, nil
 }
 
+// resolveDesiredChartVersions sets the desired revision of every source update
+// that targets a Helm chart, is meant to update the source's target revision,
+// and does not otherwise specify a desired revision, to the version of that
+// chart found in the Freight referenced by the Promotion. This permits charts
+// to be promoted by version, in the same way images are promoted by tag. An
+// error is returned if such a chart is not found in the Freight.
+func resolveDesiredChartVersions(
+	stepCtx *PromotionStepContext,
+	stepCfg *ArgoCDUpdateConfig,
+) error {
+	for i := range stepCfg.Apps {
+		appUpdate := &stepCfg.Apps[i]
+		for j := range appUpdate.Sources {
+			sourceUpdate := &appUpdate.Sources[j]
+			if sourceUpdate.Chart == "" || !sourceUpdate.UpdateTargetRevision ||
+				sourceUpdate.DesiredRevision != "" || sourceUpdate.DesiredCommitFromStep != "" {
+				continue
+			}
+			chart, err := findChartForSource(
+				stepCtx.Freight.References(),
+				getDesiredOrigin(stepCfg, sourceUpdate),
+				sourceUpdate.RepoURL,
+				sourceUpdate.Chart,
+			)
+			if err != nil {
+				return err
+			}
+			if chart == nil {
+				return fmt.Errorf(
+					"chart %q from repo %s not found in referenced Freight",
+					sourceUpdate.Chart, sourceUpdate.RepoURL,
+				)
+			}
+			sourceUpdate.DesiredRevision = chart.Version
+		}
+	}
+	return nil
+}
+
+// findChartForSource returns the chart in the provided FreightReferences that
+// corresponds to an Argo CD Application source with the provided repository
+// URL and chart name. Because Argo CD and Kargo address charts in OCI
+// registries differently, charts are compared by their normalized repository
+// URL joined with their name. If desiredOrigin is non-nil, only Freight from
+// that origin is considered. If no such chart is found, nil is returned. If
+// Freight from different origins references different versions of the chart,
+// an error is returned.
+func findChartForSource(
+	freight []kargoapi.FreightReference,
+	desiredOrigin *kargoapi.FreightOrigin,
+	repoURL string,
+	chartName string,
+) (*kargoapi.Chart, error) {
+	key := path.Join(helm.NormalizeChartRepositoryURL(repoURL), chartName)
+	var found *kargoapi.Chart
+	for _, ref := range freight {
+		if desiredOrigin != nil && !ref.Origin.Equals(desiredOrigin) {
+			continue
+		}
+		for i := range ref.Charts {
+			chart := &ref.Charts[i]
+			if path.Join(helm.NormalizeChartRepositoryURL(chart.RepoURL), chart.Name) != key {
+				continue
+			}
+			if found != nil && found.Version != chart.Version {
+				return nil, fmt.Errorf(
+					"multiple versions of chart %q from repo %s found in referenced "+
+						"Freight: please provide a Freight origin to disambiguate",
+					chartName, repoURL,
+				)
+			}
+			found = chart
+		}
+	}
+	return found, nil
+}
+
 // findSourceUpdate finds and returns the ArgoCDSourceUpdate that targets the
 // given source. If no such update exists, it returns nil.
 func (a *argocdUpdater) findSourceUpdate(
@@ -91,3 +171,16 @@ func getCommitFromStep(sharedState State, stepAlias string) (string, error) {
 	}
 	return commit, nil
 }
+
+// getCommitTrailersFromStep returns the Kargo trailers found in the message of
+// the commit referenced by the output of the step with the given alias. If the
+// step recorded no such trailers, empty CommitTrailers are returned.
+func getCommitTrailersFromStep(sharedState State, stepAlias string) kargoapi.CommitTrailers {
+	if stepAlias == "" {
+		return kargoapi.CommitTrailers{}
+	}
+	stepOutput, _ := sharedState.Get(stepAlias)
+	stepOutputMap, _ := stepOutput.(map[string]any)
+	trailers, _ := stepOutputMap[stateKeyCommitTrailers].(string)
+	return kargoapi.ParseCommitTrailers(trailers)
+}
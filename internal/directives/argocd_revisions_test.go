@@ -138,3 +138,210 @@ func Test_argoCDUpdater_getDesiredRevisions(t *testing.T) {
 		})
 	}
 }
+
+func Test_resolveDesiredChartVersions(t *testing.T) {
+	testOrigin := kargoapi.FreightOrigin{
+		Kind: kargoapi.FreightOriginKindWarehouse,
+		Name: "fake-warehouse",
+	}
+	otherOrigin := kargoapi.FreightOrigin{
+		Kind: kargoapi.FreightOriginKindWarehouse,
+		Name: "other-fake-warehouse",
+	}
+	testCases := []struct {
+		name       string
+		freight    []kargoapi.FreightReference
+		cfg        ArgoCDUpdateConfig
+		assertions func(*testing.T, ArgoCDUpdateConfig, error)
+	}{
+		{
+			name: "target revision not updated",
+			freight: []kargoapi.FreightReference{{
+				Origin: testOrigin,
+				Charts: []kargoapi.Chart{{
+					RepoURL: "https://charts.example.com",
+					Name:    "fake-chart",
+					Version: "1.4.2",
+				}},
+			}},
+			cfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL: "https://charts.example.com",
+						Chart:   "fake-chart",
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, cfg ArgoCDUpdateConfig, err error) {
+				require.NoError(t, err)
+				require.Empty(t, cfg.Apps[0].Sources[0].DesiredRevision)
+			},
+		},
+		{
+			name: "desired revision explicitly specified",
+			freight: []kargoapi.FreightReference{{
+				Origin: testOrigin,
+				Charts: []kargoapi.Chart{{
+					RepoURL: "https://charts.example.com",
+					Name:    "fake-chart",
+					Version: "1.4.2",
+				}},
+			}},
+			cfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:              "https://charts.example.com",
+						Chart:                "fake-chart",
+						DesiredRevision:      "1.0.0",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, cfg ArgoCDUpdateConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1.0.0", cfg.Apps[0].Sources[0].DesiredRevision)
+			},
+		},
+		{
+			name: "chart not found in Freight",
+			cfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:              "https://charts.example.com",
+						Chart:                "fake-chart",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, _ ArgoCDUpdateConfig, err error) {
+				require.ErrorContains(t, err, "not found in referenced Freight")
+			},
+		},
+		{
+			name: "chart from classic repository",
+			freight: []kargoapi.FreightReference{{
+				Origin: testOrigin,
+				Charts: []kargoapi.Chart{{
+					RepoURL: "https://charts.example.com",
+					Name:    "fake-chart",
+					Version: "1.4.2",
+				}},
+			}},
+			cfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:              "https://charts.example.com",
+						Chart:                "fake-chart",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, cfg ArgoCDUpdateConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1.4.2", cfg.Apps[0].Sources[0].DesiredRevision)
+			},
+		},
+		{
+			name: "chart from OCI registry",
+			freight: []kargoapi.FreightReference{{
+				Origin: testOrigin,
+				Charts: []kargoapi.Chart{{
+					RepoURL: "oci://ghcr.io/example/charts/fake-chart",
+					Version: "1.4.2",
+				}},
+			}},
+			cfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						// Argo CD addresses charts in OCI registries without a scheme
+						// and with the chart name separate from the repository URL.
+						RepoURL:              "ghcr.io/example/charts",
+						Chart:                "fake-chart",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, cfg ArgoCDUpdateConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1.4.2", cfg.Apps[0].Sources[0].DesiredRevision)
+			},
+		},
+		{
+			name: "different versions from multiple origins",
+			freight: []kargoapi.FreightReference{
+				{
+					Origin: testOrigin,
+					Charts: []kargoapi.Chart{{
+						RepoURL: "oci://ghcr.io/example/charts/fake-chart",
+						Version: "1.4.2",
+					}},
+				},
+				{
+					Origin: otherOrigin,
+					Charts: []kargoapi.Chart{{
+						RepoURL: "oci://ghcr.io/example/charts/fake-chart",
+						Version: "1.5.0",
+					}},
+				},
+			},
+			cfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:              "ghcr.io/example/charts",
+						Chart:                "fake-chart",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, _ ArgoCDUpdateConfig, err error) {
+				require.ErrorContains(t, err, "multiple versions of chart")
+			},
+		},
+		{
+			name: "different versions from multiple origins with origin specified",
+			freight: []kargoapi.FreightReference{
+				{
+					Origin: testOrigin,
+					Charts: []kargoapi.Chart{{
+						RepoURL: "oci://ghcr.io/example/charts/fake-chart",
+						Version: "1.4.2",
+					}},
+				},
+				{
+					Origin: otherOrigin,
+					Charts: []kargoapi.Chart{{
+						RepoURL: "oci://ghcr.io/example/charts/fake-chart",
+						Version: "1.5.0",
+					}},
+				},
+			},
+			cfg: ArgoCDUpdateConfig{
+				FromOrigin: &AppFromOrigin{
+					Kind: Kind(otherOrigin.Kind),
+					Name: otherOrigin.Name,
+				},
+				Apps: []ArgoCDAppUpdate{{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:              "ghcr.io/example/charts",
+						Chart:                "fake-chart",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, cfg ArgoCDUpdateConfig, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "1.5.0", cfg.Apps[0].Sources[0].DesiredRevision)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stepCtx := &PromotionStepContext{
+				Freight: kargoapi.FreightCollection{},
+			}
+			stepCtx.Freight.UpdateOrPush(testCase.freight...)
+			err := resolveDesiredChartVersions(stepCtx, &testCase.cfg)
+			testCase.assertions(t, testCase.cfg, err)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package directives
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+const (
+	// syncReasonSynced is the reason of a Synced condition indicating that
+	// Applications were synced.
+	syncReasonSynced = "Synced"
+	// syncReasonHookFailed is the reason of a Synced condition indicating that
+	// a sync failed because a sync hook failed.
+	syncReasonHookFailed = "SyncHookFailed"
+	// syncReasonPermissionDenied is the reason of a Synced condition indicating
+	// that a sync failed because Argo CD was not permitted to apply resources,
+	// either by RBAC in the destination cluster or by the Application's
+	// AppProject.
+	syncReasonPermissionDenied = "SyncPermissionDenied"
+	// syncReasonPruneBlocked is the reason of a Synced condition indicating
+	// that a sync failed because resources that were to be pruned were not.
+	syncReasonPruneBlocked = "SyncPruneBlocked"
+	// syncReasonFailed is the reason of a Synced condition indicating that a
+	// sync failed for any other reason.
+	syncReasonFailed = "SyncFailed"
+)
+
+// maxSyncFailedResources is the maximum number of resources that failed to
+// sync that are listed in the message of a Synced condition.
+const maxSyncFailedResources = 5
+
+// maxSyncResourceMessageLength is the maximum length of the message of each
+// resource that failed to sync that is included in the message of a Synced
+// condition.
+const maxSyncResourceMessageLength = 200
+
+var (
+	// syncPermissionDeniedRegex matches messages of Argo CD explaining that it
+	// was not permitted to apply a resource.
+	syncPermissionDeniedRegex = regexp.MustCompile(
+		`(?i)forbidden|unauthorized|permission denied|is not permitted|` +
+			`cannot (get|list|create|update|patch|delete) resource`,
+	)
+	// syncPruneRegex matches messages of Argo CD explaining that resources
+	// were not pruned.
+	syncPruneRegex = regexp.MustCompile(`(?i)\bprun(e|ing)\b`)
+)
+
+// syncFailedError is an error indicating that a sync operation of an Argo CD
+// Application failed. It carries the Synced condition describing the failure.
+type syncFailedError struct {
+	condition *metav1.Condition
+}
+
+// Error implements the error interface.
+func (e *syncFailedError) Error() string {
+	return fmt.Sprintf("%s: %s", e.condition.Reason, e.condition.Message)
+}
+
+// newSyncFailedError returns an error describing the failed sync operation of
+// the provided Application. It is meant to be wrapped in a terminalError, since
+// Argo CD retries failed syncs itself if the Application is configured to do
+// so, and a sync that failed to apply the desired revisions fails again if it
+// is repeated.
+func newSyncFailedError(app *argocd.Application) *syncFailedError {
+	op := app.Status.OperationState
+	failed := syncFailedResources(op)
+	message := fmt.Sprintf(
+		"Argo CD Application %q in namespace %q failed to sync with: %s",
+		app.Name, app.Namespace, op.Message,
+	)
+	if len(failed) > 0 {
+		message += "; failed resources: " + formatSyncFailedResources(failed)
+	}
+	return &syncFailedError{
+		condition: &metav1.Condition{
+			Type:    kargoapi.ConditionTypeSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  classifySyncFailure(op, failed),
+			Message: message,
+		},
+	}
+}
+
+// newSyncedCondition returns a Synced condition indicating that the
+// Applications described by the provided messages were synced.
+func newSyncedCondition(messages []string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    kargoapi.ConditionTypeSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  syncReasonSynced,
+		Message: strings.Join(messages, "; "),
+	}
+}
+
+// syncedMessage returns a message describing the successful sync operation of
+// the provided Application.
+func syncedMessage(app *argocd.Application) string {
+	return fmt.Sprintf(
+		"Argo CD Application %q in namespace %q synced with: %s",
+		app.Name, app.Namespace, app.Status.OperationState.Message,
+	)
+}
+
+// syncFailedResources returns the resources of the provided sync operation
+// that failed to be synced or pruned, including sync hooks that failed.
+func syncFailedResources(op *argocd.OperationState) []*argocd.ResourceResult {
+	if op.SyncResult == nil {
+		return nil
+	}
+	var failed []*argocd.ResourceResult
+	for _, res := range op.SyncResult.Resources {
+		if res == nil {
+			continue
+		}
+		if res.Status == argocd.ResultCodeSyncFailed ||
+			res.Status == argocd.ResultCodePruneSkipped ||
+			res.HookPhase.Failed() {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// classifySyncFailure returns the reason of the Synced condition describing
+// the provided failed sync operation, of which the provided resources failed.
+// Permission failures take precedence, since they may also cause hooks to
+// fail or resources not to be pruned.
+func classifySyncFailure(
+	op *argocd.OperationState,
+	failed []*argocd.ResourceResult,
+) string {
+	if syncPermissionDeniedRegex.MatchString(op.Message) {
+		return syncReasonPermissionDenied
+	}
+	for _, res := range failed {
+		if syncPermissionDeniedRegex.MatchString(res.Message) {
+			return syncReasonPermissionDenied
+		}
+	}
+	for _, res := range failed {
+		if res.HookType != "" || res.HookPhase.Failed() {
+			return syncReasonHookFailed
+		}
+	}
+	for _, res := range failed {
+		if res.Status == argocd.ResultCodePruneSkipped {
+			return syncReasonPruneBlocked
+		}
+	}
+	if syncPruneRegex.MatchString(op.Message) {
+		return syncReasonPruneBlocked
+	}
+	return syncReasonFailed
+}
+
+// formatSyncFailedResources returns a summary of the provided resources that
+// failed to sync, which lists at most maxSyncFailedResources of them.
+func formatSyncFailedResources(failed []*argocd.ResourceResult) string {
+	summaries := make([]string, 0, min(len(failed), maxSyncFailedResources)+1)
+	for i, res := range failed {
+		if i == maxSyncFailedResources {
+			summaries = append(summaries, fmt.Sprintf("and %d more", len(failed)-i))
+			break
+		}
+		name := res.Name
+		if res.Namespace != "" {
+			name = res.Namespace + "/" + name
+		}
+		message := res.Message
+		if len(message) > maxSyncResourceMessageLength {
+			message = message[:maxSyncResourceMessageLength] + "..."
+		}
+		summaries = append(summaries, fmt.Sprintf("%s %q: %s", res.Kind, name, message))
+	}
+	return strings.Join(summaries, "; ")
+}
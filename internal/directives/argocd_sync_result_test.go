@@ -0,0 +1,139 @@
+package directives
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+)
+
+func Test_newSyncFailedError(t *testing.T) {
+	testCases := []struct {
+		name           string
+		op             *argocd.OperationState
+		expectedReason string
+	}{
+		{
+			name: "hook failure",
+			op: &argocd.OperationState{
+				Phase:   argocd.OperationFailed,
+				Message: "one or more synchronization tasks completed unsuccessfully",
+				SyncResult: &argocd.SyncOperationResult{
+					Resources: argocd.ResourceResults{{
+						Kind:      "Job",
+						Name:      "migrate",
+						HookType:  argocd.HookTypePreSync,
+						HookPhase: argocd.OperationFailed,
+						Message:   "Job has reached the specified backoff limit",
+					}},
+				},
+			},
+			expectedReason: "SyncHookFailed",
+		},
+		{
+			name: "permission denied in destination cluster",
+			op: &argocd.OperationState{
+				Phase:   argocd.OperationFailed,
+				Message: "one or more objects failed to apply",
+				SyncResult: &argocd.SyncOperationResult{
+					Resources: argocd.ResourceResults{{
+						Kind:   "Role",
+						Name:   "reader",
+						Status: argocd.ResultCodeSyncFailed,
+						Message: `roles.rbac.authorization.k8s.io "reader" is forbidden: ` +
+							`User "system:serviceaccount:argocd:argocd-application-controller" ` +
+							`cannot create resource "roles"`,
+					}},
+				},
+			},
+			expectedReason: "SyncPermissionDenied",
+		},
+		{
+			name: "permission denied by project",
+			op: &argocd.OperationState{
+				Phase:   argocd.OperationError,
+				Message: "resource :Namespace is not permitted in project default",
+			},
+			expectedReason: "SyncPermissionDenied",
+		},
+		{
+			name: "prune skipped",
+			op: &argocd.OperationState{
+				Phase:   argocd.OperationFailed,
+				Message: "one or more synchronization tasks completed unsuccessfully",
+				SyncResult: &argocd.SyncOperationResult{
+					Resources: argocd.ResourceResults{{
+						Kind:    "ConfigMap",
+						Name:    "old",
+						Status:  argocd.ResultCodePruneSkipped,
+						Message: "ignored (requires pruning)",
+					}},
+				},
+			},
+			expectedReason: "SyncPruneBlocked",
+		},
+		{
+			name: "prune confirmation",
+			op: &argocd.OperationState{
+				Phase:   argocd.OperationFailed,
+				Message: "waiting for pruning confirmation of v1/ConfigMap/old",
+			},
+			expectedReason: "SyncPruneBlocked",
+		},
+		{
+			name: "other failure",
+			op: &argocd.OperationState{
+				Phase:   argocd.OperationFailed,
+				Message: "one or more objects failed to apply",
+				SyncResult: &argocd.SyncOperationResult{
+					Resources: argocd.ResourceResults{{
+						Kind:    "Deployment",
+						Name:    "web",
+						Status:  argocd.ResultCodeSyncFailed,
+						Message: "spec.replicas: Invalid value: -1",
+					}},
+				},
+			},
+			expectedReason: "SyncFailed",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := newSyncFailedError(&argocd.Application{
+				Status: argocd.ApplicationStatus{OperationState: testCase.op},
+			})
+			require.Equal(t, metav1.ConditionFalse, err.condition.Status)
+			require.Equal(t, testCase.expectedReason, err.condition.Reason)
+			require.Contains(t, err.condition.Message, testCase.op.Message)
+			require.True(t, strings.HasPrefix(err.Error(), testCase.expectedReason+": "))
+		})
+	}
+}
+
+func Test_formatSyncFailedResources(t *testing.T) {
+	var failed []*argocd.ResourceResult
+	for i := range maxSyncFailedResources + 2 {
+		failed = append(failed, &argocd.ResourceResult{
+			Kind:      "ConfigMap",
+			Namespace: "app",
+			Name:      fmt.Sprintf("cm-%d", i),
+			Message:   strings.Repeat("x", maxSyncResourceMessageLength+1),
+		})
+	}
+	summary := formatSyncFailedResources(failed)
+	require.Equal(t, maxSyncFailedResources, strings.Count(summary, "ConfigMap"))
+	require.Contains(t, summary, `ConfigMap "app/cm-0": `+strings.Repeat("x", maxSyncResourceMessageLength)+"...;")
+	require.True(t, strings.HasSuffix(summary, "; and 2 more"))
+
+	require.Equal(
+		t,
+		`Namespace "app": forbidden`,
+		formatSyncFailedResources([]*argocd.ResourceResult{
+			{Kind: "Namespace", Name: "app", Message: "forbidden"},
+		}),
+	)
+}
@@ -0,0 +1,108 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+// syncSkippedNoChanges prefixes the message of the result of an argocd-update
+// step that did not sync one or more Argo CD Applications because the
+// manifests they would have been synced to were unchanged.
+const syncSkippedNoChanges = "SyncSkippedNoChanges"
+
+// isSyncUnnecessary returns true if syncing the provided Argo CD Application to
+// the provided desired sources and revisions would not change what is
+// deployed, so that the sync, and in particular its hooks, can be skipped.
+// This is the case if the Application is synced, its sources change in no way
+// other than their target revisions, and every revision it would be synced to
+// is a commit whose tree is identical to that of the commit it is currently
+// synced to. A sync is never deemed unnecessary if the Promotion forces syncs.
+func (a *argocdUpdater) isSyncUnnecessary(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	app *argocd.Application,
+	desiredRevisions []string,
+	desiredSources argocd.ApplicationSources,
+) (bool, error) {
+	if stepCtx.ForceSync || app.Status.Sync.Status != argocd.SyncStatusCodeSynced {
+		return false, nil
+	}
+	currentSources := app.Spec.Sources
+	if app.Spec.Source != nil {
+		currentSources = argocd.ApplicationSources{*app.Spec.Source}
+	}
+	syncedRevisions := syncStatusRevisions(app.Status.Sync)
+	if len(currentSources) == 0 || len(desiredSources) != len(currentSources) ||
+		len(syncedRevisions) != len(currentSources) {
+		return false, nil
+	}
+
+	// Commits whose trees are to be compared, keyed by repository URL, and the
+	// pairs of synced and desired commits whose trees must be identical.
+	commitsByRepo := map[string][]string{}
+	type commitPair struct{ repoURL, synced, desired string }
+	var pairs []commitPair
+	for i := range desiredSources {
+		desired := desiredSources[i].DeepCopy()
+		current := currentSources[i].DeepCopy()
+		desired.TargetRevision, current.TargetRevision = "", ""
+		if !desired.Equals(current) {
+			// For instance, images or parameters are changed.
+			return false, nil
+		}
+		var desiredRevision string
+		if i < len(desiredRevisions) {
+			desiredRevision = desiredRevisions[i]
+		}
+		switch {
+		case desiredRevision == "":
+			// Without a desired revision, the source can only be known to be
+			// unchanged if it already targets exactly the synced revision.
+			if desiredSources[i].TargetRevision != syncedRevisions[i] {
+				return false, nil
+			}
+		case desiredRevision == syncedRevisions[i]:
+		case desiredSources[i].Chart != "":
+			// A different version of a chart.
+			return false, nil
+		default:
+			repoURL := desiredSources[i].RepoURL
+			for _, commit := range []string{syncedRevisions[i], desiredRevision} {
+				if !slices.Contains(commitsByRepo[repoURL], commit) {
+					commitsByRepo[repoURL] = append(commitsByRepo[repoURL], commit)
+				}
+			}
+			pairs = append(pairs, commitPair{
+				repoURL: repoURL,
+				synced:  syncedRevisions[i],
+				desired: desiredRevision,
+			})
+		}
+	}
+
+	treesByRepo := make(map[string]map[string]string, len(commitsByRepo))
+	for repoURL, commits := range commitsByRepo {
+		creds, err := getGitCredentials(ctx, stepCtx, repoURL)
+		if err != nil {
+			return false, err
+		}
+		if treesByRepo[repoURL], err = a.getCommitTreesFn(
+			repoURL,
+			commits,
+			&git.ClientOptions{Credentials: creds},
+		); err != nil {
+			return false, fmt.Errorf("error getting trees of commits of %s: %w", repoURL, err)
+		}
+	}
+	for _, pair := range pairs {
+		trees := treesByRepo[pair.repoURL]
+		if trees[pair.synced] == "" || trees[pair.synced] != trees[pair.desired] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,203 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func Test_argocdUpdater_isSyncUnnecessary(t *testing.T) {
+	const testRepoURL = "https://github.com/universe/42"
+	testApp := func() *argocd.Application {
+		return &argocd.Application{
+			Spec: argocd.ApplicationSpec{
+				Source: &argocd.ApplicationSource{
+					RepoURL:        testRepoURL,
+					TargetRevision: "old-commit",
+				},
+			},
+			Status: argocd.ApplicationStatus{
+				Sync: argocd.SyncStatus{
+					Status:   argocd.SyncStatusCodeSynced,
+					Revision: "old-commit",
+				},
+			},
+		}
+	}
+	testCases := []struct {
+		name             string
+		forceSync        bool
+		app              *argocd.Application
+		desiredRevisions []string
+		desiredSources   argocd.ApplicationSources
+		trees            map[string]string
+		treesErr         error
+		assertions       func(*testing.T, bool, error)
+	}{
+		{
+			name:             "trees are identical",
+			app:              testApp(),
+			desiredRevisions: []string{"new-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "new-commit",
+			}},
+			trees: map[string]string{"old-commit": "tree", "new-commit": "tree"},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.True(t, unnecessary)
+			},
+		},
+		{
+			name:             "trees differ",
+			app:              testApp(),
+			desiredRevisions: []string{"new-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "new-commit",
+			}},
+			trees: map[string]string{"old-commit": "tree", "new-commit": "other-tree"},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.False(t, unnecessary)
+			},
+		},
+		{
+			name:             "error getting trees",
+			app:              testApp(),
+			desiredRevisions: []string{"new-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "new-commit",
+			}},
+			treesErr: errors.New("something went wrong"),
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.False(t, unnecessary)
+			},
+		},
+		{
+			name:             "sync is forced",
+			forceSync:        true,
+			app:              testApp(),
+			desiredRevisions: []string{"new-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "new-commit",
+			}},
+			trees: map[string]string{"old-commit": "tree", "new-commit": "tree"},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.False(t, unnecessary)
+			},
+		},
+		{
+			name: "Application is out of sync",
+			app: func() *argocd.Application {
+				app := testApp()
+				app.Status.Sync.Status = argocd.SyncStatusCodeOutOfSync
+				return app
+			}(),
+			desiredRevisions: []string{"new-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "new-commit",
+			}},
+			trees: map[string]string{"old-commit": "tree", "new-commit": "tree"},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.False(t, unnecessary)
+			},
+		},
+		{
+			name:             "kustomize images change",
+			app:              testApp(),
+			desiredRevisions: []string{"new-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "new-commit",
+				Kustomize: &argocd.ApplicationSourceKustomize{
+					Images: argocd.KustomizeImages{"fake-image:v2.0.0"},
+				},
+			}},
+			trees: map[string]string{"old-commit": "tree", "new-commit": "tree"},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.False(t, unnecessary)
+			},
+		},
+		{
+			name: "chart version changes",
+			app: &argocd.Application{
+				Spec: argocd.ApplicationSpec{
+					Source: &argocd.ApplicationSource{
+						RepoURL:        "https://charts.example.com",
+						Chart:          "fake-chart",
+						TargetRevision: "1.0.0",
+					},
+				},
+				Status: argocd.ApplicationStatus{
+					Sync: argocd.SyncStatus{
+						Status:   argocd.SyncStatusCodeSynced,
+						Revision: "1.0.0",
+					},
+				},
+			},
+			desiredRevisions: []string{"1.1.0"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        "https://charts.example.com",
+				Chart:          "fake-chart",
+				TargetRevision: "1.1.0",
+			}},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.False(t, unnecessary)
+			},
+		},
+		{
+			name:             "revision is unchanged",
+			app:              testApp(),
+			desiredRevisions: []string{"old-commit"},
+			desiredSources: argocd.ApplicationSources{{
+				RepoURL:        testRepoURL,
+				TargetRevision: "old-commit",
+			}},
+			assertions: func(t *testing.T, unnecessary bool, err error) {
+				require.NoError(t, err)
+				require.True(t, unnecessary)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			runner := &argocdUpdater{
+				getCommitTreesFn: func(
+					repoURL string,
+					commits []string,
+					_ *git.ClientOptions,
+				) (map[string]string, error) {
+					require.Equal(t, testRepoURL, repoURL)
+					require.ElementsMatch(t, []string{"old-commit", "new-commit"}, commits)
+					return testCase.trees, testCase.treesErr
+				},
+			}
+			unnecessary, err := runner.isSyncUnnecessary(
+				context.Background(),
+				&PromotionStepContext{
+					CredentialsDB: &credentials.FakeDB{},
+					ForceSync:     testCase.forceSync,
+				},
+				testCase.app,
+				testCase.desiredRevisions,
+				testCase.desiredSources,
+			)
+			testCase.assertions(t, unnecessary, err)
+		})
+	}
+}
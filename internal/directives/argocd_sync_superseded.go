@@ -0,0 +1,192 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// syncSupersededByNewerRevision prefixes the message of the result of an
+// argocd-update step that considers one or more Argo CD Applications synced
+// because they were synced to revisions that descend from the desired ones,
+// e.g. because others pushed to the branch the Applications track in the
+// meantime.
+const syncSupersededByNewerRevision = "SupersededByNewerRevision"
+
+// syncReasonRevisionDiverged is the reason of a Synced condition indicating
+// that an Application was synced to a revision that neither is nor descends
+// from the desired one, so that it will never be synced to the desired one.
+const syncReasonRevisionDiverged = "SyncedRevisionDiverged"
+
+// syncRevisionsMismatchError is returned by mustPerformUpdate when the
+// revisions that an Application was synced to by the operation initiated for
+// the current Promotion do not match the desired revisions.
+type syncRevisionsMismatchError struct {
+	observed []string
+	desired  []string
+}
+
+// Error implements the error interface.
+func (e *syncRevisionsMismatchError) Error() string {
+	return fmt.Sprintf(
+		"sync result revisions %v do not match desired revisions %v",
+		e.observed, e.desired,
+	)
+}
+
+// supersededSync describes an Argo CD Application that was synced to revisions
+// descending from the desired ones.
+type supersededSync struct {
+	// revisions are the revisions the Application was synced to, one per
+	// source, in place of the desired ones.
+	revisions []string
+	// messages name, for every source synced to a revision that descends from
+	// its desired revision, both revisions.
+	messages []string
+}
+
+// syncedRevisionsState describes how the revisions an Argo CD Application is
+// synced to relate to the desired revisions.
+type syncedRevisionsState int
+
+const (
+	// syncedRevisionsBehind indicates that the Application is not (yet) synced
+	// to the desired revisions, or that how its revisions relate to the desired
+	// ones could not be determined.
+	syncedRevisionsBehind syncedRevisionsState = iota
+	// syncedRevisionsSuperseded indicates that every source of the Application
+	// is synced either to its desired revision or to a revision descending
+	// from it.
+	syncedRevisionsSuperseded
+	// syncedRevisionsDiverged indicates that at least one source of the
+	// Application is synced to a revision that neither is, descends from, nor
+	// precedes its desired revision.
+	syncedRevisionsDiverged
+)
+
+// compareSyncedRevisions determines how the provided observed revisions, which
+// the provided Argo CD Application is synced to, relate to the provided
+// desired revisions, which they are known not to match. Only sources of Git
+// repositories whose desired revisions are commit IDs can be synced to newer
+// revisions; for any other source, a mismatch means the Application is behind.
+// If the Application is superseded, a supersededSync describing it is
+// returned as well.
+//
+// The ancestry of commits is determined against the remote repository rather
+// than any clone in the workspace, which may be shallow. Failing to determine
+// it is not fatal; the Application is then considered to be behind, so that
+// its sync continues to be awaited.
+func (a *argocdUpdater) compareSyncedRevisions(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	app *argocd.Application,
+	observed []string,
+	desired []string,
+) (syncedRevisionsState, *supersededSync) {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"app", app.Name,
+		"namespace", app.Namespace,
+	)
+	sources := app.Spec.Sources
+	if app.Spec.Source != nil {
+		sources = argocd.ApplicationSources{*app.Spec.Source}
+	}
+	if len(sources) != len(desired) || len(observed) != len(desired) {
+		return syncedRevisionsBehind, nil
+	}
+
+	superseded := &supersededSync{revisions: slices.Clone(desired)}
+	for i, desiredRevision := range desired {
+		if desiredRevision == "" || observed[i] == desiredRevision {
+			continue
+		}
+		if sources[i].Chart != "" || !commitIDRegex.MatchString(desiredRevision) ||
+			!commitIDRegex.MatchString(observed[i]) {
+			return syncedRevisionsBehind, nil
+		}
+		repoURL := sources[i].RepoURL
+		newer, older, err := a.commitAncestry(ctx, stepCtx, repoURL, desiredRevision, observed[i])
+		if err != nil {
+			logger.Info(
+				"unable to determine whether Argo CD Application was synced to a newer revision",
+				"repoURL", repoURL,
+				"error", err.Error(),
+			)
+			return syncedRevisionsBehind, nil
+		}
+		if older {
+			return syncedRevisionsBehind, nil
+		}
+		if !newer {
+			return syncedRevisionsDiverged, nil
+		}
+		superseded.revisions[i] = observed[i]
+		superseded.messages = append(superseded.messages, fmt.Sprintf(
+			"Argo CD Application %q in namespace %q was synced to revision %q of %s, "+
+				"which descends from the desired revision %q",
+			app.Name, app.Namespace, observed[i], repoURL, desiredRevision,
+		))
+	}
+	if len(superseded.messages) == 0 {
+		// Not a single source is synced to a revision other than the desired
+		// one.
+		return syncedRevisionsBehind, nil
+	}
+	return syncedRevisionsSuperseded, superseded
+}
+
+// commitAncestry returns whether the provided observed commit of the Git
+// repository with the provided URL descends from the provided desired commit
+// and, if it does not, whether it precedes it.
+func (a *argocdUpdater) commitAncestry(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	repoURL string,
+	desired string,
+	observed string,
+) (newer bool, older bool, err error) {
+	creds, err := getGitCredentials(ctx, stepCtx, repoURL)
+	if err != nil {
+		return false, false, err
+	}
+	clientOpts := &git.ClientOptions{Credentials: creds}
+	if newer, err = a.isAncestorFn(repoURL, desired, observed, clientOpts); err != nil || newer {
+		return newer, false, err
+	}
+	older, err = a.isAncestorFn(repoURL, observed, desired, clientOpts)
+	return false, older, err
+}
+
+// newSyncRevisionDivergedError returns an error describing that the provided
+// Application was synced to the provided observed revisions, of which at
+// least one neither is nor descends from its counterpart among the provided
+// desired revisions. It is meant to be wrapped in a terminalError, since the
+// Application will not be synced to the desired revisions unless the history
+// of its repositories is rewritten again.
+func newSyncRevisionDivergedError(
+	app *argocd.Application,
+	observed []string,
+	desired []string,
+) *syncFailedError {
+	return &syncFailedError{
+		condition: &metav1.Condition{
+			Type:   kargoapi.ConditionTypeSynced,
+			Status: metav1.ConditionFalse,
+			Reason: syncReasonRevisionDiverged,
+			Message: fmt.Sprintf(
+				"Argo CD Application %q in namespace %q was synced to revisions %s, "+
+					"which neither match nor descend from the desired revisions %s",
+				app.Name, app.Namespace,
+				strings.Join(observed, ", "), strings.Join(desired, ", "),
+			),
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func Test_argocdUpdater_compareSyncedRevisions(t *testing.T) {
+	const (
+		testRepoURL   = "https://github.com/universe/42"
+		olderCommit   = "0000000000000000000000000000000000000000"
+		desiredCommit = "1111111111111111111111111111111111111111"
+		newerCommit   = "2222222222222222222222222222222222222222"
+		otherCommit   = "3333333333333333333333333333333333333333"
+	)
+	// olderCommit <- desiredCommit <- newerCommit, while otherCommit is on a
+	// history of its own.
+	history := []string{olderCommit, desiredCommit, newerCommit}
+	isAncestor := func(ancestor, descendant string) bool {
+		i := slices.Index(history, ancestor)
+		return i >= 0 && slices.Index(history, descendant) >= i
+	}
+	testApp := &argocd.Application{
+		Spec: argocd.ApplicationSpec{
+			Sources: argocd.ApplicationSources{
+				{RepoURL: testRepoURL, TargetRevision: "main"},
+				{RepoURL: "https://charts.example.com", Chart: "fake-chart"},
+			},
+		},
+	}
+	testCases := []struct {
+		name          string
+		observed      []string
+		desired       []string
+		ancestorErr   error
+		expectedState syncedRevisionsState
+		assertions    func(*testing.T, *supersededSync)
+	}{
+		{
+			name:          "synced to newer revision",
+			observed:      []string{newerCommit, "1.0.0"},
+			desired:       []string{desiredCommit, ""},
+			expectedState: syncedRevisionsSuperseded,
+			assertions: func(t *testing.T, superseded *supersededSync) {
+				require.Equal(t, []string{newerCommit, ""}, superseded.revisions)
+				require.Len(t, superseded.messages, 1)
+				require.Contains(t, superseded.messages[0], newerCommit)
+				require.Contains(t, superseded.messages[0], desiredCommit)
+			},
+		},
+		{
+			name:          "synced to older revision",
+			observed:      []string{olderCommit, "1.0.0"},
+			desired:       []string{desiredCommit, ""},
+			expectedState: syncedRevisionsBehind,
+		},
+		{
+			name:          "synced to diverged revision",
+			observed:      []string{otherCommit, "1.0.0"},
+			desired:       []string{desiredCommit, ""},
+			expectedState: syncedRevisionsDiverged,
+		},
+		{
+			name:          "synced to desired revisions",
+			observed:      []string{desiredCommit, "1.0.0"},
+			desired:       []string{desiredCommit, "1.0.0"},
+			expectedState: syncedRevisionsBehind,
+		},
+		{
+			name:          "desired revision is not a commit",
+			observed:      []string{newerCommit, "1.0.0"},
+			desired:       []string{"v1.0.0", ""},
+			expectedState: syncedRevisionsBehind,
+		},
+		{
+			name:          "chart synced to other version",
+			observed:      []string{desiredCommit, "2.0.0"},
+			desired:       []string{desiredCommit, "1.0.0"},
+			expectedState: syncedRevisionsBehind,
+		},
+		{
+			name:          "ancestry cannot be determined",
+			observed:      []string{newerCommit, "1.0.0"},
+			desired:       []string{desiredCommit, ""},
+			ancestorErr:   errors.New("something went wrong"),
+			expectedState: syncedRevisionsBehind,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			runner := &argocdUpdater{
+				isAncestorFn: func(
+					repoURL string,
+					ancestor string,
+					descendant string,
+					_ *git.ClientOptions,
+				) (bool, error) {
+					require.Equal(t, testRepoURL, repoURL)
+					return isAncestor(ancestor, descendant), testCase.ancestorErr
+				},
+			}
+			state, superseded := runner.compareSyncedRevisions(
+				context.Background(),
+				&PromotionStepContext{CredentialsDB: &credentials.FakeDB{}},
+				testApp,
+				testCase.observed,
+				testCase.desired,
+			)
+			require.Equal(t, testCase.expectedState, state)
+			if testCase.assertions != nil {
+				testCase.assertions(t, superseded)
+				return
+			}
+			require.Nil(t, superseded)
+		})
+	}
+}
@@ -2,13 +2,16 @@ package directives
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/xeipuuv/gojsonschema"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/ptr"
@@ -18,7 +21,8 @@ import (
 	libargocd "github.com/akuity/kargo/internal/argocd"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/freight"
-	"github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/controller/git"
+	libgit "github.com/akuity/kargo/internal/git"
 	"github.com/akuity/kargo/internal/kubeclient"
 	"github.com/akuity/kargo/internal/logging"
 )
@@ -28,18 +32,26 @@ const (
 	promotionInfoKey              = "kargo.akuity.io/promotion"
 )
 
+// errArgoCDAppNotFound is returned when an Argo CD Application to be updated
+// does not exist.
+var errArgoCDAppNotFound = errors.New("Application does not exist")
+
 func init() {
 	runner := newArgocdUpdater()
 	builtins.RegisterPromotionStepRunner(
 		runner,
 		&StepRunnerPermissions{
-			AllowKargoClient:  true,
-			AllowArgoCDClient: true,
+			AllowCredentialsDB: true,
+			AllowKargoClient:   true,
+			AllowArgoCDClient:  true,
 		},
 	)
 	builtins.RegisterHealthCheckStepRunner(
 		runner,
-		&StepRunnerPermissions{AllowArgoCDClient: true},
+		&StepRunnerPermissions{
+			AllowKargoClient:  true,
+			AllowArgoCDClient: true,
+		},
 	)
 }
 
@@ -78,6 +90,34 @@ type argocdUpdater struct {
 		desiredSources argocd.ApplicationSources,
 	) error
 
+	refreshApplicationFn func(
+		ctx context.Context,
+		stepCtx *PromotionStepContext,
+		app *argocd.Application,
+		desiredSources argocd.ApplicationSources,
+		refreshType argocd.RefreshType,
+	) error
+
+	getCommitTreesFn func(
+		repoURL string,
+		commits []string,
+		clientOpts *git.ClientOptions,
+	) (map[string]string, error)
+
+	isAncestorFn func(
+		repoURL string,
+		ancestor string,
+		descendant string,
+		clientOpts *git.ClientOptions,
+	) (bool, error)
+
+	getSyncWindowsFn func(
+		ctx context.Context,
+		argoCDClient client.Client,
+		argocdNamespace string,
+		app *argocd.Application,
+	) (argocd.SyncWindows, error)
+
 	applyArgoCDSourceUpdateFn func(
 		ctx context.Context,
 		stepCtx *PromotionStepContext,
@@ -114,6 +154,10 @@ func newArgocdUpdater() *argocdUpdater {
 	r.buildDesiredSourcesFn = r.buildDesiredSources
 	r.mustPerformUpdateFn = r.mustPerformUpdate
 	r.syncApplicationFn = r.syncApplication
+	r.refreshApplicationFn = r.refreshApplication
+	r.getCommitTreesFn = git.RemoteCommitTrees
+	r.isAncestorFn = git.RemoteIsAncestor
+	r.getSyncWindowsFn = r.getSyncWindows
 	r.applyArgoCDSourceUpdateFn = r.applyArgoCDSourceUpdate
 	r.argoCDAppPatchFn = r.argoCDAppPatch
 	r.logAppEventFn = r.logAppEvent
@@ -135,6 +179,23 @@ func (a *argocdUpdater) DefaultErrorThreshold() uint32 {
 	return 0 // Will fall back to the system default.
 }
 
+// DefaultTimeoutForConfig implements the ConfigTimeoutStepRunner interface.
+// Applications that are not synced by the step itself may take considerably
+// longer to converge, as Argo CD only notices new revisions when it next
+// refreshes them on its own.
+func (a *argocdUpdater) DefaultTimeoutForConfig(rawCfg []byte) *time.Duration {
+	var cfg ArgoCDUpdateConfig
+	if err := json.Unmarshal(rawCfg, &cfg); err != nil {
+		return nil
+	}
+	for i := range cfg.Apps {
+		if syncTriggerFor(&cfg, &cfg.Apps[i]) == None {
+			return ptr.To(15 * time.Minute)
+		}
+	}
+	return nil
+}
+
 // RunPromotionStep implements the PromotionStepRunner interface.
 func (a *argocdUpdater) RunPromotionStep(
 	ctx context.Context,
@@ -172,19 +233,94 @@ func (a *argocdUpdater) runPromotionStep(
 	logger := logging.LoggerFromContext(ctx)
 	logger.Debug("executing argocd-update promotion step")
 
+	if err := resolveDesiredChartVersions(stepCtx, &stepCfg); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error determining desired chart versions: %w", err,
+		)
+	}
+
 	var updateResults = make([]argocd.OperationPhase, 0, len(stepCfg.Apps))
 	appHealthChecks := make([]ArgoCDAppHealthCheck, len(stepCfg.Apps))
+	// Messages explaining why syncs of Applications are waiting for sync
+	// windows, and the earliest time at which any of them may proceed.
+	var syncWindowWaits []string
+	var waitUntil *time.Time
+	// Messages explaining why syncs of Applications were skipped.
+	var syncSkips []string
+	// Messages describing the completed syncs of Applications.
+	var syncedMessages []string
+	// Messages explaining which Applications were synced to revisions that
+	// descend from the desired ones.
+	var supersededSyncs []string
+	// Applications that this step has patched, in this or any previous
+	// execution, and whose syncs are awaited. Such an Application vanishing
+	// means the sync will never complete.
+	patchedApps := patchedArgoCDApps(stepCtx)
+	recordPatched := func(appKey client.ObjectKey) {
+		if !slices.Contains(patchedApps, appKey.String()) {
+			patchedApps = append(patchedApps, appKey.String())
+		}
+	}
+	output := func() map[string]any {
+		if len(patchedApps) == 0 {
+			return nil
+		}
+		return map[string]any{stateKeyPatchedApps: slices.Clone(patchedApps)}
+	}
 	for i := range stepCfg.Apps {
 		update := &stepCfg.Apps[i]
+		if err := validateAppHealthConfig(update.Health); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, &terminalError{
+				err: fmt.Errorf(
+					"invalid health configuration for Argo CD Application %q: %w",
+					update.Name, err,
+				),
+			}
+		}
 		// Retrieve the Argo CD Application.
 		appKey := client.ObjectKey{
 			Namespace: update.Namespace,
 			Name:      update.Name,
 		}
 		if appKey.Namespace == "" {
-			appKey.Namespace = libargocd.Namespace()
+			appKey.Namespace = argoCDNamespace(stepCtx.ArgoCDNamespace)
 		}
 		app, err := a.getAuthorizedApplicationFn(ctx, stepCtx, appKey)
+		if slices.Contains(patchedApps, appKey.String()) &&
+			(errors.Is(err, errArgoCDAppNotFound) || (err == nil && app.DeletionTimestamp != nil)) {
+			// The Application was deleted after it was patched, while its sync
+			// was being awaited.
+			logger.Info(
+				"Argo CD Application was deleted while waiting for it to be synced",
+				"app", appKey.Name,
+				"namespace", appKey.Namespace,
+			)
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+				newArgoCDAppDeletedError(appKey)
+		}
+		if errors.Is(err, errArgoCDAppNotFound) {
+			// The Application may not exist YET, for instance, when it is being
+			// created alongside the Stage. Wait for it to appear until the step
+			// times out. The Promotion is requeued as soon as the Application is
+			// created.
+			logger.Info(
+				"waiting for Argo CD Application to be created",
+				"app", appKey.Name,
+				"namespace", appKey.Namespace,
+			)
+			return PromotionStepResult{
+				Status: kargoapi.PromotionPhaseRunning,
+				Message: fmt.Sprintf(
+					"Waiting: ReferentNotFound: Argo CD Application %q in namespace %q",
+					appKey.Name, appKey.Namespace,
+				),
+				Output: output(),
+			}, nil
+		}
+		if apierrors.IsForbidden(err) {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+				newArgoCDForbiddenError("get", appKey, err)
+		}
 		if err != nil {
 			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
 				"error getting Argo CD Application %q in namespace %q: %w",
@@ -200,15 +336,104 @@ func (a *argocdUpdater) runPromotionStep(
 				app.Name, app.Namespace, err,
 			)
 		}
+		trigger := syncTriggerFor(&stepCfg, update)
+		syncWindowPolicy := syncWindowPolicyFor(&stepCfg, update)
+		if trigger != Operation && syncWindowPolicy == AllowManual {
+			// Applications that are not synced by means of an operation are
+			// synced by Argo CD's automated sync policy, which sync windows never
+			// permit to sync in the way they may permit manual syncs.
+			syncWindowPolicy = Wait
+		}
 		appHealthChecks[i] = ArgoCDAppHealthCheck{
 			Name:             app.Name,
 			Namespace:        app.Namespace,
 			DesiredRevisions: desiredRevisions,
+			Health:           update.Health,
+			SyncWindowPolicy: syncWindowPolicy,
+		}
+
+		// Applications that are not to be synced by means of an operation are
+		// only observed until they converge on the desired revisions.
+		if trigger != Operation {
+			phase, superseded, err := a.awaitSync(
+				ctx, stepCtx, &stepCfg, update, trigger, desiredRevisions, app,
+			)
+			if apierrors.IsForbidden(err) {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					newArgoCDForbiddenError("update", appKey, err)
+			}
+			if err != nil && a.wasAppDeleted(ctx, stepCtx, appKey, err) {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+					newArgoCDAppDeletedError(appKey)
+			}
+			var syncErr *syncFailedError
+			if errors.As(err, &syncErr) {
+				return PromotionStepResult{
+					Status:        kargoapi.PromotionPhaseFailed,
+					SyncCondition: syncErr.condition,
+				}, err
+			}
+			if err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+			}
+			if phase == argocd.OperationRunning {
+				recordPatched(appKey)
+				// Surface why the Application may not be synced yet.
+				wait, err := a.awaitSyncWindow(
+					ctx,
+					stepCtx.ArgoCDClient,
+					argoCDNamespace(stepCtx.ArgoCDNamespace),
+					syncWindowPolicy,
+					app,
+				)
+				if err != nil {
+					return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+				}
+				if wait != nil {
+					syncWindowWaits = append(syncWindowWaits, wait.message)
+					waitUntil = earliestTime(waitUntil, wait.until)
+				}
+			}
+			if superseded != nil {
+				supersededSyncs = append(supersededSyncs, superseded.messages...)
+				appHealthChecks[i].DesiredRevisions = superseded.revisions
+			}
+			updateResults = append(updateResults, phase)
+			continue
 		}
 
 		// Check if the update needs to be performed and retrieve its phase.
 		phase, mustUpdate, err := a.mustPerformUpdateFn(stepCtx, update, app)
 
+		// The operation initiated for this Promotion may have synced the
+		// Application to a revision that descends from the desired one, e.g.
+		// because the Application tracks a branch that others pushed to in the
+		// meantime. This is as good as syncing to the desired revision, while
+		// syncing to a revision that does not descend from it at all means the
+		// desired revision will never be synced to.
+		var mismatchErr *syncRevisionsMismatchError
+		if mustUpdate && errors.As(err, &mismatchErr) {
+			state, superseded := a.compareSyncedRevisions(
+				ctx, stepCtx, app, mismatchErr.observed, mismatchErr.desired,
+			)
+			switch state {
+			case syncedRevisionsSuperseded:
+				supersededSyncs = append(supersededSyncs, superseded.messages...)
+				appHealthChecks[i].DesiredRevisions = superseded.revisions
+				syncedMessages = append(syncedMessages, syncedMessage(app))
+				updateResults = append(updateResults, argocd.OperationSucceeded)
+				continue
+			case syncedRevisionsDiverged:
+				syncErr := newSyncRevisionDivergedError(
+					app, mismatchErr.observed, mismatchErr.desired,
+				)
+				return PromotionStepResult{
+					Status:        kargoapi.PromotionPhaseFailed,
+					SyncCondition: syncErr.condition,
+				}, &terminalError{err: syncErr}
+			}
+		}
+
 		// If we have a phase, append it to the results.
 		if phase != "" {
 			updateResults = append(updateResults, phase)
@@ -227,19 +452,23 @@ func (a *argocdUpdater) runPromotionStep(
 				logger.Info(err.Error())
 			}
 			if phase.Failed() {
-				// Record the reason for the failure if available.
+				// Record the reason for the failure if available. The failure
+				// is terminal, so that the step does not wait out its timeout
+				// for a sync that will not succeed.
 				if app.Status.OperationState != nil {
-					return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
-						"Argo CD Application %q in namespace %q failed with: %s",
-						app.Name,
-						app.Namespace,
-						app.Status.OperationState.Message,
-					)
+					syncErr := newSyncFailedError(app)
+					return PromotionStepResult{
+						Status:        kargoapi.PromotionPhaseFailed,
+						SyncCondition: syncErr.condition,
+					}, &terminalError{err: syncErr}
 				}
 				// If the update failed, we can short-circuit. This is
 				// effectively "fail fast" behavior.
 				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, nil
 			}
+			if phase == argocd.OperationSucceeded && app.Status.OperationState != nil {
+				syncedMessages = append(syncedMessages, syncedMessage(app))
+			}
 			// If we get here, we can continue to the next update.
 			continue
 		}
@@ -266,18 +495,93 @@ func (a *argocdUpdater) runPromotionStep(
 			)
 		}
 
+		// A sync that would not change what is deployed is skipped, so that it
+		// does not needlessly run sync hooks. The sources are still updated, so
+		// that they reflect the desired revisions. Failing to determine whether
+		// a sync is necessary is not fatal; the Application is synced instead.
+		unnecessary, err := a.isSyncUnnecessary(ctx, stepCtx, app, desiredRevisions, desiredSources)
+		if err != nil {
+			logger.Info(
+				"unable to determine whether sync of Argo CD Application is necessary; syncing",
+				"app", app.Name,
+				"namespace", app.Namespace,
+				"error", err.Error(),
+			)
+		}
+		if unnecessary {
+			if err = a.refreshApplicationFn(
+				ctx, stepCtx, app, desiredSources, argocd.RefreshTypeNormal,
+			); apierrors.IsForbidden(err) {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					newArgoCDForbiddenError("update", appKey, err)
+			} else if err != nil && a.wasAppDeleted(ctx, stepCtx, appKey, err) {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+					newArgoCDAppDeletedError(appKey)
+			} else if err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+					"error updating Argo CD Application %q in namespace %q: %w",
+					app.Name, app.Namespace, err,
+				)
+			}
+			logger.Info(
+				"skipped sync of Argo CD Application because its manifests are unchanged",
+				"app", app.Name,
+				"namespace", app.Namespace,
+			)
+			syncSkips = append(syncSkips, fmt.Sprintf(
+				"Argo CD Application %q in namespace %q was not synced because its "+
+					"manifests are unchanged",
+				app.Name, app.Namespace,
+			))
+			updateResults = append(updateResults, argocd.OperationSucceeded)
+			continue
+		}
+
+		// Only the sync itself is subject to sync windows. Any work preceding
+		// it, e.g. committing and pushing changes, has already been done.
+		wait, err := a.awaitSyncWindow(
+			ctx,
+			stepCtx.ArgoCDClient,
+			argoCDNamespace(stepCtx.ArgoCDNamespace),
+			syncWindowPolicy,
+			app,
+		)
+		if err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		if wait != nil {
+			logger.Info(
+				"waiting for sync windows to permit a sync of Argo CD Application",
+				"app", app.Name,
+				"namespace", app.Namespace,
+				"until", wait.until,
+			)
+			syncWindowWaits = append(syncWindowWaits, wait.message)
+			waitUntil = earliestTime(waitUntil, wait.until)
+			updateResults = append(updateResults, argocd.OperationRunning)
+			continue
+		}
+
 		// Perform the update.
 		if err = a.syncApplicationFn(
 			ctx,
 			stepCtx,
 			app,
 			desiredSources,
-		); err != nil {
+		); apierrors.IsForbidden(err) {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+				newArgoCDForbiddenError("update", appKey, err)
+		} else if err != nil && a.wasAppDeleted(ctx, stepCtx, appKey, err) {
+			// The Application was deleted in between retrieving and patching it.
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+				newArgoCDAppDeletedError(appKey)
+		} else if err != nil {
 			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
 				"error syncing Argo CD Application %q in namespace %q: %w",
 				app.Name, app.Namespace, err,
 			)
 		}
+		recordPatched(appKey)
 		// As we have initiated an update, we should wait for it to complete.
 		updateResults = append(updateResults, argocd.OperationRunning)
 	}
@@ -292,15 +596,47 @@ func (a *argocdUpdater) runPromotionStep(
 
 	logger.Debug("done executing argocd-update promotion step")
 
-	return PromotionStepResult{
+	res := PromotionStepResult{
 		Status: aggregatedStatus,
+		Output: output(),
 		HealthCheckStep: &HealthCheckStep{
 			Kind: a.Name(),
 			Config: Config{
 				"apps": appHealthChecks,
 			},
 		},
-	}, nil
+	}
+	var messages []string
+	if aggregatedStatus == kargoapi.PromotionPhaseRunning && len(syncWindowWaits) > 0 {
+		messages = append(messages, "Waiting: SyncWindow: "+strings.Join(syncWindowWaits, "; "))
+		res.WaitUntil = waitUntil
+	}
+	if len(syncSkips) > 0 {
+		messages = append(messages, syncSkippedNoChanges+": "+strings.Join(syncSkips, "; "))
+	}
+	if len(supersededSyncs) > 0 {
+		messages = append(
+			messages,
+			syncSupersededByNewerRevision+": "+strings.Join(supersededSyncs, "; "),
+		)
+	}
+	res.Message = strings.Join(messages, "; ")
+	if aggregatedStatus == kargoapi.PromotionPhaseSucceeded && len(syncedMessages) > 0 {
+		res.SyncCondition = newSyncedCondition(syncedMessages)
+	}
+	return res, nil
+}
+
+// newArgoCDForbiddenError returns a terminal error indicating that the
+// identity used for Argo CD operations, typically a ServiceAccount referenced
+// by the Stage, is not permitted by RBAC to perform the provided action on the
+// Argo CD Application with the provided key. Retrying would be futile until
+// permissions are changed.
+func newArgoCDForbiddenError(verb string, appKey client.ObjectKey, err error) error {
+	return &terminalError{err: fmt.Errorf(
+		"Forbidden: not permitted to %s Argo CD Application %q in namespace %q: %w",
+		verb, appKey.Name, appKey.Namespace, err,
+	)}
 }
 
 // buildDesiredSources returns the desired source(s) for an Argo CD Application,
@@ -454,9 +790,30 @@ func (a *argocdUpdater) mustPerformUpdate(
 			continue
 		}
 		if observedRevision != desiredRevision {
-			return "", true, fmt.Errorf(
-				"sync result revisions %v do not match desired revisions %v",
-				observedRevisions, desiredRevisions,
+			return "", true, &syncRevisionsMismatchError{
+				observed: observedRevisions,
+				desired:  desiredRevisions,
+			}
+		}
+	}
+
+	// As a further check, if the desired revisions were committed with Kargo
+	// trailers, those should identify the current Promotion. If they do not,
+	// the revision was committed by an earlier Promotion, which is expected if
+	// this Promotion did not change the rendered manifests. This is reported
+	// for the benefit of anyone investigating what was deployed, but is not
+	// treated as a failure.
+	promotion := stepCtx.Project + "/" + stepCtx.Promotion
+	for _, sourceUpdate := range update.Sources {
+		trailers := getCommitTrailersFromStep(
+			stepCtx.SharedState,
+			sourceUpdate.DesiredCommitFromStep,
+		)
+		if trailers.Promotion != "" && trailers.Promotion != promotion {
+			return status.Phase, false, fmt.Errorf(
+				"revision synced for source %q was committed by Promotion %q and "+
+					"not by the current Promotion %q",
+				sourceUpdate.RepoURL, trailers.Promotion, promotion,
 			)
 		}
 	}
@@ -465,6 +822,299 @@ func (a *argocdUpdater) mustPerformUpdate(
 	return status.Phase, false, nil
 }
 
+// syncTriggerFor returns the SyncTrigger that applies to the provided update,
+// which is the one specified for the update itself, if any, or else the one
+// specified for the step as a whole, if any, or else Operation.
+func syncTriggerFor(stepCfg *ArgoCDUpdateConfig, update *ArgoCDAppUpdate) SyncTrigger {
+	if update.SyncTrigger != "" {
+		return update.SyncTrigger
+	}
+	if stepCfg.SyncTrigger != "" {
+		return stepCfg.SyncTrigger
+	}
+	return Operation
+}
+
+// syncWindowPolicyFor returns the SyncWindowPolicy that applies to the
+// provided update, which is the one specified for the update itself, if any,
+// or else the one specified for the step as a whole, if any, or else Wait.
+func syncWindowPolicyFor(stepCfg *ArgoCDUpdateConfig, update *ArgoCDAppUpdate) SyncWindowPolicy {
+	if update.SyncWindowPolicy != "" {
+		return update.SyncWindowPolicy
+	}
+	if stepCfg.SyncWindowPolicy != "" {
+		return stepCfg.SyncWindowPolicy
+	}
+	return Wait
+}
+
+// syncWindowWait describes a sync of an Argo CD Application that is waiting
+// for the sync windows of its AppProject to permit it.
+type syncWindowWait struct {
+	// message explains what the sync is waiting for.
+	message string
+	// until is the time at which the sync windows next permit the sync, if
+	// it is known.
+	until *time.Time
+}
+
+// awaitSyncWindow returns a syncWindowWait if the sync windows of the
+// AppProject of the provided Argo CD Application currently deny a sync of it
+// under the provided SyncWindowPolicy, and nil otherwise. AppProjects are
+// looked up in the provided namespace Argo CD is installed in. Syncs are
+// regarded as manual ones only if the policy is AllowManual.
+func (a *argocdUpdater) awaitSyncWindow(
+	ctx context.Context,
+	argoCDClient client.Client,
+	argocdNamespace string,
+	policy SyncWindowPolicy,
+	app *argocd.Application,
+) (*syncWindowWait, error) {
+	if policy == Ignore {
+		return nil, nil
+	}
+	windows, err := a.getSyncWindowsFn(ctx, argoCDClient, argocdNamespace, app)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error getting sync windows of Argo CD Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)
+	}
+	manual := policy == AllowManual
+	now := time.Now()
+	canSync, err := windows.CanSync(manual, now)
+	if err == nil && canSync {
+		return nil, nil
+	}
+	var next time.Time
+	if err == nil {
+		next, err = windows.NextSyncTime(manual, now)
+	}
+	if err != nil {
+		return nil, &terminalError{err: fmt.Errorf(
+			"error evaluating sync windows of Argo CD Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)}
+	}
+	wait := &syncWindowWait{
+		message: fmt.Sprintf(
+			"syncs of Argo CD Application %q in namespace %q are denied by the "+
+				"sync windows of its AppProject",
+			app.Name, app.Namespace,
+		),
+	}
+	if !next.IsZero() {
+		wait.message += " until " + next.UTC().Format(time.RFC3339)
+		wait.until = &next
+	}
+	return wait, nil
+}
+
+// getSyncWindows returns the sync windows of the AppProject of the provided
+// Argo CD Application that apply to it. AppProjects are looked up in the
+// provided namespace Argo CD is installed in. If the AppProject does not exist,
+// no sync windows are returned, as Argo CD does not sync the Application at
+// all.
+func (a *argocdUpdater) getSyncWindows(
+	ctx context.Context,
+	argoCDClient client.Client,
+	argocdNamespace string,
+	app *argocd.Application,
+) (argocd.SyncWindows, error) {
+	projectName := app.Spec.Project
+	if projectName == "" {
+		projectName = "default"
+	}
+	project, err := argocd.GetAppProject(ctx, argoCDClient, argocdNamespace, projectName)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	return project.Spec.SyncWindows.Matches(app), nil
+}
+
+// earliestTime returns the earlier of the provided times, either of which may
+// be nil.
+func earliestTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+// awaitSync waits for an Argo CD Application that is not synced by means of an
+// operation initiated by this step to converge on the desired revisions. If
+// the provided SyncTrigger is RefreshOnly, the Application's sources are
+// updated if necessary and a hard refresh is requested until Argo CD has
+// observed the desired revisions, leaving the sync itself to the Application's
+// automated sync policy. If it is None, the Application is never modified.
+// OperationSucceeded is returned once the Application is synced to the desired
+// revisions, or to revisions descending from them, in which case a
+// supersededSync is returned as well, and OperationRunning while it is not
+// yet. If a sync to the desired revisions failed, or the Application was
+// synced to revisions that neither match nor descend from them, a terminal
+// error wrapping a syncFailedError is returned.
+func (a *argocdUpdater) awaitSync(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	stepCfg *ArgoCDUpdateConfig,
+	update *ArgoCDAppUpdate,
+	trigger SyncTrigger,
+	desiredRevisions []string,
+	app *argocd.Application,
+) (argocd.OperationPhase, *supersededSync, error) {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"app", app.Name,
+		"namespace", app.Namespace,
+		"syncTrigger", trigger,
+	)
+
+	if op := app.Status.OperationState; op != nil {
+		if !op.Phase.Completed() {
+			// Most likely, the automated sync policy is syncing the Application.
+			return argocd.OperationRunning, nil, nil
+		}
+		if op.Phase.Failed() && op.SyncResult != nil &&
+			slices.ContainsFunc(desiredRevisions, func(r string) bool { return r != "" }) &&
+			revisionsMatch(syncResultRevisions(op.SyncResult), desiredRevisions) {
+			return "", nil, &terminalError{err: newSyncFailedError(app)}
+		}
+	}
+
+	revisionsObserved := revisionsMatch(syncStatusRevisions(app.Status.Sync), desiredRevisions)
+	if revisionsObserved && app.Status.Sync.Status == argocd.SyncStatusCodeSynced {
+		logger.Debug("Argo CD Application is synced to the desired revisions")
+		return argocd.OperationSucceeded, nil, nil
+	}
+
+	desiredSources, err := a.buildDesiredSourcesFn(ctx, stepCtx, stepCfg, update, desiredRevisions, app)
+	if err != nil {
+		return "", nil, fmt.Errorf(
+			"error building desired sources for Argo CD Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)
+	}
+	currentSources := app.Spec.Sources
+	if app.Spec.Source != nil {
+		currentSources = argocd.ApplicationSources{*app.Spec.Source}
+	}
+	sourcesChanged := !currentSources.Equals(desiredSources)
+
+	// The Application may already have been synced past the desired revisions,
+	// e.g. because others pushed to the branch it tracks in the meantime.
+	if !sourcesChanged && app.Status.Sync.Status == argocd.SyncStatusCodeSynced {
+		observedRevisions := syncStatusRevisions(app.Status.Sync)
+		state, superseded := a.compareSyncedRevisions(
+			ctx, stepCtx, app, observedRevisions, desiredRevisions,
+		)
+		switch state {
+		case syncedRevisionsSuperseded:
+			logger.Debug("Argo CD Application is synced to revisions descending from the desired ones")
+			return argocd.OperationSucceeded, superseded, nil
+		case syncedRevisionsDiverged:
+			return "", nil, &terminalError{
+				err: newSyncRevisionDivergedError(app, observedRevisions, desiredRevisions),
+			}
+		}
+	}
+
+	if trigger == None {
+		if sourcesChanged {
+			return "", nil, &terminalError{err: fmt.Errorf(
+				"sources of Argo CD Application %q in namespace %q must be updated, "+
+					"which sync trigger %q does not permit",
+				app.Name, app.Namespace, trigger,
+			)}
+		}
+		logger.Debug("waiting for Argo CD Application to be synced")
+		return argocd.OperationRunning, nil, nil
+	}
+
+	// A refresh that was requested earlier, but has not been processed by Argo
+	// CD yet, is not requested again.
+	if !sourcesChanged &&
+		(revisionsObserved || app.Annotations[argocd.AnnotationKeyRefresh] != "") {
+		logger.Debug("waiting for Argo CD Application to be synced")
+		return argocd.OperationRunning, nil, nil
+	}
+	if err = a.refreshApplicationFn(ctx, stepCtx, app, desiredSources, argocd.RefreshTypeHard); err != nil {
+		if apierrors.IsForbidden(err) {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf(
+			"error refreshing Argo CD Application %q in namespace %q: %w",
+			app.Name, app.Namespace, err,
+		)
+	}
+	return argocd.OperationRunning, nil, nil
+}
+
+// syncStatusRevisions returns the revisions that the provided SyncStatus
+// reports, one per source.
+func syncStatusRevisions(status argocd.SyncStatus) []string {
+	if len(status.Revisions) > 0 {
+		return status.Revisions
+	}
+	return []string{status.Revision}
+}
+
+// syncResultRevisions returns the revisions that the provided SyncOperationResult
+// reports, one per source.
+func syncResultRevisions(result *argocd.SyncOperationResult) []string {
+	if len(result.Revisions) > 0 {
+		return result.Revisions
+	}
+	return []string{result.Revision}
+}
+
+// revisionsMatch returns true if the provided observed revisions match the
+// provided desired revisions. Desired revisions that are empty match any
+// revision.
+func revisionsMatch(observed, desired []string) bool {
+	for i, desiredRevision := range desired {
+		if desiredRevision == "" {
+			continue
+		}
+		if i >= len(observed) || observed[i] != desiredRevision {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshApplication updates the source(s) of an Argo CD Application to the
+// desired ones and requests a refresh of the provided type, without initiating
+// an operation.
+func (a *argocdUpdater) refreshApplication(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	app *argocd.Application,
+	desiredSources argocd.ApplicationSources,
+	refreshType argocd.RefreshType,
+) error {
+	if app.ObjectMeta.Annotations == nil {
+		app.ObjectMeta.Annotations = make(map[string]string, 1)
+	}
+	app.ObjectMeta.Annotations[argocd.AnnotationKeyRefresh] = string(refreshType)
+	if app.Spec.Source != nil {
+		app.Spec.Source = desiredSources[0].DeepCopy()
+	} else {
+		app.Spec.Sources = desiredSources.DeepCopy()
+	}
+	if err := a.argoCDAppPatchFn(ctx, stepCtx, app, func(src, dst unstructured.Unstructured) error {
+		dst.SetAnnotations(src.GetAnnotations())
+		dst.Object["spec"] = a.recursiveMerge(src.Object["spec"], dst.Object["spec"])
+		return nil
+	}); err != nil {
+		return err
+	}
+	logging.LoggerFromContext(ctx).Debug(
+		"requested refresh of Argo CD Application",
+		"app", app.Name,
+		"refreshType", refreshType,
+	)
+	return nil
+}
+
 func (a *argocdUpdater) syncApplication(
 	ctx context.Context,
 	stepCtx *PromotionStepContext,
@@ -666,8 +1316,8 @@ func (a *argocdUpdater) getAuthorizedApplication(
 	}
 	if app == nil {
 		return nil, fmt.Errorf(
-			"unable to find Argo CD Application %q in namespace %q",
-			appKey.Name, appKey.Namespace,
+			"unable to find Argo CD Application %q in namespace %q: %w",
+			appKey.Name, appKey.Namespace, errArgoCDAppNotFound,
 		)
 	}
 
@@ -675,9 +1325,60 @@ func (a *argocdUpdater) getAuthorizedApplication(
 		return nil, err
 	}
 
+	if err = a.authorizeArgoCDAppNamespace(ctx, stepCtx, app); err != nil {
+		return nil, err
+	}
+
 	return app, nil
 }
 
+// authorizeArgoCDAppNamespace returns an error if the provided Argo CD
+// Application resides in a namespace other than the one Argo CD is installed
+// in and its AppProject does not permit Applications to reside in that
+// namespace. Argo CD refuses to reconcile such an Application, so any update
+// to it would never complete.
+func (a *argocdUpdater) authorizeArgoCDAppNamespace(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	app *argocd.Application,
+) error {
+	argocdNamespace := argoCDNamespace(stepCtx.ArgoCDNamespace)
+	if app.Namespace == argocdNamespace {
+		return nil
+	}
+	projectName := app.Spec.Project
+	if projectName == "" {
+		projectName = "default"
+	}
+	project, err := argocd.GetAppProject(ctx, stepCtx.ArgoCDClient, argocdNamespace, projectName)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf(
+			"unable to find Argo CD AppProject %q of Application %q in namespace %q",
+			projectName, app.Name, app.Namespace,
+		)
+	}
+	if !project.PermitsSourceNamespace(app.Namespace) {
+		return fmt.Errorf(
+			"namespace %q of Application %q is not permitted by Argo CD AppProject %q; "+
+				"the namespace must be listed in the AppProject's sourceNamespaces",
+			app.Namespace, app.Name, projectName,
+		)
+	}
+	return nil
+}
+
+// argoCDNamespace returns the provided namespace that Argo CD is installed
+// into or, if it is empty, the namespace of the default Argo CD context.
+func argoCDNamespace(namespace string) string {
+	if namespace == "" {
+		return libargocd.Namespace()
+	}
+	return namespace
+}
+
 // authorizeArgoCDAppUpdate returns an error if the Argo CD Application
 // represented by appMeta does not explicitly permit mutation by the Kargo Stage
 // represented by stageMeta.
@@ -748,8 +1449,8 @@ func (a *argocdUpdater) applyArgoCDSourceUpdate(
 	} else {
 		// We're dealing with a git repo, so we should normalize the repo URLs
 		// before comparing them.
-		sourceRepoURL := git.NormalizeURL(source.RepoURL)
-		if sourceRepoURL != git.NormalizeURL(update.RepoURL) {
+		sourceRepoURL := libgit.NormalizeURL(source.RepoURL)
+		if sourceRepoURL != libgit.NormalizeURL(update.RepoURL) {
 			// The update is not applicable to this source.
 			return source, false, nil
 		}
@@ -5,18 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
 	"github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
 	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/kubeclient"
 )
 
@@ -28,6 +34,7 @@ func Test_newArgocdUpdater(t *testing.T) {
 	require.NotNil(t, runner.buildDesiredSourcesFn)
 	require.NotNil(t, runner.mustPerformUpdateFn)
 	require.NotNil(t, runner.syncApplicationFn)
+	require.NotNil(t, runner.getSyncWindowsFn)
 	require.NotNil(t, runner.applyArgoCDSourceUpdateFn)
 	require.NotNil(t, runner.argoCDAppPatchFn)
 	require.NotNil(t, runner.logAppEventFn)
@@ -75,6 +82,20 @@ func Test_argoCDUpdater_validate(t *testing.T) {
 				"apps.0.name: String length must be greater than or equal to 1",
 			},
 		},
+		{
+			name: "invalid sync trigger",
+			config: Config{
+				"syncTrigger": "sometimes",
+				"apps": []Config{{
+					"name":        "fake-app",
+					"syncTrigger": "never",
+				}},
+			},
+			expectedProblems: []string{
+				"syncTrigger: syncTrigger must be one of the following",
+				"apps.0.syncTrigger: apps.0.syncTrigger must be one of the following",
+			},
+		},
 		{
 			name: "app namespace is empty string",
 			config: Config{
@@ -149,6 +170,19 @@ func Test_argoCDUpdater_validate(t *testing.T) {
 				"apps.0.sources.0: Must validate one and only one schema",
 			},
 		},
+		{
+			name: "targetRevision=true with chart and desired revision unspecified",
+			config: Config{
+				"apps": []Config{{
+					"name": "fake-app",
+					"sources": []Config{{
+						"repoURL":              "fake-chart-repo",
+						"chart":                "fake-chart",
+						"updateTargetRevision": true,
+					}},
+				}},
+			},
+		},
 		{
 			name: "helm images is empty array",
 			config: Config{
@@ -476,6 +510,92 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				require.ErrorContains(t, err, "something went wrong")
 			},
 		},
+		{
+			name: "application does not exist yet",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return nil, fmt.Errorf("unable to find Argo CD Application: %w", errArgoCDAppNotFound)
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{Name: "fake-app"}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+				require.Contains(t, res.Message, "Waiting: ReferentNotFound")
+				require.Contains(t, res.Message, "fake-app")
+			},
+		},
+		{
+			name: "application deleted while awaiting its sync",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return nil, fmt.Errorf("unable to find Argo CD Application: %w", errArgoCDAppNotFound)
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient:    fake.NewFakeClient(),
+				ArgoCDNamespace: "argocd",
+				Alias:           "update",
+				SharedState: State{
+					"update": map[string]any{
+						stateKeyPatchedApps: []any{"argocd/fake-app"},
+					},
+				},
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{Name: "fake-app"}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.True(t, isTerminal(err))
+				require.ErrorContains(t, err, "ApplicationDeleted")
+				require.ErrorContains(t, err, "fake-app")
+			},
+		},
+		{
+			name: "not permitted to get application",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return nil, fmt.Errorf(
+						"error finding Argo CD Application: %w",
+						apierrors.NewForbidden(
+							schema.GroupResource{Group: "argoproj.io", Resource: "applications"},
+							"fake-app",
+							errors.New("access denied"),
+						),
+					)
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{Name: "fake-app"}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+				require.ErrorContains(t, err, "Forbidden: not permitted to get Argo CD Application")
+				require.ErrorContains(t, err, "access denied")
+				require.True(t, isTerminal(err))
+			},
+		},
 		{
 			name: "error determining if update is necessary",
 			runner: &argocdUpdater{
@@ -532,6 +652,14 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				) (argocd.OperationPhase, bool, error) {
 					return "", true, errors.New("something went wrong")
 				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return nil, nil
+				},
 				syncApplicationFn: func(
 					context.Context,
 					*PromotionStepContext,
@@ -545,11 +673,14 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				ArgoCDClient: fake.NewFakeClient(),
 			},
 			stepCfg: ArgoCDUpdateConfig{
-				Apps: []ArgoCDAppUpdate{{}},
+				Apps: []ArgoCDAppUpdate{{Name: "fake-app"}},
 			},
 			assertions: func(t *testing.T, res PromotionStepResult, err error) {
 				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
 				require.NoError(t, err)
+				// The patched Application is recorded so that its deletion is
+				// recognized while its sync is awaited.
+				require.Equal(t, []string{"argocd/fake-app"}, res.Output[stateKeyPatchedApps])
 			},
 		},
 		{
@@ -637,6 +768,14 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				) (argocd.ApplicationSources, error) {
 					return nil, errors.New("something went wrong")
 				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return nil, nil
+				},
 			},
 			stepCtx: &PromotionStepContext{
 				ArgoCDClient: fake.NewFakeClient(),
@@ -677,6 +816,14 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				) (argocd.ApplicationSources, error) {
 					return []argocd.ApplicationSource{{}}, nil
 				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return nil, nil
+				},
 				syncApplicationFn: func(
 					context.Context,
 					*PromotionStepContext,
@@ -698,6 +845,132 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				require.ErrorContains(t, err, "something went wrong")
 			},
 		},
+		{
+			name: "application deleted before update could be applied",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, nil
+				},
+				buildDesiredSourcesFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppUpdate,
+					[]string,
+					*argocd.Application,
+				) (argocd.ApplicationSources, error) {
+					return []argocd.ApplicationSource{{}}, nil
+				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return nil, nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					return fmt.Errorf(
+						"error patching Argo CD Application: %w",
+						apierrors.NewNotFound(
+							schema.GroupResource{Group: "argoproj.io", Resource: "applications"},
+							"fake-app",
+						),
+					)
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{Name: "fake-app"}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.True(t, isTerminal(err))
+				require.ErrorContains(t, err, "ApplicationDeleted")
+			},
+		},
+		{
+			name: "not permitted to apply update",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, nil
+				},
+				buildDesiredSourcesFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppUpdate,
+					[]string,
+					*argocd.Application,
+				) (argocd.ApplicationSources, error) {
+					return []argocd.ApplicationSource{{}}, nil
+				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return nil, nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					return fmt.Errorf(
+						"failed to patch the object: %w",
+						apierrors.NewForbidden(
+							schema.GroupResource{Group: "argoproj.io", Resource: "applications"},
+							"fake-app",
+							errors.New("access denied"),
+						),
+					)
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{Name: "fake-app"}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+				require.ErrorContains(t, err, "Forbidden: not permitted to update Argo CD Application")
+				require.ErrorContains(t, err, "access denied")
+				require.True(t, isTerminal(err))
+			},
+		},
 		{
 			name: "failed and pending update",
 			runner: &argocdUpdater{
@@ -736,6 +1009,14 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				) (argocd.ApplicationSources, error) {
 					return []argocd.ApplicationSource{{}}, nil
 				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return nil, nil
+				},
 				syncApplicationFn: func(
 					context.Context,
 					*PromotionStepContext,
@@ -817,70 +1098,678 @@ func Test_argoCDUpdater_runPromotionStep(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
-	}
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			res, err := testCase.runner.runPromotionStep(
-				context.Background(),
-				testCase.stepCtx,
-				testCase.stepCfg,
-			)
-			testCase.assertions(t, res, err)
-		})
-	}
-}
-
-func Test_argoCDUpdater_buildDesiredSources(t *testing.T) {
-	testCases := []struct {
-		name             string
-		runner           *argocdUpdater
-		update           *ArgoCDAppUpdate
-		desiredRevisions []string
-		app              *argocd.Application
-		assertions       func(
-			t *testing.T,
-			desiredSources argocd.ApplicationSources,
-			err error,
-		)
-	}{
 		{
-			name: "error applying update to source",
+			name: "completed sync is recorded",
 			runner: &argocdUpdater{
-				applyArgoCDSourceUpdateFn: func(
+				getAuthorizedApplicationFn: func(
 					context.Context,
 					*PromotionStepContext,
-					*ArgoCDUpdateConfig,
-					*ArgoCDAppSourceUpdate,
-					string,
-					argocd.ApplicationSource,
-				) (argocd.ApplicationSource, bool, error) {
-					return argocd.ApplicationSource{}, false, errors.New("something went wrong")
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace"},
+						Status: argocd.ApplicationStatus{
+							OperationState: &argocd.OperationState{
+								Phase:   argocd.OperationSucceeded,
+								Message: "successfully synced (all tasks run)",
+							},
+						},
+					}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return argocd.OperationSucceeded, false, nil
 				},
 			},
-			update: &ArgoCDAppUpdate{
-				Sources: []ArgoCDAppSourceUpdate{{}},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
 			},
-			desiredRevisions: []string{"fake-version"},
-			app: &argocd.Application{
-				Spec: argocd.ApplicationSpec{
-					Source: &argocd.ApplicationSource{},
-				},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{}},
 			},
-			assertions: func(
-				t *testing.T,
-				desiredSources argocd.ApplicationSources,
-				err error,
-			) {
-				require.ErrorContains(t, err, "something went wrong")
-				require.Nil(t, desiredSources)
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.NotNil(t, res.SyncCondition)
+				require.Equal(t, metav1.ConditionTrue, res.SyncCondition.Status)
+				require.Equal(t, "Synced", res.SyncCondition.Reason)
+				require.Equal(
+					t,
+					`Argo CD Application "fake-name" in namespace "fake-namespace" synced `+
+						`with: successfully synced (all tasks run)`,
+					res.SyncCondition.Message,
+				)
 			},
 		},
 		{
-			name: "applies updates to sources",
+			name: "failed sync fails immediately",
 			runner: &argocdUpdater{
-				applyArgoCDSourceUpdateFn: func(
-					_ context.Context,
-					_ *PromotionStepContext,
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{Name: "fake-name", Namespace: "fake-namespace"},
+						Status: argocd.ApplicationStatus{
+							OperationState: &argocd.OperationState{
+								Phase:   argocd.OperationFailed,
+								Message: "one or more synchronization tasks completed unsuccessfully",
+								SyncResult: &argocd.SyncOperationResult{
+									Resources: argocd.ResourceResults{
+										{Kind: "Deployment", Namespace: "app", Name: "web", Status: argocd.ResultCodeSynced},
+										{
+											Kind:      "Job",
+											Namespace: "app",
+											Name:      "migrate",
+											HookType:  argocd.HookTypePreSync,
+											HookPhase: argocd.OperationFailed,
+											Message:   "Job has reached the specified backoff limit",
+										},
+									},
+								},
+							},
+						},
+					}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return argocd.OperationFailed, false, nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.True(t, isTerminal(err))
+				require.ErrorContains(t, err, "SyncHookFailed")
+				require.NotNil(t, res.SyncCondition)
+				require.Equal(t, metav1.ConditionFalse, res.SyncCondition.Status)
+				require.Equal(t, "SyncHookFailed", res.SyncCondition.Reason)
+				require.Equal(
+					t,
+					`Argo CD Application "fake-name" in namespace "fake-namespace" failed to `+
+						`sync with: one or more synchronization tasks completed unsuccessfully; `+
+						`failed resources: Job "app/migrate": Job has reached the specified backoff limit`,
+					res.SyncCondition.Message,
+				)
+			},
+		},
+		{
+			name: "sync trigger is honored per app",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
+					key client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+						Status: argocd.ApplicationStatus{
+							Sync: argocd.SyncStatus{Status: argocd.SyncStatusCodeSynced},
+						},
+					}, nil
+				},
+				mustPerformUpdateFn: func(
+					_ *PromotionStepContext,
+					update *ArgoCDAppUpdate,
+					_ *argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					// Only the app that is synced by means of an operation may get
+					// here.
+					require.Equal(t, "operation-app", update.Name)
+					return argocd.OperationSucceeded, false, nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				SyncTrigger: None,
+				Apps: []ArgoCDAppUpdate{
+					{Name: "none-app"},
+					{Name: "operation-app", SyncTrigger: Operation},
+				},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+			},
+		},
+		{
+			name: "sync windows deny sync",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
+					key client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: key.Namespace,
+							Name:      key.Name,
+						},
+					}, nil
+				},
+				buildDesiredSourcesFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppUpdate,
+					[]string,
+					*argocd.Application,
+				) (argocd.ApplicationSources, error) {
+					return []argocd.ApplicationSource{{}}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, nil
+				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					now := time.Now().UTC()
+					return argocd.SyncWindows{{
+						Kind:       argocd.SyncWindowKindDeny,
+						Schedule:   fmt.Sprintf("%d %d * * *", now.Minute(), now.Hour()),
+						Duration:   "1h",
+						ManualSync: true,
+					}}, nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					require.Fail(t, "Application must not be synced")
+					return nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient:    fake.NewFakeClient(),
+				ArgoCDNamespace: "fake-argocd-namespace",
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{Name: "fake-name"}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+				require.Contains(t, res.Message, "Waiting: SyncWindow:")
+				require.Contains(t, res.Message, `Argo CD Application "fake-name"`)
+				require.NotNil(t, res.WaitUntil)
+				require.WithinDuration(t, time.Now().Add(time.Hour), *res.WaitUntil, time.Minute)
+			},
+		},
+		{
+			name: "sync windows permit manual sync",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, nil
+				},
+				buildDesiredSourcesFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppUpdate,
+					[]string,
+					*argocd.Application,
+				) (argocd.ApplicationSources, error) {
+					return []argocd.ApplicationSource{{}}, nil
+				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					return argocd.SyncWindows{{
+						Kind:       argocd.SyncWindowKindDeny,
+						Schedule:   "* * * * *",
+						Duration:   "1h",
+						ManualSync: true,
+					}}, nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					return nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				SyncWindowPolicy: AllowManual,
+				Apps:             []ArgoCDAppUpdate{{}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+				require.Empty(t, res.Message)
+				require.Nil(t, res.WaitUntil)
+			},
+		},
+		{
+			name: "sync windows are ignored",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, nil
+				},
+				buildDesiredSourcesFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppUpdate,
+					[]string,
+					*argocd.Application,
+				) (argocd.ApplicationSources, error) {
+					return []argocd.ApplicationSource{{}}, nil
+				},
+				getSyncWindowsFn: func(
+					context.Context,
+					client.Client,
+					string,
+					*argocd.Application,
+				) (argocd.SyncWindows, error) {
+					require.Fail(t, "sync windows must not be consulted")
+					return nil, nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					return nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient: fake.NewFakeClient(),
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{SyncWindowPolicy: Ignore}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+				require.Nil(t, res.WaitUntil)
+			},
+		},
+		{
+			name: "sync skipped when manifests are unchanged",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
+					key client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: key.Namespace,
+							Name:      key.Name,
+						},
+						Spec: argocd.ApplicationSpec{
+							Source: &argocd.ApplicationSource{
+								RepoURL:        "https://github.com/universe/42",
+								TargetRevision: "old-commit",
+							},
+						},
+						Status: argocd.ApplicationStatus{
+							Sync: argocd.SyncStatus{
+								Status:   argocd.SyncStatusCodeSynced,
+								Revision: "old-commit",
+							},
+						},
+					}, nil
+				},
+				buildDesiredSourcesFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppUpdate,
+					[]string,
+					*argocd.Application,
+				) (argocd.ApplicationSources, error) {
+					return []argocd.ApplicationSource{{
+						RepoURL:        "https://github.com/universe/42",
+						TargetRevision: "new-commit",
+					}}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, nil
+				},
+				getCommitTreesFn: func(
+					string,
+					[]string,
+					*git.ClientOptions,
+				) (map[string]string, error) {
+					return map[string]string{
+						"old-commit": "fake-tree",
+						"new-commit": "fake-tree",
+					}, nil
+				},
+				refreshApplicationFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
+					_ *argocd.Application,
+					_ argocd.ApplicationSources,
+					refreshType argocd.RefreshType,
+				) error {
+					require.Equal(t, argocd.RefreshTypeNormal, refreshType)
+					return nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					require.Fail(t, "Application must not be synced")
+					return nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient:  fake.NewFakeClient(),
+				CredentialsDB: &credentials.FakeDB{},
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Name: "fake-name",
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:              "https://github.com/universe/42",
+						DesiredRevision:      "new-commit",
+						UpdateTargetRevision: true,
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Contains(t, res.Message, "SyncSkippedNoChanges:")
+				require.Contains(t, res.Message, `Argo CD Application "fake-name"`)
+			},
+		},
+		{
+			name: "synced to revision descending from desired revision",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
+					key client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: key.Namespace,
+							Name:      key.Name,
+						},
+						Spec: argocd.ApplicationSpec{
+							Source: &argocd.ApplicationSource{
+								RepoURL:        "https://github.com/universe/42",
+								TargetRevision: "main",
+							},
+						},
+						Status: argocd.ApplicationStatus{
+							Sync: argocd.SyncStatus{
+								Status:   argocd.SyncStatusCodeSynced,
+								Revision: "2222222222222222222222222222222222222222",
+							},
+							OperationState: &argocd.OperationState{
+								Phase:   argocd.OperationSucceeded,
+								Message: "successfully synced",
+								SyncResult: &argocd.SyncOperationResult{
+									Revision: "2222222222222222222222222222222222222222",
+								},
+							},
+						},
+					}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, &syncRevisionsMismatchError{
+						observed: []string{"2222222222222222222222222222222222222222"},
+						desired:  []string{"1111111111111111111111111111111111111111"},
+					}
+				},
+				isAncestorFn: func(
+					_ string,
+					ancestor string,
+					descendant string,
+					_ *git.ClientOptions,
+				) (bool, error) {
+					return ancestor == "1111111111111111111111111111111111111111" && descendant == "2222222222222222222222222222222222222222", nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					require.Fail(t, "Application must not be synced again")
+					return nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient:  fake.NewFakeClient(),
+				CredentialsDB: &credentials.FakeDB{},
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Name: "fake-name",
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:         "https://github.com/universe/42",
+						DesiredRevision: "1111111111111111111111111111111111111111",
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Contains(t, res.Message, "SupersededByNewerRevision:")
+				require.Contains(t, res.Message, "2222222222222222222222222222222222222222")
+				require.NotNil(t, res.SyncCondition)
+				require.Equal(t, metav1.ConditionTrue, res.SyncCondition.Status)
+				apps, ok := res.HealthCheckStep.Config["apps"].([]ArgoCDAppHealthCheck)
+				require.True(t, ok)
+				require.Equal(t, []string{"2222222222222222222222222222222222222222"}, apps[0].DesiredRevisions)
+			},
+		},
+		{
+			name: "synced to revision diverging from desired revision",
+			runner: &argocdUpdater{
+				getAuthorizedApplicationFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
+					key client.ObjectKey,
+				) (*v1alpha1.Application, error) {
+					return &argocd.Application{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: key.Namespace,
+							Name:      key.Name,
+						},
+						Spec: argocd.ApplicationSpec{
+							Source: &argocd.ApplicationSource{
+								RepoURL:        "https://github.com/universe/42",
+								TargetRevision: "main",
+							},
+						},
+						Status: argocd.ApplicationStatus{
+							Sync: argocd.SyncStatus{
+								Status:   argocd.SyncStatusCodeSynced,
+								Revision: "2222222222222222222222222222222222222222",
+							},
+							OperationState: &argocd.OperationState{
+								Phase:   argocd.OperationSucceeded,
+								Message: "successfully synced",
+								SyncResult: &argocd.SyncOperationResult{
+									Revision: "2222222222222222222222222222222222222222",
+								},
+							},
+						},
+					}, nil
+				},
+				mustPerformUpdateFn: func(
+					*PromotionStepContext,
+					*ArgoCDAppUpdate,
+					*argocd.Application,
+				) (argocd.OperationPhase, bool, error) {
+					return "", true, &syncRevisionsMismatchError{
+						observed: []string{"2222222222222222222222222222222222222222"},
+						desired:  []string{"1111111111111111111111111111111111111111"},
+					}
+				},
+				isAncestorFn: func(
+					_ string,
+					ancestor string,
+					descendant string,
+					_ *git.ClientOptions,
+				) (bool, error) {
+					return false, nil
+				},
+				syncApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+				) error {
+					require.Fail(t, "Application must not be synced again")
+					return nil
+				},
+			},
+			stepCtx: &PromotionStepContext{
+				ArgoCDClient:  fake.NewFakeClient(),
+				CredentialsDB: &credentials.FakeDB{},
+			},
+			stepCfg: ArgoCDUpdateConfig{
+				Apps: []ArgoCDAppUpdate{{
+					Name: "fake-name",
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:         "https://github.com/universe/42",
+						DesiredRevision: "1111111111111111111111111111111111111111",
+					}},
+				}},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.Error(t, err)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.NotNil(t, res.SyncCondition)
+				require.Equal(t, "SyncedRevisionDiverged", res.SyncCondition.Reason)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			res, err := testCase.runner.runPromotionStep(
+				context.Background(),
+				testCase.stepCtx,
+				testCase.stepCfg,
+			)
+			testCase.assertions(t, res, err)
+		})
+	}
+}
+
+func Test_argoCDUpdater_buildDesiredSources(t *testing.T) {
+	testCases := []struct {
+		name             string
+		runner           *argocdUpdater
+		update           *ArgoCDAppUpdate
+		desiredRevisions []string
+		app              *argocd.Application
+		assertions       func(
+			t *testing.T,
+			desiredSources argocd.ApplicationSources,
+			err error,
+		)
+	}{
+		{
+			name: "error applying update to source",
+			runner: &argocdUpdater{
+				applyArgoCDSourceUpdateFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*ArgoCDUpdateConfig,
+					*ArgoCDAppSourceUpdate,
+					string,
+					argocd.ApplicationSource,
+				) (argocd.ApplicationSource, bool, error) {
+					return argocd.ApplicationSource{}, false, errors.New("something went wrong")
+				},
+			},
+			update: &ArgoCDAppUpdate{
+				Sources: []ArgoCDAppSourceUpdate{{}},
+			},
+			desiredRevisions: []string{"fake-version"},
+			app: &argocd.Application{
+				Spec: argocd.ApplicationSpec{
+					Source: &argocd.ApplicationSource{},
+				},
+			},
+			assertions: func(
+				t *testing.T,
+				desiredSources argocd.ApplicationSources,
+				err error,
+			) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.Nil(t, desiredSources)
+			},
+		},
+		{
+			name: "applies updates to sources",
+			runner: &argocdUpdater{
+				applyArgoCDSourceUpdateFn: func(
+					_ context.Context,
+					_ *PromotionStepContext,
 					_ *ArgoCDUpdateConfig,
 					update *ArgoCDAppSourceUpdate,
 					_ string,
@@ -1210,6 +2099,93 @@ func Test_argoCDUpdater_mustPerformUpdate(t *testing.T) {
 	}
 }
 
+func Test_argoCDUpdater_mustPerformUpdate_commitTrailers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		trailers   string
+		assertions func(t *testing.T, phase argocd.OperationPhase, mustUpdate bool, err error)
+	}{
+		{
+			name: "no trailers",
+			assertions: func(t *testing.T, phase argocd.OperationPhase, mustUpdate bool, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationSucceeded, phase)
+				require.False(t, mustUpdate)
+			},
+		},
+		{
+			name:     "trailers identify the current Promotion",
+			trailers: "Kargo-Promotion: fake-project/fake-promotion",
+			assertions: func(t *testing.T, phase argocd.OperationPhase, mustUpdate bool, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationSucceeded, phase)
+				require.False(t, mustUpdate)
+			},
+		},
+		{
+			name:     "trailers identify another Promotion",
+			trailers: "Kargo-Promotion: fake-project/other-promotion",
+			assertions: func(t *testing.T, phase argocd.OperationPhase, mustUpdate bool, err error) {
+				// The discrepancy is reported, but the operation is still
+				// considered complete
+				require.ErrorContains(t, err, `committed by Promotion "fake-project/other-promotion"`)
+				require.Equal(t, argocd.OperationSucceeded, phase)
+				require.False(t, mustUpdate)
+			},
+		},
+	}
+
+	runner := &argocdUpdater{}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			app := &argocd.Application{
+				Spec: argocd.ApplicationSpec{
+					Source: &argocd.ApplicationSource{
+						RepoURL: "https://github.com/universe/42",
+					},
+				},
+				Status: argocd.ApplicationStatus{
+					OperationState: &argocd.OperationState{
+						Phase: argocd.OperationSucceeded,
+						Operation: argocd.Operation{
+							InitiatedBy: argocd.OperationInitiator{
+								Username: applicationOperationInitiator,
+							},
+							Info: []*argocd.Info{{
+								Name:  promotionInfoKey,
+								Value: "fake-promotion",
+							}},
+						},
+						SyncResult: &argocd.SyncOperationResult{
+							Revision: "fake-commit",
+						},
+					},
+				},
+			}
+			output := map[string]any{stateKeyCommit: "fake-commit"}
+			if testCase.trailers != "" {
+				output[stateKeyCommitTrailers] = testCase.trailers
+			}
+			phase, mustUpdate, err := runner.mustPerformUpdate(
+				&PromotionStepContext{
+					Project:     "fake-project",
+					Promotion:   "fake-promotion",
+					SharedState: State{"push": output},
+				},
+				&ArgoCDAppUpdate{
+					Sources: []ArgoCDAppSourceUpdate{{
+						RepoURL:               "https://github.com/universe/42",
+						DesiredCommitFromStep: "push",
+					}},
+				},
+				app,
+			)
+			testCase.assertions(t, phase, mustUpdate, err)
+		})
+	}
+}
+
 func Test_argoCDUpdater_syncApplication(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -1301,6 +2277,257 @@ func Test_argoCDUpdater_syncApplication(t *testing.T) {
 	}
 }
 
+func Test_argoCDUpdater_awaitSync(t *testing.T) {
+	const desiredRevision = "fake-revision"
+	newApp := func(
+		syncStatus argocd.SyncStatusCode,
+		revision string,
+		opState *argocd.OperationState,
+	) *argocd.Application {
+		return &argocd.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-name",
+				Namespace: "fake-namespace",
+			},
+			Spec: argocd.ApplicationSpec{
+				Source: &argocd.ApplicationSource{
+					RepoURL:        "https://github.com/universe/42",
+					TargetRevision: "main",
+				},
+			},
+			Status: argocd.ApplicationStatus{
+				Sync: argocd.SyncStatus{
+					Status:   syncStatus,
+					Revision: revision,
+				},
+				OperationState: opState,
+			},
+		}
+	}
+	// unchangedSources returns the current sources of the Application as the
+	// desired ones.
+	unchangedSources := func(
+		_ context.Context,
+		_ *PromotionStepContext,
+		_ *ArgoCDUpdateConfig,
+		_ *ArgoCDAppUpdate,
+		_ []string,
+		app *argocd.Application,
+	) (argocd.ApplicationSources, error) {
+		return argocd.ApplicationSources{*app.Spec.Source.DeepCopy()}, nil
+	}
+	changedSources := func(
+		_ context.Context,
+		_ *PromotionStepContext,
+		_ *ArgoCDUpdateConfig,
+		_ *ArgoCDAppUpdate,
+		_ []string,
+		app *argocd.Application,
+	) (argocd.ApplicationSources, error) {
+		src := app.Spec.Source.DeepCopy()
+		src.TargetRevision = desiredRevision
+		return argocd.ApplicationSources{*src}, nil
+	}
+
+	testCases := []struct {
+		name           string
+		trigger        SyncTrigger
+		app            *argocd.Application
+		buildSourcesFn func(
+			context.Context,
+			*PromotionStepContext,
+			*ArgoCDUpdateConfig,
+			*ArgoCDAppUpdate,
+			[]string,
+			*argocd.Application,
+		) (argocd.ApplicationSources, error)
+		expectRefresh bool
+		assertions    func(*testing.T, argocd.OperationPhase, error)
+	}{
+		{
+			name:    "synced to desired revision",
+			trigger: None,
+			app:     newApp(argocd.SyncStatusCodeSynced, desiredRevision, nil),
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationSucceeded, phase)
+			},
+		},
+		{
+			name:    "automated sync in progress",
+			trigger: RefreshOnly,
+			app: newApp(
+				argocd.SyncStatusCodeOutOfSync,
+				desiredRevision,
+				&argocd.OperationState{Phase: argocd.OperationRunning},
+			),
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationRunning, phase)
+			},
+		},
+		{
+			name:    "automated sync to desired revision failed",
+			trigger: None,
+			app: newApp(
+				argocd.SyncStatusCodeOutOfSync,
+				desiredRevision,
+				&argocd.OperationState{
+					Phase:      argocd.OperationFailed,
+					Message:    "something went wrong",
+					SyncResult: &argocd.SyncOperationResult{Revision: desiredRevision},
+				},
+			),
+			assertions: func(t *testing.T, _ argocd.OperationPhase, err error) {
+				require.True(t, isTerminal(err))
+				require.ErrorContains(t, err, "SyncFailed")
+				require.ErrorContains(t, err, "failed to sync with: something went wrong")
+			},
+		},
+		{
+			name:           "none waits for sync",
+			trigger:        None,
+			app:            newApp(argocd.SyncStatusCodeSynced, "old-revision", nil),
+			buildSourcesFn: unchangedSources,
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationRunning, phase)
+			},
+		},
+		{
+			name:           "none does not permit updating sources",
+			trigger:        None,
+			app:            newApp(argocd.SyncStatusCodeSynced, "old-revision", nil),
+			buildSourcesFn: changedSources,
+			assertions: func(t *testing.T, _ argocd.OperationPhase, err error) {
+				require.ErrorContains(t, err, "which sync trigger \"none\" does not permit")
+				require.True(t, isTerminal(err))
+			},
+		},
+		{
+			name:           "refreshOnly requests refresh",
+			trigger:        RefreshOnly,
+			app:            newApp(argocd.SyncStatusCodeSynced, "old-revision", nil),
+			buildSourcesFn: unchangedSources,
+			expectRefresh:  true,
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationRunning, phase)
+			},
+		},
+		{
+			name:    "refreshOnly does not request refresh again",
+			trigger: RefreshOnly,
+			app: func() *argocd.Application {
+				app := newApp(argocd.SyncStatusCodeSynced, "old-revision", nil)
+				app.Annotations = map[string]string{
+					argocd.AnnotationKeyRefresh: string(argocd.RefreshTypeHard),
+				}
+				return app
+			}(),
+			buildSourcesFn: unchangedSources,
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationRunning, phase)
+			},
+		},
+		{
+			name:           "refreshOnly waits for automated sync of observed revision",
+			trigger:        RefreshOnly,
+			app:            newApp(argocd.SyncStatusCodeOutOfSync, desiredRevision, nil),
+			buildSourcesFn: unchangedSources,
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationRunning, phase)
+			},
+		},
+		{
+			name:           "refreshOnly updates sources",
+			trigger:        RefreshOnly,
+			app:            newApp(argocd.SyncStatusCodeOutOfSync, desiredRevision, nil),
+			buildSourcesFn: changedSources,
+			expectRefresh:  true,
+			assertions: func(t *testing.T, phase argocd.OperationPhase, err error) {
+				require.NoError(t, err)
+				require.Equal(t, argocd.OperationRunning, phase)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var refreshed bool
+			runner := &argocdUpdater{
+				buildDesiredSourcesFn: testCase.buildSourcesFn,
+				refreshApplicationFn: func(
+					context.Context,
+					*PromotionStepContext,
+					*argocd.Application,
+					argocd.ApplicationSources,
+					argocd.RefreshType,
+				) error {
+					refreshed = true
+					return nil
+				},
+			}
+			phase, _, err := runner.awaitSync(
+				context.Background(),
+				&PromotionStepContext{},
+				&ArgoCDUpdateConfig{},
+				&ArgoCDAppUpdate{},
+				testCase.trigger,
+				[]string{desiredRevision},
+				testCase.app,
+			)
+			testCase.assertions(t, phase, err)
+			require.Equal(t, testCase.expectRefresh, refreshed)
+		})
+	}
+}
+
+func Test_argoCDUpdater_refreshApplication(t *testing.T) {
+	app := &argocd.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fake-name",
+			Namespace: "fake-namespace",
+		},
+		Spec: argocd.ApplicationSpec{
+			Source: &argocd.ApplicationSource{
+				RepoURL:        "https://github.com/universe/42",
+				TargetRevision: "main",
+			},
+		},
+	}
+	var patched bool
+	runner := &argocdUpdater{
+		argoCDAppPatchFn: func(
+			_ context.Context,
+			_ *PromotionStepContext,
+			obj kubeclient.ObjectWithKind,
+			_ kubeclient.UnstructuredPatchFn,
+		) error {
+			patched = true
+			patchedApp, ok := obj.(*argocd.Application)
+			require.True(t, ok)
+			require.Nil(t, patchedApp.Operation)
+			return nil
+		},
+	}
+	err := runner.refreshApplication(
+		context.Background(),
+		&PromotionStepContext{},
+		app,
+		argocd.ApplicationSources{{
+			RepoURL:        "https://github.com/universe/42",
+			TargetRevision: "fake-revision",
+		}},
+		argocd.RefreshTypeHard,
+	)
+	require.NoError(t, err)
+	require.True(t, patched)
+	require.Equal(t, string(argocd.RefreshTypeHard), app.Annotations[argocd.AnnotationKeyRefresh])
+	require.Equal(t, "fake-revision", app.Spec.Source.TargetRevision)
+}
+
 func Test_argoCDUpdater_logAppEvent(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -1401,6 +2628,7 @@ func Test_argoCDUpdater_getAuthorizedApplication(t *testing.T) {
 	testCases := []struct {
 		name        string
 		app         *argocd.Application
+		project     *argocd.AppProject
 		interceptor interceptor.Funcs
 		assertions  func(*testing.T, *argocd.Application, error)
 	}{
@@ -1427,6 +2655,7 @@ func Test_argoCDUpdater_getAuthorizedApplication(t *testing.T) {
 			name: "Application not found",
 			assertions: func(t *testing.T, app *argocd.Application, err error) {
 				require.ErrorContains(t, err, "unable to find Argo CD Application")
+				require.ErrorIs(t, err, errArgoCDAppNotFound)
 				require.Nil(t, app)
 			},
 		},
@@ -1443,6 +2672,53 @@ func Test_argoCDUpdater_getAuthorizedApplication(t *testing.T) {
 				require.Nil(t, app)
 			},
 		},
+		{
+			name: "AppProject not found",
+			app: &argocd.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-app",
+					Namespace: "fake-namespace",
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAuthorizedStage: "fake-namespace:fake-stage",
+					},
+				},
+				Spec: argocd.ApplicationSpec{
+					Project: "fake-project",
+				},
+			},
+			assertions: func(t *testing.T, app *argocd.Application, err error) {
+				require.ErrorContains(t, err, `unable to find Argo CD AppProject "fake-project"`)
+				require.Nil(t, app)
+			},
+		},
+		{
+			name: "AppProject does not permit Application namespace",
+			app: &argocd.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-app",
+					Namespace: "fake-namespace",
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAuthorizedStage: "fake-namespace:fake-stage",
+					},
+				},
+				Spec: argocd.ApplicationSpec{
+					Project: "fake-project",
+				},
+			},
+			project: &argocd.AppProject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-project",
+					Namespace: libargocd.Namespace(),
+				},
+				Spec: argocd.AppProjectSpec{
+					SourceNamespaces: []string{"other-*"},
+				},
+			},
+			assertions: func(t *testing.T, app *argocd.Application, err error) {
+				require.ErrorContains(t, err, "is not permitted by Argo CD AppProject")
+				require.Nil(t, app)
+			},
+		},
 		{
 			name: "success",
 			app: &argocd.Application{
@@ -1453,6 +2729,18 @@ func Test_argoCDUpdater_getAuthorizedApplication(t *testing.T) {
 						kargoapi.AnnotationKeyAuthorizedStage: "fake-namespace:fake-stage",
 					},
 				},
+				Spec: argocd.ApplicationSpec{
+					Project: "fake-project",
+				},
+			},
+			project: &argocd.AppProject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-project",
+					Namespace: libargocd.Namespace(),
+				},
+				Spec: argocd.AppProjectSpec{
+					SourceNamespaces: []string{"other-*", "fake-*"},
+				},
 			},
 			assertions: func(t *testing.T, app *argocd.Application, err error) {
 				require.NoError(t, err)
@@ -1472,6 +2760,9 @@ func Test_argoCDUpdater_getAuthorizedApplication(t *testing.T) {
 			if testCase.app != nil {
 				c.WithObjects(testCase.app)
 			}
+			if testCase.project != nil {
+				c.WithObjects(testCase.project)
+			}
 
 			app, err := runner.getAuthorizedApplication(
 				context.Background(),
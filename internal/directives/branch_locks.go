@@ -0,0 +1,214 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/akuity/kargo/internal/logging"
+)
+
+var (
+	branchLockWaitSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kargo_git_push_lock_wait_seconds",
+			Help:    "Time spent waiting for the locks on the branches a git-push step pushes to",
+			Buckets: []float64{0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+		},
+	)
+	branchLockForcedReleases = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "kargo_git_push_lock_forced_releases_total",
+			Help: "Number of locks on branches that were forcibly released because " +
+				"their holder exceeded the maximum hold time",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(branchLockWaitSeconds, branchLockForcedReleases)
+}
+
+// errBranchLeaseExpired is the cause of the cancellation of the context of a
+// branchLease that was forcibly released because its holder exceeded the
+// maximum hold time. It is also returned by branchLease.release in that case.
+var errBranchLeaseExpired = errors.New("lock on branches was forcibly released")
+
+// branchLocks serializes pushes to the same branches of the same repositories
+// within the process. Locks are held as leases: a holder that neither releases
+// its lease nor renews it with a heartbeat within the maximum hold time, e.g.
+// because it is stuck in a git command that does not return, is evicted, so
+// that it does not block all subsequent pushes to the same branches until the
+// process is restarted.
+type branchLocks struct {
+	// maxHoldTime is how long a lease may be held without a heartbeat before it
+	// is forcibly released. A value of zero means leases never expire.
+	maxHoldTime time.Duration
+
+	mu sync.Mutex
+	// sems holds a semaphore with a capacity of one for each branch key.
+	sems map[string]chan struct{}
+}
+
+// newBranchLocks returns a branchLocks whose leases are forcibly released when
+// held for longer than the provided maximum hold time without a heartbeat.
+func newBranchLocks(maxHoldTime time.Duration) *branchLocks {
+	return &branchLocks{
+		maxHoldTime: maxHoldTime,
+		sems:        map[string]chan struct{}{},
+	}
+}
+
+// branchLease is held by a holder of the locks on one or more branches.
+type branchLease struct {
+	locks  *branchLocks
+	keys   []string
+	sems   []chan struct{}
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	logger *logging.Logger
+
+	mu          sync.Mutex
+	phase       string
+	acquiredAt  time.Time
+	heartbeatAt time.Time
+	watchdog    *time.Timer
+	done        bool
+	evicted     bool
+}
+
+// acquire waits for the locks on the branches with the provided keys and
+// returns a lease on them. The lease has a context of its own, derived from the
+// provided one, that is cancelled if the lease is forcibly released. Locks are
+// always obtained in the same order to prevent deadlocks between concurrent
+// holders of overlapping sets of branches. An error is returned if the
+// provided context is cancelled before all locks were obtained.
+func (b *branchLocks) acquire(
+	ctx context.Context,
+	keys []string,
+) (*branchLease, error) {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+	sems := make([]chan struct{}, len(keys))
+	b.mu.Lock()
+	for i, key := range keys {
+		if _, exists := b.sems[key]; !exists {
+			b.sems[key] = make(chan struct{}, 1)
+		}
+		sems[i] = b.sems[key]
+	}
+	b.mu.Unlock()
+
+	start := time.Now()
+	for i, sem := range sems {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for _, acquired := range sems[:i] {
+				<-acquired
+			}
+			return nil, ctx.Err()
+		}
+	}
+	now := time.Now()
+	branchLockWaitSeconds.Observe(now.Sub(start).Seconds())
+
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	l := &branchLease{
+		locks:       b,
+		keys:        keys,
+		sems:        sems,
+		ctx:         leaseCtx,
+		cancel:      cancel,
+		logger:      logging.LoggerFromContext(ctx),
+		phase:       "acquired",
+		acquiredAt:  now,
+		heartbeatAt: now,
+	}
+	if b.maxHoldTime > 0 {
+		l.watchdog = time.AfterFunc(b.maxHoldTime, l.expire)
+	}
+	return l, nil
+}
+
+// heartbeat renews the lease and records the phase its holder is entering,
+// which is reported if the lease is forcibly released. The holder must not
+// enter the phase if an error is returned, which is the cause of the
+// cancellation of the lease's context, i.e. errBranchLeaseExpired if the lease
+// was forcibly released.
+func (l *branchLease) heartbeat(phase string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ctx.Err() != nil {
+		return context.Cause(l.ctx)
+	}
+	l.phase = phase
+	l.heartbeatAt = time.Now()
+	if l.watchdog != nil {
+		l.watchdog.Reset(l.locks.maxHoldTime)
+	}
+	return nil
+}
+
+// expire forcibly releases the lease, after cancelling the context of its
+// holder. It is called by the watchdog of a lease that was held for longer
+// than the maximum hold time without a heartbeat.
+func (l *branchLease) expire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done {
+		return
+	}
+	if since := time.Since(l.heartbeatAt); since < l.locks.maxHoldTime {
+		// A heartbeat raced with the watchdog.
+		l.watchdog.Reset(l.locks.maxHoldTime - since)
+		return
+	}
+	l.cancel(errBranchLeaseExpired)
+	l.done = true
+	l.evicted = true
+	l.unlock()
+	branchLockForcedReleases.Inc()
+	l.logger.Error(
+		errBranchLeaseExpired,
+		"holder of lock on branches appears to be stuck; lock was forcibly "+
+			"released and the holder will retry",
+		"branches", l.keys,
+		"phase", l.phase,
+		"heldFor", time.Since(l.acquiredAt).String(),
+		"sinceHeartbeat", time.Since(l.heartbeatAt).String(),
+	)
+}
+
+// release releases the lease. errBranchLeaseExpired is returned if the lease
+// had already been forcibly released, in which case the holder can not assume
+// that the branches were not changed by others while it held the lease.
+func (l *branchLease) release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.evicted {
+		return errBranchLeaseExpired
+	}
+	if l.done {
+		return nil
+	}
+	l.done = true
+	if l.watchdog != nil {
+		l.watchdog.Stop()
+	}
+	l.cancel(nil)
+	l.unlock()
+	return nil
+}
+
+// unlock releases the locks on all of the lease's branches. It must be called
+// while holding the lease's mutex.
+func (l *branchLease) unlock() {
+	for _, sem := range l.sems {
+		<-sem
+	}
+}
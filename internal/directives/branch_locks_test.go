@@ -0,0 +1,69 @@
+package directives
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchLocks(t *testing.T) {
+	t.Run("stuck holder is evicted", func(t *testing.T) {
+		locks := newBranchLocks(50 * time.Millisecond)
+		stuck, err := locks.acquire(context.Background(), []string{"repo:main"})
+		require.NoError(t, err)
+
+		// The stuck holder never sends a heartbeat, so a second holder gets
+		// the lock once the lease of the first one expired.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		next, err := locks.acquire(ctx, []string{"repo:main"})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, context.Cause(stuck.ctx), errBranchLeaseExpired)
+		require.ErrorIs(t, stuck.heartbeat("push"), errBranchLeaseExpired)
+		require.ErrorIs(t, stuck.release(), errBranchLeaseExpired)
+
+		// Releasing the evicted lease did not release the second holder's lock.
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = locks.acquire(ctx, []string{"repo:main"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.NoError(t, next.release())
+	})
+
+	t.Run("heartbeats keep lease alive", func(t *testing.T) {
+		locks := newBranchLocks(100 * time.Millisecond)
+		lease, err := locks.acquire(context.Background(), []string{"repo:main"})
+		require.NoError(t, err)
+		for range 5 {
+			time.Sleep(40 * time.Millisecond)
+			require.NoError(t, lease.heartbeat("push"))
+		}
+		require.NoError(t, lease.ctx.Err())
+		require.NoError(t, lease.release())
+	})
+
+	t.Run("overlapping branches", func(t *testing.T) {
+		locks := newBranchLocks(0)
+		lease, err := locks.acquire(context.Background(), []string{"repo:b", "repo:a"})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = locks.acquire(ctx, []string{"repo:a"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// A lock on another branch is not held.
+		other, err := locks.acquire(context.Background(), []string{"repo:c"})
+		require.NoError(t, err)
+		require.NoError(t, other.release())
+
+		require.NoError(t, lease.release())
+		lease, err = locks.acquire(context.Background(), []string{"repo:a", "repo:a"})
+		require.NoError(t, err)
+		require.NoError(t, lease.release())
+	})
+}
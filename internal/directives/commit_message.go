@@ -0,0 +1,45 @@
+package directives
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// imageUpdateCommitMessage returns a commit message describing an update of
+// the file or directory at the specified path to use the specified image
+// references. The references are deduplicated and sorted, so that the message
+// does not depend on the order in which the images were specified. At most
+// maxImages references are itemized, with any further references only being
+// counted, so that updates of very many images do not produce excessively long
+// messages. A maxImages of 0 means no limit. If no references are specified,
+// an empty string is returned.
+func imageUpdateCommitMessage(path string, imageRefs []string, maxImages int) string {
+	if len(imageRefs) == 0 {
+		return ""
+	}
+
+	refs := slices.Clone(imageRefs)
+	slices.Sort(refs)
+	refs = slices.Compact(refs)
+
+	var commitMsg strings.Builder
+	_, _ = commitMsg.WriteString(fmt.Sprintf("Updated %s to use new image", path))
+	if len(refs) > 1 {
+		_, _ = commitMsg.WriteString("s")
+	}
+	_, _ = commitMsg.WriteString("\n")
+
+	itemized := refs
+	if maxImages > 0 && len(refs) > maxImages {
+		itemized = refs[:maxImages]
+	}
+	for _, ref := range itemized {
+		_, _ = commitMsg.WriteString(fmt.Sprintf("\n- %s", ref))
+	}
+	if more := len(refs) - len(itemized); more > 0 {
+		_, _ = commitMsg.WriteString(fmt.Sprintf("\n- +%d more", more))
+	}
+
+	return commitMsg.String()
+}
@@ -0,0 +1,107 @@
+package directives
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_imageUpdateCommitMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		imageRefs  []string
+		maxImages  int
+		assertions func(*testing.T, string)
+	}{
+		{
+			name:      "no image references",
+			path:      "values.yaml",
+			imageRefs: nil,
+			assertions: func(t *testing.T, result string) {
+				assert.Empty(t, result)
+			},
+		},
+		{
+			name:      "single image reference",
+			path:      "values.yaml",
+			imageRefs: []string{"repo/image:tag1"},
+			assertions: func(t *testing.T, result string) {
+				assert.Equal(t, `Updated values.yaml to use new image
+
+- repo/image:tag1`, result)
+			},
+		},
+		{
+			name:      "unsorted image references",
+			path:      "overlays/dev",
+			imageRefs: []string{"repo2/image2:tag2", "repo1/image1:tag1"},
+			assertions: func(t *testing.T, result string) {
+				assert.Equal(t, `Updated overlays/dev to use new images
+
+- repo1/image1:tag1
+- repo2/image2:tag2`, result)
+			},
+		},
+		{
+			name: "duplicate image references",
+			path: "overlays/dev",
+			imageRefs: []string{
+				"repo1/image1:tag1",
+				"repo2/image2:tag2",
+				"repo1/image1:tag1",
+			},
+			assertions: func(t *testing.T, result string) {
+				assert.Equal(t, `Updated overlays/dev to use new images
+
+- repo1/image1:tag1
+- repo2/image2:tag2`, result)
+			},
+		},
+		{
+			name:      "only duplicates of a single image reference",
+			path:      "values.yaml",
+			imageRefs: []string{"repo/image:tag1", "repo/image:tag1"},
+			assertions: func(t *testing.T, result string) {
+				assert.Equal(t, `Updated values.yaml to use new image
+
+- repo/image:tag1`, result)
+			},
+		},
+		{
+			name:      "as many image references as the limit",
+			path:      "overlays/dev",
+			imageRefs: []string{"repo2/image2:tag2", "repo1/image1:tag1"},
+			maxImages: 2,
+			assertions: func(t *testing.T, result string) {
+				assert.Equal(t, `Updated overlays/dev to use new images
+
+- repo1/image1:tag1
+- repo2/image2:tag2`, result)
+			},
+		},
+		{
+			name: "more image references than the limit",
+			path: "overlays/dev",
+			imageRefs: []string{
+				"repo3/image3:tag3",
+				"repo2/image2:tag2",
+				"repo1/image1:tag1",
+				"repo2/image2:tag2",
+			},
+			maxImages: 2,
+			assertions: func(t *testing.T, result string) {
+				assert.Equal(t, `Updated overlays/dev to use new images
+
+- repo1/image1:tag1
+- repo2/image2:tag2
+- +1 more`, result)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(t, imageUpdateCommitMessage(tt.path, tt.imageRefs, tt.maxImages))
+		})
+	}
+}
@@ -16,6 +16,11 @@ func (e *terminalError) Error() string {
 	return e.err.Error()
 }
 
+// Unwrap returns the wrapped error.
+func (e *terminalError) Unwrap() error {
+	return e.err
+}
+
 // isTerminal returns true if the error is a terminal error or wraps one and
 // false otherwise.
 func isTerminal(err error) bool {
@@ -0,0 +1,273 @@
+package directives
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libExec "github.com/akuity/kargo/internal/exec"
+)
+
+const (
+	// execNotAllowedReason prefixes the message of an exec-render step that
+	// attempted to execute a command in a way the exec policy does not allow.
+	execNotAllowedReason = "ExecNotAllowed"
+	// execMaxStderrBytes is the number of bytes of the standard error of a
+	// command that are retained to explain why it failed.
+	execMaxStderrBytes = 16 * 1024
+	// execWaitDelay is how long to wait for the standard output and standard
+	// error of a command to be closed after it was killed.
+	execWaitDelay = 5 * time.Second
+)
+
+func init() {
+	builtins.RegisterPromotionStepRunner(newExecRenderer(), nil)
+}
+
+// execRenderer is an implementation of the PromotionStepRunner interface that
+// renders manifests by executing a command that the exec policy of the
+// KargoConfig resource allows.
+type execRenderer struct {
+	schemaLoader gojsonschema.JSONLoader
+}
+
+// newExecRenderer returns an implementation of the PromotionStepRunner
+// interface that renders manifests by executing a command that the exec
+// policy of the KargoConfig resource allows.
+func newExecRenderer() PromotionStepRunner {
+	r := &execRenderer{}
+	r.schemaLoader = getConfigSchemaLoader(r.Name())
+	return r
+}
+
+// Name implements the PromotionStepRunner interface.
+func (e *execRenderer) Name() string {
+	return "exec-render"
+}
+
+// RunPromotionStep implements the PromotionStepRunner interface.
+func (e *execRenderer) RunPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+) (PromotionStepResult, error) {
+	// Validate the configuration against the JSON Schema.
+	if err := validate(e.schemaLoader, gojsonschema.NewGoLoader(stepCtx.Config), e.Name()); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Convert the configuration into a typed object.
+	cfg, err := ConfigToStruct[ExecRenderConfig](stepCtx.Config)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not convert config into %s config: %w", e.Name(), err)
+	}
+
+	return e.runPromotionStep(ctx, stepCtx, cfg)
+}
+
+// execRenderInput is the JSON document that is passed to a command on its
+// standard input.
+type execRenderInput struct {
+	Project   string                     `json:"project"`
+	Stage     string                     `json:"stage"`
+	Promotion string                     `json:"promotion"`
+	Freight   kargoapi.FreightCollection `json:"freight"`
+	Input     map[string]any             `json:"input,omitempty"`
+}
+
+func (e *execRenderer) runPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg ExecRenderConfig,
+) (PromotionStepResult, error) {
+	command := stepCtx.ExecPolicy.GetCommand(cfg.Command)
+	if command == nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"%s: command %q is not allowed by the exec policy",
+				execNotAllowedReason, cfg.Command,
+			)}
+	}
+	if len(cfg.Args) > 0 && !command.AllowArgs {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"%s: the exec policy does not allow arguments to be passed to command %q",
+				execNotAllowedReason, cfg.Command,
+			)}
+	}
+
+	dir, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.Path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not secure join path %q: %w", cfg.Path, err)
+	}
+	outPath, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.OutPath)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not secure join outPath %q: %w", cfg.OutPath, err)
+	}
+	input, err := json.Marshal(execRenderInput{
+		Project:   stepCtx.Project,
+		Stage:     stepCtx.Stage,
+		Promotion: stepCtx.Promotion,
+		Freight:   stepCtx.Freight,
+		Input:     cfg.Input,
+	})
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error marshaling input of command %q: %w", cfg.Command, err)
+	}
+
+	rendered, err := e.execute(ctx, command, append(slices.Clone(command.Args), cfg.Args...), dir, input)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if err = validateRenderedManifests(rendered); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+			err: fmt.Errorf("command %q did not write valid YAML to its standard output: %w", cfg.Command, err),
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(outPath), 0o700); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error creating directory for %q: %w", cfg.OutPath, err)
+	}
+	if err = os.WriteFile(outPath, rendered, 0o600); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error writing rendered manifests to %q: %w", cfg.OutPath, err)
+	}
+	return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+}
+
+// execute executes the provided command with the provided arguments in the
+// provided directory, passing the provided input on its standard input, and
+// returns what it wrote to its standard output. The command's process sees
+// only the environment variables specified by the exec policy, and it is
+// killed if it exceeds the time or output limits of the policy.
+func (e *execRenderer) execute(
+	ctx context.Context,
+	command *kargoapi.ExecCommand,
+	args []string,
+	dir string,
+	input []byte,
+) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, command.GetTimeout())
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: command.GetMaxOutputBytes(), onExceeded: cancel}
+	stderr := &tailBuffer{limit: execMaxStderrBytes}
+	cmd := exec.CommandContext(ctx, command.Path, args...) // nolint: gosec
+	cmd.Dir = dir
+	// A non-nil, empty environment prevents the process from inheriting the
+	// controller's environment.
+	cmd.Env = make([]string, 0, len(command.Env))
+	for _, env := range command.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = execWaitDelay
+
+	startedAt := time.Now()
+	err := cmd.Run()
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+	libExec.RecordCommand(cmd, startedAt, stderr.Bytes(), exitCode)
+
+	switch {
+	case stdout.exceeded:
+		return nil, fmt.Errorf(
+			"command %q wrote more than %d bytes to its standard output",
+			command.Name, command.GetMaxOutputBytes(),
+		)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf(
+			"command %q did not complete within %s", command.Name, command.GetTimeout(),
+		)
+	case err != nil:
+		return nil, fmt.Errorf("error executing command %q: %w: %s", command.Name, err, stderr.Bytes())
+	}
+	return stdout.Bytes(), nil
+}
+
+// validateRenderedManifests returns an error if the provided manifests are not
+// a valid stream of YAML documents.
+func validateRenderedManifests(manifests []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var doc any
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// limitedBuffer is an io.Writer that retains up to a limited number of bytes
+// and calls a function once more bytes than that are written to it.
+type limitedBuffer struct {
+	buf        bytes.Buffer
+	limit      int
+	exceeded   bool
+	onExceeded func()
+}
+
+// Write implements the io.Writer interface.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > b.limit {
+		if !b.exceeded {
+			b.exceeded = true
+			b.onExceeded()
+		}
+		return 0, errors.New("output limit exceeded")
+	}
+	return b.buf.Write(p)
+}
+
+// Bytes returns the bytes retained by the limitedBuffer.
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// tailBuffer is an io.Writer that retains the last bytes written to it, up to
+// a limited number of bytes.
+type tailBuffer struct {
+	buf   []byte
+	limit int
+}
+
+// Write implements the io.Writer interface.
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+// Bytes returns the bytes retained by the tailBuffer.
+func (b *tailBuffer) Bytes() []byte {
+	return b.buf
+}
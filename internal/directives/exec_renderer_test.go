@@ -0,0 +1,179 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_execRenderer_runPromotionStep(t *testing.T) {
+	shellCommand := func(name, script string) kargoapi.ExecCommand {
+		return kargoapi.ExecCommand{
+			Name: name,
+			Path: "/bin/sh",
+			Args: []string{"-c", script},
+		}
+	}
+	policy := &kargoapi.ExecPolicy{
+		Commands: []kargoapi.ExecCommand{
+			{
+				Name: "render",
+				Path: "/bin/sh",
+				// The input is echoed as YAML, which JSON is a subset of, along
+				// with the environment and the arguments.
+				Args: []string{
+					"-c",
+					`cat; echo; echo "---"; echo "env: '$(env | grep -v '^PWD=' | tr '\n' ' ')'"; ` +
+						`echo "args: '$*'"`,
+					"render",
+				},
+				AllowArgs: true,
+				Env:       []kargoapi.ExecEnvVar{{Name: "RENDER_MODE", Value: "strict"}},
+			},
+			shellCommand("no-args", "echo 'kind: ConfigMap'"),
+			shellCommand("invalid-yaml", "echo '{'"),
+			shellCommand("fail", "echo 'something went wrong' >&2; exit 2"),
+			func() kargoapi.ExecCommand {
+				c := shellCommand("slow", "exec sleep 5")
+				c.Timeout = &metav1.Duration{Duration: 100 * time.Millisecond}
+				return c
+			}(),
+			func() kargoapi.ExecCommand {
+				c := shellCommand("verbose", "exec yes 'kind: ConfigMap'")
+				c.MaxOutputBytes = ptr.To[int32](1024)
+				return c
+			}(),
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		policy     *kargoapi.ExecPolicy
+		cfg        ExecRenderConfig
+		assertions func(*testing.T, string, PromotionStepResult, error)
+	}{
+		{
+			name: "no exec policy",
+			cfg:  ExecRenderConfig{Command: "render", OutPath: "out.yaml"},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `ExecNotAllowed: command "render" is not allowed`)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name:   "command not allowed",
+			policy: policy,
+			cfg:    ExecRenderConfig{Command: "other", OutPath: "out.yaml"},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `ExecNotAllowed: command "other" is not allowed`)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name:   "arguments not allowed",
+			policy: policy,
+			cfg: ExecRenderConfig{
+				Command: "no-args",
+				Args:    []string{"--debug"},
+				OutPath: "out.yaml",
+			},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "does not allow arguments")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name:   "success",
+			policy: policy,
+			cfg: ExecRenderConfig{
+				Command: "render",
+				Args:    []string{"--region", "eu-west"},
+				OutPath: "rendered/out.yaml",
+				Input:   map[string]any{"replicas": float64(3)},
+			},
+			assertions: func(t *testing.T, workDir string, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				out, err := os.ReadFile(filepath.Join(workDir, "rendered", "out.yaml"))
+				require.NoError(t, err)
+				require.Contains(t, string(out), `"project":"fake-project"`)
+				require.Contains(t, string(out), `"stage":"fake-stage"`)
+				require.Contains(t, string(out), `"input":{"replicas":3}`)
+				// Only the environment of the exec policy is passed to the
+				// command.
+				require.Contains(t, string(out), "env: 'RENDER_MODE=strict '")
+				require.Contains(t, string(out), "args: '--region eu-west'")
+			},
+		},
+		{
+			name:   "invalid YAML",
+			policy: policy,
+			cfg:    ExecRenderConfig{Command: "invalid-yaml", OutPath: "out.yaml"},
+			assertions: func(t *testing.T, workDir string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "did not write valid YAML")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.NoFileExists(t, filepath.Join(workDir, "out.yaml"))
+			},
+		},
+		{
+			name:   "command fails",
+			policy: policy,
+			cfg:    ExecRenderConfig{Command: "fail", OutPath: "out.yaml"},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.False(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+			},
+		},
+		{
+			name:   "timeout exceeded",
+			policy: policy,
+			cfg:    ExecRenderConfig{Command: "slow", OutPath: "out.yaml"},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `command "slow" did not complete within 100ms`)
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+			},
+		},
+		{
+			name:   "output limit exceeded",
+			policy: policy,
+			cfg:    ExecRenderConfig{Command: "verbose", OutPath: "out.yaml"},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "wrote more than 1024 bytes")
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+			},
+		},
+	}
+
+	runner, ok := newExecRenderer().(*execRenderer)
+	require.True(t, ok)
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			res, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{
+					Project:    "fake-project",
+					Stage:      "fake-stage",
+					Promotion:  "fake-promotion",
+					WorkDir:    workDir,
+					ExecPolicy: testCase.policy,
+				},
+				testCase.cfg,
+			)
+			testCase.assertions(t, workDir, res, err)
+		})
+	}
+}
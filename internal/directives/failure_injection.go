@@ -0,0 +1,164 @@
+package directives
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// ErrInjectedFailure is the error that is returned by PromotionSteps at which
+// a failure was injected.
+var ErrInjectedFailure = errors.New("InjectedFailure")
+
+// FailurePoint is a phase of a promotion at which a failure can be injected.
+type FailurePoint string
+
+const (
+	// FailurePointClone fails the cloning of Git repositories.
+	FailurePointClone FailurePoint = "clone"
+	// FailurePointEdit fails the editing of manifests.
+	FailurePointEdit FailurePoint = "edit"
+	// FailurePointBuild fails the rendering of manifests.
+	FailurePointBuild FailurePoint = "build"
+	// FailurePointSourcePush fails pushes to any branch other than the rendered
+	// branch, after the push was made.
+	FailurePointSourcePush FailurePoint = "source-push"
+	// FailurePointRenderedPush fails pushes to the rendered branch, after the
+	// push was made.
+	FailurePointRenderedPush FailurePoint = "rendered-push"
+	// FailurePointAppPatch fails the updating of Argo CD Applications.
+	FailurePointAppPatch FailurePoint = "app-patch"
+	// FailurePointSyncWait fails the waiting for Argo CD Applications to be
+	// synced, after they were updated.
+	FailurePointSyncWait FailurePoint = "sync-wait"
+)
+
+// failurePointsBeforeStep maps the kinds of PromotionSteps to the point at
+// which a failure is injected before they are run.
+var failurePointsBeforeStep = map[string]FailurePoint{
+	"git-clone":                  FailurePointClone,
+	"kustomize-set-image":        FailurePointEdit,
+	"yaml-update":                FailurePointEdit,
+	"json-update":                FailurePointEdit,
+	"helm-update-image":          FailurePointEdit,
+	"helm-update-chart":          FailurePointEdit,
+	"set-metadata":               FailurePointEdit,
+	"kustomize-build":            FailurePointBuild,
+	"kustomize-promote-overlays": FailurePointBuild,
+	"helm-template":              FailurePointBuild,
+	"argocd-update":              FailurePointAppPatch,
+}
+
+// FailureInjection describes a synthetic failure that is injected into the
+// execution of a promotion for the purpose of testing how failures are
+// handled. It must never be honored outside of test environments.
+type FailureInjection struct {
+	// Point is the phase of the promotion at which the failure is injected.
+	Point FailurePoint
+	// Terminal indicates that the failure is unrecoverable. Otherwise, it is
+	// transient and the affected PromotionStep is retried.
+	Terminal bool
+	// Times is the number of consecutive attempts of the affected
+	// PromotionStep that fail, after which it is allowed to proceed. A value
+	// of 0 means that every attempt fails.
+	Times uint32
+}
+
+// ParseFailureInjection parses a FailureInjection from the value of the
+// kargo.akuity.io/fail-at annotation, which takes the form
+// <point>[:<mode>[:<times>]], where mode is either "transient" (the default)
+// or "terminal".
+func ParseFailureInjection(value string) (*FailureInjection, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid failure injection %q", value)
+	}
+	f := &FailureInjection{Point: FailurePoint(parts[0])}
+	switch f.Point {
+	case FailurePointClone, FailurePointEdit, FailurePointBuild,
+		FailurePointSourcePush, FailurePointRenderedPush,
+		FailurePointAppPatch, FailurePointSyncWait:
+	default:
+		return nil, fmt.Errorf("unknown failure injection point %q", parts[0])
+	}
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "transient":
+		case "terminal":
+			f.Terminal = true
+		default:
+			return nil, fmt.Errorf("unknown failure injection mode %q", parts[1])
+		}
+	}
+	if len(parts) > 2 {
+		times, err := strconv.ParseUint(parts[2], 10, 32)
+		if err != nil || times < 1 {
+			return nil, fmt.Errorf("invalid failure injection count %q", parts[2])
+		}
+		f.Times = uint32(times)
+	}
+	return f, nil
+}
+
+// beforeStep returns true if a failure is to be injected before a
+// PromotionStep of the provided kind is run, given the number of consecutive
+// attempts of it that already errored.
+func (f *FailureInjection) beforeStep(kind string, errorCount uint32) bool {
+	if !f.applies(errorCount) {
+		return false
+	}
+	return failurePointsBeforeStep[kind] == f.Point
+}
+
+// afterStep returns true if a failure is to be injected after a PromotionStep
+// of the provided kind was run with the provided result, given the number of
+// consecutive attempts of it that already errored. Failures are only injected
+// after steps that did not fail on their own.
+func (f *FailureInjection) afterStep(
+	kind string,
+	result PromotionStepResult,
+	errorCount uint32,
+) bool {
+	if !f.applies(errorCount) {
+		return false
+	}
+	switch result.Status {
+	case kargoapi.PromotionPhaseRunning, kargoapi.PromotionPhaseSucceeded:
+	default:
+		return false
+	}
+	switch kind {
+	case "git-push":
+		if result.RenderedBranchPush != nil {
+			return f.Point == FailurePointRenderedPush
+		}
+		return f.Point == FailurePointSourcePush
+	case "argocd-update":
+		return f.Point == FailurePointSyncWait
+	}
+	return false
+}
+
+// applies returns true if the failure is to be injected into an attempt of a
+// PromotionStep, given the number of consecutive attempts of it that already
+// errored.
+func (f *FailureInjection) applies(errorCount uint32) bool {
+	return f != nil && (f.Times == 0 || errorCount < f.Times)
+}
+
+// fail returns the provided result and an error as a PromotionStep would if it
+// failed at the point of the injected failure.
+func (f *FailureInjection) fail(
+	result PromotionStepResult,
+) (PromotionStepResult, error) {
+	err := fmt.Errorf("%w at %s", ErrInjectedFailure, f.Point)
+	if f.Terminal {
+		result.Status = kargoapi.PromotionPhaseFailed
+		return result, &terminalError{err: err}
+	}
+	result.Status = kargoapi.PromotionPhaseErrored
+	return result, err
+}
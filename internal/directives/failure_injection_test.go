@@ -0,0 +1,256 @@
+package directives
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestParseFailureInjection(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    *FailureInjection
+		expectedErr string
+	}{
+		{
+			value:    "rendered-push",
+			expected: &FailureInjection{Point: FailurePointRenderedPush},
+		},
+		{
+			value:    "clone:transient",
+			expected: &FailureInjection{Point: FailurePointClone},
+		},
+		{
+			value:    "sync-wait:terminal",
+			expected: &FailureInjection{Point: FailurePointSyncWait, Terminal: true},
+		},
+		{
+			value:    "build:transient:2",
+			expected: &FailureInjection{Point: FailurePointBuild, Times: 2},
+		},
+		{
+			value:       "deploy",
+			expectedErr: "unknown failure injection point",
+		},
+		{
+			value:       "edit:fatal",
+			expectedErr: "unknown failure injection mode",
+		},
+		{
+			value:       "edit:transient:0",
+			expectedErr: "invalid failure injection count",
+		},
+		{
+			value:       "edit:transient:1:2",
+			expectedErr: "invalid failure injection",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			f, err := ParseFailureInjection(testCase.value)
+			if testCase.expectedErr != "" {
+				require.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, f)
+		})
+	}
+}
+
+func TestSimpleEngine_executeSteps_failureInjection(t *testing.T) {
+	renderedPush := &kargoapi.RenderedBranchPush{
+		RepoURL:        "https://github.com/example/repo",
+		Branch:         "rendered/dev",
+		PreviousCommit: "abc123",
+		Commit:         "def456",
+	}
+	testCases := []struct {
+		name       string
+		promoCtx   PromotionContext
+		steps      []PromotionStep
+		assertions func(*testing.T, PromotionResult, error, map[string]int)
+	}{
+		{
+			name: "transient failure is retried",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointClone},
+			},
+			steps: []PromotionStep{{
+				Kind:  "git-clone",
+				Retry: &kargoapi.PromotionStepRetry{ErrorThreshold: 3},
+			}},
+			assertions: func(t *testing.T, res PromotionResult, err error, runs map[string]int) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.StepExecutionMetadata[0].Status)
+				require.Equal(t, uint32(1), res.StepExecutionMetadata[0].ErrorCount)
+				require.Contains(t, res.StepExecutionMetadata[0].Message, "InjectedFailure at clone")
+				require.Contains(t, res.StepExecutionMetadata[0].Message, "will be retried")
+				require.Zero(t, runs["git-clone"])
+			},
+		},
+		{
+			name: "transient failure meets error threshold",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointEdit},
+			},
+			steps: []PromotionStep{{Kind: "yaml-update"}},
+			assertions: func(t *testing.T, res PromotionResult, err error, _ map[string]int) {
+				require.ErrorContains(t, err, "met error threshold")
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+			},
+		},
+		{
+			name: "terminal failure is not retried",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointClone, Terminal: true},
+			},
+			steps: []PromotionStep{{
+				Kind:  "git-clone",
+				Retry: &kargoapi.PromotionStepRetry{ErrorThreshold: 3},
+			}},
+			assertions: func(t *testing.T, res PromotionResult, err error, _ map[string]int) {
+				require.ErrorContains(t, err, "an unrecoverable error occurred")
+				require.ErrorIs(t, err, ErrInjectedFailure)
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.Zero(t, res.StepExecutionMetadata[0].ErrorCount)
+			},
+		},
+		{
+			name: "step proceeds once failure was injected enough times",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointClone, Times: 2},
+				StepExecutionMetadata: kargoapi.StepExecutionMetadataList{{
+					Alias:      "step-0",
+					Status:     kargoapi.PromotionPhaseErrored,
+					ErrorCount: 2,
+				}},
+			},
+			steps: []PromotionStep{{
+				Kind:  "git-clone",
+				Retry: &kargoapi.PromotionStepRetry{ErrorThreshold: 3},
+			}},
+			assertions: func(t *testing.T, res PromotionResult, err error, runs map[string]int) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Equal(t, 1, runs["git-clone"])
+			},
+		},
+		{
+			name: "rendered push is recorded despite failure",
+			promoCtx: PromotionContext{
+				RenderedBranch:   "rendered/dev",
+				FailureInjection: &FailureInjection{Point: FailurePointRenderedPush, Terminal: true},
+			},
+			steps: []PromotionStep{
+				{Kind: "git-clone"},
+				{Kind: "git-push", Config: []byte(`{"targetBranch":"rendered/dev"}`)},
+			},
+			assertions: func(t *testing.T, res PromotionResult, err error, runs map[string]int) {
+				require.ErrorIs(t, err, ErrInjectedFailure)
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.Equal(t, int64(1), res.CurrentStep)
+				require.Equal(t, 1, runs["git-push"])
+				require.Equal(t, renderedPush, res.RenderedBranchPush)
+			},
+		},
+		{
+			name: "rendered push failure not injected into source push",
+			promoCtx: PromotionContext{
+				RenderedBranch:   "rendered/dev",
+				FailureInjection: &FailureInjection{Point: FailurePointRenderedPush},
+			},
+			steps: []PromotionStep{
+				{Kind: "git-push", Config: []byte(`{"targetBranch":"main"}`)},
+			},
+			assertions: func(t *testing.T, res PromotionResult, err error, _ map[string]int) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+			},
+		},
+		{
+			name: "source push failure",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointSourcePush, Terminal: true},
+			},
+			steps: []PromotionStep{
+				{Kind: "git-push", Config: []byte(`{"targetBranch":"main"}`)},
+			},
+			assertions: func(t *testing.T, res PromotionResult, err error, runs map[string]int) {
+				require.ErrorIs(t, err, ErrInjectedFailure)
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.Equal(t, 1, runs["git-push"])
+			},
+		},
+		{
+			name: "app patch failure",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointAppPatch, Terminal: true},
+			},
+			steps: []PromotionStep{{Kind: "argocd-update"}},
+			assertions: func(t *testing.T, res PromotionResult, err error, runs map[string]int) {
+				require.ErrorContains(t, err, "InjectedFailure at app-patch")
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				require.Zero(t, runs["argocd-update"])
+			},
+		},
+		{
+			name: "sync wait failure",
+			promoCtx: PromotionContext{
+				FailureInjection: &FailureInjection{Point: FailurePointSyncWait},
+			},
+			steps: []PromotionStep{{
+				Kind:  "argocd-update",
+				Retry: &kargoapi.PromotionStepRetry{ErrorThreshold: 3},
+			}},
+			assertions: func(t *testing.T, res PromotionResult, err error, runs map[string]int) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+				require.Contains(t, res.StepExecutionMetadata[0].Message, "InjectedFailure at sync-wait")
+				require.Equal(t, uint32(1), res.StepExecutionMetadata[0].ErrorCount)
+				require.Equal(t, 1, runs["argocd-update"])
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			runs := map[string]int{}
+			testRegistry := NewStepRunnerRegistry()
+			for _, name := range []string{"git-clone", "yaml-update", "git-push", "argocd-update"} {
+				testRegistry.RegisterPromotionStepRunner(
+					&mockPromotionStepRunner{
+						name: name,
+						runFunc: func(_ context.Context, stepCtx *PromotionStepContext) (PromotionStepResult, error) {
+							runs[name]++
+							res := PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}
+							switch name {
+							case "git-push":
+								if stepCtx.Config["targetBranch"] == stepCtx.RenderedBranch {
+									res.RenderedBranchPush = renderedPush
+								}
+							case "argocd-update":
+								// Waiting for the Applications to be synced
+								res.Status = kargoapi.PromotionPhaseRunning
+							}
+							return res, nil
+						},
+					},
+					&StepRunnerPermissions{},
+				)
+			}
+			engine := &SimpleEngine{
+				registry:    testRegistry,
+				kargoClient: fake.NewClientBuilder().Build(),
+			}
+			res, err := engine.executeSteps(
+				context.Background(), testCase.promoCtx, testCase.steps, t.TempDir(),
+			)
+			testCase.assertions(t, res, err, runs)
+		})
+	}
+}
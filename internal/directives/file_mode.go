@@ -0,0 +1,25 @@
+package directives
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// defaultOutFileMode is the mode of files written by steps that render
+// manifests when no mode has been specified.
+const defaultOutFileMode os.FileMode = 0o600
+
+// parseOutFileMode parses the provided octal representation of a file mode
+// (e.g. "0644"). If the provided string is empty, defaultOutFileMode is
+// returned.
+func parseOutFileMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return defaultOutFileMode, nil
+	}
+	m, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || m > uint64(os.ModePerm) {
+		return 0, fmt.Errorf("invalid file mode %q", mode)
+	}
+	return os.FileMode(m), nil
+}
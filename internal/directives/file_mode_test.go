@@ -0,0 +1,55 @@
+package directives
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseOutFileMode(t *testing.T) {
+	testCases := []struct {
+		mode       string
+		assertions func(*testing.T, os.FileMode, error)
+	}{
+		{
+			mode: "",
+			assertions: func(t *testing.T, m os.FileMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, defaultOutFileMode, m)
+			},
+		},
+		{
+			mode: "0644",
+			assertions: func(t *testing.T, m os.FileMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0o644), m)
+			},
+		},
+		{
+			mode: "755",
+			assertions: func(t *testing.T, m os.FileMode, err error) {
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0o755), m)
+			},
+		},
+		{
+			mode: "0888",
+			assertions: func(t *testing.T, _ os.FileMode, err error) {
+				require.ErrorContains(t, err, "invalid file mode")
+			},
+		},
+		{
+			mode: "4755",
+			assertions: func(t *testing.T, _ os.FileMode, err error) {
+				require.ErrorContains(t, err, "invalid file mode")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.mode, func(t *testing.T) {
+			m, err := parseOutFileMode(testCase.mode)
+			testCase.assertions(t, m, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package directives
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+const (
+	// stateKeyCheckouts is the key of the output of the git-clone step under
+	// which it records the branch it checked out at each path, keyed by the
+	// cleaned path. A path at which a commit, tag, or the remote's default
+	// branch was checked out without a local branch maps to an empty string.
+	stateKeyCheckouts = "checkouts"
+
+	// branchMismatchReason prefixes the message of a step that refused to
+	// commit because a working tree did not have the branch checked out that
+	// it was cloned with.
+	branchMismatchReason = "BranchMismatch"
+)
+
+// isRenderedBranch returns true if manifests rendered for the Stage the
+// provided PromotionStepContext belongs to are written to the provided branch,
+// i.e. if it is the Stage's rendered branch or the branch of one of its
+// overlays.
+func isRenderedBranch(stepCtx *PromotionStepContext, branch string) bool {
+	if branch == "" {
+		return false
+	}
+	if branch == stepCtx.RenderedBranch {
+		return true
+	}
+	for _, overlay := range stepCtx.Overlays {
+		if branch == overlay.RenderedBranch {
+			return true
+		}
+	}
+	return false
+}
+
+// clonedBranch returns the branch that a previous git-clone step recorded
+// checking out at the provided path in the provided State, and whether any
+// such record was found. An empty branch means that no local branch was
+// checked out.
+func clonedBranch(state State, path string) (string, bool) {
+	path = filepath.Clean(path)
+	for _, output := range state {
+		stepOutput, ok := output.(map[string]any)
+		if !ok {
+			continue
+		}
+		checkouts, ok := stepOutput[stateKeyCheckouts].(map[string]any)
+		if !ok {
+			continue
+		}
+		if branch, ok := checkouts[path].(string); ok {
+			return branch, true
+		}
+	}
+	return "", false
+}
+
+// verifyClonedBranch returns a terminal error if the branch checked out in the
+// provided working tree at the provided path differs from the one a previous
+// git-clone step recorded checking out there. This guards against committing
+// changes to a branch they were never meant for, e.g. to the Stage's rendered
+// branch. If no git-clone step recorded checking out the path, nil is
+// returned.
+func verifyClonedBranch(state State, path string, workTree git.WorkTree) error {
+	expected, ok := clonedBranch(state, path)
+	if !ok {
+		return nil
+	}
+	current, err := workTree.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error determining branch checked out in working tree %s: %w", path, err)
+	}
+	if current == expected {
+		return nil
+	}
+	return &terminalError{err: fmt.Errorf(
+		"%s: working tree %s was cloned with %s checked out, but %s is checked out now; "+
+			"refusing to commit",
+		branchMismatchReason, path, describeBranch(expected), describeBranch(current),
+	)}
+}
+
+// describeBranch returns a human-readable description of the provided branch,
+// where an empty branch means that no local branch is checked out.
+func describeBranch(branch string) string {
+	if branch == "" {
+		return "no branch"
+	}
+	return fmt.Sprintf("branch %q", branch)
+}
@@ -0,0 +1,113 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+// Test_renderedBranchIsDefaultBranch reproduces a repository whose default
+// branch is the branch that manifests rendered for the Stage are written to.
+// Neither cloning nor committing may end up modifying that branch with
+// changes meant for the source of the manifests.
+func Test_renderedBranchIsDefaultBranch(t *testing.T) {
+	runGit := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// Set up a local bare repository with a source branch and a rendered
+	// branch, the latter of which is the repository's default branch.
+	remoteDir := t.TempDir()
+	runGit(remoteDir, "init", "--bare", "--initial-branch=main")
+	setupDir := t.TempDir()
+	runGit(setupDir, "clone", remoteDir, ".")
+	runGit(setupDir, "config", "user.name", "Kargo")
+	runGit(setupDir, "config", "user.email", "kargo@example.com")
+	require.NoError(t, os.WriteFile(filepath.Join(setupDir, "kustomization.yaml"), []byte("resources: []\n"), 0o600))
+	runGit(setupDir, "add", ".")
+	runGit(setupDir, "commit", "-m", "Initial commit")
+	runGit(setupDir, "push", "origin", "HEAD:main", "HEAD:rendered/dev")
+	runGit(remoteDir, "symbolic-ref", "HEAD", "refs/heads/rendered/dev")
+
+	cloner, ok := newGitCloner().(*gitCloner)
+	require.True(t, ok)
+	committer, ok := newGitCommitter().(*gitCommitter)
+	require.True(t, ok)
+	stepCtx := &PromotionStepContext{
+		CredentialsDB:  &credentials.FakeDB{},
+		WorkDir:        t.TempDir(),
+		RenderedBranch: "rendered/dev",
+	}
+
+	// Checking out the remote's default branch is refused.
+	res, err := cloner.runPromotionStep(context.Background(), stepCtx, GitCloneConfig{
+		RepoURL:        remoteDir,
+		InsecureNoAuth: true,
+		Checkout:       []Checkout{{Path: "src"}},
+	})
+	require.ErrorContains(t, err, `the default branch "rendered/dev" of repo`)
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+
+	// Checking out each branch explicitly records which branch was checked
+	// out at each path.
+	stepCtx.WorkDir = t.TempDir()
+	res, err = cloner.runPromotionStep(context.Background(), stepCtx, GitCloneConfig{
+		RepoURL:        remoteDir,
+		InsecureNoAuth: true,
+		Checkout: []Checkout{
+			{Branch: "main", Path: "./src"},
+			{Branch: "rendered/dev", Path: "./out"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+	require.Equal(
+		t,
+		map[string]any{"src": "main", "out": "rendered/dev"},
+		res.Output[stateKeyCheckouts],
+	)
+	stepCtx.SharedState = State{"step-1": res.Output}
+
+	// Should a different branch be checked out by the time changes are
+	// committed, the commit is refused.
+	srcDir := filepath.Join(stepCtx.WorkDir, "src")
+	runGit(srcDir, "checkout", "--detach")
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "kustomization.yaml"), []byte("resources: [a]\n"), 0o600))
+	res, err = committer.runPromotionStep(context.Background(), stepCtx, GitCommitConfig{
+		Path:    "src",
+		Message: "Update images",
+	})
+	require.ErrorContains(
+		t, err,
+		`BranchMismatch: working tree src was cloned with branch "main" checked out, but no branch is checked out now`,
+	)
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+
+	// Committing to the rendered branch it was cloned with is fine.
+	outDir := filepath.Join(stepCtx.WorkDir, "out")
+	require.NoError(t, os.WriteFile(filepath.Join(outDir, "manifests.yaml"), []byte("kind: List\n"), 0o600))
+	res, err = committer.runPromotionStep(context.Background(), stepCtx, GitCommitConfig{
+		Path:    "out",
+		Message: "Render manifests",
+	})
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+	workTree, err := git.LoadWorkTree(outDir, nil)
+	require.NoError(t, err)
+	branch, err := workTree.CurrentBranch()
+	require.NoError(t, err)
+	require.Equal(t, "rendered/dev", branch)
+}
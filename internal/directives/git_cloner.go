@@ -2,19 +2,38 @@ package directives
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/patrickmn/go-cache"
 	"github.com/xeipuuv/gojsonschema"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/freight"
 	"github.com/akuity/kargo/internal/controller/git"
 	"github.com/akuity/kargo/internal/credentials"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/gitprovider"
+	"github.com/akuity/kargo/internal/logging"
 )
 
+// pushAccessCacheTTL is how long the outcome of verifying push access to a
+// branch is remembered. This spares Promotions to many Stages that share a
+// repository from verifying the same thing over and over again.
+const pushAccessCacheTTL = 2 * time.Minute
+
+// stateKeyAuthMethod is the key used to store the means by which the
+// repository was authenticated to when it was cloned in the shared State.
+const stateKeyAuthMethod = "authMethod"
+
 func init() {
 	builtins.RegisterPromotionStepRunner(
 		newGitCloner(),
@@ -31,6 +50,21 @@ func init() {
 type gitCloner struct {
 	gitUser      git.User
 	schemaLoader gojsonschema.JSONLoader
+	// pushAccessCache holds the outcomes of conclusive push access checks,
+	// keyed by Project, credentials Secret of the Stage's GitIdentity,
+	// repository URL, and branch.
+	pushAccessCache   *cache.Cache
+	checkPushAccessFn func(
+		ctx context.Context,
+		cfg GitCloneConfig,
+		branch string,
+		creds *git.RepoCredentials,
+	) error
+	getRepoSizeFn func(
+		ctx context.Context,
+		cfg GitCloneConfig,
+		creds *git.RepoCredentials,
+	) (int64, error)
 }
 
 // gitUserFromEnv populates a git.User struct from environment variables.
@@ -55,7 +89,10 @@ func gitUserFromEnv() git.User {
 // working directories.
 func newGitCloner() PromotionStepRunner {
 	r := &gitCloner{
-		gitUser: gitUserFromEnv(),
+		gitUser:           gitUserFromEnv(),
+		pushAccessCache:   cache.New(pushAccessCacheTTL, 2*pushAccessCacheTTL),
+		checkPushAccessFn: checkPushAccess,
+		getRepoSizeFn:     getRepoSize,
 	}
 	r.schemaLoader = getConfigSchemaLoader(r.Name())
 	return r
@@ -103,46 +140,133 @@ func (g *gitCloner) runPromotionStep(
 	}
 
 	var repoCreds *git.RepoCredentials
-	creds, found, err := stepCtx.CredentialsDB.Get(
-		ctx,
-		stepCtx.Project,
-		credentials.TypeGit,
-		cfg.RepoURL,
-	)
-	if err != nil {
-		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
-			fmt.Errorf("error getting credentials for %s: %w", cfg.RepoURL, err)
+	if !cfg.InsecureNoAuth {
+		if repoCreds, err = getGitCredentials(ctx, stepCtx, cfg.RepoURL); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+	}
+	// The repository is configured to commit as the Stage's identity, if it
+	// specifies one, rather than as the controller's default user.
+	user := gitIdentityUser(stepCtx, g.gitUser)
+	clientOpts := &git.ClientOptions{
+		User:                  &user,
+		Credentials:           repoCreds,
+		InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
+		InsecureNoAuth:        cfg.InsecureNoAuth,
 	}
-	if found {
-		repoCreds = &git.RepoCredentials{
-			Username:      creds.Username,
-			Password:      creds.Password,
-			SSHPrivateKey: creds.SSHPrivateKey,
+	// Branches that are not to be created are looked for before anything is
+	// cloned, so that a misconfigured branch fails the step right away rather
+	// than after a potentially lengthy clone. Branches whose updates are
+	// batched are skipped, as the rolling branch may be checked out instead.
+	// The same listing of the remote's branches later determines the default
+	// branch, so the remote is asked only once.
+	remoteHeads := listRemoteHeads(ctx, stepCtx, cfg, clientOpts)
+	for _, checkout := range cfg.Checkout {
+		if checkout.Branch == "" || checkout.Create ||
+			sourceUpdateBatching(stepCtx, cfg.RepoURL, checkout.Branch) != nil {
+			continue
+		}
+		if err = verifyRemoteBranch(cfg.RepoURL, checkout.Branch, remoteHeads); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, err
+		}
+	}
+	for _, checkout := range cfg.Checkout {
+		if !checkout.VerifyPushAccess || checkout.Branch == "" {
+			continue
+		}
+		if err = g.verifyPushAccess(
+			ctx, stepCtx, cfg, checkout.Branch, repoCreds,
+		); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+	}
+	if stepCtx.MaxRepoSize > 0 {
+		if err = g.verifyRepoSize(ctx, stepCtx, cfg, repoCreds); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, err
+		}
+	}
+	cloneOpts := &git.BareCloneOptions{
+		BaseDir: stepCtx.WorkDir,
+		MaxSize: stepCtx.MaxRepoSize,
+	}
+	if cfg.PartialClone {
+		cloneOpts.Filter = "blob:none"
+	}
+	repo, err := git.CloneBareContext(ctx, cfg.RepoURL, clientOpts, cloneOpts)
+	if git.IsAuthError(err) && !cfg.InsecureNoAuth {
+		// The credentials may have been rotated since they were cached. If
+		// looking them up again yields different ones, try those once.
+		creds, changed, refreshErr := refreshGitCredentials(ctx, stepCtx, cfg.RepoURL, repoCreds)
+		if refreshErr != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, refreshErr
+		}
+		if changed {
+			clientOpts.Credentials = creds
+			repo, err = git.CloneBareContext(ctx, cfg.RepoURL, clientOpts, cloneOpts)
+		}
+	}
+	if git.IsRepoTooLarge(err) {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+			err: fmt.Errorf(
+				"RepoTooLarge: cloning repo %s was aborted because it exceeded the "+
+					"maximum repository size of %d bytes: %w",
+				cfg.RepoURL, stepCtx.MaxRepoSize, err,
+			),
 		}
 	}
-	repo, err := git.CloneBare(
-		cfg.RepoURL,
-		&git.ClientOptions{
-			User:                  &g.gitUser,
-			Credentials:           repoCreds,
-			InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
-		},
-		&git.BareCloneOptions{
-			BaseDir: stepCtx.WorkDir,
-		},
-	)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error cloning %s: %w", cfg.RepoURL, err)
 	}
+	// Branches that manifests rendered for the Stage are written to are checked
+	// out from a separate, shallow clone that holds nothing but their tips.
+	// This way, the sources of the manifests and the rendered manifests never
+	// share a repository, and nothing from the former can leak into commits
+	// to the latter.
+	var renderedRepo git.BareRepo
+	checkouts := make(map[string]any, len(cfg.Checkout))
 	for _, checkout := range cfg.Checkout {
-		var ref string
+		checkoutRepo := repo
+		if isRenderedBranch(stepCtx, checkout.Branch) {
+			if renderedRepo == nil {
+				renderedCloneOpts := *cloneOpts
+				renderedCloneOpts.Depth = 1
+				if renderedRepo, err = git.CloneBareContext(
+					ctx, cfg.RepoURL, clientOpts, &renderedCloneOpts,
+				); err != nil {
+					return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+						"error cloning %s for rendered branches: %w", cfg.RepoURL, err,
+					)
+				}
+			}
+			checkoutRepo = renderedRepo
+		}
+		// headBranch is the branch that HEAD of the work tree is expected to be
+		// on once it has been added, if any.
+		var ref, branch, headBranch string
 		switch {
 		case checkout.Branch != "":
-			ref = checkout.Branch
-			if err = ensureRemoteBranch(repo, ref, checkout.Create); err != nil {
+			branch = checkout.Branch
+			headBranch = branch
+			// If updates to the branch are batched, the updates accumulated so
+			// far are built upon by checking out the rolling branch, if it
+			// exists, under the name of the branch.
+			if batching := sourceUpdateBatching(stepCtx, cfg.RepoURL, branch); batching != nil {
+				if ref, err = checkoutRepo.FetchRemoteBranchContext(
+					ctx, rollingBranch(batching),
+				); err != nil &&
+					!errors.Is(err, git.ErrRemoteBranchNotFound) {
+					return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+						"error fetching rolling branch %s: %w", rollingBranch(batching), err,
+					)
+				}
+				if err == nil {
+					break
+				}
+			}
+			if ref, err = ensureRemoteBranch(ctx, checkoutRepo, branch, checkout.Create); err != nil {
 				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
-					fmt.Errorf("error ensuring existence of remote branch %s: %w", ref, err)
+					fmt.Errorf("error ensuring existence of remote branch %s: %w", branch, err)
 			}
 		case checkout.Commit != "":
 			ref = checkout.Commit
@@ -171,7 +295,39 @@ func (g *gitCloner) runPromotionStep(
 					fmt.Errorf("error finding commit from repo %s: %w", cfg.RepoURL, err)
 			}
 			ref = commit.ID
+		default:
+			// Rather than relying on git to infer what to check out, explicitly
+			// check out the remote's default branch. The clone's HEAD is only
+			// relied upon if the remote's branches could not be listed.
+			switch {
+			case remoteHeads == nil:
+				if ref, err = repo.DefaultBranch(); err != nil {
+					return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+				}
+			case remoteHeads.DefaultBranch == "":
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+					"HEAD of remote repo %s does not point to an existing branch: %w",
+					cfg.RepoURL, git.ErrRemoteBranchNotFound,
+				)
+			default:
+				ref = remoteHeads.DefaultBranch
+			}
+			headBranch = ref
+			// Changes made to the source of the Stage's manifests must never end
+			// up on a branch rendered manifests are written to, which could
+			// happen if such a branch is the remote's default.
+			if isRenderedBranch(stepCtx, ref) {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+					err: fmt.Errorf(
+						"the default branch %q of repo %s is a branch that manifests rendered for "+
+							"the Stage are written to; specify the branch, commit, or tag to "+
+							"check out at %s explicitly",
+						ref, cfg.RepoURL, checkout.Path,
+					),
+				}
+			}
 		}
+		checkouts[filepath.Clean(checkout.Path)] = branch
 		path, err := securejoin.SecureJoin(stepCtx.WorkDir, checkout.Path)
 		if err != nil {
 			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
@@ -179,20 +335,303 @@ func (g *gitCloner) runPromotionStep(
 				checkout.Path, stepCtx.WorkDir, err,
 			)
 		}
-		if _, err = repo.AddWorkTree(
+		workTree, err := checkoutRepo.AddWorkTree(
 			path,
-			&git.AddWorkTreeOptions{Ref: ref},
-		); err != nil {
+			&git.AddWorkTreeOptions{Ref: ref, Branch: branch, SparsePaths: checkout.Sparse},
+		)
+		if err != nil {
 			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
 				"error adding work tree %s to repo %s: %w",
 				checkout.Path, cfg.RepoURL, err,
 			)
 		}
+		if headBranch != "" {
+			if err = verifyHeadBranch(workTree, headBranch); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+					"error verifying work tree %s: %w", checkout.Path, err,
+				)
+			}
+		}
+		if len(checkout.Sparse) > 0 {
+			if err = expandSparseCheckout(workTree, checkout.Sparse); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+					"error expanding sparse checkout of work tree %s: %w",
+					checkout.Path, err,
+				)
+			}
+		}
+		if !cfg.SkipLFS {
+			if err = pullLFS(ctx, workTree); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+					"error pulling Git LFS objects into work tree %s: %w",
+					checkout.Path, err,
+				)
+			}
+		}
+		if cfg.RecurseSubmodules {
+			if err = g.updateSubmodules(ctx, stepCtx, cfg.RepoURL, workTree); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+					"error updating submodules of work tree %s: %w",
+					checkout.Path, err,
+				)
+			}
+		}
 	}
 	// Note: We do NOT defer repo.Close() because we want to keep the repository
 	// around on the FS for subsequent promotion steps to use. The Engine will
 	// handle all work dir cleanup.
-	return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+	return PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			stateKeyAuthMethod: string(repo.AuthMethod()),
+			stateKeyCheckouts:  checkouts,
+		},
+	}, nil
+}
+
+// listRemoteHeads lists the branches of the remote repository with a single
+// git ls-remote, if any checkout needs them to be verified or checks out the
+// default branch. If the branches are not needed or cannot be listed, nil is
+// returned. In the latter case, the problem is logged, so that the clone
+// itself is relied upon to surface any problem with the repository.
+func listRemoteHeads(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg GitCloneConfig,
+	clientOpts *git.ClientOptions,
+) *git.RemoteHeads {
+	var needed bool
+	for _, checkout := range cfg.Checkout {
+		switch {
+		case checkout.Branch != "":
+			needed = !checkout.Create &&
+				sourceUpdateBatching(stepCtx, cfg.RepoURL, checkout.Branch) == nil
+		case checkout.Commit == "" && checkout.Tag == "" && !checkout.FromFreight:
+			needed = true
+		}
+		if needed {
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+	heads, err := git.ListRemoteHeads(cfg.RepoURL, clientOpts)
+	if err != nil {
+		logging.LoggerFromContext(ctx).Debug(
+			"could not list remote branches before cloning",
+			"repo", cfg.RepoURL,
+			"error", err.Error(),
+		)
+		return nil
+	}
+	return heads
+}
+
+// verifyRemoteBranch returns a terminal error if the specified branch is not
+// among the provided branches of the remote repository. If those are not
+// known, nil is returned.
+func verifyRemoteBranch(
+	repoURL string,
+	branch string,
+	remoteHeads *git.RemoteHeads,
+) error {
+	if remoteHeads == nil {
+		return nil
+	}
+	if _, ok := remoteHeads.Branches[branch]; ok {
+		return nil
+	}
+	return &terminalError{
+		err: fmt.Errorf(
+			"BranchNotFound: branch %q does not exist in repo %s; set create=true "+
+				"if you'd like a non-existent remote branch to be automatically "+
+				"created at checkout: %w",
+			branch, repoURL, git.ErrRemoteBranchNotFound,
+		),
+	}
+}
+
+// verifyHeadBranch returns an error if HEAD of the provided work tree is not
+// on the specified branch, so that nothing is ever promoted from a branch
+// other than the one that was asked for.
+func verifyHeadBranch(workTree git.WorkTree, branch string) error {
+	current, err := workTree.CurrentBranch()
+	if err != nil {
+		return err
+	}
+	if current != branch {
+		return fmt.Errorf("HEAD is on branch %q rather than on branch %q", current, branch)
+	}
+	return nil
+}
+
+// pushAccessResult is the outcome of a conclusive push access check. A nil
+// error indicates that pushing is permitted.
+type pushAccessResult struct {
+	err error
+}
+
+// verifyPushAccess returns a terminal error if the provided credentials are
+// conclusively not permitted to push directly to the specified branch of the
+// repository. If this cannot be determined, the problem is logged and nil is
+// returned, so the step proceeds as it would have without the check. As the
+// credentials are those of the Stage's GitIdentity, if it specifies any, this
+// also verifies that the identity has access to the repository.
+func (g *gitCloner) verifyPushAccess(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg GitCloneConfig,
+	branch string,
+	creds *git.RepoCredentials,
+) error {
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"repo", cfg.RepoURL,
+		"branch", branch,
+	)
+	credsSecret := gitCredentialsSecret(stepCtx)
+	cacheKey := fmt.Sprintf(
+		"%s:%s:%s:%s", stepCtx.Project, credsSecret, libgit.NormalizeURL(cfg.RepoURL), branch,
+	)
+	var res pushAccessResult
+	if cached, found := g.pushAccessCache.Get(cacheKey); found {
+		res = cached.(pushAccessResult) // nolint: forcetypeassert
+	} else {
+		err := g.checkPushAccessFn(ctx, cfg, branch, creds)
+		if err != nil && !isPushForbidden(err) {
+			logger.Info(
+				"could not verify push access to branch; proceeding without it",
+				"error", err.Error(),
+			)
+			return nil
+		}
+		res = pushAccessResult{err: err}
+		g.pushAccessCache.Set(cacheKey, res, cache.DefaultExpiration)
+	}
+	if res.err != nil {
+		grantee := "the credentials"
+		if credsSecret != "" {
+			grantee = fmt.Sprintf("the credentials in Secret %q of the Stage's git identity", credsSecret)
+		}
+		return &terminalError{
+			err: fmt.Errorf(
+				"PushForbidden: not permitted to push to branch %q of repo %s; "+
+					"grant %s push access or open a pull request instead: %w",
+				branch, cfg.RepoURL, grantee, res.err,
+			),
+		}
+	}
+	logger.Debug("verified push access to branch")
+	return nil
+}
+
+// verifyRepoSize returns a terminal error if the API of the repository's Git
+// provider reports that the repository is larger than the maximum repository
+// size, so that it is not cloned at all. If the size cannot be determined, the
+// problem is logged and nil is returned, so that the clone itself is relied
+// upon to enforce the limit. The same goes for partial clones, which omit most
+// of the file contents that the reported size includes.
+func (g *gitCloner) verifyRepoSize(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg GitCloneConfig,
+	creds *git.RepoCredentials,
+) error {
+	logger := logging.LoggerFromContext(ctx).WithValues("repo", cfg.RepoURL)
+	if cfg.PartialClone {
+		logger.Debug("not verifying size of repo before partially cloning it")
+		return nil
+	}
+	size, err := g.getRepoSizeFn(ctx, cfg, creds)
+	if err != nil {
+		logger.Debug("could not determine size of repo before cloning it", "error", err.Error())
+		return nil
+	}
+	if size > stepCtx.MaxRepoSize {
+		return &terminalError{
+			err: fmt.Errorf(
+				"RepoTooLarge: repo %s is reported to be %d bytes, which exceeds the "+
+					"maximum repository size of %d bytes",
+				cfg.RepoURL, size, stepCtx.MaxRepoSize,
+			),
+		}
+	}
+	return nil
+}
+
+// getRepoSize returns the approximate size of the repository in bytes, as
+// reported by the API of the repository's Git provider. An error is returned
+// if no provider that reports the size of repositories is recognized, or if no
+// token is available to authenticate to its API with.
+func getRepoSize(
+	ctx context.Context,
+	cfg GitCloneConfig,
+	creds *git.RepoCredentials,
+) (int64, error) {
+	if creds == nil || creds.Password == "" {
+		return 0, errors.New("no token is available")
+	}
+	gitProv, err := gitprovider.New(cfg.RepoURL, &gitprovider.Options{
+		Token:                 creds.Password,
+		InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
+	})
+	if err != nil {
+		return 0, err
+	}
+	sizer, ok := gitProv.(gitprovider.RepoSizer)
+	if !ok {
+		return 0, errors.New("git provider does not report the size of repositories")
+	}
+	return sizer.RepoSize(ctx)
+}
+
+// checkPushAccess determines whether the provided credentials are permitted
+// to push directly to the specified branch of the repository. The API of the
+// repository's Git provider is used if one is recognized and a token is
+// available, because, unlike Git itself, it can also account for branch
+// protection rules. Otherwise, or if the API is inconclusive, the remote is
+// asked to authorize a push without anything actually being pushed.
+func checkPushAccess(
+	ctx context.Context,
+	cfg GitCloneConfig,
+	branch string,
+	creds *git.RepoCredentials,
+) error {
+	if creds != nil && creds.Password != "" {
+		gitProv, err := gitprovider.New(cfg.RepoURL, &gitprovider.Options{
+			Token:                 creds.Password,
+			InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
+		})
+		if err == nil {
+			if checker, ok := gitProv.(gitprovider.PushAccessChecker); ok {
+				if err = checker.CheckPushAccess(ctx, branch); err == nil ||
+					errors.Is(err, gitprovider.ErrPushForbidden) {
+					return err
+				}
+				logging.LoggerFromContext(ctx).Debug(
+					"could not verify push access using Git provider API",
+					"repo", cfg.RepoURL,
+					"error", err.Error(),
+				)
+			}
+		}
+	}
+	return git.CheckRemotePushAccess(
+		cfg.RepoURL,
+		branch,
+		&git.ClientOptions{
+			Credentials:           creds,
+			InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
+			InsecureNoAuth:        cfg.InsecureNoAuth,
+		},
+	)
+}
+
+// isPushForbidden returns true if the provided error indicates that pushing
+// was conclusively determined to be forbidden.
+func isPushForbidden(err error) bool {
+	return errors.Is(err, gitprovider.ErrPushForbidden) ||
+		errors.Is(err, git.ErrPushForbidden)
 }
 
 // mustCloneRepo determines if the repository must be cloned. At present, there
@@ -222,24 +661,125 @@ func mustCloneRepo(stepCtx *PromotionStepContext, cfg GitCloneConfig) (bool, err
 	return false, nil
 }
 
-// ensureRemoteBranch checks for the existence of a remote branch. If the remote
-// branch exists, no action is taken and nil is returned. If the branch does not
-// exist and create == true, an empty orphaned branch is created and pushed to
-// the remote. If the branch does not exist and create == false, an error is
-// returned.
-func ensureRemoteBranch(repo git.BareRepo, branch string, create bool) error {
-	exists, err := repo.RemoteBranchExists(branch)
+// updateSubmodules initializes and checks out the submodules of the provided
+// working tree. Submodules hosted on the same host as the repository itself are
+// accessed using the repository's own credentials. For submodules hosted
+// elsewhere, credentials are looked up separately so the repository's own
+// credentials are never sent to another host.
+func (g *gitCloner) updateSubmodules(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	repoURL string,
+	workTree git.WorkTree,
+) error {
+	submoduleURLs, err := workTree.SubmoduleURLs()
 	if err != nil {
-		return fmt.Errorf(
-			"error checking if remote branch %q of repo %s exists: %w",
-			branch, repo.URL(), err,
+		return err
+	}
+	opts := &git.UpdateSubmodulesOptions{
+		Credentials: map[string]git.RepoCredentials{},
+	}
+	var missingCreds []string
+	for _, submoduleURL := range submoduleURLs {
+		if isSameGitHost(repoURL, submoduleURL) {
+			continue
+		}
+		creds, found, err := stepCtx.CredentialsDB.Get(
+			ctx,
+			stepCtx.Project,
+			credentials.TypeGit,
+			submoduleURL,
 		)
+		if err != nil {
+			return fmt.Errorf("error getting credentials for submodule %s: %w", submoduleURL, err)
+		}
+		if !found {
+			missingCreds = append(missingCreds, submoduleURL)
+			continue
+		}
+		opts.Credentials[submoduleURL] = git.RepoCredentials{
+			Username: creds.Username,
+			Password: creds.Password,
+		}
+	}
+	if err = workTree.UpdateSubmodulesContext(ctx, opts); err != nil {
+		if len(missingCreds) > 0 {
+			// Publicly accessible submodules do not require credentials, so the
+			// absence of credentials is only worth mentioning if the update failed.
+			return fmt.Errorf(
+				"no credentials found for submodule(s) %s: %w",
+				strings.Join(missingCreds, ", "), err,
+			)
+		}
+		return err
 	}
-	if exists {
+	return nil
+}
+
+// isSameGitHost returns true if the submodule URL refers to the same host as
+// the repository URL. Relative submodule URLs are always resolved against the
+// repository URL and therefore always refer to the same host.
+func isSameGitHost(repoURL, submoduleURL string) bool {
+	if strings.HasPrefix(submoduleURL, "./") || strings.HasPrefix(submoduleURL, "../") {
+		return true
+	}
+	return gitURLHost(repoURL) == gitURLHost(submoduleURL)
+}
+
+// gitURLHost returns the host portion of the provided Git URL, or the URL
+// itself if the host cannot be determined.
+func gitURLHost(repoURL string) string {
+	u, err := url.Parse(libgit.NormalizeURL(repoURL))
+	if err != nil || u.Host == "" {
+		return repoURL
+	}
+	return u.Hostname()
+}
+
+// pullLFS fetches and checks out Git LFS objects into the provided working
+// tree if, and only if, its .gitattributes configure Git LFS for any paths.
+// Without this, such paths would only contain LFS pointer files. If the git-lfs
+// binary is not installed, a warning is logged and the LFS objects are not
+// pulled, rather than failing the promotion step.
+func pullLFS(ctx context.Context, workTree git.WorkTree) error {
+	usesLFS, err := workTree.UsesLFS()
+	if err != nil || !usesLFS {
+		return err
+	}
+	if _, err = exec.LookPath("git-lfs"); err != nil {
+		logging.LoggerFromContext(ctx).Info(
+			"work tree uses Git LFS, but git-lfs is not installed; skipping pull "+
+				"of Git LFS objects, paths tracked by Git LFS will only contain pointer files",
+			"path", workTree.Dir(),
+		)
 		return nil
 	}
+	return workTree.PullLFSContext(ctx)
+}
+
+// ensureRemoteBranch fetches a remote branch and returns the remote-tracking
+// ref from which it may be checked out. Fetching the branch explicitly, rather
+// than relying on it having been cloned, ensures this works even if the clone
+// was shallow or limited to other branches. If the branch does not exist and
+// create == true, an empty orphaned branch is created and pushed to the remote
+// before it is fetched. If the branch does not exist and create == false, an
+// error is returned. Only a fetch that reports the branch to be absent is taken
+// to mean that it does not exist. Any other failure is returned as is.
+func ensureRemoteBranch(
+	ctx context.Context,
+	repo git.BareRepo,
+	branch string,
+	create bool,
+) (string, error) {
+	ref, err := repo.FetchRemoteBranchContext(ctx, branch)
+	if err == nil {
+		return ref, nil
+	}
+	if !errors.Is(err, git.ErrRemoteBranchNotFound) {
+		return "", err
+	}
 	if !create {
-		return fmt.Errorf(
+		return "", fmt.Errorf(
 			"remote branch %q of repo %s does not exist; set create=true if you'd "+
 				"like a non-existent remote branch to be automatically created at "+
 				"checkout",
@@ -247,6 +787,15 @@ func ensureRemoteBranch(repo git.BareRepo, branch string, create bool) error {
 			repo.URL(),
 		)
 	}
+	if err = createRemoteOrphanBranch(ctx, repo, branch); err != nil {
+		return "", err
+	}
+	return repo.FetchRemoteBranchContext(ctx, branch)
+}
+
+// createRemoteOrphanBranch creates a new, empty orphaned branch with a single
+// empty commit and pushes it to the remote.
+func createRemoteOrphanBranch(ctx context.Context, repo git.BareRepo, branch string) error {
 	tmpDir, err := os.MkdirTemp("", "repo-")
 	if err != nil {
 		return fmt.Errorf("error creating temporary directory: %w", err)
@@ -277,7 +826,7 @@ func ensureRemoteBranch(repo git.BareRepo, branch string, create bool) error {
 			branch, repo.URL(), err,
 		)
 	}
-	if err = workTree.Push(&git.PushOptions{TargetBranch: branch}); err != nil {
+	if err = workTree.PushContext(ctx, &git.PushOptions{TargetBranch: branch}); err != nil {
 		return fmt.Errorf(
 			"error pushing initial commit to new branch %q to repo %s: %w",
 			branch, repo.URL(), err,
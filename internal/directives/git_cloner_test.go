@@ -2,18 +2,25 @@ package directives
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/patrickmn/go-cache"
 	"github.com/sosedoff/gitkit"
 	"github.com/stretchr/testify/require"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/git"
 	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
 )
 
 func Test_gitCloner_validate(t *testing.T) {
@@ -203,13 +210,27 @@ func Test_gitCloner_validate(t *testing.T) {
 				"checkout.0: Must validate one and only one schema",
 			},
 		},
+		{
+			name: "sparse path is empty string",
+			config: Config{
+				"checkout": []Config{{
+					"path":   "/fake/path",
+					"sparse": []string{""},
+				}},
+			},
+			expectedProblems: []string{
+				"checkout.0.sparse.0: String length must be greater than or equal to 1",
+			},
+		},
 		{
 			name: "valid kitchen sink",
 			config: Config{
-				"repoURL": "https://github.com/example/repo.git",
+				"repoURL":      "https://github.com/example/repo.git",
+				"partialClone": true,
 				"checkout": []Config{
 					{
-						"path": "/fake/path/0",
+						"path":   "/fake/path/0",
+						"sparse": []string{"apps/guestbook"},
 					},
 					{
 						"branch":      "",
@@ -353,6 +374,14 @@ func Test_gitCloner_runPromotionStep(t *testing.T) {
 	)
 	require.NoError(t, err)
 	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+	require.Equal(
+		t,
+		map[string]any{
+			stateKeyAuthMethod: string(git.AuthMethodNone),
+			stateKeyCheckouts:  map[string]any{"src": "", "out": "stage/dev"},
+		},
+		res.Output,
+	)
 	require.DirExists(t, filepath.Join(stepCtx.WorkDir, "src"))
 	// The checked out master branch should have the content we know is in the
 	// test remote's master branch.
@@ -365,3 +394,592 @@ func Test_gitCloner_runPromotionStep(t *testing.T) {
 	require.Len(t, dirEntries, 1) // Just the .git file
 	require.FileExists(t, filepath.Join(stepCtx.WorkDir, "out", ".git"))
 }
+
+func Test_gitCloner_runPromotionStep_sparse(t *testing.T) {
+	// Set up a test Git server in-process
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	// Create a small monorepo in which an overlay refers to a base and a
+	// component elsewhere in the repository
+	repo, err := git.Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer repo.Close()
+	for file, content := range map[string]string{
+		"apps/guestbook/prod/kustomization.yaml":   "resources:\n- ../base\n",
+		"apps/guestbook/base/kustomization.yaml":   "resources:\n- deployment.yaml\ncomponents:\n- ../../../components/monitoring\n",
+		"apps/guestbook/base/deployment.yaml":      "",
+		"components/monitoring/kustomization.yaml": "kind: Component\n",
+		"apps/other/kustomization.yaml":            "",
+	} {
+		p := filepath.Join(repo.Dir(), filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o700))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	require.NoError(t, repo.AddAllAndCommit("Initial commit"))
+	require.NoError(t, repo.Push(nil))
+
+	r := newGitCloner()
+	runner, ok := r.(*gitCloner)
+	require.True(t, ok)
+
+	stepCtx := &PromotionStepContext{
+		CredentialsDB: &credentials.FakeDB{},
+		WorkDir:       t.TempDir(),
+	}
+
+	res, err := runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitCloneConfig{
+			RepoURL:      testRepoURL,
+			PartialClone: true,
+			Checkout: []Checkout{{
+				Path:   "src",
+				Sparse: []string{"apps/guestbook/prod"},
+			}},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+
+	src := filepath.Join(stepCtx.WorkDir, "src")
+	require.FileExists(t, filepath.Join(src, "apps", "guestbook", "prod", "kustomization.yaml"))
+	// What the overlay refers to is checked out as well, recursively
+	require.FileExists(t, filepath.Join(src, "apps", "guestbook", "base", "deployment.yaml"))
+	require.FileExists(t, filepath.Join(src, "components", "monitoring", "kustomization.yaml"))
+	// Everything else is left out
+	require.NoDirExists(t, filepath.Join(src, "apps", "other"))
+}
+
+func Test_gitCloner_runPromotionStep_renderedBranch(t *testing.T) {
+	// Set up a test Git server in-process
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	// Create a repository with a source branch and a rendered branch
+	repo, err := git.Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer repo.Close()
+	err = os.WriteFile(filepath.Join(repo.Dir(), "kustomization.yaml"), []byte{}, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddAllAndCommit("Initial commit"))
+	require.NoError(t, repo.Push(nil))
+	require.NoError(t, repo.CreateChildBranch("stage/dev"))
+	err = os.WriteFile(filepath.Join(repo.Dir(), "manifests.yaml"), []byte{}, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddAllAndCommit("Rendered manifests"))
+	require.NoError(t, repo.Push(&git.PushOptions{TargetBranch: "stage/dev"}))
+
+	r := newGitCloner()
+	runner, ok := r.(*gitCloner)
+	require.True(t, ok)
+
+	stepCtx := &PromotionStepContext{
+		CredentialsDB:  &credentials.FakeDB{},
+		WorkDir:        t.TempDir(),
+		RenderedBranch: "stage/dev",
+	}
+
+	res, err := runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitCloneConfig{
+			RepoURL: testRepoURL,
+			Checkout: []Checkout{
+				{Path: "src"},
+				{Path: "out", Branch: "stage/dev"},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+
+	src := filepath.Join(stepCtx.WorkDir, "src")
+	out := filepath.Join(stepCtx.WorkDir, "out")
+	require.FileExists(t, filepath.Join(src, "kustomization.yaml"))
+	require.NoFileExists(t, filepath.Join(src, "manifests.yaml"))
+	require.FileExists(t, filepath.Join(out, "manifests.yaml"))
+
+	// The rendered branch is checked out from a separate, shallow clone
+	gitOutput := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.Output()
+		require.NoError(t, err)
+		return strings.TrimSpace(string(output))
+	}
+	require.NotEqual(
+		t,
+		gitOutput(src, "rev-parse", "--path-format=absolute", "--git-common-dir"),
+		gitOutput(out, "rev-parse", "--path-format=absolute", "--git-common-dir"),
+	)
+	require.Equal(t, "false", gitOutput(src, "rev-parse", "--is-shallow-repository"))
+	require.Equal(t, "true", gitOutput(out, "rev-parse", "--is-shallow-repository"))
+}
+
+func Test_gitCloner_runPromotionStep_branches(t *testing.T) {
+	// Set up a test Git server in-process
+	serverDir := t.TempDir()
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        serverDir,
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	// Each ls-remote, clone, or fetch starts by requesting the remote's refs
+	var refAdvertisements atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/info/refs") {
+			refAdvertisements.Add(1)
+		}
+		service.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	// Create a repository whose overlays live on a branch other than master
+	repo, err := git.Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer repo.Close()
+	err = os.WriteFile(filepath.Join(repo.Dir(), "README.md"), []byte{}, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddAllAndCommit("Initial commit"))
+	require.NoError(t, repo.Push(nil))
+	require.NoError(t, repo.CreateChildBranch("env-config"))
+	err = os.WriteFile(filepath.Join(repo.Dir(), "kustomization.yaml"), []byte{}, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddAllAndCommit("Add overlay"))
+	require.NoError(t, repo.Push(&git.PushOptions{TargetBranch: "env-config"}))
+
+	setRemoteHead := func(t *testing.T, branch string) {
+		require.NoError(t, os.WriteFile(
+			filepath.Join(serverDir, "test.git", "HEAD"),
+			[]byte("ref: refs/heads/"+branch+"\n"),
+			0o600,
+		))
+	}
+
+	r := newGitCloner()
+	runner, ok := r.(*gitCloner)
+	require.True(t, ok)
+
+	testCases := []struct {
+		name       string
+		remoteHead string
+		checkout   Checkout
+		assertions func(t *testing.T, workDir string, res PromotionStepResult, err error)
+	}{
+		{
+			name:       "default branch follows the remote's HEAD",
+			remoteHead: "env-config",
+			checkout:   Checkout{Path: "src"},
+			assertions: func(t *testing.T, workDir string, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.FileExists(t, filepath.Join(workDir, "src", "kustomization.yaml"))
+				cmd := exec.Command("git", "branch", "--show-current")
+				cmd.Dir = filepath.Join(workDir, "src")
+				output, err := cmd.Output()
+				require.NoError(t, err)
+				require.Equal(t, "env-config", strings.TrimSpace(string(output)))
+				// The remote's branches were listed once, ahead of the clone
+				require.Equal(t, int32(2), refAdvertisements.Load())
+			},
+		},
+		{
+			name:       "remote's HEAD points to a missing branch",
+			remoteHead: "missing",
+			checkout:   Checkout{Path: "src"},
+			assertions: func(t *testing.T, _ string, res PromotionStepResult, err error) {
+				require.ErrorIs(t, err, git.ErrRemoteBranchNotFound)
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+			},
+		},
+		{
+			name:       "branch is checked out regardless of the remote's HEAD",
+			remoteHead: "master",
+			checkout:   Checkout{Path: "src", Branch: "env-config"},
+			assertions: func(t *testing.T, workDir string, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.FileExists(t, filepath.Join(workDir, "src", "kustomization.yaml"))
+			},
+		},
+		{
+			name:       "missing branch fails before cloning",
+			remoteHead: "master",
+			checkout:   Checkout{Path: "src", Branch: "missing"},
+			assertions: func(t *testing.T, workDir string, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "BranchNotFound")
+				require.ErrorIs(t, err, git.ErrRemoteBranchNotFound)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+				// Nothing was cloned
+				entries, err := os.ReadDir(workDir)
+				require.NoError(t, err)
+				require.Empty(t, entries)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			setRemoteHead(t, testCase.remoteHead)
+			refAdvertisements.Store(0)
+			stepCtx := &PromotionStepContext{
+				CredentialsDB: &credentials.FakeDB{},
+				WorkDir:       t.TempDir(),
+			}
+			res, err := runner.runPromotionStep(
+				context.Background(),
+				stepCtx,
+				GitCloneConfig{
+					RepoURL:  testRepoURL,
+					Checkout: []Checkout{testCase.checkout},
+				},
+			)
+			testCase.assertions(t, stepCtx.WorkDir, res, err)
+		})
+	}
+}
+
+func Test_verifyHeadBranch(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	repo, err := git.Clone(fmt.Sprintf("%s/test.git", server.URL), nil, nil)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	require.NoError(t, verifyHeadBranch(repo, "master"))
+	require.ErrorContains(
+		t,
+		verifyHeadBranch(repo, "env-config"),
+		`HEAD is on branch "master" rather than on branch "env-config"`,
+	)
+}
+
+func Test_pullLFS(t *testing.T) {
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	repo, err := git.Clone(fmt.Sprintf("%s/test.git", server.URL), nil, nil)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	// Without any Git LFS configuration, there is nothing to pull
+	require.NoError(t, pullLFS(context.Background(), repo))
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(repo.Dir(), ".gitattributes"),
+		[]byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"),
+		0600,
+	))
+	// With git-lfs missing from the PATH, pulling is skipped rather than
+	// failing
+	t.Setenv("PATH", t.TempDir())
+	require.NoError(t, pullLFS(context.Background(), repo))
+}
+
+func Test_gitCloner_verifyPushAccess(t *testing.T) {
+	const testRepoURL = "https://github.com/example/repo.git"
+	testCases := []struct {
+		name       string
+		checkErr   error
+		assertions func(t *testing.T, err error, calls int)
+	}{
+		{
+			name: "push permitted",
+			assertions: func(t *testing.T, err error, calls int) {
+				require.NoError(t, err)
+				// The outcome was cached
+				require.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:     "push forbidden by Git provider",
+			checkErr: fmt.Errorf("%w: branch is locked", gitprovider.ErrPushForbidden),
+			assertions: func(t *testing.T, err error, calls int) {
+				require.ErrorContains(t, err, "PushForbidden:")
+				require.ErrorContains(t, err, "branch is locked")
+				require.True(t, isTerminal(err))
+				// The outcome was cached
+				require.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:     "push forbidden by remote",
+			checkErr: fmt.Errorf("%w: 403", git.ErrPushForbidden),
+			assertions: func(t *testing.T, err error, calls int) {
+				require.ErrorContains(t, err, "PushForbidden:")
+				require.True(t, isTerminal(err))
+				require.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:     "push access could not be determined",
+			checkErr: errors.New("connection refused"),
+			assertions: func(t *testing.T, err error, calls int) {
+				require.NoError(t, err)
+				// Inconclusive outcomes are not cached
+				require.Equal(t, 2, calls)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var calls int
+			runner := &gitCloner{
+				pushAccessCache: cache.New(pushAccessCacheTTL, 0),
+				checkPushAccessFn: func(
+					context.Context,
+					GitCloneConfig,
+					string,
+					*git.RepoCredentials,
+				) error {
+					calls++
+					return testCase.checkErr
+				},
+			}
+			cfg := GitCloneConfig{RepoURL: testRepoURL}
+			var err error
+			for range 2 {
+				err = runner.verifyPushAccess(
+					context.Background(), &PromotionStepContext{Project: "fake-project"}, cfg, "env/prod", nil,
+				)
+			}
+			testCase.assertions(t, err, calls)
+		})
+	}
+
+	t.Run("fails before cloning", func(t *testing.T) {
+		runner := &gitCloner{
+			pushAccessCache: cache.New(pushAccessCacheTTL, 0),
+			checkPushAccessFn: func(
+				context.Context,
+				GitCloneConfig,
+				string,
+				*git.RepoCredentials,
+			) error {
+				return fmt.Errorf("%w: 403", git.ErrPushForbidden)
+			},
+		}
+		stepCtx := &PromotionStepContext{
+			CredentialsDB: &credentials.FakeDB{},
+			WorkDir:       t.TempDir(),
+		}
+		res, err := runner.runPromotionStep(
+			context.Background(),
+			stepCtx,
+			GitCloneConfig{
+				// Nothing is listening here, so cloning would fail differently
+				RepoURL: "http://127.0.0.1:1/repo.git",
+				Checkout: []Checkout{{
+					Branch:           "env/prod",
+					Path:             "out",
+					VerifyPushAccess: true,
+				}},
+			},
+		)
+		require.ErrorContains(t, err, "PushForbidden:")
+		require.True(t, isTerminal(err))
+		require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+		require.NoDirExists(t, filepath.Join(stepCtx.WorkDir, "out"))
+	})
+
+	t.Run("verifies credentials of git identity", func(t *testing.T) {
+		var checked []string
+		runner := &gitCloner{
+			pushAccessCache: cache.New(pushAccessCacheTTL, 0),
+			checkPushAccessFn: func(
+				_ context.Context,
+				_ GitCloneConfig,
+				_ string,
+				creds *git.RepoCredentials,
+			) error {
+				checked = append(checked, creds.Username)
+				if creds.Username == "prod-bot" {
+					return fmt.Errorf("%w: 403", git.ErrPushForbidden)
+				}
+				return nil
+			},
+		}
+		db := &credentials.FakeDB{
+			GetFn: func(
+				context.Context,
+				string,
+				credentials.Type,
+				string,
+			) (credentials.Credentials, bool, error) {
+				return credentials.Credentials{Username: "kargo", Password: "fake-password"}, true, nil
+			},
+			GetFromSecretFn: func(
+				_ context.Context,
+				_ string,
+				name string,
+				_ credentials.Type,
+				_ string,
+			) (credentials.Credentials, bool, error) {
+				return credentials.Credentials{Username: name, Password: "fake-password"}, true, nil
+			},
+		}
+		cfg := GitCloneConfig{RepoURL: testRepoURL}
+
+		// The outcome for the default credentials is not reused for those of
+		// the git identity.
+		stepCtx := &PromotionStepContext{Project: "fake-project", CredentialsDB: db}
+		require.NoError(t, runner.verifyPushAccess(
+			context.Background(), stepCtx, cfg, "env/prod", &git.RepoCredentials{Username: "kargo"},
+		))
+		stepCtx.GitIdentity = &kargoapi.GitIdentity{CredentialsSecret: "prod-bot"}
+		creds, err := getGitCredentials(context.Background(), stepCtx, testRepoURL)
+		require.NoError(t, err)
+		err = runner.verifyPushAccess(context.Background(), stepCtx, cfg, "env/prod", creds)
+		require.ErrorContains(t, err, "PushForbidden:")
+		require.ErrorContains(t, err, `Secret "prod-bot"`)
+		require.Equal(t, []string{"kargo", "prod-bot"}, checked)
+	})
+}
+
+func Test_gitCloner_verifyRepoSize(t *testing.T) {
+	const testRepoURL = "https://github.com/example/repo.git"
+	testCases := []struct {
+		name         string
+		partialClone bool
+		size         int64
+		sizeErr      error
+		assertions   func(t *testing.T, err error)
+	}{
+		{
+			name: "within limit",
+			size: 1024,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "exceeds limit",
+			size: 4096,
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "RepoTooLarge:")
+				require.ErrorContains(t, err, "4096 bytes")
+				require.True(t, isTerminal(err))
+			},
+		},
+		{
+			name:         "partial clone",
+			partialClone: true,
+			size:         4096,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "size could not be determined",
+			sizeErr: errors.New("git provider does not report the size of repositories"),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := &gitCloner{
+				getRepoSizeFn: func(
+					context.Context,
+					GitCloneConfig,
+					*git.RepoCredentials,
+				) (int64, error) {
+					return testCase.size, testCase.sizeErr
+				},
+			}
+			err := g.verifyRepoSize(
+				context.Background(),
+				&PromotionStepContext{MaxRepoSize: 2048},
+				GitCloneConfig{
+					RepoURL:      testRepoURL,
+					PartialClone: testCase.partialClone,
+				},
+				nil,
+			)
+			testCase.assertions(t, err)
+		})
+	}
+}
+
+func Test_isSameGitHost(t *testing.T) {
+	testCases := []struct {
+		name         string
+		repoURL      string
+		submoduleURL string
+		expected     bool
+	}{
+		{
+			name:         "relative submodule URL",
+			repoURL:      "https://github.com/example/repo.git",
+			submoduleURL: "../other.git",
+			expected:     true,
+		},
+		{
+			name:         "same host",
+			repoURL:      "https://github.com/example/repo.git",
+			submoduleURL: "https://github.com/example/other.git",
+			expected:     true,
+		},
+		{
+			name:         "same host with different protocols",
+			repoURL:      "https://github.com/example/repo.git",
+			submoduleURL: "git@github.com:example/other.git",
+			expected:     true,
+		},
+		{
+			name:         "different host",
+			repoURL:      "https://github.com/example/repo.git",
+			submoduleURL: "https://gitlab.com/example/other.git",
+			expected:     false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(
+				t,
+				testCase.expected,
+				isSameGitHost(testCase.repoURL, testCase.submoduleURL),
+			)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/xeipuuv/gojsonschema"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+// unverifiedSourceCommitReason prefixes the message of a failed git-verify-commit
+// step, and thereby the message of the Promotion it is part of.
+const unverifiedSourceCommitReason = "UnverifiedSourceCommit"
+
+func init() {
+	builtins.RegisterPromotionStepRunner(
+		newGitCommitVerifier(),
+		&StepRunnerPermissions{AllowCredentialsDB: true},
+	)
+}
+
+// gitCommitVerifier is an implementation of the PromotionStepRunner interface
+// that verifies the signature of a commit in a Git repository.
+type gitCommitVerifier struct {
+	schemaLoader gojsonschema.JSONLoader
+}
+
+// newGitCommitVerifier returns an implementation of the PromotionStepRunner
+// interface that verifies the signature of a commit in a Git repository.
+func newGitCommitVerifier() PromotionStepRunner {
+	r := &gitCommitVerifier{}
+	r.schemaLoader = getConfigSchemaLoader(r.Name())
+	return r
+}
+
+// Name implements the PromotionStepRunner interface.
+func (g *gitCommitVerifier) Name() string {
+	return "git-verify-commit"
+}
+
+// RunPromotionStep implements the PromotionStepRunner interface.
+func (g *gitCommitVerifier) RunPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+) (PromotionStepResult, error) {
+	if err := g.validate(stepCtx.Config); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	cfg, err := ConfigToStruct[GitVerifyCommitConfig](stepCtx.Config)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not convert config into %s config: %w", g.Name(), err)
+	}
+	return g.runPromotionStep(ctx, stepCtx, cfg)
+}
+
+// validate validates gitCommitVerifier configuration against a JSON schema.
+func (g *gitCommitVerifier) validate(cfg Config) error {
+	return validate(g.schemaLoader, gojsonschema.NewGoLoader(cfg), g.Name())
+}
+
+func (g *gitCommitVerifier) runPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg GitVerifyCommitConfig,
+) (PromotionStepResult, error) {
+	path, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.Path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error joining path %s with work dir %s: %w",
+			cfg.Path, stepCtx.WorkDir, err,
+		)
+	}
+	// The working tree is loaded once to learn the URL of the repository, and
+	// then again with any applicable credentials, which are needed if the
+	// commit has to be fetched.
+	loadOpts := &git.LoadWorkTreeOptions{}
+	workTree, err := git.LoadWorkTree(path, loadOpts)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
+	}
+	if loadOpts.Credentials, err = getGitCredentials(ctx, stepCtx, workTree.URL()); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if loadOpts.Credentials != nil {
+		if workTree, err = git.LoadWorkTree(path, loadOpts); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+				fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
+		}
+	}
+
+	sig, err := workTree.VerifyCommitSignature(
+		cfg.Commit,
+		&git.VerifyCommitSignatureOptions{
+			GPGPublicKeys:     cfg.GpgPublicKeys,
+			SSHAllowedSigners: cfg.SSHAllowedSigners,
+		},
+	)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error verifying signature of commit: %w", err)
+	}
+	switch {
+	case sig.Status == git.SignatureStatusUnsigned:
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"%s: commit %s is not signed",
+				unverifiedSourceCommitReason, sig.Commit,
+			)}
+	case sig.Status != git.SignatureStatusGood:
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"%s: signature of commit %s could not be verified: %s",
+				unverifiedSourceCommitReason, sig.Commit, sig.Status,
+			)}
+	case len(cfg.AllowedSigners) > 0 && !isAllowedSigner(sig, cfg.AllowedSigners):
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf(
+				"%s: commit %s was signed by %q, who is not an allowed signer",
+				unverifiedSourceCommitReason, sig.Commit, sig.Signer,
+			)}
+	}
+	return PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			stateKeyCommit: sig.Commit,
+			"signer":       sig.Signer,
+			"fingerprint":  sig.Fingerprint,
+		},
+	}, nil
+}
+
+// isAllowedSigner returns true if the signer of the provided CommitSignature
+// matches any of the provided identities. An identity matches the signer's
+// full identity, the email address contained in it, or the fingerprint of the
+// key.
+func isAllowedSigner(sig *git.CommitSignature, allowed []string) bool {
+	candidates := []string{sig.Signer}
+	if start := strings.LastIndex(sig.Signer, "<"); start >= 0 {
+		if end := strings.LastIndex(sig.Signer, ">"); end > start {
+			candidates = append(candidates, sig.Signer[start+1:end])
+		}
+	}
+	return slices.ContainsFunc(allowed, func(identity string) bool {
+		if identity == sig.Fingerprint {
+			return true
+		}
+		// GPG fingerprints are hexadecimal and commonly written in either case.
+		// SSH fingerprints are base64 encoded and prefixed by their hash
+		// algorithm, so they must match exactly.
+		if !strings.Contains(sig.Fingerprint, ":") && sig.Fingerprint != "" &&
+			strings.EqualFold(identity, sig.Fingerprint) {
+			return true
+		}
+		return slices.Contains(candidates, identity)
+	})
+}
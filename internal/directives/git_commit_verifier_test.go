@@ -0,0 +1,278 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func Test_gitCommitVerifier_validate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		config           Config
+		expectedProblems []string
+	}{
+		{
+			name:   "path not specified",
+			config: Config{},
+			expectedProblems: []string{
+				"(root): path is required",
+			},
+		},
+		{
+			name: "no keys specified",
+			config: Config{
+				"path": "/fake/path",
+			},
+			expectedProblems: []string{
+				"(root): Must validate at least one schema (anyOf)",
+			},
+		},
+		{
+			name: "keys are empty strings",
+			config: Config{
+				"path":              "/fake/path",
+				"gpgPublicKeys":     "",
+				"sshAllowedSigners": "",
+			},
+			expectedProblems: []string{
+				"(root): Must validate at least one schema (anyOf)",
+			},
+		},
+		{
+			name: "allowed signer is empty string",
+			config: Config{
+				"path":           "/fake/path",
+				"gpgPublicKeys":  "fake-keys",
+				"allowedSigners": []string{""},
+			},
+			expectedProblems: []string{
+				"allowedSigners.0: String length must be greater than or equal to 1",
+			},
+		},
+		{
+			name: "valid with GPG public keys",
+			config: Config{
+				"path":          "/fake/path",
+				"gpgPublicKeys": "fake-keys",
+			},
+		},
+		{
+			name: "valid with SSH allowed signers",
+			config: Config{
+				"path":              "/fake/path",
+				"commit":            "fake-commit",
+				"sshAllowedSigners": "fake-signers",
+				"allowedSigners":    []string{"jane@example.com"},
+			},
+		},
+	}
+
+	r := newGitCommitVerifier()
+	runner, ok := r.(*gitCommitVerifier)
+	require.True(t, ok)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := runner.validate(testCase.config)
+			if len(testCase.expectedProblems) == 0 {
+				require.NoError(t, err)
+			} else {
+				for _, problem := range testCase.expectedProblems {
+					require.ErrorContains(t, err, problem)
+				}
+			}
+		})
+	}
+}
+
+func Test_gitCommitVerifier_runPromotionStep(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	workDir := t.TempDir()
+	repo, err := git.Clone(remoteDir, nil, &git.CloneOptions{BaseDir: workDir})
+	require.NoError(t, err)
+	defer repo.Close()
+	path, err := filepath.Rel(workDir, repo.Dir())
+	require.NoError(t, err)
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo.Dir()
+		cmd.Env = []string{"HOME=" + repo.HomeDir()}
+		res, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(res))
+		return strings.TrimSpace(string(res))
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	res, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput()
+	require.NoError(t, err, string(res))
+	publicKey, err := os.ReadFile(keyPath + ".pub")
+	require.NoError(t, err)
+	allowedSigners := "jane@example.com " + string(publicKey)
+
+	run("commit", "--allow-empty", "-m", "unsigned")
+	unsigned := run("rev-parse", "HEAD")
+	run(
+		"-c", "gpg.format=ssh", "-c", "user.signingKey="+keyPath,
+		"commit", "--allow-empty", "-S", "-m", "signed",
+	)
+	signed := run("rev-parse", "HEAD")
+
+	testCases := []struct {
+		name       string
+		cfg        GitVerifyCommitConfig
+		assertions func(*testing.T, PromotionStepResult, error)
+	}{
+		{
+			name: "unsigned commit",
+			cfg: GitVerifyCommitConfig{
+				Commit:            unsigned,
+				SSHAllowedSigners: allowedSigners,
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "UnverifiedSourceCommit: commit "+unsigned+" is not signed")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name: "signed with unknown key",
+			cfg: GitVerifyCommitConfig{
+				Commit: signed,
+				// A different principal's key.
+				SSHAllowedSigners: "john@example.com ssh-ed25519 " +
+					"AAAAC3NzaC1lZDI1NTE5AAAAIOdjy9YSTxPl3Fxq9vz2F4NJuU/jcEKbQF4Hn5JFN8Ur",
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "UnverifiedSourceCommit: signature of commit "+signed)
+				require.ErrorContains(t, err, "UnknownKey")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name: "signer is not allowed",
+			cfg: GitVerifyCommitConfig{
+				Commit:            signed,
+				SSHAllowedSigners: allowedSigners,
+				AllowedSigners:    []string{"john@example.com"},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "who is not an allowed signer")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name: "verified",
+			cfg: GitVerifyCommitConfig{
+				SSHAllowedSigners: allowedSigners,
+				AllowedSigners:    []string{"jane@example.com"},
+			},
+			assertions: func(t *testing.T, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Equal(t, signed, res.Output[stateKeyCommit])
+				require.Equal(t, "jane@example.com", res.Output["signer"])
+				require.NotEmpty(t, res.Output["fingerprint"])
+			},
+		},
+	}
+
+	r := newGitCommitVerifier()
+	runner, ok := r.(*gitCommitVerifier)
+	require.True(t, ok)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.cfg.Path = path
+			res, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{
+					Project:       "fake-project",
+					WorkDir:       workDir,
+					CredentialsDB: &credentials.FakeDB{},
+				},
+				testCase.cfg,
+			)
+			testCase.assertions(t, res, err)
+		})
+	}
+}
+
+func Test_isAllowedSigner(t *testing.T) {
+	gpgSig := &git.CommitSignature{
+		Signer:      "Jane Doe <jane@example.com>",
+		Fingerprint: "0123456789ABCDEF",
+	}
+	sshSig := &git.CommitSignature{
+		Signer:      "jane@example.com",
+		Fingerprint: "SHA256:AbCdEf",
+	}
+	testCases := []struct {
+		name     string
+		sig      *git.CommitSignature
+		allowed  []string
+		expected bool
+	}{
+		{
+			name:     "full GPG user ID",
+			sig:      gpgSig,
+			allowed:  []string{"Jane Doe <jane@example.com>"},
+			expected: true,
+		},
+		{
+			name:     "GPG email address",
+			sig:      gpgSig,
+			allowed:  []string{"john@example.com", "jane@example.com"},
+			expected: true,
+		},
+		{
+			name:     "GPG fingerprint in lower case",
+			sig:      gpgSig,
+			allowed:  []string{"0123456789abcdef"},
+			expected: true,
+		},
+		{
+			name:     "GPG name only",
+			sig:      gpgSig,
+			allowed:  []string{"Jane Doe"},
+			expected: false,
+		},
+		{
+			name:     "SSH principal",
+			sig:      sshSig,
+			allowed:  []string{"jane@example.com"},
+			expected: true,
+		},
+		{
+			name:     "SSH fingerprint",
+			sig:      sshSig,
+			allowed:  []string{"SHA256:AbCdEf"},
+			expected: true,
+		},
+		{
+			name:     "SSH fingerprint in different case",
+			sig:      sshSig,
+			allowed:  []string{"SHA256:abcdef"},
+			expected: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, isAllowedSigner(testCase.sig, testCase.allowed))
+		})
+	}
+}
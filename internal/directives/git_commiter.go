@@ -3,6 +3,8 @@ package directives
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/xeipuuv/gojsonschema"
@@ -11,8 +13,14 @@ import (
 	"github.com/akuity/kargo/internal/controller/git"
 )
 
-// stateKeyCommit is the key used to store the commit ID in the shared State.
-const stateKeyCommit = "commit"
+const (
+	// stateKeyCommit is the key used to store the commit ID in the shared
+	// State.
+	stateKeyCommit = "commit"
+	// stateKeyCommitTrailers is the key used to store the Kargo trailers found
+	// in the message of a commit in the shared State.
+	stateKeyCommitTrailers = "commitTrailers"
+)
 
 func init() {
 	builtins.RegisterPromotionStepRunner(newGitCommitter(), nil)
@@ -75,6 +83,16 @@ func (g *gitCommitter) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
 	}
+	if err = verifyClonedBranch(stepCtx.SharedState, cfg.Path, workTree); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, err
+	}
+	if cfg.MetadataFile != "" {
+		// Written before staging so it is part of the same commit as everything
+		// else, or so that no commit is made if nothing at all has changed.
+		if err = writePromotionMetadata(stepCtx, path, cfg.MetadataFile); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+	}
 	if err = workTree.AddAll(); err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error adding all changes to working tree: %w", err)
@@ -90,6 +108,9 @@ func (g *gitCommitter) runPromotionStep(
 			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 				fmt.Errorf("error building commit message: %w", err)
 		}
+		if cfg.AddTrailers {
+			commitMsg = buildCommitTrailers(stepCtx).AppendTo(commitMsg)
+		}
 		commitOpts := &git.CommitOptions{}
 		if cfg.Author != nil {
 			commitOpts.Author = &git.User{}
@@ -110,9 +131,13 @@ func (g *gitCommitter) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error getting last commit ID: %w", err)
 	}
+	output := map[string]any{stateKeyCommit: commitID}
+	if err = addCommitTrailersOutput(workTree, commitID, output); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
 	return PromotionStepResult{
 		Status: kargoapi.PromotionPhaseSucceeded,
-		Output: map[string]any{stateKeyCommit: commitID},
+		Output: output,
 	}, nil
 }
 
@@ -177,3 +202,69 @@ func (g *gitCommitter) buildCommitMessage(
 	}
 	return commitMsg, nil
 }
+
+// buildCommitTrailers returns CommitTrailers summarizing the Promotion that the
+// provided PromotionStepContext belongs to, including the images, charts, and
+// commits of all Freight referenced by it.
+func buildCommitTrailers(stepCtx *PromotionStepContext) kargoapi.CommitTrailers {
+	trailers := kargoapi.CommitTrailers{
+		Promotion: stepCtx.Project + "/" + stepCtx.Promotion,
+	}
+	for _, ref := range stepCtx.Freight.References() {
+		for _, image := range ref.Images {
+			var img string
+			switch {
+			case image.Tag != "":
+				img = image.RepoURL + ":" + image.Tag
+			case image.Digest != "":
+				img = image.RepoURL + "@" + image.Digest
+			default:
+				continue
+			}
+			if !slices.Contains(trailers.Images, img) {
+				trailers.Images = append(trailers.Images, img)
+			}
+		}
+		for _, chart := range ref.Charts {
+			if chart.Version == "" {
+				continue
+			}
+			if c := chartReference(chart) + ":" + chart.Version; !slices.Contains(trailers.Charts, c) {
+				trailers.Charts = append(trailers.Charts, c)
+			}
+		}
+		for _, commit := range ref.Commits {
+			if commit.ID != "" && !slices.Contains(trailers.SourceCommits, commit.ID) {
+				trailers.SourceCommits = append(trailers.SourceCommits, commit.ID)
+			}
+		}
+	}
+	return trailers
+}
+
+// chartReference returns a reference to the provided chart, without its
+// version, in the form <repoURL>/<name> or, for charts in OCI registries, in
+// which the repository URL already identifies the chart, <repoURL>.
+func chartReference(chart kargoapi.Chart) string {
+	if chart.Name == "" {
+		return chart.RepoURL
+	}
+	return strings.TrimSuffix(chart.RepoURL, "/") + "/" + chart.Name
+}
+
+// addCommitTrailersOutput adds any Kargo trailers found in the message of the
+// commit with the provided ID to the provided step output.
+func addCommitTrailersOutput(
+	workTree git.WorkTree,
+	commitID string,
+	output map[string]any,
+) error {
+	rawTrailers, err := workTree.CommitTrailers(commitID)
+	if err != nil {
+		return err
+	}
+	if trailers := kargoapi.ParseCommitTrailers(rawTrailers); !trailers.IsEmpty() {
+		output[stateKeyCommitTrailers] = trailers.String()
+	}
+	return nil
+}
@@ -210,15 +210,18 @@ func Test_gitCommitter_runPromotionStep(t *testing.T) {
 	require.True(t, ok)
 
 	stepCtx := &PromotionStepContext{
-		WorkDir: workDir,
+		WorkDir:   workDir,
+		Project:   "fake-project",
+		Promotion: "fake-promotion",
 	}
 
 	res, err := runner.runPromotionStep(
 		context.Background(),
 		stepCtx,
 		GitCommitConfig{
-			Path:    "master",
-			Message: "Initial commit",
+			Path:        "master",
+			Message:     "Initial commit",
+			AddTrailers: true,
 		},
 	)
 	require.NoError(t, err)
@@ -230,7 +233,61 @@ func Test_gitCommitter_runPromotionStep(t *testing.T) {
 	require.Equal(t, expectedCommit, actualCommit)
 	lastCommitMsg, err := workTree.CommitMessage("HEAD")
 	require.NoError(t, err)
-	require.Equal(t, "Initial commit", lastCommitMsg)
+	require.Equal(
+		t,
+		"Initial commit\n\nKargo-Promotion: fake-project/fake-promotion",
+		lastCommitMsg,
+	)
+	require.Equal(
+		t,
+		"Kargo-Promotion: fake-project/fake-promotion",
+		res.Output[stateKeyCommitTrailers],
+	)
+}
+
+func Test_buildCommitTrailers(t *testing.T) {
+	stepCtx := &PromotionStepContext{
+		Project:   "fake-project",
+		Promotion: "fake-promotion",
+	}
+	stepCtx.Freight.UpdateOrPush(
+		kargoapi.FreightReference{
+			Name:   "fake-freight-1",
+			Origin: kargoapi.FreightOrigin{Kind: kargoapi.FreightOriginKindWarehouse, Name: "a"},
+			Images: []kargoapi.Image{
+				{RepoURL: "ghcr.io/example/app", Tag: "v1.2.3"},
+				{RepoURL: "ghcr.io/example/sidecar", Digest: "sha256:abc"},
+			},
+			Charts: []kargoapi.Chart{
+				{RepoURL: "oci://ghcr.io/example/charts/app", Version: "1.4.2"},
+			},
+			Commits: []kargoapi.GitCommit{{ID: "1234567"}},
+		},
+		kargoapi.FreightReference{
+			Name:   "fake-freight-2",
+			Origin: kargoapi.FreightOrigin{Kind: kargoapi.FreightOriginKindWarehouse, Name: "b"},
+			Images: []kargoapi.Image{
+				{RepoURL: "ghcr.io/example/app", Tag: "v1.2.3"},
+			},
+			Charts: []kargoapi.Chart{
+				{RepoURL: "https://charts.example.com/", Name: "sidecar", Version: "2.0.0"},
+			},
+			Commits: []kargoapi.GitCommit{{ID: "89abcde"}},
+		},
+	)
+	require.Equal(
+		t,
+		kargoapi.CommitTrailers{
+			Promotion: "fake-project/fake-promotion",
+			Images:    []string{"ghcr.io/example/app:v1.2.3", "ghcr.io/example/sidecar@sha256:abc"},
+			Charts: []string{
+				"oci://ghcr.io/example/charts/app:1.4.2",
+				"https://charts.example.com/sidecar:2.0.0",
+			},
+			SourceCommits: []string{"1234567", "89abcde"},
+		},
+		buildCommitTrailers(stepCtx),
+	)
 }
 
 func Test_gitCommitter_buildCommitMessage(t *testing.T) {
@@ -0,0 +1,80 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// getGitCredentials returns the credentials, if any, that apply to the Git
+// repository with the provided URL. If the GitIdentity of the provided
+// PromotionStepContext specifies a Secret holding credentials, they are taken
+// from that Secret. If there are none, nil is returned.
+func getGitCredentials(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	repoURL string,
+) (*git.RepoCredentials, error) {
+	var creds credentials.Credentials
+	var found bool
+	var err error
+	if secret := gitCredentialsSecret(stepCtx); secret != "" {
+		creds, found, err = credentials.GetFromSecret(
+			ctx,
+			stepCtx.CredentialsDB,
+			stepCtx.Project,
+			secret,
+			credentials.TypeGit,
+			repoURL,
+		)
+	} else {
+		creds, found, err = stepCtx.CredentialsDB.Get(
+			ctx,
+			stepCtx.Project,
+			credentials.TypeGit,
+			repoURL,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials for %s: %w", repoURL, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &git.RepoCredentials{
+		Username:      creds.Username,
+		Password:      creds.Password,
+		SSHPrivateKey: creds.SSHPrivateKey,
+		HTTPAuthMode:  git.HTTPAuthMode(creds.HTTPAuthMode),
+	}, nil
+}
+
+// refreshGitCredentials evicts any cached credentials for the Git repository
+// with the provided URL, which the remote has rejected, and looks them up
+// again. The credentials that were found, if any, are returned along with
+// whether they differ from the rejected ones. Retrying an operation that
+// failed is only worthwhile if they do.
+func refreshGitCredentials(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	repoURL string,
+	rejected *git.RepoCredentials,
+) (*git.RepoCredentials, bool, error) {
+	credentials.Invalidate(stepCtx.CredentialsDB, stepCtx.Project, credentials.TypeGit, repoURL)
+	creds, err := getGitCredentials(ctx, stepCtx, repoURL)
+	if err != nil {
+		return nil, false, err
+	}
+	changed := (creds == nil) != (rejected == nil) ||
+		(creds != nil && *creds != *rejected)
+	if changed {
+		logging.LoggerFromContext(ctx).Info(
+			"credentials were rejected by remote; retrying with refreshed credentials",
+			"repo", repoURL,
+		)
+	}
+	return creds, changed, nil
+}
@@ -0,0 +1,168 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func Test_getGitCredentials(t *testing.T) {
+	testCases := []struct {
+		name       string
+		db         credentials.Database
+		identity   *kargoapi.GitIdentity
+		assertions func(*testing.T, *git.RepoCredentials, error)
+	}{
+		{
+			name: "error getting credentials",
+			db: &credentials.FakeDB{
+				GetFn: func(
+					context.Context,
+					string,
+					credentials.Type,
+					string,
+				) (credentials.Credentials, bool, error) {
+					return credentials.Credentials{}, false, errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, _ *git.RepoCredentials, err error) {
+				require.ErrorContains(t, err, "error getting credentials for https://github.com/example/repo")
+				require.ErrorContains(t, err, "something went wrong")
+			},
+		},
+		{
+			name: "no credentials found",
+			db:   &credentials.FakeDB{},
+			assertions: func(t *testing.T, creds *git.RepoCredentials, err error) {
+				require.NoError(t, err)
+				require.Nil(t, creds)
+			},
+		},
+		{
+			name: "credentials found",
+			db: &credentials.FakeDB{
+				GetFn: func(
+					context.Context,
+					string,
+					credentials.Type,
+					string,
+				) (credentials.Credentials, bool, error) {
+					return credentials.Credentials{Username: "kargo", Password: "fake-password"}, true, nil
+				},
+			},
+			assertions: func(t *testing.T, creds *git.RepoCredentials, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &git.RepoCredentials{Username: "kargo", Password: "fake-password"}, creds)
+			},
+		},
+		{
+			name: "credentials taken from Secret of git identity",
+			db: &credentials.FakeDB{
+				GetFn: func(
+					context.Context,
+					string,
+					credentials.Type,
+					string,
+				) (credentials.Credentials, bool, error) {
+					return credentials.Credentials{Username: "kargo", Password: "fake-password"}, true, nil
+				},
+				GetFromSecretFn: func(
+					_ context.Context,
+					namespace string,
+					name string,
+					_ credentials.Type,
+					_ string,
+				) (credentials.Credentials, bool, error) {
+					if namespace != "fake-project" || name != "prod-bot" {
+						return credentials.Credentials{}, false, errors.New("unexpected Secret")
+					}
+					return credentials.Credentials{Username: "prod-bot", Password: "fake-password"}, true, nil
+				},
+			},
+			identity: &kargoapi.GitIdentity{Name: "Prod Bot", CredentialsSecret: "prod-bot"},
+			assertions: func(t *testing.T, creds *git.RepoCredentials, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &git.RepoCredentials{Username: "prod-bot", Password: "fake-password"}, creds)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			creds, err := getGitCredentials(
+				context.Background(),
+				&PromotionStepContext{
+					Project:       "fake-project",
+					CredentialsDB: testCase.db,
+					GitIdentity:   testCase.identity,
+				},
+				"https://github.com/example/repo",
+			)
+			testCase.assertions(t, creds, err)
+		})
+	}
+}
+
+func Test_refreshGitCredentials(t *testing.T) {
+	const testRepoURL = "https://github.com/example/repo"
+	var password string
+	var lookups int
+	stepCtx := &PromotionStepContext{
+		Project: "fake-project",
+		// Wrapping the cache the way the engine does makes sure invalidation
+		// reaches it.
+		CredentialsDB: &repoPolicyCredentialsDB{
+			Database: credentials.NewCachingDatabase(
+				&credentials.FakeDB{
+					GetFn: func(
+						context.Context,
+						string,
+						credentials.Type,
+						string,
+					) (credentials.Credentials, bool, error) {
+						lookups++
+						if password == "" {
+							return credentials.Credentials{}, false, nil
+						}
+						return credentials.Credentials{Username: "kargo", Password: password}, true, nil
+					},
+				},
+				time.Hour,
+			),
+			exec: &promotionExecution{},
+		},
+	}
+
+	password = "previous-password"
+	rejected, err := getGitCredentials(context.Background(), stepCtx, testRepoURL)
+	require.NoError(t, err)
+	require.Equal(t, 1, lookups)
+
+	// The credentials were not rotated.
+	creds, changed, err := refreshGitCredentials(context.Background(), stepCtx, testRepoURL, rejected)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, rejected, creds)
+	require.Equal(t, 2, lookups)
+
+	// The credentials were rotated, although they were still cached.
+	password = "current-password"
+	creds, changed, err = refreshGitCredentials(context.Background(), stepCtx, testRepoURL, rejected)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "current-password", creds.Password)
+	require.Equal(t, 3, lookups)
+
+	// The credentials were deleted.
+	password = ""
+	creds, changed, err = refreshGitCredentials(context.Background(), stepCtx, testRepoURL, rejected)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Nil(t, creds)
+}
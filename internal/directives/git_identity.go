@@ -0,0 +1,31 @@
+package directives
+
+import (
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+// gitCredentialsSecret returns the name of the Secret that the GitIdentity of
+// the provided PromotionStepContext takes Git credentials from, or an empty
+// string if it does not specify one.
+func gitCredentialsSecret(stepCtx *PromotionStepContext) string {
+	if stepCtx.GitIdentity == nil {
+		return ""
+	}
+	return stepCtx.GitIdentity.CredentialsSecret
+}
+
+// gitIdentityUser returns the provided user, which commits are made as by
+// default, with its name and email replaced by those specified by the
+// GitIdentity of the provided PromotionStepContext, if any. The signing key of
+// the provided user is retained.
+func gitIdentityUser(stepCtx *PromotionStepContext, user git.User) git.User {
+	if id := stepCtx.GitIdentity; id != nil {
+		if id.Name != "" {
+			user.Name = id.Name
+		}
+		if id.Email != "" {
+			user.Email = id.Email
+		}
+	}
+	return user
+}
@@ -11,10 +11,10 @@ import (
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/git"
-	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/gitprovider"
 
 	_ "github.com/akuity/kargo/internal/gitprovider/azure"  // Azure provider registration
+	_ "github.com/akuity/kargo/internal/gitprovider/gerrit" // Gerrit provider registration
 	_ "github.com/akuity/kargo/internal/gitprovider/github" // GitHub provider registration
 	_ "github.com/akuity/kargo/internal/gitprovider/gitlab" // GitLab provider registration
 )
@@ -96,23 +96,9 @@ func (g *gitPROpener) runPromotionStep(
 			fmt.Errorf("error determining source branch: %w", err)
 	}
 
-	var repoCreds *git.RepoCredentials
-	creds, found, err := stepCtx.CredentialsDB.Get(
-		ctx,
-		stepCtx.Project,
-		credentials.TypeGit,
-		cfg.RepoURL,
-	)
+	repoCreds, err := getGitCredentials(ctx, stepCtx, cfg.RepoURL)
 	if err != nil {
-		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
-			fmt.Errorf("error getting credentials for %s: %w", cfg.RepoURL, err)
-	}
-	if found {
-		repoCreds = &git.RepoCredentials{
-			Username:      creds.Username,
-			Password:      creds.Password,
-			SSHPrivateKey: creds.SSHPrivateKey,
-		}
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
 	repo, err := git.Clone(
@@ -136,6 +122,7 @@ func (g *gitPROpener) runPromotionStep(
 		InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
 	}
 	if repoCreds != nil {
+		gpOpts.Username = repoCreds.Username
 		gpOpts.Token = repoCreds.Password
 	}
 	if cfg.Provider != nil {
@@ -2,14 +2,16 @@ package directives
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/git"
-	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/gitprovider"
+	"github.com/akuity/kargo/internal/logging"
 )
 
 func init() {
@@ -22,13 +24,14 @@ func init() {
 // gitPRWaiter is an implementation of the PromotionStepRunner interface that
 // waits for a pull request to be merged or closed unmerged.
 type gitPRWaiter struct {
-	schemaLoader gojsonschema.JSONLoader
+	schemaLoader         gojsonschema.JSONLoader
+	deleteRemoteBranchFn func(string, string, *git.ClientOptions) error
 }
 
 // newGitPRWaiter returns an implementation of the PromotionStepRunner interface
 // that waits for a pull request to be merged or closed unmerged.
 func newGitPRWaiter() PromotionStepRunner {
-	r := &gitPRWaiter{}
+	r := &gitPRWaiter{deleteRemoteBranchFn: git.DeleteRemoteBranch}
 	r.schemaLoader = getConfigSchemaLoader(r.Name())
 	return r
 }
@@ -70,29 +73,16 @@ func (g *gitPRWaiter) runPromotionStep(
 			fmt.Errorf("error getting PR number from shared state: %w", err)
 	}
 
-	var repoCreds *git.RepoCredentials
-	creds, found, err := stepCtx.CredentialsDB.Get(
-		ctx,
-		stepCtx.Project,
-		credentials.TypeGit,
-		cfg.RepoURL,
-	)
+	repoCreds, err := getGitCredentials(ctx, stepCtx, cfg.RepoURL)
 	if err != nil {
-		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
-			fmt.Errorf("error getting credentials for %s: %w", cfg.RepoURL, err)
-	}
-	if found {
-		repoCreds = &git.RepoCredentials{
-			Username:      creds.Username,
-			Password:      creds.Password,
-			SSHPrivateKey: creds.SSHPrivateKey,
-		}
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
 	gpOpts := &gitprovider.Options{
 		InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
 	}
 	if repoCreds != nil {
+		gpOpts.Username = repoCreds.Username
 		gpOpts.Token = repoCreds.Password
 	}
 	if cfg.Provider != nil {
@@ -113,6 +103,9 @@ func (g *gitPRWaiter) runPromotionStep(
 	if pr.Open {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseRunning}, nil
 	}
+	if cfg.DeleteSourceBranch {
+		g.deleteSourceBranch(ctx, cfg, pr, repoCreds)
+	}
 	if !pr.Merged {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
 			&terminalError{err: fmt.Errorf("pull request %d was closed without being merged", prNumber)}
@@ -123,6 +116,48 @@ func (g *gitPRWaiter) runPromotionStep(
 	}, nil
 }
 
+// deleteSourceBranch deletes the source branch of the provided pull request
+// from the remote repository if it is a branch generated by the git-push step.
+// Branches that were not generated by Kargo may be used for other purposes and
+// are never deleted. Because the pull request has already been merged or
+// closed by the time this is called, a failure to delete the branch is logged,
+// but is not treated as an error.
+func (g *gitPRWaiter) deleteSourceBranch(
+	ctx context.Context,
+	cfg GitWaitForPRConfig,
+	pr *gitprovider.PullRequest,
+	repoCreds *git.RepoCredentials,
+) {
+	if pr.HeadBranch == "" {
+		return
+	}
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"repo", cfg.RepoURL,
+		"branch", pr.HeadBranch,
+	)
+	if !strings.HasPrefix(pr.HeadBranch, PromotionBranchPrefix) {
+		logger.Debug("pull request source branch was not generated by Kargo; not deleting it")
+		return
+	}
+	err := g.deleteRemoteBranchFn(
+		cfg.RepoURL,
+		pr.HeadBranch,
+		&git.ClientOptions{
+			Credentials:           repoCreds,
+			InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
+		},
+	)
+	switch {
+	case errors.Is(err, git.ErrRemoteBranchNotFound):
+		// The Git provider may already have deleted the branch
+		logger.Debug("pull request source branch was already deleted")
+	case err != nil:
+		logger.Error(err, "error deleting pull request source branch")
+	default:
+		logger.Debug("deleted pull request source branch")
+	}
+}
+
 // getPRNumber checks shared state for output from a previous step and returns
 // any PR number from that output. If no such output is found, the output
 // contains no PR number, or the PR number is not an int64 or float64, then an
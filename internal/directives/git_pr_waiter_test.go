@@ -10,6 +10,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
 	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/gitprovider"
 )
@@ -210,3 +211,63 @@ func Test_gitPRWaiter_runPromotionStep(t *testing.T) {
 		})
 	}
 }
+
+func Test_gitPRWaiter_deleteSourceBranch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		pr        *gitprovider.PullRequest
+		deleteErr error
+		deleted   []string
+	}{
+		{
+			name: "PR has no source branch",
+			pr:   &gitprovider.PullRequest{},
+		},
+		{
+			name: "source branch was not generated by Kargo",
+			pr:   &gitprovider.PullRequest{HeadBranch: "feature/foo"},
+		},
+		{
+			name:    "source branch is deleted",
+			pr:      &gitprovider.PullRequest{HeadBranch: "kargo/promotion/foo"},
+			deleted: []string{"kargo/promotion/foo"},
+		},
+		{
+			name:      "source branch was already deleted",
+			pr:        &gitprovider.PullRequest{HeadBranch: "kargo/promotion/foo"},
+			deleteErr: git.ErrRemoteBranchNotFound,
+			deleted:   []string{"kargo/promotion/foo"},
+		},
+		{
+			name:      "error deleting source branch",
+			pr:        &gitprovider.PullRequest{HeadBranch: "kargo/promotion/foo"},
+			deleteErr: errors.New("something went wrong"),
+			deleted:   []string{"kargo/promotion/foo"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var deleted []string
+			runner := &gitPRWaiter{
+				deleteRemoteBranchFn: func(
+					repoURL string,
+					branch string,
+					_ *git.ClientOptions,
+				) error {
+					require.Equal(t, "https://github.com/example/repo.git", repoURL)
+					deleted = append(deleted, branch)
+					return testCase.deleteErr
+				},
+			}
+			// Errors are only logged, so all that can be asserted is which
+			// branches deletion was attempted for
+			runner.deleteSourceBranch(
+				context.Background(),
+				GitWaitForPRConfig{RepoURL: "https://github.com/example/repo.git"},
+				testCase.pr,
+				nil,
+			)
+			require.Equal(t, testCase.deleted, deleted)
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+// stateKeyDrift is the key used to store the drift of a branch in the output of
+// the git-push health check.
+const stateKeyDrift = "drift"
+
+// DriftBranchPrefix is the prefix of the names of branches pushed by the
+// git-push health check to capture the drift of a branch as a patch. The
+// remainder of such a branch's name is the name of the Stage and the
+// abbreviated ID of the drifted commit.
+const DriftBranchPrefix = "kargo/drift/"
+
+// driftPatchDir is the directory, relative to the root of a repository, to
+// which patches capturing the drift of a branch are committed.
+const driftPatchDir = ".kargo/drift"
+
+// maxInlinePatchSize is the maximum size of a patch that is included in the
+// description of a pull request in addition to being committed.
+const maxInlinePatchSize = 16 * 1024
+
+// GitPushHealthConfig is the configuration for a health check to be executed by
+// the git-push directive.
+type GitPushHealthConfig struct {
+	// RepoURL is the URL of the remote repository that was pushed to.
+	RepoURL string `json:"repoURL"`
+	// Branch is the name of the remote branch that was pushed to.
+	Branch string `json:"branch"`
+	// Commit is the ID (SHA) of the commit that was pushed.
+	Commit string `json:"commit"`
+	// PullRequest configures the pull request that is opened to port the
+	// changes if the branch has drifted. If nil, no pull request is opened.
+	PullRequest *GitPushHealthPullRequestConfig `json:"pullRequest,omitempty"`
+}
+
+// GitPushHealthPullRequestConfig configures the pull request that is opened by
+// the git-push health check when a branch has drifted.
+type GitPushHealthPullRequestConfig struct {
+	// RepoURL is the URL of the repository to open the pull request in.
+	RepoURL string `json:"repoURL"`
+	// TargetBranch is the branch to open the pull request against.
+	TargetBranch string `json:"targetBranch"`
+	// Provider is the name of the Git provider to use. If empty, it is
+	// inferred from RepoURL.
+	Provider string `json:"provider,omitempty"`
+}
+
+// RunHealthCheckStep implements the HealthCheckStepRunner interface.
+func (g *gitPushPusher) RunHealthCheckStep(
+	ctx context.Context,
+	healthCtx *HealthCheckStepContext,
+) HealthCheckStepResult {
+	cfg, err := ConfigToStruct[GitPushHealthConfig](healthCtx.Config)
+	if err != nil {
+		return HealthCheckStepResult{
+			Status: kargoapi.HealthStateUnknown,
+			Issues: []string{
+				fmt.Sprintf(
+					"could not convert config into %s health check config: %s",
+					g.Name(), err.Error(),
+				),
+			},
+		}
+	}
+	return g.runHealthCheckStep(ctx, healthCtx, cfg)
+}
+
+func (g *gitPushPusher) runHealthCheckStep(
+	ctx context.Context,
+	healthCtx *HealthCheckStepContext,
+	cfg GitPushHealthConfig,
+) HealthCheckStepResult {
+	clientOpts, err := getGitClientOptions(ctx, healthCtx, cfg.RepoURL)
+	if err != nil {
+		return HealthCheckStepResult{
+			Status: kargoapi.HealthStateUnknown,
+			Issues: []string{err.Error()},
+		}
+	}
+	commitID, err := g.getRemoteBranchCommitFn(cfg.RepoURL, cfg.Branch, clientOpts)
+	if err != nil {
+		return HealthCheckStepResult{
+			Status: kargoapi.HealthStateUnknown,
+			Issues: []string{err.Error()},
+		}
+	}
+	if commitID == cfg.Commit {
+		return HealthCheckStepResult{Status: kargoapi.HealthStateHealthy}
+	}
+
+	drift := &kargoapi.Drift{
+		RepoURL:        cfg.RepoURL,
+		Branch:         cfg.Branch,
+		PromotedCommit: cfg.Commit,
+		DriftedCommit:  commitID,
+	}
+	res := HealthCheckStepResult{
+		Status: kargoapi.HealthStateUnhealthy,
+		Issues: []string{
+			fmt.Sprintf(
+				"branch %q of repo %s has drifted: it points to commit %q instead "+
+					"of commit %q, which was pushed by the last Promotion",
+				cfg.Branch, cfg.RepoURL, commitID, cfg.Commit,
+			),
+		},
+		Output: map[string]any{stateKeyDrift: drift},
+	}
+	if cfg.PullRequest != nil {
+		if drift.PullRequest, err = g.ensureDriftPullRequest(
+			ctx, healthCtx, cfg, clientOpts, drift,
+		); err != nil {
+			res.Issues = append(res.Issues, fmt.Sprintf(
+				"error opening pull request for drift of branch %q: %s",
+				cfg.Branch, err,
+			))
+		}
+	}
+	return res
+}
+
+// ensureDriftPullRequest ensures that a pull request containing the changes of
+// the provided Drift as a patch exists, and returns a reference to it. The
+// branch of the pull request is specific to the drifted commit, so any pull
+// request that was opened for the same drift before, whether it is open,
+// merged, or closed, is returned instead of opening another one.
+func (g *gitPushPusher) ensureDriftPullRequest(
+	ctx context.Context,
+	healthCtx *HealthCheckStepContext,
+	cfg GitPushHealthConfig,
+	clientOpts *git.ClientOptions,
+	drift *kargoapi.Drift,
+) (*kargoapi.DriftPullRequest, error) {
+	prCfg := cfg.PullRequest
+	prClientOpts := clientOpts
+	if prCfg.RepoURL != cfg.RepoURL {
+		var err error
+		if prClientOpts, err = getGitClientOptions(ctx, healthCtx, prCfg.RepoURL); err != nil {
+			return nil, err
+		}
+	}
+	gpOpts := &gitprovider.Options{Name: prCfg.Provider}
+	if prClientOpts.Credentials != nil {
+		gpOpts.Username = prClientOpts.Credentials.Username
+		gpOpts.Token = prClientOpts.Credentials.Password
+	}
+	gitProv, err := g.newGitProviderFn(prCfg.RepoURL, gpOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating git provider service: %w", err)
+	}
+
+	ref := &kargoapi.DriftPullRequest{
+		RepoURL: prCfg.RepoURL,
+		Branch:  DriftBranchPrefix + healthCtx.Stage + "/" + shortCommitID(drift.DriftedCommit),
+		PatchPath: path.Join(
+			driftPatchDir, healthCtx.Stage, drift.DriftedCommit+".patch",
+		),
+	}
+	prs, err := gitProv.ListPullRequests(ctx, &gitprovider.ListPullRequestOptions{
+		State:      gitprovider.PullRequestStateAny,
+		HeadBranch: ref.Branch,
+		BaseBranch: prCfg.TargetBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pull requests: %w", err)
+	}
+	if len(prs) > 0 {
+		// A merged pull request takes precedence, as it resolves the drift.
+		i := slices.IndexFunc(prs, func(pr gitprovider.PullRequest) bool {
+			return pr.Merged
+		})
+		pr := prs[max(i, 0)]
+		ref.Number, ref.URL, ref.Merged = pr.Number, pr.URL, pr.Merged
+		return ref, nil
+	}
+
+	patch, err := g.pushDriftPatch(cfg, clientOpts, prClientOpts, drift, ref)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := gitProv.CreatePullRequest(ctx, &gitprovider.CreatePullRequestOpts{
+		Head:  ref.Branch,
+		Base:  prCfg.TargetBranch,
+		Title: fmt.Sprintf("Port changes made to branch %s (Stage %s)", cfg.Branch, healthCtx.Stage),
+		Description: driftPullRequestDescription(
+			healthCtx, drift, ref, patch, prCfg.TargetBranch,
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating pull request: %w", err)
+	}
+	ref.Number, ref.URL = pr.Number, pr.URL
+	return ref, nil
+}
+
+// pushDriftPatch captures the changes of the provided Drift as a patch, commits
+// the patch on top of the target branch of the pull request, and pushes the
+// commit to the branch referenced by the provided DriftPullRequest. The patch
+// is returned.
+func (g *gitPushPusher) pushDriftPatch(
+	cfg GitPushHealthConfig,
+	clientOpts *git.ClientOptions,
+	prClientOpts *git.ClientOptions,
+	drift *kargoapi.Drift,
+	ref *kargoapi.DriftPullRequest,
+) ([]byte, error) {
+	drifted, err := git.Clone(cfg.RepoURL, clientOpts, &git.CloneOptions{
+		Branch:       cfg.Branch,
+		SingleBranch: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error cloning %s: %w", cfg.RepoURL, err)
+	}
+	defer drifted.Close()
+	patch, err := drifted.Diff(drift.PromotedCommit, drift.DriftedCommit)
+	if err != nil {
+		return nil, fmt.Errorf("error capturing drift as a patch: %w", err)
+	}
+
+	source, err := git.Clone(cfg.PullRequest.RepoURL, prClientOpts, &git.CloneOptions{
+		Branch:       cfg.PullRequest.TargetBranch,
+		SingleBranch: true,
+		Depth:        1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error cloning %s: %w", cfg.PullRequest.RepoURL, err)
+	}
+	defer source.Close()
+	if err = source.CreateChildBranch(ref.Branch); err != nil {
+		return nil, err
+	}
+	patchPath := filepath.Join(source.Dir(), filepath.FromSlash(ref.PatchPath))
+	if err = os.MkdirAll(filepath.Dir(patchPath), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory for patch: %w", err)
+	}
+	if err = os.WriteFile(patchPath, patch, 0o600); err != nil {
+		return nil, fmt.Errorf("error writing patch: %w", err)
+	}
+	if err = source.AddAllAndCommit(fmt.Sprintf(
+		"Capture changes made to branch %s in commit %s",
+		cfg.Branch, drift.DriftedCommit,
+	)); err != nil {
+		return nil, err
+	}
+	// The branch may exist if a previous attempt to open the pull request
+	// failed after it was pushed.
+	if err = source.Push(&git.PushOptions{
+		TargetBranch: ref.Branch,
+		Force:        true,
+	}); err != nil {
+		return nil, fmt.Errorf("error pushing patch to branch %q: %w", ref.Branch, err)
+	}
+	return patch, nil
+}
+
+// driftPullRequestDescription returns the description of a pull request that
+// contains the changes of the provided Drift as a patch.
+func driftPullRequestDescription(
+	healthCtx *HealthCheckStepContext,
+	drift *kargoapi.Drift,
+	ref *kargoapi.DriftPullRequest,
+	patch []byte,
+	targetBranch string,
+) string {
+	description := fmt.Sprintf(
+		"Branch `%s` of %s was changed after it was last promoted to Stage `%s` "+
+			"of Project `%s`: it points to commit `%s` instead of commit `%s`, "+
+			"which was pushed by the last Promotion.\n\n"+
+			"The changes are attached as a patch in `%s`. To keep them, port them "+
+			"to `%s`, which the promoted content is derived from, and remove the "+
+			"patch before merging. Otherwise, they are overwritten by the next "+
+			"Promotion.\n\n"+
+			"Further Promotions to the Stage are blocked until this pull request "+
+			"is merged, or until the drift is acknowledged by annotating the Stage "+
+			"with `%s: %s`.",
+		drift.Branch, drift.RepoURL, healthCtx.Stage, healthCtx.Project,
+		drift.DriftedCommit, drift.PromotedCommit,
+		ref.PatchPath, targetBranch,
+		kargoapi.AnnotationKeyAcknowledgeDrift, drift.DriftedCommit,
+	)
+	if len(patch) <= maxInlinePatchSize {
+		description += fmt.Sprintf("\n\n```diff\n%s```", patch)
+	}
+	return description
+}
+
+// getGitClientOptions returns the options for a git client that uses the
+// credentials, if any, that are applicable to the repository with the
+// provided URL.
+func getGitClientOptions(
+	ctx context.Context,
+	healthCtx *HealthCheckStepContext,
+	repoURL string,
+) (*git.ClientOptions, error) {
+	clientOpts := &git.ClientOptions{}
+	creds, found, err := healthCtx.CredentialsDB.Get(
+		ctx,
+		healthCtx.Project,
+		credentials.TypeGit,
+		repoURL,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials for %s: %w", repoURL, err)
+	}
+	if found {
+		clientOpts.Credentials = &git.RepoCredentials{
+			Username:      creds.Username,
+			Password:      creds.Password,
+			SSHPrivateKey: creds.SSHPrivateKey,
+			HTTPAuthMode:  git.HTTPAuthMode(creds.HTTPAuthMode),
+		}
+	}
+	return clientOpts, nil
+}
+
+// shortCommitID returns the abbreviated form of the provided commit ID.
+func shortCommitID(commitID string) string {
+	if len(commitID) > 12 {
+		return commitID[:12]
+	}
+	return commitID
+}
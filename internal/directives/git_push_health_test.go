@@ -0,0 +1,281 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+func Test_gitPusher_runHealthCheckStep(t *testing.T) {
+	testCfg := GitPushHealthConfig{
+		RepoURL: "https://github.com/example/repo.git",
+		Branch:  "env/prod",
+		Commit:  "fake-commit",
+	}
+	testCases := []struct {
+		name                    string
+		credsDB                 credentials.Database
+		getRemoteBranchCommitFn func(string, string, *git.ClientOptions) (string, error)
+		assertions              func(*testing.T, HealthCheckStepResult)
+	}{
+		{
+			name: "error getting credentials",
+			credsDB: &credentials.FakeDB{
+				GetFn: func(
+					context.Context,
+					string,
+					credentials.Type,
+					string,
+				) (credentials.Credentials, bool, error) {
+					return credentials.Credentials{}, false, errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Equal(t, kargoapi.HealthStateUnknown, res.Status)
+				require.Len(t, res.Issues, 1)
+				require.Contains(t, res.Issues[0], "error getting credentials")
+			},
+		},
+		{
+			name:    "error getting remote branch commit",
+			credsDB: &credentials.FakeDB{},
+			getRemoteBranchCommitFn: func(string, string, *git.ClientOptions) (string, error) {
+				return "", git.ErrRemoteBranchNotFound
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Equal(t, kargoapi.HealthStateUnknown, res.Status)
+				require.Equal(t, []string{git.ErrRemoteBranchNotFound.Error()}, res.Issues)
+			},
+		},
+		{
+			name:    "branch has drifted",
+			credsDB: &credentials.FakeDB{},
+			getRemoteBranchCommitFn: func(string, string, *git.ClientOptions) (string, error) {
+				return "other-commit", nil
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Equal(t, kargoapi.HealthStateUnhealthy, res.Status)
+				require.Len(t, res.Issues, 1)
+				require.Contains(t, res.Issues[0], "has drifted")
+				require.Equal(t, &kargoapi.Drift{
+					RepoURL:        testCfg.RepoURL,
+					Branch:         testCfg.Branch,
+					PromotedCommit: testCfg.Commit,
+					DriftedCommit:  "other-commit",
+				}, res.Output[stateKeyDrift])
+			},
+		},
+		{
+			name: "branch has not drifted",
+			credsDB: &credentials.FakeDB{
+				GetFn: func(
+					context.Context,
+					string,
+					credentials.Type,
+					string,
+				) (credentials.Credentials, bool, error) {
+					return credentials.Credentials{
+						Username: "fake-username",
+						Password: "fake-password",
+					}, true, nil
+				},
+			},
+			getRemoteBranchCommitFn: func(
+				repoURL string,
+				branch string,
+				clientOpts *git.ClientOptions,
+			) (string, error) {
+				require.Equal(t, testCfg.RepoURL, repoURL)
+				require.Equal(t, testCfg.Branch, branch)
+				require.Equal(t, "fake-password", clientOpts.Credentials.Password)
+				return "fake-commit", nil
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Equal(t, kargoapi.HealthStateHealthy, res.Status)
+				require.Empty(t, res.Issues)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			runner := &gitPushPusher{
+				getRemoteBranchCommitFn: testCase.getRemoteBranchCommitFn,
+			}
+			res := runner.runHealthCheckStep(
+				context.Background(),
+				&HealthCheckStepContext{
+					Project:       "fake-project",
+					CredentialsDB: testCase.credsDB,
+				},
+				testCfg,
+			)
+			testCase.assertions(t, res)
+		})
+	}
+}
+
+func Test_gitPusher_runHealthCheckStep_driftPullRequest(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+
+	repo, err := git.Clone(remoteDir, nil, nil)
+	require.NoError(t, err)
+	defer repo.Close()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo.Dir()
+		cmd.Env = []string{"HOME=" + repo.HomeDir()}
+		res, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(res))
+		return strings.TrimSpace(string(res))
+	}
+
+	// The source branch and the Stage's branch start out the same. The Stage's
+	// branch is then changed by hand.
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Dir(), "values.yaml"), []byte("replicas: 1\n"), 0o600))
+	run("add", ".")
+	run("commit", "-m", "initial")
+	promoted := run("rev-parse", "HEAD")
+	run("push", "origin", "HEAD:main", "HEAD:env/prod")
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Dir(), "values.yaml"), []byte("replicas: 3\n"), 0o600))
+	run("commit", "-am", "hotfix")
+	drifted := run("rev-parse", "HEAD")
+	run("push", "origin", "HEAD:env/prod")
+
+	cfg := GitPushHealthConfig{
+		RepoURL: remoteDir,
+		Branch:  "env/prod",
+		Commit:  promoted,
+		PullRequest: &GitPushHealthPullRequestConfig{
+			RepoURL:      remoteDir,
+			TargetBranch: "main",
+		},
+	}
+	expectedBranch := DriftBranchPrefix + "fake-stage/" + drifted[:12]
+	expectedPatchPath := ".kargo/drift/fake-stage/" + drifted + ".patch"
+
+	testCases := []struct {
+		name       string
+		provider   *gitprovider.Fake
+		assertions func(*testing.T, HealthCheckStepResult)
+	}{
+		{
+			name: "error listing pull requests",
+			provider: &gitprovider.Fake{
+				ListPullRequestsFn: func(
+					context.Context,
+					*gitprovider.ListPullRequestOptions,
+				) ([]gitprovider.PullRequest, error) {
+					return nil, errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Equal(t, kargoapi.HealthStateUnhealthy, res.Status)
+				require.Len(t, res.Issues, 2)
+				require.Contains(t, res.Issues[1], "error listing pull requests")
+				drift, ok := res.Output[stateKeyDrift].(*kargoapi.Drift)
+				require.True(t, ok)
+				require.Equal(t, drifted, drift.DriftedCommit)
+				require.Nil(t, drift.PullRequest)
+			},
+		},
+		{
+			name: "merged pull request exists",
+			provider: &gitprovider.Fake{
+				ListPullRequestsFn: func(
+					_ context.Context,
+					opts *gitprovider.ListPullRequestOptions,
+				) ([]gitprovider.PullRequest, error) {
+					require.Equal(t, expectedBranch, opts.HeadBranch)
+					require.Equal(t, "main", opts.BaseBranch)
+					return []gitprovider.PullRequest{
+						{Number: 1, URL: "https://example.com/pulls/1"},
+						{Number: 2, URL: "https://example.com/pulls/2", Merged: true},
+					}, nil
+				},
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Len(t, res.Issues, 1)
+				drift, ok := res.Output[stateKeyDrift].(*kargoapi.Drift)
+				require.True(t, ok)
+				require.Equal(t, &kargoapi.DriftPullRequest{
+					RepoURL:   remoteDir,
+					Number:    2,
+					URL:       "https://example.com/pulls/2",
+					Branch:    expectedBranch,
+					PatchPath: expectedPatchPath,
+					Merged:    true,
+				}, drift.PullRequest)
+			},
+		},
+		{
+			name: "pull request is opened",
+			provider: &gitprovider.Fake{
+				ListPullRequestsFn: func(
+					context.Context,
+					*gitprovider.ListPullRequestOptions,
+				) ([]gitprovider.PullRequest, error) {
+					return nil, nil
+				},
+				CreatePullRequestFn: func(
+					_ context.Context,
+					opts *gitprovider.CreatePullRequestOpts,
+				) (*gitprovider.PullRequest, error) {
+					require.Equal(t, expectedBranch, opts.Head)
+					require.Equal(t, "main", opts.Base)
+					require.Contains(t, opts.Description, kargoapi.AnnotationKeyAcknowledgeDrift+": "+drifted)
+					require.Contains(t, opts.Description, "+replicas: 3")
+					return &gitprovider.PullRequest{Number: 3, URL: "https://example.com/pulls/3"}, nil
+				},
+			},
+			assertions: func(t *testing.T, res HealthCheckStepResult) {
+				require.Len(t, res.Issues, 1)
+				drift, ok := res.Output[stateKeyDrift].(*kargoapi.Drift)
+				require.True(t, ok)
+				require.NotNil(t, drift.PullRequest)
+				require.Equal(t, int64(3), drift.PullRequest.Number)
+				require.False(t, drift.PullRequest.Merged)
+
+				// The patch was committed on top of the source branch.
+				patch := run("show", "origin/"+expectedBranch+":"+expectedPatchPath)
+				require.Contains(t, patch, "-replicas: 1")
+				require.Contains(t, patch, "+replicas: 3")
+				require.Equal(t, run("rev-parse", "origin/main"), run("rev-parse", "origin/"+expectedBranch+"^"))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			runner := &gitPushPusher{
+				getRemoteBranchCommitFn: func(string, string, *git.ClientOptions) (string, error) {
+					return drifted, nil
+				},
+				newGitProviderFn: func(string, *gitprovider.Options) (gitprovider.Interface, error) {
+					return testCase.provider, nil
+				},
+			}
+			res := runner.runHealthCheckStep(
+				context.Background(),
+				&HealthCheckStepContext{
+					Project:       "fake-project",
+					Stage:         "fake-stage",
+					CredentialsDB: &credentials.FakeDB{},
+				},
+				cfg,
+			)
+			run("fetch", "origin")
+			testCase.assertions(t, res)
+		})
+	}
+}
@@ -2,27 +2,56 @@ package directives
 
 import (
 	"context"
+	"crypto/sha1"
+	"errors"
 	"fmt"
-	"sync"
+	"strings"
 	"time"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/kelseyhightower/envconfig"
 	"github.com/xeipuuv/gojsonschema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/git"
-	"github.com/akuity/kargo/internal/credentials"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/gitprovider"
+	"github.com/akuity/kargo/internal/gitprovider/gerrit"
 )
 
 // stateKeyBranch is the key used to store the branch that was pushed to in the
 // shared State.
 const stateKeyBranch = "branch"
 
+// stateKeyAdditionalBranches is the key used to store the additional branches
+// that were pushed to, along with the commits pushed to them, in the shared
+// State.
+const stateKeyAdditionalBranches = "additionalBranches"
+
+// stateKeyAtomic is the key used to store whether all branches were pushed to
+// in a single atomic push in the shared State.
+const stateKeyAtomic = "atomic"
+
+// stateKeyChangeID is the key used to store the Change-Id of the Gerrit change
+// that commits were pushed for review to in the shared State.
+const stateKeyChangeID = "changeID"
+
+// PromotionBranchPrefix is the prefix of the names of branches generated by
+// the git-push step when it is configured to generate a target branch. The
+// remainder of such a branch's name is the name of the Promotion that pushed
+// to it.
+const PromotionBranchPrefix = "kargo/promotion/"
+
 func init() {
+	runner := newGitPusher()
 	builtins.RegisterPromotionStepRunner(
-		newGitPusher(),
+		runner,
+		&StepRunnerPermissions{AllowCredentialsDB: true},
+	)
+	builtins.RegisterHealthCheckStepRunner(
+		runner.(HealthCheckStepRunner), // nolint: forcetypeassert
 		&StepRunnerPermissions{AllowCredentialsDB: true},
 	)
 }
@@ -31,15 +60,36 @@ func init() {
 // pushes commits from a local Git repository to a remote Git repository.
 type gitPushPusher struct {
 	schemaLoader gojsonschema.JSONLoader
-	branchMus    map[string]*sync.Mutex
-	masterMu     sync.Mutex
+	branchLocks  *branchLocks
+
+	getRemoteBranchCommitFn func(
+		repoURL string,
+		branch string,
+		clientOpts *git.ClientOptions,
+	) (string, error)
+	deleteRemoteBranchFn func(
+		repoURL string,
+		branch string,
+		clientOpts *git.ClientOptions,
+	) error
+	newGitProviderFn func(
+		repoURL string,
+		opts *gitprovider.Options,
+	) (gitprovider.Interface, error)
 }
 
 // newGitPusher returns an implementation of the PromotionStepRunner interface
 // that pushes commits from a local Git repository to a remote Git repository.
 func newGitPusher() PromotionStepRunner {
+	cfg := struct {
+		LockMaxHoldTime time.Duration `envconfig:"GIT_PUSH_LOCK_MAX_HOLD_TIME" default:"10m"`
+	}{}
+	envconfig.MustProcess("", &cfg)
 	r := &gitPushPusher{
-		branchMus: map[string]*sync.Mutex{},
+		branchLocks:             newBranchLocks(cfg.LockMaxHoldTime),
+		getRemoteBranchCommitFn: git.RemoteBranchCommit,
+		deleteRemoteBranchFn:    git.DeleteRemoteBranch,
+		newGitProviderFn:        gitprovider.New,
 	}
 	r.schemaLoader = getConfigSchemaLoader(r.Name())
 	return r
@@ -76,9 +126,15 @@ func (g *gitPushPusher) runPromotionStep(
 	stepCtx *PromotionStepContext,
 	cfg GitPushConfig,
 ) (PromotionStepResult, error) {
-	// This is kind of hacky, but we needed to load the working tree to get the
-	// URL of the repository. With that in hand, we can look for applicable
-	// credentials and, if found, reload the work tree with the credentials.
+	if cfg.ForReview &&
+		(cfg.GenerateTargetBranch || len(cfg.AdditionalBranches) > 0 || cfg.DetectDrift) {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+			err: errors.New(
+				"forReview is mutually exclusive with generateTargetBranch, " +
+					"additionalBranches, and detectDrift",
+			),
+		}
+	}
 	path, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.Path)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
@@ -86,32 +142,14 @@ func (g *gitPushPusher) runPromotionStep(
 			cfg.Path, stepCtx.WorkDir, err,
 		)
 	}
-	loadOpts := &git.LoadWorkTreeOptions{}
-	workTree, err := git.LoadWorkTree(path, loadOpts)
+	workTree, loadOpts, err := loadWorkTreeWithCredentials(ctx, stepCtx, path)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
 	}
-	var creds credentials.Credentials
-	var found bool
-	if creds, found, err = stepCtx.CredentialsDB.Get(
-		ctx,
-		stepCtx.Project,
-		credentials.TypeGit,
-		workTree.URL(),
-	); err != nil {
-		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
-			fmt.Errorf("error getting credentials for %s: %w", workTree.URL(), err)
-	} else if found {
-		loadOpts.Credentials = &git.RepoCredentials{
-			Username:      creds.Username,
-			Password:      creds.Password,
-			SSHPrivateKey: creds.SSHPrivateKey,
-		}
-	}
-	if workTree, err = git.LoadWorkTree(path, loadOpts); err != nil {
-		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
-			fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
+	additional, err := loadAdditionalBranches(stepCtx.WorkDir, cfg, workTree.URL(), loadOpts)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 	pushOpts := &git.PushOptions{
 		// Start with whatever was specified in the config, which may be empty.
@@ -124,7 +162,7 @@ func (g *gitPushPusher) runPromotionStep(
 	if cfg.GenerateTargetBranch {
 		// TargetBranch and GenerateTargetBranch are mutually exclusive, so we're
 		// never overwriting a user-specified target branch here.
-		pushOpts.TargetBranch = fmt.Sprintf("kargo/promotion/%s", stepCtx.Promotion)
+		pushOpts.TargetBranch = PromotionBranchPrefix + stepCtx.Promotion
 		pushOpts.Force = true
 	}
 	if pushOpts.TargetBranch == "" {
@@ -137,6 +175,72 @@ func (g *gitPushPusher) runPromotionStep(
 		}
 	}
 
+	// Updates to a branch that the Stage batches updates to are accumulated on
+	// a rolling branch instead of being pushed to the branch directly.
+	if batching := sourceUpdateBatching(
+		stepCtx, workTree.URL(), pushOpts.TargetBranch,
+	); batching != nil && !cfg.ForReview {
+		if len(additional) > 0 || cfg.DetectDrift {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+				err: fmt.Errorf(
+					"updates to branch %q are batched, which is mutually exclusive "+
+						"with additionalBranches and detectDrift",
+					pushOpts.TargetBranch,
+				),
+			}
+		}
+		res, err := g.pushSourceUpdateBatch(ctx, workTree, loadOpts.Credentials, batching)
+		if errors.Is(err, errBranchLeaseExpired) {
+			return PromotionStepResult{
+				Status: kargoapi.PromotionPhaseRunning,
+				Message: fmt.Sprintf(
+					"push to rolling branch %q took too long and was abandoned; step "+
+						"will be retried",
+					rollingBranch(batching),
+				),
+			}, nil
+		}
+		return res, err
+	}
+
+	if cfg.ForReview {
+		pushOpts.ChangeID = gerritChangeID(stepCtx, workTree.URL(), pushOpts.TargetBranch)
+	}
+
+	// If the Stage's rendered branch is being pushed to, record the commit it
+	// points to beforehand, so that it can be reset to that commit if the
+	// Promotion fails. Pushing for review does not update the branch.
+	var renderedPush *kargoapi.RenderedBranchPush
+	var externalMod *kargoapi.ExternalModification
+	if stepCtx.RenderedBranch != "" && pushOpts.TargetBranch == stepCtx.RenderedBranch &&
+		!cfg.ForReview {
+		renderedPush = &kargoapi.RenderedBranchPush{
+			RepoURL: workTree.URL(),
+			Branch:  pushOpts.TargetBranch,
+		}
+		if renderedPush.PreviousCommit, err = g.getRemoteBranchCommitFn(
+			workTree.URL(),
+			pushOpts.TargetBranch,
+			&git.ClientOptions{Credentials: loadOpts.Credentials},
+		); err != nil && !errors.Is(err, git.ErrRemoteBranchNotFound) {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+				"error getting commit of rendered branch %q: %w", pushOpts.TargetBranch, err,
+			)
+		}
+		if externalMod = externalModification(stepCtx, renderedPush); externalMod != nil &&
+			externalMod.Policy != kargoapi.RenderedBranchExternalModificationPolicyWarn {
+			return PromotionStepResult{
+				Status:               kargoapi.PromotionPhaseFailed,
+				ExternalModification: externalMod,
+			}, &terminalError{err: fmt.Errorf(
+				"ExternalModificationDetected: rendered branch %q points to commit %s "+
+					"rather than commit %s, which Kargo last pushed to it; refusing to "+
+					"overwrite changes made by something other than Kargo",
+				pushOpts.TargetBranch, externalMod.ActualCommit, externalMod.ExpectedCommit,
+			)}
+		}
+	}
+
 	backoff := wait.Backoff{
 		// Note, the docs for this field say:
 		//
@@ -158,17 +262,57 @@ func (g *gitPushPusher) runPromotionStep(
 	if cfg.MaxAttempts != nil {
 		backoff.Steps = int(*cfg.MaxAttempts)
 	}
-	if err = retry.OnError(
-		backoff,
-		git.IsNonFastForward,
-		func() error {
-			// This will obtain a lock on the repo + branch before performing a
-			// pull/rebase + push. This means retries should only ever be necessary
-			// when there are multiple sharded controllers concurrently executing
-			// Promotions that push to the same branch.
-			return g.push(workTree, pushOpts)
-		},
-	); err != nil {
+	var atomic bool
+	pushWithRetries := func() error {
+		return retry.OnError(
+			backoff,
+			git.IsNonFastForward,
+			func() error {
+				// This will obtain a lock on the repo + branch before performing a
+				// pull/rebase + push. This means retries should only ever be
+				// necessary when there are multiple sharded controllers concurrently
+				// executing Promotions that push to the same branch.
+				var pushErr error
+				atomic, pushErr = g.push(ctx, workTree, pushOpts, additional)
+				return pushErr
+			},
+		)
+	}
+	err = pushWithRetries()
+	if git.IsAuthError(err) {
+		// The credentials may have been rotated since they were cached. If
+		// looking them up again yields different ones, try those once.
+		creds, changed, refreshErr := refreshGitCredentials(ctx, stepCtx, workTree.URL(), loadOpts.Credentials)
+		if refreshErr != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, refreshErr
+		}
+		if changed {
+			loadOpts.Credentials = creds
+			if workTree, err = git.LoadWorkTree(path, loadOpts); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
+			}
+			if additional, err = loadAdditionalBranches(
+				stepCtx.WorkDir, cfg, workTree.URL(), loadOpts,
+			); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+			}
+			err = pushWithRetries()
+		}
+	}
+	if errors.Is(err, errBranchLeaseExpired) {
+		// The push got stuck while holding the locks on the branches, which were
+		// forcibly released so that other Promotions could proceed. Whether the
+		// push took effect is unknown, so the step is executed again.
+		return PromotionStepResult{
+			Status: kargoapi.PromotionPhaseRunning,
+			Message: fmt.Sprintf(
+				"push to branch %q took too long and was abandoned; step will be retried",
+				pushOpts.TargetBranch,
+			),
+		}, nil
+	}
+	if err != nil {
 		if git.IsMergeConflict(err) {
 			// Special case: A merge conflict requires manual resolution and no amount
 			// of retries will fix that.
@@ -184,32 +328,312 @@ func (g *gitPushPusher) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error getting last commit ID: %w", err)
 	}
-	return PromotionStepResult{
+	res := PromotionStepResult{
 		Status: kargoapi.PromotionPhaseSucceeded,
 		Output: map[string]any{
 			stateKeyBranch: pushOpts.TargetBranch,
 			stateKeyCommit: commitID,
 		},
-	}, nil
+	}
+	if err = addCommitTrailersOutput(workTree, commitID, res.Output); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if cfg.ForReview {
+		changeNumber, err := g.getChangeNumber(ctx, workTree.URL(), commitID, loadOpts.Credentials)
+		if err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		res.Output[stateKeyPRNumber] = changeNumber
+		res.Output[stateKeyChangeID] = pushOpts.ChangeID
+	}
+	if renderedPush != nil {
+		renderedPush.Commit = commitID
+		res.RenderedBranchPush = renderedPush
+		res.ExternalModification = externalMod
+	}
+	if len(additional) > 0 {
+		// Commits may have been rebased while being pushed, so their IDs are
+		// only determined now.
+		branches := make([]any, len(additional))
+		for i, b := range additional {
+			if commitID, err = b.workTree.LastCommitID(); err != nil {
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					fmt.Errorf("error getting last commit ID of %s: %w", b.path, err)
+			}
+			branches[i] = map[string]any{
+				stateKeyBranch: b.targetBranch,
+				stateKeyCommit: commitID,
+			}
+		}
+		res.Output[stateKeyAdditionalBranches] = branches
+		res.Output[stateKeyAtomic] = atomic
+	}
+	if cfg.DetectDrift {
+		res.HealthCheckStep = &HealthCheckStep{
+			Kind: g.Name(),
+			Config: Config{
+				"repoURL": workTree.URL(),
+				"branch":  pushOpts.TargetBranch,
+				"commit":  commitID,
+			},
+		}
+		if pr := cfg.DriftPullRequest; pr != nil {
+			prCfg := map[string]any{
+				"repoURL":      pr.RepoURL,
+				"targetBranch": pr.TargetBranch,
+			}
+			if pr.RepoURL == "" {
+				prCfg["repoURL"] = workTree.URL()
+			}
+			if pr.Provider != nil {
+				prCfg["provider"] = string(*pr.Provider)
+			}
+			res.HealthCheckStep.Config["pullRequest"] = prCfg
+		}
+	}
+	return res, nil
 }
 
-// push obtains a repo + branch lock before pushing to the remote. This helps
-// reduce the likelihood of conflicts when multiple Promotions that push to
-// the same branch are running concurrently.
-func (g *gitPushPusher) push(workTree git.WorkTree, pushOpts *git.PushOptions) error {
-	branchKey := g.getBranchKey(workTree.URL(), pushOpts.TargetBranch)
-	if _, exists := g.branchMus[branchKey]; !exists {
-		g.masterMu.Lock()
-		// Double-check to make sure it wasn't created while we were waiting for the
-		// lock.
-		if _, exists = g.branchMus[branchKey]; !exists {
-			g.branchMus[branchKey] = &sync.Mutex{}
-		}
-		g.masterMu.Unlock()
-	}
-	g.branchMus[branchKey].Lock()
-	defer g.branchMus[branchKey].Unlock()
-	return workTree.Push(pushOpts)
+// externalModification returns a record of the modification of the Stage's
+// rendered branch by something other than Kargo if the branch, which is about
+// to be pushed to, does not point to the commit that Kargo last pushed to it.
+// It returns nil if it does, if the branch does not exist, or if the commit
+// that Kargo last pushed to it is not known.
+func externalModification(
+	stepCtx *PromotionStepContext,
+	push *kargoapi.RenderedBranchPush,
+) *kargoapi.ExternalModification {
+	expected := stepCtx.RenderedBranchCommit
+	if expected == nil || expected.Commit == "" || push.PreviousCommit == "" ||
+		expected.RepoURL != push.RepoURL || expected.Branch != push.Branch ||
+		expected.Commit == push.PreviousCommit {
+		return nil
+	}
+	policy := stepCtx.OnExternalModification
+	if policy == "" {
+		policy = kargoapi.RenderedBranchExternalModificationPolicyFail
+	}
+	return &kargoapi.ExternalModification{
+		RepoURL:        push.RepoURL,
+		Branch:         push.Branch,
+		ExpectedCommit: expected.Commit,
+		ActualCommit:   push.PreviousCommit,
+		Policy:         policy,
+	}
+}
+
+// gerritChangeID returns the Change-Id of the Gerrit change that commits are
+// pushed for review to by the current Promotion. It is derived from the
+// Promotion, the repository, and the target branch, so that pushing again,
+// e.g. when the step is retried, uploads a new patch set of the same change
+// instead of creating another one.
+func gerritChangeID(stepCtx *PromotionStepContext, repoURL, targetBranch string) string {
+	return fmt.Sprintf("I%x", sha1.Sum([]byte(strings.Join(
+		[]string{stepCtx.Project, stepCtx.Promotion, libgit.NormalizeURL(repoURL), targetBranch},
+		"/",
+	))))
+}
+
+// getChangeNumber looks up the number of the Gerrit change whose current patch
+// set is the commit with the provided ID using the Gerrit REST API, whose URL
+// is derived from the URL of the repository.
+func (g *gitPushPusher) getChangeNumber(
+	ctx context.Context,
+	repoURL string,
+	commitID string,
+	creds *git.RepoCredentials,
+) (int64, error) {
+	gpOpts := &gitprovider.Options{Name: gerrit.ProviderName}
+	if creds != nil {
+		gpOpts.Username = creds.Username
+		gpOpts.Token = creds.Password
+	}
+	gitProv, err := g.newGitProviderFn(repoURL, gpOpts)
+	if err != nil {
+		return 0, fmt.Errorf("error creating git provider service: %w", err)
+	}
+	changes, err := gitProv.ListPullRequests(
+		ctx,
+		&gitprovider.ListPullRequestOptions{
+			State:      gitprovider.PullRequestStateAny,
+			HeadCommit: commitID,
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error looking up change for commit %s: %w", commitID, err)
+	}
+	if len(changes) == 0 {
+		return 0, fmt.Errorf("no change found for commit %s", commitID)
+	}
+	return changes[0].Number, nil
+}
+
+// loadWorkTreeWithCredentials loads the working tree at the provided path
+// with the credentials, if any, that apply to the repository it belongs to.
+// The options it was loaded with are returned as well, so that other working
+// trees of the same repository can be loaded with the same credentials.
+func loadWorkTreeWithCredentials(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	path string,
+) (git.WorkTree, *git.LoadWorkTreeOptions, error) {
+	// This is kind of hacky, but we needed to load the working tree to get the
+	// URL of the repository. With that in hand, we can look for applicable
+	// credentials and, if found, reload the work tree with the credentials.
+	loadOpts := &git.LoadWorkTreeOptions{}
+	workTree, err := git.LoadWorkTree(path, loadOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if loadOpts.Credentials, err = getGitCredentials(ctx, stepCtx, workTree.URL()); err != nil {
+		return nil, nil, err
+	}
+	if loadOpts.Credentials == nil {
+		return workTree, loadOpts, nil
+	}
+	if workTree, err = git.LoadWorkTree(path, loadOpts); err != nil {
+		return nil, nil, err
+	}
+	return workTree, loadOpts, nil
+}
+
+// additionalBranch is a working tree of another branch of the repository
+// pushed to by the git-push step, whose current branch is pushed along with
+// the one of the step's main working tree.
+type additionalBranch struct {
+	path         string
+	workTree     git.WorkTree
+	targetBranch string
+}
+
+// loadAdditionalBranches loads the working trees of the additional branches
+// specified by the provided GitPushConfig. An error is returned if any of them
+// is not a working tree of the repository with the provided URL.
+func loadAdditionalBranches(
+	workDir string,
+	cfg GitPushConfig,
+	repoURL string,
+	loadOpts *git.LoadWorkTreeOptions,
+) ([]additionalBranch, error) {
+	branches := make([]additionalBranch, len(cfg.AdditionalBranches))
+	for i, b := range cfg.AdditionalBranches {
+		path, err := securejoin.SecureJoin(workDir, b.Path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error joining path %s with work dir %s: %w",
+				b.Path, workDir, err,
+			)
+		}
+		workTree, err := git.LoadWorkTree(path, loadOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error loading working tree from %s: %w", b.Path, err)
+		}
+		if workTree.URL() != repoURL {
+			return nil, &terminalError{err: fmt.Errorf(
+				"working tree at %s belongs to repository %s instead of %s",
+				b.Path, workTree.URL(), repoURL,
+			)}
+		}
+		branches[i] = additionalBranch{
+			path:         b.Path,
+			workTree:     workTree,
+			targetBranch: b.TargetBranch,
+		}
+	}
+	return branches, nil
+}
+
+// push obtains repo + branch locks for all branches being pushed to before
+// pushing to the remote. This helps reduce the likelihood of conflicts when
+// multiple Promotions that push to the same branch are running concurrently.
+// The git commands run while the locks are held are bound to the context of
+// the lease, so that they are killed if the locks are forcibly released
+// because the push takes longer than the maximum hold time. An error wrapping
+// errBranchLeaseExpired is returned in that case.
+//
+// Any additional branches are pushed along with the current branch of the
+// provided working tree in a single atomic push. If the remote does not
+// support atomic pushes, the branches are pushed one after another instead.
+// The returned bool indicates whether the branches were pushed atomically.
+func (g *gitPushPusher) push(
+	ctx context.Context,
+	workTree git.WorkTree,
+	pushOpts *git.PushOptions,
+	additional []additionalBranch,
+) (atomic bool, err error) {
+	branchKeys := []string{g.getBranchKey(workTree.URL(), pushOpts.TargetBranch)}
+	for _, b := range additional {
+		branchKeys = append(branchKeys, g.getBranchKey(workTree.URL(), b.targetBranch))
+	}
+	lease, err := g.branchLocks.acquire(ctx, branchKeys)
+	if err != nil {
+		return false, fmt.Errorf("error waiting for lock on branches: %w", err)
+	}
+	defer func() {
+		if releaseErr := lease.release(); releaseErr != nil {
+			err = releaseErr
+		}
+	}()
+
+	if len(additional) == 0 {
+		if err = lease.heartbeat("push"); err != nil {
+			return false, err
+		}
+		return false, workTree.PushContext(lease.ctx, pushOpts)
+	}
+
+	atomicOpts := *pushOpts
+	atomicOpts.AdditionalRefs = make([]git.PushRef, len(additional))
+	for i, b := range additional {
+		// Push only pulls and rebases the current branch of workTree, so the
+		// additional branches must be rebased up front.
+		if pushOpts.PullRebase {
+			if err = lease.heartbeat("pull-rebase " + b.targetBranch); err != nil {
+				return false, err
+			}
+			if err = b.workTree.PullRebaseContext(lease.ctx, b.targetBranch); err != nil {
+				return false, err
+			}
+		}
+		commitID, err := b.workTree.LastCommitID()
+		if err != nil {
+			return false, fmt.Errorf("error getting last commit ID of %s: %w", b.path, err)
+		}
+		atomicOpts.AdditionalRefs[i] = git.PushRef{
+			Commit:       commitID,
+			TargetBranch: b.targetBranch,
+		}
+	}
+	if err = lease.heartbeat("atomic push"); err != nil {
+		return false, err
+	}
+	err = workTree.PushContext(lease.ctx, &atomicOpts)
+	if !git.IsAtomicPushNotSupported(err) {
+		return true, err
+	}
+
+	// The remote rejected the atomic push without updating any branch, so fall
+	// back to pushing the branches one after another. If one of these pushes
+	// fails, the branches pushed before it remain updated. Pushing them again
+	// when the step is retried is a no-op.
+	if err = lease.heartbeat("push"); err != nil {
+		return false, err
+	}
+	if err = workTree.PushContext(lease.ctx, pushOpts); err != nil {
+		return false, err
+	}
+	for _, b := range additional {
+		if err = lease.heartbeat("push " + b.targetBranch); err != nil {
+			return false, err
+		}
+		if err = b.workTree.PushContext(lease.ctx, &git.PushOptions{
+			TargetBranch: b.targetBranch,
+			PullRebase:   pushOpts.PullRebase,
+		}); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
 }
 
 func (g *gitPushPusher) getBranchKey(repoURL, branch string) string {
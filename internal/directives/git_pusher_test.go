@@ -6,14 +6,18 @@ import (
 	"math"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/sosedoff/gitkit"
 	"github.com/stretchr/testify/require"
 
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/git"
 	"github.com/akuity/kargo/internal/credentials"
+	"github.com/akuity/kargo/internal/gitprovider"
 )
 
 func Test_gitPusher_validate(t *testing.T) {
@@ -203,7 +207,7 @@ func Test_gitPusher_runPromotionStep(t *testing.T) {
 	r := newGitPusher()
 	runner, ok := r.(*gitPushPusher)
 	require.True(t, ok)
-	require.NotNil(t, runner.branchMus)
+	require.NotNil(t, runner.branchLocks)
 
 	res, err := runner.runPromotionStep(
 		context.Background(),
@@ -217,6 +221,8 @@ func Test_gitPusher_runPromotionStep(t *testing.T) {
 		GitPushConfig{
 			Path:                 "master",
 			GenerateTargetBranch: true,
+			DetectDrift:          true,
+			DriftPullRequest:     &DriftPullRequest{TargetBranch: "master"},
 		},
 	)
 	require.NoError(t, err)
@@ -228,4 +234,472 @@ func Test_gitPusher_runPromotionStep(t *testing.T) {
 	actualCommit, ok := res.Output[stateKeyCommit]
 	require.True(t, ok)
 	require.Equal(t, expectedCommit, actualCommit)
+	require.NotNil(t, res.HealthCheckStep)
+	require.Equal(t, "git-push", res.HealthCheckStep.Kind)
+	require.Equal(
+		t,
+		Config{
+			"repoURL": testRepoURL,
+			"branch":  "kargo/promotion/fake-promotion",
+			"commit":  expectedCommit,
+			"pullRequest": map[string]any{
+				"repoURL":      testRepoURL,
+				"targetBranch": "master",
+			},
+		},
+		res.HealthCheckStep.Config,
+	)
+}
+
+func Test_gitPusher_runPromotionStep_additionalBranches(t *testing.T) {
+	testCases := []struct {
+		name           string
+		supportsAtomic bool
+	}{
+		{
+			name:           "remote supports atomic pushes",
+			supportsAtomic: true,
+		},
+		{
+			name: "remote does not support atomic pushes",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Set up a test Git server in-process
+			serverDir := t.TempDir()
+			service := gitkit.New(
+				gitkit.Config{
+					Dir:        serverDir,
+					AutoCreate: true,
+				},
+			)
+			require.NoError(t, service.Setup())
+			server := httptest.NewServer(service)
+			defer server.Close()
+
+			// This is the URL of the "remote" repository
+			testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+			// Seed the remote repository with an initial commit
+			setupRepo, err := git.Clone(testRepoURL, nil, nil)
+			require.NoError(t, err)
+			defer setupRepo.Close()
+			err = os.WriteFile(filepath.Join(setupRepo.Dir(), "test.txt"), []byte("foo"), 0600)
+			require.NoError(t, err)
+			require.NoError(t, setupRepo.AddAllAndCommit("Initial commit"))
+			require.NoError(t, setupRepo.Push(nil))
+
+			if !testCase.supportsAtomic {
+				out, err := exec.Command(
+					"git", "-C", filepath.Join(serverDir, "test.git"),
+					"config", "receive.advertiseAtomic", "false",
+				).CombinedOutput()
+				require.NoError(t, err, string(out))
+			}
+
+			workDir := t.TempDir()
+
+			// Finagle a local bare repo and a working tree for each of the source
+			// and rendered branches into place the way gitCloner might have.
+			repo, err := git.CloneBare(
+				testRepoURL,
+				nil,
+				&git.BareCloneOptions{
+					BaseDir: workDir,
+				},
+			)
+			require.NoError(t, err)
+			defer repo.Close()
+			srcTree, err := repo.AddWorkTree(
+				filepath.Join(workDir, "src"),
+				&git.AddWorkTreeOptions{Ref: "master"},
+			)
+			require.NoError(t, err)
+			outTree, err := repo.AddWorkTree(
+				filepath.Join(workDir, "out"),
+				&git.AddWorkTreeOptions{Ref: "master", Branch: "rendered"},
+			)
+			require.NoError(t, err)
+			for _, w := range []git.WorkTree{srcTree, outTree} {
+				err = os.WriteFile(filepath.Join(w.Dir(), "test.txt"), []byte(w.Dir()), 0600)
+				require.NoError(t, err)
+				require.NoError(t, w.AddAllAndCommit("Update"))
+			}
+
+			r := newGitPusher()
+			runner, ok := r.(*gitPushPusher)
+			require.True(t, ok)
+
+			res, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{
+					Project:       "fake-project",
+					Stage:         "fake-stage",
+					Promotion:     "fake-promotion",
+					WorkDir:       workDir,
+					CredentialsDB: &credentials.FakeDB{},
+				},
+				GitPushConfig{
+					Path:         "src",
+					TargetBranch: "master",
+					AdditionalBranches: []AdditionalBranch{{
+						Path:         "out",
+						TargetBranch: "rendered",
+					}},
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, testCase.supportsAtomic, res.Output[stateKeyAtomic])
+
+			srcCommit, err := srcTree.LastCommitID()
+			require.NoError(t, err)
+			require.Equal(t, srcCommit, res.Output[stateKeyCommit])
+			outCommit, err := outTree.LastCommitID()
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				[]any{
+					map[string]any{
+						stateKeyBranch: "rendered",
+						stateKeyCommit: outCommit,
+					},
+				},
+				res.Output[stateKeyAdditionalBranches],
+			)
+
+			// Both branches were updated either way
+			remoteSrcCommit, err := git.RemoteBranchCommit(testRepoURL, "master", nil)
+			require.NoError(t, err)
+			require.Equal(t, srcCommit, remoteSrcCommit)
+			remoteOutCommit, err := git.RemoteBranchCommit(testRepoURL, "rendered", nil)
+			require.NoError(t, err)
+			require.Equal(t, outCommit, remoteOutCommit)
+		})
+	}
+}
+
+func Test_gitPusher_runPromotionStep_renderedBranch(t *testing.T) {
+	// Set up a test Git server in-process
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	// This is the URL of the "remote" repository
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	// Seed the remote repository with an initial commit on both the source and
+	// the rendered branch
+	setupRepo, err := git.Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRepo.Close()
+	err = os.WriteFile(filepath.Join(setupRepo.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, setupRepo.AddAllAndCommit("Initial commit"))
+	require.NoError(t, setupRepo.Push(nil))
+	require.NoError(t, setupRepo.Push(&git.PushOptions{TargetBranch: "rendered/dev"}))
+	initialCommit, err := setupRepo.LastCommitID()
+	require.NoError(t, err)
+
+	workDir := t.TempDir()
+
+	// Finagle a local bare repo and a working tree for each of the source and
+	// rendered branches into place the way gitCloner might have.
+	repo, err := git.CloneBare(
+		testRepoURL,
+		nil,
+		&git.BareCloneOptions{
+			BaseDir: workDir,
+		},
+	)
+	require.NoError(t, err)
+	defer repo.Close()
+	srcTree, err := repo.AddWorkTree(
+		filepath.Join(workDir, "src"),
+		&git.AddWorkTreeOptions{Ref: "master"},
+	)
+	require.NoError(t, err)
+	outTree, err := repo.AddWorkTree(
+		filepath.Join(workDir, "out"),
+		&git.AddWorkTreeOptions{Ref: "master", Branch: "rendered/dev"},
+	)
+	require.NoError(t, err)
+	for _, w := range []git.WorkTree{srcTree, outTree} {
+		err = os.WriteFile(filepath.Join(w.Dir(), "test.txt"), []byte(w.Dir()), 0600)
+		require.NoError(t, err)
+		require.NoError(t, w.AddAllAndCommit("Update"))
+	}
+
+	r := newGitPusher()
+	runner, ok := r.(*gitPushPusher)
+	require.True(t, ok)
+	stepCtx := &PromotionStepContext{
+		Project:        "fake-project",
+		Stage:          "fake-stage",
+		Promotion:      "fake-promotion",
+		WorkDir:        workDir,
+		CredentialsDB:  &credentials.FakeDB{},
+		RenderedBranch: "rendered/dev",
+	}
+
+	// Pushes to other branches are not recorded
+	res, err := runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitPushConfig{Path: "src", TargetBranch: "master"},
+	)
+	require.NoError(t, err)
+	require.Nil(t, res.RenderedBranchPush)
+
+	res, err = runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitPushConfig{Path: "out", TargetBranch: "rendered/dev"},
+	)
+	require.NoError(t, err)
+	outCommit, err := outTree.LastCommitID()
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		&kargoapi.RenderedBranchPush{
+			RepoURL:        testRepoURL,
+			Branch:         "rendered/dev",
+			PreviousCommit: initialCommit,
+			Commit:         outCommit,
+		},
+		res.RenderedBranchPush,
+	)
+	require.Nil(t, res.ExternalModification)
+
+	// Someone else pushes to the rendered branch
+	externalRepo, err := git.Clone(testRepoURL, nil, &git.CloneOptions{Branch: "rendered/dev"})
+	require.NoError(t, err)
+	defer externalRepo.Close()
+	err = os.WriteFile(filepath.Join(externalRepo.Dir(), "external.txt"), []byte("bar"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, externalRepo.AddAllAndCommit("External change"))
+	require.NoError(t, externalRepo.Push(nil))
+	externalCommit, err := externalRepo.LastCommitID()
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(outTree.Dir(), "test.txt"), []byte("baz"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, outTree.AddAllAndCommit("Update again"))
+	stepCtx.RenderedBranchCommit = &kargoapi.RenderedBranchCommit{
+		RepoURL: testRepoURL,
+		Branch:  "rendered/dev",
+		Commit:  outCommit,
+	}
+	expectedMod := &kargoapi.ExternalModification{
+		RepoURL:        testRepoURL,
+		Branch:         "rendered/dev",
+		ExpectedCommit: outCommit,
+		ActualCommit:   externalCommit,
+		Policy:         kargoapi.RenderedBranchExternalModificationPolicyFail,
+	}
+
+	// By default, the Promotion fails rather than overwriting the change
+	res, err = runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitPushConfig{Path: "out", TargetBranch: "rendered/dev"},
+	)
+	require.ErrorContains(t, err, "ExternalModificationDetected")
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+	require.Equal(t, expectedMod, res.ExternalModification)
+	require.Nil(t, res.RenderedBranchPush)
+
+	// If the policy is to warn, the push proceeds
+	stepCtx.OnExternalModification = kargoapi.RenderedBranchExternalModificationPolicyWarn
+	expectedMod.Policy = kargoapi.RenderedBranchExternalModificationPolicyWarn
+	res, err = runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitPushConfig{Path: "out", TargetBranch: "rendered/dev"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+	require.Equal(t, expectedMod, res.ExternalModification)
+	require.NotNil(t, res.RenderedBranchPush)
+	require.Equal(t, externalCommit, res.RenderedBranchPush.PreviousCommit)
+}
+
+func Test_gitPusher_runPromotionStep_forReview(t *testing.T) {
+	// Set up a test Git server in-process. It stores refs/for/<branch> as an
+	// ordinary ref, which suffices to verify what would be pushed for review to
+	// a Gerrit server.
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	// This is the URL of the "remote" repository
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	setupRepo, err := git.Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRepo.Close()
+	err = os.WriteFile(filepath.Join(setupRepo.Dir(), "test.txt"), []byte("foo"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, setupRepo.AddAllAndCommit("Initial commit"))
+	require.NoError(t, setupRepo.Push(nil))
+	initialCommit, err := setupRepo.LastCommitID()
+	require.NoError(t, err)
+
+	workDir := t.TempDir()
+	repo, err := git.CloneBare(
+		testRepoURL,
+		nil,
+		&git.BareCloneOptions{
+			BaseDir: workDir,
+		},
+	)
+	require.NoError(t, err)
+	defer repo.Close()
+	workTree, err := repo.AddWorkTree(
+		filepath.Join(workDir, "master"),
+		&git.AddWorkTreeOptions{Ref: "master"},
+	)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(workTree.Dir(), "test.txt"), []byte("bar"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, workTree.AddAllAndCommit("Update"))
+
+	r := newGitPusher()
+	runner, ok := r.(*gitPushPusher)
+	require.True(t, ok)
+	var listOpts *gitprovider.ListPullRequestOptions
+	runner.newGitProviderFn = func(
+		repoURL string,
+		opts *gitprovider.Options,
+	) (gitprovider.Interface, error) {
+		require.Equal(t, testRepoURL, repoURL)
+		require.Equal(t, "gerrit", opts.Name)
+		return &gitprovider.Fake{
+			ListPullRequestsFn: func(
+				_ context.Context,
+				opts *gitprovider.ListPullRequestOptions,
+			) ([]gitprovider.PullRequest, error) {
+				listOpts = opts
+				return []gitprovider.PullRequest{{Number: 42, Open: true}}, nil
+			},
+		}, nil
+	}
+	stepCtx := &PromotionStepContext{
+		Project:        "fake-project",
+		Stage:          "fake-stage",
+		Promotion:      "fake-promotion",
+		WorkDir:        workDir,
+		CredentialsDB:  &credentials.FakeDB{},
+		RenderedBranch: "master",
+	}
+
+	res, err := runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitPushConfig{Path: "master", ForReview: true},
+	)
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+	// Pushing for review does not update the rendered branch
+	require.Nil(t, res.RenderedBranchPush)
+
+	commitID, err := workTree.LastCommitID()
+	require.NoError(t, err)
+	changeID := gerritChangeID(stepCtx, testRepoURL, "master")
+	require.Regexp(t, "^I[0-9a-f]{40}$", changeID)
+	require.Equal(t, "master", res.Output[stateKeyBranch])
+	require.Equal(t, commitID, res.Output[stateKeyCommit])
+	require.Equal(t, int64(42), res.Output[stateKeyPRNumber])
+	require.Equal(t, changeID, res.Output[stateKeyChangeID])
+	require.Equal(t, commitID, listOpts.HeadCommit)
+
+	trailers, err := workTree.CommitTrailers(commitID)
+	require.NoError(t, err)
+	require.Contains(t, trailers, "Change-Id: "+changeID)
+
+	out, err := exec.Command("git", "ls-remote", testRepoURL, "refs/for/master").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), commitID)
+	remoteCommit, err := git.RemoteBranchCommit(testRepoURL, "master", nil)
+	require.NoError(t, err)
+	require.Equal(t, initialCommit, remoteCommit)
+
+	// The Change-Id only depends on the Promotion and where it pushes to
+	require.Equal(t, changeID, gerritChangeID(stepCtx, testRepoURL, "master"))
+	require.NotEqual(t, changeID, gerritChangeID(stepCtx, testRepoURL, "main"))
+
+	res, err = runner.runPromotionStep(
+		context.Background(),
+		stepCtx,
+		GitPushConfig{Path: "master", ForReview: true, DetectDrift: true},
+	)
+	require.ErrorContains(t, err, "forReview is mutually exclusive")
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+}
+
+func Test_gitPusher_push_leaseExpiry(t *testing.T) {
+	repo := newBlockingPushRepo(t)
+
+	pusher := &gitPushPusher{branchLocks: newBranchLocks(500 * time.Millisecond)}
+	start := time.Now()
+	_, err := pusher.push(
+		context.Background(),
+		repo,
+		&git.PushOptions{TargetBranch: "main"},
+		nil,
+	)
+	require.ErrorIs(t, err, errBranchLeaseExpired)
+	// The push was killed along with the lease instead of running to
+	// completion after the locks had been released.
+	require.Less(t, time.Since(start), 30*time.Second)
+}
+
+// newBlockingPushRepo returns a clone, with a new commit, of a "remote"
+// repository whose pre-receive hook blocks, so that any push to it gets stuck
+// until the git process is killed.
+func newBlockingPushRepo(t *testing.T) git.Repo {
+	srcDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--initial-branch", "main", srcDir},
+		{"-C", srcDir, "-c", "user.name=test", "-c", "user.email=test@example.com",
+			"commit", "--allow-empty", "-m", "Initial commit"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	out, err := exec.Command("git", "clone", "--bare", srcDir, remoteDir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(remoteDir, "hooks", "pre-receive"),
+		[]byte("#!/bin/sh\nexec sleep 60\n"),
+		0o700, // nolint: gosec
+	))
+
+	repo, err := git.Clone(
+		"file://"+remoteDir,
+		&git.ClientOptions{
+			User: &git.User{Name: "test", Email: "test@example.com"},
+		},
+		&git.CloneOptions{BaseDir: t.TempDir()},
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Dir(), "test.txt"), []byte("foo"), 0o600))
+	require.NoError(t, repo.AddAllAndCommit("Update"))
+	return repo
 }
@@ -0,0 +1,360 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+// defaultRollingBranch is the branch that updates to a batched branch are
+// accumulated on if the Stage's SourceUpdateBatching does not specify one.
+const defaultRollingBranch = "kargo/image-updates"
+
+// commitTrailerBatchStarted is the key of the Git commit trailer that records
+// when the first update of a batch was pushed to the rolling branch. It marks
+// the squashed commit that holds the updates of a batch accumulated so far.
+const commitTrailerBatchStarted = "Kargo-Batch-Started"
+
+// stateKeyMergedCommit is the key used to store the commit that a batch of
+// updates was merged into its target branch with in the shared State.
+const stateKeyMergedCommit = "mergedCommit"
+
+// stateKeyUpdates is the key used to store the number of updates in a batch of
+// updates in the shared State.
+const stateKeyUpdates = "updates"
+
+// sourceUpdateBatching returns the Stage's SourceUpdateBatching if updates to
+// the provided branch of the repository with the provided URL are to be
+// accumulated on a rolling branch. Otherwise, it returns nil. Updates are
+// never accumulated on the branch they are accumulated for, which the Stage
+// CRD rejects, but Stages admitted before it did may still specify.
+func sourceUpdateBatching(
+	stepCtx *PromotionStepContext,
+	repoURL string,
+	branch string,
+) *kargoapi.SourceUpdateBatching {
+	batching := stepCtx.SourceUpdateBatching
+	if batching == nil || branch == "" || branch != batching.Branch ||
+		libgit.NormalizeURL(repoURL) != libgit.NormalizeURL(batching.RepoURL) ||
+		rollingBranch(batching) == batching.Branch {
+		return nil
+	}
+	return batching
+}
+
+// rollingBranch returns the branch that updates are accumulated on according
+// to the provided SourceUpdateBatching.
+func rollingBranch(batching *kargoapi.SourceUpdateBatching) string {
+	if batching.RollingBranch != "" {
+		return batching.RollingBranch
+	}
+	return defaultRollingBranch
+}
+
+// sourceUpdateBatchMessage is the message of the squashed commit that holds all
+// updates of a batch accumulated so far.
+type sourceUpdateBatchMessage struct {
+	// Updates lists the subjects of the commits of the updates in the batch,
+	// oldest first.
+	Updates []string
+	// Started is when the first update of the batch was pushed.
+	Started time.Time
+	// Trailers are the Kargo trailers of the most recent update.
+	Trailers kargoapi.CommitTrailers
+}
+
+// newSourceUpdateBatchMessage combines the messages of the commits made on top
+// of the commit that a batch is based on, oldest first, into the message of
+// the commit they are squashed into. The message of a commit that holds a batch
+// accumulated previously contributes all of the batch's updates and when it was
+// started. Any other commit contributes a single update. If none of the
+// commits holds a previous batch, the batch is started at the provided time.
+func newSourceUpdateBatchMessage(messages []string, now time.Time) sourceUpdateBatchMessage {
+	batch := sourceUpdateBatchMessage{Started: now}
+	for _, msg := range messages {
+		if prev, ok := parseSourceUpdateBatchMessage(msg); ok {
+			batch.Updates = append(batch.Updates, prev.Updates...)
+			if prev.Started.Before(batch.Started) {
+				batch.Started = prev.Started
+			}
+		} else {
+			subject, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+			batch.Updates = append(batch.Updates, strings.TrimSpace(subject))
+		}
+		batch.Trailers = kargoapi.ParseCommitTrailers(msg)
+	}
+	return batch
+}
+
+// parseSourceUpdateBatchMessage parses the message of a commit that holds a
+// batch of updates. It returns false if the message is not the message of
+// such a commit.
+func parseSourceUpdateBatchMessage(message string) (sourceUpdateBatchMessage, bool) {
+	var batch sourceUpdateBatchMessage
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	i := strings.LastIndex(message, "\n\n")
+	if i < 0 {
+		return batch, false
+	}
+	var started bool
+	for _, line := range strings.Split(message[i+2:], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != commitTrailerBatchStarted {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+		if err != nil {
+			return batch, false
+		}
+		batch.Started, started = t, true
+	}
+	if !started {
+		return batch, false
+	}
+	// The subject is followed by the list of updates.
+	for _, line := range strings.Split(message[:i], "\n")[1:] {
+		if update, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok {
+			batch.Updates = append(batch.Updates, update)
+		}
+	}
+	batch.Trailers = kargoapi.ParseCommitTrailers(message)
+	return batch, true
+}
+
+// String returns the sourceUpdateBatchMessage formatted as the message of the
+// commit that holds the batch of updates to the provided branch.
+func (b sourceUpdateBatchMessage) String(branch string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Batch %d update(s) to %s\n\n", len(b.Updates), branch)
+	for _, update := range b.Updates {
+		fmt.Fprintf(&sb, "- %s\n", update)
+	}
+	fmt.Fprintf(&sb, "\n%s: %s", commitTrailerBatchStarted, b.Started.UTC().Format(time.RFC3339))
+	if trailers := b.Trailers.String(); trailers != "" {
+		sb.WriteString("\n" + trailers)
+	}
+	return sb.String()
+}
+
+// shouldMerge returns true if the batch of updates is due to be merged into
+// its target branch according to the provided SourceUpdateBatching.
+func (b sourceUpdateBatchMessage) shouldMerge(
+	batching *kargoapi.SourceUpdateBatching,
+	now time.Time,
+) bool {
+	if batching.MergeAfter > 0 && len(b.Updates) >= int(batching.MergeAfter) {
+		return true
+	}
+	return batching.MergeInterval != nil && now.Sub(b.Started) >= batching.MergeInterval.Duration
+}
+
+// pushSourceUpdateBatch adds the commits made on top of the batched branch, or
+// on top of the rolling branch it was checked out from, to the batch of
+// updates accumulated on the rolling branch. They are squashed together with
+// the updates accumulated so far into a single commit that is force pushed to
+// the rolling branch. If the batch is then due to be merged, it is either
+// pushed to the batched branch, after which the rolling branch is deleted, or
+// a pull request for merging the rolling branch into the batched branch is
+// opened, depending on the SourceUpdateBatching's MergeMethod.
+func (g *gitPushPusher) pushSourceUpdateBatch(
+	ctx context.Context,
+	workTree git.WorkTree,
+	creds *git.RepoCredentials,
+	batching *kargoapi.SourceUpdateBatching,
+) (res PromotionStepResult, err error) {
+	rolling := rollingBranch(batching)
+	lease, err := g.branchLocks.acquire(ctx, []string{
+		g.getBranchKey(workTree.URL(), batching.Branch),
+		g.getBranchKey(workTree.URL(), rolling),
+	})
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error waiting for lock on branches: %w", err)
+	}
+	defer func() {
+		if releaseErr := lease.release(); releaseErr != nil {
+			res, err = PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, releaseErr
+		}
+	}()
+
+	if err = lease.heartbeat("fetch " + batching.Branch); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	branchRef, err := workTree.BareRepo().FetchRemoteBranchContext(lease.ctx, batching.Branch)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error fetching branch %q: %w", batching.Branch, err)
+	}
+	base, err := workTree.MergeBase("HEAD", branchRef)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	// Refuse to discard updates that were added to the batch since the rolling
+	// branch was checked out. This is a conflict with a concurrent update
+	// rather than a problem with the Promotion, so it is not terminal.
+	if err = lease.heartbeat("fetch " + rolling); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	rollingRef, err := workTree.BareRepo().FetchRemoteBranchContext(lease.ctx, rolling)
+	if err != nil && !errors.Is(err, git.ErrRemoteBranchNotFound) {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error fetching rolling branch %q: %w", rolling, err)
+	}
+	if err == nil {
+		var upToDate bool
+		if upToDate, err = workTree.IsAncestor(rollingRef, "HEAD"); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		if !upToDate {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+				"rolling branch %q was updated after it was checked out; refusing "+
+					"to discard the updates that were added to it since",
+				rolling,
+			)
+		}
+	}
+
+	messages, err := workTree.CommitMessagesSince(base)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if len(messages) == 0 {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+			err: fmt.Errorf("no updates to branch %q to add to a batch", batching.Branch),
+		}
+	}
+	now := time.Now()
+	msg := newSourceUpdateBatchMessage(messages, now)
+	if err = workTree.SquashSince(base, msg.String(batching.Branch)); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if err = lease.heartbeat("push " + rolling); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if err = workTree.PushContext(lease.ctx, &git.PushOptions{TargetBranch: rolling, Force: true}); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error pushing commits to rolling branch %q: %w", rolling, err)
+	}
+	rollingCommit, err := workTree.LastCommitID()
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error getting last commit ID: %w", err)
+	}
+	batch := &kargoapi.SourceUpdateBatch{
+		RepoURL:       workTree.URL(),
+		Branch:        batching.Branch,
+		RollingBranch: rolling,
+		RollingCommit: rollingCommit,
+		Updates:       int32(len(msg.Updates)), // nolint: gosec
+	}
+	res = PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			stateKeyBranch:  rolling,
+			stateKeyCommit:  rollingCommit,
+			stateKeyUpdates: len(msg.Updates),
+		},
+		SourceUpdateBatch: batch,
+	}
+	if err = addCommitTrailersOutput(workTree, rollingCommit, res.Output); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if !msg.shouldMerge(batching, now) {
+		return res, nil
+	}
+
+	if batching.MergeMethod == kargoapi.SourceUpdateMergeMethodPullRequest {
+		if err = lease.heartbeat("open pull request"); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		pr, err := g.openBatchPullRequest(ctx, workTree.URL(), creds, batching, msg)
+		if err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		batch.PullRequestURL = pr.URL
+		res.Output[stateKeyPRNumber] = pr.Number
+		return res, nil
+	}
+
+	if err = lease.heartbeat("push " + batching.Branch); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if err = workTree.PushContext(lease.ctx, &git.PushOptions{
+		TargetBranch: batching.Branch,
+		PullRebase:   true,
+	}); err != nil {
+		if git.IsMergeConflict(err) {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+				&terminalError{err: err}
+		}
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error merging batch into branch %q: %w", batching.Branch, err)
+	}
+	if batch.MergedCommit, err = workTree.LastCommitID(); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error getting last commit ID: %w", err)
+	}
+	res.Output[stateKeyMergedCommit] = batch.MergedCommit
+	// The next update starts a new batch.
+	if err = lease.heartbeat("delete " + rolling); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if err = g.deleteRemoteBranchFn(
+		workTree.URL(), rolling, &git.ClientOptions{Credentials: creds},
+	); err != nil && !errors.Is(err, git.ErrRemoteBranchNotFound) {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error deleting rolling branch %q: %w", rolling, err)
+	}
+	return res, nil
+}
+
+// openBatchPullRequest opens a pull request for merging the rolling branch
+// into the batched branch, unless one is open already, in which case that
+// one is returned.
+func (g *gitPushPusher) openBatchPullRequest(
+	ctx context.Context,
+	repoURL string,
+	creds *git.RepoCredentials,
+	batching *kargoapi.SourceUpdateBatching,
+	msg sourceUpdateBatchMessage,
+) (*gitprovider.PullRequest, error) {
+	gpOpts := &gitprovider.Options{}
+	if creds != nil {
+		gpOpts.Username = creds.Username
+		gpOpts.Token = creds.Password
+	}
+	gitProv, err := g.newGitProviderFn(repoURL, gpOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating git provider service: %w", err)
+	}
+	rolling := rollingBranch(batching)
+	prs, err := gitProv.ListPullRequests(ctx, &gitprovider.ListPullRequestOptions{
+		State:      gitprovider.PullRequestStateOpen,
+		HeadBranch: rolling,
+		BaseBranch: batching.Branch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pull requests: %w", err)
+	}
+	if len(prs) > 0 {
+		return &prs[0], nil
+	}
+	subject, description, _ := strings.Cut(msg.String(batching.Branch), "\n\n")
+	pr, err := gitProv.CreatePullRequest(ctx, &gitprovider.CreatePullRequestOpts{
+		Head:        rolling,
+		Base:        batching.Branch,
+		Title:       subject,
+		Description: description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating pull request: %w", err)
+	}
+	return pr, nil
+}
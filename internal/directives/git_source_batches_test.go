@@ -0,0 +1,261 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func Test_sourceUpdateBatching(t *testing.T) {
+	batching := &kargoapi.SourceUpdateBatching{
+		RepoURL: "https://github.com/example/repo.git",
+		Branch:  "main",
+	}
+	stepCtx := &PromotionStepContext{SourceUpdateBatching: batching}
+	require.Same(t, batching, sourceUpdateBatching(stepCtx, "https://github.com/example/repo", "main"))
+	require.Nil(t, sourceUpdateBatching(stepCtx, "https://github.com/example/repo", "stage/test"))
+	require.Nil(t, sourceUpdateBatching(stepCtx, "https://github.com/example/other", "main"))
+	require.Nil(t, sourceUpdateBatching(&PromotionStepContext{}, batching.RepoURL, "main"))
+	require.Nil(t, sourceUpdateBatching(
+		&PromotionStepContext{
+			SourceUpdateBatching: &kargoapi.SourceUpdateBatching{
+				RepoURL:       batching.RepoURL,
+				Branch:        "main",
+				RollingBranch: "main",
+			},
+		},
+		batching.RepoURL,
+		"main",
+	))
+}
+
+func Test_newSourceUpdateBatchMessage(t *testing.T) {
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := newSourceUpdateBatchMessage(
+		[]string{"Update frontend to v1.0.0\n\nKargo-Promotion: fake-project/promo-1"},
+		started,
+	)
+	require.Equal(t, []string{"Update frontend to v1.0.0"}, first.Updates)
+	require.Equal(t, started, first.Started)
+	require.Equal(
+		t,
+		"Batch 1 update(s) to main\n\n"+
+			"- Update frontend to v1.0.0\n\n"+
+			"Kargo-Batch-Started: 2024-01-01T12:00:00Z\n"+
+			"Kargo-Promotion: fake-project/promo-1",
+		first.String("main"),
+	)
+
+	second := newSourceUpdateBatchMessage(
+		[]string{
+			first.String("main"),
+			"Update backend to v2.0.0\n\nKargo-Promotion: fake-project/promo-2",
+		},
+		started.Add(time.Hour),
+	)
+	require.Equal(
+		t,
+		[]string{"Update frontend to v1.0.0", "Update backend to v2.0.0"},
+		second.Updates,
+	)
+	require.Equal(t, started, second.Started)
+	require.Equal(t, "fake-project/promo-2", second.Trailers.Promotion)
+
+	_, ok := parseSourceUpdateBatchMessage("Update backend to v2.0.0\n\n- not a batch")
+	require.False(t, ok)
+}
+
+func Test_sourceUpdateBatchMessage_shouldMerge(t *testing.T) {
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	msg := sourceUpdateBatchMessage{Updates: []string{"a", "b"}, Started: started}
+	testCases := []struct {
+		name     string
+		batching *kargoapi.SourceUpdateBatching
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "no merge policy",
+			batching: &kargoapi.SourceUpdateBatching{},
+			now:      started.Add(24 * time.Hour),
+		},
+		{
+			name:     "not enough updates",
+			batching: &kargoapi.SourceUpdateBatching{MergeAfter: 3},
+			now:      started,
+		},
+		{
+			name:     "enough updates",
+			batching: &kargoapi.SourceUpdateBatching{MergeAfter: 2},
+			now:      started,
+			expected: true,
+		},
+		{
+			name: "interval not elapsed",
+			batching: &kargoapi.SourceUpdateBatching{
+				MergeInterval: &metav1.Duration{Duration: time.Hour},
+			},
+			now: started.Add(time.Minute),
+		},
+		{
+			name: "interval elapsed",
+			batching: &kargoapi.SourceUpdateBatching{
+				MergeInterval: &metav1.Duration{Duration: time.Hour},
+			},
+			now:      started.Add(time.Hour),
+			expected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, msg.shouldMerge(testCase.batching, testCase.now))
+		})
+	}
+}
+
+func Test_gitPusher_runPromotionStep_sourceUpdateBatching(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+	seed, err := git.Clone(remoteDir, nil, nil)
+	require.NoError(t, err)
+	defer seed.Close()
+	require.NoError(t, os.WriteFile(filepath.Join(seed.Dir(), "a.txt"), []byte("a"), 0600))
+	require.NoError(t, seed.AddAllAndCommit("Initial commit"))
+	require.NoError(t, seed.Push(&git.PushOptions{TargetBranch: "master"}))
+
+	batching := &kargoapi.SourceUpdateBatching{
+		RepoURL:    remoteDir,
+		Branch:     "master",
+		MergeAfter: 2,
+	}
+	runner, ok := newGitPusher().(*gitPushPusher)
+	require.True(t, ok)
+
+	// promote checks out the rolling branch if it exists, or master otherwise,
+	// the way gitCloner would, commits a change, and pushes it.
+	promote := func(file string) PromotionStepResult {
+		workDir := t.TempDir()
+		repo, err := git.CloneBare(remoteDir, nil, &git.BareCloneOptions{BaseDir: workDir})
+		require.NoError(t, err)
+		defer repo.Close()
+		ref, err := repo.FetchRemoteBranch(defaultRollingBranch)
+		if err != nil {
+			require.ErrorIs(t, err, git.ErrRemoteBranchNotFound)
+			ref, err = repo.FetchRemoteBranch("master")
+			require.NoError(t, err)
+		}
+		workTree, err := repo.AddWorkTree(
+			filepath.Join(workDir, "src"),
+			&git.AddWorkTreeOptions{Ref: ref, Branch: "master"},
+		)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(workTree.Dir(), file), []byte(file), 0600))
+		require.NoError(t, workTree.AddAllAndCommit("Add "+file))
+		res, err := runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{
+				Project:              "fake-project",
+				Promotion:            "fake-promotion",
+				WorkDir:              workDir,
+				CredentialsDB:        &credentials.FakeDB{},
+				SourceUpdateBatching: batching,
+			},
+			GitPushConfig{Path: "src"},
+		)
+		require.NoError(t, err)
+		require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+		return res
+	}
+
+	res := promote("b.txt")
+	require.Equal(t, defaultRollingBranch, res.Output[stateKeyBranch])
+	require.Equal(t, 1, res.Output[stateKeyUpdates])
+	require.NotContains(t, res.Output, stateKeyMergedCommit)
+	require.NotNil(t, res.SourceUpdateBatch)
+	require.Equal(t, int32(1), res.SourceUpdateBatch.Updates)
+	rollingCommit, err := git.RemoteBranchCommit(remoteDir, defaultRollingBranch, nil)
+	require.NoError(t, err)
+	require.Equal(t, res.SourceUpdateBatch.RollingCommit, rollingCommit)
+
+	res = promote("c.txt")
+	require.Equal(t, 2, res.Output[stateKeyUpdates])
+	require.NotEmpty(t, res.SourceUpdateBatch.MergedCommit)
+	masterCommit, err := git.RemoteBranchCommit(remoteDir, "master", nil)
+	require.NoError(t, err)
+	require.Equal(t, res.SourceUpdateBatch.MergedCommit, masterCommit)
+	_, err = git.RemoteBranchCommit(remoteDir, defaultRollingBranch, nil)
+	require.ErrorIs(t, err, git.ErrRemoteBranchNotFound)
+}
+
+func Test_gitPusher_runPromotionStep_sourceUpdateBatchingConflict(t *testing.T) {
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+	seed, err := git.Clone(remoteDir, nil, nil)
+	require.NoError(t, err)
+	defer seed.Close()
+	require.NoError(t, os.WriteFile(filepath.Join(seed.Dir(), "a.txt"), []byte("a"), 0600))
+	require.NoError(t, seed.AddAllAndCommit("Initial commit"))
+	require.NoError(t, seed.Push(&git.PushOptions{TargetBranch: "master"}))
+
+	batching := &kargoapi.SourceUpdateBatching{
+		RepoURL: remoteDir,
+		Branch:  "master",
+	}
+	runner, ok := newGitPusher().(*gitPushPusher)
+	require.True(t, ok)
+
+	// checkout checks out master the way gitCloner would, before the rolling
+	// branch exists, and commits a change.
+	checkout := func(file string) string {
+		workDir := t.TempDir()
+		repo, err := git.CloneBare(remoteDir, nil, &git.BareCloneOptions{BaseDir: workDir})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = repo.Close() })
+		ref, err := repo.FetchRemoteBranch("master")
+		require.NoError(t, err)
+		workTree, err := repo.AddWorkTree(
+			filepath.Join(workDir, "src"),
+			&git.AddWorkTreeOptions{Ref: ref, Branch: "master"},
+		)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(workTree.Dir(), file), []byte(file), 0600))
+		require.NoError(t, workTree.AddAllAndCommit("Add "+file))
+		return workDir
+	}
+	push := func(workDir string) (PromotionStepResult, error) {
+		return runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{
+				Project:              "fake-project",
+				Promotion:            "fake-promotion",
+				WorkDir:              workDir,
+				CredentialsDB:        &credentials.FakeDB{},
+				SourceUpdateBatching: batching,
+			},
+			GitPushConfig{Path: "src"},
+		)
+	}
+
+	first := checkout("b.txt")
+	second := checkout("c.txt")
+	res, err := push(first)
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+
+	// The rolling branch was created after the second checkout, so pushing the
+	// second update would discard the first one. This must be retryable.
+	res, err = push(second)
+	require.ErrorContains(t, err, "was updated after it was checked out")
+	require.False(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+}
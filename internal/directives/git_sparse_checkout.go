@@ -0,0 +1,142 @@
+package directives
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+// expandSparseCheckout adds to the sparse checkout of the provided working
+// tree, which initially includes the provided directories, the directories of
+// all local resources, components, and bases that Kustomization files in the
+// checked out directories refer to. As newly checked out directories may hold
+// Kustomization files of their own, this is repeated until nothing that is
+// referred to is missing.
+func expandSparseCheckout(workTree git.WorkTree, dirs []string) error {
+	included := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		included = append(included, path.Clean(filepath.ToSlash(dir)))
+	}
+	pending := included
+	for len(pending) > 0 {
+		missing, err := missingKustomizationDirs(workTree.Dir(), pending, included)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if err = workTree.AddSparseCheckoutPaths(missing...); err != nil {
+			return err
+		}
+		included = append(included, missing...)
+		pending = missing
+	}
+	return nil
+}
+
+// missingKustomizationDirs returns the directories, relative to the provided
+// root, of local resources, components, and bases that Kustomization files in
+// the provided directories refer to, but that are not within any of the
+// provided included directories. References to anything outside of the root,
+// including remote resources, are ignored.
+func missingKustomizationDirs(root string, dirs, included []string) ([]string, error) {
+	var missing []string
+	for _, dir := range dirs {
+		err := filepath.WalkDir(
+			filepath.Join(root, filepath.FromSlash(dir)),
+			func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					if os.IsNotExist(err) {
+						return nil
+					}
+					return err
+				}
+				if d.IsDir() {
+					if d.Name() == ".git" {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if !slices.Contains(konfig.RecognizedKustomizationFileNames(), d.Name()) {
+					return nil
+				}
+				for _, ref := range kustomizationLocalRefs(p) {
+					target, ok := sparseCheckoutDir(root, filepath.Dir(p), ref)
+					if !ok || sparseCheckoutIncludes(included, target) ||
+						slices.Contains(missing, target) {
+						continue
+					}
+					missing = append(missing, target)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error finding Kustomization files in %q: %w", dir, err)
+		}
+	}
+	return missing, nil
+}
+
+// kustomizationLocalRefs returns the resources, components, and bases that the
+// Kustomization file at the provided path refers to and that do not look like
+// references to remote resources. Files that cannot be read or parsed yield no
+// references, as Kustomize reports such problems well enough on its own.
+func kustomizationLocalRefs(kusPath string) []string {
+	b, err := os.ReadFile(kusPath)
+	if err != nil {
+		return nil
+	}
+	var kus kustypes.Kustomization
+	if err = yaml.Unmarshal(b, &kus); err != nil {
+		return nil
+	}
+	var refs []string
+	for _, ref := range slices.Concat(kus.Resources, kus.Components, kus.Bases) { // nolint: staticcheck
+		if !isRemoteKustomizationRef(ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// sparseCheckoutDir returns the directory, relative to the provided root, that
+// must be checked out for the provided reference from a Kustomization file in
+// the provided directory to resolve, along with true, or false if the
+// reference resolves to a path outside of the root. As files outside of a
+// sparse checkout cannot be inspected, references with a file extension are
+// assumed to refer to files, in which case the directory holding the file is
+// returned.
+func sparseCheckoutDir(root, dir, ref string) (string, bool) {
+	target := filepath.Join(dir, filepath.FromSlash(ref))
+	if filepath.Ext(target) != "" {
+		target = filepath.Dir(target)
+	}
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// sparseCheckoutIncludes returns true if the provided directory is, or is
+// beneath, any of the provided included directories.
+func sparseCheckoutIncludes(included []string, dir string) bool {
+	for _, inc := range included {
+		if inc == "." || dir == inc || strings.HasPrefix(dir, inc+"/") {
+			return true
+		}
+	}
+	return false
+}
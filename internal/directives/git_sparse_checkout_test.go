@@ -0,0 +1,70 @@
+package directives
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_missingKustomizationDirs(t *testing.T) {
+	root := t.TempDir()
+	for file, content := range map[string]string{
+		"apps/guestbook/prod/kustomization.yaml": `resources:
+- ../base
+- ../../../base/deployment.yaml
+- ../../../components/../shared
+- https://github.com/example/repo//deploy?ref=main
+- deployment.yaml
+components:
+- ../../../components/monitoring
+`,
+		"apps/guestbook/prod/deployment.yaml": "",
+		"apps/guestbook/base/kustomization.yaml": `resources:
+- ../../../../outside
+`,
+		"apps/guestbook/prod/nested/kustomization.yml": `bases:
+- ../../../../legacy
+`,
+	} {
+		p := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o700))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+
+	missing, err := missingKustomizationDirs(
+		root,
+		[]string{"apps/guestbook/prod"},
+		[]string{"apps/guestbook/prod", "components/monitoring"},
+	)
+	require.NoError(t, err)
+	require.ElementsMatch(
+		t,
+		[]string{"apps/guestbook/base", "base", "shared", "legacy"},
+		missing,
+	)
+
+	// References to paths outside of the root are ignored
+	missing, err = missingKustomizationDirs(
+		root,
+		[]string{"apps/guestbook/base"},
+		[]string{"apps/guestbook"},
+	)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	// Directories that are not checked out are skipped
+	missing, err = missingKustomizationDirs(root, []string{"absent"}, []string{"absent"})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func Test_sparseCheckoutIncludes(t *testing.T) {
+	included := []string{"apps/guestbook", "base"}
+	require.True(t, sparseCheckoutIncludes(included, "apps/guestbook"))
+	require.True(t, sparseCheckoutIncludes(included, "apps/guestbook/prod"))
+	require.False(t, sparseCheckoutIncludes(included, "apps/guestbook-v2"))
+	require.False(t, sparseCheckoutIncludes(included, "apps"))
+	require.True(t, sparseCheckoutIncludes([]string{"."}, "anything"))
+}
@@ -79,7 +79,7 @@ func (g *gitTreeClearer) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error adding all files to working tree at %s: %w", cfg.Path, err)
 	}
-	if err = workTree.Clear(); err != nil {
+	if err = workTree.Clear(&git.ClearOptions{Keep: cfg.Keep}); err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("error clearing working tree at %s: %w", cfg.Path, err)
 	}
@@ -26,6 +26,10 @@ type HealthCheckContext struct {
 	Project string
 	// Stage is the Stage that the health check is targeting.
 	Stage string
+	// ArgoCDContext is the name of the Argo CD context through which
+	// HealthCheckSteps interact with Argo CD. An empty name refers to the
+	// default context.
+	ArgoCDContext string
 }
 
 // HealthCheckStep describes a single step in a health check process.
@@ -63,6 +67,10 @@ type HealthCheckStepContext struct {
 	// value of this field will often be nil, as the Engine will only furnish this
 	// to specially privileged HealthCheckStepRunners.
 	ArgoCDClient client.Client
+	// ArgoCDNamespace is the namespace into which the Argo CD control plane that
+	// ArgoCDClient interacts with is installed. It is set whenever ArgoCDClient
+	// is.
+	ArgoCDNamespace string
 	// CredentialsDB is a database of credentials that a HealthCheckStepRunner
 	// executing a HealthCheckStep may use to acquire credentials for interacting
 	// with external systems. The value of this field will often be nil, as the
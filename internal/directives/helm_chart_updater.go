@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -384,7 +385,15 @@ func (h *helmChartUpdater) generateCommitMessage(chartPath string, newVersions m
 	_, _ = commitMsg.WriteString("Updated chart dependencies for ")
 	_, _ = commitMsg.WriteString(chartPath)
 	_, _ = commitMsg.WriteString("\n")
-	for name, change := range newVersions {
+	// Sort the dependency names, so the message is the same for the same
+	// changes.
+	names := make([]string, 0, len(newVersions))
+	for name := range newVersions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		change := newVersions[name]
 		if change == "" {
 			change = "removed"
 		}
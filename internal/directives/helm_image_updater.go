@@ -3,7 +3,6 @@ package directives
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/xeipuuv/gojsonschema"
@@ -86,7 +85,11 @@ func (h *helmImageUpdater) runPromotionStep(
 				fmt.Errorf("values file update failed: %w", err)
 		}
 
-		if commitMsg := h.generateCommitMessage(cfg.Path, fullImageRefs); commitMsg != "" {
+		if commitMsg := h.generateCommitMessage(
+			cfg.Path,
+			fullImageRefs,
+			stepCtx.CommitMessageMaxImages,
+		); commitMsg != "" {
 			result.Output = map[string]any{
 				"commitMessage": commitMsg,
 			}
@@ -171,21 +174,10 @@ func (h *helmImageUpdater) updateValuesFile(workDir string, path string, updates
 	return nil
 }
 
-func (h *helmImageUpdater) generateCommitMessage(path string, fullImageRefs []string) string {
-	if len(fullImageRefs) == 0 {
-		return ""
-	}
-
-	var commitMsg strings.Builder
-	_, _ = commitMsg.WriteString(fmt.Sprintf("Updated %s to use new image", path))
-	if len(fullImageRefs) > 1 {
-		_, _ = commitMsg.WriteString("s")
-	}
-	_, _ = commitMsg.WriteString("\n")
-
-	for _, s := range fullImageRefs {
-		_, _ = commitMsg.WriteString(fmt.Sprintf("\n- %s", s))
-	}
-
-	return commitMsg.String()
+func (h *helmImageUpdater) generateCommitMessage(
+	path string,
+	fullImageRefs []string,
+	maxImages int,
+) string {
+	return imageUpdateCommitMessage(path, fullImageRefs, maxImages)
 }
@@ -756,7 +756,7 @@ func Test_helmImageUpdater_generateCommitMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := runner.generateCommitMessage(tt.path, tt.fullImageRefs)
+			result := runner.generateCommitMessage(tt.path, tt.fullImageRefs, 0)
 			tt.assertions(t, result)
 		})
 	}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
@@ -16,10 +18,17 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/helm"
+	libos "github.com/akuity/kargo/internal/os"
+	"github.com/akuity/kargo/internal/tools"
 )
 
 func init() {
@@ -89,12 +98,27 @@ func (h *helmTemplateRunner) runPromotionStep(
 	stepCtx *PromotionStepContext,
 	cfg HelmTemplateConfig,
 ) (PromotionStepResult, error) {
+	// Charts are always rendered by the embedded version of Helm, so a Stage
+	// pinning any other version cannot be promoted to by this step.
+	if pinned := pinnedToolVersion(stepCtx, tools.Helm); pinned != "" && pinned != tools.EmbeddedHelmVersion {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, &terminalError{err: fmt.Errorf(
+			"Stage pins %s %s, but this step only renders charts using the embedded version %s",
+			tools.Helm, pinned, tools.EmbeddedHelmVersion,
+		)}
+	}
+
 	composedValues, err := h.composeValues(stepCtx.WorkDir, cfg.ValuesFiles)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("failed to compose values: %w", err)
 	}
 
+	if cfg.BuildDependencies {
+		if err = h.buildDependencies(ctx, stepCtx, cfg.Path); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+	}
+
 	chartRequested, err := h.loadChart(stepCtx.WorkDir, cfg.Path)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
@@ -128,7 +152,12 @@ func (h *helmTemplateRunner) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("failed to write rendered chart: %w", err)
 	}
-	return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+	return PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			toolVersionsOutputKey: map[string]any{string(tools.Helm): tools.EmbeddedHelmVersion},
+		},
+	}, nil
 }
 
 // composeValues composes the values from the given values files. It merges the
@@ -182,6 +211,96 @@ func (h *helmTemplateRunner) newInstallAction(
 	return client, nil
 }
 
+// buildDependencies downloads the dependencies of the chart at the given path
+// into its charts/ directory, at the versions recorded in its Chart.lock. If
+// the chart has no Chart.lock, the dependencies are resolved from the version
+// constraints in its Chart.yaml instead. Credentials for chart repositories are
+// obtained, and file dependencies are validated, in the same manner as by the
+// helm-update-chart step.
+func (h *helmTemplateRunner) buildDependencies(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	relPath string,
+) error {
+	chartPath, err := securejoin.SecureJoin(stepCtx.WorkDir, relPath)
+	if err != nil {
+		return fmt.Errorf("failed to join path %q: %w", relPath, err)
+	}
+	chartFilePath := filepath.Join(chartPath, "Chart.yaml")
+	chartDependencies, err := readChartDependencies(chartFilePath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load chart dependencies from %q: %w",
+			relPath, sanitizePathError(err, stepCtx.WorkDir),
+		)
+	}
+	if len(chartDependencies) == 0 {
+		return nil
+	}
+
+	// The validation and credential handling of helm-update-chart do not depend
+	// on any of its state, so we can borrow them.
+	updater := &helmChartUpdater{}
+	for _, dep := range chartDependencies {
+		if strings.HasPrefix(dep.Repository, "file://") {
+			depPath := filepath.FromSlash(strings.TrimPrefix(dep.Repository, "file://"))
+			if err = updater.validateFileDependency(stepCtx.WorkDir, chartPath, depPath); err != nil {
+				return fmt.Errorf("invalid dependency %q: %w", dep.Repository, err)
+			}
+		}
+	}
+
+	helmHome, err := os.MkdirTemp("", "helm-template-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary Helm home directory: %w", err)
+	}
+	defer os.RemoveAll(helmHome)
+
+	registryClient, err := helm.NewRegistryClient(helmHome)
+	if err != nil {
+		return fmt.Errorf("failed to create Helm registry client: %w", err)
+	}
+	repositoryFile := repo.NewFile()
+	if err = updater.loadDependencyCredentials(
+		ctx,
+		stepCtx.CredentialsDB,
+		registryClient,
+		repositoryFile,
+		stepCtx.Project,
+		chartDependencies,
+	); err != nil {
+		return err
+	}
+	repositoryConfig := filepath.Join(helmHome, "repositories.yaml")
+	if err = repositoryFile.WriteFile(repositoryConfig, 0o600); err != nil {
+		return fmt.Errorf("failed to write Helm repositories file: %w", err)
+	}
+
+	env := &cli.EnvSettings{
+		RepositoryConfig: repositoryConfig,
+		RepositoryCache:  filepath.Join(helmHome, "cache"),
+	}
+	manager := downloader.Manager{
+		Out:              io.Discard,
+		ChartPath:        chartPath,
+		Verify:           downloader.VerifyNever,
+		Getters:          getter.All(env),
+		RegistryClient:   registryClient,
+		RepositoryConfig: env.RepositoryConfig,
+		RepositoryCache:  env.RepositoryCache,
+	}
+	// NB: Build() fails if Chart.lock is out of sync with Chart.yaml. This is
+	// intentional, as silently resolving different versions than the ones that
+	// were locked would make the rendered manifests unpredictable.
+	if err = manager.Build(); err != nil {
+		return fmt.Errorf(
+			"failed to build chart dependencies: %w",
+			sanitizePathError(err, stepCtx.WorkDir),
+		)
+	}
+	return nil
+}
+
 // loadChart loads the chart from the given path.
 func (h *helmTemplateRunner) loadChart(workDir, relPath string) (*chart.Chart, error) {
 	absChartPath, err := securejoin.SecureJoin(workDir, relPath)
@@ -208,6 +327,11 @@ func (h *helmTemplateRunner) writeOutput(cfg HelmTemplateConfig, rls *release.Re
 		outPathIsFile = cfg.OutPathIsFile()
 	)
 
+	mode, err := parseOutFileMode(cfg.OutFileMode)
+	if err != nil {
+		return err
+	}
+
 	if outPathIsFile {
 		_, _ = fmt.Fprintln(&manifests, strings.TrimSpace(rls.Manifest))
 	}
@@ -242,13 +366,37 @@ func (h *helmTemplateRunner) writeOutput(cfg HelmTemplateConfig, rls *release.Re
 	}
 
 	if !outPathIsFile {
-		return nil
+		// Files in the output directory are written by Helm itself. Their mode
+		// is only changed if one was explicitly specified.
+		if cfg.OutFileMode == "" || rls.Chart == nil {
+			return nil
+		}
+		outDir := filepath.Join(outPath, rls.Chart.Name())
+		if cfg.UseReleaseName {
+			outDir = filepath.Join(outPath, cfg.ReleaseName)
+		}
+		return chmodFiles(outDir, mode)
 	}
 
 	if err := os.MkdirAll(filepath.Dir(outPath), 0o700); err != nil {
 		return fmt.Errorf("failed to create directory %q: %w", cfg.OutPath, err)
 	}
-	return os.WriteFile(outPath, manifests.Bytes(), 0o600)
+	return libos.WriteFileAtomic(outPath, manifests.Bytes(), mode)
+}
+
+// chmodFiles sets the mode of all regular files in the provided directory and
+// its subdirectories. A directory that does not exist is ignored.
+func chmodFiles(dir string, mode os.FileMode) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		return os.Chmod(path, mode)
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to set mode of files in %q: %w", dir, err)
+	}
+	return nil
 }
 
 // defaultValue returns the value if it is not zero or empty, otherwise it
@@ -14,14 +14,23 @@ import (
 	"helm.sh/helm/v3/pkg/release"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/tools"
 )
 
 func Test_helmTemplateRunner_runPromotionStep(t *testing.T) {
+	succeeded := PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			toolVersionsOutputKey: map[string]any{string(tools.Helm): tools.EmbeddedHelmVersion},
+		},
+	}
+
 	tests := []struct {
-		name       string
-		files      map[string]string
-		cfg        HelmTemplateConfig
-		assertions func(*testing.T, string, PromotionStepResult, error)
+		name         string
+		files        map[string]string
+		toolVersions *kargoapi.ToolVersions
+		cfg          HelmTemplateConfig
+		assertions   func(*testing.T, string, PromotionStepResult, error)
 	}{
 		{
 			name: "successful run",
@@ -49,7 +58,7 @@ data:
 			},
 			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, result)
+				assert.Equal(t, succeeded, result)
 
 				outPath := filepath.Join(workDir, "output.yaml")
 				require.FileExists(t, outPath)
@@ -95,7 +104,7 @@ data:
 			},
 			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, result)
+				assert.Equal(t, succeeded, result)
 
 				outPath := filepath.Join(workDir, "output.yaml")
 				require.FileExists(t, outPath)
@@ -140,7 +149,7 @@ data:
 			},
 			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, result)
+				assert.Equal(t, succeeded, result)
 
 				outPath := filepath.Join(workDir, "output", "test-chart")
 				require.DirExists(t, outPath)
@@ -206,6 +215,75 @@ dependencies:
 				require.NoFileExists(t, filepath.Join(workDir, "output.yaml"))
 			},
 		},
+		{
+			name: "successful run with dependencies built",
+			files: map[string]string{
+				"charts/test-chart/Chart.yaml": `apiVersion: v2
+name: test-chart
+version: 0.1.0
+dependencies:
+- name: dep-chart
+  version: 0.1.0
+  repository: file://../dep-chart`,
+				"charts/dep-chart/Chart.yaml": `apiVersion: v2
+name: dep-chart
+version: 0.1.0`,
+				"charts/dep-chart/templates/test.yaml": `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-configmap
+`,
+			},
+			cfg: HelmTemplateConfig{
+				Path:              "charts/test-chart",
+				OutPath:           "output.yaml",
+				ReleaseName:       "test-release",
+				BuildDependencies: true,
+			},
+			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, succeeded, result)
+
+				require.FileExists(t, filepath.Join(workDir, "charts/test-chart/charts/dep-chart-0.1.0.tgz"))
+				content, err := os.ReadFile(filepath.Join(workDir, "output.yaml"))
+				require.NoError(t, err)
+				assert.Contains(t, string(content), "name: test-release-configmap")
+			},
+		},
+		{
+			name: "out of sync lock file",
+			files: map[string]string{
+				"charts/test-chart/Chart.yaml": `apiVersion: v2
+name: test-chart
+version: 0.1.0
+dependencies:
+- name: dep-chart
+  version: 0.1.0
+  repository: file://../dep-chart`,
+				"charts/test-chart/Chart.lock": `dependencies:
+- name: dep-chart
+  repository: file://../dep-chart
+  version: 0.0.1
+digest: sha256:0000000000000000000000000000000000000000000000000000000000000000
+generated: "2025-01-01T00:00:00Z"`,
+				"charts/dep-chart/Chart.yaml": `apiVersion: v2
+name: dep-chart
+version: 0.1.0`,
+			},
+			cfg: HelmTemplateConfig{
+				Path:              "charts/test-chart",
+				OutPath:           "output.yaml",
+				BuildDependencies: true,
+			},
+			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "failed to build chart dependencies")
+				require.ErrorContains(t, err, "out of sync")
+				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
+
+				require.NoFileExists(t, filepath.Join(workDir, "output.yaml"))
+			},
+		},
 		{
 			name: "Helm action initialization error",
 			files: map[string]string{
@@ -272,6 +350,26 @@ metadata:
 				require.ErrorContains(t, err, "failed to write rendered chart")
 				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
 
+				require.NoFileExists(t, filepath.Join(workDir, "output.yaml"))
+			},
+		},
+		{
+			name: "pinned Helm version is not embedded",
+			files: map[string]string{
+				"chart/Chart.yaml": `apiVersion: v1
+name: test-chart
+version: 0.1.0`,
+			},
+			toolVersions: &kargoapi.ToolVersions{Helm: "v3.15.0"},
+			cfg: HelmTemplateConfig{
+				Path:    "./chart/",
+				OutPath: "output.yaml",
+			},
+			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "Stage pins helm v3.15.0")
+				require.True(t, isTerminal(err))
+				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
+
 				require.NoFileExists(t, filepath.Join(workDir, "output.yaml"))
 			},
 		},
@@ -287,8 +385,9 @@ metadata:
 				require.NoError(t, os.WriteFile(filepath.Join(workDir, p), []byte(c), 0o600))
 			}
 			stepCtx := &PromotionStepContext{
-				WorkDir: workDir,
-				Project: "test-project",
+				WorkDir:      workDir,
+				Project:      "test-project",
+				ToolVersions: tt.toolVersions,
 			}
 			result, err := runner.runPromotionStep(context.Background(), stepCtx, tt.cfg)
 			tt.assertions(t, workDir, result, err)
@@ -549,6 +648,54 @@ func Test_helmTemplateRunner_writeOutput(t *testing.T) {
 				assert.Equal(t, "key: value\n", string(content))
 			},
 		},
+		{
+			name: "write to file with mode",
+			cfg: HelmTemplateConfig{
+				OutPath:     "output.yaml",
+				OutFileMode: "0644",
+			},
+			rls: &release.Release{
+				Manifest: "key: value",
+			},
+			setup: func(t *testing.T) (workDir string) {
+				return t.TempDir()
+			},
+			assertions: func(t *testing.T, workDir string, err error) {
+				require.NoError(t, err)
+
+				fi, err := os.Stat(filepath.Join(workDir, "output.yaml"))
+				require.NoError(t, err)
+				assert.Equal(t, os.FileMode(0o644), fi.Mode().Perm())
+			},
+		},
+		{
+			name: "set mode of files in output directory",
+			cfg: HelmTemplateConfig{
+				OutPath:     "output",
+				OutFileMode: "0640",
+			},
+			rls: &release.Release{
+				Chart: &chart.Chart{Metadata: &chart.Metadata{Name: "demo"}},
+			},
+			setup: func(t *testing.T) (workDir string) {
+				workDir = t.TempDir()
+				templatesDir := filepath.Join(workDir, "output", "demo", "templates")
+				require.NoError(t, os.MkdirAll(templatesDir, 0o700))
+				require.NoError(t, os.WriteFile(
+					filepath.Join(templatesDir, "deployment.yaml"),
+					[]byte("key: value\n"),
+					0o600,
+				))
+				return workDir
+			},
+			assertions: func(t *testing.T, workDir string, err error) {
+				require.NoError(t, err)
+
+				fi, err := os.Stat(filepath.Join(workDir, "output", "demo", "templates", "deployment.yaml"))
+				require.NoError(t, err)
+				assert.Equal(t, os.FileMode(0o640), fi.Mode().Perm())
+			},
+		},
 		{
 			name: "write to non-existent directory",
 			cfg: HelmTemplateConfig{
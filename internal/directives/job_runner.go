@@ -0,0 +1,221 @@
+package directives
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+const (
+	// jobRunnerComponent is the value of the component label of the Jobs
+	// created by the run-job step.
+	jobRunnerComponent = "run-job"
+	// stateKeyJobName is the key of the name of the Job in the output of the
+	// run-job step.
+	stateKeyJobName = "jobName"
+)
+
+func init() {
+	builtins.RegisterPromotionStepRunner(
+		newJobRunner(),
+		&StepRunnerPermissions{AllowKargoClient: true},
+	)
+}
+
+// jobRunner is an implementation of the PromotionStepRunner interface that
+// runs a Kubernetes Job in the Project's namespace and waits for it to
+// complete.
+type jobRunner struct {
+	schemaLoader gojsonschema.JSONLoader
+}
+
+// newJobRunner returns an implementation of the PromotionStepRunner interface
+// that runs a Kubernetes Job in the Project's namespace and waits for it to
+// complete.
+func newJobRunner() PromotionStepRunner {
+	r := &jobRunner{}
+	r.schemaLoader = getConfigSchemaLoader(r.Name())
+	return r
+}
+
+// Name implements the PromotionStepRunner interface.
+func (j *jobRunner) Name() string {
+	return "run-job"
+}
+
+// DefaultTimeout implements the RetryableStepRunner interface.
+func (j *jobRunner) DefaultTimeout() *time.Duration {
+	return ptr.To(30 * time.Minute)
+}
+
+// DefaultErrorThreshold implements the RetryableStepRunner interface.
+func (j *jobRunner) DefaultErrorThreshold() uint32 {
+	return 0 // Will fall back to the system default.
+}
+
+// RunPromotionStep implements the PromotionStepRunner interface.
+func (j *jobRunner) RunPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+) (PromotionStepResult, error) {
+	if err := j.validate(stepCtx.Config); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	cfg, err := ConfigToStruct[RunJobConfig](stepCtx.Config)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not convert config into run-job config: %w", err)
+	}
+	return j.runPromotionStep(ctx, stepCtx, cfg)
+}
+
+// validate validates jobRunner configuration against a JSON schema.
+func (j *jobRunner) validate(cfg Config) error {
+	return validate(j.schemaLoader, gojsonschema.NewGoLoader(cfg), j.Name())
+}
+
+func (j *jobRunner) runPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg RunJobConfig,
+) (PromotionStepResult, error) {
+	// Jobs run arbitrary workloads in the Project's namespace, so they are
+	// only ever created with the permissions of the Stage's ServiceAccount,
+	// never with those of the controller.
+	c := stepCtx.ServiceAccountClient
+	if c == nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+			err: fmt.Errorf(
+				"Stage %q does not reference a ServiceAccount to run Jobs as", stepCtx.Stage,
+			),
+		}
+	}
+
+	name := jobName(stepCtx.Promotion, stepCtx.Alias)
+	logger := logging.LoggerFromContext(ctx).WithValues("job", name)
+	job := &batchv1.Job{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: stepCtx.Project, Name: name}, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err = j.createJob(ctx, stepCtx, name, cfg); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		logger.Debug("created Job")
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseRunning}, nil
+	case err != nil:
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error getting Job %q in namespace %q: %w", name, stepCtx.Project, err,
+		)
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			logger.Debug("Job completed")
+			return PromotionStepResult{
+				Status: kargoapi.PromotionPhaseSucceeded,
+				Output: map[string]any{stateKeyJobName: name},
+			}, nil
+		case batchv1.JobFailed:
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, &terminalError{
+				err: fmt.Errorf("Job %q failed: %s: %s", name, cond.Reason, cond.Message),
+			}
+		}
+	}
+	logger.Debug("Job has not completed yet")
+	return PromotionStepResult{Status: kargoapi.PromotionPhaseRunning}, nil
+}
+
+// createJob creates the Job with the provided name and the spec specified by
+// the provided configuration on behalf of the Promotion described by the
+// provided PromotionStepContext. The Job is owned by the Promotion, so that it
+// is garbage collected along with it.
+func (j *jobRunner) createJob(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	name string,
+	cfg RunJobConfig,
+) error {
+	specJSON, err := json.Marshal(cfg.Spec)
+	if err != nil {
+		return fmt.Errorf("error marshaling Job spec: %w", err)
+	}
+	spec := batchv1.JobSpec{}
+	if err = json.Unmarshal(specJSON, &spec); err != nil {
+		return &terminalError{err: fmt.Errorf("invalid Job spec: %w", err)}
+	}
+
+	promo := &kargoapi.Promotion{}
+	if err = stepCtx.KargoClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: stepCtx.Project, Name: stepCtx.Promotion},
+		promo,
+	); err != nil {
+		return fmt.Errorf(
+			"error getting Promotion %q in namespace %q: %w",
+			stepCtx.Promotion, stepCtx.Project, err,
+		)
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kargoapi.StageLabelKey: stepCtx.Stage},
+		},
+		Spec: spec,
+	}
+	// The Job is created by the ChildCreator, which labels it and makes it
+	// owned by the Promotion, so that it counts towards the limit of objects
+	// created on behalf of the Promotion.
+	err = stepCtx.ChildCreator.CreateAs(
+		ctx, stepCtx.ServiceAccountClient, promo, jobRunnerComponent, job,
+	)
+	switch {
+	case err == nil, apierrors.IsAlreadyExists(err):
+		return nil
+	case errors.Is(err, controller.ErrChildLimitExceeded),
+		apierrors.IsInvalid(err), apierrors.IsForbidden(err):
+		return &terminalError{err: fmt.Errorf(
+			"error creating Job %q in namespace %q: %w", name, stepCtx.Project, err,
+		)}
+	default:
+		return fmt.Errorf(
+			"error creating Job %q in namespace %q: %w", name, stepCtx.Project, err,
+		)
+	}
+}
+
+// jobName returns the name of the Job that the step with the provided alias
+// runs on behalf of the Promotion with the provided name. The name is the same
+// every time the step is executed, so that the step finds the Job it created
+// when it is executed again to check on it. It is short enough to be used as
+// the value of the labels that the Job controller sets on its Pods.
+func jobName(promotion, alias string) string {
+	sum := sha256.Sum256([]byte(promotion + "/" + alias))
+	suffix := fmt.Sprintf("-%x", sum[:5])
+	prefix := strings.ReplaceAll(promotion, ".", "-")
+	if maxLen := validation.DNS1123LabelMaxLength - len(suffix); len(prefix) > maxLen {
+		prefix = prefix[:maxLen]
+	}
+	return strings.TrimRight(prefix, "-") + suffix
+}
@@ -0,0 +1,312 @@
+package directives
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller"
+)
+
+func Test_jobRunner_validate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		config           Config
+		expectedProblems []string
+	}{
+		{
+			name:   "spec not specified",
+			config: Config{},
+			expectedProblems: []string{
+				"(root): spec is required",
+			},
+		},
+		{
+			name: "spec is not an object",
+			config: Config{
+				"spec": "invalid",
+			},
+			expectedProblems: []string{
+				"spec: Invalid type",
+			},
+		},
+		{
+			name: "valid kitchen sink",
+			config: Config{
+				"spec": map[string]any{
+					"backoffLimit": 0,
+					"template": map[string]any{
+						"spec": map[string]any{
+							"restartPolicy": "Never",
+							"containers": []any{
+								map[string]any{
+									"name":  "hook",
+									"image": "alpine",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	r := newJobRunner()
+	runner, ok := r.(*jobRunner)
+	require.True(t, ok)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := runner.validate(testCase.config)
+			if len(testCase.expectedProblems) == 0 {
+				require.NoError(t, err)
+			} else {
+				for _, problem := range testCase.expectedProblems {
+					require.ErrorContains(t, err, problem)
+				}
+			}
+		})
+	}
+}
+
+func Test_jobRunner_runPromotionStep(t *testing.T) {
+	const (
+		testProject   = "fake-project"
+		testPromotion = "fake-promotion"
+		testAlias     = "pre-hook"
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	testPromo := &kargoapi.Promotion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testProject,
+			Name:      testPromotion,
+			UID:       "fake-uid",
+		},
+	}
+	testCfg := RunJobConfig{
+		Spec: map[string]interface{}{
+			"backoffLimit": 0,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"restartPolicy": "Never",
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "hook",
+							"image": "alpine",
+						},
+					},
+				},
+			},
+		},
+	}
+	testJobName := jobName(testPromotion, testAlias)
+	jobWithCondition := func(condType batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testProject,
+				Name:      testJobName,
+			},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{
+					Type:    condType,
+					Status:  corev1.ConditionTrue,
+					Reason:  "FakeReason",
+					Message: "fake message",
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		saClient   func() client.Client
+		children   []client.Object
+		cfg        RunJobConfig
+		assertions func(*testing.T, client.Client, PromotionStepResult, error)
+	}{
+		{
+			name:     "no ServiceAccount",
+			saClient: func() client.Client { return nil },
+			cfg:      testCfg,
+			assertions: func(t *testing.T, _ client.Client, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "does not reference a ServiceAccount")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+		{
+			name: "invalid Job spec",
+			saClient: func() client.Client {
+				return fake.NewClientBuilder().WithScheme(scheme).Build()
+			},
+			cfg: RunJobConfig{
+				Spec: map[string]interface{}{"backoffLimit": "invalid"},
+			},
+			assertions: func(t *testing.T, _ client.Client, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "invalid Job spec")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+			},
+		},
+		{
+			name: "Job is created",
+			saClient: func() client.Client {
+				return fake.NewClientBuilder().WithScheme(scheme).Build()
+			},
+			cfg: testCfg,
+			assertions: func(t *testing.T, c client.Client, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+
+				job := &batchv1.Job{}
+				require.NoError(t, c.Get(
+					context.Background(),
+					types.NamespacedName{Namespace: testProject, Name: testJobName},
+					job,
+				))
+				require.Equal(t, testPromotion, job.Labels[kargoapi.PromotionLabelKey])
+				require.Equal(t, jobRunnerComponent, job.Labels[kargoapi.ComponentLabelKey])
+				require.Len(t, job.OwnerReferences, 1)
+				require.Equal(t, testPromo.UID, job.OwnerReferences[0].UID)
+				require.Equal(t, "alpine", job.Spec.Template.Spec.Containers[0].Image)
+			},
+		},
+		{
+			name: "child limit exceeded",
+			saClient: func() client.Client {
+				return fake.NewClientBuilder().WithScheme(scheme).Build()
+			},
+			children: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testProject,
+						Name:      "fake-transcript",
+						Labels:    map[string]string{kargoapi.PromotionLabelKey: testPromotion},
+						OwnerReferences: []metav1.OwnerReference{
+							*metav1.NewControllerRef(testPromo, kargoapi.GroupVersion.WithKind("Promotion")),
+						},
+					},
+				},
+			},
+			cfg: testCfg,
+			assertions: func(t *testing.T, c client.Client, res PromotionStepResult, err error) {
+				require.ErrorIs(t, err, controller.ErrChildLimitExceeded)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseErrored, res.Status)
+
+				err = c.Get(
+					context.Background(),
+					types.NamespacedName{Namespace: testProject, Name: testJobName},
+					&batchv1.Job{},
+				)
+				require.True(t, apierrors.IsNotFound(err))
+			},
+		},
+		{
+			name: "Job has not completed",
+			saClient: func() client.Client {
+				return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					&batchv1.Job{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testProject,
+							Name:      testJobName,
+						},
+					},
+				).Build()
+			},
+			cfg: testCfg,
+			assertions: func(t *testing.T, _ client.Client, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseRunning, res.Status)
+			},
+		},
+		{
+			name: "Job completed",
+			saClient: func() client.Client {
+				return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					jobWithCondition(batchv1.JobComplete),
+				).Build()
+			},
+			cfg: testCfg,
+			assertions: func(t *testing.T, _ client.Client, res PromotionStepResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+				require.Equal(t, map[string]any{stateKeyJobName: testJobName}, res.Output)
+			},
+		},
+		{
+			name: "Job failed",
+			saClient: func() client.Client {
+				return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					jobWithCondition(batchv1.JobFailed),
+				).Build()
+			},
+			cfg: testCfg,
+			assertions: func(t *testing.T, _ client.Client, res PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "FakeReason: fake message")
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+			},
+		},
+	}
+
+	runner := &jobRunner{}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			saClient := testCase.saClient()
+			kargoClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(testPromo.DeepCopy()).
+				WithObjects(testCase.children...).
+				Build()
+			res, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{
+					Project:              testProject,
+					Stage:                "fake-stage",
+					Promotion:            testPromotion,
+					Alias:                testAlias,
+					KargoClient:          kargoClient,
+					ServiceAccountClient: saClient,
+					ChildCreator:         controller.NewChildCreator(kargoClient, 1),
+				},
+				testCase.cfg,
+			)
+			testCase.assertions(t, saClient, res, err)
+		})
+	}
+}
+
+func Test_jobName(t *testing.T) {
+	t.Run("is deterministic", func(t *testing.T) {
+		require.Equal(t, jobName("promo", "step-1"), jobName("promo", "step-1"))
+	})
+
+	t.Run("differs by step", func(t *testing.T) {
+		require.NotEqual(t, jobName("promo", "step-1"), jobName("promo", "step-2"))
+	})
+
+	t.Run("is a valid label value", func(t *testing.T) {
+		name := jobName(
+			"test.01jfhwsm6nc6amvgy3y2pjx8fh."+strings.Repeat("a", 64),
+			"step-1",
+		)
+		require.LessOrEqual(t, len(name), 63)
+		require.NotContains(t, name, ".")
+	})
+}
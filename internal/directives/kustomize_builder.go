@@ -1,9 +1,13 @@
 package directives
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -11,12 +15,19 @@ import (
 	securejoin "github.com/cyphar/filepath-securejoin"
 	securefs "github.com/fluxcd/pkg/kustomize/filesys"
 	"github.com/xeipuuv/gojsonschema"
+	kusbuiltins "sigs.k8s.io/kustomize/api/builtins"
 	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/provider"
 	"sigs.k8s.io/kustomize/api/resmap"
 	kustypes "sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/kustomize"
+	libos "github.com/akuity/kargo/internal/os"
+	"github.com/akuity/kargo/internal/tools"
 )
 
 // kustomizeRenderMutex is a mutex that ensures only one kustomize build is
@@ -25,8 +36,18 @@ import (
 // xref: https://github.com/kubernetes-sigs/kustomize/issues/3659
 var kustomizeRenderMutex sync.Mutex
 
+// errRenderTooLarge is wrapped by the errors that kustomize-build returns when
+// the manifests it renders exceed the maximum rendered output size.
+var errRenderTooLarge = errors.New("RenderTooLarge")
+
 func init() {
-	builtins.RegisterPromotionStepRunner(newKustomizeBuilder(), nil)
+	builtins.RegisterPromotionStepRunner(
+		newKustomizeBuilder(),
+		&StepRunnerPermissions{
+			AllowArgoCDClient:  true,
+			AllowCredentialsDB: true,
+		},
+	)
 }
 
 // kustomizeBuilder is an implementation of the PromotionStepRunner interface
@@ -51,7 +72,7 @@ func (k *kustomizeBuilder) Name() string {
 
 // RunPromotionStep implements the PromotionStepRunner interface.
 func (k *kustomizeBuilder) RunPromotionStep(
-	_ context.Context,
+	ctx context.Context,
 	stepCtx *PromotionStepContext,
 ) (PromotionStepResult, error) {
 	failure := PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}
@@ -67,10 +88,11 @@ func (k *kustomizeBuilder) RunPromotionStep(
 		return failure, fmt.Errorf("could not convert config into %s config: %w", k.Name(), err)
 	}
 
-	return k.runPromotionStep(stepCtx, cfg)
+	return k.runPromotionStep(ctx, stepCtx, cfg)
 }
 
 func (k *kustomizeBuilder) runPromotionStep(
+	ctx context.Context,
 	stepCtx *PromotionStepContext,
 	cfg KustomizeBuildConfig,
 ) (PromotionStepResult, error) {
@@ -80,48 +102,179 @@ func (k *kustomizeBuilder) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
-	// Build the manifests.
-	rm, err := kustomizeBuild(fs, filepath.Join(stepCtx.WorkDir, cfg.Path), cfg.Plugin)
+	mode, err := parseOutFileMode(cfg.OutFileMode)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Verify the overlay up front, as the errors Kustomize returns for a
+	// misconfigured overlay are difficult to make sense of.
+	if _, err = validateKustomizeOverlay(stepCtx.WorkDir, cfg.Path); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Fetch any remote bases using the Project's credentials, as Kustomize
+	// has no access to them.
+	restoreRemoteBases, err := fetchRemoteBases(ctx, stepCtx, cfg.Path)
+	defer restoreRemoteBases()
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Use the versions of Kustomize and Helm pinned by the Stage, if any.
+	kustomizeBin, kustomizeVersion, err := resolveTool(ctx, stepCtx, tools.Kustomize)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	toolVersions := map[string]any{string(tools.Kustomize): kustomizeVersion}
+	helmCommand, err := kustomizeHelmCommand(ctx, stepCtx, toolVersions)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
+	// Build the manifests, capturing any warnings Kustomize emits.
+	var rm resmap.ResMap
+	var warnings []string
+	if kustomizeBin == "" {
+		warnings = kustomizationWarnings(stepCtx.WorkDir, cfg.Path)
+		rm, err = kustomizeBuild(
+			fs, filepath.Join(stepCtx.WorkDir, cfg.Path), cfg.Plugin, cfg.Patches, helmCommand,
+		)
+	} else {
+		rm, warnings, err = kustomizeBuildWithBinary(
+			ctx, kustomizeBin, stepCtx.WorkDir, cfg.Path, cfg.Plugin, cfg.Patches, helmCommand,
+			stepCtx.MaxRenderedOutputSize,
+		)
+	}
+	if err == nil {
+		err = verifyRenderedOutputSize(rm, stepCtx.MaxRenderedOutputSize)
+	}
+	if errors.Is(err, errRenderTooLarge) {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, err
+	}
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if err = strictRenderError(stepCtx, warnings); err != nil {
+		return PromotionStepResult{
+			Status:         kargoapi.PromotionPhaseFailed,
+			RenderWarnings: warnings,
+		}, err
+	}
+
 	// Prepare the output path.
 	outPath, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.OutPath)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
+	// Take into account where the Argo CD Application the manifests are built
+	// for reads them from, if any. Manifests written to the root of a working
+	// tree are written to the Application's path within it instead.
+	var appDir *appDirectory
+	if cfg.App != nil {
+		workTreeRoot := findWorkTreeRoot(stepCtx.WorkDir, outPath)
+		if workTreeRoot == "" {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+				"outPath %q is not within a working tree of a Git repository", cfg.OutPath,
+			)
+		}
+		if appDir, err = getAppDirectory(ctx, stepCtx, cfg.App, workTreeRoot); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+		}
+		if outPath == workTreeRoot && !isManifestFile(outPath) {
+			outPath = appDir.path
+		}
+	}
+
 	// Write the built manifests to the output path.
-	if err := k.writeResult(rm, outPath); err != nil {
+	files, err := k.writeResult(rm, outPath, mode, cfg.Normalize)
+	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
 			"failed to write built manifests to %q: %w", cfg.OutPath,
 			sanitizePathError(err, stepCtx.WorkDir),
 		)
 	}
-	return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+	res := PromotionStepResult{
+		Status:         kargoapi.PromotionPhaseSucceeded,
+		Output:         map[string]any{toolVersionsOutputKey: toolVersions},
+		RenderWarnings: warnings,
+	}
+	if appDir != nil {
+		res.OutputIgnoredCondition = appDir.outputIgnoredCondition(stepCtx.WorkDir, files)
+	}
+	return res, nil
 }
 
-func (k *kustomizeBuilder) writeResult(rm resmap.ResMap, outPath string) error {
-	if ext := filepath.Ext(outPath); ext == ".yaml" || ext == ".yml" {
+// isManifestFile returns true if the provided path is that of a file that
+// manifests are written to, rather than of a directory that each manifest is
+// written to a separate file in.
+func isManifestFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// kustomizeHelmCommand returns the Helm binary that Kustomize inflates Helm
+// charts with. If the Stage pins a version of Helm, the binary of that version
+// is returned and the version is recorded in the provided tool versions. As
+// Kargo does not embed a Helm binary, an empty command, which disables the
+// inflation of Helm charts, is returned if no tool cache is configured to
+// provide it.
+func kustomizeHelmCommand(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	toolVersions map[string]any,
+) (string, error) {
+	version := pinnedToolVersion(stepCtx, tools.Helm)
+	if version == "" {
+		return "helm", nil
+	}
+	if stepCtx.ToolCache == nil {
+		return "", nil
+	}
+	path, err := stepCtx.ToolCache.Path(ctx, tools.Helm, version)
+	if err != nil {
+		return "", fmt.Errorf("error getting %s %s: %w", tools.Helm, version, err)
+	}
+	toolVersions[string(tools.Helm)] = version
+	return path, nil
+}
+
+// writeResult writes the provided built manifests to the provided path, which
+// is either a file or a directory to write each manifest to a separate file
+// in, normalized as described by the provided configuration. It returns the
+// paths of the files written.
+func (k *kustomizeBuilder) writeResult(
+	rm resmap.ResMap,
+	outPath string,
+	mode os.FileMode,
+	normalize *Normalize,
+) ([]string, error) {
+	if isManifestFile(outPath) {
 		if err := os.MkdirAll(filepath.Dir(outPath), 0o700); err != nil {
-			return err
+			return nil, err
 		}
 		b, err := rm.AsYaml()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return os.WriteFile(outPath, b, 0o600)
+		if b, err = normalizeManifests(b, normalize); err != nil {
+			return nil, fmt.Errorf("failed to normalize manifests: %w", err)
+		}
+		return []string{outPath}, libos.WriteFileAtomic(outPath, b, mode)
 	}
 
 	// If the output path is a directory, write each manifest to a separate file.
 	if err := os.MkdirAll(outPath, 0o700); err != nil {
-		return err
+		return nil, err
 	}
+	files := make([]string, 0, rm.Size())
 	for _, r := range rm.Resources() {
 		kind, namespace, name := r.GetKind(), r.GetNamespace(), r.GetName()
 		if kind == "" || name == "" {
-			return fmt.Errorf("resource kind and name of %q must be non-empty to write to a directory", r.CurId())
+			return nil, fmt.Errorf(
+				"resource kind and name of %q must be non-empty to write to a directory", r.CurId(),
+			)
 		}
 
 		fileName := fmt.Sprintf("%s-%s", kind, name)
@@ -131,19 +284,53 @@ func (k *kustomizeBuilder) writeResult(rm resmap.ResMap, outPath string) error {
 
 		b, err := r.AsYAML()
 		if err != nil {
-			return fmt.Errorf("failed to convert %q to YAML: %w", r.CurId(), err)
+			return nil, fmt.Errorf("failed to convert %q to YAML: %w", r.CurId(), err)
+		}
+		if b, err = normalizeManifests(b, normalize); err != nil {
+			return nil, fmt.Errorf("failed to normalize %q: %w", r.CurId(), err)
 		}
 
 		path := filepath.Join(outPath, fmt.Sprintf("%s.yaml", strings.ToLower(fileName)))
-		if err = os.WriteFile(path, b, 0o600); err != nil {
-			return err
+		if err = libos.WriteFileAtomic(path, b, mode); err != nil {
+			return nil, err
 		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// verifyRenderedOutputSize returns a terminal error wrapping errRenderTooLarge
+// if the given built manifests are larger than the given maximum size in
+// bytes, in which case they must not be written. A maximum size of 0 means no
+// limit.
+func verifyRenderedOutputSize(rm resmap.ResMap, maxSize int64) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	b, err := rm.AsYaml()
+	if err != nil {
+		return err
+	}
+	if size := int64(len(b)); size > maxSize {
+		return &terminalError{err: fmt.Errorf(
+			"%w: rendered manifests are %d bytes, which exceeds the maximum "+
+				"rendered output size of %d bytes",
+			errRenderTooLarge, size, maxSize,
+		)}
 	}
 	return nil
 }
 
-// kustomizeBuild builds the manifests in the given directory using Kustomize.
-func kustomizeBuild(fs filesys.FileSystem, path string, pluginCfg *Plugin) (_ resmap.ResMap, err error) {
+// kustomizeBuild builds the manifests in the given directory using the
+// embedded version of Kustomize and applies the given patches to the result.
+// Helm charts are inflated using the given Helm command, unless it is empty.
+func kustomizeBuild(
+	fs filesys.FileSystem,
+	path string,
+	pluginCfg *Plugin,
+	patches []Patch,
+	helmCommand string,
+) (_ resmap.ResMap, err error) {
 	kustomizeRenderMutex.Lock()
 	defer kustomizeRenderMutex.Unlock()
 
@@ -162,8 +349,8 @@ func kustomizeBuild(fs filesys.FileSystem, path string, pluginCfg *Plugin) (_ re
 	// Helm plugin builtin requires explicit enabling. Kustomize itself ensures
 	// the further Helm files (e.g. cache, data) are stored in a temporary
 	// directory, AS LONG AS the global configuration is not set.
-	buildPluginCfg.HelmConfig.Enabled = true
-	buildPluginCfg.HelmConfig.Command = "helm"
+	buildPluginCfg.HelmConfig.Enabled = helmCommand != ""
+	buildPluginCfg.HelmConfig.Command = helmCommand
 
 	if pluginCfg != nil && pluginCfg.Helm != nil {
 		buildPluginCfg.HelmConfig.ApiVersions = pluginCfg.Helm.APIVersions
@@ -178,5 +365,155 @@ func kustomizeBuild(fs filesys.FileSystem, path string, pluginCfg *Plugin) (_ re
 	}
 
 	k := krusty.MakeKustomizer(buildOptions)
-	return k.Run(fs, path)
+	rm, err := k.Run(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	if err = applyKustomizePatches(rm, patches); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
+
+// kustomizeBuildWithBinary builds the manifests in the given directory,
+// relative to the given working directory, using the given Kustomize binary and
+// applies the given patches to the result. Helm charts are inflated using the
+// given Helm command, unless it is empty. Unlike kustomizeBuild, which confines
+// Kustomize to the working directory, this relies upon Kustomize's default
+// load restrictions, so files outside of the directory of a kustomization
+// cannot be loaded by it, although other kustomizations can. Kustomize is
+// killed if its output exceeds the given maximum size in bytes, unless that
+// is 0. Anything Kustomize writes to its standard error stream while
+// succeeding is returned as warnings.
+func kustomizeBuildWithBinary(
+	ctx context.Context,
+	bin string,
+	workDir string,
+	path string,
+	pluginCfg *Plugin,
+	patches []Patch,
+	helmCommand string,
+	maxOutputSize int64,
+) (resmap.ResMap, []string, error) {
+	absPath, err := securejoin.SecureJoin(workDir, path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error joining path %q: %w", path, err)
+	}
+	relPath, err := filepath.Rel(workDir, absPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error resolving path %q: %w", path, err)
+	}
+
+	args := []string{"build", relPath, "--load-restrictor", kustypes.LoadRestrictionsRootOnly.String()}
+	if helmCommand != "" {
+		args = append(args, "--enable-helm", "--helm-command", helmCommand)
+		if pluginCfg != nil && pluginCfg.Helm != nil {
+			for _, v := range pluginCfg.Helm.APIVersions {
+				args = append(args, "--helm-api-versions", v)
+			}
+			if pluginCfg.Helm.KubeVersion != "" {
+				args = append(args, "--helm-kube-version", pluginCfg.Helm.KubeVersion)
+			}
+		}
+	}
+
+	// Isolate Kustomize, and Helm run by it, from any configuration and caches
+	// in the home directory of the controller.
+	home, err := os.MkdirTemp("", "kustomize-build-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating temporary home directory: %w", err)
+	}
+	defer os.RemoveAll(home)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stdout := &limitedBuffer{limit: math.MaxInt, onExceeded: cancel}
+	if maxOutputSize > 0 {
+		stdout.limit = int(maxOutputSize)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = workDir
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = execWaitDelay
+	err = kustomize.Run(cmd)
+	if stdout.exceeded {
+		return nil, nil, &terminalError{err: fmt.Errorf(
+			"%w: kustomize build was aborted because its output exceeded the "+
+				"maximum rendered output size of %d bytes",
+			errRenderTooLarge, maxOutputSize,
+		)}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error running kustomize build: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	rm, err := resmap.NewFactory(provider.NewDefaultDepProvider().GetResourceFactory()).
+		NewResMapFromBytes(stdout.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing output of kustomize build: %w", err)
+	}
+	if err = applyKustomizePatches(rm, patches); err != nil {
+		return nil, nil, err
+	}
+	return rm, stderrWarnings(stderr.Bytes()), nil
+}
+
+// applyKustomizePatches applies the given patches to the given built manifests.
+// Patches applied this way, unlike patches defined by a Kustomization file, are
+// required to apply to at least one resource. As patches are typically
+// assembled using expressions, errors include the patch as it was rendered.
+// Because re-attempting to apply a patch cannot produce a different outcome,
+// all errors are terminal.
+func applyKustomizePatches(rm resmap.ResMap, patches []Patch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	helpers := resmap.NewPluginHelpers(
+		nil,
+		nil,
+		resmap.NewFactory(provider.NewDefaultDepProvider().GetResourceFactory()),
+		nil,
+	)
+	for i, patch := range patches {
+		if err := applyKustomizePatch(helpers, rm, patch); err != nil {
+			return &terminalError{err: fmt.Errorf(
+				"error applying patch %d: %w; rendered patch:\n%s", i, err, patch.Patch,
+			)}
+		}
+	}
+	return nil
+}
+
+func applyKustomizePatch(helpers *resmap.PluginHelpers, rm resmap.ResMap, patch Patch) error {
+	kusPatch := kustypes.Patch{Patch: patch.Patch}
+	if t := patch.Target; t != nil {
+		kusPatch.Target = &kustypes.Selector{
+			ResId: resid.ResId{
+				Gvk:       resid.Gvk{Group: t.Group, Version: t.Version, Kind: t.Kind},
+				Name:      t.Name,
+				Namespace: t.Namespace,
+			},
+			LabelSelector:      t.LabelSelector,
+			AnnotationSelector: t.AnnotationSelector,
+		}
+		selected, err := rm.Select(*kusPatch.Target)
+		if err != nil {
+			return fmt.Errorf("error selecting target resources: %w", err)
+		}
+		if len(selected) == 0 {
+			return errors.New("target does not match any resources")
+		}
+	}
+	patchCfg, err := yaml.Marshal(kusPatch)
+	if err != nil {
+		return err
+	}
+	transformer := kusbuiltins.NewPatchTransformerPlugin()
+	if err = transformer.Config(helpers, patchCfg); err != nil {
+		return err
+	}
+	return transformer.Transform(rm)
 }
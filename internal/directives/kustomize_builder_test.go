@@ -1,6 +1,7 @@
 package directives
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -11,16 +12,30 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/repo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	argocd "github.com/akuity/kargo/internal/controller/argocd/api/v1alpha1"
+	"github.com/akuity/kargo/internal/tools"
 )
 
 func Test_kustomizeBuilder_runPromotionStep(t *testing.T) {
+	succeeded := PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			toolVersionsOutputKey: map[string]any{string(tools.Kustomize): tools.EmbeddedKustomizeVersion},
+		},
+	}
+
 	tests := []struct {
-		name       string
-		setupFiles func(*testing.T, string)
-		config     KustomizeBuildConfig
-		assertions func(*testing.T, string, PromotionStepResult, error)
+		name                  string
+		setupFiles            func(*testing.T, string)
+		config                KustomizeBuildConfig
+		maxRenderedOutputSize int64
+		strictRender          bool
+		assertions            func(*testing.T, string, PromotionStepResult, error)
 	}{
 		{
 			name: "successful build",
@@ -44,7 +59,7 @@ metadata:
 			},
 			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, result)
+				assert.Equal(t, succeeded, result)
 
 				assert.FileExists(t, filepath.Join(dir, "output.yaml"))
 				b, err := os.ReadFile(filepath.Join(dir, "output.yaml"))
@@ -52,6 +67,41 @@ metadata:
 				assert.Contains(t, string(b), "test-deployment")
 			},
 		},
+		{
+			name: "successful build with file mode",
+			setupFiles: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`), 0o600))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`), 0o600))
+				// An existing output file is replaced
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "output.yaml"), []byte("stale"), 0o600))
+			},
+			config: KustomizeBuildConfig{
+				Path:        ".",
+				OutPath:     "output.yaml",
+				OutFileMode: "0644",
+			},
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, succeeded, result)
+
+				fi, err := os.Stat(filepath.Join(dir, "output.yaml"))
+				require.NoError(t, err)
+				assert.Equal(t, os.FileMode(0o644), fi.Mode().Perm())
+				b, err := os.ReadFile(filepath.Join(dir, "output.yaml"))
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "test-deployment")
+			},
+		},
 		{
 			name: "successful build with HelmChartInflationGenerator",
 			setupFiles: func(t *testing.T, dir string) {
@@ -95,7 +145,7 @@ replicaCount: 3`), 0o600))
 			},
 			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, result)
+				assert.Equal(t, succeeded, result)
 
 				assert.FileExists(t, filepath.Join(dir, "output.yaml"))
 				b, err := os.ReadFile(filepath.Join(dir, "output.yaml"))
@@ -131,7 +181,7 @@ metadata:
 			},
 			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, result)
+				assert.Equal(t, succeeded, result)
 
 				assert.DirExists(t, filepath.Join(dir, "output"))
 				b, err := os.ReadFile(filepath.Join(dir, "output", "deployment-test-deployment.yaml"))
@@ -147,7 +197,142 @@ metadata:
 				OutPath: "output.yaml",
 			},
 			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
-				require.ErrorContains(t, err, "no such file or directory")
+				require.ErrorContains(t, err, `MisconfiguredOverlay: "invalid/" is not a valid Kustomize overlay`)
+				require.ErrorContains(t, err, "directory does not exist")
+				assert.True(t, isTerminal(err))
+				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
+
+				assert.NoFileExists(t, filepath.Join(dir, "output.yaml"))
+			},
+		},
+		{
+			name: "successful build with patches",
+			setupFiles: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`), 0o600))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+spec:
+  replicas: 1
+`), 0o600))
+			},
+			config: KustomizeBuildConfig{
+				Path:    ".",
+				OutPath: "output.yaml",
+				Patches: []Patch{
+					{
+						Patch: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+  annotations:
+    kargo.akuity.io/promotion: test-promotion
+`,
+					},
+					{
+						Patch: `[{"op": "replace", "path": "/spec/replicas", "value": 3}]`,
+						Target: &Target{
+							Kind: "Deployment",
+							Name: "test-deployment",
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, succeeded, result)
+
+				b, err := os.ReadFile(filepath.Join(dir, "output.yaml"))
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "kargo.akuity.io/promotion: test-promotion")
+				assert.Contains(t, string(b), "replicas: 3")
+
+				// Patches are never written back to the source manifests
+				b, err = os.ReadFile(filepath.Join(dir, "deployment.yaml"))
+				require.NoError(t, err)
+				assert.NotContains(t, string(b), "kargo.akuity.io/promotion")
+				assert.Contains(t, string(b), "replicas: 1")
+			},
+		},
+		{
+			name: "patch target does not match any resources",
+			setupFiles: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`), 0o600))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`), 0o600))
+			},
+			config: KustomizeBuildConfig{
+				Path:    ".",
+				OutPath: "output.yaml",
+				Patches: []Patch{{
+					Patch: `[{"op": "add", "path": "/metadata/labels/promotion", "value": "test-promotion"}]`,
+					Target: &Target{
+						Kind: "Deployment",
+						Name: "other-deployment",
+					},
+				}},
+			},
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "error applying patch 0: target does not match any resources")
+				// The rendered patch is included in the error
+				require.ErrorContains(t, err, `"value": "test-promotion"`)
+				assert.True(t, isTerminal(err))
+				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
+
+				assert.NoFileExists(t, filepath.Join(dir, "output.yaml"))
+			},
+		},
+		{
+			name: "strategic merge patch does not match any resources",
+			setupFiles: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`), 0o600))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`), 0o600))
+			},
+			config: KustomizeBuildConfig{
+				Path:    ".",
+				OutPath: "output.yaml",
+				Patches: []Patch{{
+					Patch: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other-deployment
+  annotations:
+    kargo.akuity.io/promotion: test-promotion
+`,
+				}},
+			},
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "error applying patch 0")
+				require.ErrorContains(t, err, "name: other-deployment")
+				assert.True(t, isTerminal(err))
 				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
 
 				assert.NoFileExists(t, filepath.Join(dir, "output.yaml"))
@@ -166,6 +351,76 @@ metadata:
 				require.ErrorContains(t, err, "invalid Kustomization")
 				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, result)
 
+				assert.NoFileExists(t, filepath.Join(dir, "output.yaml"))
+			},
+		},
+		{
+			name: "rendered output too large",
+			setupFiles: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`), 0o600))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`), 0o600))
+			},
+			config: KustomizeBuildConfig{
+				Path:    ".",
+				OutPath: "output.yaml",
+			},
+			maxRenderedOutputSize: 16,
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "RenderTooLarge:")
+				require.True(t, isTerminal(err))
+				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, result)
+
+				assert.NoFileExists(t, filepath.Join(dir, "output.yaml"))
+			},
+		},
+		{
+			name: "successful build with deprecated fields",
+			setupFiles: func(t *testing.T, dir string) {
+				setupDeprecatedKustomization(t, dir)
+			},
+			config: KustomizeBuildConfig{
+				Path:    "overlay",
+				OutPath: "output.yaml",
+			},
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+				assert.Equal(t, []string{
+					"base/kustomization.yaml: Warning: 'commonLabels' is deprecated. " +
+						"Please use 'labels' instead. Run 'kustomize edit fix' to update your " +
+						"Kustomization automatically.",
+				}, result.RenderWarnings)
+
+				assert.FileExists(t, filepath.Join(dir, "output.yaml"))
+			},
+		},
+		{
+			name: "deprecated fields with strict rendering",
+			setupFiles: func(t *testing.T, dir string) {
+				setupDeprecatedKustomization(t, dir)
+			},
+			config: KustomizeBuildConfig{
+				Path:    "overlay",
+				OutPath: "output.yaml",
+			},
+			strictRender: true,
+			assertions: func(t *testing.T, dir string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "RenderWarnings:")
+				require.ErrorContains(t, err, "'commonLabels' is deprecated")
+				require.True(t, isTerminal(err))
+				assert.Equal(t, kargoapi.PromotionPhaseFailed, result.Status)
+				assert.Len(t, result.RenderWarnings, 1)
+
 				assert.NoFileExists(t, filepath.Join(dir, "output.yaml"))
 			},
 		},
@@ -180,11 +435,250 @@ metadata:
 			tt.setupFiles(t, tempDir)
 
 			stepCtx := &PromotionStepContext{
-				WorkDir: tempDir,
+				WorkDir:               tempDir,
+				MaxRenderedOutputSize: tt.maxRenderedOutputSize,
+				StrictRender:          tt.strictRender,
 			}
 
-			result, err := runner.runPromotionStep(stepCtx, tt.config)
+			result, err := runner.runPromotionStep(context.Background(), stepCtx, tt.config)
 			tt.assertions(t, tempDir, result, err)
 		})
 	}
 }
+
+func Test_kustomizeBuilder_runPromotionStep_pinnedVersions(t *testing.T) {
+	// A stand-in for Kustomize that records its arguments and prints a
+	// manifest, as the real one would.
+	cacheDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "args")
+	for tool, script := range map[string]string{
+		"kustomize/v5.4.3/kustomize": `#!/bin/sh
+echo "$@" > ` + argsFile + `
+cat <<MANIFEST
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+spec:
+  replicas: 1
+MANIFEST
+`,
+		"helm/v3.15.0/helm": "#!/bin/sh\n",
+	} {
+		p := filepath.Join(cacheDir, filepath.FromSlash(tool))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(script), 0o755))
+	}
+
+	workDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "overlay"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "overlay", "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+`), 0o600))
+
+	stepCtx := &PromotionStepContext{
+		WorkDir:      workDir,
+		ToolVersions: &kargoapi.ToolVersions{Kustomize: "v5.4.3", Helm: "v3.15.0"},
+		ToolCache:    tools.NewCache(cacheDir, nil),
+	}
+	result, err := (&kustomizeBuilder{}).runPromotionStep(
+		context.Background(),
+		stepCtx,
+		KustomizeBuildConfig{
+			Path:    "overlay",
+			OutPath: "output.yaml",
+			Plugin:  &Plugin{Helm: &Helm{KubeVersion: "1.31.0"}},
+			Patches: []Patch{{
+				Patch:  `[{"op": "replace", "path": "/spec/replicas", "value": 3}]`,
+				Target: &Target{Kind: "Deployment", Name: "test-deployment"},
+			}},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, PromotionStepResult{
+		Status: kargoapi.PromotionPhaseSucceeded,
+		Output: map[string]any{
+			toolVersionsOutputKey: map[string]any{"kustomize": "v5.4.3", "helm": "v3.15.0"},
+		},
+	}, result)
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		"build overlay --load-restrictor LoadRestrictionsRootOnly --enable-helm --helm-command "+
+			filepath.Join(cacheDir, "helm", "v3.15.0", "helm")+" --helm-kube-version 1.31.0\n",
+		string(args),
+	)
+	b, err := os.ReadFile(filepath.Join(workDir, "output.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "replicas: 3")
+
+	// Without a tool cache, the pinned version is refused
+	stepCtx.ToolCache = nil
+	_, err = (&kustomizeBuilder{}).runPromotionStep(
+		context.Background(),
+		stepCtx,
+		KustomizeBuildConfig{Path: "overlay", OutPath: "output.yaml"},
+	)
+	require.ErrorContains(t, err, "the embedded version is "+tools.EmbeddedKustomizeVersion)
+	require.True(t, isTerminal(err))
+
+	// Kustomize is killed once its output exceeds the maximum size
+	stepCtx.ToolCache = tools.NewCache(cacheDir, nil)
+	stepCtx.MaxRenderedOutputSize = 16
+	result, err = (&kustomizeBuilder{}).runPromotionStep(
+		context.Background(),
+		stepCtx,
+		KustomizeBuildConfig{Path: "overlay", OutPath: "too-large.yaml"},
+	)
+	require.ErrorContains(t, err, "RenderTooLarge:")
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, result.Status)
+	require.NoFileExists(t, filepath.Join(workDir, "too-large.yaml"))
+}
+
+func Test_kustomizeBuilder_runPromotionStep_normalize(t *testing.T) {
+	// testdata/kustomize-build/normalize holds two kustomizations of the same
+	// resources, which Kustomize outputs in different orders, and the golden
+	// output of building either of them with all normalization enabled.
+	testDir := filepath.Join("testdata", "kustomize-build", "normalize")
+	expected, err := os.ReadFile(filepath.Join(testDir, "expected.yaml"))
+	require.NoError(t, err)
+	normalize := &Normalize{SortDocuments: true, SortKeys: true, StripFields: true}
+
+	build := func(t *testing.T, path string, normalize *Normalize) []byte {
+		workDir := t.TempDir()
+		require.NoError(t, os.CopyFS(workDir, os.DirFS(testDir)))
+		runner := &kustomizeBuilder{}
+		result, err := runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{WorkDir: workDir},
+			KustomizeBuildConfig{Path: path, OutPath: "out.yaml", Normalize: normalize},
+		)
+		require.NoError(t, err)
+		require.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+		b, err := os.ReadFile(filepath.Join(workDir, "out.yaml"))
+		require.NoError(t, err)
+		return b
+	}
+
+	// Without normalization, the order of the resources in the output follows
+	// the order of the resources in the kustomization.
+	require.NotEqual(t, build(t, "overlay", nil), build(t, "reordered", nil))
+
+	// With normalization, building an unchanged overlay again produces a
+	// byte-identical file, regardless of the order of its resources.
+	first := build(t, "overlay", normalize)
+	require.Equal(t, string(expected), string(first))
+	require.Equal(t, first, build(t, "overlay", normalize))
+	require.Equal(t, first, build(t, "reordered", normalize))
+}
+
+func Test_kustomizeBuilder_runPromotionStep_app(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, argocd.AddToScheme(scheme))
+
+	setup := func(t *testing.T, directory *argocd.ApplicationSourceDirectory) (string, *PromotionStepContext) {
+		workDir := t.TempDir()
+		src := filepath.Join(workDir, "src")
+		require.NoError(t, os.MkdirAll(src, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`), 0o600))
+		// The working tree of the rendered branch
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, "out"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "out", ".git"), []byte("gitdir: fake"), 0o600))
+
+		return workDir, &PromotionStepContext{
+			WorkDir:        workDir,
+			RenderedBranch: "stage/dev",
+			ArgoCDClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&argocd.Application{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "argocd", Name: "fake-app"},
+					Spec: argocd.ApplicationSpec{
+						Source: &argocd.ApplicationSource{
+							RepoURL:        "https://github.com/example/repo",
+							Path:           "apps/dev",
+							TargetRevision: "stage/dev",
+							Directory:      directory,
+						},
+					},
+				},
+			).Build(),
+		}
+	}
+	runner := &kustomizeBuilder{}
+
+	t.Run("output at root of working tree is written to app path", func(t *testing.T) {
+		workDir, stepCtx := setup(t, nil)
+		result, err := runner.runPromotionStep(
+			context.Background(),
+			stepCtx,
+			KustomizeBuildConfig{
+				Path:    "src",
+				OutPath: "out",
+				App:     &KustomizeBuildApp{Name: "fake-app", Namespace: "argocd"},
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+		require.Nil(t, result.OutputIgnoredCondition)
+		require.FileExists(t, filepath.Join(workDir, "out", "apps", "dev", "deployment-test-deployment.yaml"))
+		require.NoFileExists(t, filepath.Join(workDir, "out", "deployment-test-deployment.yaml"))
+	})
+
+	t.Run("output hidden by exclude glob of recursing app", func(t *testing.T) {
+		workDir, stepCtx := setup(t, &argocd.ApplicationSourceDirectory{
+			Recurse: true,
+			Exclude: "rendered/*",
+		})
+		result, err := runner.runPromotionStep(
+			context.Background(),
+			stepCtx,
+			KustomizeBuildConfig{
+				Path:    "src",
+				OutPath: "out/apps/dev/rendered",
+				App:     &KustomizeBuildApp{Name: "fake-app", Namespace: "argocd"},
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+		// The manifests are written, but the Promotion is warned that Argo CD
+		// will not read them.
+		require.FileExists(t, filepath.Join(workDir, "out", "apps", "dev", "rendered", "deployment-test-deployment.yaml"))
+		cond := result.OutputIgnoredCondition
+		require.NotNil(t, cond)
+		require.Equal(t, kargoapi.ConditionTypeOutputIgnored, cond.Type)
+		require.Equal(t, metav1.ConditionTrue, cond.Status)
+		require.Contains(
+			t,
+			cond.Message,
+			`"out/apps/dev/rendered/deployment-test-deployment.yaml", because it is excluded by the pattern "rendered/*"`,
+		)
+	})
+
+	t.Run("output not in working tree", func(t *testing.T) {
+		_, stepCtx := setup(t, nil)
+		_, err := runner.runPromotionStep(
+			context.Background(),
+			stepCtx,
+			KustomizeBuildConfig{
+				Path:    "src",
+				OutPath: "elsewhere.yaml",
+				App:     &KustomizeBuildApp{Name: "fake-app", Namespace: "argocd"},
+			},
+		)
+		require.ErrorContains(t, err, "not within a working tree")
+	})
+}
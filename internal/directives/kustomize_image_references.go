@@ -0,0 +1,89 @@
+package directives
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+// renderedImageRefs returns the image references found in the manifests of
+// the provided ResMap. Every field named "image" is considered, which covers
+// the containers, init containers, and ephemeral containers of all core
+// workload kinds (including the Job templates of CronJobs), and, on a
+// best-effort basis, custom resources that follow the same conventions.
+func renderedImageRefs(rm resmap.ResMap) ([]string, error) {
+	var refs []string
+	for _, r := range rm.Resources() {
+		m, err := r.Map()
+		if err != nil {
+			return nil, fmt.Errorf("error reading rendered resource %s: %w", r.CurId(), err)
+		}
+		refs = appendImageRefs(refs, m)
+	}
+	return refs, nil
+}
+
+// appendImageRefs appends the string values of all fields named "image" found
+// anywhere within the provided value to the provided references.
+func appendImageRefs(refs []string, v any) []string {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if ref, ok := val.(string); ok && key == "image" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = appendImageRefs(refs, val)
+		}
+	case []any:
+		for _, val := range v {
+			refs = appendImageRefs(refs, val)
+		}
+	}
+	return refs
+}
+
+// unreferencedImages returns the sorted names of those of the provided
+// Kustomization images whose new reference does not occur among the provided
+// rendered image references. An image is considered referenced if a rendered
+// reference has its (new) name and, if it specifies one, its digest or,
+// failing that, its tag.
+func unreferencedImages(images []kustypes.Image, refs []string) []string {
+	var unreferenced []string
+	for _, img := range images {
+		if !slices.ContainsFunc(refs, func(ref string) bool {
+			return imageRefMatches(img, ref)
+		}) {
+			unreferenced = append(unreferenced, img.Name)
+		}
+	}
+	slices.Sort(unreferenced)
+	return unreferenced
+}
+
+// imageRefMatches returns whether the provided image reference is the one
+// that the provided Kustomization image resolves to.
+func imageRefMatches(img kustypes.Image, ref string) bool {
+	name, digest, _ := strings.Cut(ref, "@")
+	var tag string
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+	wantName := img.Name
+	if img.NewName != "" {
+		wantName = img.NewName
+	}
+	switch {
+	case name != wantName:
+		return false
+	case img.Digest != "":
+		return digest == img.Digest
+	case img.NewTag != "":
+		return tag == img.NewTag
+	default:
+		return true
+	}
+}
@@ -0,0 +1,128 @@
+package directives
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+func Test_renderedImageRefs(t *testing.T) {
+	rm, err := resmap.NewFactory(resource.NewFactory(nil)).NewResMapFromBytes([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.36
+      containers:
+      - name: app
+        image: example.com/app:v1.0.0
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: app
+    image: example.com/app@sha256:abc
+  ephemeralContainers:
+  - name: debugger
+    image: example.com/debugger:latest
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: job
+            image: example.com/job:v2.0.0
+---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+spec:
+  image: example.com/db:15
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`))
+	require.NoError(t, err)
+
+	refs, err := renderedImageRefs(rm)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"busybox:1.36",
+		"example.com/app:v1.0.0",
+		"example.com/app@sha256:abc",
+		"example.com/debugger:latest",
+		"example.com/job:v2.0.0",
+		"example.com/db:15",
+	}, refs)
+}
+
+func Test_unreferencedImages(t *testing.T) {
+	refs := []string{
+		"nginx:1.21.0",
+		"registry.example.com:5000/app:v1.0.0",
+		"example.com/renamed@sha256:abc",
+		"redis:6.2.5@sha256:def",
+	}
+
+	tests := []struct {
+		name     string
+		images   []kustypes.Image
+		expected []string
+	}{
+		{
+			name: "all images referenced",
+			images: []kustypes.Image{
+				{Name: "nginx", NewTag: "1.21.0"},
+				{Name: "registry.example.com:5000/app", NewTag: "v1.0.0"},
+				{Name: "app", NewName: "example.com/renamed", Digest: "sha256:abc"},
+				{Name: "redis", NewTag: "6.2.5", Digest: "sha256:def"},
+			},
+		},
+		{
+			name: "images not referenced",
+			images: []kustypes.Image{
+				{Name: "nginx", NewTag: "1.22.0"},
+				{Name: "registry.example.com:5000/app", Digest: "sha256:abc"},
+				{Name: "example.com/renamed", NewTag: "v1.0.0"},
+				{Name: "busybox", NewTag: "1.36"},
+			},
+			expected: []string{
+				"busybox",
+				"example.com/renamed",
+				"nginx",
+				"registry.example.com:5000/app",
+			},
+		},
+		{
+			name:   "image with only a new name",
+			images: []kustypes.Image{{Name: "app", NewName: "nginx"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, unreferencedImages(tt.images, refs))
+		})
+	}
+}
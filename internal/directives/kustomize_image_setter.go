@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
+	securefs "github.com/fluxcd/pkg/kustomize/filesys"
 	"github.com/xeipuuv/gojsonschema"
 	"sigs.k8s.io/kustomize/api/konfig"
 	kustypes "sigs.k8s.io/kustomize/api/types"
@@ -19,6 +21,8 @@ import (
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/controller/freight"
+	"github.com/akuity/kargo/internal/kargo"
+	"github.com/akuity/kargo/internal/logging"
 	intyaml "github.com/akuity/kargo/internal/yaml"
 )
 
@@ -26,11 +30,16 @@ import (
 // Kustomization image field.
 const preserveSeparator = "*"
 
+// imageNotReferencedReason prefixes the message of a kustomize-set-image step
+// that set images the rendered manifests do not reference.
+const imageNotReferencedReason = "ImageNotReferenced"
+
 func init() {
 	builtins.RegisterPromotionStepRunner(
 		newKustomizeImageSetter(),
 		&StepRunnerPermissions{
-			AllowKargoClient: true,
+			AllowKargoClient:   true,
+			AllowCredentialsDB: true,
 		},
 	)
 }
@@ -84,8 +93,9 @@ func (k *kustomizeImageSetter) runPromotionStep(
 	stepCtx *PromotionStepContext,
 	cfg KustomizeSetImageConfig,
 ) (PromotionStepResult, error) {
-	// Find the Kustomization file.
-	kusPath, err := findKustomization(stepCtx.WorkDir, cfg.Path)
+	// Find the Kustomization file, making sure up front that the overlay it
+	// belongs to is not misconfigured.
+	kusPath, err := validateKustomizeOverlay(stepCtx.WorkDir, cfg.Path)
 	if err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
 			fmt.Errorf("could not discover kustomization file: %w", err)
@@ -95,7 +105,12 @@ func (k *kustomizeImageSetter) runPromotionStep(
 	switch {
 	case len(cfg.Images) > 0:
 		// Discover image origins and collect target images.
-		targetImages, err = k.buildTargetImagesFromConfig(ctx, stepCtx, cfg.Images)
+		targetImages, err = k.buildTargetImagesFromConfig(
+			ctx,
+			stepCtx,
+			cfg.Images,
+			cfg.RejectConflictingImages,
+		)
 	default:
 		// Attempt to automatically set target images based on the Freight references.
 		targetImages, err = k.buildTargetImagesAutomatically(ctx, stepCtx)
@@ -104,24 +119,205 @@ func (k *kustomizeImageSetter) runPromotionStep(
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
-	// Update the Kustomization file with the new images.
-	if err = updateKustomizationFile(kusPath, targetImages); err != nil {
+	// Rewrite the target images according to the Stage's image mappings,
+	// remembering the original references so they can be reported.
+	originalRefs, err := mapTargetImages(stepCtx.ImageMappings, targetImages)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	digestPolicy := Clear
+	if cfg.ExistingDigestPolicy != nil {
+		digestPolicy = *cfg.ExistingDigestPolicy
+	}
+
+	// Remember which images are updated, as updating the Kustomization file
+	// adds the images it already contains to the target images.
+	updatedImages := slices.Sorted(maps.Keys(targetImages))
+
+	// Update the Kustomization file with the new images and any generator
+	// literals derived from them.
+	if err = updateKustomizationFile(
+		kusPath,
+		targetImages,
+		digestPolicy,
+		resolveGeneratorLiteralImages(cfg.Images, cfg.GeneratorLiterals),
+	); err != nil {
 		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
 	result := PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}
-	if commitMsg := k.generateCommitMessage(cfg.Path, targetImages); commitMsg != "" {
+
+	// Record the versions that the overlay retains of the images the Freight
+	// does not carry, so that the Promotion records all images of the Stage.
+	if result.RetainedImages, err = findRetainedImages(kusPath, stepCtx.RetainedImages); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Setting an image that the overlay does not reference at all changes
+	// nothing about the rendered manifests, which almost always means the step
+	// is misconfigured.
+	unreferenced, err := k.findUnreferencedImages(ctx, stepCtx, cfg.Path, kusPath, updatedImages)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+	if len(unreferenced) > 0 {
+		msg := fmt.Sprintf(
+			"%s: the manifests rendered from %q do not reference the new revision of "+
+				"image(s) %s",
+			imageNotReferencedReason, cfg.Path, strings.Join(unreferenced, ", "),
+		)
+		if cfg.UnreferencedImagePolicy == nil ||
+			*cfg.UnreferencedImagePolicy == UnreferencedImagePolicyFail {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+				&terminalError{err: errors.New(msg)}
+		}
+		result.Message = msg
+	}
+
+	if commitMsg := k.generateCommitMessage(
+		cfg.Path,
+		targetImages,
+		originalRefs,
+		stepCtx.CommitMessageMaxImages,
+	); commitMsg != "" {
 		result.Output = map[string]any{
 			"commitMessage": commitMsg,
 		}
 	}
+	if len(originalRefs) > 0 {
+		if result.Output == nil {
+			result.Output = map[string]any{}
+		}
+		mappedImages := make(map[string]any, len(originalRefs))
+		for name, originalRef := range originalRefs {
+			mappedImages[kustomizeImageRef(targetImages[name])] = originalRef
+		}
+		result.Output["mappedImages"] = mappedImages
+	}
 	return result, nil
 }
 
+// findUnreferencedImages renders the overlay at the provided path and returns
+// the sorted names of those of the provided images in the provided
+// Kustomization file whose new revision the rendered manifests do not
+// reference. Rendering uses the embedded version of Kustomize, without
+// inflating Helm charts, so if the overlay cannot be rendered this way, the
+// images are not verified and no names are returned.
+func (k *kustomizeImageSetter) findUnreferencedImages(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	path string,
+	kusPath string,
+	names []string,
+) ([]string, error) {
+	logger := logging.LoggerFromContext(ctx).WithValues("path", path)
+
+	fs, err := securefs.MakeFsOnDiskSecureBuild(stepCtx.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	restoreRemoteBases, err := fetchRemoteBases(ctx, stepCtx, path)
+	defer restoreRemoteBases()
+	if err != nil {
+		logger.Info(
+			"could not fetch remote bases; skipping verification of image references",
+			"error", err,
+		)
+		return nil, nil
+	}
+	rm, err := kustomizeBuild(fs, filepath.Join(stepCtx.WorkDir, path), nil, nil, "")
+	if err != nil {
+		logger.Info(
+			"could not render overlay; skipping verification of image references",
+			"error", err,
+		)
+		return nil, nil
+	}
+	refs, err := renderedImageRefs(rm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the images back from the Kustomization file, so that any values
+	// preserved from it are taken into account.
+	node, err := readKustomizationFile(kusPath)
+	if err != nil {
+		return nil, err
+	}
+	currentImages, err := getCurrentImages(node)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]kustypes.Image, 0, len(names))
+	for _, img := range currentImages {
+		if slices.Contains(names, img.Name) {
+			images = append(images, img)
+		}
+	}
+	return unreferencedImages(images, refs), nil
+}
+
+// mapTargetImages rewrites the provided target images in place according to
+// the provided ImageMappings. It returns the original reference of each image
+// that was rewritten, keyed by the name of the image in the Kustomization
+// file. Images whose name, tag, or digest is to be preserved from the
+// Kustomization file are not rewritten. A terminal error is returned if
+// different images would be rewritten to the same image.
+func mapTargetImages(
+	mappings []kargoapi.ImageMapping,
+	targetImages map[string]kustypes.Image,
+) (map[string]string, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(targetImages))
+	for name, img := range targetImages {
+		if img.NewName == preserveSeparator || img.NewTag == preserveSeparator ||
+			img.Digest == preserveSeparator {
+			continue
+		}
+		names = append(names, name)
+	}
+	// Map images in a stable order, so any error is reported consistently.
+	slices.Sort(names)
+	images := make([]kargoapi.Image, len(names))
+	for i, name := range names {
+		img := targetImages[name]
+		repoURL := img.Name
+		if img.NewName != "" {
+			repoURL = img.NewName
+		}
+		images[i] = kargoapi.Image{RepoURL: repoURL, Tag: img.NewTag, Digest: img.Digest}
+	}
+
+	mapped, err := kargo.MapImages(mappings, images)
+	if err != nil {
+		return nil, &terminalError{err: fmt.Errorf("error applying image mappings: %w", err)}
+	}
+
+	originalRefs := make(map[string]string)
+	for i, name := range names {
+		if mapped[i] == images[i] {
+			continue
+		}
+		img := targetImages[name]
+		originalRefs[name] = kustomizeImageRef(img)
+		if mapped[i].RepoURL != images[i].RepoURL || img.NewName != "" {
+			img.NewName = mapped[i].RepoURL
+		}
+		img.NewTag = mapped[i].Tag
+		targetImages[name] = img
+	}
+	return originalRefs, nil
+}
+
 func (k *kustomizeImageSetter) buildTargetImagesFromConfig(
 	ctx context.Context,
 	stepCtx *PromotionStepContext,
 	images []KustomizeSetImageConfigImage,
+	rejectConflicts bool,
 ) (map[string]kustypes.Image, error) {
 	targetImages := make(map[string]kustypes.Image, len(images))
 	for _, img := range images {
@@ -161,6 +357,21 @@ func (k *kustomizeImageSetter) buildTargetImagesFromConfig(
 				targetImage.Digest = discoveredImage.Digest
 			}
 		}
+		// The same image may be listed more than once, for instance when the list
+		// is assembled from several sources. Identical entries are harmless. Of
+		// conflicting ones, the last one wins unless conflicts are to be rejected.
+		if existing, ok := targetImages[targetImage.Name]; ok && existing != targetImage {
+			if rejectConflicts {
+				return nil, fmt.Errorf(
+					"image %q is specified more than once with conflicting values",
+					targetImage.Name,
+				)
+			}
+			logging.LoggerFromContext(ctx).Info(
+				"image is specified more than once with conflicting values; using the last one",
+				"image", targetImage.Name,
+			)
+		}
 		targetImages[targetImage.Name] = targetImage
 	}
 	return targetImages, nil
@@ -202,46 +413,45 @@ func (k *kustomizeImageSetter) buildTargetImagesAutomatically(
 	return images, nil
 }
 
-func (k *kustomizeImageSetter) generateCommitMessage(path string, images map[string]kustypes.Image) string {
-	if len(images) == 0 {
-		return ""
+func (k *kustomizeImageSetter) generateCommitMessage(
+	path string,
+	images map[string]kustypes.Image,
+	originalRefs map[string]string,
+	maxImages int,
+) string {
+	imageRefs := make([]string, 0, len(images))
+	for name, i := range images {
+		ref := kustomizeImageRef(i)
+		if originalRef, ok := originalRefs[name]; ok {
+			ref = fmt.Sprintf("%s (mapped from %s)", ref, originalRef)
+		}
+		imageRefs = append(imageRefs, ref)
 	}
+	return imageUpdateCommitMessage(path, imageRefs, maxImages)
+}
 
-	var commitMsg strings.Builder
-	_, _ = commitMsg.WriteString(fmt.Sprintf("Updated %s to use new image", path))
-	if len(images) > 1 {
-		_, _ = commitMsg.WriteString("s")
+// kustomizeImageRef returns the reference to the image that the provided
+// Kustomization image resolves to.
+func kustomizeImageRef(i kustypes.Image) string {
+	ref := i.Name
+	if i.NewName != "" {
+		ref = i.NewName
 	}
-	_, _ = commitMsg.WriteString("\n")
-
-	// Sort the images by name, in descending order for consistency.
-	imageNames := make([]string, 0, len(images))
-	for name := range images {
-		imageNames = append(imageNames, name)
+	if i.NewTag != "" {
+		ref = fmt.Sprintf("%s:%s", ref, i.NewTag)
 	}
-	slices.Sort(imageNames)
-
-	// Append each image to the commit message.
-	for _, name := range imageNames {
-		i := images[name]
-
-		ref := i.Name
-		if i.NewName != "" {
-			ref = i.NewName
-		}
-		if i.NewTag != "" {
-			ref = fmt.Sprintf("%s:%s", ref, i.NewTag)
-		}
-		if i.Digest != "" {
-			ref = fmt.Sprintf("%s@%s", ref, i.Digest)
-		}
-		_, _ = commitMsg.WriteString(fmt.Sprintf("\n- %s", ref))
+	if i.Digest != "" {
+		ref = fmt.Sprintf("%s@%s", ref, i.Digest)
 	}
-
-	return commitMsg.String()
+	return ref
 }
 
-func updateKustomizationFile(kusPath string, targetImages map[string]kustypes.Image) error {
+func updateKustomizationFile(
+	kusPath string,
+	targetImages map[string]kustypes.Image,
+	digestPolicy ExistingDigestPolicy,
+	literals []GeneratorLiteral,
+) error {
 	// Read the Kustomization file, and unmarshal it.
 	node, err := readKustomizationFile(kusPath)
 	if err != nil {
@@ -254,6 +464,14 @@ func updateKustomizationFile(kusPath string, targetImages map[string]kustypes.Im
 		return err
 	}
 
+	// Images that are pinned to a digest lose their pin when they are updated
+	// using only a tag. Unless that is acceptable, refuse to proceed.
+	if digestPolicy == Fail {
+		if err = checkDigestPins(currentImages, targetImages); err != nil {
+			return &terminalError{err: err}
+		}
+	}
+
 	// Merge existing images with new images.
 	newImages := mergeImages(currentImages, targetImages)
 
@@ -262,10 +480,131 @@ func updateKustomizationFile(kusPath string, targetImages map[string]kustypes.Im
 		return fmt.Errorf("could not update images field in Kustomization file: %w", err)
 	}
 
+	// Update generator literals to match the new images.
+	if err = setGeneratorLiterals(node, newImages, literals); err != nil {
+		return err
+	}
+
 	// Write the updated Kustomization file.
 	return writeKustomizationFile(kusPath, node)
 }
 
+// resolveGeneratorLiteralImages returns a copy of the provided generator
+// literals in which images are referred to by the names by which they are
+// known in the Kustomization file, in case those differ from the names of the
+// images Kargo is subscribed to.
+func resolveGeneratorLiteralImages(
+	images []KustomizeSetImageConfigImage,
+	literals []GeneratorLiteral,
+) []GeneratorLiteral {
+	resolved := slices.Clone(literals)
+	for i, lit := range resolved {
+		for _, img := range images {
+			if img.Image == lit.Image && img.Name != "" {
+				resolved[i].Image = img.Name
+				break
+			}
+		}
+	}
+	return resolved
+}
+
+// setGeneratorLiterals sets each of the provided literals of ConfigMap and
+// Secret generators in the provided Kustomization to the tag or, if it has
+// none, the digest of the corresponding image. Literals are edited in place,
+// so other literals and comments are preserved. A terminal error is returned
+// if any generator, literal, or image cannot be found.
+func setGeneratorLiterals(
+	node *yaml.Node,
+	images []kustypes.Image,
+	literals []GeneratorLiteral,
+) error {
+	var errs []error
+	for _, lit := range literals {
+		if err := setGeneratorLiteral(node, images, lit); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &terminalError{err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+func setGeneratorLiteral(
+	node *yaml.Node,
+	images []kustypes.Image,
+	lit GeneratorLiteral,
+) error {
+	kind := ConfigMap
+	if lit.Kind != nil {
+		kind = *lit.Kind
+	}
+	field := "configMapGenerator"
+	if kind == Secret {
+		field = "secretGenerator"
+	}
+
+	i := slices.IndexFunc(images, func(img kustypes.Image) bool {
+		return img.Name == lit.Image
+	})
+	if i == -1 {
+		return fmt.Errorf(
+			"cannot set literal %q of %s generator %q: image %q is not in the "+
+				"Kustomization file",
+			lit.Key, kind, lit.Name, lit.Image,
+		)
+	}
+	value := images[i].NewTag
+	if value == "" {
+		value = images[i].Digest
+	}
+	if value == "" {
+		return fmt.Errorf(
+			"cannot set literal %q of %s generator %q: image %q has neither a tag "+
+				"nor a digest",
+			lit.Key, kind, lit.Name, lit.Image,
+		)
+	}
+
+	var generators []struct {
+		Name     string   `yaml:"name"`
+		Literals []string `yaml:"literals"`
+	}
+	if err := intyaml.DecodeField(node, field, &generators); err != nil {
+		var fieldErr intyaml.FieldNotFoundErr
+		if !errors.As(err, &fieldErr) {
+			return fmt.Errorf("could not decode %s field in Kustomization file: %w", field, err)
+		}
+	}
+	for j, generator := range generators {
+		if generator.Name != lit.Name {
+			continue
+		}
+		for k, literal := range generator.Literals {
+			if key, _, _ := strings.Cut(literal, "="); key != lit.Key {
+				continue
+			}
+			if err := intyaml.UpdateField(
+				node,
+				fmt.Sprintf("%s.%d.literals.%d", field, j, k),
+				lit.Key+"="+value,
+			); err != nil {
+				return fmt.Errorf(
+					"could not update literal %q of %s generator %q in Kustomization file: %w",
+					lit.Key, kind, lit.Name, err,
+				)
+			}
+			return nil
+		}
+		return fmt.Errorf(
+			"%s generator %q in Kustomization file has no literal with key %q",
+			kind, lit.Name, lit.Key,
+		)
+	}
+	return fmt.Errorf("Kustomization file has no %s generator named %q", kind, lit.Name)
+}
+
 func readKustomizationFile(kusPath string) (*yaml.Node, error) {
 	b, err := os.ReadFile(kusPath)
 	if err != nil {
@@ -289,6 +628,68 @@ func getCurrentImages(node *yaml.Node) ([]kustypes.Image, error) {
 	return currentImages, nil
 }
 
+// findRetainedImages returns the versions of the provided images that the
+// images field of the provided Kustomization file specifies. Images that the
+// Kustomization file does not specify a version of are omitted.
+func findRetainedImages(
+	kusPath string,
+	retained []kargoapi.RetainedImage,
+) ([]kargoapi.RetainedImage, error) {
+	if len(retained) == 0 {
+		return nil, nil
+	}
+	node, err := readKustomizationFile(kusPath)
+	if err != nil {
+		return nil, err
+	}
+	currentImages, err := getCurrentImages(node)
+	if err != nil {
+		return nil, err
+	}
+	var found []kargoapi.RetainedImage
+	for _, image := range retained {
+		i := slices.IndexFunc(currentImages, func(img kustypes.Image) bool {
+			return img.Name == image.RepoURL
+		})
+		if i < 0 {
+			continue
+		}
+		current := currentImages[i]
+		if current.NewTag == "" && current.Digest == "" {
+			continue
+		}
+		found = append(found, kargoapi.RetainedImage{
+			RepoURL: image.RepoURL,
+			Tag:     current.NewTag,
+			Digest:  current.Digest,
+		})
+	}
+	return found, nil
+}
+
+// checkDigestPins returns an error if any of the provided current images is
+// pinned to a digest while the corresponding target image specifies only a tag.
+func checkDigestPins(currentImages []kustypes.Image, targetImages map[string]kustypes.Image) error {
+	var errs []error
+	for _, img := range currentImages {
+		if img.Digest == "" || img.Digest == preserveSeparator {
+			continue
+		}
+		targetImg, ok := targetImages[img.Name]
+		if !ok || targetImg.Digest != "" || targetImg.NewTag == "" ||
+			targetImg.NewTag == preserveSeparator {
+			continue
+		}
+		errs = append(errs, fmt.Errorf(
+			"image %q is pinned to digest %q in the Kustomization file, but only "+
+				"tag %q was provided: specify a digest for the image, or set "+
+				"existingDigestPolicy to %q to remove the pin",
+			img.Name, img.Digest, targetImg.NewTag, Clear,
+		))
+	}
+	return errors.Join(errs...)
+}
+
 func mergeImages(currentImages []kustypes.Image, targetImages map[string]kustypes.Image) []kustypes.Image {
 	for _, img := range currentImages {
 		if targetImg, ok := targetImages[img.Name]; ok {
@@ -2,7 +2,9 @@ package directives
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	kustypes "sigs.k8s.io/kustomize/api/types"
 	yaml "sigs.k8s.io/yaml/goyaml.v3"
@@ -40,6 +43,15 @@ func Test_kustomizeImageSetter_validate(t *testing.T) {
 				"path: String length must be greater than or equal to 1",
 			},
 		},
+		{
+			name: "existingDigestPolicy is invalid",
+			config: Config{
+				"existingDigestPolicy": "keep",
+			},
+			expectedProblems: []string{
+				"existingDigestPolicy: existingDigestPolicy must be one of the following",
+			},
+		},
 		{
 			name: "image not specified",
 			config: Config{
@@ -190,9 +202,12 @@ func Test_kustomizeImageSetter_runPromotionStep(t *testing.T) {
 				tempDir := t.TempDir()
 				kustomizationContent := `apiVersion: kustomize.config.k8s.io/v1beta1
 kind: Kustomization
+resources:
+- deployment.yaml
 `
 				err := os.WriteFile(filepath.Join(tempDir, "kustomization.yaml"), []byte(kustomizationContent), 0o600)
 				require.NoError(t, err)
+				writeTestDeployment(t, tempDir, "nginx")
 				return tempDir
 			},
 			cfg: KustomizeSetImageConfig{
@@ -243,15 +258,92 @@ kind: Kustomization
 				assert.Contains(t, string(b), "newTag: 1.21.0")
 			},
 		},
+		{
+			name: "applies image mappings",
+			setupFiles: func(t *testing.T) string {
+				tempDir := t.TempDir()
+				kustomizationContent := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`
+				err := os.WriteFile(filepath.Join(tempDir, "kustomization.yaml"), []byte(kustomizationContent), 0o600)
+				require.NoError(t, err)
+				writeTestDeployment(t, tempDir, "registry.dev.example.com/app")
+				return tempDir
+			},
+			cfg: KustomizeSetImageConfig{
+				Path: ".",
+				Images: []KustomizeSetImageConfigImage{
+					{Image: "registry.dev.example.com/app"},
+				},
+			},
+			setupStepCtx: func(t *testing.T, workDir string) *PromotionStepContext {
+				scheme := runtime.NewScheme()
+				require.NoError(t, kargoapi.AddToScheme(scheme))
+				c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					mockWarehouse(testNamespace, "warehouse1", kargoapi.WarehouseSpec{
+						Subscriptions: []kargoapi.RepoSubscription{
+							{Image: &kargoapi.ImageSubscription{RepoURL: "registry.dev.example.com/app"}},
+						},
+					}),
+				).Build()
+
+				return &PromotionStepContext{
+					WorkDir:     workDir,
+					KargoClient: c,
+					Project:     testNamespace,
+					FreightRequests: []kargoapi.FreightRequest{
+						{Origin: kargoapi.FreightOrigin{Name: "warehouse1", Kind: "Warehouse"}},
+					},
+					Freight: kargoapi.FreightCollection{
+						Freight: map[string]kargoapi.FreightReference{
+							"Warehouse/warehouse1": {
+								Origin: kargoapi.FreightOrigin{Kind: "Warehouse", Name: "warehouse1"},
+								Images: []kargoapi.Image{{RepoURL: "registry.dev.example.com/app", Tag: "1.21.0"}},
+							},
+						},
+					},
+					ImageMappings: []kargoapi.ImageMapping{{
+						RepoURL:    "registry.dev.example.com/*",
+						NewRepoURL: "registry.prod.example.com/*",
+						TagSuffix:  "-prod",
+					}},
+				}
+			},
+			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, PromotionStepResult{
+					Status: kargoapi.PromotionPhaseSucceeded,
+					Output: map[string]any{
+						"commitMessage": "Updated . to use new image\n\n" +
+							"- registry.prod.example.com/app:1.21.0-prod " +
+							"(mapped from registry.dev.example.com/app:1.21.0)",
+						"mappedImages": map[string]any{
+							"registry.prod.example.com/app:1.21.0-prod": "registry.dev.example.com/app:1.21.0",
+						},
+					},
+				}, result)
+
+				b, err := os.ReadFile(filepath.Join(workDir, "kustomization.yaml"))
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "name: registry.dev.example.com/app")
+				assert.Contains(t, string(b), "newName: registry.prod.example.com/app")
+				assert.Contains(t, string(b), "newTag: 1.21.0-prod")
+			},
+		},
 		{
 			name: "automatically sets image",
 			setupFiles: func(t *testing.T) string {
 				tempDir := t.TempDir()
 				kustomizationContent := `apiVersion: kustomize.config.k8s.io/v1beta1
 kind: Kustomization
+resources:
+- deployment.yaml
 `
 				err := os.WriteFile(filepath.Join(tempDir, "kustomization.yaml"), []byte(kustomizationContent), 0o600)
 				require.NoError(t, err)
+				writeTestDeployment(t, tempDir, "nginx", "redis")
 				return tempDir
 			},
 			cfg: KustomizeSetImageConfig{
@@ -288,6 +380,77 @@ kind: Kustomization
 				assert.Contains(t, string(b), "digest: sha256:123")
 			},
 		},
+		{
+			name: "image not referenced",
+			setupFiles: func(t *testing.T) string {
+				tempDir := t.TempDir()
+				kustomizationContent := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`
+				err := os.WriteFile(filepath.Join(tempDir, "kustomization.yaml"), []byte(kustomizationContent), 0o600)
+				require.NoError(t, err)
+				writeTestDeployment(t, tempDir, "nginx")
+				return tempDir
+			},
+			cfg: KustomizeSetImageConfig{
+				Path: ".",
+				Images: []KustomizeSetImageConfigImage{
+					{Image: "nginx", Tag: "1.21.0"},
+					{Image: "redis", Tag: "6.2.5"},
+				},
+			},
+			setupStepCtx: func(_ *testing.T, workDir string) *PromotionStepContext {
+				return &PromotionStepContext{WorkDir: workDir}
+			},
+			assertions: func(t *testing.T, _ string, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "ImageNotReferenced:")
+				require.ErrorContains(t, err, "image(s) redis")
+				require.True(t, isTerminal(err))
+				assert.Equal(t, PromotionStepResult{Status: kargoapi.PromotionPhaseFailed}, result)
+			},
+		},
+		{
+			name: "image not referenced under warn policy",
+			setupFiles: func(t *testing.T) string {
+				tempDir := t.TempDir()
+				kustomizationContent := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`
+				err := os.WriteFile(filepath.Join(tempDir, "kustomization.yaml"), []byte(kustomizationContent), 0o600)
+				require.NoError(t, err)
+				writeTestDeployment(t, tempDir, "nginx")
+				return tempDir
+			},
+			cfg: KustomizeSetImageConfig{
+				Path: ".",
+				Images: []KustomizeSetImageConfigImage{
+					{Image: "redis", Tag: "6.2.5"},
+				},
+				UnreferencedImagePolicy: ptr.To(Warn),
+			},
+			setupStepCtx: func(_ *testing.T, workDir string) *PromotionStepContext {
+				return &PromotionStepContext{WorkDir: workDir}
+			},
+			assertions: func(t *testing.T, workDir string, result PromotionStepResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, PromotionStepResult{
+					Status: kargoapi.PromotionPhaseSucceeded,
+					Message: `ImageNotReferenced: the manifests rendered from "." do not reference ` +
+						"the new revision of image(s) redis",
+					Output: map[string]any{
+						"commitMessage": "Updated . to use new image\n\n- redis:6.2.5",
+					},
+				}, result)
+
+				b, err := os.ReadFile(filepath.Join(workDir, "kustomization.yaml"))
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "newTag: 6.2.5")
+			},
+		},
 		{
 			name: "Kustomization file not found",
 			setupFiles: func(t *testing.T) string {
@@ -370,12 +533,33 @@ images:
 	}
 }
 
+// writeTestDeployment writes a Deployment with a container for each of the
+// provided images to a deployment.yaml file in the provided directory.
+func writeTestDeployment(t *testing.T, dir string, images ...string) {
+	t.Helper()
+	var containers strings.Builder
+	for _, image := range images {
+		fmt.Fprintf(&containers, "      - name: %s\n        image: %s\n", path.Base(image), image)
+	}
+	err := os.WriteFile(filepath.Join(dir, "deployment.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+`+containers.String()), 0o600)
+	require.NoError(t, err)
+}
+
 func Test_kustomizeImageSetter_buildTargetImages(t *testing.T) {
 	const testNamespace = "test-project"
 
 	tests := []struct {
 		name              string
 		images            []KustomizeSetImageConfigImage
+		rejectConflicts   bool
 		freightRequests   []kargoapi.FreightRequest
 		objects           []runtime.Object
 		freightReferences map[string]kargoapi.FreightReference
@@ -401,6 +585,43 @@ func Test_kustomizeImageSetter_buildTargetImages(t *testing.T) {
 				}, result)
 			},
 		},
+		{
+			name: "identical duplicates",
+			images: []KustomizeSetImageConfigImage{
+				{Image: "nginx", Tag: "fake-tag"},
+				{Image: "nginx", Tag: "fake-tag"},
+			},
+			assertions: func(t *testing.T, result map[string]kustypes.Image, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, map[string]kustypes.Image{
+					"nginx": {Name: "nginx", NewTag: "fake-tag"},
+				}, result)
+			},
+		},
+		{
+			name: "conflicting duplicates with last one winning",
+			images: []KustomizeSetImageConfigImage{
+				{Image: "nginx", Tag: "fake-tag"},
+				{Image: "nginx", Tag: "other-fake-tag"},
+			},
+			assertions: func(t *testing.T, result map[string]kustypes.Image, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, map[string]kustypes.Image{
+					"nginx": {Name: "nginx", NewTag: "other-fake-tag"},
+				}, result)
+			},
+		},
+		{
+			name: "conflicting duplicates rejected",
+			images: []KustomizeSetImageConfigImage{
+				{Image: "nginx", Tag: "fake-tag"},
+				{Image: "nginx", Tag: "other-fake-tag"},
+			},
+			rejectConflicts: true,
+			assertions: func(t *testing.T, _ map[string]kustypes.Image, err error) {
+				require.ErrorContains(t, err, `image "nginx" is specified more than once`)
+			},
+		},
 		{
 			name: "discovers origins and builds target images",
 			images: []KustomizeSetImageConfigImage{
@@ -584,7 +805,12 @@ func Test_kustomizeImageSetter_buildTargetImages(t *testing.T) {
 				},
 			}
 
-			result, err := runner.buildTargetImagesFromConfig(context.Background(), stepCtx, tt.images)
+			result, err := runner.buildTargetImagesFromConfig(
+				context.Background(),
+				stepCtx,
+				tt.images,
+				tt.rejectConflicts,
+			)
 			tt.assertions(t, result, err)
 		})
 	}
@@ -711,10 +937,11 @@ func Test_kustomizeImageSetter_buildTargetImagesAutomatically(t *testing.T) {
 
 func Test_kustomizeImageSetter_generateCommitMessage(t *testing.T) {
 	tests := []struct {
-		name       string
-		path       string
-		images     map[string]kustypes.Image
-		assertions func(*testing.T, string)
+		name         string
+		path         string
+		images       map[string]kustypes.Image
+		originalRefs map[string]string
+		assertions   func(*testing.T, string)
 	}{
 		{
 			name:   "empty images",
@@ -790,23 +1017,117 @@ func Test_kustomizeImageSetter_generateCommitMessage(t *testing.T) {
 				assert.Equal(t, 4, strings.Count(got, "\n"))
 			},
 		},
+		{
+			name: "mapped image update",
+			path: "path/to/kustomization",
+			images: map[string]kustypes.Image{
+				"image1": {Name: "dev.example.com/app", NewName: "prod.example.com/app", NewTag: "1.0-prod"},
+				"image2": {Name: "nginx", NewTag: "1.19"},
+			},
+			originalRefs: map[string]string{
+				"image1": "dev.example.com/app:1.0",
+			},
+			assertions: func(t *testing.T, got string) {
+				assert.Contains(t, got, "- prod.example.com/app:1.0-prod (mapped from dev.example.com/app:1.0)")
+				assert.Contains(t, got, "- nginx:1.19\n")
+				assert.Equal(t, 3, strings.Count(got, "\n"))
+			},
+		},
 	}
 
 	runner := &kustomizeImageSetter{}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := runner.generateCommitMessage(tt.path, tt.images)
+			got := runner.generateCommitMessage(tt.path, tt.images, tt.originalRefs, 0)
 			tt.assertions(t, got)
 		})
 	}
 }
 
+func Test_mapTargetImages(t *testing.T) {
+	mappings := []kargoapi.ImageMapping{{
+		RepoURL:    "registry.dev.example.com/*",
+		NewRepoURL: "registry.prod.example.com/*",
+		TagSuffix:  "-prod",
+	}}
+
+	t.Run("no mappings", func(t *testing.T) {
+		targetImages := map[string]kustypes.Image{
+			"registry.dev.example.com/app": {Name: "registry.dev.example.com/app", NewTag: "1.0"},
+		}
+		originalRefs, err := mapTargetImages(nil, targetImages)
+		require.NoError(t, err)
+		assert.Empty(t, originalRefs)
+		assert.Equal(t, "1.0", targetImages["registry.dev.example.com/app"].NewTag)
+	})
+
+	t.Run("maps images", func(t *testing.T) {
+		targetImages := map[string]kustypes.Image{
+			"registry.dev.example.com/app": {
+				Name:   "registry.dev.example.com/app",
+				NewTag: "1.0",
+				Digest: "sha256:123",
+			},
+			"app":   {Name: "app", NewName: "registry.dev.example.com/other", NewTag: "2.0"},
+			"nginx": {Name: "nginx", NewTag: "1.27"},
+			"registry.dev.example.com/kept": {
+				Name:   "registry.dev.example.com/kept",
+				NewTag: preserveSeparator,
+			},
+		}
+		originalRefs, err := mapTargetImages(mappings, targetImages)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"registry.dev.example.com/app": "registry.dev.example.com/app:1.0@sha256:123",
+			"app":                          "registry.dev.example.com/other:2.0",
+		}, originalRefs)
+		assert.Equal(t, map[string]kustypes.Image{
+			"registry.dev.example.com/app": {
+				Name:    "registry.dev.example.com/app",
+				NewName: "registry.prod.example.com/app",
+				NewTag:  "1.0-prod",
+				Digest:  "sha256:123",
+			},
+			"app":   {Name: "app", NewName: "registry.prod.example.com/other", NewTag: "2.0-prod"},
+			"nginx": {Name: "nginx", NewTag: "1.27"},
+			"registry.dev.example.com/kept": {
+				Name:   "registry.dev.example.com/kept",
+				NewTag: preserveSeparator,
+			},
+		}, targetImages)
+	})
+
+	t.Run("different images mapped to the same image", func(t *testing.T) {
+		targetImages := map[string]kustypes.Image{
+			"registry.dev.example.com/app":  {Name: "registry.dev.example.com/app", NewTag: "1.0"},
+			"registry.prod.example.com/app": {Name: "registry.prod.example.com/app", NewTag: "1.0-prod"},
+		}
+		_, err := mapTargetImages(mappings, targetImages)
+		require.ErrorContains(t, err, "would both be mapped to registry.prod.example.com/app:1.0-prod")
+		assert.True(t, isTerminal(err))
+	})
+}
+
 func Test_updateKustomizationFile(t *testing.T) {
+	// readImages reads the images back from the Kustomization file at the
+	// provided path.
+	readImages := func(t *testing.T, kusPath string) []kustypes.Image {
+		b, err := os.ReadFile(kusPath)
+		require.NoError(t, err)
+		var node yaml.Node
+		require.NoError(t, yaml.Unmarshal(b, &node))
+		images, err := getCurrentImages(&node)
+		require.NoError(t, err)
+		return images
+	}
+
 	tests := []struct {
 		name         string
 		initialYAML  string
 		targetImages map[string]kustypes.Image
+		digestPolicy ExistingDigestPolicy
+		literals     []GeneratorLiteral
 		assertions   func(*testing.T, string, error)
 	}{
 		{
@@ -862,6 +1183,299 @@ kind: Kustomization
 				assert.Equal(t, "1.21.0", images[0].NewTag)
 			},
 		},
+		{
+			name: "tag pin updated with tag under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []kustypes.Image{
+					{Name: "nginx", NewTag: "1.21.0"},
+				}, readImages(t, kusPath))
+			},
+		},
+		{
+			name: "digest pin cleared when updated with tag under clear policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+			},
+			digestPolicy: Clear,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []kustypes.Image{
+					{Name: "nginx", NewTag: "1.21.0"},
+				}, readImages(t, kusPath))
+				b, err := os.ReadFile(kusPath)
+				require.NoError(t, err)
+				assert.NotContains(t, string(b), "digest")
+			},
+		},
+		{
+			name: "digest pin updated with tag under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.ErrorContains(
+					t, err,
+					`image "nginx" is pinned to digest "sha256:0123456789abcdef" in the `+
+						`Kustomization file, but only tag "1.21.0" was provided`,
+				)
+				require.True(t, isTerminal(err))
+				// The file is left untouched
+				assert.Equal(t, []kustypes.Image{
+					{Name: "nginx", Digest: "sha256:0123456789abcdef"},
+				}, readImages(t, kusPath))
+			},
+		},
+		{
+			name: "tag and digest pin updated with tag under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.ErrorContains(t, err, `image "nginx" is pinned to digest`)
+			},
+		},
+		{
+			name: "renamed digest pin updated with tag under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newName: registry.example.com/nginx
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {
+					Name:    "nginx",
+					NewName: "registry.example.com/nginx",
+					NewTag:  "1.21.0",
+				},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.ErrorContains(t, err, `image "nginx" is pinned to digest`)
+			},
+		},
+		{
+			name: "digest pin updated with digest under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0", Digest: "sha256:fedcba9876543210"},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []kustypes.Image{
+					{Name: "nginx", NewTag: "1.21.0", Digest: "sha256:fedcba9876543210"},
+				}, readImages(t, kusPath))
+			},
+		},
+		{
+			name: "digest pin preserved under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0", Digest: preserveSeparator},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []kustypes.Image{
+					{Name: "nginx", NewTag: "1.21.0", Digest: "sha256:0123456789abcdef"},
+				}, readImages(t, kusPath))
+			},
+		},
+		{
+			name: "digest pin of other image under fail policy",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+- name: redis
+  digest: sha256:0123456789abcdef
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+			},
+			digestPolicy: Fail,
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []kustypes.Image{
+					{Name: "nginx", NewTag: "1.21.0"},
+					{Name: "redis", Digest: "sha256:0123456789abcdef"},
+				}, readImages(t, kusPath))
+			},
+		},
+		{
+			name: "update generator literals",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+- name: redis
+  newTag: 6.0.0
+configMapGenerator:
+- name: other
+  literals:
+  - NGINX_VERSION=unchanged
+- name: versions
+  literals:
+  # The version of nginx in use
+  - NGINX_VERSION=1.19.0
+  - LOG_LEVEL=info
+secretGenerator:
+- name: secrets
+  literals:
+  - REDIS_VERSION=6.0.0
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+				"redis": {Name: "redis", NewTag: "7.0.0"},
+			},
+			literals: []GeneratorLiteral{
+				{Image: "nginx", Name: "versions", Key: "NGINX_VERSION"},
+				{
+					Image: "redis",
+					Kind:  ptr.To(Secret),
+					Name:  "secrets",
+					Key:   "REDIS_VERSION",
+				},
+			},
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				b, err := os.ReadFile(kusPath)
+				require.NoError(t, err)
+				assert.Equal(t, `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.21.0
+- name: redis
+  newTag: 7.0.0
+configMapGenerator:
+- name: other
+  literals:
+  - NGINX_VERSION=unchanged
+- name: versions
+  literals:
+  # The version of nginx in use
+  - NGINX_VERSION=1.21.0
+  - LOG_LEVEL=info
+secretGenerator:
+- name: secrets
+  literals:
+  - REDIS_VERSION=7.0.0
+`, string(b))
+			},
+		},
+		{
+			name: "generator literal set to digest",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+configMapGenerator:
+- name: versions
+  literals:
+  - NGINX_DIGEST=
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", Digest: "sha256:0123456789abcdef"},
+			},
+			literals: []GeneratorLiteral{
+				{Image: "nginx", Name: "versions", Key: "NGINX_DIGEST"},
+			},
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.NoError(t, err)
+				b, err := os.ReadFile(kusPath)
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "- NGINX_DIGEST=sha256:0123456789abcdef\n")
+			},
+		},
+		{
+			name: "generator literal errors",
+			initialYAML: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: nginx
+  newTag: 1.19.0
+configMapGenerator:
+- name: versions
+  literals:
+  - NGINX_VERSION=1.19.0
+`,
+			targetImages: map[string]kustypes.Image{
+				"nginx": {Name: "nginx", NewTag: "1.21.0"},
+			},
+			literals: []GeneratorLiteral{
+				{Image: "nginx", Name: "missing", Key: "NGINX_VERSION"},
+				{Image: "nginx", Name: "versions", Key: "MISSING"},
+				{
+					Image: "nginx",
+					Kind:  ptr.To(Secret),
+					Name:  "versions",
+					Key:   "NGINX_VERSION",
+				},
+				{Image: "redis", Name: "versions", Key: "NGINX_VERSION"},
+			},
+			assertions: func(t *testing.T, kusPath string, err error) {
+				require.Error(t, err)
+				assert.True(t, isTerminal(err))
+				assert.ErrorContains(t, err, `Kustomization file has no ConfigMap generator named "missing"`)
+				assert.ErrorContains(
+					t, err, `ConfigMap generator "versions" in Kustomization file has no literal with key "MISSING"`,
+				)
+				assert.ErrorContains(t, err, `Kustomization file has no Secret generator named "versions"`)
+				assert.ErrorContains(t, err, `image "redis" is not in the Kustomization file`)
+
+				// Nothing is written
+				b, readErr := os.ReadFile(kusPath)
+				require.NoError(t, readErr)
+				assert.Contains(t, string(b), "newTag: 1.19.0")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -872,7 +1486,7 @@ kind: Kustomization
 			err := os.WriteFile(kusPath, []byte(tt.initialYAML), 0o600)
 			require.NoError(t, err)
 
-			err = updateKustomizationFile(kusPath, tt.targetImages)
+			err = updateKustomizationFile(kusPath, tt.targetImages, tt.digestPolicy, tt.literals)
 			tt.assertions(t, kusPath, err)
 		})
 	}
@@ -971,6 +1585,34 @@ kind: Kustomization
 	}
 }
 
+func Test_findRetainedImages(t *testing.T) {
+	kusPath := filepath.Join(t.TempDir(), "kustomization.yaml")
+	require.NoError(t, os.WriteFile(kusPath, []byte(`images:
+- name: example.com/backend
+  newTag: v1.2.3
+- name: example.com/worker
+  digest: sha256:abc
+- name: example.com/sidecar
+  newName: example.com/other
+`), 0o600))
+
+	retained, err := findRetainedImages(kusPath, []kargoapi.RetainedImage{
+		{RepoURL: "example.com/backend"},
+		{RepoURL: "example.com/worker"},
+		{RepoURL: "example.com/sidecar"},
+		{RepoURL: "example.com/missing"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []kargoapi.RetainedImage{
+		{RepoURL: "example.com/backend", Tag: "v1.2.3"},
+		{RepoURL: "example.com/worker", Digest: "sha256:abc"},
+	}, retained)
+
+	retained, err = findRetainedImages(filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, retained)
+}
+
 func Test_mergeImages(t *testing.T) {
 	tests := []struct {
 		name          string
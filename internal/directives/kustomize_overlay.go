@@ -0,0 +1,162 @@
+package directives
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// renderedManifestsFileNames are the names of files that steps such as
+// kustomize-build and helm-template commonly write rendered manifests to.
+var renderedManifestsFileNames = []string{"all.yaml", "all.yml", "manifests.yaml"}
+
+// validateKustomizeOverlay verifies that the directory at the provided path,
+// relative to the provided working directory, is a Kustomize overlay that can
+// be built: the directory must exist, must contain exactly one Kustomization
+// file, and every local resource, component, and base that the Kustomization
+// file refers to must exist within the working directory. If so, the path of
+// the Kustomization file is returned. Otherwise, a terminal error naming
+// everything that is missing is returned, as retrying cannot remedy a
+// misconfiguration.
+func validateKustomizeOverlay(workDir, path string) (string, error) {
+	dir, err := securejoin.SecureJoin(workDir, path)
+	if err != nil {
+		return "", fmt.Errorf("could not secure join path %q: %w", path, err)
+	}
+
+	fi, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return "", newMisconfiguredOverlayError(
+			path,
+			errors.New("directory does not exist; check that the expected branch "+
+				"or commit was checked out and that the path is correct"),
+		)
+	case err != nil:
+		return "", fmt.Errorf("error reading overlay directory %q: %w", path, sanitizePathError(err, workDir))
+	case !fi.IsDir():
+		return "", newMisconfiguredOverlayError(path, errors.New("not a directory"))
+	}
+
+	kusPath, err := findKustomization(workDir, path)
+	if err != nil {
+		if rendered := findRenderedManifests(dir); rendered != "" {
+			return "", newMisconfiguredOverlayError(path, fmt.Errorf(
+				"directory contains rendered manifests (%s) but no Kustomization "+
+					"file; this looks like the output of a previous promotion rather "+
+					"than an overlay, which usually means that the wrong branch was "+
+					"checked out",
+				rendered,
+			))
+		}
+		return "", newMisconfiguredOverlayError(path, err)
+	}
+
+	b, err := os.ReadFile(kusPath)
+	if err != nil {
+		return "", fmt.Errorf("could not read Kustomization file: %w", sanitizePathError(err, workDir))
+	}
+	var kus kustypes.Kustomization
+	if err = yaml.Unmarshal(b, &kus); err != nil {
+		// Kustomize reports invalid Kustomization files well enough on its own.
+		return kusPath, nil
+	}
+
+	var errs []error
+	for _, refs := range []struct {
+		field string
+		paths []string
+	}{
+		{field: "resources", paths: kus.Resources},
+		{field: "components", paths: kus.Components},
+		{field: "bases", paths: kus.Bases}, // nolint: staticcheck
+	} {
+		for _, ref := range refs.paths {
+			if err = validateKustomizationRef(workDir, dir, ref); err != nil {
+				errs = append(errs, fmt.Errorf("%s entry %q %w", refs.field, ref, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return "", newMisconfiguredOverlayError(path, errors.Join(errs...))
+	}
+	return kusPath, nil
+}
+
+// validateKustomizationRef verifies that the provided entry of a Kustomization
+// file in the provided directory refers to a file or to a directory containing
+// a Kustomization file within the provided working directory. Entries that
+// refer to remote resources are not verified.
+func validateKustomizationRef(workDir, dir, ref string) error {
+	target := filepath.Join(dir, ref)
+	rel, err := filepath.Rel(workDir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		if isRemoteKustomizationRef(ref) {
+			return nil
+		}
+		return errors.New("resolves to a path outside of the working directory")
+	}
+	fi, err := os.Stat(target)
+	if err != nil {
+		if os.IsNotExist(err) && isRemoteKustomizationRef(ref) {
+			return nil
+		}
+		return fmt.Errorf("does not exist at %q", rel)
+	}
+	if !fi.IsDir() {
+		return nil
+	}
+	if _, err = findKustomization(workDir, rel); err != nil {
+		return fmt.Errorf("refers to directory %q, which contains no Kustomization file", rel)
+	}
+	return nil
+}
+
+// isRemoteKustomizationRef returns true if the provided entry of a
+// Kustomization file looks like a reference to a remote resource, e.g. a URL
+// or a Git repository.
+func isRemoteKustomizationRef(ref string) bool {
+	return strings.Contains(ref, "://") ||
+		strings.HasPrefix(ref, "git@") ||
+		strings.HasPrefix(ref, "github.com/") ||
+		strings.HasPrefix(ref, "gitlab.com/") ||
+		strings.HasPrefix(ref, "bitbucket.org/") ||
+		strings.Contains(ref, "?ref=") ||
+		strings.Contains(ref, ".git//")
+}
+
+// findRenderedManifests returns the name of the first file in the provided
+// directory that conventionally holds rendered manifests, or an empty string
+// if there is none. Directories that hold a Kustomization file are never
+// considered to be rendered output.
+func findRenderedManifests(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if slices.Contains(konfig.RecognizedKustomizationFileNames(), entry.Name()) {
+			return ""
+		}
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && slices.Contains(renderedManifestsFileNames, entry.Name()) {
+			return entry.Name()
+		}
+	}
+	return ""
+}
+
+func newMisconfiguredOverlayError(path string, err error) error {
+	return &terminalError{
+		err: fmt.Errorf("MisconfiguredOverlay: %q is not a valid Kustomize overlay: %w", path, err),
+	}
+}
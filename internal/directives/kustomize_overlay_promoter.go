@@ -0,0 +1,393 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/xeipuuv/gojsonschema"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+// overlayWorkTreesDir is the directory, relative to the working directory of
+// a promotion, to which the working trees of the rendered branches of a
+// Stage's overlays are added.
+const overlayWorkTreesDir = ".overlays"
+
+// overlaysFailedReason prefixes the message of a kustomize-promote-overlays
+// step that failed to promote some of the Stage's overlays.
+const overlaysFailedReason = "OverlaysFailed"
+
+func init() {
+	builtins.RegisterPromotionStepRunner(
+		newKustomizeOverlayPromoter(),
+		&StepRunnerPermissions{
+			AllowCredentialsDB: true,
+			AllowKargoClient:   true,
+		},
+	)
+}
+
+// kustomizeOverlayPromoter is an implementation of the PromotionStepRunner
+// interface that sets images in each of the Kustomize overlays of a Stage,
+// renders each overlay into its own branch, and pushes all of these branches
+// together.
+type kustomizeOverlayPromoter struct {
+	schemaLoader gojsonschema.JSONLoader
+	imageSetter  *kustomizeImageSetter
+	builder      *kustomizeBuilder
+	pusher       *gitPushPusher
+	// gitUser is the default user that manifests rendered from overlays that
+	// specify a GitIdentity of their own are committed as, with its name and
+	// email replaced by those of the identity.
+	gitUser git.User
+}
+
+// newKustomizeOverlayPromoter returns an implementation of the
+// PromotionStepRunner interface that sets images in each of the Kustomize
+// overlays of a Stage, renders each overlay into its own branch, and pushes
+// all of these branches together.
+func newKustomizeOverlayPromoter() PromotionStepRunner {
+	return &kustomizeOverlayPromoter{
+		schemaLoader: getConfigSchemaLoader("kustomize-promote-overlays"),
+		imageSetter:  newKustomizeImageSetter().(*kustomizeImageSetter), // nolint: forcetypeassert
+		builder:      newKustomizeBuilder().(*kustomizeBuilder),         // nolint: forcetypeassert
+		pusher:       newGitPusher().(*gitPushPusher),                   // nolint: forcetypeassert
+		gitUser:      gitUserFromEnv(),
+	}
+}
+
+// Name implements the PromotionStepRunner interface.
+func (k *kustomizeOverlayPromoter) Name() string {
+	return "kustomize-promote-overlays"
+}
+
+// RunPromotionStep implements the PromotionStepRunner interface.
+func (k *kustomizeOverlayPromoter) RunPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+) (PromotionStepResult, error) {
+	failure := PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}
+
+	if err := validate(k.schemaLoader, gojsonschema.NewGoLoader(stepCtx.Config), k.Name()); err != nil {
+		return failure, err
+	}
+
+	// Convert the configuration into a typed object.
+	cfg, err := ConfigToStruct[KustomizePromoteOverlaysConfig](stepCtx.Config)
+	if err != nil {
+		return failure, fmt.Errorf("could not convert config into %s config: %w", k.Name(), err)
+	}
+
+	return k.runPromotionStep(ctx, stepCtx, cfg)
+}
+
+// renderedOverlay is an overlay of a Stage whose manifests were rendered and
+// committed to its rendered branch, but not pushed yet.
+type renderedOverlay struct {
+	index  int
+	path   string
+	branch string
+	// warnings are the warnings Kustomize emitted while rendering the
+	// overlay. They are set even if rendering the overlay failed.
+	warnings []string
+	// stepCtx is the PromotionStepContext that the rendered manifests are
+	// pushed with, which reflects the GitIdentity of the overlay.
+	stepCtx *PromotionStepContext
+}
+
+func (k *kustomizeOverlayPromoter) runPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg KustomizePromoteOverlaysConfig,
+) (PromotionStepResult, error) {
+	if len(stepCtx.Overlays) == 0 {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: errors.New("the Stage does not specify any overlays")}
+	}
+
+	path, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.Path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, fmt.Errorf(
+			"error joining path %s with work dir %s: %w",
+			cfg.Path, stepCtx.WorkDir, err,
+		)
+	}
+	workTree, loadOpts, err := loadWorkTreeWithCredentials(ctx, stepCtx, path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error loading working tree from %s: %w", cfg.Path, err)
+	}
+
+	// Overlays that were pushed by a previous attempt to execute the step have
+	// a commit and are left alone, so that a retry only promotes the overlays
+	// that failed.
+	overlays := slices.Clone(stepCtx.Overlays)
+	failures := map[string]error{}
+	var rendered []renderedOverlay
+	var warnings []string
+	for i, overlay := range overlays {
+		if overlay.Commit != "" {
+			continue
+		}
+		r, err := k.renderOverlay(ctx, stepCtx, cfg, workTree.BareRepo(), loadOpts, overlay)
+		warnings = appendRenderWarnings(warnings, r.warnings...)
+		if err != nil {
+			failures[overlay.Name] = err
+			continue
+		}
+		r.index = i
+		rendered = append(rendered, r)
+	}
+
+	// Overlays are pushed together, except that overlays whose GitIdentities
+	// take credentials from different Secrets are pushed with each of them.
+	for _, group := range groupByGitCredentials(rendered) {
+		commits, err := k.push(ctx, group[0].stepCtx, group)
+		for _, r := range group {
+			if err != nil {
+				failures[overlays[r.index].Name] = err
+				continue
+			}
+			overlays[r.index].Commit = commits[r.branch]
+		}
+	}
+
+	result := PromotionStepResult{
+		Status:         kargoapi.PromotionPhaseSucceeded,
+		Overlays:       overlays,
+		Output:         map[string]any{"overlays": overlaysOutput(overlays)},
+		RenderWarnings: warnings,
+	}
+	if len(failures) == 0 {
+		return result, nil
+	}
+	err = overlaysFailedError(overlays, failures)
+	for _, failure := range failures {
+		if !isTerminal(failure) {
+			result.Status = kargoapi.PromotionPhaseErrored
+			return result, err
+		}
+	}
+	// No amount of retries will promote the overlays that failed.
+	result.Status = kargoapi.PromotionPhaseFailed
+	return result, &terminalError{err: err}
+}
+
+// renderOverlay sets images in the Kustomization file of the provided overlay
+// in the working tree at the configured path, renders the overlay into the
+// working tree of its rendered branch, and commits the rendered manifests.
+func (k *kustomizeOverlayPromoter) renderOverlay(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg KustomizePromoteOverlaysConfig,
+	repo git.BareRepo,
+	loadOpts *git.LoadWorkTreeOptions,
+	overlay kargoapi.PromotedOverlay,
+) (renderedOverlay, error) {
+	stepCtx = overlayStepContext(stepCtx, overlay)
+	overlayPath := filepath.Join(cfg.Path, overlay.Path)
+	setResult, err := k.imageSetter.runPromotionStep(ctx, stepCtx, KustomizeSetImageConfig{
+		Path:                    overlayPath,
+		Images:                  cfg.Images,
+		UnreferencedImagePolicy: cfg.UnreferencedImagePolicy,
+	})
+	if err != nil {
+		return renderedOverlay{}, err
+	}
+
+	renderedPath := filepath.Join(overlayWorkTreesDir, overlay.Name)
+	workTree, err := k.renderedWorkTree(ctx, stepCtx.WorkDir, renderedPath, repo, loadOpts, overlay)
+	if err != nil {
+		return renderedOverlay{}, err
+	}
+	if err = workTree.Clear(nil); err != nil {
+		return renderedOverlay{}, err
+	}
+	buildResult, err := k.builder.runPromotionStep(ctx, stepCtx, KustomizeBuildConfig{
+		Path:      overlayPath,
+		OutPath:   filepath.Join(renderedPath, cfg.OutPath),
+		Normalize: cfg.Normalize,
+	})
+	if err != nil {
+		return renderedOverlay{warnings: buildResult.RenderWarnings}, err
+	}
+
+	message := cfg.Message
+	if message == "" {
+		message, _ = setResult.Output["commitMessage"].(string)
+	}
+	if message == "" {
+		message = fmt.Sprintf("Rendered overlay %s", overlay.Name)
+	}
+	if err = workTree.AddAll(); err != nil {
+		return renderedOverlay{}, err
+	}
+	hasDiffs, err := workTree.HasDiffs()
+	if err != nil {
+		return renderedOverlay{}, err
+	}
+	// If nothing changed, e.g. because the overlay was already promoted by a
+	// previous Promotion, the branch is still pushed, which is a no-op.
+	if hasDiffs {
+		// Unless the overlay specifies an identity of its own, the rendered
+		// manifests are committed as the user the repository was cloned with.
+		commitOpts := &git.CommitOptions{}
+		if overlay.GitIdentity != nil {
+			user := gitIdentityUser(stepCtx, k.gitUser)
+			commitOpts.Author, commitOpts.Committer = &user, &user
+		}
+		if err = workTree.Commit(message, commitOpts); err != nil {
+			return renderedOverlay{}, err
+		}
+	}
+	return renderedOverlay{
+		path:     renderedPath,
+		branch:   overlay.RenderedBranch,
+		warnings: buildResult.RenderWarnings,
+		stepCtx:  stepCtx,
+	}, nil
+}
+
+// overlayStepContext returns the provided PromotionStepContext with its
+// GitIdentity replaced by that of the provided overlay, if it specifies one.
+func overlayStepContext(
+	stepCtx *PromotionStepContext,
+	overlay kargoapi.PromotedOverlay,
+) *PromotionStepContext {
+	if overlay.GitIdentity == nil {
+		return stepCtx
+	}
+	overlayCtx := *stepCtx
+	overlayCtx.GitIdentity = overlay.GitIdentity
+	return &overlayCtx
+}
+
+// groupByGitCredentials groups the provided rendered overlays by the Secret
+// that the GitIdentity they are pushed with takes credentials from, retaining
+// the order in which each Secret is first encountered.
+func groupByGitCredentials(rendered []renderedOverlay) [][]renderedOverlay {
+	var groups [][]renderedOverlay
+	indices := map[string]int{}
+	for _, r := range rendered {
+		secret := gitCredentialsSecret(r.stepCtx)
+		i, ok := indices[secret]
+		if !ok {
+			i = len(groups)
+			indices[secret] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], r)
+	}
+	return groups
+}
+
+// renderedWorkTree returns the working tree of the rendered branch of the
+// provided overlay at the provided path, relative to the provided working
+// directory. If a previous attempt to execute the step already added the
+// working tree, it is loaded. Otherwise, it is added to the provided
+// repository, creating the branch if it does not exist yet.
+func (k *kustomizeOverlayPromoter) renderedWorkTree(
+	ctx context.Context,
+	workDir string,
+	path string,
+	repo git.BareRepo,
+	loadOpts *git.LoadWorkTreeOptions,
+	overlay kargoapi.PromotedOverlay,
+) (git.WorkTree, error) {
+	absPath, err := securejoin.SecureJoin(workDir, path)
+	if err != nil {
+		return nil, fmt.Errorf("error joining path %s with work dir %s: %w", path, workDir, err)
+	}
+	if _, err = os.Stat(absPath); err == nil {
+		return git.LoadWorkTree(absPath, loadOpts)
+	}
+	ref, err := ensureRemoteBranch(ctx, repo, overlay.RenderedBranch, true)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error ensuring existence of remote branch %s: %w", overlay.RenderedBranch, err,
+		)
+	}
+	return repo.AddWorkTree(absPath, &git.AddWorkTreeOptions{
+		Ref:    ref,
+		Branch: overlay.RenderedBranch,
+	})
+}
+
+// push pushes the rendered branches of the provided overlays together,
+// atomically if the remote supports it, and returns the commits that were
+// pushed to them, keyed by branch.
+func (k *kustomizeOverlayPromoter) push(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	rendered []renderedOverlay,
+) (map[string]string, error) {
+	pushCfg := GitPushConfig{
+		Path:         rendered[0].path,
+		TargetBranch: rendered[0].branch,
+	}
+	for _, r := range rendered[1:] {
+		pushCfg.AdditionalBranches = append(pushCfg.AdditionalBranches, AdditionalBranch{
+			Path:         r.path,
+			TargetBranch: r.branch,
+		})
+	}
+	res, err := k.pusher.runPromotionStep(ctx, stepCtx, pushCfg)
+	if err != nil {
+		return nil, err
+	}
+	commits := map[string]string{}
+	commits[fmt.Sprint(res.Output[stateKeyBranch])] = fmt.Sprint(res.Output[stateKeyCommit])
+	additional, _ := res.Output[stateKeyAdditionalBranches].([]any)
+	for _, b := range additional {
+		if b, ok := b.(map[string]any); ok {
+			commits[fmt.Sprint(b[stateKeyBranch])] = fmt.Sprint(b[stateKeyCommit])
+		}
+	}
+	return commits, nil
+}
+
+// overlaysOutput returns the output of the step that describes the provided
+// overlays.
+func overlaysOutput(overlays []kargoapi.PromotedOverlay) []any {
+	out := make([]any, len(overlays))
+	for i, o := range overlays {
+		out[i] = map[string]any{
+			"name":         o.Name,
+			stateKeyBranch: o.RenderedBranch,
+			stateKeyCommit: o.Commit,
+		}
+	}
+	return out
+}
+
+// overlaysFailedError returns an error that names the overlays that failed to
+// be promoted, along with the reasons, and the overlays that were promoted.
+func overlaysFailedError(
+	overlays []kargoapi.PromotedOverlay,
+	failures map[string]error,
+) error {
+	var failed, landed []string
+	for _, o := range overlays {
+		if err, ok := failures[o.Name]; ok {
+			failed = append(failed, fmt.Sprintf("%s (%v)", o.Name, err))
+			continue
+		}
+		landed = append(landed, fmt.Sprintf("%s (%s)", o.Name, o.Commit))
+	}
+	msg := fmt.Sprintf(
+		"%s: %d of %d overlay(s) could not be promoted: %s",
+		overlaysFailedReason, len(failed), len(overlays), strings.Join(failed, "; "),
+	)
+	if len(landed) > 0 {
+		msg += "; promoted: " + strings.Join(landed, ", ")
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,175 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sosedoff/gitkit"
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+func Test_kustomizeOverlayPromoter_runPromotionStep(t *testing.T) {
+	// Set up a test Git server in-process
+	service := gitkit.New(
+		gitkit.Config{
+			Dir:        t.TempDir(),
+			AutoCreate: true,
+		},
+	)
+	require.NoError(t, service.Setup())
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	// This is the URL of the "remote" repository
+	testRepoURL := fmt.Sprintf("%s/test.git", server.URL)
+
+	// Push a base and two overlays, along with the rendered branches of the
+	// overlays, to the remote repository.
+	setupRepo, err := git.Clone(testRepoURL, nil, nil)
+	require.NoError(t, err)
+	defer setupRepo.Close()
+	baseDir := filepath.Join(setupRepo.Dir(), "base")
+	require.NoError(t, os.MkdirAll(baseDir, 0o700))
+	writeTestDeployment(t, baseDir, "nginx:1.20.0")
+	require.NoError(t, os.WriteFile(
+		filepath.Join(baseDir, "kustomization.yaml"),
+		[]byte("resources:\n- deployment.yaml\n"),
+		0o600,
+	))
+	for _, region := range []string{"us-east", "eu-west"} {
+		overlayDir := filepath.Join(setupRepo.Dir(), "overlays", region)
+		require.NoError(t, os.MkdirAll(overlayDir, 0o700))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(overlayDir, "kustomization.yaml"),
+			[]byte(fmt.Sprintf("resources:\n- ../../base\nnamePrefix: %s-\n", region)),
+			0o600,
+		))
+	}
+	require.NoError(t, setupRepo.AddAllAndCommit("Initial commit"))
+	for _, branch := range []string{"master", "rendered/us-east", "rendered/eu-west", "rendered/ap-south"} {
+		require.NoError(t, setupRepo.Push(&git.PushOptions{TargetBranch: branch}))
+	}
+
+	// Check out the repository the way git-clone would have.
+	workDir := t.TempDir()
+	repo, err := git.CloneBare(testRepoURL, nil, &git.BareCloneOptions{BaseDir: workDir})
+	require.NoError(t, err)
+	defer repo.Close()
+	srcWorkTree, err := repo.AddWorkTree(
+		filepath.Join(workDir, "src"),
+		&git.AddWorkTreeOptions{Ref: "master"},
+	)
+	require.NoError(t, err)
+
+	runner, ok := newKustomizeOverlayPromoter().(*kustomizeOverlayPromoter)
+	require.True(t, ok)
+	stepCtx := &PromotionStepContext{
+		Project:       "fake-project",
+		Stage:         "fake-stage",
+		Promotion:     "fake-promotion",
+		WorkDir:       workDir,
+		CredentialsDB: &credentials.FakeDB{},
+		Overlays: []kargoapi.PromotedOverlay{
+			{Name: "us-east", Path: "overlays/us-east", RenderedBranch: "rendered/us-east"},
+			// The overlay of this region does not exist yet.
+			{Name: "ap-south", Path: "overlays/ap-south", RenderedBranch: "rendered/ap-south"},
+			{Name: "eu-west", Path: "overlays/eu-west", RenderedBranch: "rendered/eu-west"},
+		},
+	}
+	cfg := KustomizePromoteOverlaysConfig{
+		Path:   "src",
+		Images: []KustomizeSetImageConfigImage{{Image: "nginx", Tag: "1.21.0"}},
+	}
+
+	// The overlays that exist are promoted, while the missing one fails. As
+	// retrying does not make it exist, the failure is terminal.
+	res, err := runner.runPromotionStep(context.Background(), stepCtx, cfg)
+	require.ErrorContains(t, err, "OverlaysFailed: 1 of 3 overlay(s) could not be promoted: ap-south (")
+	require.ErrorContains(t, err, "; promoted: us-east (")
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+	require.Len(t, res.Overlays, 3)
+	for _, overlay := range res.Overlays {
+		commit, err := git.RemoteBranchCommit(testRepoURL, overlay.RenderedBranch, nil)
+		require.NoError(t, err)
+		if overlay.Name == "ap-south" {
+			require.Empty(t, overlay.Commit)
+			continue
+		}
+		require.Equal(t, commit, overlay.Commit)
+		rendered, err := os.ReadFile(filepath.Join(
+			workDir, overlayWorkTreesDir, overlay.Name,
+			fmt.Sprintf("deployment-%s-app.yaml", overlay.Name),
+		))
+		require.NoError(t, err)
+		require.Contains(t, string(rendered), "image: nginx:1.21.0")
+	}
+	require.Len(t, res.Output["overlays"], 3)
+
+	// Once the missing overlay has been added, executing the step again only
+	// promotes it.
+	overlayDir := filepath.Join(srcWorkTree.Dir(), "overlays", "ap-south")
+	require.NoError(t, os.MkdirAll(overlayDir, 0o700))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(overlayDir, "kustomization.yaml"),
+		[]byte("resources:\n- ../../base\nnamePrefix: ap-south-\n"),
+		0o600,
+	))
+	stepCtx.Overlays = res.Overlays
+	retryRes, err := runner.runPromotionStep(context.Background(), stepCtx, cfg)
+	require.NoError(t, err)
+	require.Equal(t, kargoapi.PromotionPhaseSucceeded, retryRes.Status)
+	require.Equal(t, res.Overlays[0], retryRes.Overlays[0])
+	require.Equal(t, res.Overlays[2], retryRes.Overlays[2])
+	commit, err := git.RemoteBranchCommit(testRepoURL, "rendered/ap-south", nil)
+	require.NoError(t, err)
+	require.Equal(t, commit, retryRes.Overlays[1].Commit)
+}
+
+func Test_kustomizeOverlayPromoter_noOverlays(t *testing.T) {
+	runner, ok := newKustomizeOverlayPromoter().(*kustomizeOverlayPromoter)
+	require.True(t, ok)
+	res, err := runner.runPromotionStep(
+		context.Background(),
+		&PromotionStepContext{WorkDir: t.TempDir()},
+		KustomizePromoteOverlaysConfig{Path: "src"},
+	)
+	require.ErrorContains(t, err, "the Stage does not specify any overlays")
+	require.True(t, isTerminal(err))
+	require.Equal(t, kargoapi.PromotionPhaseFailed, res.Status)
+}
+
+func Test_groupByGitCredentials(t *testing.T) {
+	stepCtx := &PromotionStepContext{
+		GitIdentity: &kargoapi.GitIdentity{CredentialsSecret: "git-stage"},
+	}
+	usCtx := overlayStepContext(stepCtx, kargoapi.PromotedOverlay{
+		GitIdentity: &kargoapi.GitIdentity{Name: "Kargo US", CredentialsSecret: "git-us"},
+	})
+	// The identity of an overlay replaces that of the Stage entirely, so an
+	// overlay specifying only a name is pushed with the default credentials.
+	euCtx := overlayStepContext(stepCtx, kargoapi.PromotedOverlay{
+		GitIdentity: &kargoapi.GitIdentity{Name: "Kargo EU"},
+	})
+	require.Same(t, stepCtx, overlayStepContext(stepCtx, kargoapi.PromotedOverlay{}))
+	require.Equal(t, "git-stage", stepCtx.GitIdentity.CredentialsSecret)
+
+	groups := groupByGitCredentials([]renderedOverlay{
+		{branch: "a", stepCtx: stepCtx},
+		{branch: "b", stepCtx: usCtx},
+		{branch: "c", stepCtx: stepCtx},
+		{branch: "d", stepCtx: euCtx},
+	})
+	require.Len(t, groups, 3)
+	require.Equal(t, []string{"a", "c"}, []string{groups[0][0].branch, groups[0][1].branch})
+	require.Equal(t, "b", groups[1][0].branch)
+	require.Equal(t, "d", groups[2][0].branch)
+}
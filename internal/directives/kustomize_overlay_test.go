@@ -0,0 +1,130 @@
+package directives
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateKustomizeOverlay(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		path       string
+		assertions func(*testing.T, string, string, error)
+	}{
+		{
+			name: "valid overlay",
+			files: map[string]string{
+				"base/kustomization.yaml": "resources:\n- deployment.yaml\n",
+				"base/deployment.yaml":    "",
+				"components/kustomization.yaml": "apiVersion: kustomize.config.k8s.io/v1alpha1\n" +
+					"kind: Component\n",
+				"prod/kustomization.yaml": `resources:
+- ../base
+- https://github.com/example/repo//manifests?ref=main
+- github.com/example/repo/manifests
+components:
+- ../components
+`,
+			},
+			path: "prod",
+			assertions: func(t *testing.T, workDir, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, filepath.Join(workDir, "prod", "kustomization.yaml"), kusPath)
+			},
+		},
+		{
+			name:  "directory does not exist",
+			files: map[string]string{"dev/kustomization.yaml": ""},
+			path:  "prod",
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, `MisconfiguredOverlay: "prod" is not a valid Kustomize overlay`)
+				require.ErrorContains(t, err, "directory does not exist")
+				assert.True(t, isTerminal(err))
+			},
+		},
+		{
+			name:  "not a directory",
+			files: map[string]string{"prod": ""},
+			path:  "prod",
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, "MisconfiguredOverlay:")
+				require.ErrorContains(t, err, "not a directory")
+			},
+		},
+		{
+			name:  "rendered output",
+			files: map[string]string{"prod/all.yaml": "kind: Deployment\n"},
+			path:  "prod",
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, "MisconfiguredOverlay:")
+				require.ErrorContains(t, err, "contains rendered manifests (all.yaml) but no Kustomization file")
+				require.ErrorContains(t, err, "wrong branch")
+				assert.True(t, isTerminal(err))
+			},
+		},
+		{
+			name:  "no Kustomization file",
+			files: map[string]string{"prod/README.md": ""},
+			path:  "prod",
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, "MisconfiguredOverlay:")
+				require.ErrorContains(t, err, "could not find any Kustomization files")
+				assert.NotContains(t, err.Error(), "rendered manifests")
+			},
+		},
+		{
+			name: "unresolvable references",
+			files: map[string]string{
+				"base/deployment.yaml": "",
+				"prod/kustomization.yaml": `resources:
+- ../base
+- service.yaml
+- ../../../outside
+components:
+- ../components
+`,
+			},
+			path: "prod",
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, "MisconfiguredOverlay:")
+				require.ErrorContains(
+					t, err,
+					`resources entry "../base" refers to directory "base", which contains no Kustomization file`,
+				)
+				require.ErrorContains(t, err, `resources entry "service.yaml" does not exist at "prod/service.yaml"`)
+				require.ErrorContains(
+					t, err,
+					`resources entry "../../../outside" resolves to a path outside of the working directory`,
+				)
+				require.ErrorContains(t, err, `components entry "../components" does not exist at "components"`)
+				assert.True(t, isTerminal(err))
+			},
+		},
+		{
+			name:  "invalid Kustomization file is left to Kustomize",
+			files: map[string]string{"prod/kustomization.yaml": "invalid"},
+			path:  "prod",
+			assertions: func(t *testing.T, workDir, kusPath string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, filepath.Join(workDir, "prod", "kustomization.yaml"), kusPath)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			for name, content := range tt.files {
+				p := filepath.Join(workDir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o700))
+				require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+			}
+			kusPath, err := validateKustomizeOverlay(workDir, tt.path)
+			tt.assertions(t, workDir, kusPath, err)
+		})
+	}
+}
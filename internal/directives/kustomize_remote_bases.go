@@ -0,0 +1,368 @@
+package directives
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/otiai10/copy"
+	yaml "sigs.k8s.io/yaml/goyaml.v3"
+
+	"github.com/akuity/kargo/internal/controller/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// remoteBasesDir is the directory, relative to the working directory of a
+// Promotion, that remote Kustomize bases are fetched into before an overlay
+// referring to them is built.
+const remoteBasesDir = ".kustomize-remote-bases"
+
+// errRemoteBaseNotAllowed is wrapped by the errors returned when the host of a
+// remote base is not permitted by the RemoteBasePolicy.
+var errRemoteBaseNotAllowed = errors.New("RemoteBaseNotAllowed")
+
+// errRemoteBaseFetchFailed is wrapped by the errors returned when a remote base
+// cannot be fetched.
+var errRemoteBaseFetchFailed = errors.New("RemoteBaseFetchFailed")
+
+// wellKnownGitHosts are the hosts whose repositories Kustomize assumes to be
+// of the form <host>/<org>/<repo>, so that the path within the repository
+// does not need to be separated from it by "//".
+var wellKnownGitHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// commitIDRegex matches refs that are (abbreviated) commit IDs, which, unlike
+// branches and tags, cannot be cloned directly.
+var commitIDRegex = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// remoteBase is an entry of a Kustomization file that refers to a directory of
+// a Git repository, e.g. github.com/org/repo//path?ref=v1.0.0.
+type remoteBase struct {
+	// RepoURL is the URL of the repository.
+	RepoURL string
+	// Host is the host of the repository.
+	Host string
+	// Path is the path of the directory within the repository.
+	Path string
+	// Ref is the branch, tag, or commit to fetch. If empty, the repository's
+	// default branch is fetched.
+	Ref string
+}
+
+// parseRemoteBase parses the provided entry of a Kustomization file the way
+// Kustomize does and returns false if it does not refer to a directory of a
+// Git repository. References to remote files are left to Kustomize.
+func parseRemoteBase(entry string) (remoteBase, bool) {
+	s, query, _ := strings.Cut(strings.TrimPrefix(entry, "git::"), "?")
+	values, _ := url.ParseQuery(query)
+	ref := values.Get("ref")
+	if ref == "" {
+		ref = values.Get("version")
+	}
+
+	var scheme string
+	if i := strings.Index(s, "://"); i >= 0 {
+		scheme, s = s[:i+len("://")], s[i+len("://"):]
+	}
+	repo, path, found := strings.Cut(s, "//")
+	if !found {
+		parts := strings.SplitN(s, "/", 4)
+		if len(parts) < 3 || !slices.Contains(wellKnownGitHosts, parts[0]) {
+			return remoteBase{}, false
+		}
+		repo = strings.Join(parts[:3], "/")
+		if len(parts) == 4 {
+			path = parts[3]
+		}
+	}
+	if repo == "" {
+		return remoteBase{}, false
+	}
+
+	base := remoteBase{Path: strings.Trim(path, "/"), Ref: ref}
+	switch {
+	case scheme != "":
+		base.RepoURL = scheme + repo
+		u, err := url.Parse(base.RepoURL)
+		if err != nil {
+			return remoteBase{}, false
+		}
+		base.Host = u.Hostname()
+	case strings.HasPrefix(repo, "git@"):
+		// An SCP-like SSH URL, e.g. git@github.com:org/repo.
+		base.RepoURL = repo
+		base.Host, _, _ = strings.Cut(strings.TrimPrefix(repo, "git@"), ":")
+	default:
+		base.RepoURL = "https://" + repo
+		base.Host, _, _ = strings.Cut(repo, "/")
+	}
+	return base, true
+}
+
+// String returns the remote base in the form Kustomize accepts.
+func (b remoteBase) String() string {
+	s := b.RepoURL
+	if b.Path != "" {
+		s += "//" + b.Path
+	}
+	if b.Ref != "" {
+		s += "?ref=" + b.Ref
+	}
+	return s
+}
+
+// remoteBaseFetcher fetches the remote bases referred to by a Kustomize
+// overlay, using the Git credentials of the Project, and points the
+// Kustomization files referring to them at the fetched copies, so that the
+// overlay can be built without Kustomize accessing the network. This is
+// necessary because Kustomize neither has access to the credentials nor, when
+// built in-process, may it leave the working directory.
+type remoteBaseFetcher struct {
+	stepCtx *PromotionStepContext
+	// dir is the absolute path of the directory remote bases are fetched into.
+	dir string
+	// fetched maps repositories and refs, in the form <repoURL>?ref=<ref>, onto
+	// the absolute paths they were fetched to.
+	fetched map[string]string
+	// visited are the absolute paths of the Kustomization files that have
+	// already been processed.
+	visited map[string]struct{}
+	// originals maps the absolute paths of the Kustomization files within the
+	// working directory that were modified onto their original contents.
+	originals map[string][]byte
+}
+
+// fetchRemoteBases fetches the remote bases referred to, directly or
+// indirectly, by the overlay at the provided path, relative to the working
+// directory of the provided PromotionStepContext, and rewrites the
+// Kustomization files referring to them to refer to the fetched copies
+// instead. The returned function restores the rewritten Kustomization files and
+// removes the fetched copies. It must be called once the overlay has been built,
+// even if an error is returned.
+func fetchRemoteBases(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	path string,
+) (func(), error) {
+	f := &remoteBaseFetcher{
+		stepCtx:   stepCtx,
+		dir:       filepath.Join(stepCtx.WorkDir, remoteBasesDir),
+		fetched:   map[string]string{},
+		visited:   map[string]struct{}{},
+		originals: map[string][]byte{},
+	}
+	dir, err := securejoin.SecureJoin(stepCtx.WorkDir, path)
+	if err != nil {
+		return func() {}, fmt.Errorf("could not secure join path %q: %w", path, err)
+	}
+	return f.restore(ctx), f.processKustomization(ctx, dir, true)
+}
+
+// restore returns a function that restores the Kustomization files that were
+// rewritten and removes the fetched remote bases.
+func (f *remoteBaseFetcher) restore(ctx context.Context) func() {
+	return func() {
+		logger := logging.LoggerFromContext(ctx)
+		for kusPath, b := range f.originals {
+			if err := os.WriteFile(kusPath, b, 0o600); err != nil {
+				logger.Error(
+					sanitizePathError(err, f.stepCtx.WorkDir),
+					"error restoring Kustomization file",
+				)
+			}
+		}
+		if len(f.fetched) > 0 {
+			if err := os.RemoveAll(f.dir); err != nil {
+				logger.Error(err, "error removing fetched remote bases")
+			}
+		}
+	}
+}
+
+// processKustomization fetches the remote bases referred to by the
+// Kustomization file in the provided directory, if any, and rewrites the file
+// to refer to the fetched copies. Local directories it refers to are
+// processed in turn. If restore is true, the original file is restored by the
+// function returned by fetchRemoteBases. Files of fetched remote bases need not
+// be, as they are removed.
+func (f *remoteBaseFetcher) processKustomization(
+	ctx context.Context,
+	dir string,
+	restore bool,
+) error {
+	kusPath, err := findKustomization(dir, ".")
+	if err != nil {
+		// Directories without a Kustomization file are left to Kustomize.
+		return nil
+	}
+	if _, ok := f.visited[kusPath]; ok {
+		return nil
+	}
+	f.visited[kusPath] = struct{}{}
+
+	b, err := os.ReadFile(kusPath)
+	if err != nil {
+		return fmt.Errorf(
+			"could not read Kustomization file: %w",
+			sanitizePathError(err, f.stepCtx.WorkDir),
+		)
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(b, &doc); err != nil || len(doc.Content) == 0 ||
+		doc.Content[0].Kind != yaml.MappingNode {
+		// Kustomize reports invalid Kustomization files well enough on its own.
+		return nil
+	}
+
+	var rewritten bool
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case "resources", "components", "bases":
+		default:
+			continue
+		}
+		for _, entry := range root.Content[i+1].Content {
+			if entry.Kind != yaml.ScalarNode {
+				continue
+			}
+			target := filepath.Join(dir, entry.Value)
+			if fi, statErr := os.Stat(target); statErr == nil {
+				if fi.IsDir() && isSubPath(f.stepCtx.WorkDir, target) {
+					if err = f.processKustomization(ctx, target, restore); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			base, ok := parseRemoteBase(entry.Value)
+			if !ok {
+				continue
+			}
+			fetchedDir, err := f.fetch(ctx, base)
+			if err != nil {
+				return err
+			}
+			if entry.Value, err = filepath.Rel(dir, fetchedDir); err != nil {
+				return fmt.Errorf("error resolving path of remote base %q: %w", base, err)
+			}
+			entry.Value = filepath.ToSlash(entry.Value)
+			rewritten = true
+		}
+	}
+	if !rewritten {
+		return nil
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("could not marshal Kustomization file: %w", err)
+	}
+	if restore {
+		f.originals[kusPath] = b
+	}
+	if err = os.WriteFile(kusPath, out, 0o600); err != nil {
+		return fmt.Errorf(
+			"could not write Kustomization file: %w",
+			sanitizePathError(err, f.stepCtx.WorkDir),
+		)
+	}
+	return nil
+}
+
+// fetch fetches the provided remote base, unless its repository has already
+// been fetched at the same ref, and returns the absolute path of the fetched
+// directory. Remote bases referred to by the fetched one are fetched in turn.
+func (f *remoteBaseFetcher) fetch(ctx context.Context, base remoteBase) (string, error) {
+	if !f.stepCtx.RemoteBasePolicy.AllowsHost(base.Host) {
+		return "", &terminalError{err: fmt.Errorf(
+			"%w: remote base %q may not be fetched, as its host %q is not "+
+				"permitted by the remote base policy",
+			errRemoteBaseNotAllowed, base, base.Host,
+		)}
+	}
+
+	key := base.RepoURL + "?ref=" + base.Ref
+	repoDir, ok := f.fetched[key]
+	if !ok {
+		sum := sha256.Sum256([]byte(key))
+		repoDir = filepath.Join(f.dir, hex.EncodeToString(sum[:8]))
+		if err := f.clone(ctx, base, repoDir); err != nil {
+			return "", err
+		}
+		f.fetched[key] = repoDir
+	}
+
+	dir, err := securejoin.SecureJoin(repoDir, base.Path)
+	if err != nil {
+		return "", fmt.Errorf("could not secure join path %q: %w", base.Path, err)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return "", &terminalError{err: fmt.Errorf(
+			"%w: remote base %q refers to %q, which is not a directory of the repository",
+			errRemoteBaseFetchFailed, base, base.Path,
+		)}
+	}
+	if err = f.processKustomization(ctx, dir, false); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// clone clones the repository of the provided remote base at its ref and
+// copies its contents, without its .git directory, to the provided directory.
+func (f *remoteBaseFetcher) clone(ctx context.Context, base remoteBase, dir string) error {
+	logger := logging.LoggerFromContext(ctx).WithValues("remoteBase", base.String())
+
+	var creds *git.RepoCredentials
+	if f.stepCtx.CredentialsDB != nil {
+		var err error
+		if creds, err = getGitCredentials(ctx, f.stepCtx, base.RepoURL); err != nil {
+			if isTerminal(err) {
+				return fmt.Errorf("%w: remote base %q: %w", errRemoteBaseFetchFailed, base, err)
+			}
+			return err
+		}
+	}
+	cloneOpts := &git.CloneOptions{SingleBranch: true, Depth: 1}
+	isCommit := commitIDRegex.MatchString(base.Ref)
+	if isCommit {
+		// Arbitrary commits can only be checked out from a full clone.
+		cloneOpts = &git.CloneOptions{}
+	} else {
+		cloneOpts.Branch = base.Ref
+	}
+	logger.Debug("fetching remote base")
+	repo, err := git.Clone(base.RepoURL, &git.ClientOptions{Credentials: creds}, cloneOpts)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: could not fetch remote base %q: %w",
+			errRemoteBaseFetchFailed, base, err,
+		)
+	}
+	defer repo.Close()
+	if isCommit {
+		if err = repo.Checkout(base.Ref); err != nil {
+			return fmt.Errorf(
+				"%w: could not fetch remote base %q: %w",
+				errRemoteBaseFetchFailed, base, err,
+			)
+		}
+	}
+	if err = copy.Copy(repo.Dir(), dir, copy.Options{
+		Skip: func(fi os.FileInfo, _, _ string) (bool, error) {
+			return fi.IsDir() && fi.Name() == ".git", nil
+		},
+	}); err != nil {
+		return fmt.Errorf("error copying remote base %q: %w", base, err)
+	}
+	return nil
+}
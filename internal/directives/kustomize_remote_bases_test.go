@@ -0,0 +1,196 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_parseRemoteBase(t *testing.T) {
+	testCases := []struct {
+		entry    string
+		expected remoteBase
+		remote   bool
+	}{
+		{
+			entry: "github.com/org/shared-bases//workload?ref=v2.1.0",
+			expected: remoteBase{
+				RepoURL: "https://github.com/org/shared-bases",
+				Host:    "github.com",
+				Path:    "workload",
+				Ref:     "v2.1.0",
+			},
+			remote: true,
+		},
+		{
+			entry: "github.com/org/shared-bases/workload/app?version=main",
+			expected: remoteBase{
+				RepoURL: "https://github.com/org/shared-bases",
+				Host:    "github.com",
+				Path:    "workload/app",
+				Ref:     "main",
+			},
+			remote: true,
+		},
+		{
+			entry: "https://git.example.com/org/repo.git//base?ref=abc1234",
+			expected: remoteBase{
+				RepoURL: "https://git.example.com/org/repo.git",
+				Host:    "git.example.com",
+				Path:    "base",
+				Ref:     "abc1234",
+			},
+			remote: true,
+		},
+		{
+			entry: "git::ssh://git@git.example.com:2222/org/repo//base",
+			expected: remoteBase{
+				RepoURL: "ssh://git@git.example.com:2222/org/repo",
+				Host:    "git.example.com",
+				Path:    "base",
+			},
+			remote: true,
+		},
+		{
+			entry: "git@github.com:org/repo//base?ref=v1",
+			expected: remoteBase{
+				RepoURL: "git@github.com:org/repo",
+				Host:    "github.com",
+				Path:    "base",
+				Ref:     "v1",
+			},
+			remote: true,
+		},
+		{
+			// A remote file rather than a directory of a repository.
+			entry: "https://raw.githubusercontent.com/org/repo/main/deploy.yaml",
+		},
+		{
+			entry: "../base",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.entry, func(t *testing.T) {
+			base, ok := parseRemoteBase(testCase.entry)
+			require.Equal(t, testCase.remote, ok)
+			require.Equal(t, testCase.expected, base)
+		})
+	}
+}
+
+func Test_kustomizeBuilder_runPromotionStep_remoteBase(t *testing.T) {
+	runGit := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// Set up a repository with a base, tagged v1.0.0, which is then changed.
+	repoDir := t.TempDir()
+	runGit(repoDir, "init", "--initial-branch=main")
+	runGit(repoDir, "config", "user.name", "Kargo")
+	runGit(repoDir, "config", "user.email", "kargo@example.com")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "workload"), 0o700))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(repoDir, "workload", "kustomization.yaml"),
+		[]byte("resources:\n- configmap.yaml\n"),
+		0o600,
+	))
+	writeConfigMap := func(value string) {
+		require.NoError(t, os.WriteFile(
+			filepath.Join(repoDir, "workload", "configmap.yaml"),
+			[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: shared\ndata:\n  version: "+value+"\n"),
+			0o600,
+		))
+	}
+	writeConfigMap("v1")
+	runGit(repoDir, "add", ".")
+	runGit(repoDir, "commit", "-m", "Add workload")
+	runGit(repoDir, "tag", "v1.0.0")
+	writeConfigMap("v2")
+	runGit(repoDir, "commit", "-am", "Update workload")
+
+	setup := func(t *testing.T, entry string) (string, []byte) {
+		workDir := t.TempDir()
+		overlayDir := filepath.Join(workDir, "overlay")
+		require.NoError(t, os.MkdirAll(overlayDir, 0o700))
+		kustomization := []byte("namespace: prod\nresources:\n- " + entry + "\n")
+		require.NoError(t, os.WriteFile(
+			filepath.Join(overlayDir, "kustomization.yaml"), kustomization, 0o600,
+		))
+		return workDir, kustomization
+	}
+	assertRestored := func(t *testing.T, workDir string, kustomization []byte) {
+		b, err := os.ReadFile(filepath.Join(workDir, "overlay", "kustomization.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, string(kustomization), string(b))
+		require.NoDirExists(t, filepath.Join(workDir, remoteBasesDir))
+	}
+
+	runner, ok := newKustomizeBuilder().(*kustomizeBuilder)
+	require.True(t, ok)
+
+	t.Run("fetches remote base at ref", func(t *testing.T) {
+		workDir, kustomization := setup(t, "file://"+repoDir+"//workload?ref=v1.0.0")
+		res, err := runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{WorkDir: workDir},
+			KustomizeBuildConfig{Path: "overlay", OutPath: "out.yaml"},
+		)
+		require.NoError(t, err)
+		require.Equal(t, kargoapi.PromotionPhaseSucceeded, res.Status)
+		b, err := os.ReadFile(filepath.Join(workDir, "out.yaml"))
+		require.NoError(t, err)
+		require.Contains(t, string(b), "version: v1")
+		require.Contains(t, string(b), "namespace: prod")
+		assertRestored(t, workDir, kustomization)
+	})
+
+	t.Run("host not permitted", func(t *testing.T) {
+		workDir, kustomization := setup(t, "file://"+repoDir+"//workload?ref=v1.0.0")
+		_, err := runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{
+				WorkDir:          workDir,
+				RemoteBasePolicy: &kargoapi.RemoteBasePolicy{AllowedHosts: []string{"github.com"}},
+			},
+			KustomizeBuildConfig{Path: "overlay", OutPath: "out.yaml"},
+		)
+		require.ErrorIs(t, err, errRemoteBaseNotAllowed)
+		require.ErrorContains(t, err, repoDir)
+		require.True(t, isTerminal(err))
+		assertRestored(t, workDir, kustomization)
+	})
+
+	t.Run("path not in repository", func(t *testing.T) {
+		workDir, kustomization := setup(t, "file://"+repoDir+"//missing")
+		_, err := runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{WorkDir: workDir},
+			KustomizeBuildConfig{Path: "overlay", OutPath: "out.yaml"},
+		)
+		require.ErrorIs(t, err, errRemoteBaseFetchFailed)
+		require.True(t, isTerminal(err))
+		assertRestored(t, workDir, kustomization)
+	})
+
+	t.Run("repository cannot be fetched", func(t *testing.T) {
+		workDir, kustomization := setup(t, "file://"+repoDir+"-missing//workload")
+		_, err := runner.runPromotionStep(
+			context.Background(),
+			&PromotionStepContext{WorkDir: workDir},
+			KustomizeBuildConfig{Path: "overlay", OutPath: "out.yaml"},
+		)
+		require.ErrorIs(t, err, errRemoteBaseFetchFailed)
+		require.ErrorContains(t, err, repoDir+"-missing//workload")
+		require.False(t, isTerminal(err))
+		assertRestored(t, workDir, kustomization)
+	})
+}
@@ -0,0 +1,123 @@
+package directives
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// errRenderWarnings is wrapped by the errors that steps rendering manifests
+// return when the tools they render them with emit warnings while the Stage
+// demands strict rendering.
+var errRenderWarnings = errors.New("RenderWarnings")
+
+// kustomizationWarnings returns the warnings that Kustomize emits about the
+// use of deprecated fields when building the overlay at the provided path,
+// relative to the provided working directory. The embedded version of
+// Kustomize writes these warnings to the standard error stream of the process
+// rather than returning them, so they are determined the way Kustomize does
+// instead: for the Kustomization file of the overlay and of every local
+// resource, component, and base it refers to, directly or indirectly. Each
+// warning names the Kustomization file it applies to, relative to the working
+// directory. Kustomization files that cannot be read or parsed are skipped, as
+// Kustomize reports those well enough on its own.
+func kustomizationWarnings(workDir, path string) []string {
+	var warnings []string
+	visited := map[string]struct{}{}
+	queue := []string{filepath.Clean(path)}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[dir]; ok {
+			continue
+		}
+		visited[dir] = struct{}{}
+
+		kusPath, err := findKustomization(workDir, dir)
+		if err != nil {
+			continue
+		}
+		b, err := os.ReadFile(kusPath)
+		if err != nil {
+			continue
+		}
+		var kus kustypes.Kustomization
+		if err = yaml.Unmarshal(b, &kus); err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(workDir, kusPath)
+		if err != nil {
+			rel = filepath.Join(dir, filepath.Base(kusPath))
+		}
+		for _, msg := range *kus.CheckDeprecatedFields() {
+			warnings = appendRenderWarnings(
+				warnings,
+				fmt.Sprintf("%s: %s", rel, strings.TrimPrefix(msg, "# ")),
+			)
+		}
+
+		refs := slices.Concat(kus.Resources, kus.Components, kus.Bases) // nolint: staticcheck
+		for _, ref := range refs {
+			if isRemoteKustomizationRef(ref) {
+				continue
+			}
+			target := filepath.Join(workDir, dir, ref)
+			if !isSubPath(workDir, target) {
+				continue
+			}
+			if fi, err := os.Stat(target); err != nil || !fi.IsDir() {
+				continue
+			}
+			if rel, err := filepath.Rel(workDir, target); err == nil {
+				queue = append(queue, rel)
+			}
+		}
+	}
+	return warnings
+}
+
+// stderrWarnings returns the non-empty lines of the provided output that a
+// tool wrote to its standard error stream while succeeding. Tools that render
+// manifests only write to it to warn about something, so every such line is
+// considered a warning.
+func stderrWarnings(stderr []byte) []string {
+	var warnings []string
+	for _, line := range strings.Split(string(stderr), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			warnings = appendRenderWarnings(warnings, line)
+		}
+	}
+	return warnings
+}
+
+// appendRenderWarnings appends those of the provided warnings to the provided
+// list that it does not contain yet, so that warnings emitted every time the
+// same manifests are rendered are only recorded once.
+func appendRenderWarnings(list []string, warnings ...string) []string {
+	for _, warning := range warnings {
+		if !slices.Contains(list, warning) {
+			list = append(list, warning)
+		}
+	}
+	return list
+}
+
+// strictRenderError returns a terminal error wrapping errRenderWarnings that
+// lists the provided warnings if the provided PromotionStepContext demands
+// strict rendering and there are any. Otherwise, it returns nil. The error is
+// terminal, as rendering the same manifests again produces the same warnings.
+func strictRenderError(stepCtx *PromotionStepContext, warnings []string) error {
+	if !stepCtx.StrictRender || len(warnings) == 0 {
+		return nil
+	}
+	return &terminalError{err: fmt.Errorf(
+		"%w: rendering emitted warnings and the Stage demands strict rendering: %s",
+		errRenderWarnings, strings.Join(warnings, "; "),
+	)}
+}
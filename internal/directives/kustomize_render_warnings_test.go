@@ -0,0 +1,104 @@
+package directives
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDeprecatedKustomization writes an overlay to the "overlay" directory of
+// the provided directory that refers to a base which uses the deprecated
+// commonLabels field.
+func setupDeprecatedKustomization(t *testing.T, dir string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "base"), 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "overlay"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base", "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+commonLabels:
+  app: test
+resources:
+- deployment.yaml
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base", "deployment.yaml"), []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "overlay", "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../base
+`), 0o600))
+}
+
+func Test_kustomizationWarnings(t *testing.T) {
+	t.Run("deprecated fields of referenced kustomizations", func(t *testing.T) {
+		dir := t.TempDir()
+		setupDeprecatedKustomization(t, dir)
+		// A second reference to the same base does not duplicate its warnings.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "overlay", "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../base
+- ../base/
+patchesStrategicMerge:
+- patch.yaml
+`), 0o600))
+
+		warnings := kustomizationWarnings(dir, "overlay")
+		require.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "overlay/kustomization.yaml: Warning: 'patchesStrategicMerge' is deprecated.")
+		assert.Contains(t, warnings[1], "base/kustomization.yaml: Warning: 'commonLabels' is deprecated.")
+	})
+
+	t.Run("no deprecated fields", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+- https://github.com/example/repo//base
+`), 0o600))
+		assert.Empty(t, kustomizationWarnings(dir, "."))
+	})
+
+	t.Run("references outside of the working directory are ignored", func(t *testing.T) {
+		dir := t.TempDir()
+		setupDeprecatedKustomization(t, dir)
+		assert.Empty(t, kustomizationWarnings(filepath.Join(dir, "overlay"), "."))
+	})
+}
+
+func Test_stderrWarnings(t *testing.T) {
+	assert.Nil(t, stderrWarnings(nil))
+	assert.Nil(t, stderrWarnings([]byte("\n  \n")))
+	assert.Equal(
+		t,
+		[]string{"# Warning: 'vars' is deprecated.", "walk.go:42: unexpected field"},
+		stderrWarnings([]byte(
+			"# Warning: 'vars' is deprecated.\n\nwalk.go:42: unexpected field\n"+
+				"# Warning: 'vars' is deprecated.\n",
+		)),
+	)
+}
+
+func Test_strictRenderError(t *testing.T) {
+	warnings := []string{"foo", "bar"}
+
+	assert.NoError(t, strictRenderError(&PromotionStepContext{}, warnings))
+	assert.NoError(t, strictRenderError(&PromotionStepContext{StrictRender: true}, nil))
+
+	err := strictRenderError(&PromotionStepContext{StrictRender: true}, warnings)
+	require.ErrorIs(t, err, errRenderWarnings)
+	require.ErrorContains(t, err, "foo; bar")
+	assert.True(t, isTerminal(err))
+}
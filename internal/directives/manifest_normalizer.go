@@ -0,0 +1,159 @@
+package directives
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// manifestPostProcessors returns the post-processors that normalize rendered
+// manifests as described by the provided configuration, in the order in which
+// they are to be applied. Fields are stripped before keys are sorted, so that
+// sorting does not visit them.
+func manifestPostProcessors(cfg *Normalize) []kio.Filter {
+	if cfg == nil {
+		return nil
+	}
+	var filters []kio.Filter
+	if cfg.StripFields {
+		filters = append(filters, kio.FilterFunc(stripFields))
+	}
+	if cfg.SortKeys {
+		filters = append(filters, kio.FilterFunc(sortKeys))
+	}
+	if cfg.SortDocuments {
+		filters = append(filters, kio.FilterFunc(sortDocuments))
+	}
+	return filters
+}
+
+// normalizeManifests normalizes the provided stream of YAML documents as
+// described by the provided configuration. If any normalization is
+// configured, the documents are re-encoded, which normalizes their
+// indentation, quoting, and the separators between them as well. Otherwise,
+// the documents are returned as is, so that the output of renderers can be
+// kept byte-for-byte.
+func normalizeManifests(b []byte, cfg *Normalize) ([]byte, error) {
+	if cfg == nil {
+		return b, nil
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(b),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range manifestPostProcessors(cfg) {
+		if nodes, err = filter.Filter(nodes); err != nil {
+			return nil, err
+		}
+	}
+	var out bytes.Buffer
+	if err = (kio.ByteWriter{Writer: &out}).Write(nodes); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// stripFields removes fields that are managed by the cluster, or that carry no
+// information, from the provided documents: status, metadata.creationTimestamp,
+// metadata.managedFields, and any field whose value is null. Empty maps and
+// lists are retained, since some are meaningful, e.g. emptyDir: {}.
+func stripFields(nodes []*kyaml.RNode) ([]*kyaml.RNode, error) {
+	for _, node := range nodes {
+		if _, err := node.Pipe(kyaml.Clear("status")); err != nil {
+			return nil, err
+		}
+		metadata, err := node.Pipe(kyaml.Lookup(kyaml.MetadataField))
+		if err != nil {
+			return nil, err
+		}
+		if metadata != nil {
+			for _, field := range []string{"creationTimestamp", "managedFields"} {
+				if _, err = metadata.Pipe(kyaml.Clear(field)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		stripNullFields(node.YNode())
+	}
+	return nodes, nil
+}
+
+// stripNullFields recursively removes the fields whose value is null from the
+// maps within the provided node.
+func stripNullFields(node *kyaml.Node) {
+	if node.Kind == kyaml.MappingNode {
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i+1].Tag == kyaml.NodeTagNull {
+				continue
+			}
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+		node.Content = content
+	}
+	for _, child := range node.Content {
+		stripNullFields(child)
+	}
+}
+
+// sortKeys orders the keys of every map within the provided documents
+// alphabetically.
+func sortKeys(nodes []*kyaml.RNode) ([]*kyaml.RNode, error) {
+	for _, node := range nodes {
+		sortMappingKeys(node.YNode())
+	}
+	return nodes, nil
+}
+
+// sortMappingKeys recursively orders the keys of the maps within the provided
+// node alphabetically.
+func sortMappingKeys(node *kyaml.Node) {
+	if node.Kind == kyaml.MappingNode {
+		pairs := make([][2]*kyaml.Node, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, [2]*kyaml.Node{node.Content[i], node.Content[i+1]})
+		}
+		slices.SortStableFunc(pairs, func(a, b [2]*kyaml.Node) int {
+			return strings.Compare(a[0].Value, b[0].Value)
+		})
+		node.Content = node.Content[:0]
+		for _, pair := range pairs {
+			node.Content = append(node.Content, pair[0], pair[1])
+		}
+	}
+	for _, child := range node.Content {
+		sortMappingKeys(child)
+	}
+}
+
+// sortDocuments orders the provided documents by their API group, kind,
+// namespace, and name. Documents that are identical in all of these retain
+// their relative order.
+func sortDocuments(nodes []*kyaml.RNode) ([]*kyaml.RNode, error) {
+	slices.SortStableFunc(nodes, func(a, b *kyaml.RNode) int {
+		return cmp.Or(
+			strings.Compare(apiGroup(a.GetApiVersion()), apiGroup(b.GetApiVersion())),
+			strings.Compare(a.GetKind(), b.GetKind()),
+			strings.Compare(a.GetNamespace(), b.GetNamespace()),
+			strings.Compare(a.GetName(), b.GetName()),
+		)
+	})
+	return nodes, nil
+}
+
+// apiGroup returns the group of the provided API version, which is empty for
+// the core group.
+func apiGroup(apiVersion string) string {
+	group, _, ok := strings.Cut(apiVersion, "/")
+	if !ok {
+		return ""
+	}
+	return group
+}
@@ -0,0 +1,165 @@
+package directives
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_normalizeManifests(t *testing.T) {
+	const manifests = `kind: ConfigMap
+apiVersion: v1
+metadata:
+    name: b
+    creationTimestamp: null
+data:
+    key: value
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+    name: a
+status:
+    replicas: 1
+`
+	testCases := []struct {
+		name     string
+		cfg      *Normalize
+		expected string
+	}{
+		{
+			name:     "no normalization",
+			expected: manifests,
+		},
+		{
+			name: "re-encoding only",
+			cfg:  &Normalize{},
+			expected: `kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: b
+  creationTimestamp: null
+data:
+  key: value
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: a
+status:
+  replicas: 1
+`,
+		},
+		{
+			name: "strip fields",
+			cfg:  &Normalize{StripFields: true},
+			expected: `kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: b
+data:
+  key: value
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: a
+`,
+		},
+		{
+			name: "sort keys",
+			cfg:  &Normalize{SortKeys: true},
+			expected: `apiVersion: v1
+data:
+  key: value
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  name: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+status:
+  replicas: 1
+`,
+		},
+		{
+			name: "sort documents",
+			cfg:  &Normalize{SortDocuments: true},
+			expected: `kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: b
+  creationTimestamp: null
+data:
+  key: value
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: a
+status:
+  replicas: 1
+`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			b, err := normalizeManifests([]byte(manifests), testCase.cfg)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, string(b))
+		})
+	}
+}
+
+func Test_sortDocuments(t *testing.T) {
+	b, err := normalizeManifests([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+  namespace: z
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: c
+  namespace: y
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+`), &Normalize{SortDocuments: true})
+	require.NoError(t, err)
+	// The core group sorts before named groups, and resources without a
+	// namespace sort before namespaced ones.
+	require.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: c
+  namespace: y
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+  namespace: z
+`, string(b))
+}
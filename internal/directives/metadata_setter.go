@@ -0,0 +1,345 @@
+package directives
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/xeipuuv/gojsonschema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// defaultMetadataKinds are the kinds of resources that the set-metadata step
+// sets labels and annotations on if its configuration does not specify any.
+var defaultMetadataKinds = []string{
+	"CronJob",
+	"DaemonSet",
+	"Deployment",
+	"Job",
+	"ReplicaSet",
+	"StatefulSet",
+}
+
+func init() {
+	builtins.RegisterPromotionStepRunner(newMetadataSetter(), nil)
+}
+
+// metadataSetter is an implementation of the PromotionStepRunner interface
+// that sets labels and annotations on rendered manifests.
+type metadataSetter struct {
+	schemaLoader gojsonschema.JSONLoader
+	nowFn        func() time.Time
+}
+
+// newMetadataSetter returns an implementation of the PromotionStepRunner
+// interface that sets labels and annotations on rendered manifests.
+func newMetadataSetter() PromotionStepRunner {
+	r := &metadataSetter{nowFn: time.Now}
+	r.schemaLoader = getConfigSchemaLoader(r.Name())
+	return r
+}
+
+// Name implements the PromotionStepRunner interface.
+func (m *metadataSetter) Name() string {
+	return "set-metadata"
+}
+
+// RunPromotionStep implements the PromotionStepRunner interface.
+func (m *metadataSetter) RunPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+) (PromotionStepResult, error) {
+	// Validate the configuration against the JSON Schema.
+	if err := validate(m.schemaLoader, gojsonschema.NewGoLoader(stepCtx.Config), m.Name()); err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+	}
+
+	// Convert the configuration into a typed object.
+	cfg, err := ConfigToStruct[SetMetadataConfig](stepCtx.Config)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not convert config into %s config: %w", m.Name(), err)
+	}
+
+	return m.runPromotionStep(ctx, stepCtx, cfg)
+}
+
+// metadataTemplateData is the data that the templates of the labels and
+// annotations set by the set-metadata step are executed with. It embeds
+// promotionMetadata, so that, e.g., .Images and .SourceCommit are available.
+type metadataTemplateData struct {
+	promotionMetadata
+	// Promotion is the name of the Promotion.
+	Promotion string
+	// PromotedAt is the time at which the step was executed, in RFC 3339
+	// format.
+	PromotedAt string
+	// Image is the tag, or if there is no tag, the digest, of the image
+	// referenced by the promoted Freight. It is only set if exactly one image
+	// is referenced.
+	Image string
+}
+
+func (m *metadataSetter) runPromotionStep(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	cfg SetMetadataConfig,
+) (PromotionStepResult, error) {
+	data := metadataTemplateData{
+		promotionMetadata: buildPromotionMetadata(stepCtx),
+		Promotion:         stepCtx.Promotion,
+		PromotedAt:        m.nowFn().UTC().Format(time.RFC3339),
+	}
+	if len(data.Images) == 1 {
+		for _, version := range data.Images {
+			data.Image = version
+		}
+	}
+
+	labels, err := renderMetadata(cfg.Labels, data, validation.IsValidLabelValue)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf("error rendering labels: %w", err)}
+	}
+	annotations, err := renderMetadata(cfg.Annotations, data, nil)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseFailed},
+			&terminalError{err: fmt.Errorf("error rendering annotations: %w", err)}
+	}
+
+	kinds := cfg.Kinds
+	if len(kinds) == 0 {
+		kinds = defaultMetadataKinds
+	}
+
+	path, err := securejoin.SecureJoin(stepCtx.WorkDir, cfg.Path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("could not secure join path %q: %w", cfg.Path, err)
+	}
+	files, err := manifestFiles(path)
+	if err != nil {
+		return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+			fmt.Errorf("error finding manifests in %q: %w", cfg.Path, sanitizePathError(err, stepCtx.WorkDir))
+	}
+
+	logger := logging.LoggerFromContext(ctx)
+	for _, file := range files {
+		if err = setMetadataInFile(file, kinds, labels, annotations, logger); err != nil {
+			return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+				fmt.Errorf("error setting metadata in %q: %w", cfg.Path, sanitizePathError(err, stepCtx.WorkDir))
+		}
+	}
+	return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+}
+
+// renderMetadata executes the provided templates, keyed by the label or
+// annotation they are the value of, with the provided data. Keys must be
+// qualified names. If validateValue is not nil, it is used to validate each
+// rendered value.
+func renderMetadata(
+	templates map[string]string,
+	data metadataTemplateData,
+	validateValue func(string) []string,
+) (map[string]string, error) {
+	rendered := make(map[string]string, len(templates))
+	for key, text := range templates {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template of %q: %w", key, err)
+		}
+		var sb strings.Builder
+		if err = tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("error executing template of %q: %w", key, err)
+		}
+		value := strings.TrimSpace(sb.String())
+		if validateValue != nil {
+			if errs := validateValue(value); len(errs) > 0 {
+				return nil, fmt.Errorf(
+					"template of %q produced invalid value %q: %s",
+					key, value, strings.Join(errs, "; "),
+				)
+			}
+		}
+		rendered[key] = value
+	}
+	return rendered, nil
+}
+
+// manifestFiles returns the provided path if it is a file, or the YAML files
+// in the directory at the provided path and its subdirectories, in lexical
+// order.
+func manifestFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		switch filepath.Ext(p) {
+		case ".yaml", ".yml":
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// setMetadataInFile sets the provided labels and annotations on each resource
+// of one of the provided kinds in the YAML file at the provided path. Only the
+// metadata of the resources themselves is changed, not that of, e.g., the Pod
+// templates of workloads, and labels that the selector of a resource relies on
+// are never changed, since selectors are often immutable. The file is only
+// rewritten if any of its resources changed.
+func setMetadataInFile(
+	path string,
+	kinds []string,
+	labels map[string]string,
+	annotations map[string]string,
+	logger *logging.Logger,
+) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(b),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", filepath.Base(path), err)
+	}
+
+	var changed bool
+	for _, node := range nodes {
+		if !slices.Contains(kinds, node.GetKind()) {
+			continue
+		}
+		selector, err := selectorLabels(node)
+		if err != nil {
+			return fmt.Errorf(
+				"error reading selector of %s %q: %w", node.GetKind(), node.GetName(), err,
+			)
+		}
+		current := node.GetLabels()
+		for _, key := range slices.Sorted(maps.Keys(labels)) {
+			if value, ok := current[key]; ok && value == labels[key] {
+				continue
+			}
+			if _, ok := selector[key]; ok {
+				logger.Debug(
+					"not setting label used by selector",
+					"kind", node.GetKind(),
+					"name", node.GetName(),
+					"label", key,
+				)
+				continue
+			}
+			if err = node.PipeE(kyaml.SetLabel(key, labels[key])); err != nil {
+				return fmt.Errorf(
+					"error setting label %q of %s %q: %w", key, node.GetKind(), node.GetName(), err,
+				)
+			}
+			changed = true
+		}
+		current = node.GetAnnotations()
+		for _, key := range slices.Sorted(maps.Keys(annotations)) {
+			if value, ok := current[key]; ok && value == annotations[key] {
+				continue
+			}
+			if err = node.PipeE(kyaml.SetAnnotation(key, annotations[key])); err != nil {
+				return fmt.Errorf(
+					"error setting annotation %q of %s %q: %w", key, node.GetKind(), node.GetName(), err,
+				)
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	var out bytes.Buffer
+	if err = (kio.ByteWriter{Writer: &out}).Write(nodes); err != nil {
+		return fmt.Errorf("error writing %s: %w", filepath.Base(path), err)
+	}
+	return os.WriteFile(path, out.Bytes(), fi.Mode().Perm())
+}
+
+// selectorLabels returns the labels that the selector of the provided resource
+// matches, whether it is a label selector, as used by, e.g., Deployments, or
+// a plain map, as used by, e.g., ReplicationControllers. The selectors of the
+// Job templates of CronJobs are considered too.
+func selectorLabels(node *kyaml.RNode) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, path := range [][]string{
+		{"spec", "selector"},
+		{"spec", "jobTemplate", "spec", "selector"},
+	} {
+		selector, err := node.Pipe(kyaml.Lookup(path...))
+		if err != nil {
+			return nil, err
+		}
+		if selector == nil {
+			continue
+		}
+		matchLabels, err := selector.Pipe(kyaml.Lookup("matchLabels"))
+		if err != nil {
+			return nil, err
+		}
+		if matchLabels == nil && selector.Field("matchExpressions") == nil {
+			// The selector is a plain map of labels.
+			matchLabels = selector
+		}
+		if matchLabels != nil {
+			if err = matchLabels.VisitFields(func(n *kyaml.MapNode) error {
+				labels[n.Key.YNode().Value] = n.Value.YNode().Value
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if exprs := selector.Field("matchExpressions"); exprs != nil {
+			elements, err := exprs.Value.Elements()
+			if err != nil {
+				return nil, err
+			}
+			for _, expr := range elements {
+				if key := expr.Field("key"); key != nil {
+					labels[key.Value.YNode().Value] = ""
+				}
+			}
+		}
+	}
+	return labels, nil
+}
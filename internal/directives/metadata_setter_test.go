@@ -0,0 +1,206 @@
+package directives
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_metadataSetter_runPromotionStep(t *testing.T) {
+	// Each test case names a directory in testdata/set-metadata, holding the
+	// manifests the step is run on, in input, and the manifests it is expected
+	// to produce, in expected.
+	testCases := []struct {
+		name string
+		cfg  SetMetadataConfig
+	}{
+		{
+			name: "defaults",
+			cfg: SetMetadataConfig{
+				Path: "manifests.yaml",
+				Labels: map[string]string{
+					"app.kubernetes.io/version": "{{ .Image }}",
+					"app":                       "{{ .Stage }}",
+				},
+				Annotations: map[string]string{
+					"kargo.akuity.io/promoted-at": "{{ .PromotedAt }}",
+					"kargo.akuity.io/promotion":   "{{ .Project }}/{{ .Promotion }}",
+				},
+			},
+		},
+		{
+			name: "kinds",
+			cfg: SetMetadataConfig{
+				Path:  "manifests.yaml",
+				Kinds: []string{"Service"},
+				Labels: map[string]string{
+					"app":                       "{{ .Stage }}",
+					"app.kubernetes.io/part-of": "{{ .Project }}",
+				},
+			},
+		},
+		{
+			name: "directory",
+			cfg: SetMetadataConfig{
+				Path: ".",
+				Labels: map[string]string{
+					"app.kubernetes.io/version": "{{ .Image }}",
+				},
+				Annotations: map[string]string{
+					"kargo.akuity.io/promoted-at": "{{ .PromotedAt }}",
+				},
+				Kinds: []string{"ConfigMap", "StatefulSet"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testDir := filepath.Join("testdata", "set-metadata", testCase.name)
+			workDir := t.TempDir()
+			require.NoError(t, os.CopyFS(workDir, os.DirFS(filepath.Join(testDir, "input"))))
+
+			runner := &metadataSetter{
+				nowFn: func() time.Time {
+					return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+				},
+			}
+			result, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{
+					WorkDir:   workDir,
+					Project:   "guestbook",
+					Stage:     "prod",
+					Promotion: "prod.01hxyz",
+					Freight: kargoapi.FreightCollection{
+						Freight: map[string]kargoapi.FreightReference{
+							"Warehouse/guestbook": {
+								Images: []kargoapi.Image{{
+									RepoURL: "ghcr.io/example/guestbook",
+									Tag:     "v1.2.3",
+								}},
+							},
+						},
+					},
+				},
+				testCase.cfg,
+			)
+			require.NoError(t, err)
+			require.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+
+			expectedDir := filepath.Join(testDir, "expected")
+			require.NoError(t, filepath.WalkDir(
+				expectedDir,
+				func(path string, d fs.DirEntry, err error) error {
+					require.NoError(t, err)
+					if d.IsDir() {
+						return nil
+					}
+					rel, err := filepath.Rel(expectedDir, path)
+					require.NoError(t, err)
+					expected, err := os.ReadFile(path)
+					require.NoError(t, err)
+					actual, err := os.ReadFile(filepath.Join(workDir, rel))
+					require.NoError(t, err)
+					require.Equal(t, string(expected), string(actual), rel)
+					return nil
+				},
+			))
+		})
+	}
+}
+
+func Test_metadataSetter_runPromotionStep_errors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		cfg        SetMetadataConfig
+		assertions func(*testing.T, PromotionStepResult, error)
+	}{
+		{
+			name: "invalid label key",
+			cfg: SetMetadataConfig{
+				Path:   "manifests.yaml",
+				Labels: map[string]string{"not a key": "value"},
+			},
+			assertions: func(t *testing.T, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `invalid key "not a key"`)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, result.Status)
+			},
+		},
+		{
+			name: "invalid label value",
+			cfg: SetMetadataConfig{
+				Path:   "manifests.yaml",
+				Labels: map[string]string{"promoted-at": "{{ .PromotedAt }}"},
+			},
+			assertions: func(t *testing.T, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `template of "promoted-at" produced invalid value`)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, result.Status)
+			},
+		},
+		{
+			name: "unknown template field",
+			cfg: SetMetadataConfig{
+				Path:        "manifests.yaml",
+				Annotations: map[string]string{"example.com/tag": "{{ .Tag }}"},
+			},
+			assertions: func(t *testing.T, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `error executing template of "example.com/tag"`)
+				require.True(t, isTerminal(err))
+				require.Equal(t, kargoapi.PromotionPhaseFailed, result.Status)
+			},
+		},
+		{
+			name: "path does not exist",
+			cfg: SetMetadataConfig{
+				Path:   "missing.yaml",
+				Labels: map[string]string{"app": "guestbook"},
+			},
+			assertions: func(t *testing.T, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, `error finding manifests in "missing.yaml"`)
+				require.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+			},
+		},
+		{
+			name: "invalid YAML",
+			cfg: SetMetadataConfig{
+				Path:   "invalid.yaml",
+				Labels: map[string]string{"app": "guestbook"},
+			},
+			assertions: func(t *testing.T, result PromotionStepResult, err error) {
+				require.ErrorContains(t, err, "error parsing invalid.yaml")
+				require.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			require.NoError(t, os.WriteFile(
+				filepath.Join(workDir, "manifests.yaml"),
+				[]byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: guestbook\n"),
+				0o600,
+			))
+			require.NoError(t, os.WriteFile(
+				filepath.Join(workDir, "invalid.yaml"),
+				[]byte("kind: [Deployment\n"),
+				0o600,
+			))
+			runner := &metadataSetter{nowFn: time.Now}
+			result, err := runner.runPromotionStep(
+				context.Background(),
+				&PromotionStepContext{WorkDir: workDir},
+				testCase.cfg,
+			)
+			testCase.assertions(t, result, err)
+		})
+	}
+}
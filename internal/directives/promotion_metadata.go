@@ -0,0 +1,124 @@
+package directives
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+// promotionMetadataSchemaVersion is the version of the schema of the file
+// written by writePromotionMetadata. It must be incremented whenever a field is
+// removed or its meaning changes. Adding fields does not require a new version.
+const promotionMetadataSchemaVersion = "v1"
+
+// promotionMetadata describes what was promoted to a Stage. It is written to a
+// file alongside rendered manifests, in a form that can be consumed by the Argo
+// CD ApplicationSet Git file generator. That generator flattens nested objects
+// into keys delimited by dots, so everything of interest to most templates is
+// available at the top level.
+type promotionMetadata struct {
+	// SchemaVersion is the version of the schema of this file.
+	SchemaVersion string `json:"schemaVersion"`
+	// Project is the name of the Project the Stage belongs to.
+	Project string `json:"project"`
+	// Stage is the name of the Stage that was promoted to.
+	Stage string `json:"stage"`
+	// SourceCommit is the ID of the Git commit referenced by the promoted
+	// Freight. It is only set if exactly one commit is referenced.
+	SourceCommit string `json:"sourceCommit,omitempty"`
+	// SourceCommits maps the URL of each Git repository referenced by the
+	// promoted Freight to the ID of the commit that is referenced.
+	SourceCommits map[string]string `json:"sourceCommits,omitempty"`
+	// Images maps the URL of each image repository referenced by the promoted
+	// Freight to the tag of the image that is referenced or, if there is no
+	// tag, its digest.
+	Images map[string]string `json:"images,omitempty"`
+	// Charts maps a reference to each Helm chart referenced by the promoted
+	// Freight, in the form <repoURL>/<name> or, for charts in OCI registries,
+	// <repoURL>, to the version of the chart that is referenced.
+	Charts map[string]string `json:"charts,omitempty"`
+	// Tools maps the name of each tool that previous steps of the Promotion
+	// rendered manifests with, e.g. "kustomize", to the version that was used.
+	Tools map[string]string `json:"tools,omitempty"`
+}
+
+// buildPromotionMetadata returns promotionMetadata describing the Freight
+// referenced by the Promotion that the provided PromotionStepContext belongs
+// to, along with the versions of the tools that manifests were rendered with.
+// Nothing specific to the Promotion itself, such as its name, is included, so
+// the same Freight promoted twice yields identical metadata.
+func buildPromotionMetadata(stepCtx *PromotionStepContext) promotionMetadata {
+	md := promotionMetadata{
+		SchemaVersion: promotionMetadataSchemaVersion,
+		Project:       stepCtx.Project,
+		Stage:         stepCtx.Stage,
+	}
+	for _, ref := range stepCtx.Freight.References() {
+		for _, image := range ref.Images {
+			version := image.Tag
+			if version == "" {
+				version = image.Digest
+			}
+			if version == "" {
+				continue
+			}
+			if md.Images == nil {
+				md.Images = map[string]string{}
+			}
+			md.Images[image.RepoURL] = version
+		}
+		for _, chart := range ref.Charts {
+			if chart.Version == "" {
+				continue
+			}
+			if md.Charts == nil {
+				md.Charts = map[string]string{}
+			}
+			md.Charts[chartReference(chart)] = chart.Version
+		}
+		for _, commit := range ref.Commits {
+			if commit.ID == "" {
+				continue
+			}
+			if md.SourceCommits == nil {
+				md.SourceCommits = map[string]string{}
+			}
+			md.SourceCommits[commit.RepoURL] = commit.ID
+		}
+	}
+	md.Tools = usedToolVersions(stepCtx.SharedState)
+	if len(md.SourceCommits) == 1 {
+		for _, id := range md.SourceCommits {
+			md.SourceCommit = id
+		}
+	}
+	return md
+}
+
+// writePromotionMetadata writes promotionMetadata for the Promotion that the
+// provided PromotionStepContext belongs to, to the specified file, relative to
+// the specified directory.
+func writePromotionMetadata(
+	stepCtx *PromotionStepContext,
+	dir string,
+	file string,
+) error {
+	path, err := securejoin.SecureJoin(dir, file)
+	if err != nil {
+		return fmt.Errorf("error joining path %s with %s: %w", file, dir, err)
+	}
+	data, err := json.MarshalIndent(buildPromotionMetadata(stepCtx), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling promotion metadata: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", file, err)
+	}
+	if err = os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("error writing promotion metadata to %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package directives
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_buildPromotionMetadata(t *testing.T) {
+	testCases := []struct {
+		name     string
+		freight  []kargoapi.FreightReference
+		state    State
+		expected string
+	}{
+		{
+			name: "no Freight",
+			expected: `{
+				"schemaVersion": "v1",
+				"project": "fake-project",
+				"stage": "fake-stage"
+			}`,
+		},
+		{
+			name: "single source commit",
+			freight: []kargoapi.FreightReference{{
+				Name: "fake-freight",
+				Commits: []kargoapi.GitCommit{{
+					RepoURL: "https://github.com/example/repo.git",
+					ID:      "abc123",
+				}},
+				Images: []kargoapi.Image{
+					{RepoURL: "docker.io/example/app", Tag: "v1.2.3"},
+					{RepoURL: "ghcr.io/example/sidecar", Digest: "sha256:deadbeef"},
+				},
+			}},
+			expected: `{
+				"schemaVersion": "v1",
+				"project": "fake-project",
+				"stage": "fake-stage",
+				"sourceCommit": "abc123",
+				"sourceCommits": {
+					"https://github.com/example/repo.git": "abc123"
+				},
+				"images": {
+					"docker.io/example/app": "v1.2.3",
+					"ghcr.io/example/sidecar": "sha256:deadbeef"
+				}
+			}`,
+		},
+		{
+			name: "charts",
+			freight: []kargoapi.FreightReference{{
+				Name: "fake-freight",
+				Charts: []kargoapi.Chart{
+					{RepoURL: "oci://ghcr.io/example/charts/app", Version: "1.4.2"},
+					{RepoURL: "https://charts.example.com", Name: "sidecar", Version: "2.0.0"},
+				},
+			}},
+			expected: `{
+				"schemaVersion": "v1",
+				"project": "fake-project",
+				"stage": "fake-stage",
+				"charts": {
+					"oci://ghcr.io/example/charts/app": "1.4.2",
+					"https://charts.example.com/sidecar": "2.0.0"
+				}
+			}`,
+		},
+		{
+			name: "multiple source commits",
+			freight: []kargoapi.FreightReference{
+				{
+					Name:   "fake-freight",
+					Origin: kargoapi.FreightOrigin{Kind: kargoapi.FreightOriginKindWarehouse, Name: "base"},
+					Commits: []kargoapi.GitCommit{{
+						RepoURL: "https://github.com/example/repo.git",
+						ID:      "abc123",
+					}},
+				},
+				{
+					Name:   "other-freight",
+					Origin: kargoapi.FreightOrigin{Kind: kargoapi.FreightOriginKindWarehouse, Name: "overlay"},
+					Commits: []kargoapi.GitCommit{{
+						RepoURL: "https://github.com/example/other.git",
+						ID:      "def456",
+					}},
+				},
+			},
+			expected: `{
+				"schemaVersion": "v1",
+				"project": "fake-project",
+				"stage": "fake-stage",
+				"sourceCommits": {
+					"https://github.com/example/repo.git": "abc123",
+					"https://github.com/example/other.git": "def456"
+				}
+			}`,
+		},
+		{
+			name: "tool versions",
+			state: State{
+				"build-app": map[string]any{
+					toolVersionsOutputKey: map[string]any{"kustomize": "v5.4.3", "helm": "v3.15.0"},
+				},
+				"build-sidecar": map[string]any{
+					toolVersionsOutputKey: map[string]any{"kustomize": "v5.5.0"},
+				},
+				"render-chart": map[string]any{
+					toolVersionsOutputKey: map[string]any{"helm": "v3.15.0"},
+				},
+				"commit": map[string]any{"commit": "abc123"},
+			},
+			expected: `{
+				"schemaVersion": "v1",
+				"project": "fake-project",
+				"stage": "fake-stage",
+				"tools": {
+					"helm": "v3.15.0",
+					"kustomize": "v5.4.3,v5.5.0"
+				}
+			}`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stepCtx := &PromotionStepContext{
+				Project:     "fake-project",
+				Stage:       "fake-stage",
+				Freight:     kargoapi.FreightCollection{},
+				SharedState: testCase.state,
+			}
+			stepCtx.Freight.UpdateOrPush(testCase.freight...)
+			data, err := json.Marshal(buildPromotionMetadata(stepCtx))
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.expected, string(data))
+		})
+	}
+}
+
+func Test_writePromotionMetadata(t *testing.T) {
+	stepCtx := &PromotionStepContext{
+		Project: "fake-project",
+		Stage:   "fake-stage",
+		Freight: kargoapi.FreightCollection{},
+	}
+	stepCtx.Freight.UpdateOrPush(kargoapi.FreightReference{
+		Name: "fake-freight",
+		Commits: []kargoapi.GitCommit{{
+			RepoURL: "https://github.com/example/repo.git",
+			ID:      "abc123",
+		}},
+		Images: []kargoapi.Image{{RepoURL: "docker.io/example/app", Tag: "v1.2.3"}},
+	})
+	dir := t.TempDir()
+
+	require.NoError(t, writePromotionMetadata(stepCtx, dir, "meta/config.json"))
+	data, err := os.ReadFile(filepath.Join(dir, "meta", "config.json"))
+	require.NoError(t, err)
+
+	// The ApplicationSet Git file generator needs the values templates most
+	// commonly refer to to be available as top-level scalars.
+	md := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &md))
+	for _, key := range []string{"schemaVersion", "project", "stage", "sourceCommit"} {
+		require.IsType(t, "", md[key], key)
+	}
+	require.Equal(t, "fake-stage", md["stage"])
+	require.Equal(t, "abc123", md["sourceCommit"])
+
+	// Writing the same metadata again must not produce a change to commit
+	require.NoError(t, writePromotionMetadata(stepCtx, dir, "meta/config.json"))
+	rewritten, err := os.ReadFile(filepath.Join(dir, "meta", "config.json"))
+	require.NoError(t, err)
+	require.Equal(t, data, rewritten)
+
+	// The file cannot be written outside the directory
+	require.NoError(t, writePromotionMetadata(stepCtx, dir, "../config.json"))
+	require.FileExists(t, filepath.Join(dir, "config.json"))
+}
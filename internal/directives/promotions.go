@@ -7,15 +7,19 @@ import (
 	"time"
 
 	"github.com/expr-lang/expr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	yaml "sigs.k8s.io/yaml/goyaml.v3"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller"
 	"github.com/akuity/kargo/internal/controller/freight"
 	"github.com/akuity/kargo/internal/credentials"
 	"github.com/akuity/kargo/internal/expressions"
+	libgit "github.com/akuity/kargo/internal/git"
 	"github.com/akuity/kargo/internal/kargo"
+	"github.com/akuity/kargo/internal/tools"
 )
 
 // PromotionStepRunner is an interface for components that implement the logic for
@@ -42,6 +46,17 @@ type RetryableStepRunner interface {
 	DefaultErrorThreshold() uint32
 }
 
+// ConfigTimeoutStepRunner is an interface for RetryableStepRunners whose
+// default timeout depends upon how a step is configured.
+type ConfigTimeoutStepRunner interface {
+	// DefaultTimeoutForConfig returns the default timeout for a step with the
+	// provided (opaque JSON) configuration, or nil if the one returned by
+	// DefaultTimeout applies. As the configuration has not been evaluated yet,
+	// settings that are specified using expressions cannot be taken into
+	// account.
+	DefaultTimeoutForConfig([]byte) *time.Duration
+}
+
 // PromotionContext is the context of a user-defined promotion process that is
 // executed by the Engine.
 type PromotionContext struct {
@@ -82,6 +97,100 @@ type PromotionContext struct {
 	Vars []kargoapi.PromotionVariable
 	// Secrets is a map of secrets that can be used by the PromotionSteps.
 	Secrets map[string]map[string]string
+	// ServiceAccount is the optional name of a ServiceAccount in the Project
+	// namespace whose identity is assumed by the client for Argo CD resources
+	// that is provided to PromotionSteps, and by the client for the Kargo
+	// control plane that is provided to them as ServiceAccountClient.
+	ServiceAccount string
+	// ArgoCDContext is the name of the Argo CD context through which
+	// PromotionSteps interact with Argo CD. An empty name refers to the default
+	// context.
+	ArgoCDContext string
+	// ChildCreator creates objects on behalf of the Promotion, subject to the
+	// limit on the number of such objects.
+	ChildCreator *controller.ChildCreator
+	// Lanes configures the concurrent execution of PromotionSteps that are
+	// assigned to lanes.
+	Lanes *kargoapi.PromotionLanes
+	// RenderedBranch is the name of the branch that manifests rendered for the
+	// Stage are written to, as resolved from the Stage's RenderedBranch
+	// template. It is empty if the Stage does not specify a template.
+	RenderedBranch string
+	// Overlays are the overlays of the Stage, along with the branches that
+	// manifests rendered from them are written to and the commits that were
+	// pushed to them so far. It is empty if the Stage does not specify any
+	// overlays.
+	Overlays []kargoapi.PromotedOverlay
+	// RenderedBranchPush records the commits pushed to RenderedBranch so far,
+	// if any.
+	RenderedBranchPush *kargoapi.RenderedBranchPush
+	// RenderedBranchCommit records the commit that Kargo last pushed to
+	// RenderedBranch before the promotion began, if known.
+	RenderedBranchCommit *kargoapi.RenderedBranchCommit
+	// OnExternalModification is the policy that is applied if RenderedBranch is
+	// found not to point to the commit that Kargo last pushed to it.
+	OnExternalModification kargoapi.RenderedBranchExternalModificationPolicy
+	// StageMetadata is the free-form metadata of the Stage, which expressions
+	// can refer to using the stageMetadata() function.
+	StageMetadata map[string]string
+	// ForceSync indicates that Argo CD Applications are to be synced even if
+	// the manifests they are synced to did not change.
+	ForceSync bool
+	// FailureInjection is a synthetic failure that is to be injected into the
+	// execution of the promotion. It is only ever set in test environments.
+	FailureInjection *FailureInjection
+	// RepoPolicy restricts the Git repositories that PromotionSteps may look up
+	// credentials for. A nil policy allows all repositories.
+	RepoPolicy *libgit.RepoURLPolicy
+	// CommitMessageMaxImages is the maximum number of images PromotionSteps
+	// itemize in the commit messages they generate. A value of 0 means no
+	// limit.
+	CommitMessageMaxImages int
+	// ImageMappings are the Stage's rules for rewriting references to images
+	// before PromotionSteps write them to manifests.
+	ImageMappings []kargoapi.ImageMapping
+	// ToolVersions are the versions of the tools PromotionSteps render
+	// manifests with, as pinned by the Stage.
+	ToolVersions *kargoapi.ToolVersions
+	// ToolCache provides the binaries of pinned tool versions other than the
+	// ones embedded in Kargo. If it is nil, only the embedded versions are
+	// available.
+	ToolCache *tools.Cache
+	// ExecPolicy specifies the commands PromotionSteps may execute. A nil
+	// policy allows no commands.
+	ExecPolicy *kargoapi.ExecPolicy
+	// MaxRepoSize is the maximum size, in bytes, of the Git repositories that
+	// PromotionSteps clone. A value of 0 means no limit.
+	MaxRepoSize int64
+	// MaxRenderedOutputSize is the maximum size, in bytes, of the manifests
+	// that PromotionSteps render. A value of 0 means no limit.
+	MaxRenderedOutputSize int64
+	// RemoteBasePolicy restricts the hosts that PromotionSteps may fetch
+	// remote Kustomize bases from. A nil policy allows all hosts.
+	RemoteBasePolicy *kargoapi.RemoteBasePolicy
+	// DryRunApply is the Stage's permission for PromotionSteps to apply
+	// rendered manifests to the destination clusters of Argo CD Applications
+	// in dry-run mode, along with how they are applied. A nil value means
+	// that they may not.
+	DryRunApply *kargoapi.DryRunApply
+	// RetainedImages are the images subscribed to by the Warehouse the Freight
+	// originates from that the Freight does not carry, along with the versions
+	// of them that were found to be retained by the Stage so far.
+	RetainedImages []kargoapi.RetainedImage
+	// SourceUpdateBatching is the Stage's policy for accumulating the updates
+	// that PromotionSteps push to a branch of a Git repository on a rolling
+	// branch. It is nil if the Stage does not specify one.
+	SourceUpdateBatching *kargoapi.SourceUpdateBatching
+	// StrictRender specifies whether PromotionSteps fail when the tools they
+	// render manifests with emit warnings.
+	StrictRender bool
+	// RenderWarnings are the warnings that tools rendering manifests emitted
+	// so far over the course of the promotion.
+	RenderWarnings []string
+	// GitIdentity is the identity that PromotionSteps commit to Git
+	// repositories as, and the credentials they access them with, in place of
+	// the controller's defaults. It is nil if the Stage does not specify one.
+	GitIdentity *kargoapi.GitIdentity
 }
 
 // PromotionStep describes a single step in a user-defined promotion process.
@@ -98,6 +207,14 @@ type PromotionStep struct {
 	Alias string
 	// Retry is the retry configuration for the PromotionStep.
 	Retry *kargoapi.PromotionStepRetry
+	// ContinueOnError indicates whether the promotion process should continue
+	// with the next step if this step fails or errors after exhausting any
+	// retries.
+	ContinueOnError bool
+	// Lane is the optional name of the lane the PromotionStep is assigned to.
+	// Consecutive PromotionSteps that are assigned to lanes are executed
+	// concurrently with the PromotionSteps of other lanes.
+	Lane string
 	// Vars is a list of variables definitions that can be used by the
 	// PromotionStep.
 	Vars []kargoapi.PromotionVariable
@@ -153,11 +270,18 @@ func StepEnvWithTaskOutputs(alias string, outputs State) PromotionStepEnvOption
 // GetTimeout returns the maximum interval the provided runner may spend
 // attempting to execute the step before retries are abandoned and the entire
 // Promotion is marked as failed. If the runner is a RetryableStepRunner, its
-// timeout is used as the default. Otherwise, the default is 0 (no limit).
+// timeout is used as the default, unless the runner is also a
+// ConfigTimeoutStepRunner that returns a timeout for the step's configuration.
+// Otherwise, the default is 0 (no limit).
 func (s *PromotionStep) GetTimeout(runner any) *time.Duration {
 	fallback := ptr.To(time.Duration(0))
 	if retryCfg, isRetryable := runner.(RetryableStepRunner); isRetryable {
 		fallback = retryCfg.DefaultTimeout()
+		if cfgTimeout, ok := runner.(ConfigTimeoutStepRunner); ok {
+			if timeout := cfgTimeout.DefaultTimeoutForConfig(s.Config); timeout != nil {
+				fallback = timeout
+			}
+		}
 	}
 	return s.Retry.GetTimeout(fallback)
 }
@@ -187,9 +311,10 @@ func (s *PromotionStep) BuildEnv(
 ) map[string]any {
 	env := map[string]any{
 		"ctx": map[string]any{
-			"project":   promoCtx.Project,
-			"promotion": promoCtx.Promotion,
-			"stage":     promoCtx.Stage,
+			"project":        promoCtx.Project,
+			"promotion":      promoCtx.Promotion,
+			"stage":          promoCtx.Stage,
+			"renderedBranch": promoCtx.RenderedBranch,
 		},
 	}
 
@@ -251,6 +376,11 @@ func (s *PromotionStep) GetConfig(
 			new(func(repoURL string, origin kargoapi.FreightOrigin) kargoapi.Chart),
 			new(func(repoURL string) kargoapi.Chart),
 		),
+		expr.Function(
+			"stageMetadata",
+			getStageMetadataFunc(promoCtx),
+			new(func(key string) string),
+		),
 	)
 	if err != nil {
 		return nil, err
@@ -329,6 +459,43 @@ type PromotionResult struct {
 	StepExecutionMetadata kargoapi.StepExecutionMetadataList
 	// State is the current state of the promotion process.
 	State State
+	// RepoPolicyDecisions records the decisions made by the RepoPolicy of the
+	// PromotionContext for the Git repositories that PromotionSteps looked up
+	// credentials for.
+	RepoPolicyDecisions []kargoapi.RepoPolicyDecision
+	// Transcript is a transcript of the commands, such as git commands, that
+	// were executed over the course of the promotion, with any credentials
+	// redacted. It is only populated if the promotion Errored or Failed.
+	Transcript string
+	// Overlays are the overlays of the Stage, as provided by the
+	// PromotionContext and updated by the PromotionSteps that promoted them.
+	Overlays []kargoapi.PromotedOverlay
+	// RenderedBranchPush records the commits pushed to the rendered branch of
+	// the Stage, as provided by the PromotionContext and updated by the
+	// PromotionSteps that pushed to it.
+	RenderedBranchPush *kargoapi.RenderedBranchPush
+	// ExternalModification records that a PromotionStep found the rendered
+	// branch of the Stage to have been modified by something other than Kargo.
+	ExternalModification *kargoapi.ExternalModification
+	// SyncCondition describes the outcome of the most recently completed sync
+	// of Argo CD Applications by a PromotionStep, if any.
+	SyncCondition *metav1.Condition
+	// OutputIgnoredCondition describes the rendered manifests that
+	// PromotionSteps wrote to files not read by the Argo CD Applications they
+	// were rendered for, if any.
+	OutputIgnoredCondition *metav1.Condition
+	// RetainedImages are the images subscribed to by the Warehouse the Freight
+	// originates from that the Freight does not carry, as provided by the
+	// PromotionContext and updated with the versions of them that
+	// PromotionSteps found to be left in place.
+	RetainedImages []kargoapi.RetainedImage
+	// SourceUpdateBatch records the batch of updates to a branch of a Git
+	// repository that a PromotionStep added the promotion's update to, if any.
+	SourceUpdateBatch *kargoapi.SourceUpdateBatch
+	// RenderWarnings are the distinct warnings that tools rendering manifests
+	// emitted over the course of the promotion, as provided by the
+	// PromotionContext and extended with those reported by PromotionSteps.
+	RenderWarnings []string
 }
 
 // PromotionStepContext is a type that represents the context in which a
@@ -383,11 +550,25 @@ type PromotionStepContext struct {
 	// TODO: krancour: Longer term, we may be able to do without this. See notes
 	// on previous two fields.
 	KargoClient client.Client
+	// ServiceAccountClient is a Kubernetes client for the Kargo control plane
+	// that impersonates the ServiceAccount referenced by the Stage targeted by
+	// the Promotion, so that what a PromotionStepRunner does with it is subject
+	// to that ServiceAccount's permissions. It is only furnished along with
+	// KargoClient, and is nil if the Stage does not reference a ServiceAccount.
+	ServiceAccountClient client.Client
+	// ChildCreator creates objects on behalf of the Promotion, subject to the
+	// limit on the number of such objects. It is only furnished along with
+	// KargoClient.
+	ChildCreator *controller.ChildCreator
 	// ArgoCDClient is a Kubernetes client that a PromotionStepRunner executing a
 	// PromotionStep may use to interact with an Argo CD control plane. The value
 	// of this field will often be nil, as the Engine will only furnish this to
 	// specially privileged PromotionStepRunners.
 	ArgoCDClient client.Client
+	// ArgoCDNamespace is the namespace into which the Argo CD control plane that
+	// ArgoCDClient interacts with is installed. It is set whenever ArgoCDClient
+	// is.
+	ArgoCDNamespace string
 	// CredentialsDB is a database of credentials that a PromotionStepRunner
 	// executing a PromotionStep may use to acquire credentials for interacting
 	// with external systems. The value of this field will often be nil, as the
@@ -399,6 +580,72 @@ type PromotionStepContext struct {
 	// responsible for finding them and furnishing them directly to each
 	// PromotionStepRunner.
 	CredentialsDB credentials.Database
+	// CommitMessageMaxImages is the maximum number of images to itemize in
+	// generated commit messages. A value of 0 means no limit.
+	CommitMessageMaxImages int
+	// ImageMappings are rules for rewriting references to images before they
+	// are written to manifests.
+	ImageMappings []kargoapi.ImageMapping
+	// ToolVersions are the pinned versions of the tools used to render
+	// manifests. It may be nil.
+	ToolVersions *kargoapi.ToolVersions
+	// ToolCache provides the binaries of pinned tool versions other than the
+	// ones embedded in Kargo. It may be nil.
+	ToolCache *tools.Cache
+	// ExecPolicy specifies the commands that may be executed. It may be nil,
+	// in which case no commands may be executed.
+	ExecPolicy *kargoapi.ExecPolicy
+	// RenderedBranch is the name of the branch that manifests rendered for the
+	// Stage are written to. It is empty if the Stage does not specify a
+	// RenderedBranch template.
+	RenderedBranch string
+	// RenderedBranchCommit records the commit that Kargo last pushed to
+	// RenderedBranch, whether before the promotion began or by an earlier
+	// PromotionStep. It is nil if no such commit is known.
+	RenderedBranchCommit *kargoapi.RenderedBranchCommit
+	// OnExternalModification is the policy that is applied if RenderedBranch is
+	// found not to point to RenderedBranchCommit before it is pushed to.
+	OnExternalModification kargoapi.RenderedBranchExternalModificationPolicy
+	// Overlays are the overlays of the Stage, along with the branches that
+	// manifests rendered from them are written to and the commits that were
+	// pushed to them by previous attempts to execute the PromotionStep. It is
+	// empty if the Stage does not specify any overlays.
+	Overlays []kargoapi.PromotedOverlay
+	// ForceSync indicates that Argo CD Applications are to be synced even if
+	// the manifests they are synced to did not change.
+	ForceSync bool
+	// MaxRepoSize is the maximum size, in bytes, of a Git repository that may
+	// be cloned. A value of 0 means no limit.
+	MaxRepoSize int64
+	// MaxRenderedOutputSize is the maximum size, in bytes, of the manifests
+	// that may be rendered. A value of 0 means no limit.
+	MaxRenderedOutputSize int64
+	// RemoteBasePolicy restricts the hosts that remote Kustomize bases may be
+	// fetched from. A nil policy allows all hosts.
+	RemoteBasePolicy *kargoapi.RemoteBasePolicy
+	// DryRunApply describes how rendered manifests are applied to the
+	// destination clusters of Argo CD Applications in dry-run mode. It is nil
+	// if the Stage does not permit doing so.
+	DryRunApply *kargoapi.DryRunApply
+	// RetainedImages are the images subscribed to by the Warehouse the Freight
+	// originates from that the Freight does not carry. PromotionStepRunners
+	// that find the versions of them that are left in place report them using
+	// PromotionStepResult.RetainedImages.
+	RetainedImages []kargoapi.RetainedImage
+	// SourceUpdateBatching describes how updates that are pushed to a branch
+	// of a Git repository are accumulated on a rolling branch instead. It is
+	// nil if the Stage does not specify this.
+	SourceUpdateBatching *kargoapi.SourceUpdateBatching
+	// StrictRender specifies whether PromotionStepRunners that render
+	// manifests fail when the tools they render them with emit warnings.
+	// Warnings are reported using PromotionStepResult.RenderWarnings either
+	// way.
+	StrictRender bool
+	// GitIdentity describes the identity that PromotionStepRunners commit to
+	// Git repositories as and the credentials they access them with. It is
+	// nil if the Stage does not specify one, in which case the controller's
+	// defaults apply.
+	GitIdentity *kargoapi.GitIdentity
 }
 
 // PromotionStepResult represents the results of single PromotionStep executed
@@ -418,6 +665,47 @@ type PromotionStepResult struct {
 	// a PromotionStepRunner's successful execution of a PromotionStep. This
 	// configuration can later be used as input to health check processes.
 	HealthCheckStep *HealthCheckStep
+	// WaitUntil is optionally returned along with a Running Status by a
+	// PromotionStepRunner that is waiting for a point in time, rather than for
+	// a change in some external state. Time spent waiting this way does not
+	// count toward the PromotionStep's timeout.
+	WaitUntil *time.Time
+	// Overlays are optionally returned by a PromotionStepRunner that promoted
+	// any of the overlays of the Stage, e.g. to record the commits that were
+	// pushed to their branches. The Engine records them regardless of the
+	// Status, so that overlays that were promoted before the PromotionStep
+	// failed are not promoted again when it is retried.
+	Overlays []kargoapi.PromotedOverlay
+	// RenderedBranchPush is optionally returned by a PromotionStepRunner that
+	// pushed to the rendered branch of the Stage. The Engine records it
+	// regardless of the Status, so that the branch can be cleaned up if the
+	// promotion fails.
+	RenderedBranchPush *kargoapi.RenderedBranchPush
+	// ExternalModification is optionally returned by a PromotionStepRunner that
+	// found the rendered branch of the Stage to have been modified by something
+	// other than Kargo. The Engine records it regardless of the Status.
+	ExternalModification *kargoapi.ExternalModification
+	// SyncCondition is optionally returned by a PromotionStepRunner that
+	// observed the completion of a sync of Argo CD Applications. The Engine
+	// records it regardless of the Status.
+	SyncCondition *metav1.Condition
+	// OutputIgnoredCondition is optionally returned by a PromotionStepRunner
+	// that wrote rendered manifests to files not read by the Argo CD
+	// Application they were rendered for.
+	OutputIgnoredCondition *metav1.Condition
+	// RetainedImages are optionally returned by a PromotionStepRunner that
+	// found the versions of images the Freight does not carry that are left in
+	// place, e.g. in a Kustomization file. The Engine records them regardless
+	// of the Status.
+	RetainedImages []kargoapi.RetainedImage
+	// SourceUpdateBatch is optionally returned by a PromotionStepRunner that
+	// pushed an update to the rolling branch of a batch of updates. The Engine
+	// records it regardless of the Status.
+	SourceUpdateBatch *kargoapi.SourceUpdateBatch
+	// RenderWarnings are optionally returned by a PromotionStepRunner that
+	// rendered manifests using a tool that emitted warnings, e.g. about the use
+	// of deprecated fields. The Engine records them regardless of the Status.
+	RenderWarnings []string
 }
 
 func warehouseFunc(name ...any) (any, error) { // nolint: unparam
@@ -427,6 +715,23 @@ func warehouseFunc(name ...any) (any, error) { // nolint: unparam
 	}, nil
 }
 
+// getStageMetadataFunc returns a function that returns the value of the
+// Stage's metadata with the provided key. Referring to a key that the Stage's
+// metadata does not contain is an error, so that a missing key does not go
+// unnoticed.
+func getStageMetadataFunc(promoCtx PromotionContext) func(a ...any) (any, error) {
+	return func(a ...any) (any, error) {
+		key := a[0].(string) // nolint: forcetypeassert
+		value, ok := promoCtx.StageMetadata[key]
+		if !ok {
+			return nil, fmt.Errorf(
+				"Stage %q has no metadata with key %q", promoCtx.Stage, key,
+			)
+		}
+		return value, nil
+	}
+}
+
 func getCommitFunc(
 	ctx context.Context,
 	cl client.Client,
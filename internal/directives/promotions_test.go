@@ -83,6 +83,26 @@ func TestPromotionStep_GetTimeout(t *testing.T) {
 				assert.Equal(t, ptr.To(time.Duration(3)), result)
 			},
 		},
+		{
+			name: "returns default timeout for config",
+			step: &PromotionStep{
+				Config: []byte(`{"syncTrigger":"none","apps":[{"name":"app"}]}`),
+			},
+			runner: newArgocdUpdater(),
+			assertions: func(t *testing.T, result *time.Duration) {
+				assert.Equal(t, ptr.To(15*time.Minute), result)
+			},
+		},
+		{
+			name: "returns default timeout when config does not affect it",
+			step: &PromotionStep{
+				Config: []byte(`{"syncTrigger":"none","apps":[{"name":"app","syncTrigger":"operation"}]}`),
+			},
+			runner: newArgocdUpdater(),
+			assertions: func(t *testing.T, result *time.Duration) {
+				assert.Equal(t, ptr.To(5*time.Minute), result)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -219,6 +239,25 @@ func TestPromotionStep_GetConfig(t *testing.T) {
 				"promotion": "fake-promotion",
 			},
 		},
+		{
+			name: "test stageMetadata function",
+			// Test that expressions can reference the Stage's metadata
+			promoCtx: PromotionContext{
+				Stage: "fake-stage",
+				StageMetadata: map[string]string{
+					"owningTeam":   "payments",
+					"slackChannel": "#payments-deploys",
+				},
+			},
+			rawCfg: []byte(`{
+				"message": "Promote to ${{ ctx.stage }} (owned by ${{ stageMetadata('owningTeam') }})",
+				"channel": "${{ stageMetadata('slackChannel') }}"
+			}`),
+			expectedCfg: Config{
+				"message": "Promote to fake-stage (owned by payments)",
+				"channel": "#payments-deploys",
+			},
+		},
 		{
 			name: "test secrets",
 			// Test that expressions can reference secrets
@@ -559,3 +598,19 @@ func TestPromotionStep_GetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestPromotionStep_GetConfig_missingStageMetadata(t *testing.T) {
+	promoStep := PromotionStep{
+		Config: []byte(`{"message": "${{ stageMetadata('changeID') }}"}`),
+	}
+	_, err := promoStep.GetConfig(
+		context.Background(),
+		nil,
+		PromotionContext{
+			Stage:         "fake-stage",
+			StageMetadata: map[string]string{"owningTeam": "payments"},
+		},
+		nil,
+	)
+	require.ErrorContains(t, err, `Stage "fake-stage" has no metadata with key "changeID"`)
+}
@@ -0,0 +1,193 @@
+package directives
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/otiai10/copy"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// RenderSimulation describes a hypothetical promotion of container images to a
+// Kustomize overlay, which SimulateRender renders exactly the way a Promotion
+// running the kustomize-set-image and kustomize-build steps would.
+type RenderSimulation struct {
+	// RepoDir is the path to a local checkout of the repository containing the
+	// overlay. It is never modified.
+	RepoDir string
+	// Overlay is the path to the overlay, relative to RepoDir.
+	Overlay string
+	// Images are the container images to set in the overlay's Kustomization
+	// file, each in the form <repoURL>:<tag> or <repoURL>@<digest>.
+	Images []string
+	// Normalize configures the normalization of the rendered manifests, like
+	// the normalize option of the kustomize-build step does.
+	Normalize *Normalize
+	// Split specifies whether each rendered manifest is written to a file of
+	// its own, like the kustomize-build step does when its outPath is a
+	// directory, rather than all of them to a single file.
+	Split bool
+}
+
+// SimulatedRender is the result of a RenderSimulation.
+type SimulatedRender struct {
+	// Files maps the names of the files that the rendered manifests were
+	// written to onto their contents. Unless the RenderSimulation is split,
+	// there is a single file named manifests.yaml.
+	Files map[string][]byte
+	// Messages are any warnings reported by the steps that do not cause a
+	// Promotion to fail.
+	Messages []string
+}
+
+// renderSimulationOutFile is the name of the file that the manifests rendered
+// by a RenderSimulation that is not split are written to.
+const renderSimulationOutFile = "manifests.yaml"
+
+// SimulateRender renders the manifests that promoting the images of the
+// provided RenderSimulation to its overlay would produce. The overlay is
+// updated in a throwaway copy of the repository by the kustomize-set-image
+// step and built by the kustomize-build step, including all of their
+// validation and normalization, so the manifests are identical to those a
+// Promotion would produce. Nothing is pushed and no cluster is accessed. An
+// error is returned if either step would not succeed.
+func SimulateRender(ctx context.Context, sim RenderSimulation) (*SimulatedRender, error) {
+	images := make([]KustomizeSetImageConfigImage, len(sim.Images))
+	for i, ref := range sim.Images {
+		image, err := parseSimulatedImage(ref)
+		if err != nil {
+			return nil, err
+		}
+		images[i] = image
+	}
+
+	workDir, err := os.MkdirTemp("", "render-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+	const repoPath = "repo"
+	if err = copy.Copy(sim.RepoDir, filepath.Join(workDir, repoPath), copy.Options{
+		Skip: func(f os.FileInfo, _, _ string) (bool, error) {
+			return f.IsDir() && f.Name() == ".git", nil
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("error copying %q: %w", sim.RepoDir, err)
+	}
+	overlay := filepath.ToSlash(filepath.Join(repoPath, sim.Overlay))
+
+	outPath := renderSimulationOutFile
+	if sim.Split {
+		outPath = "out"
+	}
+	res := &SimulatedRender{}
+	steps := []struct {
+		kind string
+		cfg  any
+	}{
+		{
+			kind: "kustomize-set-image",
+			cfg:  KustomizeSetImageConfig{Path: overlay, Images: images},
+		},
+		{
+			kind: "kustomize-build",
+			cfg: KustomizeBuildConfig{
+				Path:      overlay,
+				OutPath:   outPath,
+				Normalize: sim.Normalize,
+			},
+		},
+	}
+	for _, step := range steps {
+		msg, err := runSimulatedStep(ctx, workDir, step.kind, step.cfg)
+		if err != nil {
+			return nil, err
+		}
+		if msg != "" {
+			res.Messages = append(res.Messages, msg)
+		}
+	}
+
+	if res.Files, err = readSimulatedRender(filepath.Join(workDir, outPath), sim.Split); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// runSimulatedStep runs the built-in promotion step of the provided kind with
+// the provided configuration in the provided working directory, the way the
+// Engine would. It returns the step's message if it succeeds and an error
+// otherwise.
+func runSimulatedStep(ctx context.Context, workDir, kind string, cfg any) (string, error) {
+	reg, err := builtins.GetPromotionStepRunnerRegistration(kind)
+	if err != nil {
+		return "", err
+	}
+	// Configuration reaches steps as the result of decoding JSON.
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling %s config: %w", kind, err)
+	}
+	var stepCfg Config
+	if err = json.Unmarshal(b, &stepCfg); err != nil {
+		return "", fmt.Errorf("error unmarshaling %s config: %w", kind, err)
+	}
+	res, err := reg.Runner.RunPromotionStep(ctx, &PromotionStepContext{
+		WorkDir: workDir,
+		Config:  stepCfg,
+	})
+	if err != nil {
+		return "", fmt.Errorf("step %q failed: %w", kind, sanitizePathError(err, workDir))
+	}
+	if res.Status != kargoapi.PromotionPhaseSucceeded {
+		return "", fmt.Errorf("step %q did not succeed: %s: %s", kind, res.Status, res.Message)
+	}
+	return res.Message, nil
+}
+
+// readSimulatedRender reads the manifests rendered by a RenderSimulation from
+// the provided file or, if split, directory.
+func readSimulatedRender(outPath string, split bool) (map[string][]byte, error) {
+	if !split {
+		b, err := os.ReadFile(outPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rendered manifests: %w", err)
+		}
+		return map[string][]byte{renderSimulationOutFile: b}, nil
+	}
+	entries, err := os.ReadDir(outPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rendered manifests: %w", err)
+	}
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(outPath, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading rendered manifests: %w", err)
+		}
+		files[entry.Name()] = b
+	}
+	return files, nil
+}
+
+// parseSimulatedImage parses an image of a RenderSimulation in the form
+// <repoURL>:<tag> or <repoURL>@<digest>.
+func parseSimulatedImage(ref string) (KustomizeSetImageConfigImage, error) {
+	if repo, digest, ok := strings.Cut(ref, "@"); ok && repo != "" && digest != "" {
+		return KustomizeSetImageConfigImage{Image: repo, Digest: digest}, nil
+	}
+	// A colon before the last slash separates the host from the port of the
+	// registry rather than the repository from the tag.
+	if i := strings.LastIndex(ref, ":"); i > 0 && i > strings.LastIndex(ref, "/") &&
+		i < len(ref)-1 {
+		return KustomizeSetImageConfigImage{Image: ref[:i], Tag: ref[i+1:]}, nil
+	}
+	return KustomizeSetImageConfigImage{}, fmt.Errorf(
+		"image %q is not of the form <repoURL>:<tag> or <repoURL>@<digest>", ref,
+	)
+}
@@ -0,0 +1,122 @@
+package directives
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/directives/rendertest"
+)
+
+func TestSimulateRender(t *testing.T) {
+	rendertest.Run(t, func(t *testing.T, c rendertest.Case) ([]byte, error) {
+		res, err := SimulateRender(context.Background(), RenderSimulation{
+			RepoDir:   c.RepoDir,
+			Overlay:   c.Overlay,
+			Images:    c.Images,
+			Normalize: renderTestNormalize(c),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Files[renderSimulationOutFile], nil
+	})
+}
+
+func TestSimpleEngine_Promote_rendersLikeSimulation(t *testing.T) {
+	// A Promotion running the kustomize-set-image and kustomize-build steps
+	// must render exactly what SimulateRender does.
+	rendertest.Run(t, func(t *testing.T, c rendertest.Case) ([]byte, error) {
+		workDir := t.TempDir()
+		require.NoError(t, os.CopyFS(filepath.Join(workDir, "src"), os.DirFS(c.RepoDir)))
+		overlay := filepath.ToSlash(filepath.Join("src", c.Overlay))
+		images := make([]KustomizeSetImageConfigImage, len(c.Images))
+		for i, ref := range c.Images {
+			var err error
+			images[i], err = parseSimulatedImage(ref)
+			require.NoError(t, err)
+		}
+		setImageCfg, err := json.Marshal(KustomizeSetImageConfig{Path: overlay, Images: images})
+		require.NoError(t, err)
+		buildCfg, err := json.Marshal(KustomizeBuildConfig{
+			Path:      overlay,
+			OutPath:   "out/manifests.yaml",
+			Normalize: renderTestNormalize(c),
+		})
+		require.NoError(t, err)
+
+		engine := &SimpleEngine{
+			registry:    builtins,
+			kargoClient: fake.NewClientBuilder().Build(),
+		}
+		res, err := engine.Promote(
+			context.Background(),
+			PromotionContext{WorkDir: workDir},
+			[]PromotionStep{
+				{Kind: "kustomize-set-image", Config: setImageCfg},
+				{Kind: "kustomize-build", Config: buildCfg},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if res.Status != kargoapi.PromotionPhaseSucceeded {
+			return nil, errors.New(res.Message)
+		}
+		return os.ReadFile(filepath.Join(workDir, "out", "manifests.yaml"))
+	})
+}
+
+func Test_parseSimulatedImage(t *testing.T) {
+	testCases := []struct {
+		ref         string
+		expected    KustomizeSetImageConfigImage
+		expectedErr bool
+	}{
+		{
+			ref:      "example.com/app:v1.0.0",
+			expected: KustomizeSetImageConfigImage{Image: "example.com/app", Tag: "v1.0.0"},
+		},
+		{
+			ref:      "localhost:5000/app:v1.0.0",
+			expected: KustomizeSetImageConfigImage{Image: "localhost:5000/app", Tag: "v1.0.0"},
+		},
+		{
+			ref:      "example.com/app@sha256:abc",
+			expected: KustomizeSetImageConfigImage{Image: "example.com/app", Digest: "sha256:abc"},
+		},
+		{
+			ref:         "localhost:5000/app",
+			expectedErr: true,
+		},
+		{
+			ref:         "example.com/app:",
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.ref, func(t *testing.T) {
+			image, err := parseSimulatedImage(testCase.ref)
+			if testCase.expectedErr {
+				require.ErrorContains(t, err, "is not of the form")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, image)
+		})
+	}
+}
+
+func renderTestNormalize(c rendertest.Case) *Normalize {
+	if !c.Normalize {
+		return nil
+	}
+	return &Normalize{SortDocuments: true, SortKeys: true, StripFields: true}
+}
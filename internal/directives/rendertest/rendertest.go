@@ -0,0 +1,75 @@
+// Package rendertest provides the golden-file test suite that every way of
+// rendering manifests for a promotion of images to a Kustomize overlay must
+// pass, so that they are guaranteed to render identical manifests.
+package rendertest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// Case is a hypothetical promotion of images to a Kustomize overlay.
+type Case struct {
+	// Name is the name of the Case.
+	Name string `json:"-"`
+	// RepoDir is the path to the repository containing the overlay. It must
+	// not be modified.
+	RepoDir string `json:"-"`
+	// Overlay is the path to the overlay, relative to RepoDir.
+	Overlay string `json:"overlay"`
+	// Images are the images to promote, each in the form <repoURL>:<tag> or
+	// <repoURL>@<digest>.
+	Images []string `json:"images"`
+	// Normalize specifies whether all normalization of the rendered manifests
+	// is enabled.
+	Normalize bool `json:"normalize,omitempty"`
+	// ExpectedError is a substring of the error that rendering must fail with.
+	// If empty, rendering must succeed.
+	ExpectedError string `json:"expectedError,omitempty"`
+}
+
+// RenderFunc renders the manifests for the provided Case into a single file
+// and returns its contents.
+type RenderFunc func(t *testing.T, c Case) ([]byte, error)
+
+// Run runs each Case in the testdata directory of this package as a subtest,
+// rendering it using the provided RenderFunc. Each Case is a directory
+// containing a case.yaml file that specifies it, a repo directory containing
+// the repository, and, unless rendering is expected to fail, an expected.yaml
+// file containing the rendered manifests.
+func Run(t *testing.T, render RenderFunc) {
+	_, file, _, ok := runtime.Caller(0)
+	require.True(t, ok)
+	testdataDir := filepath.Join(filepath.Dir(file), "testdata")
+	entries, err := os.ReadDir(testdataDir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		caseDir := filepath.Join(testdataDir, entry.Name())
+		t.Run(entry.Name(), func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join(caseDir, "case.yaml"))
+			require.NoError(t, err)
+			var c Case
+			require.NoError(t, yaml.UnmarshalStrict(b, &c))
+			c.Name = entry.Name()
+			c.RepoDir = filepath.Join(caseDir, "repo")
+
+			rendered, err := render(t, c)
+			if c.ExpectedError != "" {
+				require.ErrorContains(t, err, c.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := os.ReadFile(filepath.Join(caseDir, "expected.yaml"))
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(rendered))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/credentials"
+	libgit "github.com/akuity/kargo/internal/git"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+// RepoNotAllowedError is returned when a PromotionStep attempts to access a
+// Git repository that is not allowed by the RepoPolicy of the
+// PromotionContext.
+type RepoNotAllowedError struct {
+	// RepoURL is the URL of the repository.
+	RepoURL string
+	// Decision is the decision that denied access to the repository.
+	Decision libgit.RepoURLDecision
+}
+
+// Error implements the error interface.
+func (e *RepoNotAllowedError) Error() string {
+	return fmt.Sprintf("access to repository %s is %s", e.RepoURL, e.Decision)
+}
+
+// IsRepoNotAllowed returns true if the provided error is a
+// RepoNotAllowedError or wraps one and false otherwise.
+func IsRepoNotAllowed(err error) bool {
+	var e *RepoNotAllowedError
+	return errors.As(err, &e)
+}
+
+// repoPolicyCredentialsDB is a credentials.Database that consults a
+// libgit.RepoURLPolicy before Git credentials are looked up, so that steps
+// cannot obtain credentials for, and therefore cannot push to, repositories
+// that are not allowed.
+type repoPolicyCredentialsDB struct {
+	credentials.Database
+	policy *libgit.RepoURLPolicy
+	exec   *promotionExecution
+}
+
+// Get implements the credentials.Database interface. If access to the
+// repository is not allowed, a terminal error wrapping a RepoNotAllowedError is
+// returned.
+func (d *repoPolicyCredentialsDB) Get(
+	ctx context.Context,
+	namespace string,
+	credType credentials.Type,
+	repoURL string,
+) (credentials.Credentials, bool, error) {
+	if credType != credentials.TypeGit {
+		return d.Database.Get(ctx, namespace, credType, repoURL)
+	}
+	decision := d.policy.Evaluate(repoURL)
+	d.exec.recordRepoPolicyDecision(kargoapi.RepoPolicyDecision{
+		RepoURL: repoURL,
+		Allowed: decision.Allowed,
+		Rule:    decision.Rule,
+	})
+	logger := logging.LoggerFromContext(ctx).WithValues(
+		"repo", repoURL,
+		"allowed", decision.Allowed,
+		"rule", decision.Rule,
+	)
+	if !decision.Allowed {
+		logger.Info("access to repository denied by repo policy")
+		return credentials.Credentials{}, false, &terminalError{
+			err: &RepoNotAllowedError{RepoURL: repoURL, Decision: decision},
+		}
+	}
+	logger.Debug("access to repository allowed by repo policy")
+	return d.Database.Get(ctx, namespace, credType, repoURL)
+}
+
+// Invalidate implements the credentials.Invalidator interface by passing
+// through to the underlying credentials.Database, if it caches credentials.
+func (d *repoPolicyCredentialsDB) Invalidate(
+	namespace string,
+	credType credentials.Type,
+	repoURL string,
+) {
+	credentials.Invalidate(d.Database, namespace, credType, repoURL)
+}
@@ -0,0 +1,172 @@
+package directives
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/credentials"
+	libgit "github.com/akuity/kargo/internal/git"
+)
+
+func Test_repoPolicyCredentialsDB_Get(t *testing.T) {
+	policy, err := libgit.NewRepoURLPolicy(
+		[]string{"https://github.com/example/*"},
+		[]string{"https://github.com/example/secret"},
+	)
+	require.NoError(t, err)
+
+	var lookups []string
+	exec := &promotionExecution{}
+	db := &repoPolicyCredentialsDB{
+		Database: &credentials.FakeDB{
+			GetFn: func(
+				_ context.Context,
+				_ string,
+				_ credentials.Type,
+				repoURL string,
+			) (credentials.Credentials, bool, error) {
+				lookups = append(lookups, repoURL)
+				return credentials.Credentials{Username: "user"}, true, nil
+			},
+		},
+		policy: policy,
+		exec:   exec,
+	}
+
+	// Allowed repositories are looked up
+	creds, found, err := db.Get(
+		context.Background(), "fake-project", credentials.TypeGit, "https://github.com/example/repo",
+	)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "user", creds.Username)
+
+	// Denied repositories are not looked up
+	_, found, err = db.Get(
+		context.Background(), "fake-project", credentials.TypeGit, "https://github.com/example/secret",
+	)
+	require.False(t, found)
+	require.True(t, IsRepoNotAllowed(err))
+	require.True(t, isTerminal(err))
+	require.ErrorContains(t, err, `denied by rule "https://github.com/example/secret"`)
+
+	_, _, err = db.Get(
+		context.Background(), "fake-project", credentials.TypeGit, "https://github.com/other/repo",
+	)
+	require.True(t, IsRepoNotAllowed(err))
+	require.ErrorContains(t, err, "matches no allowed rule")
+
+	// The policy does not apply to other types of credentials
+	_, found, err = db.Get(
+		context.Background(), "fake-project", credentials.TypeImage, "ghcr.io/other/image",
+	)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// Decisions are recorded once per repository
+	_, _, err = db.Get(
+		context.Background(), "fake-project", credentials.TypeGit, "https://github.com/example/repo",
+	)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		[]string{"https://github.com/example/repo", "ghcr.io/other/image", "https://github.com/example/repo"},
+		lookups,
+	)
+	require.Equal(
+		t,
+		[]kargoapi.RepoPolicyDecision{
+			{
+				RepoURL: "https://github.com/example/repo",
+				Allowed: true,
+				Rule:    "https://github.com/example/*",
+			},
+			{
+				RepoURL: "https://github.com/example/secret",
+				Rule:    "https://github.com/example/secret",
+			},
+			{
+				RepoURL: "https://github.com/other/repo",
+			},
+		},
+		exec.repoDecisions,
+	)
+}
+
+func TestSimpleEngine_executeSteps_repoPolicy(t *testing.T) {
+	policy, err := libgit.NewRepoURLPolicy([]string{"https://github.com/example/*"}, nil)
+	require.NoError(t, err)
+
+	testRegistry := NewStepRunnerRegistry()
+	testRegistry.RegisterPromotionStepRunner(
+		&mockPromotionStepRunner{
+			name: "push",
+			runFunc: func(ctx context.Context, stepCtx *PromotionStepContext) (PromotionStepResult, error) {
+				repoURL, _ := stepCtx.Config["repoURL"].(string)
+				if _, _, err := stepCtx.CredentialsDB.Get(
+					ctx, stepCtx.Project, credentials.TypeGit, repoURL,
+				); err != nil {
+					return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+				}
+				return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+			},
+		},
+		&StepRunnerPermissions{AllowCredentialsDB: true},
+	)
+	engine := &SimpleEngine{
+		registry:      testRegistry,
+		kargoClient:   fake.NewClientBuilder().Build(),
+		credentialsDB: &credentials.FakeDB{},
+	}
+
+	result, err := engine.executeSteps(
+		context.Background(),
+		PromotionContext{RepoPolicy: policy},
+		[]PromotionStep{
+			{
+				Kind:   "push",
+				Alias:  "allowed",
+				Config: []byte(`{"repoURL":"https://github.com/example/repo"}`),
+			},
+			{
+				Kind:  "push",
+				Alias: "denied",
+				// Attempts to access repositories that are not allowed are not
+				// tolerated, even if the step is configured to continue on error.
+				ContinueOnError: true,
+				Config:          []byte(`{"repoURL":"https://github.com/other/repo"}`),
+			},
+			{
+				Kind:   "push",
+				Alias:  "never-run",
+				Config: []byte(`{"repoURL":"https://github.com/example/repo"}`),
+			},
+		},
+		t.TempDir(),
+	)
+	require.Error(t, err)
+	assert.True(t, IsRepoNotAllowed(err))
+	assert.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+	assert.Equal(t, int64(1), result.CurrentStep)
+	assert.Len(t, result.StepExecutionMetadata, 2)
+	assert.Equal(
+		t,
+		[]kargoapi.RepoPolicyDecision{
+			{
+				RepoURL: "https://github.com/example/repo",
+				Allowed: true,
+				Rule:    "https://github.com/example/*",
+			},
+			{
+				RepoURL: "https://github.com/other/repo",
+			},
+		},
+		result.RepoPolicyDecisions,
+	)
+}
@@ -0,0 +1,59 @@
+package directives
+
+import (
+	"context"
+	"time"
+)
+
+// shutdownKey is the key under which the channel that is closed when shutdown
+// begins is stored in a context returned by ContextWithShutdownGracePeriod.
+type shutdownKey struct{}
+
+// ContextWithShutdownGracePeriod returns a context for the execution of a
+// promotion process that, unlike the provided context, is not canceled as
+// soon as the provided context is done. Instead, once the provided context is
+// done, Engines stop starting new PromotionSteps, so that the promotion process
+// can be resumed from the next step, and PromotionSteps that are in progress
+// are given the provided grace period to finish before the returned context is
+// canceled, which kills any git commands and other subprocesses they run with
+// it. A grace period that is not positive cancels the returned context
+// as soon as the provided context is done. The returned CancelFunc must be
+// called once the promotion process has been executed.
+func ContextWithShutdownGracePeriod(
+	ctx context.Context,
+	gracePeriod time.Duration,
+) (context.Context, context.CancelFunc) {
+	execCtx, cancel := context.WithCancel(
+		context.WithValue(context.WithoutCancel(ctx), shutdownKey{}, ctx.Done()),
+	)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-execCtx.Done():
+			return
+		}
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-execCtx.Done():
+		}
+	}()
+	return execCtx, cancel
+}
+
+// shuttingDown returns true if the provided context was derived from a context
+// returned by ContextWithShutdownGracePeriod and shutdown has begun.
+func shuttingDown(ctx context.Context) bool {
+	done, _ := ctx.Value(shutdownKey{}).(<-chan struct{})
+	if done == nil {
+		return false
+	}
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,181 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/controller/git"
+)
+
+func TestContextWithShutdownGracePeriod(t *testing.T) {
+	t.Run("not shutting down", func(t *testing.T) {
+		ctx, cancel := ContextWithShutdownGracePeriod(context.Background(), time.Hour)
+		defer cancel()
+		require.False(t, shuttingDown(ctx))
+		require.NoError(t, ctx.Err())
+		require.False(t, shuttingDown(context.Background()))
+	})
+
+	t.Run("grace period elapses", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := ContextWithShutdownGracePeriod(parent, 50*time.Millisecond)
+		defer cancel()
+		cancelParent()
+		require.True(t, shuttingDown(ctx))
+		// The context is not canceled until the grace period has elapsed.
+		require.NoError(t, ctx.Err())
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context was not canceled after the grace period elapsed")
+		}
+	})
+
+	t.Run("no grace period", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := ContextWithShutdownGracePeriod(parent, 0)
+		defer cancel()
+		cancelParent()
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context was not canceled")
+		}
+	})
+}
+
+func TestContextWithShutdownGracePeriod_killsGitCommands(t *testing.T) {
+	repo := newBlockingPushRepo(t)
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := ContextWithShutdownGracePeriod(parent, 200*time.Millisecond)
+	defer cancel()
+	pushed := make(chan error, 1)
+	go func() {
+		pushed <- repo.PushContext(ctx, &git.PushOptions{TargetBranch: "main"})
+	}()
+	cancelParent()
+
+	// The push is killed once the grace period has elapsed, rather than once
+	// the process exits.
+	select {
+	case err := <-pushed:
+		require.Error(t, err)
+		require.Error(t, ctx.Err())
+	case <-time.After(30 * time.Second):
+		t.Fatal("push was not killed after the grace period elapsed")
+	}
+}
+
+func TestSimpleEngine_executeSteps_shutdown(t *testing.T) {
+	// newEngine returns an engine with a "render" step that signals when it has
+	// started and then runs until it is released or its context is canceled,
+	// like a long-running render, and a "push" step that records that it ran.
+	newEngine := func(started chan<- struct{}, release <-chan struct{}, pushed *bool) *SimpleEngine {
+		registry := NewStepRunnerRegistry()
+		registry.RegisterPromotionStepRunner(
+			&mockPromotionStepRunner{
+				name: "render",
+				runFunc: func(ctx context.Context, _ *PromotionStepContext) (PromotionStepResult, error) {
+					close(started)
+					select {
+					case <-release:
+						return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+					case <-ctx.Done():
+						return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, ctx.Err()
+					}
+				},
+			},
+			nil,
+		)
+		registry.RegisterPromotionStepRunner(
+			&mockPromotionStepRunner{
+				name: "push",
+				runFunc: func(context.Context, *PromotionStepContext) (PromotionStepResult, error) {
+					*pushed = true
+					return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+				},
+			},
+			nil,
+		)
+		return &SimpleEngine{
+			registry:    registry,
+			kargoClient: fake.NewClientBuilder().Build(),
+		}
+	}
+	steps := []PromotionStep{
+		{Kind: "render", Alias: "render"},
+		{Kind: "push", Alias: "push"},
+	}
+
+	t.Run("in-flight step finishes within grace period", func(t *testing.T) {
+		sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := ContextWithShutdownGracePeriod(sigCtx, time.Minute)
+		defer cancel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var pushed bool
+		engine := newEngine(started, release, &pushed)
+
+		go func() {
+			<-started
+			proc, err := os.FindProcess(os.Getpid())
+			if err == nil {
+				_ = proc.Signal(syscall.SIGTERM)
+			}
+			<-sigCtx.Done()
+			close(release)
+		}()
+
+		result, err := engine.executeSteps(ctx, PromotionContext{}, steps, t.TempDir())
+		require.NoError(t, err)
+		// The render finished, but the push was not started, so the Promotion
+		// resumes with the push.
+		assert.Equal(t, kargoapi.PromotionPhaseRunning, result.Status)
+		assert.Equal(t, int64(1), result.CurrentStep)
+		assert.False(t, pushed)
+		require.Len(t, result.StepExecutionMetadata, 1)
+		assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.StepExecutionMetadata[0].Status)
+		assert.NotNil(t, result.StepExecutionMetadata[0].FinishedAt)
+	})
+
+	t.Run("in-flight step is interrupted when grace period elapses", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := ContextWithShutdownGracePeriod(parent, 50*time.Millisecond)
+		defer cancel()
+
+		started := make(chan struct{})
+		var pushed bool
+		engine := newEngine(started, nil, &pushed)
+
+		go func() {
+			<-started
+			cancelParent()
+		}()
+
+		result, err := engine.executeSteps(ctx, PromotionContext{}, steps, t.TempDir())
+		require.NoError(t, err)
+		// The render is retried on the next reconciliation without counting as
+		// an error.
+		assert.Equal(t, kargoapi.PromotionPhaseRunning, result.Status)
+		assert.Equal(t, int64(0), result.CurrentStep)
+		assert.False(t, pushed)
+		require.Len(t, result.StepExecutionMetadata, 1)
+		meta := result.StepExecutionMetadata[0]
+		assert.Equal(t, kargoapi.PromotionPhaseRunning, meta.Status)
+		assert.Equal(t, uint32(0), meta.ErrorCount)
+		assert.Contains(t, meta.Message, "interrupted by shutdown")
+		assert.Nil(t, meta.FinishedAt)
+	})
+}
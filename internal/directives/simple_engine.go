@@ -2,9 +2,11 @@ package directives
 
 import (
 	"regexp"
+	"sync"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	libargocd "github.com/akuity/kargo/internal/argocd"
 	"github.com/akuity/kargo/internal/credentials"
 )
 
@@ -18,20 +20,37 @@ type SimpleEngine struct {
 	registry      *StepRunnerRegistry
 	credentialsDB credentials.Database
 	kargoClient   client.Client
-	argoCDClient  client.Client
+	// kargoClientForServiceAccount returns a client for the Kargo control plane
+	// that impersonates the ServiceAccount with the provided namespace and
+	// name. It may be nil, in which case no such clients are furnished to
+	// steps.
+	kargoClientForServiceAccount func(namespace, name string) (client.Client, error)
+	// argoCDContexts are the Argo CD contexts through which steps interact with
+	// Argo CD. It is nil if Argo CD integration is disabled.
+	argoCDContexts *libargocd.Contexts
+	// transcripts are the transcripts of the commands executed by promotions
+	// that are in progress, by working directory.
+	transcriptsMu sync.Mutex
+	transcripts   map[string]*promotionTranscript
 }
 
 // NewSimpleEngine returns a new SimpleEngine that uses the package's built-in
-// StepRunnerRegistry.
+// StepRunnerRegistry. Steps interact with Argo CD through the context, from
+// the provided argoCDContexts, that is named by the Promotion or health check
+// being executed. Steps that act on the Kargo control plane on behalf of a
+// Stage that references a ServiceAccount do so using a client obtained from
+// the provided kargoClientForServiceAccount function.
 func NewSimpleEngine(
 	credentialsDB credentials.Database,
 	kargoClient client.Client,
-	argoCDClient client.Client,
+	kargoClientForServiceAccount func(namespace, name string) (client.Client, error),
+	argoCDContexts *libargocd.Contexts,
 ) *SimpleEngine {
 	return &SimpleEngine{
-		registry:      builtins,
-		credentialsDB: credentialsDB,
-		kargoClient:   kargoClient,
-		argoCDClient:  argoCDClient,
+		registry:                     builtins,
+		credentialsDB:                credentialsDB,
+		kargoClient:                  kargoClient,
+		kargoClientForServiceAccount: kargoClientForServiceAccount,
+		argoCDContexts:               argoCDContexts,
 	}
 }
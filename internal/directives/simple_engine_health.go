@@ -85,7 +85,13 @@ func (e *SimpleEngine) executeHealthCheck(
 		}
 	}
 
-	stepCtx := e.prepareHealthCheckStepContext(healthCtx, step, reg)
+	stepCtx, err := e.prepareHealthCheckStepContext(healthCtx, step, reg)
+	if err != nil {
+		return HealthCheckStepResult{
+			Status: kargoapi.HealthStateUnknown,
+			Issues: []string{err.Error()},
+		}
+	}
 	return reg.Runner.RunHealthCheckStep(ctx, stepCtx)
 }
 
@@ -94,7 +100,7 @@ func (e *SimpleEngine) prepareHealthCheckStepContext(
 	healthCtx HealthCheckContext,
 	step HealthCheckStep,
 	reg HealthCheckStepRunnerRegistration,
-) *HealthCheckStepContext {
+) (*HealthCheckStepContext, error) {
 	stepCtx := &HealthCheckStepContext{
 		Config:  step.Config.DeepCopy(),
 		Project: healthCtx.Project,
@@ -108,8 +114,15 @@ func (e *SimpleEngine) prepareHealthCheckStepContext(
 		stepCtx.KargoClient = e.kargoClient
 	}
 	if reg.Permissions.AllowArgoCDClient {
-		stepCtx.ArgoCDClient = e.argoCDClient
+		argoCDCtx, err := e.argoCDContexts.Get(healthCtx.ArgoCDContext)
+		if err != nil {
+			return nil, err
+		}
+		if argoCDCtx != nil {
+			stepCtx.ArgoCDClient = argoCDCtx.Client
+			stepCtx.ArgoCDNamespace = argoCDCtx.Namespace
+		}
 	}
 
-	return stepCtx
+	return stepCtx, nil
 }
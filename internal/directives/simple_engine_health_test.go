@@ -2,12 +2,15 @@ package directives
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
 	"github.com/akuity/kargo/internal/credentials"
 )
 
@@ -243,6 +246,19 @@ func TestSimpleEngine_executeHealthCheck(t *testing.T) {
 				assert.Contains(t, result.Issues[0], "unknown")
 			},
 		},
+		{
+			name:      "unavailable Argo CD context",
+			healthCtx: HealthCheckContext{ArgoCDContext: "broken"},
+			step:      HealthCheckStep{Kind: "argocd-check"},
+			assertions: func(t *testing.T, result HealthCheckStepResult) {
+				assert.Equal(t, kargoapi.HealthStateUnknown, result.Status)
+				assert.Equal(
+					t,
+					[]string{`Argo CD context "broken" is unavailable: connection refused`},
+					result.Issues,
+				)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -257,9 +273,21 @@ func TestSimpleEngine_executeHealthCheck(t *testing.T) {
 				},
 				nil,
 			)
+			testRegistry.RegisterHealthCheckStepRunner(
+				&mockHealthCheckStepRunner{
+					name: "argocd-check",
+					runResult: HealthCheckStepResult{
+						Status: kargoapi.HealthStateHealthy,
+					},
+				},
+				&StepRunnerPermissions{AllowArgoCDClient: true},
+			)
 
+			argoCDContexts := libargocd.NewContexts()
+			argoCDContexts.SetUnavailable("broken", errors.New("connection refused"))
 			engine := &SimpleEngine{
-				registry: testRegistry,
+				registry:       testRegistry,
+				argoCDContexts: argoCDContexts,
 			}
 
 			result := engine.executeHealthCheck(context.Background(), tt.healthCtx, tt.step)
@@ -318,14 +346,18 @@ func TestSimpleEngine_prepareHealthCheckStepContext(t *testing.T) {
 			engine := &SimpleEngine{
 				credentialsDB: &credentials.FakeDB{},
 				kargoClient:   fake.NewClientBuilder().Build(),
-				argoCDClient:  fake.NewClientBuilder().Build(),
+				argoCDContexts: libargocd.NewContexts(&libargocd.Context{
+					Namespace: "argocd",
+					Client:    fake.NewClientBuilder().Build(),
+				}),
 			}
 
 			reg := HealthCheckStepRunnerRegistration{
 				Permissions: tt.permissions,
 			}
 
-			ctx := engine.prepareHealthCheckStepContext(tt.healthCtx, tt.step, reg)
+			ctx, err := engine.prepareHealthCheckStepContext(tt.healthCtx, tt.step, reg)
+			require.NoError(t, err)
 			tt.assertions(t, ctx)
 		})
 	}
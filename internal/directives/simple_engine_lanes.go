@@ -0,0 +1,271 @@
+package directives
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// lanePathConfigKeys are the keys of step configuration whose values are paths
+// that a step may write to.
+var lanePathConfigKeys = []string{"path", "outPath"}
+
+// laneBranchConfigKeys are the keys of step configuration whose values are
+// branches that a step may check out or push to.
+var laneBranchConfigKeys = []string{"branch", "targetBranch"}
+
+// executeLanes executes steps[start:end], all of which are assigned to lanes.
+// The steps of each lane are executed in order, while different lanes are
+// executed concurrently, up to the limit configured by the PromotionContext.
+// Lanes whose steps refer to overlapping paths or to the same branch are
+// executed one after the other. Steps that finished during a previous attempt
+// to execute the block are skipped.
+//
+// Unless the PromotionContext configures lanes to fail fast, a failing lane
+// does not interrupt the others. If any lane failed, the index of the first
+// step that failed, the status of the Promotion, and the errors of all lanes
+// that failed are returned. Otherwise, if any lane is waiting, the index of
+// the first step of the block is returned, so that the block is executed
+// again on the next reconciliation.
+func (e *SimpleEngine) executeLanes(
+	ctx context.Context,
+	promoCtx PromotionContext,
+	steps []PromotionStep,
+	start, end int64,
+	exec *promotionExecution,
+	workDir string,
+) (stepOutcome, int64, kargoapi.PromotionPhase, error) {
+	// Make sure the list of metadata does not have to grow while the steps are
+	// executed concurrently.
+	exec.reserveStepExecMetas(end)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type laneFailure struct {
+		step   int64
+		status kargoapi.PromotionPhase
+		err    error
+	}
+	var (
+		mu       sync.Mutex
+		failures []laneFailure
+		waiting  bool
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, promoCtx.Lanes.GetMaxConcurrent())
+	)
+	for _, group := range groupLanes(steps, start, end) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			for _, i := range group {
+				if exec.stepFinished(i) {
+					continue
+				}
+				if shuttingDown(ctx) {
+					// Do not start another step while shutting down.
+					mu.Lock()
+					waiting = true
+					mu.Unlock()
+					return
+				}
+				if ctx.Err() != nil {
+					// Another lane failed fast.
+					return
+				}
+				outcome, status, err := e.runStep(ctx, promoCtx, steps[i], i, exec, workDir)
+				switch outcome {
+				case stepOutcomeContinue:
+					continue
+				case stepOutcomeWait:
+					mu.Lock()
+					waiting = true
+					mu.Unlock()
+				default:
+					mu.Lock()
+					failures = append(failures, laneFailure{
+						step:   i,
+						status: status,
+						err:    fmt.Errorf("lane %q: %w", steps[i].Lane, err),
+					})
+					mu.Unlock()
+					if promoCtx.Lanes.GetFailFast() {
+						cancel()
+					}
+				}
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		slices.SortFunc(failures, func(lhs, rhs laneFailure) int {
+			return int(lhs.step - rhs.step)
+		})
+		errs := make([]error, len(failures))
+		for i, failure := range failures {
+			errs[i] = failure.err
+		}
+		return stepOutcomeAbort, failures[0].step, failures[0].status, errors.Join(errs...)
+	}
+	if waiting {
+		return stepOutcomeWait, start, "", nil
+	}
+	return stepOutcomeContinue, end - 1, "", nil
+}
+
+// reserveStepExecMetas ensures that the list of StepExecutionMetadata holds
+// (possibly empty) metadata for all steps with an index lower than the
+// provided one.
+func (x *promotionExecution) reserveStepExecMetas(n int64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for int64(len(x.stepExecMetas)) < n {
+		x.stepExecMetas = append(x.stepExecMetas, kargoapi.StepExecutionMetadata{})
+	}
+}
+
+// stepFinished returns true if the step with the provided index has finished,
+// i.e. it succeeded or its failure was tolerated.
+func (x *promotionExecution) stepFinished(i int64) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return i < int64(len(x.stepExecMetas)) && x.stepExecMetas[i].FinishedAt != nil
+}
+
+// groupLanes groups steps[start:end] by the lanes they are assigned to and
+// returns the indices of the steps of each group, in order. Lanes whose steps
+// refer to overlapping paths or to the same branch are grouped together, so
+// that they are not executed concurrently. As this is determined from the
+// configuration of the steps before any expressions in it are evaluated, a
+// lane with a path or branch given by an expression is considered to conflict
+// with every other lane. Lanes may thus be grouped together unnecessarily, but
+// never the other way around.
+func groupLanes(steps []PromotionStep, start, end int64) [][]int64 {
+	var lanes []string
+	paths := map[string][]string{}
+	branches := map[string][]string{}
+	dynamic := map[string]bool{}
+	for i := start; i < end; i++ {
+		lane := steps[i].Lane
+		if !slices.Contains(lanes, lane) {
+			lanes = append(lanes, lane)
+		}
+		p, b := stepWriteTargets(steps[i].Config)
+		paths[lane] = append(paths[lane], p...)
+		branches[lane] = append(branches[lane], b...)
+		if slices.ContainsFunc(p, isExpression) || slices.ContainsFunc(b, isExpression) {
+			dynamic[lane] = true
+		}
+	}
+
+	// Merge lanes that conflict with one another into the same group.
+	group := make(map[string]int, len(lanes))
+	for i, lane := range lanes {
+		group[lane] = i
+	}
+	for i, lhs := range lanes {
+		for _, rhs := range lanes[i+1:] {
+			if group[lhs] == group[rhs] || (!dynamic[lhs] && !dynamic[rhs] &&
+				!lanesConflict(paths[lhs], paths[rhs], branches[lhs], branches[rhs])) {
+				continue
+			}
+			from, to := group[rhs], group[lhs]
+			for lane, g := range group {
+				if g == from {
+					group[lane] = to
+				}
+			}
+		}
+	}
+
+	var groups [][]int64
+	groupIndex := map[int]int{}
+	for i := start; i < end; i++ {
+		g := group[steps[i].Lane]
+		idx, ok := groupIndex[g]
+		if !ok {
+			idx = len(groups)
+			groupIndex[g] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], i)
+	}
+	return groups
+}
+
+// lanesConflict returns true if any of the provided paths of one lane overlap
+// with any of those of another lane, or if the lanes have a branch in common.
+func lanesConflict(lhsPaths, rhsPaths, lhsBranches, rhsBranches []string) bool {
+	for _, lhs := range lhsPaths {
+		for _, rhs := range rhsPaths {
+			if pathsOverlap(lhs, rhs) {
+				return true
+			}
+		}
+	}
+	for _, branch := range lhsBranches {
+		if slices.Contains(rhsBranches, branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathsOverlap returns true if the provided relative paths are the same, or if
+// one of them is contained in the other.
+func pathsOverlap(lhs, rhs string) bool {
+	lhs, rhs = filepath.Clean(lhs), filepath.Clean(rhs)
+	return lhs == rhs || lhs == "." || rhs == "." ||
+		strings.HasPrefix(lhs, rhs+string(filepath.Separator)) ||
+		strings.HasPrefix(rhs, lhs+string(filepath.Separator))
+}
+
+// isExpression returns true if the provided configuration value contains an
+// expression, whose value is not known until the step is executed.
+func isExpression(s string) bool {
+	return strings.Contains(s, "${{")
+}
+
+// stepWriteTargets returns the paths and branches found anywhere in the
+// provided step configuration.
+func stepWriteTargets(config []byte) (paths, branches []string) {
+	var cfg any
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, nil
+	}
+	var walk func(any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, val := range v {
+				if s, ok := val.(string); ok && s != "" {
+					switch {
+					case slices.Contains(lanePathConfigKeys, key):
+						paths = append(paths, s)
+					case slices.Contains(laneBranchConfigKeys, key):
+						branches = append(branches, s)
+					}
+					continue
+				}
+				walk(val)
+			}
+		case []any:
+			for _, val := range v {
+				walk(val)
+			}
+		}
+	}
+	walk(cfg)
+	return paths, branches
+}
@@ -0,0 +1,311 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestSimpleEngine_executeLanes(t *testing.T) {
+	tests := []struct {
+		name       string
+		promoCtx   PromotionContext
+		steps      []PromotionStep
+		assertions func(*testing.T, PromotionResult, error)
+	}{
+		{
+			name: "lanes are executed concurrently",
+			steps: []PromotionStep{
+				{Kind: "success-step", Alias: "before"},
+				{Kind: "barrier-step", Alias: "a", Lane: "a"},
+				{Kind: "barrier-step", Alias: "b", Lane: "b"},
+				{Kind: "success-step", Alias: "after"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+				assert.Equal(t, int64(3), result.CurrentStep)
+				require.Len(t, result.StepExecutionMetadata, 4)
+				for _, meta := range result.StepExecutionMetadata {
+					assert.Equal(t, kargoapi.PromotionPhaseSucceeded, meta.Status)
+					assert.NotNil(t, meta.FinishedAt)
+				}
+				assert.Len(t, result.State, 4)
+			},
+		},
+		{
+			name: "failing lane does not interrupt other lanes",
+			steps: []PromotionStep{
+				{Kind: "terminal-error-step", Alias: "a1", Lane: "a"},
+				{Kind: "success-step", Alias: "a2", Lane: "a"},
+				{Kind: "success-step", Alias: "b1", Lane: "b"},
+				{Kind: "success-step", Alias: "b2", Lane: "b"},
+				{Kind: "success-step", Alias: "after"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.ErrorContains(t, err, `lane "a": an unrecoverable error occurred`)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+				assert.Equal(t, int64(0), result.CurrentStep)
+				require.Len(t, result.StepExecutionMetadata, 4)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.StepExecutionMetadata[0].Status)
+				assert.Nil(t, result.StepExecutionMetadata[1].StartedAt)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.StepExecutionMetadata[2].Status)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.StepExecutionMetadata[3].Status)
+				assert.NotContains(t, result.State, "after")
+			},
+		},
+		{
+			name: "errors of all failing lanes are reported",
+			steps: []PromotionStep{
+				{Kind: "success-step", Alias: "a", Lane: "a"},
+				{Kind: "terminal-error-step", Alias: "b", Lane: "b"},
+				{Kind: "terminal-error-step", Alias: "c", Lane: "c"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.ErrorContains(t, err, `lane "b"`)
+				require.ErrorContains(t, err, `lane "c"`)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+				assert.Equal(t, int64(1), result.CurrentStep)
+			},
+		},
+		{
+			name: "tolerated failure in a lane",
+			steps: []PromotionStep{
+				{Kind: "terminal-error-step", Alias: "a1", Lane: "a", ContinueOnError: true},
+				{Kind: "success-step", Alias: "a2", Lane: "a"},
+				{Kind: "success-step", Alias: "b", Lane: "b"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+				assert.Contains(t, result.Message, "1 step(s)")
+				assert.Contains(t, result.State, "a2")
+			},
+		},
+		{
+			name: "fail fast interrupts other lanes",
+			promoCtx: PromotionContext{
+				Lanes: &kargoapi.PromotionLanes{FailFast: true},
+			},
+			steps: []PromotionStep{
+				{Kind: "terminal-error-step", Alias: "a", Lane: "a"},
+				{Kind: "context-waiter", Alias: "b1", Lane: "b"},
+				{Kind: "success-step", Alias: "b2", Lane: "b"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.ErrorContains(t, err, `lane "a"`)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+				assert.Equal(t, int64(0), result.CurrentStep)
+				require.Len(t, result.StepExecutionMetadata, 3)
+				assert.Nil(t, result.StepExecutionMetadata[2].StartedAt)
+			},
+		},
+		{
+			name: "running lane waits for the entire block",
+			steps: []PromotionStep{
+				{Kind: "running-step", Alias: "a", Lane: "a"},
+				{Kind: "success-step", Alias: "b", Lane: "b"},
+				{Kind: "success-step", Alias: "after"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseRunning, result.Status)
+				assert.Equal(t, int64(0), result.CurrentStep)
+				require.Len(t, result.StepExecutionMetadata, 2)
+				assert.Equal(t, kargoapi.PromotionPhaseRunning, result.StepExecutionMetadata[0].Status)
+				assert.Nil(t, result.StepExecutionMetadata[0].FinishedAt)
+				assert.NotNil(t, result.StepExecutionMetadata[1].FinishedAt)
+			},
+		},
+		{
+			name: "finished steps are not executed again",
+			promoCtx: PromotionContext{
+				StepExecutionMetadata: kargoapi.StepExecutionMetadataList{
+					{},
+					{
+						Alias:      "b",
+						Status:     kargoapi.PromotionPhaseSucceeded,
+						StartedAt:  ptr.To(metav1.Now()),
+						FinishedAt: ptr.To(metav1.Now()),
+					},
+				},
+			},
+			steps: []PromotionStep{
+				{Kind: "success-step", Alias: "a", Lane: "a"},
+				// This step must have already succeeded and should not be run again
+				{Kind: "error-step", Alias: "b", Lane: "b"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+				assert.Equal(t, State{
+					"a": map[string]any{"key": "value"},
+				}, result.State)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barrier := &sync.WaitGroup{}
+			barrier.Add(2)
+
+			testRegistry := NewStepRunnerRegistry()
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "success-step",
+					runResult: PromotionStepResult{
+						Status: kargoapi.PromotionPhaseSucceeded,
+						Output: map[string]any{"key": "value"},
+					},
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "barrier-step",
+					runFunc: func(context.Context, *PromotionStepContext) (PromotionStepResult, error) {
+						// Only succeeds if another step arrives at the barrier while
+						// this one is running.
+						barrier.Done()
+						done := make(chan struct{})
+						go func() {
+							barrier.Wait()
+							close(done)
+						}()
+						select {
+						case <-done:
+							return PromotionStepResult{Status: kargoapi.PromotionPhaseSucceeded}, nil
+						case <-time.After(5 * time.Second):
+							return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+								&terminalError{err: errors.New("steps were not executed concurrently")}
+						}
+					},
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "running-step",
+					runResult: PromotionStepResult{
+						Status: kargoapi.PromotionPhaseRunning,
+					},
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name:      "error-step",
+					runResult: PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					runErr:    errors.New("something went wrong"),
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name:      "terminal-error-step",
+					runResult: PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					runErr:    &terminalError{err: errors.New("something went wrong")},
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "context-waiter",
+					runFunc: func(ctx context.Context, _ *PromotionStepContext) (PromotionStepResult, error) {
+						<-ctx.Done()
+						return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, ctx.Err()
+					},
+				},
+				&StepRunnerPermissions{},
+			)
+
+			engine := &SimpleEngine{
+				registry:    testRegistry,
+				kargoClient: fake.NewClientBuilder().Build(),
+			}
+
+			result, err := engine.executeSteps(context.Background(), tt.promoCtx, tt.steps, t.TempDir())
+			tt.assertions(t, result, err)
+		})
+	}
+}
+
+func Test_groupLanes(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []PromotionStep
+		expected [][]int64
+	}{
+		{
+			name: "independent lanes",
+			steps: []PromotionStep{
+				{Lane: "a", Config: []byte(`{"path":"./dev"}`)},
+				{Lane: "b", Config: []byte(`{"path":"prod"}`)},
+				{Lane: "a", Config: []byte(`{"outPath":"dev/all.yaml"}`)},
+				{Lane: "c"},
+			},
+			expected: [][]int64{{0, 2}, {1}, {3}},
+		},
+		{
+			name: "overlapping paths",
+			steps: []PromotionStep{
+				{Lane: "a", Config: []byte(`{"path":"out"}`)},
+				{Lane: "b", Config: []byte(`{"images":[{"image":"nginx"}],"path":"prod"}`)},
+				{Lane: "c", Config: []byte(`{"outPath":"out/c/all.yaml"}`)},
+			},
+			expected: [][]int64{{0, 2}, {1}},
+		},
+		{
+			name: "same branch",
+			steps: []PromotionStep{
+				{Lane: "a", Config: []byte(`{"checkout":[{"branch":"stage/dev","path":"dev"}]}`)},
+				{Lane: "b", Config: []byte(`{"path":"prod","targetBranch":"stage/dev"}`)},
+				{Lane: "c", Config: []byte(`{"path":"test","targetBranch":"stage/test"}`)},
+			},
+			expected: [][]int64{{0, 1}, {2}},
+		},
+		{
+			name: "transitively conflicting lanes",
+			steps: []PromotionStep{
+				{Lane: "a", Config: []byte(`{"path":"a"}`)},
+				{Lane: "b", Config: []byte(`{"path":"b"}`)},
+				{Lane: "c", Config: []byte(`{"path":"c"}`)},
+				{Lane: "c", Config: []byte(`{"outPath":"b/all.yaml"}`)},
+				{Lane: "c", Config: []byte(`{"outPath":"a/all.yaml"}`)},
+			},
+			expected: [][]int64{{0, 1, 2, 3, 4}},
+		},
+		{
+			name: "path given by an expression",
+			steps: []PromotionStep{
+				{Lane: "a", Config: []byte(`{"path":"a"}`)},
+				{Lane: "b", Config: []byte(`{"path":"${{ vars.path }}"}`)},
+				{Lane: "c", Config: []byte(`{"path":"c"}`)},
+			},
+			expected: [][]int64{{0, 1, 2}},
+		},
+		{
+			name: "branch given by an expression",
+			steps: []PromotionStep{
+				{Lane: "a", Config: []byte(`{"path":"a","targetBranch":"stage/${{ ctx.stage }}"}`)},
+				{Lane: "b", Config: []byte(`{"path":"b"}`)},
+			},
+			expected: [][]int64{{0, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, groupLanes(tt.steps, 0, int64(len(tt.steps))))
+		})
+	}
+}
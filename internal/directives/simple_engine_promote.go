@@ -3,8 +3,12 @@ package directives
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,6 +16,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/credentials"
+	libExec "github.com/akuity/kargo/internal/exec"
+	"github.com/akuity/kargo/internal/logging"
 )
 
 // Promote implements the Engine interface.
@@ -32,7 +39,25 @@ func (e *SimpleEngine) Promote(
 		return PromotionResult{Status: kargoapi.PromotionPhaseErrored}, err
 	}
 
+	timings, untrack := libExec.TrackTimings(workDir)
+	defer untrack()
+	transcript := e.transcriptFor(workDir)
+	untrackTranscript := transcript.Track()
+	start := time.Now()
 	result, err := e.executeSteps(ctx, promoCtx, steps, workDir)
+	untrackTranscript()
+	logOperationTimings(ctx, timings, time.Since(start))
+	if result.Status.IsTerminal() {
+		e.forgetTranscript(workDir)
+		logging.LoggerFromContext(ctx).Debug(
+			"promotion command transcript",
+			"transcript", transcript.String(),
+		)
+		switch result.Status {
+		case kargoapi.PromotionPhaseErrored, kargoapi.PromotionPhaseFailed:
+			result.Transcript = transcript.String()
+		}
+	}
 	if err != nil {
 		return result, fmt.Errorf("step execution failed: %w", err)
 	}
@@ -40,7 +65,75 @@ func (e *SimpleEngine) Promote(
 	return result, nil
 }
 
-// executeSteps executes a list of PromotionSteps in sequence.
+// staleTranscriptAge is the age after which the transcript of a promotion
+// that has not been continued is discarded. This prevents the transcripts of
+// promotions that were aborted or deleted while in progress from accumulating.
+const staleTranscriptAge = 24 * time.Hour
+
+// promotionTranscript is the transcript of the commands executed by a
+// promotion so far, which is retained across the reconciliations of a
+// promotion that remains in progress.
+type promotionTranscript struct {
+	transcript *libExec.Transcript
+	lastUsed   time.Time
+}
+
+// transcriptFor returns the transcript of the commands executed by the
+// promotion that uses the provided working directory, creating it if the
+// promotion has not executed any steps before. Stale transcripts of other
+// promotions are discarded.
+func (e *SimpleEngine) transcriptFor(workDir string) *libExec.Transcript {
+	e.transcriptsMu.Lock()
+	defer e.transcriptsMu.Unlock()
+	now := time.Now()
+	if e.transcripts == nil {
+		e.transcripts = map[string]*promotionTranscript{}
+	}
+	maps.DeleteFunc(e.transcripts, func(_ string, t *promotionTranscript) bool {
+		return now.Sub(t.lastUsed) > staleTranscriptAge
+	})
+	t, ok := e.transcripts[workDir]
+	if !ok {
+		t = &promotionTranscript{transcript: libExec.NewTranscript(workDir)}
+		e.transcripts[workDir] = t
+	}
+	t.lastUsed = now
+	return t.transcript
+}
+
+// forgetTranscript discards the transcript of the commands executed by the
+// promotion that uses the provided working directory.
+func (e *SimpleEngine) forgetTranscript(workDir string) {
+	e.transcriptsMu.Lock()
+	defer e.transcriptsMu.Unlock()
+	delete(e.transcripts, workDir)
+}
+
+// logOperationTimings logs a summary of the share of the provided total
+// duration of a promotion that was spent on each operation, such as a git
+// clone, that was executed as a command, if there were any. As steps assigned
+// to lanes run concurrently, the shares do not necessarily add up to 100%.
+func logOperationTimings(ctx context.Context, timings *libExec.Timings, total time.Duration) {
+	ops := timings.Get()
+	if len(ops) == 0 || total <= 0 {
+		return
+	}
+	summary := make([]string, len(ops))
+	for i, op := range ops {
+		summary[i] = fmt.Sprintf(
+			"%d%% in %s (%s)",
+			op.Duration*100/total, op.Operation, op.Duration.Round(time.Millisecond),
+		)
+	}
+	logging.LoggerFromContext(ctx).Info(
+		"promotion time spent on operations",
+		"duration", total.Round(time.Millisecond).String(),
+		"summary", "promotion spent "+strings.Join(summary, ", "),
+	)
+}
+
+// executeSteps executes a list of PromotionSteps in sequence. Consecutive
+// PromotionSteps that are assigned to lanes are executed by executeLanes.
 func (e *SimpleEngine) executeSteps(
 	ctx context.Context,
 	promoCtx PromotionContext,
@@ -55,224 +148,555 @@ func (e *SimpleEngine) executeSteps(
 	if state == nil {
 		state = make(State)
 	}
-
-	var (
-		healthChecks  []HealthCheckStep
-		err           error
-		stepExecMetas = promoCtx.StepExecutionMetadata.DeepCopy()
-	)
+	exec := &promotionExecution{
+		state:         state,
+		stepExecMetas: promoCtx.StepExecutionMetadata.DeepCopy(),
+		healthChecks:  map[int64]HealthCheckStep{},
+		overlays:      slices.Clone(promoCtx.Overlays),
+		renderedPush:  promoCtx.RenderedBranchPush.DeepCopy(),
+		retained:      slices.Clone(promoCtx.RetainedImages),
+		warnings:      slices.Clone(promoCtx.RenderWarnings),
+	}
 
 	// Execute each step in sequence, starting from the step index
 	// specified in the PromotionContext if provided.
-	for i := promoCtx.StartFromStep; i < int64(len(steps)); i++ {
+	for i := promoCtx.StartFromStep; i < int64(len(steps)); {
+		if shuttingDown(ctx) {
+			// Do not start another step while shutting down. As no step is in
+			// progress, this is a safe point from which the promotion process can
+			// be resumed on the next reconciliation.
+			logging.LoggerFromContext(ctx).Info(
+				"stopping promotion process at step boundary due to shutdown",
+				"step", i,
+			)
+			return exec.result(kargoapi.PromotionPhaseRunning, i), nil
+		}
 		select {
 		case <-ctx.Done():
-			return PromotionResult{
-				Status:                kargoapi.PromotionPhaseErrored,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, ctx.Err()
+			return exec.result(kargoapi.PromotionPhaseErrored, i), ctx.Err()
 		default:
 		}
 
-		// Prepare the step for execution by setting the alias.
-		step := steps[i]
-		if step.Alias, err = e.stepAlias(step.Alias, i); err != nil {
-			return PromotionResult{
-				Status:                kargoapi.PromotionPhaseErrored,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, fmt.Errorf("error getting step alias for step %d: %w", i, err)
+		var (
+			outcome stepOutcome
+			current = i
+			status  kargoapi.PromotionPhase
+			err     error
+			next    = i + 1
+		)
+		if steps[i].Lane != "" {
+			// Execute the block of consecutive steps that are assigned to lanes.
+			for next < int64(len(steps)) && steps[next].Lane != "" {
+				next++
+			}
+			outcome, current, status, err = e.executeLanes(ctx, promoCtx, steps, i, next, exec, workDir)
+		} else {
+			outcome, status, err = e.runStep(ctx, promoCtx, steps[i], i, exec, workDir)
 		}
 
-		// Get the PromotionStepRunner for the step.
-		reg, err := e.registry.GetPromotionStepRunnerRegistration(step.Kind)
-		if err != nil {
-			return PromotionResult{
-				Status:                kargoapi.PromotionPhaseErrored,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, fmt.Errorf("error getting runner for step %d: %w", i, err)
+		switch outcome {
+		case stepOutcomeContinue:
+			i = next
+		case stepOutcomeWait:
+			// The step is either Running (waiting for some external condition to
+			// be met) or it Errored/Failed but may be retried. Either way, the
+			// Promotion will be requeued and the step will be executed again on
+			// the next reconciliation.
+			return exec.result(kargoapi.PromotionPhaseRunning, current), nil
+		default:
+			return exec.result(status, current), err
 		}
+	}
 
-		// If we don't have metadata for this step yet, create it.
-		if int64(len(stepExecMetas)) == i {
-			stepExecMetas = append(stepExecMetas, kargoapi.StepExecutionMetadata{
-				Alias:     step.Alias,
-				StartedAt: ptr.To(metav1.Now()),
-			})
+	// All steps have succeeded (or their failure was tolerated), return the
+	// final state.
+	var message string
+	var tolerated int
+	for _, meta := range exec.stepExecMetas {
+		switch meta.Status {
+		case kargoapi.PromotionPhaseErrored, kargoapi.PromotionPhaseFailed:
+			tolerated++
 		}
-		stepExecMeta := &stepExecMetas[i]
-
-		// Execute the step
-		result, err := e.executeStep(ctx, promoCtx, step, reg, workDir, state)
-		stepExecMeta.Status = result.Status
-		stepExecMeta.Message = result.Message
-
-		// TODO(hidde): until we have a better way to handle the output of steps
-		// inflated from tasks, we need to apply a special treatment to the output
-		// to allow it to become available under the alias of the "task".
-		aliasNamespace := getAliasNamespace(step.Alias)
-		if aliasNamespace != "" && reg.Runner.Name() == (&outputComposer{}).Name() {
-			if state[aliasNamespace] == nil {
-				state[aliasNamespace] = make(map[string]any)
-			}
-			for k, v := range result.Output {
-				state[aliasNamespace].(map[string]any)[k] = v // nolint: forcetypeassert
-			}
-		} else {
-			state[step.Alias] = result.Output
+	}
+	if tolerated > 0 {
+		message = fmt.Sprintf(
+			"%d step(s) failed but were configured to continue on error",
+			tolerated,
+		)
+	}
+	result := exec.result(kargoapi.PromotionPhaseSucceeded, int64(len(steps))-1)
+	result.Message = message
+	return result, nil
+}
+
+// stepOutcome describes how the execution of a promotion process proceeds
+// after an attempt to execute one of its steps.
+type stepOutcome int
+
+const (
+	// stepOutcomeContinue indicates that the step succeeded, or that its
+	// failure is tolerated, and that execution continues with the next step.
+	stepOutcomeContinue stepOutcome = iota
+	// stepOutcomeWait indicates that the step is still running, or that it
+	// failed in a way that may be retried, and that it is to be executed again
+	// on the next reconciliation.
+	stepOutcomeWait
+	// stepOutcomeAbort indicates that the step failed and that the promotion
+	// process is to be aborted.
+	stepOutcomeAbort
+)
+
+// promotionExecution records the progress of the execution of a promotion
+// process. As the steps of different lanes may be executed concurrently, its
+// fields must only be accessed while holding its lock, with the exception of
+// the elements of stepExecMetas, each of which is only ever accessed by the
+// step it belongs to.
+type promotionExecution struct {
+	mu            sync.Mutex
+	state         State
+	stepExecMetas kargoapi.StepExecutionMetadataList
+	healthChecks  map[int64]HealthCheckStep
+	repoDecisions []kargoapi.RepoPolicyDecision
+	overlays      []kargoapi.PromotedOverlay
+	renderedPush  *kargoapi.RenderedBranchPush
+	externalMod   *kargoapi.ExternalModification
+	syncCondition *metav1.Condition
+	outputIgnored *metav1.Condition
+	retained      []kargoapi.RetainedImage
+	sourceBatch   *kargoapi.SourceUpdateBatch
+	warnings      []string
+}
+
+// stepExecMeta returns the StepExecutionMetadata of the step with the provided
+// index, creating it if necessary. Metadata is created for all steps up to
+// the provided index, so that the metadata of any step with a lower index can
+// subsequently be accessed without the list having to grow.
+func (x *promotionExecution) stepExecMeta(i int64, alias string) *kargoapi.StepExecutionMetadata {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for int64(len(x.stepExecMetas)) <= i {
+		x.stepExecMetas = append(x.stepExecMetas, kargoapi.StepExecutionMetadata{})
+	}
+	meta := &x.stepExecMetas[i]
+	if meta.StartedAt == nil {
+		meta.Alias = alias
+		meta.StartedAt = ptr.To(metav1.Now())
+	}
+	return meta
+}
+
+// snapshotState returns a copy of the current state.
+func (x *promotionExecution) snapshotState() State {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.state.DeepCopy()
+}
+
+// recordOutput records the output of the provided step in the state.
+func (x *promotionExecution) recordOutput(step PromotionStep, runnerName string, output map[string]any) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	// TODO(hidde): until we have a better way to handle the output of steps
+	// inflated from tasks, we need to apply a special treatment to the output
+	// to allow it to become available under the alias of the "task".
+	aliasNamespace := getAliasNamespace(step.Alias)
+	if aliasNamespace != "" && runnerName == (&outputComposer{}).Name() {
+		if x.state[aliasNamespace] == nil {
+			x.state[aliasNamespace] = make(map[string]any)
 		}
+		for k, v := range output {
+			x.state[aliasNamespace].(map[string]any)[k] = v // nolint: forcetypeassert
+		}
+	} else {
+		x.state[step.Alias] = output
+	}
+}
 
-		switch result.Status {
-		case kargoapi.PromotionPhaseErrored, kargoapi.PromotionPhaseFailed,
-			kargoapi.PromotionPhaseRunning, kargoapi.PromotionPhaseSucceeded:
-		default:
-			// Deal with statuses that no step should have returned.
-			stepExecMeta.FinishedAt = ptr.To(metav1.Now())
-			return PromotionResult{
-				Status:                kargoapi.PromotionPhaseErrored,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, fmt.Errorf("step %d returned an invalid status", i)
+// recordHealthCheck records the HealthCheckStep of the step with the provided
+// index.
+func (x *promotionExecution) recordHealthCheck(i int64, healthCheck HealthCheckStep) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.healthChecks[i] = healthCheck
+}
+
+// recordRepoPolicyDecision records the provided RepoPolicyDecision, replacing
+// any decision that was previously recorded for the same repository.
+func (x *promotionExecution) recordRepoPolicyDecision(decision kargoapi.RepoPolicyDecision) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for i, d := range x.repoDecisions {
+		if d.RepoURL == decision.RepoURL {
+			x.repoDecisions[i] = decision
+			return
 		}
+	}
+	x.repoDecisions = append(x.repoDecisions, decision)
+}
 
-		// Reconcile status and err...
-		if err != nil {
-			if stepExecMeta.Status != kargoapi.PromotionPhaseFailed {
-				// All states other than Errored and Failed should be mutually exclusive
-				// with a hard error. If we got to here, a step has violated this
-				// assumption. We will prioritize the error over the status and change
-				// the status to Errored.
-				stepExecMeta.Status = kargoapi.PromotionPhaseErrored
-			}
-			// Let the hard error take precedence over the message.
-			stepExecMeta.Message = err.Error()
-		} else if result.Status == kargoapi.PromotionPhaseErrored {
-			// A nil err should be mutually exclusive with an Errored status. If we
-			// got to here, a step has violated this assumption. We will prioritize
-			// the Errored status over the nil error and create an error.
-			message := stepExecMeta.Message
-			if message == "" {
-				message = "no details provided"
-			}
-			err = fmt.Errorf("step %d errored: %s", i, message)
+// snapshotOverlays returns a copy of the current overlays.
+func (x *promotionExecution) snapshotOverlays() []kargoapi.PromotedOverlay {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return slices.Clone(x.overlays)
+}
+
+// recordOverlays records the provided overlays, replacing the overlays with
+// the same names.
+func (x *promotionExecution) recordOverlays(overlays []kargoapi.PromotedOverlay) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, overlay := range overlays {
+		i := slices.IndexFunc(x.overlays, func(o kargoapi.PromotedOverlay) bool {
+			return o.Name == overlay.Name
+		})
+		if i < 0 {
+			x.overlays = append(x.overlays, overlay)
+			continue
 		}
+		x.overlays[i] = overlay
+	}
+}
 
-		// At this point, we've sorted out any discrepancies between the status and
-		// err.
+// recordRenderedBranchPush records the provided push to the rendered branch
+// of the Stage. The commit the branch pointed to before the first push is
+// retained, so that the branch can be reset to it.
+func (x *promotionExecution) recordRenderedBranchPush(push *kargoapi.RenderedBranchPush) {
+	if push == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if prev := x.renderedPush; prev != nil &&
+		prev.RepoURL == push.RepoURL && prev.Branch == push.Branch {
+		push = push.DeepCopy()
+		push.PreviousCommit = prev.PreviousCommit
+	}
+	x.renderedPush = push
+}
 
-		switch {
-		case stepExecMeta.Status == kargoapi.PromotionPhaseSucceeded:
-			// Best case scenario: The step succeeded.
-			stepExecMeta.FinishedAt = ptr.To(metav1.Now())
-			if healthCheck := result.HealthCheckStep; healthCheck != nil {
-				healthChecks = append(healthChecks, *healthCheck)
-			}
-			continue // Move on to the next step
-		case isTerminal(err):
-			// This is an unrecoverable error.
-			stepExecMeta.FinishedAt = ptr.To(metav1.Now())
-			return PromotionResult{
-				Status:                stepExecMeta.Status,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, fmt.Errorf("an unrecoverable error occurred: %w", err)
-		case err != nil:
-			// If we get to here, the error is POTENTIALLY recoverable.
-			stepExecMeta.ErrorCount++
-			// Check if the error threshold has been met.
-			errorThreshold := step.GetErrorThreshold(reg.Runner)
-			if stepExecMeta.ErrorCount >= errorThreshold {
-				// The error threshold has been met.
-				stepExecMeta.FinishedAt = ptr.To(metav1.Now())
-				return PromotionResult{
-						Status:                kargoapi.PromotionPhaseErrored,
-						CurrentStep:           i,
-						StepExecutionMetadata: stepExecMetas,
-						State:                 state,
-						HealthCheckSteps:      healthChecks,
-					}, fmt.Errorf(
-						"step %d met error threshold of %d: %s", i,
-						errorThreshold, stepExecMeta.Message,
-					)
-			}
+// recordExternalModification records the provided modification of the
+// rendered branch of the Stage.
+func (x *promotionExecution) recordExternalModification(mod *kargoapi.ExternalModification) {
+	if mod == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.externalMod = mod
+}
+
+// recordSyncCondition records the provided outcome of a sync of Argo CD
+// Applications.
+func (x *promotionExecution) recordSyncCondition(cond *metav1.Condition) {
+	if cond == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.syncCondition = cond
+}
+
+// recordOutputIgnoredCondition records the provided description of rendered
+// manifests that were written to files not read by the Argo CD Application
+// they were rendered for. Descriptions recorded by multiple steps are
+// combined.
+func (x *promotionExecution) recordOutputIgnoredCondition(cond *metav1.Condition) {
+	if cond == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.outputIgnored == nil {
+		x.outputIgnored = cond.DeepCopy()
+		return
+	}
+	x.outputIgnored.Message += "; " + cond.Message
+}
+
+// recordRetainedImages records the versions of the provided images that were
+// found to be left in place, replacing any versions previously recorded for
+// the same repositories.
+func (x *promotionExecution) recordRetainedImages(images []kargoapi.RetainedImage) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, image := range images {
+		i := slices.IndexFunc(x.retained, func(r kargoapi.RetainedImage) bool {
+			return r.RepoURL == image.RepoURL
+		})
+		if i < 0 {
+			x.retained = append(x.retained, image)
+			continue
+		}
+		x.retained[i] = image
+	}
+}
+
+// recordSourceUpdateBatch records the provided batch of updates to a branch
+// of a Git repository.
+func (x *promotionExecution) recordSourceUpdateBatch(batch *kargoapi.SourceUpdateBatch) {
+	if batch == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.sourceBatch = batch
+}
+
+// recordRenderWarnings records those of the provided warnings that were not
+// recorded before, so that warnings emitted every time the same manifests are
+// rendered, e.g. by steps that are retried, are only recorded once.
+func (x *promotionExecution) recordRenderWarnings(warnings []string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.warnings = appendRenderWarnings(x.warnings, warnings...)
+}
+
+// renderedBranchCommit returns the commit that Kargo last pushed to the
+// rendered branch of the Stage. This is the commit most recently pushed by
+// this execution, if it has pushed to the branch the provided commit belongs
+// to, and the provided commit, which was recorded before the promotion began,
+// otherwise.
+func (x *promotionExecution) renderedBranchCommit(
+	recorded *kargoapi.RenderedBranchCommit,
+) *kargoapi.RenderedBranchCommit {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	push := x.renderedPush
+	if push == nil || push.Commit == "" ||
+		(recorded != nil && (recorded.RepoURL != push.RepoURL || recorded.Branch != push.Branch)) {
+		return recorded.DeepCopy()
+	}
+	return &kargoapi.RenderedBranchCommit{
+		RepoURL: push.RepoURL,
+		Branch:  push.Branch,
+		Commit:  push.Commit,
+	}
+}
+
+// result returns a PromotionResult with the provided status and current step
+// that reflects the progress of the execution. HealthCheckSteps are ordered by
+// the index of the step they belong to, regardless of the order in which the
+// steps were executed.
+func (x *promotionExecution) result(status kargoapi.PromotionPhase, currentStep int64) PromotionResult {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var healthChecks []HealthCheckStep
+	for _, i := range slices.Sorted(maps.Keys(x.healthChecks)) {
+		healthChecks = append(healthChecks, x.healthChecks[i])
+	}
+	return PromotionResult{
+		Status:                 status,
+		CurrentStep:            currentStep,
+		StepExecutionMetadata:  x.stepExecMetas,
+		State:                  x.state,
+		HealthCheckSteps:       healthChecks,
+		RepoPolicyDecisions:    x.repoDecisions,
+		Overlays:               x.overlays,
+		RenderedBranchPush:     x.renderedPush,
+		ExternalModification:   x.externalMod,
+		SyncCondition:          x.syncCondition,
+		OutputIgnoredCondition: x.outputIgnored,
+		RetainedImages:         x.retained,
+		SourceUpdateBatch:      x.sourceBatch,
+		RenderWarnings:         x.warnings,
+	}
+}
+
+// runStep executes the provided step, which has the provided index, and
+// records the results in the provided promotionExecution. It returns how the
+// execution of the promotion process proceeds. If it is to be aborted, the
+// status of the Promotion and the error that caused the abort are returned as
+// well.
+func (e *SimpleEngine) runStep(
+	ctx context.Context,
+	promoCtx PromotionContext,
+	step PromotionStep,
+	i int64,
+	exec *promotionExecution,
+	workDir string,
+) (stepOutcome, kargoapi.PromotionPhase, error) {
+	// Prepare the step for execution by setting the alias.
+	var err error
+	if step.Alias, err = e.stepAlias(step.Alias, i); err != nil {
+		return stepOutcomeAbort, kargoapi.PromotionPhaseErrored,
+			fmt.Errorf("error getting step alias for step %d: %w", i, err)
+	}
+
+	// Get the PromotionStepRunner for the step.
+	reg, err := e.registry.GetPromotionStepRunnerRegistration(step.Kind)
+	if err != nil {
+		return stepOutcomeAbort, kargoapi.PromotionPhaseErrored,
+			fmt.Errorf("error getting runner for step %d: %w", i, err)
+	}
+
+	// If we don't have metadata for this step yet, create it.
+	stepExecMeta := exec.stepExecMeta(i, step.Alias)
+
+	// Execute the step, unless a failure is to be injected before it is run
+	var result PromotionStepResult
+	inject := promoCtx.FailureInjection
+	if inject.beforeStep(step.Kind, stepExecMeta.ErrorCount) {
+		result, err = inject.fail(PromotionStepResult{})
+	} else {
+		result, err = e.executeStep(ctx, promoCtx, step, reg, workDir, exec)
+		if err == nil && inject.afterStep(step.Kind, result, stepExecMeta.ErrorCount) {
+			result, err = inject.fail(result)
+		}
+	}
+	if err != nil && shuttingDown(ctx) && ctx.Err() != nil {
+		// The step was interrupted because the grace period for shutdown
+		// elapsed. This is not the step's fault, so it does not count toward
+		// its error threshold. It will be executed again on the next
+		// reconciliation.
+		stepExecMeta.Status = kargoapi.PromotionPhaseRunning
+		stepExecMeta.Message = fmt.Sprintf(
+			"step was interrupted by shutdown: %v; step will be retried", err,
+		)
+		return stepOutcomeWait, "", nil
+	}
+	stepExecMeta.Status = result.Status
+	stepExecMeta.Message = credentials.Redact(result.Message)
+
+	exec.recordOutput(step, reg.Runner.Name(), result.Output)
+	exec.recordOverlays(result.Overlays)
+	exec.recordRenderedBranchPush(result.RenderedBranchPush)
+	exec.recordExternalModification(result.ExternalModification)
+	exec.recordSyncCondition(result.SyncCondition)
+	exec.recordOutputIgnoredCondition(result.OutputIgnoredCondition)
+	exec.recordRetainedImages(result.RetainedImages)
+	exec.recordSourceUpdateBatch(result.SourceUpdateBatch)
+	exec.recordRenderWarnings(result.RenderWarnings)
+
+	switch result.Status {
+	case kargoapi.PromotionPhaseErrored, kargoapi.PromotionPhaseFailed,
+		kargoapi.PromotionPhaseRunning, kargoapi.PromotionPhaseSucceeded:
+	default:
+		// Deal with statuses that no step should have returned.
+		stepExecMeta.FinishedAt = ptr.To(metav1.Now())
+		return stepOutcomeAbort, kargoapi.PromotionPhaseErrored,
+			fmt.Errorf("step %d returned an invalid status", i)
+	}
+
+	// Reconcile status and err...
+	if err != nil {
+		if stepExecMeta.Status != kargoapi.PromotionPhaseFailed {
+			// All states other than Errored and Failed should be mutually exclusive
+			// with a hard error. If we got to here, a step has violated this
+			// assumption. We will prioritize the error over the status and change
+			// the status to Errored.
+			stepExecMeta.Status = kargoapi.PromotionPhaseErrored
 		}
+		// Let the hard error take precedence over the message.
+		stepExecMeta.Message = credentials.Redact(err.Error())
+	} else if result.Status == kargoapi.PromotionPhaseErrored {
+		// A nil err should be mutually exclusive with an Errored status. If we
+		// got to here, a step has violated this assumption. We will prioritize
+		// the Errored status over the nil error and create an error.
+		message := stepExecMeta.Message
+		if message == "" {
+			message = "no details provided"
+		}
+		err = fmt.Errorf("step %d errored: %s", i, message)
+	}
 
-		// If we get to here, the step is either Running (waiting for some external
-		// condition to be met) or it Errored/Failed but did not meet the error
-		// threshold. Now we need to check if the timeout has elapsed. A nil timeout
-		// or any non-positive timeout interval are treated as NO timeout, although
-		// a nil timeout really shouldn't happen.
-		timeout := step.GetTimeout(reg.Runner)
-		if timeout != nil && *timeout > 0 && metav1.Now().Sub(stepExecMeta.StartedAt.Time) > *timeout {
-			// Timeout has elapsed.
+	// At this point, we've sorted out any discrepancies between the status and
+	// err.
+
+	switch {
+	case stepExecMeta.Status == kargoapi.PromotionPhaseSucceeded:
+		// Best case scenario: The step succeeded.
+		stepExecMeta.FinishedAt = ptr.To(metav1.Now())
+		if healthCheck := result.HealthCheckStep; healthCheck != nil {
+			exec.recordHealthCheck(i, *healthCheck)
+		}
+		return stepOutcomeContinue, "", nil // Move on to the next step
+	case isTerminal(err):
+		// This is an unrecoverable error.
+		stepExecMeta.FinishedAt = ptr.To(metav1.Now())
+		// Attempts to access repositories that are not allowed are never
+		// tolerated.
+		if step.ContinueOnError && !IsRepoNotAllowed(err) {
+			// The failure of this step is tolerated. Move on to the next step.
+			return stepOutcomeContinue, "", nil
+		}
+		return stepOutcomeAbort, stepExecMeta.Status,
+			fmt.Errorf("an unrecoverable error occurred: %w", err)
+	case err != nil:
+		// If we get to here, the error is POTENTIALLY recoverable.
+		stepExecMeta.ErrorCount++
+		// Check if the error threshold has been met.
+		errorThreshold := step.GetErrorThreshold(reg.Runner)
+		if stepExecMeta.ErrorCount >= errorThreshold {
+			// The error threshold has been met.
 			stepExecMeta.FinishedAt = ptr.To(metav1.Now())
-			return PromotionResult{
-				Status:                kargoapi.PromotionPhaseErrored,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, fmt.Errorf("step %d timeout of %s has elapsed", i, timeout.String())
+			if step.ContinueOnError {
+				// The failure of this step is tolerated. Move on to the next step.
+				return stepOutcomeContinue, "", nil
+			}
+			return stepOutcomeAbort, kargoapi.PromotionPhaseErrored, fmt.Errorf(
+				"step %d met error threshold of %d: %s", i,
+				errorThreshold, stepExecMeta.Message,
+			)
 		}
+	}
+
+	stepExecMeta.WaitUntil = nil
+	if err == nil && result.WaitUntil != nil {
+		// The step is waiting for a point in time. As time spent waiting this way
+		// does not count toward the step's timeout, the timeout is measured from
+		// the last time the step waited.
+		stepExecMeta.StartedAt = ptr.To(metav1.Now())
+		stepExecMeta.WaitUntil = &metav1.Time{Time: *result.WaitUntil}
+	}
 
-		if err != nil {
-			// Treat Errored/Failed as if the step is still running so that the
-			// Promotion will be requeued. The step will be retried on the next
-			// reconciliation.
-			stepExecMeta.Message += "; step will be retried"
-			return PromotionResult{
-				Status:                kargoapi.PromotionPhaseRunning,
-				CurrentStep:           i,
-				StepExecutionMetadata: stepExecMetas,
-				State:                 state,
-				HealthCheckSteps:      healthChecks,
-			}, nil
+	// If we get to here, the step is either Running (waiting for some external
+	// condition to be met) or it Errored/Failed but did not meet the error
+	// threshold. Now we need to check if the timeout has elapsed. A nil timeout
+	// or any non-positive timeout interval are treated as NO timeout, although
+	// a nil timeout really shouldn't happen.
+	timeout := step.GetTimeout(reg.Runner)
+	if timeout != nil && *timeout > 0 && metav1.Now().Sub(stepExecMeta.StartedAt.Time) > *timeout {
+		// Timeout has elapsed.
+		stepExecMeta.FinishedAt = ptr.To(metav1.Now())
+		if step.ContinueOnError {
+			// The failure of this step is tolerated. Record the timeout and move
+			// on to the next step.
+			stepExecMeta.Status = kargoapi.PromotionPhaseErrored
+			stepExecMeta.Message = fmt.Sprintf("step %d timeout of %s has elapsed", i, timeout.String())
+			return stepOutcomeContinue, "", nil
 		}
+		return stepOutcomeAbort, kargoapi.PromotionPhaseErrored,
+			fmt.Errorf("step %d timeout of %s has elapsed", i, timeout.String())
+	}
 
-		// If we get to here, the step is still Running (waiting for some external
-		// condition to be met).
-		stepExecMeta.ErrorCount = 0 // Reset the error count
-		return PromotionResult{
-			Status:                kargoapi.PromotionPhaseRunning,
-			CurrentStep:           i,
-			StepExecutionMetadata: stepExecMetas,
-			State:                 state,
-			HealthCheckSteps:      healthChecks,
-		}, nil
+	if err != nil {
+		// Treat Errored/Failed as if the step is still running so that the
+		// Promotion will be requeued. The step will be retried on the next
+		// reconciliation.
+		stepExecMeta.Message += "; step will be retried"
+		return stepOutcomeWait, "", nil
 	}
 
-	// All steps have succeeded, return the final state.
-	return PromotionResult{
-		Status:                kargoapi.PromotionPhaseSucceeded,
-		CurrentStep:           int64(len(steps)) - 1,
-		StepExecutionMetadata: stepExecMetas,
-		State:                 state,
-		HealthCheckSteps:      healthChecks,
-	}, nil
+	// If we get to here, the step is still Running (waiting for some external
+	// condition to be met).
+	stepExecMeta.ErrorCount = 0 // Reset the error count
+	return stepOutcomeWait, "", nil
 }
 
-// executeStep executes a single PromotionStep.
+// executeStep executes a single PromotionStep as part of the provided
+// promotionExecution.
 func (e *SimpleEngine) executeStep(
 	ctx context.Context,
 	promoCtx PromotionContext,
 	step PromotionStep,
 	reg PromotionStepRunnerRegistration,
 	workDir string,
-	state State,
+	exec *promotionExecution,
 ) (PromotionStepResult, error) {
-	stepCtx, err := e.preparePromotionStepContext(ctx, promoCtx, step, reg.Permissions, workDir, state)
+	stepCtx, err := e.preparePromotionStepContext(
+		ctx, promoCtx, step, reg.Permissions, workDir, exec.snapshotState(),
+	)
 	if err != nil {
 		// TODO(krancour): We're not yet distinguishing between retryable and
 		// non-retryable errors. When we start to do this, failure to prepare the
@@ -282,6 +706,18 @@ func (e *SimpleEngine) executeStep(
 			Status: kargoapi.PromotionPhaseErrored,
 		}, err
 	}
+	// Overlays may have been promoted by earlier steps of this execution.
+	stepCtx.Overlays = exec.snapshotOverlays()
+	// So may the rendered branch have been pushed to.
+	stepCtx.RenderedBranchCommit = exec.renderedBranchCommit(promoCtx.RenderedBranchCommit)
+
+	if stepCtx.CredentialsDB != nil && promoCtx.RepoPolicy != nil {
+		stepCtx.CredentialsDB = &repoPolicyCredentialsDB{
+			Database: stepCtx.CredentialsDB,
+			policy:   promoCtx.RepoPolicy,
+			exec:     exec,
+		}
+	}
 
 	result, err := reg.Runner.RunPromotionStep(ctx, stepCtx)
 	if err != nil {
@@ -317,6 +753,23 @@ func (e *SimpleEngine) preparePromotionStepContext(
 		Promotion:       promoCtx.Promotion,
 		FreightRequests: promoCtx.FreightRequests,
 		Freight:         promoCtx.Freight,
+
+		CommitMessageMaxImages: promoCtx.CommitMessageMaxImages,
+		ImageMappings:          promoCtx.ImageMappings,
+		ToolVersions:           promoCtx.ToolVersions,
+		ToolCache:              promoCtx.ToolCache,
+		ExecPolicy:             promoCtx.ExecPolicy,
+		RenderedBranch:         promoCtx.RenderedBranch,
+		OnExternalModification: promoCtx.OnExternalModification,
+		ForceSync:              promoCtx.ForceSync,
+		MaxRepoSize:            promoCtx.MaxRepoSize,
+		MaxRenderedOutputSize:  promoCtx.MaxRenderedOutputSize,
+		RemoteBasePolicy:       promoCtx.RemoteBasePolicy,
+		DryRunApply:            promoCtx.DryRunApply,
+		RetainedImages:         promoCtx.RetainedImages,
+		SourceUpdateBatching:   promoCtx.SourceUpdateBatching,
+		StrictRender:           promoCtx.StrictRender,
+		GitIdentity:            promoCtx.GitIdentity,
 	}
 
 	if permissions.AllowCredentialsDB {
@@ -324,14 +777,54 @@ func (e *SimpleEngine) preparePromotionStepContext(
 	}
 	if permissions.AllowKargoClient {
 		stepCtx.KargoClient = e.kargoClient
+		stepCtx.ChildCreator = promoCtx.ChildCreator
+		if promoCtx.ServiceAccount != "" && e.kargoClientForServiceAccount != nil {
+			if stepCtx.ServiceAccountClient, err = e.kargoClientForServiceAccount(
+				promoCtx.Project, promoCtx.ServiceAccount,
+			); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if permissions.AllowArgoCDClient {
-		stepCtx.ArgoCDClient = e.argoCDClient
+		if stepCtx.ArgoCDClient, stepCtx.ArgoCDNamespace, err = e.getArgoCDClient(promoCtx); err != nil {
+			return nil, err
+		}
 	}
 
 	return stepCtx, nil
 }
 
+// getArgoCDClient returns the client for Argo CD resources to be provided to
+// the steps of the Promotion described by the provided PromotionContext, along
+// with the namespace Argo CD is installed into. The client belongs to the Argo
+// CD context named by the PromotionContext. If the PromotionContext specifies a
+// ServiceAccount, the client impersonates it.
+func (e *SimpleEngine) getArgoCDClient(promoCtx PromotionContext) (client.Client, string, error) {
+	argoCDCtx, err := e.argoCDContexts.Get(promoCtx.ArgoCDContext)
+	if err != nil {
+		return nil, "", err
+	}
+	if argoCDCtx == nil || argoCDCtx.Client == nil {
+		return nil, "", nil
+	}
+	if promoCtx.ServiceAccount == "" {
+		return argoCDCtx.Client, argoCDCtx.Namespace, nil
+	}
+	if argoCDCtx.ClientForServiceAccount == nil {
+		return nil, "", fmt.Errorf(
+			"cannot impersonate ServiceAccount %q in namespace %q: impersonation "+
+				"is not supported by this engine",
+			promoCtx.ServiceAccount, promoCtx.Project,
+		)
+	}
+	c, err := argoCDCtx.ClientForServiceAccount(promoCtx.Project, promoCtx.ServiceAccount)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get Argo CD client: %w", err)
+	}
+	return c, argoCDCtx.Namespace, nil
+}
+
 // stepAlias returns the alias for a step. If the alias is empty, a default
 // alias is returned based on the step index.
 func (e *SimpleEngine) stepAlias(alias string, index int64) (string, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -16,7 +17,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	libargocd "github.com/akuity/kargo/internal/argocd"
 	"github.com/akuity/kargo/internal/credentials"
+	libExec "github.com/akuity/kargo/internal/exec"
 )
 
 func TestSimpleEngine_Promote(t *testing.T) {
@@ -42,6 +45,7 @@ func TestSimpleEngine_Promote(t *testing.T) {
 				assert.NotNil(t, result.State)
 				assert.Equal(t, "state", result.State["existing"])
 				assert.Equal(t, int64(0), result.CurrentStep)
+				assert.Empty(t, result.Transcript)
 			},
 		},
 		{
@@ -58,6 +62,65 @@ func TestSimpleEngine_Promote(t *testing.T) {
 				assert.ErrorContains(t, err, "step execution failed")
 			},
 		},
+		{
+			name: "failed promotion with transcript",
+			promoCtx: PromotionContext{
+				Project: "test-project",
+			},
+			steps: []PromotionStep{
+				{Kind: "command-step"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+				assert.Contains(t, result.Transcript, "$ sh -c echo oops; exit 1\n")
+				assert.Contains(t, result.Transcript, "exit code: 1\noops\n")
+			},
+		},
+		{
+			name: "overlays recorded despite error",
+			promoCtx: PromotionContext{
+				Project: "test-project",
+				Overlays: []kargoapi.PromotedOverlay{
+					{Name: "us-east", RenderedBranch: "rendered/us-east"},
+					{Name: "eu-west", RenderedBranch: "rendered/eu-west"},
+				},
+			},
+			steps: []PromotionStep{
+				{Kind: "overlay-step"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, []kargoapi.PromotedOverlay{
+					{Name: "us-east", RenderedBranch: "rendered/us-east", Commit: "abc123"},
+					{Name: "eu-west", RenderedBranch: "rendered/eu-west"},
+				}, result.Overlays)
+			},
+		},
+		{
+			name: "rendered branch push retains previous commit",
+			promoCtx: PromotionContext{
+				Project: "test-project",
+				RenderedBranchPush: &kargoapi.RenderedBranchPush{
+					RepoURL:        "https://github.com/example/repo",
+					Branch:         "rendered/dev",
+					PreviousCommit: "abc123",
+					Commit:         "def456",
+				},
+			},
+			steps: []PromotionStep{
+				{Kind: "rendered-push-step"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &kargoapi.RenderedBranchPush{
+					RepoURL:        "https://github.com/example/repo",
+					Branch:         "rendered/dev",
+					PreviousCommit: "abc123",
+					Commit:         "789abc",
+				}, result.RenderedBranchPush)
+			},
+		},
 		{
 			name: "context cancellation",
 			promoCtx: PromotionContext{
@@ -116,6 +179,49 @@ func TestSimpleEngine_Promote(t *testing.T) {
 				},
 				&StepRunnerPermissions{},
 			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "command-step",
+					runFunc: func(_ context.Context, stepCtx *PromotionStepContext) (PromotionStepResult, error) {
+						cmd := exec.Command("sh", "-c", "echo oops; exit 1")
+						cmd.Dir = stepCtx.WorkDir
+						_, err := libExec.Exec(cmd)
+						return PromotionStepResult{Status: kargoapi.PromotionPhaseErrored}, err
+					},
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "overlay-step",
+					runFunc: func(_ context.Context, stepCtx *PromotionStepContext) (PromotionStepResult, error) {
+						overlay := stepCtx.Overlays[0]
+						overlay.Commit = "abc123"
+						return PromotionStepResult{
+							Status:   kargoapi.PromotionPhaseErrored,
+							Overlays: []kargoapi.PromotedOverlay{overlay},
+						}, errors.New("something went wrong")
+					},
+				},
+				&StepRunnerPermissions{},
+			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "rendered-push-step",
+					runFunc: func(context.Context, *PromotionStepContext) (PromotionStepResult, error) {
+						return PromotionStepResult{
+							Status: kargoapi.PromotionPhaseSucceeded,
+							RenderedBranchPush: &kargoapi.RenderedBranchPush{
+								RepoURL:        "https://github.com/example/repo",
+								Branch:         "rendered/dev",
+								PreviousCommit: "def456",
+								Commit:         "789abc",
+							},
+						}, nil
+					},
+				},
+				&StepRunnerPermissions{},
+			)
 			testRegistry.RegisterPromotionStepRunner(
 				&mockPromotionStepRunner{
 					name: "context-waiter",
@@ -290,6 +396,56 @@ func TestSimpleEngine_executeSteps(t *testing.T) {
 				}, result.State)
 			},
 		},
+		{
+			name: "credentials are redacted from step messages",
+			steps: []PromotionStep{
+				{Kind: "leaky-error-step", Alias: "step1"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.Error(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.Status)
+				assert.Len(t, result.StepExecutionMetadata, 1)
+				assert.Contains(t, result.StepExecutionMetadata[0].Message, "Authorization: Bearer ***")
+				assert.NotContains(t, result.StepExecutionMetadata[0].Message, "fake-token")
+			},
+		},
+		{
+			name: "terminal error on step execution; continue on error",
+			steps: []PromotionStep{
+				{Kind: "terminal-error-step", Alias: "step1", ContinueOnError: true},
+				{Kind: "success-step", Alias: "step2"},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+				assert.Contains(t, result.Message, "1 step(s) failed")
+				assert.Equal(t, int64(1), result.CurrentStep)
+				assert.Len(t, result.StepExecutionMetadata, 2)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.StepExecutionMetadata[0].Status)
+				assert.NotNil(t, result.StepExecutionMetadata[0].FinishedAt)
+				assert.Contains(t, result.StepExecutionMetadata[0].Message, "something went wrong")
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.StepExecutionMetadata[1].Status)
+				assert.NotNil(t, result.StepExecutionMetadata[1].FinishedAt)
+			},
+		},
+		{
+			name: "non-terminal error on step execution; error threshold met; continue on error",
+			steps: []PromotionStep{
+				{Kind: "success-step", Alias: "step1"},
+				{Kind: "error-step", Alias: "step2", ContinueOnError: true},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.Status)
+				assert.Contains(t, result.Message, "1 step(s) failed")
+				assert.Equal(t, int64(1), result.CurrentStep)
+				assert.Len(t, result.StepExecutionMetadata, 2)
+				assert.Equal(t, kargoapi.PromotionPhaseSucceeded, result.StepExecutionMetadata[0].Status)
+				assert.Equal(t, kargoapi.PromotionPhaseErrored, result.StepExecutionMetadata[1].Status)
+				assert.NotNil(t, result.StepExecutionMetadata[1].FinishedAt)
+				assert.Equal(t, uint32(1), result.StepExecutionMetadata[1].ErrorCount)
+			},
+		},
 		{
 			name: "non-terminal error on step execution; error threshold not met",
 			steps: []PromotionStep{
@@ -384,6 +540,35 @@ func TestSimpleEngine_executeSteps(t *testing.T) {
 				assert.Nil(t, result.StepExecutionMetadata[0].FinishedAt)
 			},
 		},
+		{
+			name: "step is waiting for a point in time; timeout does not elapse",
+			promoCtx: PromotionContext{
+				StepExecutionMetadata: kargoapi.StepExecutionMetadataList{{
+					// Start time is set to an hour ago
+					StartedAt: ptr.To(metav1.NewTime(time.Now().Add(-time.Hour))),
+				}},
+			},
+			steps: []PromotionStep{
+				{
+					Kind: "waiting-step",
+					Retry: &kargoapi.PromotionStepRetry{
+						Timeout: &metav1.Duration{
+							Duration: time.Hour,
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, result PromotionResult, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, kargoapi.PromotionPhaseRunning, result.Status)
+				assert.Len(t, result.StepExecutionMetadata, 1)
+				meta := result.StepExecutionMetadata[0]
+				assert.Equal(t, kargoapi.PromotionPhaseRunning, meta.Status)
+				assert.WithinDuration(t, time.Now(), meta.StartedAt.Time, time.Minute)
+				assert.NotNil(t, meta.WaitUntil)
+				assert.Nil(t, meta.FinishedAt)
+			},
+		},
 		{
 			name:  "context cancellation",
 			steps: []PromotionStep{{Kind: "context-waiter"}},
@@ -423,6 +608,16 @@ func TestSimpleEngine_executeSteps(t *testing.T) {
 				},
 				&StepRunnerPermissions{},
 			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name: "waiting-step",
+					runResult: PromotionStepResult{
+						Status:    kargoapi.PromotionPhaseRunning,
+						WaitUntil: ptr.To(time.Now().Add(time.Hour)),
+					},
+				},
+				&StepRunnerPermissions{},
+			)
 			testRegistry.RegisterPromotionStepRunner(
 				&mockPromotionStepRunner{
 					name:      "error-step",
@@ -439,6 +634,16 @@ func TestSimpleEngine_executeSteps(t *testing.T) {
 				},
 				&StepRunnerPermissions{},
 			)
+			testRegistry.RegisterPromotionStepRunner(
+				&mockPromotionStepRunner{
+					name:      "leaky-error-step",
+					runResult: PromotionStepResult{Status: kargoapi.PromotionPhaseErrored},
+					runErr: &terminalError{
+						err: errors.New("git fetch failed: Authorization: Bearer fake-token"),
+					},
+				},
+				&StepRunnerPermissions{},
+			)
 			testRegistry.RegisterPromotionStepRunner(
 				&mockPromotionStepRunner{
 					name: "context-waiter",
@@ -511,19 +716,26 @@ func TestSimpleEngine_executeStep(t *testing.T) {
 				kargoClient: fake.NewClientBuilder().Build(),
 			}
 
-			result, err := engine.executeStep(context.Background(), tt.promoCtx, tt.step, tt.reg, t.TempDir(), make(State))
+			result, err := engine.executeStep(
+				context.Background(), tt.promoCtx, tt.step, tt.reg, t.TempDir(),
+				&promotionExecution{state: make(State)},
+			)
 			tt.assertions(t, result, err)
 		})
 	}
 }
 
 func TestSimpleEngine_preparePromotionStepContext(t *testing.T) {
+	impersonatingClient := fake.NewClientBuilder().Build()
+	otherContextClient := fake.NewClientBuilder().Build()
+
 	tests := []struct {
-		name        string
-		promoCtx    PromotionContext
-		step        PromotionStep
-		permissions StepRunnerPermissions
-		assertions  func(*testing.T, *PromotionStepContext, error)
+		name                          string
+		promoCtx                      PromotionContext
+		step                          PromotionStep
+		permissions                   StepRunnerPermissions
+		argoCDClientForServiceAccount func(string, string) (client.Client, error)
+		assertions                    func(*testing.T, *PromotionStepContext, error)
 	}{
 		{
 			name: "successful context preparation",
@@ -560,15 +772,115 @@ func TestSimpleEngine_preparePromotionStepContext(t *testing.T) {
 				assert.Nil(t, ctx.ArgoCDClient)
 			},
 		},
+		{
+			name: "ServiceAccount is impersonated",
+			promoCtx: PromotionContext{
+				Project:        "test-project",
+				ServiceAccount: "test-sa",
+			},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{AllowArgoCDClient: true},
+			argoCDClientForServiceAccount: func(namespace, name string) (client.Client, error) {
+				assert.Equal(t, "test-project", namespace)
+				assert.Equal(t, "test-sa", name)
+				return impersonatingClient, nil
+			},
+			assertions: func(t *testing.T, ctx *PromotionStepContext, err error) {
+				assert.NoError(t, err)
+				assert.Same(t, impersonatingClient, ctx.ArgoCDClient)
+			},
+		},
+		{
+			name: "ServiceAccount is ignored without Argo CD client permission",
+			promoCtx: PromotionContext{
+				Project:        "test-project",
+				ServiceAccount: "test-sa",
+			},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{},
+			assertions: func(t *testing.T, ctx *PromotionStepContext, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, ctx.ArgoCDClient)
+			},
+		},
+		{
+			name:        "named Argo CD context is used",
+			promoCtx:    PromotionContext{ArgoCDContext: "other"},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{AllowArgoCDClient: true},
+			assertions: func(t *testing.T, ctx *PromotionStepContext, err error) {
+				assert.NoError(t, err)
+				assert.Same(t, otherContextClient, ctx.ArgoCDClient)
+				assert.Equal(t, "other-argocd", ctx.ArgoCDNamespace)
+			},
+		},
+		{
+			name:        "unavailable Argo CD context",
+			promoCtx:    PromotionContext{ArgoCDContext: "broken"},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{AllowArgoCDClient: true},
+			assertions: func(t *testing.T, _ *PromotionStepContext, err error) {
+				assert.ErrorContains(t, err, `Argo CD context "broken" is unavailable: connection refused`)
+			},
+		},
+		{
+			name:        "unknown Argo CD context",
+			promoCtx:    PromotionContext{ArgoCDContext: "missing"},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{AllowArgoCDClient: true},
+			assertions: func(t *testing.T, _ *PromotionStepContext, err error) {
+				assert.ErrorContains(t, err, `Argo CD context "missing" is not configured`)
+			},
+		},
+		{
+			name: "impersonation not supported",
+			promoCtx: PromotionContext{
+				Project:        "test-project",
+				ServiceAccount: "test-sa",
+			},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{AllowArgoCDClient: true},
+			assertions: func(t *testing.T, _ *PromotionStepContext, err error) {
+				assert.ErrorContains(t, err, "impersonation is not supported")
+			},
+		},
+		{
+			name: "error getting impersonating client",
+			promoCtx: PromotionContext{
+				Project:        "test-project",
+				ServiceAccount: "test-sa",
+			},
+			step:        PromotionStep{Kind: "test-step"},
+			permissions: StepRunnerPermissions{AllowArgoCDClient: true},
+			argoCDClientForServiceAccount: func(string, string) (client.Client, error) {
+				return nil, errors.New("something went wrong")
+			},
+			assertions: func(t *testing.T, _ *PromotionStepContext, err error) {
+				assert.ErrorContains(t, err, "something went wrong")
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			argoCDContexts := libargocd.NewContexts(
+				&libargocd.Context{
+					Namespace:               "argocd",
+					Client:                  fake.NewClientBuilder().Build(),
+					ClientForServiceAccount: tt.argoCDClientForServiceAccount,
+				},
+				&libargocd.Context{
+					Name:      "other",
+					Namespace: "other-argocd",
+					Client:    otherContextClient,
+				},
+			)
+			argoCDContexts.SetUnavailable("broken", errors.New("connection refused"))
 			engine := &SimpleEngine{
-				registry:      NewStepRunnerRegistry(),
-				kargoClient:   fake.NewClientBuilder().Build(),
-				argoCDClient:  fake.NewClientBuilder().Build(),
-				credentialsDB: &credentials.FakeDB{},
+				registry:       NewStepRunnerRegistry(),
+				kargoClient:    fake.NewClientBuilder().Build(),
+				credentialsDB:  &credentials.FakeDB{},
+				argoCDContexts: argoCDContexts,
 			}
 
 			stepCtx, err := engine.preparePromotionStepContext(
@@ -736,3 +1048,71 @@ func TestSimpleEngine_getProjectSecrets(t *testing.T) {
 		})
 	}
 }
+
+func Test_promotionExecution_renderedBranchCommit(t *testing.T) {
+	recorded := &kargoapi.RenderedBranchCommit{
+		RepoURL:   "https://github.com/example/repo.git",
+		Branch:    "rendered/dev",
+		Commit:    "recorded",
+		Promotion: "earlier-promotion",
+	}
+	tests := []struct {
+		name     string
+		push     *kargoapi.RenderedBranchPush
+		recorded *kargoapi.RenderedBranchCommit
+		expected *kargoapi.RenderedBranchCommit
+	}{
+		{
+			name: "nothing is known",
+		},
+		{
+			name:     "nothing has been pushed yet",
+			recorded: recorded,
+			expected: recorded,
+		},
+		{
+			name: "pushed to the recorded branch",
+			push: &kargoapi.RenderedBranchPush{
+				RepoURL:        "https://github.com/example/repo.git",
+				Branch:         "rendered/dev",
+				PreviousCommit: "recorded",
+				Commit:         "pushed",
+			},
+			recorded: recorded,
+			expected: &kargoapi.RenderedBranchCommit{
+				RepoURL: "https://github.com/example/repo.git",
+				Branch:  "rendered/dev",
+				Commit:  "pushed",
+			},
+		},
+		{
+			name: "pushed without a recorded commit",
+			push: &kargoapi.RenderedBranchPush{
+				RepoURL: "https://github.com/example/repo.git",
+				Branch:  "rendered/dev",
+				Commit:  "pushed",
+			},
+			expected: &kargoapi.RenderedBranchCommit{
+				RepoURL: "https://github.com/example/repo.git",
+				Branch:  "rendered/dev",
+				Commit:  "pushed",
+			},
+		},
+		{
+			name: "pushed to a different branch",
+			push: &kargoapi.RenderedBranchPush{
+				RepoURL: "https://github.com/example/repo.git",
+				Branch:  "rendered/dev-new",
+				Commit:  "pushed",
+			},
+			recorded: recorded,
+			expected: recorded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := &promotionExecution{renderedPush: tt.push}
+			assert.Equal(t, tt.expected, x.renderedBranchCommit(tt.recorded))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/akuity/kargo/internal/tools"
+)
+
+// toolVersionsOutputKey is the key of the output of PromotionSteps that render
+// manifests under which they record the versions of the tools they rendered
+// them with.
+const toolVersionsOutputKey = "toolVersions"
+
+// pinnedToolVersion returns the version of the provided tool that the Stage
+// the provided PromotionStepContext belongs to pins, or an empty string if it
+// does not pin one.
+func pinnedToolVersion(stepCtx *PromotionStepContext, tool tools.Tool) string {
+	if stepCtx.ToolVersions == nil {
+		return ""
+	}
+	switch tool {
+	case tools.Kustomize:
+		return stepCtx.ToolVersions.Kustomize
+	case tools.Helm:
+		return stepCtx.ToolVersions.Helm
+	default:
+		return ""
+	}
+}
+
+// resolveTool returns the path to the binary of the version of the provided
+// tool that the Stage pins, along with that version. If the Stage does not pin
+// a version or pins the version that is embedded in Kargo, an empty path is
+// returned, along with the embedded version, meaning the embedded version is
+// to be used. If the pinned version is not available, a terminal error is
+// returned.
+func resolveTool(
+	ctx context.Context,
+	stepCtx *PromotionStepContext,
+	tool tools.Tool,
+) (string, string, error) {
+	embedded := tools.EmbeddedVersion(tool)
+	pinned := pinnedToolVersion(stepCtx, tool)
+	if pinned == "" || pinned == embedded {
+		return "", embedded, nil
+	}
+	if stepCtx.ToolCache == nil {
+		return "", "", &terminalError{err: fmt.Errorf(
+			"Stage pins %s %s, but the embedded version is %s and no tool cache is "+
+				"configured to provide other versions",
+			tool, pinned, embedded,
+		)}
+	}
+	path, err := stepCtx.ToolCache.Path(ctx, tool, pinned)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting %s %s: %w", tool, pinned, err)
+	}
+	return path, pinned, nil
+}
+
+// usedToolVersions returns the versions of the tools that previous
+// PromotionSteps recorded rendering manifests with in the provided State.
+// Should different steps have used different versions of the same tool, all
+// of them are listed, separated by commas.
+func usedToolVersions(state State) map[string]string {
+	used := map[string][]string{}
+	for _, output := range state {
+		stepOutput, ok := output.(map[string]any)
+		if !ok {
+			continue
+		}
+		versions, ok := stepOutput[toolVersionsOutputKey].(map[string]any)
+		if !ok {
+			continue
+		}
+		for tool, version := range versions {
+			if v, ok := version.(string); ok && v != "" && !slices.Contains(used[tool], v) {
+				used[tool] = append(used[tool], v)
+			}
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(used))
+	for tool, versions := range used {
+		slices.Sort(versions)
+		result[tool] = strings.Join(versions, ",")
+	}
+	return result
+}
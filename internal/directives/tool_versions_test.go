@@ -0,0 +1,87 @@
+package directives
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/tools"
+)
+
+func Test_resolveTool(t *testing.T) {
+	cacheDir := t.TempDir()
+	preloaded := filepath.Join(cacheDir, "kustomize", "v5.4.3", "kustomize")
+	require.NoError(t, os.MkdirAll(filepath.Dir(preloaded), 0o755))
+	require.NoError(t, os.WriteFile(preloaded, []byte("#!/bin/sh\n"), 0o755))
+	cache := tools.NewCache(cacheDir, nil)
+
+	testCases := []struct {
+		name       string
+		stepCtx    *PromotionStepContext
+		assertions func(t *testing.T, path, version string, err error)
+	}{
+		{
+			name:    "not pinned",
+			stepCtx: &PromotionStepContext{ToolCache: cache},
+			assertions: func(t *testing.T, path, version string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, path)
+				require.Equal(t, tools.EmbeddedKustomizeVersion, version)
+			},
+		},
+		{
+			name: "pinned to embedded version",
+			stepCtx: &PromotionStepContext{
+				ToolVersions: &kargoapi.ToolVersions{Kustomize: tools.EmbeddedKustomizeVersion},
+			},
+			assertions: func(t *testing.T, path, version string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, path)
+				require.Equal(t, tools.EmbeddedKustomizeVersion, version)
+			},
+		},
+		{
+			name: "pinned without tool cache",
+			stepCtx: &PromotionStepContext{
+				ToolVersions: &kargoapi.ToolVersions{Kustomize: "v5.4.3"},
+			},
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, "no tool cache is configured")
+				require.True(t, isTerminal(err))
+			},
+		},
+		{
+			name: "pinned version is cached",
+			stepCtx: &PromotionStepContext{
+				ToolVersions: &kargoapi.ToolVersions{Kustomize: "v5.4.3"},
+				ToolCache:    cache,
+			},
+			assertions: func(t *testing.T, path, version string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, preloaded, path)
+				require.Equal(t, "v5.4.3", version)
+			},
+		},
+		{
+			name: "pinned version is unavailable",
+			stepCtx: &PromotionStepContext{
+				ToolVersions: &kargoapi.ToolVersions{Kustomize: "v5.3.0"},
+				ToolCache:    cache,
+			},
+			assertions: func(t *testing.T, _, _ string, err error) {
+				require.ErrorContains(t, err, "no download URL is configured")
+				require.False(t, isTerminal(err))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path, version, err := resolveTool(context.Background(), testCase.stepCtx, tools.Kustomize)
+			testCase.assertions(t, path, version, err)
+		})
+	}
+}
@@ -6,20 +6,47 @@ type CommonDefs interface{}
 
 type ComposeOutput map[string]interface{}
 
+type ArgoCDDryRunConfig struct {
+	// App identifies the Argo CD Application whose destination cluster the manifests are
+	// applied to. Resources that do not specify a namespace are applied to the Application's
+	// destination namespace.
+	App ArgoCDDryRunApp `json:"app"`
+	// Path is the path to a file or directory, relative to the working directory of the
+	// Promotion, containing rendered manifests. If it is a directory, all YAML files in it and
+	// its subdirectories are applied.
+	Path string `json:"path"`
+}
+
+// App identifies the Argo CD Application whose destination cluster the manifests are
+// applied to. Resources that do not specify a namespace are applied to the Application's
+// destination namespace.
+type ArgoCDDryRunApp struct {
+	// The name of the Argo CD Application.
+	Name string `json:"name"`
+	// The namespace of the Argo CD Application. If left unspecified, the namespace will be the
+	// one Argo CD is installed in.
+	Namespace string `json:"namespace,omitempty"`
+}
+
 type ArgoCDUpdateConfig struct {
-	Apps       []ArgoCDAppUpdate `json:"apps"`
-	FromOrigin *AppFromOrigin    `json:"fromOrigin,omitempty"`
+	Apps             []ArgoCDAppUpdate `json:"apps"`
+	FromOrigin       *AppFromOrigin    `json:"fromOrigin,omitempty"`
+	SyncTrigger      SyncTrigger       `json:"syncTrigger,omitempty"`
+	SyncWindowPolicy SyncWindowPolicy  `json:"syncWindowPolicy,omitempty"`
 }
 
 type ArgoCDAppUpdate struct {
-	FromOrigin *AppFromOrigin `json:"fromOrigin,omitempty"`
+	FromOrigin *AppFromOrigin   `json:"fromOrigin,omitempty"`
+	Health     *ArgoCDAppHealth `json:"health,omitempty"`
 	// Specifies the name of an Argo CD Application resource to be updated.
 	Name string `json:"name"`
 	// Specifies the namespace of an Argo CD Application resource to be updated. If left
 	// unspecified, the namespace will be the controller's configured default.
 	Namespace string `json:"namespace,omitempty"`
 	// Describes updates to be applied to various sources of an Argo CD Application resource.
-	Sources []ArgoCDAppSourceUpdate `json:"sources,omitempty"`
+	Sources          []ArgoCDAppSourceUpdate `json:"sources,omitempty"`
+	SyncTrigger      SyncTrigger             `json:"syncTrigger,omitempty"`
+	SyncWindowPolicy SyncWindowPolicy        `json:"syncWindowPolicy,omitempty"`
 }
 
 type AppFromOrigin struct {
@@ -29,6 +56,38 @@ type AppFromOrigin struct {
 	Name string `json:"name"`
 }
 
+// Describes how the health of an Argo CD Application is assessed. If neither 'resources'
+// nor 'expression' is specified, the health reported by Argo CD is used.
+type ArgoCDAppHealth struct {
+	// An expression evaluated against the Application ('app') and the resources it manages
+	// ('resources') to assess its health. It must evaluate to a boolean, where false means the
+	// Application is still progressing, or to an Argo CD health status such as 'Healthy' or
+	// 'Degraded'. Mutually exclusive with 'resources'.
+	Expression string `json:"expression,omitempty"`
+	// The maximum amount of time the Application may be progressing after its last sync
+	// finished before it is considered unhealthy. If not specified, the Application may be
+	// progressing indefinitely.
+	ProgressDeadline string `json:"progressDeadline,omitempty"`
+	// Resources managed by the Application that must be healthy for the Application to be
+	// considered healthy. The health of other resources is disregarded. Mutually exclusive with
+	// 'expression'.
+	Resources []ArgoCDResourceSelector `json:"resources,omitempty"`
+}
+
+// Selects resources managed by an Argo CD Application.
+type ArgoCDResourceSelector struct {
+	// The API group of the resources. If not specified, resources of the core API group are
+	// selected.
+	Group string `json:"group,omitempty"`
+	// The kind of the resources.
+	Kind string `json:"kind"`
+	// The name of the resource. If not specified, all resources of the kind are selected.
+	Name string `json:"name,omitempty"`
+	// The namespace of the resources. If not specified, resources in any namespace are
+	// selected.
+	Namespace string `json:"namespace,omitempty"`
+}
+
 type ArgoCDAppSourceUpdate struct {
 	// If applicable, identifies a specific chart within the Helm chart repository specified by
 	// the 'repoURL' field. When the source to be updated references a Helm chart repository,
@@ -62,7 +121,9 @@ type ArgoCDAppSourceUpdate struct {
 	RepoURL string `json:"repoURL"`
 	// Indicates whether the source should be updated such that its 'targetRevision' field
 	// points directly at the desired revision. If set to true, exactly one of
-	// 'desiredCommitFromStep' or 'desiredRevision' must be specified.
+	// 'desiredCommitFromStep' or 'desiredRevision' must be specified, unless 'chart' is
+	// specified, in which case the desired revision defaults to the version of that chart found
+	// in Freight.
 	UpdateTargetRevision bool `json:"updateTargetRevision,omitempty"`
 }
 
@@ -124,7 +185,29 @@ type DeleteConfig struct {
 	Path string `json:"path"`
 }
 
+type ExecRenderConfig struct {
+	// Args are further arguments to pass to the command. They may only be specified if the exec
+	// policy allows them for the command.
+	Args []string `json:"args,omitempty"`
+	// Command is the name of the command to execute, as specified by the exec policy of the
+	// KargoConfig resource.
+	Command string `json:"command"`
+	// Input is an arbitrary object that is passed to the command, along with the Freight being
+	// promoted, as part of the JSON document on its standard input.
+	Input map[string]interface{} `json:"input,omitempty"`
+	// OutPath is the path of the file to write the manifests the command writes to its standard
+	// output to.
+	OutPath string `json:"outPath"`
+	// Path is the directory, relative to the working directory of the Promotion, in which to
+	// execute the command. Defaults to the working directory of the Promotion.
+	Path string `json:"path,omitempty"`
+}
+
 type GitClearConfig struct {
+	// Paths or glob patterns, relative to path, of files that should not be removed. Kept files
+	// are left untouched, so their content and mode (including any executable bit) are
+	// preserved.
+	Keep []string `json:"keep,omitempty"`
 	// Path to a working directory of a local repository from which to remove all files,
 	// excluding the .git/ directory.
 	Path string `json:"path"`
@@ -134,10 +217,25 @@ type GitCloneConfig struct {
 	// The commits, branches, or tags to check out from the repository and the paths where they
 	// should be checked out. At least one must be specified.
 	Checkout []Checkout `json:"checkout"`
+	// Indicates that the repository does not require authentication, in which case no
+	// credentials are looked up or used for it, including by subsequent steps operating on the
+	// cloned repository. Repositories on the local file system (file:// URLs and absolute paths)
+	// never use credentials. Default is false.
+	InsecureNoAuth bool `json:"insecureNoAuth,omitempty"`
 	// Indicates whether to skip TLS verification when cloning the repository. Default is false.
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+	// Indicates whether to clone the repository without the contents of files, which are then
+	// fetched only for the files that are checked out. Combined with sparse checkouts, this
+	// greatly reduces the amount of data transferred for large repositories. Default is false.
+	PartialClone bool `json:"partialClone,omitempty"`
+	// Indicates whether to initialize and check out the submodules of checked out revisions,
+	// recursively. Default is false.
+	RecurseSubmodules bool `json:"recurseSubmodules,omitempty"`
 	// The URL of a remote Git repository to clone. Required.
 	RepoURL string `json:"repoURL"`
+	// Indicates whether to skip fetching Git LFS objects for checked out paths that are
+	// configured to use Git LFS. Default is false.
+	SkipLFS bool `json:"skipLFS,omitempty"`
 }
 
 type Checkout struct {
@@ -157,9 +255,21 @@ type Checkout struct {
 	FromOrigin  *CheckoutFromOrigin `json:"fromOrigin,omitempty"`
 	// The path where the repository should be checked out.
 	Path string `json:"path"`
+	// The directories, relative to the root of the repository, to check out. Files in other
+	// directories are left out, except for files at the root of the repository and of the
+	// parents of these directories. Directories of local resources, components, and bases that
+	// Kustomization files in the checked out directories refer to are checked out as well,
+	// recursively, so that Kustomize builds of checked out overlays succeed. If not specified,
+	// all files are checked out.
+	Sparse []string `json:"sparse,omitempty"`
 	// The tag to checkout. Mutually exclusive with 'branch', 'commit', and 'fromFreight=true'.
 	// If none of these are specified, the default branch is checked out.
 	Tag string `json:"tag,omitempty"`
+	// Indicates whether to verify, before cloning, that the credentials for the repository
+	// are permitted to push directly to the branch. If they are not, the step fails right away
+	// instead of when a later step attempts to push. Has no effect unless 'branch' is
+	// specified. Default is false.
+	VerifyPushAccess bool `json:"verifyPushAccess,omitempty"`
 }
 
 type CheckoutFromOrigin struct {
@@ -170,12 +280,20 @@ type CheckoutFromOrigin struct {
 }
 
 type GitCommitConfig struct {
+	// Whether to append Git trailers summarizing the Promotion (its name, and the images and
+	// commits it promotes) to the commit message. Default is false.
+	AddTrailers bool `json:"addTrailers,omitempty"`
 	// The author of the commit.
 	Author *Author `json:"author,omitempty"`
 	// The commit message. Mutually exclusive with 'messageFromSteps'.
 	Message string `json:"message,omitempty"`
 	// TODO
 	MessageFromSteps []string `json:"messageFromSteps,omitempty"`
+	// The path, relative to 'path', of a JSON file to write before committing, describing the
+	// Stage and the images and commits being promoted to it. The file is suitable for
+	// consumption by the Argo CD ApplicationSet Git file generator. If not specified, no such
+	// file is written.
+	MetadataFile string `json:"metadataFile,omitempty"`
 	// The path to a working directory of a local repository.
 	Path string `json:"path"`
 }
@@ -216,6 +334,31 @@ type GitOpenPRConfig struct {
 }
 
 type GitPushConfig struct {
+	// Working trees of other branches of the same repository, e.g. ones checked out by the same
+	// git-clone step, whose commits are pushed along with those of 'path'. All branches are
+	// updated in a single atomic push, so that either all of them are updated or none are. If
+	// the remote repository does not support atomic pushes, the branches are pushed one after
+	// another instead.
+	AdditionalBranches []AdditionalBranch `json:"additionalBranches,omitempty"`
+	// Indicates whether to register a health check that reports the Stage as unhealthy if the
+	// remote branch pushed to no longer points to the commit pushed by this step, e.g. because
+	// it was force-pushed or edited by hand. This should only be enabled if no other process
+	// pushes to the same branch. Default is false.
+	DetectDrift bool `json:"detectDrift,omitempty"`
+	// Configures how drift detected by the health check registered with 'detectDrift' is
+	// handled. When specified, the changes that were made to the branch are captured as a patch
+	// and a pull request adding the patch is opened against the branch the pushed content is
+	// derived from, so that the changes can be ported there. Has no effect unless 'detectDrift'
+	// is true.
+	DriftPullRequest *DriftPullRequest `json:"driftPullRequest,omitempty"`
+	// Indicates whether to push the commit for review to a Gerrit server instead of pushing it
+	// to the target branch directly. The commit is amended to carry a Change-Id trailer that is
+	// the same for every push of the same Promotion to the same branch, and is pushed to the
+	// refs/for/<targetBranch> ref, creating a change or uploading a new patch set of it. The
+	// number of the change is output as 'prNumber', so that a subsequent git-wait-for-pr step
+	// can wait for it to be submitted. Mutually exclusive with 'generateTargetBranch',
+	// 'additionalBranches', and 'detectDrift'. Default is false.
+	ForReview bool `json:"forReview,omitempty"`
 	// Indicates whether to push to a new remote branch. A value of 'true' is mutually exclusive
 	// with 'targetBranch'. If neither of these is provided, the target branch will be the
 	// currently checked out branch.
@@ -233,7 +376,57 @@ type GitPushConfig struct {
 	TargetBranch string `json:"targetBranch,omitempty"`
 }
 
+type AdditionalBranch struct {
+	// The path to a working directory of another branch of the same local repository.
+	Path string `json:"path"`
+	// The target branch to push the commits of this working directory to.
+	TargetBranch string `json:"targetBranch"`
+}
+
+// Configures how drift detected by the health check registered with 'detectDrift' is
+// handled. When specified, the changes that were made to the branch are captured as a patch
+// and a pull request adding the patch is opened against the branch the pushed content is
+// derived from, so that the changes can be ported there. Has no effect unless 'detectDrift'
+// is true.
+type DriftPullRequest struct {
+	// The name of the Git provider to use. Currently only 'github', 'gitlab' and 'azure' are
+	// supported. Kargo will try to infer the provider if it is not explicitly specified.
+	Provider *Provider `json:"provider,omitempty"`
+	// The URL of the repository to open the pull request in. Defaults to the repository pushed
+	// to.
+	RepoURL string `json:"repoURL,omitempty"`
+	// The branch to open the pull request against, typically the branch the pushed content was
+	// rendered from.
+	TargetBranch string `json:"targetBranch"`
+}
+
+type GitVerifyCommitConfig struct {
+	// Identities that are allowed to have signed the commit. An identity matches the signer's
+	// full user ID (e.g. 'Jane Doe <jane@example.com>'), its email address, the principal of an
+	// SSH key, or the fingerprint of the key. If not specified, a good signature made with any
+	// of the provided keys is accepted.
+	AllowedSigners []string `json:"allowedSigners,omitempty"`
+	// The ID (sha) of the commit to verify. The commit is fetched from the remote repository if
+	// it is not present in the local repository. If not specified, the commit checked out in
+	// the working tree is verified.
+	Commit string `json:"commit,omitempty"`
+	// One or more ASCII-armored GPG public keys. Commits signed with GPG are only considered
+	// verified if they were signed with one of these keys.
+	GpgPublicKeys string `json:"gpgPublicKeys,omitempty"`
+	// The path to a working directory of a local repository.
+	Path string `json:"path"`
+	// SSH public keys and the principals they belong to, in the format of an ssh-keygen allowed
+	// signers file. Commits signed with SSH are only considered verified if they were signed
+	// with one of these keys.
+	SSHAllowedSigners string `json:"sshAllowedSigners,omitempty"`
+}
+
 type GitWaitForPRConfig struct {
+	// Indicates whether to delete the pull request's source branch from the remote repository
+	// once the pull request has been merged or closed. Only branches generated by the git-push
+	// step, whose names begin with 'kargo/promotion/', are deleted. Failure to delete the
+	// branch does not fail the step. Default is false.
+	DeleteSourceBranch bool `json:"deleteSourceBranch,omitempty"`
 	// Indicates whether to skip TLS verification when cloning the repository. Default is false.
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
 	// The number of the pull request to wait for.
@@ -241,8 +434,9 @@ type GitWaitForPRConfig struct {
 	// This field references the 'prNumber' output from a previous step and uses it as the
 	// number of the pull request to wait for.
 	PRNumberFromStep string `json:"prNumberFromStep,omitempty"`
-	// The name of the Git provider to use. Currently only 'github', 'gitlab' and 'azure' are
-	// supported. Kargo will try to infer the provider if it is not explicitly specified.
+	// The name of the Git provider to use. Currently only 'github', 'gitlab', 'azure' and
+	// 'gerrit' are supported. Kargo will try to infer the provider if it is not explicitly
+	// specified.
 	Provider *Provider `json:"provider,omitempty"`
 	// The URL of a remote Git repository to clone.
 	RepoURL string `json:"repoURL"`
@@ -252,6 +446,9 @@ type HelmTemplateConfig struct {
 	// APIVersions allows a manual set of supported API Versions to be passed when rendering the
 	// manifests.
 	APIVersions []string `json:"apiVersions,omitempty"`
+	// Whether to download the chart's dependencies, at the versions recorded in its Chart.lock,
+	// before rendering the manifests.
+	BuildDependencies bool `json:"buildDependencies,omitempty"`
 	// Whether to disable hooks in the rendered manifests.
 	DisableHooks bool `json:"disableHooks,omitempty"`
 	// Whether to include CRDs in the rendered manifests.
@@ -261,6 +458,11 @@ type HelmTemplateConfig struct {
 	KubeVersion string `json:"kubeVersion,omitempty"`
 	// Namespace to use for the rendered manifests.
 	Namespace string `json:"namespace,omitempty"`
+	// OutFileMode is the mode, in octal notation (e.g. "0644"), of the files the rendered
+	// manifests are written to. When outPath is a .yaml or .yml file, this defaults to "0600".
+	// When outPath is a directory, the mode of the files written by Helm is left unchanged
+	// unless this is specified.
+	OutFileMode string `json:"outFileMode,omitempty"`
 	// OutPath to write the rendered manifests to. If it points to a .yaml or .yml file, the
 	// rendered manifests will be written to that file. If it points to a directory, the
 	// rendered manifests will be written to this directory joined with the chart name.
@@ -382,14 +584,92 @@ type JSONUpdate struct {
 }
 
 type KustomizeBuildConfig struct {
+	// App identifies the Argo CD Application that reads the built manifests from the branch they
+	// are written to. When specified, the path and directory settings of the Application's
+	// source are taken into account: if outPath is the root of a working tree, the manifests are
+	// written to the Application's path within it instead, and an OutputIgnored condition is
+	// recorded on the Promotion if the Application would not read some of the files written.
+	App *KustomizeBuildApp `json:"app,omitempty"`
+	// Normalize configures the normalization of the built manifests, which makes them identical
+	// between builds of an unchanged overlay and makes the differences between builds of a changed
+	// one easier to review. If any normalization is configured, the manifests are also re-encoded
+	// with consistent indentation and quoting. When left unspecified, the manifests are written
+	// exactly as Kustomize outputs them.
+	Normalize *Normalize `json:"normalize,omitempty"`
+	// OutFileMode is the mode, in octal notation (e.g. "0644"), of the files the rendered
+	// manifests are written to. Defaults to "0600".
+	OutFileMode string `json:"outFileMode,omitempty"`
 	// OutPath is the file path to write the built manifests to.
 	OutPath string `json:"outPath"`
+	// Patches is a list of patches to apply to the built manifests, in addition to any patches
+	// defined by the Kustomization file itself. Patches affect only the built manifests and are
+	// never written to the Kustomization file. Each patch must apply to at least one resource.
+	Patches []Patch `json:"patches,omitempty"`
 	// Path to the directory containing the Kustomization file.
 	Path string `json:"path"`
 	// Plugin contains configuration for customizing the behavior of builtin Kustomize plugins.
 	Plugin *Plugin `json:"plugin,omitempty"`
 }
 
+// App identifies the Argo CD Application that reads the built manifests from the branch they
+// are written to. When specified, the path and directory settings of the Application's source
+// are taken into account: if outPath is the root of a working tree, the manifests are written
+// to the Application's path within it instead, and an OutputIgnored condition is recorded on
+// the Promotion if the Application would not read some of the files written.
+type KustomizeBuildApp struct {
+	// The name of the Argo CD Application.
+	Name string `json:"name"`
+	// The namespace of the Argo CD Application. If left unspecified, the namespace will be the
+	// one Argo CD is installed in.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Normalize configures the normalization of the built manifests, which makes them identical
+// between builds of an unchanged overlay and makes the differences between builds of a changed
+// one easier to review. If any normalization is configured, the manifests are also re-encoded
+// with consistent indentation and quoting. When left unspecified, the manifests are written
+// exactly as Kustomize outputs them.
+type Normalize struct {
+	// Whether to order the manifests by their API group, kind, namespace, and name, rather than
+	// in the order Kustomize outputs them in.
+	SortDocuments bool `json:"sortDocuments,omitempty"`
+	// Whether to order the keys of every map within the manifests alphabetically.
+	SortKeys bool `json:"sortKeys,omitempty"`
+	// Whether to remove fields that are managed by the cluster or that carry no information
+	// from the manifests: status, metadata.creationTimestamp, metadata.managedFields, and any
+	// field whose value is null.
+	StripFields bool `json:"stripFields,omitempty"`
+}
+
+type Patch struct {
+	// Patch is an inline strategic merge patch or JSON6902 patch.
+	Patch string `json:"patch"`
+	// Target selects the resources to apply the patch to. Required for JSON6902 patches. A
+	// strategic merge patch without a target applies to the resource it identifies by its own
+	// kind and name.
+	Target *Target `json:"target,omitempty"`
+}
+
+// Target selects the resources to apply the patch to. Required for JSON6902 patches. A
+// strategic merge patch without a target applies to the resource it identifies by its own
+// kind and name.
+type Target struct {
+	// AnnotationSelector selects the resources to patch by their annotations.
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
+	// Group of the resources to patch.
+	Group string `json:"group,omitempty"`
+	// Kind of the resources to patch.
+	Kind string `json:"kind,omitempty"`
+	// LabelSelector selects the resources to patch by their labels.
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Name of the resources to patch. May be a regular expression.
+	Name string `json:"name,omitempty"`
+	// Namespace of the resources to patch. May be a regular expression.
+	Namespace string `json:"namespace,omitempty"`
+	// Version of the resources to patch.
+	Version string `json:"version,omitempty"`
+}
+
 // Plugin contains configuration for customizing the behavior of builtin Kustomize plugins.
 type Plugin struct {
 	// Helm contains configuration for inflating a Helm chart.
@@ -406,7 +686,44 @@ type Helm struct {
 	KubeVersion string `json:"kubeVersion,omitempty"`
 }
 
+type KustomizePromoteOverlaysConfig struct {
+	// Images is a list of container images to set or update in the Kustomization file of each
+	// overlay. When left unspecified, all images from the Freight collection will be set.
+	// Unless there is an ambiguous image name (for example, due to two Warehouses subscribing
+	// to the same repository), which requires manual configuration.
+	Images []KustomizeSetImageConfigImage `json:"images"`
+	// The commit message to use for the commit to each overlay's rendered branch. Defaults to a
+	// message describing the images that were set.
+	Message string `json:"message,omitempty"`
+	// Normalize configures the normalization of the manifests rendered from each overlay, which
+	// makes them identical between renders of an unchanged overlay and makes the differences
+	// between renders of a changed one easier to review. If any normalization is configured, the
+	// manifests are also re-encoded with consistent indentation and quoting. When left
+	// unspecified, the manifests are written exactly as Kustomize outputs them.
+	Normalize *Normalize `json:"normalize,omitempty"`
+	// Path, relative to the root of each overlay's rendered branch, of the file or directory
+	// the manifests rendered from the overlay are written to. If it ends with .yaml or .yml, it
+	// indicates a file. Defaults to the root of the branch.
+	OutPath string `json:"outPath,omitempty"`
+	// Path to a working tree of the repository containing the Stage's overlays, e.g. as checked
+	// out by git-clone. The paths of the overlays are relative to it.
+	Path string `json:"path"`
+	// What to do when the manifests rendered from an overlay after updating its Kustomization
+	// file do not reference the new revision of an image. 'fail' causes the promotion of the
+	// overlay to fail. 'warn' lets it succeed. Defaults to 'fail'.
+	UnreferencedImagePolicy *UnreferencedImagePolicy `json:"unreferencedImagePolicy,omitempty"`
+}
+
 type KustomizeSetImageConfig struct {
+	// What to do when an image that is pinned to a digest in the Kustomization file is to be
+	// updated using only a tag. 'clear' removes the digest, so that the tag takes effect.
+	// 'fail' causes the step to fail, so that a digest can be specified instead. Defaults to
+	// 'clear'.
+	ExistingDigestPolicy *ExistingDigestPolicy `json:"existingDigestPolicy,omitempty"`
+	// A list of literals in ConfigMap or Secret generators in the Kustomization file to set to
+	// the new tag of an image, for instance so an application can report its own version. All
+	// are updated along with the images, in the same write of the Kustomization file.
+	GeneratorLiterals []GeneratorLiteral `json:"generatorLiterals,omitempty"`
 	// Images is a list of container images to set or update in the Kustomization file. When
 	// left unspecified, all images from the Freight collection will be set in the Kustomization
 	// file. Unless there is an ambiguous image name (for example, due to two Warehouses
@@ -414,6 +731,29 @@ type KustomizeSetImageConfig struct {
 	Images []KustomizeSetImageConfigImage `json:"images"`
 	// Path to the directory containing the Kustomization file.
 	Path string `json:"path"`
+	// Whether to fail the step when several entries in images for the same image specify
+	// conflicting revisions. When false, the last of them wins.
+	RejectConflictingImages bool `json:"rejectConflictingImages,omitempty"`
+	// What to do when the manifests rendered from the overlay after updating the Kustomization
+	// file do not reference the new revision of an image, which usually means that the overlay
+	// does not use the image at all. 'fail' causes the step to fail. 'warn' lets the step
+	// succeed with a message naming the image. Defaults to 'fail'.
+	UnreferencedImagePolicy *UnreferencedImagePolicy `json:"unreferencedImagePolicy,omitempty"`
+}
+
+type GeneratorLiteral struct {
+	// Image whose new tag (or, if it has none, digest) the literal is set to. This is either
+	// the 'image' or 'name' of an entry in 'images' or, when 'images' is unspecified, the URL
+	// of an image repository in the Freight collection.
+	Image string `json:"image"`
+	// The key of the literal to update. The generator must already define a literal with this
+	// key.
+	Key string `json:"key"`
+	// The kind of generator. 'ConfigMap' refers to an entry in configMapGenerator and 'Secret'
+	// to an entry in secretGenerator. Defaults to 'ConfigMap'.
+	Kind *GeneratorKind `json:"kind,omitempty"`
+	// The name of the generator, as defined in the Kustomization file.
+	Name string `json:"name"`
 }
 
 type KustomizeSetImageConfigImage struct {
@@ -436,6 +776,28 @@ type KustomizeSetImageConfigImage struct {
 	UseDigest bool `json:"useDigest,omitempty"`
 }
 
+type RunJobConfig struct {
+	// Spec is the spec of the Kubernetes Job to run, in the format of the spec of a batch/v1
+	// Job. The Job is created in the Project's namespace as the ServiceAccount referenced by the
+	// Stage.
+	Spec map[string]interface{} `json:"spec"`
+}
+
+type SetMetadataConfig struct {
+	// Annotations maps the keys of annotations to set to Go templates that their values are
+	// rendered from.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Kinds are the kinds of resources to set labels and annotations on. Defaults to CronJob,
+	// DaemonSet, Deployment, Job, ReplicaSet, and StatefulSet.
+	Kinds []string `json:"kinds,omitempty"`
+	// Labels maps the keys of labels to set to Go templates that their values are rendered from.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Path is the path to a file or directory, relative to the working directory of the
+	// Promotion, containing rendered manifests. If it is a directory, all YAML files in it and
+	// its subdirectories are updated.
+	Path string `json:"path"`
+}
+
 type YAMLUpdateConfig struct {
 	// The path to a YAML file.
 	Path string `json:"path"`
@@ -457,12 +819,70 @@ const (
 	Warehouse Kind = "Warehouse"
 )
 
+// How a sync of the Argo CD Application is brought about. 'operation' requests a hard
+// refresh and initiates a sync operation. 'refreshOnly' only requests a hard refresh (and
+// updates the Application's sources if necessary), leaving the sync to Argo CD's automated
+// sync policy. 'none' does not modify the Application at all and only waits for it to be
+// synced to the desired revisions by other means; in this case, no sources may need to be
+// updated. Defaults to 'operation'.
+type SyncTrigger string
+
+const (
+	None        SyncTrigger = "none"
+	Operation   SyncTrigger = "operation"
+	RefreshOnly SyncTrigger = "refreshOnly"
+)
+
+// How the sync windows of the Argo CD Application's AppProject are respected when syncing
+// it. 'wait' waits for as long as any sync window denies syncs. 'allowManual' syncs while
+// the sync windows deny syncs only if they permit manual syncs, and waits otherwise.
+// 'ignore' does not consult sync windows at all. Defaults to 'wait'.
+type SyncWindowPolicy string
+
+const (
+	AllowManual SyncWindowPolicy = "allowManual"
+	Ignore      SyncWindowPolicy = "ignore"
+	Wait        SyncWindowPolicy = "wait"
+)
+
 // The name of the Git provider to use. Currently only 'github', 'gitlab' and 'azure' are
 // supported. Kargo will try to infer the provider if it is not explicitly specified.
 type Provider string
 
 const (
 	Azure  Provider = "azure"
+	Gerrit Provider = "gerrit"
 	Github Provider = "github"
 	Gitlab Provider = "gitlab"
 )
+
+// What to do when an image that is pinned to a digest in the Kustomization file is to be
+// updated using only a tag. 'clear' removes the digest, so that the tag takes effect.
+// 'fail' causes the step to fail, so that a digest can be specified instead. Defaults to
+// 'clear'.
+type ExistingDigestPolicy string
+
+const (
+	Clear ExistingDigestPolicy = "clear"
+	Fail  ExistingDigestPolicy = "fail"
+)
+
+// What to do when the manifests rendered from the overlay after updating the Kustomization
+// file do not reference the new revision of an image, which usually means that the overlay
+// does not use the image at all. 'fail' causes the step to fail. 'warn' lets the step
+// succeed with a message naming the image. Defaults to 'fail'.
+type UnreferencedImagePolicy string
+
+const (
+	UnreferencedImagePolicyFail UnreferencedImagePolicy = "fail"
+	Warn                        UnreferencedImagePolicy = "warn"
+)
+
+// The kind of generator. 'ConfigMap' refers to an entry in configMapGenerator and 'Secret'
+// to an entry in secretGenerator. Defaults to 'ConfigMap'.
+type GeneratorKind string
+
+const (
+	ConfigMap GeneratorKind = "ConfigMap"
+	Secret    GeneratorKind = "Secret"
+)
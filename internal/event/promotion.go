@@ -69,6 +69,29 @@ func NewPromotionAnnotations(
 		}
 	}
 
+	if p.Status.GitIdentity != nil {
+		data, err := json.Marshal(p.Status.GitIdentity)
+		if err != nil {
+			logger.Error(err, "marshal git identity in JSON")
+		} else {
+			annotations[kargoapi.AnnotationKeyEventGitIdentity] = string(data)
+		}
+	}
+	overlayIdentities := map[string]*kargoapi.GitIdentity{}
+	for _, o := range p.Status.Overlays {
+		if o.GitIdentity != nil {
+			overlayIdentities[o.Name] = o.GitIdentity
+		}
+	}
+	if len(overlayIdentities) > 0 {
+		data, err := json.Marshal(overlayIdentities)
+		if err != nil {
+			logger.Error(err, "marshal overlay git identities in JSON")
+		} else {
+			annotations[kargoapi.AnnotationKeyEventOverlayGitIdentities] = string(data)
+		}
+	}
+
 	var apps []types.NamespacedName
 	for _, step := range p.Spec.Steps {
 		if step.Uses != "argocd-update" || step.Config == nil {
@@ -101,3 +124,21 @@ func NewPromotionAnnotations(
 
 	return annotations
 }
+
+// AddStageMetadata adds the metadata of the provided Stage, if any, to the
+// provided annotations of an event recorded for a Promotion to the Stage.
+func AddStageMetadata(
+	ctx context.Context,
+	annotations map[string]string,
+	stage *kargoapi.Stage,
+) {
+	if stage == nil || len(stage.Spec.Metadata) == 0 {
+		return
+	}
+	data, err := json.Marshal(stage.Spec.Metadata)
+	if err != nil {
+		logging.LoggerFromContext(ctx).Error(err, "marshal stage metadata in JSON")
+		return
+	}
+	annotations[kargoapi.AnnotationKeyEventStageMetadata] = string(data)
+}
@@ -82,6 +82,42 @@ func TestNewPromotionAnnotations(t *testing.T) {
 					`{"name":"test-app-2","namespace":"test-namespace"}]`,
 			},
 		},
+		"promotion with git identities": {
+			promotion: &v1alpha1.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-promotion",
+					Namespace: "test-namespace",
+					CreationTimestamp: metav1.Time{
+						Time: time.Date(2024, 10, 22, 0, 0, 0, 0, time.UTC),
+					},
+				},
+				Spec: v1alpha1.PromotionSpec{
+					Freight: "test-freight",
+					Stage:   "test-stage",
+				},
+				Status: v1alpha1.PromotionStatus{
+					GitIdentity: &v1alpha1.GitIdentity{
+						Name:              "Kargo Prod",
+						Email:             "prod@example.com",
+						CredentialsSecret: "git-prod",
+					},
+					Overlays: []v1alpha1.PromotedOverlay{
+						{Name: "us-east"},
+						{Name: "eu-west", GitIdentity: &v1alpha1.GitIdentity{Name: "Kargo EU"}},
+					},
+				},
+			},
+			expected: map[string]string{
+				v1alpha1.AnnotationKeyEventProject:             "test-namespace",
+				v1alpha1.AnnotationKeyEventPromotionName:       "test-promotion",
+				v1alpha1.AnnotationKeyEventFreightName:         "test-freight",
+				v1alpha1.AnnotationKeyEventStageName:           "test-stage",
+				v1alpha1.AnnotationKeyEventPromotionCreateTime: "2024-10-22T00:00:00Z",
+				v1alpha1.AnnotationKeyEventGitIdentity: `{"name":"Kargo Prod","email":"prod@example.com",` +
+					`"credentialsSecret":"git-prod"}`,
+				v1alpha1.AnnotationKeyEventOverlayGitIdentities: `{"eu-west":{"name":"Kargo EU"}}`,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -107,3 +143,25 @@ func TestNewPromotionAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestAddStageMetadata(t *testing.T) {
+	annotations := map[string]string{}
+	AddStageMetadata(context.Background(), annotations, &v1alpha1.Stage{})
+	require.Empty(t, annotations)
+
+	AddStageMetadata(context.Background(), annotations, &v1alpha1.Stage{
+		Spec: v1alpha1.StageSpec{
+			Metadata: map[string]string{
+				"owningTeam": "payments",
+				"changeID":   "CHG-123",
+			},
+		},
+	})
+	require.Equal(
+		t,
+		map[string]string{
+			v1alpha1.AnnotationKeyEventStageMetadata: `{"changeID":"CHG-123","owningTeam":"payments"}`,
+		},
+		annotations,
+	)
+}
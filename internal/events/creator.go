@@ -0,0 +1,232 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/kargo"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+const (
+	// resultCreated is the result of a request for which a Promotion was
+	// created.
+	resultCreated = "created"
+	// resultDuplicate is the result of a request for which a Promotion had
+	// already been created.
+	resultDuplicate = "duplicate"
+	// resultDropped is the result of a request that was dropped by a Hook.
+	resultDropped = "dropped"
+	// resultRejected is the result of a request that was not valid.
+	resultRejected = "rejected"
+	// resultFailed is the result of a request for which a Promotion could not
+	// be created.
+	resultFailed = "failed"
+)
+
+var promotionRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kargo_promotion_requests_total",
+		Help: "Number of requests to promote Freight to a Stage, by source and result",
+	},
+	[]string{"source", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(promotionRequests)
+}
+
+// Hook is invoked for every valid PromotionRequested event before a Promotion
+// is created for it. Hooks may, for instance, deduplicate or batch requests.
+// If a Hook returns false, the event is dropped and no Promotion is created.
+type Hook func(context.Context, *PromotionRequested) (bool, error)
+
+// PromotionCreatorOptions are options for a PromotionCreator.
+type PromotionCreatorOptions struct {
+	// CreatePromotionFn is used to create Promotions. If nil, the Create method
+	// of the PromotionCreator's client is used. This permits callers to create
+	// Promotions with a client that is subject to different authorization than
+	// the one used for everything else.
+	CreatePromotionFn func(context.Context, client.Object, ...client.CreateOption) error
+	// Journal journals accepted events, so they can be replayed. If nil,
+	// events are not journaled.
+	Journal *Journal
+	// Hooks are invoked, in order, for every valid event.
+	Hooks []Hook
+}
+
+// PromotionCreator creates Promotions for PromotionRequested events. It is the
+// single path through which Promotions are created in response to requests,
+// and ensures that all of them are validated, journaled, labeled, and counted
+// in the same way.
+type PromotionCreator struct {
+	client client.Client
+	opts   PromotionCreatorOptions
+	nowFn  func() time.Time
+}
+
+// NewPromotionCreator returns a PromotionCreator that uses the provided client
+// to look up existing Promotions and to build new ones.
+func NewPromotionCreator(c client.Client, opts PromotionCreatorOptions) *PromotionCreator {
+	if opts.CreatePromotionFn == nil {
+		opts.CreatePromotionFn = c.Create
+	}
+	return &PromotionCreator{
+		client: c,
+		opts:   opts,
+		nowFn:  time.Now,
+	}
+}
+
+// Create accepts the provided event and creates a Promotion for it, unless a
+// Promotion was already created for an event with the same ID, in which case
+// that Promotion is returned instead. The provided Stage must be the one the
+// event requests a Promotion to. If a Hook drops the event, nil is returned.
+// Accepted events are journaled before the Promotion is created, so that
+// events for which no Promotion could be created, e.g. because of an outage,
+// can be replayed.
+func (c *PromotionCreator) Create(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	req *PromotionRequested,
+) (*kargoapi.Promotion, error) {
+	if err := c.validate(stage, req); err != nil {
+		return nil, err
+	}
+	for _, hook := range c.opts.Hooks {
+		ok, err := hook(ctx, req)
+		if err != nil {
+			promotionRequests.WithLabelValues(string(req.Source), resultFailed).Inc()
+			return nil, err
+		}
+		if !ok {
+			promotionRequests.WithLabelValues(string(req.Source), resultDropped).Inc()
+			return nil, nil
+		}
+	}
+	req.accept(c.nowFn())
+	if c.opts.Journal != nil {
+		// Failing to journal the request only affects the ability to replay it,
+		// so it does not prevent the Promotion from being created.
+		if err := c.opts.Journal.Append(ctx, req); err != nil {
+			logging.LoggerFromContext(ctx).Error(
+				err, "error journaling promotion request",
+				"request", req.ID,
+			)
+		}
+	}
+	return c.create(ctx, stage, req)
+}
+
+// Replay creates a Promotion for the provided event, which was previously
+// accepted and journaled, unless a Promotion was already created for it.
+// Hooks are not invoked and the event is not journaled again.
+func (c *PromotionCreator) Replay(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	req *PromotionRequested,
+) (*kargoapi.Promotion, error) {
+	if err := c.validate(stage, req); err != nil {
+		return nil, err
+	}
+	if req.ID == "" {
+		promotionRequests.WithLabelValues(string(req.Source), resultRejected).Inc()
+		return nil, fmt.Errorf("%w: replayed request has no ID", ErrInvalidRequest)
+	}
+	return c.create(ctx, stage, req)
+}
+
+// validate validates the provided event and Stage, and counts the event as
+// rejected if either is not valid.
+func (c *PromotionCreator) validate(stage *kargoapi.Stage, req *PromotionRequested) error {
+	err := req.validate()
+	if err == nil && (stage == nil || stage.Namespace != req.Project || stage.Name != req.Stage) {
+		err = fmt.Errorf(
+			"%w: Stage %q in namespace %q was not provided",
+			ErrInvalidRequest, req.Stage, req.Project,
+		)
+	}
+	if err != nil {
+		promotionRequests.WithLabelValues(string(req.Source), resultRejected).Inc()
+	}
+	return err
+}
+
+// create creates a Promotion for the provided accepted event, unless one was
+// already created for it.
+func (c *PromotionCreator) create(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	req *PromotionRequested,
+) (*kargoapi.Promotion, error) {
+	existing, err := c.GetPromotion(ctx, req)
+	if err != nil {
+		promotionRequests.WithLabelValues(string(req.Source), resultFailed).Inc()
+		return nil, err
+	}
+	if existing != nil {
+		promotionRequests.WithLabelValues(string(req.Source), resultDuplicate).Inc()
+		return existing, nil
+	}
+
+	promo, err := kargo.NewPromotionBuilder(c.client).Build(ctx, *stage, req.Freight)
+	if err != nil {
+		promotionRequests.WithLabelValues(string(req.Source), resultFailed).Inc()
+		return nil, fmt.Errorf("error building Promotion: %w", err)
+	}
+	promo.Spec.OriginCommit = req.OriginCommit.DeepCopy()
+	if promo.Labels == nil {
+		promo.Labels = make(map[string]string, 2)
+	}
+	promo.Labels[kargoapi.PromotionSourceLabelKey] = string(req.Source)
+	promo.Labels[kargoapi.PromotionRequestLabelKey] = req.idLabelValue()
+	if promo.Annotations == nil {
+		promo.Annotations = make(map[string]string, len(req.Annotations)+2)
+	}
+	for k, v := range req.Annotations {
+		promo.Annotations[k] = v
+	}
+	promo.Annotations[kargoapi.AnnotationKeyPromotionRequest] = req.ID
+	if req.Actor != "" {
+		promo.Annotations[kargoapi.AnnotationKeyCreateActor] = req.Actor
+	}
+
+	if err = c.opts.CreatePromotionFn(ctx, promo); err != nil {
+		promotionRequests.WithLabelValues(string(req.Source), resultFailed).Inc()
+		return nil, err
+	}
+	promotionRequests.WithLabelValues(string(req.Source), resultCreated).Inc()
+	return promo, nil
+}
+
+// GetPromotion returns the Promotion that was created for the provided event,
+// or nil if there is none.
+func (c *PromotionCreator) GetPromotion(
+	ctx context.Context,
+	req *PromotionRequested,
+) (*kargoapi.Promotion, error) {
+	promos := &kargoapi.PromotionList{}
+	if err := c.client.List(
+		ctx,
+		promos,
+		client.InNamespace(req.Project),
+		client.MatchingLabels{kargoapi.PromotionRequestLabelKey: req.idLabelValue()},
+	); err != nil {
+		return nil, fmt.Errorf(
+			"error listing Promotions in namespace %q: %w", req.Project, err,
+		)
+	}
+	for i := range promos.Items {
+		// Guard against collisions of the digests used as label values.
+		if promos.Items[i].Annotations[kargoapi.AnnotationKeyPromotionRequest] == req.ID {
+			return &promos.Items[i], nil
+		}
+	}
+	return nil, nil
+}
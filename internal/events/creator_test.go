@@ -0,0 +1,283 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestPromotionCreator_Create(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	testStage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "fake-stage",
+		},
+		Spec: kargoapi.StageSpec{
+			PromotionTemplate: &kargoapi.PromotionTemplate{
+				Spec: kargoapi.PromotionTemplateSpec{
+					Steps: []kargoapi.PromotionStep{{Uses: "fake-step"}},
+				},
+			},
+		},
+	}
+	newRequest := func() *PromotionRequested {
+		return &PromotionRequested{
+			Source:  SourceWebhook,
+			Project: "fake-project",
+			Stage:   "fake-stage",
+			Freight: "fake-freight",
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		stage      *kargoapi.Stage
+		req        func() *PromotionRequested
+		hooks      []Hook
+		objects    []client.Object
+		createErr  error
+		assertions func(*testing.T, client.Client, *PromotionRequested, *kargoapi.Promotion, error)
+	}{
+		{
+			name:  "unknown source",
+			stage: testStage,
+			req: func() *PromotionRequested {
+				req := newRequest()
+				req.Source = "carrier-pigeon"
+				return req
+			},
+			assertions: func(t *testing.T, _ client.Client, _ *PromotionRequested, _ *kargoapi.Promotion, err error) {
+				require.ErrorIs(t, err, ErrInvalidRequest)
+				require.ErrorContains(t, err, "unknown source")
+			},
+		},
+		{
+			name:  "missing freight",
+			stage: testStage,
+			req: func() *PromotionRequested {
+				req := newRequest()
+				req.Freight = ""
+				return req
+			},
+			assertions: func(t *testing.T, _ client.Client, _ *PromotionRequested, _ *kargoapi.Promotion, err error) {
+				require.ErrorIs(t, err, ErrInvalidRequest)
+				require.ErrorContains(t, err, "freight is required")
+			},
+		},
+		{
+			name:  "origin commit without ID",
+			stage: testStage,
+			req: func() *PromotionRequested {
+				req := newRequest()
+				req.OriginCommit = &kargoapi.OriginCommit{RepoURL: "https://github.com/example/app"}
+				return req
+			},
+			assertions: func(t *testing.T, _ client.Client, _ *PromotionRequested, _ *kargoapi.Promotion, err error) {
+				require.ErrorIs(t, err, ErrInvalidRequest)
+				require.ErrorContains(t, err, "origin commit ID is required")
+			},
+		},
+		{
+			name:  "stage does not match request",
+			stage: testStage,
+			req: func() *PromotionRequested {
+				req := newRequest()
+				req.Stage = "other-stage"
+				return req
+			},
+			assertions: func(t *testing.T, _ client.Client, _ *PromotionRequested, _ *kargoapi.Promotion, err error) {
+				require.ErrorIs(t, err, ErrInvalidRequest)
+				require.ErrorContains(t, err, "was not provided")
+			},
+		},
+		{
+			name:  "hook fails",
+			stage: testStage,
+			req:   newRequest,
+			hooks: []Hook{
+				func(context.Context, *PromotionRequested) (bool, error) {
+					return false, errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, _ client.Client, _ *PromotionRequested, _ *kargoapi.Promotion, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+			},
+		},
+		{
+			name:  "hook drops request",
+			stage: testStage,
+			req:   newRequest,
+			hooks: []Hook{
+				func(context.Context, *PromotionRequested) (bool, error) {
+					return false, nil
+				},
+			},
+			assertions: func(t *testing.T, c client.Client, _ *PromotionRequested, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				require.Nil(t, promo)
+				reqs, err := NewJournal(c, 0).List(context.Background(), "fake-project")
+				require.NoError(t, err)
+				require.Empty(t, reqs)
+			},
+		},
+		{
+			name:      "error creating promotion",
+			stage:     testStage,
+			req:       newRequest,
+			createErr: errors.New("something went wrong"),
+			assertions: func(t *testing.T, c client.Client, req *PromotionRequested, promo *kargoapi.Promotion, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.Nil(t, promo)
+				// The request was journaled before the Promotion was to be
+				// created, so it can be replayed.
+				reqs, err := NewJournal(c, 0).List(context.Background(), "fake-project")
+				require.NoError(t, err)
+				require.Len(t, reqs, 1)
+				require.Equal(t, req.ID, reqs[0].ID)
+			},
+		},
+		{
+			name:  "promotion is created",
+			stage: testStage,
+			req: func() *PromotionRequested {
+				req := newRequest()
+				req.Actor = "fake-actor"
+				req.Annotations = map[string]string{"foo": "bar"}
+				req.OriginCommit = &kargoapi.OriginCommit{
+					RepoURL: "https://github.com/example/app",
+					ID:      "abc123",
+				}
+				return req
+			},
+			assertions: func(t *testing.T, c client.Client, req *PromotionRequested, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, promo)
+				require.Equal(t, "fake-stage", promo.Spec.Stage)
+				require.Equal(t, "fake-freight", promo.Spec.Freight)
+				require.Equal(t, string(SourceWebhook), promo.Labels[kargoapi.PromotionSourceLabelKey])
+				require.Equal(t, req.idLabelValue(), promo.Labels[kargoapi.PromotionRequestLabelKey])
+				require.Equal(t, req.ID, promo.Annotations[kargoapi.AnnotationKeyPromotionRequest])
+				require.Equal(t, "fake-actor", promo.Annotations[kargoapi.AnnotationKeyCreateActor])
+				require.Equal(t, "bar", promo.Annotations["foo"])
+				require.Equal(t, req.OriginCommit, promo.Spec.OriginCommit)
+
+				// An ID was assigned and the request was journaled.
+				require.Regexp(t, "^webhook/", req.ID)
+				require.Equal(t, now, req.Time)
+				reqs, err := NewJournal(c, 0).List(context.Background(), "fake-project")
+				require.NoError(t, err)
+				require.Len(t, reqs, 1)
+				require.Equal(t, req.ID, reqs[0].ID)
+			},
+		},
+		{
+			name:  "promotion exists for request",
+			stage: testStage,
+			req: func() *PromotionRequested {
+				req := newRequest()
+				req.ID = "fake-id"
+				return req
+			},
+			objects: []client.Object{
+				&kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "fake-project",
+						Name:      "existing",
+						Labels: map[string]string{
+							kargoapi.PromotionRequestLabelKey: (&PromotionRequested{ID: "fake-id"}).idLabelValue(),
+						},
+						Annotations: map[string]string{
+							kargoapi.AnnotationKeyPromotionRequest: "fake-id",
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, c client.Client, _ *PromotionRequested, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, promo)
+				require.Equal(t, "existing", promo.Name)
+				promos := &kargoapi.PromotionList{}
+				require.NoError(t, c.List(context.Background(), promos))
+				require.Len(t, promos.Items, 1)
+				// The request is journaled, even though its Promotion exists.
+				reqs, err := NewJournal(c, 0).List(context.Background(), "fake-project")
+				require.NoError(t, err)
+				require.Len(t, reqs, 1)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(testCase.objects...).Build()
+			creator := NewPromotionCreator(c, PromotionCreatorOptions{
+				Journal: NewJournal(c, 0),
+				Hooks:   testCase.hooks,
+			})
+			if testCase.createErr != nil {
+				creator.opts.CreatePromotionFn = func(context.Context, client.Object, ...client.CreateOption) error {
+					return testCase.createErr
+				}
+			}
+			creator.nowFn = func() time.Time { return now }
+			req := testCase.req()
+			promo, err := creator.Create(context.Background(), testCase.stage, req)
+			testCase.assertions(t, c, req, promo, err)
+		})
+	}
+}
+
+func TestPromotionCreator_Replay(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kargoapi.AddToScheme(scheme))
+
+	testStage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "fake-stage",
+		},
+		Spec: kargoapi.StageSpec{
+			PromotionTemplate: &kargoapi.PromotionTemplate{
+				Spec: kargoapi.PromotionTemplateSpec{
+					Steps: []kargoapi.PromotionStep{{Uses: "fake-step"}},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	creator := NewPromotionCreator(c, PromotionCreatorOptions{})
+
+	req := &PromotionRequested{
+		Source:  SourceWebhook,
+		Project: "fake-project",
+		Stage:   "fake-stage",
+		Freight: "fake-freight",
+	}
+	_, err := creator.Replay(context.Background(), testStage, req)
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	req.ID = "fake-id"
+	first, err := creator.Replay(context.Background(), testStage, req)
+	require.NoError(t, err)
+	second, err := creator.Replay(context.Background(), testStage, req)
+	require.NoError(t, err)
+	require.Equal(t, first.Name, second.Name)
+
+	promos := &kargoapi.PromotionList{}
+	require.NoError(t, c.List(context.Background(), promos))
+	require.Len(t, promos.Items, 1)
+}
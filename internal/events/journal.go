@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// JournalConfigMapName is the name of the ConfigMap, in the namespace of
+	// each Project, that journals the PromotionRequested events accepted for
+	// the Project.
+	JournalConfigMapName = "kargo-promotion-requests"
+
+	// DefaultJournalSize is the default number of events retained by a
+	// Journal per Project.
+	DefaultJournalSize = 100
+
+	// journalDataKey is the key of the journal ConfigMap's data that holds the
+	// JSON-encoded events.
+	journalDataKey = "requests.json"
+)
+
+// Journal is a ring buffer of accepted PromotionRequested events, backed by a
+// ConfigMap in the namespace of each Project. Once the buffer is full, the
+// oldest events are discarded as new ones are appended.
+type Journal struct {
+	client client.Client
+	size   int
+}
+
+// NewJournal returns a Journal that uses the provided client to read and
+// write its ConfigMaps and retains up to the provided number of events per
+// Project. If size is not positive, DefaultJournalSize is used.
+func NewJournal(c client.Client, size int) *Journal {
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+	return &Journal{
+		client: c,
+		size:   size,
+	}
+}
+
+// Append appends the provided event to the journal of its Project, unless an
+// event with the same ID was journaled already. This keeps sources that submit
+// the same request repeatedly from crowding other events out of the journal.
+func (j *Journal) Append(ctx context.Context, req *PromotionRequested) error {
+	return retry.OnError(
+		retry.DefaultRetry,
+		func(err error) bool {
+			// Another writer updated or created the ConfigMap first.
+			return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+		},
+		func() error {
+			cm := &corev1.ConfigMap{}
+			err := j.client.Get(
+				ctx,
+				types.NamespacedName{Namespace: req.Project, Name: JournalConfigMapName},
+				cm,
+			)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("error getting journal of Project %q: %w", req.Project, err)
+			}
+			found := err == nil
+
+			reqs, err := decodeJournal(cm)
+			if err != nil {
+				return err
+			}
+			for i := range reqs {
+				if reqs[i].ID == req.ID {
+					return nil
+				}
+			}
+			reqs = append(reqs, *req)
+			if len(reqs) > j.size {
+				reqs = reqs[len(reqs)-j.size:]
+			}
+			data, err := json.Marshal(reqs)
+			if err != nil {
+				return fmt.Errorf("error encoding journal of Project %q: %w", req.Project, err)
+			}
+
+			if !found {
+				cm = &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: req.Project,
+						Name:      JournalConfigMapName,
+					},
+					Data: map[string]string{journalDataKey: string(data)},
+				}
+				return j.client.Create(ctx, cm)
+			}
+			if cm.Data == nil {
+				cm.Data = make(map[string]string, 1)
+			}
+			cm.Data[journalDataKey] = string(data)
+			return j.client.Update(ctx, cm)
+		},
+	)
+}
+
+// List returns the journaled events of the provided Project, oldest first.
+func (j *Journal) List(ctx context.Context, project string) ([]PromotionRequested, error) {
+	cm := &corev1.ConfigMap{}
+	if err := j.client.Get(
+		ctx,
+		types.NamespacedName{Namespace: project, Name: JournalConfigMapName},
+		cm,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting journal of Project %q: %w", project, err)
+	}
+	return decodeJournal(cm)
+}
+
+// decodeJournal returns the events held by the provided journal ConfigMap.
+func decodeJournal(cm *corev1.ConfigMap) ([]PromotionRequested, error) {
+	data, ok := cm.Data[journalDataKey]
+	if !ok || data == "" {
+		return nil, nil
+	}
+	var reqs []PromotionRequested
+	if err := json.Unmarshal([]byte(data), &reqs); err != nil {
+		return nil, fmt.Errorf(
+			"error decoding journal in ConfigMap %q in namespace %q: %w",
+			cm.Name, cm.Namespace, err,
+		)
+	}
+	return reqs, nil
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestJournal(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	t.Run("no journal", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		reqs, err := NewJournal(c, 0).List(context.Background(), "fake-project")
+		require.NoError(t, err)
+		require.Empty(t, reqs)
+	})
+
+	t.Run("oldest requests are discarded", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		journal := NewJournal(c, 3)
+		for i := range 5 {
+			require.NoError(t, journal.Append(context.Background(), &PromotionRequested{
+				ID:      fmt.Sprintf("request-%d", i),
+				Project: "fake-project",
+			}))
+		}
+		// Requests of other Projects are journaled separately.
+		require.NoError(t, journal.Append(context.Background(), &PromotionRequested{
+			ID:      "other",
+			Project: "other-project",
+		}))
+
+		reqs, err := journal.List(context.Background(), "fake-project")
+		require.NoError(t, err)
+		require.Len(t, reqs, 3)
+		for i, req := range reqs {
+			require.Equal(t, fmt.Sprintf("request-%d", i+2), req.ID)
+		}
+	})
+
+	t.Run("requests are journaled once", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		journal := NewJournal(c, 0)
+		for range 2 {
+			require.NoError(t, journal.Append(context.Background(), &PromotionRequested{
+				ID:      "fake-id",
+				Project: "fake-project",
+			}))
+		}
+		reqs, err := journal.List(context.Background(), "fake-project")
+		require.NoError(t, err)
+		require.Len(t, reqs, 1)
+	})
+
+	t.Run("journal is corrupt", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      JournalConfigMapName,
+				},
+				Data: map[string]string{journalDataKey: "{"},
+			},
+		).Build()
+		journal := NewJournal(c, 0)
+		_, err := journal.List(context.Background(), "fake-project")
+		require.ErrorContains(t, err, "error decoding journal")
+		err = journal.Append(context.Background(), &PromotionRequested{Project: "fake-project"})
+		require.ErrorContains(t, err, "error decoding journal")
+	})
+}
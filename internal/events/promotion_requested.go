@@ -0,0 +1,108 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// Source identifies the origin of a PromotionRequested event.
+type Source string
+
+const (
+	// SourceAPI indicates a request made through the Kargo API, e.g. by the UI
+	// or the CLI.
+	SourceAPI Source = "api"
+	// SourceAutoPromotion indicates a request made by the controller upon new
+	// Freight becoming available to a Stage that permits auto-promotion.
+	SourceAutoPromotion Source = "auto-promotion"
+	// SourceWebhook indicates a request made in response to an inbound webhook.
+	SourceWebhook Source = "webhook"
+	// SourcePoller indicates a request made in response to a change discovered
+	// by polling a repository.
+	SourcePoller Source = "poller"
+	// SourceApplication indicates a request made by annotating an Argo CD
+	// Application.
+	SourceApplication Source = "argocd-application"
+)
+
+// ErrInvalidRequest is returned, wrapped, for a PromotionRequested event that
+// is not valid.
+var ErrInvalidRequest = errors.New("invalid promotion request")
+
+// PromotionRequested is the normalized form of a request to promote a piece of
+// Freight to a Stage. Every source of Promotions expresses its requests as
+// such an event and submits it to a PromotionCreator.
+type PromotionRequested struct {
+	// ID is the idempotency key of the request. No more than one Promotion is
+	// created for any given ID. Sources that may submit the same request more
+	// than once must derive it from the request. If empty, a unique ID is
+	// assigned when the request is accepted.
+	ID string `json:"id"`
+	// Source is the origin of the request.
+	Source Source `json:"source"`
+	// Project is the name of the Project the Stage belongs to.
+	Project string `json:"project"`
+	// Stage is the name of the Stage to promote to.
+	Stage string `json:"stage"`
+	// Freight is the name of the Freight to promote.
+	Freight string `json:"freight"`
+	// Actor is the actor on whose behalf the Promotion is created, formatted as
+	// for the kargo.akuity.io/create-actor annotation. Optional.
+	Actor string `json:"actor,omitempty"`
+	// Annotations are additional annotations to set on the Promotion.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// OriginCommit identifies the commit to an application's source repository
+	// that produced the Freight, to which the outcome of the Promotion is to be
+	// reported. Optional.
+	OriginCommit *kargoapi.OriginCommit `json:"originCommit,omitempty"`
+	// Time is the time the request was accepted. It is set when the request is
+	// accepted.
+	Time time.Time `json:"time"`
+}
+
+// validate returns an error wrapping ErrInvalidRequest if the request is
+// missing any required field or has an unknown Source.
+func (p *PromotionRequested) validate() error {
+	switch p.Source {
+	case SourceAPI, SourceAutoPromotion, SourceWebhook, SourcePoller, SourceApplication:
+	default:
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidRequest, p.Source)
+	}
+	switch {
+	case p.Project == "":
+		return fmt.Errorf("%w: project is required", ErrInvalidRequest)
+	case p.Stage == "":
+		return fmt.Errorf("%w: stage is required", ErrInvalidRequest)
+	case p.Freight == "":
+		return fmt.Errorf("%w: freight is required", ErrInvalidRequest)
+	case p.OriginCommit != nil && p.OriginCommit.RepoURL == "":
+		return fmt.Errorf("%w: origin commit repo URL is required", ErrInvalidRequest)
+	case p.OriginCommit != nil && p.OriginCommit.ID == "":
+		return fmt.Errorf("%w: origin commit ID is required", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// accept assigns the request an ID, if it has none, and the provided time of
+// acceptance.
+func (p *PromotionRequested) accept(now time.Time) {
+	if p.ID == "" {
+		p.ID = fmt.Sprintf("%s/%s", p.Source, ulid.Make())
+	}
+	p.Time = now
+}
+
+// idLabelValue returns a digest of the request's ID that is suitable for use
+// as a label value, which IDs themselves may not be due to their length or the
+// characters they contain.
+func (p *PromotionRequested) idLabelValue() string {
+	sum := sha256.Sum256([]byte(p.ID))
+	return hex.EncodeToString(sum[:16])
+}
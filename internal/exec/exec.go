@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"time"
 )
 
 // ExitError is an error type that is produced by the Exec() function when a
@@ -34,19 +35,25 @@ func (e *ExitError) Error() string {
 // as-is. The primary benefit to calling Exec() over calling
 // cmd.CombinedOutput() directly is that errors will automatically include
 // command output, which is likely to contain important information about the
-// cause of the error.
+// cause of the error. Commands are also recorded in any Transcript that is
+// tracked for the directory they are executed in.
 func Exec(cmd *exec.Cmd) ([]byte, error) {
+	start := time.Now()
 	res, err := cmd.CombinedOutput()
 	var exitErr *exec.ExitError
 	if ok := errors.As(err, &exitErr); ok {
+		RecordCommand(cmd, start, res, exitErr.ExitCode())
 		return res, &ExitError{
 			Command:  cmd.String(),
 			Output:   res,
 			ExitCode: exitErr.ExitCode(),
 		}
 	}
+	exitCode := 0
 	if err != nil {
+		exitCode = -1
 		err = fmt.Errorf("error executing cmd [%s]: %s: %w", cmd.String(), string(res), err)
 	}
+	RecordCommand(cmd, start, res, exitCode)
 	return res, err
 }
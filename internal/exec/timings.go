@@ -0,0 +1,136 @@
+package exec
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// OperationsConfig represents configuration for the observation of
+// operations, such as git clones or kustomize builds, that are performed by
+// executing commands.
+type OperationsConfig struct {
+	// SlowOperationThreshold is the duration after which a single operation is
+	// considered slow and a message naming it is logged. A value of zero
+	// disables these messages.
+	SlowOperationThreshold time.Duration `envconfig:"SLOW_OPERATION_THRESHOLD" default:"1m"`
+}
+
+func OperationsConfigFromEnv() OperationsConfig {
+	cfg := OperationsConfig{}
+	envconfig.MustProcess("", &cfg)
+	return cfg
+}
+
+// slowOperationThreshold is the threshold shared by all operations in the
+// process.
+var slowOperationThreshold atomic.Int64
+
+func init() {
+	slowOperationThreshold.Store(int64(time.Minute))
+}
+
+// SetOperationsConfig configures the observation of operations. It is intended
+// to be called once, at startup.
+func SetOperationsConfig(cfg OperationsConfig) {
+	slowOperationThreshold.Store(int64(cfg.SlowOperationThreshold))
+}
+
+// IsSlow returns true if an operation that took the provided duration is
+// considered slow.
+func IsSlow(d time.Duration) bool {
+	threshold := time.Duration(slowOperationThreshold.Load())
+	return threshold > 0 && d > threshold
+}
+
+// Timings accumulates the time spent on operations whose commands were
+// executed in a directory tree, by operation.
+type Timings struct {
+	dir string
+	mu  sync.Mutex
+	ops map[string]time.Duration
+}
+
+// OperationTiming is the total time spent on a single operation.
+type OperationTiming struct {
+	Operation string
+	Duration  time.Duration
+}
+
+// Get returns the total time spent on each operation, from the operation that
+// took longest to the one that took shortest.
+func (t *Timings) Get() []OperationTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timings := make([]OperationTiming, 0, len(t.ops))
+	for op, d := range t.ops {
+		timings = append(timings, OperationTiming{Operation: op, Duration: d})
+	}
+	slices.SortFunc(timings, func(a, b OperationTiming) int {
+		if a.Duration != b.Duration {
+			return int(b.Duration - a.Duration)
+		}
+		return strings.Compare(a.Operation, b.Operation)
+	})
+	return timings
+}
+
+var (
+	trackedMu sync.RWMutex
+	tracked   []*Timings
+)
+
+// TrackTimings starts accumulating the time spent on operations whose commands
+// are executed anywhere in the provided directory tree. It returns the Timings
+// and a function that must be called to stop tracking them.
+func TrackTimings(dir string) (*Timings, func()) {
+	t := &Timings{dir: normalizeDir(dir), ops: map[string]time.Duration{}}
+	trackedMu.Lock()
+	tracked = append(tracked, t)
+	trackedMu.Unlock()
+	return t, func() {
+		trackedMu.Lock()
+		defer trackedMu.Unlock()
+		tracked = slices.DeleteFunc(tracked, func(other *Timings) bool {
+			return other == t
+		})
+	}
+}
+
+// RecordTiming adds the provided duration of the provided operation, whose
+// command was executed in the provided directory, to all Timings that are
+// tracked for a directory tree containing it.
+func RecordTiming(dir, operation string, d time.Duration) {
+	if dir == "" {
+		return
+	}
+	dir = normalizeDir(dir)
+	trackedMu.RLock()
+	defer trackedMu.RUnlock()
+	for _, t := range tracked {
+		if dir != t.dir && !strings.HasPrefix(dir, t.dir+string(filepath.Separator)) {
+			continue
+		}
+		t.mu.Lock()
+		t.ops[operation] += d
+		t.mu.Unlock()
+	}
+}
+
+// normalizeDir returns the provided directory as a clean, absolute path with
+// symlinks resolved, so that paths referring to the same directory compare
+// equal. If the path can not be resolved, it is only cleaned.
+func normalizeDir(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	return filepath.Clean(dir)
+}
@@ -0,0 +1,49 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSlow(t *testing.T) {
+	t.Cleanup(func() {
+		SetOperationsConfig(OperationsConfig{SlowOperationThreshold: time.Minute})
+	})
+
+	SetOperationsConfig(OperationsConfig{SlowOperationThreshold: time.Second})
+	require.False(t, IsSlow(time.Second))
+	require.True(t, IsSlow(2*time.Second))
+
+	SetOperationsConfig(OperationsConfig{})
+	require.False(t, IsSlow(time.Hour))
+}
+
+func TestTrackTimings(t *testing.T) {
+	dir := t.TempDir()
+	subDir := filepath.Join(dir, "repo")
+	require.NoError(t, os.Mkdir(subDir, 0o755))
+	// A sibling whose name shares the tracked directory's name as a prefix.
+	sibling := dir + "-other"
+
+	timings, untrack := TrackTimings(dir)
+	RecordTiming(subDir, "git clone", 3*time.Second)
+	RecordTiming(dir, "git push", time.Second)
+	RecordTiming(subDir, "git clone", time.Second)
+	RecordTiming(sibling, "git clone", time.Hour)
+	RecordTiming("", "git clone", time.Hour)
+	untrack()
+	RecordTiming(subDir, "git clone", time.Hour)
+
+	require.Equal(
+		t,
+		[]OperationTiming{
+			{Operation: "git clone", Duration: 4 * time.Second},
+			{Operation: "git push", Duration: time.Second},
+		},
+		timings.Get(),
+	)
+}
@@ -0,0 +1,184 @@
+package exec
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akuity/kargo/internal/credentials"
+)
+
+const (
+	// maxTranscriptOutputBytes is the number of bytes of the output of a
+	// command that a Transcript retains. Longer output is truncated at the
+	// start, as its end most likely explains why a command failed.
+	maxTranscriptOutputBytes = 4 * 1024
+	// maxTranscriptBytes is the approximate size a Transcript is limited to.
+	// Once it is reached, the oldest commands are discarded, so that the
+	// Transcript fits comfortably in a ConfigMap.
+	maxTranscriptBytes = 512 * 1024
+)
+
+// TranscriptEntry describes a single command that was executed.
+type TranscriptEntry struct {
+	// Command is the command, with its arguments, with any credentials
+	// redacted.
+	Command string
+	// Dir is the directory the command was executed in.
+	Dir string
+	// StartedAt is the time the command was started.
+	StartedAt time.Time
+	// Duration is how long the command took to execute.
+	Duration time.Duration
+	// ExitCode is the exit code of the command, or -1 if the command could not
+	// be executed or did not exit normally.
+	ExitCode int
+	// Output is the combined output (stdout and stderr) of the command, with
+	// any credentials redacted, truncated to its last few kilobytes.
+	Output string
+}
+
+func (e TranscriptEntry) size() int {
+	return len(e.Command) + len(e.Dir) + len(e.Output)
+}
+
+// Transcript accumulates the commands that were executed in a directory tree
+// while it was tracked, in the order they completed.
+type Transcript struct {
+	dir     string
+	mu      sync.Mutex
+	entries []TranscriptEntry
+	size    int
+	omitted int
+}
+
+// NewTranscript returns an empty Transcript of the commands that are executed
+// anywhere in the provided directory tree. Commands are only recorded while
+// the Transcript is tracked.
+func NewTranscript(dir string) *Transcript {
+	return &Transcript{dir: normalizeDir(dir)}
+}
+
+// Track starts recording the commands that are executed in the Transcript's
+// directory tree. It returns a function that must be called to stop recording
+// them. A Transcript may be tracked repeatedly, to accumulate the commands of
+// several separate executions.
+func (t *Transcript) Track() func() {
+	transcriptsMu.Lock()
+	transcripts = append(transcripts, t)
+	transcriptsMu.Unlock()
+	return func() {
+		transcriptsMu.Lock()
+		defer transcriptsMu.Unlock()
+		transcripts = slices.DeleteFunc(transcripts, func(other *Transcript) bool {
+			return other == t
+		})
+	}
+}
+
+// Entries returns the commands recorded in the Transcript, oldest first.
+func (t *Transcript) Entries() []TranscriptEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.entries)
+}
+
+// String returns a human-readable rendition of the Transcript. If earlier
+// commands had to be discarded to limit its size, this is noted first.
+func (t *Transcript) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var sb strings.Builder
+	if t.omitted > 0 {
+		fmt.Fprintf(&sb, "(%d earlier command(s) omitted)\n\n", t.omitted)
+	}
+	for i, e := range t.entries {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(
+			&sb,
+			"$ %s\n# dir: %s, started: %s, duration: %s, exit code: %d\n",
+			e.Command,
+			e.Dir,
+			e.StartedAt.UTC().Format(time.RFC3339),
+			e.Duration.Round(time.Millisecond),
+			e.ExitCode,
+		)
+		if e.Output != "" {
+			sb.WriteString(e.Output)
+			if !strings.HasSuffix(e.Output, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func (t *Transcript) append(e TranscriptEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, e)
+	t.size += e.size()
+	for t.size > maxTranscriptBytes && len(t.entries) > 1 {
+		t.size -= t.entries[0].size()
+		t.entries = t.entries[1:]
+		t.omitted++
+	}
+}
+
+var (
+	transcriptsMu sync.RWMutex
+	transcripts   []*Transcript
+)
+
+// RecordCommand adds the provided command, which was started at the provided
+// time and produced the provided output and exit code, to all Transcripts
+// that are tracked for a directory tree containing the directory it was
+// executed in. Commands executed using Exec are recorded automatically, so
+// this only needs to be called for commands that are executed otherwise.
+func RecordCommand(cmd *exec.Cmd, startedAt time.Time, output []byte, exitCode int) {
+	if cmd.Dir == "" {
+		return
+	}
+	transcriptsMu.RLock()
+	defer transcriptsMu.RUnlock()
+	if len(transcripts) == 0 {
+		return
+	}
+	dir := normalizeDir(cmd.Dir)
+	var entry *TranscriptEntry
+	for _, t := range transcripts {
+		if dir != t.dir && !strings.HasPrefix(dir, t.dir+string(filepath.Separator)) {
+			continue
+		}
+		if entry == nil {
+			entry = &TranscriptEntry{
+				Command:   credentials.Redact(strings.Join(cmd.Args, " ")),
+				Dir:       cmd.Dir,
+				StartedAt: startedAt,
+				Duration:  time.Since(startedAt),
+				ExitCode:  exitCode,
+				Output:    credentials.Redact(truncateOutput(output)),
+			}
+		}
+		t.append(*entry)
+	}
+}
+
+// truncateOutput returns the last maxTranscriptOutputBytes of the provided
+// output, noting how much was truncated, if anything.
+func truncateOutput(output []byte) string {
+	if len(output) <= maxTranscriptOutputBytes {
+		return string(output)
+	}
+	return fmt.Sprintf(
+		"(%d bytes truncated)\n%s",
+		len(output)-maxTranscriptOutputBytes,
+		output[len(output)-maxTranscriptOutputBytes:],
+	)
+}
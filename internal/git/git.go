@@ -14,12 +14,29 @@ var scpSyntaxRegex = regexp.MustCompile(`^((?:[\w-]+@)?[\w-]+(?:\.[\w-]+)*)(?::(
 //   - http[s]://[proxy-user:proxy-pass@]host.xz[:port][/path/to/repo[.git][/]]
 //   - ssh://[user@]host.xz[:port][/path/to/repo[.git][/]]
 //   - [user@]host.xz[:path/to/repo[.git][/]]
+//   - file:///path/to/repo[.git][/]
+//   - /path/to/repo[.git][/]
 //
 // This is useful for the purposes of comparison and also in cases where a
 // canonical representation of a Git URL is needed. Any URL that cannot be
 // normalized will be returned as-is.
 func NormalizeURL(repo string) string {
 	origRepo := repo
+
+	// Local repositories. These are handled before anything else because paths
+	// are case-sensitive and because a file:// URL would otherwise be mistaken
+	// for an SCP-style URL.
+	if IsLocalURL(repo) {
+		var scheme string
+		if len(repo) >= len(fileURLPrefix) &&
+			strings.EqualFold(repo[:len(fileURLPrefix)], fileURLPrefix) {
+			scheme, repo = fileURLPrefix, repo[len(fileURLPrefix):]
+		}
+		repo = strings.TrimSuffix(repo, "/")
+		repo = strings.TrimSuffix(repo, ".git")
+		return scheme + repo
+	}
+
 	repo = strings.ToLower(repo)
 
 	// HTTP/S URLs
@@ -65,14 +82,116 @@ func NormalizeURL(repo string) string {
 	if len(matches) == 3 {
 		path = matches[2]
 	}
-	pathURL, err := url.Parse(path)
-	if err != nil {
-		return origRepo
-	}
-	pathURL.Path = strings.TrimSuffix(pathURL.Path, "/")
-	pathURL.Path = strings.TrimSuffix(pathURL.Path, ".git")
-	if pathURL.Path == "" {
+	path = strings.TrimSuffix(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	if path == "" {
 		return fmt.Sprintf("ssh://%s", userHost)
 	}
-	return fmt.Sprintf("ssh://%s/%s", userHost, pathURL.String())
+	// Paths that cannot be parsed as a URL path (e.g. because they contain a
+	// colon or an invalid escape sequence) are used verbatim.
+	if pathURL, err := url.Parse(path); err == nil {
+		path = pathURL.String()
+	}
+	return fmt.Sprintf("ssh://%s/%s", userHost, path)
+}
+
+const fileURLPrefix = "file://"
+
+// IsLocalURL returns true if the provided Git URL refers to a repository on
+// the local file system, i.e. it is a file:// URL or an absolute path. Such
+// repositories never require credentials.
+func IsLocalURL(repo string) bool {
+	return strings.HasPrefix(strings.ToLower(repo), fileURLPrefix) ||
+		strings.HasPrefix(repo, "/")
+}
+
+// sshHostAliases maps the hostnames that some Git hosting providers serve SSH
+// on, as an alternative to port 22, to the hostnames they serve HTTPS on.
+var sshHostAliases = map[string]string{
+	"ssh.github.com":       "github.com",
+	"altssh.gitlab.com":    "gitlab.com",
+	"altssh.bitbucket.org": "bitbucket.org",
+}
+
+// bitbucketServerSSHPort is the port that Bitbucket Server and Bitbucket Data
+// Center serve SSH on by default. Repositories that are addressed using this
+// port are served over HTTPS beneath the /scm path.
+const bitbucketServerSSHPort = "7999"
+
+// SSHToHTTPSURL returns the HTTPS URL of the repository addressed by the
+// provided SSH URL, which may be of the form
+// ssh://[user@]host.xz[:port]/path/to/repo[.git] or
+// [user@]host.xz:path/to/repo[.git]. The user and port are dropped, with the
+// exception that a Bitbucket Server port indicates the path must be prefixed
+// with /scm. Hostnames that providers use to serve SSH over port 443 (e.g.
+// ssh.github.com) are mapped to their HTTPS equivalents. If the provided URL is
+// not an SSH URL, false is returned.
+func SSHToHTTPSURL(repo string) (string, bool) {
+	var host, port, path string
+	switch lower := strings.ToLower(repo); {
+	case strings.HasPrefix(lower, "ssh://"), strings.HasPrefix(lower, "git+ssh://"):
+		repoURL, err := url.Parse(repo)
+		if err != nil || repoURL.Hostname() == "" {
+			return "", false
+		}
+		host, port, path = repoURL.Hostname(), repoURL.Port(), repoURL.Path
+	case strings.Contains(lower, "://") || IsLocalURL(repo):
+		return "", false
+	default:
+		matches := scpSyntaxRegex.FindStringSubmatch(repo)
+		if len(matches) != 3 || matches[2] == "" {
+			return "", false
+		}
+		host = matches[1]
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+		path = matches[2]
+	}
+	host = strings.ToLower(host)
+	if alias, ok := sshHostAliases[host]; ok {
+		host = alias
+	}
+	path = "/" + strings.TrimPrefix(path, "/")
+	if port == bitbucketServerSSHPort {
+		path = "/scm" + path
+	}
+	return (&url.URL{Scheme: "https", Host: host, Path: path}).String(), true
+}
+
+// ValidateBranchName returns an error if the provided name is not a valid
+// name for a Git branch, following the rules applied by
+// `git check-ref-format --branch`.
+func ValidateBranchName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("branch name must not be empty")
+	case name == "@" || name == "HEAD":
+		return fmt.Errorf("%q is not a valid branch name", name)
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("branch name %q must not begin with '-'", name)
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return fmt.Errorf("branch name %q must not begin or end with '/'", name)
+	case strings.HasSuffix(name, "."):
+		return fmt.Errorf("branch name %q must not end with '.'", name)
+	}
+	for _, seq := range []string{"..", "//", "@{"} {
+		if strings.Contains(name, seq) {
+			return fmt.Errorf("branch name %q must not contain %q", name, seq)
+		}
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(` ~^:?*[\`, r) {
+			return fmt.Errorf("branch name %q must not contain %q", name, r)
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			return fmt.Errorf(
+				"components of branch name %q must not begin with '.' or end with '.lock'",
+				name,
+			)
+		}
+	}
+	return nil
 }
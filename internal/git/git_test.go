@@ -81,6 +81,15 @@ func TestNormalizeURL(t *testing.T) {
 		"git@github.com:example/repo/":     "ssh://git@github.com/example/repo", // 101
 		"git@github.com:example/repo.git":  "ssh://git@github.com/example/repo", // 110
 		"git@github.com:example/repo.git/": "ssh://git@github.com/example/repo", // 111
+		// SCP-style URLs with paths that are not valid URL paths
+		"git@github.com:example/repo:v1.git": "ssh://git@github.com/example/repo:v1",
+		"git@github.com:example/%zz.git":     "ssh://git@github.com/example/%zz",
+		// Local repositories, whose paths are case-sensitive
+		"file:///srv/git/Repo":      "file:///srv/git/Repo",
+		"file:///srv/git/Repo.git/": "file:///srv/git/Repo",
+		"FILE:///srv/git/Repo.git":  "file:///srv/git/Repo",
+		"/srv/git/Repo":             "/srv/git/Repo",
+		"/srv/git/Repo.git/":        "/srv/git/Repo",
 	}
 	for in, out := range testCases {
 		t.Run(in, func(t *testing.T) {
@@ -88,3 +97,98 @@ func TestNormalizeURL(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLocalURL(t *testing.T) {
+	testCases := map[string]bool{
+		"file:///srv/git/repo.git":          true,
+		"FILE:///srv/git/repo.git":          true,
+		"/srv/git/repo.git":                 true,
+		"https://github.com/example/repo":   false,
+		"ssh://git@github.com/example/repo": false,
+		"git@github.com:example/repo.git":   false,
+		"relative/path/to/repo":             false,
+	}
+	for in, out := range testCases {
+		t.Run(in, func(t *testing.T) {
+			require.Equal(t, out, IsLocalURL(in))
+		})
+	}
+}
+
+func TestSSHToHTTPSURL(t *testing.T) {
+	testCases := map[string]string{
+		"git@github.com:example/repo.git":                       "https://github.com/example/repo.git",
+		"ssh://git@github.com/example/repo.git":                 "https://github.com/example/repo.git",
+		"ssh://git@ssh.github.com:443/example/repo.git":         "https://github.com/example/repo.git",
+		"git@gitlab.com:group/subgroup/Repo.git":                "https://gitlab.com/group/subgroup/Repo.git",
+		"ssh://git@gitlab.example.com:2222/group/repo.git":      "https://gitlab.example.com/group/repo.git",
+		"ssh://git@altssh.gitlab.com:443/group/repo.git":        "https://gitlab.com/group/repo.git",
+		"git@bitbucket.org:workspace/repo.git":                  "https://bitbucket.org/workspace/repo.git",
+		"ssh://git@altssh.bitbucket.org:443/workspace/repo":     "https://bitbucket.org/workspace/repo",
+		"ssh://git@bitbucket.example.com:7999/project/repo.git": "https://bitbucket.example.com/scm/project/repo.git",
+		"git+ssh://git@GitHub.com/example/repo":                 "https://github.com/example/repo",
+	}
+	for in, out := range testCases {
+		t.Run(in, func(t *testing.T) {
+			httpsURL, ok := SSHToHTTPSURL(in)
+			require.True(t, ok)
+			require.Equal(t, out, httpsURL)
+		})
+	}
+	for _, in := range []string{
+		"https://github.com/example/repo.git",
+		"http://git.example.com/repo",
+		"file:///srv/git/repo.git",
+		"/srv/git/repo.git",
+		"github.com",
+		"",
+	} {
+		t.Run(in, func(t *testing.T) {
+			_, ok := SSHToHTTPSURL(in)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestValidateBranchName(t *testing.T) {
+	testCases := map[string]bool{
+		"main":                    true,
+		"rendered/prod/us-east-1": true,
+		"feature/foo.bar":         true,
+		"v1.0@beta":               true,
+		"":                        false,
+		"@":                       false,
+		"HEAD":                    false,
+		"-main":                   false,
+		"/main":                   false,
+		"main/":                   false,
+		"main.":                   false,
+		"foo..bar":                false,
+		"foo//bar":                false,
+		"foo@{bar":                false,
+		"foo bar":                 false,
+		"foo~1":                   false,
+		"foo^":                    false,
+		"foo:bar":                 false,
+		"foo?":                    false,
+		"foo*":                    false,
+		"foo[bar":                 false,
+		`foo\bar`:                 false,
+		"foo\tbar":                false,
+		"foo\x7fbar":              false,
+		".hidden/main":            false,
+		"foo/.hidden":             false,
+		"main.lock":               false,
+		"main.lock/foo":           false,
+	}
+	for name, valid := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateBranchName(name)
+			if valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	repoURLPatternRegexpPrefix = "regexp:"
+	repoURLPatternRegexPrefix  = "regex:"
+	repoURLPatternGlobPrefix   = "glob:"
+)
+
+// RepoURLPolicy restricts the Git repositories that may be accessed to those
+// with URLs matching a list of allowed patterns and not matching a list of
+// denied patterns. Patterns are matched against normalized URLs (see
+// NormalizeURL) and are glob patterns (optionally prefixed with "glob:") or
+// regular expressions (prefixed with "regex:" or "regexp:"). In glob
+// patterns, "*" does not match "/".
+//
+// A nil *RepoURLPolicy is valid and allows access to all repositories.
+type RepoURLPolicy struct {
+	allow []repoURLPattern
+	deny  []repoURLPattern
+}
+
+// RepoURLDecision is the outcome of the evaluation of a RepoURLPolicy for a
+// single repository URL.
+type RepoURLDecision struct {
+	// Allowed indicates whether access to the repository is allowed.
+	Allowed bool
+	// Rule is the pattern that the decision is based on. It is empty if access
+	// was allowed because no allowed patterns were specified, or denied
+	// because the URL did not match any of them.
+	Rule string
+}
+
+// String returns a human-readable description of the decision.
+func (d RepoURLDecision) String() string {
+	switch {
+	case d.Allowed && d.Rule == "":
+		return "allowed"
+	case d.Allowed:
+		return fmt.Sprintf("allowed by rule %q", d.Rule)
+	case d.Rule == "":
+		return "denied because it matches no allowed rule"
+	default:
+		return fmt.Sprintf("denied by rule %q", d.Rule)
+	}
+}
+
+type repoURLPattern struct {
+	pattern string
+	match   func(string) bool
+}
+
+// NewRepoURLPolicy returns a RepoURLPolicy for the provided allowed and denied
+// patterns. An empty list of allowed patterns allows all repositories that
+// are not explicitly denied. An error is returned if any pattern is invalid.
+func NewRepoURLPolicy(allow, deny []string) (*RepoURLPolicy, error) {
+	p := &RepoURLPolicy{}
+	var err error
+	if p.allow, err = compileRepoURLPatterns(allow); err != nil {
+		return nil, err
+	}
+	if p.deny, err = compileRepoURLPatterns(deny); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ValidateRepoURLPattern returns an error if the provided pattern cannot be
+// used in a RepoURLPolicy.
+func ValidateRepoURLPattern(pattern string) error {
+	_, err := compileRepoURLPattern(pattern)
+	return err
+}
+
+// Evaluate decides whether access to the repository with the provided URL is
+// allowed. Denied patterns take precedence over allowed patterns.
+func (p *RepoURLPolicy) Evaluate(repoURL string) RepoURLDecision {
+	if p == nil {
+		return RepoURLDecision{Allowed: true}
+	}
+	repoURL = NormalizeURL(repoURL)
+	for _, d := range p.deny {
+		if d.match(repoURL) {
+			return RepoURLDecision{Rule: d.pattern}
+		}
+	}
+	if len(p.allow) == 0 {
+		return RepoURLDecision{Allowed: true}
+	}
+	for _, a := range p.allow {
+		if a.match(repoURL) {
+			return RepoURLDecision{Allowed: true, Rule: a.pattern}
+		}
+	}
+	return RepoURLDecision{}
+}
+
+func compileRepoURLPatterns(patterns []string) ([]repoURLPattern, error) {
+	compiled := make([]repoURLPattern, len(patterns))
+	for i, pattern := range patterns {
+		var err error
+		if compiled[i], err = compileRepoURLPattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+func compileRepoURLPattern(pattern string) (repoURLPattern, error) {
+	var expr string
+	switch {
+	case strings.HasPrefix(pattern, repoURLPatternRegexpPrefix):
+		expr = strings.TrimPrefix(pattern, repoURLPatternRegexpPrefix)
+	case strings.HasPrefix(pattern, repoURLPatternRegexPrefix):
+		expr = strings.TrimPrefix(pattern, repoURLPatternRegexPrefix)
+	default:
+		glob := strings.TrimPrefix(pattern, repoURLPatternGlobPrefix)
+		if _, err := path.Match(glob, ""); err != nil {
+			return repoURLPattern{}, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+		return repoURLPattern{
+			pattern: pattern,
+			match: func(repoURL string) bool {
+				matched, _ := path.Match(glob, repoURL)
+				return matched
+			},
+		}, nil
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return repoURLPattern{}, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+	}
+	return repoURLPattern{pattern: pattern, match: regex.MatchString}, nil
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRepoURLPolicy(t *testing.T) {
+	testCases := []struct {
+		name  string
+		allow []string
+		deny  []string
+		err   string
+	}{
+		{
+			name: "no patterns",
+		},
+		{
+			name:  "valid patterns",
+			allow: []string{"https://github.com/example/*", "glob:ssh://git@github.com/example/*"},
+			deny:  []string{`regex:^https://github\.com/example/secret-.*$`, "regexp:internal"},
+		},
+		{
+			name:  "invalid glob pattern",
+			allow: []string{"https://github.com/[example/*"},
+			err:   "invalid glob pattern",
+		},
+		{
+			name: "invalid regular expression",
+			deny: []string{"regex:(example"},
+			err:  "invalid regular expression",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policy, err := NewRepoURLPolicy(testCase.allow, testCase.deny)
+			if testCase.err != "" {
+				require.ErrorContains(t, err, testCase.err)
+				require.Nil(t, policy)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, policy)
+		})
+	}
+}
+
+func TestRepoURLPolicy_Evaluate(t *testing.T) {
+	policy, err := NewRepoURLPolicy(
+		[]string{
+			"https://github.com/example/*",
+			"regex:^ssh://git@github\\.com/example/",
+		},
+		[]string{"https://github.com/example/secret-*"},
+	)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		policy   *RepoURLPolicy
+		repoURL  string
+		expected RepoURLDecision
+	}{
+		{
+			name:     "nil policy",
+			repoURL:  "https://github.com/other/repo",
+			expected: RepoURLDecision{Allowed: true},
+		},
+		{
+			name:     "no allowed patterns",
+			policy:   &RepoURLPolicy{},
+			repoURL:  "https://github.com/other/repo",
+			expected: RepoURLDecision{Allowed: true},
+		},
+		{
+			name:     "allowed by glob pattern",
+			policy:   policy,
+			repoURL:  "https://github.com/example/repo",
+			expected: RepoURLDecision{Allowed: true, Rule: "https://github.com/example/*"},
+		},
+		{
+			name:     "allowed after normalization",
+			policy:   policy,
+			repoURL:  "https://GitHub.com/example/repo.git/",
+			expected: RepoURLDecision{Allowed: true, Rule: "https://github.com/example/*"},
+		},
+		{
+			name:     "allowed by regular expression",
+			policy:   policy,
+			repoURL:  "git@github.com:example/repo.git",
+			expected: RepoURLDecision{Allowed: true, Rule: "regex:^ssh://git@github\\.com/example/"},
+		},
+		{
+			name:     "glob pattern does not match nested path",
+			policy:   policy,
+			repoURL:  "https://github.com/example/repo/nested",
+			expected: RepoURLDecision{},
+		},
+		{
+			name:     "denied by matching no allowed pattern",
+			policy:   policy,
+			repoURL:  "https://github.com/other/repo",
+			expected: RepoURLDecision{},
+		},
+		{
+			name:     "deny takes precedence over allow",
+			policy:   policy,
+			repoURL:  "https://github.com/example/secret-repo",
+			expected: RepoURLDecision{Rule: "https://github.com/example/secret-*"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, testCase.policy.Evaluate(testCase.repoURL))
+		})
+	}
+}
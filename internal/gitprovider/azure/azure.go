@@ -206,6 +206,7 @@ func convertADOPullRequest(pr *adogit.GitPullRequest) (*gitprovider.PullRequest,
 		MergeCommitSHA: ptr.Deref(mergeCommit.CommitId, ""),
 		Object:         pr,
 		HeadSHA:        ptr.Deref(pr.LastMergeSourceCommit.CommitId, ""),
+		HeadBranch:     strings.TrimPrefix(ptr.Deref(pr.SourceRefName, ""), "refs/heads/"),
 	}, nil
 }
 
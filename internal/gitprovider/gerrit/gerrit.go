@@ -0,0 +1,292 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+const ProviderName = "gerrit"
+
+// magicPrefix is the prefix with which Gerrit prevents its JSON responses
+// from being used in cross-site script inclusion attacks.
+var magicPrefix = []byte(")]}'")
+
+// gerritTimeFormat is the format of timestamps in responses of the Gerrit REST
+// API, which are in UTC.
+const gerritTimeFormat = "2006-01-02 15:04:05.000000000"
+
+var registration = gitprovider.Registration{
+	Predicate: func(repoURL string) bool {
+		baseURL, _, err := parseRepoURL(repoURL)
+		if err != nil {
+			return false
+		}
+		// We assume that any hostname with the word "gerrit" in it can use this
+		// provider. Gerrit servers whose hostnames do not include it must be
+		// selected explicitly.
+		return strings.Contains(baseURL.Host, ProviderName)
+	},
+	NewProvider: func(
+		repoURL string,
+		opts *gitprovider.Options,
+	) (gitprovider.Interface, error) {
+		return NewProvider(repoURL, opts)
+	},
+}
+
+func init() {
+	gitprovider.Register(ProviderName, registration)
+}
+
+// provider is a Gerrit implementation of gitprovider.Interface, to which pull
+// requests are Gerrit changes. Changes are created by pushing commits to the
+// magic refs/for/<branch> ref of a repository rather than through the API, so
+// creating pull requests is not supported.
+type provider struct {
+	// baseURL is the URL of the Gerrit server, including any path prefix.
+	baseURL *url.URL
+	// project is the name of the Gerrit project, i.e. repository.
+	project  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewProvider returns a Gerrit-based implementation of gitprovider.Interface.
+// The URL of the Gerrit REST API is derived from the provided repository URL.
+// If the options include a username, the API is authenticated against using
+// the username and the token as the HTTP password of the user.
+func NewProvider(
+	repoURL string,
+	opts *gitprovider.Options,
+) (gitprovider.Interface, error) {
+	if opts == nil {
+		opts = &gitprovider.Options{}
+	}
+	baseURL, project, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	return &provider{
+		baseURL:  baseURL,
+		project:  project,
+		username: opts.Username,
+		password: opts.Token,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: opts.InsecureSkipTLSVerify, // nolint: gosec
+				},
+			},
+		},
+	}, nil
+}
+
+// CreatePullRequest implements gitprovider.Interface.
+func (p *provider) CreatePullRequest(
+	context.Context,
+	*gitprovider.CreatePullRequestOpts,
+) (*gitprovider.PullRequest, error) {
+	return nil, errors.New(
+		"Gerrit changes cannot be created through the API; push commits for " +
+			"review instead",
+	)
+}
+
+// GetPullRequest implements gitprovider.Interface.
+func (p *provider) GetPullRequest(
+	ctx context.Context,
+	number int64,
+) (*gitprovider.PullRequest, error) {
+	var change changeInfo
+	if err := p.get(
+		ctx,
+		fmt.Sprintf("changes/%s~%d", url.PathEscape(p.project), number),
+		url.Values{"o": []string{"CURRENT_REVISION"}},
+		&change,
+	); err != nil {
+		return nil, fmt.Errorf("error getting change %d: %w", number, err)
+	}
+	return p.convertChange(&change), nil
+}
+
+// ListPullRequests implements gitprovider.Interface.
+func (p *provider) ListPullRequests(
+	ctx context.Context,
+	opts *gitprovider.ListPullRequestOptions,
+) ([]gitprovider.PullRequest, error) {
+	if opts == nil {
+		opts = &gitprovider.ListPullRequestOptions{}
+	}
+	// Gerrit changes have no source branch, so filtering by one yields nothing.
+	if opts.HeadBranch != "" {
+		return []gitprovider.PullRequest{}, nil
+	}
+	query := []string{fmt.Sprintf("project:%q", p.project)}
+	switch opts.State {
+	case gitprovider.PullRequestStateOpen:
+		query = append(query, "is:open")
+	case gitprovider.PullRequestStateClosed:
+		query = append(query, "is:closed")
+	}
+	if opts.BaseBranch != "" {
+		query = append(query, fmt.Sprintf("branch:%q", opts.BaseBranch))
+	}
+	if opts.HeadCommit != "" {
+		query = append(query, "commit:"+opts.HeadCommit)
+	}
+	var changes []changeInfo
+	if err := p.get(
+		ctx,
+		"changes/",
+		url.Values{
+			"q": []string{strings.Join(query, " ")},
+			"o": []string{"CURRENT_REVISION"},
+		},
+		&changes,
+	); err != nil {
+		return nil, fmt.Errorf("error listing changes: %w", err)
+	}
+	prs := make([]gitprovider.PullRequest, len(changes))
+	for i := range changes {
+		prs[i] = *p.convertChange(&changes[i])
+	}
+	return prs, nil
+}
+
+// changeInfo is the subset of the ChangeInfo entity of the Gerrit REST API
+// that is of interest.
+type changeInfo struct {
+	Number          int64  `json:"_number"`
+	ChangeID        string `json:"change_id"`
+	Status          string `json:"status"`
+	CurrentRevision string `json:"current_revision"`
+	Created         string `json:"created"`
+}
+
+// convertChange converts a Gerrit change to a gitprovider.PullRequest. The
+// merge commit of a merged change is the current revision of the change,
+// which is the commit that was submitted. This is only the commit the target
+// branch points to if the project's submit type does not create merge commits,
+// e.g. if it is "Rebase Always" or "Cherry Pick".
+func (p *provider) convertChange(change *changeInfo) *gitprovider.PullRequest {
+	pr := &gitprovider.PullRequest{
+		Number: change.Number,
+		URL: p.baseURL.JoinPath(
+			"c", p.project, "+", fmt.Sprintf("%d", change.Number),
+		).String(),
+		Open:    change.Status == "NEW",
+		Merged:  change.Status == "MERGED",
+		HeadSHA: change.CurrentRevision,
+		Object:  change,
+	}
+	if pr.Merged {
+		pr.MergeCommitSHA = change.CurrentRevision
+	}
+	if created, err := time.Parse(gerritTimeFormat, change.Created); err == nil {
+		pr.CreatedAt = &created
+	}
+	return pr
+}
+
+// get sends a GET request for the provided path, relative to the base URL of
+// the REST API, and unmarshals the JSON response into the provided value.
+func (p *provider) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	v any,
+) error {
+	u := *p.baseURL
+	if p.username != "" {
+		// Authenticated endpoints are prefixed with /a/.
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/a"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/"
+	u.RawPath = ""
+	endpoint := u.String() + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)),
+		)
+	}
+	body = bytes.TrimPrefix(body, magicPrefix)
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+	return nil
+}
+
+// parseRepoURL returns the URL of the Gerrit server that hosts the repository
+// with the provided URL, along with the name of the project. Repositories
+// cloned over SSH are assumed to be served over HTTPS on the same host. A path
+// prefix, for servers not served from the root of their host, is only
+// recognized if the repository URL is that of an authenticated endpoint, i.e.
+// if the project name is preceded by /a/. Unlike git.NormalizeURL, this
+// preserves case, since Gerrit project names are case-sensitive.
+func parseRepoURL(repoURL string) (*url.URL, string, error) {
+	if !strings.Contains(repoURL, "://") {
+		// SCP-style URLs of the form [user@]host.xz:path/to/repo[.git]
+		userHost, path, ok := strings.Cut(repoURL, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("error parsing Gerrit repository URL %q", repoURL)
+		}
+		if _, host, ok := strings.Cut(userHost, "@"); ok {
+			userHost = host
+		}
+		repoURL = fmt.Sprintf("ssh://%s/%s", userHost, strings.TrimPrefix(path, "/"))
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing Gerrit repository URL %q: %w", repoURL, err)
+	}
+	base := &url.URL{Scheme: u.Scheme, Host: u.Host}
+	switch u.Scheme {
+	case "http", "https":
+	case "ssh":
+		base.Scheme = "https"
+		base.Host = u.Hostname()
+	default:
+		return nil, "", fmt.Errorf("unsupported scheme %q in Gerrit repository URL %q", u.Scheme, repoURL)
+	}
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if prefix, project, ok := strings.Cut("/"+path, "/a/"); ok {
+		base.Path = prefix
+		path = project
+	}
+	if path == "" {
+		return nil, "", fmt.Errorf("could not extract project from Gerrit repository URL %q", repoURL)
+	}
+	return base, path, nil
+}
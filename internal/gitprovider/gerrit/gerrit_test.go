@@ -0,0 +1,176 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akuity/kargo/internal/gitprovider"
+)
+
+func TestParseRepoURL(t *testing.T) {
+	testCases := []struct {
+		repoURL         string
+		expectedBaseURL string
+		expectedProject string
+		expectedErr     string
+	}{
+		{
+			repoURL:         "https://gerrit.example.com/platform/Manifests",
+			expectedBaseURL: "https://gerrit.example.com",
+			expectedProject: "platform/Manifests",
+		},
+		{
+			repoURL:         "https://gerrit.example.com:8443/a/platform/manifests.git",
+			expectedBaseURL: "https://gerrit.example.com:8443",
+			expectedProject: "platform/manifests",
+		},
+		{
+			repoURL:         "https://example.com/gerrit/a/platform/manifests",
+			expectedBaseURL: "https://example.com/gerrit",
+			expectedProject: "platform/manifests",
+		},
+		{
+			repoURL:         "ssh://kargo@gerrit.example.com:29418/platform/manifests",
+			expectedBaseURL: "https://gerrit.example.com",
+			expectedProject: "platform/manifests",
+		},
+		{
+			repoURL:         "kargo@gerrit.example.com:platform/manifests.git",
+			expectedBaseURL: "https://gerrit.example.com",
+			expectedProject: "platform/manifests",
+		},
+		{
+			repoURL:     "https://gerrit.example.com/",
+			expectedErr: "could not extract project",
+		},
+		{
+			repoURL:     "file:///tmp/manifests",
+			expectedErr: "unsupported scheme",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.repoURL, func(t *testing.T) {
+			baseURL, project, err := parseRepoURL(testCase.repoURL)
+			if testCase.expectedErr != "" {
+				require.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedBaseURL, baseURL.String())
+			require.Equal(t, testCase.expectedProject, project)
+		})
+	}
+}
+
+func TestRegistrationPredicate(t *testing.T) {
+	require.True(t, registration.Predicate("https://gerrit.example.com/platform/manifests"))
+	require.True(t, registration.Predicate("ssh://kargo@gerrit.example.com:29418/platform/manifests"))
+	require.False(t, registration.Predicate("https://github.com/example/manifests"))
+}
+
+func TestGetPullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/a/changes/platform%2Fmanifests~42", r.URL.RawPath)
+		require.Equal(t, "CURRENT_REVISION", r.URL.Query().Get("o"))
+		username, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "kargo", username)
+		require.Equal(t, "secret", password)
+		_, _ = w.Write([]byte(`)]}'
+{
+  "_number": 42,
+  "change_id": "I8473b95934b5732ac55d26311a706c9c2bde9940",
+  "status": "MERGED",
+  "current_revision": "184ebe53805e102605d11f6b143486d15c23a09c",
+  "created": "2024-05-01 12:00:00.000000000"
+}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(
+		srv.URL+"/a/platform/manifests",
+		&gitprovider.Options{Username: "kargo", Token: "secret"},
+	)
+	require.NoError(t, err)
+
+	pr, err := p.GetPullRequest(context.Background(), 42)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), pr.Number)
+	require.Equal(t, srv.URL+"/c/platform/manifests/+/42", pr.URL)
+	require.False(t, pr.Open)
+	require.True(t, pr.Merged)
+	require.Equal(t, "184ebe53805e102605d11f6b143486d15c23a09c", pr.MergeCommitSHA)
+	require.Equal(t, "184ebe53805e102605d11f6b143486d15c23a09c", pr.HeadSHA)
+	require.NotNil(t, pr.CreatedAt)
+}
+
+func TestListPullRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Without a username, the anonymous endpoints are used.
+		require.Equal(t, "/changes/", r.URL.Path)
+		require.Equal(
+			t,
+			`project:"platform/manifests" is:open branch:"main" commit:184ebe5`,
+			r.URL.Query().Get("q"),
+		)
+		_, _ = w.Write([]byte(`)]}'
+[
+  {
+    "_number": 42,
+    "status": "NEW",
+    "current_revision": "184ebe53805e102605d11f6b143486d15c23a09c"
+  }
+]`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(srv.URL+"/platform/manifests", nil)
+	require.NoError(t, err)
+
+	prs, err := p.ListPullRequests(
+		context.Background(),
+		&gitprovider.ListPullRequestOptions{
+			State:      gitprovider.PullRequestStateOpen,
+			BaseBranch: "main",
+			HeadCommit: "184ebe5",
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	require.Equal(t, int64(42), prs[0].Number)
+	require.True(t, prs[0].Open)
+	require.False(t, prs[0].Merged)
+	require.Empty(t, prs[0].MergeCommitSHA)
+
+	prs, err = p.ListPullRequests(
+		context.Background(),
+		&gitprovider.ListPullRequestOptions{HeadBranch: "feature"},
+	)
+	require.NoError(t, err)
+	require.Empty(t, prs)
+}
+
+func TestGetPullRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Not found: 42\n"))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(srv.URL+"/platform/manifests", nil)
+	require.NoError(t, err)
+
+	_, err = p.GetPullRequest(context.Background(), 42)
+	require.ErrorContains(t, err, "unexpected status 404: Not found: 42")
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	p, err := NewProvider("https://gerrit.example.com/platform/manifests", nil)
+	require.NoError(t, err)
+	_, err = p.CreatePullRequest(context.Background(), &gitprovider.CreatePullRequestOpts{})
+	require.ErrorContains(t, err, "push commits for review instead")
+}
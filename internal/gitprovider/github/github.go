@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -70,6 +71,27 @@ type githubClient interface {
 		number int,
 		labels []string,
 	) ([]*github.Label, *github.Response, error)
+
+	GetRepository(
+		ctx context.Context,
+		owner string,
+		repo string,
+	) (*github.Repository, *github.Response, error)
+
+	GetBranchProtection(
+		ctx context.Context,
+		owner string,
+		repo string,
+		branch string,
+	) (*github.Protection, *github.Response, error)
+
+	CreateStatus(
+		ctx context.Context,
+		owner string,
+		repo string,
+		ref string,
+		status *github.RepoStatus,
+	) (*github.RepoStatus, *github.Response, error)
 }
 
 // provider is a GitHub implementation of gitprovider.Interface.
@@ -157,6 +179,33 @@ func (g githubClientWrapper) AddLabelsToIssue(
 	return g.client.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
 }
 
+func (g githubClientWrapper) GetRepository(
+	ctx context.Context,
+	owner string,
+	repo string,
+) (*github.Repository, *github.Response, error) {
+	return g.client.Repositories.Get(ctx, owner, repo)
+}
+
+func (g githubClientWrapper) GetBranchProtection(
+	ctx context.Context,
+	owner string,
+	repo string,
+	branch string,
+) (*github.Protection, *github.Response, error) {
+	return g.client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+}
+
+func (g githubClientWrapper) CreateStatus(
+	ctx context.Context,
+	owner string,
+	repo string,
+	ref string,
+	status *github.RepoStatus,
+) (*github.RepoStatus, *github.Response, error) {
+	return g.client.Repositories.CreateStatus(ctx, owner, repo, ref, status)
+}
+
 // CreatePullRequest implements gitprovider.Interface.
 func (p *provider) CreatePullRequest(
 	ctx context.Context,
@@ -261,6 +310,103 @@ func (p *provider) ListPullRequests(
 	return prs, nil
 }
 
+// CheckPushAccess implements gitprovider.PushAccessChecker.
+func (p *provider) CheckPushAccess(ctx context.Context, branch string) error {
+	ghRepo, _, err := p.client.GetRepository(ctx, p.owner, p.repo)
+	if err != nil {
+		return fmt.Errorf("error getting repository %s/%s: %w", p.owner, p.repo, err)
+	}
+	if ghRepo == nil {
+		return fmt.Errorf("unexpected nil repository")
+	}
+	if !ghRepo.Permissions["push"] {
+		return fmt.Errorf(
+			"%w: credentials do not have push permission on repository %s/%s",
+			gitprovider.ErrPushForbidden, p.owner, p.repo,
+		)
+	}
+	protection, res, err := p.client.GetBranchProtection(ctx, p.owner, p.repo, branch)
+	if err != nil {
+		// A 404 is returned when the branch does not exist yet, in which case it
+		// cannot be protected either.
+		if errors.Is(err, github.ErrBranchNotProtected) ||
+			(res != nil && res.StatusCode == http.StatusNotFound) {
+			return nil
+		}
+		return fmt.Errorf("error getting protection for branch %q: %w", branch, err)
+	}
+	if protection == nil {
+		return nil
+	}
+	if protection.LockBranch != nil && ptr.Deref(protection.LockBranch.Enabled, false) {
+		return fmt.Errorf(
+			"%w: branch %q is locked", gitprovider.ErrPushForbidden, branch,
+		)
+	}
+	// Admins may push directly to a branch that requires pull request reviews
+	// unless the protection is also enforced for admins.
+	if protection.RequiredPullRequestReviews != nil &&
+		(!ghRepo.Permissions["admin"] ||
+			(protection.EnforceAdmins != nil && protection.EnforceAdmins.Enabled)) {
+		return fmt.Errorf(
+			"%w: branch %q requires changes to be made through a pull request",
+			gitprovider.ErrPushForbidden, branch,
+		)
+	}
+	return nil
+}
+
+// RepoSize implements gitprovider.RepoSizer. GitHub reports the size of a
+// repository in kilobytes.
+func (p *provider) RepoSize(ctx context.Context) (int64, error) {
+	ghRepo, _, err := p.client.GetRepository(ctx, p.owner, p.repo)
+	if err != nil {
+		return 0, fmt.Errorf("error getting repository %s/%s: %w", p.owner, p.repo, err)
+	}
+	if ghRepo == nil || ghRepo.Size == nil {
+		return 0, fmt.Errorf("size of repository %s/%s is unknown", p.owner, p.repo)
+	}
+	return int64(*ghRepo.Size) * 1024, nil
+}
+
+// SetCommitStatus implements gitprovider.CommitStatusSetter.
+func (p *provider) SetCommitStatus(
+	ctx context.Context,
+	commitID string,
+	status *gitprovider.CommitStatus,
+) error {
+	ghStatus := &github.RepoStatus{
+		State:       ptr.To(githubCommitStatusState(status.State)),
+		Context:     ptr.To(status.Context),
+		Description: ptr.To(status.Description),
+	}
+	if status.TargetURL != "" {
+		ghStatus.TargetURL = ptr.To(status.TargetURL)
+	}
+	if _, _, err := p.client.CreateStatus(ctx, p.owner, p.repo, commitID, ghStatus); err != nil {
+		return fmt.Errorf(
+			"error setting status of commit %q in repository %s/%s: %w",
+			commitID, p.owner, p.repo, err,
+		)
+	}
+	return nil
+}
+
+// githubCommitStatusState returns the GitHub commit status state corresponding
+// to the provided gitprovider.CommitStatusState.
+func githubCommitStatusState(state gitprovider.CommitStatusState) string {
+	switch state {
+	case gitprovider.CommitStatusStateSuccess:
+		return "success"
+	case gitprovider.CommitStatusStateFailure:
+		return "failure"
+	case gitprovider.CommitStatusStateError:
+		return "error"
+	default:
+		return "pending"
+	}
+}
+
 func convertGithubPR(ghPR github.PullRequest) gitprovider.PullRequest {
 	pr := gitprovider.PullRequest{
 		Number:         int64(ptr.Deref(ghPR.Number, 0)),
@@ -270,6 +416,7 @@ func convertGithubPR(ghPR github.PullRequest) gitprovider.PullRequest {
 		MergeCommitSHA: ptr.Deref(ghPR.MergeCommitSHA, ""),
 		Object:         ghPR,
 		HeadSHA:        ptr.Deref(ghPR.Head.SHA, ""),
+		HeadBranch:     ptr.Deref(ghPR.Head.Ref, ""),
 	}
 	if ghPR.CreatedAt != nil {
 		pr.CreatedAt = &ghPR.CreatedAt.Time
@@ -2,6 +2,8 @@ package github
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-github/v56/github"
@@ -158,6 +160,53 @@ func (m *mockGithubClient) CreatePullRequest(
 	return pr, resp, args.Error(2)
 }
 
+func (m *mockGithubClient) GetRepository(
+	ctx context.Context,
+	owner string,
+	repo string,
+) (*github.Repository, *github.Response, error) {
+	args := m.Called(ctx, owner, repo)
+	ghRepo, ok := args.Get(0).(*github.Repository)
+	if !ok {
+		return nil, nil, args.Error(2)
+	}
+	resp, ok := args.Get(1).(*github.Response)
+	if !ok {
+		return ghRepo, nil, args.Error(2)
+	}
+	return ghRepo, resp, args.Error(2)
+}
+
+func (m *mockGithubClient) GetBranchProtection(
+	ctx context.Context,
+	owner string,
+	repo string,
+	branch string,
+) (*github.Protection, *github.Response, error) {
+	args := m.Called(ctx, owner, repo, branch)
+	protection, ok := args.Get(0).(*github.Protection)
+	if !ok {
+		resp, _ := args.Get(1).(*github.Response)
+		return nil, resp, args.Error(2)
+	}
+	resp, ok := args.Get(1).(*github.Response)
+	if !ok {
+		return protection, nil, args.Error(2)
+	}
+	return protection, resp, args.Error(2)
+}
+
+func (m *mockGithubClient) CreateStatus(
+	ctx context.Context,
+	owner string,
+	repo string,
+	ref string,
+	status *github.RepoStatus,
+) (*github.RepoStatus, *github.Response, error) {
+	args := m.Called(ctx, owner, repo, ref, status)
+	return status, nil, args.Error(2)
+}
+
 func TestCreatePullRequestWithLabels(t *testing.T) {
 	opts := gitprovider.CreatePullRequestOpts{
 		Head:        "feature-branch",
@@ -345,3 +394,190 @@ func TestListPullRequests(t *testing.T) {
 	require.Equal(t, *mockClient.pr.URL, prs[0].URL)
 	require.True(t, prs[0].Open)
 }
+
+func TestCheckPushAccess(t *testing.T) {
+	const testBranch = "env/prod"
+	notFound := &github.Response{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+	}
+	testCases := []struct {
+		name       string
+		repo       *github.Repository
+		protection *github.Protection
+		resp       *github.Response
+		err        error
+		assertions func(*testing.T, error)
+	}{
+		{
+			name: "no push permission",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"pull": true},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, gitprovider.ErrPushForbidden)
+				require.ErrorContains(t, err, "do not have push permission")
+			},
+		},
+		{
+			name: "branch not protected",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true},
+			},
+			err: github.ErrBranchNotProtected,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "branch not found",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true},
+			},
+			resp: notFound,
+			err:  errors.New("not found"),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "error getting branch protection",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true},
+			},
+			err: errors.New("something went wrong"),
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.NotErrorIs(t, err, gitprovider.ErrPushForbidden)
+			},
+		},
+		{
+			name: "branch requires pull requests",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true},
+			},
+			protection: &github.Protection{
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, gitprovider.ErrPushForbidden)
+				require.ErrorContains(t, err, "through a pull request")
+			},
+		},
+		{
+			name: "admin bypasses required pull requests",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true, "admin": true},
+			},
+			protection: &github.Protection{
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{},
+				EnforceAdmins:              &github.AdminEnforcement{Enabled: false},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "required pull requests enforced for admins",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true, "admin": true},
+			},
+			protection: &github.Protection{
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{},
+				EnforceAdmins:              &github.AdminEnforcement{Enabled: true},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, gitprovider.ErrPushForbidden)
+			},
+		},
+		{
+			name: "branch locked",
+			repo: &github.Repository{
+				Permissions: map[string]bool{"push": true},
+			},
+			protection: &github.Protection{
+				LockBranch: &github.LockBranch{Enabled: github.Bool(true)},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, gitprovider.ErrPushForbidden)
+				require.ErrorContains(t, err, "is locked")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockClient := &mockGithubClient{}
+			mockClient.
+				On("GetRepository", context.Background(), testRepoOwner, testRepoName).
+				Return(testCase.repo, &github.Response{}, nil)
+			if testCase.repo.Permissions["push"] {
+				mockClient.
+					On(
+						"GetBranchProtection",
+						context.Background(),
+						testRepoOwner,
+						testRepoName,
+						testBranch,
+					).
+					Return(testCase.protection, testCase.resp, testCase.err)
+			}
+			g := provider{
+				owner:  testRepoOwner,
+				repo:   testRepoName,
+				client: mockClient,
+			}
+			err := g.CheckPushAccess(context.Background(), testBranch)
+			mockClient.AssertExpectations(t)
+			testCase.assertions(t, err)
+		})
+	}
+}
+
+func TestRepoSize(t *testing.T) {
+	mockClient := &mockGithubClient{}
+	mockClient.
+		On("GetRepository", context.Background(), testRepoOwner, testRepoName).
+		Return(&github.Repository{Size: github.Int(2048)}, &github.Response{}, nil)
+	g := provider{
+		owner:  testRepoOwner,
+		repo:   testRepoName,
+		client: mockClient,
+	}
+	size, err := g.RepoSize(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2048*1024), size)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSetCommitStatus(t *testing.T) {
+	mockClient := &mockGithubClient{}
+	mockClient.On(
+		"CreateStatus",
+		context.Background(),
+		testRepoOwner,
+		testRepoName,
+		"abc123",
+		&github.RepoStatus{
+			State:       github.String("failure"),
+			Context:     github.String("kargo/fake-project/fake-stage"),
+			Description: github.String("Promotion Failed"),
+			TargetURL:   github.String("https://kargo.example.com"),
+		},
+	).Return(nil, nil, nil)
+	g := provider{
+		owner:  testRepoOwner,
+		repo:   testRepoName,
+		client: mockClient,
+	}
+	err := g.SetCommitStatus(
+		context.Background(),
+		"abc123",
+		&gitprovider.CommitStatus{
+			Context:     "kargo/fake-project/fake-stage",
+			State:       gitprovider.CommitStatusStateFailure,
+			Description: "Promotion Failed",
+			TargetURL:   "https://kargo.example.com",
+		},
+	)
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
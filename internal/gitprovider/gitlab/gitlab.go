@@ -61,10 +61,38 @@ type mergeRequestClient interface {
 	) (*gitlab.MergeRequest, *gitlab.Response, error)
 }
 
+type projectClient interface {
+	GetProject(
+		pid any,
+		opt *gitlab.GetProjectOptions,
+		options ...gitlab.RequestOptionFunc,
+	) (*gitlab.Project, *gitlab.Response, error)
+}
+
+type protectedBranchClient interface {
+	GetProtectedBranch(
+		pid any,
+		branch string,
+		options ...gitlab.RequestOptionFunc,
+	) (*gitlab.ProtectedBranch, *gitlab.Response, error)
+}
+
+type commitStatusClient interface {
+	SetCommitStatus(
+		pid any,
+		sha string,
+		opt *gitlab.SetCommitStatusOptions,
+		options ...gitlab.RequestOptionFunc,
+	) (*gitlab.CommitStatus, *gitlab.Response, error)
+}
+
 // provider is a GitLab-based implementation of gitprovider.Interface.
 type provider struct { // nolint: revive
-	projectName string
-	client      mergeRequestClient
+	projectName       string
+	client            mergeRequestClient
+	projects          projectClient
+	protectedBranches protectedBranchClient
+	commits           commitStatusClient
 }
 
 // NewProvider returns a GitLab-based implementation of gitprovider.Interface.
@@ -103,8 +131,11 @@ func NewProvider(
 		return nil, err
 	}
 	return &provider{
-		projectName: projectName,
-		client:      client.MergeRequests,
+		projectName:       projectName,
+		client:            client.MergeRequests,
+		projects:          client.Projects,
+		protectedBranches: client.ProtectedBranches,
+		commits:           client.Commits,
 	}, nil
 }
 
@@ -196,6 +227,106 @@ func (p *provider) ListPullRequests(
 	return prs, nil
 }
 
+// CheckPushAccess implements gitprovider.PushAccessChecker.
+func (p *provider) CheckPushAccess(_ context.Context, branch string) error {
+	project, _, err := p.projects.GetProject(p.projectName, nil)
+	if err != nil {
+		return fmt.Errorf("error getting project %q: %w", p.projectName, err)
+	}
+	if project == nil || project.Permissions == nil {
+		return fmt.Errorf("could not determine access level for project %q", p.projectName)
+	}
+	// The effective access level is the higher of the levels granted through
+	// project and group membership.
+	accessLevel := gitlab.NoPermissions
+	if project.Permissions.ProjectAccess != nil {
+		accessLevel = project.Permissions.ProjectAccess.AccessLevel
+	}
+	if project.Permissions.GroupAccess != nil &&
+		project.Permissions.GroupAccess.AccessLevel > accessLevel {
+		accessLevel = project.Permissions.GroupAccess.AccessLevel
+	}
+	if accessLevel < gitlab.DeveloperPermissions {
+		return fmt.Errorf(
+			"%w: credentials do not have push permission on project %q",
+			gitprovider.ErrPushForbidden, p.projectName,
+		)
+	}
+	protectedBranch, res, err := p.protectedBranches.GetProtectedBranch(p.projectName, branch)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			return nil // The branch is not protected
+		}
+		return fmt.Errorf("error getting protection for branch %q: %w", branch, err)
+	}
+	if protectedBranch == nil {
+		return nil
+	}
+	var grantsToOthers bool
+	for _, level := range protectedBranch.PushAccessLevels {
+		if level == nil {
+			continue
+		}
+		if level.UserID != 0 || level.GroupID != 0 || level.DeployKeyID != 0 {
+			grantsToOthers = true
+			continue
+		}
+		if level.AccessLevel != gitlab.NoPermissions && accessLevel >= level.AccessLevel {
+			return nil
+		}
+	}
+	if grantsToOthers {
+		// Push access to the branch is also granted to specific users, groups,
+		// or deploy keys, and there is no way of telling whether the credentials
+		// belong to one of them.
+		return fmt.Errorf(
+			"could not determine whether credentials may push to protected branch %q",
+			branch,
+		)
+	}
+	return fmt.Errorf(
+		"%w: branch %q is protected and credentials are not allowed to push to it",
+		gitprovider.ErrPushForbidden, branch,
+	)
+}
+
+// SetCommitStatus implements gitprovider.CommitStatusSetter.
+func (p *provider) SetCommitStatus(
+	_ context.Context,
+	commitID string,
+	status *gitprovider.CommitStatus,
+) error {
+	opts := &gitlab.SetCommitStatusOptions{
+		State:       gitlabCommitStatusState(status.State),
+		Name:        &status.Context,
+		Description: &status.Description,
+	}
+	if status.TargetURL != "" {
+		opts.TargetURL = &status.TargetURL
+	}
+	if _, _, err := p.commits.SetCommitStatus(p.projectName, commitID, opts); err != nil {
+		return fmt.Errorf(
+			"error setting status of commit %q in project %q: %w",
+			commitID, p.projectName, err,
+		)
+	}
+	return nil
+}
+
+// gitlabCommitStatusState returns the GitLab build state corresponding to the
+// provided gitprovider.CommitStatusState. GitLab does not distinguish between
+// failures and errors.
+func gitlabCommitStatusState(state gitprovider.CommitStatusState) gitlab.BuildStateValue {
+	switch state {
+	case gitprovider.CommitStatusStateSuccess:
+		return gitlab.Success
+	case gitprovider.CommitStatusStateFailure, gitprovider.CommitStatusStateError:
+		return gitlab.Failed
+	default:
+		return gitlab.Pending
+	}
+}
+
 func convertGitlabMR(glMR gitlab.MergeRequest) gitprovider.PullRequest {
 	fmt.Println(glMR.MergeCommitSHA)
 	return gitprovider.PullRequest{
@@ -206,6 +337,7 @@ func convertGitlabMR(glMR gitlab.MergeRequest) gitprovider.PullRequest {
 		MergeCommitSHA: glMR.MergeCommitSHA,
 		Object:         glMR,
 		HeadSHA:        glMR.SHA,
+		HeadBranch:     glMR.SourceBranch,
 		CreatedAt:      glMR.CreatedAt,
 	}
 }
@@ -2,6 +2,8 @@ package gitlab
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -49,6 +51,48 @@ func (m *mockGitLabClient) GetMergeRequest(
 	return m.mr, nil, nil
 }
 
+type mockGitLabProjectClient struct {
+	project *gitlab.Project
+}
+
+func (m *mockGitLabProjectClient) GetProject(
+	any,
+	*gitlab.GetProjectOptions,
+	...gitlab.RequestOptionFunc,
+) (*gitlab.Project, *gitlab.Response, error) {
+	return m.project, nil, nil
+}
+
+type mockGitLabProtectedBranchClient struct {
+	protectedBranch *gitlab.ProtectedBranch
+	res             *gitlab.Response
+	err             error
+}
+
+func (m *mockGitLabProtectedBranchClient) GetProtectedBranch(
+	any,
+	string,
+	...gitlab.RequestOptionFunc,
+) (*gitlab.ProtectedBranch, *gitlab.Response, error) {
+	return m.protectedBranch, m.res, m.err
+}
+
+type mockGitLabCommitStatusClient struct {
+	sha  string
+	opts *gitlab.SetCommitStatusOptions
+}
+
+func (m *mockGitLabCommitStatusClient) SetCommitStatus(
+	_ any,
+	sha string,
+	opts *gitlab.SetCommitStatusOptions,
+	_ ...gitlab.RequestOptionFunc,
+) (*gitlab.CommitStatus, *gitlab.Response, error) {
+	m.sha = sha
+	m.opts = opts
+	return &gitlab.CommitStatus{}, nil, nil
+}
+
 func TestCreatePullRequest(t *testing.T) {
 	mockClient := &mockGitLabClient{
 		mr: &gitlab.MergeRequest{
@@ -170,3 +214,142 @@ func TestParseGitLabURL(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPushAccess(t *testing.T) {
+	developer := &gitlab.Project{
+		Permissions: &gitlab.Permissions{
+			ProjectAccess: &gitlab.ProjectAccess{
+				AccessLevel: gitlab.DeveloperPermissions,
+			},
+		},
+	}
+	testCases := []struct {
+		name              string
+		project           *gitlab.Project
+		protectedBranches *mockGitLabProtectedBranchClient
+		assertions        func(*testing.T, error)
+	}{
+		{
+			name: "insufficient access level",
+			project: &gitlab.Project{
+				Permissions: &gitlab.Permissions{
+					ProjectAccess: &gitlab.ProjectAccess{
+						AccessLevel: gitlab.ReporterPermissions,
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, gitprovider.ErrPushForbidden)
+				require.ErrorContains(t, err, "do not have push permission")
+			},
+		},
+		{
+			name: "access level granted through group",
+			project: &gitlab.Project{
+				Permissions: &gitlab.Permissions{
+					GroupAccess: &gitlab.GroupAccess{
+						AccessLevel: gitlab.MaintainerPermissions,
+					},
+				},
+			},
+			protectedBranches: &mockGitLabProtectedBranchClient{
+				protectedBranch: &gitlab.ProtectedBranch{
+					PushAccessLevels: []*gitlab.BranchAccessDescription{
+						{AccessLevel: gitlab.MaintainerPermissions},
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "branch not protected",
+			project: developer,
+			protectedBranches: &mockGitLabProtectedBranchClient{
+				res: &gitlab.Response{
+					Response: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				err: errors.New("404 Not Found"),
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "error getting protected branch",
+			project: developer,
+			protectedBranches: &mockGitLabProtectedBranchClient{
+				err: errors.New("something went wrong"),
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "something went wrong")
+				require.NotErrorIs(t, err, gitprovider.ErrPushForbidden)
+			},
+		},
+		{
+			name:    "no one allowed to push",
+			project: developer,
+			protectedBranches: &mockGitLabProtectedBranchClient{
+				protectedBranch: &gitlab.ProtectedBranch{
+					PushAccessLevels: []*gitlab.BranchAccessDescription{
+						{AccessLevel: gitlab.NoPermissions},
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, gitprovider.ErrPushForbidden)
+				require.ErrorContains(t, err, "is protected")
+			},
+		},
+		{
+			name:    "push restricted to specific users",
+			project: developer,
+			protectedBranches: &mockGitLabProtectedBranchClient{
+				protectedBranch: &gitlab.ProtectedBranch{
+					PushAccessLevels: []*gitlab.BranchAccessDescription{
+						{AccessLevel: gitlab.MaintainerPermissions},
+						{UserID: 42},
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "could not determine")
+				require.NotErrorIs(t, err, gitprovider.ErrPushForbidden)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := provider{
+				projectName:       testProjectName,
+				projects:          &mockGitLabProjectClient{project: testCase.project},
+				protectedBranches: testCase.protectedBranches,
+			}
+			testCase.assertions(t, g.CheckPushAccess(context.Background(), "main"))
+		})
+	}
+}
+
+func TestSetCommitStatus(t *testing.T) {
+	mockClient := &mockGitLabCommitStatusClient{}
+	g := provider{
+		projectName: testProjectName,
+		commits:     mockClient,
+	}
+	err := g.SetCommitStatus(
+		context.Background(),
+		"abc123",
+		&gitprovider.CommitStatus{
+			Context:     "kargo/fake-project/fake-stage",
+			State:       gitprovider.CommitStatusStateError,
+			Description: "Promotion Errored",
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "abc123", mockClient.sha)
+	require.Equal(t, gitlab.Failed, mockClient.opts.State)
+	require.Equal(t, "kargo/fake-project/fake-stage", *mockClient.opts.Name)
+	require.Equal(t, "Promotion Errored", *mockClient.opts.Description)
+	require.Nil(t, mockClient.opts.TargetURL)
+}
@@ -2,9 +2,14 @@ package gitprovider
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrPushForbidden is returned by implementations of PushAccessChecker when
+// the credentials in use are not permitted to push directly to a branch.
+var ErrPushForbidden = errors.New("push forbidden")
+
 // PullRequestState represents the state of a pull request. e.g. Closed, Open,
 // etc.
 type PullRequestState string
@@ -28,6 +33,11 @@ type Options struct {
 	// Name specifies which Git provider to use when that information cannot be
 	// inferred from the repository URL.
 	Name string
+	// Username is the username used to authenticate against the Git provider's
+	// API. It is only used by providers whose APIs require a username in
+	// addition to a token, such as Gerrit, which authenticates API requests
+	// using HTTP basic authentication.
+	Username string
 	// Token is the access token used to authenticate against the Git provider's
 	// API.
 	Token string
@@ -53,6 +63,63 @@ type Interface interface {
 	ListPullRequests(context.Context, *ListPullRequestOptions) ([]PullRequest, error)
 }
 
+// PushAccessChecker is an optional interface implemented by providers that are
+// able to use their APIs to determine, without pushing anything, whether the
+// credentials in use are permitted to push directly to a branch.
+type PushAccessChecker interface {
+	// CheckPushAccess returns nil if the credentials in use are permitted to
+	// push directly to the specified branch, which need not exist yet, and an
+	// error wrapping ErrPushForbidden, along with the reason, if they are not.
+	// Any other error indicates that access could not be determined.
+	CheckPushAccess(ctx context.Context, branch string) error
+}
+
+// RepoSizer is an optional interface implemented by providers that are able to
+// use their APIs to report the approximate size of a repository without it
+// being cloned.
+type RepoSizer interface {
+	// RepoSize returns the approximate size of the repository in bytes.
+	RepoSize(ctx context.Context) (int64, error)
+}
+
+// CommitStatusSetter is an optional interface implemented by providers that are
+// able to use their APIs to report the status of a commit, e.g. for display
+// alongside the commit in the provider's UI.
+type CommitStatusSetter interface {
+	// SetCommitStatus creates or updates the status of the commit with the
+	// specified ID. Statuses with the same Context replace one another.
+	SetCommitStatus(ctx context.Context, commitID string, status *CommitStatus) error
+}
+
+// CommitStatusState represents the state of a commit status.
+type CommitStatusState string
+
+const (
+	// CommitStatusStatePending represents an operation that is in progress.
+	CommitStatusStatePending CommitStatusState = "Pending"
+	// CommitStatusStateSuccess represents an operation that succeeded.
+	CommitStatusStateSuccess CommitStatusState = "Success"
+	// CommitStatusStateFailure represents an operation that failed.
+	CommitStatusStateFailure CommitStatusState = "Failure"
+	// CommitStatusStateError represents an operation that could not be
+	// completed due to an error.
+	CommitStatusStateError CommitStatusState = "Error"
+)
+
+// CommitStatus is an abstracted representation of a Git hosting provider's
+// commit status (or equivalent; e.g. a GitLab pipeline status).
+type CommitStatus struct {
+	// Context distinguishes the status from the statuses reported by others
+	// for the same commit.
+	Context string
+	// State is the state of the status.
+	State CommitStatusState
+	// Description is a short, human-readable description of the status.
+	Description string
+	// TargetURL is a URL, if any, at which more details are available.
+	TargetURL string
+}
+
 // CreatePullRequestOpts encapsulates the options used when creating a pull
 // request.
 type CreatePullRequestOpts struct {
@@ -105,6 +172,8 @@ type PullRequest struct {
 	Object any `json:"-"`
 	// HeadSHA is the SHA of the commit at the head of the source branch.
 	HeadSHA string `json:"headSHA"`
+	// HeadBranch is the name of the source branch.
+	HeadBranch string `json:"headBranch"`
 	// CreatedAt is the time the pull request was created.
 	CreatedAt *time.Time `json:"createdAt"`
 }
@@ -126,6 +195,10 @@ type Fake struct {
 		context.Context,
 		*ListPullRequestOptions,
 	) ([]PullRequest, error)
+	// CheckPushAccessFn defines the functionality of the CheckPushAccess method.
+	CheckPushAccessFn func(context.Context, string) error
+	// SetCommitStatusFn defines the functionality of the SetCommitStatus method.
+	SetCommitStatusFn func(context.Context, string, *CommitStatus) error
 }
 
 // CreatePullRequest implements gitprovider.Interface.
@@ -151,3 +224,17 @@ func (f *Fake) ListPullRequests(
 ) ([]PullRequest, error) {
 	return f.ListPullRequestsFn(ctx, opts)
 }
+
+// CheckPushAccess implements gitprovider.PushAccessChecker.
+func (f *Fake) CheckPushAccess(ctx context.Context, branch string) error {
+	return f.CheckPushAccessFn(ctx, branch)
+}
+
+// SetCommitStatus implements gitprovider.CommitStatusSetter.
+func (f *Fake) SetCommitStatus(
+	ctx context.Context,
+	commitID string,
+	status *CommitStatus,
+) error {
+	return f.SetCommitStatusFn(ctx, commitID, status)
+}
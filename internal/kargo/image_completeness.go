@@ -0,0 +1,73 @@
+package kargo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// ImageCoverage describes how the images carried by a piece of Freight
+// compare with the images that its Warehouse subscribes to.
+type ImageCoverage struct {
+	// Missing are the sorted repository URLs of the images that the Warehouse
+	// subscribes to, but that the Freight does not carry.
+	Missing []string
+	// Undeclared are the sorted repository URLs of the images that the Freight
+	// carries, but that the Warehouse does not subscribe to.
+	Undeclared []string
+}
+
+// NewImageCoverage compares the provided images, carried by a piece of
+// Freight, with the images that the provided Warehouse, which the Freight
+// originates from, subscribes to.
+func NewImageCoverage(warehouse *kargoapi.Warehouse, images []kargoapi.Image) ImageCoverage {
+	var subscribed []string
+	for _, sub := range warehouse.Spec.Subscriptions {
+		if sub.Image != nil {
+			subscribed = append(subscribed, sub.Image.RepoURL)
+		}
+	}
+	carried := make([]string, len(images))
+	for i, image := range images {
+		carried[i] = image.RepoURL
+	}
+	var coverage ImageCoverage
+	for _, repoURL := range subscribed {
+		if !slices.Contains(carried, repoURL) {
+			coverage.Missing = append(coverage.Missing, repoURL)
+		}
+	}
+	for _, repoURL := range carried {
+		if !slices.Contains(subscribed, repoURL) {
+			coverage.Undeclared = append(coverage.Undeclared, repoURL)
+		}
+	}
+	slices.Sort(coverage.Missing)
+	slices.Sort(coverage.Undeclared)
+	return coverage
+}
+
+// Complete returns true if the Freight carries exactly the images that its
+// Warehouse subscribes to.
+func (c ImageCoverage) Complete() bool {
+	return len(c.Missing) == 0 && len(c.Undeclared) == 0
+}
+
+// String returns a description of the differences between the images carried
+// by the Freight and the images that its Warehouse subscribes to.
+func (c ImageCoverage) String() string {
+	var parts []string
+	if len(c.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf(
+			"does not carry subscribed image(s) %s", strings.Join(c.Missing, ", "),
+		))
+	}
+	if len(c.Undeclared) > 0 {
+		parts = append(parts, fmt.Sprintf(
+			"carries image(s) %s that are not subscribed to", strings.Join(c.Undeclared, ", "),
+		))
+	}
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,81 @@
+package kargo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestNewImageCoverage(t *testing.T) {
+	warehouse := &kargoapi.Warehouse{
+		Spec: kargoapi.WarehouseSpec{
+			Subscriptions: []kargoapi.RepoSubscription{
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/frontend"}},
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/backend"}},
+				{Git: &kargoapi.GitSubscription{RepoURL: "https://github.com/example/repo"}},
+				{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/worker"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		images     []kargoapi.Image
+		assertions func(*testing.T, ImageCoverage)
+	}{
+		{
+			name: "complete",
+			images: []kargoapi.Image{
+				{RepoURL: "example.com/worker", Tag: "v1.0.0"},
+				{RepoURL: "example.com/frontend", Tag: "v1.0.0"},
+				{RepoURL: "example.com/backend", Tag: "v1.0.0"},
+			},
+			assertions: func(t *testing.T, coverage ImageCoverage) {
+				require.True(t, coverage.Complete())
+				require.Empty(t, coverage.String())
+			},
+		},
+		{
+			name: "missing images",
+			images: []kargoapi.Image{
+				{RepoURL: "example.com/frontend", Tag: "v1.0.0"},
+			},
+			assertions: func(t *testing.T, coverage ImageCoverage) {
+				require.False(t, coverage.Complete())
+				require.Equal(t, []string{"example.com/backend", "example.com/worker"}, coverage.Missing)
+				require.Empty(t, coverage.Undeclared)
+				require.Equal(
+					t,
+					"does not carry subscribed image(s) example.com/backend, example.com/worker",
+					coverage.String(),
+				)
+			},
+		},
+		{
+			name: "missing and undeclared images",
+			images: []kargoapi.Image{
+				{RepoURL: "example.com/frontend", Tag: "v1.0.0"},
+				{RepoURL: "example.com/backend", Tag: "v1.0.0"},
+				{RepoURL: "example.com/sidecar", Tag: "v1.0.0"},
+			},
+			assertions: func(t *testing.T, coverage ImageCoverage) {
+				require.False(t, coverage.Complete())
+				require.Equal(t, []string{"example.com/worker"}, coverage.Missing)
+				require.Equal(t, []string{"example.com/sidecar"}, coverage.Undeclared)
+				require.Equal(
+					t,
+					"does not carry subscribed image(s) example.com/worker and carries "+
+						"image(s) example.com/sidecar that are not subscribed to",
+					coverage.String(),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(t, NewImageCoverage(warehouse, tt.images))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package kargo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+// imageMappingWildcard is the suffix of the repository URLs of ImageMappings
+// that apply to all repositories beneath a path.
+const imageMappingWildcard = "/*"
+
+// ValidateImageMapping returns an error if the provided ImageMapping is
+// malformed.
+func ValidateImageMapping(mapping kargoapi.ImageMapping) error {
+	if mapping.RepoURL == "" {
+		return errors.New("repoURL must not be empty")
+	}
+	for _, repoURL := range []string{mapping.RepoURL, mapping.NewRepoURL} {
+		prefix := strings.TrimSuffix(repoURL, imageMappingWildcard)
+		if strings.ContainsAny(prefix, "*@ ") {
+			return fmt.Errorf(
+				"repository URL %q must not contain a digest, whitespace, or any "+
+					"wildcard other than a trailing %q",
+				repoURL, imageMappingWildcard,
+			)
+		}
+	}
+	if mapping.NewRepoURL != "" &&
+		isWildcardRepoURL(mapping.RepoURL) != isWildcardRepoURL(mapping.NewRepoURL) {
+		return fmt.Errorf(
+			"newRepoURL must end with %q if and only if repoURL does",
+			imageMappingWildcard,
+		)
+	}
+	for _, affix := range []string{mapping.TagPrefix, mapping.TagSuffix} {
+		if strings.ContainsAny(affix, ":@/ ") {
+			return fmt.Errorf("tag prefix or suffix %q contains invalid characters", affix)
+		}
+	}
+	return nil
+}
+
+// FindConflictingImageMappings returns the indices of the first two of the
+// provided ImageMappings that could map references to different images to the
+// same image, along with true, or false if there are no such mappings. Two
+// mappings conflict if the repositories they map to overlap and their tag
+// transformations could produce the same tag.
+func FindConflictingImageMappings(mappings []kargoapi.ImageMapping) (int, int, bool) {
+	for i := range mappings {
+		for j := i + 1; j < len(mappings); j++ {
+			if !repoURLPatternsOverlap(
+				imageMappingTarget(mappings[i]),
+				imageMappingTarget(mappings[j]),
+			) {
+				continue
+			}
+			if !tagAffixesMayCollide(mappings[i], mappings[j]) {
+				continue
+			}
+			return i, j, true
+		}
+	}
+	return 0, 0, false
+}
+
+// MapImage returns the provided image as rewritten by the first of the
+// provided ImageMappings that applies to it, along with true, or the image
+// unchanged, along with false, if no mapping applies to it. Mapping is a pure
+// function of its inputs. The digest of an image is never changed, as the
+// same image is expected to be available from the new repository.
+func MapImage(mappings []kargoapi.ImageMapping, image kargoapi.Image) (kargoapi.Image, bool) {
+	for _, mapping := range mappings {
+		repoURL, ok := mapImageRepoURL(mapping, image.RepoURL)
+		if !ok {
+			continue
+		}
+		mapped := image
+		mapped.RepoURL = repoURL
+		if image.Tag != "" {
+			mapped.Tag = mapping.TagPrefix + image.Tag + mapping.TagSuffix
+		}
+		return mapped, true
+	}
+	return image, false
+}
+
+// MapImages returns the provided images as rewritten by the provided
+// ImageMappings. An error is returned if different images would be mapped to
+// the same image, including if an image that is mapped would become identical
+// to one that is not.
+func MapImages(
+	mappings []kargoapi.ImageMapping,
+	images []kargoapi.Image,
+) ([]kargoapi.Image, error) {
+	mapped := make([]kargoapi.Image, len(images))
+	sources := make(map[string]kargoapi.Image, len(images))
+	for i, image := range images {
+		mapped[i], _ = MapImage(mappings, image)
+		key := imageKey(mapped[i])
+		if source, ok := sources[key]; ok && imageKey(source) != imageKey(image) {
+			return nil, fmt.Errorf(
+				"images %s and %s would both be mapped to %s",
+				imageKey(source), imageKey(image), key,
+			)
+		}
+		sources[key] = image
+	}
+	return mapped, nil
+}
+
+// mapImageRepoURL returns the repository URL that the provided mapping maps
+// the provided repository URL to, along with true, or false if the mapping
+// does not apply to the repository.
+func mapImageRepoURL(mapping kargoapi.ImageMapping, repoURL string) (string, bool) {
+	if !isWildcardRepoURL(mapping.RepoURL) {
+		if repoURL != mapping.RepoURL {
+			return "", false
+		}
+		if mapping.NewRepoURL == "" {
+			return repoURL, true
+		}
+		return mapping.NewRepoURL, true
+	}
+	prefix := strings.TrimSuffix(mapping.RepoURL, "*")
+	rest, ok := strings.CutPrefix(repoURL, prefix)
+	if !ok || rest == "" {
+		return "", false
+	}
+	if mapping.NewRepoURL == "" {
+		return repoURL, true
+	}
+	return strings.TrimSuffix(mapping.NewRepoURL, "*") + rest, true
+}
+
+// imageMappingTarget returns the repository URL, possibly ending with a
+// wildcard, that the provided mapping maps repositories to.
+func imageMappingTarget(mapping kargoapi.ImageMapping) string {
+	if mapping.NewRepoURL != "" {
+		return mapping.NewRepoURL
+	}
+	return mapping.RepoURL
+}
+
+// repoURLPatternsOverlap returns true if there is any repository URL that
+// both of the provided repository URLs, each possibly ending with a wildcard,
+// match.
+func repoURLPatternsOverlap(a, b string) bool {
+	aPrefix, aWildcard := strings.CutSuffix(a, "*")
+	bPrefix, bWildcard := strings.CutSuffix(b, "*")
+	switch {
+	case aWildcard && bWildcard:
+		return strings.HasPrefix(aPrefix, bPrefix) || strings.HasPrefix(bPrefix, aPrefix)
+	case aWildcard:
+		return strings.HasPrefix(b, aPrefix) && b != aPrefix
+	case bWildcard:
+		return strings.HasPrefix(a, bPrefix) && a != bPrefix
+	default:
+		return a == b
+	}
+}
+
+// tagAffixesMayCollide returns true if the tag transformations of the
+// provided mappings could produce the same tag for different tags or, if
+// their transformations are identical, for the same tag.
+func tagAffixesMayCollide(a, b kargoapi.ImageMapping) bool {
+	// For some tags x and y, a.TagPrefix+x+a.TagSuffix can only equal
+	// b.TagPrefix+y+b.TagSuffix if one prefix is a prefix of the other and one
+	// suffix is a suffix of the other.
+	prefixesCompatible := strings.HasPrefix(a.TagPrefix, b.TagPrefix) ||
+		strings.HasPrefix(b.TagPrefix, a.TagPrefix)
+	suffixesCompatible := strings.HasSuffix(a.TagSuffix, b.TagSuffix) ||
+		strings.HasSuffix(b.TagSuffix, a.TagSuffix)
+	return prefixesCompatible && suffixesCompatible
+}
+
+func isWildcardRepoURL(repoURL string) bool {
+	return strings.HasSuffix(repoURL, imageMappingWildcard)
+}
+
+func imageKey(image kargoapi.Image) string {
+	key := image.RepoURL
+	if image.Tag != "" {
+		key += ":" + image.Tag
+	}
+	if image.Digest != "" {
+		key += "@" + image.Digest
+	}
+	return key
+}
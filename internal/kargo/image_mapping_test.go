@@ -0,0 +1,269 @@
+package kargo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestValidateImageMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping kargoapi.ImageMapping
+		err     string
+	}{
+		{
+			name: "missing repoURL",
+			err:  "repoURL must not be empty",
+		},
+		{
+			name:    "wildcard in the middle of repoURL",
+			mapping: kargoapi.ImageMapping{RepoURL: "registry.example.com/*/app"},
+			err:     "must not contain",
+		},
+		{
+			name: "digest in newRepoURL",
+			mapping: kargoapi.ImageMapping{
+				RepoURL:    "registry.dev.example.com/app",
+				NewRepoURL: "registry.prod.example.com/app@sha256:abc",
+			},
+			err: "must not contain",
+		},
+		{
+			name: "wildcard mapped to single repository",
+			mapping: kargoapi.ImageMapping{
+				RepoURL:    "registry.dev.example.com/*",
+				NewRepoURL: "registry.prod.example.com/app",
+			},
+			err: "if and only if",
+		},
+		{
+			name: "invalid tag suffix",
+			mapping: kargoapi.ImageMapping{
+				RepoURL:   "registry.dev.example.com/app",
+				TagSuffix: ":prod",
+			},
+			err: "invalid characters",
+		},
+		{
+			name: "valid wildcard mapping",
+			mapping: kargoapi.ImageMapping{
+				RepoURL:    "registry.dev.example.com/*",
+				NewRepoURL: "registry.prod.example.com/*",
+				TagPrefix:  "release-",
+				TagSuffix:  "-prod",
+			},
+		},
+		{
+			name: "valid tag-only mapping",
+			mapping: kargoapi.ImageMapping{
+				RepoURL:   "localhost:5000/app",
+				TagSuffix: "-prod",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImageMapping(tt.mapping)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestFindConflictingImageMappings(t *testing.T) {
+	tests := []struct {
+		name       string
+		mappings   []kargoapi.ImageMapping
+		conflicted bool
+		i, j       int
+	}{
+		{
+			name: "no mappings",
+		},
+		{
+			name: "distinct targets",
+			mappings: []kargoapi.ImageMapping{
+				{RepoURL: "dev.example.com/*", NewRepoURL: "prod.example.com/*"},
+				{RepoURL: "dev.example.com/other", NewRepoURL: "mirror.example.com/other"},
+			},
+		},
+		{
+			name: "same target repository",
+			mappings: []kargoapi.ImageMapping{
+				{RepoURL: "dev.example.com/app", NewRepoURL: "prod.example.com/app"},
+				{RepoURL: "staging.example.com/app", NewRepoURL: "prod.example.com/app"},
+			},
+			conflicted: true,
+			i:          0,
+			j:          1,
+		},
+		{
+			name: "overlapping wildcard targets",
+			mappings: []kargoapi.ImageMapping{
+				{RepoURL: "other.example.com/app", NewRepoURL: "mirror.example.com/app"},
+				{RepoURL: "dev.example.com/*", NewRepoURL: "prod.example.com/*"},
+				{RepoURL: "staging.example.com/team/*", NewRepoURL: "prod.example.com/team/*"},
+			},
+			conflicted: true,
+			i:          1,
+			j:          2,
+		},
+		{
+			name: "same target repository with distinct tag suffixes",
+			mappings: []kargoapi.ImageMapping{
+				{RepoURL: "dev.example.com/app", NewRepoURL: "prod.example.com/app", TagSuffix: "-dev"},
+				{RepoURL: "staging.example.com/app", NewRepoURL: "prod.example.com/app", TagSuffix: "-stg"},
+			},
+		},
+		{
+			name: "same target repository with tag suffixes that may collide",
+			mappings: []kargoapi.ImageMapping{
+				{RepoURL: "dev.example.com/app", NewRepoURL: "prod.example.com/app", TagSuffix: "-prod"},
+				{RepoURL: "staging.example.com/app", NewRepoURL: "prod.example.com/app", TagSuffix: "prod"},
+			},
+			conflicted: true,
+			i:          0,
+			j:          1,
+		},
+		{
+			name: "wildcard does not overlap its own path",
+			mappings: []kargoapi.ImageMapping{
+				{RepoURL: "dev.example.com/*", NewRepoURL: "prod.example.com/*"},
+				{RepoURL: "staging.example.com/app", NewRepoURL: "prod.example.com/"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, j, conflicted := FindConflictingImageMappings(tt.mappings)
+			require.Equal(t, tt.conflicted, conflicted)
+			if tt.conflicted {
+				require.Equal(t, tt.i, i)
+				require.Equal(t, tt.j, j)
+			}
+		})
+	}
+}
+
+func TestMapImage(t *testing.T) {
+	mappings := []kargoapi.ImageMapping{
+		{
+			RepoURL:    "registry.dev.example.com/team/app",
+			NewRepoURL: "registry.prod.example.com/app",
+		},
+		{
+			RepoURL:    "registry.dev.example.com/*",
+			NewRepoURL: "registry.prod.example.com/*",
+			TagSuffix:  "-prod",
+		},
+		{
+			RepoURL:   "ghcr.io/example/app",
+			TagPrefix: "release-",
+		},
+	}
+	tests := []struct {
+		name     string
+		image    kargoapi.Image
+		expected kargoapi.Image
+		mapped   bool
+	}{
+		{
+			name:     "no mapping applies",
+			image:    kargoapi.Image{RepoURL: "docker.io/library/nginx", Tag: "1.27"},
+			expected: kargoapi.Image{RepoURL: "docker.io/library/nginx", Tag: "1.27"},
+		},
+		{
+			name:     "exact mapping takes precedence",
+			image:    kargoapi.Image{RepoURL: "registry.dev.example.com/team/app", Tag: "v1.2.3"},
+			expected: kargoapi.Image{RepoURL: "registry.prod.example.com/app", Tag: "v1.2.3"},
+			mapped:   true,
+		},
+		{
+			name: "wildcard mapping",
+			image: kargoapi.Image{
+				RepoURL: "registry.dev.example.com/team/other",
+				Tag:     "v1.2.3",
+				Digest:  "sha256:abc",
+			},
+			expected: kargoapi.Image{
+				RepoURL: "registry.prod.example.com/team/other",
+				Tag:     "v1.2.3-prod",
+				Digest:  "sha256:abc",
+			},
+			mapped: true,
+		},
+		{
+			name:     "wildcard does not match its own path",
+			image:    kargoapi.Image{RepoURL: "registry.dev.example.com/", Tag: "v1.2.3"},
+			expected: kargoapi.Image{RepoURL: "registry.dev.example.com/", Tag: "v1.2.3"},
+		},
+		{
+			name:     "tag-only mapping",
+			image:    kargoapi.Image{RepoURL: "ghcr.io/example/app", Tag: "v1.2.3"},
+			expected: kargoapi.Image{RepoURL: "ghcr.io/example/app", Tag: "release-v1.2.3"},
+			mapped:   true,
+		},
+		{
+			name:     "digest only",
+			image:    kargoapi.Image{RepoURL: "ghcr.io/example/app", Digest: "sha256:abc"},
+			expected: kargoapi.Image{RepoURL: "ghcr.io/example/app", Digest: "sha256:abc"},
+			mapped:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, mapped := MapImage(mappings, tt.image)
+			require.Equal(t, tt.expected, image)
+			require.Equal(t, tt.mapped, mapped)
+			// Mapping is deterministic
+			again, _ := MapImage(mappings, tt.image)
+			require.Equal(t, image, again)
+		})
+	}
+}
+
+func TestMapImages(t *testing.T) {
+	mappings := []kargoapi.ImageMapping{{
+		RepoURL:    "registry.dev.example.com/*",
+		NewRepoURL: "registry.prod.example.com/*",
+	}}
+
+	mapped, err := MapImages(mappings, []kargoapi.Image{
+		{RepoURL: "registry.dev.example.com/app", Tag: "v1.2.3"},
+		{RepoURL: "docker.io/library/nginx", Tag: "1.27"},
+	})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]kargoapi.Image{
+			{RepoURL: "registry.prod.example.com/app", Tag: "v1.2.3"},
+			{RepoURL: "docker.io/library/nginx", Tag: "1.27"},
+		},
+		mapped,
+	)
+
+	// A mapped image must not become identical to an image that is not mapped
+	_, err = MapImages(mappings, []kargoapi.Image{
+		{RepoURL: "registry.dev.example.com/app", Tag: "v1.2.3"},
+		{RepoURL: "registry.prod.example.com/app", Tag: "v1.2.3"},
+	})
+	require.ErrorContains(
+		t,
+		err,
+		"images registry.dev.example.com/app:v1.2.3 and registry.prod.example.com/app:v1.2.3 "+
+			"would both be mapped to registry.prod.example.com/app:v1.2.3",
+	)
+
+	// The same image listed twice is not a conflict
+	_, err = MapImages(mappings, []kargoapi.Image{
+		{RepoURL: "registry.dev.example.com/app", Tag: "v1.2.3"},
+		{RepoURL: "registry.dev.example.com/app", Tag: "v1.2.3"},
+	})
+	require.NoError(t, err)
+}
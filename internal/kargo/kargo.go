@@ -75,7 +75,8 @@ func NewPromoPhaseChangedPredicate(logger *logging.Logger) PromoPhaseChanged[*ka
 // object when the phase of a Promotion changes. A concrete example is to trigger
 // the reconciliation of a Stage when the phase of a Promotion for that Stage
 // changes, so that the Stage can update the last Promotion reference in its
-// status.
+// status. It also returns true if a Promotion's approval has been recorded, so
+// that a Stage can pick a Promotion that was waiting to be approved.
 type PromoPhaseChanged[T any] struct {
 	predicate.Funcs
 	logger *logging.Logger
@@ -113,7 +114,8 @@ func (p PromoPhaseChanged[T]) Update(e event.TypedUpdateEvent[T]) bool {
 		)
 		return false
 	}
-	return newPromo.Status.Phase != oldPromo.Status.Phase
+	return newPromo.Status.Phase != oldPromo.Status.Phase ||
+		(oldPromo.Status.Approval == nil && newPromo.Status.Approval != nil)
 }
 
 // RefreshRequested is a predicate that returns true if the refresh annotation
@@ -207,3 +209,82 @@ func (p PromotionAbortRequested) Update(e event.UpdateEvent) bool {
 	}
 	return false
 }
+
+// PromotionApproved is a predicate that returns true if the approve annotation
+// has been set on a resource.
+type PromotionApproved struct {
+	predicate.Funcs
+}
+
+// Update returns true if the approve annotation has been set on the new
+// object, but not on the old object.
+func (p PromotionApproved) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	_, newOk := kargoapi.ApprovePromotionAnnotationValue(e.ObjectNew.GetAnnotations())
+	_, oldOk := kargoapi.ApprovePromotionAnnotationValue(e.ObjectOld.GetAnnotations())
+	return newOk && !oldOk
+}
+
+// DriftAcknowledged is a predicate that returns true if the acknowledge-drift
+// annotation has been set on a resource, or its value has changed compared to
+// the previous state.
+type DriftAcknowledged struct {
+	predicate.Funcs
+}
+
+// Update returns true if the acknowledge-drift annotation has been set on the
+// new object, or if its value has changed compared to the old object.
+func (p DriftAcknowledged) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	if newVal, newOk := kargoapi.AcknowledgeDriftAnnotationValue(e.ObjectNew.GetAnnotations()); newOk {
+		if oldVal, oldOk := kargoapi.AcknowledgeDriftAnnotationValue(e.ObjectOld.GetAnnotations()); oldOk {
+			return oldVal != newVal
+		}
+		return true
+	}
+	return false
+}
+
+// PromotionRequestsReplayRequested is a predicate that returns true if the
+// replay-promotion-requests annotation has been set on a resource, or its value
+// has changed compared to the previous state.
+type PromotionRequestsReplayRequested struct {
+	predicate.Funcs
+}
+
+// Update returns true if the replay-promotion-requests annotation has been set
+// on the new object, or if its value has changed compared to the old object.
+func (p PromotionRequestsReplayRequested) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	if newVal, newOk := kargoapi.ReplayPromotionRequestsAnnotationValue(e.ObjectNew.GetAnnotations()); newOk {
+		if oldVal, oldOk := kargoapi.ReplayPromotionRequestsAnnotationValue(e.ObjectOld.GetAnnotations()); oldOk {
+			return oldVal != newVal
+		}
+		return true
+	}
+	return false
+}
+
+// PromotionsPauseChanged is a predicate that returns true if a resource has
+// been paused or unpaused by means of the pause-promotions annotation.
+type PromotionsPauseChanged struct {
+	predicate.Funcs
+}
+
+// Update returns true if the value of the pause-promotions annotation of the
+// new object differs from that of the old object.
+func (p PromotionsPauseChanged) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	return kargoapi.PromotionsPausedAnnotationValue(e.ObjectOld.GetAnnotations()) !=
+		kargoapi.PromotionsPausedAnnotationValue(e.ObjectNew.GetAnnotations())
+}
@@ -65,6 +65,21 @@ func TestPromoPhaseChanged_Update(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "approval recorded",
+			oldObject: &kargoapi.Promotion{
+				Status: kargoapi.PromotionStatus{
+					Phase: kargoapi.PromotionPhasePending,
+				},
+			},
+			newObject: &kargoapi.Promotion{
+				Status: kargoapi.PromotionStatus{
+					Phase:    kargoapi.PromotionPhasePending,
+					Approval: &kargoapi.PromotionApproval{Approver: "fake-approver"},
+				},
+			},
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -765,3 +780,306 @@ func TestPromotionAbortRequested_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestPromotionApproved_Update(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldObject client.Object
+		newObject client.Object
+		want      bool
+	}{
+		{
+			name:      "no old or new object",
+			oldObject: nil,
+			newObject: nil,
+			want:      false,
+		},
+		{
+			name:      "no approve annotation",
+			oldObject: &kargoapi.Promotion{},
+			newObject: &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"other": "annotation",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name:      "approve annotation set on new object",
+			oldObject: &kargoapi.Promotion{},
+			newObject: &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyApprove: `{"actor":"kubernetes:alice"}`,
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "approve annotation already set on old object",
+			oldObject: &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyApprove: `{"actor":"kubernetes:alice"}`,
+					},
+				},
+			},
+			newObject: &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyApprove: `{"actor":"kubernetes:alice"}`,
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PromotionApproved{}
+			require.Equal(t, tt.want, p.Update(event.UpdateEvent{
+				ObjectOld: tt.oldObject,
+				ObjectNew: tt.newObject,
+			}))
+		})
+	}
+}
+
+func TestDriftAcknowledged_Update(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldObject client.Object
+		newObject client.Object
+		want      bool
+	}{
+		{
+			name:      "no old or new object",
+			oldObject: nil,
+			newObject: nil,
+			want:      false,
+		},
+		{
+			name: "no acknowledge-drift annotation",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"other": "annotation",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "acknowledge-drift annotation set on new object",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "abc123",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "acknowledge-drift annotation removed from new object",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "abc123",
+					},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "acknowledge-drift annotation value changed",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "abc123",
+					},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "def456",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "acknowledge-drift annotation value unchanged",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "abc123",
+					},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyAcknowledgeDrift: "abc123",
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DriftAcknowledged{}
+			require.Equal(t, tt.want, p.Update(event.UpdateEvent{
+				ObjectOld: tt.oldObject,
+				ObjectNew: tt.newObject,
+			}))
+		})
+	}
+}
+
+func TestPromotionRequestsReplayRequested_Update(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldObject client.Object
+		newObject client.Object
+		want      bool
+	}{
+		{
+			name:      "no old or new object",
+			oldObject: nil,
+			newObject: nil,
+			want:      false,
+		},
+		{
+			name: "replay annotation set on new object",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyReplayPromotionRequests: "2025-01-01T00:00:00Z",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "replay annotation value changed",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyReplayPromotionRequests: "2025-01-01T00:00:00Z",
+					},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyReplayPromotionRequests: "2025-01-02T00:00:00Z",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "replay annotation value unchanged",
+			oldObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyReplayPromotionRequests: "2025-01-01T00:00:00Z",
+					},
+				},
+			},
+			newObject: &kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyReplayPromotionRequests: "2025-01-01T00:00:00Z",
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PromotionRequestsReplayRequested{}
+			require.Equal(t, tt.want, p.Update(event.UpdateEvent{
+				ObjectOld: tt.oldObject,
+				ObjectNew: tt.newObject,
+			}))
+		})
+	}
+}
+
+func TestPromotionsPauseChanged_Update(t *testing.T) {
+	paused := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				kargoapi.AnnotationKeyPausePromotions: kargoapi.AnnotationValueTrue,
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		oldObject client.Object
+		newObject client.Object
+		want      bool
+	}{
+		{
+			name:      "no old or new object",
+			oldObject: nil,
+			newObject: nil,
+			want:      false,
+		},
+		{
+			name:      "paused",
+			oldObject: &kargoapi.Stage{},
+			newObject: paused,
+			want:      true,
+		},
+		{
+			name:      "unpaused",
+			oldObject: paused,
+			newObject: &kargoapi.Stage{},
+			want:      true,
+		},
+		{
+			name:      "still paused",
+			oldObject: paused,
+			newObject: paused.DeepCopy(),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PromotionsPauseChanged{}
+			require.Equal(t, tt.want, p.Update(event.UpdateEvent{
+				ObjectOld: tt.oldObject,
+				ObjectNew: tt.newObject,
+			}))
+		})
+	}
+}
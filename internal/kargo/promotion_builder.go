@@ -81,6 +81,7 @@ func (b *PromotionBuilder) Build(
 			Freight: freight,
 			Vars:    stage.Spec.PromotionTemplate.Spec.Vars,
 			Steps:   stage.Spec.PromotionTemplate.Spec.Steps,
+			Lanes:   stage.Spec.PromotionTemplate.Spec.Lanes,
 		},
 	}
 	return &promotion, nil
@@ -146,6 +147,16 @@ func (b *PromotionBuilder) inflateTaskSteps(
 		// the Config of the step during the Promotion execution.
 		step.Vars = append(vars, step.Vars...)
 
+		// If failure of the task as a whole is tolerated, so is the failure of
+		// each of its steps.
+		step.ContinueOnError = step.ContinueOnError || taskStep.ContinueOnError
+
+		// If the task as a whole is assigned to a lane, so is each of its steps
+		// that is not assigned to a lane of its own.
+		if step.Lane == "" {
+			step.Lane = taskStep.Lane
+		}
+
 		// Append the inflated step to the list of steps.
 		steps = append(steps, *step)
 	}
@@ -516,6 +516,37 @@ func TestPromotionBuilder_inflateTaskSteps(t *testing.T) {
 				}, steps[1].Vars)
 			},
 		},
+		{
+			name:      "task lane is inherited by its steps",
+			project:   "test-project",
+			taskAlias: "task-1",
+			taskStep: kargoapi.PromotionStep{
+				Task: &kargoapi.PromotionTaskReference{
+					Name: "test-task",
+				},
+				Lane: "dev",
+			},
+			objects: []client.Object{
+				&kargoapi.PromotionTask{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-task",
+						Namespace: "test-project",
+					},
+					Spec: kargoapi.PromotionTaskSpec{
+						Steps: []kargoapi.PromotionStep{
+							{As: "step1", Uses: "fake-step"},
+							{As: "step2", Uses: "fake-step", Lane: "other"},
+						},
+					},
+				},
+			},
+			assertions: func(t *testing.T, steps []kargoapi.PromotionStep, err error) {
+				require.NoError(t, err)
+				require.Len(t, steps, 2)
+				assert.Equal(t, "dev", steps[0].Lane)
+				assert.Equal(t, "other", steps[1].Lane)
+			},
+		},
 		{
 			name:      "task steps with default alias",
 			project:   "test-project",
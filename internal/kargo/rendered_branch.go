@@ -0,0 +1,133 @@
+package kargo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/git"
+)
+
+// renderedBranchData is the data that a Stage's RenderedBranch template is
+// executed with.
+type renderedBranchData struct {
+	Project string
+	Stage   string
+	App     string
+	Cluster string
+	Region  string
+}
+
+// ResolveRenderedBranch returns the name of the branch that manifests rendered
+// for the provided Stage are written to, as rendered from the Stage's
+// RenderedBranch template. If the Stage does not specify a template, or if it
+// specifies overlays, for each of which the template is rendered separately,
+// an empty string is returned. An error is returned if the template cannot be
+// parsed or executed, or if it renders an invalid branch name.
+func ResolveRenderedBranch(stage *kargoapi.Stage) (string, error) {
+	rb := stage.Spec.RenderedBranch
+	if rb == nil || len(stage.Spec.Overlays) > 0 {
+		return "", nil
+	}
+	return renderBranch(stage, rb.Region)
+}
+
+// ResolveOverlays returns the overlays of the provided Stage along with the
+// names of the branches that manifests rendered from them are written to. The
+// branch of an overlay that does not specify one is rendered from the Stage's
+// RenderedBranch template, with the name of the overlay as .Region. An error
+// is returned if the path of an overlay escapes the repository, if the branch
+// of an overlay cannot be resolved, if it is not a valid branch name, or if two
+// overlays share a name or a branch.
+func ResolveOverlays(stage *kargoapi.Stage) ([]kargoapi.PromotedOverlay, error) {
+	if len(stage.Spec.Overlays) == 0 {
+		return nil, nil
+	}
+	overlays := make([]kargoapi.PromotedOverlay, len(stage.Spec.Overlays))
+	names := make(map[string]struct{}, len(overlays))
+	branches := make(map[string]string, len(overlays))
+	for i, o := range stage.Spec.Overlays {
+		if _, ok := names[o.Name]; ok {
+			return nil, fmt.Errorf("overlay name %q is not unique", o.Name)
+		}
+		names[o.Name] = struct{}{}
+		// The overlay is rendered from the working tree of the repository, which
+		// it must not escape.
+		if !filepath.IsLocal(o.Path) {
+			return nil, fmt.Errorf(
+				"path %q of overlay %q is not a relative path within the repository",
+				o.Path, o.Name,
+			)
+		}
+		branch := strings.TrimSpace(o.RenderedBranch)
+		switch {
+		case branch != "":
+			if err := git.ValidateBranchName(branch); err != nil {
+				return nil, fmt.Errorf("overlay %q specifies an invalid rendered branch: %w", o.Name, err)
+			}
+		case stage.Spec.RenderedBranch == nil:
+			return nil, fmt.Errorf(
+				"overlay %q does not specify a rendered branch and the Stage does not "+
+					"specify a rendered branch template",
+				o.Name,
+			)
+		default:
+			var err error
+			if branch, err = renderBranch(stage, o.Name); err != nil {
+				return nil, fmt.Errorf("error resolving rendered branch of overlay %q: %w", o.Name, err)
+			}
+		}
+		if other, ok := branches[branch]; ok {
+			return nil, fmt.Errorf(
+				"overlays %q and %q are both rendered into branch %q", other, o.Name, branch,
+			)
+		}
+		branches[branch] = o.Name
+		overlays[i] = kargoapi.PromotedOverlay{
+			Name:           o.Name,
+			Path:           o.Path,
+			RenderedBranch: branch,
+			GitIdentity:    o.GitIdentity.DeepCopy(),
+		}
+	}
+	return overlays, nil
+}
+
+// renderBranch renders the name of a branch from the provided Stage's
+// RenderedBranch template, which must not be nil, with the provided region
+// available to it as .Region.
+func renderBranch(stage *kargoapi.Stage, region string) (string, error) {
+	rb := stage.Spec.RenderedBranch
+	tmpl, err := template.New("renderedBranch").
+		Option("missingkey=error").
+		Parse(rb.Template)
+	if err != nil {
+		return "", fmt.Errorf("error parsing rendered branch template: %w", err)
+	}
+	data := renderedBranchData{
+		Project: stage.Namespace,
+		Stage:   stage.Name,
+		App:     rb.App,
+		Cluster: rb.Cluster,
+		Region:  region,
+	}
+	if apps := stage.Spec.ArgoCDApps; len(apps) > 0 {
+		if data.App == "" {
+			data.App = apps[0].Name
+		}
+		if data.Cluster == "" {
+			data.Cluster = apps[0].Destination.Name
+		}
+	}
+	var sb strings.Builder
+	if err = tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("error executing rendered branch template: %w", err)
+	}
+	branch := strings.TrimSpace(sb.String())
+	if err = git.ValidateBranchName(branch); err != nil {
+		return "", fmt.Errorf("rendered branch template produced an invalid branch name: %w", err)
+	}
+	return branch, nil
+}
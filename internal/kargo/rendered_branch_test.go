@@ -0,0 +1,262 @@
+package kargo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func TestResolveRenderedBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       kargoapi.StageSpec
+		assertions func(*testing.T, string, error)
+	}{
+		{
+			name: "no template",
+			assertions: func(t *testing.T, branch string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, branch)
+			},
+		},
+		{
+			name: "explicit values",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .Project }}/{{ .Stage }}/{{ .App }}/{{ .Cluster }}/{{ .Region }}",
+					App:      "guestbook",
+					Cluster:  "prod-1",
+					Region:   "us-east-1",
+				},
+				ArgoCDApps: []kargoapi.ManagedArgoCDApp{{
+					Name: "ignored",
+					Destination: kargoapi.ManagedArgoCDAppDestination{
+						Name: "ignored",
+					},
+				}},
+			},
+			assertions: func(t *testing.T, branch string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "rendered/fake-project/prod/guestbook/prod-1/us-east-1", branch)
+			},
+		},
+		{
+			name: "values defaulted from managed Argo CD Application",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .App }}/{{ .Cluster }}",
+				},
+				ArgoCDApps: []kargoapi.ManagedArgoCDApp{{
+					Name: "guestbook",
+					Destination: kargoapi.ManagedArgoCDAppDestination{
+						Name: "prod-1",
+					},
+				}},
+			},
+			assertions: func(t *testing.T, branch string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "rendered/guestbook/prod-1", branch)
+			},
+		},
+		{
+			name: "invalid template",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .Stage",
+				},
+			},
+			assertions: func(t *testing.T, _ string, err error) {
+				require.ErrorContains(t, err, "error parsing rendered branch template")
+			},
+		},
+		{
+			name: "unknown field",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .Environment }}",
+				},
+			},
+			assertions: func(t *testing.T, _ string, err error) {
+				require.ErrorContains(t, err, "error executing rendered branch template")
+			},
+		},
+		{
+			name: "resolved per overlay instead",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .Region }}/{{ .Stage }}",
+				},
+				Overlays: []kargoapi.StageOverlay{{Name: "eu", Path: "overlays/eu"}},
+			},
+			assertions: func(t *testing.T, branch string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, branch)
+			},
+		},
+		{
+			name: "invalid branch name",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					// Region is empty, resulting in a double slash
+					Template: "rendered/{{ .Region }}/{{ .Stage }}",
+				},
+			},
+			assertions: func(t *testing.T, _ string, err error) {
+				require.ErrorContains(t, err, "invalid branch name")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branch, err := ResolveRenderedBranch(&kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "prod",
+				},
+				Spec: tt.spec,
+			})
+			tt.assertions(t, branch, err)
+		})
+	}
+}
+
+func TestResolveOverlays(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       kargoapi.StageSpec
+		assertions func(*testing.T, []kargoapi.PromotedOverlay, error)
+	}{
+		{
+			name: "no overlays",
+			assertions: func(t *testing.T, overlays []kargoapi.PromotedOverlay, err error) {
+				require.NoError(t, err)
+				require.Empty(t, overlays)
+			},
+		},
+		{
+			name: "branches from template and explicit",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .Stage }}/{{ .Region }}",
+				},
+				Overlays: []kargoapi.StageOverlay{
+					{Name: "us-east", Path: "overlays/us-east"},
+					{Name: "eu-west", Path: "overlays/eu-west", RenderedBranch: "env/eu"},
+				},
+			},
+			assertions: func(t *testing.T, overlays []kargoapi.PromotedOverlay, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []kargoapi.PromotedOverlay{
+					{Name: "us-east", Path: "overlays/us-east", RenderedBranch: "rendered/prod/us-east"},
+					{Name: "eu-west", Path: "overlays/eu-west", RenderedBranch: "env/eu"},
+				}, overlays)
+			},
+		},
+		{
+			name: "git identity of overlay",
+			spec: kargoapi.StageSpec{
+				Overlays: []kargoapi.StageOverlay{{
+					Name:           "us-east",
+					Path:           "overlays/us-east",
+					RenderedBranch: "env/us",
+					GitIdentity: &kargoapi.GitIdentity{
+						Name:              "Kargo US",
+						CredentialsSecret: "git-us",
+					},
+				}},
+			},
+			assertions: func(t *testing.T, overlays []kargoapi.PromotedOverlay, err error) {
+				require.NoError(t, err)
+				require.Len(t, overlays, 1)
+				require.Equal(t, &kargoapi.GitIdentity{
+					Name:              "Kargo US",
+					CredentialsSecret: "git-us",
+				}, overlays[0].GitIdentity)
+			},
+		},
+		{
+			name: "no branch and no template",
+			spec: kargoapi.StageSpec{
+				Overlays: []kargoapi.StageOverlay{{Name: "us-east", Path: "overlays/us-east"}},
+			},
+			assertions: func(t *testing.T, _ []kargoapi.PromotedOverlay, err error) {
+				require.ErrorContains(t, err, "does not specify a rendered branch template")
+			},
+		},
+		{
+			name: "invalid explicit branch",
+			spec: kargoapi.StageSpec{
+				Overlays: []kargoapi.StageOverlay{
+					{Name: "us-east", Path: "overlays/us-east", RenderedBranch: "env//us"},
+				},
+			},
+			assertions: func(t *testing.T, _ []kargoapi.PromotedOverlay, err error) {
+				require.ErrorContains(t, err, "invalid rendered branch")
+			},
+		},
+		{
+			name: "path escapes repository",
+			spec: kargoapi.StageSpec{
+				Overlays: []kargoapi.StageOverlay{
+					{Name: "us-east", Path: "overlays/../../us-east", RenderedBranch: "env/us"},
+				},
+			},
+			assertions: func(t *testing.T, _ []kargoapi.PromotedOverlay, err error) {
+				require.ErrorContains(t, err, "not a relative path within the repository")
+			},
+		},
+		{
+			name: "absolute path",
+			spec: kargoapi.StageSpec{
+				Overlays: []kargoapi.StageOverlay{
+					{Name: "us-east", Path: "/overlays/us-east", RenderedBranch: "env/us"},
+				},
+			},
+			assertions: func(t *testing.T, _ []kargoapi.PromotedOverlay, err error) {
+				require.ErrorContains(t, err, "not a relative path within the repository")
+			},
+		},
+		{
+			name: "duplicate name",
+			spec: kargoapi.StageSpec{
+				Overlays: []kargoapi.StageOverlay{
+					{Name: "us-east", Path: "overlays/a", RenderedBranch: "env/a"},
+					{Name: "us-east", Path: "overlays/b", RenderedBranch: "env/b"},
+				},
+			},
+			assertions: func(t *testing.T, _ []kargoapi.PromotedOverlay, err error) {
+				require.ErrorContains(t, err, "is not unique")
+			},
+		},
+		{
+			name: "shared branch",
+			spec: kargoapi.StageSpec{
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "rendered/{{ .Stage }}",
+				},
+				Overlays: []kargoapi.StageOverlay{
+					{Name: "us-east", Path: "overlays/us-east"},
+					{Name: "eu-west", Path: "overlays/eu-west"},
+				},
+			},
+			assertions: func(t *testing.T, _ []kargoapi.PromotedOverlay, err error) {
+				require.ErrorContains(t, err, `are both rendered into branch "rendered/prod"`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlays, err := ResolveOverlays(&kargoapi.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "fake-project",
+					Name:      "prod",
+				},
+				Spec: tt.spec,
+			})
+			tt.assertions(t, overlays, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package kubeclient
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceAccountUsername returns the username by which the Kubernetes API
+// server identifies the ServiceAccount with the provided namespace and name.
+func ServiceAccountUsername(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// ImpersonatingClientFactory builds Kubernetes clients that impersonate
+// ServiceAccounts. Clients are cached per ServiceAccount, so that they (and
+// their underlying HTTP transports) are built only once and reused by all
+// callers.
+type ImpersonatingClientFactory struct {
+	cfg  *rest.Config
+	opts client.Options
+
+	newClientFn func(*rest.Config, client.Options) (client.Client, error)
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]client.Client
+}
+
+// NewImpersonatingClientFactory returns an ImpersonatingClientFactory that
+// builds clients from a copy of the provided *rest.Config using the provided
+// client.Options. The clients it builds are not backed by a cache, as it is
+// the very purpose of these clients that reads, too, are subject to the
+// ServiceAccount's permissions.
+func NewImpersonatingClientFactory(
+	cfg *rest.Config,
+	opts client.Options,
+) *ImpersonatingClientFactory {
+	return &ImpersonatingClientFactory{
+		cfg:         cfg,
+		opts:        opts,
+		newClientFn: client.New,
+		clients:     map[types.NamespacedName]client.Client{},
+	}
+}
+
+// ForServiceAccount returns a client that impersonates the ServiceAccount with
+// the provided namespace and name.
+func (f *ImpersonatingClientFactory) ForServiceAccount(
+	namespace string,
+	name string,
+) (client.Client, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.clients[key]; ok {
+		return c, nil
+	}
+	cfg := rest.CopyConfig(f.cfg)
+	// Groups are deliberately not specified. The API server then associates
+	// the ServiceAccount with the same groups it would if the ServiceAccount had
+	// authenticated itself, and impersonating the groups is not separately
+	// subject to authorization.
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: ServiceAccountUsername(namespace, name),
+	}
+	c, err := f.newClientFn(cfg, f.opts)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error building client impersonating ServiceAccount %q in namespace %q: %w",
+			name, namespace, err,
+		)
+	}
+	f.clients[key] = c
+	return c, nil
+}
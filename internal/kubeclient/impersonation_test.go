@@ -0,0 +1,113 @@
+package kubeclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestImpersonatingClientFactory_ForServiceAccount(t *testing.T) {
+	var builds []rest.ImpersonationConfig
+	f := NewImpersonatingClientFactory(&rest.Config{Host: "https://example.com"}, client.Options{})
+	f.newClientFn = func(cfg *rest.Config, _ client.Options) (client.Client, error) {
+		builds = append(builds, cfg.Impersonate)
+		return fake.NewClientBuilder().Build(), nil
+	}
+
+	c1, err := f.ForServiceAccount("team-a", "kargo")
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	require.Equal(t, "system:serviceaccount:team-a:kargo", builds[0].UserName)
+	require.Empty(t, builds[0].Groups)
+
+	// The client is cached
+	c2, err := f.ForServiceAccount("team-a", "kargo")
+	require.NoError(t, err)
+	require.Same(t, c1, c2)
+	require.Len(t, builds, 1)
+
+	// A different ServiceAccount gets a different client
+	c3, err := f.ForServiceAccount("team-b", "kargo")
+	require.NoError(t, err)
+	require.NotSame(t, c1, c3)
+	require.Len(t, builds, 2)
+	require.Equal(t, "system:serviceaccount:team-b:kargo", builds[1].UserName)
+
+	// The original config is left untouched
+	require.Empty(t, f.cfg.Impersonate.UserName)
+
+	// Failures are not cached
+	f.newClientFn = func(*rest.Config, client.Options) (client.Client, error) {
+		return nil, errors.New("something went wrong")
+	}
+	_, err = f.ForServiceAccount("team-c", "kargo")
+	require.ErrorContains(t, err, "something went wrong")
+	require.NotContains(t, f.clients, types.NamespacedName{Namespace: "team-c", Name: "kargo"})
+}
+
+func TestImpersonatingClientFactory_rbac(t *testing.T) {
+	// The fake API server only permits team-a's ServiceAccount to get the
+	// ConfigMap, mimicking an RBAC rule.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Impersonate-User") != "system:serviceaccount:team-a:kargo" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(apierrors.NewForbidden(
+				schema.GroupResource{Resource: "configmaps"},
+				"fake-cm",
+				errors.New("access denied"),
+			).Status())
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "team-a",
+				Name:      "fake-cm",
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	f := NewImpersonatingClientFactory(
+		&rest.Config{Host: srv.URL},
+		client.Options{Scheme: scheme, Mapper: mapper},
+	)
+
+	c, err := f.ForServiceAccount("team-a", "kargo")
+	require.NoError(t, err)
+	err = c.Get(
+		context.Background(),
+		client.ObjectKey{Namespace: "team-a", Name: "fake-cm"},
+		&corev1.ConfigMap{},
+	)
+	require.NoError(t, err)
+
+	c, err = f.ForServiceAccount("team-b", "kargo")
+	require.NoError(t, err)
+	err = c.Get(
+		context.Background(),
+		client.ObjectKey{Namespace: "team-a", Name: "fake-cm"},
+		&corev1.ConfigMap{},
+	)
+	require.True(t, apierrors.IsForbidden(err))
+}
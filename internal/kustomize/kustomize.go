@@ -11,8 +11,11 @@ import (
 // The specified directory must already exist and contain a kustomization.yaml
 // file.
 func SetImage(dir, fqImageRef string) error {
-	_, err := libExec.Exec(buildSetImageCmd(dir, fqImageRef))
-	return err
+	cmd := buildSetImageCmd(dir, fqImageRef)
+	return observe(cmd, func() error {
+		_, err := libExec.Exec(cmd)
+		return err
+	})
 }
 
 func buildSetImageCmd(dir, fqImageRef string) *exec.Cmd {
@@ -26,3 +26,10 @@ func TestBuildSetImageCmd(t *testing.T) {
 	)
 	require.Equal(t, testDir, cmd.Dir)
 }
+
+func Test_kustomizeOperation(t *testing.T) {
+	require.Equal(t, "build", kustomizeOperation([]string{"kustomize", "build", "."}))
+	require.Equal(t, "edit", kustomizeOperation([]string{"kustomize", "edit", "set", "image"}))
+	require.Equal(t, "other", kustomizeOperation([]string{"kustomize", "version"}))
+	require.Equal(t, "other", kustomizeOperation([]string{"kustomize"}))
+}
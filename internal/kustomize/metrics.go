@@ -0,0 +1,80 @@
+package kustomize
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	libExec "github.com/akuity/kargo/internal/exec"
+	"github.com/akuity/kargo/internal/logging"
+)
+
+var (
+	operationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kargo_kustomize_operation_duration_seconds",
+			Help:    "Duration of kustomize operations that are executed as commands, by operation",
+			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"operation"},
+	)
+	operationFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kargo_kustomize_operation_failures_total",
+			Help: "Number of failed kustomize operations that are executed as commands, by operation",
+		},
+		[]string{"operation"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(operationDuration, operationFailures)
+}
+
+// Run runs the provided kustomize command, whose output is expected to be
+// captured by the caller, and records its duration and outcome in metrics, as
+// well as in any libExec.Timings tracked for the directory it is run in. A
+// message naming the operation is logged if the command is slow.
+func Run(cmd *exec.Cmd) error {
+	return observe(cmd, cmd.Run)
+}
+
+// observe calls the provided function, which executes the provided kustomize
+// command, and records the command's duration and outcome.
+func observe(cmd *exec.Cmd, run func() error) error {
+	op := kustomizeOperation(cmd.Args)
+	start := time.Now()
+	err := run()
+	duration := time.Since(start)
+
+	operationDuration.WithLabelValues(op).Observe(duration.Seconds())
+	if err != nil {
+		operationFailures.WithLabelValues(op).Inc()
+	}
+	libExec.RecordTiming(cmd.Dir, "kustomize "+op, duration)
+	if libExec.IsSlow(duration) {
+		logging.LoggerFromContext(context.Background()).Info(
+			"slow kustomize operation",
+			"operation", op,
+			"dir", cmd.Dir,
+			"duration", duration.Round(time.Millisecond).String(),
+		)
+	}
+	return err
+}
+
+// kustomizeOperation returns the name under which the kustomize command with
+// the provided arguments is reported. The first argument is expected to be the
+// kustomize binary.
+func kustomizeOperation(args []string) string {
+	if len(args) > 1 {
+		switch args[1] {
+		case "build", "edit":
+			return args[1]
+		}
+	}
+	return "other"
+}
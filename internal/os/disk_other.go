@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package os
+
+import (
+	"errors"
+	"runtime"
+)
+
+// FreeSpace returns the number of bytes available to unprivileged users on the
+// file system containing the specified path. It is not supported on this
+// platform and always returns an error.
+func FreeSpace(string) (uint64, error) {
+	return 0, errors.New("determining free space is not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,15 @@
+//go:build linux || darwin
+
+package os
+
+import "syscall"
+
+// FreeSpace returns the number of bytes available to unprivileged users on the
+// file system containing the specified path.
+func FreeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil // nolint: gosec
+}
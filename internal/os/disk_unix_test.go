@@ -0,0 +1,23 @@
+//go:build linux || darwin
+
+package os
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeSpace(t *testing.T) {
+	t.Run("path exists", func(t *testing.T) {
+		free, err := FreeSpace(t.TempDir())
+		require.NoError(t, err)
+		require.NotZero(t, free)
+	})
+
+	t.Run("path does not exist", func(t *testing.T) {
+		_, err := FreeSpace(filepath.Join(t.TempDir(), "nonexistent"))
+		require.Error(t, err)
+	})
+}
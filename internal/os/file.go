@@ -0,0 +1,43 @@
+package os
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to the named file, creating it if necessary, and
+// sets the file's permission bits to perm. Data is first written to a
+// temporary file in the same directory, which is then renamed to the named
+// file. This ensures the named file is either left untouched or completely
+// replaced, and never observed in a partially written state.
+func WriteFileAtomic(name string, data []byte, perm os.FileMode) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	// CreateTemp always creates files with mode 0600, and the mode passed to
+	// Chmod is not subject to the umask.
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("error renaming temporary file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package os
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("file does not exist", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "all.yaml")
+		require.NoError(t, WriteFileAtomic(name, []byte("foo"), 0o644))
+
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(b))
+		fi, err := os.Stat(name)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o644), fi.Mode().Perm())
+	})
+
+	t.Run("file is replaced", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, "all.yaml")
+		require.NoError(t, os.WriteFile(name, []byte("a much longer value"), 0o600))
+		require.NoError(t, WriteFileAtomic(name, []byte("bar"), 0o640))
+
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		require.Equal(t, "bar", string(b))
+		fi, err := os.Stat(name)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o640), fi.Mode().Perm())
+
+		// No temporary files are left behind
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("directory does not exist", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "missing", "all.yaml")
+		require.Error(t, WriteFileAtomic(name, []byte("foo"), 0o644))
+	})
+}
@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/hashicorp/go-cleanhttp"
+
+	"github.com/akuity/kargo/internal/logging"
+	libos "github.com/akuity/kargo/internal/os"
+)
+
+// maxDownloadBytes is the maximum size of an archive or binary that is
+// downloaded into a Cache.
+const maxDownloadBytes = 256 << 20
+
+const (
+	// DefaultKustomizeURL is the default template of the URL that Kustomize
+	// is downloaded from.
+	DefaultKustomizeURL = "https://github.com/kubernetes-sigs/kustomize/releases/download/" +
+		"kustomize%2F{{.Version}}/kustomize_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz"
+	// DefaultKustomizeChecksumURL is the default template of the URL of the
+	// checksums of Kustomize downloads.
+	DefaultKustomizeChecksumURL = "https://github.com/kubernetes-sigs/kustomize/releases/download/" +
+		"kustomize%2F{{.Version}}/checksums.txt"
+	// DefaultHelmURL is the default template of the URL that Helm is
+	// downloaded from.
+	DefaultHelmURL = "https://get.helm.sh/helm-{{.Version}}-{{.OS}}-{{.Arch}}.tar.gz"
+	// DefaultHelmChecksumURL is the default template of the URL of the
+	// checksum of Helm downloads.
+	DefaultHelmChecksumURL = "https://get.helm.sh/helm-{{.Version}}-{{.OS}}-{{.Arch}}.tar.gz.sha256sum"
+)
+
+// Source describes where the binaries of a Tool are downloaded from. Both URLs
+// are Go templates that may refer to .Version, .OS, and .Arch.
+type Source struct {
+	// URL is the URL of either the binary itself or a gzipped tarball
+	// containing it.
+	URL string
+	// ChecksumURL is the URL of a file listing the SHA-256 checksum of the
+	// download, in the format produced by sha256sum. The download is only used
+	// if its checksum is listed.
+	ChecksumURL string
+}
+
+// Cache is a directory of binaries of specific versions of Tools. The binary
+// of version v of Tool t is located at <dir>/<t>/<v>/<t>. Binaries may be
+// placed there in advance, e.g. when building a container image, or are
+// downloaded on first use from the Source configured for the Tool.
+type Cache struct {
+	dir        string
+	sources    map[Tool]Source
+	httpClient *http.Client
+	// mu serializes downloads, so that concurrent Promotions requiring the
+	// same binary download it only once.
+	mu sync.Mutex
+}
+
+// NewCache returns a Cache of binaries in the provided directory that
+// downloads missing binaries from the provided Sources. Binaries of Tools
+// without a Source are never downloaded.
+func NewCache(dir string, sources map[Tool]Source) *Cache {
+	return &Cache{
+		dir:        dir,
+		sources:    sources,
+		httpClient: &http.Client{Transport: cleanhttp.DefaultPooledTransport()},
+	}
+}
+
+// Path returns the path to the binary of the provided version of the provided
+// Tool, downloading it first if it is not in the Cache yet.
+func (c *Cache) Path(ctx context.Context, tool Tool, version string) (string, error) {
+	if err := ValidateVersion(version); err != nil {
+		return "", err
+	}
+	binPath := filepath.Join(c.dir, string(tool), version, string(tool))
+	if exists, err := isExecutable(binPath); err != nil || exists {
+		return binPath, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Another Promotion may have downloaded the binary in the meantime.
+	if exists, err := isExecutable(binPath); err != nil || exists {
+		return binPath, err
+	}
+
+	src, ok := c.sources[tool]
+	if !ok || src.URL == "" {
+		return "", fmt.Errorf("%s %s is not available and no download URL is configured", tool, version)
+	}
+	logger := logging.LoggerFromContext(ctx).WithValues("tool", string(tool), "version", version)
+	logger.Info("downloading tool")
+	bin, err := c.download(ctx, tool, version, src)
+	if err != nil {
+		return "", fmt.Errorf("error downloading %s %s: %w", tool, version, err)
+	}
+	if err = os.MkdirAll(filepath.Dir(binPath), 0o755); err != nil {
+		return "", fmt.Errorf("error creating directory for %s %s: %w", tool, version, err)
+	}
+	if err = libos.WriteFileAtomic(binPath, bin, 0o755); err != nil {
+		return "", fmt.Errorf("error writing %s %s: %w", tool, version, err)
+	}
+	logger.Debug("downloaded tool", "path", binPath)
+	return binPath, nil
+}
+
+// download downloads the binary of the provided version of the provided Tool
+// from the provided Source and verifies its checksum.
+func (c *Cache) download(
+	ctx context.Context,
+	tool Tool,
+	version string,
+	src Source,
+) ([]byte, error) {
+	data := struct{ Version, OS, Arch string }{version, runtime.GOOS, runtime.GOARCH}
+	binURL, err := renderURL(src.URL, data)
+	if err != nil {
+		return nil, err
+	}
+	if src.ChecksumURL == "" {
+		return nil, errors.New("no checksum URL is configured")
+	}
+	checksumURL, err := renderURL(src.ChecksumURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums, err := c.get(ctx, checksumURL)
+	if err != nil {
+		return nil, err
+	}
+	download, err := c.get(ctx, binURL)
+	if err != nil {
+		return nil, err
+	}
+	name, err := downloadName(binURL)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(download)
+	if !checksumListed(checksums, name, hex.EncodeToString(sum[:])) {
+		return nil, fmt.Errorf("checksum of %s is not listed in %s", binURL, checksumURL)
+	}
+
+	if !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".tgz") {
+		return download, nil
+	}
+	return extractBinary(download, string(tool))
+}
+
+// get returns the body of the response to a GET request for the provided URL.
+func (c *Cache) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request for %s: %w", u, err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q requesting %s", resp.Status, u)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", u, err)
+	}
+	if len(body) > maxDownloadBytes {
+		return nil, fmt.Errorf("%s exceeds the maximum size of %d bytes", u, maxDownloadBytes)
+	}
+	return body, nil
+}
+
+// renderURL renders the provided URL template with the provided data.
+func renderURL(tmpl string, data any) (string, error) {
+	t, err := template.New("url").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("error parsing URL template %q: %w", tmpl, err)
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering URL template %q: %w", tmpl, err)
+	}
+	return buf.String(), nil
+}
+
+// downloadName returns the name of the file at the provided URL, which is
+// what checksum files refer to it by.
+func downloadName(u string) (string, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("error parsing URL %s: %w", u, err)
+	}
+	return path.Base(parsed.Path), nil
+}
+
+// checksumListed returns true if the provided checksum file lists the provided
+// hex-encoded checksum for the file with the provided name. Lines consisting
+// of nothing but a checksum apply to any file.
+func checksumListed(checksums []byte, name, sum string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 1:
+		case len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name:
+		default:
+			continue
+		}
+		if strings.EqualFold(fields[0], sum) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractBinary returns the content of the regular file with the provided
+// name, in any directory, of the provided gzipped tarball.
+func extractBinary(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive does not contain %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Base(hdr.Name) != name {
+			continue
+		}
+		bin, err := io.ReadAll(io.LimitReader(tr, maxDownloadBytes))
+		if err != nil {
+			return nil, fmt.Errorf("error extracting %q from archive: %w", hdr.Name, err)
+		}
+		return bin, nil
+	}
+}
+
+// isExecutable returns true if the file at the provided path exists and is an
+// executable regular file.
+func isExecutable(p string) (bool, error) {
+	fi, err := os.Stat(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !fi.Mode().IsRegular() || fi.Mode().Perm()&0o111 == 0 {
+		return false, fmt.Errorf("%s is not an executable file", p)
+	}
+	return true, nil
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Path(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho kustomize\n")
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: "LICENSE", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3,
+	}))
+	_, err := tw.Write([]byte("MIT"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: runtime.GOOS + "/kustomize", Typeflag: tar.TypeReg, Mode: 0o755, Size: int64(len(binary)),
+	}))
+	_, err = tw.Write(binary)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	sum := sha256.Sum256(archive.Bytes())
+
+	archiveName := fmt.Sprintf("kustomize_v5.4.3_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v5.4.3/checksums.txt":
+			_, _ = fmt.Fprintf(w, "%s  other.tar.gz\n%s  %s\n", hex.EncodeToString(make([]byte, 32)),
+				hex.EncodeToString(sum[:]), archiveName)
+		case "/v5.4.3/" + archiveName:
+			downloads.Add(1)
+			_, _ = w.Write(archive.Bytes())
+		case "/v5.4.4/checksums.txt":
+			_, _ = fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(make([]byte, 32)),
+				fmt.Sprintf("kustomize_v5.4.4_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH))
+		case fmt.Sprintf("/v5.4.4/kustomize_v5.4.4_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH):
+			_, _ = w.Write(archive.Bytes())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	// A binary placed into the cache in advance is used as is.
+	preloaded := filepath.Join(dir, "helm", "v3.15.0", "helm")
+	require.NoError(t, os.MkdirAll(filepath.Dir(preloaded), 0o755))
+	require.NoError(t, os.WriteFile(preloaded, []byte("helm"), 0o755))
+
+	cache := NewCache(dir, map[Tool]Source{
+		Kustomize: {
+			URL:         srv.URL + "/{{.Version}}/kustomize_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz",
+			ChecksumURL: srv.URL + "/{{.Version}}/checksums.txt",
+		},
+	})
+
+	t.Run("preloaded", func(t *testing.T) {
+		p, err := cache.Path(context.Background(), Helm, "v3.15.0")
+		require.NoError(t, err)
+		require.Equal(t, preloaded, p)
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		_, err := cache.Path(context.Background(), Helm, "v3.16.0")
+		require.ErrorContains(t, err, "no download URL is configured")
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := cache.Path(context.Background(), Kustomize, "../v5.4.3")
+		require.ErrorContains(t, err, "is not of the form")
+	})
+
+	t.Run("downloaded once", func(t *testing.T) {
+		for range 2 {
+			p, err := cache.Path(context.Background(), Kustomize, "v5.4.3")
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(dir, "kustomize", "v5.4.3", "kustomize"), p)
+			content, err := os.ReadFile(p)
+			require.NoError(t, err)
+			require.Equal(t, binary, content)
+		}
+		require.Equal(t, int32(1), downloads.Load())
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, err := cache.Path(context.Background(), Kustomize, "v5.4.4")
+		require.ErrorContains(t, err, "is not listed")
+		_, err = os.Stat(filepath.Join(dir, "kustomize", "v5.4.4"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := cache.Path(context.Background(), Kustomize, "v1.0.0")
+		require.ErrorContains(t, err, "404")
+	})
+}
+
+func Test_checksumListed(t *testing.T) {
+	const sum = "ABCDEF"
+	require.True(t, checksumListed([]byte("abcdef  tool.tar.gz\n"), "tool.tar.gz", "abcdef"))
+	require.True(t, checksumListed([]byte(sum+" *tool.tar.gz\n"), "tool.tar.gz", "abcdef"))
+	require.True(t, checksumListed([]byte(sum+"\n"), "tool.tar.gz", "abcdef"))
+	require.False(t, checksumListed([]byte("abcdef  other.tar.gz\n"), "tool.tar.gz", "abcdef"))
+	require.False(t, checksumListed([]byte("123456  tool.tar.gz\n"), "tool.tar.gz", "abcdef"))
+}
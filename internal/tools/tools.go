@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Tool is the name of a tool used to render manifests.
+type Tool string
+
+const (
+	// Kustomize is the kustomize CLI.
+	Kustomize Tool = "kustomize"
+	// Helm is the helm CLI.
+	Helm Tool = "helm"
+)
+
+const (
+	// EmbeddedKustomizeVersion is the version of Kustomize whose API module
+	// (see EmbeddedKustomizeAPIVersion) is compiled into Kargo. The two must be
+	// updated together whenever sigs.k8s.io/kustomize/api is upgraded.
+	EmbeddedKustomizeVersion = "v5.5.0"
+	// EmbeddedKustomizeAPIVersion is the version of sigs.k8s.io/kustomize/api
+	// that Kargo is compiled with.
+	EmbeddedKustomizeAPIVersion = "v0.18.0"
+	// EmbeddedHelmVersion is the version of helm.sh/helm/v3 that Kargo is
+	// compiled with.
+	EmbeddedHelmVersion = "v3.16.4"
+)
+
+// versionRegex matches the versions tools may be pinned to. It deliberately
+// admits nothing but release versions, as versions are used in file paths and
+// download URLs.
+var versionRegex = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// EmbeddedVersion returns the version of the provided Tool that is compiled
+// into Kargo.
+func EmbeddedVersion(tool Tool) string {
+	switch tool {
+	case Kustomize:
+		return EmbeddedKustomizeVersion
+	case Helm:
+		return EmbeddedHelmVersion
+	default:
+		return ""
+	}
+}
+
+// ValidateVersion returns an error if the provided version is not a version
+// that a Tool may be pinned to.
+func ValidateVersion(version string) error {
+	if !versionRegex.MatchString(version) {
+		return fmt.Errorf("version %q is not of the form vMAJOR.MINOR.PATCH", version)
+	}
+	return nil
+}
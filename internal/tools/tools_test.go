@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	// Imported for their presence in the build info only.
+	_ "helm.sh/helm/v3/pkg/action"
+	_ "sigs.k8s.io/kustomize/api/krusty"
+)
+
+func TestEmbeddedVersions(t *testing.T) {
+	info, ok := debug.ReadBuildInfo()
+	require.True(t, ok)
+	versions := map[string]string{}
+	for _, dep := range info.Deps {
+		versions[dep.Path] = dep.Version
+	}
+	// If either of these fails, the embedded versions need to be updated to
+	// reflect an upgraded dependency.
+	require.Equal(t, EmbeddedKustomizeAPIVersion, versions["sigs.k8s.io/kustomize/api"])
+	require.Equal(t, EmbeddedHelmVersion, versions["helm.sh/helm/v3"])
+}
+
+func TestValidateVersion(t *testing.T) {
+	require.NoError(t, ValidateVersion("v5.5.0"))
+	require.Error(t, ValidateVersion("5.5.0"))
+	require.Error(t, ValidateVersion("v5.5"))
+	require.Error(t, ValidateVersion("v5.5.0/../../bin"))
+}
@@ -73,6 +73,8 @@ type webhook struct {
 
 	validateFreightArtifactsFn func(*kargoapi.Freight, *kargoapi.Warehouse) error
 
+	getKargoConfigFn func(context.Context, client.Client) (*kargoapi.KargoConfig, error)
+
 	isRequestFromKargoControlplaneFn libWebhook.IsRequestFromKargoControlplaneFn
 }
 
@@ -110,6 +112,7 @@ func newWebhook(
 	w.listStagesFn = kubeClient.List
 	w.getWarehouseFn = kargoapi.GetWarehouse
 	w.validateFreightArtifactsFn = validateFreightArtifacts
+	w.getKargoConfigFn = kargoapi.GetKargoConfig
 	w.isRequestFromKargoControlplaneFn = libWebhook.IsRequestFromKargoControlplane(cfg.ControlplaneUserRegex)
 	return w
 }
@@ -222,6 +225,43 @@ func (w *webhook) ValidateCreate(
 		return nil, err
 	}
 
+	return w.validateImageLimits(ctx, freight)
+}
+
+// validateImageLimits checks the number of images in the Freight against the
+// image limits of the KargoConfig. It returns a warning if the soft limit is
+// exceeded and an error if the hard limit is exceeded.
+func (w *webhook) validateImageLimits(
+	ctx context.Context,
+	freight *kargoapi.Freight,
+) (admission.Warnings, error) {
+	cfg, err := w.getKargoConfigFn(ctx, w.client)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	var limits *kargoapi.ImageLimits
+	if cfg != nil {
+		limits = cfg.Spec.ImageLimits
+	}
+
+	if hardLimit := limits.GetHardLimit(); hardLimit > 0 && len(freight.Images) > hardLimit {
+		return nil, apierrors.NewInvalid(
+			freightGroupKind,
+			freight.Name,
+			field.ErrorList{
+				field.TooMany(field.NewPath("images"), len(freight.Images), hardLimit),
+			},
+		)
+	}
+	if softLimit := limits.GetSoftLimit(); softLimit > 0 && len(freight.Images) > softLimit {
+		return admission.Warnings{
+			fmt.Sprintf(
+				"freight contains %d images, which is more than the recommended maximum of %d",
+				len(freight.Images),
+				softLimit,
+			),
+		}, nil
+	}
 	return nil, nil
 }
 
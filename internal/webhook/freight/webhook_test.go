@@ -43,6 +43,7 @@ func TestNewWebhook(t *testing.T) {
 	require.NotNil(t, w.listStagesFn)
 	require.NotNil(t, w.getWarehouseFn)
 	require.NotNil(t, w.validateFreightArtifactsFn)
+	require.NotNil(t, w.getKargoConfigFn)
 	require.NotNil(t, w.isRequestFromKargoControlplaneFn)
 }
 
@@ -412,6 +413,12 @@ func TestValidateCreate(t *testing.T) {
 				) error {
 					return nil
 				},
+				getKargoConfigFn: func(
+					context.Context,
+					client.Client,
+				) (*kargoapi.KargoConfig, error) {
+					return nil, nil
+				},
 			},
 			freight: kargoapi.Freight{
 				Commits: []kargoapi.GitCommit{{}},
@@ -430,6 +437,117 @@ func TestValidateCreate(t *testing.T) {
 	}
 }
 
+func TestValidateImageLimits(t *testing.T) {
+	testCases := []struct {
+		name       string
+		cfg        *kargoapi.KargoConfig
+		cfgErr     error
+		images     int
+		assertions func(*testing.T, admission.Warnings, error)
+	}{
+		{
+			name:   "error getting KargoConfig",
+			cfgErr: errors.New("something went wrong"),
+			assertions: func(t *testing.T, _ admission.Warnings, err error) {
+				statusErr, ok := err.(*apierrors.StatusError)
+				require.True(t, ok)
+				require.Equal(t, int32(http.StatusInternalServerError), statusErr.Status().Code)
+			},
+		},
+		{
+			name:   "default limits not exceeded",
+			images: kargoapi.DefaultImageSoftLimit,
+			assertions: func(t *testing.T, warnings admission.Warnings, err error) {
+				require.NoError(t, err)
+				require.Empty(t, warnings)
+			},
+		},
+		{
+			name:   "default soft limit exceeded",
+			images: kargoapi.DefaultImageSoftLimit + 1,
+			assertions: func(t *testing.T, warnings admission.Warnings, err error) {
+				require.NoError(t, err)
+				require.Len(t, warnings, 1)
+				require.Contains(t, warnings[0], "more than the recommended maximum of 50")
+			},
+		},
+		{
+			name:   "default hard limit exceeded",
+			images: kargoapi.DefaultImageHardLimit + 1,
+			assertions: func(t *testing.T, warnings admission.Warnings, err error) {
+				require.True(t, apierrors.IsInvalid(err))
+				require.ErrorContains(t, err, "must have at most 500 items")
+				require.Empty(t, warnings)
+			},
+		},
+		{
+			name:   "configured limits not exceeded",
+			cfg:    newKargoConfigWithImageLimits(2, 3),
+			images: 2,
+			assertions: func(t *testing.T, warnings admission.Warnings, err error) {
+				require.NoError(t, err)
+				require.Empty(t, warnings)
+			},
+		},
+		{
+			name:   "configured soft limit exceeded",
+			cfg:    newKargoConfigWithImageLimits(2, 3),
+			images: 3,
+			assertions: func(t *testing.T, warnings admission.Warnings, err error) {
+				require.NoError(t, err)
+				require.Len(t, warnings, 1)
+				require.Contains(t, warnings[0], "freight contains 3 images")
+			},
+		},
+		{
+			name:   "configured hard limit exceeded",
+			cfg:    newKargoConfigWithImageLimits(2, 3),
+			images: 4,
+			assertions: func(t *testing.T, _ admission.Warnings, err error) {
+				require.True(t, apierrors.IsInvalid(err))
+				require.ErrorContains(t, err, "must have at most 3 items")
+			},
+		},
+		{
+			name:   "limits disabled",
+			cfg:    newKargoConfigWithImageLimits(0, 0),
+			images: kargoapi.DefaultImageHardLimit + 1,
+			assertions: func(t *testing.T, warnings admission.Warnings, err error) {
+				require.NoError(t, err)
+				require.Empty(t, warnings)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := &webhook{
+				getKargoConfigFn: func(
+					context.Context,
+					client.Client,
+				) (*kargoapi.KargoConfig, error) {
+					return testCase.cfg, testCase.cfgErr
+				},
+			}
+			freight := &kargoapi.Freight{
+				Images: make([]kargoapi.Image, testCase.images),
+			}
+			warnings, err := w.validateImageLimits(context.Background(), freight)
+			testCase.assertions(t, warnings, err)
+		})
+	}
+}
+
+func newKargoConfigWithImageLimits(softLimit, hardLimit int32) *kargoapi.KargoConfig {
+	return &kargoapi.KargoConfig{
+		Spec: kargoapi.KargoConfigSpec{
+			ImageLimits: &kargoapi.ImageLimits{
+				SoftLimit: &softLimit,
+				HardLimit: &hardLimit,
+			},
+		},
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -0,0 +1,107 @@
+package kargoconfig
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+	"github.com/akuity/kargo/internal/git"
+)
+
+var kargoConfigGroupKind = schema.GroupKind{
+	Group: kargoapi.GroupVersion.Group,
+	Kind:  "KargoConfig",
+}
+
+type webhook struct{}
+
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	w := &webhook{}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kargoapi.KargoConfig{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *webhook) ValidateCreate(
+	_ context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	cfg := obj.(*kargoapi.KargoConfig) // nolint: forcetypeassert
+	return nil, w.validate(cfg)
+}
+
+func (w *webhook) ValidateUpdate(
+	_ context.Context,
+	_ runtime.Object,
+	newObj runtime.Object,
+) (admission.Warnings, error) {
+	cfg := newObj.(*kargoapi.KargoConfig) // nolint: forcetypeassert
+	return nil, w.validate(cfg)
+}
+
+func (w *webhook) ValidateDelete(
+	context.Context,
+	runtime.Object,
+) (admission.Warnings, error) {
+	// No-op
+	return nil, nil
+}
+
+func (w *webhook) validate(cfg *kargoapi.KargoConfig) error {
+	var errs field.ErrorList
+	if cfg.Name != kargoapi.KargoConfigName {
+		errs = append(errs, field.Invalid(
+			field.NewPath("metadata", "name"),
+			cfg.Name,
+			"KargoConfig is a singleton and must be named "+kargoapi.KargoConfigName,
+		))
+	}
+	errs = append(errs, validateGitClient(field.NewPath("spec", "gitClient"), cfg.Spec.GitClient)...)
+	errs = append(errs, validateRepoPolicy(field.NewPath("spec", "repoPolicy"), cfg.Spec.RepoPolicy)...)
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(kargoConfigGroupKind, cfg.Name, errs)
+	}
+	return nil
+}
+
+func validateGitClient(f *field.Path, cfg *kargoapi.GitClientConfig) field.ErrorList {
+	if cfg == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if v := cfg.MaxConcurrentOpsPerHost; v != nil && *v < 0 {
+		errs = append(errs, field.Invalid(f.Child("maxConcurrentOpsPerHost"), *v, "must not be negative"))
+	}
+	if v := cfg.MaxOpsPerMinutePerHost; v != nil && *v < 0 {
+		errs = append(errs, field.Invalid(f.Child("maxOpsPerMinutePerHost"), *v, "must not be negative"))
+	}
+	if v := cfg.NetworkMaxAttempts; v != nil && *v < 1 {
+		errs = append(errs, field.Invalid(f.Child("networkMaxAttempts"), *v, "must be at least 1"))
+	}
+	return errs
+}
+
+func validateRepoPolicy(f *field.Path, policy *kargoapi.RepoPolicy) field.ErrorList {
+	if policy == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for i, pattern := range policy.Allow {
+		if err := git.ValidateRepoURLPattern(pattern); err != nil {
+			errs = append(errs, field.Invalid(f.Child("allow").Index(i), pattern, err.Error()))
+		}
+	}
+	for i, pattern := range policy.Deny {
+		if err := git.ValidateRepoURLPattern(pattern); err != nil {
+			errs = append(errs, field.Invalid(f.Child("deny").Index(i), pattern, err.Error()))
+		}
+	}
+	return errs
+}
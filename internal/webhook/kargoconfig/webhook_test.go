@@ -0,0 +1,92 @@
+package kargoconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kargoapi "github.com/akuity/kargo/api/v1alpha1"
+)
+
+func Test_webhook_ValidateCreate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		cfg        *kargoapi.KargoConfig
+		assertions func(*testing.T, error)
+	}{
+		{
+			name: "valid",
+			cfg: &kargoapi.KargoConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: kargoapi.KargoConfigName},
+				Spec: kargoapi.KargoConfigSpec{
+					PausePromotions: true,
+					GitClient: &kargoapi.GitClientConfig{
+						MaxConcurrentOpsPerHost: ptr.To[int32](0),
+						NetworkMaxAttempts:      ptr.To[int32](1),
+					},
+					RepoPolicy: &kargoapi.RepoPolicy{
+						Allow: []string{"https://github.com/example/*"},
+						Deny:  []string{`regex:^https://github\.com/example/secret-`},
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "wrong name",
+			cfg: &kargoapi.KargoConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "metadata.name")
+				require.ErrorContains(t, err, "must be named kargo")
+			},
+		},
+		{
+			name: "invalid git client settings",
+			cfg: &kargoapi.KargoConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: kargoapi.KargoConfigName},
+				Spec: kargoapi.KargoConfigSpec{
+					GitClient: &kargoapi.GitClientConfig{
+						MaxOpsPerMinutePerHost: ptr.To[int32](-1),
+						NetworkMaxAttempts:     ptr.To[int32](0),
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "spec.gitClient.maxOpsPerMinutePerHost")
+				require.ErrorContains(t, err, "spec.gitClient.networkMaxAttempts")
+			},
+		},
+		{
+			name: "invalid repo policy",
+			cfg: &kargoapi.KargoConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: kargoapi.KargoConfigName},
+				Spec: kargoapi.KargoConfigSpec{
+					RepoPolicy: &kargoapi.RepoPolicy{
+						Allow: []string{"https://github.com/example/*", "https://github.com/[example"},
+						Deny:  []string{"regex:("},
+					},
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "spec.repoPolicy.allow[1]")
+				require.ErrorContains(t, err, "spec.repoPolicy.deny[0]")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := &webhook{}
+			_, err := w.ValidateCreate(context.Background(), testCase.cfg)
+			testCase.assertions(t, err)
+			_, err = w.ValidateUpdate(context.Background(), nil, testCase.cfg)
+			testCase.assertions(t, err)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authzv1 "k8s.io/api/authorization/v1"
@@ -15,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -58,6 +61,12 @@ type webhook struct {
 		types.NamespacedName,
 	) (*kargoapi.Stage, error)
 
+	getWarehouseFn func(
+		context.Context,
+		client.Client,
+		types.NamespacedName,
+	) (*kargoapi.Warehouse, error)
+
 	validateProjectFn func(
 		context.Context,
 		client.Client,
@@ -113,6 +122,7 @@ func newWebhook(
 	}
 	w.getFreightFn = kargoapi.GetFreight
 	w.getStageFn = kargoapi.GetStage
+	w.getWarehouseFn = kargoapi.GetWarehouse
 	w.validateProjectFn = libWebhook.ValidateProject
 	w.authorizeFn = w.authorize
 	w.admissionRequestFromContextFn = admission.RequestFromContext
@@ -199,6 +209,22 @@ func (w *webhook) Default(ctx context.Context, obj runtime.Object) error {
 		)
 	}
 
+	// Promotions that do not specify a priority when they are created inherit
+	// the Stage's. The spec is immutable afterwards.
+	if req.Operation == admissionv1.Create && promo.Spec.Priority == nil && stage.Spec.PromotionPriority != 0 {
+		promo.Spec.Priority = ptr.To(stage.Spec.PromotionPriority)
+	}
+
+	// Promotions are subject to the approval policy of the Stage at the time
+	// they are created, which users cannot override.
+	if req.Operation == admissionv1.Create {
+		promo.Spec.Approval = stage.Spec.PromotionApproval.DeepCopy()
+	}
+
+	// Enrich any approval with the approver and the spec it applies to. This
+	// must happen after all changes to the spec have been made.
+	w.setApproveAnnotation(req, oldPromo, promo)
+
 	// Make sure the Promotion has the same shard as the Stage
 	if stage.Spec.Shard != "" {
 		if promo.Labels == nil {
@@ -249,6 +275,12 @@ func (w *webhook) ValidateCreate(
 		return nil, fmt.Errorf("get freight: %w", err)
 	}
 
+	if _, ok := kargoapi.ApprovePromotionAnnotationValue(promo.Annotations); ok {
+		if err = w.validateApproval(req, promo); err != nil {
+			return nil, err
+		}
+	}
+
 	if !stage.IsFreightAvailable(freight) {
 		return nil, apierrors.NewInvalid(
 			promotionGroupKind,
@@ -263,6 +295,10 @@ func (w *webhook) ValidateCreate(
 		)
 	}
 
+	if err = w.validateImageCompleteness(ctx, promo, stage, freight); err != nil {
+		return nil, err
+	}
+
 	// Record Promotion created event if the request doesn't come from Kargo controlplane
 	if !w.isRequestFromKargoControlplaneFn(req) {
 		w.recordPromotionCreatedEvent(ctx, req, promo, freight)
@@ -270,6 +306,50 @@ func (w *webhook) ValidateCreate(
 	return nil, nil
 }
 
+// validateImageCompleteness returns an error if the Stage requires the images
+// carried by Freight to cover exactly the images subscribed to by the
+// Warehouse the Freight originates from, and the Freight does not.
+func (w *webhook) validateImageCompleteness(
+	ctx context.Context,
+	promo *kargoapi.Promotion,
+	stage *kargoapi.Stage,
+	freight *kargoapi.Freight,
+) error {
+	if stage.Spec.ImageCompleteness != kargoapi.ImageCompletenessPolicyDeclared ||
+		freight.Origin.Kind != kargoapi.FreightOriginKindWarehouse {
+		return nil
+	}
+	warehouse, err := w.getWarehouseFn(ctx, w.client, types.NamespacedName{
+		Namespace: freight.Namespace,
+		Name:      freight.Origin.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("get warehouse: %w", err)
+	}
+	if warehouse == nil {
+		return nil
+	}
+	if coverage := kargo.NewImageCoverage(warehouse, freight.Images); !coverage.Complete() {
+		return apierrors.NewInvalid(
+			promotionGroupKind,
+			promo.Name,
+			field.ErrorList{
+				field.Invalid(
+					field.NewPath("spec", "freight"),
+					promo.Spec.Freight,
+					fmt.Sprintf(
+						"Stage requires Freight to carry exactly the images subscribed to by "+
+							"Warehouse %q, but the Freight %s",
+						warehouse.Name,
+						coverage,
+					),
+				),
+			},
+		)
+	}
+	return nil
+}
+
 func (w *webhook) ValidateUpdate(
 	ctx context.Context,
 	oldObj runtime.Object,
@@ -280,8 +360,10 @@ func (w *webhook) ValidateUpdate(
 		return nil, err
 	}
 
+	oldPromo := oldObj.(*kargoapi.Promotion) // nolint: forcetypeassert
+
 	// PromotionSpecs are meant to be immutable
-	if !reflect.DeepEqual(promo.Spec, oldObj.(*kargoapi.Promotion).Spec) { // nolint: forcetypeassert
+	if !reflect.DeepEqual(promo.Spec, oldPromo.Spec) {
 		return nil, apierrors.NewInvalid(
 			promotionGroupKind,
 			promo.Name,
@@ -294,6 +376,18 @@ func (w *webhook) ValidateUpdate(
 			},
 		)
 	}
+
+	_, approved := kargoapi.ApprovePromotionAnnotationValue(promo.Annotations)
+	_, wasApproved := kargoapi.ApprovePromotionAnnotationValue(oldPromo.Annotations)
+	if approved && !wasApproved {
+		req, err := w.admissionRequestFromContextFn(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get admission request from context: %w", err)
+		}
+		if err = w.validateApproval(req, promo); err != nil {
+			return nil, err
+		}
+	}
 	return nil, nil
 }
 
@@ -405,3 +499,102 @@ func (w *webhook) setAbortAnnotationActor(req admission.Request, existing, updat
 		}
 	}
 }
+
+// setApproveAnnotation replaces the value of a newly set approve annotation of
+// the updated Promotion with a record of who approved the Promotion, when, and
+// for which spec. An approve annotation that was already set on the existing
+// Promotion cannot be changed or removed.
+func (w *webhook) setApproveAnnotation(req admission.Request, existing, updated *kargoapi.Promotion) {
+	if existing != nil {
+		if oldVal, ok := existing.Annotations[kargoapi.AnnotationKeyApprove]; ok {
+			updated.Annotations[kargoapi.AnnotationKeyApprove] = oldVal
+			return
+		}
+	}
+	if _, ok := kargoapi.ApprovePromotionAnnotationValue(updated.Annotations); !ok {
+		return
+	}
+	updated.Annotations[kargoapi.AnnotationKeyApprove] = (&kargoapi.ApprovePromotionRequest{
+		Actor:      kargoapi.FormatEventKubernetesUserActor(req.UserInfo),
+		ApprovedAt: &metav1.Time{Time: time.Now()},
+		SpecHash:   updated.Spec.Hash(),
+	}).String()
+}
+
+// validateApproval returns an error if the user who made the provided
+// admission request may not approve the provided Promotion.
+func (w *webhook) validateApproval(req admission.Request, promo *kargoapi.Promotion) error {
+	policy := promo.Spec.Approval
+	if policy == nil {
+		return apierrors.NewInvalid(
+			promotionGroupKind,
+			promo.Name,
+			field.ErrorList{
+				field.Invalid(
+					field.NewPath("metadata", "annotations").Key(kargoapi.AnnotationKeyApprove),
+					promo.Annotations[kargoapi.AnnotationKeyApprove],
+					fmt.Sprintf("Promotions for Stage %q do not require approval", promo.Spec.Stage),
+				),
+			},
+		)
+	}
+	if promo.Status.Phase == kargoapi.PromotionPhaseExpired {
+		return apierrors.NewInvalid(
+			promotionGroupKind,
+			promo.Name,
+			field.ErrorList{
+				field.Invalid(
+					field.NewPath("metadata", "annotations").Key(kargoapi.AnnotationKeyApprove),
+					promo.Annotations[kargoapi.AnnotationKeyApprove],
+					"the Promotion has expired and can no longer be approved",
+				),
+			},
+		)
+	}
+	if !policy.IsAllowedApprover(req.UserInfo.Username, req.UserInfo.Groups, promo.Namespace) {
+		return apierrors.NewForbidden(
+			promotionGroupResource,
+			promo.Name,
+			fmt.Errorf(
+				"subject %q is not an allowed approver of Promotions for Stage %q",
+				req.UserInfo.Username,
+				promo.Spec.Stage,
+			),
+		)
+	}
+	// Promotions created using the Kargo API record the user who created them
+	// differently than Promotions created using the Kubernetes API, so the
+	// creator and the approver are compared without regard to how they were
+	// authenticated.
+	approver := actorIdentity(kargoapi.FormatEventKubernetesUserActor(req.UserInfo))
+	if policy.PreventSelfApproval && approver != "" &&
+		actorIdentity(promo.Annotations[kargoapi.AnnotationKeyCreateActor]) == approver {
+		return apierrors.NewForbidden(
+			promotionGroupResource,
+			promo.Name,
+			fmt.Errorf(
+				"subject %q created the Promotion and may not approve it",
+				req.UserInfo.Username,
+			),
+		)
+	}
+	return nil
+}
+
+// actorIdentity returns the identity of the user represented by the provided
+// actor, as formatted by kargoapi.FormatEventKubernetesUserActor or
+// kargoapi.FormatEventUserActor, without the prefix indicating how the user
+// was authenticated. An empty string is returned for actors that do not
+// identify a user.
+func actorIdentity(actor string) string {
+	for _, prefix := range []string{
+		kargoapi.EventActorKubernetesUserPrefix,
+		kargoapi.EventActorEmailPrefix,
+		kargoapi.EventActorSubjectPrefix,
+	} {
+		if identity, ok := strings.CutPrefix(actor, prefix); ok {
+			return identity
+		}
+	}
+	return ""
+}
@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -167,6 +168,77 @@ func TestDefault(t *testing.T) {
 				require.NotEmpty(t, promo.OwnerReferences)
 			},
 		},
+		{
+			name: "priority defaulted from Stage",
+			webhook: &webhook{
+				admissionRequestFromContextFn: admission.RequestFromContext,
+				getStageFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{
+						Spec: kargoapi.StageSpec{
+							PromotionPriority: 50,
+						},
+					}, nil
+				},
+				isRequestFromKargoControlplaneFn: func(admission.Request) bool {
+					return false
+				},
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+				},
+			},
+			promotion: &kargoapi.Promotion{
+				Spec: kargoapi.PromotionSpec{
+					Stage: "fake-stage",
+					Steps: []kargoapi.PromotionStep{{}},
+				},
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				require.Equal(t, ptr.To[int32](50), promo.Spec.Priority)
+			},
+		},
+		{
+			name: "priority specified by Promotion is kept",
+			webhook: &webhook{
+				admissionRequestFromContextFn: admission.RequestFromContext,
+				getStageFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{
+						Spec: kargoapi.StageSpec{
+							PromotionPriority: 50,
+						},
+					}, nil
+				},
+				isRequestFromKargoControlplaneFn: func(admission.Request) bool {
+					return false
+				},
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+				},
+			},
+			promotion: &kargoapi.Promotion{
+				Spec: kargoapi.PromotionSpec{
+					Stage:    "fake-stage",
+					Steps:    []kargoapi.PromotionStep{{}},
+					Priority: ptr.To[int32](0),
+				},
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				require.Equal(t, ptr.To[int32](0), promo.Spec.Priority)
+			},
+		},
 		{
 			name: "set abort actor when request doesn't come from kargo control plane",
 			webhook: &webhook{
@@ -605,6 +677,158 @@ func TestDefault(t *testing.T) {
 				}).String(), v)
 			},
 		},
+		{
+			name: "approval policy copied from Stage on create",
+			webhook: &webhook{
+				admissionRequestFromContextFn: admission.RequestFromContext,
+				getStageFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{
+						Spec: kargoapi.StageSpec{
+							PromotionApproval: &kargoapi.PromotionApprovalPolicy{
+								AllowedApprovers: []kargoapi.PromotionApprover{{
+									Kind: kargoapi.PromotionApproverKindGroup,
+									Name: "prod-approvers",
+								}},
+							},
+						},
+					}, nil
+				},
+				isRequestFromKargoControlplaneFn: func(admission.Request) bool {
+					return false
+				},
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+				},
+			},
+			promotion: &kargoapi.Promotion{
+				Spec: kargoapi.PromotionSpec{
+					Stage: "fake-stage",
+					Steps: []kargoapi.PromotionStep{{}},
+					// Users cannot choose their own approvers.
+					Approval: &kargoapi.PromotionApprovalPolicy{
+						AllowedApprovers: []kargoapi.PromotionApprover{{
+							Kind: kargoapi.PromotionApproverKindUser,
+							Name: "real-user",
+						}},
+					},
+				},
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &kargoapi.PromotionApprovalPolicy{
+					AllowedApprovers: []kargoapi.PromotionApprover{{
+						Kind: kargoapi.PromotionApproverKindGroup,
+						Name: "prod-approvers",
+					}},
+				}, promo.Spec.Approval)
+			},
+		},
+		{
+			name: "set approver when approve annotation is set",
+			webhook: &webhook{
+				admissionRequestFromContextFn: admission.RequestFromContext,
+				getStageFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{}, nil
+				},
+				isRequestFromKargoControlplaneFn: func(admission.Request) bool {
+					return false
+				},
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					UserInfo: authnv1.UserInfo{
+						Username: "real-user",
+					},
+					OldObject: runtime.RawExtension{
+						Object: &kargoapi.Promotion{},
+					},
+				},
+			},
+			promotion: &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyApprove: kargoapi.AnnotationValueTrue,
+					},
+				},
+				Spec: kargoapi.PromotionSpec{
+					Steps: []kargoapi.PromotionStep{{}},
+				},
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				ar, ok := kargoapi.ApprovePromotionAnnotationValue(promo.Annotations)
+				require.True(t, ok)
+				require.Equal(
+					t,
+					kargoapi.FormatEventKubernetesUserActor(authnv1.UserInfo{Username: "real-user"}),
+					ar.Actor,
+				)
+				require.NotNil(t, ar.ApprovedAt)
+				require.Equal(t, promo.Spec.Hash(), ar.SpecHash)
+			},
+		},
+		{
+			name: "approve annotation is immutable",
+			webhook: &webhook{
+				admissionRequestFromContextFn: admission.RequestFromContext,
+				getStageFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{}, nil
+				},
+				isRequestFromKargoControlplaneFn: func(admission.Request) bool {
+					return false
+				},
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					UserInfo: authnv1.UserInfo{
+						Username: "real-user",
+					},
+					OldObject: runtime.RawExtension{
+						Object: &kargoapi.Promotion{
+							ObjectMeta: metav1.ObjectMeta{
+								Annotations: map[string]string{
+									kargoapi.AnnotationKeyApprove: (&kargoapi.ApprovePromotionRequest{
+										Actor: "fake-user",
+									}).String(),
+								},
+							},
+						},
+					},
+				},
+			},
+			promotion: &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyApprove: kargoapi.AnnotationValueTrue,
+					},
+				},
+				Spec: kargoapi.PromotionSpec{
+					Steps: []kargoapi.PromotionStep{{}},
+				},
+			},
+			assertions: func(t *testing.T, promo *kargoapi.Promotion, err error) {
+				require.NoError(t, err)
+				ar, ok := kargoapi.ApprovePromotionAnnotationValue(promo.Annotations)
+				require.True(t, ok)
+				require.Equal(t, "fake-user", ar.Actor)
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -776,6 +1000,72 @@ func TestValidateCreate(t *testing.T) {
 				require.ErrorContains(t, err, "Freight is not available to this Stage")
 			},
 		},
+		{
+			name: "Freight does not carry exactly the declared images",
+			webhook: &webhook{
+				validateProjectFn: func(
+					context.Context,
+					client.Client,
+					schema.GroupKind,
+					client.Object,
+				) error {
+					return nil
+				},
+				authorizeFn: func(context.Context, *kargoapi.Promotion, string) error {
+					return nil
+				},
+				admissionRequestFromContextFn: admission.RequestFromContext,
+				getStageFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Stage, error) {
+					return &kargoapi.Stage{
+						Spec: kargoapi.StageSpec{
+							RequestedFreight: []kargoapi.FreightRequest{{
+								Origin: kargoapi.FreightOrigin{
+									Kind: kargoapi.FreightOriginKindWarehouse,
+									Name: testWarehouse,
+								},
+								Sources: kargoapi.FreightSources{Direct: true},
+							}},
+							ImageCompleteness: kargoapi.ImageCompletenessPolicyDeclared,
+						},
+					}, nil
+				},
+				getFreightFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Freight, error) {
+					return &kargoapi.Freight{
+						Origin: kargoapi.FreightOrigin{
+							Kind: kargoapi.FreightOriginKindWarehouse,
+							Name: testWarehouse,
+						},
+						Images: []kargoapi.Image{{RepoURL: "example.com/frontend", Tag: "v1.0.0"}},
+					}, nil
+				},
+				getWarehouseFn: func(
+					context.Context,
+					client.Client,
+					types.NamespacedName,
+				) (*kargoapi.Warehouse, error) {
+					return &kargoapi.Warehouse{
+						ObjectMeta: metav1.ObjectMeta{Name: testWarehouse},
+						Spec: kargoapi.WarehouseSpec{
+							Subscriptions: []kargoapi.RepoSubscription{
+								{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/frontend"}},
+								{Image: &kargoapi.ImageSubscription{RepoURL: "example.com/backend"}},
+							},
+						},
+					}, nil
+				},
+			},
+			assertions: func(t *testing.T, _ *fakeevent.EventRecorder, err error) {
+				require.ErrorContains(t, err, "does not carry subscribed image(s) example.com/backend")
+			},
+		},
 		{
 			name: "record promotion created event on non-controlplane request",
 			webhook: &webhook{
@@ -988,19 +1278,284 @@ func TestValidateUpdate(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "unauthorized approval",
+			setup: func() (*kargoapi.Promotion, *kargoapi.Promotion) {
+				oldPromo := &kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fake-name",
+						Namespace: "fake-namespace",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage:   "fake-stage",
+						Freight: "fake-freight",
+						Approval: &kargoapi.PromotionApprovalPolicy{
+							AllowedApprovers: []kargoapi.PromotionApprover{{
+								Kind: kargoapi.PromotionApproverKindGroup,
+								Name: "prod-approvers",
+							}},
+						},
+					},
+				}
+				newPromo := oldPromo.DeepCopy()
+				newPromo.Annotations = map[string]string{
+					kargoapi.AnnotationKeyApprove: kargoapi.AnnotationValueTrue,
+				}
+				return oldPromo, newPromo
+			},
+			authorizeFn: func(context.Context, *kargoapi.Promotion, string) error {
+				return nil
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, `subject "fake-user" is not an allowed approver`)
+			},
+		},
+		{
+			name: "approval of expired Promotion",
+			setup: func() (*kargoapi.Promotion, *kargoapi.Promotion) {
+				oldPromo := &kargoapi.Promotion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "fake-name",
+						Namespace: "fake-namespace",
+					},
+					Spec: kargoapi.PromotionSpec{
+						Stage:   "fake-stage",
+						Freight: "fake-freight",
+						Approval: &kargoapi.PromotionApprovalPolicy{
+							AllowedApprovers: []kargoapi.PromotionApprover{{
+								Kind: kargoapi.PromotionApproverKindUser,
+								Name: "fake-user",
+							}},
+						},
+					},
+					Status: kargoapi.PromotionStatus{
+						Phase: kargoapi.PromotionPhaseExpired,
+					},
+				}
+				newPromo := oldPromo.DeepCopy()
+				newPromo.Annotations = map[string]string{
+					kargoapi.AnnotationKeyApprove: kargoapi.AnnotationValueTrue,
+				}
+				return oldPromo, newPromo
+			},
+			authorizeFn: func(context.Context, *kargoapi.Promotion, string) error {
+				return nil
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "has expired and can no longer be approved")
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			w := &webhook{
-				authorizeFn: testCase.authorizeFn,
+				authorizeFn:                   testCase.authorizeFn,
+				admissionRequestFromContextFn: admission.RequestFromContext,
 			}
 			oldPromo, newPromo := testCase.setup()
-			_, err := w.ValidateUpdate(context.Background(), oldPromo, newPromo)
+			ctx := admission.NewContextWithRequest(
+				context.Background(),
+				admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						UserInfo: authnv1.UserInfo{Username: "fake-user"},
+					},
+				},
+			)
+			_, err := w.ValidateUpdate(ctx, oldPromo, newPromo)
 			testCase.assertions(t, err)
 		})
 	}
 }
 
+func TestValidateApproval(t *testing.T) {
+	policy := &kargoapi.PromotionApprovalPolicy{
+		AllowedApprovers: []kargoapi.PromotionApprover{
+			{
+				Kind: kargoapi.PromotionApproverKindUser,
+				Name: "alice",
+			},
+			{
+				Kind: kargoapi.PromotionApproverKindGroup,
+				Name: "prod-approvers",
+			},
+			{
+				Kind: kargoapi.PromotionApproverKindServiceAccount,
+				Name: "release-bot",
+			},
+		},
+	}
+	testCases := []struct {
+		name        string
+		userInfo    authnv1.UserInfo
+		policy      *kargoapi.PromotionApprovalPolicy
+		createActor string
+		assertions  func(*testing.T, error)
+	}{
+		{
+			name:     "Promotion does not require approval",
+			userInfo: authnv1.UserInfo{Username: "alice"},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "do not require approval")
+			},
+		},
+		{
+			name:     "allowed user",
+			userInfo: authnv1.UserInfo{Username: "alice"},
+			policy:   policy,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "member of allowed group",
+			userInfo: authnv1.UserInfo{
+				Username: "bob",
+				Groups:   []string{"developers", "prod-approvers"},
+			},
+			policy: policy,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "not a member of any allowed group",
+			userInfo: authnv1.UserInfo{
+				Username: "bob",
+				Groups:   []string{"developers", "prod-approvers-readonly"},
+			},
+			policy: policy,
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, `subject "bob" is not an allowed approver`)
+			},
+		},
+		{
+			name: "allowed ServiceAccount in the Promotion's namespace",
+			userInfo: authnv1.UserInfo{
+				Username: serviceaccount.MakeUsername("fake-namespace", "release-bot"),
+			},
+			policy: policy,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ServiceAccount of the same name in another namespace",
+			userInfo: authnv1.UserInfo{
+				Username: serviceaccount.MakeUsername("other-namespace", "release-bot"),
+			},
+			policy: policy,
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "is not an allowed approver")
+			},
+		},
+		{
+			name:        "self-approval allowed",
+			userInfo:    authnv1.UserInfo{Username: "alice"},
+			policy:      policy,
+			createActor: kargoapi.FormatEventKubernetesUserActor(authnv1.UserInfo{Username: "alice"}),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "self-approval prevented",
+			userInfo: authnv1.UserInfo{Username: "alice"},
+			policy: &kargoapi.PromotionApprovalPolicy{
+				AllowedApprovers:    policy.AllowedApprovers,
+				PreventSelfApproval: true,
+			},
+			createActor: kargoapi.FormatEventKubernetesUserActor(authnv1.UserInfo{Username: "alice"}),
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, `subject "alice" created the Promotion and may not approve it`)
+			},
+		},
+		{
+			name:     "self-approval of a Promotion created using the Kargo API prevented",
+			userInfo: authnv1.UserInfo{Username: "alice@example.com"},
+			policy: &kargoapi.PromotionApprovalPolicy{
+				AllowedApprovers: []kargoapi.PromotionApprover{{
+					Kind: kargoapi.PromotionApproverKindUser,
+					Name: "alice@example.com",
+				}},
+				PreventSelfApproval: true,
+			},
+			createActor: kargoapi.EventActorEmailPrefix + "alice@example.com",
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "created the Promotion and may not approve it")
+			},
+		},
+		{
+			name:     "self-approval of a Promotion created using the Kargo API by subject prevented",
+			userInfo: authnv1.UserInfo{Username: "alice"},
+			policy: &kargoapi.PromotionApprovalPolicy{
+				AllowedApprovers:    policy.AllowedApprovers,
+				PreventSelfApproval: true,
+			},
+			createActor: kargoapi.EventActorSubjectPrefix + "alice",
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "created the Promotion and may not approve it")
+			},
+		},
+		{
+			name:     "approval of a Promotion created by the Kargo admin while self-approval is prevented",
+			userInfo: authnv1.UserInfo{Username: "admin"},
+			policy: &kargoapi.PromotionApprovalPolicy{
+				AllowedApprovers: []kargoapi.PromotionApprover{{
+					Kind: kargoapi.PromotionApproverKindUser,
+					Name: "admin",
+				}},
+				PreventSelfApproval: true,
+			},
+			createActor: kargoapi.EventActorAdmin,
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "approval of another user's Promotion while self-approval is prevented",
+			userInfo: authnv1.UserInfo{
+				Username: "bob",
+				Groups:   []string{"prod-approvers"},
+			},
+			policy: &kargoapi.PromotionApprovalPolicy{
+				AllowedApprovers:    policy.AllowedApprovers,
+				PreventSelfApproval: true,
+			},
+			createActor: kargoapi.FormatEventKubernetesUserActor(authnv1.UserInfo{Username: "alice"}),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			promo := &kargoapi.Promotion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-name",
+					Namespace: "fake-namespace",
+					Annotations: map[string]string{
+						kargoapi.AnnotationKeyApprove: kargoapi.AnnotationValueTrue,
+					},
+				},
+				Spec: kargoapi.PromotionSpec{
+					Stage:    "fake-stage",
+					Approval: testCase.policy,
+				},
+			}
+			if testCase.createActor != "" {
+				promo.Annotations[kargoapi.AnnotationKeyCreateActor] = testCase.createActor
+			}
+			w := &webhook{}
+			testCase.assertions(t, w.validateApproval(
+				admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: testCase.userInfo},
+				},
+				promo,
+			))
+		})
+	}
+}
+
 func TestValidateDelete(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -3,12 +3,16 @@ package stage
 import (
 	"context"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,6 +20,7 @@ import (
 
 	kargoapi "github.com/akuity/kargo/api/v1alpha1"
 	"github.com/akuity/kargo/internal/directives"
+	"github.com/akuity/kargo/internal/kargo"
 	libWebhook "github.com/akuity/kargo/internal/webhook"
 )
 
@@ -26,6 +31,21 @@ var (
 	}
 )
 
+const (
+	// metadataKeyMaxLength is the maximum length of a key of a Stage's
+	// metadata.
+	metadataKeyMaxLength = 63
+	// metadataValueMaxLength is the maximum length of a value of a Stage's
+	// metadata.
+	metadataValueMaxLength = 256
+	// metadataMaxSize is the maximum combined length of all keys and values of
+	// a Stage's metadata.
+	metadataMaxSize = 4096
+)
+
+// metadataKeyRegex matches valid keys of a Stage's metadata.
+var metadataKeyRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.-]*$`)
+
 type webhook struct {
 	client  client.Client
 	decoder admission.Decoder
@@ -43,6 +63,10 @@ type webhook struct {
 
 	validateCreateOrUpdateFn func(*kargoapi.Stage) (admission.Warnings, error)
 
+	validateUpstreamStagesFn func(context.Context, *kargoapi.Stage) error
+
+	validateRenderedBranchSourcesFn func(context.Context, *kargoapi.Stage) error
+
 	validateSpecFn func(*field.Path, *kargoapi.StageSpec) field.ErrorList
 
 	isRequestFromKargoControlplaneFn libWebhook.IsRequestFromKargoControlplaneFn
@@ -76,6 +100,8 @@ func newWebhook(
 	w.admissionRequestFromContextFn = admission.RequestFromContext
 	w.validateProjectFn = libWebhook.ValidateProject
 	w.validateCreateOrUpdateFn = w.validateCreateOrUpdate
+	w.validateUpstreamStagesFn = w.validateUpstreamStages
+	w.validateRenderedBranchSourcesFn = w.validateRenderedBranchSources
 	w.validateSpecFn = w.validateSpec
 	w.isRequestFromKargoControlplaneFn =
 		libWebhook.IsRequestFromKargoControlplane(cfg.ControlplaneUserRegex)
@@ -160,16 +186,30 @@ func (w *webhook) ValidateCreate(
 		w.validateProjectFn(ctx, w.client, stageGroupKind, stage); err != nil {
 		return nil, err
 	}
-	return w.validateCreateOrUpdateFn(stage)
+	warnings, err := w.validateCreateOrUpdateFn(stage)
+	if err != nil {
+		return warnings, err
+	}
+	if err = w.validateUpstreamStagesFn(ctx, stage); err != nil {
+		return warnings, err
+	}
+	return warnings, w.validateRenderedBranchSourcesFn(ctx, stage)
 }
 
 func (w *webhook) ValidateUpdate(
-	_ context.Context,
+	ctx context.Context,
 	_ runtime.Object,
 	newObj runtime.Object,
 ) (admission.Warnings, error) {
 	stage := newObj.(*kargoapi.Stage) // nolint: forcetypeassert
-	return w.validateCreateOrUpdateFn(stage)
+	warnings, err := w.validateCreateOrUpdateFn(stage)
+	if err != nil {
+		return warnings, err
+	}
+	if err = w.validateUpstreamStagesFn(ctx, stage); err != nil {
+		return warnings, err
+	}
+	return warnings, w.validateRenderedBranchSourcesFn(ctx, stage)
 }
 
 func (w *webhook) ValidateDelete(
@@ -183,12 +223,113 @@ func (w *webhook) ValidateDelete(
 func (w *webhook) validateCreateOrUpdate(
 	s *kargoapi.Stage,
 ) (admission.Warnings, error) {
-	if errs := w.validateSpecFn(field.NewPath("spec"), &s.Spec); len(errs) > 0 {
+	errs := w.validateSpecFn(field.NewPath("spec"), &s.Spec)
+	errs = append(errs, w.validateRenderedBranch(field.NewPath("spec", "renderedBranch"), s)...)
+	errs = append(errs, w.validateOverlays(field.NewPath("spec", "overlays"), s)...)
+	errs = append(errs, w.validateImageMappings(field.NewPath("spec", "imageMappings"), s)...)
+	errs = append(errs, w.validateMetadata(field.NewPath("spec", "metadata"), s)...)
+	if len(errs) > 0 {
 		return nil, apierrors.NewInvalid(stageGroupKind, s.Name, errs)
 	}
 	return nil, nil
 }
 
+// validateRenderedBranch makes sure the Stage's RenderedBranch template, if
+// any, renders a valid branch name. As everything the template may refer to
+// is known at this point, this catches any error that would otherwise only
+// surface when a Promotion to the Stage is executed.
+func (w *webhook) validateRenderedBranch(
+	f *field.Path,
+	stage *kargoapi.Stage,
+) field.ErrorList {
+	if stage.Spec.RenderedBranch == nil {
+		return nil
+	}
+	if _, err := kargo.ResolveRenderedBranch(stage); err != nil {
+		return field.ErrorList{
+			field.Invalid(f.Child("template"), stage.Spec.RenderedBranch.Template, err.Error()),
+		}
+	}
+	return nil
+}
+
+// validateOverlays makes sure the branch of each of the Stage's overlays, if
+// any, can be resolved, and that no two overlays share a name or a branch.
+func (w *webhook) validateOverlays(
+	f *field.Path,
+	stage *kargoapi.Stage,
+) field.ErrorList {
+	if len(stage.Spec.Overlays) == 0 {
+		return nil
+	}
+	if _, err := kargo.ResolveOverlays(stage); err != nil {
+		return field.ErrorList{field.Invalid(f, stage.Spec.Overlays, err.Error())}
+	}
+	return nil
+}
+
+// validateImageMappings makes sure each of the Stage's ImageMappings is well
+// formed and that no two of them could map references to different images to
+// the same image.
+func (w *webhook) validateImageMappings(
+	f *field.Path,
+	stage *kargoapi.Stage,
+) field.ErrorList {
+	mappings := stage.Spec.ImageMappings
+	var errs field.ErrorList
+	for i, mapping := range mappings {
+		if err := kargo.ValidateImageMapping(mapping); err != nil {
+			errs = append(errs, field.Invalid(f.Index(i), mapping, err.Error()))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	if i, j, conflicted := kargo.FindConflictingImageMappings(mappings); conflicted {
+		errs = append(errs, field.Invalid(
+			f.Index(j),
+			mappings[j],
+			fmt.Sprintf(
+				"mapping could map a different image to the same image as %s",
+				f.Index(i),
+			),
+		))
+	}
+	return errs
+}
+
+// validateMetadata makes sure the keys of the Stage's metadata are valid and
+// that neither any of its values nor the metadata as a whole is too large.
+func (w *webhook) validateMetadata(
+	f *field.Path,
+	stage *kargoapi.Stage,
+) field.ErrorList {
+	var errs field.ErrorList
+	var size int
+	for _, key := range slices.Sorted(maps.Keys(stage.Spec.Metadata)) {
+		value := stage.Spec.Metadata[key]
+		size += len(key) + len(value)
+		switch {
+		case len(key) > metadataKeyMaxLength:
+			errs = append(errs, field.TooLong(f.Key(key), key, metadataKeyMaxLength))
+		case !metadataKeyRegex.MatchString(key):
+			errs = append(errs, field.Invalid(
+				f.Key(key),
+				key,
+				"key must begin with a letter and may only contain letters, "+
+					"digits, '_', '-', and '.'",
+			))
+		}
+		if len(value) > metadataValueMaxLength {
+			errs = append(errs, field.TooLong(f.Key(key), value, metadataValueMaxLength))
+		}
+	}
+	if size > metadataMaxSize {
+		errs = append(errs, field.TooLong(f, "", metadataMaxSize))
+	}
+	return errs
+}
+
 func (w *webhook) validateSpec(
 	f *field.Path,
 	spec *kargoapi.StageSpec,
@@ -197,10 +338,30 @@ func (w *webhook) validateSpec(
 		return nil
 	}
 	errs := w.validateRequestedFreight(f.Child("requestedFreight"), spec.RequestedFreight)
-	return append(
+	errs = append(
 		errs,
 		w.ValidatePromotionTemplate(f.Child("promotionTemplate"), spec.PromotionTemplate)...,
 	)
+	return append(errs, w.validateArgoCDApps(f.Child("argoCDApps"), spec.ArgoCDApps)...)
+}
+
+// validateArgoCDApps makes sure the same Argo CD Application is not managed
+// more than once by a Stage.
+func (w *webhook) validateArgoCDApps(
+	f *field.Path,
+	apps []kargoapi.ManagedArgoCDApp,
+) field.ErrorList {
+	var errs field.ErrorList
+	seen := make(map[types.NamespacedName]struct{}, len(apps))
+	for i, app := range apps {
+		key := types.NamespacedName{Namespace: app.Namespace, Name: app.Name}
+		if _, ok := seen[key]; ok {
+			errs = append(errs, field.Duplicate(f.Index(i).Child("name"), app.Name))
+			continue
+		}
+		seen[key] = struct{}{}
+	}
+	return errs
 }
 
 func (w *webhook) validateRequestedFreight(
@@ -228,6 +389,179 @@ func (w *webhook) validateRequestedFreight(
 	return nil
 }
 
+// validateUpstreamStages returns an error if the Stage, either directly or by
+// way of other Stages, requests Freight from itself. Upstream Stages that do
+// not exist (yet) are ignored.
+func (w *webhook) validateUpstreamStages(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) error {
+	f := field.NewPath("spec", "requestedFreight")
+	for i, req := range stage.Spec.RequestedFreight {
+		cycle, err := w.findUpstreamCycle(
+			ctx,
+			stage,
+			req.Origin,
+			req.Sources.Stages,
+			[]string{stage.Name},
+			map[string]struct{}{},
+		)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if cycle != nil {
+			return apierrors.NewInvalid(
+				stageGroupKind,
+				stage.Name,
+				field.ErrorList{
+					field.Invalid(
+						f.Index(i).Child("sources", "stages"),
+						req.Sources.Stages,
+						fmt.Sprintf(
+							"freight with origin %s would be requested in a cycle: %s",
+							req.Origin.String(),
+							strings.Join(cycle, " -> "),
+						),
+					),
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// validateRenderedBranchSources returns an error if manifests rendered for the
+// Stage would be written to a branch that a Warehouse the Stage requests
+// Freight from subscribes to. Committing rendered manifests to the branch they
+// are rendered from would overwrite their source and, in turn, produce new
+// Freight with every Promotion. Warehouses that do not exist (yet) are
+// ignored.
+func (w *webhook) validateRenderedBranchSources(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+) error {
+	renderedBranch, err := kargo.ResolveRenderedBranch(stage)
+	if err != nil {
+		// This is caught by validateRenderedBranch
+		return nil
+	}
+	overlays, err := kargo.ResolveOverlays(stage)
+	if err != nil {
+		// This is caught by validateOverlays
+		return nil
+	}
+	// Map each rendered branch to the field it is derived from
+	f := field.NewPath("spec")
+	renderedBranches := make(map[string]*field.Path, len(overlays)+1)
+	if renderedBranch != "" {
+		renderedBranches[renderedBranch] = f.Child("renderedBranch", "template")
+	}
+	for i, overlay := range overlays {
+		if stage.Spec.Overlays[i].RenderedBranch != "" {
+			renderedBranches[overlay.RenderedBranch] = f.Child("overlays").Index(i).Child("renderedBranch")
+		} else {
+			renderedBranches[overlay.RenderedBranch] = f.Child("renderedBranch", "template")
+		}
+	}
+	if len(renderedBranches) == 0 {
+		return nil
+	}
+	for _, req := range stage.Spec.RequestedFreight {
+		if req.Origin.Kind != kargoapi.FreightOriginKindWarehouse {
+			continue
+		}
+		warehouse, err := kargoapi.GetWarehouse(
+			ctx,
+			w.client,
+			types.NamespacedName{Namespace: stage.Namespace, Name: req.Origin.Name},
+		)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if warehouse == nil {
+			continue
+		}
+		for _, sub := range warehouse.Spec.Subscriptions {
+			if sub.Git == nil || sub.Git.Branch == "" {
+				continue
+			}
+			branchPath, ok := renderedBranches[sub.Git.Branch]
+			if !ok {
+				continue
+			}
+			return apierrors.NewInvalid(
+				stageGroupKind,
+				stage.Name,
+				field.ErrorList{
+					field.Invalid(
+						branchPath,
+						sub.Git.Branch,
+						fmt.Sprintf(
+							"manifests would be rendered into branch %q of repository %s, "+
+								"which Warehouse %q subscribes to as a source branch",
+							sub.Git.Branch,
+							sub.Git.RepoURL,
+							warehouse.Name,
+						),
+					),
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// findUpstreamCycle performs a depth-first search of the Stages upstream of
+// the provided Stage for the specified origin. If the provided Stage is found
+// among them, the path leading back to it is returned.
+func (w *webhook) findUpstreamCycle(
+	ctx context.Context,
+	stage *kargoapi.Stage,
+	origin kargoapi.FreightOrigin,
+	upstreamStages []string,
+	path []string,
+	visited map[string]struct{},
+) ([]string, error) {
+	for _, upstreamName := range upstreamStages {
+		upstreamPath := append(slices.Clone(path), upstreamName)
+		if upstreamName == stage.Name {
+			return upstreamPath, nil
+		}
+		if _, ok := visited[upstreamName]; ok {
+			continue
+		}
+		visited[upstreamName] = struct{}{}
+		upstream, err := kargoapi.GetStage(
+			ctx,
+			w.client,
+			types.NamespacedName{Namespace: stage.Namespace, Name: upstreamName},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if upstream == nil {
+			continue
+		}
+		for _, req := range upstream.Spec.RequestedFreight {
+			if !req.Origin.Equals(&origin) {
+				continue
+			}
+			cycle, err := w.findUpstreamCycle(
+				ctx,
+				stage,
+				origin,
+				req.Sources.Stages,
+				upstreamPath,
+				visited,
+			)
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+	}
+	return nil, nil
+}
+
 func (w *webhook) ValidatePromotionTemplate(
 	f *field.Path,
 	promoTemplate *kargoapi.PromotionTemplate,
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -32,6 +34,8 @@ func TestNewWebhook(t *testing.T) {
 	require.NotNil(t, w.admissionRequestFromContextFn)
 	require.NotNil(t, w.validateProjectFn)
 	require.NotNil(t, w.validateCreateOrUpdateFn)
+	require.NotNil(t, w.validateUpstreamStagesFn)
+	require.NotNil(t, w.validateRenderedBranchSourcesFn)
 	require.NotNil(t, w.validateSpecFn)
 	require.NotNil(t, w.isRequestFromKargoControlplaneFn)
 }
@@ -878,6 +882,59 @@ func TestValidateCreate(t *testing.T) {
 				require.Equal(t, "something went wrong", err.Error())
 			},
 		},
+		{
+			name: "error validating upstream stages",
+			webhook: &webhook{
+				validateProjectFn: func(
+					context.Context,
+					client.Client,
+					schema.GroupKind,
+					client.Object,
+				) error {
+					return nil
+				},
+				validateCreateOrUpdateFn: func(
+					*kargoapi.Stage,
+				) (admission.Warnings, error) {
+					return nil, nil
+				},
+				validateUpstreamStagesFn: func(context.Context, *kargoapi.Stage) error {
+					return errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
+		{
+			name: "error validating rendered branch sources",
+			webhook: &webhook{
+				validateProjectFn: func(
+					context.Context,
+					client.Client,
+					schema.GroupKind,
+					client.Object,
+				) error {
+					return nil
+				},
+				validateCreateOrUpdateFn: func(
+					*kargoapi.Stage,
+				) (admission.Warnings, error) {
+					return nil, nil
+				},
+				validateUpstreamStagesFn: func(context.Context, *kargoapi.Stage) error {
+					return nil
+				},
+				validateRenderedBranchSourcesFn: func(context.Context, *kargoapi.Stage) error {
+					return errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
 		{
 			name: "success",
 			webhook: &webhook{
@@ -894,6 +951,12 @@ func TestValidateCreate(t *testing.T) {
 				) (admission.Warnings, error) {
 					return nil, nil
 				},
+				validateUpstreamStagesFn: func(context.Context, *kargoapi.Stage) error {
+					return nil
+				},
+				validateRenderedBranchSourcesFn: func(context.Context, *kargoapi.Stage) error {
+					return nil
+				},
 			},
 			assertions: func(t *testing.T, err error) {
 				require.NoError(t, err)
@@ -931,6 +994,43 @@ func TestValidateUpdate(t *testing.T) {
 				require.Equal(t, "something went wrong", err.Error())
 			},
 		},
+		{
+			name: "error validating upstream stages",
+			webhook: &webhook{
+				validateCreateOrUpdateFn: func(
+					*kargoapi.Stage,
+				) (admission.Warnings, error) {
+					return nil, nil
+				},
+				validateUpstreamStagesFn: func(context.Context, *kargoapi.Stage) error {
+					return errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
+		{
+			name: "error validating rendered branch sources",
+			webhook: &webhook{
+				validateCreateOrUpdateFn: func(
+					*kargoapi.Stage,
+				) (admission.Warnings, error) {
+					return nil, nil
+				},
+				validateUpstreamStagesFn: func(context.Context, *kargoapi.Stage) error {
+					return nil
+				},
+				validateRenderedBranchSourcesFn: func(context.Context, *kargoapi.Stage) error {
+					return errors.New("something went wrong")
+				},
+			},
+			assertions: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.Equal(t, "something went wrong", err.Error())
+			},
+		},
 		{
 			name: "success",
 			webhook: &webhook{
@@ -939,6 +1039,12 @@ func TestValidateUpdate(t *testing.T) {
 				) (admission.Warnings, error) {
 					return nil, nil
 				},
+				validateUpstreamStagesFn: func(context.Context, *kargoapi.Stage) error {
+					return nil
+				},
+				validateRenderedBranchSourcesFn: func(context.Context, *kargoapi.Stage) error {
+					return nil
+				},
 			},
 			assertions: func(t *testing.T, err error) {
 				require.NoError(t, err)
@@ -1092,6 +1198,160 @@ func TestValidateSpec(t *testing.T) {
 	}
 }
 
+func TestValidateArgoCDApps(t *testing.T) {
+	w := &webhook{}
+	require.Nil(
+		t,
+		w.validateArgoCDApps(
+			field.NewPath("argoCDApps"),
+			[]kargoapi.ManagedArgoCDApp{
+				{Name: "app-a"},
+				{Name: "app-b"},
+				{Name: "app-a", Namespace: "other"},
+			},
+		),
+	)
+	require.Equal(
+		t,
+		field.ErrorList{
+			field.Duplicate(field.NewPath("argoCDApps").Index(1).Child("name"), "app-a"),
+		},
+		w.validateArgoCDApps(
+			field.NewPath("argoCDApps"),
+			[]kargoapi.ManagedArgoCDApp{
+				{Name: "app-a"},
+				{Name: "app-a"},
+			},
+		),
+	)
+}
+
+func TestValidateRenderedBranch(t *testing.T) {
+	w := &webhook{}
+	stage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "prod",
+		},
+	}
+	require.Nil(t, w.validateRenderedBranch(field.NewPath("renderedBranch"), stage))
+
+	stage.Spec.RenderedBranch = &kargoapi.RenderedBranch{
+		Template: "rendered/{{ .Stage }}/{{ .Region }}",
+		Region:   "us-east-1",
+	}
+	require.Nil(t, w.validateRenderedBranch(field.NewPath("renderedBranch"), stage))
+
+	stage.Spec.RenderedBranch.Region = ""
+	errs := w.validateRenderedBranch(field.NewPath("renderedBranch"), stage)
+	require.Len(t, errs, 1)
+	require.Equal(t, "renderedBranch.template", errs[0].Field)
+	require.Contains(t, errs[0].Detail, "invalid branch name")
+
+	stage.Spec.RenderedBranch.Template = "rendered/{{ .Stage"
+	errs = w.validateRenderedBranch(field.NewPath("renderedBranch"), stage)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Detail, "error parsing rendered branch template")
+}
+
+func TestValidateOverlays(t *testing.T) {
+	w := &webhook{}
+	stage := &kargoapi.Stage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "fake-project",
+			Name:      "prod",
+		},
+	}
+	require.Nil(t, w.validateOverlays(field.NewPath("overlays"), stage))
+
+	stage.Spec.RenderedBranch = &kargoapi.RenderedBranch{
+		Template: "rendered/{{ .Stage }}/{{ .Region }}",
+	}
+	stage.Spec.Overlays = []kargoapi.StageOverlay{
+		{Name: "us-east", Path: "overlays/us-east"},
+		{Name: "eu-west", Path: "overlays/eu-west"},
+	}
+	require.Nil(t, w.validateOverlays(field.NewPath("overlays"), stage))
+	// The template is only resolved per overlay, so the empty Region is fine.
+	require.Nil(t, w.validateRenderedBranch(field.NewPath("renderedBranch"), stage))
+
+	stage.Spec.Overlays[1].RenderedBranch = "rendered/prod/us-east"
+	errs := w.validateOverlays(field.NewPath("overlays"), stage)
+	require.Len(t, errs, 1)
+	require.Equal(t, "overlays", errs[0].Field)
+	require.Contains(t, errs[0].Detail, "are both rendered into branch")
+}
+
+func TestValidateImageMappings(t *testing.T) {
+	w := &webhook{}
+	stage := &kargoapi.Stage{}
+	require.Nil(t, w.validateImageMappings(field.NewPath("imageMappings"), stage))
+
+	stage.Spec.ImageMappings = []kargoapi.ImageMapping{
+		{
+			RepoURL:    "registry.dev.example.com/legacy-app",
+			NewRepoURL: "registry.prod.example.com/app",
+			TagSuffix:  "-legacy",
+		},
+		{
+			RepoURL:    "registry.dev.example.com/*",
+			NewRepoURL: "registry.prod.example.com/*",
+			TagSuffix:  "-prod",
+		},
+	}
+	require.Nil(t, w.validateImageMappings(field.NewPath("imageMappings"), stage))
+
+	stage.Spec.ImageMappings[0].NewRepoURL = "registry.prod.example.com/*"
+	errs := w.validateImageMappings(field.NewPath("imageMappings"), stage)
+	require.Len(t, errs, 1)
+	require.Equal(t, "imageMappings[0]", errs[0].Field)
+	require.Contains(t, errs[0].Detail, "if and only if")
+
+	stage.Spec.ImageMappings[0].NewRepoURL = "registry.prod.example.com/app"
+	stage.Spec.ImageMappings[0].TagSuffix = "-legacy-prod"
+	errs = w.validateImageMappings(field.NewPath("imageMappings"), stage)
+	require.Len(t, errs, 1)
+	require.Equal(t, "imageMappings[1]", errs[0].Field)
+	require.Contains(t, errs[0].Detail, "same image as imageMappings[0]")
+}
+
+func TestValidateMetadata(t *testing.T) {
+	w := &webhook{}
+	stage := &kargoapi.Stage{}
+	require.Nil(t, w.validateMetadata(field.NewPath("metadata"), stage))
+
+	stage.Spec.Metadata = map[string]string{
+		"owningTeam":     "payments",
+		"slack.channel":  "#payments-deploys",
+		"change-mgmt_id": "CHG-123",
+	}
+	require.Nil(t, w.validateMetadata(field.NewPath("metadata"), stage))
+
+	stage.Spec.Metadata = map[string]string{
+		"1team":                 "payments",
+		"owner team":            "payments",
+		strings.Repeat("k", 64): "value",
+		"description":           strings.Repeat("v", 257),
+	}
+	errs := w.validateMetadata(field.NewPath("metadata"), stage)
+	require.Len(t, errs, 4)
+	require.Equal(t, "metadata[1team]", errs[0].Field)
+	require.Contains(t, errs[0].Detail, "must begin with a letter")
+	require.Equal(t, "metadata[description]", errs[1].Field)
+	require.Equal(t, field.ErrorTypeTooLong, errs[1].Type)
+	require.Equal(t, field.ErrorTypeTooLong, errs[2].Type)
+	require.Equal(t, "metadata[owner team]", errs[3].Field)
+
+	stage.Spec.Metadata = map[string]string{}
+	for i := 0; i < 20; i++ {
+		stage.Spec.Metadata[fmt.Sprintf("key%d", i)] = strings.Repeat("v", 256)
+	}
+	errs = w.validateMetadata(field.NewPath("metadata"), stage)
+	require.Len(t, errs, 1)
+	require.Equal(t, "metadata", errs[0].Field)
+	require.Equal(t, field.ErrorTypeTooLong, errs[0].Type)
+}
+
 func TestValidateRequestedFreight(t *testing.T) {
 	testFreightRequest := kargoapi.FreightRequest{
 		Origin: kargoapi.FreightOrigin{
@@ -1152,6 +1412,216 @@ func TestValidateRequestedFreight(t *testing.T) {
 	}
 }
 
+func TestValidateUpstreamStages(t *testing.T) {
+	const testNamespace = "fake-namespace"
+	testOrigin := kargoapi.FreightOrigin{
+		Kind: kargoapi.FreightOriginKindWarehouse,
+		Name: "fake-warehouse",
+	}
+	otherOrigin := kargoapi.FreightOrigin{
+		Kind: kargoapi.FreightOriginKindWarehouse,
+		Name: "other-warehouse",
+	}
+	newStage := func(name string, origin kargoapi.FreightOrigin, upstream ...string) *kargoapi.Stage {
+		return &kargoapi.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      name,
+			},
+			Spec: kargoapi.StageSpec{
+				RequestedFreight: []kargoapi.FreightRequest{{
+					Origin: origin,
+					Sources: kargoapi.FreightSources{
+						Direct: len(upstream) == 0,
+						Stages: upstream,
+					},
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		stage      *kargoapi.Stage
+		objects    []client.Object
+		assertions func(*testing.T, error)
+	}{
+		{
+			name:  "no upstream stages",
+			stage: newStage("a", testOrigin),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "upstream stage does not exist",
+			stage: newStage("a", testOrigin, "b"),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "requests freight from itself",
+			stage: newStage("a", testOrigin, "a"),
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "cycle: a -> a")
+			},
+		},
+		{
+			name:  "fan-in without cycle",
+			stage: newStage("c", testOrigin, "a", "b"),
+			objects: []client.Object{
+				newStage("a", testOrigin),
+				newStage("b", testOrigin, "a"),
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "indirect cycle",
+			stage: newStage("a", testOrigin, "c"),
+			objects: []client.Object{
+				newStage("b", testOrigin, "a"),
+				newStage("c", testOrigin, "b"),
+			},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "cycle: a -> c -> b -> a")
+			},
+		},
+		{
+			name:  "cycle for a different origin",
+			stage: newStage("a", testOrigin, "b"),
+			objects: []client.Object{
+				newStage("b", otherOrigin, "a"),
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, kargoapi.AddToScheme(scheme))
+			w := &webhook{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(testCase.objects...).
+					Build(),
+			}
+			testCase.assertions(
+				t,
+				w.validateUpstreamStages(context.Background(), testCase.stage),
+			)
+		})
+	}
+}
+
+func TestValidateRenderedBranchSources(t *testing.T) {
+	const testNamespace = "fake-namespace"
+	testOrigin := kargoapi.FreightOrigin{
+		Kind: kargoapi.FreightOriginKindWarehouse,
+		Name: "fake-warehouse",
+	}
+	newWarehouse := func(branch string) *kargoapi.Warehouse {
+		return &kargoapi.Warehouse{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      testOrigin.Name,
+			},
+			Spec: kargoapi.WarehouseSpec{
+				Subscriptions: []kargoapi.RepoSubscription{{
+					Git: &kargoapi.GitSubscription{
+						RepoURL: "https://github.com/example/repo.git",
+						Branch:  branch,
+					},
+				}},
+			},
+		}
+	}
+	newStage := func(overlays ...kargoapi.StageOverlay) *kargoapi.Stage {
+		return &kargoapi.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      "dev",
+			},
+			Spec: kargoapi.StageSpec{
+				RequestedFreight: []kargoapi.FreightRequest{{
+					Origin:  testOrigin,
+					Sources: kargoapi.FreightSources{Direct: true},
+				}},
+				RenderedBranch: &kargoapi.RenderedBranch{
+					Template: "stage/{{ .Stage }}",
+				},
+				Overlays: overlays,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		stage      *kargoapi.Stage
+		objects    []client.Object
+		assertions func(*testing.T, error)
+	}{
+		{
+			name:  "warehouse does not exist",
+			stage: newStage(),
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "warehouse subscribes to a different branch",
+			stage:   newStage(),
+			objects: []client.Object{newWarehouse("main")},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "warehouse subscribes to the rendered branch",
+			stage:   newStage(),
+			objects: []client.Object{newWarehouse("stage/dev")},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "spec.renderedBranch.template")
+				require.ErrorContains(
+					t, err,
+					`Warehouse "fake-warehouse" subscribes to as a source branch`,
+				)
+			},
+		},
+		{
+			name: "warehouse subscribes to the rendered branch of an overlay",
+			stage: newStage(
+				kargoapi.StageOverlay{Name: "us-east", Path: "overlays/us-east"},
+				kargoapi.StageOverlay{Name: "eu-west", Path: "overlays/eu-west", RenderedBranch: "main"},
+			),
+			objects: []client.Object{newWarehouse("main")},
+			assertions: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "spec.overlays[1].renderedBranch")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, kargoapi.AddToScheme(scheme))
+			w := &webhook{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(testCase.objects...).
+					Build(),
+			}
+			testCase.assertions(
+				t,
+				w.validateRenderedBranchSources(context.Background(), testCase.stage),
+			)
+		})
+	}
+}
+
 func TestValidatePromotionTemplate(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -221,6 +221,9 @@ func (w *webhook) validateImageSub(
 	if err := seen.addImage(sub, f); err != nil {
 		errs = append(errs, field.Invalid(f, sub.RepoURL, err.Error()))
 	}
+	if err := seen.addImageName(sub, f); err != nil {
+		errs = append(errs, field.Invalid(f.Child("name"), sub.Name, err.Error()))
+	}
 	return errs
 }
 
@@ -304,6 +307,18 @@ func (s uniqueSubSet) addImage(sub kargoapi.ImageSubscription, p *field.Path) er
 	return nil
 }
 
+func (s uniqueSubSet) addImageName(sub kargoapi.ImageSubscription, p *field.Path) error {
+	if sub.Name == "" {
+		return nil
+	}
+	k := subscriptionKey{kind: "image-name", id: sub.Name}
+	if _, exists := s[k]; exists {
+		return fmt.Errorf("image subscription named %q already exists at %q", sub.Name, s[k])
+	}
+	s[k] = p
+	return nil
+}
+
 func (s uniqueSubSet) addChart(sub kargoapi.ChartSubscription, isHTTP bool, p *field.Path) error {
 	k := subscriptionKey{kind: "chart", id: helm.NormalizeChartRepositoryURL(sub.RepoURL)}
 	if isHTTP {
@@ -630,7 +630,33 @@ func TestValidateImageSub(t *testing.T) {
 				)
 			},
 		},
-
+		{
+			name: "duplicate name",
+			sub: kargoapi.ImageSubscription{
+				Name:    "api",
+				RepoURL: "example/api-v2",
+			},
+			seen: uniqueSubSet{
+				subscriptionKey{
+					kind: "image-name",
+					id:   "api",
+				}: field.NewPath("spec.subscriptions[0].image"),
+			},
+			assertions: func(t *testing.T, errs field.ErrorList) {
+				require.Equal(
+					t,
+					field.ErrorList{
+						{
+							Type:     field.ErrorTypeInvalid,
+							Field:    "image.name",
+							BadValue: "api",
+							Detail:   "image subscription named \"api\" already exists at \"spec.subscriptions[0].image\"",
+						},
+					},
+					errs,
+				)
+			},
+		},
 		{
 			name: "valid",
 			seen: uniqueSubSet{},
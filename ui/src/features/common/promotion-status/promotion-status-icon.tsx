@@ -3,6 +3,8 @@ import {
   faCircleCheck,
   faCircleExclamation,
   faCircleNotch,
+  faForwardStep,
+  faHourglassEnd,
   faHourglassStart
 } from '@fortawesome/free-solid-svg-icons';
 import { theme } from 'antd';
@@ -51,6 +53,12 @@ export const PromotionStatusIcon = ({
     case 'Aborted':
       icon = faCancel;
       break;
+    case 'Skipped':
+      icon = faForwardStep;
+      break;
+    case 'Expired':
+      icon = faHourglassEnd;
+      break;
     case 'Pending':
     default:
       break;
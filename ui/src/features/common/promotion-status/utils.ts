@@ -7,7 +7,9 @@ export enum PromotionStatusPhase {
   SUCCEEDED = 'Succeeded',
   FAILED = 'Failed',
   ERRORED = 'Errored',
-  ABORTED = 'Aborted'
+  ABORTED = 'Aborted',
+  SKIPPED = 'Skipped',
+  EXPIRED = 'Expired'
 }
 
 export const getPromotionStatusPhase = (promotion: Promotion) =>
@@ -20,6 +22,8 @@ export const isPromotionPhaseTerminal = (promotionPhase: PromotionStatusPhase) =
     case PromotionStatusPhase.FAILED:
     case PromotionStatusPhase.ERRORED:
     case PromotionStatusPhase.ABORTED:
+    case PromotionStatusPhase.SKIPPED:
+    case PromotionStatusPhase.EXPIRED:
       return true;
   }
 
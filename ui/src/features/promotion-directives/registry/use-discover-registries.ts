@@ -1,14 +1,17 @@
 import { JSONSchema7 } from 'json-schema';
 
 // IMPORTANT(Marvin9): this must be replaced with proper discovery mechanism
+import argocdDryRunConfig from '@ui/gen/directives/argocd-dry-run-config.json';
 import argocdUpdateConfig from '@ui/gen/directives/argocd-update-config.json';
 import copyConfig from '@ui/gen/directives/copy-config.json';
 import deleteConfig from '@ui/gen/directives/delete-config.json';
+import execRenderConfig from '@ui/gen/directives/exec-render-config.json';
 import gitOverwriteConfig from '@ui/gen/directives/git-clear-config.json';
 import gitCloneConfig from '@ui/gen/directives/git-clone-config.json';
 import gitCommitConfig from '@ui/gen/directives/git-commit-config.json';
 import gitOpenPR from '@ui/gen/directives/git-open-pr-config.json';
 import gitPushConfig from '@ui/gen/directives/git-push-config.json';
+import gitVerifyCommitConfig from '@ui/gen/directives/git-verify-commit-config.json';
 import gitWaitForPR from '@ui/gen/directives/git-wait-for-pr-config.json';
 import helmTemplateConfig from '@ui/gen/directives/helm-template-config.json';
 import helmUpdateChartConfig from '@ui/gen/directives/helm-update-chart-config.json';
@@ -16,7 +19,10 @@ import helmUpdateImageConfig from '@ui/gen/directives/helm-update-image-config.j
 import httpConfig from '@ui/gen/directives/http-config.json';
 import jsonUpdateConfig from '@ui/gen/directives/json-update-config.json';
 import kustomizeBuildConfig from '@ui/gen/directives/kustomize-build-config.json';
+import kustomizePromoteOverlaysConfig from '@ui/gen/directives/kustomize-promote-overlays-config.json';
 import kustomizeSetImageConfig from '@ui/gen/directives/kustomize-set-image-config.json';
+import runJobConfig from '@ui/gen/directives/run-job-config.json';
+import setMetadataConfig from '@ui/gen/directives/set-metadata-config.json';
 import yamlUpdateConfig from '@ui/gen/directives/yaml-update-config.json';
 
 import { PromotionDirectivesRegistry } from './types';
@@ -27,6 +33,10 @@ export const useDiscoverPromotionDirectivesRegistries = (): PromotionDirectivesR
   // when we actually starts accepting external promotion directives registry, this must be the only place to care about
   const registry: PromotionDirectivesRegistry = {
     runners: [
+      {
+        identifier: 'argocd-dry-run',
+        config: argocdDryRunConfig as JSONSchema7
+      },
       {
         identifier: 'argocd-update',
         config: argocdUpdateConfig as JSONSchema7
@@ -40,6 +50,10 @@ export const useDiscoverPromotionDirectivesRegistries = (): PromotionDirectivesR
         unstable_icons: [],
         config: deleteConfig as JSONSchema7
       },
+      {
+        identifier: 'exec-render',
+        config: execRenderConfig as JSONSchema7
+      },
       {
         identifier: 'git-clone',
         config: gitCloneConfig as JSONSchema7
@@ -56,6 +70,10 @@ export const useDiscoverPromotionDirectivesRegistries = (): PromotionDirectivesR
         identifier: 'git-open-pr',
         config: gitOpenPR as unknown as JSONSchema7
       },
+      {
+        identifier: 'git-verify-commit',
+        config: gitVerifyCommitConfig as JSONSchema7
+      },
       {
         identifier: 'git-wait-for-pr',
         config: gitWaitForPR as unknown as JSONSchema7
@@ -92,10 +110,22 @@ export const useDiscoverPromotionDirectivesRegistries = (): PromotionDirectivesR
         identifier: 'kustomize-build',
         config: kustomizeBuildConfig as JSONSchema7
       },
+      {
+        identifier: 'kustomize-promote-overlays',
+        config: kustomizePromoteOverlaysConfig as JSONSchema7
+      },
       {
         identifier: 'kustomize-set-image',
         config: kustomizeSetImageConfig as JSONSchema7
       },
+      {
+        identifier: 'run-job',
+        config: runJobConfig as JSONSchema7
+      },
+      {
+        identifier: 'set-metadata',
+        config: setMetadataConfig as JSONSchema7
+      },
       {
         identifier: 'http',
         config: httpConfig as JSONSchema7
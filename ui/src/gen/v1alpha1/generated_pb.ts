@@ -8,6 +8,8 @@ import type { GenFile, GenMessage } from "@bufbuild/protobuf/codegenv1";
 import { fileDesc, messageDesc } from "@bufbuild/protobuf/codegenv1";
 import type { JSON } from "../k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1/generated_pb";
 import { file_k8s_io_apiextensions_apiserver_pkg_apis_apiextensions_v1_generated } from "../k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1/generated_pb";
+import type { Quantity } from "../k8s.io/apimachinery/pkg/api/resource/generated_pb";
+import { file_k8s_io_apimachinery_pkg_api_resource_generated } from "../k8s.io/apimachinery/pkg/api/resource/generated_pb";
 import type { Condition, Duration, ListMeta, ObjectMeta, Time } from "../k8s.io/apimachinery/pkg/apis/meta/v1/generated_pb";
 import { file_k8s_io_apimachinery_pkg_apis_meta_v1_generated } from "../k8s.io/apimachinery/pkg/apis/meta/v1/generated_pb";
 import { file_k8s_io_apimachinery_pkg_runtime_generated } from "../k8s.io/apimachinery/pkg/runtime/generated_pb";
@@ -18,7 +20,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file v1alpha1/generated.proto.
  */
 export const file_v1alpha1_generated: GenFile = /*@__PURE__*/
-  fileDesc("Chh2MWFscGhhMS9nZW5lcmF0ZWQucHJvdG8SJGdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMSIyChNBbmFseXNpc1J1bkFyZ3VtZW50EgwKBG5hbWUYASABKAkSDQoFdmFsdWUYAiABKAkisAIKE0FuYWx5c2lzUnVuTWV0YWRhdGESVQoGbGFiZWxzGAEgAygLMkUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkFuYWx5c2lzUnVuTWV0YWRhdGEuTGFiZWxzRW50cnkSXwoLYW5ub3RhdGlvbnMYAiADKAsySi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQW5hbHlzaXNSdW5NZXRhZGF0YS5Bbm5vdGF0aW9uc0VudHJ5Gi0KC0xhYmVsc0VudHJ5EgsKA2tleRgBIAEoCRINCgV2YWx1ZRgCIAEoCToCOAEaMgoQQW5ub3RhdGlvbnNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBIkYKFEFuYWx5c2lzUnVuUmVmZXJlbmNlEhEKCW5hbWVzcGFjZRgBIAEoCRIMCgRuYW1lGAIgASgJEg0KBXBoYXNlGAMgASgJIikKGUFuYWx5c2lzVGVtcGxhdGVSZWZlcmVuY2USDAoEbmFtZRgBIAEoCSJPCg1BcHByb3ZlZFN0YWdlEj4KCmFwcHJvdmVkQXQYASABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZSI4ChVBcmdvQ0RBcHBIZWFsdGhTdGF0dXMSDgoGc3RhdHVzGAEgASgJEg8KB21lc3NhZ2UYAiABKAki1AEKD0FyZ29DREFwcFN0YXR1cxIRCgluYW1lc3BhY2UYASABKAkSDAoEbmFtZRgCIAEoCRJRCgxoZWFsdGhTdGF0dXMYAyABKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQXJnb0NEQXBwSGVhbHRoU3RhdHVzEk0KCnN5bmNTdGF0dXMYBCABKAsyOS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQXJnb0NEQXBwU3luY1N0YXR1cyJKChNBcmdvQ0RBcHBTeW5jU3RhdHVzEg4KBnN0YXR1cxgBIAEoCRIQCghyZXZpc2lvbhgCIAEoCRIRCglyZXZpc2lvbnMYAyADKAkiNwoFQ2hhcnQSDwoHcmVwb1VSTBgBIAEoCRIMCgRuYW1lGAIgASgJEg8KB3ZlcnNpb24YAyABKAkiYQoUQ2hhcnREaXNjb3ZlcnlSZXN1bHQSDwoHcmVwb1VSTBgBIAEoCRIMCgRuYW1lGAIgASgJEhgKEHNlbXZlckNvbnN0cmFpbnQYAyABKAkSEAoIdmVyc2lvbnMYBCADKAkiZAoRQ2hhcnRTdWJzY3JpcHRpb24SDwoHcmVwb1VSTBgBIAEoCRIMCgRuYW1lGAIgASgJEhgKEHNlbXZlckNvbnN0cmFpbnQYAyABKAkSFgoOZGlzY292ZXJ5TGltaXQYBCABKAUioQEKFENsdXN0ZXJQcm9tb3Rpb25UYXNrEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESRQoEc3BlYxgCIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25UYXNrU3BlYyKnAQoYQ2x1c3RlclByb21vdGlvblRhc2tMaXN0EkAKCG1ldGFkYXRhGAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkxpc3RNZXRhEkkKBWl0ZW1zGAIgAygLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkNsdXN0ZXJQcm9tb3Rpb25UYXNrIkkKDEN1cnJlbnRTdGFnZRI5CgVzaW5jZRgBIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIrYCChNEaXNjb3ZlcmVkQXJ0aWZhY3RzEkAKDGRpc2NvdmVyZWRBdBgEIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lEkUKA2dpdBgBIAMoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5HaXREaXNjb3ZlcnlSZXN1bHQSSgoGaW1hZ2VzGAIgAygLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkltYWdlRGlzY292ZXJ5UmVzdWx0EkoKBmNoYXJ0cxgDIAMoCzI6LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5DaGFydERpc2NvdmVyeVJlc3VsdCKwAQoQRGlzY292ZXJlZENvbW1pdBIKCgJpZBgBIAEoCRIOCgZicmFuY2gYAiABKAkSCwoDdGFnGAMgASgJEg8KB3N1YmplY3QYBCABKAkSDgoGYXV0aG9yGAUgASgJEhEKCWNvbW1pdHRlchgGIAEoCRI/CgtjcmVhdG9yRGF0ZRgHIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIooBChhEaXNjb3ZlcmVkSW1hZ2VSZWZlcmVuY2USCwoDdGFnGAEgASgJEg4KBmRpZ2VzdBgCIAEoCRISCgpnaXRSZXBvVVJMGAMgASgJEj0KCWNyZWF0ZWRBdBgEIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIqIDCgdGcmVpZ2h0EkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESDQoFYWxpYXMYByABKAkSQwoGb3JpZ2luGAkgASgLMjMuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRPcmlnaW4SQAoHY29tbWl0cxgDIAMoCzIvLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5HaXRDb21taXQSOwoGaW1hZ2VzGAQgAygLMisuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkltYWdlEjsKBmNoYXJ0cxgFIAMoCzIrLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5DaGFydBJDCgZzdGF0dXMYBiABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFN0YXR1cyKtAgoRRnJlaWdodENvbGxlY3Rpb24SCgoCaWQYAyABKAkSUQoFaXRlbXMYASADKAsyQi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodENvbGxlY3Rpb24uSXRlbXNFbnRyeRJTChN2ZXJpZmljYXRpb25IaXN0b3J5GAIgAygLMjYuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlZlcmlmaWNhdGlvbkluZm8aZAoKSXRlbXNFbnRyeRILCgNrZXkYASABKAkSRQoFdmFsdWUYAiABKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFJlZmVyZW5jZToCOAEijQEKC0ZyZWlnaHRMaXN0EkAKCG1ldGFkYXRhGAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkxpc3RNZXRhEjwKBWl0ZW1zGAIgAygLMi0uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHQiKwoNRnJlaWdodE9yaWdpbhIMCgRraW5kGAEgASgJEgwKBG5hbWUYAiABKAkioQIKEEZyZWlnaHRSZWZlcmVuY2USDAoEbmFtZRgBIAEoCRJDCgZvcmlnaW4YCCABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodE9yaWdpbhJACgdjb21taXRzGAIgAygLMi8uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkdpdENvbW1pdBI7CgZpbWFnZXMYAyADKAsyKy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSW1hZ2USOwoGY2hhcnRzGAQgAygLMisuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkNoYXJ0IpwBCg5GcmVpZ2h0UmVxdWVzdBJDCgZvcmlnaW4YASABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodE9yaWdpbhJFCgdzb3VyY2VzGAIgASgLMjQuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRTb3VyY2VzInoKDkZyZWlnaHRTb3VyY2VzEg4KBmRpcmVjdBgBIAEoCBIOCgZzdGFnZXMYAiADKAkSSAoQcmVxdWlyZWRTb2FrVGltZRgDIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5EdXJhdGlvbiLXBAoNRnJlaWdodFN0YXR1cxJZCgtjdXJyZW50bHlJbhgDIAMoCzJELmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0U3RhdHVzLkN1cnJlbnRseUluRW50cnkSVwoKdmVyaWZpZWRJbhgBIAMoCzJDLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0U3RhdHVzLlZlcmlmaWVkSW5FbnRyeRJZCgthcHByb3ZlZEZvchgCIAMoCzJELmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0U3RhdHVzLkFwcHJvdmVkRm9yRW50cnkaZgoQQ3VycmVudGx5SW5FbnRyeRILCgNrZXkYASABKAkSQQoFdmFsdWUYAiABKAsyMi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQ3VycmVudFN0YWdlOgI4ARpmCg9WZXJpZmllZEluRW50cnkSCwoDa2V5GAEgASgJEkIKBXZhbHVlGAIgASgLMjMuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlZlcmlmaWVkU3RhZ2U6AjgBGmcKEEFwcHJvdmVkRm9yRW50cnkSCwoDa2V5GAEgASgJEkIKBXZhbHVlGAIgASgLMjMuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkFwcHJvdmVkU3RhZ2U6AjgBInkKCUdpdENvbW1pdBIPCgdyZXBvVVJMGAEgASgJEgoKAmlkGAIgASgJEg4KBmJyYW5jaBgDIAEoCRILCgN0YWcYBCABKAkSDwoHbWVzc2FnZRgGIAEoCRIOCgZhdXRob3IYByABKAkSEQoJY29tbWl0dGVyGAggASgJIm4KEkdpdERpc2NvdmVyeVJlc3VsdBIPCgdyZXBvVVJMGAEgASgJEkcKB2NvbW1pdHMYAiADKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRGlzY292ZXJlZENvbW1pdCKOAgoPR2l0U3Vic2NyaXB0aW9uEg8KB3JlcG9VUkwYASABKAkSHwoXY29tbWl0U2VsZWN0aW9uU3RyYXRlZ3kYAiABKAkSDgoGYnJhbmNoGAMgASgJEhUKDXN0cmljdFNlbXZlcnMYCyABKAgSGAoQc2VtdmVyQ29uc3RyYWludBgEIAEoCRIRCglhbGxvd1RhZ3MYBSABKAkSEgoKaWdub3JlVGFncxgGIAMoCRIdChVpbnNlY3VyZVNraXBUTFNWZXJpZnkYByABKAgSFAoMaW5jbHVkZVBhdGhzGAggAygJEhQKDGV4Y2x1ZGVQYXRocxgJIAMoCRIWCg5kaXNjb3ZlcnlMaW1pdBgKIAEoBSLIAQoGSGVhbHRoEg4KBnN0YXR1cxgBIAEoCRIOCgZpc3N1ZXMYAiADKAkSTgoGY29uZmlnGAQgASgLMj4uazhzLmlvLmFwaWV4dGVuc2lvbnNfYXBpc2VydmVyLnBrZy5hcGlzLmFwaWV4dGVuc2lvbnMudjEuSlNPThJOCgZvdXRwdXQYBSABKAsyPi5rOHMuaW8uYXBpZXh0ZW5zaW9uc19hcGlzZXJ2ZXIucGtnLmFwaXMuYXBpZXh0ZW5zaW9ucy52MS5KU09OIm8KD0hlYWx0aENoZWNrU3RlcBIMCgR1c2VzGAEgASgJEk4KBmNvbmZpZxgCIAEoCzI+Lms4cy5pby5hcGlleHRlbnNpb25zX2FwaXNlcnZlci5wa2cuYXBpcy5hcGlleHRlbnNpb25zLnYxLkpTT04iSQoFSW1hZ2USDwoHcmVwb1VSTBgBIAEoCRISCgpnaXRSZXBvVVJMGAIgASgJEgsKA3RhZxgDIAEoCRIOCgZkaWdlc3QYBCABKAkijQEKFEltYWdlRGlzY292ZXJ5UmVzdWx0Eg8KB3JlcG9VUkwYASABKAkSEAoIcGxhdGZvcm0YAiABKAkSUgoKcmVmZXJlbmNlcxgDIAMoCzI+LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5EaXNjb3ZlcmVkSW1hZ2VSZWZlcmVuY2Ui+QEKEUltYWdlU3Vic2NyaXB0aW9uEg8KB3JlcG9VUkwYASABKAkSEgoKZ2l0UmVwb1VSTBgCIAEoCRIeChZpbWFnZVNlbGVjdGlvblN0cmF0ZWd5GAMgASgJEhUKDXN0cmljdFNlbXZlcnMYCiABKAgSGAoQc2VtdmVyQ29uc3RyYWludBgEIAEoCRIRCglhbGxvd1RhZ3MYBSABKAkSEgoKaWdub3JlVGFncxgGIAMoCRIQCghwbGF0Zm9ybRgHIAEoCRIdChVpbnNlY3VyZVNraXBUTFNWZXJpZnkYCCABKAgSFgoOZGlzY292ZXJ5TGltaXQYCSABKAUi0wEKB1Byb2plY3QSQgoIbWV0YWRhdGEYASABKAsyMC5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuT2JqZWN0TWV0YRI/CgRzcGVjGAIgASgLMjEuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb2plY3RTcGVjEkMKBnN0YXR1cxgDIAEoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9qZWN0U3RhdHVzIo0BCgtQcm9qZWN0TGlzdBJACghtZXRhZGF0YRgBIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5MaXN0TWV0YRI8CgVpdGVtcxgCIAMoCzItLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9qZWN0Il8KC1Byb2plY3RTcGVjElAKEXByb21vdGlvblBvbGljaWVzGAEgAygLMjUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvblBvbGljeSJ0Cg1Qcm9qZWN0U3RhdHVzEkMKCmNvbmRpdGlvbnMYAyADKAsyLy5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuQ29uZGl0aW9uEg0KBXBoYXNlGAEgASgJEg8KB21lc3NhZ2UYAiABKAki2QEKCVByb21vdGlvbhJCCghtZXRhZGF0YRgBIAEoCzIwLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5PYmplY3RNZXRhEkEKBHNwZWMYAiABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uU3BlYxJFCgZzdGF0dXMYAyABKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uU3RhdHVzIpEBCg1Qcm9tb3Rpb25MaXN0EkAKCG1ldGFkYXRhGAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkxpc3RNZXRhEj4KBWl0ZW1zGAIgAygLMi8uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvbiI+Cg9Qcm9tb3Rpb25Qb2xpY3kSDQoFc3RhZ2UYASABKAkSHAoUYXV0b1Byb21vdGlvbkVuYWJsZWQYAiABKAgi8gEKElByb21vdGlvblJlZmVyZW5jZRIMCgRuYW1lGAEgASgJEkcKB2ZyZWlnaHQYAiABKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFJlZmVyZW5jZRJFCgZzdGF0dXMYAyABKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uU3RhdHVzEj4KCmZpbmlzaGVkQXQYBCABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZSK6AQoNUHJvbW90aW9uU3BlYxINCgVzdGFnZRgBIAEoCRIPCgdmcmVpZ2h0GAIgASgJEkUKBHZhcnMYBCADKAsyNy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVmFyaWFibGUSQgoFc3RlcHMYAyADKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uU3RlcCK3BAoPUHJvbW90aW9uU3RhdHVzEhoKEmxhc3RIYW5kbGVkUmVmcmVzaBgEIAEoCRINCgVwaGFzZRgBIAEoCRIPCgdtZXNzYWdlGAIgASgJEkcKB2ZyZWlnaHQYBSABKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFJlZmVyZW5jZRJSChFmcmVpZ2h0Q29sbGVjdGlvbhgHIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0Q29sbGVjdGlvbhJLCgxoZWFsdGhDaGVja3MYCCADKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSGVhbHRoQ2hlY2tTdGVwEj4KCmZpbmlzaGVkQXQYBiABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZRITCgtjdXJyZW50U3RlcBgJIAEoAxJaChVzdGVwRXhlY3V0aW9uTWV0YWRhdGEYCyADKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU3RlcEV4ZWN1dGlvbk1ldGFkYXRhEk0KBXN0YXRlGAogASgLMj4uazhzLmlvLmFwaWV4dGVuc2lvbnNfYXBpc2VydmVyLnBrZy5hcGlzLmFwaWV4dGVuc2lvbnMudjEuSlNPTiLVAgoNUHJvbW90aW9uU3RlcBIMCgR1c2VzGAEgASgJEkoKBHRhc2sYBSABKAsyPC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGFza1JlZmVyZW5jZRIKCgJhcxgCIAEoCRJHCgVyZXRyeRgEIAEoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGVwUmV0cnkSRQoEdmFycxgGIAMoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25WYXJpYWJsZRJOCgZjb25maWcYAyABKAsyPi5rOHMuaW8uYXBpZXh0ZW5zaW9uc19hcGlzZXJ2ZXIucGtnLmFwaXMuYXBpZXh0ZW5zaW9ucy52MS5KU09OIm0KElByb21vdGlvblN0ZXBSZXRyeRI/Cgd0aW1lb3V0GAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkR1cmF0aW9uEhYKDmVycm9yVGhyZXNob2xkGAIgASgNIpoBCg1Qcm9tb3Rpb25UYXNrEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESRQoEc3BlYxgCIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25UYXNrU3BlYyKZAQoRUHJvbW90aW9uVGFza0xpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESQgoFaXRlbXMYAiADKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGFzayI0ChZQcm9tb3Rpb25UYXNrUmVmZXJlbmNlEgwKBG5hbWUYASABKAkSDAoEa2luZBgCIAEoCSKeAQoRUHJvbW90aW9uVGFza1NwZWMSRQoEdmFycxgBIAMoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25WYXJpYWJsZRJCCgVzdGVwcxgCIAMoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGVwIl4KEVByb21vdGlvblRlbXBsYXRlEkkKBHNwZWMYASABKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGVtcGxhdGVTcGVjIqIBChVQcm9tb3Rpb25UZW1wbGF0ZVNwZWMSRQoEdmFycxgCIAMoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25WYXJpYWJsZRJCCgVzdGVwcxgBIAMoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGVwIjAKEVByb21vdGlvblZhcmlhYmxlEgwKBG5hbWUYASABKAkSDQoFdmFsdWUYAiABKAki5gEKEFJlcG9TdWJzY3JpcHRpb24SQgoDZ2l0GAEgASgLMjUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkdpdFN1YnNjcmlwdGlvbhJGCgVpbWFnZRgCIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5JbWFnZVN1YnNjcmlwdGlvbhJGCgVjaGFydBgDIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5DaGFydFN1YnNjcmlwdGlvbiLNAQoFU3RhZ2USQgoIbWV0YWRhdGEYASABKAsyMC5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuT2JqZWN0TWV0YRI9CgRzcGVjGAIgASgLMi8uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlN0YWdlU3BlYxJBCgZzdGF0dXMYAyABKAsyMS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU3RhZ2VTdGF0dXMiiQEKCVN0YWdlTGlzdBJACghtZXRhZGF0YRgBIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5MaXN0TWV0YRI6CgVpdGVtcxgCIAMoCzIrLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5TdGFnZSKIAgoJU3RhZ2VTcGVjEg0KBXNoYXJkGAQgASgJEk4KEHJlcXVlc3RlZEZyZWlnaHQYBSADKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFJlcXVlc3QSUgoRcHJvbW90aW9uVGVtcGxhdGUYBiABKAsyNy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGVtcGxhdGUSSAoMdmVyaWZpY2F0aW9uGAMgASgLMjIuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlZlcmlmaWNhdGlvbiL2AwoLU3RhZ2VTdGF0dXMSQwoKY29uZGl0aW9ucxgNIAMoCzIvLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5Db25kaXRpb24SGgoSbGFzdEhhbmRsZWRSZWZyZXNoGAsgASgJEg0KBXBoYXNlGAEgASgJEk8KDmZyZWlnaHRIaXN0b3J5GAQgAygLMjcuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRDb2xsZWN0aW9uEhYKDmZyZWlnaHRTdW1tYXJ5GAwgASgJEjwKBmhlYWx0aBgIIAEoCzIsLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5IZWFsdGgSDwoHbWVzc2FnZRgJIAEoCRIaChJvYnNlcnZlZEdlbmVyYXRpb24YBiABKAMSUgoQY3VycmVudFByb21vdGlvbhgHIAEoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25SZWZlcmVuY2USTwoNbGFzdFByb21vdGlvbhgKIAEoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25SZWZlcmVuY2Ui2gEKFVN0ZXBFeGVjdXRpb25NZXRhZGF0YRINCgVhbGlhcxgBIAEoCRI9CglzdGFydGVkQXQYAiABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZRI+CgpmaW5pc2hlZEF0GAMgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUSEgoKZXJyb3JDb3VudBgEIAEoDRIOCgZzdGF0dXMYBSABKAkSDwoHbWVzc2FnZRgGIAEoCSKLAgoMVmVyaWZpY2F0aW9uEloKEWFuYWx5c2lzVGVtcGxhdGVzGAEgAygLMj8uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkFuYWx5c2lzVGVtcGxhdGVSZWZlcmVuY2USVgoTYW5hbHlzaXNSdW5NZXRhZGF0YRgCIAEoCzI5LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5BbmFseXNpc1J1bk1ldGFkYXRhEkcKBGFyZ3MYAyADKAsyOS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQW5hbHlzaXNSdW5Bcmd1bWVudCKdAgoQVmVyaWZpY2F0aW9uSW5mbxIKCgJpZBgEIAEoCRINCgVhY3RvchgHIAEoCRI9CglzdGFydFRpbWUYBSABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZRINCgVwaGFzZRgBIAEoCRIPCgdtZXNzYWdlGAIgASgJEk8KC2FuYWx5c2lzUnVuGAMgASgLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkFuYWx5c2lzUnVuUmVmZXJlbmNlEj4KCmZpbmlzaFRpbWUYBiABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZSKUAQoNVmVyaWZpZWRTdGFnZRI+Cgp2ZXJpZmllZEF0GAEgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUSQwoLbG9uZ2VzdFNvYWsYAiABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuRHVyYXRpb24i2QEKCVdhcmVob3VzZRJCCghtZXRhZGF0YRgBIAEoCzIwLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5PYmplY3RNZXRhEkEKBHNwZWMYAiABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuV2FyZWhvdXNlU3BlYxJFCgZzdGF0dXMYAyABKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuV2FyZWhvdXNlU3RhdHVzIpEBCg1XYXJlaG91c2VMaXN0EkAKCG1ldGFkYXRhGAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkxpc3RNZXRhEj4KBWl0ZW1zGAIgAygLMi8uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLldhcmVob3VzZSLOAQoNV2FyZWhvdXNlU3BlYxINCgVzaGFyZBgCIAEoCRJACghpbnRlcnZhbBgEIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5EdXJhdGlvbhIdChVmcmVpZ2h0Q3JlYXRpb25Qb2xpY3kYAyABKAkSTQoNc3Vic2NyaXB0aW9ucxgBIAMoCzI2LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5SZXBvU3Vic2NyaXB0aW9uIv0BCg9XYXJlaG91c2VTdGF0dXMSQwoKY29uZGl0aW9ucxgJIAMoCzIvLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5Db25kaXRpb24SGgoSbGFzdEhhbmRsZWRSZWZyZXNoGAYgASgJEhoKEm9ic2VydmVkR2VuZXJhdGlvbhgEIAEoAxIVCg1sYXN0RnJlaWdodElEGAggASgJElYKE2Rpc2NvdmVyZWRBcnRpZmFjdHMYByABKAsyOS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRGlzY292ZXJlZEFydGlmYWN0c0KXAgooY29tLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMUIOR2VuZXJhdGVkUHJvdG9QAVokZ2l0aHViLmNvbS9ha3VpdHkva2FyZ28vYXBpL3YxYWxwaGExogIFR0NBS0GqAiRHaXRodWIuQ29tLkFrdWl0eS5LYXJnby5BcGkuVjFhbHBoYTHKAiRHaXRodWJcQ29tXEFrdWl0eVxLYXJnb1xBcGlcVjFhbHBoYTHiAjBHaXRodWJcQ29tXEFrdWl0eVxLYXJnb1xBcGlcVjFhbHBoYTFcR1BCTWV0YWRhdGHqAilHaXRodWI6OkNvbTo6QWt1aXR5OjpLYXJnbzo6QXBpOjpWMWFscGhhMQ", [file_k8s_io_apiextensions_apiserver_pkg_apis_apiextensions_v1_generated, file_k8s_io_apimachinery_pkg_apis_meta_v1_generated, file_k8s_io_apimachinery_pkg_runtime_generated, file_k8s_io_apimachinery_pkg_runtime_schema_generated]);
+  fileDesc("Chh2MWFscGhhMS9nZW5lcmF0ZWQucHJvdG8SJGdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMSIyChNBbmFseXNpc1J1bkFyZ3VtZW50EgwKBG5hbWUYASABKAkSDQoFdmFsdWUYAiABKAkisAIKE0FuYWx5c2lzUnVuTWV0YWRhdGESVQoGbGFiZWxzGAEgAygLMkUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkFuYWx5c2lzUnVuTWV0YWRhdGEuTGFiZWxzRW50cnkSXwoLYW5ub3RhdGlvbnMYAiADKAsySi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQW5hbHlzaXNSdW5NZXRhZGF0YS5Bbm5vdGF0aW9uc0VudHJ5Gi0KC0xhYmVsc0VudHJ5EgsKA2tleRgBIAEoCRINCgV2YWx1ZRgCIAEoCToCOAEaMgoQQW5ub3RhdGlvbnNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBIkYKFEFuYWx5c2lzUnVuUmVmZXJlbmNlEhEKCW5hbWVzcGFjZRgBIAEoCRIMCgRuYW1lGAIgASgJEg0KBXBoYXNlGAMgASgJIikKGUFuYWx5c2lzVGVtcGxhdGVSZWZlcmVuY2USDAoEbmFtZRgBIAEoCSJPCg1BcHByb3ZlZFN0YWdlEj4KCmFwcHJvdmVkQXQYASABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZSI4ChVBcmdvQ0RBcHBIZWFsdGhTdGF0dXMSDgoGc3RhdHVzGAEgASgJEg8KB21lc3NhZ2UYAiABKAki1AEKD0FyZ29DREFwcFN0YXR1cxIRCgluYW1lc3BhY2UYASABKAkSDAoEbmFtZRgCIAEoCRJRCgxoZWFsdGhTdGF0dXMYAyABKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQXJnb0NEQXBwSGVhbHRoU3RhdHVzEk0KCnN5bmNTdGF0dXMYBCABKAsyOS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQXJnb0NEQXBwU3luY1N0YXR1cyJKChNBcmdvQ0RBcHBTeW5jU3RhdHVzEg4KBnN0YXR1cxgBIAEoCRIQCghyZXZpc2lvbhgCIAEoCRIRCglyZXZpc2lvbnMYAyADKAkiNwoFQ2hhcnQSDwoHcmVwb1VSTBgBIAEoCRIMCgRuYW1lGAIgASgJEg8KB3ZlcnNpb24YAyABKAkiYQoUQ2hhcnREaXNjb3ZlcnlSZXN1bHQSDwoHcmVwb1VSTBgBIAEoCRIMCgRuYW1lGAIgASgJEhgKEHNlbXZlckNvbnN0cmFpbnQYAyABKAkSEAoIdmVyc2lvbnMYBCADKAkiZAoRQ2hhcnRTdWJzY3JpcHRpb24SDwoHcmVwb1VSTBgBIAEoCRIMCgRuYW1lGAIgASgJEhgKEHNlbXZlckNvbnN0cmFpbnQYAyABKAkSFgoOZGlzY292ZXJ5TGltaXQYBCABKAUioQEKFENsdXN0ZXJQcm9tb3Rpb25UYXNrEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESRQoEc3BlYxgCIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25UYXNrU3BlYyKnAQoYQ2x1c3RlclByb21vdGlvblRhc2tMaXN0EkAKCG1ldGFkYXRhGAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkxpc3RNZXRhEkkKBWl0ZW1zGAIgAygLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkNsdXN0ZXJQcm9tb3Rpb25UYXNrIkkKDEN1cnJlbnRTdGFnZRI5CgVzaW5jZRgBIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIrYCChNEaXNjb3ZlcmVkQXJ0aWZhY3RzEkAKDGRpc2NvdmVyZWRBdBgEIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lEkUKA2dpdBgBIAMoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5HaXREaXNjb3ZlcnlSZXN1bHQSSgoGaW1hZ2VzGAIgAygLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkltYWdlRGlzY292ZXJ5UmVzdWx0EkoKBmNoYXJ0cxgDIAMoCzI6LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5DaGFydERpc2NvdmVyeVJlc3VsdCKwAQoQRGlzY292ZXJlZENvbW1pdBIKCgJpZBgBIAEoCRIOCgZicmFuY2gYAiABKAkSCwoDdGFnGAMgASgJEg8KB3N1YmplY3QYBCABKAkSDgoGYXV0aG9yGAUgASgJEhEKCWNvbW1pdHRlchgGIAEoCRI/CgtjcmVhdG9yRGF0ZRgHIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIooBChhEaXNjb3ZlcmVkSW1hZ2VSZWZlcmVuY2USCwoDdGFnGAEgASgJEg4KBmRpZ2VzdBgCIAEoCRISCgpnaXRSZXBvVVJMGAMgASgJEj0KCWNyZWF0ZWRBdBgEIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIvgBCgVEcmlmdBIPCgdyZXBvVVJMGAEgASgJEg4KBmJyYW5jaBgCIAEoCRIWCg5wcm9tb3RlZENvbW1pdBgDIAEoCRIVCg1kcmlmdGVkQ29tbWl0GAQgASgJEj4KCmRldGVjdGVkQXQYBSABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZRJLCgtwdWxsUmVxdWVzdBgGIAEoCzI2LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5EcmlmdFB1bGxSZXF1ZXN0EhIKCnJlc29sdXRpb24YByABKAkicwoQRHJpZnRQdWxsUmVxdWVzdBIPCgdyZXBvVVJMGAEgASgJEg4KBm51bWJlchgCIAEoAxILCgN1cmwYAyABKAkSDgoGYnJhbmNoGAQgASgJEhEKCXBhdGNoUGF0aBgFIAEoCRIOCgZtZXJnZWQYBiABKAgifwoLRHJ5UnVuQXBwbHkSFAoMbWF4RG9jdW1lbnRzGAEgASgFEj8KB3RpbWVvdXQYAiABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuRHVyYXRpb24SGQoRbWlzc2luZ05hbWVzcGFjZXMYAyABKAki4gEKC0V4ZWNDb21tYW5kEgwKBG5hbWUYASABKAkSDAoEcGF0aBgCIAEoCRIMCgRhcmdzGAMgAygJEhEKCWFsbG93QXJncxgEIAEoCBI9CgNlbnYYBSADKAsyMC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRXhlY0VudlZhchI/Cgd0aW1lb3V0GAYgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkR1cmF0aW9uEhYKDm1heE91dHB1dEJ5dGVzGAcgASgFIikKCkV4ZWNFbnZWYXISDAoEbmFtZRgBIAEoCRINCgV2YWx1ZRgCIAEoCSJRCgpFeGVjUG9saWN5EkMKCGNvbW1hbmRzGAEgAygLMjEuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkV4ZWNDb21tYW5kInUKFEV4dGVybmFsTW9kaWZpY2F0aW9uEg8KB3JlcG9VUkwYASABKAkSDgoGYnJhbmNoGAIgASgJEhYKDmV4cGVjdGVkQ29tbWl0GAMgASgJEhQKDGFjdHVhbENvbW1pdBgEIAEoCRIOCgZwb2xpY3kYBSABKAkiogMKB0ZyZWlnaHQSQgoIbWV0YWRhdGEYASABKAsyMC5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuT2JqZWN0TWV0YRINCgVhbGlhcxgHIAEoCRJDCgZvcmlnaW4YCSABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodE9yaWdpbhJACgdjb21taXRzGAMgAygLMi8uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkdpdENvbW1pdBI7CgZpbWFnZXMYBCADKAsyKy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSW1hZ2USOwoGY2hhcnRzGAUgAygLMisuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkNoYXJ0EkMKBnN0YXR1cxgGIAEoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0U3RhdHVzIq0CChFGcmVpZ2h0Q29sbGVjdGlvbhIKCgJpZBgDIAEoCRJRCgVpdGVtcxgBIAMoCzJCLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0Q29sbGVjdGlvbi5JdGVtc0VudHJ5ElMKE3ZlcmlmaWNhdGlvbkhpc3RvcnkYAiADKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuVmVyaWZpY2F0aW9uSW5mbxpkCgpJdGVtc0VudHJ5EgsKA2tleRgBIAEoCRJFCgV2YWx1ZRgCIAEoCzI2LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0UmVmZXJlbmNlOgI4ASKNAQoLRnJlaWdodExpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESPAoFaXRlbXMYAiADKAsyLS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodCIrCg1GcmVpZ2h0T3JpZ2luEgwKBGtpbmQYASABKAkSDAoEbmFtZRgCIAEoCSKhAgoQRnJlaWdodFJlZmVyZW5jZRIMCgRuYW1lGAEgASgJEkMKBm9yaWdpbhgIIAEoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0T3JpZ2luEkAKB2NvbW1pdHMYAiADKAsyLy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuR2l0Q29tbWl0EjsKBmltYWdlcxgDIAMoCzIrLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5JbWFnZRI7CgZjaGFydHMYBCADKAsyKy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQ2hhcnQinAEKDkZyZWlnaHRSZXF1ZXN0EkMKBm9yaWdpbhgBIAEoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0T3JpZ2luEkUKB3NvdXJjZXMYAiABKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFNvdXJjZXMiegoORnJlaWdodFNvdXJjZXMSDgoGZGlyZWN0GAEgASgIEg4KBnN0YWdlcxgCIAMoCRJIChByZXF1aXJlZFNvYWtUaW1lGAMgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkR1cmF0aW9uItcECg1GcmVpZ2h0U3RhdHVzElkKC2N1cnJlbnRseUluGAMgAygLMkQuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRTdGF0dXMuQ3VycmVudGx5SW5FbnRyeRJXCgp2ZXJpZmllZEluGAEgAygLMkMuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRTdGF0dXMuVmVyaWZpZWRJbkVudHJ5ElkKC2FwcHJvdmVkRm9yGAIgAygLMkQuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRTdGF0dXMuQXBwcm92ZWRGb3JFbnRyeRpmChBDdXJyZW50bHlJbkVudHJ5EgsKA2tleRgBIAEoCRJBCgV2YWx1ZRgCIAEoCzIyLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5DdXJyZW50U3RhZ2U6AjgBGmYKD1ZlcmlmaWVkSW5FbnRyeRILCgNrZXkYASABKAkSQgoFdmFsdWUYAiABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuVmVyaWZpZWRTdGFnZToCOAEaZwoQQXBwcm92ZWRGb3JFbnRyeRILCgNrZXkYASABKAkSQgoFdmFsdWUYAiABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQXBwcm92ZWRTdGFnZToCOAEibgoPR2l0Q2xpZW50Q29uZmlnEh8KF21heENvbmN1cnJlbnRPcHNQZXJIb3N0GAEgASgFEh4KFm1heE9wc1Blck1pbnV0ZVBlckhvc3QYAiABKAUSGgoSbmV0d29ya01heEF0dGVtcHRzGAMgASgFInkKCUdpdENvbW1pdBIPCgdyZXBvVVJMGAEgASgJEgoKAmlkGAIgASgJEg4KBmJyYW5jaBgDIAEoCRILCgN0YWcYBCABKAkSDwoHbWVzc2FnZRgGIAEoCRIOCgZhdXRob3IYByABKAkSEQoJY29tbWl0dGVyGAggASgJIm4KEkdpdERpc2NvdmVyeVJlc3VsdBIPCgdyZXBvVVJMGAEgASgJEkcKB2NvbW1pdHMYAiADKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRGlzY292ZXJlZENvbW1pdCJFCgtHaXRJZGVudGl0eRIMCgRuYW1lGAEgASgJEg0KBWVtYWlsGAIgASgJEhkKEWNyZWRlbnRpYWxzU2VjcmV0GAMgASgJIo4CCg9HaXRTdWJzY3JpcHRpb24SDwoHcmVwb1VSTBgBIAEoCRIfChdjb21taXRTZWxlY3Rpb25TdHJhdGVneRgCIAEoCRIOCgZicmFuY2gYAyABKAkSFQoNc3RyaWN0U2VtdmVycxgLIAEoCBIYChBzZW12ZXJDb25zdHJhaW50GAQgASgJEhEKCWFsbG93VGFncxgFIAEoCRISCgppZ25vcmVUYWdzGAYgAygJEh0KFWluc2VjdXJlU2tpcFRMU1ZlcmlmeRgHIAEoCBIUCgxpbmNsdWRlUGF0aHMYCCADKAkSFAoMZXhjbHVkZVBhdGhzGAkgAygJEhYKDmRpc2NvdmVyeUxpbWl0GAogASgFIsgBCgZIZWFsdGgSDgoGc3RhdHVzGAEgASgJEg4KBmlzc3VlcxgCIAMoCRJOCgZjb25maWcYBCABKAsyPi5rOHMuaW8uYXBpZXh0ZW5zaW9uc19hcGlzZXJ2ZXIucGtnLmFwaXMuYXBpZXh0ZW5zaW9ucy52MS5KU09OEk4KBm91dHB1dBgFIAEoCzI+Lms4cy5pby5hcGlleHRlbnNpb25zX2FwaXNlcnZlci5wa2cuYXBpcy5hcGlleHRlbnNpb25zLnYxLkpTT04ibwoPSGVhbHRoQ2hlY2tTdGVwEgwKBHVzZXMYASABKAkSTgoGY29uZmlnGAIgASgLMj4uazhzLmlvLmFwaWV4dGVuc2lvbnNfYXBpc2VydmVyLnBrZy5hcGlzLmFwaWV4dGVuc2lvbnMudjEuSlNPTiJJCgVJbWFnZRIPCgdyZXBvVVJMGAEgASgJEhIKCmdpdFJlcG9VUkwYAiABKAkSCwoDdGFnGAMgASgJEg4KBmRpZ2VzdBgEIAEoCSJkCg9JbWFnZURpZmZlcmVuY2USDwoHcmVwb1VSTBgBIAEoCRIPCgd2ZXJzaW9uGAIgASgJEhcKD3Vwc3RyZWFtVmVyc2lvbhgDIAEoCRIWCg52ZXJzaW9uc0JlaGluZBgEIAEoBSKNAQoUSW1hZ2VEaXNjb3ZlcnlSZXN1bHQSDwoHcmVwb1VSTBgBIAEoCRIQCghwbGF0Zm9ybRgCIAEoCRJSCgpyZWZlcmVuY2VzGAMgAygLMj4uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkRpc2NvdmVyZWRJbWFnZVJlZmVyZW5jZSJsCgtJbWFnZUxpbWl0cxIeChZjb21taXRNZXNzYWdlTWF4SW1hZ2VzGAEgASgFEhcKD3N0YXR1c01heEltYWdlcxgCIAEoBRIRCglzb2Z0TGltaXQYAyABKAUSEQoJaGFyZExpbWl0GAQgASgFIlkKDEltYWdlTWFwcGluZxIPCgdyZXBvVVJMGAEgASgJEhIKCm5ld1JlcG9VUkwYAiABKAkSEQoJdGFnUHJlZml4GAMgASgJEhEKCXRhZ1N1ZmZpeBgEIAEoCSJqCg5JbWFnZVNldERpZ2VzdBINCgVjb3VudBgBIAEoBRIMCgRoYXNoGAIgASgJEjsKBmltYWdlcxgDIAMoCzIrLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5JbWFnZSKXAgoRSW1hZ2VTdWJzY3JpcHRpb24SDAoEbmFtZRgLIAEoCRIOCgZwYXVzZWQYDCABKAgSDwoHcmVwb1VSTBgBIAEoCRISCgpnaXRSZXBvVVJMGAIgASgJEh4KFmltYWdlU2VsZWN0aW9uU3RyYXRlZ3kYAyABKAkSFQoNc3RyaWN0U2VtdmVycxgKIAEoCBIYChBzZW12ZXJDb25zdHJhaW50GAQgASgJEhEKCWFsbG93VGFncxgFIAEoCRISCgppZ25vcmVUYWdzGAYgAygJEhAKCHBsYXRmb3JtGAcgASgJEh0KFWluc2VjdXJlU2tpcFRMU1ZlcmlmeRgIIAEoCBIWCg5kaXNjb3ZlcnlMaW1pdBgJIAEoBSLFAQoXSW1hZ2VTdWJzY3JpcHRpb25TdGF0dXMSDAoEbmFtZRgBIAEoCRIPCgdyZXBvVVJMGAIgASgJEg4KBnBhdXNlZBgDIAEoCBJEChBsYXN0RGlzY292ZXJlZEF0GAQgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUSDwoHbGFzdFRhZxgFIAEoCRIVCg1sYXN0RnJlaWdodElEGAYgASgJEg0KBWVycm9yGAcgASgJIpYBCgtLYXJnb0NvbmZpZxJCCghtZXRhZGF0YRgBIAEoCzIwLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5PYmplY3RNZXRhEkMKBHNwZWMYAiABKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuS2FyZ29Db25maWdTcGVjIpUBCg9LYXJnb0NvbmZpZ0xpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESQAoFaXRlbXMYAiADKAsyMS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuS2FyZ29Db25maWci6AMKD0thcmdvQ29uZmlnU3BlYxIXCg9wYXVzZVByb21vdGlvbnMYASABKAgSSAoJZ2l0Q2xpZW50GAIgASgLMjUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkdpdENsaWVudENvbmZpZxJECgpyZXBvUG9saWN5GAMgASgLMjAuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlJlcG9Qb2xpY3kSRgoLaW1hZ2VMaW1pdHMYBCABKAsyMS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSW1hZ2VMaW1pdHMSRAoKZXhlY1BvbGljeRgFIAEoCzIwLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5FeGVjUG9saWN5EkwKDnJlc291cmNlTGltaXRzGAYgASgLMjQuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlJlc291cmNlTGltaXRzElAKEHJlbW90ZUJhc2VQb2xpY3kYByABKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUmVtb3RlQmFzZVBvbGljeSLnAgoQTWFuYWdlZEFyZ29DREFwcBIMCgRuYW1lGAEgASgJEhEKCW5hbWVzcGFjZRgCIAEoCRIPCgdwcm9qZWN0GAMgASgJEkwKBnNvdXJjZRgEIAEoCzI8LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5NYW5hZ2VkQXJnb0NEQXBwU291cmNlElYKC2Rlc3RpbmF0aW9uGAUgASgLMkEuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLk1hbmFnZWRBcmdvQ0RBcHBEZXN0aW5hdGlvbhJUCgpzeW5jUG9saWN5GAYgASgLMkAuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLk1hbmFnZWRBcmdvQ0RBcHBTeW5jUG9saWN5Eg0KBWFkb3B0GAcgASgIEhYKDmRlbGV0aW9uUG9saWN5GAggASgJIk4KG01hbmFnZWRBcmdvQ0RBcHBEZXN0aW5hdGlvbhIOCgZzZXJ2ZXIYASABKAkSDAoEbmFtZRgCIAEoCRIRCgluYW1lc3BhY2UYAyABKAkiTwoWTWFuYWdlZEFyZ29DREFwcFNvdXJjZRIPCgdyZXBvVVJMGAEgASgJEhYKDnRhcmdldFJldmlzaW9uGAIgASgJEgwKBHBhdGgYAyABKAkiZQoaTWFuYWdlZEFyZ29DREFwcFN5bmNQb2xpY3kSEQoJYXV0b21hdGVkGAEgASgIEg0KBXBydW5lGAIgASgIEhAKCHNlbGZIZWFsGAMgASgIEhMKC3N5bmNPcHRpb25zGAQgAygJIisKDE9yaWdpbkNvbW1pdBIPCgdyZXBvVVJMGAEgASgJEgoKAmlkGAIgASgJImoKDlBlbmRpbmdGcmVpZ2h0EgoKAmlkGAEgASgJEjkKBXNpbmNlGAIgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUSEQoJcmVmcmVzaGVzGAMgAygJItMBCgdQcm9qZWN0EkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESPwoEc3BlYxgCIAEoCzIxLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9qZWN0U3BlYxJDCgZzdGF0dXMYAyABKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvamVjdFN0YXR1cyKNAQoLUHJvamVjdExpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESPAoFaXRlbXMYAiADKAsyLS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvamVjdCJfCgtQcm9qZWN0U3BlYxJQChFwcm9tb3Rpb25Qb2xpY2llcxgBIAMoCzI1LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25Qb2xpY3kidAoNUHJvamVjdFN0YXR1cxJDCgpjb25kaXRpb25zGAMgAygLMi8uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkNvbmRpdGlvbhINCgVwaGFzZRgBIAEoCRIPCgdtZXNzYWdlGAIgASgJIp0BCg9Qcm9tb3RlZE92ZXJsYXkSDAoEbmFtZRgBIAEoCRIMCgRwYXRoGAIgASgJEhYKDnJlbmRlcmVkQnJhbmNoGAMgASgJEg4KBmNvbW1pdBgEIAEoCRJGCgtnaXRJZGVudGl0eRgFIAEoCzIxLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5HaXRJZGVudGl0eSLZAQoJUHJvbW90aW9uEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESQQoEc3BlYxgCIAEoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TcGVjEkUKBnN0YXR1cxgDIAEoCzI1LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGF0dXMidwoRUHJvbW90aW9uQXBwcm92YWwSEAoIYXBwcm92ZXIYASABKAkSPgoKYXBwcm92ZWRBdBgCIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lEhAKCHNwZWNIYXNoGAMgASgJIskBChdQcm9tb3Rpb25BcHByb3ZhbFBvbGljeRJRChBhbGxvd2VkQXBwcm92ZXJzGAEgAygLMjcuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvbkFwcHJvdmVyEhsKE3ByZXZlbnRTZWxmQXBwcm92YWwYAiABKAgSPgoGbWF4QWdlGAMgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkR1cmF0aW9uIkIKEVByb21vdGlvbkFwcHJvdmVyEgwKBGtpbmQYASABKAkSDAoEbmFtZRgCIAEoCRIRCgluYW1lc3BhY2UYAyABKAkiOQoOUHJvbW90aW9uTGFuZXMSFQoNbWF4Q29uY3VycmVudBgBIAEoBRIQCghmYWlsRmFzdBgCIAEoCCKRAQoNUHJvbW90aW9uTGlzdBJACghtZXRhZGF0YRgBIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5MaXN0TWV0YRI+CgVpdGVtcxgCIAMoCzIvLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb24iPgoPUHJvbW90aW9uUG9saWN5Eg0KBXN0YWdlGAEgASgJEhwKFGF1dG9Qcm9tb3Rpb25FbmFibGVkGAIgASgIImgKDlByb21vdGlvblF1ZXVlEg8KB3BlbmRpbmcYASADKAkSRQoNZXN0aW1hdGVkV2FpdBgCIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5EdXJhdGlvbiKHAgoPUHJvbW90aW9uUmVjb3JkEgwKBG5hbWUYASABKAkSRwoHZnJlaWdodBgCIAEoCzI2LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0UmVmZXJlbmNlEg0KBXBoYXNlGAMgASgJEg8KB21lc3NhZ2UYBCABKAkSPQoJc3RhcnRlZEF0GAUgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUSPgoKZmluaXNoZWRBdBgGIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIvIBChJQcm9tb3Rpb25SZWZlcmVuY2USDAoEbmFtZRgBIAEoCRJHCgdmcmVpZ2h0GAIgASgLMjYuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRSZWZlcmVuY2USRQoGc3RhdHVzGAMgASgLMjUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvblN0YXR1cxI+CgpmaW5pc2hlZEF0GAQgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUirAMKDVByb21vdGlvblNwZWMSDQoFc3RhZ2UYASABKAkSDwoHZnJlaWdodBgCIAEoCRJFCgR2YXJzGAQgAygLMjcuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvblZhcmlhYmxlEkIKBXN0ZXBzGAMgAygLMjMuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvblN0ZXASQwoFbGFuZXMYBSABKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uTGFuZXMSEAoIcHJpb3JpdHkYBiABKAUSTwoIYXBwcm92YWwYByABKAsyPS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uQXBwcm92YWxQb2xpY3kSSAoMb3JpZ2luQ29tbWl0GAggASgLMjIuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLk9yaWdpbkNvbW1pdCKQDQoPUHJvbW90aW9uU3RhdHVzEhoKEmxhc3RIYW5kbGVkUmVmcmVzaBgEIAEoCRINCgVwaGFzZRgBIAEoCRIPCgdtZXNzYWdlGAIgASgJEkcKB2ZyZWlnaHQYBSABKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRnJlaWdodFJlZmVyZW5jZRJSChFmcmVpZ2h0Q29sbGVjdGlvbhgHIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5GcmVpZ2h0Q29sbGVjdGlvbhJLCgxoZWFsdGhDaGVja3MYCCADKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSGVhbHRoQ2hlY2tTdGVwEj4KCmZpbmlzaGVkQXQYBiABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZRITCgtjdXJyZW50U3RlcBgJIAEoAxJaChVzdGVwRXhlY3V0aW9uTWV0YWRhdGEYCyADKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU3RlcEV4ZWN1dGlvbk1ldGFkYXRhEk0KBXN0YXRlGAogASgLMj4uazhzLmlvLmFwaWV4dGVuc2lvbnNfYXBpc2VydmVyLnBrZy5hcGlzLmFwaWV4dGVuc2lvbnMudjEuSlNPThIWCg5yZW5kZXJlZEJyYW5jaBgMIAEoCRJVChNyZXBvUG9saWN5RGVjaXNpb25zGA0gAygLMjguZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlJlcG9Qb2xpY3lEZWNpc2lvbhJECgZpbWFnZXMYDiABKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSW1hZ2VTZXREaWdlc3QSGwoTdHJhbnNjcmlwdENvbmZpZ01hcBgPIAEoCRJHCghvdmVybGF5cxgQIAMoCzI1LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3RlZE92ZXJsYXkSSQoIYXBwcm92YWwYESABKAsyNy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uQXBwcm92YWwSVAoScmVuZGVyZWRCcmFuY2hQdXNoGBIgASgLMjguZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlJlbmRlcmVkQnJhbmNoUHVzaBJaChVyZW5kZXJlZEJyYW5jaENsZWFudXAYEyABKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUmVuZGVyZWRCcmFuY2hDbGVhbnVwElgKFGV4dGVybmFsTW9kaWZpY2F0aW9uGBQgASgLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkV4dGVybmFsTW9kaWZpY2F0aW9uEkMKCmNvbmRpdGlvbnMYFSADKAsyLy5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuQ29uZGl0aW9uEkkKFWF3YWl0aW5nQXBwcm92YWxTaW5jZRgWIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lEksKDnJldGFpbmVkSW1hZ2VzGBcgAygLMjMuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlJldGFpbmVkSW1hZ2USUgoRc291cmNlVXBkYXRlQmF0Y2gYGCABKAsyNy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU291cmNlVXBkYXRlQmF0Y2gSFgoOcmVuZGVyV2FybmluZ3MYGSADKAkSRgoLZ2l0SWRlbnRpdHkYGiABKAsyMS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuR2l0SWRlbnRpdHkSVAoUbGFzdFJlY29uY2lsZU91dGNvbWUYGyABKAsyNi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUmVjb25jaWxlT3V0Y29tZSL8AgoNUHJvbW90aW9uU3RlcBIMCgR1c2VzGAEgASgJEkoKBHRhc2sYBSABKAsyPC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGFza1JlZmVyZW5jZRIKCgJhcxgCIAEoCRJHCgVyZXRyeRgEIAEoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGVwUmV0cnkSFwoPY29udGludWVPbkVycm9yGAcgASgIEgwKBGxhbmUYCCABKAkSRQoEdmFycxgGIAMoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25WYXJpYWJsZRJOCgZjb25maWcYAyABKAsyPi5rOHMuaW8uYXBpZXh0ZW5zaW9uc19hcGlzZXJ2ZXIucGtnLmFwaXMuYXBpZXh0ZW5zaW9ucy52MS5KU09OIm0KElByb21vdGlvblN0ZXBSZXRyeRI/Cgd0aW1lb3V0GAEgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkR1cmF0aW9uEhYKDmVycm9yVGhyZXNob2xkGAIgASgNIpoBCg1Qcm9tb3Rpb25UYXNrEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESRQoEc3BlYxgCIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25UYXNrU3BlYyKZAQoRUHJvbW90aW9uVGFza0xpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESQgoFaXRlbXMYAiADKAsyMy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGFzayI0ChZQcm9tb3Rpb25UYXNrUmVmZXJlbmNlEgwKBG5hbWUYASABKAkSDAoEa2luZBgCIAEoCSKeAQoRUHJvbW90aW9uVGFza1NwZWMSRQoEdmFycxgBIAMoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25WYXJpYWJsZRJCCgVzdGVwcxgCIAMoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGVwIl4KEVByb21vdGlvblRlbXBsYXRlEkkKBHNwZWMYASABKAsyOy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uVGVtcGxhdGVTcGVjIucBChVQcm9tb3Rpb25UZW1wbGF0ZVNwZWMSRQoEdmFycxgCIAMoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25WYXJpYWJsZRJCCgVzdGVwcxgBIAMoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25TdGVwEkMKBWxhbmVzGAMgASgLMjQuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvbkxhbmVzIjAKEVByb21vdGlvblZhcmlhYmxlEgwKBG5hbWUYASABKAkSDQoFdmFsdWUYAiABKAkibgoQUmVjb25jaWxlT3V0Y29tZRIOCgZyZWFzb24YASABKAkSDwoHbWVzc2FnZRgCIAEoCRI5CgVzaW5jZRgDIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIigKEFJlbW90ZUJhc2VQb2xpY3kSFAoMYWxsb3dlZEhvc3RzGAEgAygJIoMBCg5SZW5kZXJlZEJyYW5jaBIQCgh0ZW1wbGF0ZRgBIAEoCRILCgNhcHAYAiABKAkSDwoHY2x1c3RlchgDIAEoCRIOCgZyZWdpb24YBCABKAkSEQoJb25GYWlsdXJlGAUgASgJEh4KFm9uRXh0ZXJuYWxNb2RpZmljYXRpb24YBiABKAkiWAoVUmVuZGVyZWRCcmFuY2hDbGVhbnVwEg4KBmFjdGlvbhgBIAEoCRILCgN0YWcYAiABKAkSEQoJc3VjY2VlZGVkGAMgASgIEg8KB21lc3NhZ2UYBCABKAkiWgoUUmVuZGVyZWRCcmFuY2hDb21taXQSDwoHcmVwb1VSTBgBIAEoCRIOCgZicmFuY2gYAiABKAkSDgoGY29tbWl0GAMgASgJEhEKCXByb21vdGlvbhgEIAEoCSJdChJSZW5kZXJlZEJyYW5jaFB1c2gSDwoHcmVwb1VSTBgBIAEoCRIOCgZicmFuY2gYAiABKAkSFgoOcHJldmlvdXNDb21taXQYAyABKAkSDgoGY29tbWl0GAQgASgJIikKClJlcG9Qb2xpY3kSDQoFYWxsb3cYASADKAkSDAoEZGVueRgCIAMoCSJEChJSZXBvUG9saWN5RGVjaXNpb24SDwoHcmVwb1VSTBgBIAEoCRIPCgdhbGxvd2VkGAIgASgIEgwKBHJ1bGUYAyABKAki5gEKEFJlcG9TdWJzY3JpcHRpb24SQgoDZ2l0GAEgASgLMjUuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkdpdFN1YnNjcmlwdGlvbhJGCgVpbWFnZRgCIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5JbWFnZVN1YnNjcmlwdGlvbhJGCgVjaGFydBgDIAEoCzI3LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5DaGFydFN1YnNjcmlwdGlvbiKkAQoOUmVzb3VyY2VMaW1pdHMSQwoLbWF4UmVwb1NpemUYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGkucmVzb3VyY2UuUXVhbnRpdHkSTQoVbWF4UmVuZGVyZWRPdXRwdXRTaXplGAIgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpLnJlc291cmNlLlF1YW50aXR5Ij0KDVJldGFpbmVkSW1hZ2USDwoHcmVwb1VSTBgBIAEoCRILCgN0YWcYAiABKAkSDgoGZGlnZXN0GAMgASgJIicKF1NlcnZpY2VBY2NvdW50UmVmZXJlbmNlEgwKBG5hbWUYASABKAkioQEKEVNvdXJjZVVwZGF0ZUJhdGNoEg8KB3JlcG9VUkwYASABKAkSDgoGYnJhbmNoGAIgASgJEhUKDXJvbGxpbmdCcmFuY2gYAyABKAkSFQoNcm9sbGluZ0NvbW1pdBgEIAEoCRIPCgd1cGRhdGVzGAUgASgFEhQKDG1lcmdlZENvbW1pdBgGIAEoCRIWCg5wdWxsUmVxdWVzdFVSTBgHIAEoCSK+AQoUU291cmNlVXBkYXRlQmF0Y2hpbmcSDwoHcmVwb1VSTBgBIAEoCRIOCgZicmFuY2gYAiABKAkSFQoNcm9sbGluZ0JyYW5jaBgDIAEoCRISCgptZXJnZUFmdGVyGAQgASgFEkUKDW1lcmdlSW50ZXJ2YWwYBSABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuRHVyYXRpb24SEwoLbWVyZ2VNZXRob2QYBiABKAkizQEKBVN0YWdlEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESPQoEc3BlYxgCIAEoCzIvLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5TdGFnZVNwZWMSQQoGc3RhdHVzGAMgASgLMjEuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlN0YWdlU3RhdHVzIuoBCgtTdGFnZUltYWdlcxI8CgdjdXJyZW50GAEgAygLMisuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkltYWdlEhUKDWN1cnJlbnRTb3VyY2UYAiABKAkSOQoEbmV4dBgDIAMoCzIrLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5JbWFnZRJLCgh1cHN0cmVhbRgEIAMoCzI5LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5VcHN0cmVhbVN0YWdlSW1hZ2VzIokBCglTdGFnZUxpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESOgoFaXRlbXMYAiADKAsyKy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU3RhZ2UiigEKDFN0YWdlT3ZlcmxheRIMCgRuYW1lGAEgASgJEgwKBHBhdGgYAiABKAkSFgoOcmVuZGVyZWRCcmFuY2gYAyABKAkSRgoLZ2l0SWRlbnRpdHkYBCABKAsyMS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuR2l0SWRlbnRpdHki6QoKCVN0YWdlU3BlYxINCgVzaGFyZBgEIAEoCRJOChByZXF1ZXN0ZWRGcmVpZ2h0GAUgAygLMjQuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRSZXF1ZXN0ElIKEXByb21vdGlvblRlbXBsYXRlGAYgASgLMjcuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlByb21vdGlvblRlbXBsYXRlEkgKDHZlcmlmaWNhdGlvbhgDIAEoCzIyLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5WZXJpZmljYXRpb24SSgoKYXJnb0NEQXBwcxgHIAMoCzI2LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5NYW5hZ2VkQXJnb0NEQXBwElgKEXNlcnZpY2VBY2NvdW50UmVmGAggASgLMj0uZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlNlcnZpY2VBY2NvdW50UmVmZXJlbmNlEhUKDWFyZ29DRENvbnRleHQYCSABKAkSGQoRcHJldmVudERvd25ncmFkZXMYCiABKAgSTAoOcmVuZGVyZWRCcmFuY2gYCyABKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUmVuZGVyZWRCcmFuY2gSSQoNaW1hZ2VNYXBwaW5ncxgMIAMoCzIyLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5JbWFnZU1hcHBpbmcSSAoMdG9vbFZlcnNpb25zGA0gASgLMjIuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlRvb2xWZXJzaW9ucxIZChFwcm9tb3Rpb25Qcmlvcml0eRgOIAEoBRJECghvdmVybGF5cxgPIAMoCzIyLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5TdGFnZU92ZXJsYXkSWAoRcHJvbW90aW9uQXBwcm92YWwYECABKAsyPS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uQXBwcm92YWxQb2xpY3kSTwoIbWV0YWRhdGEYESADKAsyPS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU3RhZ2VTcGVjLk1ldGFkYXRhRW50cnkSTAoOcmVzb3VyY2VMaW1pdHMYEiABKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUmVzb3VyY2VMaW1pdHMSRgoLZHJ5UnVuQXBwbHkYEyABKAsyMS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRHJ5UnVuQXBwbHkSGQoRaW1hZ2VDb21wbGV0ZW5lc3MYFCABKAkSWAoUc291cmNlVXBkYXRlQmF0Y2hpbmcYFSABKAsyOi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuU291cmNlVXBkYXRlQmF0Y2hpbmcSFAoMc3RyaWN0UmVuZGVyGBYgASgIEkYKC2dpdElkZW50aXR5GBcgASgLMjEuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkdpdElkZW50aXR5Gi8KDU1ldGFkYXRhRW50cnkSCwoDa2V5GAEgASgJEg0KBXZhbHVlGAIgASgJOgI4ASKJBwoLU3RhZ2VTdGF0dXMSQwoKY29uZGl0aW9ucxgNIAMoCzIvLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5Db25kaXRpb24SGgoSbGFzdEhhbmRsZWRSZWZyZXNoGAsgASgJEhkKEWxhc3RIYW5kbGVkUmVwbGF5GBIgASgJEg0KBXBoYXNlGAEgASgJEk8KDmZyZWlnaHRIaXN0b3J5GAQgAygLMjcuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkZyZWlnaHRDb2xsZWN0aW9uEhYKDmZyZWlnaHRTdW1tYXJ5GAwgASgJEjwKBmhlYWx0aBgIIAEoCzIsLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5IZWFsdGgSDwoHbWVzc2FnZRgJIAEoCRIaChJvYnNlcnZlZEdlbmVyYXRpb24YBiABKAMSUgoQY3VycmVudFByb21vdGlvbhgHIAEoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25SZWZlcmVuY2USTwoNbGFzdFByb21vdGlvbhgKIAEoCzI4LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25SZWZlcmVuY2USTwoQcHJvbW90aW9uSGlzdG9yeRgOIAMoCzI1LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5Qcm9tb3Rpb25SZWNvcmQSTAoOcHJvbW90aW9uUXVldWUYDyABKAsyNC5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuUHJvbW90aW9uUXVldWUSQQoGaW1hZ2VzGBAgASgLMjEuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlN0YWdlSW1hZ2VzEjoKBWRyaWZ0GBEgASgLMisuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkRyaWZ0ElgKFHJlbmRlcmVkQnJhbmNoQ29tbWl0GBMgASgLMjouZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLlJlbmRlcmVkQnJhbmNoQ29tbWl0IpkCChVTdGVwRXhlY3V0aW9uTWV0YWRhdGESDQoFYWxpYXMYASABKAkSPQoJc3RhcnRlZEF0GAIgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUSPgoKZmluaXNoZWRBdBgDIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lEhIKCmVycm9yQ291bnQYBCABKA0SDgoGc3RhdHVzGAUgASgJEg8KB21lc3NhZ2UYBiABKAkSPQoJd2FpdFVudGlsGAcgASgLMiouazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLlRpbWUiLwoMVG9vbFZlcnNpb25zEhEKCWt1c3RvbWl6ZRgBIAEoCRIMCgRoZWxtGAIgASgJInAKE1Vwc3RyZWFtU3RhZ2VJbWFnZXMSDQoFc3RhZ2UYASABKAkSSgoLZGlmZmVyZW5jZXMYAiADKAsyNS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSW1hZ2VEaWZmZXJlbmNlIosCCgxWZXJpZmljYXRpb24SWgoRYW5hbHlzaXNUZW1wbGF0ZXMYASADKAsyPy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQW5hbHlzaXNUZW1wbGF0ZVJlZmVyZW5jZRJWChNhbmFseXNpc1J1bk1ldGFkYXRhGAIgASgLMjkuZ2l0aHViLmNvbS5ha3VpdHkua2FyZ28uYXBpLnYxYWxwaGExLkFuYWx5c2lzUnVuTWV0YWRhdGESRwoEYXJncxgDIAMoCzI5LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5BbmFseXNpc1J1bkFyZ3VtZW50Ip0CChBWZXJpZmljYXRpb25JbmZvEgoKAmlkGAQgASgJEg0KBWFjdG9yGAcgASgJEj0KCXN0YXJ0VGltZRgFIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lEg0KBXBoYXNlGAEgASgJEg8KB21lc3NhZ2UYAiABKAkSTwoLYW5hbHlzaXNSdW4YAyABKAsyOi5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuQW5hbHlzaXNSdW5SZWZlcmVuY2USPgoKZmluaXNoVGltZRgGIAEoCzIqLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5UaW1lIpQBCg1WZXJpZmllZFN0YWdlEj4KCnZlcmlmaWVkQXQYASABKAsyKi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuVGltZRJDCgtsb25nZXN0U29haxgCIAEoCzIuLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5EdXJhdGlvbiLZAQoJV2FyZWhvdXNlEkIKCG1ldGFkYXRhGAEgASgLMjAuazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLk9iamVjdE1ldGESQQoEc3BlYxgCIAEoCzIzLmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5XYXJlaG91c2VTcGVjEkUKBnN0YXR1cxgDIAEoCzI1LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5XYXJlaG91c2VTdGF0dXMikQEKDVdhcmVob3VzZUxpc3QSQAoIbWV0YWRhdGEYASABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuTGlzdE1ldGESPgoFaXRlbXMYAiADKAsyLy5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuV2FyZWhvdXNlIpoCCg1XYXJlaG91c2VTcGVjEg0KBXNoYXJkGAIgASgJEkAKCGludGVydmFsGAQgASgLMi4uazhzLmlvLmFwaW1hY2hpbmVyeS5wa2cuYXBpcy5tZXRhLnYxLkR1cmF0aW9uEh0KFWZyZWlnaHRDcmVhdGlvblBvbGljeRgDIAEoCRJKChJmcmVpZ2h0QmF0Y2hXaW5kb3cYBSABKAsyLi5rOHMuaW8uYXBpbWFjaGluZXJ5LnBrZy5hcGlzLm1ldGEudjEuRHVyYXRpb24STQoNc3Vic2NyaXB0aW9ucxgBIAMoCzI2LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5SZXBvU3Vic2NyaXB0aW9uIqYDCg9XYXJlaG91c2VTdGF0dXMSQwoKY29uZGl0aW9ucxgJIAMoCzIvLms4cy5pby5hcGltYWNoaW5lcnkucGtnLmFwaXMubWV0YS52MS5Db25kaXRpb24SGgoSbGFzdEhhbmRsZWRSZWZyZXNoGAYgASgJEhoKEm9ic2VydmVkR2VuZXJhdGlvbhgEIAEoAxIVCg1sYXN0RnJlaWdodElEGAggASgJElYKE2Rpc2NvdmVyZWRBcnRpZmFjdHMYByABKAsyOS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuRGlzY292ZXJlZEFydGlmYWN0cxJMCg5wZW5kaW5nRnJlaWdodBgKIAEoCzI0LmdpdGh1Yi5jb20uYWt1aXR5LmthcmdvLmFwaS52MWFscGhhMS5QZW5kaW5nRnJlaWdodBJZChJpbWFnZVN1YnNjcmlwdGlvbnMYCyADKAsyPS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTEuSW1hZ2VTdWJzY3JpcHRpb25TdGF0dXNClwIKKGNvbS5naXRodWIuY29tLmFrdWl0eS5rYXJnby5hcGkudjFhbHBoYTFCDkdlbmVyYXRlZFByb3RvUAFaJGdpdGh1Yi5jb20vYWt1aXR5L2thcmdvL2FwaS92MWFscGhhMaICBUdDQUtBqgIkR2l0aHViLkNvbS5Ba3VpdHkuS2FyZ28uQXBpLlYxYWxwaGExygIkR2l0aHViXENvbVxBa3VpdHlcS2FyZ29cQXBpXFYxYWxwaGEx4gIwR2l0aHViXENvbVxBa3VpdHlcS2FyZ29cQXBpXFYxYWxwaGExXEdQQk1ldGFkYXRh6gIpR2l0aHViOjpDb206OkFrdWl0eTo6S2FyZ286OkFwaTo6VjFhbHBoYTE", [file_k8s_io_apiextensions_apiserver_pkg_apis_apiextensions_v1_generated, file_k8s_io_apimachinery_pkg_api_resource_generated, file_k8s_io_apimachinery_pkg_apis_meta_v1_generated, file_k8s_io_apimachinery_pkg_runtime_generated, file_k8s_io_apimachinery_pkg_runtime_schema_generated]);
 
 /**
  * AnalysisRunArgument represents an argument to be added to an AnalysisRun.
@@ -672,6 +674,373 @@ export type DiscoveredImageReference = Message<"github.com.akuity.kargo.api.v1al
 export const DiscoveredImageReferenceSchema: GenMessage<DiscoveredImageReference> = /*@__PURE__*/
   messageDesc(file_v1alpha1_generated, 16);
 
+/**
+ * Drift describes changes that were made to a branch after a Promotion pushed
+ * to it. The changes themselves are the difference between PromotedCommit and
+ * DriftedCommit.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.Drift
+ */
+export type Drift = Message<"github.com.akuity.kargo.api.v1alpha1.Drift"> & {
+  /**
+   * RepoURL is the URL of the repository containing the branch that drifted.
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * Branch is the name of the branch that drifted.
+   *
+   * @generated from field: optional string branch = 2;
+   */
+  branch: string;
+
+  /**
+   * PromotedCommit is the ID of the commit that was pushed to the branch by
+   * the last Promotion.
+   *
+   * @generated from field: optional string promotedCommit = 3;
+   */
+  promotedCommit: string;
+
+  /**
+   * DriftedCommit is the ID of the commit the branch pointed to when the
+   * drift was detected.
+   *
+   * @generated from field: optional string driftedCommit = 4;
+   */
+  driftedCommit: string;
+
+  /**
+   * DetectedAt is the time at which the drift was first detected.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time detectedAt = 5;
+   */
+  detectedAt?: Time;
+
+  /**
+   * PullRequest describes the pull request that was opened to port the drift
+   * to the branch the promoted content is derived from. It is absent if no
+   * pull request was requested, or if it has not been opened yet.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.DriftPullRequest pullRequest = 6;
+   */
+  pullRequest?: DriftPullRequest;
+
+  /**
+   * Resolution describes how the drift has been resolved. As long as it is
+   * empty, no further Promotions to the Stage are started.
+   *
+   * @generated from field: optional string resolution = 7;
+   */
+  resolution: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.Drift.
+ * Use `create(DriftSchema)` to create a new message.
+ */
+export const DriftSchema: GenMessage<Drift> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 17);
+
+/**
+ * DriftPullRequest describes a pull request that contains the changes of a
+ * Drift as a patch, for a human to port to the source branch.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.DriftPullRequest
+ */
+export type DriftPullRequest = Message<"github.com.akuity.kargo.api.v1alpha1.DriftPullRequest"> & {
+  /**
+   * RepoURL is the URL of the repository the pull request was opened in.
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * Number is the number of the pull request.
+   *
+   * @generated from field: optional int64 number = 2;
+   */
+  number: bigint;
+
+  /**
+   * URL is the URL of the pull request.
+   *
+   * @generated from field: optional string url = 3;
+   */
+  url: string;
+
+  /**
+   * Branch is the name of the branch containing the patch.
+   *
+   * @generated from field: optional string branch = 4;
+   */
+  branch: string;
+
+  /**
+   * PatchPath is the path of the patch, relative to the root of the
+   * repository.
+   *
+   * @generated from field: optional string patchPath = 5;
+   */
+  patchPath: string;
+
+  /**
+   * Merged is true if the pull request has been merged.
+   *
+   * @generated from field: optional bool merged = 6;
+   */
+  merged: boolean;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.DriftPullRequest.
+ * Use `create(DriftPullRequestSchema)` to create a new message.
+ */
+export const DriftPullRequestSchema: GenMessage<DriftPullRequest> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 18);
+
+/**
+ * DryRunApply describes how manifests rendered for a Stage are applied to the
+ * destination cluster of an Argo CD Application in server-side dry-run mode.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.DryRunApply
+ */
+export type DryRunApply = Message<"github.com.akuity.kargo.api.v1alpha1.DryRunApply"> & {
+  /**
+   * MaxDocuments is the maximum number of resources that are applied. The
+   * resources of very large renders beyond this number are not verified.
+   *
+   * +kubebuilder:default=500
+   * +kubebuilder:validation:Minimum=1
+   *
+   * @generated from field: optional int32 maxDocuments = 1;
+   */
+  maxDocuments: number;
+
+  /**
+   * Timeout is the maximum amount of time spent applying resources. The
+   * resources that were not applied yet when it elapses are not verified.
+   *
+   * +kubebuilder:validation:Type=string
+   * +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+   * +kubebuilder:default="2m0s"
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration timeout = 2;
+   */
+  timeout?: Duration;
+
+  /**
+   * MissingNamespaces describes what is done with resources in namespaces
+   * that do not exist in the destination cluster yet, which cannot be
+   * applied even in dry-run mode. Skip, the default, does not verify them.
+   * Create verifies that each missing namespace could be created, in
+   * dry-run mode, but still does not verify the resources in it.
+   *
+   * +kubebuilder:default=Skip
+   *
+   * @generated from field: optional string missingNamespaces = 3;
+   */
+  missingNamespaces: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.DryRunApply.
+ * Use `create(DryRunApplySchema)` to create a new message.
+ */
+export const DryRunApplySchema: GenMessage<DryRunApply> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 19);
+
+/**
+ * ExecCommand describes a command that the exec-render promotion step may
+ * execute.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ExecCommand
+ */
+export type ExecCommand = Message<"github.com.akuity.kargo.api.v1alpha1.ExecCommand"> & {
+  /**
+   * Name is the name by which promotion steps refer to the command.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Path is the absolute path of the command's executable.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:Pattern=`^/`
+   *
+   * @generated from field: optional string path = 2;
+   */
+  path: string;
+
+  /**
+   * Args are arguments that are always passed to the command, ahead of any
+   * arguments specified by promotion steps.
+   *
+   * @generated from field: repeated string args = 3;
+   */
+  args: string[];
+
+  /**
+   * AllowArgs indicates whether promotion steps may pass further arguments to
+   * the command.
+   *
+   * @generated from field: optional bool allowArgs = 4;
+   */
+  allowArgs: boolean;
+
+  /**
+   * Env are the environment variables of the command's process. The process
+   * does not inherit any environment variables from the controller.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.ExecEnvVar env = 5;
+   */
+  env: ExecEnvVar[];
+
+  /**
+   * Timeout is the maximum amount of time the command may run for before it
+   * is killed. Defaults to 1m.
+   *
+   * +kubebuilder:validation:Format=duration
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration timeout = 6;
+   */
+  timeout?: Duration;
+
+  /**
+   * MaxOutputBytes is the maximum number of bytes the command may write to
+   * its standard output before it is killed. Defaults to 10MiB.
+   *
+   * +kubebuilder:validation:Minimum=1
+   *
+   * @generated from field: optional int32 maxOutputBytes = 7;
+   */
+  maxOutputBytes: number;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ExecCommand.
+ * Use `create(ExecCommandSchema)` to create a new message.
+ */
+export const ExecCommandSchema: GenMessage<ExecCommand> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 20);
+
+/**
+ * ExecEnvVar is an environment variable of the process of an ExecCommand.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ExecEnvVar
+ */
+export type ExecEnvVar = Message<"github.com.akuity.kargo.api.v1alpha1.ExecEnvVar"> & {
+  /**
+   * Name is the name of the environment variable.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Value is the value of the environment variable.
+   *
+   * @generated from field: optional string value = 2;
+   */
+  value: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ExecEnvVar.
+ * Use `create(ExecEnvVarSchema)` to create a new message.
+ */
+export const ExecEnvVarSchema: GenMessage<ExecEnvVar> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 21);
+
+/**
+ * ExecPolicy specifies the commands that the exec-render promotion step may
+ * execute. Commands are executed by the controller, in the working directory
+ * of the Promotion, and must therefore be present in the controller's image.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ExecPolicy
+ */
+export type ExecPolicy = Message<"github.com.akuity.kargo.api.v1alpha1.ExecPolicy"> & {
+  /**
+   * Commands are the commands that may be executed. Commands that are not
+   * listed may not be executed.
+   *
+   * +listType=map
+   * +listMapKey=name
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.ExecCommand commands = 1;
+   */
+  commands: ExecCommand[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ExecPolicy.
+ * Use `create(ExecPolicySchema)` to create a new message.
+ */
+export const ExecPolicySchema: GenMessage<ExecPolicy> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 22);
+
+/**
+ * ExternalModification records that a Stage's rendered branch was found to
+ * have been modified by something other than Kargo.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ExternalModification
+ */
+export type ExternalModification = Message<"github.com.akuity.kargo.api.v1alpha1.ExternalModification"> & {
+  /**
+   * RepoURL is the URL of the repository the branch belongs to.
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * Branch is the name of the branch.
+   *
+   * @generated from field: optional string branch = 2;
+   */
+  branch: string;
+
+  /**
+   * ExpectedCommit is the ID of the commit that Kargo last pushed to the
+   * branch.
+   *
+   * @generated from field: optional string expectedCommit = 3;
+   */
+  expectedCommit: string;
+
+  /**
+   * ActualCommit is the ID of the commit that the branch pointed to instead.
+   *
+   * @generated from field: optional string actualCommit = 4;
+   */
+  actualCommit: string;
+
+  /**
+   * Policy is the OnExternalModification policy that was applied.
+   *
+   * @generated from field: optional string policy = 5;
+   */
+  policy: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ExternalModification.
+ * Use `create(ExternalModificationSchema)` to create a new message.
+ */
+export const ExternalModificationSchema: GenMessage<ExternalModification> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 23);
+
 /**
  * Freight represents a collection of versioned artifacts.
  *
@@ -741,7 +1110,7 @@ export type Freight = Message<"github.com.akuity.kargo.api.v1alpha1.Freight"> &
  * Use `create(FreightSchema)` to create a new message.
  */
 export const FreightSchema: GenMessage<Freight> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 17);
+  messageDesc(file_v1alpha1_generated, 24);
 
 /**
  * FreightCollection is a collection of FreightReferences, each of which
@@ -781,7 +1150,7 @@ export type FreightCollection = Message<"github.com.akuity.kargo.api.v1alpha1.Fr
  * Use `create(FreightCollectionSchema)` to create a new message.
  */
 export const FreightCollectionSchema: GenMessage<FreightCollection> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 18);
+  messageDesc(file_v1alpha1_generated, 25);
 
 /**
  * FreightList is a list of Freight resources.
@@ -805,7 +1174,7 @@ export type FreightList = Message<"github.com.akuity.kargo.api.v1alpha1.FreightL
  * Use `create(FreightListSchema)` to create a new message.
  */
 export const FreightListSchema: GenMessage<FreightList> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 19);
+  messageDesc(file_v1alpha1_generated, 26);
 
 /**
  * FreightOrigin describes a kind of Freight in terms of where it may have
@@ -842,7 +1211,7 @@ export type FreightOrigin = Message<"github.com.akuity.kargo.api.v1alpha1.Freigh
  * Use `create(FreightOriginSchema)` to create a new message.
  */
 export const FreightOriginSchema: GenMessage<FreightOrigin> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 20);
+  messageDesc(file_v1alpha1_generated, 27);
 
 /**
  * FreightReference is a simplified representation of a piece of Freight -- not
@@ -894,7 +1263,7 @@ export type FreightReference = Message<"github.com.akuity.kargo.api.v1alpha1.Fre
  * Use `create(FreightReferenceSchema)` to create a new message.
  */
 export const FreightReferenceSchema: GenMessage<FreightReference> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 21);
+  messageDesc(file_v1alpha1_generated, 28);
 
 /**
  * FreightRequest expresses a Stage's need for Freight having originated from a
@@ -927,7 +1296,7 @@ export type FreightRequest = Message<"github.com.akuity.kargo.api.v1alpha1.Freig
  * Use `create(FreightRequestSchema)` to create a new message.
  */
 export const FreightRequestSchema: GenMessage<FreightRequest> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 22);
+  messageDesc(file_v1alpha1_generated, 29);
 
 /**
  * @generated from message github.com.akuity.kargo.api.v1alpha1.FreightSources
@@ -976,7 +1345,7 @@ export type FreightSources = Message<"github.com.akuity.kargo.api.v1alpha1.Freig
  * Use `create(FreightSourcesSchema)` to create a new message.
  */
 export const FreightSourcesSchema: GenMessage<FreightSources> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 23);
+  messageDesc(file_v1alpha1_generated, 30);
 
 /**
  * FreightStatus describes a piece of Freight's most recently observed state.
@@ -1015,43 +1384,93 @@ export type FreightStatus = Message<"github.com.akuity.kargo.api.v1alpha1.Freigh
  * Use `create(FreightStatusSchema)` to create a new message.
  */
 export const FreightStatusSchema: GenMessage<FreightStatus> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 24);
+  messageDesc(file_v1alpha1_generated, 31);
 
 /**
- * GitCommit describes a specific commit from a specific Git repository.
+ * GitClientConfig describes how the controller performs network operations
+ * (e.g. clone, fetch, and push) against Git hosts. Any setting that is not
+ * specified falls back to the value specified when the controller was
+ * installed.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.GitCommit
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.GitClientConfig
  */
-export type GitCommit = Message<"github.com.akuity.kargo.api.v1alpha1.GitCommit"> & {
+export type GitClientConfig = Message<"github.com.akuity.kargo.api.v1alpha1.GitClientConfig"> & {
   /**
-   * RepoURL is the URL of a Git repository.
+   * MaxConcurrentOpsPerHost is the maximum number of network operations the
+   * controller may perform concurrently against any single Git host. A value
+   * of 0 means no limit.
    *
-   * @generated from field: optional string repoURL = 1;
-   */
-  repoURL: string;
-
-  /**
-   * ID is the ID of a specific commit in the Git repository specified by
-   * RepoURL.
+   * +kubebuilder:validation:Minimum=0
    *
-   * @generated from field: optional string id = 2;
+   * @generated from field: optional int32 maxConcurrentOpsPerHost = 1;
    */
-  id: string;
+  maxConcurrentOpsPerHost: number;
 
   /**
-   * Branch denotes the branch of the repository where this commit was found.
+   * MaxOpsPerMinutePerHost is the maximum number of network operations the
+   * controller may start against any single Git host per minute. A value of
+   * 0 means no limit.
    *
-   * @generated from field: optional string branch = 3;
+   * +kubebuilder:validation:Minimum=0
+   *
+   * @generated from field: optional int32 maxOpsPerMinutePerHost = 2;
    */
-  branch: string;
+  maxOpsPerMinutePerHost: number;
 
   /**
-   * Tag denotes a tag in the repository that matched selection criteria and
-   * resolved to this commit.
+   * NetworkMaxAttempts is the maximum number of attempts, including the
+   * first, the controller makes for any network operation that fails for
+   * reasons that appear to be transient.
    *
-   * @generated from field: optional string tag = 4;
+   * +kubebuilder:validation:Minimum=1
+   *
+   * @generated from field: optional int32 networkMaxAttempts = 3;
    */
-  tag: string;
+  networkMaxAttempts: number;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.GitClientConfig.
+ * Use `create(GitClientConfigSchema)` to create a new message.
+ */
+export const GitClientConfigSchema: GenMessage<GitClientConfig> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 32);
+
+/**
+ * GitCommit describes a specific commit from a specific Git repository.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.GitCommit
+ */
+export type GitCommit = Message<"github.com.akuity.kargo.api.v1alpha1.GitCommit"> & {
+  /**
+   * RepoURL is the URL of a Git repository.
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * ID is the ID of a specific commit in the Git repository specified by
+   * RepoURL.
+   *
+   * @generated from field: optional string id = 2;
+   */
+  id: string;
+
+  /**
+   * Branch denotes the branch of the repository where this commit was found.
+   *
+   * @generated from field: optional string branch = 3;
+   */
+  branch: string;
+
+  /**
+   * Tag denotes a tag in the repository that matched selection criteria and
+   * resolved to this commit.
+   *
+   * @generated from field: optional string tag = 4;
+   */
+  tag: string;
 
   /**
    * Message is the message associated with the commit. At present, this only
@@ -1081,7 +1500,7 @@ export type GitCommit = Message<"github.com.akuity.kargo.api.v1alpha1.GitCommit"
  * Use `create(GitCommitSchema)` to create a new message.
  */
 export const GitCommitSchema: GenMessage<GitCommit> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 25);
+  messageDesc(file_v1alpha1_generated, 33);
 
 /**
  * GitDiscoveryResult represents the result of a Git discovery operation for a
@@ -1117,7 +1536,52 @@ export type GitDiscoveryResult = Message<"github.com.akuity.kargo.api.v1alpha1.G
  * Use `create(GitDiscoveryResultSchema)` to create a new message.
  */
 export const GitDiscoveryResultSchema: GenMessage<GitDiscoveryResult> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 26);
+  messageDesc(file_v1alpha1_generated, 34);
+
+/**
+ * GitIdentity describes the identity that commits are made as and the
+ * credentials that Git repositories are accessed with, e.g. so that branch
+ * protection rules or CODEOWNERS route the review of the changes Promotions
+ * make according to the environment they are made for.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.GitIdentity
+ */
+export type GitIdentity = Message<"github.com.akuity.kargo.api.v1alpha1.GitIdentity"> & {
+  /**
+   * Name is the name of the author and committer of commits. If not
+   * specified, the controller's default is used.
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Email is the email address of the author and committer of commits. If
+   * not specified, the controller's default is used.
+   *
+   * @generated from field: optional string email = 2;
+   */
+  email: string;
+
+  /**
+   * CredentialsSecret is the name of a Secret in the Project namespace that
+   * holds the Git credentials, e.g. a token or a GitHub App, that
+   * repositories are cloned, pushed to, and opened pull requests against
+   * with, in place of the credentials otherwise looked up for them. The
+   * Secret must be labeled as holding Git credentials. If not specified,
+   * credentials are looked up as usual.
+   *
+   * @generated from field: optional string credentialsSecret = 3;
+   */
+  credentialsSecret: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.GitIdentity.
+ * Use `create(GitIdentitySchema)` to create a new message.
+ */
+export const GitIdentitySchema: GenMessage<GitIdentity> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 35);
 
 /**
  * GitSubscription defines a subscription to a Git repository.
@@ -1287,7 +1751,7 @@ export type GitSubscription = Message<"github.com.akuity.kargo.api.v1alpha1.GitS
  * Use `create(GitSubscriptionSchema)` to create a new message.
  */
 export const GitSubscriptionSchema: GenMessage<GitSubscription> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 27);
+  messageDesc(file_v1alpha1_generated, 36);
 
 /**
  * Health describes the health of a Stage.
@@ -1331,7 +1795,7 @@ export type Health = Message<"github.com.akuity.kargo.api.v1alpha1.Health"> & {
  * Use `create(HealthSchema)` to create a new message.
  */
 export const HealthSchema: GenMessage<Health> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 28);
+  messageDesc(file_v1alpha1_generated, 37);
 
 /**
  * HealthCheckStep describes a health check directive which can be executed by
@@ -1362,7 +1826,7 @@ export type HealthCheckStep = Message<"github.com.akuity.kargo.api.v1alpha1.Heal
  * Use `create(HealthCheckStepSchema)` to create a new message.
  */
 export const HealthCheckStepSchema: GenMessage<HealthCheckStep> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 29);
+  messageDesc(file_v1alpha1_generated, 38);
 
 /**
  * Image describes a specific version of a container image.
@@ -1408,7 +1872,56 @@ export type Image = Message<"github.com.akuity.kargo.api.v1alpha1.Image"> & {
  * Use `create(ImageSchema)` to create a new message.
  */
 export const ImageSchema: GenMessage<Image> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 30);
+  messageDesc(file_v1alpha1_generated, 39);
+
+/**
+ * ImageDifference describes an image whose version differs between an upstream
+ * Stage and a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ImageDifference
+ */
+export type ImageDifference = Message<"github.com.akuity.kargo.api.v1alpha1.ImageDifference"> & {
+  /**
+   * RepoURL is the URL of the image repository.
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * Version is the tag or, in its absence, the digest of the image in the
+   * Stage. It is empty if the image has not been promoted to the Stage.
+   *
+   * @generated from field: optional string version = 2;
+   */
+  version: string;
+
+  /**
+   * UpstreamVersion is the tag or, in its absence, the digest of the image in
+   * the upstream Stage.
+   *
+   * @generated from field: optional string upstreamVersion = 3;
+   */
+  upstreamVersion: string;
+
+  /**
+   * VersionsBehind is the number of versions by which the Stage trails the
+   * upstream Stage. It is negative if the Stage is ahead. It is counted
+   * among the tags most recently discovered by the Warehouse that the image
+   * originated from, and is only set when both versions are semantic
+   * versions.
+   *
+   * @generated from field: optional int32 versionsBehind = 4;
+   */
+  versionsBehind: number;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ImageDifference.
+ * Use `create(ImageDifferenceSchema)` to create a new message.
+ */
+export const ImageDifferenceSchema: GenMessage<ImageDifference> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 40);
 
 /**
  * ImageDiscoveryResult represents the result of an image discovery operation
@@ -1454,7 +1967,157 @@ export type ImageDiscoveryResult = Message<"github.com.akuity.kargo.api.v1alpha1
  * Use `create(ImageDiscoveryResultSchema)` to create a new message.
  */
 export const ImageDiscoveryResultSchema: GenMessage<ImageDiscoveryResult> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 31);
+  messageDesc(file_v1alpha1_generated, 41);
+
+/**
+ * ImageLimits limits the number of container images that Freight may
+ * reference and how many of them are itemized in commit messages and in the
+ * status of Promotions. Any limit that is not specified falls back to its
+ * default.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ImageLimits
+ */
+export type ImageLimits = Message<"github.com.akuity.kargo.api.v1alpha1.ImageLimits"> & {
+  /**
+   * CommitMessageMaxImages is the maximum number of images itemized in commit
+   * messages generated by promotion steps. Any further images are only
+   * counted. Defaults to 20.
+   *
+   * +kubebuilder:validation:Minimum=1
+   *
+   * @generated from field: optional int32 commitMessageMaxImages = 1;
+   */
+  commitMessageMaxImages: number;
+
+  /**
+   * StatusMaxImages is the maximum number of images listed in the image
+   * digest of a Promotion's status. If the promoted Freight references more
+   * images than this, they are also omitted from the Freight recorded in the
+   * Promotion's status and must be retrieved from the Freight itself.
+   * Defaults to 10.
+   *
+   * +kubebuilder:validation:Minimum=1
+   *
+   * @generated from field: optional int32 statusMaxImages = 2;
+   */
+  statusMaxImages: number;
+
+  /**
+   * SoftLimit is the number of images above which the creation of Freight
+   * is accompanied by a warning. A value of 0 disables the warning. Defaults
+   * to 50.
+   *
+   * +kubebuilder:validation:Minimum=0
+   *
+   * @generated from field: optional int32 softLimit = 3;
+   */
+  softLimit: number;
+
+  /**
+   * HardLimit is the number of images above which the creation of Freight is
+   * rejected. A value of 0 disables the limit. Defaults to 500.
+   *
+   * +kubebuilder:validation:Minimum=0
+   *
+   * @generated from field: optional int32 hardLimit = 4;
+   */
+  hardLimit: number;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ImageLimits.
+ * Use `create(ImageLimitsSchema)` to create a new message.
+ */
+export const ImageLimitsSchema: GenMessage<ImageLimits> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 42);
+
+/**
+ * ImageMapping describes how references to the container images of one or
+ * more image repositories are rewritten.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ImageMapping
+ */
+export type ImageMapping = Message<"github.com.akuity.kargo.api.v1alpha1.ImageMapping"> & {
+  /**
+   * RepoURL is the URL of the image repository the mapping applies to. If it
+   * ends with "/*", the mapping applies to all repositories beneath that
+   * path. e.g. "registry.dev.example.com/*"
+   *
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * NewRepoURL is the URL of the image repository that references are
+   * rewritten to. It must end with "/*" if and only if RepoURL does, in which
+   * case the part of the URL matched by "*" is appended to it. e.g.
+   * "registry.prod.example.com/*". If not specified, the repository is left
+   * unchanged.
+   *
+   * @generated from field: optional string newRepoURL = 2;
+   */
+  newRepoURL: string;
+
+  /**
+   * TagPrefix is prepended to the tags of images the mapping applies to.
+   *
+   * @generated from field: optional string tagPrefix = 3;
+   */
+  tagPrefix: string;
+
+  /**
+   * TagSuffix is appended to the tags of images the mapping applies to.
+   *
+   * @generated from field: optional string tagSuffix = 4;
+   */
+  tagSuffix: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ImageMapping.
+ * Use `create(ImageMappingSchema)` to create a new message.
+ */
+export const ImageMappingSchema: GenMessage<ImageMapping> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 43);
+
+/**
+ * ImageSetDigest is a compact digest of a set of container images.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ImageSetDigest
+ */
+export type ImageSetDigest = Message<"github.com.akuity.kargo.api.v1alpha1.ImageSetDigest"> & {
+  /**
+   * Count is the number of images in the set.
+   *
+   * @generated from field: optional int32 count = 1;
+   */
+  count: number;
+
+  /**
+   * Hash is a hash of all images in the set, which changes whenever any
+   * image is added, removed, or changes its tag or digest.
+   *
+   * @generated from field: optional string hash = 2;
+   */
+  hash: string;
+
+  /**
+   * Images lists the first images in the set. It lists all images in the set
+   * if Count does not exceed the number of images listed.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Image images = 3;
+   */
+  images: Image[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ImageSetDigest.
+ * Use `create(ImageSetDigestSchema)` to create a new message.
+ */
+export const ImageSetDigestSchema: GenMessage<ImageSetDigest> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 44);
 
 /**
  * ImageSubscription defines a subscription to an image repository.
@@ -1462,6 +2125,34 @@ export const ImageDiscoveryResultSchema: GenMessage<ImageDiscoveryResult> = /*@_
  * @generated from message github.com.akuity.kargo.api.v1alpha1.ImageSubscription
  */
 export type ImageSubscription = Message<"github.com.akuity.kargo.api.v1alpha1.ImageSubscription"> & {
+  /**
+   * Name uniquely identifies the subscription among the Warehouse's image
+   * subscriptions. Its status is reported under this name in the Warehouse's
+   * status. This field is optional. When left unspecified, the subscription
+   * is identified by its RepoURL instead.
+   *
+   * +kubebuilder:validation:Optional
+   * +kubebuilder:validation:MaxLength=63
+   * +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+   *
+   * @generated from field: optional string name = 11;
+   */
+  name: string;
+
+  /**
+   * Paused specifies whether discovery of new images for this subscription
+   * is paused. While it is, the image last discovered for the subscription
+   * continues to be included in new Freight, but newer images are neither
+   * discovered nor cause Freight to be created, regardless of whether
+   * discovery was requested by a refresh or is due on the Warehouse's
+   * interval. Other subscriptions of the Warehouse are not affected.
+   *
+   * +kubebuilder:validation:Optional
+   *
+   * @generated from field: optional bool paused = 12;
+   */
+  paused: boolean;
+
   /**
    * RepoURL specifies the URL of the image repository to subscribe to. The
    * value in this field MUST NOT include an image tag. This field is required.
@@ -1601,807 +2292,2490 @@ export type ImageSubscription = Message<"github.com.akuity.kargo.api.v1alpha1.Im
  * Use `create(ImageSubscriptionSchema)` to create a new message.
  */
 export const ImageSubscriptionSchema: GenMessage<ImageSubscription> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 32);
+  messageDesc(file_v1alpha1_generated, 45);
 
 /**
- * Project is a resource type that reconciles to a specially labeled namespace
- * and other TODO: TBD project-level resources.
+ * ImageSubscriptionStatus describes the most recently observed state of an
+ * ImageSubscription.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.Project
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ImageSubscriptionStatus
  */
-export type Project = Message<"github.com.akuity.kargo.api.v1alpha1.Project"> & {
+export type ImageSubscriptionStatus = Message<"github.com.akuity.kargo.api.v1alpha1.ImageSubscriptionStatus"> & {
   /**
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
+   * Name is the name of the ImageSubscription, or its RepoURL if it has no
+   * name.
+   *
+   * @generated from field: optional string name = 1;
    */
-  metadata?: ObjectMeta;
+  name: string;
 
   /**
-   * Spec describes a Project.
+   * RepoURL is the URL of the image repository of the ImageSubscription.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ProjectSpec spec = 2;
+   * @generated from field: optional string repoURL = 2;
    */
-  spec?: ProjectSpec;
+  repoURL: string;
 
   /**
-   * Status describes the Project's current status.
+   * Paused indicates that discovery of new images for the ImageSubscription
+   * is paused.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ProjectStatus status = 3;
+   * +optional
+   *
+   * @generated from field: optional bool paused = 3;
    */
-  status?: ProjectStatus;
+  paused: boolean;
+
+  /**
+   * LastDiscoveredAt is the time at which the image repository was last
+   * polled for images, whether because discovery was due on the Warehouse's
+   * interval or because it was requested by a refresh.
+   *
+   * +optional
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time lastDiscoveredAt = 4;
+   */
+  lastDiscoveredAt?: Time;
+
+  /**
+   * LastTag is the tag of the newest image that was last discovered for the
+   * ImageSubscription.
+   *
+   * +optional
+   *
+   * @generated from field: optional string lastTag = 5;
+   */
+  lastTag: string;
+
+  /**
+   * LastFreightID is the identifier (name) of the most recent Freight that
+   * was created while discovery for the ImageSubscription was not paused.
+   *
+   * +optional
+   *
+   * @generated from field: optional string lastFreightID = 6;
+   */
+  lastFreightID: string;
+
+  /**
+   * Error describes why the most recent discovery of images for the
+   * ImageSubscription failed, e.g. because its constraint was invalid or the
+   * registry rejected the credentials. It is empty if discovery succeeded.
+   *
+   * +optional
+   *
+   * @generated from field: optional string error = 7;
+   */
+  error: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.Project.
- * Use `create(ProjectSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ImageSubscriptionStatus.
+ * Use `create(ImageSubscriptionStatusSchema)` to create a new message.
  */
-export const ProjectSchema: GenMessage<Project> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 33);
+export const ImageSubscriptionStatusSchema: GenMessage<ImageSubscriptionStatus> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 46);
 
 /**
- * ProjectList is a list of Project resources.
+ * KargoConfig is a cluster-scoped singleton resource holding configuration
+ * that applies to the Kargo controller as a whole. Settings specified here take
+ * precedence over those specified when the controller was installed, while
+ * settings specified on individual Stages (e.g. through annotations) take
+ * precedence over those specified here.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.ProjectList
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.KargoConfig
  */
-export type ProjectList = Message<"github.com.akuity.kargo.api.v1alpha1.ProjectList"> & {
+export type KargoConfig = Message<"github.com.akuity.kargo.api.v1alpha1.KargoConfig"> & {
   /**
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
    */
-  metadata?: ListMeta;
+  metadata?: ObjectMeta;
 
   /**
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Project items = 2;
+   * Spec describes the configuration of the Kargo controller.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.KargoConfigSpec spec = 2;
    */
-  items: Project[];
+  spec?: KargoConfigSpec;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.ProjectList.
- * Use `create(ProjectListSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.KargoConfig.
+ * Use `create(KargoConfigSchema)` to create a new message.
  */
-export const ProjectListSchema: GenMessage<ProjectList> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 34);
+export const KargoConfigSchema: GenMessage<KargoConfig> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 47);
 
 /**
- * ProjectSpec describes a Project.
+ * KargoConfigList contains a list of KargoConfigs.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.ProjectSpec
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.KargoConfigList
  */
-export type ProjectSpec = Message<"github.com.akuity.kargo.api.v1alpha1.ProjectSpec"> & {
+export type KargoConfigList = Message<"github.com.akuity.kargo.api.v1alpha1.KargoConfigList"> & {
   /**
-   * PromotionPolicies defines policies governing the promotion of Freight to
-   * specific Stages within this Project.
-   *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionPolicy promotionPolicies = 1;
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
    */
-  promotionPolicies: PromotionPolicy[];
+  metadata?: ListMeta;
+
+  /**
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.KargoConfig items = 2;
+   */
+  items: KargoConfig[];
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.ProjectSpec.
- * Use `create(ProjectSpecSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.KargoConfigList.
+ * Use `create(KargoConfigListSchema)` to create a new message.
  */
-export const ProjectSpecSchema: GenMessage<ProjectSpec> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 35);
+export const KargoConfigListSchema: GenMessage<KargoConfigList> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 48);
 
 /**
- * ProjectStatus describes a Project's current status.
+ * KargoConfigSpec describes the configuration of the Kargo controller.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.ProjectStatus
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.KargoConfigSpec
  */
-export type ProjectStatus = Message<"github.com.akuity.kargo.api.v1alpha1.ProjectStatus"> & {
+export type KargoConfigSpec = Message<"github.com.akuity.kargo.api.v1alpha1.KargoConfigSpec"> & {
   /**
-   * Conditions contains the last observations of the Project's current
-   * state.
-   * +patchMergeKey=type
-   * +patchStrategy=merge
-   * +listType=map
-   * +listMapKey=type
+   * PausePromotions indicates whether the execution of all Promotions should
+   * be paused. Paused Promotions resume where they left off once this is
+   * disabled again. Changes to this setting take effect without restarting
+   * the controller.
    *
-   * @generated from field: repeated k8s.io.apimachinery.pkg.apis.meta.v1.Condition conditions = 3;
+   * @generated from field: optional bool pausePromotions = 1;
    */
-  conditions: Condition[];
+  pausePromotions: boolean;
 
   /**
-   * Phase describes the Project's current phase.
+   * GitClient describes how the controller performs network operations
+   * against Git hosts. Changes to these settings take effect only after the
+   * controller has been restarted.
    *
-   * Deprecated: Use the Conditions field instead.
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitClientConfig gitClient = 2;
+   */
+  gitClient?: GitClientConfig;
+
+  /**
+   * RepoPolicy restricts the Git repositories that Promotions may access.
+   * Changes to this setting take effect without restarting the controller.
    *
-   * @generated from field: optional string phase = 1;
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.RepoPolicy repoPolicy = 3;
    */
-  phase: string;
+  repoPolicy?: RepoPolicy;
 
   /**
-   * Message is a display message about the Project, including any errors
-   * preventing the Project from being reconciled. i.e. If the Phase field has a
-   * value of CreationFailed, this field can be expected to explain why.
+   * ImageLimits limits the number of container images that Freight may
+   * reference and how many of them are itemized in commit messages and in
+   * the status of Promotions. Changes to these settings take effect without
+   * restarting the controller.
    *
-   * Deprecated: Use the Conditions field instead.
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ImageLimits imageLimits = 4;
+   */
+  imageLimits?: ImageLimits;
+
+  /**
+   * ExecPolicy specifies the commands that the exec-render promotion step may
+   * execute. If it is not specified, no commands may be executed. Changes to
+   * this setting take effect without restarting the controller.
    *
-   * @generated from field: optional string message = 2;
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ExecPolicy execPolicy = 5;
    */
-  message: string;
+  execPolicy?: ExecPolicy;
+
+  /**
+   * ResourceLimits limits the size of the Git repositories that Promotions
+   * clone and of the manifests they render. Stages may override these limits.
+   * Changes to these settings take effect without restarting the controller.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ResourceLimits resourceLimits = 6;
+   */
+  resourceLimits?: ResourceLimits;
+
+  /**
+   * RemoteBasePolicy restricts the hosts that the kustomize-build and
+   * kustomize-set-image promotion steps may fetch remote Kustomize bases
+   * from. If it is not specified, remote bases may be fetched from any host,
+   * subject to the RepoPolicy. Changes to this setting take effect without
+   * restarting the controller.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.RemoteBasePolicy remoteBasePolicy = 7;
+   */
+  remoteBasePolicy?: RemoteBasePolicy;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.ProjectStatus.
- * Use `create(ProjectStatusSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.KargoConfigSpec.
+ * Use `create(KargoConfigSpecSchema)` to create a new message.
  */
-export const ProjectStatusSchema: GenMessage<ProjectStatus> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 36);
+export const KargoConfigSpecSchema: GenMessage<KargoConfigSpec> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 49);
 
 /**
- * Promotion represents a request to transition a particular Stage into a
- * particular Freight.
+ * ManagedArgoCDApp is a template for an Argo CD Application whose lifecycle is
+ * managed by a Stage.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.Promotion
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDApp
  */
-export type Promotion = Message<"github.com.akuity.kargo.api.v1alpha1.Promotion"> & {
+export type ManagedArgoCDApp = Message<"github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDApp"> & {
   /**
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
+   * Name is the name of the Application.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string name = 1;
    */
-  metadata?: ObjectMeta;
+  name: string;
 
   /**
-   * Spec describes the desired transition of a specific Stage into a specific
-   * Freight.
+   * Namespace is the namespace of the Application. If not specified, the
+   * namespace Argo CD is installed in is used.
+   *
+   * @generated from field: optional string namespace = 2;
+   */
+  namespace: string;
+
+  /**
+   * Project is the Argo CD project the Application belongs to. If not
+   * specified, the "default" project is used.
+   *
+   * @generated from field: optional string project = 3;
+   */
+  project: string;
+
+  /**
+   * Source describes where the Application's manifests are obtained from.
    *
    * +kubebuilder:validation:Required
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionSpec spec = 2;
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSource source = 4;
    */
-  spec?: PromotionSpec;
+  source?: ManagedArgoCDAppSource;
 
   /**
-   * Status describes the current state of the transition represented by this
-   * Promotion.
+   * Destination describes where the Application's manifests are deployed.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionStatus status = 3;
+   * +kubebuilder:validation:Required
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppDestination destination = 5;
    */
-  status?: PromotionStatus;
+  destination?: ManagedArgoCDAppDestination;
+
+  /**
+   * SyncPolicy describes when and how the Application is synced. If not
+   * specified, the Application's sync policy is left unmanaged.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSyncPolicy syncPolicy = 6;
+   */
+  syncPolicy?: ManagedArgoCDAppSyncPolicy;
+
+  /**
+   * Adopt indicates whether an existing Application with the same name that
+   * was not created by Kargo may be taken over by the Stage. If false, the
+   * controller refuses to modify such an Application.
+   *
+   * @generated from field: optional bool adopt = 7;
+   */
+  adopt: boolean;
+
+  /**
+   * DeletionPolicy describes what happens to the Application when the Stage
+   * is deleted. Defaults to Orphan.
+   *
+   * +kubebuilder:default=Orphan
+   *
+   * @generated from field: optional string deletionPolicy = 8;
+   */
+  deletionPolicy: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.Promotion.
- * Use `create(PromotionSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDApp.
+ * Use `create(ManagedArgoCDAppSchema)` to create a new message.
  */
-export const PromotionSchema: GenMessage<Promotion> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 37);
+export const ManagedArgoCDAppSchema: GenMessage<ManagedArgoCDApp> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 50);
 
 /**
- * PromotionList contains a list of Promotion
+ * ManagedArgoCDAppDestination describes where the manifests of an Argo CD
+ * Application managed by a Stage are deployed.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionList
+ * +kubebuilder:validation:XValidation:message="exactly one of server or name must be specified",rule="has(self.server) ? !has(self.name) : has(self.name)"
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppDestination
  */
-export type PromotionList = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionList"> & {
+export type ManagedArgoCDAppDestination = Message<"github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppDestination"> & {
   /**
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
+   * Server is the URL of the Kubernetes API server of the target cluster.
+   * Mutually exclusive with Name.
+   *
+   * @generated from field: optional string server = 1;
    */
-  metadata?: ListMeta;
+  server: string;
 
   /**
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Promotion items = 2;
+   * Name is the name of the target cluster, as known to Argo CD. Mutually
+   * exclusive with Server.
+   *
+   * @generated from field: optional string name = 2;
    */
-  items: Promotion[];
+  name: string;
+
+  /**
+   * Namespace is the namespace manifests are deployed to.
+   *
+   * @generated from field: optional string namespace = 3;
+   */
+  namespace: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionList.
- * Use `create(PromotionListSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppDestination.
+ * Use `create(ManagedArgoCDAppDestinationSchema)` to create a new message.
  */
-export const PromotionListSchema: GenMessage<PromotionList> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 38);
+export const ManagedArgoCDAppDestinationSchema: GenMessage<ManagedArgoCDAppDestination> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 51);
 
 /**
- * PromotionPolicy defines policies governing the promotion of Freight to a
- * specific Stage.
+ * ManagedArgoCDAppSource describes where the manifests of an Argo CD
+ * Application managed by a Stage are obtained from.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionPolicy
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSource
  */
-export type PromotionPolicy = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionPolicy"> & {
+export type ManagedArgoCDAppSource = Message<"github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSource"> & {
   /**
+   * RepoURL is the URL of the repository containing the manifests.
+   *
+   * +kubebuilder:validation:Required
    * +kubebuilder:validation:MinLength=1
-   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
    *
-   * @generated from field: optional string stage = 1;
+   * @generated from field: optional string repoURL = 1;
    */
-  stage: string;
+  repoURL: string;
 
   /**
-   * AutoPromotionEnabled indicates whether new Freight can automatically be
-   * promoted into the Stage referenced by the Stage field. Note: There are may
-   * be other conditions also required for an auto-promotion to occur. This
-   * field defaults to false, but is commonly set to true for Stages that
-   * subscribe to Warehouses instead of other, upstream Stages. This allows
-   * users to define Stages that are automatically updated as soon as new
-   * artifacts are detected.
+   * TargetRevision is the revision (e.g. a branch) of the repository to
+   * deploy. Note that this field is kept in sync by the controller and should
+   * therefore not also be updated by promotion steps.
    *
-   * @generated from field: optional bool autoPromotionEnabled = 2;
+   * @generated from field: optional string targetRevision = 2;
    */
-  autoPromotionEnabled: boolean;
+  targetRevision: string;
+
+  /**
+   * Path is the path to the manifests within the repository.
+   *
+   * @generated from field: optional string path = 3;
+   */
+  path: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionPolicy.
- * Use `create(PromotionPolicySchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSource.
+ * Use `create(ManagedArgoCDAppSourceSchema)` to create a new message.
  */
-export const PromotionPolicySchema: GenMessage<PromotionPolicy> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 39);
+export const ManagedArgoCDAppSourceSchema: GenMessage<ManagedArgoCDAppSource> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 52);
 
 /**
- * PromotionReference contains the relevant information about a Promotion
- * as observed by a Stage.
+ * ManagedArgoCDAppSyncPolicy describes when and how an Argo CD Application
+ * managed by a Stage is synced.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionReference
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSyncPolicy
  */
-export type PromotionReference = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionReference"> & {
+export type ManagedArgoCDAppSyncPolicy = Message<"github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSyncPolicy"> & {
   /**
-   * Name is the name of the Promotion.
+   * Automated indicates whether Argo CD should automatically sync the
+   * Application when it is out of sync.
    *
-   * @generated from field: optional string name = 1;
+   * @generated from field: optional bool automated = 1;
    */
-  name: string;
+  automated: boolean;
 
   /**
-   * Freight is the freight being promoted.
+   * Prune indicates whether automated syncs may delete resources that are no
+   * longer defined in the source.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightReference freight = 2;
+   * @generated from field: optional bool prune = 2;
    */
-  freight?: FreightReference;
+  prune: boolean;
 
   /**
-   * Status is the (optional) status of the Promotion.
+   * SelfHeal indicates whether automated syncs are triggered when the live
+   * state deviates from the desired state.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionStatus status = 3;
+   * @generated from field: optional bool selfHeal = 3;
    */
-  status?: PromotionStatus;
+  selfHeal: boolean;
 
   /**
-   * FinishedAt is the time at which the Promotion was completed.
+   * SyncOptions are options applied to every sync of the Application.
    *
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time finishedAt = 4;
+   * @generated from field: repeated string syncOptions = 4;
    */
-  finishedAt?: Time;
+  syncOptions: string[];
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionReference.
- * Use `create(PromotionReferenceSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDAppSyncPolicy.
+ * Use `create(ManagedArgoCDAppSyncPolicySchema)` to create a new message.
  */
-export const PromotionReferenceSchema: GenMessage<PromotionReference> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 40);
+export const ManagedArgoCDAppSyncPolicySchema: GenMessage<ManagedArgoCDAppSyncPolicy> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 53);
 
 /**
- * PromotionSpec describes the desired transition of a specific Stage into a
- * specific Freight.
+ * OriginCommit identifies a commit to an application's source repository.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionSpec
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.OriginCommit
  */
-export type PromotionSpec = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionSpec"> & {
+export type OriginCommit = Message<"github.com.akuity.kargo.api.v1alpha1.OriginCommit"> & {
   /**
-   * Stage specifies the name of the Stage to which this Promotion
-   * applies. The Stage referenced by this field MUST be in the same
-   * namespace as the Promotion.
+   * RepoURL is the URL of the source repository.
    *
-   * +kubebuilder:validation:Required
    * +kubebuilder:validation:MinLength=1
-   * +kubebuilder:validation:MaxLength=253
-   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
    *
-   * @generated from field: optional string stage = 1;
+   * @generated from field: optional string repoURL = 1;
    */
-  stage: string;
+  repoURL: string;
 
   /**
-   * Freight specifies the piece of Freight to be promoted into the Stage
-   * referenced by the Stage field.
+   * ID is the ID (SHA) of the commit.
    *
-   * +kubebuilder:validation:Required
    * +kubebuilder:validation:MinLength=1
-   * +kubebuilder:validation:MaxLength=253
-   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
-   *
-   * @generated from field: optional string freight = 2;
-   */
-  freight: string;
-
-  /**
-   * Vars is a list of variables that can be referenced by expressions in
-   * promotion steps.
-   *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 4;
-   */
-  vars: PromotionVariable[];
-
-  /**
-   * Steps specifies the directives to be executed as part of this Promotion.
-   * The order in which the directives are executed is the order in which they
-   * are listed in this field.
-   *
-   * +kubebuilder:validation:Required
-   * +kubebuilder:validation:MinItems=1
-   * +kubebuilder:validation:items:XValidation:message="Promotion step must have uses set and must not reference a task",rule="has(self.uses) && !has(self.task)"
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionStep steps = 3;
+   * @generated from field: optional string id = 2;
    */
-  steps: PromotionStep[];
+  id: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionSpec.
- * Use `create(PromotionSpecSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.OriginCommit.
+ * Use `create(OriginCommitSchema)` to create a new message.
  */
-export const PromotionSpecSchema: GenMessage<PromotionSpec> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 41);
+export const OriginCommitSchema: GenMessage<OriginCommit> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 54);
 
 /**
- * PromotionStatus describes the current state of the transition represented by
- * a Promotion.
+ * PendingFreight describes Freight whose creation has been deferred by a
+ * Warehouse's FreightBatchWindow.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionStatus
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PendingFreight
  */
-export type PromotionStatus = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionStatus"> & {
+export type PendingFreight = Message<"github.com.akuity.kargo.api.v1alpha1.PendingFreight"> & {
   /**
-   * LastHandledRefresh holds the value of the most recent AnnotationKeyRefresh
-   * annotation that was handled by the controller. This field can be used to
-   * determine whether the request to refresh the resource has been handled.
-   * +optional
+   * ID is the system-assigned identifier (name) of the Freight that would be
+   * created from the latest discovered artifacts. It is updated in place as
+   * further changes are discovered within the batch window.
    *
-   * @generated from field: optional string lastHandledRefresh = 4;
+   * @generated from field: optional string id = 1;
    */
-  lastHandledRefresh: string;
+  id: string;
 
   /**
-   * Phase describes where the Promotion currently is in its lifecycle.
+   * Since is the time at which changes to the latest artifacts were first
+   * discovered, and therefore the time at which the batch window opened.
    *
-   * @generated from field: optional string phase = 1;
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time since = 2;
    */
-  phase: string;
+  since?: Time;
 
   /**
-   * Message is a display message about the promotion, including any errors
-   * preventing the Promotion controller from executing this Promotion.
-   * i.e. If the Phase field has a value of Failed, this field can be expected
-   * to explain why.
+   * Refreshes holds the values of the AnnotationKeyRefresh annotations that
+   * were handled while the batch window was open. These identify the refresh
+   * requests that contributed to the Freight.
    *
-   * @generated from field: optional string message = 2;
+   * +optional
+   *
+   * @generated from field: repeated string refreshes = 3;
+   */
+  refreshes: string[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PendingFreight.
+ * Use `create(PendingFreightSchema)` to create a new message.
+ */
+export const PendingFreightSchema: GenMessage<PendingFreight> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 55);
+
+/**
+ * Project is a resource type that reconciles to a specially labeled namespace
+ * and other TODO: TBD project-level resources.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.Project
+ */
+export type Project = Message<"github.com.akuity.kargo.api.v1alpha1.Project"> & {
+  /**
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
+   */
+  metadata?: ObjectMeta;
+
+  /**
+   * Spec describes a Project.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ProjectSpec spec = 2;
+   */
+  spec?: ProjectSpec;
+
+  /**
+   * Status describes the Project's current status.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ProjectStatus status = 3;
+   */
+  status?: ProjectStatus;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.Project.
+ * Use `create(ProjectSchema)` to create a new message.
+ */
+export const ProjectSchema: GenMessage<Project> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 56);
+
+/**
+ * ProjectList is a list of Project resources.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ProjectList
+ */
+export type ProjectList = Message<"github.com.akuity.kargo.api.v1alpha1.ProjectList"> & {
+  /**
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
+   */
+  metadata?: ListMeta;
+
+  /**
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Project items = 2;
+   */
+  items: Project[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ProjectList.
+ * Use `create(ProjectListSchema)` to create a new message.
+ */
+export const ProjectListSchema: GenMessage<ProjectList> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 57);
+
+/**
+ * ProjectSpec describes a Project.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ProjectSpec
+ */
+export type ProjectSpec = Message<"github.com.akuity.kargo.api.v1alpha1.ProjectSpec"> & {
+  /**
+   * PromotionPolicies defines policies governing the promotion of Freight to
+   * specific Stages within this Project.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionPolicy promotionPolicies = 1;
+   */
+  promotionPolicies: PromotionPolicy[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ProjectSpec.
+ * Use `create(ProjectSpecSchema)` to create a new message.
+ */
+export const ProjectSpecSchema: GenMessage<ProjectSpec> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 58);
+
+/**
+ * ProjectStatus describes a Project's current status.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ProjectStatus
+ */
+export type ProjectStatus = Message<"github.com.akuity.kargo.api.v1alpha1.ProjectStatus"> & {
+  /**
+   * Conditions contains the last observations of the Project's current
+   * state.
+   * +patchMergeKey=type
+   * +patchStrategy=merge
+   * +listType=map
+   * +listMapKey=type
+   *
+   * @generated from field: repeated k8s.io.apimachinery.pkg.apis.meta.v1.Condition conditions = 3;
+   */
+  conditions: Condition[];
+
+  /**
+   * Phase describes the Project's current phase.
+   *
+   * Deprecated: Use the Conditions field instead.
+   *
+   * @generated from field: optional string phase = 1;
+   */
+  phase: string;
+
+  /**
+   * Message is a display message about the Project, including any errors
+   * preventing the Project from being reconciled. i.e. If the Phase field has a
+   * value of CreationFailed, this field can be expected to explain why.
+   *
+   * Deprecated: Use the Conditions field instead.
+   *
+   * @generated from field: optional string message = 2;
+   */
+  message: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ProjectStatus.
+ * Use `create(ProjectStatusSchema)` to create a new message.
+ */
+export const ProjectStatusSchema: GenMessage<ProjectStatus> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 59);
+
+/**
+ * PromotedOverlay records the promotion of one of the overlays of a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotedOverlay
+ */
+export type PromotedOverlay = Message<"github.com.akuity.kargo.api.v1alpha1.PromotedOverlay"> & {
+  /**
+   * Name is the name of the overlay.
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Path is the path of the overlay's directory, relative to the root of the
+   * repository.
+   *
+   * @generated from field: optional string path = 2;
+   */
+  path: string;
+
+  /**
+   * RenderedBranch is the name of the branch that manifests rendered from the
+   * overlay are written to.
+   *
+   * @generated from field: optional string renderedBranch = 3;
+   */
+  renderedBranch: string;
+
+  /**
+   * Commit is the ID of the commit that was pushed to RenderedBranch. It is
+   * empty until manifests rendered from the overlay have been pushed.
+   *
+   * @generated from field: optional string commit = 4;
+   */
+  commit: string;
+
+  /**
+   * GitIdentity is the identity that manifests rendered from the overlay are
+   * committed as, if the overlay specifies one in place of the Stage's.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitIdentity gitIdentity = 5;
+   */
+  gitIdentity?: GitIdentity;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotedOverlay.
+ * Use `create(PromotedOverlaySchema)` to create a new message.
+ */
+export const PromotedOverlaySchema: GenMessage<PromotedOverlay> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 60);
+
+/**
+ * Promotion represents a request to transition a particular Stage into a
+ * particular Freight.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.Promotion
+ */
+export type Promotion = Message<"github.com.akuity.kargo.api.v1alpha1.Promotion"> & {
+  /**
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
+   */
+  metadata?: ObjectMeta;
+
+  /**
+   * Spec describes the desired transition of a specific Stage into a specific
+   * Freight.
+   *
+   * +kubebuilder:validation:Required
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionSpec spec = 2;
+   */
+  spec?: PromotionSpec;
+
+  /**
+   * Status describes the current state of the transition represented by this
+   * Promotion.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionStatus status = 3;
+   */
+  status?: PromotionStatus;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.Promotion.
+ * Use `create(PromotionSchema)` to create a new message.
+ */
+export const PromotionSchema: GenMessage<Promotion> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 61);
+
+/**
+ * PromotionApproval records the approval of a Promotion.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionApproval
+ */
+export type PromotionApproval = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionApproval"> & {
+  /**
+   * Approver is the user who approved the Promotion.
+   *
+   * @generated from field: optional string approver = 1;
+   */
+  approver: string;
+
+  /**
+   * ApprovedAt is the time at which the Promotion was approved.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time approvedAt = 2;
+   */
+  approvedAt?: Time;
+
+  /**
+   * SpecHash is the hash of the Promotion's spec at the time it was
+   * approved. The approval is only valid for a spec with the same hash.
+   *
+   * @generated from field: optional string specHash = 3;
+   */
+  specHash: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionApproval.
+ * Use `create(PromotionApprovalSchema)` to create a new message.
+ */
+export const PromotionApprovalSchema: GenMessage<PromotionApproval> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 62);
+
+/**
+ * PromotionApprovalPolicy describes who may approve Promotions to a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionApprovalPolicy
+ */
+export type PromotionApprovalPolicy = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionApprovalPolicy"> & {
+  /**
+   * AllowedApprovers lists the users, groups, and ServiceAccounts that may
+   * approve Promotions to the Stage.
+   *
+   * +kubebuilder:validation:MinItems=1
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionApprover allowedApprovers = 1;
+   */
+  allowedApprovers: PromotionApprover[];
+
+  /**
+   * PreventSelfApproval indicates whether the user who created a Promotion
+   * is prevented from approving it, even if they are an allowed approver.
+   *
+   * @generated from field: optional bool preventSelfApproval = 2;
+   */
+  preventSelfApproval: boolean;
+
+  /**
+   * MaxAge optionally limits how long a Promotion may wait to be approved.
+   * A Promotion that has been waiting for longer than this, counted from the
+   * time it began waiting, is Expired instead of being executed. This
+   * prevents a Promotion from being approved long after newer Freight has
+   * become available. The deadline of an individual Promotion can be
+   * extended using the kargo.akuity.io/extend-expiry annotation. Promotions
+   * never expire if this is not specified.
+   *
+   * +kubebuilder:validation:Type=string
+   * +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration maxAge = 3;
+   */
+  maxAge?: Duration;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionApprovalPolicy.
+ * Use `create(PromotionApprovalPolicySchema)` to create a new message.
+ */
+export const PromotionApprovalPolicySchema: GenMessage<PromotionApprovalPolicy> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 63);
+
+/**
+ * PromotionApprover describes a user, group, or ServiceAccount that may approve
+ * Promotions.
+ *
+ * +kubebuilder:validation:XValidation:message="namespace may only be specified for a ServiceAccount",rule="!has(self.__namespace__) || self.kind == 'ServiceAccount'"
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionApprover
+ */
+export type PromotionApprover = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionApprover"> & {
+  /**
+   * Kind is the kind of subject: User, Group, or ServiceAccount.
+   *
+   * +kubebuilder:validation:Required
+   *
+   * @generated from field: optional string kind = 1;
+   */
+  kind: string;
+
+  /**
+   * Name is the name of the user, group, or ServiceAccount, as it is known
+   * to the Kubernetes API server.
+   *
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string name = 2;
+   */
+  name: string;
+
+  /**
+   * Namespace is the namespace of a ServiceAccount. If not specified, the
+   * namespace of the Stage is assumed.
+   *
+   * @generated from field: optional string namespace = 3;
+   */
+  namespace: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionApprover.
+ * Use `create(PromotionApproverSchema)` to create a new message.
+ */
+export const PromotionApproverSchema: GenMessage<PromotionApprover> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 64);
+
+/**
+ * PromotionLanes configures the concurrent execution of the steps of a
+ * Promotion that are assigned to lanes.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionLanes
+ */
+export type PromotionLanes = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionLanes"> & {
+  /**
+   * MaxConcurrent is the maximum number of lanes whose steps may be executed
+   * concurrently. Defaults to 4.
+   *
+   * +kubebuilder:validation:Minimum=1
+   * +kubebuilder:validation:Maximum=32
+   *
+   * @generated from field: optional int32 maxConcurrent = 1;
+   */
+  maxConcurrent: number;
+
+  /**
+   * FailFast indicates whether a step in one lane failing should interrupt
+   * the steps of all other lanes. By default, the other lanes run to
+   * completion and the failures of all lanes are reported together.
+   *
+   * @generated from field: optional bool failFast = 2;
+   */
+  failFast: boolean;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionLanes.
+ * Use `create(PromotionLanesSchema)` to create a new message.
+ */
+export const PromotionLanesSchema: GenMessage<PromotionLanes> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 65);
+
+/**
+ * PromotionList contains a list of Promotion
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionList
+ */
+export type PromotionList = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionList"> & {
+  /**
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
+   */
+  metadata?: ListMeta;
+
+  /**
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Promotion items = 2;
+   */
+  items: Promotion[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionList.
+ * Use `create(PromotionListSchema)` to create a new message.
+ */
+export const PromotionListSchema: GenMessage<PromotionList> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 66);
+
+/**
+ * PromotionPolicy defines policies governing the promotion of Freight to a
+ * specific Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionPolicy
+ */
+export type PromotionPolicy = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionPolicy"> & {
+  /**
+   * +kubebuilder:validation:MinLength=1
+   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+   *
+   * @generated from field: optional string stage = 1;
+   */
+  stage: string;
+
+  /**
+   * AutoPromotionEnabled indicates whether new Freight can automatically be
+   * promoted into the Stage referenced by the Stage field. Note: There are may
+   * be other conditions also required for an auto-promotion to occur. This
+   * field defaults to false, but is commonly set to true for Stages that
+   * subscribe to Warehouses instead of other, upstream Stages. This allows
+   * users to define Stages that are automatically updated as soon as new
+   * artifacts are detected.
+   *
+   * @generated from field: optional bool autoPromotionEnabled = 2;
+   */
+  autoPromotionEnabled: boolean;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionPolicy.
+ * Use `create(PromotionPolicySchema)` to create a new message.
+ */
+export const PromotionPolicySchema: GenMessage<PromotionPolicy> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 67);
+
+/**
+ * PromotionQueue describes the Promotions that are waiting for their turn to be
+ * executed against a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionQueue
+ */
+export type PromotionQueue = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionQueue"> & {
+  /**
+   * Pending lists the names of the Promotions that are waiting for their turn,
+   * in the order in which they will be executed. The Promotion that is
+   * currently being executed is not included, as it is referenced by the
+   * Stage's CurrentPromotion field. Promotions are ordered by their effective
+   * priority, which is their priority raised for the time they have spent
+   * waiting, and Promotions of equal effective priority in the order in which
+   * they were created. This order therefore changes whenever a Promotion of
+   * higher priority is created or a Promotion has waited long enough to
+   * overtake others.
+   *
+   * @generated from field: repeated string pending = 1;
+   */
+  pending: string[];
+
+  /**
+   * EstimatedWait is an estimate of how long a Promotion created now would
+   * have to wait before it is executed. It is derived from the durations of
+   * the Promotions in the Stage's PromotionHistory, and is absent when the
+   * history does not hold enough information to make an estimate.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration estimatedWait = 2;
+   */
+  estimatedWait?: Duration;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionQueue.
+ * Use `create(PromotionQueueSchema)` to create a new message.
+ */
+export const PromotionQueueSchema: GenMessage<PromotionQueue> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 68);
+
+/**
+ * PromotionRecord is a lightweight record of a completed Promotion, retained
+ * by a Stage for reporting purposes.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionRecord
+ */
+export type PromotionRecord = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionRecord"> & {
+  /**
+   * Name is the name of the Promotion.
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Freight is the Freight that was promoted.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightReference freight = 2;
+   */
+  freight?: FreightReference;
+
+  /**
+   * Phase is the terminal phase of the Promotion.
+   *
+   * @generated from field: optional string phase = 3;
+   */
+  phase: string;
+
+  /**
+   * Message is the message of the Promotion at the time it completed. It
+   * typically explains why a Promotion did not succeed.
+   *
+   * @generated from field: optional string message = 4;
+   */
+  message: string;
+
+  /**
+   * StartedAt is the time at which the first step of the Promotion started
+   * executing.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time startedAt = 5;
+   */
+  startedAt?: Time;
+
+  /**
+   * FinishedAt is the time at which the Promotion was completed.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time finishedAt = 6;
+   */
+  finishedAt?: Time;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionRecord.
+ * Use `create(PromotionRecordSchema)` to create a new message.
+ */
+export const PromotionRecordSchema: GenMessage<PromotionRecord> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 69);
+
+/**
+ * PromotionReference contains the relevant information about a Promotion
+ * as observed by a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionReference
+ */
+export type PromotionReference = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionReference"> & {
+  /**
+   * Name is the name of the Promotion.
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Freight is the freight being promoted.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightReference freight = 2;
+   */
+  freight?: FreightReference;
+
+  /**
+   * Status is the (optional) status of the Promotion.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionStatus status = 3;
+   */
+  status?: PromotionStatus;
+
+  /**
+   * FinishedAt is the time at which the Promotion was completed.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time finishedAt = 4;
+   */
+  finishedAt?: Time;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionReference.
+ * Use `create(PromotionReferenceSchema)` to create a new message.
+ */
+export const PromotionReferenceSchema: GenMessage<PromotionReference> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 70);
+
+/**
+ * PromotionSpec describes the desired transition of a specific Stage into a
+ * specific Freight.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionSpec
+ */
+export type PromotionSpec = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionSpec"> & {
+  /**
+   * Stage specifies the name of the Stage to which this Promotion
+   * applies. The Stage referenced by this field MUST be in the same
+   * namespace as the Promotion.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinLength=1
+   * +kubebuilder:validation:MaxLength=253
+   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+   *
+   * @generated from field: optional string stage = 1;
+   */
+  stage: string;
+
+  /**
+   * Freight specifies the piece of Freight to be promoted into the Stage
+   * referenced by the Stage field.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinLength=1
+   * +kubebuilder:validation:MaxLength=253
+   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+   *
+   * @generated from field: optional string freight = 2;
+   */
+  freight: string;
+
+  /**
+   * Vars is a list of variables that can be referenced by expressions in
+   * promotion steps.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 4;
+   */
+  vars: PromotionVariable[];
+
+  /**
+   * Steps specifies the directives to be executed as part of this Promotion.
+   * The order in which the directives are executed is the order in which they
+   * are listed in this field.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinItems=1
+   * +kubebuilder:validation:items:XValidation:message="Promotion step must have uses set and must not reference a task",rule="has(self.uses) && !has(self.task)"
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionStep steps = 3;
+   */
+  steps: PromotionStep[];
+
+  /**
+   * Lanes configures the concurrent execution of steps that are assigned to
+   * lanes.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionLanes lanes = 5;
+   */
+  lanes?: PromotionLanes;
+
+  /**
+   * Priority determines the order in which Promotions waiting for their turn
+   * to be executed against the same Stage are executed. Promotions of higher
+   * priority are executed first, and Promotions of equal priority in the
+   * order in which they were created. A Promotion that is already being
+   * executed is never preempted. To prevent Promotions of low priority from
+   * waiting indefinitely, the priority of a waiting Promotion is raised
+   * periodically. If not specified when the Promotion is created, the
+   * PromotionPriority of the Stage is used.
+   *
+   * +kubebuilder:validation:Minimum=0
+   * +kubebuilder:validation:Maximum=100
+   *
+   * @generated from field: optional int32 priority = 6;
+   */
+  priority: number;
+
+  /**
+   * Approval is the PromotionApproval policy of the Stage at the time the
+   * Promotion was created. It is always set by the webhook. When set, the
+   * Promotion is not executed until one of the allowed approvers has
+   * approved it using the kargo.akuity.io/approve annotation.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionApprovalPolicy approval = 7;
+   */
+  approval?: PromotionApprovalPolicy;
+
+  /**
+   * OriginCommit identifies the commit to an application's source repository
+   * that produced the Freight being promoted, e.g. the commit a container
+   * image was built from. When set, the outcome of the Promotion is reported
+   * to that commit as a commit status, using the Git provider's API.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.OriginCommit originCommit = 8;
+   */
+  originCommit?: OriginCommit;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionSpec.
+ * Use `create(PromotionSpecSchema)` to create a new message.
+ */
+export const PromotionSpecSchema: GenMessage<PromotionSpec> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 71);
+
+/**
+ * PromotionStatus describes the current state of the transition represented by
+ * a Promotion.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionStatus
+ */
+export type PromotionStatus = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionStatus"> & {
+  /**
+   * LastHandledRefresh holds the value of the most recent AnnotationKeyRefresh
+   * annotation that was handled by the controller. This field can be used to
+   * determine whether the request to refresh the resource has been handled.
+   * +optional
+   *
+   * @generated from field: optional string lastHandledRefresh = 4;
+   */
+  lastHandledRefresh: string;
+
+  /**
+   * Phase describes where the Promotion currently is in its lifecycle.
+   *
+   * @generated from field: optional string phase = 1;
+   */
+  phase: string;
+
+  /**
+   * Message is a display message about the promotion, including any errors
+   * preventing the Promotion controller from executing this Promotion.
+   * i.e. If the Phase field has a value of Failed, this field can be expected
+   * to explain why.
+   *
+   * @generated from field: optional string message = 2;
+   */
+  message: string;
+
+  /**
+   * Freight is the detail of the piece of freight that was referenced by this promotion.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightReference freight = 5;
+   */
+  freight?: FreightReference;
+
+  /**
+   * FreightCollection contains the details of the piece of Freight referenced
+   * by this Promotion as well as any additional Freight that is carried over
+   * from the target Stage's current state.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightCollection freightCollection = 7;
+   */
+  freightCollection?: FreightCollection;
+
+  /**
+   * HealthChecks contains the health check directives to be executed after
+   * the Promotion has completed.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.HealthCheckStep healthChecks = 8;
+   */
+  healthChecks: HealthCheckStep[];
+
+  /**
+   * FinishedAt is the time when the promotion was completed.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time finishedAt = 6;
+   */
+  finishedAt?: Time;
+
+  /**
+   * CurrentStep is the index of the current promotion step being executed. This
+   * permits steps that have already run successfully to be skipped on
+   * subsequent reconciliations attempts.
+   *
+   * @generated from field: optional int64 currentStep = 9;
+   */
+  currentStep: bigint;
+
+  /**
+   * StepExecutionMetadata tracks metadata pertaining to the execution
+   * of individual promotion steps.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.StepExecutionMetadata stepExecutionMetadata = 11;
+   */
+  stepExecutionMetadata: StepExecutionMetadata[];
+
+  /**
+   * State stores the state of the promotion process between reconciliation
+   * attempts.
+   *
+   * @generated from field: optional k8s.io.apiextensions_apiserver.pkg.apis.apiextensions.v1.JSON state = 10;
+   */
+  state?: JSON;
+
+  /**
+   * RenderedBranch is the name of the branch that manifests rendered for the
+   * Stage are written to, as resolved from the Stage's RenderedBranch
+   * template when the Promotion began. It is empty if the Stage does not
+   * specify a template.
+   *
+   * @generated from field: optional string renderedBranch = 12;
+   */
+  renderedBranch: string;
+
+  /**
+   * RepoPolicyDecisions records, for each Git repository the Promotion
+   * attempted to access, whether access was allowed by the RepoPolicy of the
+   * KargoConfig resource. It is only populated when such a policy is in
+   * effect.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.RepoPolicyDecision repoPolicyDecisions = 13;
+   */
+  repoPolicyDecisions: RepoPolicyDecision[];
+
+  /**
+   * Images is a compact digest of the container images referenced by the
+   * Freight referenced by this Promotion. If there are many images, only the
+   * first few are listed, and the Freight field omits them altogether. The
+   * full set can be retrieved from the Freight referenced by the Promotion's
+   * spec.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ImageSetDigest images = 14;
+   */
+  images?: ImageSetDigest;
+
+  /**
+   * TranscriptConfigMap is the name of a ConfigMap, in the namespace of the
+   * Promotion, holding a transcript of the commands that were executed over the
+   * course of the Promotion, with any credentials redacted. It is only set if
+   * the Promotion Errored or Failed.
+   *
+   * @generated from field: optional string transcriptConfigMap = 15;
+   */
+  transcriptConfigMap: string;
+
+  /**
+   * Overlays records, for each of the overlays of the Stage, the branch that
+   * manifests rendered from it are written to, as resolved when the
+   * Promotion began, and the commit that was pushed to it, once it has been.
+   * It is empty if the Stage does not specify any overlays.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotedOverlay overlays = 16;
+   */
+  overlays: PromotedOverlay[];
+
+  /**
+   * Approval records who approved the Promotion and when, if the Stage
+   * required the Promotion to be approved. Once recorded, it is never
+   * changed.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionApproval approval = 17;
+   */
+  approval?: PromotionApproval;
+
+  /**
+   * RenderedBranchPush records the commits that the Promotion pushed to the
+   * Stage's rendered branch, if it has pushed to it.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.RenderedBranchPush renderedBranchPush = 18;
+   */
+  renderedBranchPush?: RenderedBranchPush;
+
+  /**
+   * RenderedBranchCleanup records what was done to the Stage's rendered
+   * branch after the Promotion failed, as described by the OnFailure policy
+   * of the Stage's RenderedBranch, and whether it was done successfully. It
+   * is only set if the Promotion failed after pushing to the branch.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.RenderedBranchCleanup renderedBranchCleanup = 19;
+   */
+  renderedBranchCleanup?: RenderedBranchCleanup;
+
+  /**
+   * ExternalModification records that the Stage's rendered branch did not
+   * point to the commit that Kargo last pushed to it when the Promotion was
+   * about to push to it, i.e. that the branch was modified by something
+   * other than Kargo, along with how the Stage's OnExternalModification
+   * policy dealt with it.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ExternalModification externalModification = 20;
+   */
+  externalModification?: ExternalModification;
+
+  /**
+   * Conditions contains the last observations of the Promotion's current
+   * state.
+   * +patchMergeKey=type
+   * +patchStrategy=merge
+   * +listType=map
+   * +listMapKey=type
+   *
+   * @generated from field: repeated k8s.io.apimachinery.pkg.apis.meta.v1.Condition conditions = 21;
+   */
+  conditions: Condition[];
+
+  /**
+   * AwaitingApprovalSince is the time at which the Promotion began waiting
+   * to be approved, i.e. when its Stage was first ready to execute it while
+   * it had not been approved yet. It is the time from which the MaxAge of
+   * the Promotion's approval policy is counted.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time awaitingApprovalSince = 22;
+   */
+  awaitingApprovalSince?: Time;
+
+  /**
+   * RetainedImages records the images that the Warehouse of the promoted
+   * Freight subscribes to, but that the Freight does not carry. These are
+   * left at the versions the Stage's manifests already specify, which are
+   * recorded as well once a step has read them from a Kustomization file.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.RetainedImage retainedImages = 23;
+   */
+  retainedImages: RetainedImage[];
+
+  /**
+   * SourceUpdateBatch records the batch of updates to a branch of a Git
+   * repository that the Promotion added its update to, in accordance with
+   * the SourceUpdateBatching of the Stage.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatch sourceUpdateBatch = 24;
+   */
+  sourceUpdateBatch?: SourceUpdateBatch;
+
+  /**
+   * RenderWarnings records the distinct warnings that the tools rendering
+   * the Stage's manifests, such as Kustomize, emitted over the course of the
+   * Promotion, e.g. about the use of deprecated fields.
+   *
+   * @generated from field: repeated string renderWarnings = 25;
+   */
+  renderWarnings: string[];
+
+  /**
+   * GitIdentity records the GitIdentity of the Stage that the Promotion
+   * commits to Git repositories as, as resolved when the Promotion began.
+   * Overlays of the Stage that specify an identity of their own record it
+   * among the Promotion's Overlays.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitIdentity gitIdentity = 26;
+   */
+  gitIdentity?: GitIdentity;
+
+  /**
+   * LastReconcileOutcome describes the outcome of the last reconciliation
+   * of the Promotion, i.e. what it did or why it did nothing. It is only
+   * updated when the outcome changes.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ReconcileOutcome lastReconcileOutcome = 27;
+   */
+  lastReconcileOutcome?: ReconcileOutcome;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionStatus.
+ * Use `create(PromotionStatusSchema)` to create a new message.
+ */
+export const PromotionStatusSchema: GenMessage<PromotionStatus> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 72);
+
+/**
+ * PromotionStep describes a directive to be executed as part of a Promotion.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionStep
+ */
+export type PromotionStep = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionStep"> & {
+  /**
+   * Uses identifies a runner that can execute this step.
+   *
+   * +kubebuilder:validation:Optional
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string uses = 1;
+   */
+  uses: string;
+
+  /**
+   * Task is a reference to a PromotionTask that should be inflated into a
+   * Promotion when it is built from a PromotionTemplate.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference task = 5;
+   */
+  task?: PromotionTaskReference;
+
+  /**
+   * As is the alias this step can be referred to as.
+   *
+   * @generated from field: optional string as = 2;
+   */
+  as: string;
+
+  /**
+   * Retry is the retry policy for this step.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry retry = 4;
+   */
+  retry?: PromotionStepRetry;
+
+  /**
+   * ContinueOnError indicates whether a failure of this step, once retries
+   * (if any) have been exhausted, should be tolerated. When true, the step's
+   * failure is recorded in the Promotion's StepExecutionMetadata and execution
+   * continues with the next step instead of failing the entire Promotion.
+   * This is useful for optional steps, such as notifying an external system
+   * after all other steps have completed.
+   *
+   * @generated from field: optional bool continueOnError = 7;
+   */
+  continueOnError: boolean;
+
+  /**
+   * Lane optionally assigns the step to a named lane. Consecutive steps that
+   * are assigned to lanes form a block in which the steps of different lanes
+   * are executed concurrently, while the steps of each lane are executed in
+   * the order in which they are listed. This is useful for e.g. rendering
+   * manifests for several independent environments and pushing them to
+   * distinct branches after a single shared clone. Lanes whose steps refer to
+   * overlapping paths or to the same branch are executed one after the
+   * other. Steps may only rely on the output of steps in the same lane or of
+   * steps preceding the block. Steps of a task that is assigned to a lane are
+   * assigned to the same lane unless they specify a lane of their own.
+   *
+   * +kubebuilder:validation:MaxLength=63
+   * +kubebuilder:validation:Pattern=^[a-zA-Z0-9]([a-zA-Z0-9_-]*[a-zA-Z0-9])?$
+   *
+   * @generated from field: optional string lane = 8;
+   */
+  lane: string;
+
+  /**
+   * Vars is a list of variables that can be referenced by expressions in
+   * the step's Config. The values override the values specified in the
+   * PromotionSpec.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 6;
+   */
+  vars: PromotionVariable[];
+
+  /**
+   * Config is opaque configuration for the PromotionStep that is understood
+   * only by each PromotionStep's implementation. It is legal to utilize
+   * expressions in defining values at any level of this block.
+   * See https://docs.kargo.io/references/expression-language for details.
+   *
+   * @generated from field: optional k8s.io.apiextensions_apiserver.pkg.apis.apiextensions.v1.JSON config = 3;
+   */
+  config?: JSON;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionStep.
+ * Use `create(PromotionStepSchema)` to create a new message.
+ */
+export const PromotionStepSchema: GenMessage<PromotionStep> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 73);
+
+/**
+ * PromotionStepRetry describes the retry policy for a PromotionStep.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry
+ */
+export type PromotionStepRetry = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry"> & {
+  /**
+   * Timeout is the soft maximum interval in which a step that returns a Running
+   * status (which typically indicates it's waiting for something to happen)
+   * may be retried.
+   *
+   * The maximum is a soft one because the check for whether the interval has
+   * elapsed occurs AFTER the step has run. This effectively means a step may
+   * run ONCE beyond the close of the interval.
+   *
+   * If this field is set to nil, the effective default will be a step-specific
+   * one. If no step-specific default exists (i.e. is also nil), the effective
+   * default will be the system-wide default of 0.
+   *
+   * A value of 0 will cause the step to be retried indefinitely unless the
+   * ErrorThreshold is reached.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration timeout = 1;
+   */
+  timeout?: Duration;
+
+  /**
+   * ErrorThreshold is the number of consecutive times the step must fail (for
+   * any reason) before retries are abandoned and the entire Promotion is marked
+   * as failed.
+   *
+   * If this field is set to 0, the effective default will be a step-specific
+   * one. If no step-specific default exists (i.e. is also 0), the effective
+   * default will be the system-wide default of 1.
+   *
+   * A value of 1 will cause the Promotion to be marked as failed after just
+   * a single failure; i.e. no retries will be attempted.
+   *
+   * There is no option to specify an infinite number of retries using a value
+   * such as -1.
+   *
+   * In a future release, Kargo is likely to become capable of distinguishing
+   * between recoverable and non-recoverable step failures. At that time, it is
+   * planned that unrecoverable failures will not be subject to this threshold
+   * and will immediately cause the Promotion to be marked as failed without
+   * further condition.
+   *
+   * @generated from field: optional uint32 errorThreshold = 2;
+   */
+  errorThreshold: number;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry.
+ * Use `create(PromotionStepRetrySchema)` to create a new message.
+ */
+export const PromotionStepRetrySchema: GenMessage<PromotionStepRetry> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 74);
+
+/**
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTask
+ */
+export type PromotionTask = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTask"> & {
+  /**
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
+   */
+  metadata?: ObjectMeta;
+
+  /**
+   * Spec describes the composition of a PromotionTask, including the
+   * variables available to the task and the steps.
+   *
+   * +kubebuilder:validation:Required
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec spec = 2;
+   */
+  spec?: PromotionTaskSpec;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTask.
+ * Use `create(PromotionTaskSchema)` to create a new message.
+ */
+export const PromotionTaskSchema: GenMessage<PromotionTask> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 75);
+
+/**
+ * PromotionTaskList contains a list of PromotionTasks.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTaskList
+ */
+export type PromotionTaskList = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTaskList"> & {
+  /**
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
+   */
+  metadata?: ListMeta;
+
+  /**
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionTask items = 2;
+   */
+  items: PromotionTask[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTaskList.
+ * Use `create(PromotionTaskListSchema)` to create a new message.
+ */
+export const PromotionTaskListSchema: GenMessage<PromotionTaskList> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 76);
+
+/**
+ * PromotionTaskReference describes a reference to a PromotionTask.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference
+ */
+export type PromotionTaskReference = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference"> & {
+  /**
+   * Name is the name of the (Cluster)PromotionTask.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinLength=1
+   * +kubebuilder:validation:MaxLength=253
+   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Kind is the type of the PromotionTask. Can be either PromotionTask or
+   * ClusterPromotionTask, default is PromotionTask.
+   *
+   * +kubebuilder:validation:Optional
+   * +kubebuilder:validation:Enum=PromotionTask;ClusterPromotionTask
+   *
+   * @generated from field: optional string kind = 2;
    */
-  message: string;
+  kind: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference.
+ * Use `create(PromotionTaskReferenceSchema)` to create a new message.
+ */
+export const PromotionTaskReferenceSchema: GenMessage<PromotionTaskReference> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 77);
 
+/**
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec
+ */
+export type PromotionTaskSpec = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec"> & {
   /**
-   * Freight is the detail of the piece of freight that was referenced by this promotion.
+   * Vars specifies the variables available to the PromotionTask. The
+   * values of these variables are the default values that can be
+   * overridden by the step referencing the task.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightReference freight = 5;
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 1;
    */
-  freight?: FreightReference;
+  vars: PromotionVariable[];
 
   /**
-   * FreightCollection contains the details of the piece of Freight referenced
-   * by this Promotion as well as any additional Freight that is carried over
-   * from the target Stage's current state.
+   * Steps specifies the directives to be executed as part of this
+   * PromotionTask. The steps as defined here are inflated into a
+   * Promotion when it is built from a PromotionTemplate.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.FreightCollection freightCollection = 7;
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinItems=1
+   * +kubebuilder:validation:items:XValidation:message="PromotionTask step must have uses set and must not reference another task",rule="has(self.uses) && !has(self.task)"
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionStep steps = 2;
    */
-  freightCollection?: FreightCollection;
+  steps: PromotionStep[];
+};
 
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec.
+ * Use `create(PromotionTaskSpecSchema)` to create a new message.
+ */
+export const PromotionTaskSpecSchema: GenMessage<PromotionTaskSpec> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 78);
+
+/**
+ * PromotionTemplate defines a template for a Promotion that can be used to
+ * incorporate Freight into a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTemplate
+ */
+export type PromotionTemplate = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTemplate"> & {
   /**
-   * HealthChecks contains the health check directives to be executed after
-   * the Promotion has completed.
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec spec = 1;
+   */
+  spec?: PromotionTemplateSpec;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTemplate.
+ * Use `create(PromotionTemplateSchema)` to create a new message.
+ */
+export const PromotionTemplateSchema: GenMessage<PromotionTemplate> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 79);
+
+/**
+ * PromotionTemplateSpec describes the (partial) specification of a Promotion
+ * for a Stage. This is a template that can be used to create a Promotion for a
+ * Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec
+ */
+export type PromotionTemplateSpec = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec"> & {
+  /**
+   * Vars is a list of variables that can be referenced by expressions in
+   * promotion steps.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.HealthCheckStep healthChecks = 8;
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 2;
    */
-  healthChecks: HealthCheckStep[];
+  vars: PromotionVariable[];
 
   /**
-   * FinishedAt is the time when the promotion was completed.
+   * Steps specifies the directives to be executed as part of a Promotion.
+   * The order in which the directives are executed is the order in which they
+   * are listed in this field.
    *
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time finishedAt = 6;
+   * +kubebuilder:validation:MinItems=1
+   * +kubebuilder:validation:items:XValidation:message="PromotionTemplate step must have exactly one of uses or task set",rule="(has(self.uses) ? !has(self.task) : has(self.task))"
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionStep steps = 1;
    */
-  finishedAt?: Time;
+  steps: PromotionStep[];
 
   /**
-   * CurrentStep is the index of the current promotion step being executed. This
-   * permits steps that have already run successfully to be skipped on
-   * subsequent reconciliations attempts.
+   * Lanes configures the concurrent execution of steps that are assigned to
+   * lanes.
    *
-   * @generated from field: optional int64 currentStep = 9;
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionLanes lanes = 3;
    */
-  currentStep: bigint;
+  lanes?: PromotionLanes;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec.
+ * Use `create(PromotionTemplateSpecSchema)` to create a new message.
+ */
+export const PromotionTemplateSpecSchema: GenMessage<PromotionTemplateSpec> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 80);
 
+/**
+ * PromotionVariable describes a single variable that may be referenced by
+ * expressions in promotion steps.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionVariable
+ */
+export type PromotionVariable = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionVariable"> & {
   /**
-   * StepExecutionMetadata tracks metadata pertaining to the execution
-   * of individual promotion steps.
+   * Name is the name of the variable.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.StepExecutionMetadata stepExecutionMetadata = 11;
+   * +kubebuilder:validation:MinLength=1
+   * +kubebuilder:validation:Pattern=^[a-zA-Z_]\w*$
+   *
+   * @generated from field: optional string name = 1;
    */
-  stepExecutionMetadata: StepExecutionMetadata[];
+  name: string;
 
   /**
-   * State stores the state of the promotion process between reconciliation
-   * attempts.
+   * Value is the value of the variable. It is allowed to utilize expressions
+   * in the value.
+   * See https://docs.kargo.io/references/expression-language for details.
    *
-   * @generated from field: optional k8s.io.apiextensions_apiserver.pkg.apis.apiextensions.v1.JSON state = 10;
+   * @generated from field: optional string value = 2;
    */
-  state?: JSON;
+  value: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionStatus.
- * Use `create(PromotionStatusSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionVariable.
+ * Use `create(PromotionVariableSchema)` to create a new message.
  */
-export const PromotionStatusSchema: GenMessage<PromotionStatus> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 42);
+export const PromotionVariableSchema: GenMessage<PromotionVariable> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 81);
 
 /**
- * PromotionStep describes a directive to be executed as part of a Promotion.
+ * ReconcileOutcome describes what a reconciliation of a Promotion did, or why
+ * it did nothing.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionStep
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ReconcileOutcome
  */
-export type PromotionStep = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionStep"> & {
+export type ReconcileOutcome = Message<"github.com.akuity.kargo.api.v1alpha1.ReconcileOutcome"> & {
   /**
-   * Uses identifies a runner that can execute this step.
+   * Reason is a machine-readable reason for the outcome.
+   *
+   * @generated from field: optional string reason = 1;
+   */
+  reason: string;
+
+  /**
+   * Message is a human-readable description of the outcome.
+   *
+   * @generated from field: optional string message = 2;
+   */
+  message: string;
+
+  /**
+   * Since is the time at which the first of the consecutive
+   * reconciliations that had this outcome completed.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time since = 3;
+   */
+  since?: Time;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ReconcileOutcome.
+ * Use `create(ReconcileOutcomeSchema)` to create a new message.
+ */
+export const ReconcileOutcomeSchema: GenMessage<ReconcileOutcome> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 82);
+
+/**
+ * RemoteBasePolicy restricts the hosts that remote Kustomize bases, i.e.
+ * entries of Kustomization files referring to directories of Git
+ * repositories, may be fetched from.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RemoteBasePolicy
+ */
+export type RemoteBasePolicy = Message<"github.com.akuity.kargo.api.v1alpha1.RemoteBasePolicy"> & {
+  /**
+   * AllowedHosts are the hosts that remote bases may be fetched from. A host
+   * prefixed with "*." also matches all of its subdomains. If empty, remote
+   * bases may not be fetched from any host.
+   *
+   * @generated from field: repeated string allowedHosts = 1;
+   */
+  allowedHosts: string[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RemoteBasePolicy.
+ * Use `create(RemoteBasePolicySchema)` to create a new message.
+ */
+export const RemoteBasePolicySchema: GenMessage<RemoteBasePolicy> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 83);
+
+/**
+ * RenderedBranch describes how the name of the branch that manifests rendered
+ * for a Stage are written to is derived.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RenderedBranch
+ */
+export type RenderedBranch = Message<"github.com.akuity.kargo.api.v1alpha1.RenderedBranch"> & {
+  /**
+   * Template is a Go template that renders the name of the branch. The
+   * template may refer to .Project, .Stage, .App, .Cluster, and .Region. The
+   * rendered name must be a valid Git branch name. e.g.
+   * "rendered/{{ .Stage }}/{{ .Region }}"
    *
-   * +kubebuilder:validation:Optional
    * +kubebuilder:validation:MinLength=1
    *
-   * @generated from field: optional string uses = 1;
+   * @generated from field: optional string template = 1;
    */
-  uses: string;
+  template: string;
 
   /**
-   * Task is a reference to a PromotionTask that should be inflated into a
-   * Promotion when it is built from a PromotionTemplate.
+   * App is the name of the application that is available to the template as
+   * .App. If not specified, the name of the first Argo CD Application
+   * managed by the Stage is used.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference task = 5;
+   * @generated from field: optional string app = 2;
    */
-  task?: PromotionTaskReference;
+  app: string;
+
+  /**
+   * Cluster is the name of the cluster that is available to the template as
+   * .Cluster. If not specified, the destination name of the first Argo CD
+   * Application managed by the Stage is used.
+   *
+   * @generated from field: optional string cluster = 3;
+   */
+  cluster: string;
+
+  /**
+   * Region is the name of the region that is available to the template as
+   * .Region.
+   *
+   * @generated from field: optional string region = 4;
+   */
+  region: string;
+
+  /**
+   * OnFailure describes what is done to the branch when a Promotion to the
+   * Stage fails or errors after pushing to it, e.g. so that Argo CD does not
+   * sync manifests that the Promotion did not finish promoting. Leave, the
+   * default, leaves the branch as is. Tag tags the commit that was pushed as
+   * kargo/failed/<promotion>, so that it can be found. Reset resets the
+   * branch to the commit it pointed to before the Promotion pushed to it,
+   * unless it has been pushed to since. Nothing is done if the Promotion did
+   * not push to the branch.
+   *
+   * +kubebuilder:default=Leave
+   *
+   * @generated from field: optional string onFailure = 5;
+   */
+  onFailure: string;
+
+  /**
+   * OnExternalModification describes what is done when a Promotion to the
+   * Stage is about to push to the branch and finds that the branch no longer
+   * points to the commit that Kargo last pushed to it, e.g. because a person
+   * or another tool pushed to it, whose changes the Promotion would
+   * otherwise overwrite. Fail, the default, fails the Promotion. Warn
+   * proceeds with the Promotion and sets the Stage's ExternallyModified
+   * condition. Drift fails the Promotion and records the modification as
+   * drift of the Stage, which blocks further Promotions until it is
+   * acknowledged using the kargo.akuity.io/acknowledge-drift annotation,
+   * after which subsequent Promotions accept the modification and push on
+   * top of it.
+   *
+   * +kubebuilder:default=Fail
+   *
+   * @generated from field: optional string onExternalModification = 6;
+   */
+  onExternalModification: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RenderedBranch.
+ * Use `create(RenderedBranchSchema)` to create a new message.
+ */
+export const RenderedBranchSchema: GenMessage<RenderedBranch> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 84);
+
+/**
+ * RenderedBranchCleanup records what was done to the rendered branch of a
+ * Stage after a Promotion to the Stage failed.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RenderedBranchCleanup
+ */
+export type RenderedBranchCleanup = Message<"github.com.akuity.kargo.api.v1alpha1.RenderedBranchCleanup"> & {
+  /**
+   * Action is what was done to the branch.
+   *
+   * @generated from field: optional string action = 1;
+   */
+  action: string;
+
+  /**
+   * Tag is the name of the tag that was created, if Action is Tag.
+   *
+   * @generated from field: optional string tag = 2;
+   */
+  tag: string;
+
+  /**
+   * Succeeded indicates whether the action was carried out successfully.
+   *
+   * @generated from field: optional bool succeeded = 3;
+   */
+  succeeded: boolean;
+
+  /**
+   * Message describes the outcome of the action.
+   *
+   * @generated from field: optional string message = 4;
+   */
+  message: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RenderedBranchCleanup.
+ * Use `create(RenderedBranchCleanupSchema)` to create a new message.
+ */
+export const RenderedBranchCleanupSchema: GenMessage<RenderedBranchCleanup> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 85);
+
+/**
+ * RenderedBranchCommit records the commit that Kargo last pushed to the
+ * rendered branch of a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RenderedBranchCommit
+ */
+export type RenderedBranchCommit = Message<"github.com.akuity.kargo.api.v1alpha1.RenderedBranchCommit"> & {
+  /**
+   * RepoURL is the URL of the repository the branch belongs to.
+   *
+   * @generated from field: optional string repoURL = 1;
+   */
+  repoURL: string;
+
+  /**
+   * Branch is the name of the branch.
+   *
+   * @generated from field: optional string branch = 2;
+   */
+  branch: string;
+
+  /**
+   * Commit is the ID of the commit.
+   *
+   * @generated from field: optional string commit = 3;
+   */
+  commit: string;
+
+  /**
+   * Promotion is the name of the Promotion that pushed the commit. It is
+   * empty if the commit was not pushed by a Promotion, e.g. because drift of
+   * the branch to the commit was acknowledged.
+   *
+   * @generated from field: optional string promotion = 4;
+   */
+  promotion: string;
+};
 
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RenderedBranchCommit.
+ * Use `create(RenderedBranchCommitSchema)` to create a new message.
+ */
+export const RenderedBranchCommitSchema: GenMessage<RenderedBranchCommit> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 86);
+
+/**
+ * RenderedBranchPush records the commits that a Promotion pushed to the
+ * rendered branch of a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RenderedBranchPush
+ */
+export type RenderedBranchPush = Message<"github.com.akuity.kargo.api.v1alpha1.RenderedBranchPush"> & {
   /**
-   * As is the alias this step can be referred to as.
+   * RepoURL is the URL of the repository the branch belongs to.
    *
-   * @generated from field: optional string as = 2;
+   * @generated from field: optional string repoURL = 1;
    */
-  as: string;
+  repoURL: string;
 
   /**
-   * Retry is the retry policy for this step.
+   * Branch is the name of the branch.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry retry = 4;
+   * @generated from field: optional string branch = 2;
    */
-  retry?: PromotionStepRetry;
+  branch: string;
 
   /**
-   * Vars is a list of variables that can be referenced by expressions in
-   * the step's Config. The values override the values specified in the
-   * PromotionSpec.
+   * PreviousCommit is the ID of the commit that the branch pointed to before
+   * the Promotion first pushed to it. It is empty if the branch did not
+   * exist.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 6;
+   * @generated from field: optional string previousCommit = 3;
    */
-  vars: PromotionVariable[];
+  previousCommit: string;
 
   /**
-   * Config is opaque configuration for the PromotionStep that is understood
-   * only by each PromotionStep's implementation. It is legal to utilize
-   * expressions in defining values at any level of this block.
-   * See https://docs.kargo.io/references/expression-language for details.
+   * Commit is the ID of the commit that the Promotion most recently pushed to
+   * the branch.
    *
-   * @generated from field: optional k8s.io.apiextensions_apiserver.pkg.apis.apiextensions.v1.JSON config = 3;
+   * @generated from field: optional string commit = 4;
    */
-  config?: JSON;
+  commit: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionStep.
- * Use `create(PromotionStepSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RenderedBranchPush.
+ * Use `create(RenderedBranchPushSchema)` to create a new message.
  */
-export const PromotionStepSchema: GenMessage<PromotionStep> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 43);
+export const RenderedBranchPushSchema: GenMessage<RenderedBranchPush> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 87);
 
 /**
- * PromotionStepRetry describes the retry policy for a PromotionStep.
+ * RepoPolicy restricts the Git repositories that Promotions may access. It is
+ * consulted before credentials for a repository are looked up, and Promotions
+ * that attempt to access a repository that is not allowed are Errored with a
+ * RepoNotAllowed message.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry
+ * Patterns are matched against normalized repository URLs, e.g.
+ * "https://github.com/example/repo" or "ssh://git@github.com/example/repo".
+ * They are glob patterns (optionally prefixed with "glob:"), in which "*" does
+ * not match "/", or regular expressions (prefixed with "regex:" or
+ * "regexp:").
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RepoPolicy
  */
-export type PromotionStepRetry = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry"> & {
+export type RepoPolicy = Message<"github.com.akuity.kargo.api.v1alpha1.RepoPolicy"> & {
   /**
-   * Timeout is the soft maximum interval in which a step that returns a Running
-   * status (which typically indicates it's waiting for something to happen)
-   * may be retried.
-   *
-   * The maximum is a soft one because the check for whether the interval has
-   * elapsed occurs AFTER the step has run. This effectively means a step may
-   * run ONCE beyond the close of the interval.
-   *
-   * If this field is set to nil, the effective default will be a step-specific
-   * one. If no step-specific default exists (i.e. is also nil), the effective
-   * default will be the system-wide default of 0.
-   *
-   * A value of 0 will cause the step to be retried indefinitely unless the
-   * ErrorThreshold is reached.
+   * Allow is a list of patterns of repository URLs that may be accessed. If
+   * empty, all repositories that are not denied may be accessed.
    *
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration timeout = 1;
+   * @generated from field: repeated string allow = 1;
    */
-  timeout?: Duration;
+  allow: string[];
 
   /**
-   * ErrorThreshold is the number of consecutive times the step must fail (for
-   * any reason) before retries are abandoned and the entire Promotion is marked
-   * as failed.
-   *
-   * If this field is set to 0, the effective default will be a step-specific
-   * one. If no step-specific default exists (i.e. is also 0), the effective
-   * default will be the system-wide default of 1.
-   *
-   * A value of 1 will cause the Promotion to be marked as failed after just
-   * a single failure; i.e. no retries will be attempted.
-   *
-   * There is no option to specify an infinite number of retries using a value
-   * such as -1.
-   *
-   * In a future release, Kargo is likely to become capable of distinguishing
-   * between recoverable and non-recoverable step failures. At that time, it is
-   * planned that unrecoverable failures will not be subject to this threshold
-   * and will immediately cause the Promotion to be marked as failed without
-   * further condition.
+   * Deny is a list of patterns of repository URLs that may not be accessed.
+   * Deny patterns take precedence over Allow patterns.
    *
-   * @generated from field: optional uint32 errorThreshold = 2;
+   * @generated from field: repeated string deny = 2;
    */
-  errorThreshold: number;
+  deny: string[];
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionStepRetry.
- * Use `create(PromotionStepRetrySchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RepoPolicy.
+ * Use `create(RepoPolicySchema)` to create a new message.
  */
-export const PromotionStepRetrySchema: GenMessage<PromotionStepRetry> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 44);
+export const RepoPolicySchema: GenMessage<RepoPolicy> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 88);
 
 /**
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTask
+ * RepoPolicyDecision records whether a Promotion was allowed to access a Git
+ * repository.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RepoPolicyDecision
  */
-export type PromotionTask = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTask"> & {
+export type RepoPolicyDecision = Message<"github.com.akuity.kargo.api.v1alpha1.RepoPolicyDecision"> & {
   /**
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ObjectMeta metadata = 1;
+   * RepoURL is the URL of the repository.
+   *
+   * @generated from field: optional string repoURL = 1;
    */
-  metadata?: ObjectMeta;
+  repoURL: string;
 
   /**
-   * Spec describes the composition of a PromotionTask, including the
-   * variables available to the task and the steps.
+   * Allowed indicates whether access to the repository was allowed.
    *
-   * +kubebuilder:validation:Required
+   * @generated from field: optional bool allowed = 2;
+   */
+  allowed: boolean;
+
+  /**
+   * Rule is the pattern the decision was based on. It is empty if access was
+   * denied because the URL matched none of the allowed patterns.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec spec = 2;
+   * @generated from field: optional string rule = 3;
    */
-  spec?: PromotionTaskSpec;
+  rule: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTask.
- * Use `create(PromotionTaskSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RepoPolicyDecision.
+ * Use `create(RepoPolicyDecisionSchema)` to create a new message.
  */
-export const PromotionTaskSchema: GenMessage<PromotionTask> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 45);
+export const RepoPolicyDecisionSchema: GenMessage<RepoPolicyDecision> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 89);
 
 /**
- * PromotionTaskList contains a list of PromotionTasks.
+ * RepoSubscription describes a subscription to ONE OF a Git repository, a
+ * container image repository, or a Helm chart repository.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTaskList
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RepoSubscription
  */
-export type PromotionTaskList = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTaskList"> & {
+export type RepoSubscription = Message<"github.com.akuity.kargo.api.v1alpha1.RepoSubscription"> & {
   /**
-   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.ListMeta metadata = 1;
+   * Git describes a subscriptions to a Git repository.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitSubscription git = 1;
    */
-  metadata?: ListMeta;
+  git?: GitSubscription;
 
   /**
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionTask items = 2;
+   * Image describes a subscription to container image repository.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ImageSubscription image = 2;
    */
-  items: PromotionTask[];
+  image?: ImageSubscription;
+
+  /**
+   * Chart describes a subscription to a Helm chart repository.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ChartSubscription chart = 3;
+   */
+  chart?: ChartSubscription;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTaskList.
- * Use `create(PromotionTaskListSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RepoSubscription.
+ * Use `create(RepoSubscriptionSchema)` to create a new message.
  */
-export const PromotionTaskListSchema: GenMessage<PromotionTaskList> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 46);
+export const RepoSubscriptionSchema: GenMessage<RepoSubscription> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 90);
 
 /**
- * PromotionTaskReference describes a reference to a PromotionTask.
+ * ResourceLimits limits the size of the Git repositories that Promotions clone
+ * and of the manifests they render, so that a misconfigured Stage cannot
+ * exhaust the disk or memory of the controller. Any limit that is not
+ * specified falls back to its default.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ResourceLimits
  */
-export type PromotionTaskReference = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference"> & {
+export type ResourceLimits = Message<"github.com.akuity.kargo.api.v1alpha1.ResourceLimits"> & {
   /**
-   * Name is the name of the (Cluster)PromotionTask.
-   *
-   * +kubebuilder:validation:Required
-   * +kubebuilder:validation:MinLength=1
-   * +kubebuilder:validation:MaxLength=253
-   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+   * MaxRepoSize is the maximum size of a Git repository that the git-clone
+   * promotion step clones. Where the API of the repository's Git provider
+   * reports its size, larger repositories are not cloned at all. Otherwise,
+   * the clone is aborted as soon as it exceeds this size. A value of 0
+   * disables the limit. Defaults to 1Gi.
    *
-   * @generated from field: optional string name = 1;
+   * @generated from field: optional k8s.io.apimachinery.pkg.api.resource.Quantity maxRepoSize = 1;
    */
-  name: string;
+  maxRepoSize?: Quantity;
 
   /**
-   * Kind is the type of the PromotionTask. Can be either PromotionTask or
-   * ClusterPromotionTask, default is PromotionTask.
-   *
-   * +kubebuilder:validation:Optional
-   * +kubebuilder:validation:Enum=PromotionTask;ClusterPromotionTask
+   * MaxRenderedOutputSize is the maximum size of the manifests that the
+   * kustomize-build promotion step renders. Promotions rendering larger
+   * manifests fail with a RenderTooLarge message. A value of 0 disables the
+   * limit. Defaults to 50Mi.
    *
-   * @generated from field: optional string kind = 2;
+   * @generated from field: optional k8s.io.apimachinery.pkg.api.resource.Quantity maxRenderedOutputSize = 2;
    */
-  kind: string;
+  maxRenderedOutputSize?: Quantity;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTaskReference.
- * Use `create(PromotionTaskReferenceSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ResourceLimits.
+ * Use `create(ResourceLimitsSchema)` to create a new message.
  */
-export const PromotionTaskReferenceSchema: GenMessage<PromotionTaskReference> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 47);
+export const ResourceLimitsSchema: GenMessage<ResourceLimits> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 91);
 
 /**
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec
+ * RetainedImage records an image that a Promotion left at the version the
+ * Stage's manifests already specified, because the promoted Freight did not
+ * carry it.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.RetainedImage
  */
-export type PromotionTaskSpec = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec"> & {
+export type RetainedImage = Message<"github.com.akuity.kargo.api.v1alpha1.RetainedImage"> & {
   /**
-   * Vars specifies the variables available to the PromotionTask. The
-   * values of these variables are the default values that can be
-   * overridden by the step referencing the task.
+   * RepoURL is the URL of the image's repository.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 1;
+   * @generated from field: optional string repoURL = 1;
    */
-  vars: PromotionVariable[];
+  repoURL: string;
 
   /**
-   * Steps specifies the directives to be executed as part of this
-   * PromotionTask. The steps as defined here are inflated into a
-   * Promotion when it is built from a PromotionTemplate.
+   * Tag is the tag the image was left at. It is empty if the version of the
+   * image was not read, or if it is only specified by digest.
    *
-   * +kubebuilder:validation:Required
-   * +kubebuilder:validation:MinItems=1
-   * +kubebuilder:validation:items:XValidation:message="PromotionTask step must have uses set and must not reference another task",rule="has(self.uses) && !has(self.task)"
+   * @generated from field: optional string tag = 2;
+   */
+  tag: string;
+
+  /**
+   * Digest is the digest the image was left at. It is empty if the version
+   * of the image was not read, or if it is only specified by tag.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionStep steps = 2;
+   * @generated from field: optional string digest = 3;
    */
-  steps: PromotionStep[];
+  digest: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTaskSpec.
- * Use `create(PromotionTaskSpecSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.RetainedImage.
+ * Use `create(RetainedImageSchema)` to create a new message.
  */
-export const PromotionTaskSpecSchema: GenMessage<PromotionTaskSpec> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 48);
+export const RetainedImageSchema: GenMessage<RetainedImage> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 92);
 
 /**
- * PromotionTemplate defines a template for a Promotion that can be used to
- * incorporate Freight into a Stage.
+ * ServiceAccountReference is a reference to a ServiceAccount.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTemplate
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ServiceAccountReference
  */
-export type PromotionTemplate = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTemplate"> & {
+export type ServiceAccountReference = Message<"github.com.akuity.kargo.api.v1alpha1.ServiceAccountReference"> & {
   /**
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec spec = 1;
+   * Name is the name of the ServiceAccount in the same project/namespace as
+   * the Stage.
+   *
+   * +kubebuilder:validation:Required
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string name = 1;
    */
-  spec?: PromotionTemplateSpec;
+  name: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTemplate.
- * Use `create(PromotionTemplateSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ServiceAccountReference.
+ * Use `create(ServiceAccountReferenceSchema)` to create a new message.
  */
-export const PromotionTemplateSchema: GenMessage<PromotionTemplate> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 49);
+export const ServiceAccountReferenceSchema: GenMessage<ServiceAccountReference> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 93);
 
 /**
- * PromotionTemplateSpec describes the (partial) specification of a Promotion
- * for a Stage. This is a template that can be used to create a Promotion for a
- * Stage.
+ * SourceUpdateBatch records a batch of updates to a branch of a Git repository
+ * that are accumulated on a rolling branch.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatch
  */
-export type PromotionTemplateSpec = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec"> & {
+export type SourceUpdateBatch = Message<"github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatch"> & {
   /**
-   * Vars is a list of variables that can be referenced by expressions in
-   * promotion steps.
+   * RepoURL is the URL of the Git repository.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionVariable vars = 2;
+   * @generated from field: optional string repoURL = 1;
    */
-  vars: PromotionVariable[];
+  repoURL: string;
 
   /**
-   * Steps specifies the directives to be executed as part of a Promotion.
-   * The order in which the directives are executed is the order in which they
-   * are listed in this field.
+   * Branch is the name of the branch that the updates are accumulated for.
    *
-   * +kubebuilder:validation:MinItems=1
-   * +kubebuilder:validation:items:XValidation:message="PromotionTemplate step must have exactly one of uses or task set",rule="(has(self.uses) ? !has(self.task) : has(self.task))"
+   * @generated from field: optional string branch = 2;
+   */
+  branch: string;
+
+  /**
+   * RollingBranch is the name of the branch that the updates are
+   * accumulated on.
    *
-   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionStep steps = 1;
+   * @generated from field: optional string rollingBranch = 3;
    */
-  steps: PromotionStep[];
+  rollingBranch: string;
+
+  /**
+   * RollingCommit is the ID of the commit that the Promotion pushed to
+   * RollingBranch, which contains all updates of the batch squashed into
+   * one. Manifests rendered by the Promotion were rendered from it.
+   *
+   * @generated from field: optional string rollingCommit = 4;
+   */
+  rollingCommit: string;
+
+  /**
+   * Updates is the number of updates in the batch, including the
+   * Promotion's own.
+   *
+   * @generated from field: optional int32 updates = 5;
+   */
+  updates: number;
+
+  /**
+   * MergedCommit is the ID of the commit that the batch was merged into
+   * Branch as, if the Promotion merged it. As the squashed commit is rebased
+   * onto Branch when it is merged, it usually differs from RollingCommit.
+   * It is empty if the batch has not been merged yet or was merged by a pull
+   * request or by a later Promotion.
+   *
+   * @generated from field: optional string mergedCommit = 6;
+   */
+  mergedCommit: string;
+
+  /**
+   * PullRequestURL is the URL of the pull request that was opened to merge
+   * the batch into Branch, if any.
+   *
+   * @generated from field: optional string pullRequestURL = 7;
+   */
+  pullRequestURL: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionTemplateSpec.
- * Use `create(PromotionTemplateSpecSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatch.
+ * Use `create(SourceUpdateBatchSchema)` to create a new message.
  */
-export const PromotionTemplateSpecSchema: GenMessage<PromotionTemplateSpec> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 50);
+export const SourceUpdateBatchSchema: GenMessage<SourceUpdateBatch> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 94);
 
 /**
- * PromotionVariable describes a single variable that may be referenced by
- * expressions in promotion steps.
+ * SourceUpdateBatching describes how the updates that Promotions to a Stage
+ * push to a branch of a Git repository are accumulated on a rolling branch
+ * before they are merged into it.
  *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.PromotionVariable
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatching
  */
-export type PromotionVariable = Message<"github.com.akuity.kargo.api.v1alpha1.PromotionVariable"> & {
+export type SourceUpdateBatching = Message<"github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatching"> & {
   /**
-   * Name is the name of the variable.
+   * RepoURL is the URL of the Git repository whose branch updates are
+   * accumulated for.
    *
    * +kubebuilder:validation:MinLength=1
-   * +kubebuilder:validation:Pattern=^[a-zA-Z_]\w*$
    *
-   * @generated from field: optional string name = 1;
+   * @generated from field: optional string repoURL = 1;
    */
-  name: string;
+  repoURL: string;
 
   /**
-   * Value is the value of the variable. It is allowed to utilize expressions
-   * in the value.
-   * See https://docs.kargo.io/references/expression-language for details.
+   * Branch is the name of the branch that updates are accumulated for. If
+   * the rolling branch exists, the git-clone step checks it out when asked
+   * to check out this branch, and the git-push step pushes to the rolling
+   * branch when asked to push to this branch.
+   *
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string branch = 2;
+   */
+  branch: string;
+
+  /**
+   * RollingBranch is the name of the branch that updates are accumulated on.
+   * It holds a single commit on top of Branch, which every update amends.
+   *
+   * +kubebuilder:default=kargo/image-updates
    *
-   * @generated from field: optional string value = 2;
+   * @generated from field: optional string rollingBranch = 3;
    */
-  value: string;
-};
-
-/**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.PromotionVariable.
- * Use `create(PromotionVariableSchema)` to create a new message.
- */
-export const PromotionVariableSchema: GenMessage<PromotionVariable> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 51);
+  rollingBranch: string;
 
-/**
- * RepoSubscription describes a subscription to ONE OF a Git repository, a
- * container image repository, or a Helm chart repository.
- *
- * @generated from message github.com.akuity.kargo.api.v1alpha1.RepoSubscription
- */
-export type RepoSubscription = Message<"github.com.akuity.kargo.api.v1alpha1.RepoSubscription"> & {
   /**
-   * Git describes a subscriptions to a Git repository.
+   * MergeAfter is the number of updates after which the accumulated updates
+   * are merged into Branch. If neither MergeAfter nor MergeInterval is
+   * specified, updates accumulate until the rolling branch is merged by
+   * other means.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitSubscription git = 1;
+   * +kubebuilder:validation:Minimum=1
+   *
+   * @generated from field: optional int32 mergeAfter = 4;
    */
-  git?: GitSubscription;
+  mergeAfter: number;
 
   /**
-   * Image describes a subscription to container image repository.
+   * MergeInterval is the time after the first update on the rolling branch
+   * after which the accumulated updates are merged into Branch. It is
+   * evaluated whenever a Promotion pushes an update, so the updates are
+   * merged along with the first update pushed after the interval elapsed.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ImageSubscription image = 2;
+   * +kubebuilder:validation:Type=string
+   * +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration mergeInterval = 5;
    */
-  image?: ImageSubscription;
+  mergeInterval?: Duration;
 
   /**
-   * Chart describes a subscription to a Helm chart repository.
+   * MergeMethod describes how the accumulated updates are merged into
+   * Branch. Push, the default, rebases the squashed commit onto Branch,
+   * pushes it to Branch, and deletes the rolling branch. PullRequest opens
+   * a pull request from the rolling branch to Branch, unless one is open
+   * already, and leaves merging it to the Git hosting provider's merge
+   * queue or to a person.
    *
-   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ChartSubscription chart = 3;
+   * +kubebuilder:default=Push
+   *
+   * @generated from field: optional string mergeMethod = 6;
    */
-  chart?: ChartSubscription;
+  mergeMethod: string;
 };
 
 /**
- * Describes the message github.com.akuity.kargo.api.v1alpha1.RepoSubscription.
- * Use `create(RepoSubscriptionSchema)` to create a new message.
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatching.
+ * Use `create(SourceUpdateBatchingSchema)` to create a new message.
  */
-export const RepoSubscriptionSchema: GenMessage<RepoSubscription> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 52);
+export const SourceUpdateBatchingSchema: GenMessage<SourceUpdateBatching> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 95);
 
 /**
  * Stage is the Kargo API's main type.
@@ -2437,7 +4811,57 @@ export type Stage = Message<"github.com.akuity.kargo.api.v1alpha1.Stage"> & {
  * Use `create(StageSchema)` to create a new message.
  */
 export const StageSchema: GenMessage<Stage> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 53);
+  messageDesc(file_v1alpha1_generated, 96);
+
+/**
+ * StageImages compares the container images that have been promoted to a Stage
+ * with those that are about to be promoted to it and with those that have been
+ * promoted to its upstream Stages.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.StageImages
+ */
+export type StageImages = Message<"github.com.akuity.kargo.api.v1alpha1.StageImages"> & {
+  /**
+   * Current lists the images that have most recently been promoted to the
+   * Stage, ordered by repository URL.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Image current = 1;
+   */
+  current: Image[];
+
+  /**
+   * CurrentSource indicates where the images listed by Current were learned
+   * from.
+   *
+   * @generated from field: optional string currentSource = 2;
+   */
+  currentSource: string;
+
+  /**
+   * Next lists the images of the Freight that is being promoted to the Stage
+   * or, if no Promotion is running, the Freight of the next queued
+   * Promotion. It is absent when there is neither.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.Image next = 3;
+   */
+  next: Image[];
+
+  /**
+   * Upstream describes, for each of the Stage's upstream Stages, how the
+   * images that have been promoted to that Stage differ from those that have
+   * been promoted to this one.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.UpstreamStageImages upstream = 4;
+   */
+  upstream: UpstreamStageImages[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.StageImages.
+ * Use `create(StageImagesSchema)` to create a new message.
+ */
+export const StageImagesSchema: GenMessage<StageImages> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 97);
 
 /**
  * StageList is a list of Stage resources.
@@ -2461,7 +4885,64 @@ export type StageList = Message<"github.com.akuity.kargo.api.v1alpha1.StageList"
  * Use `create(StageListSchema)` to create a new message.
  */
 export const StageListSchema: GenMessage<StageList> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 54);
+  messageDesc(file_v1alpha1_generated, 98);
+
+/**
+ * StageOverlay describes one of several Kustomize overlays that make up a
+ * Stage, along with the branch that manifests rendered from it are written
+ * to.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.StageOverlay
+ */
+export type StageOverlay = Message<"github.com.akuity.kargo.api.v1alpha1.StageOverlay"> & {
+  /**
+   * Name uniquely identifies the overlay among the Stage's overlays, e.g. by
+   * the region it targets. It is available to the Stage's RenderedBranch
+   * template as .Region.
+   *
+   * +kubebuilder:validation:MinLength=1
+   * +kubebuilder:validation:MaxLength=63
+   * +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+   *
+   * @generated from field: optional string name = 1;
+   */
+  name: string;
+
+  /**
+   * Path is the path of the overlay's directory, relative to the root of the
+   * repository.
+   *
+   * +kubebuilder:validation:MinLength=1
+   *
+   * @generated from field: optional string path = 2;
+   */
+  path: string;
+
+  /**
+   * RenderedBranch is the name of the branch that manifests rendered from the
+   * overlay are written to. If not specified, it is resolved from the Stage's
+   * RenderedBranch template.
+   *
+   * @generated from field: optional string renderedBranch = 3;
+   */
+  renderedBranch: string;
+
+  /**
+   * GitIdentity optionally specifies the identity that manifests rendered
+   * from the overlay are committed as, and the credentials they are pushed
+   * with, in place of the Stage's GitIdentity.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitIdentity gitIdentity = 4;
+   */
+  gitIdentity?: GitIdentity;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.StageOverlay.
+ * Use `create(StageOverlaySchema)` to create a new message.
+ */
+export const StageOverlaySchema: GenMessage<StageOverlay> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 99);
 
 /**
  * StageSpec describes the sources of Freight used by a Stage and how to
@@ -2512,6 +4993,214 @@ export type StageSpec = Message<"github.com.akuity.kargo.api.v1alpha1.StageSpec"
    * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.Verification verification = 3;
    */
   verification?: Verification;
+
+  /**
+   * ArgoCDApps describes Argo CD Applications whose lifecycle is managed by
+   * the Stage. The controller creates each Application that does not exist,
+   * adopts existing ones only if explicitly permitted, and keeps their
+   * source, destination, and sync policy in sync with what is specified here.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.ManagedArgoCDApp argoCDApps = 7;
+   */
+  argoCDApps: ManagedArgoCDApp[];
+
+  /**
+   * ServiceAccountRef optionally references a ServiceAccount in the Stage's
+   * namespace whose identity the controller assumes when getting and
+   * updating Argo CD Applications on behalf of Promotions to this Stage. This
+   * permits Kubernetes RBAC to limit which Applications the Stage may
+   * promote to. When not specified, the controller's own identity is used.
+   * The run-job promotion step creates its Jobs with this identity and can
+   * not be used by Stages that do not specify one.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ServiceAccountReference serviceAccountRef = 8;
+   */
+  serviceAccountRef?: ServiceAccountReference;
+
+  /**
+   * ArgoCDContext optionally names the Argo CD context, registered in the
+   * controller's configuration, in which the Argo CD Applications related to
+   * this Stage reside. All interactions with those Applications, including
+   * updating them on behalf of Promotions, managing their lifecycle, and
+   * assessing their health, take place through this context. When not
+   * specified, the controller's default Argo CD context is used.
+   *
+   * @generated from field: optional string argoCDContext = 9;
+   */
+  argoCDContext: string;
+
+  /**
+   * PreventDowngrades indicates whether Promotions that would move any of
+   * the Stage's images back to an older version should be skipped instead of
+   * executed. Versions are compared as semantic versions where possible and
+   * otherwise by the order in which they were promoted to the Stage. This
+   * guards against stale Promotions, e.g. ones that are retried after a more
+   * recent Promotion already succeeded. Promotions that are annotated with
+   * kargo.akuity.io/allow-downgrade: "true" are executed regardless, which
+   * permits deliberate rollbacks.
+   *
+   * @generated from field: optional bool preventDowngrades = 10;
+   */
+  preventDowngrades: boolean;
+
+  /**
+   * RenderedBranch optionally describes how the name of the branch that
+   * manifests rendered for the Stage are written to is derived. The name is
+   * resolved whenever a Promotion to the Stage is executed, is recorded in
+   * the Promotion's status, and is available to its steps as
+   * ctx.renderedBranch, so that the same branch can consistently be checked
+   * out, pushed to, and synced by Argo CD.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.RenderedBranch renderedBranch = 11;
+   */
+  renderedBranch?: RenderedBranch;
+
+  /**
+   * ImageMappings optionally describes how references to container images
+   * found in Freight are rewritten before promotion steps such as
+   * kustomize-set-image write them to the Stage's manifests. This is useful
+   * when images are copied to a different registry or repository for each
+   * environment. The first mapping that applies to an image is used, and
+   * images that no mapping applies to are left unchanged.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.ImageMapping imageMappings = 12;
+   */
+  imageMappings: ImageMapping[];
+
+  /**
+   * ToolVersions optionally pins the versions of the tools that promotion
+   * steps such as kustomize-build and helm-template use to render the
+   * Stage's manifests, so that the rendered manifests do not change when
+   * the versions of the tools embedded in Kargo do.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ToolVersions toolVersions = 13;
+   */
+  toolVersions?: ToolVersions;
+
+  /**
+   * PromotionPriority is the priority assigned to Promotions to this Stage
+   * that do not specify one themselves when they are created. See the
+   * Priority field of PromotionSpec.
+   *
+   * +kubebuilder:validation:Minimum=0
+   * +kubebuilder:validation:Maximum=100
+   *
+   * @generated from field: optional int32 promotionPriority = 14;
+   */
+  promotionPriority: number;
+
+  /**
+   * Overlays optionally describes several Kustomize overlays that together
+   * make up the Stage, e.g. one per region, each of which manifests are
+   * rendered from into a branch of its own. The kustomize-promote-overlays
+   * promotion step updates and renders all of them as a unit. The branch of
+   * each overlay is resolved whenever a Promotion to the Stage is executed
+   * and is recorded in the Promotion's status, along with the commit pushed
+   * to it. When overlays are specified, the Stage's RenderedBranch template
+   * is resolved once per overlay instead of once for the whole Stage.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.StageOverlay overlays = 15;
+   */
+  overlays: StageOverlay[];
+
+  /**
+   * PromotionApproval optionally requires Promotions to the Stage to be
+   * approved by one of a list of allowed approvers before they are executed.
+   * It is copied to each Promotion to the Stage when the Promotion is
+   * created, so changes to it only apply to Promotions created afterwards.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionApprovalPolicy promotionApproval = 16;
+   */
+  promotionApproval?: PromotionApprovalPolicy;
+
+  /**
+   * Metadata is optional, free-form information about the Stage, such as the
+   * team that owns it or the ID of the Stage in a change management system.
+   * The expressions in the Stage's promotion steps can refer to it using the
+   * stageMetadata() function, and it is included in the annotations of the
+   * events recorded for Promotions to the Stage. Keys must begin with a
+   * letter and may only contain letters, digits, '_', '-', and '.'.
+   *
+   * +kubebuilder:validation:MaxProperties=32
+   *
+   * @generated from field: map<string, string> metadata = 17;
+   */
+  metadata: { [key: string]: string };
+
+  /**
+   * ResourceLimits optionally lowers, for Promotions to the Stage, the
+   * limits on the size of the Git repositories they clone and of the
+   * manifests they render that are specified by the KargoConfig resource.
+   * Limits above those of the KargoConfig resource are lowered to them.
+   * Limits that are not specified here, or that are 0, fall back to those
+   * of the KargoConfig resource.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.ResourceLimits resourceLimits = 18;
+   */
+  resourceLimits?: ResourceLimits;
+
+  /**
+   * DryRunApply optionally permits Promotions to the Stage to verify the
+   * manifests they render using the argocd-dry-run promotion step, which
+   * applies them to the destination cluster of an Argo CD Application in
+   * server-side dry-run mode before they are committed. Doing so requires
+   * the controller to read the credentials of the destination cluster from
+   * Argo CD, so the step fails for Stages that do not specify this.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.DryRunApply dryRunApply = 19;
+   */
+  dryRunApply?: DryRunApply;
+
+  /**
+   * ImageCompleteness specifies whether the Freight promoted to the Stage
+   * must carry every image that its Warehouse subscribes to. Any, the
+   * default, permits Freight that carries only some of them, in which case
+   * the others are left at the versions the Stage's manifests already
+   * specify, which the Promotion records in its status. Declared requires
+   * the Freight to carry exactly the images its Warehouse subscribes to, and
+   * Promotions of any other Freight are rejected.
+   *
+   * +kubebuilder:default=Any
+   *
+   * @generated from field: optional string imageCompleteness = 20;
+   */
+  imageCompleteness: string;
+
+  /**
+   * SourceUpdateBatching optionally accumulates the updates that Promotions
+   * to the Stage push to a branch of a Git repository, such as image updates
+   * to the source of the Stage's manifests, on a rolling branch instead of
+   * committing each of them to the branch. The updates on the rolling
+   * branch are squashed into a single commit, which is merged into the
+   * branch once enough updates have accumulated or enough time has passed.
+   * Manifests that the Promotions render are rendered from the content of
+   * the rolling branch, so rendered branches continue to be updated by every
+   * Promotion.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.SourceUpdateBatching sourceUpdateBatching = 21;
+   */
+  sourceUpdateBatching?: SourceUpdateBatching;
+
+  /**
+   * StrictRender specifies whether Promotions to the Stage fail when the
+   * tools rendering the Stage's manifests, such as Kustomize, emit warnings,
+   * e.g. about the use of deprecated fields. Regardless of this setting,
+   * such warnings are recorded in the status of the Promotion.
+   *
+   * @generated from field: optional bool strictRender = 22;
+   */
+  strictRender: boolean;
+
+  /**
+   * GitIdentity optionally specifies the identity that Promotions to the
+   * Stage commit to Git repositories as, and the credentials they access the
+   * repositories with, in place of the controller's defaults. It is resolved
+   * when a Promotion to the Stage begins and is recorded in the Promotion's
+   * status.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.GitIdentity gitIdentity = 23;
+   */
+  gitIdentity?: GitIdentity;
 };
 
 /**
@@ -2519,7 +5208,7 @@ export type StageSpec = Message<"github.com.akuity.kargo.api.v1alpha1.StageSpec"
  * Use `create(StageSpecSchema)` to create a new message.
  */
 export const StageSpecSchema: GenMessage<StageSpec> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 55);
+  messageDesc(file_v1alpha1_generated, 100);
 
 /**
  * StageStatus describes a Stages's current and recent Freight, health, and
@@ -2550,6 +5239,16 @@ export type StageStatus = Message<"github.com.akuity.kargo.api.v1alpha1.StageSta
    */
   lastHandledRefresh: string;
 
+  /**
+   * LastHandledReplay holds the value of the most recent
+   * AnnotationKeyReplayPromotionRequests annotation that was handled by the
+   * controller.
+   * +optional
+   *
+   * @generated from field: optional string lastHandledReplay = 18;
+   */
+  lastHandledReplay: string;
+
   /**
    * Phase describes where the Stage currently is in its lifecycle.
    *
@@ -2619,6 +5318,60 @@ export type StageStatus = Message<"github.com.akuity.kargo.api.v1alpha1.StageSta
    * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionReference lastPromotion = 10;
    */
   lastPromotion?: PromotionReference;
+
+  /**
+   * PromotionHistory is a list of recently completed Promotions to the Stage,
+   * regardless of their outcome. By default, the last ten Promotions are
+   * stored. The first item in the list is the most recently completed
+   * Promotion, subsequent items are older Promotions. Unlike the Promotion
+   * resources themselves, these records are retained when the Promotions they
+   * describe are deleted.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.PromotionRecord promotionHistory = 14;
+   */
+  promotionHistory: PromotionRecord[];
+
+  /**
+   * PromotionQueue describes the Promotions that are waiting for their turn
+   * to be executed against the Stage. It is absent when no Promotions are
+   * waiting.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PromotionQueue promotionQueue = 15;
+   */
+  promotionQueue?: PromotionQueue;
+
+  /**
+   * Images compares the container images that have been promoted to the
+   * Stage with those that are about to be promoted to it and with those that
+   * have been promoted to its upstream Stages. It is recomputed on every
+   * reconciliation.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.StageImages images = 16;
+   */
+  images?: StageImages;
+
+  /**
+   * Drift describes changes that were made to a branch pushed to by the last
+   * Promotion after that Promotion completed, e.g. a hotfix applied by hand to
+   * rendered manifests. Unless the drift has been resolved, no further
+   * Promotions to the Stage are started. It is absent when no drift has been
+   * detected.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.Drift drift = 17;
+   */
+  drift?: Drift;
+
+  /**
+   * RenderedBranchCommit records the commit that Kargo last pushed to the
+   * Stage's rendered branch. It is updated from the status of each
+   * Promotion to the Stage that pushed to the branch once the Promotion
+   * completes, and is compared to the commit the branch points to by the
+   * next Promotion before it pushes to the branch, to detect modifications
+   * of the branch made by something other than Kargo.
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.RenderedBranchCommit renderedBranchCommit = 19;
+   */
+  renderedBranchCommit?: RenderedBranchCommit;
 };
 
 /**
@@ -2626,7 +5379,7 @@ export type StageStatus = Message<"github.com.akuity.kargo.api.v1alpha1.StageSta
  * Use `create(StageStatusSchema)` to create a new message.
  */
 export const StageStatusSchema: GenMessage<StageStatus> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 56);
+  messageDesc(file_v1alpha1_generated, 101);
 
 /**
  * StepExecutionMetadata tracks metadata pertaining to the execution of
@@ -2644,7 +5397,8 @@ export type StepExecutionMetadata = Message<"github.com.akuity.kargo.api.v1alpha
 
   /**
    * StartedAt is the time at which the first attempt to execute the step
-   * began.
+   * began or, if the step waited for a point in time, the time at which it
+   * last did so.
    *
    * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time startedAt = 2;
    */
@@ -2678,6 +5432,15 @@ export type StepExecutionMetadata = Message<"github.com.akuity.kargo.api.v1alpha
    * @generated from field: optional string message = 6;
    */
   message: string;
+
+  /**
+   * WaitUntil is the point in time the step is waiting for, if it is waiting
+   * for one rather than for a change in some external state. The Promotion
+   * is reconciled again no later than this time.
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Time waitUntil = 7;
+   */
+  waitUntil?: Time;
 };
 
 /**
@@ -2685,7 +5448,72 @@ export type StepExecutionMetadata = Message<"github.com.akuity.kargo.api.v1alpha
  * Use `create(StepExecutionMetadataSchema)` to create a new message.
  */
 export const StepExecutionMetadataSchema: GenMessage<StepExecutionMetadata> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 57);
+  messageDesc(file_v1alpha1_generated, 102);
+
+/**
+ * ToolVersions describes the versions of the tools used to render manifests.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.ToolVersions
+ */
+export type ToolVersions = Message<"github.com.akuity.kargo.api.v1alpha1.ToolVersions"> & {
+  /**
+   * Kustomize is the version of Kustomize, e.g. "v5.5.0". If not specified,
+   * the version embedded in Kargo is used.
+   *
+   * +kubebuilder:validation:Pattern=^v[0-9]+\.[0-9]+\.[0-9]+$
+   *
+   * @generated from field: optional string kustomize = 1;
+   */
+  kustomize: string;
+
+  /**
+   * Helm is the version of Helm, e.g. "v3.16.4". If not specified, the
+   * version embedded in Kargo is used.
+   *
+   * +kubebuilder:validation:Pattern=^v[0-9]+\.[0-9]+\.[0-9]+$
+   *
+   * @generated from field: optional string helm = 2;
+   */
+  helm: string;
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.ToolVersions.
+ * Use `create(ToolVersionsSchema)` to create a new message.
+ */
+export const ToolVersionsSchema: GenMessage<ToolVersions> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 103);
+
+/**
+ * UpstreamStageImages describes how the images that have been promoted to an
+ * upstream Stage differ from those that have been promoted to a Stage.
+ *
+ * @generated from message github.com.akuity.kargo.api.v1alpha1.UpstreamStageImages
+ */
+export type UpstreamStageImages = Message<"github.com.akuity.kargo.api.v1alpha1.UpstreamStageImages"> & {
+  /**
+   * Stage is the name of the upstream Stage.
+   *
+   * @generated from field: optional string stage = 1;
+   */
+  stage: string;
+
+  /**
+   * Differences lists the images whose versions differ between the upstream
+   * Stage and this one, ordered by repository URL. It is empty when the
+   * Stages are in step.
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.ImageDifference differences = 2;
+   */
+  differences: ImageDifference[];
+};
+
+/**
+ * Describes the message github.com.akuity.kargo.api.v1alpha1.UpstreamStageImages.
+ * Use `create(UpstreamStageImagesSchema)` to create a new message.
+ */
+export const UpstreamStageImagesSchema: GenMessage<UpstreamStageImages> = /*@__PURE__*/
+  messageDesc(file_v1alpha1_generated, 104);
 
 /**
  * Verification describes how to verify that a Promotion has been successful
@@ -2724,7 +5552,7 @@ export type Verification = Message<"github.com.akuity.kargo.api.v1alpha1.Verific
  * Use `create(VerificationSchema)` to create a new message.
  */
 export const VerificationSchema: GenMessage<Verification> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 58);
+  messageDesc(file_v1alpha1_generated, 105);
 
 /**
  * VerificationInfo contains the details of an instance of a Verification
@@ -2794,7 +5622,7 @@ export type VerificationInfo = Message<"github.com.akuity.kargo.api.v1alpha1.Ver
  * Use `create(VerificationInfoSchema)` to create a new message.
  */
 export const VerificationInfoSchema: GenMessage<VerificationInfo> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 59);
+  messageDesc(file_v1alpha1_generated, 106);
 
 /**
  * VerifiedStage describes a Stage in which Freight has been verified.
@@ -2826,7 +5654,7 @@ export type VerifiedStage = Message<"github.com.akuity.kargo.api.v1alpha1.Verifi
  * Use `create(VerifiedStageSchema)` to create a new message.
  */
 export const VerifiedStageSchema: GenMessage<VerifiedStage> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 60);
+  messageDesc(file_v1alpha1_generated, 107);
 
 /**
  * Warehouse is a source of Freight.
@@ -2861,7 +5689,7 @@ export type Warehouse = Message<"github.com.akuity.kargo.api.v1alpha1.Warehouse"
  * Use `create(WarehouseSchema)` to create a new message.
  */
 export const WarehouseSchema: GenMessage<Warehouse> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 61);
+  messageDesc(file_v1alpha1_generated, 108);
 
 /**
  * WarehouseList is a list of Warehouse resources.
@@ -2885,7 +5713,7 @@ export type WarehouseList = Message<"github.com.akuity.kargo.api.v1alpha1.Wareho
  * Use `create(WarehouseListSchema)` to create a new message.
  */
 export const WarehouseListSchema: GenMessage<WarehouseList> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 62);
+  messageDesc(file_v1alpha1_generated, 109);
 
 /**
  * WarehouseSpec describes sources of versioned artifacts to be included in
@@ -2935,6 +5763,24 @@ export type WarehouseSpec = Message<"github.com.akuity.kargo.api.v1alpha1.Wareho
    */
   freightCreationPolicy: string;
 
+  /**
+   * FreightBatchWindow, when specified, causes Freight creation to be deferred
+   * for this long after changes to the latest artifacts are first discovered.
+   * Further changes discovered within the window (for instance, due to refresh
+   * requests issued for each of several images built by a single CI run) are
+   * combined into the same Freight instead of each producing Freight of its
+   * own. This field is optional and only has any effect when the
+   * FreightCreationPolicy is Automatic. When left unspecified, Freight is
+   * created as soon as changes are discovered.
+   *
+   * +kubebuilder:validation:Type=string
+   * +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(s|m|h))+$"
+   * +kubebuilder:validation:Optional
+   *
+   * @generated from field: optional k8s.io.apimachinery.pkg.apis.meta.v1.Duration freightBatchWindow = 5;
+   */
+  freightBatchWindow?: Duration;
+
   /**
    * Subscriptions describes sources of artifacts to be included in Freight
    * produced by this Warehouse.
@@ -2951,7 +5797,7 @@ export type WarehouseSpec = Message<"github.com.akuity.kargo.api.v1alpha1.Wareho
  * Use `create(WarehouseSpecSchema)` to create a new message.
  */
 export const WarehouseSpecSchema: GenMessage<WarehouseSpec> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 63);
+  messageDesc(file_v1alpha1_generated, 110);
 
 /**
  * WarehouseStatus describes a Warehouse's most recently observed state.
@@ -3003,6 +5849,26 @@ export type WarehouseStatus = Message<"github.com.akuity.kargo.api.v1alpha1.Ware
    * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.DiscoveredArtifacts discoveredArtifacts = 7;
    */
   discoveredArtifacts?: DiscoveredArtifacts;
+
+  /**
+   * PendingFreight describes Freight that the Warehouse will create from the
+   * latest discovered artifacts once its FreightBatchWindow has elapsed.
+   *
+   * +optional
+   *
+   * @generated from field: optional github.com.akuity.kargo.api.v1alpha1.PendingFreight pendingFreight = 10;
+   */
+  pendingFreight?: PendingFreight;
+
+  /**
+   * ImageSubscriptions describes the most recently observed state of each of
+   * the Warehouse's image subscriptions.
+   *
+   * +optional
+   *
+   * @generated from field: repeated github.com.akuity.kargo.api.v1alpha1.ImageSubscriptionStatus imageSubscriptions = 11;
+   */
+  imageSubscriptions: ImageSubscriptionStatus[];
 };
 
 /**
@@ -3010,5 +5876,5 @@ export type WarehouseStatus = Message<"github.com.akuity.kargo.api.v1alpha1.Ware
  * Use `create(WarehouseStatusSchema)` to create a new message.
  */
 export const WarehouseStatusSchema: GenMessage<WarehouseStatus> = /*@__PURE__*/
-  messageDesc(file_v1alpha1_generated, 64);
+  messageDesc(file_v1alpha1_generated, 111);
 